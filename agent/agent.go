@@ -61,6 +61,16 @@ const (
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+
+	// ConfigChangeQuietPeriod holds the duration, in time.ParseDuration
+	// format, for which a unit agent waits after the last of a run of
+	// service config changes before running a single config-changed
+	// hook for them all.
+	ConfigChangeQuietPeriod = "CONFIG_CHANGE_QUIET_PERIOD"
+
+	// PasswordRotated holds the time, in RFC 3339 format, at which the
+	// agent's API password was last replaced by the password rotator.
+	PasswordRotated = "PASSWORD_ROTATED"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -153,6 +153,33 @@ func (s *machineSuite) TestEntitySetPassword(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
 }
 
+func (s *machineSuite) TestEntityRotatePassword(c *gc.C) {
+	entity, err := s.st.Agent().Entity(s.machine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = entity.SetPassword("foo-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = entity.SetNextPassword("foo")
+	c.Assert(err, gc.ErrorMatches, "password is only 3 bytes long, and is not a valid Agent password")
+	err = entity.SetNextPassword("bar-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.PasswordValid("foo-12345678901234567890"), jc.IsTrue)
+	c.Assert(s.machine.PasswordValid("bar-12345678901234567890"), jc.IsTrue)
+
+	err = entity.ConfirmPassword("baz-12345678901234567890")
+	c.Assert(err, gc.ErrorMatches, "cannot confirm password of machine .*: password does not match")
+	err = entity.ConfirmPassword("bar-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.PasswordValid("foo-12345678901234567890"), jc.IsFalse)
+	c.Assert(s.machine.PasswordValid("bar-12345678901234567890"), jc.IsTrue)
+}
+
 func (s *machineSuite) TestClearReboot(c *gc.C) {
 	err := s.machine.SetRebootFlag(true)
 	c.Assert(err, jc.ErrorIsNil)
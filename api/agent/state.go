@@ -134,3 +134,31 @@ func (m *Entity) ClearReboot() error {
 	}
 	return result.OneError()
 }
+
+// SetNextPassword records a new password for the agent's entity. Both
+// the current and the new password remain valid until the new one is
+// confirmed with ConfirmPassword.
+func (m *Entity) SetNextPassword(password string) error {
+	return m.callPassword("SetNextPasswords", password)
+}
+
+// ConfirmPassword makes the given password, previously recorded with
+// SetNextPassword, the only valid password for the agent's entity.
+func (m *Entity) ConfirmPassword(password string) error {
+	return m.callPassword("ConfirmPasswords", password)
+}
+
+func (m *Entity) callPassword(method, password string) error {
+	var results params.ErrorResults
+	args := params.EntityPasswords{
+		Changes: []params.EntityPassword{{
+			Tag:      m.tag.String(),
+			Password: password,
+		}},
+	}
+	err := m.st.facade.FacadeCall(method, args, &results)
+	if err != nil {
+		return err
+	}
+	return results.OneError()
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package api
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// AllEnvWatcher holds information allowing us to get Deltas describing
+// changes to the entities of every environment in the system.
+type AllEnvWatcher struct {
+	caller base.APICaller
+	id     *string
+}
+
+// NewAllEnvWatcher returns an AllEnvWatcher instance which interacts
+// with a watcher created by the SystemManager WatchAllEnvs API call.
+func NewAllEnvWatcher(caller base.APICaller, id *string) *AllEnvWatcher {
+	return &AllEnvWatcher{caller, id}
+}
+
+// Next returns the deltas that have occurred since the last call,
+// blocking until some are available.
+func (watcher *AllEnvWatcher) Next() ([]multiwatcher.EnvironDelta, error) {
+	var info params.AllEnvWatcherNextResults
+	err := watcher.caller.APICall(
+		"AllEnvWatcher", watcher.caller.BestFacadeVersion("AllEnvWatcher"),
+		*watcher.id, "Next", nil, &info)
+	return info.Deltas, err
+}
+
+// Stop stops the watcher.
+func (watcher *AllEnvWatcher) Stop() error {
+	return watcher.caller.APICall(
+		"AllEnvWatcher", watcher.caller.BestFacadeVersion("AllEnvWatcher"),
+		*watcher.id, "Stop", nil, nil)
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/names"
 	"github.com/juju/utils"
 	"github.com/juju/utils/parallel"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 
 	"github.com/juju/juju/apiserver/params"
@@ -32,6 +34,11 @@ var logger = loggo.GetLogger("juju.api")
 // will run. It's a variable so it can be changed in tests.
 var PingPeriod = 1 * time.Minute
 
+// sessionRefreshMargin is how long before its token expires that a
+// login session is refreshed. It's a variable so it can be changed in
+// tests.
+var sessionRefreshMargin = 30 * time.Minute
+
 type State struct {
 	client *rpc.Conn
 	conn   *websocket.Conn
@@ -84,6 +91,21 @@ type State struct {
 	// certPool holds the cert pool that is used to authenticate the tls
 	// connections to the API.
 	certPool *x509.CertPool
+
+	// sessionToken and requestSession hold the session details sent
+	// when logging in.
+	sessionToken   string
+	requestSession bool
+
+	// totpCode holds the second factor sent when logging in.
+	totpCode string
+
+	// sessionRefreshed is called whenever the session is refreshed.
+	sessionRefreshed func(params.SessionInfo)
+
+	// sessionMu guards session, which holds the login session, if any.
+	sessionMu sync.Mutex
+	session   *params.SessionInfo
 }
 
 // Info encapsulates information about a server holding juju state and
@@ -105,6 +127,15 @@ type Info struct {
 	// Password holds the password for the administrator or connecting entity.
 	Password string
 
+	// SessionToken holds the token of a login session, which a user
+	// may log in with in place of their password.
+	SessionToken string `yaml:",omitempty"`
+
+	// TOTPCode holds the time-based one-time password that users
+	// enrolled for two-factor authentication must give when logging
+	// in with a password.
+	TOTPCode string `yaml:",omitempty"`
+
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
@@ -128,6 +159,30 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// OrderAddresses, if set, is called with the addresses to be
+	// dialed and returns them in the order in which they should be
+	// tried. Addresses dialed earlier get a head start on the others,
+	// so those most likely to succeed should come first.
+	OrderAddresses func(addrs []string) []string
+
+	// RecordDial, if set, is called with the error when the first
+	// attempt to dial an address fails, and with a nil error when a
+	// connection to the address is made. Dials abandoned because
+	// another address connected first are not reported.
+	RecordDial func(addr string, err error)
+
+	// RequestSession, if set, asks the API server to start a login
+	// session for a user logging in with their password. The session
+	// token can be obtained with State.Session once connected.
+	RequestSession bool
+
+	// SessionRefreshed, if set, is called with the new session
+	// details whenever the login session is refreshed, so that the
+	// new token can be saved for later logins. The token the
+	// connection logged in with can no longer be used once it has
+	// been refreshed.
+	SessionRefreshed func(params.SessionInfo)
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -171,8 +226,13 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 		tag:      toString(info.Tag),
 		password: info.Password,
 		certPool: conn.Config().TlsConfig.RootCAs,
+
+		sessionToken:     info.SessionToken,
+		totpCode:         info.TOTPCode,
+		requestSession:   opts.RequestSession,
+		sessionRefreshed: opts.SessionRefreshed,
 	}
-	if info.Tag != nil || info.Password != "" {
+	if info.Tag != nil || info.Password != "" || info.SessionToken != "" {
 		if err := loginFunc(st, info.Tag.String(), info.Password, info.Nonce); err != nil {
 			conn.Close()
 			return nil, err
@@ -181,6 +241,11 @@ func open(info *Info, opts DialOpts, loginFunc func(st *State, tag, pwd, nonce s
 	st.broken = make(chan struct{})
 	st.closed = make(chan struct{})
 	go st.heartbeatMonitor()
+	// A session close to expiry is refreshed before returning, so
+	// that short-lived clients keep it alive too.
+	if st.session != nil && st.keepSessionAlive() {
+		go st.sessionRefresher()
+	}
 	return st, nil
 }
 
@@ -235,6 +300,9 @@ func Connect(info *Info, pathTail string, header http.Header, opts DialOpts) (*w
 	if len(addrs) == 0 {
 		addrs = info.Addrs
 	}
+	if opts.OrderAddresses != nil {
+		addrs = opts.OrderAddresses(addrs)
+	}
 
 	path := makeAPIPath(info.EnvironTag.Id(), pathTail)
 
@@ -322,6 +390,7 @@ func createWebsocketDialer(cfg *websocket.Config, opts DialOpts) func(<-chan str
 		Delay: opts.RetryDelay,
 	}
 	return func(stop <-chan struct{}) (io.Closer, error) {
+		failed := false
 		for a := openAttempt.Start(); a.Next(); {
 			select {
 			case <-stop:
@@ -331,8 +400,15 @@ func createWebsocketDialer(cfg *websocket.Config, opts DialOpts) func(<-chan str
 			logger.Infof("dialing %q", cfg.Location)
 			conn, err := websocket.DialConfig(cfg)
 			if err == nil {
+				if opts.RecordDial != nil {
+					opts.RecordDial(cfg.Location.Host, nil)
+				}
 				return conn, nil
 			}
+			if !failed && opts.RecordDial != nil {
+				opts.RecordDial(cfg.Location.Host, err)
+			}
+			failed = true
 			if a.HasNext() {
 				logger.Debugf("error dialing %q, will retry: %v", cfg.Location, err)
 			} else {
@@ -357,6 +433,77 @@ func (s *State) heartbeatMonitor() {
 	}
 }
 
+// sessionDue reports whether the login session should be refreshed.
+func (s *State) sessionDue() bool {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	return time.Now().Add(sessionRefreshMargin).After(s.session.Expires)
+}
+
+// keepSessionAlive refreshes the login session if it is due, and
+// reports whether it can be refreshed again later. A session cannot be
+// refreshed if the refresh fails, or once it is close to its maximum
+// lifetime.
+func (s *State) keepSessionAlive() bool {
+	if !s.sessionDue() {
+		return true
+	}
+	if err := s.refreshSession(); err != nil {
+		// The connection stays logged in regardless; the session
+		// will need to be started again at the next login.
+		logger.Warningf("%v", err)
+		return false
+	}
+	return !s.sessionDue()
+}
+
+// sessionRefresher refreshes the login session shortly before each
+// of its tokens expires, until the connection is closed.
+func (s *State) sessionRefresher() {
+	for {
+		s.sessionMu.Lock()
+		due := s.session.Expires.Add(-sessionRefreshMargin)
+		s.sessionMu.Unlock()
+		select {
+		case <-time.After(due.Sub(time.Now())):
+		case <-s.closed:
+			return
+		}
+		if !s.keepSessionAlive() {
+			return
+		}
+	}
+}
+
+// refreshSession replaces the token of the login session with a new
+// one, and reports the new session to the SessionRefreshed callback.
+func (s *State) refreshSession() error {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	var result params.SessionInfo
+	args := params.SessionToken{Token: s.session.Token}
+	if err := s.APICall("UserManager", 0, "", "RefreshSession", args, &result); err != nil {
+		return errors.Annotate(err, "cannot refresh login session")
+	}
+	s.session = &result
+	if s.sessionRefreshed != nil {
+		s.sessionRefreshed(result)
+	}
+	return nil
+}
+
+// Session returns the login session of the connection, and whether
+// there is one. There is a session if the connection logged in with a
+// session token, or if one was requested with DialOpts.RequestSession.
+func (s *State) Session() (params.SessionInfo, bool) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	if s.session == nil {
+		return params.SessionInfo{}, false
+	}
+	return *s.session, true
+}
+
 func (s *State) Ping() error {
 	return s.APICall("Pinger", s.BestFacadeVersion("Pinger"), "", "Ping", nil, nil)
 }
@@ -376,6 +523,22 @@ func (s *State) APICall(facade string, version int, id, method string, args, res
 	return params.ClientError(err)
 }
 
+// APICallContext is like APICall, but abandons the call when ctx is
+// done, asking the API server to cancel the request and returning
+// ctx.Err().
+func (s *State) APICallContext(ctx context.Context, facade string, version int, id, method string, args, response interface{}) error {
+	err := s.client.CallContext(ctx, rpc.Request{
+		Type:    facade,
+		Version: version,
+		Id:      id,
+		Action:  method,
+	}, args, response)
+	if err == ctx.Err() {
+		return err
+	}
+	return params.ClientError(err)
+}
+
 func (s *State) Close() error {
 	err := s.client.Close()
 	select {
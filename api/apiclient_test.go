@@ -172,6 +172,55 @@ func (s *apiclientSuite) TestConnectMultipleError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `unable to connect to "wss://.*/environment/[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12}/api"`)
 }
 
+func (s *apiclientSuite) TestConnectOrderAddresses(c *gc.C) {
+	var dialed []string
+	fakeNewDialer := func(cfg *websocket.Config, _ api.DialOpts) func(<-chan struct{}) (io.Closer, error) {
+		dialed = append(dialed, cfg.Location.Host)
+		return func(<-chan struct{}) (io.Closer, error) {
+			return nil, errors.New("fake")
+		}
+	}
+	s.PatchValue(api.NewWebsocketDialerPtr, fakeNewDialer)
+
+	info := s.APIInfo(c)
+	info.Addrs = []string{"0.1.2.3:1", "0.1.2.4:1", "0.1.2.5:1"}
+	opts := api.DialOpts{
+		OrderAddresses: func(addrs []string) []string {
+			c.Check(addrs, jc.DeepEquals, info.Addrs)
+			return []string{addrs[2], addrs[0], addrs[1]}
+		},
+	}
+	api.Connect(info, "", nil, opts) // Return values not important here
+	c.Assert(dialed, jc.DeepEquals, []string{"0.1.2.5:1", "0.1.2.3:1", "0.1.2.4:1"})
+}
+
+func (s *apiclientSuite) TestConnectRecordDial(c *gc.C) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	badAddr := listener.Addr().String()
+	listener.Close()
+
+	var recorded []string
+	opts := api.DialOpts{
+		RecordDial: func(addr string, err error) {
+			recorded = append(recorded, fmt.Sprintf("%s %v", addr, err))
+		},
+	}
+	info := s.APIInfo(c)
+	goodAddr := info.Addrs[0]
+	conn, err := api.Connect(info, "/api", nil, opts)
+	c.Assert(err, jc.ErrorIsNil)
+	conn.Close()
+	c.Assert(recorded, jc.DeepEquals, []string{goodAddr + " <nil>"})
+
+	recorded = nil
+	info.Addrs = []string{badAddr}
+	_, err = api.Connect(info, "/api", nil, opts)
+	c.Assert(err, gc.NotNil)
+	c.Assert(recorded, gc.HasLen, 1)
+	c.Assert(recorded[0], gc.Matches, badAddr+" .*connection refused.*")
+}
+
 func (s *apiclientSuite) TestOpen(c *gc.C) {
 	info := s.APIInfo(c)
 	st, err := api.Open(info, api.DialOpts{})
@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apischema
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the APISchema facade, used to describe the
+// facades served by the API server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new APISchema client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "APISchema")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Schema returns a description of the facades served by the API
+// server, their methods and the types of their arguments and results.
+func (c *Client) Schema() (params.APISchemaResult, error) {
+	var result params.APISchemaResult
+	if err := c.facade.FacadeCall("Schema", nil, &result); err != nil {
+		return params.APISchemaResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
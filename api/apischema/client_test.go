@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apischema_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/apischema"
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestSchema(c *gc.C) {
+	schema := params.APISchemaResult{
+		Facades: []params.FacadeSchema{{
+			Name:    "Pinger",
+			Version: 0,
+			Methods: []params.MethodSchema{{Name: "Ping"}},
+		}},
+		Types: map[string]params.TypeSchema{},
+	}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "APISchema")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Schema")
+			c.Check(a, gc.IsNil)
+			*response.(*params.APISchemaResult) = schema
+			return nil
+		})
+	client := apischema.NewClient(apiCaller)
+	result, err := client.Schema()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, schema)
+}
+
+func (s *clientSuite) TestSchemaError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("permission denied")
+		})
+	client := apischema.NewClient(apiCaller)
+	_, err := client.Schema()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -4,6 +4,8 @@
 package backups
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/juju/juju/api/base"
 )
 
@@ -49,6 +51,10 @@ func (f *resultCaller) FacadeCall(request string, params, response interface{})
 	return f.mockCall(request, params, response)
 }
 
+func (f *resultCaller) FacadeCallContext(_ context.Context, request string, params, response interface{}) error {
+	return f.mockCall(request, params, response)
+}
+
 func (f *resultCaller) Name() string {
 	return ""
 }
@@ -5,6 +5,7 @@ package base
 
 import (
 	"github.com/juju/names"
+	"golang.org/x/net/context"
 )
 
 // APICaller is implemented by the client-facing State object.
@@ -14,6 +15,11 @@ type APICaller interface {
 	// call's result if the call is successful.
 	APICall(objType string, version int, id, request string, params, response interface{}) error
 
+	// APICallContext is like APICall, but abandons the call and asks
+	// the API server to cancel it when ctx is done, returning
+	// ctx.Err().
+	APICallContext(ctx context.Context, objType string, version int, id, request string, params, response interface{}) error
+
 	// BestFacadeVersion returns the newest version of 'objType' that this
 	// client can use with the current API server.
 	BestFacadeVersion(facade string) int
@@ -32,6 +38,10 @@ type FacadeCaller interface {
 	// also known to the client.
 	FacadeCall(request string, params, response interface{}) error
 
+	// FacadeCallContext is like FacadeCall, but abandons the call
+	// and asks the API server to cancel it when ctx is done.
+	FacadeCallContext(ctx context.Context, request string, params, response interface{}) error
+
 	// Name returns the facade name.
 	Name() string
 
@@ -62,6 +72,14 @@ func (fc facadeCaller) FacadeCall(request string, params, response interface{})
 		request, params, response)
 }
 
+// FacadeCallContext is like FacadeCall, but abandons the call and asks
+// the API server to cancel it when ctx is done.
+func (fc facadeCaller) FacadeCallContext(ctx context.Context, request string, params, response interface{}) error {
+	return fc.caller.APICallContext(ctx,
+		fc.facadeName, fc.bestVersion, "",
+		request, params, response)
+}
+
 // Name returns the facade name.
 func (fc facadeCaller) Name() string {
 	return fc.facadeName
@@ -3,7 +3,10 @@
 
 package testing
 
-import "github.com/juju/names"
+import (
+	"github.com/juju/names"
+	"golang.org/x/net/context"
+)
 
 // APICallerFunc is a function type that implements APICaller.
 type APICallerFunc func(objType string, version int, id, request string, params, response interface{}) error
@@ -12,6 +15,14 @@ func (f APICallerFunc) APICall(objType string, version int, id, request string,
 	return f(objType, version, id, request, params, response)
 }
 
+// APICallContext calls f unless ctx is already done.
+func (f APICallerFunc) APICallContext(ctx context.Context, objType string, version int, id, request string, params, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f(objType, version, id, request, params, response)
+}
+
 func (APICallerFunc) BestFacadeVersion(facade string) int {
 	return 0
 }
@@ -4,12 +4,14 @@
 package testing
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/juju/juju/api/base"
 )
 
 // PatchFacadeCall patches the provided FacadeCaller such
-// that the FacadeCall method calls are diverted to the
-// provided function.
+// that the FacadeCall and FacadeCallContext method calls
+// are diverted to the provided function.
 func PatchFacadeCall(p Patcher, caller *base.FacadeCaller, f func(request string, params, response interface{}) error) {
 	p.PatchValue(caller, &facadeWrapper{*caller, f})
 }
@@ -26,3 +28,7 @@ type facadeWrapper struct {
 func (f *facadeWrapper) FacadeCall(request string, params, response interface{}) error {
 	return f.facadeCall(request, params, response)
 }
+
+func (f *facadeWrapper) FacadeCallContext(_ context.Context, request string, params, response interface{}) error {
+	return f.facadeCall(request, params, response)
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmvalidation
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the CharmValidation facade, used to check
+// a charm against the features supported by the controller.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new CharmValidation client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "CharmValidation")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ValidateCharm checks the given charm archive against the features
+// supported by the controller for the given series, or for the
+// environment's default series if it is empty.
+func (c *Client) ValidateCharm(archive []byte, series string) (params.ValidateCharmResult, error) {
+	args := params.ValidateCharmArgs{
+		Archive: archive,
+		Series:  series,
+	}
+	var result params.ValidateCharmResult
+	if err := c.facade.FacadeCall("ValidateCharm", args, &result); err != nil {
+		return params.ValidateCharmResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
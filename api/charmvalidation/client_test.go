@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmvalidation_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/charmvalidation"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestValidateCharm(c *gc.C) {
+	expected := params.ValidateCharmResult{
+		Series: "trusty",
+		Warnings: []params.CharmCompatibilityWarning{{
+			Kind:    "hook",
+			Name:    "common.sh",
+			Message: `"common.sh" is not a hook known to this controller and will never be run`,
+		}},
+	}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "CharmValidation")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ValidateCharm")
+			c.Check(a, jc.DeepEquals, params.ValidateCharmArgs{
+				Archive: []byte("archive"),
+				Series:  "trusty",
+			})
+			*response.(*params.ValidateCharmResult) = expected
+			return nil
+		})
+	client := charmvalidation.NewClient(apiCaller)
+	result, err := client.ValidateCharm([]byte("archive"), "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *clientSuite) TestValidateCharmError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("invalid charm archive: zip: not a valid zip file")
+		})
+	client := charmvalidation.NewClient(apiCaller)
+	_, err := client.ValidateCharm([]byte("archive"), "")
+	c.Assert(err, gc.ErrorMatches, "invalid charm archive: zip: not a valid zip file")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/macaroon.v1"
@@ -84,6 +85,12 @@ type MachineStatus struct {
 	Jobs          []multiwatcher.MachineJob
 	HasVote       bool
 	WantsVote     bool
+
+	// InstanceType and InstanceTypeRationale hold the instance type
+	// to which the provider normalized the machine's constraints, and
+	// why, if known.
+	InstanceType          string
+	InstanceTypeRationale string
 }
 
 // ServiceStatus holds status info about a service.
@@ -98,6 +105,48 @@ type ServiceStatus struct {
 	SubordinateTo []string
 	Units         map[string]UnitStatus
 	Status        AgentStatus
+	Summary       ServiceSummary
+
+	// ConfigRevision holds the revision of the service's
+	// configuration settings.
+	ConfigRevision int
+
+	// UnmappedConfig holds the service's configuration settings that
+	// could not be carried over when its charm was last changed.
+	UnmappedConfig []UnmappedConfigSetting
+}
+
+// UnmappedConfigSetting describes a configuration setting of a service
+// that could not be carried over when the service's charm was changed,
+// either because the new charm does not define it ("removed") or
+// because its value is not valid for the new charm ("invalid").
+type UnmappedConfigSetting struct {
+	Key    string
+	Value  interface{}
+	Reason string
+}
+
+// ServiceSummary holds the counts that summarise the state of a
+// principal service's units, for reporting large environments
+// without listing every unit.
+type ServiceSummary struct {
+	// UnitCount holds the number of units in the service.
+	UnitCount int
+
+	// MachineCount holds the number of distinct machines that the
+	// service's units are assigned to.
+	MachineCount int
+
+	// WorkloadCounts holds the number of units with each workload
+	// status.
+	WorkloadCounts map[params.Status]int
+
+	// AgentCounts holds the number of units with each agent status.
+	AgentCounts map[params.Status]int
+
+	// WorstStatus holds the most severe of the units' workload
+	// statuses.
+	WorstStatus params.Status
 }
 
 // UnitStatusHistory holds a slice of statuses.
@@ -126,6 +175,22 @@ type UnitStatus struct {
 	PublicAddress string
 	Charm         string
 	Subordinates  map[string]UnitStatus
+
+	// Paused is true if the unit has been paused.
+	Paused bool
+
+	// Utilization holds the latest sample of the resources used by
+	// the unit's processes, if one was recently reported.
+	Utilization *UnitUtilization
+}
+
+// UnitUtilization holds a sample of the resources used by the
+// processes of a unit.
+type UnitUtilization struct {
+	CPUPercent  float64
+	MemoryBytes uint64
+	Processes   int
+	Time        time.Time
 }
 
 // RelationStatus holds status info about a relation.
@@ -176,6 +241,30 @@ func (c *Client) Status(patterns []string) (*Status, error) {
 	return &result, nil
 }
 
+// StatusWithSections returns the status of the juju environment,
+// restricted to the named sections; see params.AllStatusSections.
+// Servers that cannot restrict the status return every section.
+func (c *Client) StatusWithSections(patterns, sections []string) (*Status, error) {
+	var result Status
+	p := params.StatusParams{Patterns: patterns, Sections: sections}
+	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StatusContext is like StatusWithSections, but abandons the call and
+// asks the API server to stop gathering the status when ctx is done. If
+// sections is nil, every section is returned.
+func (c *Client) StatusContext(ctx context.Context, patterns, sections []string) (*Status, error) {
+	var result Status
+	p := params.StatusParams{Patterns: patterns, Sections: sections}
+	if err := c.facade.FacadeCallContext(ctx, "FullStatus", p, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // UnitStatusHistory retrieves the last <size> results of <kind:combined|agent|workload> status
 // for <unitName> unit
 func (c *Client) UnitStatusHistory(kind params.HistoryKind, unitName string, size int) (*UnitStatusHistory, error) {
@@ -257,6 +346,66 @@ func (c *Client) RetryProvisioning(machines ...names.MachineTag) ([]params.Error
 	return results.Results, err
 }
 
+// PauseUnits pauses the specified units, so that their agents run no
+// periodic hooks and handle no relation changes until they are resumed.
+func (c *Client) PauseUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	return c.setUnitsPaused("PauseUnits", units)
+}
+
+// ResumeUnits resumes the specified paused units.
+func (c *Client) ResumeUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	return c.setUnitsPaused("ResumeUnits", units)
+}
+
+func (c *Client) setUnitsPaused(method string, units []names.UnitTag) ([]params.ErrorResult, error) {
+	p := params.Entities{}
+	p.Entities = make([]params.Entity, len(units))
+	for i, unit := range units {
+		p.Entities[i] = params.Entity{Tag: unit.String()}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall(method, p, &results)
+	return results.Results, err
+}
+
+// ResolveUnits marks the errors of the specified units as resolved,
+// retrying their failed hooks if retry is true. It returns an error
+// result for each unit, in order.
+func (c *Client) ResolveUnits(retry bool, units ...names.UnitTag) ([]params.ErrorResult, error) {
+	p := params.ResolveUnits{
+		Units: make([]params.ResolveUnit, len(units)),
+	}
+	for i, unit := range units {
+		p.Units[i] = params.ResolveUnit{UnitTag: unit.String(), Retry: retry}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("ResolveUnits", p, &results)
+	return results.Results, err
+}
+
+// RemoveUnits destroys the specified principal units. It returns an
+// error result for each unit, in order.
+func (c *Client) RemoveUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	p := params.Entities{}
+	p.Entities = make([]params.Entity, len(units))
+	for i, unit := range units {
+		p.Entities[i] = params.Entity{Tag: unit.String()}
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("RemoveUnits", p, &results)
+	return results.Results, err
+}
+
+// SetConstraintsBulk sets the constraints of several services, or of
+// the environment for entries with no service name, in one call. It
+// returns an error result for each entry, in order.
+func (c *Client) SetConstraintsBulk(args []params.SetConstraints) ([]params.ErrorResult, error) {
+	p := params.SetConstraintsBulk{Constraints: args}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("SetConstraintsBulk", p, &results)
+	return results.Results, err
+}
+
 // PublicAddress returns the public address of the specified
 // machine or unit. For a machine, target is an id not a tag.
 func (c *Client) PublicAddress(target string) (string, error) {
@@ -266,6 +415,15 @@ func (c *Client) PublicAddress(target string) (string, error) {
 	return results.PublicAddress, err
 }
 
+// SSHHostKeys returns the SSH host keys reported by the specified
+// machine, or by the machine hosting the specified unit.
+func (c *Client) SSHHostKeys(target string) ([]string, error) {
+	var results params.SSHHostKeysResults
+	p := params.SSHHostKeysTarget{Target: target}
+	err := c.facade.FacadeCall("SSHHostKeys", p, &results)
+	return results.PublicKeys, err
+}
+
 // PrivateAddress returns the private address of the specified
 // machine or unit.
 func (c *Client) PrivateAddress(target string) (string, error) {
@@ -293,6 +451,28 @@ func (c *Client) ServiceGet(service string) (*params.ServiceGetResults, error) {
 	return &results, err
 }
 
+// ServiceGetConfigRevision returns the given recorded revision of the
+// configuration settings of the named service.
+func (c *Client) ServiceGetConfigRevision(service string, revision int) (*params.ServiceConfigRevision, error) {
+	var result params.ServiceConfigRevision
+	args := params.ServiceConfigRevisionArgs{
+		ServiceName: service,
+		Revision:    revision,
+	}
+	err := c.facade.FacadeCall("ServiceGetConfigRevision", args, &result)
+	return &result, err
+}
+
+// ServiceRollbackConfig restores the configuration settings of the
+// named service to those recorded at the given revision.
+func (c *Client) ServiceRollbackConfig(service string, revision int) error {
+	args := params.ServiceConfigRevisionArgs{
+		ServiceName: service,
+		Revision:    revision,
+	}
+	return c.facade.FacadeCall("ServiceRollbackConfig", args, nil)
+}
+
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
 func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, error) {
 	var addRelRes params.AddRelationResults
@@ -301,6 +481,17 @@ func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, e
 	return &addRelRes, err
 }
 
+// AddRelationWithToken is like AddRelation, but identifies the call
+// with the given idempotency token, so that it can be retried safely:
+// a retry within state.IdempotencyWindow returns the relation added by
+// the first call.
+func (c *Client) AddRelationWithToken(token string, endpoints ...string) (*params.AddRelationResults, error) {
+	var addRelRes params.AddRelationResults
+	params := params.AddRelation{Endpoints: endpoints, IdempotencyToken: token}
+	err := c.facade.FacadeCall("AddRelation", params, &addRelRes)
+	return &addRelRes, err
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
@@ -340,6 +531,20 @@ func (c *Client) AddMachines(machineParams []params.AddMachineParams) ([]params.
 	return results.Machines, err
 }
 
+// AddMachinesWithToken is like AddMachines, but identifies the call
+// with the given idempotency token, so that it can be retried safely:
+// a retry within state.IdempotencyWindow returns the machines added by
+// the first call rather than adding more.
+func (c *Client) AddMachinesWithToken(token string, machineParams []params.AddMachineParams) ([]params.AddMachinesResult, error) {
+	args := params.AddMachines{
+		MachineParams:    machineParams,
+		IdempotencyToken: token,
+	}
+	results := new(params.AddMachinesResults)
+	err := c.facade.FacadeCall("AddMachinesV2", args, results)
+	return results.Machines, err
+}
+
 // ProvisioningScript returns a shell script that, when run,
 // provisions a machine agent on the machine executing the script.
 func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (script string, err error) {
@@ -350,6 +555,31 @@ func (c *Client) ProvisioningScript(args params.ProvisioningScriptParams) (scrip
 	return result.Script, nil
 }
 
+// ProviderInstance returns what the environment's provider knows of
+// the instance with the given id.
+func (c *Client) ProviderInstance(id instance.Id) (*params.ProviderInstanceResult, error) {
+	var result params.ProviderInstanceResult
+	args := params.ProviderInstanceArgs{InstanceId: id}
+	if err := c.facade.FacadeCall("ProviderInstance", args, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportBundle returns the services, relations and, if includePlacement
+// is true, the machines of the environment as a bundle in YAML format.
+func (c *Client) ExportBundle(includePlacement bool) (string, error) {
+	var result params.StringResult
+	args := params.ExportBundleParams{IncludePlacement: includePlacement}
+	if err := c.facade.FacadeCall("ExportBundle", args, &result); err != nil {
+		return "", err
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
 // DestroyMachines removes a given set of machines.
 func (c *Client) DestroyMachines(machines ...string) error {
 	params := params.DestroyMachines{MachineNames: machines}
@@ -456,9 +686,35 @@ func (c *Client) AddServiceUnits(service string, numUnits int, machineSpec strin
 	return results.Units, err
 }
 
+// ScaleService adds or removes units of a service so that it has the
+// given number of live units, placing the added unit with machineSpec
+// when scaling up by one. It returns the names of the units added and
+// removed.
+func (c *Client) ScaleService(service string, numUnits int, machineSpec string) (params.ScaleServiceResult, error) {
+	args := params.ScaleService{
+		ServiceName:   service,
+		NumUnits:      numUnits,
+		ToMachineSpec: machineSpec,
+	}
+	var result params.ScaleServiceResult
+	err := c.facade.FacadeCall("ScaleService", args, &result)
+	return result, err
+}
+
 // DestroyServiceUnits decreases the number of units dedicated to a service.
 func (c *Client) DestroyServiceUnits(unitNames ...string) error {
-	params := params.DestroyServiceUnits{unitNames}
+	params := params.DestroyServiceUnits{UnitNames: unitNames}
+	return c.facade.FacadeCall("DestroyServiceUnits", params, nil)
+}
+
+// DestroyServiceUnitsRemovingEmptyMachines destroys the given units as
+// DestroyServiceUnits does, and has the hosts of their containers
+// destroyed if the containers' removal leaves them empty.
+func (c *Client) DestroyServiceUnitsRemovingEmptyMachines(unitNames ...string) error {
+	params := params.DestroyServiceUnits{
+		UnitNames:           unitNames,
+		RemoveEmptyMachines: true,
+	}
 	return c.facade.FacadeCall("DestroyServiceUnits", params, nil)
 }
 
@@ -484,6 +740,28 @@ func (c *Client) GetEnvironmentConstraints() (constraints.Value, error) {
 	return results.Constraints, err
 }
 
+// UnitConstraints returns the constraints recorded for the given unit,
+// and the constraints that would be used to provision a new machine
+// for it now.
+func (c *Client) UnitConstraints(unit string) (params.UnitConstraintsResult, error) {
+	if !names.IsValidUnit(unit) {
+		return params.UnitConstraintsResult{}, errors.NotValidf("unit name %q", unit)
+	}
+	args := params.Entities{Entities: []params.Entity{{Tag: names.NewUnitTag(unit).String()}}}
+	var results params.UnitConstraintsResults
+	if err := c.facade.FacadeCall("UnitConstraints", args, &results); err != nil {
+		return params.UnitConstraintsResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.UnitConstraintsResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.UnitConstraintsResult{}, result.Error
+	}
+	return result, nil
+}
+
 // SetServiceConstraints specifies the constraints for the given service.
 func (c *Client) SetServiceConstraints(service string, constraints constraints.Value) error {
 	params := params.SetConstraints{
@@ -573,6 +851,27 @@ func (c *Client) ShareEnvironment(users ...names.UserTag) error {
 	return result.Combine()
 }
 
+// InviteEnvironmentUsers invites the given users to access the
+// environment. The users are given access only once they accept.
+func (c *Client) InviteEnvironmentUsers(users ...names.UserTag) error {
+	var args params.Entities
+	for _, user := range users {
+		args.Entities = append(args.Entities, params.Entity{Tag: user.String()})
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("InviteEnvironmentUsers", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for i, r := range result.Results {
+		if r.Error != nil && r.Error.Code == params.CodeAlreadyExists {
+			logger.Warningf("%s already has access or an invitation: %v", users[i].Username(), r.Error)
+			result.Results[i].Error = nil
+		}
+	}
+	return result.Combine()
+}
+
 // EnvironmentUserInfo returns information on all users in the environment.
 func (c *Client) EnvironmentUserInfo() ([]params.EnvUserInfo, error) {
 	var results params.EnvUserInfoResults
@@ -687,12 +986,56 @@ func (c *Client) SetEnvironAgentVersion(version version.Number) error {
 	return c.facade.FacadeCall("SetEnvironAgentVersion", args, nil)
 }
 
+// StartAgentRollout starts a staged upgrade of the environment's
+// agents to the given version. The canary machines are upgraded
+// first; once they have run healthily for the bake period the rest
+// of the environment follows.
+func (c *Client) StartAgentRollout(version version.Number, canaries []string, bakePeriod time.Duration) (params.AgentRolloutStatus, error) {
+	args := params.StartAgentRollout{
+		Version:    version,
+		Canaries:   canaries,
+		BakePeriod: bakePeriod,
+	}
+	var result params.AgentRolloutStatus
+	err := c.facade.FacadeCall("StartAgentRollout", args, &result)
+	return result, err
+}
+
+// AgentRolloutStatus returns the status of the environment's most
+// recent staged upgrade.
+func (c *Client) AgentRolloutStatus() (params.AgentRolloutStatus, error) {
+	var result params.AgentRolloutStatus
+	err := c.facade.FacadeCall("AgentRolloutStatus", nil, &result)
+	return result, err
+}
+
+// PromoteAgentRollout upgrades the rest of the environment to the
+// current staged upgrade's version without waiting for the bake
+// period to pass.
+func (c *Client) PromoteAgentRollout() error {
+	return c.facade.FacadeCall("PromoteAgentRollout", nil, nil)
+}
+
+// RollBackAgentRollout abandons the current staged upgrade.
+func (c *Client) RollBackAgentRollout() error {
+	return c.facade.FacadeCall("RollBackAgentRollout", nil, nil)
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
 	return c.facade.FacadeCall("AbortCurrentUpgrade", nil, nil)
 }
 
+// UpgradeInfo returns details of the upgrade that the state servers
+// are currently coordinating. An error satisfying
+// params.IsCodeNotFound is returned if no upgrade is in progress.
+func (c *Client) UpgradeInfo() (params.UpgradeInfo, error) {
+	var result params.UpgradeInfo
+	err := c.facade.FacadeCall("UpgradeInfo", nil, &result)
+	return result, err
+}
+
 // FindTools returns a List containing all tools matching the specified parameters.
 func (c *Client) FindTools(
 	majorVersion, minorVersion int,
@@ -729,8 +1072,20 @@ func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 // and removes all non-manager machine instances. DestroyEnvironment
 // will fail if there are any manually-provisioned non-manager machines
 // in state.
-func (c *Client) DestroyEnvironment() error {
-	return c.facade.FacadeCall("DestroyEnvironment", nil, nil)
+//
+// If destroyStorage is nil, DestroyEnvironment also fails if there are
+// any persistent volumes in the environment. Otherwise they are
+// destroyed if it points to true, and released (detached and left in
+// the cloud) if it points to false.
+//
+// Any kinds of blocker named in force, such as "manual-machines", do
+// not prevent the environment from being destroyed.
+func (c *Client) DestroyEnvironment(destroyStorage *bool, force []string) error {
+	args := params.DestroyEnvironment{
+		DestroyStorage: destroyStorage,
+		Force:          force,
+	}
+	return c.facade.FacadeCall("DestroyEnvironment", args, nil)
 }
 
 // AddLocalCharm prepares the given charm with a local: schema in its
@@ -1022,6 +1377,13 @@ type DebugLogParams struct {
 	// ExcludeModule lists logging modules to exclude from the resposne. If a
 	// module is specified, all the submodules are also excluded.
 	ExcludeModule []string
+	// IncludeMessage lists regular expressions, one of which must match
+	// a log message for it to be included in the response. If none are
+	// set all messages are considered included.
+	IncludeMessage []string
+	// ExcludeMessage lists regular expressions matching log messages to
+	// exclude from the response.
+	ExcludeMessage []string
 	// Limit defines the maximum number of lines to return. Once this many
 	// have been sent, the socket is closed.  If zero, all filtered lines are
 	// sent down the connection until the client closes the connection.
@@ -1029,12 +1391,27 @@ type DebugLogParams struct {
 	// Backlog tells the server to try to go back this many lines before
 	// starting filtering. If backlog is zero and replay is false, then there
 	// may be an initial delay until the next matching log message is written.
+	// The server bounds the backlog it will send.
 	Backlog uint
 	// Level specifies the minimum logging level to be sent back in the response.
 	Level loggo.Level
 	// Replay tells the server to start at the start of the log file rather
-	// than the end. If replay is true, backlog is ignored.
+	// than the end, or as far back as its backlog bound if that is later.
+	// If replay is true, backlog is ignored.
 	Replay bool
+	// Format specifies the format of the returned lines, either "text"
+	// (the default) or "json", in which case each line is a JSON
+	// encoded params.LogRecord.
+	Format string
+	// Fields lists name=value pairs that a log message's fields must all
+	// match for it to be included in the response. The names are those
+	// of the JSON fields of params.LogRecord, and the values may contain
+	// '*' wildcards.
+	Fields []string
+	// AllModels tells the server to send the log messages of all
+	// environments rather than just those of the connected one. Only
+	// the owner of the state server environment may ask for this.
+	AllModels bool
 }
 
 // WatchDebugLog returns a ReadCloser that the caller can read the log
@@ -1071,6 +1448,15 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	attrs["includeModule"] = args.IncludeModule
 	attrs["excludeEntity"] = args.ExcludeEntity
 	attrs["excludeModule"] = args.ExcludeModule
+	attrs["includeMessage"] = args.IncludeMessage
+	attrs["excludeMessage"] = args.ExcludeMessage
+	if args.Format != "" {
+		attrs.Set("format", args.Format)
+	}
+	attrs["field"] = args.Fields
+	if args.AllModels {
+		attrs.Set("allModels", fmt.Sprint(args.AllModels))
+	}
 
 	path := "/log"
 	if _, ok := c.st.ServerVersion(); ok {
@@ -1097,13 +1483,108 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
-	// Read the initial error and translate to a real error.
+	if err := readInitialStreamError(connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// websocketDialStream is called to open the binary websocket streams
+// used by SSHTunnel, so we can override it in tests.
+var websocketDialStream = func(config *websocket.Config) (io.ReadWriteCloser, error) {
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	conn.PayloadType = websocket.BinaryFrame
+	return conn, nil
+}
+
+// SSHTunnel returns a connection to the SSH server on the machine
+// in the environment with the given address, relayed through the
+// API server. The address must belong to a machine in the
+// environment; it is typically the machine's private address, which
+// may not be reachable by the client directly.
+func (c *Client) SSHTunnel(address string) (io.ReadWriteCloser, error) {
+	// The tunnel is only served at the environment path, so the
+	// server must have reported its version at login.
+	if _, ok := c.st.ServerVersion(); !ok {
+		return nil, errors.NotSupportedf("SSHTunnel")
+	}
+	envTag, err := c.st.EnvironTag()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	target := url.URL{
+		Scheme:   "wss",
+		Host:     c.st.addr,
+		Path:     fmt.Sprintf("/environment/%s/sshtunnel", envTag.Id()),
+		RawQuery: url.Values{"address": {address}}.Encode(),
+	}
+	cfg, err := websocket.NewConfig(target.String(), "http://localhost/")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg.Header = utils.BasicAuthHeader(c.st.tag, c.st.password)
+	cfg.TlsConfig = &tls.Config{RootCAs: c.st.certPool, ServerName: "juju-apiserver"}
+	connection, err := websocketDialStream(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := readInitialStreamError(connection); err != nil {
+		connection.Close()
+		return nil, err
+	}
+	return connection, nil
+}
+
+// WatchActionOutput returns a ReadCloser from which the output of the
+// action with the given tag can be read while it runs. Each line holds
+// a JSON-encoded params.ActionOutputMessage; the stream ends once the
+// action has finished and all its output has been sent.
+func (c *Client) WatchActionOutput(tag names.ActionTag) (io.ReadCloser, error) {
+	// The output is only served at the environment path, so the
+	// server must have reported its version at login.
+	if _, ok := c.st.ServerVersion(); !ok {
+		return nil, errors.NotSupportedf("WatchActionOutput")
+	}
+	envTag, err := c.st.EnvironTag()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	target := url.URL{
+		Scheme:   "wss",
+		Host:     c.st.addr,
+		Path:     fmt.Sprintf("/environment/%s/actionoutput", envTag.Id()),
+		RawQuery: url.Values{"action": {tag.String()}}.Encode(),
+	}
+	cfg, err := websocket.NewConfig(target.String(), "http://localhost/")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg.Header = utils.BasicAuthHeader(c.st.tag, c.st.password)
+	cfg.TlsConfig = &tls.Config{RootCAs: c.st.certPool, ServerName: "juju-apiserver"}
+	connection, err := websocketDialConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := readInitialStreamError(connection); err != nil {
+		connection.Close()
+		return nil, err
+	}
+	return connection, nil
+}
+
+// readInitialStreamError reads the JSON-encoded error result that
+// the API server sends as the first line of a websocket stream, and
+// translates it to a real error.
+func readInitialStreamError(conn io.Reader) error {
 	// Read up to the first new line character. We can't use bufio here as it
 	// reads too much from the reader.
 	line := make([]byte, 4096)
-	n, err := connection.Read(line)
+	n, err := conn.Read(line)
 	if err != nil {
-		return nil, errors.Annotate(err, "unable to read initial response")
+		return errors.Annotate(err, "unable to read initial response")
 	}
 	line = line[0:n]
 
@@ -1111,10 +1592,10 @@ func (c *Client) WatchDebugLog(args DebugLogParams) (io.ReadCloser, error) {
 	var errResult params.ErrorResult
 	err = json.Unmarshal(line, &errResult)
 	if err != nil {
-		return nil, errors.Annotate(err, "unable to unmarshal initial response")
+		return errors.Annotate(err, "unable to unmarshal initial response")
 	}
 	if errResult.Error != nil {
-		return nil, errResult.Error
+		return errResult.Error
 	}
-	return connection, nil
+	return nil
 }
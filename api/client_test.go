@@ -14,11 +14,13 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	"golang.org/x/net/websocket"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
@@ -202,6 +204,19 @@ func (s *clientSuite) TestShareEnvironmentExistingUser(c *gc.C) {
 	c.Assert(c.GetTestLog(), jc.Contains, logMsg)
 }
 
+func (s *clientSuite) TestInviteEnvironmentUsers(c *gc.C) {
+	client := s.APIState.Client()
+	shared := s.Factory.MakeEnvUser(c, nil)
+	err := client.InviteEnvironmentUsers(names.NewLocalUserTag("bob"), shared.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	logMsg := fmt.Sprintf("WARNING juju.api %s already has access or an invitation", shared.UserName())
+	c.Assert(c.GetTestLog(), jc.Contains, logMsg)
+
+	invitation, err := s.State.EnvironmentInvitation(names.NewLocalUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitation.UserName(), gc.Equals, "bob@local")
+}
+
 func (s *clientSuite) TestDestroyEnvironment(c *gc.C) {
 	client := s.APIState.Client()
 	var called bool
@@ -213,7 +228,26 @@ func (s *clientSuite) TestDestroyEnvironment(c *gc.C) {
 		})
 	defer cleanup()
 
-	err := client.DestroyEnvironment()
+	err := client.DestroyEnvironment(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestDestroyEnvironmentReleasingStorage(c *gc.C) {
+	client := s.APIState.Client()
+	var called bool
+	cleanup := api.PatchClientFacadeCall(client,
+		func(req string, args interface{}, resp interface{}) error {
+			c.Assert(req, gc.Equals, "DestroyEnvironment")
+			destroyStorage := false
+			c.Assert(args, jc.DeepEquals, params.DestroyEnvironment{DestroyStorage: &destroyStorage})
+			called = true
+			return nil
+		})
+	defer cleanup()
+
+	destroyStorage := false
+	err := client.DestroyEnvironment(&destroyStorage, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(called, jc.IsTrue)
 }
@@ -375,14 +409,19 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
 
 	params := api.DebugLogParams{
-		IncludeEntity: []string{"a", "b"},
-		IncludeModule: []string{"c", "d"},
-		ExcludeEntity: []string{"e", "f"},
-		ExcludeModule: []string{"g", "h"},
-		Limit:         100,
-		Backlog:       200,
-		Level:         loggo.ERROR,
-		Replay:        true,
+		IncludeEntity:  []string{"a", "b"},
+		IncludeModule:  []string{"c", "d"},
+		ExcludeEntity:  []string{"e", "f"},
+		ExcludeModule:  []string{"g", "h"},
+		IncludeMessage: []string{"k"},
+		ExcludeMessage: []string{"l", "m"},
+		Limit:          100,
+		Backlog:        200,
+		Level:          loggo.ERROR,
+		Replay:         true,
+		Format:         "json",
+		Fields:         []string{"env-uuid=i", "module=j"},
+		AllModels:      true,
 	}
 
 	client := s.APIState.Client()
@@ -392,14 +431,19 @@ func (s *clientSuite) TestParamsEncoded(c *gc.C) {
 	connectURL := connectURLFromReader(c, reader)
 	values := connectURL.Query()
 	c.Assert(values, jc.DeepEquals, url.Values{
-		"includeEntity": params.IncludeEntity,
-		"includeModule": params.IncludeModule,
-		"excludeEntity": params.ExcludeEntity,
-		"excludeModule": params.ExcludeModule,
-		"maxLines":      {"100"},
-		"backlog":       {"200"},
-		"level":         {"ERROR"},
-		"replay":        {"true"},
+		"includeEntity":  params.IncludeEntity,
+		"includeModule":  params.IncludeModule,
+		"excludeEntity":  params.ExcludeEntity,
+		"excludeModule":  params.ExcludeModule,
+		"includeMessage": params.IncludeMessage,
+		"excludeMessage": params.ExcludeMessage,
+		"maxLines":       {"100"},
+		"backlog":        {"200"},
+		"level":          {"ERROR"},
+		"replay":         {"true"},
+		"format":         {"json"},
+		"field":          params.Fields,
+		"allModels":      {"true"},
 	})
 }
 
@@ -434,6 +478,64 @@ func (s *clientSuite) TestDebugLogAtUUIDLogPath(c *gc.C) {
 	c.Assert(connectURL.Path, gc.Matches, fmt.Sprintf("/environment/%s/log", environ.UUID()))
 }
 
+func (s *clientSuite) TestWatchActionOutputPath(c *gc.C) {
+	s.PatchValue(api.WebsocketDialConfig, echoURL(c))
+	tag := names.NewActionTag("feedface-0123-4567-8901-2345deadbeef")
+	reader, err := s.APIState.Client().WatchActionOutput(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	connectURL := connectURLFromReader(c, reader)
+	c.Assert(connectURL.Path, gc.Equals, fmt.Sprintf("/environment/%s/actionoutput", s.State.EnvironUUID()))
+	c.Assert(connectURL.Query(), jc.DeepEquals, url.Values{"action": {tag.String()}})
+}
+
+func (s *clientSuite) TestSSHTunnelPath(c *gc.C) {
+	var location *url.URL
+	s.PatchValue(api.WebsocketDialStream, func(config *websocket.Config) (io.ReadWriteCloser, error) {
+		location = config.Location
+		return &fakeStream{Reader: strings.NewReader("{}\n")}, nil
+	})
+	client := s.APIState.Client()
+	conn, err := client.SSHTunnel("10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn, gc.NotNil)
+	c.Assert(location.Path, gc.Equals, fmt.Sprintf("/environment/%s/sshtunnel", s.State.EnvironUUID()))
+	c.Assert(location.Query(), jc.DeepEquals, url.Values{"address": {"10.0.0.1"}})
+}
+
+func (s *clientSuite) TestSSHTunnelError(c *gc.C) {
+	stream := &fakeStream{Reader: strings.NewReader(`{"Error":{"Message":"boom"}}` + "\n")}
+	s.PatchValue(api.WebsocketDialStream, func(*websocket.Config) (io.ReadWriteCloser, error) {
+		return stream, nil
+	})
+	client := s.APIState.Client()
+	conn, err := client.SSHTunnel("10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(conn, gc.IsNil)
+	c.Assert(stream.closed, jc.IsTrue)
+}
+
+func (s *clientSuite) TestSSHTunnelDialError(c *gc.C) {
+	s.PatchValue(api.WebsocketDialStream, func(*websocket.Config) (io.ReadWriteCloser, error) {
+		return nil, fmt.Errorf("bad connection")
+	})
+	client := s.APIState.Client()
+	conn, err := client.SSHTunnel("10.0.0.1")
+	c.Assert(err, gc.ErrorMatches, "bad connection")
+	c.Assert(conn, gc.IsNil)
+}
+
+func (s *clientSuite) TestSSHTunnelNotSupported(c *gc.C) {
+	// Old servers do not serve the environment paths.
+	info := s.APIInfo(c)
+	info.EnvironTag = names.NewEnvironTag("")
+	apistate, err := api.OpenWithVersion(info, api.DialOpts{}, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	defer apistate.Close()
+	conn, err := apistate.Client().SSHTunnel("10.0.0.1")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(conn, gc.IsNil)
+}
+
 func (s *clientSuite) TestOpenUsesEnvironUUIDPaths(c *gc.C) {
 	info := s.APIInfo(c)
 	// Backwards compatibility, passing EnvironTag = "" should just work
@@ -500,6 +602,92 @@ func (s *clientSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Assert(err, gc.Equals, someErr) // Confirms that the correct facade was called
 }
 
+func (s *clientSuite) TestUpgradeInfo(c *gc.C) {
+	client := s.APIState.Client()
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "UpgradeInfo")
+			c.Assert(args, gc.IsNil)
+			result, ok := response.(*params.UpgradeInfo)
+			c.Assert(ok, jc.IsTrue)
+			result.Status = "running"
+			result.StateServersReady = []string{"0", "1"}
+			return nil
+		},
+	)
+	defer cleanup()
+
+	info, err := client.UpgradeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Status, gc.Equals, "running")
+	c.Assert(info.StateServersReady, jc.DeepEquals, []string{"0", "1"})
+}
+
+func (s *clientSuite) TestUpgradeInfoNotFound(c *gc.C) {
+	_, err := s.APIState.Client().UpgradeInfo()
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *clientSuite) TestStartAgentRollout(c *gc.C) {
+	client := s.APIState.Client()
+	target := version.MustParse("1.2.4")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "StartAgentRollout")
+			c.Assert(args, jc.DeepEquals, params.StartAgentRollout{
+				Version:    target,
+				Canaries:   []string{"1", "2"},
+				BakePeriod: time.Hour,
+			})
+			result, ok := response.(*params.AgentRolloutStatus)
+			c.Assert(ok, jc.IsTrue)
+			result.TargetVersion = target
+			result.Phase = "canary"
+			return nil
+		},
+	)
+	defer cleanup()
+
+	status, err := client.StartAgentRollout(target, []string{"1", "2"}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.TargetVersion, gc.Equals, target)
+	c.Assert(status.Phase, gc.Equals, "canary")
+}
+
+func (s *clientSuite) TestPromoteAgentRollout(c *gc.C) {
+	client := s.APIState.Client()
+	someErr := errors.New("random")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "PromoteAgentRollout")
+			c.Assert(args, gc.IsNil)
+			c.Assert(response, gc.IsNil)
+			return someErr
+		},
+	)
+	defer cleanup()
+
+	err := client.PromoteAgentRollout()
+	c.Assert(err, gc.Equals, someErr)
+}
+
+func (s *clientSuite) TestRollBackAgentRollout(c *gc.C) {
+	client := s.APIState.Client()
+	someErr := errors.New("random")
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "RollBackAgentRollout")
+			c.Assert(args, gc.IsNil)
+			c.Assert(response, gc.IsNil)
+			return someErr
+		},
+	)
+	defer cleanup()
+
+	err := client.RollBackAgentRollout()
+	c.Assert(err, gc.Equals, someErr)
+}
+
 func (s *clientSuite) TestEnvironmentGet(c *gc.C) {
 	client := s.APIState.Client()
 	env, err := client.EnvironmentGet()
@@ -571,3 +759,33 @@ func connectURLFromReader(c *gc.C, rc io.ReadCloser) *url.URL {
 	rc.Close()
 	return connectURL
 }
+
+type fakeStream struct {
+	io.Reader
+	closed bool
+}
+
+func (s *fakeStream) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (s *fakeStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *clientSuite) TestStatusContext(c *gc.C) {
+	client := s.APIState.Client()
+	status, err := client.StatusContext(context.Background(), nil, []string{params.StatusSectionMachines})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Services, gc.HasLen, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.StatusContext(ctx, nil, nil)
+	c.Assert(err, gc.Equals, context.Canceled)
+
+	// The connection is still usable after a cancelled call.
+	_, err = client.StatusContext(context.Background(), nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clouds
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Clouds facade, used to manage the
+// clouds that a state server can host environments in.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Clouds client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Clouds")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddCloud adds a cloud with the given name and regions, managed by
+// the given type of provider.
+func (c *Client) AddCloud(name, providerType string, regions []params.CloudRegion) error {
+	args := params.Clouds{
+		Clouds: []params.Cloud{{
+			Name:         name,
+			ProviderType: providerType,
+			Regions:      regions,
+		}},
+	}
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall("AddClouds", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListClouds describes the clouds known to the state server.
+func (c *Client) ListClouds() ([]params.Cloud, error) {
+	var result params.Clouds
+	if err := c.facade.FacadeCall("ListClouds", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Clouds, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clouds_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/clouds"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestAddCloud(c *gc.C) {
+	regions := []params.CloudRegion{{Name: "us-east-1"}}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Clouds")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddClouds")
+			c.Check(a, jc.DeepEquals, params.Clouds{
+				Clouds: []params.Cloud{{
+					Name:         "aws",
+					ProviderType: "ec2",
+					Regions:      regions,
+				}},
+			})
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := clouds.NewClient(apiCaller)
+	err := client.AddCloud("aws", "ec2", regions)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestAddCloudError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	client := clouds.NewClient(apiCaller)
+	err := client.AddCloud("aws", "ec2", nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestListClouds(c *gc.C) {
+	info := []params.Cloud{{
+		Name:         "aws",
+		ProviderType: "ec2",
+		Regions:      []params.CloudRegion{{Name: "us-east-1"}},
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "ListClouds")
+			c.Check(a, gc.IsNil)
+			*response.(*params.Clouds) = params.Clouds{Clouds: info}
+			return nil
+		})
+	client := clouds.NewClient(apiCaller)
+	result, err := client.ListClouds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, info)
+}
+
+func (s *clientSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("blam")
+		})
+	client := clouds.NewClient(apiCaller)
+	_, err := client.ListClouds()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
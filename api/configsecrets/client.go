@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configsecrets
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the ConfigSecrets facade, used by
+// environment administrators to see the secret values in the
+// environment's configuration.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new ConfigSecrets client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ConfigSecrets")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Reveal returns the values of the given secret attributes of the
+// environment's configuration, or of all its secret attributes if
+// none are given.
+func (c *Client) Reveal(keys ...string) (map[string]interface{}, error) {
+	var result params.EnvironmentConfigResults
+	args := params.RevealConfigSecrets{Keys: keys}
+	if err := c.facade.FacadeCall("Reveal", args, &result); err != nil {
+		return nil, err
+	}
+	return result.Config, nil
+}
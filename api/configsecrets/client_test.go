@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configsecrets_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/configsecrets"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestReveal(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "ConfigSecrets")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Reveal")
+			c.Check(a, jc.DeepEquals, params.RevealConfigSecrets{
+				Keys: []string{"admin-secret"},
+			})
+			*response.(*params.EnvironmentConfigResults) = params.EnvironmentConfigResults{
+				Config: map[string]interface{}{"admin-secret": "sekrit"},
+			}
+			return nil
+		})
+	client := configsecrets.NewClient(apiCaller)
+	attrs, err := client.Reveal("admin-secret")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(attrs, jc.DeepEquals, map[string]interface{}{"admin-secret": "sekrit"})
+}
+
+func (s *clientSuite) TestRevealAll(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(_ string, _ int, _, _ string, a, _ interface{}) error {
+			c.Check(a, jc.DeepEquals, params.RevealConfigSecrets{})
+			return nil
+		})
+	client := configsecrets.NewClient(apiCaller)
+	_, err := client.Reveal()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestRevealError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("permission denied")
+		})
+	client := configsecrets.NewClient(apiCaller)
+	_, err := client.Reveal("admin-secret")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package consistency
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Consistency facade, used to check the
+// referential integrity of the state database.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Consistency client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Consistency")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CheckConsistency returns the problems found in the state database
+// for the environment. If repair is true, problems that can be safely
+// repaired are repaired.
+func (c *Client) CheckConsistency(repair bool) ([]params.Inconsistency, error) {
+	args := params.CheckConsistencyArgs{Repair: repair}
+	var report params.ConsistencyReport
+	if err := c.facade.FacadeCall("CheckConsistency", args, &report); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return report.Inconsistencies, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package consistency_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/consistency"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestCheckConsistency(c *gc.C) {
+	inconsistencies := []params.Inconsistency{{
+		Kind:       "orphaned-settings",
+		Collection: "settings",
+		DocID:      "uuid:s#wordpress#cs:quantal/wordpress-3",
+		Detail:     `settings of missing service "wordpress"`,
+		Repairable: true,
+		Repaired:   true,
+	}}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Consistency")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CheckConsistency")
+			c.Check(a, jc.DeepEquals, params.CheckConsistencyArgs{Repair: true})
+			*response.(*params.ConsistencyReport) = params.ConsistencyReport{
+				Inconsistencies: inconsistencies,
+			}
+			return nil
+		})
+	client := consistency.NewClient(apiCaller)
+	result, err := client.CheckConsistency(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, inconsistencies)
+}
+
+func (s *clientSuite) TestCheckConsistencyError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := consistency.NewClient(apiCaller)
+	_, err := client.CheckConsistency(false)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerusage
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the ControllerUsage facade, used to report
+// the API usage of the environments hosted by the state server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new ControllerUsage client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ControllerUsage")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// EnvironmentUsage returns the API usage of each environment over the
+// given number of hours, busiest first.
+func (c *Client) EnvironmentUsage(hours int) ([]params.EnvironmentUsage, error) {
+	args := params.EnvironmentUsageArgs{Hours: hours}
+	var result params.EnvironmentUsageResults
+	if err := c.facade.FacadeCall("EnvironmentUsage", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerusage_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controllerusage"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestEnvironmentUsage(c *gc.C) {
+	usage := []params.EnvironmentUsage{{
+		EnvironTag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		Name:       "busy",
+		Calls:      100,
+		BytesIn:    1000,
+		BytesOut:   10000,
+	}}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "ControllerUsage")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "EnvironmentUsage")
+			c.Check(a, jc.DeepEquals, params.EnvironmentUsageArgs{Hours: 24})
+			*response.(*params.EnvironmentUsageResults) = params.EnvironmentUsageResults{
+				Environments: usage,
+			}
+			return nil
+		})
+	client := controllerusage.NewClient(apiCaller)
+	result, err := client.EnvironmentUsage(24)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, usage)
+}
+
+func (s *clientSuite) TestEnvironmentUsageError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := controllerusage.NewClient(apiCaller)
+	_, err := client.EnvironmentUsage(24)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Credentials facade, used to manage
+// the provider credentials stored by the state server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Credentials client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Credentials")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddCredential adds a credential with the given name, for use by
+// environments of the given provider type.
+func (c *Client) AddCredential(name, providerType string, attrs map[string]string) error {
+	args := params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:         name,
+			ProviderType: providerType,
+			Attributes:   attrs,
+		}},
+	}
+	return c.oneError("AddCredential", args)
+}
+
+// UpdateCredential replaces the attributes of the named credential.
+func (c *Client) UpdateCredential(name string, attrs map[string]string) error {
+	args := params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:       name,
+			Attributes: attrs,
+		}},
+	}
+	return c.oneError("UpdateCredential", args)
+}
+
+func (c *Client) oneError(method string, args params.CloudCredentials) error {
+	var results params.ErrorResults
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListCredentials describes the credentials stored by the state server.
+func (c *Client) ListCredentials() ([]params.CloudCredentialInfo, error) {
+	var result params.CloudCredentialInfoResults
+	if err := c.facade.FacadeCall("ListCredentials", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Credentials, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/credentials"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestAddCredential(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Credentials")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddCredential")
+			c.Check(a, jc.DeepEquals, params.CloudCredentials{
+				Credentials: []params.CloudCredential{{
+					Name:         "shared",
+					ProviderType: "ec2",
+					Attributes:   map[string]string{"access-key": "x"},
+				}},
+			})
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	client := credentials.NewClient(apiCaller)
+	err := client.AddCredential("shared", "ec2", map[string]string{"access-key": "x"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *clientSuite) TestUpdateCredential(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "UpdateCredential")
+			c.Check(a, jc.DeepEquals, params.CloudCredentials{
+				Credentials: []params.CloudCredential{{
+					Name:       "shared",
+					Attributes: map[string]string{"access-key": "y"},
+				}},
+			})
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	client := credentials.NewClient(apiCaller)
+	err := client.UpdateCredential("shared", map[string]string{"access-key": "y"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestListCredentials(c *gc.C) {
+	info := []params.CloudCredentialInfo{{
+		Name:           "shared",
+		OwnerTag:       "user-admin@local",
+		ProviderType:   "ec2",
+		AttributeNames: []string{"access-key", "secret-key"},
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(request, gc.Equals, "ListCredentials")
+			c.Check(a, gc.IsNil)
+			*response.(*params.CloudCredentialInfoResults) = params.CloudCredentialInfoResults{
+				Credentials: info,
+			}
+			return nil
+		})
+	client := credentials.NewClient(apiCaller)
+	result, err := client.ListCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, info)
+}
+
+func (s *clientSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("blam")
+		})
+	client := credentials.NewClient(apiCaller)
+	_, err := client.ListCredentials()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
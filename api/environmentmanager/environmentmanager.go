@@ -51,14 +51,24 @@ func (c *Client) ConfigSkeleton(provider, region string) (params.EnvironConfig,
 // CreateEnvironment creates a new environment using the account and
 // environment config specified in the args.
 func (c *Client) CreateEnvironment(owner string, account, config map[string]interface{}) (params.Environment, error) {
+	return c.CreateEnvironmentInCloud(owner, "", "", account, config)
+}
+
+// CreateEnvironmentInCloud creates a new environment hosted in the
+// given cloud and region, using the account and environment config
+// specified in the args. If cloud is empty, the environment is hosted
+// in the same cloud as the state server.
+func (c *Client) CreateEnvironmentInCloud(owner, cloud, region string, account, config map[string]interface{}) (params.Environment, error) {
 	var result params.Environment
 	if !names.IsValidUser(owner) {
 		return result, fmt.Errorf("invalid owner name %q", owner)
 	}
 	createArgs := params.EnvironmentCreateArgs{
-		OwnerTag: names.NewUserTag(owner).String(),
-		Account:  account,
-		Config:   config,
+		OwnerTag:    names.NewUserTag(owner).String(),
+		Account:     account,
+		Config:      config,
+		Cloud:       cloud,
+		CloudRegion: region,
 	}
 	err := c.facade.FacadeCall("CreateEnvironment", createArgs, &result)
 	if err != nil {
@@ -84,3 +94,56 @@ func (c *Client) ListEnvironments(user string) ([]params.Environment, error) {
 	}
 	return result.Environments, nil
 }
+
+// ListInvitations returns the pending invitations for the specified
+// user to access environments in the current server. Only the state
+// server owner can list the invitations of other users.
+func (c *Client) ListInvitations(user string) ([]params.EnvironmentInvitation, error) {
+	var result params.EnvironmentInvitationList
+	if !names.IsValidUser(user) {
+		return nil, fmt.Errorf("invalid user name %q", user)
+	}
+	entity := params.Entity{names.NewUserTag(user).String()}
+	err := c.facade.FacadeCall("ListInvitations", entity, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Invitations, nil
+}
+
+// AcceptInvitations accepts the logged in user's pending invitations
+// to access the environments with the given UUIDs.
+func (c *Client) AcceptInvitations(envUUIDs ...string) error {
+	var args params.Entities
+	for _, uuid := range envUUIDs {
+		if !names.IsValidEnvironment(uuid) {
+			return fmt.Errorf("invalid environment UUID %q", uuid)
+		}
+		args.Entities = append(args.Entities, params.Entity{names.NewEnvironTag(uuid).String()})
+	}
+	var result params.ErrorResults
+	err := c.facade.FacadeCall("AcceptInvitations", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.Combine()
+}
+
+// TransferEnvironmentOwnership makes the specified user the owner of the
+// environment with the given UUID. If revokePrevious is true, the
+// previous owner loses access to the environment. Only the current
+// owner of the environment or the state server owner can transfer it.
+func (c *Client) TransferEnvironmentOwnership(envUUID, newOwner string, revokePrevious bool) error {
+	if !names.IsValidEnvironment(envUUID) {
+		return fmt.Errorf("invalid environment UUID %q", envUUID)
+	}
+	if !names.IsValidUser(newOwner) {
+		return fmt.Errorf("invalid user name %q", newOwner)
+	}
+	args := params.TransferEnvironmentOwnership{
+		EnvironTag:     names.NewEnvironTag(envUUID).String(),
+		OwnerTag:       names.NewUserTag(newOwner).String(),
+		RevokePrevious: revokePrevious,
+	}
+	return c.facade.FacadeCall("TransferEnvironmentOwnership", args, nil)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -93,6 +94,33 @@ func (s *environmentmanagerSuite) TestCreateEnvironment(c *gc.C) {
 	c.Assert(utils.IsValidUUIDString(newEnv.UUID), jc.IsTrue)
 }
 
+func (s *environmentmanagerSuite) TestCreateEnvironmentInCloud(c *gc.C) {
+	s.SetFeatureFlags(feature.JES)
+	_, err := s.State.AddCloud("other", "dummy", []state.CloudRegion{{Name: "north"}})
+	c.Assert(err, jc.ErrorIsNil)
+	envManager := s.OpenAPI(c)
+	user := s.Factory.MakeUser(c, nil)
+	owner := user.UserTag().Username()
+	newEnv, err := envManager.CreateEnvironmentInCloud(owner, "other", "north", nil, map[string]interface{}{
+		"name":            "new-env",
+		"authorized-keys": "ssh-key",
+		// dummy needs state-server
+		"state-server": false,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newEnv.Name, gc.Equals, "new-env")
+
+	st, err := s.State.ForEnviron(names.NewEnvironTag(newEnv.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	cloud, region, ok := env.Cloud()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(cloud, gc.Equals, "other")
+	c.Assert(region, gc.Equals, "north")
+}
+
 func (s *environmentmanagerSuite) TestListEnvironmentsBadUser(c *gc.C) {
 	envManager := s.OpenAPI(c)
 	_, err := envManager.ListEnvironments("not a user")
@@ -115,3 +143,56 @@ func (s *environmentmanagerSuite) TestListEnvironments(c *gc.C) {
 	envNames := []string{envs[0].Name, envs[1].Name}
 	c.Assert(envNames, jc.SameContents, []string{"first", "second"})
 }
+
+func (s *environmentmanagerSuite) TestInvitations(c *gc.C) {
+	s.SetFeatureFlags(feature.JES)
+	otherState := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Name: "shared", Owner: names.NewUserTag("user@remote")})
+	defer otherState.Close()
+	_, err := otherState.InviteEnvironmentUser(s.AdminUserTag(c), names.NewUserTag("user@remote"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	envManager := s.OpenAPI(c)
+	invitations, err := envManager.ListInvitations(s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitations, gc.HasLen, 1)
+	c.Assert(invitations[0].EnvironName, gc.Equals, "shared")
+	c.Assert(invitations[0].CreatedBy, gc.Equals, "user@remote")
+
+	err = envManager.AcceptInvitations(otherState.EnvironUUID())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = otherState.EnvironmentUser(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	invitations, err = envManager.ListInvitations(s.AdminUserTag(c).Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitations, gc.HasLen, 0)
+}
+
+func (s *environmentmanagerSuite) TestAcceptInvitationsBadUUID(c *gc.C) {
+	envManager := s.OpenAPI(c)
+	err := envManager.AcceptInvitations("not-a-uuid")
+	c.Assert(err, gc.ErrorMatches, `invalid environment UUID "not-a-uuid"`)
+}
+
+func (s *environmentmanagerSuite) TestTransferEnvironmentOwnership(c *gc.C) {
+	s.SetFeatureFlags(feature.JES)
+	s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	otherState := s.Factory.MakeEnvironment(c, &factory.EnvParams{
+		Name: "shared", Owner: names.NewUserTag("user@remote")})
+	defer otherState.Close()
+
+	envManager := s.OpenAPI(c)
+	err := envManager.TransferEnvironmentOwnership(otherState.EnvironUUID(), "bob", true)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := otherState.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Owner().Username(), gc.Equals, "bob@local")
+}
+
+func (s *environmentmanagerSuite) TestTransferEnvironmentOwnershipBadArgs(c *gc.C) {
+	envManager := s.OpenAPI(c)
+	err := envManager.TransferEnvironmentOwnership("not-a-uuid", "bob", false)
+	c.Assert(err, gc.ErrorMatches, `invalid environment UUID "not-a-uuid"`)
+	err = envManager.TransferEnvironmentOwnership(coretesting.EnvironmentTag.Id(), "not a user", false)
+	c.Assert(err, gc.ErrorMatches, `invalid user name "not a user"`)
+}
@@ -4,6 +4,8 @@
 package api
 
 import (
+	"golang.org/x/net/context"
+
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/network"
 )
@@ -12,10 +14,12 @@ var (
 	NewWebsocketDialer    = newWebsocketDialer
 	NewWebsocketDialerPtr = &newWebsocketDialer
 	WebsocketDialConfig   = &websocketDialConfig
+	WebsocketDialStream   = &websocketDialStream
 	SlideAddressToFront   = slideAddressToFront
 	BestVersion           = bestVersion
 	FacadeVersions        = &facadeVersions
 	NewHTTPClient         = &newHTTPClient
+	SessionRefreshMargin  = &sessionRefreshMargin
 )
 
 // SetServerAddress allows changing the URL to the internal API server
@@ -86,6 +90,10 @@ func (f *resultCaller) FacadeCall(request string, params, response interface{})
 	return f.mockCall(request, params, response)
 }
 
+func (f *resultCaller) FacadeCallContext(_ context.Context, request string, params, response interface{}) error {
+	return f.mockCall(request, params, response)
+}
+
 func (f *resultCaller) Name() string {
 	return ""
 }
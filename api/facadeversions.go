@@ -11,15 +11,23 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
+	"APISchema":                    1,
 	"Action":                       0,
-	"Agent":                        1,
+	"Agent":                        2,
+	"AllEnvWatcher":                1,
 	"AllWatcher":                   0,
 	"Annotations":                  1,
 	"Backups":                      0,
 	"Block":                        1,
 	"Charms":                       1,
 	"CharmRevisionUpdater":         0,
+	"CharmValidation":              1,
 	"Client":                       0,
+	"Clouds":                       1,
+	"ConfigSecrets":                1,
+	"Consistency":                  1,
+	"ControllerUsage":              1,
+	"Credentials":                  1,
 	"Deployer":                     0,
 	"DiskManager":                  1,
 	"Environment":                  0,
@@ -27,7 +35,9 @@ var facadeVersions = map[string]int{
 	"FilesystemAttachmentsWatcher": 1,
 	"Firewaller":                   1,
 	"HighAvailability":             1,
+	"HostKeyReporter":              1,
 	"ImageManager":                 1,
+	"Introspection":                1,
 	"KeyManager":                   0,
 	"KeyUpdater":                   0,
 	"LeadershipService":            1,
@@ -39,17 +49,23 @@ var facadeVersions = map[string]int{
 	"NotifyWatcher":                0,
 	"Pinger":                       0,
 	"Provisioner":                  0,
+	"ProvisioningErrors":           1,
 	"Reboot":                       1,
 	"RelationUnitsWatcher":         0,
 	"Rsyslog":                      0,
+	"Scale":                        1,
 	"Service":                      1,
 	"Storage":                      1,
 	"StorageProvisioner":           1,
 	"StringsWatcher":               0,
+	"SystemManager":                1,
+	"UpgradeChecks":                1,
 	"Upgrader":                     0,
-	"Uniter":                       2,
+	"Uniter":                       3,
+	"UnitUtilization":              1,
 	"UserManager":                  0,
 	"VolumeAttachmentsWatcher":     1,
+	"Webhooks":                     1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
@@ -75,3 +75,26 @@ func (s *Service) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// ExposedEndpoints returns the endpoints to which the exposure of the
+// service is restricted, keyed by endpoint name. If the service is
+// exposed and no endpoints are returned, every opened port is exposed
+// to everyone.
+func (s *Service) ExposedEndpoints() (map[string]params.ExposedEndpoint, error) {
+	var results params.ExposedEndpointsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposedEndpoints", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Endpoints, nil
+}
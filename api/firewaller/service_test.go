@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 )
 
@@ -94,3 +95,19 @@ func (s *serviceSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *serviceSuite) TestExposedEndpoints(c *gc.C) {
+	endpoints, err := s.apiService.ExposedEndpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoints, gc.HasLen, 0)
+
+	err = s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"url": {Ports: []string{"443/tcp"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	endpoints, err = s.apiService.ExposedEndpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoints, jc.DeepEquals, map[string]params.ExposedEndpoint{
+		"url": {Ports: []string{"443/tcp"}},
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter provides the client side of the
+// HostKeyReporter facade, used by machine agents to report their SSH
+// host keys.
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Facade provides access to the HostKeyReporter API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side HostKeyReporter facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "HostKeyReporter"),
+	}
+}
+
+// ReportKeys reports the public SSH host keys for a machine to the
+// API server.
+func (f *Facade) ReportKeys(machineId string, publicKeys []string) error {
+	args := params.SSHHostKeySet{EntityKeys: []params.SSHHostKeys{{
+		Tag:        names.NewMachineTag(machineId).String(),
+		PublicKeys: publicKeys,
+	}}}
+	var result params.ErrorResults
+	err := f.caller.FacadeCall("ReportKeys", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/hostkeyreporter"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestReportKeys(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "HostKeyReporter")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ReportKeys")
+			c.Check(a, jc.DeepEquals, params.SSHHostKeySet{
+				EntityKeys: []params.SSHHostKeys{{
+					Tag:        "machine-42",
+					PublicKeys: []string{"rsa", "dsa"},
+				}},
+			})
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	facade := hostkeyreporter.NewFacade(apiCaller)
+	err := facade.ReportKeys("42", []string{"rsa", "dsa"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("blam")
+		})
+	facade := hostkeyreporter.NewFacade(apiCaller)
+	err := facade.ReportKeys("42", []string{"rsa", "dsa"})
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestReportKeysError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(_ string, _ int, _, _ string, _, response interface{}) error {
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	facade := hostkeyreporter.NewFacade(apiCaller)
+	err := facade.ReportKeys("42", []string{"rsa", "dsa"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
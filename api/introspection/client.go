@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Introspection facade, used to inspect
+// the workers running in the agent that hosts the API server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Introspection client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Introspection")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// WorkerReports returns reports on the workers running in the agent
+// with the given tag.
+func (c *Client) WorkerReports(tag names.Tag) ([]params.AgentWorkerReport, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	var results params.AgentWorkerReportsResults
+	if err := c.facade.FacadeCall("WorkerReports", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Reports, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/introspection"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestWorkerReports(c *gc.C) {
+	reports := []params.AgentWorkerReport{{
+		Name:       "api",
+		State:      "started",
+		StartCount: 1,
+	}}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Introspection")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "WorkerReports")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-0"}},
+			})
+			*response.(*params.AgentWorkerReportsResults) = params.AgentWorkerReportsResults{
+				Results: []params.AgentWorkerReportsResult{{Reports: reports}},
+			}
+			return nil
+		})
+	client := introspection.NewClient(apiCaller)
+	result, err := client.WorkerReports(names.NewMachineTag("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, reports)
+}
+
+func (s *clientSuite) TestWorkerReportsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			*response.(*params.AgentWorkerReportsResults) = params.AgentWorkerReportsResults{
+				Results: []params.AgentWorkerReportsResult{{
+					Error: &params.Error{Message: "agent not found", Code: params.CodeNotFound},
+				}},
+			}
+			return nil
+		})
+	client := introspection.NewClient(apiCaller)
+	_, err := client.WorkerReports(names.NewMachineTag("1"))
+	c.Assert(err, gc.ErrorMatches, "agent not found")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
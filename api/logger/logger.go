@@ -27,11 +27,23 @@ func NewState(caller base.APICaller) *State {
 // LoggingConfig returns the loggo configuration string for the agent
 // specified by agentTag.
 func (st *State) LoggingConfig(agentTag names.Tag) (string, error) {
+	return st.stringResult("LoggingConfig", agentTag)
+}
+
+// LoggingFormat returns the format, either "text" or "json", in which
+// the agent specified by agentTag should write its log messages.
+func (st *State) LoggingFormat(agentTag names.Tag) (string, error) {
+	return st.stringResult("LoggingFormat", agentTag)
+}
+
+// stringResult calls the given facade method for the agent specified
+// by agentTag and returns its single string result.
+func (st *State) stringResult(method string, agentTag names.Tag) (string, error) {
 	var results params.StringResults
 	args := params.Entities{
 		Entities: []params.Entity{{Tag: agentTag.String()}},
 	}
-	err := st.facade.FacadeCall("LoggingConfig", args, &results)
+	err := st.facade.FacadeCall(method, args, &results)
 	if err != nil {
 		// TODO: Not directly tested
 		return "", err
@@ -51,6 +51,24 @@ func (s *loggerSuite) TestLoggingConfig(c *gc.C) {
 	c.Assert(config, gc.Not(gc.Equals), "")
 }
 
+func (s *loggerSuite) TestLoggingFormatWrongMachine(c *gc.C) {
+	format, err := s.logger.LoggingFormat(names.NewMachineTag("42"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(format, gc.Equals, "")
+}
+
+func (s *loggerSuite) TestLoggingFormat(c *gc.C) {
+	format, err := s.logger.LoggingFormat(s.rawMachine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(format, gc.Equals, "text")
+
+	err = s.BackingState.UpdateEnvironConfig(map[string]interface{}{"logging-format": "json"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	format, err = s.logger.LoggingFormat(s.rawMachine.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(format, gc.Equals, "json")
+}
+
 func (s *loggerSuite) setLoggingConfig(c *gc.C, loggingConfig string) {
 	err := s.BackingState.UpdateEnvironConfig(map[string]interface{}{"logging-config": loggingConfig}, nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -4,7 +4,10 @@
 package machinemanager
 
 import (
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
@@ -36,3 +39,110 @@ func (client *Client) AddMachines(machineParams []params.AddMachineParams) ([]pa
 	}
 	return results.Machines, err
 }
+
+// PrepareSeriesUpgrade starts an in-place upgrade of the given machine
+// to the given series, asking the units on the machine to run their
+// pre-series-upgrade hooks.
+func (client *Client) PrepareSeriesUpgrade(machineId, series string) error {
+	args := params.UpgradeSeriesParams{
+		Params: []params.UpgradeSeriesParam{{
+			Entity: params.Entity{Tag: names.NewMachineTag(machineId).String()},
+			Series: series,
+		}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("PrepareSeriesUpgrade", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// CompleteSeriesUpgrade records that the operating system of the given
+// machine has been upgraded, asking the units on the machine to run
+// their post-series-upgrade hooks.
+func (client *Client) CompleteSeriesUpgrade(machineId string) error {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("CompleteSeriesUpgrade", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RebootMachine asks the agent of the given machine to reboot it once
+// no hooks are running on it. Unless force is true, the request is
+// refused if rebooting the machine would leave the environment without
+// a majority of its state servers.
+func (client *Client) RebootMachine(machineId string, force bool) error {
+	args := params.RebootMachinesParams{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+		Force:    force,
+	}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("RebootMachines", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// RebootStatus returns the progress of the reboot requested for the
+// given machine, or an empty status if no reboot is pending.
+func (client *Client) RebootStatus(machineId string) (params.RebootStatus, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}},
+	}
+	var results params.RebootStatusResults
+	if err := client.facade.FacadeCall("RebootStatus", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", err
+	}
+	return results.Results[0].Status, nil
+}
+
+// ScheduleMachineRemovals schedules the given machines to be destroyed
+// once the grace period has passed, unless the removals are cancelled
+// with CancelMachineRemovals first. There is a result for each machine,
+// holding the time at which it will be destroyed.
+func (client *Client) ScheduleMachineRemovals(gracePeriod time.Duration, machineIds ...string) ([]params.ScheduledRemovalResult, error) {
+	args := params.ScheduleMachineRemovalsParams{
+		Entities:    machineEntities(machineIds),
+		GracePeriod: gracePeriod,
+	}
+	var results params.ScheduledRemovalResults
+	if err := client.facade.FacadeCall("ScheduleMachineRemovals", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machineIds) {
+		return nil, errors.Errorf("expected %d results, got %d", len(machineIds), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// CancelMachineRemovals cancels the scheduled removals of the given
+// machines. There is a result for each machine.
+func (client *Client) CancelMachineRemovals(machineIds ...string) ([]params.ErrorResult, error) {
+	args := params.Entities{Entities: machineEntities(machineIds)}
+	var results params.ErrorResults
+	if err := client.facade.FacadeCall("CancelMachineRemovals", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(machineIds) {
+		return nil, errors.Errorf("expected %d results, got %d", len(machineIds), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+func machineEntities(machineIds []string) []params.Entity {
+	entities := make([]params.Entity, len(machineIds))
+	for i, id := range machineIds {
+		entities[i].Tag = names.NewMachineTag(id).String()
+	}
+	return entities
+}
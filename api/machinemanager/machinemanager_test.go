@@ -6,6 +6,7 @@ package machinemanager_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -116,3 +117,165 @@ func (s *MachinemanagerSuite) TestAddMachinesResultCountInvalid(c *gc.C) {
 		c.Check(err, gc.ErrorMatches, fmt.Sprintf("expected 1 result, got %d", n))
 	}
 }
+
+func (s *MachinemanagerSuite) TestPrepareSeriesUpgrade(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "PrepareSeriesUpgrade")
+		c.Check(arg, gc.DeepEquals, params.UpgradeSeriesParams{
+			Params: []params.UpgradeSeriesParam{{
+				Entity: params.Entity{Tag: "machine-1"},
+				Series: "trusty",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "MSG"}}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.PrepareSeriesUpgrade("1", "trusty")
+	c.Check(err, gc.ErrorMatches, "MSG")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestCompleteSeriesUpgrade(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "CompleteSeriesUpgrade")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.CompleteSeriesUpgrade("1")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestCompleteSeriesUpgradeClientError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("blargh")
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.CompleteSeriesUpgrade("1")
+	c.Check(err, gc.ErrorMatches, "blargh")
+}
+
+func (s *MachinemanagerSuite) TestRebootMachine(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "RebootMachines")
+		c.Check(arg, gc.DeepEquals, params.RebootMachinesParams{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+			Force:    true,
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		callCount++
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	err := st.RebootMachine("1", true)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *MachinemanagerSuite) TestRebootStatus(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "RebootStatus")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.RebootStatusResults{})
+		*(result.(*params.RebootStatusResults)) = params.RebootStatusResults{
+			Results: []params.RebootStatusResult{{Status: params.RebootDraining}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	status, err := st.RebootStatus("1")
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(status, gc.Equals, params.RebootDraining)
+}
+
+func (s *MachinemanagerSuite) TestRebootStatusServerError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.RebootStatusResults)) = params.RebootStatusResults{
+			Results: []params.RebootStatusResult{{Error: &params.Error{Message: "machine 1 not found"}}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	_, err := st.RebootStatus("1")
+	c.Check(err, gc.ErrorMatches, "machine 1 not found")
+}
+
+func (s *MachinemanagerSuite) TestScheduleMachineRemovals(c *gc.C) {
+	due := time.Date(2015, 10, 14, 14, 0, 0, 0, time.UTC)
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "ScheduleMachineRemovals")
+		c.Check(arg, gc.DeepEquals, params.ScheduleMachineRemovalsParams{
+			Entities:    []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}},
+			GracePeriod: 2 * time.Hour,
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ScheduledRemovalResults{})
+		*(result.(*params.ScheduledRemovalResults)) = params.ScheduledRemovalResults{
+			Results: []params.ScheduledRemovalResult{
+				{Due: due},
+				{Error: &params.Error{Message: "boom"}},
+			},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	results, err := st.ScheduleMachineRemovals(2*time.Hour, "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ScheduledRemovalResult{
+		{Due: due},
+		{Error: &params.Error{Message: "boom"}},
+	})
+}
+
+func (s *MachinemanagerSuite) TestCancelMachineRemovals(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineManager")
+		c.Check(request, gc.Equals, "CancelMachineRemovals")
+		c.Check(arg, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	results, err := st.CancelMachineRemovals("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{{}})
+}
+
+func (s *MachinemanagerSuite) TestCancelMachineRemovalsResultCount(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return nil
+	})
+	st := machinemanager.NewClient(apiCaller)
+	_, err := st.CancelMachineRemovals("1")
+	c.Assert(err, gc.ErrorMatches, "expected 1 results, got 0")
+}
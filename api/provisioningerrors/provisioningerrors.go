@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package provisioningerrors provides the client side of the
+// ProvisioningErrors facade, used by the state server to find machines
+// stuck in provisioning errors.
+package provisioningerrors
+
+import (
+	"time"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const provisioningErrorsFacade = "ProvisioningErrors"
+
+// Facade provides access to the ProvisioningErrors API facade.
+type Facade struct {
+	*common.EnvironWatcher
+	facade base.FacadeCaller
+}
+
+// NewFacade creates a new client-side ProvisioningErrors facade.
+func NewFacade(caller base.APICaller) *Facade {
+	facadeCaller := base.NewFacadeCaller(caller, provisioningErrorsFacade)
+	return &Facade{
+		EnvironWatcher: common.NewEnvironWatcher(facadeCaller),
+		facade:         facadeCaller,
+	}
+}
+
+// WatchMachineStatuses returns a StringsWatcher that notifies of the
+// ids of the machines whose statuses change.
+func (f *Facade) WatchMachineStatuses() (watcher.StringsWatcher, error) {
+	var result params.StringsWatchResult
+	err := f.facade.FacadeCall("WatchMachineStatuses", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return watcher.NewStringsWatcher(f.facade.RawAPICaller(), result), nil
+}
+
+// ProvisioningErrors returns the machines that have been stuck in
+// provisioning errors for at least the given threshold.
+func (f *Facade) ProvisioningErrors(threshold time.Duration) ([]params.ProvisioningError, error) {
+	args := params.ProvisioningErrorsArgs{Threshold: threshold}
+	var result params.ProvisioningErrorsResult
+	if err := f.facade.FacadeCall("ProvisioningErrors", args, &result); err != nil {
+		return nil, err
+	}
+	return result.Errors, nil
+}
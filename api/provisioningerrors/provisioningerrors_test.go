@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioningerrors_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/provisioningerrors"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) TestProvisioningErrors(c *gc.C) {
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "ProvisioningErrors")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ProvisioningErrors")
+			c.Check(a, jc.DeepEquals, params.ProvisioningErrorsArgs{Threshold: time.Minute})
+			*response.(*params.ProvisioningErrorsResult) = params.ProvisioningErrorsResult{
+				Errors: []params.ProvisioningError{{
+					MachineTag: "machine-1",
+					Message:    "cannot start instance",
+					Since:      since,
+				}},
+			}
+			return nil
+		})
+	facade := provisioningerrors.NewFacade(apiCaller)
+	stuck, err := facade.ProvisioningErrors(time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(stuck, jc.DeepEquals, []params.ProvisioningError{{
+		MachineTag: "machine-1",
+		Message:    "cannot start instance",
+		Since:      since,
+	}})
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("blam")
+		})
+	facade := provisioningerrors.NewFacade(apiCaller)
+	_, err := facade.ProvisioningErrors(time.Minute)
+	c.Assert(err, gc.ErrorMatches, "blam")
+	_, err = facade.WatchMachineStatuses()
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
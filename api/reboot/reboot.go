@@ -89,6 +89,24 @@ func (st *State) ClearReboot() error {
 	return nil
 }
 
+// SetRebootStatus records the progress of the reboot requested for
+// the calling machine.
+func (st *State) SetRebootStatus(status params.RebootStatus) error {
+	var results params.ErrorResults
+	args := params.RebootStatusParams{
+		Params: []params.RebootStatusParam{{
+			Entity: params.Entity{Tag: st.machineTag.String()},
+			Status: status,
+		}},
+	}
+
+	err := st.facade.FacadeCall("SetRebootStatus", args, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // GetRebootAction returns the reboot action for the calling machine
 func (st *State) GetRebootAction() (params.RebootAction, error) {
 	var results params.RebootActionResults
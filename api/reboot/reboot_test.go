@@ -179,3 +179,21 @@ func (s *machineRebootSuite) TestClearRebootError(c *gc.C) {
 	err := s.reboot.ClearReboot()
 	c.Assert(err.Error(), gc.Equals, "Some error.")
 }
+
+func (s *machineRebootSuite) TestSetRebootStatus(c *gc.C) {
+	reboot.PatchFacadeCall(s, s.reboot, func(request string, p interface{}, resp interface{}) error {
+		c.Check(request, gc.Equals, "SetRebootStatus")
+		c.Check(p, jc.DeepEquals, params.RebootStatusParams{
+			Params: []params.RebootStatusParam{{
+				Entity: params.Entity{Tag: s.machine.Tag().String()},
+				Status: params.RebootDraining,
+			}},
+		})
+		if resp, ok := resp.(*params.ErrorResults); ok {
+			resp.Results = []params.ErrorResult{{}}
+		}
+		return nil
+	})
+	err := s.reboot.SetRebootStatus(params.RebootDraining)
+	c.Assert(err, jc.ErrorIsNil)
+}
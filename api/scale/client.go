@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package scale
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Scale facade, used by external scalers
+// to drive the number of units of services.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Scale client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Scale")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetDesiredUnits scales the given service to n live units, recording
+// the given reason. If token is not empty, retrying the call with the
+// same token returns the result of the first call.
+func (c *Client) SetDesiredUnits(service string, n int, reason, token string) (params.DesiredUnitsResult, error) {
+	args := params.DesiredUnitsArgs{
+		Entries: []params.DesiredUnits{{
+			ServiceTag: names.NewServiceTag(service).String(),
+			NumUnits:   n,
+			Reason:     reason,
+			Token:      token,
+		}},
+	}
+	var results params.DesiredUnitsResults
+	if err := c.facade.FacadeCall("SetDesiredUnits", args, &results); err != nil {
+		return params.DesiredUnitsResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.DesiredUnitsResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.DesiredUnitsResult{}, result.Error
+	}
+	return result, nil
+}
+
+// ServiceScale returns the current scale of the given service.
+func (c *Client) ServiceScale(service string) (params.ServiceScaleResult, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(service).String()}},
+	}
+	var results params.ServiceScaleResults
+	if err := c.facade.FacadeCall("ServiceScales", args, &results); err != nil {
+		return params.ServiceScaleResult{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.ServiceScaleResult{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.ServiceScaleResult{}, result.Error
+	}
+	return result, nil
+}
+
+// ScaleEvents returns the scale events recorded after the given time,
+// oldest first. If service is not empty, only the events of that
+// service are returned.
+func (c *Client) ScaleEvents(service string, since time.Time) ([]params.ScaleEvent, error) {
+	args := params.ScaleEventsArgs{Since: since}
+	if service != "" {
+		args.ServiceTag = names.NewServiceTag(service).String()
+	}
+	var result params.ScaleEventsResult
+	if err := c.facade.FacadeCall("ScaleEvents", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Events, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package scale_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/scale"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestSetDesiredUnits(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Scale")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetDesiredUnits")
+			c.Check(a, jc.DeepEquals, params.DesiredUnitsArgs{
+				Entries: []params.DesiredUnits{{
+					ServiceTag: "service-wordpress",
+					NumUnits:   3,
+					Reason:     "load is high",
+					Token:      "scaler-42",
+				}},
+			})
+			*response.(*params.DesiredUnitsResults) = params.DesiredUnitsResults{
+				Results: []params.DesiredUnitsResult{{
+					Previous: 1,
+					Added:    []string{"wordpress/1", "wordpress/2"},
+				}},
+			}
+			return nil
+		})
+	client := scale.NewClient(apiCaller)
+	result, err := client.SetDesiredUnits("wordpress", 3, "load is high", "scaler-42")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DesiredUnitsResult{
+		Previous: 1,
+		Added:    []string{"wordpress/1", "wordpress/2"},
+	})
+}
+
+func (s *clientSuite) TestSetDesiredUnitsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			*response.(*params.DesiredUnitsResults) = params.DesiredUnitsResults{
+				Results: []params.DesiredUnitsResult{{
+					Error: &params.Error{Message: "try again", Code: params.CodeTryAgain},
+				}},
+			}
+			return nil
+		})
+	client := scale.NewClient(apiCaller)
+	_, err := client.SetDesiredUnits("wordpress", 3, "load is high", "")
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+}
+
+func (s *clientSuite) TestServiceScale(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Scale")
+			c.Check(request, gc.Equals, "ServiceScales")
+			c.Check(a, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "service-wordpress"}},
+			})
+			*response.(*params.ServiceScaleResults) = params.ServiceScaleResults{
+				Results: []params.ServiceScaleResult{{Units: 2, MinUnits: 1}},
+			}
+			return nil
+		})
+	client := scale.NewClient(apiCaller)
+	result, err := client.ServiceScale("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ServiceScaleResult{Units: 2, MinUnits: 1})
+}
+
+func (s *clientSuite) TestScaleEvents(c *gc.C) {
+	since := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Scale")
+			c.Check(request, gc.Equals, "ScaleEvents")
+			c.Check(a, jc.DeepEquals, params.ScaleEventsArgs{
+				ServiceTag: "service-wordpress",
+				Since:      since,
+			})
+			*response.(*params.ScaleEventsResult) = params.ScaleEventsResult{
+				Events: []params.ScaleEvent{{ServiceTag: "service-wordpress", Desired: 3}},
+			}
+			return nil
+		})
+	client := scale.NewClient(apiCaller)
+	events, err := client.ScaleEvents("wordpress", since)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, jc.DeepEquals, []params.ScaleEvent{{ServiceTag: "service-wordpress", Desired: 3}})
+}
@@ -44,6 +44,56 @@ func (c *Client) SetMetricCredentials(service string, credentials []byte) error
 	return errors.Trace(results.OneError())
 }
 
+// SetAddressPolicy sets the policy used to select the public addresses
+// of the service's units: one of "public", "private" or "space:<name>".
+func (c *Client) SetAddressPolicy(service, policy string) error {
+	args := params.ServiceAddressPolicies{
+		Policies: []params.ServiceAddressPolicy{{
+			ServiceName: service,
+			Policy:      policy,
+		}},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("SetAddressPolicies", args, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(results.OneError())
+}
+
+// ExposeEndpoints exposes the service only on the given endpoints, in
+// addition to any endpoints already exposed.
+func (c *Client) ExposeEndpoints(service string, endpoints map[string]params.ExposedEndpoint) error {
+	args := params.ServicesExposeEndpoints{
+		Services: []params.ServiceExposeEndpoints{{
+			ServiceName: service,
+			Endpoints:   endpoints,
+		}},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("ExposeEndpoints", args, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(results.OneError())
+}
+
+// UnexposeEndpoints stops exposing the given endpoints of the service.
+func (c *Client) UnexposeEndpoints(service string, endpoints ...string) error {
+	args := params.ServicesUnexposeEndpoints{
+		Services: []params.ServiceUnexposeEndpoints{{
+			ServiceName: service,
+			Endpoints:   endpoints,
+		}},
+	}
+	results := new(params.ErrorResults)
+	err := c.facade.FacadeCall("UnexposeEndpoints", args, results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(results.OneError())
+}
+
 // ServiceDeploy obtains the charm, either locally or from
 // the charm store, and deploys it. It allows the specification of
 // requested networks that must be present on the machines where the
@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 )
 
@@ -73,6 +74,81 @@ func (s *serviceSuite) TestSetServiceMetricCredentialsNoMocks(c *gc.C) {
 	c.Assert(service.MetricCredentials(), gc.DeepEquals, []byte("creds"))
 }
 
+func (s *serviceSuite) TestSetAddressPolicy(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "SetAddressPolicies")
+		c.Assert(a, jc.DeepEquals, params.ServiceAddressPolicies{
+			Policies: []params.ServiceAddressPolicy{{
+				ServiceName: "serviceA",
+				Policy:      "space:db",
+			}},
+		})
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.client.SetAddressPolicy("serviceA", "space:db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestSetAddressPolicyNoMocks(c *gc.C) {
+	svc := s.Factory.MakeService(c, nil)
+	err := s.client.SetAddressPolicy(svc.Name(), "private")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.AddressPolicy(), gc.Equals, state.AddressPolicyPrivate)
+
+	err = s.client.SetAddressPolicy(svc.Name(), "nowhere")
+	c.Assert(err, gc.ErrorMatches, `address policy "nowhere" not valid`)
+}
+
+func (s *serviceSuite) TestExposeEndpoints(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "ExposeEndpoints")
+		c.Assert(a, jc.DeepEquals, params.ServicesExposeEndpoints{
+			Services: []params.ServiceExposeEndpoints{{
+				ServiceName: "serviceA",
+				Endpoints: map[string]params.ExposedEndpoint{
+					"website": {Ports: []string{"443/tcp"}},
+				},
+			}},
+		})
+		result := response.(*params.ErrorResults)
+		result.Results = make([]params.ErrorResult, 1)
+		return nil
+	})
+	err := s.client.ExposeEndpoints("serviceA", map[string]params.ExposedEndpoint{
+		"website": {Ports: []string{"443/tcp"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *serviceSuite) TestExposeEndpointsNoMocks(c *gc.C) {
+	svc := s.Factory.MakeService(c, nil)
+	err := s.client.ExposeEndpoints(svc.Name(), map[string]params.ExposedEndpoint{
+		"server": {CIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server": {CIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	err = s.client.UnexposeEndpoints(svc.Name(), "server")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.IsExposed(), jc.IsFalse)
+}
+
 func (s *serviceSuite) TestSetServiceDeploy(c *gc.C) {
 	var called bool
 	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
@@ -17,15 +17,18 @@ import (
 	"github.com/juju/juju/api/diskmanager"
 	"github.com/juju/juju/api/environment"
 	"github.com/juju/juju/api/firewaller"
+	"github.com/juju/juju/api/hostkeyreporter"
 	"github.com/juju/juju/api/keyupdater"
 	apilogger "github.com/juju/juju/api/logger"
 	"github.com/juju/juju/api/machiner"
 	"github.com/juju/juju/api/networker"
 	"github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/api/provisioningerrors"
 	"github.com/juju/juju/api/reboot"
 	"github.com/juju/juju/api/rsyslog"
 	"github.com/juju/juju/api/storageprovisioner"
 	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/api/unitutilization"
 	"github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
@@ -51,9 +54,12 @@ func (st *State) Login(tag, password, nonce string) error {
 func (st *State) loginV2(tag, password, nonce string) error {
 	var result params.LoginResultV1
 	request := &params.LoginRequest{
-		AuthTag:     tag,
-		Credentials: password,
-		Nonce:       nonce,
+		AuthTag:        tag,
+		Credentials:    password,
+		Nonce:          nonce,
+		SessionToken:   st.sessionToken,
+		RequestSession: st.requestSession,
+		TOTPCode:       st.totpCode,
 	}
 	err := st.APICall("Admin", 2, "", "Login", request, &result)
 	if err != nil {
@@ -82,6 +88,9 @@ func (st *State) loginV2(tag, password, nonce string) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if result.UserInfo != nil && result.UserInfo.Session != nil {
+		st.session = result.UserInfo.Session
+	}
 	return nil
 }
 
@@ -321,6 +330,21 @@ func (st *State) Logger() *apilogger.State {
 	return apilogger.NewState(st)
 }
 
+// HostKeyReporter returns access to the HostKeyReporter API.
+func (st *State) HostKeyReporter() *hostkeyreporter.Facade {
+	return hostkeyreporter.NewFacade(st)
+}
+
+// ProvisioningErrors returns access to the ProvisioningErrors API.
+func (st *State) ProvisioningErrors() *provisioningerrors.Facade {
+	return provisioningerrors.NewFacade(st)
+}
+
+// UnitUtilization returns access to the UnitUtilization API.
+func (st *State) UnitUtilization() *unitutilization.Facade {
+	return unitutilization.NewFacade(st)
+}
+
 // KeyUpdater returns access to the KeyUpdater API
 func (st *State) KeyUpdater() *keyupdater.State {
 	return keyupdater.NewState(st)
@@ -5,15 +5,18 @@ package api_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/utils/totp"
 )
 
 func TestAll(t *stdtesting.T) {
@@ -127,6 +130,93 @@ func (s *stateSuite) TestBestFacadeVersion(c *gc.C) {
 	c.Check(s.APIState.BestFacadeVersion("Client"), gc.Equals, 0)
 }
 
+func (s *stateSuite) TestLoginWithSession(c *gc.C) {
+	info := s.APIInfo(c)
+	st, err := api.Open(info, api.DialOpts{RequestSession: true})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	session, ok := st.Session()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(session.Token, gc.Not(gc.Equals), "")
+
+	// The session token can be used in place of the password.
+	info.Password = ""
+	info.SessionToken = session.Token
+	st2, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st2.Close()
+	session2, ok := st2.Session()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(session2, jc.DeepEquals, session)
+
+	info.SessionToken = "bad-token"
+	_, err = api.Open(info, api.DialOpts{})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *stateSuite) TestLoginRefreshesSessionNearExpiry(c *gc.C) {
+	s.PatchValue(api.SessionRefreshMargin, 2*time.Hour)
+	session, err := s.State.AddUserSession(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	info.Password = ""
+	info.SessionToken = session.Token
+	var refreshed []params.SessionInfo
+	st, err := api.Open(info, api.DialOpts{
+		SessionRefreshed: func(session params.SessionInfo) {
+			refreshed = append(refreshed, session)
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	c.Assert(refreshed, gc.HasLen, 1)
+	c.Assert(refreshed[0].Token, gc.Not(gc.Equals), session.Token)
+	current, ok := st.Session()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(current, jc.DeepEquals, refreshed[0])
+}
+
+func (s *stateSuite) TestLoginWithSecondFactor(c *gc.C) {
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetTOTPSecret(secret)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	_, err = api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.Satisfies, params.IsCodeSecondFactorRequired)
+
+	info.TOTPCode, err = totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	st, err := api.Open(info, api.DialOpts{RequestSession: true})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// Logins with the session started by the login need a second
+	// factor too, and the code already used is not accepted again.
+	session, ok := st.Session()
+	c.Assert(ok, jc.IsTrue)
+	info.Password = ""
+	usedCode := info.TOTPCode
+	info.TOTPCode = ""
+	info.SessionToken = session.Token
+	_, err = api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.Satisfies, params.IsCodeSecondFactorRequired)
+
+	info.TOTPCode = usedCode
+	_, err = api.Open(info, api.DialOpts{})
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	info.TOTPCode, err = totp.Code(secret, time.Now().Add(totp.Period))
+	c.Assert(err, jc.ErrorIsNil)
+	st2, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	st2.Close()
+}
+
 func (s *stateSuite) TestAPIHostPortsMovesConnectedValueFirst(c *gc.C) {
 	hostportslist := s.APIState.APIHostPorts()
 	c.Check(hostportslist, gc.HasLen, 1)
@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+var logger = loggo.GetLogger("juju.api.systemmanager")
+
+// Client provides methods that the Juju client command uses to interact
+// with the system as a whole.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "SystemManager")
+	logger.Tracef("%#v", frontend)
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AllEnvironments returns all environments in the system.
+func (c *Client) AllEnvironments() ([]params.Environment, error) {
+	var result params.EnvironmentList
+	if err := c.facade.FacadeCall("AllEnvironments", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
+
+// HostedEnvironments returns summaries of the environments hosted by
+// the system, other than the state server environment.
+func (c *Client) HostedEnvironments() ([]params.HostedEnvironment, error) {
+	var result params.HostedEnvironmentList
+	if err := c.facade.FacadeCall("HostedEnvironments", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
+
+// EnvironmentSummaries returns the number of machines, services and
+// units in every environment in the system.
+func (c *Client) EnvironmentSummaries() ([]params.EnvironmentSummary, error) {
+	var result params.EnvironmentSummaryList
+	if err := c.facade.FacadeCall("EnvironmentSummaries", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Environments, nil
+}
+
+// WatchAllEnvs returns an AllEnvWatcher, from which you can request
+// the Next collection of Deltas for all environments in the system.
+func (c *Client) WatchAllEnvs() (*api.AllEnvWatcher, error) {
+	var info params.AllWatcherId
+	if err := c.facade.FacadeCall("WatchAllEnvs", nil, &info); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return api.NewAllEnvWatcher(c.facade.RawAPICaller(), &info.AllWatcherId), nil
+}
+
+// RotateServerCert requests that the state servers generate and start
+// serving new server certificates, signed by the existing CA.
+func (c *Client) RotateServerCert() error {
+	return c.facade.FacadeCall("RotateServerCert", nil, nil)
+}
+
+// PublicAPIAddresses returns the host:port addresses that agents and
+// clients are told to use in place of the addresses of the API
+// servers, if any have been set.
+func (c *Client) PublicAPIAddresses() ([]string, error) {
+	var result params.PublicAPIAddresses
+	if err := c.facade.FacadeCall("PublicAPIAddresses", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Addresses, nil
+}
+
+// SetPublicAPIAddresses sets the host:port addresses that agents and
+// clients are told to use in place of the addresses of the API
+// servers. Setting no addresses restores the servers' own addresses.
+func (c *Client) SetPublicAPIAddresses(addresses ...string) error {
+	args := params.PublicAPIAddresses{Addresses: addresses}
+	return c.facade.FacadeCall("SetPublicAPIAddresses", args, nil)
+}
+
+// ExportEnvironmentUsers returns the users with access to the
+// environment with the given tag.
+func (c *Client) ExportEnvironmentUsers(env names.EnvironTag) (params.MigratedUsers, error) {
+	var result params.MigratedUsers
+	args := params.Entity{Tag: env.String()}
+	if err := c.facade.FacadeCall("ExportEnvironmentUsers", args, &result); err != nil {
+		return params.MigratedUsers{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ImportEnvironmentUsers gives the users exported from another system
+// access to the environment with the given tag. The users are imported
+// under the names given in userMap, or under their own names.
+func (c *Client) ImportEnvironmentUsers(env names.EnvironTag, users []params.MigratedUser, userMap map[string]string) ([]params.UserImportResult, error) {
+	var result params.UserImportResults
+	args := params.ImportEnvironmentUsers{
+		EnvironTag: env.String(),
+		Users:      users,
+		UserMap:    userMap,
+	}
+	if err := c.facade.FacadeCall("ImportEnvironmentUsers", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Results, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/systemmanager"
+	"github.com/juju/juju/juju"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/testing/factory"
+)
+
+type systemManagerSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&systemManagerSuite{})
+
+func (s *systemManagerSuite) OpenAPI(c *gc.C) *systemmanager.Client {
+	conn, err := juju.NewAPIState(s.AdminUserTag(c), s.Environ, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { conn.Close() })
+	return systemmanager.NewClient(conn)
+}
+
+func (s *systemManagerSuite) TestAllEnvironments(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "hosted"})
+	defer st.Close()
+
+	sysManager := s.OpenAPI(c)
+	envs, err := sysManager.AllEnvironments()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	c.Assert(names, jc.SameContents, []string{"dummyenv", "hosted"})
+}
+
+func (s *systemManagerSuite) TestWatchAllEnvs(c *gc.C) {
+	m := s.Factory.MakeMachine(c, nil)
+
+	sysManager := s.OpenAPI(c)
+	w, err := sysManager.WatchAllEnvs()
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		err := w.Stop()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	deltas, err := w.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	var found bool
+	for _, delta := range deltas {
+		c.Check(delta.EnvUUID, gc.Equals, s.State.EnvironUUID())
+		if info, ok := delta.Delta.Entity.(*multiwatcher.MachineInfo); ok {
+			c.Check(info.Id, gc.Equals, m.Id())
+			found = true
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (s *systemManagerSuite) TestRotateServerCert(c *gc.C) {
+	sysManager := s.OpenAPI(c)
+	err := sysManager.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+
+	serial, err := s.State.ServerCertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 1)
+}
+
+func (s *systemManagerSuite) TestPublicAPIAddresses(c *gc.C) {
+	sysManager := s.OpenAPI(c)
+	err := sysManager.SetPublicAPIAddresses("api.example.com:443")
+	c.Assert(err, jc.ErrorIsNil)
+
+	addresses, err := sysManager.PublicAPIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, jc.DeepEquals, []string{"api.example.com:443"})
+	hostPorts, err := s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostPorts, jc.DeepEquals, [][]network.HostPort{
+		network.NewHostPorts(443, "api.example.com"),
+	})
+
+	err = sysManager.SetPublicAPIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	addresses, err = sysManager.PublicAPIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addresses, gc.HasLen, 0)
+}
+
+func (s *systemManagerSuite) TestExportImportEnvironmentUsers(c *gc.C) {
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	source := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "source", Owner: bob.UserTag()})
+	defer source.Close()
+	target := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "target"})
+	defer target.Close()
+
+	sysManager := s.OpenAPI(c)
+	exported, err := sysManager.ExportEnvironmentUsers(names.NewEnvironTag(source.EnvironUUID()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exported.Users, gc.HasLen, 1)
+	c.Assert(exported.Users[0].UserName, gc.Equals, "bob@local")
+
+	results, err := sysManager.ImportEnvironmentUsers(names.NewEnvironTag(target.EnvironUUID()), exported.Users, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Conflict, gc.Equals, "")
+	_, err = target.EnvironmentUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
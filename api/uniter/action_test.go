@@ -4,6 +4,7 @@
 package uniter_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -122,3 +123,29 @@ func (s *actionSuite) TestActionFail(c *gc.C) {
 	c.Assert(res, gc.DeepEquals, map[string]interface{}{})
 	c.Assert(completed[0].Name(), gc.Equals, "fakeaction")
 }
+
+func (s *actionSuite) TestAppendActionOutput(c *gc.C) {
+	action, err := s.uniterSuite.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.uniter.AppendActionOutput(action.ActionTag(), "stdout", "too soon\n")
+	c.Assert(err, gc.ErrorMatches, ".*action is not running")
+
+	err = s.uniter.ActionBegin(action.ActionTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.uniter.AppendActionOutput(action.ActionTag(), "stdout", "working\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output, err := action.Output(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 1)
+	c.Assert(output[0].Stream, gc.Equals, state.ActionOutputStdout)
+	c.Assert(output[0].Data, gc.Equals, "working\n")
+}
+
+func (s *actionSuite) TestAppendActionOutputOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+	action, err := s.uniterSuite.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.uniter.AppendActionOutput(action.ActionTag(), "stdout", "working\n")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
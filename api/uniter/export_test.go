@@ -12,6 +12,7 @@ var (
 	NewSettings = newSettings
 	NewStateV0  = newStateV0
 	NewStateV1  = newStateV1
+	NewStateV2  = newStateV2
 )
 
 // PatchResponses changes the internal FacadeCaller to one that lets you return
@@ -162,6 +162,61 @@ func (ru *RelationUnit) ReadSettings(uname string) (params.Settings, error) {
 	return result.Settings, nil
 }
 
+// ReadServiceSettings returns the settings that the named service
+// shares with the other services in the relation, as distinct from
+// the settings of its units.
+func (ru *RelationUnit) ReadServiceSettings(serviceName string) (params.Settings, error) {
+	if ru.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("ReadServiceRelationSettings")
+	}
+	if !names.IsValidService(serviceName) {
+		return nil, errors.Errorf("%q is not a valid service", serviceName)
+	}
+	var results params.SettingsResults
+	args := params.RelationUnitServices{
+		RelationUnitServices: []params.RelationUnitService{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+			Service:  names.NewServiceTag(serviceName).String(),
+		}},
+	}
+	err := ru.st.facade.FacadeCall("ReadServiceRelationSettings", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Settings, nil
+}
+
+// UpdateServiceSettings applies the given changes to the settings that
+// the unit's service shares with the other services in the relation.
+// Keys with empty values are deleted. Only the leader of the service
+// may change the settings.
+func (ru *RelationUnit) UpdateServiceSettings(settings params.Settings) error {
+	if ru.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("UpdateServiceRelationSettings")
+	}
+	var result params.ErrorResults
+	args := params.RelationUnitsSettings{
+		RelationUnits: []params.RelationUnitSettings{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+			Settings: settings,
+		}},
+	}
+	err := ru.st.facade.FacadeCall("UpdateServiceRelationSettings", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 // Watch returns a watcher that notifies of changes to counterpart
 // units in the relation.
 func (ru *RelationUnit) Watch() (watcher.RelationUnitsWatcher, error) {
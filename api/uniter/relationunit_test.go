@@ -4,6 +4,7 @@
 package uniter_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -207,6 +208,34 @@ func (s *relationUnitSuite) TestSettings(c *gc.C) {
 	})
 }
 
+func (s *relationUnitSuite) TestReadServiceSettings(c *gc.C) {
+	err := s.stateRelation.UpdateServiceSettings("mysql", map[string]interface{}{
+		"database": "wordpress",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, apiRelUnit := s.getRelationUnits(c)
+	gotSettings, err := apiRelUnit.ReadServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotSettings, gc.DeepEquals, params.Settings{"database": "wordpress"})
+	gotSettings, err = apiRelUnit.ReadServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotSettings, gc.HasLen, 0)
+
+	_, err = apiRelUnit.ReadServiceSettings("mysql/0")
+	c.Assert(err, gc.ErrorMatches, `"mysql/0" is not a valid service`)
+}
+
+func (s *relationUnitSuite) TestServiceSettingsOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, apiRelUnit := s.getRelationUnits(c)
+	_, err := apiRelUnit.ReadServiceSettings("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = apiRelUnit.UpdateServiceSettings(params.Settings{"database": "wordpress"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
 func (s *relationUnitSuite) TestReadSettings(c *gc.C) {
 	// First try to read the settings which are not set.
 	myRelUnit, err := s.stateRelation.Unit(s.mysqlUnit)
@@ -734,3 +734,180 @@ func (u *Unit) WatchMeterStatus() (watcher.NotifyWatcher, error) {
 func (u *Unit) WatchStorage() (watcher.StringsWatcher, error) {
 	return u.st.WatchUnitStorageAttachments(u.tag)
 }
+
+// WatchUpgradeSeriesNotifications returns a watcher for observing
+// changes to the series upgrade of the unit's machine.
+func (u *Unit) WatchUpgradeSeriesNotifications() (watcher.NotifyWatcher, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("WatchUpgradeSeriesNotifications")
+	}
+	var results params.NotifyWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchUpgradeSeriesNotifications", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := watcher.NewNotifyWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
+// UpgradeSeriesStatus returns the unit's progress in the series upgrade
+// of its machine, which is empty if the unit is not taking part in one.
+func (u *Unit) UpgradeSeriesStatus() (params.UpgradeSeriesStatus, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return "", errors.NotImplementedf("UpgradeSeriesStatus")
+	}
+	var results params.UpgradeSeriesStatusResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UpgradeSeriesUnitStatus", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Status, nil
+}
+
+// SetUpgradeSeriesStatus records that the unit has finished its part of
+// the current phase of its machine's series upgrade.
+func (u *Unit) SetUpgradeSeriesStatus(status params.UpgradeSeriesStatus) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetUpgradeSeriesStatus")
+	}
+	var result params.ErrorResults
+	args := params.UpgradeSeriesStatusParams{
+		Params: []params.UpgradeSeriesStatusParam{
+			{Entity: params.Entity{Tag: u.tag.String()}, Status: status},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetUpgradeSeriesUnitStatus", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// NetworkInfo returns the addresses the unit should use for each of the
+// given endpoint bindings, keyed on binding name.
+func (u *Unit) NetworkInfo(bindings []string) (map[string]params.NetworkInfoResult, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotImplementedf("NetworkInfo")
+	}
+	var results params.NetworkInfoResults
+	args := params.NetworkInfoParams{
+		Unit:     u.tag.String(),
+		Bindings: bindings,
+	}
+	err := u.st.facade.FacadeCall("NetworkInfo", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// GoalState returns the units and relations the unit's service is
+// expected to have, whether or not they are up yet.
+func (u *Unit) GoalState() (params.GoalState, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return params.GoalState{}, errors.NotImplementedf("GoalState")
+	}
+	var results params.GoalStateResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("GoalStates", args, &results)
+	if err != nil {
+		return params.GoalState{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return params.GoalState{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return params.GoalState{}, result.Error
+	}
+	return *result.Result, nil
+}
+
+// UniterState returns the operation state last recorded in the
+// controller by the unit's uniter, which is empty if none has been.
+func (u *Unit) UniterState() (string, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return "", errors.NotImplementedf("UniterState")
+	}
+	var results params.StringResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UniterStates", args, &results)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
+}
+
+// SetUniterState records the operation state of the unit's uniter in
+// the controller.
+func (u *Unit) SetUniterState(state string) error {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("SetUniterState")
+	}
+	var result params.ErrorResults
+	args := params.SetUniterStateParams{
+		Params: []params.SetUniterStateParam{
+			{Entity: params.Entity{Tag: u.tag.String()}, State: state},
+		},
+	}
+	err := u.st.facade.FacadeCall("SetUniterStates", args, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return result.OneError()
+}
+
+// IsPaused returns whether the unit has been paused, in which case no
+// periodic hooks are run and no relation changes are handled for it.
+func (u *Unit) IsPaused() (bool, error) {
+	if u.st.facade.BestAPIVersion() < 3 {
+		return false, errors.NotImplementedf("IsPaused")
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("UnitPaused", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
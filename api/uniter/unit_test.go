@@ -909,7 +909,7 @@ func (s *unitMetricBatchesSuite) TestSendMetricBatchNotImplemented(c *gc.C) {
 		case "AddMetricBatches":
 			result := response.(*params.ErrorResults)
 			result.Results = make([]params.ErrorResult, 1)
-			return &params.Error{"not implemented", params.CodeNotImplemented}
+			return &params.Error{Message: "not implemented", Code: params.CodeNotImplemented}
 		case "AddMetrics":
 			called = true
 			result := response.(*params.ErrorResults)
@@ -963,3 +963,153 @@ func (s *unitMetricBatchesSuite) TestSendMetricBatch(c *gc.C) {
 	c.Assert(batches[0].Metrics()[0].Key, gc.Equals, "pings")
 	c.Assert(batches[0].Metrics()[0].Value, gc.Equals, "5")
 }
+
+func (s *unitSuite) TestUpgradeSeriesStatus(c *gc.C) {
+	status, err := s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesStatus(""))
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, params.UpgradeSeriesPrepareStarted)
+
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	machineStatus, err := s.wordpressMachine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineStatus, gc.Equals, state.UpgradeSeriesPrepareCompleted)
+
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesCompleted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit "wordpress/0": unexpected status "completed" while series upgrade is prepare-completed`)
+}
+
+func (s *unitSuite) TestUpgradeSeriesStatusOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.UpgradeSeriesStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	_, err = s.apiUnit.WatchUpgradeSeriesNotifications()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w, err := s.apiUnit.WatchUpgradeSeriesNotifications()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+
+	err = s.wordpressMachine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.apiUnit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *unitSuite) TestNetworkInfo(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24", SpaceName: "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressMachine.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.10", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressService.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.apiUnit.NetworkInfo([]string{"db", "url"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, map[string]params.NetworkInfoResult{
+		"db": {
+			Space:            "internal",
+			IngressAddresses: []string{"10.0.0.10"},
+			EgressSubnets:    []string{"10.0.0.0/24"},
+		},
+		"url": {
+			IngressAddresses: []string{"10.0.0.10"},
+			EgressSubnets:    []string{"10.0.0.10/32"},
+		},
+	})
+}
+
+func (s *unitSuite) TestNetworkInfoOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.NetworkInfo([]string{"db"})
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestGoalState(c *gc.C) {
+	_, err := s.wordpressService.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	goalState, err := s.apiUnit.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState.Units, gc.HasLen, 2)
+	for _, name := range []string{"wordpress/0", "wordpress/1"} {
+		status, ok := goalState.Units[name]
+		c.Assert(ok, jc.IsTrue, gc.Commentf("%s", name))
+		c.Assert(status.Status, gc.Equals, "unknown")
+	}
+	c.Assert(goalState.Relations, gc.HasLen, 0)
+}
+
+func (s *unitSuite) TestGoalStateOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.GoalState()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestUniterState(c *gc.C) {
+	data, err := s.apiUnit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "")
+
+	err = s.apiUnit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = s.apiUnit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "kind: install\nstep: pending\n")
+	data, err = s.wordpressUnit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "kind: install\nstep: pending\n")
+}
+
+func (s *unitSuite) TestUniterStateOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.UniterState()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+	err = s.apiUnit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
+
+func (s *unitSuite) TestIsPaused(c *gc.C) {
+	paused, err := s.apiUnit.IsPaused()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paused, jc.IsFalse)
+
+	err = s.wordpressUnit.SetPaused(true)
+	c.Assert(err, jc.ErrorIsNil)
+	paused, err = s.apiUnit.IsPaused()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paused, jc.IsTrue)
+}
+
+func (s *unitSuite) TestIsPausedOldServer(c *gc.C) {
+	s.patchNewState(c, uniter.NewStateV2)
+
+	_, err := s.apiUnit.IsPaused()
+	c.Assert(err, jc.Satisfies, errors.IsNotImplemented)
+}
@@ -80,9 +80,12 @@ var newStateV1 = newStateForVersionFn(1)
 // newStateV2 creates a new client-side Uniter facade, version 2.
 var newStateV2 = newStateForVersionFn(2)
 
+// newStateV3 creates a new client-side Uniter facade, version 3.
+var newStateV3 = newStateForVersionFn(3)
+
 // NewState creates a new client-side Uniter facade.
 // Defined like this to allow patching during tests.
-var NewState = newStateV2
+var NewState = newStateV3
 
 // BestAPIVersion returns the API version that we were able to
 // determine is supported by both the client and the API Server.
@@ -279,6 +282,34 @@ func (st *State) ActionFinish(tag names.ActionTag, status string, results map[st
 	return nil
 }
 
+// AppendActionOutput records output written by a running action to
+// the named stream, so it can be watched before the action completes.
+func (st *State) AppendActionOutput(tag names.ActionTag, stream, data string) error {
+	if st.BestAPIVersion() < 3 {
+		return errors.NotImplementedf("AppendActionOutput() (need V3+)")
+	}
+	var outcome params.ErrorResults
+	args := params.ActionOutputChunks{
+		Chunks: []params.ActionOutputChunk{{
+			ActionTag: tag.String(),
+			Stream:    stream,
+			Data:      data,
+		}},
+	}
+	err := st.facade.FacadeCall("AppendActionOutput", args, &outcome)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(outcome.Results) != 1 {
+		return fmt.Errorf("expected 1 result, got %d", len(outcome.Results))
+	}
+	result := outcome.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
 // RelationById returns the existing relation with the given id.
 func (st *State) RelationById(id int) (*Relation, error) {
 	var results params.RelationResults
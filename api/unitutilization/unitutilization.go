@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitutilization provides the client side of the
+// UnitUtilization facade, used by machine agents to report the
+// resources used by the units on their machines.
+package unitutilization
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Facade provides access to the UnitUtilization API facade.
+type Facade struct {
+	caller base.FacadeCaller
+}
+
+// NewFacade creates a new client-side UnitUtilization facade.
+func NewFacade(caller base.APICaller) *Facade {
+	return &Facade{
+		caller: base.NewFacadeCaller(caller, "UnitUtilization"),
+	}
+}
+
+// SetUnitUtilization reports the given samples of the resources used
+// by units to the API server.
+func (f *Facade) SetUnitUtilization(samples []params.UnitUtilizationSample) error {
+	args := params.UnitUtilizationSamples{Samples: samples}
+	var result params.ErrorResults
+	err := f.caller.FacadeCall("SetUnitUtilization", args, &result)
+	if err != nil {
+		return err
+	}
+	return result.Combine()
+}
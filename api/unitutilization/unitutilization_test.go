@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitutilization_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/unitutilization"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+var testSamples = []params.UnitUtilizationSample{{
+	UnitTag:     "unit-wordpress-0",
+	Time:        time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC),
+	CPUPercent:  12.5,
+	MemoryBytes: 1024,
+	Processes:   3,
+}}
+
+func (s *facadeSuite) TestSetUnitUtilization(c *gc.C) {
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "UnitUtilization")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetUnitUtilization")
+			c.Check(a, jc.DeepEquals, params.UnitUtilizationSamples{
+				Samples: testSamples,
+			})
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		})
+	facade := unitutilization.NewFacade(apiCaller)
+	err := facade.SetUnitUtilization(testSamples)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *facadeSuite) TestCallError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("blam")
+		})
+	facade := unitutilization.NewFacade(apiCaller)
+	err := facade.SetUnitUtilization(testSamples)
+	c.Assert(err, gc.ErrorMatches, "blam")
+}
+
+func (s *facadeSuite) TestSetUnitUtilizationError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(_ string, _ int, _, _ string, _, response interface{}) error {
+			*response.(*params.ErrorResults) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		})
+	facade := unitutilization.NewFacade(apiCaller)
+	err := facade.SetUnitUtilization(testSamples)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,37 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradechecks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/version"
+)
+
+// Client provides access to the UpgradeChecks facade, used to check
+// that an environment is fit to be upgraded.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new UpgradeChecks client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "UpgradeChecks")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// RunUpgradeChecks makes the pre-upgrade checks for an upgrade of the
+// environment's agents to the given version, and returns the outcome
+// of each.
+func (c *Client) RunUpgradeChecks(vers version.Number) ([]params.UpgradeCheckResult, error) {
+	args := params.RunUpgradeChecks{Version: vers}
+	var results params.UpgradeCheckResults
+	if err := c.facade.FacadeCall("Run", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
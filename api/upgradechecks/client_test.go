@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradechecks_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/upgradechecks"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestRunUpgradeChecks(c *gc.C) {
+	checks := []params.UpgradeCheckResult{{
+		Name:        "disk-space",
+		Description: "state server has enough free disk space",
+	}, {
+		Name:        "mongo-version",
+		Description: "mongo server version is supported",
+		Error:       &params.Error{Message: "mongo server version 2.2.4 is too old"},
+	}}
+	called := false
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			_ int,
+			id, request string,
+			a, response interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "UpgradeChecks")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Run")
+			c.Check(a, jc.DeepEquals, params.RunUpgradeChecks{
+				Version: version.MustParse("1.26.0"),
+			})
+			*response.(*params.UpgradeCheckResults) = params.UpgradeCheckResults{
+				Results: checks,
+			}
+			return nil
+		})
+	client := upgradechecks.NewClient(apiCaller)
+	results, err := client.RunUpgradeChecks(version.MustParse("1.26.0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(results, jc.DeepEquals, checks)
+}
+
+func (s *clientSuite) TestRunUpgradeChecksError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			_ int,
+			id, request string,
+			a, response interface{},
+		) error {
+			return errors.New("boom")
+		})
+	client := upgradechecks.NewClient(apiCaller)
+	_, err := client.RunUpgradeChecks(version.MustParse("1.26.0"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -166,3 +166,39 @@ func (c *Client) SetPassword(username, password string) error {
 	}
 	return results.OneError()
 }
+
+// RefreshSession replaces the token of one of the logged in user's
+// login sessions with a new one that expires later. The old token can
+// no longer be used.
+func (c *Client) RefreshSession(token string) (params.SessionInfo, error) {
+	var result params.SessionInfo
+	args := params.SessionToken{Token: token}
+	if err := c.facade.FacadeCall("RefreshSession", args, &result); err != nil {
+		return params.SessionInfo{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// Logout ends one of the logged in user's login sessions, so that its
+// token can no longer be used to log in.
+func (c *Client) Logout(token string) error {
+	args := params.SessionToken{Token: token}
+	return c.facade.FacadeCall("Logout", args, nil)
+}
+
+// EnableTOTP enrols the logged in user for two-factor authentication
+// with the given time-based one-time password secret. The code must be
+// the current one for the secret. A user who is already enrolled must
+// give the current code for their existing secret as currentCode;
+// otherwise an error satisfying params.IsCodeSecondFactorRequired is
+// returned.
+func (c *Client) EnableTOTP(secret, code, currentCode string) error {
+	args := params.EnableTOTP{Secret: secret, Code: code, CurrentCode: currentCode}
+	return c.facade.FacadeCall("EnableTOTP", args, nil)
+}
+
+// DisableTOTP removes the two-factor authentication enrollment of the
+// specified user.
+func (c *Client) DisableTOTP(username string) error {
+	return c.userCall(username, "DisableTOTP")
+}
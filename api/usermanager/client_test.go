@@ -4,6 +4,8 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -12,6 +14,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/utils/totp"
 )
 
 type usermanagerSuite struct {
@@ -177,3 +180,45 @@ func (s *usermanagerSuite) TestSetUserPasswordBadName(c *gc.C) {
 	err := s.usermanager.SetPassword("not@home", "new-password")
 	c.Assert(err, gc.ErrorMatches, `"not@home" is not a valid username`)
 }
+
+func (s *usermanagerSuite) TestRefreshSessionAndLogout(c *gc.C) {
+	session, err := s.State.AddUserSession(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.usermanager.RefreshSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Token, gc.Not(gc.Equals), session.Token)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.usermanager.Logout(info.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(info.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *usermanagerSuite) TestEnableAndDisableTOTP(c *gc.C) {
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(secret, code, "")
+	c.Assert(err, jc.ErrorIsNil)
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, secret)
+
+	// Replacing the secret needs a code for the current one.
+	other, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	code, err = totp.Code(other, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(other, code, "")
+	c.Assert(err, jc.Satisfies, params.IsCodeSecondFactorRequired)
+
+	err = s.usermanager.DisableTOTP(user.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, "")
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the Webhooks facade, used to manage the
+// URLs to which notifications of environment events are posted.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Webhooks client.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Webhooks")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddWebhook records that notifications of the given events are to be
+// posted to the given URL, signed with the given secret if it is not
+// empty, and returns the id of the new webhook. No events means all
+// events.
+func (c *Client) AddWebhook(url, secret string, events []string) (string, error) {
+	args := params.AddWebhookArgs{
+		URL:    url,
+		Secret: secret,
+		Events: events,
+	}
+	var result params.AddWebhookResult
+	if err := c.facade.FacadeCall("AddWebhook", args, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Id, nil
+}
+
+// Webhooks returns the webhooks of the environment.
+func (c *Client) Webhooks() ([]params.Webhook, error) {
+	var result params.WebhooksResult
+	if err := c.facade.FacadeCall("Webhooks", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Webhooks, nil
+}
+
+// RemoveWebhook removes the webhook with the given id.
+func (c *Client) RemoveWebhook(id string) error {
+	args := params.RemoveWebhookArgs{Id: id}
+	var result params.ErrorResult
+	if err := c.facade.FacadeCall("RemoveWebhook", args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
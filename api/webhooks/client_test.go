@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestAddWebhook(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddWebhook")
+			c.Check(a, jc.DeepEquals, params.AddWebhookArgs{
+				URL:    "https://example.com/hook",
+				Secret: "s3cret",
+				Events: []string{"hook-failed"},
+			})
+			*response.(*params.AddWebhookResult) = params.AddWebhookResult{Id: "3"}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	id, err := client.AddWebhook("https://example.com/hook", "s3cret", []string{"hook-failed"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "3")
+}
+
+func (s *clientSuite) TestAddWebhookError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			*response.(*params.AddWebhookResult) = params.AddWebhookResult{
+				Error: &params.Error{Message: "boom"},
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	_, err := client.AddWebhook("https://example.com/hook", "", nil)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestWebhooks(c *gc.C) {
+	expect := []params.Webhook{{
+		Id:     "0",
+		URL:    "https://example.com/hook",
+		Signed: true,
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "Webhooks")
+			c.Check(a, gc.IsNil)
+			*response.(*params.WebhooksResult) = params.WebhooksResult{Webhooks: expect}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	result, err := client.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expect)
+}
+
+func (s *clientSuite) TestRemoveWebhook(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, response interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "RemoveWebhook")
+			c.Check(a, jc.DeepEquals, params.RemoveWebhookArgs{Id: "0"})
+			*response.(*params.ErrorResult) = params.ErrorResult{
+				Error: &params.Error{Message: `webhook "0" not found`, Code: params.CodeNotFound},
+			}
+			return nil
+		})
+	client := webhooks.NewClient(apiCaller)
+	err := client.RemoveWebhook("0")
+	c.Assert(err, gc.ErrorMatches, `webhook "0" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
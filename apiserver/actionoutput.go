@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"golang.org/x/net/websocket"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// actionOutputPollInterval is how often the output recorded for an
+// action is checked for new chunks.
+var actionOutputPollInterval = time.Second
+
+// actionOutputHandler takes requests to watch the output of an action
+// as it runs.
+type actionOutputHandler struct {
+	httpHandler
+}
+
+// ServeHTTP will serve up connections as a websocket.
+// Args for the HTTP request are as follows:
+//   action -> string - the tag of the action whose output is watched
+//
+// The first line sent on the socket is a JSON-encoded error result;
+// if the error is nil, each chunk of output recorded for the action is
+// then sent as a line holding a JSON-encoded params.ActionOutputMessage.
+// The socket is closed once the action has finished and all its output
+// has been sent.
+func (h *actionOutputHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server := websocket.Server{
+		Handler: func(socket *websocket.Conn) {
+			defer socket.Close()
+			// Validate before authenticate because the authentication is
+			// dependent on the state connection that is determined during the
+			// validation.
+			stateWrapper, err := h.validateEnvironUUID(req)
+			if err != nil {
+				h.sendError(socket, err)
+				return
+			}
+			defer stateWrapper.cleanup()
+			if err := stateWrapper.authenticateUser(req); err != nil {
+				h.sendError(socket, fmt.Errorf("auth failed: %v", err))
+				return
+			}
+			tag, err := names.ParseActionTag(req.URL.Query().Get("action"))
+			if err != nil {
+				h.sendError(socket, err)
+				return
+			}
+			action, err := stateWrapper.state.ActionByTag(tag)
+			if err != nil {
+				h.sendError(socket, err)
+				return
+			}
+
+			// If we get to here, no more errors to report, so we report a nil
+			// error.  This way the first line of the socket is always a json
+			// formatted simple error.
+			if err := h.sendError(socket, nil); err != nil {
+				logger.Errorf("failed to send nil error at start of connection")
+				return
+			}
+			if err := sendActionOutput(stateWrapper.state, action, socket); err != nil {
+				logger.Debugf("action output stream closed: %v", err)
+			}
+		}}
+	server.ServeHTTP(w, req)
+}
+
+// sendActionOutput writes each chunk of the action's output to w as it
+// is recorded, returning once the action has finished.
+func sendActionOutput(st *state.State, action *state.Action, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	last := -1
+	for {
+		// The status is read before the output, so that no output
+		// recorded before the action finished can be missed.
+		current, err := st.Action(action.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		finished := actionFinished(current.Status())
+		outputs, err := action.Output(last)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, output := range outputs {
+			err := encoder.Encode(params.ActionOutputMessage{
+				Seq:       output.Seq,
+				Stream:    output.Stream,
+				Data:      output.Data,
+				Timestamp: output.Timestamp,
+			})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			last = output.Seq
+		}
+		if finished {
+			return nil
+		}
+		time.Sleep(actionOutputPollInterval)
+	}
+}
+
+func actionFinished(status state.ActionStatus) bool {
+	switch status {
+	case state.ActionCompleted, state.ActionFailed, state.ActionCancelled:
+		return true
+	}
+	return false
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *actionOutputHandler) sendError(w io.Writer, err error) error {
+	response := &params.ErrorResult{}
+	if err != nil {
+		response.Error = &params.Error{Message: err.Error()}
+	}
+	message, err := json.Marshal(response)
+	if err != nil {
+		// If we are having trouble marshalling the error, we are in big trouble.
+		logger.Errorf("failure to marshal SimpleError: %v", err)
+		return errors.Trace(err)
+	}
+	message = append(message, []byte("\n")...)
+	_, err = w.Write(message)
+	return errors.Trace(err)
+}
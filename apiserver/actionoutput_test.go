@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type actionOutputSuite struct {
+	userAuthHttpSuite
+	action *state.Action
+}
+
+var _ = gc.Suite(&actionOutputSuite{})
+
+func (s *actionOutputSuite) SetUpTest(c *gc.C) {
+	s.userAuthHttpSuite.SetUpTest(c)
+	s.PatchValue(apiserver.ActionOutputPollInterval, 10*time.Millisecond)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{
+		Service: s.Factory.MakeService(c, &factory.ServiceParams{
+			Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"}),
+		}),
+	})
+	action, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.action, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *actionOutputSuite) dialOutput(c *gc.C, tag string, header http.Header) *bufio.Reader {
+	u := s.makeURL(c, "wss", "/environment/"+s.envUUID+"/actionoutput", url.Values{
+		"action": {tag},
+	})
+	conn := s.dialWebsocketFromURL(c, u.String(), header)
+	s.AddCleanup(func(*gc.C) { conn.Close() })
+	return bufio.NewReader(conn)
+}
+
+func (s *actionOutputSuite) userHeader() http.Header {
+	return utils.BasicAuthHeader(s.userTag.String(), s.password)
+}
+
+func (s *actionOutputSuite) readMessage(c *gc.C, reader *bufio.Reader) params.ActionOutputMessage {
+	line, err := reader.ReadSlice('\n')
+	c.Assert(err, jc.ErrorIsNil)
+	var message params.ActionOutputMessage
+	err = json.Unmarshal(line, &message)
+	c.Assert(err, jc.ErrorIsNil)
+	return message
+}
+
+func (s *actionOutputSuite) TestNoAuth(c *gc.C) {
+	reader := s.dialOutput(c, s.action.Tag().String(), nil)
+	assertJSONError(c, reader, "auth failed: invalid request format")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *actionOutputSuite) TestBadActionTag(c *gc.C) {
+	reader := s.dialOutput(c, "unit-dummy-0", s.userHeader())
+	assertJSONError(c, reader, `"unit-dummy-0" is not a valid action tag`)
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *actionOutputSuite) TestStreamsOutputUntilFinished(c *gc.C) {
+	err := s.action.AppendOutput(state.ActionOutputStdout, "one\n")
+	c.Assert(err, jc.ErrorIsNil)
+	reader := s.dialOutput(c, s.action.Tag().String(), s.userHeader())
+	errResult := readJSONErrorLine(c, reader)
+	c.Assert(errResult.Error, gc.IsNil)
+
+	message := s.readMessage(c, reader)
+	c.Assert(message.Stream, gc.Equals, "stdout")
+	c.Assert(message.Data, gc.Equals, "one\n")
+
+	err = s.action.AppendOutput(state.ActionOutputStderr, "two\n")
+	c.Assert(err, jc.ErrorIsNil)
+	message = s.readMessage(c, reader)
+	c.Assert(message.Stream, gc.Equals, "stderr")
+	c.Assert(message.Data, gc.Equals, "two\n")
+
+	_, err = s.action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertWebsocketClosed(c, reader)
+}
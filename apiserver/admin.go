@@ -139,6 +139,11 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 			Identity:       entity.Tag().String(),
 			LastConnection: lastConnection,
 		}
+		session, err := loginSession(a.root.state, entity, req)
+		if err != nil {
+			return fail, err
+		}
+		maybeUserInfo.Session = session
 	}
 
 	// Fetch the API server addresses from state.
@@ -178,12 +183,38 @@ func (a *admin) doLogin(req params.LoginRequest, loginVersion int) (params.Login
 		}
 		loginResult.Facades = facades
 	}
+	authedApi = newAuthorizingRoot(authedApi, a.srv.callAuthorizer, a.root.state, a.root)
 
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return loginResult, nil
 }
 
+// loginSession returns the session of a user logging in, starting a new
+// one if the user asked for it, or nil if the user did not ask for one.
+func loginSession(st *state.State, entity state.Entity, req params.LoginRequest) (*params.SessionInfo, error) {
+	user, ok := entity.(*state.User)
+	if !ok {
+		return nil, nil
+	}
+	var session *state.UserSession
+	var err error
+	switch {
+	case req.SessionToken != "":
+		// Report the expiry of the session logged in with, so
+		// that the client knows when to refresh it.
+		session, err = st.UserSession(req.SessionToken)
+	case req.RequestSession:
+		session, err = st.AddUserSession(user.UserTag())
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &params.SessionInfo{Token: session.Token, Expires: session.Expires}, nil
+}
+
 // checkCredsOfStateServerMachine checks the special case of a state server
 // machine creating an API connection for a different environment so it can
 // run API workers for that environment to do things like provisioning
@@ -251,15 +282,27 @@ func checkCreds(st *state.State, req params.LoginRequest, lookForEnvUser bool) (
 		return nil, nil, errors.Trace(err)
 	}
 
-	authenticator, err := authentication.FindEntityAuthenticator(entity)
-	if err != nil {
+	var authenticator authentication.EntityAuthenticator
+	credentials := req.Credentials
+	if req.SessionToken != "" {
+		// Users may log in with the token of an earlier session
+		// in place of their password.
+		authenticator = &authentication.SessionAuthenticator{}
+		credentials = req.SessionToken
+	} else if authenticator, err = authentication.FindEntityAuthenticator(entity); err != nil {
 		return nil, nil, err
 	}
 
-	if err = authenticator.Authenticate(entity, req.Credentials, req.Nonce); err != nil {
+	if err = authenticator.Authenticate(entity, credentials, req.Nonce); err != nil {
 		logger.Debugf("bad credentials")
 		return nil, nil, err
 	}
+	// A session token only stands in for the password, so logins
+	// with one must give any second factor required too.
+	if err := authentication.CheckSecondFactor(entity, req.TOTPCode); err != nil {
+		logger.Debugf("bad second factor")
+		return nil, nil, err
+	}
 
 	// For user logins, update the last login time.
 	// NOTE: this code path is only for local users. When we support remote
@@ -12,4 +12,5 @@ import (
 func init() {
 	common.RegisterStandardFacade("Agent", 0, NewAgentAPIV0)
 	common.RegisterStandardFacade("Agent", 1, NewAgentAPIV1)
+	common.RegisterStandardFacade("Agent", 2, NewAgentAPIV2)
 }
@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+// AgentAPIV2 implements the version 2 of the API provided to an agent.
+type AgentAPIV2 struct {
+	*AgentAPIV1
+	*common.PasswordRotator
+}
+
+// NewAgentAPIV2 returns an object implementing version 2 of the Agent API
+// with the given authorizer representing the currently logged in client.
+// The functionality is like V1, except that agents can also rotate their
+// passwords with SetNextPasswords and ConfirmPasswords.
+func NewAgentAPIV2(st *state.State, resources *common.Resources, auth common.Authorizer) (*AgentAPIV2, error) {
+	apiV1, err := NewAgentAPIV1(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	getCanChange := func() (common.AuthFunc, error) {
+		return auth.AuthOwner, nil
+	}
+	return &AgentAPIV2{
+		AgentAPIV1:      apiV1,
+		PasswordRotator: common.NewPasswordRotator(st, getCanChange),
+	}, nil
+}
@@ -0,0 +1,102 @@
+package agent_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/agent"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+// V2 test suite, adding password rotation.
+
+func factoryWrapperV2(st *state.State, resources *common.Resources, auth common.Authorizer) (interface{}, error) {
+	return agent.NewAgentAPIV2(st, resources, auth)
+}
+
+type agentSuiteV2 struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&agentSuiteV2{})
+
+func (s *agentSuiteV2) TestAgentFailsWithNonAgent(c *gc.C) {
+	s.testAgentFailsWithNonAgentV0(c, factoryWrapperV2)
+}
+
+func (s *agentSuiteV2) TestAgentSucceedsWithUnitAgent(c *gc.C) {
+	s.testAgentSucceedsWithUnitAgentV0(c, factoryWrapperV2)
+}
+
+func (s *agentSuiteV2) TestGetEntities(c *gc.C) {
+	s.testGetEntitiesV0(c, s.newAPI(c))
+}
+
+func (s *agentSuiteV2) TestSetPasswords(c *gc.C) {
+	s.testSetPasswordsV0(c, s.newAPI(c))
+}
+
+func (s *agentSuiteV2) TestRotatePassword(c *gc.C) {
+	api := s.newAPI(c)
+	err := s.machine1.SetPassword("xxx-12345678901234567890")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.SetNextPasswords(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "machine-0", Password: "xxx-12345678901234567890"},
+			{Tag: "machine-1", Password: "yyy-12345678901234567890"},
+			{Tag: "machine-42", Password: "zzz-12345678901234567890"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.PasswordValid("xxx-12345678901234567890"), jc.IsTrue)
+	c.Assert(s.machine1.PasswordValid("yyy-12345678901234567890"), jc.IsTrue)
+
+	results, err = api.ConfirmPasswords(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "machine-0", Password: "xxx-12345678901234567890"},
+			{Tag: "machine-1", Password: "yyy-12345678901234567890"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{apiservertesting.ErrUnauthorized},
+			{nil},
+		},
+	})
+	err = s.machine1.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine1.PasswordValid("xxx-12345678901234567890"), jc.IsFalse)
+	c.Assert(s.machine1.PasswordValid("yyy-12345678901234567890"), jc.IsTrue)
+}
+
+func (s *agentSuiteV2) TestConfirmPasswordMismatch(c *gc.C) {
+	results, err := s.newAPI(c).ConfirmPasswords(params.EntityPasswords{
+		Changes: []params.EntityPassword{
+			{Tag: "machine-1", Password: "yyy-12345678901234567890"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches,
+		"cannot confirm password of machine 1: password does not match")
+}
+
+func (s *agentSuiteV2) newAPI(c *gc.C) *agent.AgentAPIV2 {
+	api, err := agent.NewAgentAPIV2(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
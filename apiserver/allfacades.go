@@ -10,17 +10,26 @@ import (
 	_ "github.com/juju/juju/apiserver/action"
 	_ "github.com/juju/juju/apiserver/agent"
 	_ "github.com/juju/juju/apiserver/annotations"
+	_ "github.com/juju/juju/apiserver/apischema"
 	_ "github.com/juju/juju/apiserver/backups"
 	_ "github.com/juju/juju/apiserver/block"
 	_ "github.com/juju/juju/apiserver/charmrevisionupdater"
 	_ "github.com/juju/juju/apiserver/charms"
+	_ "github.com/juju/juju/apiserver/charmvalidation"
 	_ "github.com/juju/juju/apiserver/client"
+	_ "github.com/juju/juju/apiserver/clouds"
+	_ "github.com/juju/juju/apiserver/configsecrets"
+	_ "github.com/juju/juju/apiserver/consistency"
+	_ "github.com/juju/juju/apiserver/controllerusage"
+	_ "github.com/juju/juju/apiserver/credentials"
 	_ "github.com/juju/juju/apiserver/deployer"
 	_ "github.com/juju/juju/apiserver/diskmanager"
 	_ "github.com/juju/juju/apiserver/environment"
 	_ "github.com/juju/juju/apiserver/environmentmanager"
 	_ "github.com/juju/juju/apiserver/firewaller"
+	_ "github.com/juju/juju/apiserver/hostkeyreporter"
 	_ "github.com/juju/juju/apiserver/imagemanager"
+	_ "github.com/juju/juju/apiserver/introspection"
 	_ "github.com/juju/juju/apiserver/keymanager"
 	_ "github.com/juju/juju/apiserver/keyupdater"
 	_ "github.com/juju/juju/apiserver/logger"
@@ -29,12 +38,18 @@ import (
 	_ "github.com/juju/juju/apiserver/metricsmanager"
 	_ "github.com/juju/juju/apiserver/networker"
 	_ "github.com/juju/juju/apiserver/provisioner"
+	_ "github.com/juju/juju/apiserver/provisioningerrors"
 	_ "github.com/juju/juju/apiserver/reboot"
 	_ "github.com/juju/juju/apiserver/rsyslog"
+	_ "github.com/juju/juju/apiserver/scale"
 	_ "github.com/juju/juju/apiserver/service"
 	_ "github.com/juju/juju/apiserver/storage"
 	_ "github.com/juju/juju/apiserver/storageprovisioner"
+	_ "github.com/juju/juju/apiserver/systemmanager"
 	_ "github.com/juju/juju/apiserver/uniter"
+	_ "github.com/juju/juju/apiserver/unitutilization"
+	_ "github.com/juju/juju/apiserver/upgradechecks"
 	_ "github.com/juju/juju/apiserver/upgrader"
 	_ "github.com/juju/juju/apiserver/usermanager"
+	_ "github.com/juju/juju/apiserver/webhooks"
 )
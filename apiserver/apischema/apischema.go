@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package apischema implements the API facade used to describe the
+// facades served by the API server, so that clients in other languages
+// can be generated from it.
+package apischema
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("APISchema", 1, NewAPISchemaAPI)
+}
+
+// APISchemaAPI implements the APISchema facade.
+type APISchemaAPI struct {
+	registry *common.FacadeRegistry
+}
+
+// NewAPISchemaAPI creates a new server-side APISchema API end point.
+// Only the owner of the state server environment may use it.
+func NewAPISchemaAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*APISchemaAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &APISchemaAPI{registry: common.Facades}, nil
+}
+
+// Schema returns a description of every version of every facade
+// served by the API server, with the methods of each and the types
+// of their arguments and results.
+func (api *APISchemaAPI) Schema() (params.APISchemaResult, error) {
+	return Describe(api.registry), nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apischema_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/apischema"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type apiSchemaSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&apiSchemaSuite{})
+
+func (s *apiSchemaSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := apischema.NewAPISchemaAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *apiSchemaSuite) TestAgentRejected(c *gc.C) {
+	_, err := apischema.NewAPISchemaAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *apiSchemaSuite) TestSchema(c *gc.C) {
+	api, err := apischema.NewAPISchemaAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.Schema()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var found bool
+	for _, facade := range result.Facades {
+		if facade.Name == "APISchema" && facade.Version == 1 {
+			found = true
+			c.Check(facade.Methods, jc.DeepEquals, []params.MethodSchema{{
+				Name:   "Schema",
+				Result: &params.TypeSchema{Kind: "ref", Ref: "params.APISchemaResult"},
+			}})
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+	c.Assert(result.Types["params.MethodSchema"], jc.DeepEquals, params.TypeSchema{
+		Kind: "struct",
+		Fields: []params.FieldSchema{{
+			Name: "name",
+			Type: params.TypeSchema{Kind: "string"},
+		}, {
+			Name:     "params",
+			Type:     params.TypeSchema{Kind: "ref", Ref: "params.TypeSchema"},
+			Optional: true,
+		}, {
+			Name:     "result",
+			Type:     params.TypeSchema{Kind: "ref", Ref: "params.TypeSchema"},
+			Optional: true,
+		}},
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apischema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Describe returns a description of the facades in the given registry,
+// found by reflecting on their types. Facades disabled by a feature
+// flag are left out.
+func Describe(registry *common.FacadeRegistry) params.APISchemaResult {
+	b := &schemaBuilder{types: make(map[string]params.TypeSchema)}
+	var facades []params.FacadeSchema
+	for _, facade := range registry.List() {
+		for _, version := range facade.Versions {
+			goType, err := registry.GetType(facade.Name, version)
+			if err != nil {
+				continue
+			}
+			facades = append(facades, params.FacadeSchema{
+				Name:    facade.Name,
+				Version: version,
+				Methods: b.methods(rpcreflect.ObjTypeOf(goType)),
+			})
+		}
+	}
+	return params.APISchemaResult{
+		Facades: facades,
+		Types:   b.types,
+	}
+}
+
+// schemaBuilder describes types, collecting the definitions of the
+// named struct types it comes across.
+type schemaBuilder struct {
+	types map[string]params.TypeSchema
+}
+
+// methods describes the RPC methods of a facade.
+func (b *schemaBuilder) methods(objType *rpcreflect.ObjType) []params.MethodSchema {
+	methods := []params.MethodSchema{}
+	for _, name := range objType.MethodNames() {
+		method, err := objType.Method(name)
+		if err != nil {
+			continue
+		}
+		methods = append(methods, params.MethodSchema{
+			Name:   name,
+			Params: b.optionalType(method.Params),
+			Result: b.optionalType(method.Result),
+		})
+	}
+	return methods
+}
+
+func (b *schemaBuilder) optionalType(t reflect.Type) *params.TypeSchema {
+	if t == nil {
+		return nil
+	}
+	schema := b.typeSchema(t)
+	return &schema
+}
+
+// typeSchema describes the JSON encoding of t. Named struct types are
+// referred to by name, so that recursive types can be described.
+func (b *schemaBuilder) typeSchema(t reflect.Type) params.TypeSchema {
+	if t == timeType {
+		return params.TypeSchema{Kind: "time"}
+	}
+	if t.Kind() != reflect.Ptr && (t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType)) {
+		// The encoding of a type that marshals itself cannot be known.
+		return params.TypeSchema{Kind: "any"}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.typeSchema(t.Elem())
+	case reflect.Bool:
+		return params.TypeSchema{Kind: "bool"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return params.TypeSchema{Kind: "int"}
+	case reflect.Float32, reflect.Float64:
+		return params.TypeSchema{Kind: "float"}
+	case reflect.String:
+		return params.TypeSchema{Kind: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return params.TypeSchema{Kind: "bytes"}
+		}
+		elem := b.typeSchema(t.Elem())
+		return params.TypeSchema{Kind: "array", Elem: &elem}
+	case reflect.Map:
+		elem := b.typeSchema(t.Elem())
+		return params.TypeSchema{Kind: "map", Elem: &elem}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return params.TypeSchema{Kind: "struct", Fields: b.fields(t)}
+		}
+		name := t.String()
+		if _, ok := b.types[name]; !ok {
+			// Record the type before describing its fields, so that
+			// fields referring back to it do not recurse.
+			b.types[name] = params.TypeSchema{Kind: "struct"}
+			b.types[name] = params.TypeSchema{Kind: "struct", Fields: b.fields(t)}
+		}
+		return params.TypeSchema{Kind: "ref", Ref: name}
+	}
+	return params.TypeSchema{Kind: "any"}
+}
+
+// fields describes the fields of a struct as encoding/json encodes
+// them, with the fields of untagged embedded structs promoted.
+func (b *schemaBuilder) fields(t reflect.Type) []params.FieldSchema {
+	var fields []params.FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, b.fields(embedded)...)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		optional := false
+		for _, option := range tag[1:] {
+			if option == "omitempty" {
+				optional = true
+			}
+		}
+		fields = append(fields, params.FieldSchema{
+			Name:     name,
+			Type:     b.typeSchema(field.Type),
+			Optional: optional,
+		})
+	}
+	return fields
+}
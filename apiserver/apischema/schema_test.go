@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apischema_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/apischema"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type schemaSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&schemaSuite{})
+
+type testEmbedded struct {
+	Data []byte `json:"data"`
+}
+
+type testArgs struct {
+	Tags  []string `json:"tags"`
+	Limit int      `json:"limit,omitempty"`
+	Since time.Time
+	testEmbedded
+	Ignored string `json:"-"`
+	hidden  string
+}
+
+type testNode struct {
+	Name     string
+	Children map[string]*testNode
+	Value    interface{}
+}
+
+type testFacade struct{}
+
+func (testFacade) Find(args testArgs) (testNode, error) { return testNode{}, nil }
+func (testFacade) Ping()                                {}
+func (testFacade) Stop() error                          { return nil }
+
+func testFactory(*state.State, *common.Resources, common.Authorizer, string) (interface{}, error) {
+	return testFacade{}, nil
+}
+
+func (s *schemaSuite) TestDescribe(c *gc.C) {
+	registry := &common.FacadeRegistry{}
+	facadeType := reflect.TypeOf(testFacade{})
+	c.Assert(registry.Register("Test", 1, testFactory, facadeType, ""), jc.ErrorIsNil)
+	c.Assert(registry.Register("Test", 2, testFactory, facadeType, ""), jc.ErrorIsNil)
+	c.Assert(registry.Register("Hidden", 1, testFactory, facadeType, "no-such-feature"), jc.ErrorIsNil)
+
+	methods := []params.MethodSchema{{
+		Name:   "Find",
+		Params: &params.TypeSchema{Kind: "ref", Ref: "apischema_test.testArgs"},
+		Result: &params.TypeSchema{Kind: "ref", Ref: "apischema_test.testNode"},
+	}, {
+		Name: "Ping",
+	}, {
+		Name: "Stop",
+	}}
+	c.Assert(apischema.Describe(registry), jc.DeepEquals, params.APISchemaResult{
+		Facades: []params.FacadeSchema{
+			{Name: "Test", Version: 1, Methods: methods},
+			{Name: "Test", Version: 2, Methods: methods},
+		},
+		Types: map[string]params.TypeSchema{
+			"apischema_test.testArgs": {Kind: "struct", Fields: []params.FieldSchema{{
+				Name: "tags",
+				Type: params.TypeSchema{Kind: "array", Elem: &params.TypeSchema{Kind: "string"}},
+			}, {
+				Name:     "limit",
+				Type:     params.TypeSchema{Kind: "int"},
+				Optional: true,
+			}, {
+				Name: "Since",
+				Type: params.TypeSchema{Kind: "time"},
+			}, {
+				Name: "data",
+				Type: params.TypeSchema{Kind: "bytes"},
+			}}},
+			"apischema_test.testNode": {Kind: "struct", Fields: []params.FieldSchema{{
+				Name: "Name",
+				Type: params.TypeSchema{Kind: "string"},
+			}, {
+				Name: "Children",
+				Type: params.TypeSchema{Kind: "map", Elem: &params.TypeSchema{Kind: "ref", Ref: "apischema_test.testNode"}},
+			}, {
+				Name: "Value",
+				Type: params.TypeSchema{Kind: "any"},
+			}}},
+		},
+	})
+}
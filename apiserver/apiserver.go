@@ -25,9 +25,12 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/introspection"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
@@ -47,10 +50,17 @@ type Server struct {
 	logDir            string
 	limiter           utils.Limiter
 	validator         LoginValidator
+	callAuthorizer    CallAuthorizer
 	adminApiFactories map[int]adminApiFactory
+	metricsRegistry   *introspection.Registry
+	metrics           *serverMetrics
+	usage             *usageTracker
+	hub               *pubsub.Hub
+	agentReporter     worker.Reporter
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
+	handlers    map[*apiHandler]bool
 }
 
 // LoginValidator functions are used to decide whether login requests
@@ -67,6 +77,25 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// CallAuthorizer, if set, decides whether facade method calls
+	// may be made. If nil, DefaultCallAuthorizer is used.
+	CallAuthorizer CallAuthorizer
+
+	// Metrics, if set, is the registry that the server adds its
+	// metrics to. All the metrics in the registry are served at
+	// /metrics. If nil, only the server's own metrics are served.
+	Metrics *introspection.Registry
+
+	// Hub, if set, is the hub on which facades publish events for
+	// the workers of the state server. If nil, the server uses a
+	// hub of its own.
+	Hub *pubsub.Hub
+
+	// AgentReporter, if set, reports on the workers of the agent
+	// running the server, so that clients can inspect them through
+	// the Introspection facade.
+	AgentReporter worker.Reporter
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -183,19 +212,32 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 		return nil, err
 	}
 	srv := &Server{
-		state:     s,
-		addr:      net.JoinHostPort("localhost", listeningPort),
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:          s,
+		addr:           net.JoinHostPort("localhost", listeningPort),
+		tag:            cfg.Tag,
+		dataDir:        cfg.DataDir,
+		logDir:         cfg.LogDir,
+		limiter:        utils.NewLimiter(loginRateLimit),
+		validator:      cfg.Validator,
+		callAuthorizer: cfg.CallAuthorizer,
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
 			2: newAdminApiV2,
 		},
+		metricsRegistry: cfg.Metrics,
+		hub:             cfg.Hub,
+		agentReporter:   cfg.AgentReporter,
+		usage:           newUsageTracker(),
+		handlers:        make(map[*apiHandler]bool),
 	}
+	if srv.metricsRegistry == nil {
+		srv.metricsRegistry = introspection.NewRegistry()
+	}
+	if srv.hub == nil {
+		srv.hub = pubsub.NewHub()
+	}
+	srv.registerMetrics(srv.metricsRegistry)
 	// TODO(rog) check that *srvRoot is a valid type for using
 	// as an RPC server.
 	tlsConfig := tls.Config{
@@ -326,6 +368,12 @@ func (srv *Server) run(lis net.Listener) {
 		srv.tomb.Kill(err)
 		srv.wg.Done()
 	}()
+	srv.wg.Add(1)
+	go func() {
+		err := srv.usageFlusher()
+		srv.tomb.Kill(err)
+		srv.wg.Done()
+	}()
 	// for pat based handlers, they are matched in-order of being
 	// registered, first match wins. So more specific ones have to be
 	// registered first.
@@ -340,9 +388,20 @@ func (srv *Server) run(lis net.Listener) {
 		handleAll(mux, "/environment/:envuuid/logsink",
 			&logSinkHandler{
 				httpHandler: httpHandler{ssState: srv.state},
+				limiter:     newLogSinkLimiter(),
 			},
 		)
 	}
+	handleAll(mux, "/environment/:envuuid/sshtunnel",
+		&sshTunnelHandler{
+			httpHandler: httpHandler{ssState: srv.state},
+		},
+	)
+	handleAll(mux, "/environment/:envuuid/actionoutput",
+		&actionOutputHandler{
+			httpHandler: httpHandler{ssState: srv.state},
+		},
+	)
 	handleAll(mux, "/environment/:envuuid/charms",
 		&charmsHandler{
 			httpHandler: httpHandler{ssState: srv.state},
@@ -394,6 +453,12 @@ func (srv *Server) run(lis net.Listener) {
 			httpHandler{ssState: srv.state},
 		}},
 	)
+	handleAll(mux, "/metrics",
+		&metricsHandler{
+			httpHandler: httpHandler{ssState: srv.state},
+			registry:    srv.metricsRegistry,
+		},
+	)
 	handleAll(mux, "/", http.HandlerFunc(srv.apiHandler))
 	// The error from http.Serve is not interesting.
 	http.Serve(lis, mux)
@@ -430,7 +495,17 @@ func (srv *Server) Addr() string {
 }
 
 func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
-	codec := jsoncodec.NewWebsocket(wsConn)
+	st, _, err := validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
+	var usage envUsageCounter
+	if err == nil {
+		usage = envUsageCounter{tracker: srv.usage, envUUID: st.EnvironUUID()}
+	}
+	var codec *jsoncodec.Codec
+	if usage.tracker != nil {
+		codec = jsoncodec.NewWebsocketCounted(wsConn, usage)
+	} else {
+		codec = jsoncodec.NewWebsocket(wsConn)
+	}
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
@@ -440,13 +515,20 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 		// know we'll need it.
 		notifier = reqNotifier
 	}
+	notifier = &metricsNotifier{next: notifier, metrics: srv.metrics}
+	if usage.tracker != nil {
+		notifier = &usageNotifier{next: notifier, counter: usage}
+	}
 	conn := rpc.NewConn(codec, notifier)
 
 	var h *apiHandler
-	st, _, err := validateEnvironUUID(validateArgs{st: srv.state, envUUID: envUUID})
 	if err == nil {
 		h, err = newApiHandler(srv, st, conn, reqNotifier, envUUID)
 	}
+	if h != nil {
+		srv.addHandler(h)
+		defer srv.removeHandler(h)
+	}
 	if err != nil {
 		conn.Serve(&errRoot{err}, serverError)
 	} else {
@@ -456,6 +538,8 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifie
 		}
 		conn.ServeFinder(newAnonRoot(h, adminApis), serverError)
 	}
+	srv.metrics.connections.Inc()
+	defer srv.metrics.connections.Dec()
 	conn.Start()
 	select {
 	case <-conn.Dead():
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/totp"
+)
+
+// CheckSecondFactor checks the time-based one-time password given by
+// an entity that has already authenticated with its password or a
+// session token. Only users enrolled for two-factor authentication need
+// give a code; for them, ErrSecondFactorRequired is returned if the
+// code is missing, so that the client can ask for one. Each code is
+// accepted only once, so codes that are no later than the last one
+// accepted are rejected.
+func CheckSecondFactor(entity state.Entity, code string) error {
+	user, ok := entity.(*state.User)
+	if !ok || user.TOTPSecret() == "" {
+		return nil
+	}
+	if code == "" {
+		return common.ErrSecondFactorRequired
+	}
+	step, ok := totp.Step(user.TOTPSecret(), code, time.Now())
+	if !ok {
+		return common.ErrBadCreds
+	}
+	if err := user.UseTOTPStep(step); err == state.ErrTOTPCodeUsed {
+		return common.ErrBadCreds
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/totp"
+)
+
+type secondFactorSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&secondFactorSuite{})
+
+func (s *secondFactorSuite) TestNotEnrolled(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	err := authentication.CheckSecondFactor(user, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = authentication.CheckSecondFactor(machine, "")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *secondFactorSuite) TestEnrolled(c *gc.C) {
+	user := s.Factory.MakeUser(c, nil)
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetTOTPSecret(secret)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = authentication.CheckSecondFactor(user, "")
+	c.Assert(err, gc.ErrorMatches, "two-factor authentication code required")
+	c.Assert(params.IsCodeSecondFactorRequired(common.ServerError(err)), jc.IsTrue)
+
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = authentication.CheckSecondFactor(user, code)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A code cannot be replayed, nor can an earlier one be used.
+	err = authentication.CheckSecondFactor(user, code)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+	code, err = totp.Code(secret, time.Now().Add(-totp.Period))
+	c.Assert(err, jc.ErrorIsNil)
+	err = authentication.CheckSecondFactor(user, code)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+
+	code, err = totp.Code(secret, time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = authentication.CheckSecondFactor(user, code)
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
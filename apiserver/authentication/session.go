@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+)
+
+// SessionAuthenticator performs authentication for users logging in
+// with the token of a session started by an earlier login.
+type SessionAuthenticator struct{}
+
+var _ EntityAuthenticator = (*SessionAuthenticator)(nil)
+
+// Authenticate authenticates the provided entity, treating the password
+// as a session token, and returns an error on authentication failure.
+func (*SessionAuthenticator) Authenticate(entity state.Entity, token, nonce string) error {
+	user, ok := entity.(*state.User)
+	if !ok {
+		return common.ErrBadRequest
+	}
+	if !user.SessionValid(token) {
+		return common.ErrBadCreds
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package authentication_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/authentication"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type sessionAuthenticatorSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&sessionAuthenticatorSuite{})
+
+func (s *sessionAuthenticatorSuite) TestValidSession(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bobbrown"})
+	session, err := s.State.AddUserSession(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	authenticator := &authentication.SessionAuthenticator{}
+	err = authenticator.Authenticate(user, session.Token, "")
+	c.Assert(err, jc.ErrorIsNil)
+	err = authenticator.Authenticate(user, "wrong", "")
+	c.Assert(err, gc.ErrorMatches, "invalid entity name or password")
+}
+
+func (s *sessionAuthenticatorSuite) TestMachineLoginFails(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	authenticator := &authentication.SessionAuthenticator{}
+	err = authenticator.Authenticate(machine, "token", "")
+	c.Assert(err, gc.ErrorMatches, "invalid request")
+}
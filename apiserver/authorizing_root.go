@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+	"github.com/juju/juju/state"
+)
+
+// FacadeCall describes a call to a facade method that is to be
+// authorized.
+type FacadeCall struct {
+	// State is the state of the environment the call is made in.
+	State *state.State
+
+	// Authorizer describes the authenticated entity making the call.
+	Authorizer common.Authorizer
+
+	RootName   string
+	Version    int
+	MethodName string
+}
+
+// CallAuthorizer decides whether a facade method call may be made.
+// It is consulted for every call made by an authenticated entity,
+// before the facade is created, so alternative policies (for example
+// ones based on LDAP groups or roles) can be plugged into the API
+// server without changing the facades themselves.
+type CallAuthorizer interface {
+	// AuthorizeCall returns an error if the call is not allowed.
+	AuthorizeCall(call FacadeCall) error
+}
+
+// CallAuthorizerFunc is a function that implements CallAuthorizer.
+type CallAuthorizerFunc func(call FacadeCall) error
+
+// AuthorizeCall implements CallAuthorizer.
+func (f CallAuthorizerFunc) AuthorizeCall(call FacadeCall) error {
+	return f(call)
+}
+
+// systemAdminRootNames are the facades that may only be used by the
+// administrator of the system, the owner of the state server
+// environment. Facades that restrict themselves to that user must be
+// listed here too, so that a replacement CallAuthorizer sees them.
+var systemAdminRootNames = set.NewStrings(
+	"APISchema",
+	"Clouds",
+	"Consistency",
+	"ControllerUsage",
+	"Credentials",
+	"Introspection",
+	"SystemManager",
+)
+
+// DefaultCallAuthorizer is the CallAuthorizer used by the API server
+// when none is given in its configuration. It only allows the owner of
+// the state server environment to call the system-wide facades, and
+// leaves all other checks to the facades themselves.
+var DefaultCallAuthorizer CallAuthorizer = CallAuthorizerFunc(defaultAuthorizeCall)
+
+func defaultAuthorizeCall(call FacadeCall) error {
+	if !systemAdminRootNames.Contains(call.RootName) {
+		return nil
+	}
+	// TODO: PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the initial
+	// environment is able to manage the system.
+	if !call.Authorizer.AuthClient() {
+		return common.ErrPerm
+	}
+	apiUser, ok := call.Authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	stateServerEnv, err := call.State.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// authorizingRoot checks each API call with a CallAuthorizer before
+// allowing it to be made.
+type authorizingRoot struct {
+	rpc.MethodFinder
+	callAuthorizer CallAuthorizer
+	state          *state.State
+	authorizer     common.Authorizer
+}
+
+// newAuthorizingRoot returns a new authorizingRoot. If callAuthorizer
+// is nil, DefaultCallAuthorizer is used.
+func newAuthorizingRoot(
+	finder rpc.MethodFinder,
+	callAuthorizer CallAuthorizer,
+	st *state.State,
+	authorizer common.Authorizer,
+) *authorizingRoot {
+	if callAuthorizer == nil {
+		callAuthorizer = DefaultCallAuthorizer
+	}
+	return &authorizingRoot{
+		MethodFinder:   finder,
+		callAuthorizer: callAuthorizer,
+		state:          st,
+		authorizer:     authorizer,
+	}
+}
+
+// FindMethod returns the error reported by the CallAuthorizer if the
+// authenticated entity may not make the call.
+func (r *authorizingRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	// The lookup of the name is done first to return a not found error if the
+	// user is looking for a method that we just don't have.
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	err = r.callAuthorizer.AuthorizeCall(FacadeCall{
+		State:      r.state,
+		Authorizer: r.authorizer,
+		RootName:   rootName,
+		Version:    version,
+		MethodName: methodName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return caller, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/common"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type authorizingRootSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&authorizingRootSuite{})
+
+func (s *authorizingRootSuite) TestCallAuthorizerConsulted(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("bob")}
+	var calls []apiserver.FacadeCall
+	callAuthorizer := apiserver.CallAuthorizerFunc(func(call apiserver.FacadeCall) error {
+		calls = append(calls, call)
+		return nil
+	})
+	root := apiserver.TestingAuthorizingRoot(nil, callAuthorizer, authorizer)
+
+	caller, err := root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+	c.Assert(calls, jc.DeepEquals, []apiserver.FacadeCall{{
+		Authorizer: authorizer,
+		RootName:   "Client",
+		Version:    0,
+		MethodName: "FullStatus",
+	}})
+}
+
+func (s *authorizingRootSuite) TestCallAuthorizerRefuses(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("bob")}
+	callAuthorizer := apiserver.CallAuthorizerFunc(func(call apiserver.FacadeCall) error {
+		if call.MethodName == "ServiceDestroy" {
+			return common.ErrPerm
+		}
+		return nil
+	})
+	root := apiserver.TestingAuthorizingRoot(nil, callAuthorizer, authorizer)
+
+	caller, err := root.FindMethod("Client", 0, "ServiceDestroy")
+	c.Assert(err, gc.Equals, common.ErrPerm)
+	c.Assert(caller, gc.IsNil)
+
+	caller, err = root.FindMethod("Client", 0, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
+func (s *authorizingRootSuite) TestNonExistentFacade(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: names.NewUserTag("bob")}
+	callAuthorizer := apiserver.CallAuthorizerFunc(func(apiserver.FacadeCall) error {
+		return errors.New("should not be called")
+	})
+	root := apiserver.TestingAuthorizingRoot(nil, callAuthorizer, authorizer)
+
+	caller, err := root.FindMethod("NonExistent", 0, "Method")
+	c.Assert(err, gc.ErrorMatches, `unknown object type "NonExistent"`)
+	c.Assert(caller, gc.IsNil)
+}
+
+type defaultCallAuthorizerSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&defaultCallAuthorizerSuite{})
+
+func (s *defaultCallAuthorizerSuite) authorizeCall(tag names.Tag, rootName string) error {
+	return apiserver.DefaultCallAuthorizer.AuthorizeCall(apiserver.FacadeCall{
+		State:      s.State,
+		Authorizer: apiservertesting.FakeAuthorizer{Tag: tag},
+		RootName:   rootName,
+		Version:    1,
+		MethodName: "AllEnvironments",
+	})
+}
+
+func (s *defaultCallAuthorizerSuite) TestOtherFacadesAllowed(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	err := s.authorizeCall(user.UserTag(), "Client")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.authorizeCall(names.NewMachineTag("0"), "Provisioner")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+var systemFacades = []string{
+	"APISchema",
+	"Clouds",
+	"Consistency",
+	"ControllerUsage",
+	"Credentials",
+	"Introspection",
+	"SystemManager",
+}
+
+func (s *defaultCallAuthorizerSuite) TestSystemFacadeAllowsAdmin(c *gc.C) {
+	for _, rootName := range systemFacades {
+		c.Logf("facade %s", rootName)
+		err := s.authorizeCall(s.AdminUserTag(c), rootName)
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *defaultCallAuthorizerSuite) TestSystemFacadeRefusesNonAdmin(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	for _, rootName := range systemFacades {
+		c.Logf("facade %s", rootName)
+		err := s.authorizeCall(user.UserTag(), rootName)
+		c.Check(err, gc.Equals, common.ErrPerm)
+	}
+}
+
+func (s *defaultCallAuthorizerSuite) TestSystemFacadeRefusesAgents(c *gc.C) {
+	for _, rootName := range systemFacades {
+		c.Logf("facade %s", rootName)
+		err := s.authorizeCall(names.NewMachineTag("0"), rootName)
+		c.Check(err, gc.Equals, common.ErrPerm)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/state"
 )
 
@@ -57,13 +58,21 @@ func (api *CharmRevisionUpdaterAPI) UpdateLatestRevisions() (params.ErrorResult,
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
 	uuid := env.UUID()
+	envConfig, err := api.state.EnvironConfig()
+	if err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
+	csParams, err := common.CharmStoreParams(envConfig)
+	if err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
 
 	deployedCharms, err := fetchAllDeployedCharms(api.state)
 	if err != nil {
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
 	// Look up the revision information for all the deployed charms.
-	curls, err := retrieveLatestCharmInfo(deployedCharms, uuid)
+	curls, err := retrieveLatestCharmInfo(deployedCharms, uuid, csParams)
 	if err != nil {
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
@@ -100,7 +109,7 @@ var NewCharmStore = charmrepo.NewCharmStore
 
 // retrieveLatestCharmInfo looks up the charm store to return the charm URLs for the
 // latest revision of the deployed charms.
-func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, uuid string) ([]*charm.URL, error) {
+func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, uuid string, csParams charmrepo.NewCharmStoreParams) ([]*charm.URL, error) {
 	var curls []*charm.URL
 	for _, curl := range deployedCharms {
 		if curl.Schema == "local" {
@@ -114,10 +123,11 @@ func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, uuid string)
 
 	// Do a bulk call to get the revision info for all charms.
 	logger.Infof("retrieving revision information for %d charms", len(curls))
-	repo := NewCharmStore(charmrepo.NewCharmStoreParams{})
+	repo := NewCharmStore(csParams)
 	repo = repo.(*charmrepo.CharmStore).WithJujuAttrs(map[string]string{
 		"environment_uuid": uuid,
 	})
+	repo = charmstore.NewRetryingRepo(repo, charmstore.DefaultRetryPolicy, nil)
 	revInfo, err := repo.Latest(curls...)
 	if err != nil {
 		err = errors.Annotate(err, "finding charm revision info")
@@ -0,0 +1,275 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmvalidation implements the API facade used to check a
+// charm against the features supported by the controller before it is
+// deployed.
+package charmvalidation
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/hooks"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/uniter/hook"
+)
+
+func init() {
+	common.RegisterStandardFacade("CharmValidation", 1, NewCharmValidationAPI)
+}
+
+// CharmValidationAPI implements the CharmValidation facade.
+type CharmValidationAPI struct {
+	st *state.State
+}
+
+// NewCharmValidationAPI creates a new server-side CharmValidation API
+// end point.
+func NewCharmValidationAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*CharmValidationAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &CharmValidationAPI{st: st}, nil
+}
+
+// ValidateCharm checks the given charm archive against the features
+// supported by the controller, returning a warning for each feature
+// the charm uses that will not work when it is deployed. An error is
+// returned only if the archive cannot be read.
+func (api *CharmValidationAPI) ValidateCharm(args params.ValidateCharmArgs) (params.ValidateCharmResult, error) {
+	archive, err := readArchive(args.Archive)
+	if err != nil {
+		return params.ValidateCharmResult{}, errors.Annotate(err, "invalid charm archive")
+	}
+	series := args.Series
+	if series == "" {
+		cfg, err := api.st.EnvironConfig()
+		if err != nil {
+			return params.ValidateCharmResult{}, errors.Trace(err)
+		}
+		series = config.PreferredSeries(cfg)
+	}
+	var warnings []params.CharmCompatibilityWarning
+	warnings = append(warnings, checkSeries(series)...)
+	warnings = append(warnings, checkMetadata(archive.metadata)...)
+	warnings = append(warnings, checkStorage(archive.meta)...)
+	warnings = append(warnings, checkHooks(archive.meta, archive.hooks)...)
+	return params.ValidateCharmResult{
+		Series:   series,
+		Warnings: warnings,
+	}, nil
+}
+
+// charmArchive holds the parts of a charm archive that are validated.
+type charmArchive struct {
+	meta     *charm.Meta
+	metadata map[string]interface{}
+	hooks    []string
+}
+
+// readArchive reads the metadata and the names of the hooks of the
+// zipped charm in data.
+func readArchive(data []byte) (*charmArchive, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	archive := &charmArchive{}
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if dir, name := path.Split(f.Name); dir == "hooks/" {
+			archive.hooks = append(archive.hooks, name)
+			continue
+		}
+		if f.Name != "metadata.yaml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := goyaml.Unmarshal(data, &archive.metadata); err != nil {
+			return nil, errors.Annotate(err, "cannot parse metadata.yaml")
+		}
+		if archive.meta, err = charm.ReadMeta(bytes.NewReader(data)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if archive.meta == nil {
+		return nil, errors.New("no metadata.yaml")
+	}
+	sort.Strings(archive.hooks)
+	return archive, nil
+}
+
+// knownMetadataFields holds the fields of metadata.yaml understood by
+// the controller.
+var knownMetadataFields = set.NewStrings(
+	"name", "summary", "description", "subordinate",
+	"provides", "requires", "peers", "revision", "format",
+	"categories", "tags", "series", "storage",
+)
+
+func checkSeries(series string) []params.CharmCompatibilityWarning {
+	if set.NewStrings(version.SupportedSeries()...).Contains(series) {
+		return nil
+	}
+	return []params.CharmCompatibilityWarning{{
+		Kind:    "series",
+		Name:    series,
+		Message: fmt.Sprintf("series %q is not supported by this controller", series),
+	}}
+}
+
+// checkMetadata warns about the fields of metadata.yaml that the
+// controller ignores. Resources, which are not supported at all, are
+// warned about one by one.
+func checkMetadata(metadata map[string]interface{}) []params.CharmCompatibilityWarning {
+	var fields []string
+	for field := range metadata {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var warnings []params.CharmCompatibilityWarning
+	for _, field := range fields {
+		switch {
+		case knownMetadataFields.Contains(field):
+		case field == "resources":
+			warnings = append(warnings, checkResources(metadata[field])...)
+		default:
+			warnings = append(warnings, params.CharmCompatibilityWarning{
+				Kind:    "metadata",
+				Name:    field,
+				Message: fmt.Sprintf("metadata field %q is not supported by this controller and will be ignored", field),
+			})
+		}
+	}
+	return warnings
+}
+
+func checkResources(resources interface{}) []params.CharmCompatibilityWarning {
+	byName, _ := resources.(map[interface{}]interface{})
+	var names []string
+	for name := range byName {
+		names = append(names, fmt.Sprint(name))
+	}
+	sort.Strings(names)
+	var warnings []params.CharmCompatibilityWarning
+	for _, name := range names {
+		resourceType := "file"
+		if resource, ok := byName[name].(map[interface{}]interface{}); ok {
+			if t, ok := resource["type"].(string); ok {
+				resourceType = t
+			}
+		}
+		warnings = append(warnings, params.CharmCompatibilityWarning{
+			Kind:    "resource",
+			Name:    name,
+			Message: fmt.Sprintf("resources of type %q are not supported by this controller", resourceType),
+		})
+	}
+	return warnings
+}
+
+func checkStorage(meta *charm.Meta) []params.CharmCompatibilityWarning {
+	var names []string
+	for name := range meta.Storage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var warnings []params.CharmCompatibilityWarning
+	for _, name := range names {
+		store := meta.Storage[name]
+		switch store.Type {
+		case charm.StorageBlock, charm.StorageFilesystem:
+		default:
+			warnings = append(warnings, params.CharmCompatibilityWarning{
+				Kind:    "storage",
+				Name:    name,
+				Message: fmt.Sprintf("storage type %q is not supported by this controller", store.Type),
+			})
+			continue
+		}
+		if store.Shared {
+			warnings = append(warnings, params.CharmCompatibilityWarning{
+				Kind:    "storage",
+				Name:    name,
+				Message: "shared storage is not supported by this controller",
+			})
+		}
+	}
+	return warnings
+}
+
+// unitHooks holds the kinds of hook run by unit agents for every unit.
+var unitHooks = []hooks.Kind{
+	hooks.Install,
+	hooks.Start,
+	hooks.ConfigChanged,
+	hooks.UpgradeCharm,
+	hooks.Stop,
+	hooks.CollectMetrics,
+	hooks.MeterStatusChanged,
+	hooks.UpdateStatus,
+	hook.LeaderElected,
+	hook.LeaderDeposed,
+	hook.LeaderSettingsChanged,
+	hook.PreSeriesUpgrade,
+	hook.PostSeriesUpgrade,
+}
+
+// checkHooks warns about the files in the hooks directory of a charm
+// that unit agents will never run, because they are not hooks of a
+// known kind or belong to relations or stores the charm does not
+// declare.
+func checkHooks(meta *charm.Meta, hookNames []string) []params.CharmCompatibilityWarning {
+	known := set.NewStrings()
+	for _, kind := range unitHooks {
+		known.Add(string(kind))
+	}
+	for _, relations := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		for name := range relations {
+			for _, kind := range []hooks.Kind{hooks.RelationJoined, hooks.RelationChanged, hooks.RelationDeparted, hooks.RelationBroken} {
+				known.Add(name + "-" + string(kind))
+			}
+		}
+	}
+	for name := range meta.Storage {
+		for _, kind := range []hooks.Kind{hooks.StorageAttached, hooks.StorageDetaching} {
+			known.Add(name + "-" + string(kind))
+		}
+	}
+	var warnings []params.CharmCompatibilityWarning
+	for _, name := range hookNames {
+		if known.Contains(name) {
+			continue
+		}
+		warnings = append(warnings, params.CharmCompatibilityWarning{
+			Kind:    "hook",
+			Name:    name,
+			Message: fmt.Sprintf("%q is not a hook known to this controller and will never be run", name),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmvalidation_test
+
+import (
+	"archive/zip"
+	"bytes"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/charmvalidation"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type charmValidationSuite struct {
+	jujutesting.JujuConnSuite
+	api *charmvalidation.CharmValidationAPI
+}
+
+var _ = gc.Suite(&charmValidationSuite{})
+
+func (s *charmValidationSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api, err := charmvalidation.NewCharmValidationAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+const compatibleMetadata = `
+name: mysql
+summary: a database
+description: a database
+provides:
+  db:
+    interface: mysql
+peers:
+  cluster:
+    interface: mysql-ha
+storage:
+  data:
+    type: filesystem
+`
+
+const incompatibleMetadata = `
+name: mysql
+summary: a database
+description: a database
+provides:
+  db:
+    interface: mysql
+terms: [licence/1]
+resources:
+  software:
+    type: file
+    filename: mysql.tgz
+  image:
+    type: docker
+storage:
+  shared-data:
+    type: filesystem
+    shared: true
+`
+
+// makeArchive returns a charm archive holding the given metadata and
+// an empty file for each of the given hooks.
+func makeArchive(c *gc.C, metadata string, hooks ...string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	files := map[string]string{"metadata.yaml": metadata, "revision": "1"}
+	for _, hook := range hooks {
+		files["hooks/"+hook] = "#!/bin/sh\n"
+	}
+	for name, content := range files {
+		f, err := w.Create(name)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = f.Write([]byte(content))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *charmValidationSuite) TestAgentRejected(c *gc.C) {
+	_, err := charmvalidation.NewCharmValidationAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *charmValidationSuite) TestCompatible(c *gc.C) {
+	archive := makeArchive(c, compatibleMetadata,
+		"install", "start", "leader-elected", "update-status", "pre-series-upgrade",
+		"db-relation-joined", "cluster-relation-changed", "data-storage-attached",
+	)
+	result, err := s.api.ValidateCharm(params.ValidateCharmArgs{Archive: archive})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ValidateCharmResult{
+		Series: coretesting.FakeDefaultSeries,
+	})
+}
+
+func (s *charmValidationSuite) TestIncompatible(c *gc.C) {
+	archive := makeArchive(c, incompatibleMetadata,
+		"install", "common.sh", "website-relation-joined", "db-relation-joined", "shared-data-storage-attached",
+	)
+	result, err := s.api.ValidateCharm(params.ValidateCharmArgs{
+		Archive: archive,
+		Series:  "nosuchseries",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ValidateCharmResult{
+		Series: "nosuchseries",
+		Warnings: []params.CharmCompatibilityWarning{{
+			Kind:    "series",
+			Name:    "nosuchseries",
+			Message: `series "nosuchseries" is not supported by this controller`,
+		}, {
+			Kind:    "resource",
+			Name:    "image",
+			Message: `resources of type "docker" are not supported by this controller`,
+		}, {
+			Kind:    "resource",
+			Name:    "software",
+			Message: `resources of type "file" are not supported by this controller`,
+		}, {
+			Kind:    "metadata",
+			Name:    "terms",
+			Message: `metadata field "terms" is not supported by this controller and will be ignored`,
+		}, {
+			Kind:    "storage",
+			Name:    "shared-data",
+			Message: "shared storage is not supported by this controller",
+		}, {
+			Kind:    "hook",
+			Name:    "common.sh",
+			Message: `"common.sh" is not a hook known to this controller and will never be run`,
+		}, {
+			Kind:    "hook",
+			Name:    "website-relation-joined",
+			Message: `"website-relation-joined" is not a hook known to this controller and will never be run`,
+		}},
+	})
+}
+
+func (s *charmValidationSuite) TestInvalidArchive(c *gc.C) {
+	_, err := s.api.ValidateCharm(params.ValidateCharmArgs{Archive: []byte("not a zip")})
+	c.Assert(err, gc.ErrorMatches, "invalid charm archive: .*")
+
+	archive := makeArchive(c, "summary: a database\n")
+	_, err = s.api.ValidateCharm(params.ValidateCharmArgs{Archive: archive})
+	c.Assert(err, gc.ErrorMatches, "invalid charm archive: .*")
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+// instanceZoner is implemented by environs whose instances are
+// placed in availability zones.
+type instanceZoner interface {
+	InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, error)
+}
+
+// ProviderInstance returns what the environment's provider knows of
+// the instance with the given id, so that an existing instance can be
+// adopted as a machine.
+func (c *Client) ProviderInstance(args params.ProviderInstanceArgs) (params.ProviderInstanceResult, error) {
+	if args.InstanceId == "" {
+		return params.ProviderInstanceResult{}, errors.New("no instance id specified")
+	}
+	machineId, err := instanceMachineId(c.api.state, args.InstanceId)
+	if err != nil {
+		return params.ProviderInstanceResult{}, errors.Trace(err)
+	}
+	envcfg, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return params.ProviderInstanceResult{}, errors.Trace(err)
+	}
+	env, err := environs.New(envcfg)
+	if err != nil {
+		return params.ProviderInstanceResult{}, errors.Trace(err)
+	}
+	ids := []instance.Id{args.InstanceId}
+	insts, err := env.Instances(ids)
+	if err == environs.ErrNoInstances {
+		return params.ProviderInstanceResult{}, errors.NotFoundf("instance %q", args.InstanceId)
+	} else if err != nil {
+		return params.ProviderInstanceResult{}, errors.Annotatef(err, "cannot get instance %q", args.InstanceId)
+	}
+	addrs, err := insts[0].Addresses()
+	if err != nil {
+		return params.ProviderInstanceResult{}, errors.Annotatef(err, "cannot get addresses of instance %q", args.InstanceId)
+	}
+	result := params.ProviderInstanceResult{
+		InstanceId: args.InstanceId,
+		Status:     insts[0].Status(),
+		Addresses:  params.FromNetworkAddresses(addrs),
+		MachineId:  machineId,
+	}
+	if zoner, ok := env.(instanceZoner); ok {
+		// Not every instance reports a zone, so failing to find
+		// one is not an error.
+		if zones, err := zoner.InstanceAvailabilityZoneNames(ids); err == nil {
+			result.AvailabilityZone = zones[0]
+		} else {
+			logger.Debugf("cannot get availability zone of instance %q: %v", args.InstanceId, err)
+		}
+	}
+	return result, nil
+}
+
+// instanceMachineId returns the id of the machine associated with the
+// instance with the given id, or "" if there is none.
+func instanceMachineId(st *state.State, id instance.Id) (string, error) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, m := range machines {
+		machineInstanceId, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return "", errors.Trace(err)
+		}
+		if machineInstanceId == id {
+			return m.Id(), nil
+		}
+	}
+	return "", nil
+}
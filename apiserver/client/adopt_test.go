@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/state"
+)
+
+type adoptSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&adoptSuite{})
+
+func (s *adoptSuite) TestProviderInstance(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.Environ, "99")
+	addrs := network.NewAddresses("10.0.0.1", "example.com")
+	dummy.SetInstanceAddresses(inst, addrs)
+
+	result, err := s.APIState.Client().ProviderInstance(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, &params.ProviderInstanceResult{
+		InstanceId: inst.Id(),
+		Status:     inst.Status(),
+		Addresses:  params.FromNetworkAddresses(addrs),
+	})
+}
+
+func (s *adoptSuite) TestProviderInstanceOfMachine(c *gc.C) {
+	inst, hc := testing.AssertStartInstance(c, s.Environ, "99")
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetProvisioned(inst.Id(), "fake_nonce", hc)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.APIState.Client().ProviderInstance(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.MachineId, gc.Equals, m.Id())
+}
+
+func (s *adoptSuite) TestProviderInstanceNotFound(c *gc.C) {
+	_, err := s.APIState.Client().ProviderInstance(instance.Id("i-missing"))
+	c.Assert(err, gc.ErrorMatches, `instance "i-missing" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *adoptSuite) TestProviderInstanceNoId(c *gc.C) {
+	_, err := s.APIState.Client().ProviderInstance("")
+	c.Assert(err, gc.ErrorMatches, "no instance id specified")
+}
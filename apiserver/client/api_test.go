@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/api"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -230,6 +231,13 @@ var scenarioStatus = &api.Status{
 				Info:   "blam",
 				Data:   map[string]interface{}{"remote-unit": "logging/0", "foo": "bar", "relation-id": "0"},
 			},
+			Summary: api.ServiceSummary{
+				UnitCount:      2,
+				MachineCount:   2,
+				WorkloadCounts: map[params.Status]int{"error": 1, "unknown": 1},
+				AgentCounts:    map[params.Status]int{"idle": 1, "allocating": 1},
+				WorstStatus:    "error",
+			},
 			Units: map[string]api.UnitStatus{
 				"wordpress/0": {
 					Workload: api.AgentStatus{
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// The bulk operations below act on many entities in one call and
+// report an error for each entity, rather than stopping at the first,
+// so that clients acting on hundreds of units need only one round
+// trip.
+
+// ResolveUnits marks the errors of the given units as resolved.
+func (c *Client) ResolveUnits(args params.ResolveUnits) (params.ErrorResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Units)),
+	}
+	for i, arg := range args.Units {
+		unit, err := c.unitFromTag(arg.UnitTag)
+		if err == nil {
+			err = unit.Resolve(arg.Retry)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// RemoveUnits destroys the given principal units. Units that are
+// already dying or dead are left as they are.
+func (c *Client) RemoveUnits(args params.Entities) (params.ErrorResults, error) {
+	if err := c.check.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		unit, err := c.unitFromTag(entity.Tag)
+		switch {
+		case err != nil:
+		case unit.Life() != state.Alive:
+		case unit.IsPrincipal():
+			err = unit.Destroy()
+		default:
+			err = errors.Errorf("unit %q is a subordinate", unit.Name())
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetConstraintsBulk sets the constraints of the given services, or of
+// the environment for entries with no service name.
+func (c *Client) SetConstraintsBulk(args params.SetConstraintsBulk) (params.ErrorResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Constraints)),
+	}
+	for i, arg := range args.Constraints {
+		var err error
+		if arg.ServiceName == "" {
+			err = c.api.state.SetEnvironConstraints(arg.Constraints)
+		} else {
+			var svc *state.Service
+			if svc, err = c.api.state.Service(arg.ServiceName); err == nil {
+				err = svc.SetConstraints(arg.Constraints)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// unitFromTag returns the unit with the given tag.
+func (c *Client) unitFromTag(tag string) (*state.Unit, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.api.state.Unit(unitTag.Id())
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type bulkSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&bulkSuite{})
+
+func (s *bulkSuite) TestResolveUnits(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().ResolveUnits(true,
+		names.NewUnitTag("wordpress/0"),
+		names.NewUnitTag("wordpress/1"),
+		names.NewUnitTag("wordpress/9"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "wordpress/1" is not in an error state`)
+	c.Assert(results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Resolved(), gc.Equals, state.ResolvedRetryHooks)
+}
+
+func (s *bulkSuite) TestRemoveUnits(c *gc.C) {
+	s.setUpScenario(c)
+	results, err := s.APIState.Client().RemoveUnits(
+		names.NewUnitTag("wordpress/0"),
+		names.NewUnitTag("logging/0"),
+		names.NewUnitTag("wordpress/9"),
+		names.NewUnitTag("wordpress/0"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 4)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "logging/0" is a subordinate`)
+	c.Assert(results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+	// Removing a unit that is already dying is not an error.
+	c.Assert(results[3].Error, gc.IsNil)
+
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.Life(), gc.Equals, state.Dying)
+}
+
+func (s *bulkSuite) TestRemoveUnitsBlocked(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockRemoveObject(c, "TestRemoveUnitsBlocked")
+	_, err := s.APIState.Client().RemoveUnits(names.NewUnitTag("wordpress/0"))
+	s.AssertBlocked(c, err, "TestRemoveUnitsBlocked")
+}
+
+func (s *bulkSuite) TestSetConstraintsBulk(c *gc.C) {
+	s.setUpScenario(c)
+	mem := constraints.MustParse("mem=4G")
+	cores := constraints.MustParse("cpu-cores=2")
+	results, err := s.APIState.Client().SetConstraintsBulk([]params.SetConstraints{
+		{ServiceName: "wordpress", Constraints: mem},
+		{ServiceName: "nosuch", Constraints: mem},
+		{Constraints: cores},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results[2].Error, gc.IsNil)
+
+	svc, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	obtained, err := svc.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, gc.DeepEquals, mem)
+	obtained, err = s.State.EnvironConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained, gc.DeepEquals, cores)
+}
+
+func (s *bulkSuite) TestSetConstraintsBulkBlocked(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockAllChanges(c, "TestSetConstraintsBulkBlocked")
+	_, err := s.APIState.Client().SetConstraintsBulk([]params.SetConstraints{{ServiceName: "wordpress"}})
+	s.AssertBlocked(c, err, "TestSetConstraintsBulkBlocked")
+}
@@ -23,6 +23,7 @@ import (
 	jjj "github.com/juju/juju/juju"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/upgrades"
 	"github.com/juju/juju/version"
 )
 
@@ -85,7 +86,7 @@ func (c *Client) ServiceSet(p params.ServiceSet) error {
 	if err != nil {
 		return err
 	}
-	return service.ServiceSetSettingsStrings(svc, p.Options)
+	return service.ServiceSetSettingsStrings(svc, p.Options, c.api.auth.GetAuthTag())
 }
 
 // NewServiceSetForClientAPI implements the server side of
@@ -101,7 +102,7 @@ func (c *Client) NewServiceSetForClientAPI(p params.ServiceSet) error {
 	if err != nil {
 		return err
 	}
-	return newServiceSetSettingsStringsForClientAPI(svc, p.Options)
+	return newServiceSetSettingsStringsForClientAPI(svc, p.Options, c.api.auth.GetAuthTag())
 }
 
 // ServiceUnset implements the server side of Client.ServiceUnset.
@@ -118,7 +119,7 @@ func (c *Client) ServiceUnset(p params.ServiceUnset) error {
 	for _, option := range p.Options {
 		settings[option] = nil
 	}
-	return svc.UpdateConfigSettings(settings)
+	return svc.UpdateConfigSettingsBy(settings, c.api.auth.GetAuthTag())
 }
 
 // ServiceSetYAML implements the server side of Client.ServerSetYAML.
@@ -131,7 +132,20 @@ func (c *Client) ServiceSetYAML(p params.ServiceSetYAML) error {
 	if err != nil {
 		return err
 	}
-	return serviceSetSettingsYAML(svc, p.Config)
+	return serviceSetSettingsYAML(svc, p.Config, c.api.auth.GetAuthTag())
+}
+
+// ServiceRollbackConfig restores the configuration settings of a
+// service to those recorded at the given revision.
+func (c *Client) ServiceRollbackConfig(args params.ServiceConfigRevisionArgs) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	svc, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return svc.RollbackConfigSettings(args.Revision, c.api.auth.GetAuthTag())
 }
 
 // ServiceCharmRelations implements the server side of Client.ServiceCharmRelations.
@@ -193,6 +207,33 @@ func (c *Client) PublicAddress(p params.PublicAddress) (results params.PublicAdd
 	return results, fmt.Errorf("unknown unit or machine %q", p.Target)
 }
 
+// SSHHostKeys implements the server side of Client.SSHHostKeys.
+func (c *Client) SSHHostKeys(p params.SSHHostKeysTarget) (results params.SSHHostKeysResults, err error) {
+	var machineId string
+	switch {
+	case names.IsValidMachine(p.Target):
+		machineId = p.Target
+
+	case names.IsValidUnit(p.Target):
+		unit, err := c.api.state.Unit(p.Target)
+		if err != nil {
+			return results, err
+		}
+		machineId, err = unit.AssignedMachineId()
+		if err != nil {
+			return results, err
+		}
+
+	default:
+		return results, fmt.Errorf("unknown unit or machine %q", p.Target)
+	}
+	keys, err := c.api.state.GetSSHHostKeys(names.NewMachineTag(machineId))
+	if err != nil {
+		return results, err
+	}
+	return params.SSHHostKeysResults{PublicKeys: keys}, nil
+}
+
 // PrivateAddress implements the server side of Client.PrivateAddress.
 func (c *Client) PrivateAddress(p params.PrivateAddress) (results params.PrivateAddressResults, err error) {
 	switch {
@@ -295,11 +336,11 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	}
 	// Set up service's settings.
 	if args.SettingsYAML != "" {
-		if err = serviceSetSettingsYAML(svc, args.SettingsYAML); err != nil {
+		if err = serviceSetSettingsYAML(svc, args.SettingsYAML, c.api.auth.GetAuthTag()); err != nil {
 			return err
 		}
 	} else if len(args.SettingsStrings) > 0 {
-		if err = service.ServiceSetSettingsStrings(svc, args.SettingsStrings); err != nil {
+		if err = service.ServiceSetSettingsStrings(svc, args.SettingsStrings, c.api.auth.GetAuthTag()); err != nil {
 			return err
 		}
 	}
@@ -353,7 +394,7 @@ func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL,
 
 // serviceSetSettingsYAML updates the settings for the given service,
 // taking the configuration from a YAML string.
-func serviceSetSettingsYAML(service *state.Service, settings string) error {
+func serviceSetSettingsYAML(service *state.Service, settings string, changedBy names.Tag) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -362,7 +403,7 @@ func serviceSetSettingsYAML(service *state.Service, settings string) error {
 	if err != nil {
 		return err
 	}
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsBy(changes, changedBy)
 }
 
 // newServiceSetSettingsStringsForClientAPI updates the settings for the given
@@ -370,7 +411,7 @@ func serviceSetSettingsYAML(service *state.Service, settings string) error {
 //
 // TODO(Nate): replace serviceSetSettingsStrings with this onces the GUI no
 // longer expects to be able to unset values by sending an empty string.
-func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string) error {
+func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings map[string]string, changedBy names.Tag) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -382,7 +423,7 @@ func newServiceSetSettingsStringsForClientAPI(service *state.Service, settings m
 		return err
 	}
 
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsBy(changes, changedBy)
 }
 
 // ServiceSetCharm sets the charm for a given service.
@@ -453,10 +494,12 @@ func (c *Client) DestroyServiceUnits(args params.DestroyServiceUnits) error {
 		case err != nil:
 		case unit.Life() != state.Alive:
 			continue
-		case unit.IsPrincipal():
-			err = unit.Destroy()
-		default:
+		case !unit.IsPrincipal():
 			err = fmt.Errorf("unit %q is a subordinate", name)
+		case args.RemoveEmptyMachines:
+			err = unit.DestroyRemovingEmptyMachines()
+		default:
+			err = unit.Destroy()
 		}
 		if err != nil {
 			errs = append(errs, err.Error())
@@ -498,6 +541,52 @@ func (c *Client) GetEnvironmentConstraints() (params.GetConstraintsResults, erro
 	return params.GetConstraintsResults{cons}, nil
 }
 
+// UnitConstraints returns, for each given unit, the constraints
+// recorded when it was added and the constraints that would be used
+// to provision a new machine for it now, with the source of each
+// effective attribute.
+func (c *Client) UnitConstraints(args params.Entities) (params.UnitConstraintsResults, error) {
+	results := params.UnitConstraintsResults{
+		Results: make([]params.UnitConstraintsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		result, err := c.unitConstraints(entity.Tag)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (c *Client) unitConstraints(tag string) (params.UnitConstraintsResult, error) {
+	unitTag, err := names.ParseUnitTag(tag)
+	if err != nil {
+		return params.UnitConstraintsResult{}, errors.Trace(err)
+	}
+	unit, err := c.api.state.Unit(unitTag.Id())
+	if err != nil {
+		return params.UnitConstraintsResult{}, errors.Trace(err)
+	}
+	effective, err := unit.EffectiveConstraints()
+	if err != nil {
+		return params.UnitConstraintsResult{}, errors.Trace(err)
+	}
+	cons, err := unit.Constraints()
+	if err != nil {
+		return params.UnitConstraintsResult{}, errors.Trace(err)
+	}
+	sources := make(map[string]string)
+	for attr, source := range effective.Sources {
+		sources[attr] = string(source)
+	}
+	return params.UnitConstraintsResult{
+		Constraints: *cons,
+		Effective:   effective.Value,
+		Sources:     sources,
+	}, nil
+}
+
 // SetServiceConstraints sets the constraints for a given service.
 // TODO(mattyw, all): This api call should be move to the new service facade. The client api version will then need bumping.
 func (c *Client) SetServiceConstraints(args params.SetConstraints) error {
@@ -520,11 +609,25 @@ func (c *Client) SetEnvironmentConstraints(args params.SetConstraints) error {
 }
 
 // AddRelation adds a relation between the specified endpoints and returns the relation info.
+// A call retried with the same idempotency token returns the relation
+// added by the first call rather than failing.
 func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.AddRelationResults{}, errors.Trace(err)
 	}
-	inEps, err := c.api.state.InferEndpoints(args.Endpoints...)
+	var result params.AddRelationResults
+	err := common.RunIdempotent(c.api.state, args.IdempotencyToken, "AddRelation", &result, func() (err error) {
+		result, err = c.addRelation(args.Endpoints)
+		return err
+	})
+	if err != nil {
+		return params.AddRelationResults{}, err
+	}
+	return result, nil
+}
+
+func (c *Client) addRelation(endpoints []string) (params.AddRelationResults, error) {
+	inEps, err := c.api.state.InferEndpoints(endpoints...)
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
@@ -564,7 +667,9 @@ func (c *Client) AddMachines(args params.AddMachines) (params.AddMachinesResults
 	return c.AddMachinesV2(args)
 }
 
-// AddMachinesV2 adds new machines with the supplied parameters.
+// AddMachinesV2 adds new machines with the supplied parameters. A call
+// retried with the same idempotency token returns the results of the
+// first call rather than adding the machines again.
 func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResults, error) {
 	results := params.AddMachinesResults{
 		Machines: make([]params.AddMachinesResult, len(args.MachineParams)),
@@ -572,14 +677,17 @@ func (c *Client) AddMachinesV2(args params.AddMachines) (params.AddMachinesResul
 	if err := c.check.ChangeAllowed(); err != nil {
 		return results, errors.Trace(err)
 	}
-	for i, p := range args.MachineParams {
-		m, err := c.addOneMachine(p)
-		results.Machines[i].Error = common.ServerError(err)
-		if err == nil {
-			results.Machines[i].Machine = m.Id()
+	err := common.RunIdempotent(c.api.state, args.IdempotencyToken, "AddMachines", &results, func() error {
+		for i, p := range args.MachineParams {
+			m, err := c.addOneMachine(p)
+			results.Machines[i].Error = common.ServerError(err)
+			if err == nil {
+				results.Machines[i].Machine = m.Id()
+			}
 		}
-	}
-	return results, nil
+		return nil
+	})
+	return results, err
 }
 
 // InjectMachines injects a machine into state with provisioned status.
@@ -654,6 +762,11 @@ func (c *Client) addOneMachine(p params.AddMachineParams) (*state.Machine, error
 		return c.api.state.AddOneMachine(template)
 	}
 	if p.ParentId != "" {
+		// The parent may be a new container to be created on an
+		// existing machine, eg "kvm:1".
+		if hostType, hostId, err := instance.ParseNestedContainer(p.ContainerType, p.ParentId); err == nil {
+			return c.api.state.AddMachineInsideNewContainer(template, template, hostId, hostType, p.ContainerType)
+		}
 		return c.api.state.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
 	}
 	return c.api.state.AddMachineInsideNewMachine(template, template, p.ContainerType)
@@ -801,6 +914,33 @@ func (c *Client) ShareEnvironment(args params.ModifyEnvironUsers) (result params
 	return result, nil
 }
 
+// InviteEnvironmentUsers invites the given users to access the
+// environment. Users are only given access once they accept their
+// invitations through the EnvironmentManager facade.
+func (c *Client) InviteEnvironmentUsers(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	createdBy, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return result, errors.Errorf("api connection is not through a user")
+	}
+	if err := c.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		user, err := names.ParseUserTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if _, err := c.api.state.InviteEnvironmentUser(user, createdBy); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
 // EnvUserInfo returns information on all users in the environment.
 func (c *Client) EnvUserInfo() (params.EnvUserInfoResults, error) {
 	var results params.EnvUserInfoResults
@@ -895,11 +1035,23 @@ func (c *Client) AgentVersion() (params.AgentVersionResult, error) {
 func (c *Client) EnvironmentGet() (params.EnvironmentConfigResults, error) {
 	result := params.EnvironmentConfigResults{}
 	// Get the existing environment config from the state.
-	config, err := c.api.state.EnvironConfig()
+	cfg, err := c.api.state.EnvironConfig()
 	if err != nil {
 		return result, err
 	}
-	result.Config = config.AllAttrs()
+	attrs := cfg.AllAttrs()
+	isAdmin, err := common.IsEnvironAdmin(c.api.state, c.api.auth)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	if !isAdmin {
+		// Secret attributes are left out rather than replaced by a
+		// placeholder, so that the config remains valid for clients.
+		for _, name := range config.SchemaFor(cfg.Type()).SecretAttrs() {
+			delete(attrs, name)
+		}
+	}
+	result.Config = attrs
 	return result, nil
 }
 
@@ -939,14 +1091,93 @@ func (c *Client) EnvironmentUnset(args params.EnvironmentUnset) error {
 	return c.api.state.UpdateEnvironConfig(nil, args.Keys, nil)
 }
 
-// SetEnvironAgentVersion sets the environment agent version.
+// SetEnvironAgentVersion sets the environment agent version. The
+// version may be lowered within a patch series, to recover from a
+// bad patch release, as long as no upgrade steps have changed the
+// database since.
 func (c *Client) SetEnvironAgentVersion(args params.SetEnvironAgentVersion) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
+	envConfig, err := c.api.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if current, ok := envConfig.AgentVersion(); ok && args.Version.Compare(current) < 0 {
+		if err := upgrades.CheckDowngrade(current, args.Version); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return c.api.state.SetEnvironAgentVersion(args.Version)
 }
 
+// StartAgentRollout starts a staged upgrade of the environment's
+// agents, which upgrades the canary machines before the rest of
+// the environment.
+func (c *Client) StartAgentRollout(args params.StartAgentRollout) (params.AgentRolloutStatus, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.AgentRolloutStatus{}, errors.Trace(err)
+	}
+	rollout, err := c.api.state.StartAgentRollout(args.Version, args.Canaries, args.BakePeriod)
+	if err != nil {
+		return params.AgentRolloutStatus{}, errors.Trace(err)
+	}
+	return agentRolloutStatus(rollout), nil
+}
+
+// AgentRolloutStatus returns the status of the environment's most
+// recent staged upgrade.
+func (c *Client) AgentRolloutStatus() (params.AgentRolloutStatus, error) {
+	rollout, err := c.api.state.AgentRollout()
+	if err != nil {
+		return params.AgentRolloutStatus{}, errors.Trace(err)
+	}
+	return agentRolloutStatus(rollout), nil
+}
+
+// PromoteAgentRollout completes the current staged upgrade without
+// waiting for the bake period to pass, upgrading the rest of the
+// environment's agents.
+func (c *Client) PromoteAgentRollout() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	rollout, err := c.api.state.AgentRollout()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return rollout.Promote()
+}
+
+// RollBackAgentRollout abandons the current staged upgrade, so that
+// the canaries return to the environment's agent version.
+func (c *Client) RollBackAgentRollout() error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	rollout, err := c.api.state.AgentRollout()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return rollout.RollBack("rolled back by user")
+}
+
+func agentRolloutStatus(rollout *state.AgentRollout) params.AgentRolloutStatus {
+	status := params.AgentRolloutStatus{
+		PreviousVersion: rollout.PreviousVersion(),
+		TargetVersion:   rollout.TargetVersion(),
+		Canaries:        rollout.Canaries(),
+		BakePeriod:      rollout.BakePeriod(),
+		Phase:           string(rollout.Phase()),
+		Started:         rollout.Started(),
+		Message:         rollout.Message(),
+	}
+	if bakeStarted := rollout.BakeStarted(); !bakeStarted.IsZero() {
+		status.BakeStarted = &bakeStarted
+	}
+	return status
+}
+
 // AbortCurrentUpgrade aborts and archives the current upgrade
 // synchronisation record, if any.
 func (c *Client) AbortCurrentUpgrade() error {
@@ -956,6 +1187,28 @@ func (c *Client) AbortCurrentUpgrade() error {
 	return c.api.state.AbortCurrentUpgrade()
 }
 
+// UpgradeInfo returns details of the upgrade that the state servers
+// are currently coordinating.
+func (c *Client) UpgradeInfo() (params.UpgradeInfo, error) {
+	info, err := c.api.state.CurrentUpgradeInfo()
+	if err != nil {
+		return params.UpgradeInfo{}, errors.Trace(err)
+	}
+	pending, err := info.StateServersPending()
+	if err != nil {
+		return params.UpgradeInfo{}, errors.Trace(err)
+	}
+	return params.UpgradeInfo{
+		PreviousVersion:     info.PreviousVersion(),
+		TargetVersion:       info.TargetVersion(),
+		Status:              string(info.Status()),
+		Started:             info.Started(),
+		StateServersReady:   info.StateServersReady(),
+		StateServersPending: pending,
+		StateServersDone:    info.StateServersDone(),
+	}, nil
+}
+
 // FindTools returns a List containing all tools matching the given parameters.
 func (c *Client) FindTools(args params.FindToolsParams) (params.FindToolsResult, error) {
 	return c.api.toolsFinder.FindTools(args)
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -35,6 +36,7 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
+	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
@@ -276,6 +278,28 @@ func (s *serverSuite) TestShareEnvironmentAddLocalUser(c *gc.C) {
 	c.Assert(envUser.LastConnection(), gc.IsNil)
 }
 
+func (s *serverSuite) TestInviteEnvironmentUsers(c *gc.C) {
+	shared := s.Factory.MakeEnvUser(c, nil)
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: names.NewLocalUserTag("foobar").String()},
+		{Tag: shared.UserTag().String()},
+		{Tag: "machine-0"},
+	}}
+	result, err := s.client.InviteEnvironmentUsers(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot invite user ".*": environment user ".*" already exists`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid user tag`)
+
+	invitation, err := s.State.EnvironmentInvitation(names.NewLocalUserTag("foobar"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitation.CreatedBy(), gc.Equals, dummy.AdminUserTag().Username())
+	// An invitation grants no access by itself.
+	_, err = s.State.EnvironmentUser(names.NewLocalUserTag("foobar"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *serverSuite) TestShareEnvironmentAddRemoteUser(c *gc.C) {
 	user := names.NewUserTag("foobar@ubuntuone")
 	args := params.ModifyEnvironUsers{
@@ -433,6 +457,32 @@ func (s *serverSuite) TestSetEnvironAgentVersion(c *gc.C) {
 	c.Assert(agentVersion, gc.Equals, "9.8.7")
 }
 
+func (s *serverSuite) setEnvironAgentVersion(c *gc.C, vers string) {
+	err := statetesting.SetAgentVersion(s.State, version.MustParse(vers))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *serverSuite) TestSetEnvironAgentVersionDowngrade(c *gc.C) {
+	// There are no state upgrade steps for 1.25.2 or 1.25.3.
+	s.setEnvironAgentVersion(c, "1.25.3")
+	err := s.client.SetEnvironAgentVersion(params.SetEnvironAgentVersion{
+		Version: version.MustParse("1.25.1"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, _ := envConfig.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, version.MustParse("1.25.1"))
+}
+
+func (s *serverSuite) TestSetEnvironAgentVersionDowngradeAcrossMinor(c *gc.C) {
+	s.setEnvironAgentVersion(c, "1.25.3")
+	err := s.client.SetEnvironAgentVersion(params.SetEnvironAgentVersion{
+		Version: version.MustParse("1.24.7"),
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot downgrade from 1.25.3 to 1.24.7: only downgrades within a patch series are supported")
+}
+
 func (s *serverSuite) assertSetEnvironAgentVersion(c *gc.C) {
 	args := params.SetEnvironAgentVersion{
 		Version: version.MustParse("9.8.7"),
@@ -469,6 +519,82 @@ func (s *serverSuite) TestBlockChangesSetEnvironAgentVersion(c *gc.C) {
 	s.assertSetEnvironAgentVersionBlocked(c, "TestBlockChangesSetEnvironAgentVersion")
 }
 
+func (s *serverSuite) addAgentRolloutCanary(c *gc.C) (*state.Machine, version.Number) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAgentVersion(version.Current)
+	c.Assert(err, jc.ErrorIsNil)
+	target := version.Current.Number
+	target.Patch++
+	return machine, target
+}
+
+func (s *serverSuite) TestStartAgentRollout(c *gc.C) {
+	machine, target := s.addAgentRolloutCanary(c)
+	status, err := s.client.StartAgentRollout(params.StartAgentRollout{
+		Version:    target,
+		Canaries:   []string{machine.Id()},
+		BakePeriod: time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.PreviousVersion, gc.Equals, version.Current.Number)
+	c.Assert(status.TargetVersion, gc.Equals, target)
+	c.Assert(status.Canaries, jc.DeepEquals, []string{machine.Id()})
+	c.Assert(status.BakePeriod, gc.Equals, time.Hour)
+	c.Assert(status.Phase, gc.Equals, "canary")
+	c.Assert(status.BakeStarted, gc.IsNil)
+
+	status, err = s.client.AgentRolloutStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.TargetVersion, gc.Equals, target)
+	c.Assert(status.Phase, gc.Equals, "canary")
+}
+
+func (s *serverSuite) TestAgentRolloutStatusNotFound(c *gc.C) {
+	_, err := s.client.AgentRolloutStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *serverSuite) TestPromoteAgentRollout(c *gc.C) {
+	machine, target := s.addAgentRolloutCanary(c)
+	_, err := s.State.StartAgentRollout(target, []string{machine.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.PromoteAgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err := s.State.AgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutPromoted)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, _ := envConfig.AgentVersion()
+	c.Assert(agentVersion, gc.Equals, target)
+}
+
+func (s *serverSuite) TestRollBackAgentRollout(c *gc.C) {
+	machine, target := s.addAgentRolloutCanary(c)
+	_, err := s.State.StartAgentRollout(target, []string{machine.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.client.RollBackAgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err := s.State.AgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutRolledBack)
+	c.Assert(rollout.Message(), gc.Equals, "rolled back by user")
+}
+
+func (s *serverSuite) TestBlockChangesStartAgentRollout(c *gc.C) {
+	machine, target := s.addAgentRolloutCanary(c)
+	s.BlockAllChanges(c, "TestBlockChangesStartAgentRollout")
+	_, err := s.client.StartAgentRollout(params.StartAgentRollout{
+		Version:    target,
+		Canaries:   []string{machine.Id()},
+		BakePeriod: time.Hour,
+	})
+	s.AssertBlocked(c, err, "TestBlockChangesStartAgentRollout")
+}
+
 func (s *serverSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	// Create a provisioned state server.
 	machine, err := s.State.AddMachine("series", state.JobManageEnviron)
@@ -496,6 +622,35 @@ func (s *serverSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Assert(isUpgrading, jc.IsFalse)
 }
 
+func (s *serverSuite) TestUpgradeInfo(c *gc.C) {
+	_, err := s.client.UpgradeInfo()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	machine, err := s.State.AddMachine("series", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned(instance.Id("i-blah"), "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.State.AddMachine("series", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.SetProvisioned(instance.Id("i-other"), "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnsureUpgradeInfo(
+		machine.Id(),
+		version.MustParse("1.2.3"),
+		version.MustParse("9.8.7"),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := s.client.UpgradeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.PreviousVersion, gc.Equals, version.MustParse("1.2.3"))
+	c.Assert(info.TargetVersion, gc.Equals, version.MustParse("9.8.7"))
+	c.Assert(info.Status, gc.Equals, "pending")
+	c.Assert(info.StateServersReady, jc.DeepEquals, []string{machine.Id()})
+	c.Assert(info.StateServersPending, jc.DeepEquals, []string{other.Id()})
+	c.Assert(info.StateServersDone, gc.HasLen, 0)
+}
+
 func (s *serverSuite) assertAbortCurrentUpgradeBlocked(c *gc.C, msg string) {
 	err := s.client.AbortCurrentUpgrade()
 	s.AssertBlocked(c, err, msg)
@@ -812,6 +967,39 @@ func (s *clientSuite) TestBlockChangesServiceSetYAML(c *gc.C) {
 	s.assertServiceSetYAMLBlocked(c, dummy, "TestBlockChangesServiceSetYAML")
 }
 
+func (s *clientSuite) TestClientServiceRollbackConfig(c *gc.C) {
+	dummy := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := s.APIState.Client().ServiceSetYAML("dummy", "dummy:\n  title: foobar\n")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.APIState.Client().ServiceSetYAML("dummy", "dummy:\n  title: barfoo\n  username: user name\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().ServiceRollbackConfig("dummy", 1)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := dummy.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.DeepEquals, charm.Settings{
+		"title": "foobar",
+	})
+
+	// Each change records the user that made it.
+	err = dummy.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(dummy.ConfigRevision(), gc.Equals, 3)
+	revision, err := dummy.ConfigAtRevision(3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revision.ChangedBy, gc.Equals, s.AdminUserTag(c).String())
+}
+
+func (s *clientSuite) TestBlockChangesServiceRollbackConfig(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := s.APIState.Client().ServiceSetYAML("dummy", "dummy:\n  title: foobar\n")
+	c.Assert(err, jc.ErrorIsNil)
+	s.BlockAllChanges(c, "TestBlockChangesServiceRollbackConfig")
+	err = s.APIState.Client().ServiceRollbackConfig("dummy", 1)
+	s.AssertBlocked(c, err, "TestBlockChangesServiceRollbackConfig")
+}
+
 var clientAddServiceUnitsTests = []struct {
 	about    string
 	service  string // if not set, defaults to 'dummy'
@@ -2245,6 +2433,22 @@ func (s *clientSuite) TestSuccessfullyAddRelation(c *gc.C) {
 	s.assertAddRelation(c, endpoints)
 }
 
+func (s *clientSuite) TestAddRelationRetriedWithToken(c *gc.C) {
+	s.setUpScenario(c)
+	client := s.APIState.Client()
+	res, err := client.AddRelationWithToken("tok", "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkEndpoints(c, res.Endpoints)
+
+	// Retrying the call returns the relation rather than failing
+	// because it already exists.
+	res, err = client.AddRelationWithToken("tok", "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkEndpoints(c, res.Endpoints)
+	_, err = client.AddRelation("wordpress", "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot add relation "wordpress:db mysql:server": relation already exists`)
+}
+
 func (s *clientSuite) TestBlockDestroyAddRelation(c *gc.C) {
 	s.BlockDestroyEnvironment(c, "TestBlockDestroyAddRelation")
 	s.assertAddRelation(c, []string{"wordpress", "mysql"})
@@ -2550,6 +2754,28 @@ func (s *clientSuite) TestClientGetEnvironmentConstraints(c *gc.C) {
 	c.Assert(obtained, gc.DeepEquals, cons)
 }
 
+func (s *clientSuite) TestClientUnitConstraints(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := service.SetConstraints(constraints.MustParse("mem=4096"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironConstraints(constraints.MustParse("cpu-cores=2 mem=1024"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.APIState.Client().UnitConstraints("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Constraints, gc.DeepEquals, constraints.MustParse("mem=4096"))
+	c.Assert(result.Effective, gc.DeepEquals, constraints.MustParse("cpu-cores=2 mem=4096"))
+	c.Assert(result.Sources, gc.DeepEquals, map[string]string{
+		"cpu-cores": "environment",
+		"mem":       "service",
+	})
+
+	_, err = s.APIState.Client().UnitConstraints("dummy/1")
+	c.Assert(err, gc.ErrorMatches, `unit "dummy/1" not found`)
+}
+
 func (s *clientSuite) TestClientServiceCharmRelations(c *gc.C) {
 	s.setUpScenario(c)
 	_, err := s.APIState.Client().ServiceCharmRelations("blah")
@@ -2646,6 +2872,28 @@ func (s *clientSuite) TestClientPrivateAddressUnit(c *gc.C) {
 	c.Assert(addr, gc.Equals, "private")
 }
 
+func (s *clientSuite) TestClientSSHHostKeysErrors(c *gc.C) {
+	s.setUpScenario(c)
+	_, err := s.APIState.Client().SSHHostKeys("wordpress")
+	c.Assert(err, gc.ErrorMatches, `unknown unit or machine "wordpress"`)
+	_, err = s.APIState.Client().SSHHostKeys("0")
+	c.Assert(err, gc.ErrorMatches, `SSH host keys for machine 0 not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *clientSuite) TestClientSSHHostKeys(c *gc.C) {
+	s.setUpScenario(c)
+
+	err := s.State.SetSSHHostKeys(names.NewMachineTag("1"), state.SSHHostKeys{"rsa", "dsa"})
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err := s.APIState.Client().SSHHostKeys("1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"rsa", "dsa"})
+	keys, err = s.APIState.Client().SSHHostKeys("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"rsa", "dsa"})
+}
+
 func (s *serverSuite) TestClientEnvironmentGet(c *gc.C) {
 	envConfig, err := s.State.EnvironConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -2654,6 +2902,28 @@ func (s *serverSuite) TestClientEnvironmentGet(c *gc.C) {
 	c.Assert(result.Config, gc.DeepEquals, envConfig.AllAttrs())
 }
 
+func (s *serverSuite) TestClientEnvironmentGetOmitsSecretsForNonAdmins(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	authorizer := apiservertesting.FakeAuthorizer{Tag: user.UserTag()}
+	userClient, err := client.NewClient(s.State, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"rsyslog-ca-cert": coretesting.CACert,
+		"rsyslog-ca-key":  coretesting.CAKey,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	expected := envConfig.AllAttrs()
+	c.Assert(expected["rsyslog-ca-key"], gc.Equals, coretesting.CAKey)
+	delete(expected, "rsyslog-ca-key")
+
+	result, err := userClient.EnvironmentGet()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config, jc.DeepEquals, expected)
+}
+
 func (s *serverSuite) assertEnvValue(c *gc.C, key string, expected interface{}) {
 	envConfig, err := s.State.EnvironConfig()
 	c.Assert(err, jc.ErrorIsNil)
@@ -2820,6 +3090,32 @@ func (s *clientSuite) TestClientAddMachinesDefaultSeries(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestClientAddMachinesRetriedWithToken(c *gc.C) {
+	apiParams := []params.AddMachineParams{{
+		Jobs: []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}, {
+		Jobs: []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}}
+	client := s.APIState.Client()
+	machines, err := client.AddMachinesWithToken("tok", apiParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 2)
+
+	// Retrying the call returns the same machines without adding
+	// any more.
+	retried, err := client.AddMachinesWithToken("tok", apiParams)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retried, jc.DeepEquals, machines)
+	all, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 2)
+
+	// A different token adds more machines.
+	machines, err = client.AddMachinesWithToken("other", apiParams[:1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines[0].Machine, gc.Equals, "2")
+}
+
 func (s *clientSuite) assertAddMachines(c *gc.C) {
 	apiParams := make([]params.AddMachineParams, 3)
 	for i := 0; i < 3; i++ {
@@ -2894,6 +3190,21 @@ func (s *clientSuite) TestClientAddMachineInsideMachine(c *gc.C) {
 	c.Assert(machines[0].Machine, gc.Equals, "0/lxc/0")
 }
 
+func (s *clientSuite) TestClientAddMachineInsideNewContainer(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{{
+		Jobs:      []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Placement: instance.MustParsePlacement("lxc:kvm:0"),
+		Series:    "quantal",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+	c.Assert(machines[0].Error, gc.IsNil)
+	c.Assert(machines[0].Machine, gc.Equals, "0/kvm/0/lxc/0")
+}
+
 // updateConfig sets config variable with given key to a given value
 // Asserts that no errors were encountered.
 func (s *baseSuite) updateConfig(c *gc.C, key string, block bool) {
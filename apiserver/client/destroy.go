@@ -6,14 +6,18 @@ package client
 import (
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 )
 
 // DestroyEnvironment destroys all services and non-manager machine
-// instances in the environment.
-func (c *Client) DestroyEnvironment() (err error) {
+// instances in the environment. Persistent volumes are destroyed or
+// released as args.DestroyStorage says; if it is not set, the
+// environment is not destroyed while it has any persistent volumes.
+// Any kinds of blocker named in args.Force are ignored.
+func (c *Client) DestroyEnvironment(args params.DestroyEnvironment) (err error) {
 	if err = c.check.DestroyAllowed(); err != nil {
 		return errors.Trace(err)
 	}
@@ -23,7 +27,14 @@ func (c *Client) DestroyEnvironment() (err error) {
 		return errors.Trace(err)
 	}
 
-	if err = env.Destroy(); err != nil {
+	force := make([]state.DestroyBlockerKind, len(args.Force))
+	for i, kind := range args.Force {
+		force[i] = state.DestroyBlockerKind(kind)
+	}
+	if err = env.DestroyWithParams(state.DestroyEnvironmentParams{
+		DestroyStorage: args.DestroyStorage,
+		Force:          force,
+	}); err != nil {
 		return errors.Trace(err)
 	}
 
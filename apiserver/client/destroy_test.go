@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
@@ -75,7 +76,7 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironmentManual(c *gc.C) {
 
 	// If there are any non-manager manual machines in state, DestroyEnvironment will
 	// error. It will not set the Dying flag on the environment.
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, gc.ErrorMatches, fmt.Sprintf("failed to destroy environment: manually provisioned machines must first be destroyed with `juju destroy-machine %s`", nonManager.Id()))
 	env, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
@@ -87,7 +88,7 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironmentManual(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	err = nonManager.Remove()
 	c.Assert(err, jc.ErrorIsNil)
-	err = s.APIState.Client().DestroyEnvironment()
+	err = s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	err = env.Refresh()
 	c.Assert(err, jc.ErrorIsNil)
@@ -108,7 +109,7 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironment(c *gc.C) {
 	services, err := s.State.AllServices()
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = s.APIState.Client().DestroyEnvironment()
+	err = s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	// After DestroyEnvironment returns, we should have:
@@ -145,7 +146,7 @@ func (s *destroyEnvironmentSuite) TestDestroyEnvironmentWithContainers(c *gc.C)
 	_, nonManager, _ := s.setUpInstances(c)
 	nonManagerId, _ := nonManager.InstanceId()
 
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 	for op := range ops {
 		if op, ok := op.(dummy.OpStopInstances); ok {
@@ -159,7 +160,7 @@ func (s *destroyEnvironmentSuite) TestBlockDestroyDestroyEnvironment(c *gc.C) {
 	// Setup environment
 	s.setUpInstances(c)
 	s.BlockDestroyEnvironment(c, "TestBlockDestroyDestroyEnvironment")
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	s.AssertBlocked(c, err, "TestBlockDestroyDestroyEnvironment")
 }
 
@@ -167,7 +168,7 @@ func (s *destroyEnvironmentSuite) TestBlockRemoveDestroyEnvironment(c *gc.C) {
 	// Setup environment
 	s.setUpInstances(c)
 	s.BlockRemoveObject(c, "TestBlockRemoveDestroyEnvironment")
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	s.AssertBlocked(c, err, "TestBlockRemoveDestroyEnvironment")
 }
 
@@ -176,7 +177,7 @@ func (s *destroyEnvironmentSuite) TestBlockChangesDestroyEnvironment(c *gc.C) {
 	s.setUpInstances(c)
 	// lock environment: can't destroy locked environment
 	s.BlockAllChanges(c, "TestBlockChangesDestroyEnvironment")
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	s.AssertBlocked(c, err, "TestBlockChangesDestroyEnvironment")
 }
 
@@ -217,7 +218,7 @@ func (s *destroyTwoEnvironmentsSuite) TestCleanupEnvironDocs(c *gc.C) {
 	m := otherFactory.MakeMachine(c, nil)
 	otherFactory.MakeMachineNested(c, m.Id(), nil)
 
-	err := s.otherEnvClient.DestroyEnvironment()
+	err := s.otherEnvClient.DestroyEnvironment(params.DestroyEnvironment{})
 	c.Assert(err, jc.ErrorIsNil)
 
 	_, err = s.otherState.Environment()
@@ -229,10 +230,24 @@ func (s *destroyTwoEnvironmentsSuite) TestCleanupEnvironDocs(c *gc.C) {
 }
 
 func (s *destroyTwoEnvironmentsSuite) TestDestroyStateServerAfterNonStateServerIsDestroyed(c *gc.C) {
-	err := s.APIState.Client().DestroyEnvironment()
+	err := s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, gc.ErrorMatches, "failed to destroy environment: state server environment cannot be destroyed before all other environments are destroyed")
-	err = s.otherEnvClient.DestroyEnvironment()
+	err = s.otherEnvClient.DestroyEnvironment(params.DestroyEnvironment{})
 	c.Assert(err, jc.ErrorIsNil)
-	err = s.APIState.Client().DestroyEnvironment()
+	err = s.APIState.Client().DestroyEnvironment(nil, nil)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *destroyEnvironmentSuite) TestDestroyEnvironmentManualForced(c *gc.C) {
+	_, nonManager := s.setUpManual(c)
+
+	// Forcing past manual machines leaves them in state, but the
+	// environment becomes Dying.
+	err := s.APIState.Client().DestroyEnvironment(nil, []string{"manual-machines"})
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dying)
+	c.Assert(nonManager.Refresh(), jc.ErrorIsNil)
+	c.Assert(nonManager.Life(), gc.Equals, state.Alive)
+}
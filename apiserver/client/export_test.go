@@ -6,6 +6,7 @@ package client
 var (
 	RemoteParamsForMachine = remoteParamsForMachine
 	GetAllUnitNames        = getAllUnitNames
+	IsSecretOption         = isSecretOption
 )
 
 // Filtering exports
@@ -0,0 +1,222 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// secretOptionWords holds the words which, when found in the name of a
+// charm option, mark the option's value as secret.
+var secretOptionWords = []string{"password", "passwd", "secret", "token", "key"}
+
+// ExportBundle returns the services, relations and, optionally, the
+// machines of the environment as a bundle in YAML format, from which
+// the environment can be reproduced.
+func (c *Client) ExportBundle(args params.ExportBundleParams) (params.StringResult, error) {
+	data, err := exportBundle(c.api.state, args.IncludePlacement)
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	bytes, err := goyaml.Marshal(data)
+	if err != nil {
+		return params.StringResult{}, errors.Trace(err)
+	}
+	return params.StringResult{Result: string(bytes)}, nil
+}
+
+// exportBundle returns the bundle data describing the environment. If
+// includePlacement is true, the bundle also holds the machines of the
+// environment and the placement of units on them.
+func exportBundle(st *state.State, includePlacement bool) (*charm.BundleData, error) {
+	envcfg, err := st.EnvironConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data := &charm.BundleData{
+		Services: make(map[string]*charm.ServiceSpec),
+	}
+	if series, ok := envcfg.DefaultSeries(); ok {
+		data.Series = series
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machines := make(map[string]bool)
+	for _, svc := range services {
+		spec, err := exportService(st, svc, includePlacement)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot export service %q", svc.Name())
+		}
+		for _, to := range spec.To {
+			machines[placementMachineId(to)] = true
+		}
+		data.Services[svc.Name()] = spec
+	}
+	if includePlacement {
+		data.Machines = make(map[string]*charm.MachineSpec)
+		for id := range machines {
+			spec, err := exportMachine(st, id)
+			if err != nil {
+				return nil, errors.Annotatef(err, "cannot export machine %q", id)
+			}
+			data.Machines[id] = spec
+		}
+	}
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, rel := range relations {
+		eps := rel.Endpoints()
+		// Peer relations are established by deploying the charm.
+		if len(eps) != 2 {
+			continue
+		}
+		pair := []string{eps[0].String(), eps[1].String()}
+		sort.Strings(pair)
+		data.Relations = append(data.Relations, pair)
+	}
+	sort.Sort(relationPairs(data.Relations))
+	return data, nil
+}
+
+// exportService returns the bundle service spec describing svc.
+func exportService(st *state.State, svc *state.Service, includePlacement bool) (*charm.ServiceSpec, error) {
+	curl, _ := svc.CharmURL()
+	spec := &charm.ServiceSpec{
+		Charm: curl.String(),
+	}
+	settings, err := svc.ConfigSettings()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, value := range settings {
+		// Secret options are left out rather than replaced by a
+		// placeholder, so that the bundle remains deployable.
+		if isSecretOption(name) {
+			continue
+		}
+		if spec.Options == nil {
+			spec.Options = make(map[string]interface{})
+		}
+		spec.Options[name] = value
+	}
+	annotations, err := st.Annotations(svc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	// Subordinate services have no units or constraints of their own.
+	if !svc.IsPrincipal() {
+		return spec, nil
+	}
+	cons, err := svc.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec.Constraints = cons.String()
+	units, err := svc.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec.NumUnits = len(units)
+	if !includePlacement {
+		return spec, nil
+	}
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			// The unit is placed wherever the deployment puts it.
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		spec.To = append(spec.To, machinePlacement(machineId))
+	}
+	sort.Strings(spec.To)
+	return spec, nil
+}
+
+// exportMachine returns the bundle machine spec describing the
+// machine with the given id.
+func exportMachine(st *state.State, id string) (*charm.MachineSpec, error) {
+	m, err := st.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cons, err := m.Constraints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	annotations, err := st.Annotations(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec := &charm.MachineSpec{
+		Constraints: cons.String(),
+		Series:      m.Series(),
+	}
+	if len(annotations) > 0 {
+		spec.Annotations = annotations
+	}
+	return spec, nil
+}
+
+// machinePlacement returns the bundle placement directive for the
+// machine with the given id. Bundles place units in containers only on
+// top level machines, so units in nested containers are placed in a
+// container of the same type on the top level machine.
+func machinePlacement(machineId string) string {
+	containerType := state.ContainerTypeFromId(machineId)
+	if containerType == "" {
+		return machineId
+	}
+	return fmt.Sprintf("%s:%s", containerType, state.TopParentId(machineId))
+}
+
+// placementMachineId returns the id of the machine named by the given
+// placement directive.
+func placementMachineId(placement string) string {
+	if i := strings.Index(placement, ":"); i >= 0 {
+		return placement[i+1:]
+	}
+	return placement
+}
+
+// isSecretOption reports whether the value of the charm option with
+// the given name should be kept out of exported bundles.
+func isSecretOption(name string) bool {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return r == '-' || r == '_' || r == '.'
+	})
+	for _, word := range words {
+		for _, secret := range secretOptionWords {
+			if word == secret {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type relationPairs [][]string
+
+func (p relationPairs) Len() int      { return len(p) }
+func (p relationPairs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p relationPairs) Less(i, j int) bool {
+	return strings.Join(p[i], " ") < strings.Join(p[j], " ")
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type exportBundleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&exportBundleSuite{})
+
+func (s *exportBundleSuite) setUpBundleScenario(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := wordpress.UpdateConfigSettings(charm.Settings{"blog-title": "Hello"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = wordpress.SetConstraints(constraints.MustParse("mem=2G"))
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wu, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = wu.AssignToMachine(m)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, m.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	mu, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = mu.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	// An unassigned unit is counted, but not placed.
+	_, err = mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *exportBundleSuite) exportBundle(c *gc.C, includePlacement bool) *charm.BundleData {
+	out, err := s.APIState.Client().ExportBundle(includePlacement)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := charm.ReadBundleData(strings.NewReader(out))
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *exportBundleSuite) TestExportBundle(c *gc.C) {
+	s.setUpBundleScenario(c)
+	data := s.exportBundle(c, false)
+	c.Assert(data.Services, jc.DeepEquals, map[string]*charm.ServiceSpec{
+		"wordpress": {
+			Charm:       "local:quantal/wordpress-3",
+			NumUnits:    1,
+			Options:     map[string]interface{}{"blog-title": "Hello"},
+			Constraints: "mem=2048M",
+		},
+		"mysql": {
+			Charm:    "local:quantal/mysql-1",
+			NumUnits: 2,
+		},
+	})
+	c.Assert(data.Machines, gc.HasLen, 0)
+	c.Assert(data.Relations, jc.DeepEquals, [][]string{{"mysql:server", "wordpress:db"}})
+}
+
+func (s *exportBundleSuite) TestExportBundleWithPlacement(c *gc.C) {
+	s.setUpBundleScenario(c)
+	data := s.exportBundle(c, true)
+	c.Assert(data.Services["wordpress"].To, jc.DeepEquals, []string{"0"})
+	c.Assert(data.Services["mysql"].To, jc.DeepEquals, []string{"lxc:0"})
+	c.Assert(data.Machines, jc.DeepEquals, map[string]*charm.MachineSpec{
+		"0": {Series: "quantal"},
+	})
+}
+
+func (s *exportBundleSuite) TestExportBundleVerifies(c *gc.C) {
+	s.setUpBundleScenario(c)
+	out, err := s.APIState.Client().ExportBundle(true)
+	c.Assert(err, jc.ErrorIsNil)
+	var data charm.BundleData
+	err = goyaml.Unmarshal([]byte(out), &data)
+	c.Assert(err, jc.ErrorIsNil)
+	err = data.Verify(func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *exportBundleSuite) TestExportBundleEmpty(c *gc.C) {
+	data := s.exportBundle(c, true)
+	c.Assert(data.Services, gc.HasLen, 0)
+	c.Assert(data.Machines, gc.HasLen, 0)
+	c.Assert(data.Relations, gc.HasLen, 0)
+}
+
+func (s *exportBundleSuite) TestIsSecretOption(c *gc.C) {
+	for name, secret := range map[string]bool{
+		"blog-title":     false,
+		"password":       true,
+		"admin-password": true,
+		"API_Token":      true,
+		"ssl.key":        true,
+		"keyboard":       false,
+		"secretary":      false,
+	} {
+		c.Check(client.IsSecretOption(name), gc.Equals, secret, gc.Commentf("option %q", name))
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
 )
 
 // ServiceGet returns the configuration for a service.
@@ -33,13 +34,51 @@ func (c *Client) ServiceGet(args params.ServiceGet) (params.ServiceGetResults, e
 		}
 	}
 	return params.ServiceGetResults{
-		Service:     args.ServiceName,
-		Charm:       charm.Meta().Name,
-		Config:      configInfo,
-		Constraints: constraints,
+		Service:        args.ServiceName,
+		Charm:          charm.Meta().Name,
+		Config:         configInfo,
+		Constraints:    constraints,
+		ConfigRevision: service.ConfigRevision(),
 	}, nil
 }
 
+// ServiceGetConfigRevision returns a recorded revision of the
+// configuration settings of a service, with the changes that produced
+// it.
+func (c *Client) ServiceGetConfigRevision(args params.ServiceConfigRevisionArgs) (params.ServiceConfigRevision, error) {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.ServiceConfigRevision{}, err
+	}
+	revision, err := service.ConfigAtRevision(args.Revision)
+	if err != nil {
+		return params.ServiceConfigRevision{}, err
+	}
+	changes := make([]params.ServiceConfigChange, len(revision.Changes))
+	for i, change := range revision.Changes {
+		changes[i] = params.ServiceConfigChange{
+			Type:     configChangeTypes[change.Type],
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	return params.ServiceConfigRevision{
+		Service:   args.ServiceName,
+		Revision:  revision.Revision,
+		ChangedBy: revision.ChangedBy,
+		Changed:   revision.Changed,
+		Changes:   changes,
+		Settings:  revision.Settings,
+	}, nil
+}
+
+var configChangeTypes = map[int]string{
+	state.ItemAdded:    "added",
+	state.ItemModified: "modified",
+	state.ItemDeleted:  "deleted",
+}
+
 func describe(settings charm.Settings, config *charm.Config) map[string]interface{} {
 	results := make(map[string]interface{})
 	for name, option := range config.Options {
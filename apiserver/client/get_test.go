@@ -64,6 +64,7 @@ var getTests = []struct {
 		// Outlook is left unset.
 	},
 	expect: params.ServiceGetResults{
+		ConfigRevision: 1,
 		Config: map[string]interface{}{
 			"title": map[string]interface{}{
 				"description": "A descriptive title used for the service.",
@@ -101,6 +102,7 @@ var getTests = []struct {
 		"outlook": "phlegmatic",
 	},
 	expect: params.ServiceGetResults{
+		ConfigRevision: 1,
 		Config: map[string]interface{}{
 			"title": map[string]interface{}{
 				"description": "A descriptive title used for the service.",
@@ -190,6 +192,37 @@ func (s *getSuite) TestServiceGetMaxResolutionInt(c *gc.C) {
 	})
 }
 
+func (s *getSuite) TestServiceGetConfigRevision(c *gc.C) {
+	ch := s.AddTestingCharm(c, "dummy")
+	svc := s.AddTestingService(c, "test-service", ch)
+	err := svc.UpdateConfigSettings(charm.Settings{"title": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.UpdateConfigSettings(charm.Settings{"title": "second", "outlook": "sunny"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.APIState.Client().ServiceGetConfigRevision(svc.Name(), 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got.Service, gc.Equals, "test-service")
+	c.Assert(got.Revision, gc.Equals, 2)
+	c.Assert(got.Changes, jc.DeepEquals, []params.ServiceConfigChange{{
+		Type:     "added",
+		Key:      "outlook",
+		NewValue: "sunny",
+	}, {
+		Type:     "modified",
+		Key:      "title",
+		OldValue: "first",
+		NewValue: "second",
+	}})
+	c.Assert(got.Settings, jc.DeepEquals, map[string]interface{}{
+		"title":   "second",
+		"outlook": "sunny",
+	})
+
+	_, err = s.APIState.Client().ServiceGetConfigRevision(svc.Name(), 3)
+	c.Assert(err, gc.ErrorMatches, `config revision 3 of service "test-service" not found`)
+}
+
 func (s *getSuite) TestServiceGetCharmURL(c *gc.C) {
 	s.setUpScenario(c)
 	charmURL, err := s.APIState.Client().ServiceGetCharmURL("wordpress")
@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// PauseUnits pauses the given units, so that their agents run no
+// periodic hooks and handle no relation changes until they are resumed.
+func (c *Client) PauseUnits(args params.Entities) (params.ErrorResults, error) {
+	return c.setUnitsPaused(args, true)
+}
+
+// ResumeUnits resumes the given paused units.
+func (c *Client) ResumeUnits(args params.Entities) (params.ErrorResults, error) {
+	return c.setUnitsPaused(args, false)
+}
+
+func (c *Client) setUnitsPaused(args params.Entities, paused bool) (params.ErrorResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		unit, err := c.unitFromTag(entity.Tag)
+		if err == nil {
+			err = unit.SetPaused(paused)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
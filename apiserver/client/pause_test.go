@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type pauseSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&pauseSuite{})
+
+func (s *pauseSuite) TestPauseResumeUnits(c *gc.C) {
+	s.setUpScenario(c)
+	unit, err := s.State.Unit("wordpress/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.APIState.Client().PauseUnits(unit.UnitTag(), names.NewUnitTag("wordpress/9"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0].Error, gc.IsNil)
+	c.Assert(results[1].Error, gc.ErrorMatches, `unit "wordpress/9" not found`)
+	c.Assert(results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.IsPaused(), jc.IsTrue)
+
+	results, err = s.APIState.Client().ResumeUnits(unit.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.IsNil)
+	err = unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.IsPaused(), jc.IsFalse)
+}
+
+func (s *pauseSuite) TestPauseUnitsBlocked(c *gc.C) {
+	s.setUpScenario(c)
+	s.BlockAllChanges(c, "TestPauseUnitsBlocked")
+	_, err := s.APIState.Client().PauseUnits(names.NewUnitTag("wordpress/0"))
+	s.AssertBlocked(c, err, "TestPauseUnitsBlocked")
+}
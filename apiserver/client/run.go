@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"github.com/juju/utils"
 	"github.com/juju/utils/set"
 
@@ -111,17 +113,75 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 		params = append(params, execParam)
 	}
 	for _, machineId := range run.Machines {
-		machine, err := c.api.state.Machine(machineId)
+		var machine *state.Machine
+		if id, ok := controllerMachineId(machineId); ok {
+			machine, err = c.controllerMachine(id)
+		} else {
+			machine, err = c.api.state.Machine(machineId)
+		}
 		if err != nil {
 			return results, err
 		}
 		command := fmt.Sprintf("juju-run --no-context %s", quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
+		execParam.MachineId = machineId
 		params = append(params, execParam)
 	}
 	return ParallelExecute(c.getDataDir(), params), nil
 }
 
+// controllerMachinePrefix identifies machines given to Run as state
+// server machines, which belong to the state server environment rather
+// than the environment the client is connected to.
+const controllerMachinePrefix = "controller/"
+
+// controllerMachineId returns the id of the state server machine
+// identified by a machine given to Run, such as "controller/0", and
+// whether it names a state server machine at all.
+func controllerMachineId(machine string) (string, bool) {
+	if !strings.HasPrefix(machine, controllerMachinePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(machine, controllerMachinePrefix), true
+}
+
+// controllerMachine returns the state server machine with the given id.
+// Only the owner of the state server environment may run commands on
+// state server machines.
+func (c *Client) controllerMachine(id string) (*state.Machine, error) {
+	info, err := c.api.state.StateServerInfo()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	apiUser, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	st, err := c.api.state.ForEnviron(info.EnvironmentTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Close()
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != env.Owner() {
+		return nil, common.ErrPerm
+	}
+	isStateServer := false
+	for _, machineId := range info.MachineIds {
+		if machineId == id {
+			isStateServer = true
+			break
+		}
+	}
+	if !isStateServer {
+		return nil, errors.NotFoundf("state server machine %q", id)
+	}
+	return st.Machine(id)
+}
+
 // RunOnAllMachines attempts to run the specified command on all the machines.
 func (c *Client) RunOnAllMachines(run params.RunParams) (params.RunResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
@@ -13,10 +13,13 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/utils/ssh"
 )
 
@@ -346,3 +349,52 @@ func (s *runSuite) TestBlockRunMachineAndService(c *gc.C) {
 		})
 	s.AssertBlocked(c, err, "TestBlockRunMachineAndService")
 }
+
+func (s *runSuite) TestRunOnControllerMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(network.NewAddress("10.3.2.1"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.mockSSH(c, echoInput)
+
+	client := s.APIState.Client()
+	results, err := client.Run(
+		params.RunParams{
+			Commands: "hostname",
+			Timeout:  testing.LongWait,
+			Machines: []string{"controller/" + machine.Id()},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.RunResult{{
+		ExecResponse: exec.ExecResponse{Stdout: []byte(expectedCommand[0])},
+		MachineId:    "controller/" + machine.Id(),
+	}})
+}
+
+func (s *runSuite) TestRunOnControllerMachineNotStateServer(c *gc.C) {
+	s.addMachineWithAddress(c, "10.3.2.1")
+	_, err := s.APIState.Client().Run(
+		params.RunParams{
+			Commands: "hostname",
+			Timeout:  testing.LongWait,
+			Machines: []string{"controller/0"},
+		})
+	c.Assert(err, gc.ErrorMatches, `state server machine "0" not found`)
+}
+
+func (s *runSuite) TestRunOnControllerMachineNotOwner(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	authorizer := apiservertesting.FakeAuthorizer{Tag: user.UserTag()}
+	userClient, err := client.NewClient(s.State, common.NewResources(), authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = userClient.Run(
+		params.RunParams{
+			Commands: "hostname",
+			Timeout:  testing.LongWait,
+			Machines: []string{"controller/0"},
+		})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	jjj "github.com/juju/juju/juju"
+)
+
+// ScaleService adds or removes units of a service so that it has the
+// given number of live units. Units that are already dying are not
+// counted. When scaling down, the most recently added units are
+// removed first, and the service is not scaled below its minimum
+// number of units. All the checks are made before any unit is added
+// or removed.
+func (c *Client) ScaleService(args params.ScaleService) (params.ScaleServiceResult, error) {
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.ScaleServiceResult{}, errors.Trace(err)
+	}
+	plan, err := jjj.PlanScale(service, args.NumUnits, args.ToMachineSpec)
+	if err != nil {
+		return params.ScaleServiceResult{}, errors.Trace(err)
+	}
+	switch {
+	case plan.Delta > 0:
+		err = c.check.ChangeAllowed()
+	case plan.Delta < 0:
+		err = c.check.RemoveAllowed()
+	}
+	if err != nil {
+		return params.ScaleServiceResult{}, errors.Trace(err)
+	}
+	added, removed, err := plan.Apply(c.api.state)
+	return params.ScaleServiceResult{Added: added, Removed: removed}, errors.Trace(err)
+}
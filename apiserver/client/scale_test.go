@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+type scaleSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&scaleSuite{})
+
+func (s *scaleSuite) assertAliveUnits(c *gc.C, service *state.Service, expected ...string) {
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	var alive []string
+	for _, unit := range units {
+		if unit.Life() == state.Alive {
+			alive = append(alive, unit.Name())
+		}
+	}
+	c.Assert(alive, jc.SameContents, expected)
+}
+
+func (s *scaleSuite) TestScaleService(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	client := s.APIState.Client()
+
+	result, err := client.ScaleService("dummy", 3, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ScaleServiceResult{
+		Added: []string{"dummy/0", "dummy/1", "dummy/2"},
+	})
+	s.assertAliveUnits(c, service, "dummy/0", "dummy/1", "dummy/2")
+
+	// The most recently added units are removed first.
+	result, err = client.ScaleService("dummy", 1, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ScaleServiceResult{
+		Removed: []string{"dummy/2", "dummy/1"},
+	})
+	s.assertAliveUnits(c, service, "dummy/0")
+
+	// Dying units are not counted.
+	result, err = client.ScaleService("dummy", 2, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ScaleServiceResult{
+		Added: []string{"dummy/3"},
+	})
+
+	// Scaling to the current count changes nothing.
+	result, err = client.ScaleService("dummy", 2, "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ScaleServiceResult{})
+}
+
+func (s *scaleSuite) TestScaleServiceToMachine(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.APIState.Client().ScaleService("dummy", 2, machine.Id())
+	c.Assert(err, gc.ErrorMatches, `cannot use a placement when scaling service "dummy" by 2 units`)
+
+	result, err := s.APIState.Client().ScaleService("dummy", 1, machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Added, gc.DeepEquals, []string{"dummy/0"})
+	unit, err := s.State.Unit("dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, machine.Id())
+	s.assertAliveUnits(c, service, "dummy/0")
+}
+
+func (s *scaleSuite) TestScaleServiceBelowMinUnits(c *gc.C) {
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	err := service.SetMinUnits(2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.APIState.Client().ScaleService("dummy", 1, "")
+	c.Assert(err, gc.ErrorMatches, `cannot scale service "dummy" to 1 units: minimum is 2`)
+	s.assertAliveUnits(c, service)
+}
+
+func (s *scaleSuite) TestScaleServiceErrors(c *gc.C) {
+	s.setUpScenario(c)
+	client := s.APIState.Client()
+	_, err := client.ScaleService("logging", 1, "")
+	c.Assert(err, gc.ErrorMatches, `cannot scale subordinate service "logging"`)
+	_, err = client.ScaleService("wordpress", -1, "")
+	c.Assert(err, gc.ErrorMatches, "cannot scale to a negative number of units")
+	_, err = client.ScaleService("unknown", 1, "")
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *scaleSuite) TestBlockScaleService(c *gc.C) {
+	s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	s.BlockAllChanges(c, "TestBlockScaleService")
+	_, err := s.APIState.Client().ScaleService("dummy", 1, "")
+	s.AssertBlocked(c, err, "TestBlockScaleService")
+}
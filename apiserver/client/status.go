@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/set"
+	"golang.org/x/net/context"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/hooks"
 
@@ -106,23 +108,57 @@ func (c *Client) UnitStatusHistory(args params.StatusHistory) (api.UnitStatusHis
 	return statuses, nil
 }
 
-// FullStatus gives the information needed for juju status over the api
-func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
+// maxUtilizationAge holds how old the latest utilization sample of a
+// unit may be for it to be reported in the unit's status.
+const maxUtilizationAge = 15 * time.Minute
+
+// FullStatus gives the information needed for juju status over the api.
+// It stops early if the client cancels the request, as gathering the
+// status of a large environment can take a long time.
+func (c *Client) FullStatus(ctx context.Context, args params.StatusParams) (api.Status, error) {
 	cfg, err := c.api.state.EnvironConfig()
 	if err != nil {
 		return api.Status{}, errors.Annotate(err, "could not get environ config")
 	}
 	var noStatus api.Status
+	sections, err := statusSections(args.Sections)
+	if err != nil {
+		return noStatus, errors.Trace(err)
+	}
+	// Services and units are always needed to filter by pattern, and
+	// relations to report services, even when those sections are
+	// not themselves requested.
 	var context statusContext
 	if context.services, context.units, context.latestCharms, err =
 		fetchAllServicesAndUnits(c.api.state, len(args.Patterns) <= 0); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
-	} else if context.machines, err = fetchMachines(c.api.state, nil); err != nil {
-		return noStatus, errors.Annotate(err, "could not fetch machines")
-	} else if context.relations, err = fetchRelations(c.api.state); err != nil {
-		return noStatus, errors.Annotate(err, "could not fetch relations")
-	} else if context.networks, err = fetchNetworks(c.api.state); err != nil {
-		return noStatus, errors.Annotate(err, "could not fetch networks")
+	}
+	if err := ctx.Err(); err != nil {
+		return noStatus, errors.Trace(err)
+	}
+	if sections.Contains(params.StatusSectionMachines) {
+		if context.machines, err = fetchMachines(c.api.state, nil); err != nil {
+			return noStatus, errors.Annotate(err, "could not fetch machines")
+		}
+	}
+	if sections.Contains(params.StatusSectionServices) || sections.Contains(params.StatusSectionRelations) {
+		if context.relations, err = fetchRelations(c.api.state); err != nil {
+			return noStatus, errors.Annotate(err, "could not fetch relations")
+		}
+	}
+	if sections.Contains(params.StatusSectionNetworks) {
+		if context.networks, err = fetchNetworks(c.api.state); err != nil {
+			return noStatus, errors.Annotate(err, "could not fetch networks")
+		}
+	}
+	if sections.Contains(params.StatusSectionServices) {
+		since := time.Now().Add(-maxUtilizationAge)
+		if context.utilization, err = c.api.state.LatestUnitUtilization(since); err != nil {
+			return noStatus, errors.Annotate(err, "could not fetch unit utilization")
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return noStatus, errors.Trace(err)
 	}
 
 	logger.Debugf("Services: %v", context.services)
@@ -174,6 +210,9 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 
 		// Filter machines
 		for status, machineList := range context.machines {
+			if err := ctx.Err(); err != nil {
+				return noStatus, errors.Trace(err)
+			}
 			filteredList := make([]*state.Machine, 0, len(machineList))
 			for _, m := range machineList {
 				machineContainers, err := m.Containers()
@@ -198,19 +237,45 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 		}
 	}
 
-	return api.Status{
+	status := api.Status{
 		EnvironmentName: cfg.Name(),
 		Machines:        processMachines(context.machines),
-		Services:        context.processServices(),
 		Networks:        context.processNetworks(),
-		Relations:       context.processRelations(),
-	}, nil
+	}
+	if sections.Contains(params.StatusSectionServices) {
+		status.Services = context.processServices()
+		if !sections.Contains(params.StatusSectionRelations) {
+			for name, service := range status.Services {
+				service.Relations = nil
+				status.Services[name] = service
+			}
+		}
+	}
+	if sections.Contains(params.StatusSectionRelations) {
+		status.Relations = context.processRelations()
+	}
+	return status, nil
+}
+
+// statusSections returns the set of status sections named, or of all
+// sections if none are.
+func statusSections(names []string) (set.Strings, error) {
+	if len(names) == 0 {
+		return set.NewStrings(params.AllStatusSections...), nil
+	}
+	all := set.NewStrings(params.AllStatusSections...)
+	for _, name := range names {
+		if !all.Contains(name) {
+			return nil, errors.NotValidf("status section %q", name)
+		}
+	}
+	return set.NewStrings(names...), nil
 }
 
 // Status is a stub version of FullStatus that was introduced in 1.16
 func (c *Client) Status() (api.LegacyStatus, error) {
 	var legacyStatus api.LegacyStatus
-	status, err := c.FullStatus(params.StatusParams{})
+	status, err := c.FullStatus(context.Background(), params.StatusParams{})
 	if err != nil {
 		return legacyStatus, err
 	}
@@ -234,6 +299,8 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// utilization: unit name -> latest utilization sample
+	utilization map[string]state.UnitUtilization
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -453,6 +520,12 @@ func makeMachineStatus(machine *state.Machine) (status api.MachineStatus) {
 	} else {
 		status.Hardware = hc.String()
 	}
+	if mapping, err := machine.InstanceTypeMapping(); err == nil {
+		status.InstanceType = mapping.InstanceType
+		status.InstanceTypeRationale = mapping.Rationale
+	} else if !errors.IsNotFound(err) {
+		logger.Debugf("cannot get instance type mapping for machine %q: %v", machine.Id(), err)
+	}
 	status.Containers = make(map[string]api.MachineStatus)
 	return
 }
@@ -553,6 +626,14 @@ func (context *statusContext) processService(service *state.Service) (status api
 	status.Charm = serviceCharmURL.String()
 	status.Exposed = service.IsExposed()
 	status.Life = processLife(service)
+	status.ConfigRevision = service.ConfigRevision()
+	for _, setting := range service.UnmappedConfig() {
+		status.UnmappedConfig = append(status.UnmappedConfig, api.UnmappedConfigSetting{
+			Key:    setting.Key,
+			Value:  setting.Value,
+			Reason: setting.Reason,
+		})
+	}
 
 	latestCharm, ok := context.latestCharms[*serviceCharmURL.WithRevision(-1)]
 	if ok && latestCharm != serviceCharmURL.String() {
@@ -590,6 +671,7 @@ func (context *statusContext) processService(service *state.Service) (status api
 	}
 	if service.IsPrincipal() {
 		status.Units = context.processUnits(context.units[service.Name()], serviceCharmURL.String())
+		status.Summary = summarizeUnits(status.Units)
 		serviceStatus, err := service.Status()
 		if err != nil {
 			status.Err = err
@@ -611,6 +693,29 @@ func (context *statusContext) processUnits(units map[string]*state.Unit, service
 	return unitsMap
 }
 
+// summarizeUnits returns the counts that summarise the given units of
+// a principal service.
+func summarizeUnits(units map[string]api.UnitStatus) api.ServiceSummary {
+	summary := api.ServiceSummary{
+		UnitCount:      len(units),
+		WorkloadCounts: make(map[params.Status]int),
+		AgentCounts:    make(map[params.Status]int),
+	}
+	machines := make(map[string]bool)
+	statuses := make([]state.Status, 0, len(units))
+	for _, unit := range units {
+		if unit.Machine != "" {
+			machines[unit.Machine] = true
+		}
+		summary.WorkloadCounts[unit.Workload.Status]++
+		summary.AgentCounts[unit.UnitAgent.Status]++
+		statuses = append(statuses, state.Status(unit.Workload.Status))
+	}
+	summary.MachineCount = len(machines)
+	summary.WorstStatus = params.Status(state.WorstStatus(statuses...))
+	return summary
+}
+
 func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string) api.UnitStatus {
 	var result api.UnitStatus
 	result.PublicAddress, _ = unit.PublicAddress()
@@ -626,6 +731,15 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		result.Charm = curl.String()
 	}
 	processUnitAndAgentStatus(unit, &result)
+	result.Paused = unit.IsPaused()
+	if sample, ok := context.utilization[unit.Name()]; ok {
+		result.Utilization = &api.UnitUtilization{
+			CPUPercent:  sample.CPUPercent,
+			MemoryBytes: sample.MemoryBytes,
+			Processes:   sample.Processes,
+			Time:        sample.Time,
+		}
+	}
 
 	if subUnits := unit.SubordinateNames(); len(subUnits) > 0 {
 		result.Subordinates = make(map[string]api.UnitStatus)
@@ -732,9 +846,10 @@ func processMachine(machine *state.Machine) (out api.AgentStatus, compat api.Age
 	if out.Err != nil {
 		return
 	}
-	if out.Status == params.StatusPending {
-		// The status is pending - there's no point
-		// in enquiring about the agent liveness.
+	if out.Status == params.StatusPending || out.Status == params.StatusInstanceMissing {
+		// The status is pending, or the machine's instance
+		// has gone - there's no point in enquiring about the
+		// agent liveness.
 		return
 	}
 	agentAlive, err := machine.AgentPresence()
@@ -4,9 +4,12 @@
 package client_test
 
 import (
+	"time"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -45,6 +48,69 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusSections(c *gc.C) {
+	s.addMachine(c)
+	s.Factory.MakeService(c, nil)
+	client := s.APIState.Client()
+	status, err := client.StatusWithSections(nil, []string{"services"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Machines, gc.HasLen, 0)
+	c.Check(status.Services, gc.HasLen, 1)
+	c.Check(status.Relations, gc.HasLen, 0)
+
+	status, err = client.StatusWithSections(nil, []string{"machines"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Machines, gc.HasLen, 1)
+	c.Check(status.Services, gc.HasLen, 0)
+}
+
+func (s *statusSuite) TestFullStatusUnknownSection(c *gc.C) {
+	_, err := s.APIState.Client().StatusWithSections(nil, []string{"storage"})
+	c.Assert(err, gc.ErrorMatches, `status section "storage" not valid`)
+}
+
+func (s *statusSuite) TestFullStatusInstanceType(c *gc.C) {
+	machine := s.addMachine(c)
+	cons, err := machine.ProvisioningConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetInstanceTypeMapping(state.InstanceTypeMapping{
+		Constraints:  cons,
+		InstanceType: "m1.small",
+		Rationale:    "cheapest of 8 instance types matching default constraints",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resultMachine, ok := status.Machines[machine.Id()]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resultMachine.InstanceType, gc.Equals, "m1.small")
+	c.Check(resultMachine.InstanceTypeRationale, gc.Equals, "cheapest of 8 instance types matching default constraints")
+}
+
+func (s *statusSuite) TestFullStatusUtilization(c *gc.C) {
+	unit := s.Factory.MakeUnit(c, nil)
+	now := time.Now().UTC().Round(time.Second)
+	err := s.State.AddUnitUtilization([]state.UnitUtilization{{
+		Unit:        unit.Name(),
+		Time:        now,
+		CPUPercent:  12.5,
+		MemoryBytes: 1024,
+		Processes:   3,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	resultUnit := status.Services[unit.ServiceName()].Units[unit.Name()]
+	c.Assert(resultUnit.Utilization, jc.DeepEquals, &api.UnitUtilization{
+		CPUPercent:  12.5,
+		MemoryBytes: 1024,
+		Processes:   3,
+		Time:        now,
+	})
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
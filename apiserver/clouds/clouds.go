@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package clouds implements the API facade used to manage the clouds
+// that a state server can host environments in.
+package clouds
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Clouds", 1, NewCloudsAPI)
+}
+
+// Clouds defines the methods on the clouds API end point.
+type Clouds interface {
+	AddClouds(args params.Clouds) (params.ErrorResults, error)
+	ListClouds() (params.Clouds, error)
+}
+
+// CloudsAPI implements the Clouds interface.
+type CloudsAPI struct {
+	state *state.State
+}
+
+var _ Clouds = (*CloudsAPI)(nil)
+
+// NewCloudsAPI creates a new server-side Clouds API end point. Only
+// the owner of the state server environment may use it.
+func NewCloudsAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*CloudsAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// TODO: PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the initial
+	// environment is able to manage the system's clouds.
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &CloudsAPI{state: st}, nil
+}
+
+// AddClouds adds the given clouds. The provider type of each cloud must
+// be one that the state server knows how to manage.
+func (api *CloudsAPI) AddClouds(args params.Clouds) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Clouds)),
+	}
+	for i, arg := range args.Clouds {
+		err := api.addCloud(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *CloudsAPI) addCloud(arg params.Cloud) error {
+	if _, err := environs.Provider(arg.ProviderType); err != nil {
+		return errors.Annotatef(err, "cannot add cloud %q", arg.Name)
+	}
+	regions := make([]state.CloudRegion, len(arg.Regions))
+	for i, region := range arg.Regions {
+		regions[i] = state.CloudRegion{
+			Name:     region.Name,
+			Endpoint: region.Endpoint,
+		}
+	}
+	_, err := api.state.AddCloud(arg.Name, arg.ProviderType, regions)
+	return errors.Trace(err)
+}
+
+// ListClouds describes all the clouds known to the state server.
+func (api *CloudsAPI) ListClouds() (params.Clouds, error) {
+	var result params.Clouds
+	clouds, err := api.state.AllClouds()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, cloud := range clouds {
+		info := params.Cloud{
+			Name:         cloud.Name(),
+			ProviderType: cloud.ProviderType(),
+		}
+		for _, region := range cloud.Regions() {
+			info.Regions = append(info.Regions, params.CloudRegion{
+				Name:     region.Name,
+				Endpoint: region.Endpoint,
+			})
+		}
+		result.Clouds = append(result.Clouds, info)
+	}
+	return result, nil
+}
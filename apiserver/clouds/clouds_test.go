@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package clouds_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/clouds"
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type cloudsSuite struct {
+	jujutesting.JujuConnSuite
+	api *clouds.CloudsAPI
+}
+
+var _ = gc.Suite(&cloudsSuite{})
+
+func (s *cloudsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api, err := clouds.NewCloudsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *cloudsSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := clouds.NewCloudsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *cloudsSuite) TestAgentRejected(c *gc.C) {
+	_, err := clouds.NewCloudsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *cloudsSuite) TestAddClouds(c *gc.C) {
+	results, err := s.api.AddClouds(params.Clouds{
+		Clouds: []params.Cloud{{
+			Name:         "other",
+			ProviderType: "dummy",
+			Regions: []params.CloudRegion{
+				{Name: "north"},
+				{Name: "south", Endpoint: "https://south.example.com"},
+			},
+		}, {
+			Name:         "other",
+			ProviderType: "dummy",
+		}, {
+			Name:         "unknown",
+			ProviderType: "nonsense",
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{
+				Message: `cloud "other" already exists`,
+				Code:    params.CodeAlreadyExists,
+			},
+		}, {
+			Error: &params.Error{
+				Message: `cannot add cloud "unknown": no registered provider for "nonsense"`,
+			},
+		}},
+	})
+
+	cloud, err := s.State.Cloud("other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloud.ProviderType(), gc.Equals, "dummy")
+	c.Assert(cloud.Regions(), jc.DeepEquals, []state.CloudRegion{
+		{Name: "north"},
+		{Name: "south", Endpoint: "https://south.example.com"},
+	})
+}
+
+func (s *cloudsSuite) TestListClouds(c *gc.C) {
+	_, err := s.State.AddCloud("b", "dummy", []state.CloudRegion{{Name: "north"}})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloud("a", "dummy", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ListClouds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.Clouds{
+		Clouds: []params.Cloud{{
+			Name:         "a",
+			ProviderType: "dummy",
+		}, {
+			Name:         "b",
+			ProviderType: "dummy",
+			Regions:      []params.CloudRegion{{Name: "north"}},
+		}},
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/proxy"
+	"gopkg.in/juju/charm.v5/charmrepo"
+	"gopkg.in/juju/charmstore.v4/csclient"
+	"gopkg.in/macaroon-bakery.v0/httpbakery"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/environs/config"
+)
+
+// CharmStoreParams returns the parameters for connecting to the charm
+// store used by the environment with the given configuration. The
+// charm store URL, the proxies through which it is reached and the
+// macaroons authorizing access to a private charm store are all taken
+// from the configuration. Any extra macaroons given are added to those.
+func CharmStoreParams(cfg *config.Config, extra ...*macaroon.Macaroon) (charmrepo.NewCharmStoreParams, error) {
+	csURL := csclient.ServerURL
+	if configURL, ok := cfg.CharmStoreURL(); ok {
+		csURL = configURL
+	}
+	parsedURL, err := url.Parse(csURL)
+	if err != nil {
+		return charmrepo.NewCharmStoreParams{}, errors.Trace(err)
+	}
+	ms, err := cfg.CharmStoreAuth()
+	if err != nil {
+		return charmrepo.NewCharmStoreParams{}, errors.Trace(err)
+	}
+	ms = append(ms, extra...)
+
+	client := httpbakery.NewHTTPClient()
+	client.Transport = &http.Transport{
+		Proxy: charmStoreProxy(cfg.ProxySettings()),
+	}
+	if len(ms) > 0 {
+		// Set the authorizing macaroons as a cookie in the
+		// HTTP client.
+		// TODO discharge any third party caveats in the macaroons.
+		httpbakery.SetCookie(client.Jar, parsedURL, ms)
+	}
+	return charmrepo.NewCharmStoreParams{
+		URL:        parsedURL.String(),
+		HTTPClient: client,
+	}, nil
+}
+
+// charmStoreProxy returns a function, suitable for use in an
+// http.Transport, that selects the proxy for a request to the charm
+// store according to the given proxy settings.
+func charmStoreProxy(settings proxy.Settings) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL := settings.Http
+		if req.URL.Scheme == "https" {
+			proxyURL = settings.Https
+		}
+		if proxyURL == "" || noProxy(settings.NoProxy, req.URL.Host) {
+			return nil, nil
+		}
+		if !strings.Contains(proxyURL, "://") {
+			proxyURL = "http://" + proxyURL
+		}
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid proxy address %q", proxyURL)
+		}
+		return parsed, nil
+	}
+}
+
+// noProxy reports whether the given host matches any entry of the
+// comma-separated no-proxy list, either exactly or as a subdomain.
+func noProxy(noProxyList, host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for _, entry := range strings.Split(noProxyList, ",") {
+		entry = strings.TrimPrefix(strings.TrimSpace(entry), ".")
+		switch {
+		case entry == "":
+		case entry == "*", host == entry, strings.HasSuffix(host, "."+entry):
+			return true
+		}
+	}
+	return false
+}
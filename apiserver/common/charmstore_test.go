@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charmstore.v4/csclient"
+	"gopkg.in/macaroon.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type charmStoreSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&charmStoreSuite{})
+
+func (s *charmStoreSuite) TestCharmStoreParamsDefaults(c *gc.C) {
+	cfg := coretesting.EnvironConfig(c)
+	csParams, err := common.CharmStoreParams(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(csParams.URL, gc.Equals, csclient.ServerURL)
+	csURL, err := url.Parse(csParams.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(csParams.HTTPClient.Jar.Cookies(csURL), gc.HasLen, 0)
+
+	transport, ok := csParams.HTTPClient.Transport.(*http.Transport)
+	c.Assert(ok, jc.IsTrue)
+	req, err := http.NewRequest("GET", csParams.URL, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	proxyURL, err := transport.Proxy(req)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(proxyURL, gc.IsNil)
+}
+
+func (s *charmStoreSuite) TestCharmStoreParamsPrivateStore(c *gc.C) {
+	configured, err := macaroon.New([]byte("root key"), "configured", "charmstore")
+	c.Assert(err, jc.ErrorIsNil)
+	extra, err := macaroon.New([]byte("root key"), "extra", "charmstore")
+	c.Assert(err, jc.ErrorIsNil)
+	auth, err := json.Marshal([]*macaroon.Macaroon{configured})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"charm-store-url":  "https://charmstore.example.com/v4",
+		"charm-store-auth": string(auth),
+	})
+
+	csParams, err := common.CharmStoreParams(cfg, extra)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(csParams.URL, gc.Equals, "https://charmstore.example.com/v4")
+	csURL, err := url.Parse(csParams.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	cookies := csParams.HTTPClient.Jar.Cookies(csURL)
+	c.Assert(cookies, gc.HasLen, 1)
+}
+
+func (s *charmStoreSuite) TestCharmStoreParamsProxies(c *gc.C) {
+	cfg := coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"http-proxy":  "10.0.0.1:3128",
+		"https-proxy": "https://10.0.0.2:3129",
+		"no-proxy":    "localhost,.internal.example.com",
+	})
+	csParams, err := common.CharmStoreParams(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	transport := csParams.HTTPClient.Transport.(*http.Transport)
+
+	for i, test := range []struct {
+		url   string
+		proxy string
+	}{{
+		url:   "http://charmstore.example.com/v4",
+		proxy: "http://10.0.0.1:3128",
+	}, {
+		url:   "https://charmstore.example.com/v4",
+		proxy: "https://10.0.0.2:3129",
+	}, {
+		url: "http://localhost:8080/v4",
+	}, {
+		url: "https://store.internal.example.com/v4",
+	}, {
+		url: "https://internal.example.com/v4",
+	}} {
+		c.Logf("test %d: %s", i, test.url)
+		req, err := http.NewRequest("GET", test.url, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		proxyURL, err := transport.Proxy(req)
+		c.Assert(err, jc.ErrorIsNil)
+		if test.proxy == "" {
+			c.Check(proxyURL, gc.IsNil)
+		} else {
+			c.Assert(proxyURL, gc.NotNil)
+			c.Check(proxyURL.String(), gc.Equals, test.proxy)
+		}
+	}
+}
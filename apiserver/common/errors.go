@@ -64,17 +64,40 @@ func IsUnknownEnviromentError(err error) bool {
 	return ok
 }
 
+type quotaExceededError struct {
+	resource string
+	limit    int
+	current  int
+}
+
+func (e *quotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded: %d of %d in use", e.resource, e.current, e.limit)
+}
+
+// QuotaExceededError returns an error reporting that a request would
+// take the given resource past its limit. The error is sent to
+// clients with params.CodeQuotaExceeded and a params.QuotaExceededInfo.
+func QuotaExceededError(resource string, limit, current int) error {
+	return &quotaExceededError{resource, limit, current}
+}
+
+func IsQuotaExceededError(err error) bool {
+	_, ok := err.(*quotaExceededError)
+	return ok
+}
+
 var (
-	ErrBadId              = stderrors.New("id not found")
-	ErrBadCreds           = stderrors.New("invalid entity name or password")
-	ErrPerm               = stderrors.New("permission denied")
-	ErrNotLoggedIn        = stderrors.New("not logged in")
-	ErrUnknownWatcher     = stderrors.New("unknown watcher id")
-	ErrUnknownPinger      = stderrors.New("unknown pinger id")
-	ErrStoppedWatcher     = stderrors.New("watcher has been stopped")
-	ErrBadRequest         = stderrors.New("invalid request")
-	ErrTryAgain           = stderrors.New("try again")
-	ErrActionNotAvailable = stderrors.New("action no longer available")
+	ErrBadId                = stderrors.New("id not found")
+	ErrBadCreds             = stderrors.New("invalid entity name or password")
+	ErrPerm                 = stderrors.New("permission denied")
+	ErrNotLoggedIn          = stderrors.New("not logged in")
+	ErrUnknownWatcher       = stderrors.New("unknown watcher id")
+	ErrUnknownPinger        = stderrors.New("unknown pinger id")
+	ErrStoppedWatcher       = stderrors.New("watcher has been stopped")
+	ErrBadRequest           = stderrors.New("invalid request")
+	ErrTryAgain             = stderrors.New("try again")
+	ErrActionNotAvailable   = stderrors.New("action no longer available")
+	ErrSecondFactorRequired = stderrors.New("two-factor authentication code required")
 
 	ErrOperationBlocked = func(msg string) *params.Error {
 		if msg == "" {
@@ -102,6 +125,7 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrSecondFactorRequired:      params.CodeSecondFactorRequired,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -126,6 +150,7 @@ func ServerError(err error) *params.Error {
 	// Skip past annotations when looking for the code.
 	err = errors.Cause(err)
 	code, ok := singletonCode(err)
+	var info map[string]interface{}
 	switch {
 	case ok:
 	case errors.IsUnauthorized(err):
@@ -146,11 +171,21 @@ func ServerError(err error) *params.Error {
 		code = params.CodeUpgradeInProgress
 	case IsUnknownEnviromentError(err):
 		code = params.CodeNotFound
+	case IsQuotaExceededError(err):
+		err := err.(*quotaExceededError)
+		code = params.CodeQuotaExceeded
+		info = map[string]interface{}{
+			"resource": err.resource,
+			"limit":    err.limit,
+			"current":  err.current,
+		}
 	default:
 		code = params.ErrCode(err)
+		info = params.ErrInfo(err)
 	}
 	return &params.Error{
 		Message: msg,
 		Code:    code,
+		Info:    info,
 	}
 }
@@ -105,6 +105,10 @@ var errorTransformTests = []struct {
 	err:        common.ErrTryAgain,
 	code:       params.CodeTryAgain,
 	helperFunc: params.IsCodeTryAgain,
+}, {
+	err:        common.ErrSecondFactorRequired,
+	code:       params.CodeSecondFactorRequired,
+	helperFunc: params.IsCodeSecondFactorRequired,
 }, {
 	err:        state.UpgradeInProgressError,
 	code:       params.CodeUpgradeInProgress,
@@ -117,6 +121,10 @@ var errorTransformTests = []struct {
 	err:        common.ErrOperationBlocked("test"),
 	code:       params.CodeOperationBlocked,
 	helperFunc: params.IsCodeOperationBlocked,
+}, {
+	err:        common.QuotaExceededError("machines", 10, 10),
+	code:       params.CodeQuotaExceeded,
+	helperFunc: params.IsCodeQuotaExceeded,
 }, {
 	err:  stderrors.New("an error"),
 	code: "",
@@ -157,3 +165,17 @@ func (s *errorsSuite) TestUnknownEnvironment(c *gc.C) {
 	err := common.UnknownEnvironmentError("dead-beef")
 	c.Check(err, gc.ErrorMatches, `unknown environment: "dead-beef"`)
 }
+
+func (s *errorsSuite) TestQuotaExceeded(c *gc.C) {
+	err := common.ServerError(errors.Annotate(common.QuotaExceededError("machines", 10, 12), "cannot add machine"))
+	c.Assert(err, gc.ErrorMatches, "cannot add machine: machines quota exceeded: 12 of 10 in use")
+	info, ok := params.ErrQuotaExceededInfo(err)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info, gc.Equals, params.QuotaExceededInfo{Resource: "machines", Limit: 10, Current: 12})
+}
+
+func (s *errorsSuite) TestServerErrorKeepsInfo(c *gc.C) {
+	info := map[string]interface{}{"limit": 3.0}
+	err := common.ServerError(&params.Error{Message: "m", Code: "c", Info: info})
+	c.Assert(err, jc.DeepEquals, &params.Error{Message: "m", Code: "c", Info: info})
+}
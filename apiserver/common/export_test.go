@@ -11,6 +11,8 @@ var (
 	WrapNewFacade        = wrapNewFacade
 	NilFacadeRecord      = facadeRecord{}
 	EnvtoolsFindTools    = &envtoolsFindTools
+
+	InstanceTypeMappingMaxAge = &instanceTypeMappingMaxAge
 )
 
 type Patcher interface {
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"encoding/json"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// IdempotencyTokens records the idempotency tokens of API calls. It is
+// implemented by *state.State.
+type IdempotencyTokens interface {
+	ClaimIdempotencyToken(token, action string) (*state.IdempotentCall, error)
+	CompleteIdempotencyToken(token string, result []byte) error
+	ReleaseIdempotencyToken(token string) error
+}
+
+// RunIdempotent makes the given call, which fills in result, unless a
+// call of the same action has already been made with the given token
+// within state.IdempotencyWindow, in which case result is filled in
+// with the result of that call instead. This lets clients safely retry
+// calls that create entities after a timeout. If token is empty, the
+// call is always made. The result must be a pointer that can be
+// marshalled as JSON, or nil for calls that return only an error.
+func RunIdempotent(tokens IdempotencyTokens, token, action string, result interface{}, call func() error) error {
+	if token == "" {
+		return call()
+	}
+	held, err := tokens.ClaimIdempotencyToken(token, action)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if held != nil {
+		switch {
+		case held.Action != action:
+			return errors.Errorf("idempotency token %q already used for %s", token, held.Action)
+		case !held.Done:
+			return errors.Errorf("call with idempotency token %q still in progress", token)
+		case result != nil:
+			return errors.Trace(json.Unmarshal(held.Result, result))
+		}
+		return nil
+	}
+	if err := call(); err != nil {
+		// Let the call be retried with the same token.
+		if releaseErr := tokens.ReleaseIdempotencyToken(token); releaseErr != nil {
+			logger.Warningf("%v", releaseErr)
+		}
+		return err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(tokens.CompleteIdempotencyToken(token, data))
+}
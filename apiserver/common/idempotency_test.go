@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type idempotencySuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&idempotencySuite{})
+
+type fakeIdempotencyTokens struct {
+	calls map[string]*state.IdempotentCall
+}
+
+func (f *fakeIdempotencyTokens) ClaimIdempotencyToken(token, action string) (*state.IdempotentCall, error) {
+	if call := f.calls[token]; call != nil {
+		return call, nil
+	}
+	f.calls[token] = &state.IdempotentCall{Action: action}
+	return nil, nil
+}
+
+func (f *fakeIdempotencyTokens) CompleteIdempotencyToken(token string, result []byte) error {
+	f.calls[token].Done = true
+	f.calls[token].Result = result
+	return nil
+}
+
+func (f *fakeIdempotencyTokens) ReleaseIdempotencyToken(token string) error {
+	delete(f.calls, token)
+	return nil
+}
+
+type idempotentResult struct {
+	Ids []string
+}
+
+func (s *idempotencySuite) TestRunIdempotent(c *gc.C) {
+	tokens := &fakeIdempotencyTokens{calls: make(map[string]*state.IdempotentCall)}
+	calls := 0
+	call := func(result *idempotentResult) func() error {
+		return func() error {
+			calls++
+			result.Ids = []string{"0", "1"}
+			return nil
+		}
+	}
+
+	var first idempotentResult
+	err := common.RunIdempotent(tokens, "tok", "AddMachines", &first, call(&first))
+	c.Assert(err, jc.ErrorIsNil)
+	var second idempotentResult
+	err = common.RunIdempotent(tokens, "tok", "AddMachines", &second, call(&second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 1)
+	c.Assert(second, jc.DeepEquals, first)
+
+	err = common.RunIdempotent(tokens, "tok", "Deploy", nil, func() error { return nil })
+	c.Assert(err, gc.ErrorMatches, `idempotency token "tok" already used for AddMachines`)
+
+	// Without a token the call is always made.
+	err = common.RunIdempotent(tokens, "", "AddMachines", &second, call(&second))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, gc.Equals, 2)
+}
+
+func (s *idempotencySuite) TestRunIdempotentInProgress(c *gc.C) {
+	tokens := &fakeIdempotencyTokens{calls: map[string]*state.IdempotentCall{
+		"tok": {Action: "Deploy"},
+	}}
+	err := common.RunIdempotent(tokens, "tok", "Deploy", nil, func() error {
+		c.Fatalf("call made while in progress")
+		return nil
+	})
+	c.Assert(err, gc.ErrorMatches, `call with idempotency token "tok" still in progress`)
+}
+
+func (s *idempotencySuite) TestRunIdempotentFailureReleasesToken(c *gc.C) {
+	tokens := &fakeIdempotencyTokens{calls: make(map[string]*state.IdempotentCall)}
+	err := common.RunIdempotent(tokens, "tok", "Deploy", nil, func() error {
+		return errors.New("boom")
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(tokens.calls, gc.HasLen, 0)
+
+	err = common.RunIdempotent(tokens, "tok", "Deploy", nil, func() error { return nil })
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens.calls["tok"].Done, jc.IsTrue)
+}
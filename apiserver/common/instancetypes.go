@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/state"
+)
+
+// instanceTypeMappingMaxAge is how long a recorded instance type
+// mapping is used before the provider is asked again, so that changes
+// in the instance types a cloud offers are eventually noticed.
+var instanceTypeMappingMaxAge = 24 * time.Hour
+
+// InstanceTypeMappings records the instance types to which constraints
+// have been normalized. It is implemented by *state.State.
+type InstanceTypeMappings interface {
+	InstanceTypeMapping(cons constraints.Value) (state.InstanceTypeMapping, error)
+	SetInstanceTypeMapping(m state.InstanceTypeMapping) error
+}
+
+// NormalizeConstraints returns the instance type to which the
+// normalizer maps the given constraints. A mapping recorded in st is
+// used if it is recent enough; otherwise the normalizer is consulted
+// and its answer recorded.
+func NormalizeConstraints(
+	st InstanceTypeMappings,
+	normalizer environs.ConstraintsNormalizer,
+	cons constraints.Value,
+) (state.InstanceTypeMapping, error) {
+	mapping, err := st.InstanceTypeMapping(cons)
+	if err == nil && time.Since(mapping.Updated) < instanceTypeMappingMaxAge {
+		return mapping, nil
+	} else if err != nil && !errors.IsNotFound(err) {
+		return state.InstanceTypeMapping{}, errors.Trace(err)
+	}
+	normalized, err := normalizer.NormalizeConstraints(cons)
+	if err != nil {
+		return state.InstanceTypeMapping{}, errors.Annotatef(err, "cannot normalize constraints %q", cons)
+	}
+	mapping = state.InstanceTypeMapping{
+		Constraints:  cons,
+		InstanceType: normalized.InstanceType,
+		Hardware:     normalized.Hardware,
+		Rationale:    normalized.Rationale,
+		Updated:      time.Now(),
+	}
+	if err := st.SetInstanceTypeMapping(mapping); err != nil {
+		return state.InstanceTypeMapping{}, errors.Trace(err)
+	}
+	return mapping, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/state"
+)
+
+type instanceTypesSuite struct {
+	testing.IsolationSuite
+	mappings   *fakeInstanceTypeMappings
+	normalizer *fakeNormalizer
+}
+
+var _ = gc.Suite(&instanceTypesSuite{})
+
+type fakeInstanceTypeMappings struct {
+	mappings map[string]state.InstanceTypeMapping
+}
+
+func (f *fakeInstanceTypeMappings) InstanceTypeMapping(cons constraints.Value) (state.InstanceTypeMapping, error) {
+	m, ok := f.mappings[cons.String()]
+	if !ok {
+		return state.InstanceTypeMapping{}, errors.NotFoundf("instance type mapping for %q", cons)
+	}
+	return m, nil
+}
+
+func (f *fakeInstanceTypeMappings) SetInstanceTypeMapping(m state.InstanceTypeMapping) error {
+	f.mappings[m.Constraints.String()] = m
+	return nil
+}
+
+type fakeNormalizer struct {
+	calls int
+	err   error
+}
+
+func (f *fakeNormalizer) NormalizeConstraints(cons constraints.Value) (instances.Normalization, error) {
+	f.calls++
+	if f.err != nil {
+		return instances.Normalization{}, f.err
+	}
+	return instances.Normalization{
+		InstanceType: "m1.large",
+		Hardware:     constraints.MustParse("cpu-cores=2 mem=7680M"),
+		Rationale:    "cheapest of 2 instance types matching " + cons.String(),
+	}, nil
+}
+
+func (s *instanceTypesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mappings = &fakeInstanceTypeMappings{mappings: make(map[string]state.InstanceTypeMapping)}
+	s.normalizer = &fakeNormalizer{}
+}
+
+func (s *instanceTypesSuite) TestNormalizeConstraintsRecords(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	mapping, err := common.NormalizeConstraints(s.mappings, s.normalizer, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mapping.Constraints, jc.DeepEquals, cons)
+	c.Check(mapping.InstanceType, gc.Equals, "m1.large")
+	c.Check(mapping.Rationale, gc.Equals, "cheapest of 2 instance types matching mem=4096M")
+	c.Check(s.mappings.mappings["mem=4096M"], jc.DeepEquals, mapping)
+
+	// The recorded mapping is used next time.
+	_, err = common.NormalizeConstraints(s.mappings, s.normalizer, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.normalizer.calls, gc.Equals, 1)
+}
+
+func (s *instanceTypesSuite) TestNormalizeConstraintsRefreshesStale(c *gc.C) {
+	s.PatchValue(common.InstanceTypeMappingMaxAge, time.Minute)
+	cons := constraints.MustParse("mem=4G")
+	s.mappings.mappings["mem=4096M"] = state.InstanceTypeMapping{
+		Constraints:  cons,
+		InstanceType: "m1.old",
+		Updated:      time.Now().Add(-time.Hour),
+	}
+	mapping, err := common.NormalizeConstraints(s.mappings, s.normalizer, cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mapping.InstanceType, gc.Equals, "m1.large")
+	c.Check(s.normalizer.calls, gc.Equals, 1)
+}
+
+func (s *instanceTypesSuite) TestNormalizeConstraintsError(c *gc.C) {
+	s.normalizer.err = errors.New("no instance types in test matching constraints")
+	_, err := common.NormalizeConstraints(s.mappings, s.normalizer, constraints.MustParse("mem=4G"))
+	c.Assert(err, gc.ErrorMatches, `cannot normalize constraints "mem=4096M": no instance types in test matching constraints`)
+	c.Check(s.mappings.mappings, gc.HasLen, 0)
+}
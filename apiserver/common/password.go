@@ -106,3 +106,78 @@ func (pc *PasswordChanger) setPassword(tag names.Tag, password string) error {
 	}
 	return err
 }
+
+// PasswordRotator implements common SetNextPasswords and
+// ConfirmPasswords methods, allowing agents to replace their
+// passwords without a window in which they cannot log in.
+type PasswordRotator struct {
+	st           state.EntityFinder
+	getCanChange GetAuthFunc
+}
+
+// NewPasswordRotator returns a new PasswordRotator. The GetAuthFunc will
+// be used on each invocation to determine current permissions.
+func NewPasswordRotator(st state.EntityFinder, getCanChange GetAuthFunc) *PasswordRotator {
+	return &PasswordRotator{
+		st:           st,
+		getCanChange: getCanChange,
+	}
+}
+
+// SetNextPasswords records the given password for each supplied entity
+// as its next password. Both the current and next passwords are valid
+// until the next password is confirmed.
+func (pr *PasswordRotator) SetNextPasswords(args params.EntityPasswords) (params.ErrorResults, error) {
+	return pr.forEach(args, func(entity state.PasswordRotator, password string) error {
+		return entity.SetNextPassword(password)
+	})
+}
+
+// ConfirmPasswords makes the given password, previously set with
+// SetNextPasswords, the only valid password for each supplied entity.
+func (pr *PasswordRotator) ConfirmPasswords(args params.EntityPasswords) (params.ErrorResults, error) {
+	return pr.forEach(args, func(entity state.PasswordRotator, password string) error {
+		return entity.ConfirmPassword(password)
+	})
+}
+
+func (pr *PasswordRotator) forEach(
+	args params.EntityPasswords,
+	f func(entity state.PasswordRotator, password string) error,
+) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Changes)),
+	}
+	if len(args.Changes) == 0 {
+		return result, nil
+	}
+	canChange, err := pr.getCanChange()
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, param := range args.Changes {
+		tag, err := names.ParseTag(param.Tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		if !canChange(tag) {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		entity0, err := pr.st.FindEntity(tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(err)
+			continue
+		}
+		entity, ok := entity0.(state.PasswordRotator)
+		if !ok {
+			result.Results[i].Error = ServerError(NotSupportedError(tag, "password rotation"))
+			continue
+		}
+		if err := f(entity, param.Password); err != nil {
+			result.Results[i].Error = ServerError(err)
+		}
+	}
+	return result, nil
+}
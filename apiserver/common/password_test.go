@@ -182,3 +182,100 @@ func (*passwordSuite) TestSetPasswordsNoArgsNoError(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(result.Results, gc.HasLen, 0)
 }
+
+// fakePasswordRotator simulates an agent entity whose password can be
+// rotated.
+type fakePasswordRotator struct {
+	state.Entity
+	fetchError
+	err       error
+	next      string
+	confirmed string
+}
+
+func (r *fakePasswordRotator) SetNextPassword(pass string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.next = pass
+	return nil
+}
+
+func (r *fakePasswordRotator) ConfirmPassword(pass string) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.confirmed = pass
+	return nil
+}
+
+func (*passwordSuite) newRotator() (*fakeState, *common.PasswordRotator, params.EntityPasswords) {
+	st := &fakeState{
+		entities: map[names.Tag]entityWithError{
+			u("x/0"): &fakePasswordRotator{},
+			u("x/1"): &fakePasswordRotator{},
+			u("x/2"): &fakePasswordRotator{
+				err: fmt.Errorf("x2 error"),
+			},
+			u("x/3"): &fakePasswordRotator{
+				fetchError: "x3 error",
+			},
+			u("x/4"): &fakeAuthenticator{},
+		},
+	}
+	getCanChange := func() (common.AuthFunc, error) {
+		return func(tag names.Tag) bool {
+			return tag != names.NewUnitTag("x/0")
+		}, nil
+	}
+	var changes []params.EntityPassword
+	for i := 0; i < len(st.entities); i++ {
+		tag := fmt.Sprintf("unit-x-%d", i)
+		changes = append(changes, params.EntityPassword{
+			Tag:      tag,
+			Password: fmt.Sprintf("%spass", tag),
+		})
+	}
+	return st, common.NewPasswordRotator(st, getCanChange), params.EntityPasswords{Changes: changes}
+}
+
+func (s *passwordSuite) checkRotatorResults(c *gc.C, results params.ErrorResults) {
+	c.Assert(results.Results, gc.HasLen, 5)
+	c.Check(results.Results[0].Error, jc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, "x2 error")
+	c.Check(results.Results[3].Error, gc.ErrorMatches, "x3 error")
+	c.Check(results.Results[4].Error, gc.ErrorMatches, `entity "unit-x-4" does not support password rotation`)
+}
+
+func (s *passwordSuite) TestSetNextPasswords(c *gc.C) {
+	st, pr, args := s.newRotator()
+	results, err := pr.SetNextPasswords(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRotatorResults(c, results)
+	c.Check(st.entities[u("x/0")].(*fakePasswordRotator).next, gc.Equals, "")
+	c.Check(st.entities[u("x/1")].(*fakePasswordRotator).next, gc.Equals, "unit-x-1pass")
+	c.Check(st.entities[u("x/1")].(*fakePasswordRotator).confirmed, gc.Equals, "")
+}
+
+func (s *passwordSuite) TestConfirmPasswords(c *gc.C) {
+	st, pr, args := s.newRotator()
+	results, err := pr.ConfirmPasswords(args)
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkRotatorResults(c, results)
+	c.Check(st.entities[u("x/0")].(*fakePasswordRotator).confirmed, gc.Equals, "")
+	c.Check(st.entities[u("x/1")].(*fakePasswordRotator).confirmed, gc.Equals, "unit-x-1pass")
+	c.Check(st.entities[u("x/1")].(*fakePasswordRotator).next, gc.Equals, "")
+}
+
+func (*passwordSuite) TestPasswordRotatorError(c *gc.C) {
+	getCanChange := func() (common.AuthFunc, error) {
+		return nil, fmt.Errorf("splat")
+	}
+	pr := common.NewPasswordRotator(&fakeState{}, getCanChange)
+	changes := []params.EntityPassword{{Tag: "unit-x-0", Password: "pass"}}
+	_, err := pr.SetNextPasswords(params.EntityPasswords{Changes: changes})
+	c.Assert(err, gc.ErrorMatches, "splat")
+	_, err = pr.ConfirmPasswords(params.EntityPasswords{Changes: changes})
+	c.Assert(err, gc.ErrorMatches, "splat")
+}
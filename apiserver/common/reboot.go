@@ -172,3 +172,56 @@ func (r *RebootFlagClearer) ClearReboot(args params.Entities) (params.ErrorResul
 	}
 	return result, nil
 }
+
+// RebootStatusSetter implements the SetRebootStatus API call
+type RebootStatusSetter struct {
+	st   state.EntityFinder
+	auth GetAuthFunc
+}
+
+func NewRebootStatusSetter(st state.EntityFinder, auth GetAuthFunc) *RebootStatusSetter {
+	return &RebootStatusSetter{
+		st:   st,
+		auth: auth,
+	}
+}
+
+func (r *RebootStatusSetter) setOneStatus(tag names.Tag, status params.RebootStatus) error {
+	entity0, err := r.st.FindEntity(tag)
+	if err != nil {
+		return err
+	}
+	entity, ok := entity0.(state.RebootStatusSetter)
+	if !ok {
+		return NotSupportedError(tag, "set reboot status")
+	}
+	return entity.SetRebootStatus(state.RebootStatus(status))
+}
+
+// SetRebootStatus records the progress of the reboots requested
+// for the provided machines.
+func (r *RebootStatusSetter) SetRebootStatus(args params.RebootStatusParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Params)),
+	}
+	if len(args.Params) == 0 {
+		return result, nil
+	}
+	auth, err := r.auth()
+	if err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	for i, arg := range args.Params {
+		tag, err := names.ParseTag(arg.Entity.Tag)
+		if err != nil {
+			result.Results[i].Error = ServerError(ErrPerm)
+			continue
+		}
+		err = ErrPerm
+		if auth(tag) {
+			err = r.setOneStatus(tag, arg.Status)
+		}
+		result.Results[i].Error = ServerError(err)
+	}
+	return result, nil
+}
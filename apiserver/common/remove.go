@@ -10,6 +10,7 @@ import (
 	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/state"
 )
 
@@ -18,6 +19,8 @@ type Remover struct {
 	st             state.EntityFinder
 	callEnsureDead bool
 	getCanModify   GetAuthFunc
+	hub            *pubsub.Hub
+	envUUID        string
 }
 
 // NewRemover returns a new Remover. The callEnsureDead flag specifies
@@ -32,6 +35,15 @@ func NewRemover(st state.EntityFinder, callEnsureDead bool, getCanModify GetAuth
 	}
 }
 
+// PublishRemovals arranges for the removal of each machine of the
+// environment with the given UUID to be published on the hub, which
+// may be nil. It returns the Remover.
+func (r *Remover) PublishRemovals(hub *pubsub.Hub, envUUID string) *Remover {
+	r.hub = hub
+	r.envUUID = envUUID
+	return r
+}
+
 func (r *Remover) removeEntity(tag names.Tag) error {
 	entity, err := r.st.FindEntity(tag)
 	if err != nil {
@@ -54,7 +66,16 @@ func (r *Remover) removeEntity(tag names.Tag) error {
 			return err
 		}
 	}
-	return remover.Remove()
+	if err := remover.Remove(); err != nil {
+		return err
+	}
+	if tag, ok := tag.(names.MachineTag); ok && r.hub != nil {
+		r.hub.Publish(pubsub.MachineRemovedTopic, pubsub.MachineRemoved{
+			EnvironUUID: r.envUUID,
+			MachineId:   tag.Id(),
+		})
+	}
+	return nil
 }
 
 // Remove removes every given entity from state, calling EnsureDead
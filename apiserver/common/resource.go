@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+
+	"github.com/juju/juju/pubsub"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
 )
 
 // Resource represents any resource that should be cleaned up when an
@@ -130,6 +134,19 @@ func (rs *Resources) Count() int {
 	return len(rs.resources)
 }
 
+// WatcherCount returns the number of state watchers currently held.
+func (rs *Resources) WatcherCount() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	count := 0
+	for _, r := range rs.resources {
+		if _, ok := r.(state.Watcher); ok {
+			count++
+		}
+	}
+	return count
+}
+
 // StringResource is just a regular 'string' that matches the Resource
 // interface.
 type StringResource string
@@ -141,3 +158,40 @@ func (StringResource) Stop() error {
 func (s StringResource) String() string {
 	return string(s)
 }
+
+// HubResource holds the pubsub hub of the API server so that facades
+// can publish events on it. The hub outlives any connection, so it is
+// not stopped with the connection's resources.
+type HubResource struct {
+	*pubsub.Hub
+}
+
+// Stop is part of the Resource interface.
+func (HubResource) Stop() error {
+	return nil
+}
+
+// ReporterResource holds the reporter on the workers of the agent
+// running the API server. Like the hub, it is not stopped with the
+// connection's resources.
+type ReporterResource struct {
+	worker.Reporter
+}
+
+// Stop is part of the Resource interface.
+func (ReporterResource) Stop() error {
+	return nil
+}
+
+// Hub returns the pubsub hub held in the given resources, or nil if
+// there is none.
+func Hub(resources *Resources) *pubsub.Hub {
+	if resources == nil {
+		return nil
+	}
+	hub, ok := resources.Get("hub").(HubResource)
+	if !ok {
+		return nil
+	}
+	return hub.Hub
+}
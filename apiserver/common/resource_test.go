@@ -22,6 +22,29 @@ func (r *fakeResource) Stop() error {
 	return nil
 }
 
+type fakeWatcher struct {
+	fakeResource
+}
+
+func (w *fakeWatcher) Kill()       {}
+func (w *fakeWatcher) Wait() error { return nil }
+func (w *fakeWatcher) Err() error  { return nil }
+
+func (resourceSuite) TestWatcherCount(c *gc.C) {
+	rs := common.NewResources()
+	rs.Register(&fakeResource{})
+	id := rs.Register(&fakeWatcher{})
+	rs.Register(&fakeWatcher{})
+	err := rs.RegisterNamed("named", common.StringResource("x"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rs.Count(), gc.Equals, 4)
+	c.Assert(rs.WatcherCount(), gc.Equals, 2)
+
+	err = rs.Stop(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rs.WatcherCount(), gc.Equals, 1)
+}
+
 func (resourceSuite) TestRegisterGetCount(c *gc.C) {
 	rs := common.NewResources()
 	r1 := &fakeResource{}
@@ -0,0 +1,40 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
+
+// IsEnvironAdmin reports whether the authenticated entity is a user
+// who administers the environment, and so may see the secret values
+// in its configuration.
+//
+// TODO: PERMISSIONS Change this check when we have real permissions.
+// For now, only the owner of the environment or of the state server
+// environment is an administrator.
+func IsEnvironAdmin(st *state.State, authorizer Authorizer) (bool, error) {
+	if !authorizer.AuthClient() {
+		return false, nil
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return false, nil
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if apiUser == env.Owner() {
+		return true, nil
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return apiUser == stateServerEnv.Owner(), nil
+}
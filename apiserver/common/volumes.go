@@ -110,11 +110,12 @@ func VolumeFromState(v state.Volume) (params.Volume, error) {
 		return params.Volume{}, errors.Trace(err)
 	}
 	return params.Volume{
-		v.VolumeTag().String(),
-		info.VolumeId,
-		info.HardwareId,
-		info.Size,
-		info.Persistent,
+		VolumeTag:  v.VolumeTag().String(),
+		VolumeId:   info.VolumeId,
+		HardwareId: info.HardwareId,
+		Size:       info.Size,
+		Persistent: info.Persistent,
+		Releasing:  v.Releasing(),
 	}, nil
 }
 
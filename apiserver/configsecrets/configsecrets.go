@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package configsecrets implements the API facade that lets
+// environment administrators see the secret values in the
+// environment's configuration, which are otherwise withheld.
+package configsecrets
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.configsecrets")
+
+func init() {
+	common.RegisterStandardFacade("ConfigSecrets", 1, NewAPI)
+}
+
+// API implements the ConfigSecrets facade.
+type API struct {
+	state      *state.State
+	authorizer common.Authorizer
+}
+
+// NewAPI returns a new ConfigSecrets API facade. Only administrators
+// of the environment may use it.
+func NewAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*API, error) {
+	isAdmin, err := common.IsEnvironAdmin(st, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		state:      st,
+		authorizer: authorizer,
+	}, nil
+}
+
+// Reveal returns the values of the requested secret attributes of
+// the environment's configuration. Attributes that are not set are
+// left out of the result.
+func (api *API) Reveal(args params.RevealConfigSecrets) (params.EnvironmentConfigResults, error) {
+	var result params.EnvironmentConfigResults
+	cfg, err := api.state.EnvironConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	schema := config.SchemaFor(cfg.Type())
+	keys := args.Keys
+	if len(keys) == 0 {
+		keys = schema.SecretAttrs()
+	}
+	attrs := cfg.AllAttrs()
+	result.Config = make(map[string]interface{})
+	for _, key := range keys {
+		if !schema[key].Secret {
+			return params.EnvironmentConfigResults{}, errors.Errorf("%q is not a secret attribute", key)
+		}
+		if value, ok := attrs[key]; ok {
+			result.Config[key] = value
+		}
+	}
+	logger.Infof("revealed secret attributes %v to %s", keys, api.authorizer.GetAuthTag())
+	return result, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package configsecrets_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/configsecrets"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type configSecretsSuite struct {
+	jujutesting.JujuConnSuite
+	api *configsecrets.API
+}
+
+var _ = gc.Suite(&configSecretsSuite{})
+
+func (s *configSecretsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"rsyslog-ca-cert": coretesting.CACert,
+		"rsyslog-ca-key":  coretesting.CAKey,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = s.newAPI(c, s.AdminUserTag(c))
+}
+
+func (s *configSecretsSuite) newAPI(c *gc.C, tag names.Tag) *configsecrets.API {
+	api, err := configsecrets.NewAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{Tag: tag})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *configSecretsSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := configsecrets.NewAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *configSecretsSuite) TestAgentRejected(c *gc.C) {
+	_, err := configsecrets.NewAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *configSecretsSuite) TestRevealKeys(c *gc.C) {
+	result, err := s.api.Reveal(params.RevealConfigSecrets{
+		Keys: []string{"rsyslog-ca-key", "admin-secret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// admin-secret is never stored in state, so it is left out.
+	c.Assert(result.Config, jc.DeepEquals, map[string]interface{}{
+		"rsyslog-ca-key": coretesting.CAKey,
+	})
+}
+
+func (s *configSecretsSuite) TestRevealAll(c *gc.C) {
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := envConfig.AllAttrs()
+
+	result, err := s.api.Reveal(params.RevealConfigSecrets{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Config["rsyslog-ca-key"], gc.Equals, coretesting.CAKey)
+	for key, value := range result.Config {
+		c.Check(attrs[key], gc.Equals, value)
+	}
+	_, ok := result.Config["name"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *configSecretsSuite) TestRevealNonSecretKey(c *gc.C) {
+	_, err := s.api.Reveal(params.RevealConfigSecrets{
+		Keys: []string{"rsyslog-ca-key", "name"},
+	})
+	c.Assert(err, gc.ErrorMatches, `"name" is not a secret attribute`)
+}
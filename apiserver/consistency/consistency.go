@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package consistency implements the API facade used to check, and
+// repair, the referential integrity of the state database.
+package consistency
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Consistency", 1, NewConsistencyAPI)
+}
+
+// ConsistencyAPI implements the Consistency facade.
+type ConsistencyAPI struct {
+	st *state.State
+}
+
+// NewConsistencyAPI creates a new server-side Consistency API end
+// point. Only the owner of the state server environment may use it.
+func NewConsistencyAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ConsistencyAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &ConsistencyAPI{st: st}, nil
+}
+
+// CheckConsistency checks the referential integrity of the documents
+// in the environment, repairing what can safely be repaired if asked.
+func (api *ConsistencyAPI) CheckConsistency(args params.CheckConsistencyArgs) (params.ConsistencyReport, error) {
+	report, err := api.st.CheckConsistency(state.CheckConsistencyParams{
+		Repair: args.Repair,
+	})
+	if err != nil {
+		return params.ConsistencyReport{}, errors.Trace(err)
+	}
+	result := params.ConsistencyReport{
+		Inconsistencies: make([]params.Inconsistency, len(report.Inconsistencies)),
+	}
+	for i, problem := range report.Inconsistencies {
+		result.Inconsistencies[i] = params.Inconsistency{
+			Kind:       string(problem.Kind),
+			Collection: problem.Collection,
+			DocID:      problem.DocID,
+			Detail:     problem.Detail,
+			Repairable: problem.Repairable,
+			Repaired:   problem.Repaired,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package consistency_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/consistency"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/testing/factory"
+)
+
+type consistencySuite struct {
+	jujutesting.JujuConnSuite
+	api *consistency.ConsistencyAPI
+}
+
+var _ = gc.Suite(&consistencySuite{})
+
+func (s *consistencySuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api, err := consistency.NewConsistencyAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *consistencySuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := consistency.NewConsistencyAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *consistencySuite) TestAgentRejected(c *gc.C) {
+	_, err := consistency.NewConsistencyAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *consistencySuite) TestCheckConsistencyClean(c *gc.C) {
+	report, err := s.api.CheckConsistency(params.CheckConsistencyArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Inconsistencies, gc.HasLen, 0)
+}
+
+func (s *consistencySuite) TestCheckConsistencyRepair(c *gc.C) {
+	err := s.State.LeasePersistor.WriteToken("ghost-leadership", lease.Token{
+		Namespace:  "ghost-leadership",
+		Id:         "ghost/0",
+		Expiration: time.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	report, err := s.api.CheckConsistency(params.CheckConsistencyArgs{Repair: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, params.ConsistencyReport{
+		Inconsistencies: []params.Inconsistency{{
+			Kind:       "orphaned-leadership",
+			Collection: "lease",
+			DocID:      "ghost-leadership",
+			Detail:     `leadership of missing service "ghost"`,
+			Repairable: true,
+			Repaired:   true,
+		}},
+	})
+	tokens, err := s.State.LeasePersistor.PersistedTokens()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 0)
+}
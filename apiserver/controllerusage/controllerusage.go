@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package controllerusage implements the API facade used to report the
+// API usage of each environment hosted by the state server, so that
+// the busiest environments can be found.
+package controllerusage
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ControllerUsage", 1, NewControllerUsageAPI)
+}
+
+// MaxHours holds the largest number of hours over which usage may be
+// reported; older usage is not kept.
+const MaxHours = 7 * 24
+
+// ControllerUsageAPI implements the ControllerUsage facade.
+type ControllerUsageAPI struct {
+	st *state.State
+}
+
+// NewControllerUsageAPI creates a new server-side ControllerUsage API
+// end point. Only the owner of the state server environment may use
+// it.
+func NewControllerUsageAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ControllerUsageAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &ControllerUsageAPI{st: st}, nil
+}
+
+// EnvironmentUsage returns the API calls made to each environment over
+// the given number of hours, busiest first. Usage is recorded by the
+// API servers once a minute, so the most recent calls may not be
+// reported yet.
+func (api *ControllerUsageAPI) EnvironmentUsage(args params.EnvironmentUsageArgs) (params.EnvironmentUsageResults, error) {
+	if args.Hours < 1 || args.Hours > MaxHours {
+		return params.EnvironmentUsageResults{}, errors.NotValidf("hours %d (expected 1 to %d)", args.Hours, MaxHours)
+	}
+	since := time.Now().Add(-time.Duration(args.Hours-1) * time.Hour)
+	usage, err := api.st.APIUsageSince(since)
+	if err != nil {
+		return params.EnvironmentUsageResults{}, errors.Trace(err)
+	}
+	envs, err := api.st.AllEnvironments()
+	if err != nil {
+		return params.EnvironmentUsageResults{}, errors.Trace(err)
+	}
+	envNames := make(map[string]string)
+	for _, env := range envs {
+		envNames[env.UUID()] = env.Name()
+	}
+	result := params.EnvironmentUsageResults{
+		Environments: make([]params.EnvironmentUsage, len(usage)),
+	}
+	for i, u := range usage {
+		// Usage of removed environments is reported without
+		// a name until it ages out.
+		result.Environments[i] = params.EnvironmentUsage{
+			EnvironTag: names.NewEnvironTag(u.EnvUUID).String(),
+			Name:       envNames[u.EnvUUID],
+			Calls:      u.Calls,
+			BytesIn:    u.BytesIn,
+			BytesOut:   u.BytesOut,
+		}
+	}
+	return result, nil
+}
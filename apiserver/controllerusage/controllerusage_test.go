@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controllerusage_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/controllerusage"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type controllerUsageSuite struct {
+	jujutesting.JujuConnSuite
+	api *controllerusage.ControllerUsageAPI
+}
+
+var _ = gc.Suite(&controllerUsageSuite{})
+
+func (s *controllerUsageSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api, err := controllerusage.NewControllerUsageAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *controllerUsageSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := controllerusage.NewControllerUsageAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *controllerUsageSuite) TestAgentRejected(c *gc.C) {
+	_, err := controllerusage.NewControllerUsageAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *controllerUsageSuite) TestEnvironmentUsage(c *gc.C) {
+	other := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "other"})
+	defer other.Close()
+	const removedUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	now := time.Now()
+	err := s.State.AddAPIUsage(now, []state.APIUsage{
+		{EnvUUID: s.State.EnvironUUID(), Calls: 10, BytesIn: 100, BytesOut: 1000},
+		{EnvUUID: other.EnvironUUID(), Calls: 50, BytesIn: 500, BytesOut: 5000},
+		{EnvUUID: removedUUID, Calls: 1, BytesIn: 10, BytesOut: 100},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddAPIUsage(now.Add(-48*time.Hour), []state.APIUsage{
+		{EnvUUID: s.State.EnvironUUID(), Calls: 100, BytesIn: 1000, BytesOut: 10000},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.EnvironmentUsage(params.EnvironmentUsageArgs{Hours: 24})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, jc.DeepEquals, []params.EnvironmentUsage{{
+		EnvironTag: names.NewEnvironTag(other.EnvironUUID()).String(),
+		Name:       "other",
+		Calls:      50,
+		BytesIn:    500,
+		BytesOut:   5000,
+	}, {
+		EnvironTag: names.NewEnvironTag(s.State.EnvironUUID()).String(),
+		Name:       "dummyenv",
+		Calls:      10,
+		BytesIn:    100,
+		BytesOut:   1000,
+	}, {
+		EnvironTag: names.NewEnvironTag(removedUUID).String(),
+		Calls:      1,
+		BytesIn:    10,
+		BytesOut:   100,
+	}})
+
+	result, err = s.api.EnvironmentUsage(params.EnvironmentUsageArgs{Hours: 72})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, gc.HasLen, 3)
+	c.Assert(result.Environments[0].Name, gc.Equals, "dummyenv")
+	c.Assert(result.Environments[0].Calls, gc.Equals, int64(110))
+}
+
+func (s *controllerUsageSuite) TestEnvironmentUsageInvalidHours(c *gc.C) {
+	for _, hours := range []int{0, -1, controllerusage.MaxHours + 1} {
+		_, err := s.api.EnvironmentUsage(params.EnvironmentUsageArgs{Hours: hours})
+		c.Check(err, gc.ErrorMatches, `hours -?\d+ \(expected 1 to 168\) not valid`)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package credentials implements the API facade used to manage the
+// provider credentials stored by the state server, which environments
+// refer to rather than holding their own copies.
+package credentials
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Credentials", 1, NewCredentialsAPI)
+}
+
+// Credentials defines the methods on the credentials API end point.
+type Credentials interface {
+	AddCredential(args params.CloudCredentials) (params.ErrorResults, error)
+	UpdateCredential(args params.CloudCredentials) (params.ErrorResults, error)
+	ListCredentials() (params.CloudCredentialInfoResults, error)
+}
+
+// CredentialsAPI implements the Credentials interface.
+type CredentialsAPI struct {
+	state   *state.State
+	apiUser names.UserTag
+}
+
+var _ Credentials = (*CredentialsAPI)(nil)
+
+// NewCredentialsAPI creates a new server-side Credentials API end
+// point. Only the owner of the state server environment may use it.
+func NewCredentialsAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*CredentialsAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// TODO: PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the initial
+	// environment is able to manage the system's credentials.
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &CredentialsAPI{
+		state:   st,
+		apiUser: apiUser,
+	}, nil
+}
+
+// AddCredential adds the given credentials.
+func (api *CredentialsAPI) AddCredential(args params.CloudCredentials) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Credentials)),
+	}
+	for i, arg := range args.Credentials {
+		_, err := api.state.AddCloudCredential(arg.Name, api.apiUser, arg.ProviderType, arg.Attributes)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// validateCredentials checks that the provider accepts the credentials
+// in the given environment config.
+var validateCredentials = func(cfg *config.Config) error {
+	env, err := environs.New(cfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return environs.CheckCredentials(env)
+}
+
+// UpdateCredential replaces the attributes of the given credentials.
+// The new values are checked with the provider for every environment
+// that refers to a credential before they are stored; once stored,
+// the workers of those environments are notified through their config
+// watchers and reconnect to the provider using the new values.
+func (api *CredentialsAPI) UpdateCredential(args params.CloudCredentials) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Credentials)),
+	}
+	for i, arg := range args.Credentials {
+		err := api.updateCredential(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *CredentialsAPI) updateCredential(arg params.CloudCredential) error {
+	cred, err := api.state.CloudCredential(arg.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if arg.ProviderType != "" && arg.ProviderType != cred.ProviderType() {
+		return errors.Errorf("cannot change provider type of cloud credential %q", arg.Name)
+	}
+	envs, err := cred.Environments()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, env := range envs {
+		if err := api.checkEnvironCredential(env.EnvironTag(), arg.Attributes); err != nil {
+			return errors.Annotatef(err, "credential rejected for environment %q", env.Name())
+		}
+	}
+	return api.state.UpdateCloudCredential(arg.Name, arg.Attributes)
+}
+
+// checkEnvironCredential validates the environment's config with the
+// given credential attributes applied.
+func (api *CredentialsAPI) checkEnvironCredential(tag names.EnvironTag, attrs map[string]string) error {
+	st := api.state
+	if tag != api.state.EnvironTag() {
+		var err error
+		st, err = api.state.ForEnviron(tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer st.Close()
+	}
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newAttrs := make(map[string]interface{})
+	for name, value := range attrs {
+		newAttrs[name] = value
+	}
+	cfg, err = cfg.Apply(newAttrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return validateCredentials(cfg)
+}
+
+// ListCredentials describes all the credentials stored by the state
+// server. The values of the credentials' attributes are not returned.
+func (api *CredentialsAPI) ListCredentials() (params.CloudCredentialInfoResults, error) {
+	var result params.CloudCredentialInfoResults
+	creds, err := api.state.AllCloudCredentials()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, cred := range creds {
+		result.Credentials = append(result.Credentials, params.CloudCredentialInfo{
+			Name:           cred.Name(),
+			OwnerTag:       cred.Owner().String(),
+			ProviderType:   cred.ProviderType(),
+			AttributeNames: cred.AttributeNames(),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/credentials"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type credentialsSuite struct {
+	jujutesting.JujuConnSuite
+	api *credentials.CredentialsAPI
+}
+
+var _ = gc.Suite(&credentialsSuite{})
+
+func (s *credentialsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	api, err := credentials.NewCredentialsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *credentialsSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := credentials.NewCredentialsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *credentialsSuite) TestAgentRejected(c *gc.C) {
+	_, err := credentials.NewCredentialsAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *credentialsSuite) TestAddCredential(c *gc.C) {
+	results, err := s.api.AddCredential(params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:         "shared",
+			ProviderType: "dummy",
+			Attributes:   map[string]string{"secret": "pork"},
+		}, {
+			Name:         "shared",
+			ProviderType: "dummy",
+			Attributes:   map[string]string{"secret": "beef"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{
+				Message: `cloud credential "shared" already exists`,
+				Code:    params.CodeAlreadyExists,
+			},
+		}},
+	})
+
+	cred, err := s.State.CloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Owner(), gc.Equals, s.AdminUserTag(c))
+	c.Assert(cred.Attributes(), jc.DeepEquals, map[string]string{"secret": "pork"})
+}
+
+func (s *credentialsSuite) TestUpdateCredential(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.AdminUserTag(c), "dummy", map[string]string{"secret": "pork"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.UpdateCredential(params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:       "shared",
+			Attributes: map[string]string{"secret": "beef"},
+		}, {
+			Name:         "shared",
+			ProviderType: "ec2",
+			Attributes:   map[string]string{"secret": "lamb"},
+		}, {
+			Name:       "missing",
+			Attributes: map[string]string{"secret": "lamb"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}, {
+			Error: &params.Error{
+				Message: `cannot change provider type of cloud credential "shared"`,
+			},
+		}, {
+			Error: &params.Error{
+				Message: `cloud credential "missing" not found`,
+				Code:    params.CodeNotFound,
+			},
+		}},
+	})
+
+	cred, err := s.State.CloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Attributes(), jc.DeepEquals, map[string]string{"secret": "beef"})
+}
+
+func (s *credentialsSuite) TestUpdateCredentialValidatesEnvironments(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.AdminUserTag(c), "dummy", map[string]string{"secret": "pork"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var validated []*config.Config
+	s.PatchValue(credentials.ValidateCredentials, func(cfg *config.Config) error {
+		validated = append(validated, cfg)
+		return nil
+	})
+	results, err := s.api.UpdateCredential(params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:       "shared",
+			Attributes: map[string]string{"secret": "beef"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	c.Assert(validated, gc.HasLen, 1)
+	uuid, ok := validated[0].UUID()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(uuid, gc.Equals, s.State.EnvironUUID())
+	c.Assert(validated[0].AllAttrs()["secret"], gc.Equals, "beef")
+
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret"], gc.Equals, "beef")
+}
+
+func (s *credentialsSuite) TestUpdateCredentialRejectedByProvider(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.AdminUserTag(c), "dummy", map[string]string{"secret": "pork"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchValue(credentials.ValidateCredentials, func(cfg *config.Config) error {
+		return errors.New("authentication failed")
+	})
+	results, err := s.api.UpdateCredential(params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:       "shared",
+			Attributes: map[string]string{"secret": "beef"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: &params.Error{
+				Message: `credential rejected for environment "dummyenv": authentication failed`,
+			},
+		}},
+	})
+
+	cred, err := s.State.CloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Attributes(), jc.DeepEquals, map[string]string{"secret": "pork"})
+}
+
+func (s *credentialsSuite) TestUpdateCredentialChecksWithProvider(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.AdminUserTag(c), "dummy", map[string]string{"secret": "pork"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.UpdateCredential(params.CloudCredentials{
+		Credentials: []params.CloudCredential{{
+			Name:       "shared",
+			Attributes: map[string]string{"secret": "beef"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+}
+
+func (s *credentialsSuite) TestListCredentials(c *gc.C) {
+	_, err := s.State.AddCloudCredential("b", s.AdminUserTag(c), "dummy", map[string]string{"secret": "pork"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloudCredential("a", s.AdminUserTag(c), "ec2", map[string]string{
+		"access-key": "x",
+		"secret-key": "y",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ListCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.CloudCredentialInfoResults{
+		Credentials: []params.CloudCredentialInfo{{
+			Name:           "a",
+			OwnerTag:       s.AdminUserTag(c).String(),
+			ProviderType:   "ec2",
+			AttributeNames: []string{"access-key", "secret-key"},
+		}, {
+			Name:           "b",
+			OwnerTag:       s.AdminUserTag(c).String(),
+			ProviderType:   "dummy",
+			AttributeNames: []string{"secret"},
+		}},
+	})
+}
@@ -4,6 +4,7 @@
 package apiserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/loggo"
 	"github.com/juju/names"
@@ -21,6 +23,7 @@ import (
 	"golang.org/x/net/websocket"
 	"launchpad.net/tomb"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -32,6 +35,11 @@ type debugLogHandler struct {
 
 var maxLinesReached = fmt.Errorf("max lines reached")
 
+// maxBacklog holds the greatest number of matching lines before the
+// end of the log file that are sent to a client, however far back it
+// asks to start.
+var maxBacklog uint = 100000
+
 // ServeHTTP will serve up connections as a websocket.
 // Args for the HTTP request are as follows:
 //   includeEntity -> []string - lists entity tags to include in the response
@@ -42,12 +50,29 @@ var maxLinesReached = fmt.Errorf("max lines reached")
 //   excludeEntity -> []string - lists entity tags to exclude from the response
 //      - as with include, it may finish with a '*'
 //   excludeModule -> []string - lists logging modules to exclude from the response
+//   includeMessage -> []string - lists regular expressions, one of which must
+//      match the message of a line for it to be included in the response
+//      - if none are set, then all lines are considered included
+//   excludeMessage -> []string - lists regular expressions that must not match
+//      the message of a line for it to be included in the response
 //   limit -> uint - show *at most* this many lines
 //   backlog -> uint
-//      - go back this many lines from the end before starting to filter
+//      - go back this many matching lines from the end before starting to filter
 //      - has no meaning if 'replay' is true
+//      - at most maxBacklog lines are sent
 //   level -> string one of [TRACE, DEBUG, INFO, WARNING, ERROR]
-//   replay -> string - one of [true, false], if true, start the file from the start
+//   replay -> string - one of [true, false], if true, start the file from the start,
+//      or from maxBacklog matching lines before the end if that is later
+//   format -> string - one of [text, json], if json, each line is sent as a JSON
+//      encoded params.LogRecord
+//   field -> []string - name=value pairs; only lines whose fields match all of
+//      them are considered included
+//      - names are one of entity, env-uuid, module, location, level, message
+//      - as with entities, values may contain '*' wildcards
+//   allModels -> string - one of [true, false], if true, lines are sent for
+//      all environments rather than just the one connected to
+//      - only the owner of the state server environment may ask for this
+//      - lines that do not record their environment are always sent
 func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server := websocket.Server{
 		Handler: func(socket *websocket.Conn) {
@@ -62,9 +87,8 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				return
 			}
 			defer stateWrapper.cleanup()
-			// TODO (thumper): We need to work out how we are going to filter
-			// logging information based on environment.
-			if err := stateWrapper.authenticateUser(req); err != nil {
+			userTag, err := stateWrapper.authenticateUserTag(req)
+			if err != nil {
 				h.sendError(socket, fmt.Errorf("auth failed: %v", err))
 				socket.Close()
 				return
@@ -75,6 +99,15 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 				socket.Close()
 				return
 			}
+			if stream.allModels {
+				if err := h.checkStateServerOwner(userTag); err != nil {
+					h.sendError(socket, fmt.Errorf("cannot show the logs of all environments: %v", err))
+					socket.Close()
+					return
+				}
+			} else {
+				stream.envUUID = stateWrapper.state.EnvironUUID()
+			}
 			// Open log file.
 			logLocation := filepath.Join(h.logDir, "all-machines.log")
 			logFile, err := os.Open(logLocation)
@@ -114,6 +147,19 @@ func (h *debugLogHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	server.ServeHTTP(w, req)
 }
 
+// checkStateServerOwner returns an error unless the given user is the
+// owner of the state server environment.
+func (h *debugLogHandler) checkStateServerOwner(user names.UserTag) error {
+	stateServerEnv, err := h.ssState.StateServerEnvironment()
+	if err != nil {
+		return err
+	}
+	if user != stateServerEnv.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
 func newLogStream(queryMap url.Values) (*logStream, error) {
 	maxLines := uint(0)
 	if value := queryMap.Get("maxLines"); value != "" {
@@ -141,6 +187,9 @@ func newLogStream(queryMap url.Values) (*logStream, error) {
 		}
 		backlog = uint(num)
 	}
+	if backlog > maxBacklog {
+		backlog = maxBacklog
+	}
 
 	level := loggo.UNSPECIFIED
 	if value := queryMap.Get("level"); value != "" {
@@ -152,18 +201,114 @@ func newLogStream(queryMap url.Values) (*logStream, error) {
 		}
 	}
 
+	includeMessage, err := compileMessageFilters("includeMessage", queryMap["includeMessage"])
+	if err != nil {
+		return nil, err
+	}
+	excludeMessage, err := compileMessageFilters("excludeMessage", queryMap["excludeMessage"])
+	if err != nil {
+		return nil, err
+	}
+
+	asJSON := false
+	switch value := queryMap.Get("format"); value {
+	case "", "text":
+	case "json":
+		asJSON = true
+	default:
+		return nil, fmt.Errorf("format value %q is not one of %q, %q", value, "text", "json")
+	}
+
+	allModels := false
+	if value := queryMap.Get("allModels"); value != "" {
+		all, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("allModels value %q is not a valid boolean", value)
+		}
+		allModels = all
+	}
+
+	var fields []fieldFilter
+	for _, value := range queryMap["field"] {
+		filter, err := parseFieldFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, filter)
+	}
+
 	return &logStream{
-		includeEntity: queryMap["includeEntity"],
-		includeModule: queryMap["includeModule"],
-		excludeEntity: queryMap["excludeEntity"],
-		excludeModule: queryMap["excludeModule"],
-		maxLines:      maxLines,
-		fromTheStart:  fromTheStart,
-		backlog:       backlog,
-		filterLevel:   level,
+		includeEntity:  queryMap["includeEntity"],
+		includeModule:  queryMap["includeModule"],
+		excludeEntity:  queryMap["excludeEntity"],
+		excludeModule:  queryMap["excludeModule"],
+		includeMessage: includeMessage,
+		excludeMessage: excludeMessage,
+		maxLines:       maxLines,
+		fromTheStart:   fromTheStart,
+		backlog:        backlog,
+		filterLevel:    level,
+		fields:         fields,
+		asJSON:         asJSON,
+		allModels:      allModels,
 	}, nil
 }
 
+// compileMessageFilters compiles the regular expressions given as the
+// values of the named parameter.
+func compileMessageFilters(name string, values []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, value := range values {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s value %q is not a valid regular expression: %v", name, value, err)
+		}
+		result = append(result, re)
+	}
+	return result, nil
+}
+
+// fieldFilter matches log lines by the value of one of their fields.
+type fieldFilter struct {
+	name  string
+	value string
+}
+
+// parseFieldFilter parses a field filter in the form name=value.
+func parseFieldFilter(value string) (fieldFilter, error) {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fieldFilter{}, fmt.Errorf("field value %q is not of the form name=value", value)
+	}
+	switch parts[0] {
+	case "entity", "env-uuid", "module", "location", "level", "message":
+	default:
+		return fieldFilter{}, fmt.Errorf("field name %q is not one of entity, env-uuid, module, location, level, message", parts[0])
+	}
+	return fieldFilter{name: parts[0], value: parts[1]}, nil
+}
+
+// matches reports whether the line's field matches the filter.
+func (f fieldFilter) matches(line *logLine) bool {
+	record := line.record
+	var value string
+	switch f.name {
+	case "entity":
+		value = record.Entity
+	case "env-uuid":
+		value = record.EnvUUID
+	case "module":
+		value = record.Module
+	case "location":
+		value = record.Location
+	case "level":
+		value = record.Level
+	case "message":
+		value = record.Message
+	}
+	return hasMatch(value, f.value)
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *debugLogHandler) sendError(w io.Writer, err error) error {
 	response := &params.ErrorResult{}
@@ -187,18 +332,51 @@ type logLine struct {
 	agentName string
 	level     loggo.Level
 	module    string
+	// record holds the fields of the line, as sent to clients that
+	// ask for JSON.
+	record params.LogRecord
 }
 
+// logTimeFormat is the format of the timestamps written by agents
+// using the text logging format.
+const logTimeFormat = "2006-01-02 15:04:05"
+
+// locationPattern matches the location of a log message, as written
+// by agents using the text logging format.
+var locationPattern = regexp.MustCompile(`^\S+:\d+$`)
+
 func parseLogLine(line string) *logLine {
 	const (
 		agentTagIndex = 0
 		levelIndex    = 3
 		moduleIndex   = 4
+		locationIndex = 5
 	)
 	fields := strings.Fields(line)
 	result := &logLine{
 		line: line,
 	}
+	// Agents that log in JSON write a params.LogRecord after the tag
+	// added by rsyslog.
+	if len(fields) > agentTagIndex+1 && strings.HasPrefix(fields[agentTagIndex+1], "{") {
+		parts := strings.SplitN(line, " ", 2)
+		if err := json.Unmarshal([]byte(parts[1]), &result.record); err == nil {
+			result.agentTag = result.record.Entity
+			if result.agentTag == "" {
+				result.agentTag = strings.TrimSuffix(parts[0], ":")
+				result.record.Entity = result.agentTag
+			}
+			if tag, err := names.ParseTag(result.agentTag); err == nil {
+				result.agentName = tag.Id()
+			}
+			if level, valid := loggo.ParseLevel(result.record.Level); valid {
+				result.level = level
+			}
+			result.module = result.record.Module
+			return result
+		}
+		result.record = params.LogRecord{}
+	}
 	if len(fields) > agentTagIndex {
 		agentTag := fields[agentTagIndex]
 		// Drop mandatory trailing colon (:).
@@ -228,10 +406,24 @@ func parseLogLine(line string) *logLine {
 			result.agentName = entityTag.Id()
 		}
 	}
+	result.record.Entity = result.agentTag
+	result.record.Message = line
 	if len(fields) > moduleIndex {
 		if level, valid := loggo.ParseLevel(fields[levelIndex]); valid {
 			result.level = level
 			result.module = fields[moduleIndex]
+			result.record.Level = level.String()
+			result.record.Module = result.module
+			timestamp := fields[agentTagIndex+1] + " " + fields[agentTagIndex+2]
+			if t, err := time.Parse(logTimeFormat, timestamp); err == nil {
+				result.record.Time = t
+			}
+			messageIndex := moduleIndex + 1
+			if len(fields) > locationIndex && locationPattern.MatchString(fields[locationIndex]) {
+				result.record.Location = fields[locationIndex]
+				messageIndex++
+			}
+			result.record.Message = strings.Join(fields[messageIndex:], " ")
 		}
 	}
 
@@ -241,32 +433,43 @@ func parseLogLine(line string) *logLine {
 // logStream runs the tailer to read a log file and stream
 // it via a web socket.
 type logStream struct {
-	tomb          tomb.Tomb
-	logTailer     *tailer.Tailer
-	filterLevel   loggo.Level
-	includeEntity []string
-	includeModule []string
-	excludeEntity []string
-	excludeModule []string
-	backlog       uint
-	maxLines      uint
-	lineCount     uint
-	fromTheStart  bool
+	tomb           tomb.Tomb
+	logTailer      *tailer.Tailer
+	filterLevel    loggo.Level
+	includeEntity  []string
+	includeModule  []string
+	excludeEntity  []string
+	excludeModule  []string
+	includeMessage []*regexp.Regexp
+	excludeMessage []*regexp.Regexp
+	fields         []fieldFilter
+	backlog        uint
+	maxLines       uint
+	lineCount      uint
+	fromTheStart   bool
+	asJSON         bool
+	allModels      bool
+	envUUID        string
 }
 
 // positionLogFile will update the internal read position of the logFile to be
 // at the end of the file or somewhere in the middle if backlog has been specified.
+// When replaying from the start, the position is bounded by maxBacklog.
 func (stream *logStream) positionLogFile(logFile io.ReadSeeker) error {
 	// Seek to the end, or lines back from the end if we need to.
-	if !stream.fromTheStart {
-		return tailer.SeekLastLines(logFile, stream.backlog, stream.filterLine)
+	backlog := stream.backlog
+	if stream.fromTheStart {
+		backlog = maxBacklog
 	}
-	return nil
+	return tailer.SeekLastLines(logFile, backlog, stream.filterLine)
 }
 
 // start the tailer listening to the logFile, and sending the matching
 // lines to the writer.
 func (stream *logStream) start(logFile io.ReadSeeker, writer io.Writer) {
+	if stream.asJSON {
+		writer = &jsonLineWriter{writer: writer}
+	}
 	stream.logTailer = tailer.NewTailer(logFile, writer, stream.countedFilterLine)
 }
 
@@ -286,8 +489,11 @@ func (stream *logStream) filterLine(line []byte) bool {
 	log := parseLogLine(string(line))
 	return stream.checkIncludeEntity(log) &&
 		stream.checkIncludeModule(log) &&
+		stream.checkIncludeMessage(log) &&
 		!stream.exclude(log) &&
-		stream.checkLevel(log)
+		stream.checkLevel(log) &&
+		stream.checkFields(log) &&
+		stream.checkEnviron(log)
 }
 
 // countedFilterLine checks the received line for one of the configured tags,
@@ -364,9 +570,71 @@ func (stream *logStream) exclude(line *logLine) bool {
 			return true
 		}
 	}
+	for _, re := range stream.excludeMessage {
+		if re.MatchString(line.record.Message) {
+			return true
+		}
+	}
+	return false
+}
+
+func (stream *logStream) checkIncludeMessage(line *logLine) bool {
+	if len(stream.includeMessage) == 0 {
+		return true
+	}
+	for _, re := range stream.includeMessage {
+		if re.MatchString(line.record.Message) {
+			return true
+		}
+	}
 	return false
 }
 
 func (stream *logStream) checkLevel(line *logLine) bool {
 	return line.level >= stream.filterLevel
 }
+
+func (stream *logStream) checkFields(line *logLine) bool {
+	for _, filter := range stream.fields {
+		if !filter.matches(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEnviron checks that the line was logged in the stream's
+// environment, if both are known.
+func (stream *logStream) checkEnviron(line *logLine) bool {
+	if stream.envUUID == "" || line.record.EnvUUID == "" {
+		return true
+	}
+	return line.record.EnvUUID == stream.envUUID
+}
+
+// jsonLineWriter writes each complete line written to it as a JSON
+// encoded params.LogRecord.
+type jsonLineWriter struct {
+	writer  io.Writer
+	partial []byte
+}
+
+func (w *jsonLineWriter) Write(data []byte) (int, error) {
+	w.partial = append(w.partial, data...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.partial[:i])
+		w.partial = w.partial[i+1:]
+		message, err := json.Marshal(parseLogLine(line).record)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := w.writer.Write(append(message, '\n')); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
@@ -16,6 +16,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/testing"
 )
 
@@ -34,6 +35,54 @@ func (s *debugInternalSuite) TestParseLogLine(c *gc.C) {
 	c.Assert(logLine.module, gc.Equals, "juju.cmd.jujud")
 }
 
+func (s *debugInternalSuite) TestParseLogLineRecord(c *gc.C) {
+	line := "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 machine agent  start"
+	logLine := parseLogLine(line)
+	c.Assert(logLine.record, jc.DeepEquals, params.LogRecord{
+		Time:     time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		Level:    "INFO",
+		Module:   "juju.cmd.jujud",
+		Location: "machine.go:127",
+		Message:  "machine agent start",
+		Entity:   "machine-0",
+	})
+}
+
+func (s *debugInternalSuite) TestParseLogLineRecordWithoutLocation(c *gc.C) {
+	line := "unit-mysql-0: 2014-03-24 22:34:25 INFO unit.mysql/0.install some output"
+	logLine := parseLogLine(line)
+	c.Assert(logLine.record.Location, gc.Equals, "")
+	c.Assert(logLine.record.Message, gc.Equals, "some output")
+	c.Assert(logLine.record.Entity, gc.Equals, "unit-mysql-0")
+}
+
+func (s *debugInternalSuite) TestParseLogLineJSON(c *gc.C) {
+	line := `machine-0: {"time":"2014-03-24T22:34:25Z","level":"WARNING","module":"juju.worker","location":"worker.go:12","message":"oops","entity":"machine-0","env-uuid":"some-uuid"}`
+	logLine := parseLogLine(line)
+	c.Assert(logLine.line, gc.Equals, line)
+	c.Assert(logLine.agentTag, gc.Equals, "machine-0")
+	c.Assert(logLine.agentName, gc.Equals, "0")
+	c.Assert(logLine.level, gc.Equals, loggo.WARNING)
+	c.Assert(logLine.module, gc.Equals, "juju.worker")
+	c.Assert(logLine.record, jc.DeepEquals, params.LogRecord{
+		Time:     time.Date(2014, 3, 24, 22, 34, 25, 0, time.UTC),
+		Level:    "WARNING",
+		Module:   "juju.worker",
+		Location: "worker.go:12",
+		Message:  "oops",
+		Entity:   "machine-0",
+		EnvUUID:  "some-uuid",
+	})
+}
+
+func (s *debugInternalSuite) TestParseLogLineInvalidJSON(c *gc.C) {
+	line := "machine-0: {not json"
+	logLine := parseLogLine(line)
+	c.Assert(logLine.agentTag, gc.Equals, "machine-0")
+	c.Assert(logLine.level, gc.Equals, loggo.UNSPECIFIED)
+	c.Assert(logLine.record.Message, gc.Equals, line)
+}
+
 func (s *debugInternalSuite) TestParseLogLineMachineMultiline(c *gc.C) {
 	line := "machine-1: continuation line"
 	logLine := parseLogLine(line)
@@ -151,6 +200,41 @@ func (s *debugInternalSuite) TestCheckExcludeModule(c *gc.C) {
 	c.Check(checkExcludeModule("unit.mysql/1", "juju", "unit"), jc.IsTrue)
 }
 
+func checkMessage(c *gc.C, logValue string, include, exclude []string) bool {
+	stream := &logStream{}
+	var err error
+	stream.includeMessage, err = compileMessageFilters("includeMessage", include)
+	c.Assert(err, jc.ErrorIsNil)
+	stream.excludeMessage, err = compileMessageFilters("excludeMessage", exclude)
+	c.Assert(err, jc.ErrorIsNil)
+	line := &logLine{record: params.LogRecord{Message: logValue}}
+	return stream.checkIncludeMessage(line) && !stream.exclude(line)
+}
+
+func (s *debugInternalSuite) TestCheckMessage(c *gc.C) {
+	c.Check(checkMessage(c, "worker started", nil, nil), jc.IsTrue)
+	c.Check(checkMessage(c, "worker started", []string{"start"}, nil), jc.IsTrue)
+	c.Check(checkMessage(c, "worker started", []string{"^start"}, nil), jc.IsFalse)
+	c.Check(checkMessage(c, "worker started", []string{"stop", "start"}, nil), jc.IsTrue)
+	c.Check(checkMessage(c, "worker started", nil, []string{"work(er)?"}), jc.IsFalse)
+	c.Check(checkMessage(c, "worker started", nil, []string{"stopped$"}), jc.IsTrue)
+	c.Check(checkMessage(c, "worker started", []string{"worker"}, []string{"started"}), jc.IsFalse)
+}
+
+func (s *debugInternalSuite) TestFilterLineMessage(c *gc.C) {
+	stream, err := newLogStream(url.Values{
+		"includeMessage": {"connection"},
+		"excludeMessage": {"refused$"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stream.filterLine([]byte(
+		"machine-0: 2014-03-24 22:34:25 INFO juju.state open.go:118 connection established")), jc.IsTrue)
+	c.Check(stream.filterLine([]byte(
+		"machine-0: 2014-03-24 22:34:25 ERROR juju apiclient.go:119 dial tcp: connection refused")), jc.IsFalse)
+	c.Check(stream.filterLine([]byte(
+		"machine-0: 2014-03-24 22:34:25 INFO juju runner.go:262 worker: start \"api\"")), jc.IsFalse)
+}
+
 func (s *debugInternalSuite) TestFilterLine(c *gc.C) {
 	stream := &logStream{
 		filterLevel:   loggo.INFO,
@@ -275,6 +359,16 @@ line 3
 	s.testStreamInternal(c, true, 0, 3, expected, "max lines reached")
 }
 
+func (s *debugInternalSuite) TestLogStreamLoopFromTheStartBoundedByMaxBacklog(c *gc.C) {
+	s.PatchValue(&maxBacklog, uint(2))
+	expected := `line 2
+line 3
+line 4
+line 5
+`
+	s.testStreamInternal(c, true, 0, 0, expected, "")
+}
+
 func (s *debugInternalSuite) TestLogStreamLoopJustTail(c *gc.C) {
 	expected := `line 4
 line 5
@@ -348,6 +442,113 @@ func (s *debugInternalSuite) TestNewLogStream(c *gc.C) {
 
 	_, err = newLogStream(url.Values{"level": []string{"foo"}})
 	c.Assert(err, gc.ErrorMatches, `level value "foo" is not one of "TRACE", "DEBUG", "INFO", "WARNING", "ERROR"`)
+
+	_, err = newLogStream(url.Values{"includeMessage": []string{"("}})
+	c.Assert(err, gc.ErrorMatches, `includeMessage value "\(" is not a valid regular expression: .*`)
+
+	_, err = newLogStream(url.Values{"excludeMessage": []string{"a["}})
+	c.Assert(err, gc.ErrorMatches, `excludeMessage value "a\[" is not a valid regular expression: .*`)
+
+	_, err = newLogStream(url.Values{"format": []string{"xml"}})
+	c.Assert(err, gc.ErrorMatches, `format value "xml" is not one of "text", "json"`)
+
+	_, err = newLogStream(url.Values{"field": []string{"entity"}})
+	c.Assert(err, gc.ErrorMatches, `field value "entity" is not of the form name=value`)
+
+	_, err = newLogStream(url.Values{"field": []string{"colour=blue"}})
+	c.Assert(err, gc.ErrorMatches, `field name "colour" is not one of .*`)
+}
+
+func (s *debugInternalSuite) TestNewLogStreamBoundsBacklog(c *gc.C) {
+	s.PatchValue(&maxBacklog, uint(50))
+	obtained, err := newLogStream(url.Values{"backlog": []string{"100"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained.backlog, gc.Equals, uint(50))
+}
+
+func (s *debugInternalSuite) TestNewLogStreamMessageFilters(c *gc.C) {
+	obtained, err := newLogStream(url.Values{
+		"includeMessage": []string{"start", "stop"},
+		"excludeMessage": []string{"^debug"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(obtained.includeMessage, gc.HasLen, 2)
+	c.Check(obtained.includeMessage[0].String(), gc.Equals, "start")
+	c.Check(obtained.includeMessage[1].String(), gc.Equals, "stop")
+	c.Assert(obtained.excludeMessage, gc.HasLen, 1)
+	c.Check(obtained.excludeMessage[0].String(), gc.Equals, "^debug")
+}
+
+func (s *debugInternalSuite) TestNewLogStreamFormatAndFields(c *gc.C) {
+	obtained, err := newLogStream(url.Values{
+		"format": []string{"json"},
+		"field":  []string{"env-uuid=some-uuid", "message=a=b"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained.asJSON, jc.IsTrue)
+	c.Check(obtained.fields, jc.DeepEquals, []fieldFilter{
+		{name: "env-uuid", value: "some-uuid"},
+		{name: "message", value: "a=b"},
+	})
+
+	obtained, err = newLogStream(url.Values{"format": []string{"text"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained.asJSON, jc.IsFalse)
+}
+
+func (s *debugInternalSuite) TestNewLogStreamAllModels(c *gc.C) {
+	obtained, err := newLogStream(url.Values{"allModels": []string{"true"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained.allModels, jc.IsTrue)
+
+	obtained, err = newLogStream(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(obtained.allModels, jc.IsFalse)
+}
+
+func (s *debugInternalSuite) TestCheckEnviron(c *gc.C) {
+	line := parseLogLine(`machine-0: {"level":"INFO","module":"juju.worker","message":"hello there","entity":"machine-0","env-uuid":"some-uuid"}`)
+	c.Check((&logStream{}).checkEnviron(line), jc.IsTrue)
+	c.Check((&logStream{envUUID: "some-uuid"}).checkEnviron(line), jc.IsTrue)
+	c.Check((&logStream{envUUID: "other-uuid"}).checkEnviron(line), jc.IsFalse)
+
+	// Lines that do not record their environment are always included.
+	line = parseLogLine("machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 started")
+	c.Check((&logStream{envUUID: "other-uuid"}).checkEnviron(line), jc.IsTrue)
+}
+
+func (s *debugInternalSuite) TestCheckFields(c *gc.C) {
+	line := parseLogLine(`machine-0: {"level":"INFO","module":"juju.worker","message":"hello there","entity":"machine-0","env-uuid":"some-uuid"}`)
+	check := func(filters ...string) bool {
+		stream := &logStream{}
+		for _, value := range filters {
+			filter, err := parseFieldFilter(value)
+			c.Assert(err, jc.ErrorIsNil)
+			stream.fields = append(stream.fields, filter)
+		}
+		return stream.checkFields(line)
+	}
+	c.Check(check(), jc.IsTrue)
+	c.Check(check("env-uuid=some-uuid"), jc.IsTrue)
+	c.Check(check("env-uuid=other-uuid"), jc.IsFalse)
+	c.Check(check("env-uuid=some-*", "module=juju.*"), jc.IsTrue)
+	c.Check(check("env-uuid=some-*", "module=unit.*"), jc.IsFalse)
+	c.Check(check("message=*there"), jc.IsTrue)
+	c.Check(check("level=INFO", "entity=machine-0"), jc.IsTrue)
+}
+
+func (s *debugInternalSuite) TestJSONLineWriter(c *gc.C) {
+	var buf bytes.Buffer
+	writer := &jsonLineWriter{writer: &buf}
+	line := "machine-0: 2014-03-24 22:34:25 INFO juju.cmd.jujud machine.go:127 started\n"
+	n, err := writer.Write([]byte(line[:20]))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 20)
+	c.Assert(buf.String(), gc.Equals, "")
+	_, err = writer.Write([]byte(line[20:] + "machine-1: continued"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals,
+		`{"time":"2014-03-24T22:34:25Z","level":"INFO","module":"juju.cmd.jujud","location":"machine.go:127","message":"started","entity":"machine-0"}`+"\n")
 }
 
 type agentMatchTest struct {
@@ -5,18 +5,22 @@ package apiserver_test
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/juju/juju/testing/factory"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"golang.org/x/net/websocket"
 	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
 )
 
 type debugLogSuite struct {
@@ -171,6 +175,66 @@ func (s *debugLogSuite) TestBacklogWithMaxLines(c *gc.C) {
 	s.assertWebsocketClosed(c, reader)
 }
 
+func (s *debugLogSuite) TestFormatJSON(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{
+		"replay": {"true"},
+		"format": {"json"},
+		"field":  {"entity=machine-1", "module=juju.cmd*"},
+	})
+	s.assertLogFollowing(c, reader)
+	s.writeLogLines(c, logLineCount)
+
+	linesRead := s.readLogLines(c, reader, 2)
+	records := make([]params.LogRecord, len(linesRead))
+	for i, line := range linesRead {
+		err := json.Unmarshal([]byte(line), &records[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(records, jc.DeepEquals, []params.LogRecord{{
+		Time:     time.Date(2014, 3, 24, 22, 36, 28, 0, time.UTC),
+		Level:    "INFO",
+		Module:   "juju.cmd",
+		Location: "supercommand.go:297",
+		Message:  "running juju-1.17.7.1-precise-amd64 [gc]",
+		Entity:   "machine-1",
+	}, {
+		Time:     time.Date(2014, 3, 24, 22, 36, 28, 0, time.UTC),
+		Level:    "INFO",
+		Module:   "juju.cmd.jujud",
+		Location: "machine.go:127",
+		Message:  "machine agent machine-1 start (1.17.7.1-precise-amd64 [gc])",
+		Entity:   "machine-1",
+	}})
+}
+
+func (s *debugLogSuite) TestBadFormat(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"format": {"xml"}})
+	assertJSONError(c, reader, `format value "xml" is not one of "text", "json"`)
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestAllModelsRequiresStateServerOwner(c *gc.C) {
+	s.ensureLogFile(c)
+	reader := s.openWebsocket(c, url.Values{"allModels": {"true"}})
+	assertJSONError(c, reader, "cannot show the logs of all environments: permission denied")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *debugLogSuite) TestAllModels(c *gc.C) {
+	s.ensureLogFile(c)
+	info := s.APIInfo(c)
+	header := utils.BasicAuthHeader(info.Tag.String(), info.Password)
+	conn := s.dialWebsocketInternal(c, url.Values{"allModels": {"true"}}, header)
+	defer conn.Close()
+	s.assertLogReader(c, bufio.NewReader(conn))
+}
+
+func (s *debugLogSuite) TestBadAllModels(c *gc.C) {
+	reader := s.openWebsocket(c, url.Values{"allModels": {"always"}})
+	assertJSONError(c, reader, `allModels value "always" is not a valid boolean`)
+	s.assertWebsocketClosed(c, reader)
+}
+
 type filterTest struct {
 	about    string
 	filter   url.Values
@@ -277,6 +341,13 @@ var filterTests []filterTest = []filterTest{
 			"excludeEntity": {"0", "1", "ubuntu/0"},
 		},
 		filtered: []string{logLines[54], logLines[55]},
+	}, {
+		about: "Include and exclude message filters",
+		filter: url.Values{
+			"includeMessage": {"connection (established|refused)"},
+			"excludeMessage": {"^api:"},
+		},
+		filtered: []string{logLines[10], logLines[12]},
 	},
 }
 
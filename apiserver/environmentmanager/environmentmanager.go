@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/version"
 )
@@ -32,6 +33,9 @@ type EnvironmentManager interface {
 	ConfigSkeleton(args params.EnvironmentSkeletonConfigArgs) (params.EnvironConfigResult, error)
 	CreateEnvironment(args params.EnvironmentCreateArgs) (params.Environment, error)
 	ListEnvironments(user params.Entity) (params.EnvironmentList, error)
+	ListInvitations(user params.Entity) (params.EnvironmentInvitationList, error)
+	AcceptInvitations(args params.Entities) (params.ErrorResults, error)
+	TransferEnvironmentOwnership(args params.TransferEnvironmentOwnership) error
 }
 
 // EnvironmentManagerAPI implements the environment manager interface and is
@@ -40,6 +44,7 @@ type EnvironmentManagerAPI struct {
 	state       stateInterface
 	authorizer  common.Authorizer
 	toolsFinder *common.ToolsFinder
+	hub         *pubsub.Hub
 }
 
 var _ EnvironmentManager = (*EnvironmentManagerAPI)(nil)
@@ -60,6 +65,7 @@ func NewEnvironmentManagerAPI(
 		state:       getState(st),
 		authorizer:  authorizer,
 		toolsFinder: common.NewToolsFinder(st, st, urlGetter),
+		hub:         common.Hub(resources),
 	}, nil
 }
 
@@ -203,7 +209,54 @@ func (em *EnvironmentManagerAPI) validConfig(attrs map[string]interface{}) (*con
 	return cfg, nil
 }
 
-func (em *EnvironmentManagerAPI) newEnvironmentConfig(args params.EnvironmentCreateArgs, source ConfigSource) (*config.Config, error) {
+// cloudRegionAttrs holds, for each provider type, the config
+// attributes from which the provider reads the region it uses and
+// that region's endpoint. Either may be empty if the provider has no
+// such attribute.
+var cloudRegionAttrs = map[string]struct {
+	region, endpoint string
+}{
+	"azure":      {region: "location"},
+	"cloudsigma": {region: "region"},
+	"ec2":        {region: "region"},
+	"gce":        {region: "region"},
+	"joyent":     {endpoint: "sdc-url"},
+	"maas":       {endpoint: "maas-server"},
+	"openstack":  {region: "region", endpoint: "auth-url"},
+	"vsphere":    {region: "datacenter", endpoint: "host"},
+}
+
+// setCloudRegionAttrs sets the provider config attributes for the
+// region of the cloud in attrs. Values already in attrs must match
+// those of the region.
+func setCloudRegionAttrs(attrs map[string]interface{}, cloud *state.Cloud, region state.CloudRegion) error {
+	keys := cloudRegionAttrs[cloud.ProviderType()]
+	for _, attr := range []struct {
+		key, value string
+	}{
+		{keys.region, region.Name},
+		{keys.endpoint, region.Endpoint},
+	} {
+		if attr.key == "" || attr.value == "" {
+			continue
+		}
+		if value, found := attrs[attr.key]; found && value != attr.value {
+			return errors.Errorf(
+				"specified %s \"%v\" does not match cloud %q region %q",
+				attr.key, value, cloud.Name(), region.Name)
+		}
+		attrs[attr.key] = attr.value
+	}
+	return nil
+}
+
+// newEnvironmentConfig returns the config for a new environment. If
+// cloud is not nil, the environment is to be hosted in the given
+// region of that cloud, and the provider's region and endpoint are
+// taken from the region; if the cloud uses a different provider from
+// the state server, only the provider independent values are taken
+// from the state server config.
+func (em *EnvironmentManagerAPI) newEnvironmentConfig(args params.EnvironmentCreateArgs, source ConfigSource, cloud *state.Cloud) (*config.Config, error) {
 	// For now, we just smash to the two maps together as we store
 	// the account values and the environment config together in the
 	// *config.Config instance.
@@ -223,9 +276,33 @@ func (em *EnvironmentManagerAPI) newEnvironmentConfig(args params.EnvironmentCre
 		return nil, errors.Trace(err)
 	}
 	baseMap := baseConfig.AllAttrs()
-	fields, err := em.restrictedProviderFields(baseConfig.Type())
-	if err != nil {
-		return nil, errors.Trace(err)
+	var fields []string
+	if cloud != nil && cloud.ProviderType() != baseConfig.Type() {
+		if value, found := joint["type"]; found && value != cloud.ProviderType() {
+			return nil, errors.Errorf(
+				"specified type \"%v\" does not match cloud %q type %q",
+				value, cloud.Name(), cloud.ProviderType())
+		}
+		joint["type"] = cloud.ProviderType()
+		for _, field := range configValuesFromStateServer {
+			if field != "type" {
+				fields = append(fields, field)
+			}
+		}
+	} else {
+		fields, err = em.restrictedProviderFields(baseConfig.Type())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if cloud != nil && args.CloudRegion != "" {
+		region, err := cloud.Region(args.CloudRegion)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := setCloudRegionAttrs(joint, cloud, region); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 	// Before comparing any values, we need to push the config through
 	// the provider validation code.  One of the reasons for this is that
@@ -297,14 +374,29 @@ func (em *EnvironmentManagerAPI) CreateEnvironment(args params.EnvironmentCreate
 		return result, errors.Trace(err)
 	}
 
-	newConfig, err := em.newEnvironmentConfig(args, stateServerEnv)
+	var cloud *state.Cloud
+	if args.Cloud != "" {
+		cloud, err = em.state.Cloud(args.Cloud)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if args.CloudRegion != "" || len(cloud.Regions()) > 0 {
+			if _, err := cloud.Region(args.CloudRegion); err != nil {
+				return result, errors.Trace(err)
+			}
+		}
+	} else if args.CloudRegion != "" {
+		return result, errors.New("cloud region specified without a cloud")
+	}
+
+	newConfig, err := em.newEnvironmentConfig(args, stateServerEnv, cloud)
 	if err != nil {
 		return result, errors.Trace(err)
 	}
 	// NOTE: check the agent-version of the config, and if it is > the current
 	// version, it is not supported, also check existing tools, and if we don't
 	// have tools for that version, also die.
-	env, st, err := em.state.NewEnvironment(newConfig, ownerTag)
+	env, st, err := em.state.NewEnvironmentInCloud(newConfig, ownerTag, args.Cloud, args.CloudRegion)
 	if err != nil {
 		return result, errors.Annotate(err, "failed to create new environment")
 	}
@@ -314,6 +406,13 @@ func (em *EnvironmentManagerAPI) CreateEnvironment(args params.EnvironmentCreate
 	result.UUID = env.UUID()
 	result.OwnerTag = env.Owner().String()
 
+	if em.hub != nil {
+		em.hub.Publish(pubsub.EnvironmentAddedTopic, pubsub.EnvironmentAdded{
+			UUID:  result.UUID,
+			Name:  result.Name,
+			Owner: result.OwnerTag,
+		})
+	}
 	return result, nil
 }
 
@@ -356,3 +455,113 @@ func (em *EnvironmentManagerAPI) ListEnvironments(user params.Entity) (params.En
 
 	return result, nil
 }
+
+// ListInvitations returns the pending invitations for the specified
+// user to access environments in the current server. As with
+// ListEnvironments, only the state server owner can list the
+// invitations of other users.
+func (em *EnvironmentManagerAPI) ListInvitations(user params.Entity) (params.EnvironmentInvitationList, error) {
+	result := params.EnvironmentInvitationList{}
+
+	stateServerEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	adminUser := stateServerEnv.Owner()
+
+	userTag, err := names.ParseUserTag(user.Tag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	err = em.authCheck(userTag, adminUser)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	invitations, err := em.state.InvitationsForUser(userTag)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	for _, invitation := range invitations {
+		env, err := em.state.GetEnvironment(invitation.EnvironmentTag())
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		result.Invitations = append(result.Invitations, params.EnvironmentInvitation{
+			EnvironTag:  env.Tag().String(),
+			EnvironName: env.Name(),
+			UserTag:     invitation.UserTag().String(),
+			CreatedBy:   invitation.CreatedBy(),
+			DateCreated: invitation.DateCreated(),
+		})
+	}
+	return result, nil
+}
+
+// AcceptInvitations accepts the API user's pending invitations to
+// access each of the given environments, giving the user access to
+// them.
+func (em *EnvironmentManagerAPI) AcceptInvitations(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	authTag := em.authorizer.GetAuthTag()
+	user, ok := authTag.(names.UserTag)
+	if !ok {
+		return result, errors.Errorf("auth tag should be a user, but isn't: %q", authTag.String())
+	}
+	for i, entity := range args.Entities {
+		err := em.acceptInvitation(entity.Tag, user)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (em *EnvironmentManagerAPI) acceptInvitation(tag string, user names.UserTag) error {
+	envTag, err := names.ParseEnvironTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	st, err := em.state.ForEnviron(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+	_, err = st.AcceptEnvironmentInvitation(user)
+	return errors.Trace(err)
+}
+
+// TransferEnvironmentOwnership makes the specified user the owner of
+// the specified environment. Only the current owner of the environment
+// or the state server owner can transfer it.
+func (em *EnvironmentManagerAPI) TransferEnvironmentOwnership(args params.TransferEnvironmentOwnership) error {
+	envTag, err := names.ParseEnvironTag(args.EnvironTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newOwner, err := names.ParseUserTag(args.OwnerTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	stateServerEnv, err := em.state.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := em.state.GetEnvironment(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := em.authCheck(env.Owner(), stateServerEnv.Owner()); err != nil {
+		return errors.Trace(err)
+	}
+
+	st, err := em.state.ForEnviron(envTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Close()
+	return errors.Trace(st.TransferEnvironmentOwnership(newOwner, args.RevokePrevious))
+}
@@ -4,6 +4,9 @@
 package environmentmanager_test
 
 import (
+	"time"
+
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -23,8 +26,10 @@ import (
 	_ "github.com/juju/juju/provider/local"
 	_ "github.com/juju/juju/provider/maas"
 	_ "github.com/juju/juju/provider/openstack"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
 )
 
@@ -110,6 +115,32 @@ func (s *envManagerSuite) TestAdminCanCreateEnvironmentForSomeoneElse(c *gc.C) {
 	c.Assert(env.Name, gc.Equals, "test-env")
 }
 
+func (s *envManagerSuite) TestCreateEnvironmentPublishesEnvironmentAdded(c *gc.C) {
+	hub := pubsub.NewHub()
+	err := s.resources.RegisterNamed("hub", common.HubResource{Hub: hub})
+	c.Assert(err, jc.ErrorIsNil)
+	added := make(chan pubsub.EnvironmentAdded, 1)
+	unsubscribe := hub.Subscribe(pubsub.EnvironmentAddedTopic, func(_ pubsub.Topic, data interface{}) {
+		added <- data.(pubsub.EnvironmentAdded)
+	})
+	defer unsubscribe()
+
+	owner := names.NewUserTag("external@remote")
+	s.setAPIUser(c, owner)
+	env, err := s.envmanager.CreateEnvironment(s.createArgs(c, owner))
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case event := <-added:
+		c.Assert(event, jc.DeepEquals, pubsub.EnvironmentAdded{
+			UUID:  env.UUID,
+			Name:  "test-env",
+			Owner: owner.String(),
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for environment added event")
+	}
+}
+
 func (s *envManagerSuite) TestNonAdminCannotCreateEnvironmentForSomeoneElse(c *gc.C) {
 	s.setAPIUser(c, names.NewUserTag("non-admin@remote"))
 	owner := names.NewUserTag("external@remote")
@@ -252,6 +283,116 @@ func (s *envManagerSuite) TestCreateEnvironmentBadConfig(c *gc.C) {
 	}
 }
 
+func (s *envManagerSuite) TestCreateEnvironmentInCloud(c *gc.C) {
+	_, err := s.State.AddCloud("other", "dummy", []state.CloudRegion{{Name: "north"}})
+	c.Assert(err, jc.ErrorIsNil)
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := s.createArgs(c, admin)
+	args.Cloud = "other"
+	args.CloudRegion = "north"
+	result, err := s.envmanager.CreateEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := s.State.ForEnviron(names.NewEnvironTag(result.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	cloud, region, ok := env.Cloud()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(cloud, gc.Equals, "other")
+	c.Assert(region, gc.Equals, "north")
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentInCloudWithOtherProvider(c *gc.C) {
+	_, err := s.State.AddCloud("aws", "ec2", []state.CloudRegion{{Name: "eu-west-1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := s.createArgs(c, admin)
+	args.Cloud = "aws"
+	args.CloudRegion = "eu-west-1"
+	args.Account = map[string]interface{}{
+		"access-key": "access",
+		"secret-key": "secret",
+	}
+	result, err := s.envmanager.CreateEnvironment(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st, err := s.State.ForEnviron(names.NewEnvironTag(result.UUID))
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	cfg, err := st.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Type(), gc.Equals, "ec2")
+	// The provider's region is taken from the cloud region.
+	c.Assert(cfg.AllAttrs()["region"], gc.Equals, "eu-west-1")
+
+	// Values that do not depend on the provider are still taken from
+	// the state server.
+	ssCfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.StatePort(), gc.Equals, ssCfg.StatePort())
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentInCloudRegionMismatch(c *gc.C) {
+	_, err := s.State.AddCloud("aws", "ec2", []state.CloudRegion{{Name: "eu-west-1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := s.createArgs(c, admin)
+	args.Cloud = "aws"
+	args.CloudRegion = "eu-west-1"
+	args.Config["region"] = "us-east-1"
+	_, err = s.envmanager.CreateEnvironment(args)
+	c.Assert(err, gc.ErrorMatches, `specified region "us-east-1" does not match cloud "aws" region "eu-west-1"`)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentInCloudTypeMismatch(c *gc.C) {
+	_, err := s.State.AddCloud("aws", "ec2", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	args := s.createArgs(c, admin)
+	args.Cloud = "aws"
+	args.Config["type"] = "dummy"
+	_, err = s.envmanager.CreateEnvironment(args)
+	c.Assert(err, gc.ErrorMatches, `specified type "dummy" does not match cloud "aws" type "ec2"`)
+}
+
+func (s *envManagerSuite) TestCreateEnvironmentInCloudErrors(c *gc.C) {
+	_, err := s.State.AddCloud("other", "dummy", []state.CloudRegion{{Name: "north"}})
+	c.Assert(err, jc.ErrorIsNil)
+	admin := s.AdminUserTag(c)
+	s.setAPIUser(c, admin)
+	for i, test := range []struct {
+		cloud    string
+		region   string
+		errMatch string
+	}{{
+		cloud:    "missing",
+		errMatch: `cloud "missing" not found`,
+	}, {
+		cloud:    "other",
+		region:   "south",
+		errMatch: `region "south" in cloud "other" not found`,
+	}, {
+		cloud:    "other",
+		errMatch: `region "" in cloud "other" not found`,
+	}, {
+		region:   "north",
+		errMatch: `cloud region specified without a cloud`,
+	}} {
+		c.Logf("%d: %q %q", i, test.cloud, test.region)
+		args := s.createArgs(c, admin)
+		args.Cloud = test.cloud
+		args.CloudRegion = test.region
+		_, err := s.envmanager.CreateEnvironment(args)
+		c.Assert(err, gc.ErrorMatches, test.errMatch)
+	}
+}
+
 func (s *envManagerSuite) TestCreateEnvironmentSameAgentVersion(c *gc.C) {
 	admin := s.AdminUserTag(c)
 	s.setAPIUser(c, admin)
@@ -337,6 +478,108 @@ func (s *envManagerSuite) TestListEnvironmentsDenied(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *envManagerSuite) TestListInvitations(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	_, err := s.State.InviteEnvironmentUser(user.UserTag(), s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setAPIUser(c, user.UserTag())
+	result, err := s.envmanager.ListInvitations(params.Entity{user.Tag().String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Invitations, gc.HasLen, 1)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	invitation := result.Invitations[0]
+	c.Check(invitation.EnvironTag, gc.Equals, env.Tag().String())
+	c.Check(invitation.EnvironName, gc.Equals, env.Name())
+	c.Check(invitation.UserTag, gc.Equals, user.Tag().String())
+	c.Check(invitation.CreatedBy, gc.Equals, s.AdminUserTag(c).Username())
+}
+
+func (s *envManagerSuite) TestListInvitationsDenied(c *gc.C) {
+	s.setAPIUser(c, names.NewUserTag("external@remote"))
+	_, err := s.envmanager.ListInvitations(params.Entity{names.NewUserTag("other@remote").String()})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) TestAcceptInvitations(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	_, err := s.State.InviteEnvironmentUser(user.UserTag(), s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setAPIUser(c, user.UserTag())
+	result, err := s.envmanager.AcceptInvitations(params.Entities{Entities: []params.Entity{
+		{Tag: s.State.EnvironTag().String()},
+		{Tag: names.NewEnvironTag("deadbeef-0bad-400d-8000-4b1d0d06f00d").String()},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, `cannot accept invitation for "bob@local": invitation for "bob@local" not found`)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"machine-0" is not a valid environment tag`)
+
+	envUser, err := s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
+}
+
+func (s *envManagerSuite) TestTransferEnvironmentOwnership(c *gc.C) {
+	owner := names.NewUserTag("alice@remote")
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+
+	s.setAPIUser(c, owner)
+	err := s.envmanager.TransferEnvironmentOwnership(params.TransferEnvironmentOwnership{
+		EnvironTag:     st.EnvironTag().String(),
+		OwnerTag:       bob.Tag().String(),
+		RevokePrevious: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Owner().Username(), gc.Equals, "bob@local")
+	_, err = st.EnvironmentUser(bob.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.EnvironmentUser(owner)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *envManagerSuite) TestTransferEnvironmentOwnershipDenied(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: names.NewUserTag("alice@remote")})
+	defer st.Close()
+
+	s.setAPIUser(c, names.NewUserTag("external@remote"))
+	err := s.envmanager.TransferEnvironmentOwnership(params.TransferEnvironmentOwnership{
+		EnvironTag: st.EnvironTag().String(),
+		OwnerTag:   names.NewUserTag("external@remote").String(),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *envManagerSuite) TestTransferEnvironmentOwnershipByAdmin(c *gc.C) {
+	owner := names.NewUserTag("alice@remote")
+	bob := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner})
+	defer st.Close()
+
+	s.setAPIUser(c, s.AdminUserTag(c))
+	err := s.envmanager.TransferEnvironmentOwnership(params.TransferEnvironmentOwnership{
+		EnvironTag: st.EnvironTag().String(),
+		OwnerTag:   bob.Tag().String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Owner().Username(), gc.Equals, "bob@local")
+	// The previous owner keeps access unless it is revoked.
+	_, err = st.EnvironmentUser(owner)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type fakeProvider struct {
 	environs.EnvironProvider
 }
@@ -16,8 +16,12 @@ var getState = func(st *state.State) stateInterface {
 
 type stateInterface interface {
 	StateServerEnvironment() (*state.Environment, error)
-	NewEnvironment(*config.Config, names.UserTag) (*state.Environment, *state.State, error)
+	NewEnvironmentInCloud(cfg *config.Config, owner names.UserTag, cloud, region string) (*state.Environment, *state.State, error)
+	Cloud(name string) (*state.Cloud, error)
 	EnvironmentsForUser(names.UserTag) ([]*state.Environment, error)
+	InvitationsForUser(names.UserTag) ([]*state.EnvironmentInvitation, error)
+	GetEnvironment(names.EnvironTag) (*state.Environment, error)
+	ForEnviron(names.EnvironTag) (*state.State, error)
 }
 
 type stateShim struct {
@@ -19,15 +19,18 @@ import (
 )
 
 var (
-	RootType              = reflect.TypeOf(&apiHandler{})
-	NewPingTimeout        = newPingTimeout
-	MaxClientPingInterval = &maxClientPingInterval
-	MongoPingInterval     = &mongoPingInterval
-	NewTimer              = &newTimer
-	ResetTimer            = &resetTimer
-	NewBackups            = &newBackups
-	ParseLogLine          = parseLogLine
-	AgentMatchesFilter    = agentMatchesFilter
+	RootType                 = reflect.TypeOf(&apiHandler{})
+	NewPingTimeout           = newPingTimeout
+	MaxClientPingInterval    = &maxClientPingInterval
+	MongoPingInterval        = &mongoPingInterval
+	NewTimer                 = &newTimer
+	ResetTimer               = &resetTimer
+	NewBackups               = &newBackups
+	ParseLogLine             = parseLogLine
+	AgentMatchesFilter       = agentMatchesFilter
+	SSHTunnelPort            = &sshTunnelPort
+	ActionOutputPollInterval = &actionOutputPollInterval
+	UsageFlushInterval       = &usageFlushInterval
 )
 
 func ApiHandlerWithEntity(entity state.Entity) *apiHandler {
@@ -98,6 +101,13 @@ func TestingRestrictedApiHandler(st *state.State) rpc.MethodFinder {
 	return newRestrictedRoot(r)
 }
 
+// TestingAuthorizingRoot returns a srvRoot that checks each call
+// with the given CallAuthorizer on behalf of the given authorizer.
+func TestingAuthorizingRoot(st *state.State, callAuthorizer CallAuthorizer, authorizer common.Authorizer) rpc.MethodFinder {
+	r := TestingApiRoot(st)
+	return newAuthorizingRoot(r, callAuthorizer, st, authorizer)
+}
+
 type preFacadeAdminApi struct{}
 
 func newPreFacadeAdminApi(srv *Server, root *apiHandler, reqNotifier *requestNotifier) interface{} {
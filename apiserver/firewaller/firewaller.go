@@ -263,6 +263,40 @@ func (f *FirewallerAPI) GetExposed(args params.Entities) (params.BoolResults, er
 	return result, nil
 }
 
+// GetExposedEndpoints returns the endpoints to which the exposure of
+// each given service is restricted. No endpoints are returned for a
+// service that is exposed on all its endpoints.
+func (f *FirewallerAPI) GetExposedEndpoints(args params.Entities) (params.ExposedEndpointsResults, error) {
+	result := params.ExposedEndpointsResults{
+		Results: make([]params.ExposedEndpointsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessService()
+	if err != nil {
+		return params.ExposedEndpointsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := f.getService(canAccess, tag)
+		if err == nil {
+			for name, endpoint := range service.ExposedEndpoints() {
+				if result.Results[i].Endpoints == nil {
+					result.Results[i].Endpoints = make(map[string]params.ExposedEndpoint)
+				}
+				result.Results[i].Endpoints[name] = params.ExposedEndpoint{
+					Ports: endpoint.Ports,
+					CIDRs: endpoint.CIDRs,
+				}
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 // GetAssignedMachine returns the assigned machine tag (if any) for
 // each given unit.
 func (f *FirewallerAPI) GetAssignedMachine(args params.Entities) (params.StringResults, error) {
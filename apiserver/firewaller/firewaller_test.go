@@ -75,6 +75,38 @@ func (s *firewallerSuite) TestGetExposed(c *gc.C) {
 	s.testGetExposed(c, s.firewaller)
 }
 
+func (s *firewallerSuite) TestGetExposedEndpoints(c *gc.C) {
+	err := s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"url": {Ports: []string{"443/tcp"}, CIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.service.Tag().String()},
+		{Tag: "service-bar"},
+		{Tag: "unit-wordpress-0"},
+	}}
+	result, err := s.firewaller.GetExposedEndpoints(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposedEndpointsResults{
+		Results: []params.ExposedEndpointsResult{
+			{Endpoints: map[string]params.ExposedEndpoint{
+				"url": {Ports: []string{"443/tcp"}, CIDRs: []string{"10.0.0.0/8"}},
+			}},
+			{Error: apiservertesting.NotFoundError(`service "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.firewaller.GetExposedEndpoints(params.Entities{Entities: args.Entities[:1]})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposedEndpointsResults{
+		Results: []params.ExposedEndpointsResult{{}},
+	})
+}
+
 func (s *firewallerSuite) TestOpenedPortsNotImplemented(c *gc.C) {
 	apiservertesting.AssertNotImplemented(c, s.firewaller, "OpenedPorts")
 }
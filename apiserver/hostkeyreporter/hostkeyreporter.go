@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter implements the API facade used by machine
+// agents to report their SSH host keys.
+package hostkeyreporter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("HostKeyReporter", 1, NewFacade)
+}
+
+// Backend defines the state methods this facade needs, so they can be
+// mocked for testing.
+type Backend interface {
+	SetSSHHostKeys(names.MachineTag, state.SSHHostKeys) error
+}
+
+// Facade implements the HostKeyReporter API.
+type Facade struct {
+	backend      Backend
+	getCanModify common.GetAuthFunc
+}
+
+// NewFacade returns a new HostKeyReporter facade. Only machine agents
+// may use it, and only to report their own keys.
+func NewFacade(st *state.State, _ *common.Resources, authorizer common.Authorizer) (*Facade, error) {
+	return newFacade(st, authorizer)
+}
+
+func newFacade(backend Backend, authorizer common.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend: backend,
+		getCanModify: func() (common.AuthFunc, error) {
+			return authorizer.AuthOwner, nil
+		},
+	}, nil
+}
+
+// ReportKeys sets the SSH host keys for one or more entities.
+func (facade *Facade) ReportKeys(args params.SSHHostKeySet) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.EntityKeys)),
+	}
+	canModify, err := facade.getCanModify()
+	if err != nil {
+		return results, err
+	}
+	for i, arg := range args.EntityKeys {
+		tag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canModify(tag) {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = facade.backend.SetSSHHostKeys(tag, state.SSHHostKeys(arg.PublicKeys))
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
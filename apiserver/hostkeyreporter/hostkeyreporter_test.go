@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/hostkeyreporter"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	coretesting.BaseSuite
+	backend    *mockBackend
+	authorizer apiservertesting.FakeAuthorizer
+	facade     *hostkeyreporter.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{keys: make(map[string]state.SSHHostKeys)}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	var err error
+	s.facade, err = hostkeyreporter.NewFacadeForTest(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *facadeSuite) TestOnlyMachineAgents(c *gc.C) {
+	for _, tag := range []names.Tag{
+		names.NewUserTag("bob"),
+		names.NewUnitTag("ubuntu/0"),
+	} {
+		authorizer := apiservertesting.FakeAuthorizer{Tag: tag}
+		facade, err := hostkeyreporter.NewFacadeForTest(s.backend, authorizer)
+		c.Check(facade, gc.IsNil)
+		c.Check(err, gc.Equals, common.ErrPerm)
+	}
+}
+
+func (s *facadeSuite) TestReportKeys(c *gc.C) {
+	args := params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        names.NewMachineTag("1").String(),
+			PublicKeys: []string{"rsa1", "dsa1"},
+		}, {
+			Tag:        names.NewMachineTag("2").String(),
+			PublicKeys: []string{"rsa2"},
+		}, {
+			Tag:        names.NewUnitTag("ubuntu/0").String(),
+			PublicKeys: []string{"rsa3"},
+		}, {
+			Tag:        "not-a-tag",
+			PublicKeys: []string{"rsa4"},
+		}},
+	}
+	result, err := s.facade.ReportKeys(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.backend.keys, jc.DeepEquals, map[string]state.SSHHostKeys{
+		"1": {"rsa1", "dsa1"},
+	})
+}
+
+func (s *facadeSuite) TestReportKeysError(c *gc.C) {
+	s.backend.err = errors.New("boom")
+	result, err := s.facade.ReportKeys(params.SSHHostKeySet{
+		EntityKeys: []params.SSHHostKeys{{
+			Tag:        names.NewMachineTag("1").String(),
+			PublicKeys: []string{"rsa1"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	keys map[string]state.SSHHostKeys
+	err  error
+}
+
+func (b *mockBackend) SetSSHHostKeys(tag names.MachineTag, keys state.SSHHostKeys) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.keys[tag.Id()] = keys
+	return nil
+}
@@ -101,15 +101,22 @@ func (h *httpStateWrapper) authenticate(r *http.Request) (names.Tag, error) {
 }
 
 func (h *httpStateWrapper) authenticateUser(r *http.Request) error {
+	_, err := h.authenticateUserTag(r)
+	return err
+}
+
+// authenticateUserTag is like authenticateUser, but also returns the
+// tag of the authenticated user.
+func (h *httpStateWrapper) authenticateUserTag(r *http.Request) (names.UserTag, error) {
 	tag, err := h.authenticate(r)
 	if err != nil {
-		return err
+		return names.UserTag{}, err
 	}
-	switch tag.(type) {
+	switch tag := tag.(type) {
 	case names.UserTag:
-		return nil
+		return tag, nil
 	default:
-		return common.ErrBadCreds
+		return names.UserTag{}, common.ErrBadCreds
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	apihttp "github.com/juju/juju/apiserver/http"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/imagestorage"
@@ -83,7 +84,12 @@ func (h *imagesDownloadHandler) processGet(r *http.Request, resp http.ResponseWr
 	metadata, imageReader, err := storage.Image(kind, series, arch)
 	// Not in storage, so go fetch it.
 	if errors.IsNotFound(err) {
-		metadata, imageReader, err = h.fetchAndCacheLxcImage(storage, envuuid, series, arch)
+		var envConfig *config.Config
+		envConfig, err = st.EnvironConfig()
+		if err != nil {
+			return errors.Annotate(err, "cannot get environment config")
+		}
+		metadata, imageReader, err = h.fetchAndCacheLxcImage(storage, envConfig, envuuid, series, arch)
 		if err != nil {
 			return errors.Annotate(err, "error fetching and caching image")
 		}
@@ -105,12 +111,18 @@ func (h *imagesDownloadHandler) processGet(r *http.Request, resp http.ResponseWr
 	return nil
 }
 
-// fetchAndCacheLxcImage fetches an lxc image tarball from http://cloud-images.ubuntu.com
-// and caches it in the state blobstore.
-func (h *imagesDownloadHandler) fetchAndCacheLxcImage(storage imagestorage.Storage, envuuid, series, arch string) (
+// fetchAndCacheLxcImage fetches an lxc image tarball from http://cloud-images.ubuntu.com,
+// or the container image source configured for the environment, and
+// caches it in the state blobstore.
+func (h *imagesDownloadHandler) fetchAndCacheLxcImage(
+	storage imagestorage.Storage, envConfig *config.Config, envuuid, series, arch string,
+) (
 	*imagestorage.Metadata, io.ReadCloser, error,
 ) {
-	imageURL, err := container.ImageDownloadURL(instance.LXC, series, arch)
+	sourceURL, _ := envConfig.ContainerImageSourceURL()
+	imageURL, err := container.ImageDownloadURL(
+		instance.LXC, series, arch, envConfig.ContainerImageStream(), sourceURL,
+	)
 	if err != nil {
 		return nil, nil, errors.Annotatef(err, "cannot determine LXC image URL: %v", err)
 	}
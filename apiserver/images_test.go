@@ -126,6 +126,28 @@ func (s *imageSuite) TestDownloadFetchesAndCaches(c *gc.C) {
 	c.Assert(string(data), gc.Equals, string(cachedData))
 }
 
+func (s *imageSuite) TestDownloadFetchesContainerImageStream(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"container-image-stream": "daily",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testing.PatchExecutable(c, s, "ubuntu-cloudimg-query", containertesting.FakeLxcURLScript)
+	useTestImageData(map[string]string{
+		"/trusty-daily-amd64-root.tar.gz": s.imageData,
+		"/SHA256SUMS":                     s.imageChecksum + " *trusty-daily-amd64-root.tar.gz",
+	})
+	defer func() {
+		useTestImageData(nil)
+	}()
+
+	response, err := s.downloadRequest(c, s.imageURL(c, "lxc", "trusty", "amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.testDownload(c, response)
+
+	metadata, _ := s.getImageFromStorage(c, s.State, "lxc", "trusty", "amd64")
+	c.Assert(metadata.SourceURL, gc.Equals, "test://cloud-images/trusty-daily-amd64-root.tar.gz")
+}
+
 func (s *imageSuite) TestDownloadFetchChecksumMismatch(c *gc.C) {
 	// Set up some image data for a fake server.
 	testing.PatchExecutable(c, s, "ubuntu-cloudimg-query", containertesting.FakeLxcURLScript)
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection implements the API facade used to inspect the
+// workers running in the agent that hosts the API server.
+package introspection
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+func init() {
+	common.RegisterStandardFacade("Introspection", 1, NewIntrospectionAPI)
+}
+
+// IntrospectionAPI implements the Introspection facade.
+type IntrospectionAPI struct {
+	agentTag names.Tag
+	reporter worker.Reporter
+}
+
+// NewIntrospectionAPI creates a new server-side Introspection API end
+// point. Only the owner of the state server environment may use it.
+func NewIntrospectionAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*IntrospectionAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	api := &IntrospectionAPI{}
+	if machineID, ok := resources.Get("machineID").(common.StringResource); ok {
+		api.agentTag = names.NewMachineTag(machineID.String())
+	}
+	if reporter, ok := resources.Get("agentReporter").(common.ReporterResource); ok {
+		api.reporter = reporter.Reporter
+	}
+	return api, nil
+}
+
+// WorkerReports returns reports on the workers of each of the given
+// agents. Only the agent running the API server can be reported on.
+func (api *IntrospectionAPI) WorkerReports(args params.Entities) (params.AgentWorkerReportsResults, error) {
+	results := params.AgentWorkerReportsResults{
+		Results: make([]params.AgentWorkerReportsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		reports, err := api.workerReports(entity.Tag)
+		results.Results[i].Reports = reports
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *IntrospectionAPI) workerReports(tagString string) ([]params.AgentWorkerReport, error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if api.agentTag == nil || tag != api.agentTag {
+		return nil, errors.NotFoundf("agent %q on this API server", tag)
+	}
+	if api.reporter == nil {
+		return nil, errors.NotSupportedf("worker reports for agent %q", tag)
+	}
+	var reports []params.AgentWorkerReport
+	for _, report := range api.reporter.Report() {
+		reports = append(reports, params.AgentWorkerReport{
+			Name:       report.Name,
+			State:      report.State,
+			Inputs:     report.Inputs,
+			StartCount: report.StartCount,
+			LastError:  report.LastError,
+		})
+	}
+	return reports, nil
+}
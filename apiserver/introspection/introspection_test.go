@@ -0,0 +1,122 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/introspection"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+)
+
+type introspectionSuite struct {
+	jujutesting.JujuConnSuite
+	resources *common.Resources
+	api       *introspection.IntrospectionAPI
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+type fakeReporter []worker.WorkerReport
+
+func (r fakeReporter) Report() []worker.WorkerReport {
+	return r
+}
+
+func (s *introspectionSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	err := s.resources.RegisterNamed("machineID", common.StringResource("0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.resources.RegisterNamed("agentReporter", common.ReporterResource{Reporter: fakeReporter{{
+		Name:       "api",
+		State:      worker.StateStarted,
+		StartCount: 2,
+		LastError:  "connection is shut down",
+	}, {
+		Name:  "upgrader",
+		State: worker.StateStarting,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = s.newAPI(c)
+}
+
+func (s *introspectionSuite) newAPI(c *gc.C) *introspection.IntrospectionAPI {
+	api, err := introspection.NewIntrospectionAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *introspectionSuite) TestNonAdminUserRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := introspection.NewIntrospectionAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *introspectionSuite) TestAgentRejected(c *gc.C) {
+	_, err := introspection.NewIntrospectionAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *introspectionSuite) TestWorkerReports(c *gc.C) {
+	results, err := s.api.WorkerReports(params.Entities{Entities: []params.Entity{
+		{Tag: "machine-0"},
+		{Tag: "machine-1"},
+		{Tag: "unit-mysql-0"},
+		{Tag: "not-a-tag"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.AgentWorkerReportsResults{
+		Results: []params.AgentWorkerReportsResult{{
+			Reports: []params.AgentWorkerReport{{
+				Name:       "api",
+				State:      "started",
+				StartCount: 2,
+				LastError:  "connection is shut down",
+			}, {
+				Name:  "upgrader",
+				State: "starting",
+			}},
+		}, {
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `agent "machine-1" on this API server not found`,
+			},
+		}, {
+			Error: &params.Error{
+				Code:    params.CodeNotFound,
+				Message: `agent "unit-mysql-0" on this API server not found`,
+			},
+		}, {
+			Error: &params.Error{
+				Message: `"not-a-tag" is not a valid tag`,
+			},
+		}},
+	})
+}
+
+func (s *introspectionSuite) TestWorkerReportsWithoutReporter(c *gc.C) {
+	err := s.resources.Stop("agentReporter")
+	c.Assert(err, jc.ErrorIsNil)
+	api := s.newAPI(c)
+	results, err := api.WorkerReports(params.Entities{Entities: []params.Entity{{Tag: "machine-0"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `worker reports for agent "machine-0" not supported`)
+}
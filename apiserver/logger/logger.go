@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
@@ -26,6 +27,7 @@ func init() {
 type Logger interface {
 	WatchLoggingConfig(args params.Entities) params.NotifyWatchResults
 	LoggingConfig(args params.Entities) params.StringResults
+	LoggingFormat(args params.Entities) params.StringResults
 }
 
 // LoggerAPI implements the Logger interface and is the concrete
@@ -82,11 +84,23 @@ func (api *LoggerAPI) WatchLoggingConfig(arg params.Entities) params.NotifyWatch
 
 // LoggingConfig reports the logging configuration for the agents specified.
 func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
+	return api.configResults(arg, (*config.Config).LoggingConfig)
+}
+
+// LoggingFormat reports the format in which the agents specified should
+// write their log messages.
+func (api *LoggerAPI) LoggingFormat(arg params.Entities) params.StringResults {
+	return api.configResults(arg, (*config.Config).LoggingFormat)
+}
+
+// configResults returns the result of calling get on the environment
+// config for each of the given agents.
+func (api *LoggerAPI) configResults(arg params.Entities, get func(*config.Config) string) params.StringResults {
 	if len(arg.Entities) == 0 {
 		return params.StringResults{}
 	}
 	results := make([]params.StringResult, len(arg.Entities))
-	config, configErr := api.state.EnvironConfig()
+	cfg, configErr := api.state.EnvironConfig()
 	for i, entity := range arg.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -96,7 +110,7 @@ func (api *LoggerAPI) LoggingConfig(arg params.Entities) params.StringResults {
 		err = common.ErrPerm
 		if api.authorizer.AuthOwner(tag) {
 			if configErr == nil {
-				results[i].Result = config.LoggingConfig()
+				results[i].Result = get(cfg)
 				err = nil
 			} else {
 				err = configErr
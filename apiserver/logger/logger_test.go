@@ -144,3 +144,38 @@ func (s *loggerSuite) TestLoggingConfigForAgent(c *gc.C) {
 	c.Assert(result.Error, gc.IsNil)
 	c.Assert(result.Result, gc.Equals, newLoggingConfig)
 }
+
+func (s *loggerSuite) TestLoggingFormatRefusesWrongAgent(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: "machine-12354"}},
+	}
+	results := s.logger.LoggingFormat(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+}
+
+func (s *loggerSuite) TestLoggingFormatDefault(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.LoggingFormat(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, gc.Equals, "text")
+}
+
+func (s *loggerSuite) TestLoggingFormatForAgent(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"logging-format": "json"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results := s.logger.LoggingFormat(args)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Result, gc.Equals, "json")
+}
@@ -5,21 +5,26 @@ package apiserver
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/ratelimit"
 	"golang.org/x/net/websocket"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
 
 type logSinkHandler struct {
 	httpHandler
-	st *state.State
+	st      *state.State
+	limiter *logSinkLimiter
 }
 
 // LogMessage is used to transmit log messages to the logsink API
@@ -72,6 +77,8 @@ func (h *logSinkHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 			dbLogger := state.NewDbLogger(stateWrapper.state, tag)
 			defer dbLogger.Close()
+			bucket := h.limiter.bucket(tag.String(), h.rateLimit())
+			dropped := 0
 			var m LogMessage
 			for {
 				if err := websocket.JSON.Receive(socket, &m); err != nil {
@@ -80,15 +87,90 @@ func (h *logSinkHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 					}
 					break
 				}
+				if bucket != nil && bucket.TakeAvailable(1) == 0 {
+					dropped++
+					continue
+				}
+				if dropped > 0 {
+					// Record the gap so that readers of the log know
+					// that messages are missing.
+					msg := fmt.Sprintf("%d log messages dropped: rate limit exceeded", dropped)
+					if err := dbLogger.Log(time.Now(), logSinkModule, "", loggo.WARNING, msg); err != nil {
+						logger.Errorf("logging to DB failed: %v", err)
+						break
+					}
+					dropped = 0
+				}
 				if err := dbLogger.Log(m.Time, m.Module, m.Location, m.Level, m.Message); err != nil {
 					logger.Errorf("logging to DB failed: %v", err)
 					break
 				}
 			}
+			if dropped > 0 {
+				logger.Warningf("%d log messages from %s dropped: rate limit exceeded", dropped, tag)
+			}
 		}}
 	server.ServeHTTP(w, req)
 }
 
+// rateLimit returns the number of log messages per second that each
+// agent may send, as configured in the state server environment.
+func (h *logSinkHandler) rateLimit() int {
+	cfg, err := h.ssState.EnvironConfig()
+	if err != nil {
+		logger.Warningf("cannot read log rate limit, using default: %v", err)
+		return config.DefaultLogSinkRateLimit
+	}
+	return cfg.LogSinkRateLimit()
+}
+
+// logSinkModule is the module recorded with the messages that the
+// logsink itself adds to the log.
+const logSinkModule = "juju.apiserver.logsink"
+
+// logSinkBurstFactor is the number of seconds' worth of log messages
+// an agent may send in a burst before it is limited.
+const logSinkBurstFactor = 10
+
+// logSinkLimiter limits the rate at which each entity may send log
+// messages, across all of its connections to the logsink.
+type logSinkLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*logSinkBucket
+}
+
+type logSinkBucket struct {
+	rate   int
+	bucket *ratelimit.Bucket
+}
+
+func newLogSinkLimiter() *logSinkLimiter {
+	return &logSinkLimiter{
+		buckets: make(map[string]*logSinkBucket),
+	}
+}
+
+// bucket returns the token bucket that limits the messages of the given
+// entity to rate per second, or nil if rate is zero and the entity is
+// not limited.
+func (l *logSinkLimiter) bucket(entity string, rate int) *ratelimit.Bucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rate <= 0 {
+		delete(l.buckets, entity)
+		return nil
+	}
+	b, ok := l.buckets[entity]
+	if !ok || b.rate != rate {
+		b = &logSinkBucket{
+			rate:   rate,
+			bucket: ratelimit.NewBucketWithRate(float64(rate), int64(rate*logSinkBurstFactor)),
+		}
+		l.buckets[entity] = b
+	}
+	return b.bucket
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *logSinkHandler) sendError(w io.Writer, err error) error {
 	response := &params.ErrorResult{}
@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type logSinkLimiterSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&logSinkLimiterSuite{})
+
+func (s *logSinkLimiterSuite) TestBucketSharedByEntity(c *gc.C) {
+	limiter := newLogSinkLimiter()
+	bucket := limiter.bucket("machine-0", 5)
+	c.Assert(bucket, gc.NotNil)
+	c.Assert(limiter.bucket("machine-0", 5), gc.Equals, bucket)
+	c.Assert(limiter.bucket("machine-1", 5), gc.Not(gc.Equals), bucket)
+}
+
+func (s *logSinkLimiterSuite) TestBucketCapacity(c *gc.C) {
+	limiter := newLogSinkLimiter()
+	bucket := limiter.bucket("machine-0", 2)
+	c.Assert(bucket.TakeAvailable(100), gc.Equals, int64(2*logSinkBurstFactor))
+	c.Assert(bucket.TakeAvailable(1), gc.Equals, int64(0))
+}
+
+func (s *logSinkLimiterSuite) TestBucketReplacedWhenRateChanges(c *gc.C) {
+	limiter := newLogSinkLimiter()
+	bucket := limiter.bucket("machine-0", 5)
+	c.Assert(limiter.bucket("machine-0", 10), gc.Not(gc.Equals), bucket)
+}
+
+func (s *logSinkLimiterSuite) TestNoBucketWithoutLimit(c *gc.C) {
+	limiter := newLogSinkLimiter()
+	limiter.bucket("machine-0", 5)
+	c.Assert(limiter.bucket("machine-0", 0), gc.IsNil)
+	c.Assert(limiter.buckets, gc.HasLen, 0)
+}
@@ -176,6 +176,58 @@ func (s *logsinkSuite) TestLogging(c *gc.C) {
 	}
 }
 
+func (s *logsinkSuite) TestRateLimiting(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"logsink-rate-limit": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	conn := s.dialWebsocket(c)
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	errResult := readJSONErrorLine(c, reader)
+	c.Assert(errResult.Error, gc.IsNil)
+
+	send := func(message string) {
+		err := websocket.JSON.Send(conn, &apiserver.LogMessage{
+			Time:    time.Now(),
+			Module:  "some.where",
+			Level:   loggo.INFO,
+			Message: message,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// With a rate of 1 message per second, a burst of 10 messages
+	// is allowed.
+	for i := 0; i < 30; i++ {
+		send("chatty")
+	}
+
+	// Keep sending until the limiter lets a message through, at which
+	// point the dropped messages are noted in the log.
+	logsColl := s.State.MongoSession().DB("logs").C("logs")
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		send("later")
+		count, err := logsColl.Find(bson.M{"m": "juju.apiserver.logsink"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if count > 0 {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for dropped messages to be noted")
+		}
+	}
+	chatty, err := logsColl.Find(bson.M{"x": "chatty"}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(chatty < 30, jc.IsTrue, gc.Commentf("%d messages accepted", chatty))
+	c.Assert(chatty >= 10, jc.IsTrue, gc.Commentf("%d messages accepted", chatty))
+
+	var doc bson.M
+	err = logsColl.Find(bson.M{"m": "juju.apiserver.logsink"}).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc["n"], gc.Equals, s.machineTag.String())
+	c.Assert(doc["v"], gc.Equals, int(loggo.WARNING))
+	c.Assert(doc["x"], gc.Matches, `\d+ log messages dropped: rate limit exceeded`)
+}
+
 func (s *logsinkSuite) dialWebsocket(c *gc.C) *websocket.Conn {
 	return s.dialWebsocketInternal(c, s.makeAuthHeader())
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/juju/errors"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -153,7 +154,161 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 		return mm.st.AddOneMachine(template)
 	}
 	if p.ParentId != "" {
+		// The parent may be a new container to be created on an
+		// existing machine, eg "kvm:1".
+		if hostType, hostId, err := instance.ParseNestedContainer(p.ContainerType, p.ParentId); err == nil {
+			return mm.st.AddMachineInsideNewContainer(template, template, hostId, hostType, p.ContainerType)
+		}
 		return mm.st.AddMachineInsideMachine(template, p.ParentId, p.ContainerType)
 	}
 	return mm.st.AddMachineInsideNewMachine(template, template, p.ContainerType)
 }
+
+// PrepareSeriesUpgrade starts the in-place series upgrades of the given
+// machines, asking the units on each machine to run their
+// pre-series-upgrade hooks.
+func (mm *MachineManagerAPI) PrepareSeriesUpgrade(args params.UpgradeSeriesParams) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Params)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, p := range args.Params {
+		m, err := mm.machineFromTag(p.Entity.Tag)
+		if err == nil {
+			err = m.PrepareUpgradeSeries(p.Series)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// CompleteSeriesUpgrade records that the operating systems of the given
+// machines have been upgraded, asking the units on each machine to run
+// their post-series-upgrade hooks.
+func (mm *MachineManagerAPI) CompleteSeriesUpgrade(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		m, err := mm.machineFromTag(entity.Tag)
+		if err == nil {
+			err = m.CompleteUpgradeSeries()
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RebootMachines asks the agents of the given machines to reboot them
+// once no hooks are running on them. Unless args.Force is set, a
+// machine is not rebooted if doing so would leave the environment
+// without a majority of its voting state servers.
+func (mm *MachineManagerAPI) RebootMachines(args params.RebootMachinesParams) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if err := mm.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		err := mm.rebootOneMachine(entity.Tag, args.Force)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) rebootOneMachine(tag string, force bool) error {
+	m, err := mm.machineFromTag(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if m.IsManager() && !force {
+		info, err := mm.st.StateServerInfo()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Rebooting a state server is only safe if the remaining
+		// voting state servers can still form a majority.
+		voters := len(info.VotingMachineIds)
+		if voters-1 <= voters/2 {
+			return errors.Errorf(
+				"cannot reboot %s: the environment would lose its majority of state servers",
+				tag,
+			)
+		}
+	}
+	return m.SetRebootFlag(true)
+}
+
+// RebootStatus returns the progress of the reboots requested for the
+// given machines. The status is empty for machines with no reboot
+// pending.
+func (mm *MachineManagerAPI) RebootStatus(args params.Entities) (params.RebootStatusResults, error) {
+	results := params.RebootStatusResults{
+		Results: make([]params.RebootStatusResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		m, err := mm.machineFromTag(entity.Tag)
+		if err == nil {
+			var status state.RebootStatus
+			status, err = m.RebootStatus()
+			if errors.IsNotFound(err) {
+				err = nil
+			}
+			results.Results[i].Status = params.RebootStatus(status)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ScheduleMachineRemovals schedules the given machines to be destroyed
+// once the grace period has passed, so that a removal made by mistake
+// can be cancelled with CancelMachineRemovals. Each result holds the
+// time at which the machine will be destroyed.
+func (mm *MachineManagerAPI) ScheduleMachineRemovals(args params.ScheduleMachineRemovalsParams) (params.ScheduledRemovalResults, error) {
+	results := params.ScheduledRemovalResults{
+		Results: make([]params.ScheduledRemovalResult, len(args.Entities)),
+	}
+	if err := mm.check.RemoveAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		m, err := mm.machineFromTag(entity.Tag)
+		if err == nil {
+			results.Results[i].Due, err = m.ScheduleDestroy(args.GracePeriod)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// CancelMachineRemovals cancels the scheduled removals of the given
+// machines. Cancelling is never blocked, since it only keeps machines
+// that would otherwise be removed.
+func (mm *MachineManagerAPI) CancelMachineRemovals(args params.Entities) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		m, err := mm.machineFromTag(entity.Tag)
+		if err == nil {
+			err = m.CancelScheduledDestroy()
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+func (mm *MachineManagerAPI) machineFromTag(tag string) (Machine, error) {
+	machineTag, err := names.ParseMachineTag(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return mm.st.Machine(machineTag.Id())
+}
@@ -4,8 +4,10 @@
 package machinemanager_test
 
 import (
-	"errors"
+	"fmt"
+	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -112,16 +114,173 @@ func (s *MachineManagerSuite) TestAddMachinesStateError(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.AddMachinesResults{
 		Machines: []params.AddMachinesResult{{
-			Error: &params.Error{"boom", ""},
+			Error: &params.Error{Message: "boom", Code: ""},
 		}},
 	})
 	c.Assert(s.st.calls, gc.Equals, 1)
 }
 
+func (s *MachineManagerSuite) TestPrepareSeriesUpgrade(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {},
+		"1": {err: errors.New("boom")},
+	}
+	results, err := s.api.PrepareSeriesUpgrade(params.UpgradeSeriesParams{
+		Params: []params.UpgradeSeriesParam{
+			{Entity: params.Entity{Tag: "machine-0"}, Series: "trusty"},
+			{Entity: params.Entity{Tag: "machine-1"}, Series: "trusty"},
+			{Entity: params.Entity{Tag: "machine-2"}, Series: "trusty"},
+			{Entity: params.Entity{Tag: "unit-mysql-0"}, Series: "trusty"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: "machine 2 not found", Code: params.CodeNotFound}},
+			{Error: &params.Error{Message: `"unit-mysql-0" is not a valid machine tag`}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"PrepareUpgradeSeries trusty"})
+}
+
+func (s *MachineManagerSuite) TestCompleteSeriesUpgrade(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {},
+		"1": {err: errors.New("boom")},
+	}
+	results, err := s.api.CompleteSeriesUpgrade(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "boom"}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"CompleteUpgradeSeries"})
+}
+
+func (s *MachineManagerSuite) TestRebootMachines(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {},
+		"1": {err: errors.New("boom")},
+		"2": {manager: true},
+	}
+	s.st.votingMachines = []string{"2"}
+	results, err := s.api.RebootMachines(params.RebootMachinesParams{
+		Entities: []params.Entity{
+			{Tag: "machine-0"},
+			{Tag: "machine-1"},
+			{Tag: "machine-2"},
+			{Tag: "machine-3"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: "cannot reboot machine-2: the environment would lose its majority of state servers"}},
+			{Error: &params.Error{Message: "machine 3 not found", Code: params.CodeNotFound}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"SetRebootFlag true"})
+	c.Assert(s.st.upgradeMachines["2"].calls, gc.HasLen, 0)
+}
+
+func (s *MachineManagerSuite) TestRebootMachinesStateServers(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {manager: true},
+	}
+	s.st.votingMachines = []string{"0", "1", "2"}
+	results, err := s.api.RebootMachines(params.RebootMachinesParams{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"SetRebootFlag true"})
+}
+
+func (s *MachineManagerSuite) TestRebootMachinesForce(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {manager: true},
+	}
+	s.st.votingMachines = []string{"0"}
+	results, err := s.api.RebootMachines(params.RebootMachinesParams{
+		Entities: []params.Entity{{Tag: "machine-0"}},
+		Force:    true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"SetRebootFlag true"})
+}
+
+func (s *MachineManagerSuite) TestRebootStatus(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {rebootStatus: state.RebootDraining},
+		"1": {},
+	}
+	results, err := s.api.RebootStatus(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.RebootStatusResults{
+		Results: []params.RebootStatusResult{
+			{Status: params.RebootDraining},
+			{},
+			{Error: &params.Error{Message: "machine 2 not found", Code: params.CodeNotFound}},
+		},
+	})
+}
+
+func (s *MachineManagerSuite) TestScheduleMachineRemovals(c *gc.C) {
+	due := time.Date(2015, 10, 14, 14, 0, 0, 0, time.UTC)
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {due: due},
+		"1": {err: errors.New("boom")},
+	}
+	results, err := s.api.ScheduleMachineRemovals(params.ScheduleMachineRemovalsParams{
+		Entities:    []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}, {Tag: "machine-2"}},
+		GracePeriod: 2 * time.Hour,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ScheduledRemovalResults{
+		Results: []params.ScheduledRemovalResult{
+			{Due: due},
+			{Error: &params.Error{Message: "boom"}},
+			{Error: &params.Error{Message: "machine 2 not found", Code: params.CodeNotFound}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"ScheduleDestroy 2h0m0s"})
+}
+
+func (s *MachineManagerSuite) TestCancelMachineRemovals(c *gc.C) {
+	s.st.upgradeMachines = map[string]*mockMachine{
+		"0": {},
+		"1": {err: errors.NotFoundf("scheduled removal of machine 1")},
+	}
+	results, err := s.api.CancelMachineRemovals(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-0"}, {Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "scheduled removal of machine 1 not found", Code: params.CodeNotFound}},
+		},
+	})
+	c.Assert(s.st.upgradeMachines["0"].calls, jc.DeepEquals, []string{"CancelScheduledDestroy"})
+}
+
 type mockState struct {
-	calls    int
-	machines []state.MachineTemplate
-	err      error
+	calls           int
+	machines        []state.MachineTemplate
+	upgradeMachines map[string]*mockMachine
+	votingMachines  []string
+	err             error
 }
 
 func (st *mockState) AddOneMachine(template state.MachineTemplate) (*state.Machine, error) {
@@ -151,6 +310,69 @@ func (st *mockState) AddMachineInsideMachine(template state.MachineTemplate, par
 	panic("not implemented")
 }
 
+func (st *mockState) AddMachineInsideNewContainer(template, parentTemplate state.MachineTemplate, hostId string, hostType, containerType instance.ContainerType) (*state.Machine, error) {
+	panic("not implemented")
+}
+
+func (st *mockState) Machine(id string) (machinemanager.Machine, error) {
+	m, ok := st.upgradeMachines[id]
+	if !ok {
+		return nil, errors.NotFoundf("machine %s", id)
+	}
+	return m, nil
+}
+
+func (st *mockState) StateServerInfo() (*state.StateServerInfo, error) {
+	return &state.StateServerInfo{VotingMachineIds: st.votingMachines}, nil
+}
+
+type mockMachine struct {
+	calls        []string
+	manager      bool
+	rebootStatus state.RebootStatus
+	due          time.Time
+	err          error
+}
+
+func (m *mockMachine) PrepareUpgradeSeries(series string) error {
+	m.calls = append(m.calls, "PrepareUpgradeSeries "+series)
+	return m.err
+}
+
+func (m *mockMachine) CompleteUpgradeSeries() error {
+	m.calls = append(m.calls, "CompleteUpgradeSeries")
+	return m.err
+}
+
+func (m *mockMachine) IsManager() bool {
+	return m.manager
+}
+
+func (m *mockMachine) SetRebootFlag(flag bool) error {
+	m.calls = append(m.calls, fmt.Sprintf("SetRebootFlag %v", flag))
+	if m.err == nil && flag {
+		m.rebootStatus = state.RebootPending
+	}
+	return m.err
+}
+
+func (m *mockMachine) RebootStatus() (state.RebootStatus, error) {
+	if m.rebootStatus == "" {
+		return "", errors.NotFoundf("reboot request")
+	}
+	return m.rebootStatus, nil
+}
+
+func (m *mockMachine) ScheduleDestroy(gracePeriod time.Duration) (time.Time, error) {
+	m.calls = append(m.calls, fmt.Sprintf("ScheduleDestroy %v", gracePeriod))
+	return m.due, m.err
+}
+
+func (m *mockMachine) CancelScheduledDestroy() error {
+	m.calls = append(m.calls, "CancelScheduledDestroy")
+	return m.err
+}
+
 type mockBlock struct{}
 
 func (st *mockBlock) Id() string {
@@ -4,6 +4,8 @@
 package machinemanager
 
 import (
+	"time"
+
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -16,6 +18,21 @@ type stateInterface interface {
 	AddOneMachine(template state.MachineTemplate) (*state.Machine, error)
 	AddMachineInsideNewMachine(template, parentTemplate state.MachineTemplate, containerType instance.ContainerType) (*state.Machine, error)
 	AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error)
+	AddMachineInsideNewContainer(template, parentTemplate state.MachineTemplate, hostId string, hostType, containerType instance.ContainerType) (*state.Machine, error)
+	Machine(id string) (Machine, error)
+	StateServerInfo() (*state.StateServerInfo, error)
+}
+
+// Machine describes the operations on a machine used by the
+// MachineManager facade.
+type Machine interface {
+	PrepareUpgradeSeries(series string) error
+	CompleteUpgradeSeries() error
+	IsManager() bool
+	SetRebootFlag(flag bool) error
+	RebootStatus() (state.RebootStatus, error)
+	ScheduleDestroy(gracePeriod time.Duration) (time.Time, error)
+	CancelScheduledDestroy() error
 }
 
 type stateShim struct {
@@ -45,3 +62,15 @@ func (s stateShim) AddMachineInsideNewMachine(template, parentTemplate state.Mac
 func (s stateShim) AddMachineInsideMachine(template state.MachineTemplate, parentId string, containerType instance.ContainerType) (*state.Machine, error) {
 	return s.State.AddMachineInsideMachine(template, parentId, containerType)
 }
+
+func (s stateShim) AddMachineInsideNewContainer(template, parentTemplate state.MachineTemplate, hostId string, hostType, containerType instance.ContainerType) (*state.Machine, error) {
+	return s.State.AddMachineInsideNewContainer(template, parentTemplate, hostId, hostType, containerType)
+}
+
+func (s stateShim) Machine(id string) (Machine, error) {
+	return s.State.Machine(id)
+}
+
+func (s stateShim) StateServerInfo() (*state.StateServerInfo, error) {
+	return s.State.StateServerInfo()
+}
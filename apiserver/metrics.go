@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/introspection"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// serverMetrics holds the metrics recorded by the API server.
+type serverMetrics struct {
+	connections *introspection.Gauge
+	requests    *introspection.HistogramVec
+}
+
+// registerMetrics creates the server's metrics and registers them,
+// along with metrics describing the state it serves, in the given
+// registry.
+func (srv *Server) registerMetrics(registry *introspection.Registry) {
+	srv.metrics = &serverMetrics{
+		connections: introspection.NewGauge(
+			"juju_apiserver_connections",
+			"Current API connections.",
+		),
+		requests: introspection.NewHistogramVec(
+			"juju_apiserver_request_duration_seconds",
+			"Time taken to serve API requests, by facade and method.",
+			[]string{"facade", "method"},
+			introspection.DefaultLatencyBuckets,
+		),
+	}
+	registry.Register(srv.metrics.connections)
+	registry.Register(srv.metrics.requests)
+	registry.Register(introspection.GaugeFunc(
+		"juju_apiserver_watchers",
+		"Watchers held by current API connections.",
+		func() float64 { return float64(srv.watcherCount()) },
+	))
+	registry.Register(introspection.CollectorFunc(func() []introspection.Family {
+		return []introspection.Family{{
+			Name:    "juju_state_transactions_total",
+			Help:    "Transactions run against the state database.",
+			Type:    introspection.CounterType,
+			Samples: []introspection.Sample{{Value: float64(state.TransactionCount())}},
+		}}
+	}))
+}
+
+// watcherCount returns the number of watchers held by the server's
+// current connections.
+func (srv *Server) watcherCount() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	count := 0
+	for h := range srv.handlers {
+		count += h.getResources().WatcherCount()
+	}
+	return count
+}
+
+// addHandler records a connection's handler while it is being served.
+func (srv *Server) addHandler(h *apiHandler) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.handlers[h] = true
+}
+
+// removeHandler forgets a handler recorded by addHandler.
+func (srv *Server) removeHandler(h *apiHandler) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	delete(srv.handlers, h)
+}
+
+// metricsNotifier records the time taken by each API request,
+// passing all notifications to the next notifier, if any.
+type metricsNotifier struct {
+	next    rpc.RequestNotifier
+	metrics *serverMetrics
+}
+
+func (n *metricsNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ServerRequest(hdr, body)
+	}
+}
+
+func (n *metricsNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	n.metrics.requests.ObserveDuration(timeSpent, req.Type, req.Action)
+	if n.next != nil {
+		n.next.ServerReply(req, hdr, body, timeSpent)
+	}
+}
+
+func (n *metricsNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientRequest(hdr, body)
+	}
+}
+
+func (n *metricsNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientReply(req, hdr, body)
+	}
+}
+
+// metricsHandler serves the metrics in the server's registry to the
+// owner of the state server environment.
+type metricsHandler struct {
+	httpHandler
+	registry *introspection.Registry
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(req)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+	tag, err := stateWrapper.authenticate(req)
+	if err != nil {
+		h.authError(w, h)
+		return
+	}
+	if err := h.checkOwner(tag); err != nil {
+		h.sendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if req.Method != "GET" {
+		h.sendError(w, http.StatusMethodNotAllowed, "unsupported method: "+req.Method)
+		return
+	}
+	introspection.Handler(h.registry).ServeHTTP(w, req)
+}
+
+// checkOwner returns an error unless the given tag is that of the
+// owner of the state server environment.
+func (h *metricsHandler) checkOwner(tag names.Tag) error {
+	stateServerEnv, err := h.ssState.StateServerEnvironment()
+	if err != nil {
+		return err
+	}
+	if tag != stateServerEnv.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// sendError sends a plain text error response.
+func (h *metricsHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	http.Error(w, message, statusCode)
+}
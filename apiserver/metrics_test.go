@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/introspection"
+)
+
+type metricsSuite struct {
+	userAuthHttpSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) metricsURL(c *gc.C) string {
+	return s.makeURL(c, "https", "/metrics", nil).String()
+}
+
+func (s *metricsSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusUnauthorized)
+}
+
+func (s *metricsSuite) TestRequiresStateServerOwner(c *gc.C) {
+	resp, err := s.authRequest(c, "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
+}
+
+func (s *metricsSuite) TestRequiresGET(c *gc.C) {
+	info := s.APIInfo(c)
+	resp, err := s.sendRequest(c, info.Tag.String(), info.Password, "POST", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+}
+
+func (s *metricsSuite) TestServesMetrics(c *gc.C) {
+	// Make an API request so that there are request metrics.
+	_, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info := s.APIInfo(c)
+	resp, err := s.sendRequest(c, info.Tag.String(), info.Password, "GET", s.metricsURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, introspection.TextContentType)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(body), gc.Matches, `(?s).*# TYPE juju_apiserver_connections gauge\njuju_apiserver_connections [1-9][0-9]*\n.*`)
+	c.Assert(string(body), gc.Matches, `(?s).*juju_apiserver_request_duration_seconds_count{facade="Client",method="FullStatus"} [1-9][0-9]*\n.*`)
+	c.Assert(string(body), gc.Matches, `(?s).*# TYPE juju_apiserver_watchers gauge\n.*`)
+	c.Assert(string(body), gc.Matches, `(?s).*# TYPE juju_state_transactions_total counter\njuju_state_transactions_total [1-9][0-9]*\n.*`)
+}
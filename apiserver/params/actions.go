@@ -103,6 +103,29 @@ type ActionExecutionResult struct {
 	Message   string                 `json:"message,omitempty"`
 }
 
+// ActionOutputChunks holds a slice of ActionOutputChunk for a bulk
+// call to record the output of running actions.
+type ActionOutputChunks struct {
+	Chunks []ActionOutputChunk `json:"chunks,omitempty"`
+}
+
+// ActionOutputChunk holds output written by a running action to one of
+// its output streams.
+type ActionOutputChunk struct {
+	ActionTag string `json:"actiontag"`
+	Stream    string `json:"stream"`
+	Data      string `json:"data"`
+}
+
+// ActionOutputMessage is sent over the action output websocket for each
+// chunk of output recorded for an action.
+type ActionOutputMessage struct {
+	Seq       int       `json:"seq"`
+	Stream    string    `json:"stream"`
+	Data      string    `json:"data"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // ServicesCharmActionsResults holds a slice of ServiceCharmActionsResult for
 // a bulk result of charm Actions for Services.
 type ServicesCharmActionsResults struct {
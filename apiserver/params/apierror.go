@@ -4,6 +4,7 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/juju/errors"
@@ -15,6 +16,11 @@ import (
 type Error struct {
 	Message string
 	Code    string
+
+	// Info holds structured data about the error, such as the
+	// limit and current usage of an exceeded quota. What it holds
+	// depends on the code; see UnmarshalInfo.
+	Info map[string]interface{} `json:",omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -25,11 +31,31 @@ func (e *Error) ErrorCode() string {
 	return e.Code
 }
 
-var _ rpc.ErrorCoder = (*Error)(nil)
+func (e *Error) ErrorInfo() map[string]interface{} {
+	return e.Info
+}
+
+// UnmarshalInfo fills in the value pointed to by v, which is
+// usually one of the *Info types below, from the error's Info.
+func (e *Error) UnmarshalInfo(v interface{}) error {
+	data, err := json.Marshal(e.Info)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(data, v))
+}
+
+var (
+	_ rpc.ErrorCoder  = (*Error)(nil)
+	_ rpc.ErrorInfoer = (*Error)(nil)
+)
 
 // GoString implements fmt.GoStringer.  It means that a *Error shows its
 // contents correctly when printed with %#v.
 func (e Error) GoString() string {
+	if e.Info != nil {
+		return fmt.Sprintf("&params.Error{%q, %q, %#v}", e.Code, e.Message, e.Info)
+	}
 	return fmt.Sprintf("&params.Error{%q, %q}", e.Code, e.Message)
 }
 
@@ -54,8 +80,22 @@ const (
 	CodeActionNotAvailable    = "action no longer available"
 	CodeOperationBlocked      = "operation is blocked"
 	CodeLeadershipClaimDenied = "leadership claim denied"
+	CodeQuotaExceeded         = "quota exceeded"
+	CodeSecondFactorRequired  = "second factor required"
 )
 
+// QuotaExceededInfo holds the Info of an error with
+// CodeQuotaExceeded.
+type QuotaExceededInfo struct {
+	// Resource names what the quota limits, such as "machines".
+	Resource string `json:"resource"`
+
+	// Limit holds the quota, and Current how much of the
+	// resource was in use when the request was refused.
+	Limit   int `json:"limit"`
+	Current int `json:"current"`
+}
+
 // ErrCode returns the error code associated with
 // the given error, or the empty string if there
 // is none.
@@ -67,6 +107,31 @@ func ErrCode(err error) string {
 	return ""
 }
 
+// ErrInfo returns the structured data associated with
+// the given error, or nil if there is none.
+func ErrInfo(err error) map[string]interface{} {
+	err = errors.Cause(err)
+	if err, _ := err.(rpc.ErrorInfoer); err != nil {
+		return err.ErrorInfo()
+	}
+	return nil
+}
+
+// ErrQuotaExceededInfo returns the details of the quota
+// that the given error reports was exceeded, and whether
+// the error has CodeQuotaExceeded at all.
+func ErrQuotaExceededInfo(err error) (QuotaExceededInfo, bool) {
+	var info QuotaExceededInfo
+	if !IsCodeQuotaExceeded(err) {
+		return info, false
+	}
+	perr := &Error{Info: ErrInfo(err)}
+	if err := perr.UnmarshalInfo(&info); err != nil {
+		return info, false
+	}
+	return info, true
+}
+
 // ClientError maps errors returned from an RPC call into local errors with
 // appropriate values.
 func ClientError(err error) error {
@@ -81,6 +146,7 @@ func ClientError(err error) error {
 	return &Error{
 		Message: rerr.Message,
 		Code:    rerr.Code,
+		Info:    rerr.Info,
 	}
 }
 
@@ -168,3 +234,11 @@ func IsCodeOperationBlocked(err error) bool {
 func IsCodeLeadershipClaimDenied(err error) bool {
 	return ErrCode(err) == CodeLeadershipClaimDenied
 }
+
+func IsCodeQuotaExceeded(err error) bool {
+	return ErrCode(err) == CodeQuotaExceeded
+}
+
+func IsCodeSecondFactorRequired(err error) bool {
+	return ErrCode(err) == CodeSecondFactorRequired
+}
@@ -5,9 +5,11 @@ package params_test
 
 import (
 	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
 )
 
 type errorSuite struct{}
@@ -22,3 +24,30 @@ func (*errorSuite) TestErrCode(c *gc.C) {
 	err = errors.Trace(err)
 	c.Check(params.ErrCode(err), gc.Equals, params.CodeDead)
 }
+
+func (*errorSuite) TestErrInfo(c *gc.C) {
+	var err error = &params.Error{
+		Code:    params.CodeQuotaExceeded,
+		Message: "machines quota exceeded",
+		Info:    map[string]interface{}{"resource": "machines", "limit": 10.0, "current": 10.0},
+	}
+	err = errors.Trace(err)
+	c.Check(params.ErrInfo(err), jc.DeepEquals, map[string]interface{}{
+		"resource": "machines", "limit": 10.0, "current": 10.0,
+	})
+	info, ok := params.ErrQuotaExceededInfo(err)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(info, gc.Equals, params.QuotaExceededInfo{Resource: "machines", Limit: 10, Current: 10})
+
+	err = &params.Error{Code: params.CodeNotFound, Message: "not found"}
+	c.Check(params.ErrInfo(err), gc.IsNil)
+	_, ok = params.ErrQuotaExceededInfo(err)
+	c.Assert(ok, jc.IsFalse)
+	c.Check(params.ErrInfo(errors.New("plain")), gc.IsNil)
+}
+
+func (*errorSuite) TestClientErrorKeepsInfo(c *gc.C) {
+	info := map[string]interface{}{"limit": 3.0}
+	err := params.ClientError(&rpc.RequestError{Message: "m", Code: "c", Info: info})
+	c.Assert(err, jc.DeepEquals, &params.Error{Message: "m", Code: "c", Info: info})
+}
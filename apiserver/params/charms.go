@@ -17,3 +17,36 @@ type CharmsList struct {
 type CharmsListResult struct {
 	CharmURLs []string
 }
+
+// ValidateCharmArgs holds the arguments for a
+// CharmValidation.ValidateCharm call.
+type ValidateCharmArgs struct {
+	// Archive holds the zipped charm to validate.
+	Archive []byte
+
+	// Series holds the series the charm is to be deployed on. The
+	// environment's default series is used when it is empty.
+	Series string
+}
+
+// CharmCompatibilityWarning describes a feature used by a charm that
+// the controller does not support.
+type CharmCompatibilityWarning struct {
+	// Kind holds the kind of feature: one of "series", "metadata",
+	// "resource", "storage" or "hook".
+	Kind string
+
+	// Name holds the name of the feature, such as the name of the
+	// store or hook.
+	Name string
+
+	Message string
+}
+
+// ValidateCharmResult holds the result of a
+// CharmValidation.ValidateCharm call.
+type ValidateCharmResult struct {
+	// Series holds the series the charm was validated against.
+	Series   string
+	Warnings []CharmCompatibilityWarning
+}
@@ -21,6 +21,26 @@ const (
 	ShouldShutdown RebootAction = "shutdown"
 )
 
+// RebootStatus describes the progress of a requested machine reboot.
+type RebootStatus string
+
+const (
+	RebootPending  RebootStatus = "pending"
+	RebootDraining RebootStatus = "draining"
+	RebootStarted  RebootStatus = "rebooting"
+)
+
+// UpgradeSeriesStatus describes the progress of an in-place series
+// upgrade of a machine, or of a unit's part in it.
+type UpgradeSeriesStatus string
+
+const (
+	UpgradeSeriesPrepareStarted   UpgradeSeriesStatus = "prepare-started"
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare-completed"
+	UpgradeSeriesCompleteStarted  UpgradeSeriesStatus = "complete-started"
+	UpgradeSeriesCompleted        UpgradeSeriesStatus = "completed"
+)
+
 // ResolvedMode describes the way state transition errors
 // are resolved.
 type ResolvedMode string
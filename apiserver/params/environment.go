@@ -15,6 +15,61 @@ type EnvironmentConfigResults struct {
 	Config map[string]interface{}
 }
 
+// RevealConfigSecrets holds the arguments for the ConfigSecrets
+// Reveal API call. If Keys is empty, all secret attributes are
+// revealed.
+type RevealConfigSecrets struct {
+	Keys []string
+}
+
+// CloudCredential holds a set of provider credentials that are stored
+// by the state server and referenced by environments.
+type CloudCredential struct {
+	Name         string
+	ProviderType string
+	Attributes   map[string]string
+}
+
+// CloudCredentials holds the arguments for the Credentials facade's
+// AddCredential and UpdateCredential API calls.
+type CloudCredentials struct {
+	Credentials []CloudCredential
+}
+
+// CloudCredentialInfo describes a cloud credential without revealing
+// the values of its attributes.
+type CloudCredentialInfo struct {
+	Name           string
+	OwnerTag       string
+	ProviderType   string
+	AttributeNames []string
+}
+
+// CloudCredentialInfoResults holds the result of the Credentials
+// facade's ListCredentials API call.
+type CloudCredentialInfoResults struct {
+	Credentials []CloudCredentialInfo
+}
+
+// CloudRegion describes a region of a cloud.
+type CloudRegion struct {
+	Name     string
+	Endpoint string `json:",omitempty"`
+}
+
+// Cloud describes a cloud that environments may be hosted in.
+type Cloud struct {
+	Name         string
+	ProviderType string
+	Regions      []CloudRegion `json:",omitempty"`
+}
+
+// Clouds holds the arguments for the Clouds facade's AddClouds API
+// call, and the result of its ListClouds API call.
+type Clouds struct {
+	Clouds []Cloud
+}
+
 // EnvironmentSet contains the arguments for EnvironmentSet client API
 // call.
 type EnvironmentSet struct {
@@ -53,6 +108,56 @@ type SetEnvironAgentVersion struct {
 	Version version.Number
 }
 
+// RunUpgradeChecks contains the arguments for the UpgradeChecks
+// facade's Run call.
+type RunUpgradeChecks struct {
+	Version version.Number
+}
+
+// UpgradeCheckResult holds the outcome of a single pre-upgrade check.
+type UpgradeCheckResult struct {
+	Name        string
+	Description string
+	Error       *Error
+}
+
+// UpgradeCheckResults holds the outcomes of the pre-upgrade checks.
+type UpgradeCheckResults struct {
+	Results []UpgradeCheckResult
+}
+
+// UpgradeInfo describes how the state servers are synchronising the
+// upgrade that is in progress.
+type UpgradeInfo struct {
+	PreviousVersion     version.Number
+	TargetVersion       version.Number
+	Status              string
+	Started             time.Time
+	StateServersReady   []string
+	StateServersPending []string
+	StateServersDone    []string
+}
+
+// StartAgentRollout contains the arguments for the StartAgentRollout
+// client API call.
+type StartAgentRollout struct {
+	Version    version.Number
+	Canaries   []string
+	BakePeriod time.Duration
+}
+
+// AgentRolloutStatus describes a staged agent upgrade.
+type AgentRolloutStatus struct {
+	PreviousVersion version.Number
+	TargetVersion   version.Number
+	Canaries        []string
+	BakePeriod      time.Duration
+	Phase           string
+	Started         time.Time
+	BakeStarted     *time.Time
+	Message         string
+}
+
 // EnvUserInfo holds information on a user.
 type EnvUserInfo struct {
 	UserName       string     `json:"user"`
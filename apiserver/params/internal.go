@@ -105,6 +105,12 @@ type EnvironmentCreateArgs struct {
 	// environment.  An environment UUID is allocated by the API server during
 	// the creation of the environment.
 	Config map[string]interface{}
+
+	// Cloud and CloudRegion name the cloud and region the environment
+	// is to be hosted in. If Cloud is empty, the environment is hosted
+	// in the same cloud as the state server.
+	Cloud       string `json:",omitempty"`
+	CloudRegion string `json:",omitempty"`
 }
 
 // Environment holds the result of an API call returning a name and UUID
@@ -121,6 +127,114 @@ type EnvironmentList struct {
 	Environments []Environment
 }
 
+// HostedEnvironment summarises an environment hosted by a system,
+// with the number of machines and services still in it.
+type HostedEnvironment struct {
+	Name         string
+	UUID         string
+	OwnerTag     string
+	Life         Life
+	MachineCount int
+	ServiceCount int
+}
+
+// HostedEnvironmentList holds the summaries of the environments
+// hosted by a system.
+type HostedEnvironmentList struct {
+	Environments []HostedEnvironment
+}
+
+// EnvironmentSummary holds the number of machines, services and units
+// in an environment.
+type EnvironmentSummary struct {
+	Name         string
+	UUID         string
+	OwnerTag     string
+	MachineCount int
+	ServiceCount int
+	UnitCount    int
+}
+
+// EnvironmentSummaryList holds the summaries of every environment in
+// a system.
+type EnvironmentSummaryList struct {
+	Environments []EnvironmentSummary
+}
+
+// EnvironmentInvitation holds the details of a pending invitation for
+// a user to access an environment.
+type EnvironmentInvitation struct {
+	EnvironTag  string
+	EnvironName string
+	UserTag     string
+	CreatedBy   string
+	DateCreated time.Time
+}
+
+// EnvironmentInvitationList holds a list of pending invitations.
+type EnvironmentInvitationList struct {
+	Invitations []EnvironmentInvitation
+}
+
+// TransferEnvironmentOwnership holds the arguments for transferring
+// the ownership of an environment to another user.
+type TransferEnvironmentOwnership struct {
+	EnvironTag string
+	OwnerTag   string
+
+	// RevokePrevious, if true, removes the previous owner's access
+	// to the environment.
+	RevokePrevious bool
+}
+
+// PublicAPIAddresses holds the host:port addresses that agents and
+// clients are told to use to connect to the API servers, in place of
+// the addresses of the API server instances.
+type PublicAPIAddresses struct {
+	Addresses []string
+}
+
+// MigratedUser describes a user with access to an environment, as
+// exported from one system to be imported into another. Credentials
+// are never exported.
+type MigratedUser struct {
+	UserName    string
+	DisplayName string
+	CreatedBy   string
+	DateCreated time.Time
+	Disabled    bool
+}
+
+// MigratedUsers holds the users with access to an environment, as
+// returned by ExportEnvironmentUsers.
+type MigratedUsers struct {
+	EnvironTag string
+	Users      []MigratedUser
+}
+
+// ImportEnvironmentUsers holds the parameters for giving migrated users
+// access to an environment. UserMap maps the names of migrated users to
+// the names of existing users to import them as.
+type ImportEnvironmentUsers struct {
+	EnvironTag string
+	Users      []MigratedUser
+	UserMap    map[string]string
+}
+
+// UserImportResult reports the outcome of importing a migrated user.
+// If Conflict is not empty, the user was not given access.
+type UserImportResult struct {
+	UserName string
+	Target   string
+	Created  bool
+	Conflict string
+}
+
+// UserImportResults holds the outcomes of importing migrated users.
+type UserImportResults struct {
+	Results []UserImportResult
+}
+
 // ResolvedModeResult holds a resolved mode or an error.
 type ResolvedModeResult struct {
 	Error *Error
@@ -159,6 +273,19 @@ type BoolResults struct {
 	Results []BoolResult
 }
 
+// ExposedEndpointsResult holds the exposed endpoints of a service,
+// or an error.
+type ExposedEndpointsResult struct {
+	Error     *Error
+	Endpoints map[string]ExposedEndpoint
+}
+
+// ExposedEndpointsResults holds multiple results with
+// ExposedEndpointsResult each.
+type ExposedEndpointsResults struct {
+	Results []ExposedEndpointsResult
+}
+
 // Settings holds relation settings names and values.
 type Settings map[string]string
 
@@ -227,6 +354,20 @@ type RelationUnitPairs struct {
 	RelationUnitPairs []RelationUnitPair
 }
 
+// RelationUnitService holds a relation tag, the tag of a unit in the
+// relation and the tag of a service in the relation.
+type RelationUnitService struct {
+	Relation string
+	Unit     string
+	Service  string
+}
+
+// RelationUnitServices holds the parameters for API calls expecting
+// multiple sets of a relation tag, a unit tag and a service tag.
+type RelationUnitServices struct {
+	RelationUnitServices []RelationUnitService
+}
+
 // RelationUnitSettings holds a relation tag, a unit tag and local
 // unit settings.
 type RelationUnitSettings struct {
@@ -553,6 +694,10 @@ type ProvisioningInfo struct {
 	Networks    []string
 	Jobs        []multiwatcher.MachineJob
 	Volumes     []VolumeParams
+
+	// DistributionGroupName is the name of the group of machines
+	// across which the provider should distribute the instance.
+	DistributionGroupName string `json:",omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -614,3 +759,67 @@ type MeterStatusResult struct {
 type MeterStatusResults struct {
 	Results []MeterStatusResult
 }
+
+// AgentWorkerReport describes a worker running in an agent.
+type AgentWorkerReport struct {
+	Name       string
+	State      string
+	Inputs     []string
+	StartCount int
+	LastError  string
+}
+
+// AgentWorkerReportsResult holds the reports on the workers of an
+// agent, or an error.
+type AgentWorkerReportsResult struct {
+	Reports []AgentWorkerReport
+	Error   *Error
+}
+
+// AgentWorkerReportsResults holds the results of a WorkerReports call.
+type AgentWorkerReportsResults struct {
+	Results []AgentWorkerReportsResult
+}
+
+// ProvisioningErrorsArgs holds the arguments for a ProvisioningErrors
+// call.
+type ProvisioningErrorsArgs struct {
+	// Threshold holds how long a machine must have been in a
+	// provisioning error for it to be reported.
+	Threshold time.Duration
+}
+
+// ProviderErrorStatusKey is the key of the machine status data that
+// holds the raw error reported by the provider when an instance could
+// not be started for the machine.
+const ProviderErrorStatusKey = "provider-error"
+
+// ProvisioningError describes a machine stuck in a provisioning error.
+type ProvisioningError struct {
+	MachineTag string
+	Message    string
+	Data       map[string]interface{}
+	Since      time.Time
+}
+
+// ProvisioningErrorsResult holds the results of a ProvisioningErrors
+// call.
+type ProvisioningErrorsResult struct {
+	Errors []ProvisioningError
+}
+
+// UnitUtilizationSample holds a sample of the resources used by the
+// processes of a unit, as measured on the unit's machine.
+type UnitUtilizationSample struct {
+	UnitTag     string
+	Time        time.Time
+	CPUPercent  float64
+	MemoryBytes uint64
+	Processes   int
+}
+
+// UnitUtilizationSamples holds the arguments for a SetUnitUtilization
+// call.
+type UnitUtilizationSamples struct {
+	Samples []UnitUtilizationSample
+}
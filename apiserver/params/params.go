@@ -101,6 +101,10 @@ type ErrorResult struct {
 // The endpoints specified are unordered.
 type AddRelation struct {
 	Endpoints []string
+
+	// IdempotencyToken, if set, identifies the call so that it
+	// is not made twice if the client retries it.
+	IdempotencyToken string `json:",omitempty"`
 }
 
 // AddRelationResults holds the results of a AddRelation call. The Endpoints
@@ -166,6 +170,10 @@ type AddMachineParams struct {
 // AddMachinesWithPlacement call.
 type AddMachines struct {
 	MachineParams []AddMachineParams `json:"MachineParams"`
+
+	// IdempotencyToken, if set, identifies the call so that the
+	// machines are not added twice if the client retries it.
+	IdempotencyToken string `json:"IdempotencyToken,omitempty"`
 }
 
 // AddMachinesResults holds the results of an AddMachines call.
@@ -186,6 +194,22 @@ type DestroyMachines struct {
 	Force        bool
 }
 
+// DestroyEnvironment holds the parameters for making the
+// Client.DestroyEnvironment call.
+type DestroyEnvironment struct {
+	// DestroyStorage says what should become of the environment's
+	// persistent volumes: if true they are destroyed, and if false
+	// they are released, which detaches them and leaves them in the
+	// cloud. If it is nil, the environment cannot be destroyed while
+	// it has any persistent volumes.
+	DestroyStorage *bool `json:",omitempty"`
+
+	// Force holds the kinds of blocker, such as "manual-machines" or
+	// "persistent-storage", that should not prevent the environment
+	// from being destroyed.
+	Force []string `json:",omitempty"`
+}
+
 // ServicesDeploy holds the parameters for deploying one or more services.
 type ServicesDeploy struct {
 	Services []ServiceDeploy
@@ -202,6 +226,10 @@ type ServiceDeploy struct {
 	ToMachineSpec string
 	Networks      []string
 	Storage       map[string]storage.Constraints
+
+	// IdempotencyToken, if set, identifies the call so that the
+	// service is not deployed twice if the client retries it.
+	IdempotencyToken string `json:",omitempty"`
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -258,10 +286,40 @@ type ServiceGet struct {
 
 // ServiceGetResults holds results of the ServiceGet call.
 type ServiceGetResults struct {
-	Service     string
-	Charm       string
-	Config      map[string]interface{}
-	Constraints constraints.Value
+	Service        string
+	Charm          string
+	Config         map[string]interface{}
+	Constraints    constraints.Value
+	ConfigRevision int
+}
+
+// ServiceConfigRevisionArgs holds the parameters for making the
+// ServiceGetConfigRevision and ServiceRollbackConfig calls.
+type ServiceConfigRevisionArgs struct {
+	ServiceName string
+	Revision    int
+}
+
+// ServiceConfigChange describes a change made to a single service
+// configuration setting. Type is one of "added", "modified" or
+// "deleted".
+type ServiceConfigChange struct {
+	Type     string
+	Key      string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// ServiceConfigRevision holds the results of the
+// ServiceGetConfigRevision call: a recorded revision of a service's
+// configuration settings.
+type ServiceConfigRevision struct {
+	Service   string
+	Revision  int
+	ChangedBy string
+	Changed   time.Time
+	Changes   []ServiceConfigChange
+	Settings  map[string]interface{}
 }
 
 // ServiceCharmRelations holds parameters for making the ServiceCharmRelations call.
@@ -290,6 +348,51 @@ type ServiceMetricCredentials struct {
 	Creds []ServiceMetricCredential
 }
 
+// ServiceAddressPolicy holds parameters for the SetAddressPolicies call.
+type ServiceAddressPolicy struct {
+	ServiceName string
+	Policy      string
+}
+
+// ServiceAddressPolicies holds multiple ServiceAddressPolicy parameters.
+type ServiceAddressPolicies struct {
+	Policies []ServiceAddressPolicy
+}
+
+// ExposedEndpoint holds the ports and source networks to which the
+// exposure of a service endpoint is restricted.
+type ExposedEndpoint struct {
+	Ports []string `json:",omitempty"`
+	CIDRs []string `json:",omitempty"`
+}
+
+// ServiceExposeEndpoints holds parameters for the ExposeEndpoints
+// call. The endpoints replace any already exposed endpoints of the
+// same name.
+type ServiceExposeEndpoints struct {
+	ServiceName string
+	Endpoints   map[string]ExposedEndpoint
+}
+
+// ServicesExposeEndpoints holds multiple ServiceExposeEndpoints
+// parameters.
+type ServicesExposeEndpoints struct {
+	Services []ServiceExposeEndpoints
+}
+
+// ServiceUnexposeEndpoints holds parameters for the UnexposeEndpoints
+// call.
+type ServiceUnexposeEndpoints struct {
+	ServiceName string
+	Endpoints   []string
+}
+
+// ServicesUnexposeEndpoints holds multiple ServiceUnexposeEndpoints
+// parameters.
+type ServicesUnexposeEndpoints struct {
+	Services []ServiceUnexposeEndpoints
+}
+
 // PublicAddress holds parameters for the PublicAddress call.
 type PublicAddress struct {
 	Target string
@@ -310,12 +413,45 @@ type PrivateAddressResults struct {
 	PrivateAddress string
 }
 
+// SSHHostKeysTarget holds parameters for the SSHHostKeys call.
+type SSHHostKeysTarget struct {
+	Target string
+}
+
+// SSHHostKeysResults holds results of the SSHHostKeys call.
+type SSHHostKeysResults struct {
+	PublicKeys []string
+}
+
+// SSHHostKeys holds the SSH host keys of an entity.
+type SSHHostKeys struct {
+	Tag        string
+	PublicKeys []string
+}
+
+// SSHHostKeySet holds the SSH host keys reported by a set of entities.
+type SSHHostKeySet struct {
+	EntityKeys []SSHHostKeys
+}
+
 // Resolved holds parameters for the Resolved call.
 type Resolved struct {
 	UnitName string
 	Retry    bool
 }
 
+// ResolveUnits holds the parameters for making the ResolveUnits call.
+type ResolveUnits struct {
+	Units []ResolveUnit
+}
+
+// ResolveUnit identifies a unit in an error state to be resolved, and
+// whether its failed hook should be retried.
+type ResolveUnit struct {
+	UnitTag string
+	Retry   bool
+}
+
 // ResolvedResults holds results of the Resolved call.
 type ResolvedResults struct {
 	Service  string
@@ -336,9 +472,31 @@ type AddServiceUnits struct {
 	ToMachineSpec string
 }
 
+// ScaleService holds parameters for the ScaleService call.
+type ScaleService struct {
+	ServiceName string
+	// NumUnits is the number of units the service should have.
+	NumUnits int
+	// ToMachineSpec, if set, places the unit added when scaling up
+	// by one.
+	ToMachineSpec string
+}
+
+// ScaleServiceResult holds the units added or removed by the
+// ScaleService call.
+type ScaleServiceResult struct {
+	Added   []string
+	Removed []string
+}
+
 // DestroyServiceUnits holds parameters for the DestroyUnits call.
 type DestroyServiceUnits struct {
 	UnitNames []string
+	// RemoveEmptyMachines, if true, has the hosts of the units'
+	// containers destroyed when the removal of the containers leaves
+	// them empty, whatever the environment's remove-empty-machines
+	// setting.
+	RemoveEmptyMachines bool `json:",omitempty"`
 }
 
 // ServiceDestroy holds the parameters for making the ServiceDestroy call.
@@ -359,6 +517,20 @@ type LoginRequest struct {
 	AuthTag     string `json:"auth-tag"`
 	Credentials string `json:"credentials"`
 	Nonce       string `json:"nonce"`
+
+	// SessionToken, if set, is used in place of Credentials to log
+	// in a user with a session started by an earlier login.
+	SessionToken string `json:"session-token,omitempty"`
+
+	// RequestSession asks for a session to be started for a user
+	// logging in with a password, so that later logins can use its
+	// token instead.
+	RequestSession bool `json:"request-session,omitempty"`
+
+	// TOTPCode holds the time-based one-time password that users
+	// enrolled for two-factor authentication must give when logging
+	// in with a password.
+	TOTPCode string `json:"totp-code,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
@@ -394,12 +566,43 @@ type GetConstraintsResults struct {
 	Constraints constraints.Value
 }
 
+// UnitConstraintsResult holds the constraints of a single unit, or an
+// error.
+type UnitConstraintsResult struct {
+	Error *Error `json:"error,omitempty"`
+
+	// Constraints holds the constraints recorded for the unit when it
+	// was added.
+	Constraints constraints.Value `json:"constraints"`
+
+	// Effective holds the constraints that would be used to provision
+	// a new machine for the unit now.
+	Effective constraints.Value `json:"effective"`
+
+	// Sources maps each attribute set in Effective to the level at
+	// which it was specified: "environment", "service" or
+	// "placement".
+	Sources map[string]string `json:"sources,omitempty"`
+}
+
+// UnitConstraintsResults holds the results of a UnitConstraints call.
+type UnitConstraintsResults struct {
+	Results []UnitConstraintsResult `json:"results"`
+}
+
 // SetConstraints stores parameters for making the SetConstraints call.
 type SetConstraints struct {
 	ServiceName string //optional, if empty, environment constraints are set.
 	Constraints constraints.Value
 }
 
+// SetConstraintsBulk holds the parameters for making the
+// SetConstraintsBulk call, setting the constraints of several services,
+// or of the environment, at once.
+type SetConstraintsBulk struct {
+	Constraints []SetConstraints
+}
+
 // ResolveCharms stores charm references for a ResolveCharms call.
 type ResolveCharms struct {
 	References []charm.Reference
@@ -426,6 +629,12 @@ type AllWatcherNextResults struct {
 	Deltas []multiwatcher.Delta
 }
 
+// AllEnvWatcherNextResults holds deltas returned from calling
+// AllEnvWatcher.Next().
+type AllEnvWatcherNextResults struct {
+	Deltas []multiwatcher.EnvironDelta
+}
+
 // ListSSHKeys stores parameters used for a KeyManager.ListKeys call.
 type ListSSHKeys struct {
 	Entities
@@ -517,6 +726,30 @@ type ProvisioningScriptResult struct {
 	Script string
 }
 
+// ProviderInstanceArgs holds the parameters for making the
+// ProviderInstance call.
+type ProviderInstanceArgs struct {
+	InstanceId instance.Id
+}
+
+// ProviderInstanceResult holds what the environment's provider knows
+// of an instance, as returned by the ProviderInstance call. MachineId
+// holds the id of the machine associated with the instance, if any.
+type ProviderInstanceResult struct {
+	InstanceId       instance.Id
+	Status           string
+	Addresses        []Address
+	AvailabilityZone string
+	MachineId        string
+}
+
+// ExportBundleParams holds the parameters for making the ExportBundle
+// call. If IncludePlacement is true, the bundle holds the machines of
+// the environment and the placement of units on them.
+type ExportBundleParams struct {
+	IncludePlacement bool
+}
+
 // DeployerConnectionValues containers the result of deployer.ConnectionInfo
 // API call.
 type DeployerConnectionValues struct {
@@ -524,9 +757,31 @@ type DeployerConnectionValues struct {
 	APIAddresses   []string
 }
 
+// The sections of an environment's status that may be requested in
+// StatusParams.Sections.
+const (
+	StatusSectionMachines  = "machines"
+	StatusSectionServices  = "services"
+	StatusSectionRelations = "relations"
+	StatusSectionNetworks  = "networks"
+)
+
+// AllStatusSections holds every section of an environment's status.
+var AllStatusSections = []string{
+	StatusSectionMachines,
+	StatusSectionServices,
+	StatusSectionRelations,
+	StatusSectionNetworks,
+}
+
 // StatusParams holds parameters for the Status call.
 type StatusParams struct {
 	Patterns []string
+
+	// Sections, if not empty, restricts the status computed to the
+	// named sections. Service relations are reported only when the
+	// relations section is requested.
+	Sections []string
 }
 
 // SetRsyslogCertParams holds parameters for the SetRsyslogCert call.
@@ -608,6 +863,34 @@ type AuthUserInfo struct {
 	// Credentials contains an optional opaque credential value to be held by
 	// the client, if any.
 	Credentials *string `json:"credentials,omitempty"`
+
+	// Session holds the session the user is logged in with, if one
+	// was requested or used to log in.
+	Session *SessionInfo `json:"session,omitempty"`
+}
+
+// SessionInfo describes a session that lets a user log in with a
+// token in place of their password until the token expires. The
+// token can be refreshed before then with UserManager.RefreshSession.
+type SessionInfo struct {
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// SessionToken identifies a session by its token.
+type SessionToken struct {
+	Token string `json:"token"`
+}
+
+// EnableTOTP holds the arguments for enrolling a user for two-factor
+// authentication. Code must be the current time-based one-time
+// password for Secret, to show that the user's authenticator has been
+// set up with it. A user who is already enrolled must also give the
+// CurrentCode for the secret they are enrolled with.
+type EnableTOTP struct {
+	Secret      string `json:"secret"`
+	Code        string `json:"code"`
+	CurrentCode string `json:"current-code,omitempty"`
 }
 
 // LoginRequestV1 holds the result of an Admin v1 Login call.
@@ -746,6 +1029,167 @@ type RebootActionResult struct {
 	Error  *Error       `json:"error,omitempty"`
 }
 
+// RebootMachinesParams holds the arguments for requesting that
+// machines be rebooted.
+type RebootMachinesParams struct {
+	Entities []Entity `json:"entities"`
+	// Force skips the checks that refuse to reboot a machine
+	// whose absence would make the environment unavailable.
+	Force bool `json:"force,omitempty"`
+}
+
+// RebootStatusParams holds the arguments for recording the
+// progress of machine reboots.
+type RebootStatusParams struct {
+	Params []RebootStatusParam `json:"params"`
+}
+
+// RebootStatusParam holds a machine and the progress of its reboot.
+type RebootStatusParam struct {
+	Entity Entity       `json:"entity"`
+	Status RebootStatus `json:"status"`
+}
+
+// RebootStatusResults holds the reboot statuses of machines.
+type RebootStatusResults struct {
+	Results []RebootStatusResult `json:"results"`
+}
+
+// RebootStatusResult holds the progress of the reboot requested for
+// a machine. The status is empty if no reboot has been requested.
+type RebootStatusResult struct {
+	Status RebootStatus `json:"status,omitempty"`
+	Error  *Error       `json:"error,omitempty"`
+}
+
+// ScheduleMachineRemovalsParams holds the arguments for scheduling the
+// removal of machines once a grace period has passed.
+type ScheduleMachineRemovalsParams struct {
+	Entities    []Entity      `json:"entities"`
+	GracePeriod time.Duration `json:"grace-period"`
+}
+
+// ScheduledRemovalResults holds the results of scheduling the removal
+// of machines.
+type ScheduledRemovalResults struct {
+	Results []ScheduledRemovalResult `json:"results"`
+}
+
+// ScheduledRemovalResult holds the time at which a machine will be
+// removed unless the removal is cancelled first.
+type ScheduledRemovalResult struct {
+	Due   time.Time `json:"due"`
+	Error *Error    `json:"error,omitempty"`
+}
+
+// UpgradeSeriesParams holds the arguments for preparing the series
+// upgrades of machines.
+type UpgradeSeriesParams struct {
+	Params []UpgradeSeriesParam `json:"params"`
+}
+
+// UpgradeSeriesParam holds a machine and the series it is to be
+// upgraded to.
+type UpgradeSeriesParam struct {
+	Entity Entity `json:"entity"`
+	Series string `json:"series"`
+}
+
+// UpgradeSeriesStatusParams holds the arguments for setting the series
+// upgrade statuses of units.
+type UpgradeSeriesStatusParams struct {
+	Params []UpgradeSeriesStatusParam `json:"params"`
+}
+
+// UpgradeSeriesStatusParam holds a unit and its series upgrade status.
+type UpgradeSeriesStatusParam struct {
+	Entity Entity              `json:"entity"`
+	Status UpgradeSeriesStatus `json:"status"`
+}
+
+// UpgradeSeriesStatusResults holds the results of a call to
+// UpgradeSeriesUnitStatus.
+type UpgradeSeriesStatusResults struct {
+	Results []UpgradeSeriesStatusResult `json:"results"`
+}
+
+// UpgradeSeriesStatusResult holds the series upgrade status of a
+// unit, or an error. A unit that is not taking part in a series
+// upgrade has an empty status.
+type UpgradeSeriesStatusResult struct {
+	Status UpgradeSeriesStatus `json:"status,omitempty"`
+	Error  *Error              `json:"error,omitempty"`
+}
+
+// NetworkInfoParams holds the arguments for getting the network
+// information of a unit's endpoint bindings.
+type NetworkInfoParams struct {
+	Unit     string   `json:"unit"`
+	Bindings []string `json:"bindings"`
+}
+
+// NetworkInfoResults holds the network information of each requested
+// binding, keyed on binding name.
+type NetworkInfoResults struct {
+	Results map[string]NetworkInfoResult `json:"results"`
+}
+
+// NetworkInfoResult holds the addresses a unit should use for one of
+// its endpoint bindings, or an error. IngressAddresses holds the
+// addresses other units should use to reach the unit, and
+// EgressSubnets the subnets from which the unit's traffic originates.
+type NetworkInfoResult struct {
+	Space            string   `json:"space,omitempty"`
+	IngressAddresses []string `json:"ingress-addresses,omitempty"`
+	EgressSubnets    []string `json:"egress-subnets,omitempty"`
+	Error            *Error   `json:"error,omitempty"`
+}
+
+// GoalStateResults holds the results of a call to GoalStates.
+type GoalStateResults struct {
+	Results []GoalStateResult `json:"results"`
+}
+
+// GoalStateResult holds the goal state of a unit, or an error.
+type GoalStateResult struct {
+	Result *GoalState `json:"result,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// GoalState describes the units and relations a unit's service is
+// expected to have once deployment settles, including those that are
+// not yet up. Units holds the status of each unit of the service, and
+// Relations the status of each related service and unit, keyed on the
+// name of the unit's endpoint.
+type GoalState struct {
+	Units     UnitsGoalState            `json:"units"`
+	Relations map[string]UnitsGoalState `json:"relations"`
+}
+
+// UnitsGoalState holds the goal state status of each of a number of
+// entities, keyed on name.
+type UnitsGoalState map[string]GoalStateStatus
+
+// GoalStateStatus holds the status of an entity that is part of a
+// unit's goal state, and the time it was last set, if known.
+type GoalStateStatus struct {
+	Status string     `json:"status"`
+	Since  *time.Time `json:"since,omitempty"`
+}
+
+// SetUniterStateParams holds the arguments for recording the uniter
+// operation states of units.
+type SetUniterStateParams struct {
+	Params []SetUniterStateParam `json:"params"`
+}
+
+// SetUniterStateParam holds a unit and the serialized operation state
+// of its uniter.
+type SetUniterStateParam struct {
+	Entity Entity `json:"entity"`
+	State  string `json:"state"`
+}
+
 // Life describes the lifecycle state of an entity ("alive", "dying" or "dead").
 type Life multiwatcher.Life
 
@@ -787,6 +1231,9 @@ const (
 	// The machine ought to be signalling activity, but it cannot be
 	// detected.
 	StatusDown Status = "down"
+
+	// The machine's instance can no longer be found in the provider.
+	StatusInstanceMissing Status = "instance-missing"
 )
 
 const (
@@ -856,3 +1303,227 @@ const (
 	// been asked to offer.
 	StatusActive Status = "active"
 )
+
+// LogRecord holds a single log message with the fields that identify
+// where it came from. Agents whose logging format is "json" write their
+// log messages in this form, and the debug-log API streams them in this
+// form when asked for JSON.
+type LogRecord struct {
+	Time     time.Time `json:"time"`
+	Level    string    `json:"level"`
+	Module   string    `json:"module"`
+	Location string    `json:"location,omitempty"`
+	Message  string    `json:"message"`
+	Entity   string    `json:"entity,omitempty"`
+	EnvUUID  string    `json:"env-uuid,omitempty"`
+}
+
+// CheckConsistencyArgs holds the arguments for the
+// Consistency.CheckConsistency call.
+type CheckConsistencyArgs struct {
+	// Repair causes problems that can be safely repaired to be
+	// repaired.
+	Repair bool
+}
+
+// Inconsistency describes a problem found in the state database by
+// the Consistency.CheckConsistency call.
+type Inconsistency struct {
+	Kind       string
+	Collection string
+	DocID      string
+	Detail     string
+	Repairable bool
+	Repaired   bool
+}
+
+// ConsistencyReport holds the results of the
+// Consistency.CheckConsistency call.
+type ConsistencyReport struct {
+	Inconsistencies []Inconsistency
+}
+
+// AddWebhookArgs holds the arguments for the Webhooks.AddWebhook call.
+type AddWebhookArgs struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// AddWebhookResult holds the result of the Webhooks.AddWebhook call.
+type AddWebhookResult struct {
+	Id    string `json:"id"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// Webhook describes a URL to which notifications of environment events
+// are posted. Its secret is never returned.
+type Webhook struct {
+	Id     string   `json:"id"`
+	URL    string   `json:"url"`
+	Signed bool     `json:"signed"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhooksResult holds the result of the Webhooks.Webhooks call.
+type WebhooksResult struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// RemoveWebhookArgs holds the arguments for the
+// Webhooks.RemoveWebhook call.
+type RemoveWebhookArgs struct {
+	Id string `json:"id"`
+}
+
+// DesiredUnits holds the number of units a service should have, as
+// requested by an external scaler.
+type DesiredUnits struct {
+	ServiceTag string `json:"service-tag"`
+	NumUnits   int    `json:"num-units"`
+
+	// Reason describes why the service is being scaled. It is
+	// recorded with the scale event.
+	Reason string `json:"reason"`
+
+	// Token, if set, makes the request idempotent: retrying it with
+	// the same token returns the result of the first request.
+	Token string `json:"token,omitempty"`
+}
+
+// DesiredUnitsArgs holds the arguments for the Scale.SetDesiredUnits
+// call.
+type DesiredUnitsArgs struct {
+	Entries []DesiredUnits `json:"entries"`
+}
+
+// DesiredUnitsResult holds the result of scaling a service to its
+// desired number of units.
+type DesiredUnitsResult struct {
+	// Previous holds the number of live units before scaling.
+	Previous int      `json:"previous"`
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Error    *Error   `json:"error,omitempty"`
+}
+
+// DesiredUnitsResults holds the results of the Scale.SetDesiredUnits
+// call.
+type DesiredUnitsResults struct {
+	Results []DesiredUnitsResult `json:"results"`
+}
+
+// ServiceScaleResult holds the current scale of a service.
+type ServiceScaleResult struct {
+	Units      int        `json:"units"`
+	MinUnits   int        `json:"min-units"`
+	LastScaled *time.Time `json:"last-scaled,omitempty"`
+	Error      *Error     `json:"error,omitempty"`
+}
+
+// ServiceScaleResults holds the results of the Scale.ServiceScales
+// call.
+type ServiceScaleResults struct {
+	Results []ServiceScaleResult `json:"results"`
+}
+
+// ScaleEventsArgs holds the arguments for the Scale.ScaleEvents call.
+type ScaleEventsArgs struct {
+	// ServiceTag, if set, restricts the events to those of the
+	// service.
+	ServiceTag string `json:"service-tag,omitempty"`
+
+	// Since restricts the events to those after the given time.
+	Since time.Time `json:"since"`
+}
+
+// ScaleEvent describes a change made to the number of units of a
+// service.
+type ScaleEvent struct {
+	ServiceTag   string    `json:"service-tag"`
+	Previous     int       `json:"previous"`
+	Desired      int       `json:"desired"`
+	Reason       string    `json:"reason"`
+	RequesterTag string    `json:"requester-tag"`
+	Time         time.Time `json:"time"`
+}
+
+// ScaleEventsResult holds the result of the Scale.ScaleEvents call.
+type ScaleEventsResult struct {
+	Events []ScaleEvent `json:"events"`
+}
+
+// EnvironmentUsageArgs holds the arguments for the
+// ControllerUsage.EnvironmentUsage call.
+type EnvironmentUsageArgs struct {
+	// Hours holds the number of hours, up to and including the
+	// current one, over which usage is reported.
+	Hours int `json:"hours"`
+}
+
+// EnvironmentUsage holds the API usage of an environment.
+type EnvironmentUsage struct {
+	EnvironTag string `json:"environ-tag"`
+	Name       string `json:"name,omitempty"`
+	Calls      int64  `json:"calls"`
+	BytesIn    int64  `json:"bytes-in"`
+	BytesOut   int64  `json:"bytes-out"`
+}
+
+// EnvironmentUsageResults holds the result of the
+// ControllerUsage.EnvironmentUsage call.
+type EnvironmentUsageResults struct {
+	Environments []EnvironmentUsage `json:"environments"`
+}
+
+// APISchemaResult holds the result of the APISchema.Schema call: a
+// description of every facade served by the API server.
+type APISchemaResult struct {
+	Facades []FacadeSchema `json:"facades"`
+
+	// Types holds the definitions of the struct types used by the
+	// methods of the facades, keyed by the names they are referred to
+	// by.
+	Types map[string]TypeSchema `json:"types"`
+}
+
+// FacadeSchema describes a version of a facade.
+type FacadeSchema struct {
+	Name    string         `json:"name"`
+	Version int            `json:"version"`
+	Methods []MethodSchema `json:"methods"`
+}
+
+// MethodSchema describes a method of a facade. Params and Result are
+// nil when the method takes no argument or returns no value.
+type MethodSchema struct {
+	Name   string      `json:"name"`
+	Params *TypeSchema `json:"params,omitempty"`
+	Result *TypeSchema `json:"result,omitempty"`
+}
+
+// TypeSchema describes the JSON encoding of a type.
+type TypeSchema struct {
+	// Kind holds one of "bool", "int", "float", "string", "time",
+	// "bytes", "any", "array", "map", "struct" or "ref".
+	Kind string `json:"kind"`
+
+	// Ref holds the name of the struct type in APISchemaResult.Types
+	// described, when Kind is "ref".
+	Ref string `json:"ref,omitempty"`
+
+	// Elem describes the elements of an array or the values of a map,
+	// which is always keyed by strings.
+	Elem *TypeSchema `json:"elem,omitempty"`
+
+	// Fields holds the fields of a struct.
+	Fields []FieldSchema `json:"fields,omitempty"`
+}
+
+// FieldSchema describes a field of a struct. Optional fields are
+// omitted from the encoding when empty.
+type FieldSchema struct {
+	Name     string     `json:"name"`
+	Type     TypeSchema `json:"type"`
+	Optional bool       `json:"optional,omitempty"`
+}
@@ -170,6 +170,13 @@ type Volume struct {
 	// Size is the size of the volume in MiB.
 	Size       uint64 `json:"size"`
 	Persistent bool   `json:"persistent"`
+	// Releasing is true if the volume is to be detached and left in
+	// the cloud, rather than destroyed, once it is Dead.
+	Releasing bool `json:"releasing,omitempty"`
+	// Provider is the type of the storage provider that manages the
+	// volume. It is only reported to storage provisioners, which use
+	// it to destroy the volume.
+	Provider string `json:"provider,omitempty"`
 }
 
 // Volumes describes a set of storage volumes in the environment.
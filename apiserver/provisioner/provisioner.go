@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -93,7 +94,7 @@ func NewProvisionerAPI(st *state.State, resources *common.Resources, authorizer
 	}
 	urlGetter := common.NewToolsURLGetter(env.UUID(), st)
 	return &ProvisionerAPI{
-		Remover:                common.NewRemover(st, false, getAuthFunc),
+		Remover:                common.NewRemover(st, false, getAuthFunc).PublishRemovals(common.Hub(resources), env.UUID()),
 		StatusSetter:           common.NewStatusSetter(st, getAuthFunc),
 		StatusGetter:           common.NewStatusGetter(st, getAuthFunc),
 		DeadEnsurer:            common.NewDeadEnsurer(st, getAuthFunc),
@@ -230,6 +231,15 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 		if useLxcCloneAufs, ok := config.LXCUseCloneAUFS(); ok {
 			cfg["use-aufs"] = fmt.Sprint(useLxcCloneAufs)
 		}
+		if !config.ContainerImageProxy() {
+			cfg[container.ConfigImageProxy] = "false"
+		}
+	}
+	if stream := config.ContainerImageStream(); stream != imagemetadata.ReleasedStream {
+		cfg[container.ConfigImageStream] = stream
+	}
+	if sourceURL, ok := config.ContainerImageSourceURL(); ok {
+		cfg[container.ConfigImageSourceURL] = sourceURL
 	}
 
 	if !environs.AddressAllocationEnabled() {
@@ -378,10 +388,11 @@ func (p *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provisio
 }
 
 func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.ProvisioningInfo, error) {
-	cons, err := m.Constraints()
+	cons, err := m.ProvisioningConstraints()
 	if err != nil {
-		return nil, err
+		return nil, errors.Trace(err)
 	}
+	p.recordInstanceType(cons)
 	volumes, err := p.machineVolumeParams(m)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -407,9 +418,36 @@ func (p *ProvisionerAPI) getProvisioningInfo(m *state.Machine) (*params.Provisio
 		Networks:    networks,
 		Jobs:        jobs,
 		Volumes:     volumes,
+
+		DistributionGroupName: m.DistributionGroupName(),
 	}, nil
 }
 
+// recordInstanceType normalizes the given constraints to an instance
+// type, if the environment's provider supports it, so that status can
+// report which instance type is chosen and why. A failure is only
+// logged: the provider reports its own error when provisioning if no
+// instance type satisfies the constraints.
+func (p *ProvisionerAPI) recordInstanceType(cons constraints.Value) {
+	cfg, err := p.st.EnvironConfig()
+	if err != nil {
+		logger.Warningf("cannot get environment config to normalize constraints: %v", err)
+		return
+	}
+	env, err := environs.New(cfg)
+	if err != nil {
+		logger.Warningf("cannot open environment to normalize constraints: %v", err)
+		return
+	}
+	normalizer, ok := environs.SupportsConstraintsNormalization(env)
+	if !ok {
+		return
+	}
+	if _, err := common.NormalizeConstraints(p.st, normalizer, cons); err != nil {
+		logger.Warningf("%v", err)
+	}
+}
+
 // DistributionGroup returns, for each given machine entity,
 // a slice of instance.Ids that belong to the same distribution
 // group as that machine. This information may be used to
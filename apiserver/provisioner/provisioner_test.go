@@ -873,6 +873,44 @@ func (s *withoutStateServerSuite) TestStorageProviderFallbackToType(c *gc.C) {
 	})
 }
 
+func (s *withoutStateServerSuite) TestProvisioningInfoResolvesEnvironConstraints(c *gc.C) {
+	template := state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=8G"),
+	}
+	machine, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	// Environment constraints set after the machine was added are
+	// used for any attributes the machine does not specify.
+	err = s.State.SetEnvironConstraints(constraints.MustParse("arch=amd64 mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.Constraints, jc.DeepEquals, constraints.MustParse("arch=amd64 mem=8G"))
+}
+
+func (s *withoutStateServerSuite) TestProvisioningInfoDistributionGroupName(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{{Tag: names.NewMachineTag(machineId).String()}}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.DistributionGroupName, gc.Equals, "wordpress")
+}
+
 func (s *withoutStateServerSuite) TestProvisioningInfoPermissions(c *gc.C) {
 	// Login as a machine agent for machine 0.
 	anAuthorizer := s.authorizer
@@ -1334,6 +1372,31 @@ func (s *withoutStateServerSuite) TestContainerManagerConfigNoIPForwarding(c *gc
 	})
 }
 
+func (s *withoutStateServerSuite) TestContainerManagerConfigImageSource(c *gc.C) {
+	s.SetFeatureFlags() // clear the flags.
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"container-image-stream":     "daily",
+		"container-image-source-url": "http://mirror.example.com/ubuntu",
+		"container-image-proxy":      false,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg := s.getManagerConfig(c, instance.KVM)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigName:           "juju",
+		container.ConfigImageStream:    "daily",
+		container.ConfigImageSourceURL: "http://mirror.example.com/ubuntu",
+	})
+
+	cfg = s.getManagerConfig(c, instance.LXC)
+	c.Assert(cfg, jc.DeepEquals, map[string]string{
+		container.ConfigName:           "juju",
+		container.ConfigImageStream:    "daily",
+		container.ConfigImageSourceURL: "http://mirror.example.com/ubuntu",
+		container.ConfigImageProxy:     "false",
+	})
+}
+
 func (s *withoutStateServerSuite) TestContainerConfig(c *gc.C) {
 	attrs := map[string]interface{}{
 		"http-proxy":            "http://proxy.example.com:9000",
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package provisioningerrors implements the API facade used by the
+// state server to find, and report, machines stuck in provisioning
+// errors.
+package provisioningerrors
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+func init() {
+	common.RegisterStandardFacade("ProvisioningErrors", 1, NewProvisioningErrorsAPI)
+}
+
+// ProvisioningErrorsAPI implements the ProvisioningErrors facade.
+type ProvisioningErrorsAPI struct {
+	*common.EnvironWatcher
+
+	st        *state.State
+	resources *common.Resources
+}
+
+// NewProvisioningErrorsAPI creates a new server-side ProvisioningErrors
+// API end point. Only environment managers may use it.
+func NewProvisioningErrorsAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ProvisioningErrorsAPI, error) {
+	if !authorizer.AuthEnvironManager() {
+		return nil, common.ErrPerm
+	}
+	return &ProvisioningErrorsAPI{
+		EnvironWatcher: common.NewEnvironWatcher(st, resources, authorizer),
+		st:             st,
+		resources:      resources,
+	}, nil
+}
+
+// WatchMachineStatuses returns a StringsWatcher that notifies of the ids
+// of the machines whose statuses change.
+func (api *ProvisioningErrorsAPI) WatchMachineStatuses() (params.StringsWatchResult, error) {
+	watch := api.st.WatchMachineStatuses()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: api.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(watch)
+}
+
+// ProvisioningErrors returns the machines that have been stuck in
+// provisioning errors for at least the given threshold.
+func (api *ProvisioningErrorsAPI) ProvisioningErrors(args params.ProvisioningErrorsArgs) (params.ProvisioningErrorsResult, error) {
+	stuck, err := api.st.ProvisioningErrors(args.Threshold)
+	if err != nil {
+		return params.ProvisioningErrorsResult{}, errors.Trace(err)
+	}
+	result := params.ProvisioningErrorsResult{
+		Errors: make([]params.ProvisioningError, len(stuck)),
+	}
+	for i, e := range stuck {
+		result.Errors[i] = params.ProvisioningError{
+			MachineTag: names.NewMachineTag(e.MachineId).String(),
+			Message:    e.Message,
+			Data:       e.Data,
+			Since:      e.Since,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioningerrors_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/provisioningerrors"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type provisioningErrorsSuite struct {
+	jujutesting.JujuConnSuite
+	resources *common.Resources
+	api       *provisioningerrors.ProvisioningErrorsAPI
+}
+
+var _ = gc.Suite(&provisioningErrorsSuite{})
+
+func (s *provisioningErrorsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	api, err := provisioningerrors.NewProvisioningErrorsAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *provisioningErrorsSuite) TestNonManagerRejected(c *gc.C) {
+	_, err := provisioningerrors.NewProvisioningErrorsAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *provisioningErrorsSuite) TestProvisioningErrors(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	data := map[string]interface{}{"provider-error": "quota exceeded"}
+	err = m.SetStatus(state.StatusError, "cannot start instance", data)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.ProvisioningErrors(params.ProvisioningErrorsArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Errors, gc.HasLen, 1)
+	c.Assert(result.Errors[0].MachineTag, gc.Equals, m.Tag().String())
+	c.Assert(result.Errors[0].Message, gc.Equals, "cannot start instance")
+	c.Assert(result.Errors[0].Data, jc.DeepEquals, data)
+
+	result, err = s.api.ProvisioningErrors(params.ProvisioningErrorsArgs{Threshold: time.Hour})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Errors, gc.HasLen, 0)
+}
+
+func (s *provisioningErrorsSuite) TestWatchMachineStatuses(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.WatchMachineStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.StringsWatcherId, gc.Equals, "1")
+	c.Assert(result.Changes, jc.DeepEquals, []string{m.Id()})
+
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+
+	err = m.SetStatus(state.StatusError, "cannot start instance", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(m.Id())
+	wc.AssertNoChange()
+}
@@ -25,6 +25,7 @@ type RebootAPI struct {
 	// Where installing the hyper-v role will require a reboot.
 	*common.RebootRequester
 	*common.RebootFlagClearer
+	*common.RebootStatusSetter
 
 	auth      common.Authorizer
 	st        *state.State
@@ -59,6 +60,7 @@ func NewRebootAPI(st *state.State, resources *common.Resources, auth common.Auth
 		RebootActionGetter: common.NewRebootActionGetter(st, canAccess),
 		RebootRequester:    common.NewRebootRequester(st, canAccess),
 		RebootFlagClearer:  common.NewRebootFlagClearer(st, canAccess),
+		RebootStatusSetter: common.NewRebootStatusSetter(st, canAccess),
 		st:                 st,
 		machine:            machine,
 		resources:          resources,
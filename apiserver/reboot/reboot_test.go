@@ -187,6 +187,33 @@ func (s *rebootSuite) TestClearReboot(c *gc.C) {
 		}})
 }
 
+func (s *rebootSuite) TestSetRebootStatus(c *gc.C) {
+	args := params.RebootStatusParams{
+		Params: []params.RebootStatusParam{
+			{Entity: params.Entity{Tag: s.machine.machine.Tag().String()}, Status: params.RebootDraining},
+			{Entity: params.Entity{Tag: s.container.machine.Tag().String()}, Status: params.RebootDraining},
+		},
+	}
+	errResult, err := s.machine.rebootAPI.SetRebootStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errResult, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.NotFoundError("reboot request for machine " + s.machine.machine.Id())},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine.machine.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	errResult, err = s.machine.rebootAPI.SetRebootStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errResult.Results[0].Error, gc.IsNil)
+
+	status, err := s.machine.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.RebootDraining)
+}
+
 func (s *rebootSuite) TestRebootRequestFromMachine(c *gc.C) {
 	// Request reboot on the root machine: all machines should see it
 	// machine should reboot
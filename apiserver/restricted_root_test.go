@@ -35,6 +35,12 @@ func (r *restrictedRootSuite) assertMethodAllowed(c *gc.C, rootName string, vers
 }
 
 func (r *restrictedRootSuite) TestFindAllowedMethod(c *gc.C) {
+	r.assertMethodAllowed(c, "Clouds", 1, "AddClouds")
+	r.assertMethodAllowed(c, "Clouds", 1, "ListClouds")
+
+	r.assertMethodAllowed(c, "Credentials", 1, "AddCredential")
+	r.assertMethodAllowed(c, "Credentials", 1, "ListCredentials")
+
 	r.assertMethodAllowed(c, "EnvironmentManager", 1, "CreateEnvironment")
 	r.assertMethodAllowed(c, "EnvironmentManager", 1, "ListEnvironments")
 
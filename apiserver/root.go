@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -74,6 +75,14 @@ func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier
 	if err := r.resources.RegisterNamed("logDir", common.StringResource(srv.logDir)); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := r.resources.RegisterNamed("hub", common.HubResource{Hub: srv.hub}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if srv.agentReporter != nil {
+		if err := r.resources.RegisterNamed("agentReporter", common.ReporterResource{Reporter: srv.agentReporter}); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	return r, nil
 }
 
@@ -131,6 +140,17 @@ func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error)
 	return s.objMethod.Call(objVal, arg)
 }
 
+// CallContext is like Call, but passes ctx to facade methods that take
+// a context.Context, so that they can stop when the client cancels the
+// request. See rpcreflect.ContextMethodCaller for more detail.
+func (s *srvCaller) CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	objVal, err := s.creator(objId)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return s.objMethod.CallContext(ctx, objVal, arg)
+}
+
 // apiRoot implements basic method dispatching to the facade registry.
 type apiRoot struct {
 	state       *state.State
@@ -0,0 +1,205 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package scale implements the API facade used by external scalers to
+// drive the number of units of services.
+package scale
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	jjj "github.com/juju/juju/juju"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Scale", 1, NewScaleAPI)
+}
+
+// minScaleInterval is the shortest time allowed between changes to the
+// number of units of a service made through the Scale facade, so that
+// a misbehaving scaler cannot thrash a service.
+var minScaleInterval = time.Minute
+
+// ScaleAPI implements the Scale facade.
+type ScaleAPI struct {
+	st         *state.State
+	check      *common.BlockChecker
+	authorizer common.Authorizer
+}
+
+// NewScaleAPI creates a new server-side Scale API end point. Only
+// clients may use it.
+func NewScaleAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*ScaleAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	return &ScaleAPI{
+		st:         st,
+		check:      common.NewBlockChecker(st),
+		authorizer: authorizer,
+	}, nil
+}
+
+// SetDesiredUnits adds or removes units of each given service so that
+// it has the desired number of live units, recording the reason given
+// as a scale event. Asking for the number of units a service already
+// has changes nothing and always succeeds, so a scaler may repeat its
+// requests freely. A service whose number of units was changed less
+// than a minute ago is not changed again, and the request fails with
+// the "try again" error code.
+func (api *ScaleAPI) SetDesiredUnits(args params.DesiredUnitsArgs) (params.DesiredUnitsResults, error) {
+	results := params.DesiredUnitsResults{
+		Results: make([]params.DesiredUnitsResult, len(args.Entries)),
+	}
+	for i, arg := range args.Entries {
+		result := &results.Results[i]
+		err := common.RunIdempotent(api.st, arg.Token, "Scale.SetDesiredUnits", result, func() error {
+			return api.setDesiredUnits(arg, result)
+		})
+		if err != nil {
+			*result = params.DesiredUnitsResult{Error: common.ServerError(err)}
+		}
+	}
+	return results, nil
+}
+
+func (api *ScaleAPI) setDesiredUnits(arg params.DesiredUnits, result *params.DesiredUnitsResult) error {
+	tag, err := names.ParseServiceTag(arg.ServiceTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if arg.Reason == "" {
+		return errors.Errorf("no reason given for scaling service %q", tag.Id())
+	}
+	service, err := api.st.Service(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	plan, err := jjj.PlanScale(service, arg.NumUnits, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result.Previous = len(plan.Units)
+	switch {
+	case plan.Delta == 0:
+		return nil
+	case plan.Delta > 0:
+		err = api.check.ChangeAllowed()
+	default:
+		err = api.check.RemoveAllowed()
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.checkScaleInterval(service.Name()); err != nil {
+		return errors.Trace(err)
+	}
+	added, removed, applyErr := plan.Apply(api.st)
+	result.Added, result.Removed = added, removed
+	if len(added) > 0 || len(removed) > 0 {
+		// Record even a partial change, so that it is limited in
+		// the same way as a complete one.
+		desired := result.Previous + len(added) - len(removed)
+		_, err := api.st.AddScaleEvent(service.Name(), result.Previous, desired, arg.Reason, api.authorizer.GetAuthTag())
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(applyErr)
+}
+
+// checkScaleInterval returns an error satisfying common.ErrTryAgain if
+// the number of units of the named service was changed less than
+// minScaleInterval ago.
+func (api *ScaleAPI) checkScaleInterval(serviceName string) error {
+	last, err := api.st.LastScaleEvent(serviceName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if time.Since(last.Time) < minScaleInterval {
+		return errors.Annotatef(common.ErrTryAgain, "service %q was scaled less than %v ago", serviceName, minScaleInterval)
+	}
+	return nil
+}
+
+// ServiceScales returns the number of live units of each given
+// service, with its minimum number of units and when it was last
+// scaled.
+func (api *ScaleAPI) ServiceScales(args params.Entities) (params.ServiceScaleResults, error) {
+	results := params.ServiceScaleResults{
+		Results: make([]params.ServiceScaleResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		result, err := api.serviceScale(entity.Tag)
+		if err != nil {
+			result = params.ServiceScaleResult{Error: common.ServerError(err)}
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+func (api *ScaleAPI) serviceScale(serviceTag string) (params.ServiceScaleResult, error) {
+	tag, err := names.ParseServiceTag(serviceTag)
+	if err != nil {
+		return params.ServiceScaleResult{}, errors.Trace(err)
+	}
+	service, err := api.st.Service(tag.Id())
+	if err != nil {
+		return params.ServiceScaleResult{}, errors.Trace(err)
+	}
+	units, err := jjj.AliveUnits(service)
+	if err != nil {
+		return params.ServiceScaleResult{}, errors.Trace(err)
+	}
+	result := params.ServiceScaleResult{
+		Units:    len(units),
+		MinUnits: service.MinUnits(),
+	}
+	last, err := api.st.LastScaleEvent(service.Name())
+	if err == nil {
+		result.LastScaled = &last.Time
+	} else if !errors.IsNotFound(err) {
+		return params.ServiceScaleResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// ScaleEvents returns the scale events recorded after the given time,
+// oldest first, optionally restricted to those of a single service.
+func (api *ScaleAPI) ScaleEvents(args params.ScaleEventsArgs) (params.ScaleEventsResult, error) {
+	var serviceName string
+	if args.ServiceTag != "" {
+		tag, err := names.ParseServiceTag(args.ServiceTag)
+		if err != nil {
+			return params.ScaleEventsResult{}, errors.Trace(err)
+		}
+		serviceName = tag.Id()
+	}
+	events, err := api.st.ScaleEvents(serviceName, args.Since)
+	if err != nil {
+		return params.ScaleEventsResult{}, errors.Trace(err)
+	}
+	result := params.ScaleEventsResult{
+		Events: make([]params.ScaleEvent, len(events)),
+	}
+	for i, event := range events {
+		result.Events[i] = params.ScaleEvent{
+			ServiceTag:   names.NewServiceTag(event.Service).String(),
+			Previous:     event.Previous,
+			Desired:      event.Desired,
+			Reason:       event.Reason,
+			RequesterTag: event.Requester,
+			Time:         event.Time,
+		}
+	}
+	return result, nil
+}
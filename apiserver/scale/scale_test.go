@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package scale_test
+
+import (
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/scale"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type scaleSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+	service *state.Service
+	api     *scale.ScaleAPI
+}
+
+var _ = gc.Suite(&scaleSuite{})
+
+func (s *scaleSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+	s.service = s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	api, err := scale.NewScaleAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *scaleSuite) setDesiredUnits(c *gc.C, n int, reason, token string) params.DesiredUnitsResult {
+	results, err := s.api.SetDesiredUnits(params.DesiredUnitsArgs{
+		Entries: []params.DesiredUnits{{
+			ServiceTag: "service-dummy",
+			NumUnits:   n,
+			Reason:     reason,
+			Token:      token,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	return results.Results[0]
+}
+
+func (s *scaleSuite) TestAgentRejected(c *gc.C) {
+	_, err := scale.NewScaleAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *scaleSuite) TestSetDesiredUnits(c *gc.C) {
+	s.PatchValue(scale.MinScaleInterval, time.Duration(0))
+	result := s.setDesiredUnits(c, 2, "load is high", "")
+	c.Assert(result, jc.DeepEquals, params.DesiredUnitsResult{
+		Added: []string{"dummy/0", "dummy/1"},
+	})
+
+	result = s.setDesiredUnits(c, 1, "load is low", "")
+	c.Assert(result, jc.DeepEquals, params.DesiredUnitsResult{
+		Previous: 2,
+		Removed:  []string{"dummy/1"},
+	})
+
+	events, err := s.State.ScaleEvents("dummy", time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 2)
+	c.Assert(events[0].Previous, gc.Equals, 0)
+	c.Assert(events[0].Desired, gc.Equals, 2)
+	c.Assert(events[0].Reason, gc.Equals, "load is high")
+	c.Assert(events[0].Requester, gc.Equals, s.AdminUserTag(c).String())
+	c.Assert(events[1].Desired, gc.Equals, 1)
+}
+
+func (s *scaleSuite) TestSetDesiredUnitsUnchanged(c *gc.C) {
+	result := s.setDesiredUnits(c, 1, "load is high", "")
+	c.Assert(result.Error, gc.IsNil)
+
+	// Asking again for the same number of units succeeds, even
+	// within the minimum interval, and records nothing.
+	result = s.setDesiredUnits(c, 1, "load is still high", "")
+	c.Assert(result, jc.DeepEquals, params.DesiredUnitsResult{Previous: 1})
+	events, err := s.State.ScaleEvents("dummy", time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+}
+
+func (s *scaleSuite) TestSetDesiredUnitsRateLimited(c *gc.C) {
+	result := s.setDesiredUnits(c, 1, "load is high", "")
+	c.Assert(result.Error, gc.IsNil)
+
+	result = s.setDesiredUnits(c, 2, "load is higher", "")
+	c.Assert(result.Error, gc.ErrorMatches, `service "dummy" was scaled less than 1m0s ago: try again`)
+	c.Assert(result.Error, jc.Satisfies, params.IsCodeTryAgain)
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
+
+func (s *scaleSuite) TestSetDesiredUnitsIdempotent(c *gc.C) {
+	s.PatchValue(scale.MinScaleInterval, time.Duration(0))
+	first := s.setDesiredUnits(c, 2, "load is high", "scaler-42")
+	c.Assert(first.Added, gc.HasLen, 2)
+
+	// A retried request gets the first result, and adds nothing.
+	retried := s.setDesiredUnits(c, 2, "load is high", "scaler-42")
+	c.Assert(retried, jc.DeepEquals, first)
+	units, err := s.service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+}
+
+func (s *scaleSuite) TestSetDesiredUnitsErrors(c *gc.C) {
+	err := s.service.SetMinUnits(2)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := s.api.SetDesiredUnits(params.DesiredUnitsArgs{
+		Entries: []params.DesiredUnits{
+			{ServiceTag: "service-dummy", NumUnits: 3},
+			{ServiceTag: "service-dummy", NumUnits: 1, Reason: "quiet"},
+			{ServiceTag: "service-unknown", NumUnits: 1, Reason: "quiet"},
+			{ServiceTag: "unit-dummy-0", NumUnits: 1, Reason: "quiet"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `no reason given for scaling service "dummy"`)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `cannot scale service "dummy" to 1 units: minimum is 2`)
+	c.Assert(results.Results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+	c.Assert(results.Results[3].Error, gc.ErrorMatches, `"unit-dummy-0" is not a valid service tag`)
+}
+
+func (s *scaleSuite) TestSetDesiredUnitsBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestSetDesiredUnitsBlocked")
+	result := s.setDesiredUnits(c, 1, "load is high", "")
+	s.AssertBlocked(c, result.Error, "TestSetDesiredUnitsBlocked")
+}
+
+func (s *scaleSuite) TestServiceScales(c *gc.C) {
+	result := s.setDesiredUnits(c, 2, "load is high", "")
+	c.Assert(result.Error, gc.IsNil)
+	err := s.service.SetMinUnits(1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ServiceScales(params.Entities{
+		Entities: []params.Entity{{Tag: "service-dummy"}, {Tag: "service-unknown"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+	c.Assert(results.Results[0].Units, gc.Equals, 2)
+	c.Assert(results.Results[0].MinUnits, gc.Equals, 1)
+	c.Assert(results.Results[0].LastScaled, gc.NotNil)
+	c.Assert(results.Results[1].Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *scaleSuite) TestScaleEvents(c *gc.C) {
+	s.PatchValue(scale.MinScaleInterval, time.Duration(0))
+	s.setDesiredUnits(c, 2, "load is high", "")
+	s.setDesiredUnits(c, 1, "load is low", "")
+
+	result, err := s.api.ScaleEvents(params.ScaleEventsArgs{ServiceTag: "service-dummy"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Events, gc.HasLen, 2)
+	event := result.Events[0]
+	c.Assert(event.ServiceTag, gc.Equals, "service-dummy")
+	c.Assert(event.Previous, gc.Equals, 0)
+	c.Assert(event.Desired, gc.Equals, 2)
+	c.Assert(event.Reason, gc.Equals, "load is high")
+	c.Assert(event.RequesterTag, gc.Equals, s.AdminUserTag(c).String())
+
+	result, err = s.api.ScaleEvents(params.ScaleEventsArgs{Since: result.Events[1].Time})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Events, gc.HasLen, 0)
+}
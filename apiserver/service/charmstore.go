@@ -6,18 +6,18 @@ package service
 import (
 	"fmt"
 	"io"
-	"net/url"
 	"os"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/charmrepo"
-	"gopkg.in/juju/charmstore.v4/csclient"
 	"gopkg.in/macaroon-bakery.v0/httpbakery"
 	"gopkg.in/macaroon.v1"
 
+	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
@@ -61,24 +61,19 @@ func AddCharmWithAuthorization(st *state.State, args params.AddCharmWithAuthoriz
 	if err != nil {
 		return err
 	}
-	csURL, err := url.Parse(csclient.ServerURL)
+	var ms []*macaroon.Macaroon
+	if args.CharmStoreMacaroon != nil {
+		// The provided charmstore authorizing macaroon is
+		// used along with any configured for the environment.
+		ms = append(ms, args.CharmStoreMacaroon)
+	}
+	csParams, err := common.CharmStoreParams(envConfig, ms...)
 	if err != nil {
 		return err
 	}
-	csParams := charmrepo.NewCharmStoreParams{
-		URL:        csURL.String(),
-		HTTPClient: httpbakery.NewHTTPClient(),
-	}
-	if args.CharmStoreMacaroon != nil {
-		// Set the provided charmstore authorizing macaroon
-		// as a cookie in the HTTP client.
-		// TODO discharge any third party caveats in the macaroon.
-		ms := []*macaroon.Macaroon{args.CharmStoreMacaroon}
-		httpbakery.SetCookie(csParams.HTTPClient.Jar, csURL, ms)
-	}
-	repo := config.SpecializeCharmRepo(
-		NewCharmStore(csParams),
-		envConfig,
+	repo := charmstore.NewRetryingRepo(
+		config.SpecializeCharmRepo(NewCharmStore(csParams), envConfig),
+		charmstore.DefaultRetryPolicy, nil,
 	)
 	downloadedCharm, err := repo.Get(charmURL)
 	if err != nil {
@@ -171,9 +166,14 @@ func ResolveCharms(st *state.State, args params.ResolveCharms) (params.ResolveCh
 	if err != nil {
 		return params.ResolveCharmResults{}, err
 	}
-	repo := config.SpecializeCharmRepo(
-		NewCharmStore(charmrepo.NewCharmStoreParams{}),
-		envConfig)
+	csParams, err := common.CharmStoreParams(envConfig)
+	if err != nil {
+		return params.ResolveCharmResults{}, err
+	}
+	repo := charmstore.NewRetryingRepo(
+		config.SpecializeCharmRepo(NewCharmStore(csParams), envConfig),
+		charmstore.DefaultRetryPolicy, nil,
+	)
 
 	for _, ref := range args.References {
 		result := params.ResolveCharmResult{}
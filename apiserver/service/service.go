@@ -32,6 +32,9 @@ func init() {
 // Service defines the methods on the service API end point.
 type Service interface {
 	SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error)
+	SetAddressPolicies(args params.ServiceAddressPolicies) (params.ErrorResults, error)
+	ExposeEndpoints(args params.ServicesExposeEndpoints) (params.ErrorResults, error)
+	UnexposeEndpoints(args params.ServicesUnexposeEndpoints) (params.ErrorResults, error)
 }
 
 // API implements the service interface and is the concrete
@@ -81,6 +84,110 @@ func (api *API) SetMetricCredentials(args params.ServiceMetricCredentials) (para
 	return result, nil
 }
 
+// SetAddressPolicies sets the policy used to select the public
+// addresses of each service's units.
+func (api *API) SetAddressPolicies(args params.ServiceAddressPolicies) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Policies)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Policies {
+		err := api.setAddressPolicy(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) setAddressPolicy(arg params.ServiceAddressPolicy) error {
+	policy, err := state.ParseAddressPolicy(arg.Policy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return service.SetAddressPolicy(policy)
+}
+
+// ExposeEndpoints exposes each service only on the given endpoints,
+// adding them to the endpoints already exposed. A service that was
+// exposed on all its endpoints becomes exposed only on those given.
+func (api *API) ExposeEndpoints(args params.ServicesExposeEndpoints) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Services)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Services {
+		err := api.exposeEndpoints(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) exposeEndpoints(arg params.ServiceExposeEndpoints) error {
+	if len(arg.Endpoints) == 0 {
+		return errors.New("no endpoints specified")
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	endpoints := service.ExposedEndpoints()
+	if endpoints == nil {
+		endpoints = make(map[string]state.ExposedEndpoint)
+	}
+	for name, endpoint := range arg.Endpoints {
+		endpoints[name] = state.ExposedEndpoint{
+			Ports: endpoint.Ports,
+			CIDRs: endpoint.CIDRs,
+		}
+	}
+	return service.SetExposedEndpoints(endpoints)
+}
+
+// UnexposeEndpoints stops exposing the given endpoints of each
+// service. A service is unexposed when none of its endpoints remain
+// exposed.
+func (api *API) UnexposeEndpoints(args params.ServicesUnexposeEndpoints) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Services)),
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, arg := range args.Services {
+		err := api.unexposeEndpoints(arg)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) unexposeEndpoints(arg params.ServiceUnexposeEndpoints) error {
+	if len(arg.Endpoints) == 0 {
+		return errors.New("no endpoints specified")
+	}
+	service, err := api.state.Service(arg.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	endpoints := service.ExposedEndpoints()
+	for _, name := range arg.Endpoints {
+		if _, ok := endpoints[name]; !ok {
+			return errors.Errorf("endpoint %q of service %q is not exposed", name, service.Name())
+		}
+		delete(endpoints, name)
+	}
+	if len(endpoints) == 0 {
+		return service.ClearExposed()
+	}
+	return service.SetExposedEndpoints(endpoints)
+}
+
 // ServicesDeploy fetches the charms from the charm store and deploys them.
 func (api *API) ServicesDeploy(args params.ServicesDeploy) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -100,7 +207,15 @@ func (api *API) ServicesDeploy(args params.ServicesDeploy) (params.ErrorResults,
 // DeployService fetches the charm from the charm store and deploys it.
 // The logic has been factored out into a common function which is called by
 // both the legacy API on the client facade, as well as the new service facade.
+// A deployment retried with the same idempotency token succeeds without
+// deploying the service again.
 func DeployService(st *state.State, owner string, args params.ServiceDeploy) error {
+	return common.RunIdempotent(st, args.IdempotencyToken, "Deploy", nil, func() error {
+		return deployService(st, owner, args)
+	})
+}
+
+func deployService(st *state.State, owner string, args params.ServiceDeploy) error {
 	curl, err := charm.ParseURL(args.CharmUrl)
 	if err != nil {
 		return errors.Trace(err)
@@ -167,7 +282,7 @@ func DeployService(st *state.State, owner string, args params.ServiceDeploy) err
 
 // ServiceSetSettingsStrings updates the settings for the given service,
 // taking the configuration from a map of strings.
-func ServiceSetSettingsStrings(service *state.Service, settings map[string]string) error {
+func ServiceSetSettingsStrings(service *state.Service, settings map[string]string, changedBy names.Tag) error {
 	ch, _, err := service.Charm()
 	if err != nil {
 		return err
@@ -177,7 +292,7 @@ func ServiceSetSettingsStrings(service *state.Service, settings map[string]strin
 	if err != nil {
 		return err
 	}
-	return service.UpdateConfigSettings(changes)
+	return service.UpdateConfigSettingsBy(changes, changedBy)
 }
 
 func networkTagsToNames(tags []string) ([]string, error) {
@@ -129,7 +129,7 @@ func (s *serviceSuite) TestSetMetricCredentials(c *gc.C) {
 			}},
 			params.ErrorResults{[]params.ErrorResult{
 				{Error: nil},
-				{Error: &params.Error{`service "not-a-service" not found`, "not found"}},
+				{Error: &params.Error{Message: `service "not-a-service" not found`, Code: "not found"}},
 			}},
 		},
 	}
@@ -563,3 +563,119 @@ func (s *recordingStorage) Remove(path string) error {
 	s.blobs.Remove(path)
 	return nil
 }
+
+func (s *serviceSuite) TestSetAddressPolicies(c *gc.C) {
+	results, err := s.serviceApi.SetAddressPolicies(params.ServiceAddressPolicies{
+		Policies: []params.ServiceAddressPolicy{
+			{ServiceName: s.service.Name(), Policy: "space:db"},
+			{ServiceName: s.service.Name(), Policy: "nowhere"},
+			{ServiceName: "missing", Policy: "private"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `address policy "nowhere" not valid`)
+	c.Assert(results.Results[2].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.AddressPolicy(), gc.Equals, state.SpaceAddressPolicy("db"))
+}
+
+func (s *serviceSuite) TestBlockSetAddressPolicies(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockSetAddressPolicies")
+	_, err := s.serviceApi.SetAddressPolicies(params.ServiceAddressPolicies{
+		Policies: []params.ServiceAddressPolicy{
+			{ServiceName: s.service.Name(), Policy: "private"},
+		},
+	})
+	s.AssertBlocked(c, err, "TestBlockSetAddressPolicies")
+}
+
+func (s *serviceSuite) TestExposeEndpoints(c *gc.C) {
+	results, err := s.serviceApi.ExposeEndpoints(params.ServicesExposeEndpoints{
+		Services: []params.ServiceExposeEndpoints{{
+			ServiceName: s.service.Name(),
+			Endpoints: map[string]params.ExposedEndpoint{
+				"server": {Ports: []string{"443/tcp"}, CIDRs: []string{"10.0.0.0/8"}},
+			},
+		}, {
+			ServiceName: s.service.Name(),
+			Endpoints: map[string]params.ExposedEndpoint{
+				"juju-info": {},
+			},
+		}, {
+			ServiceName: s.service.Name(),
+			Endpoints: map[string]params.ExposedEndpoint{
+				"admin": {},
+			},
+		}, {
+			ServiceName: "missing",
+			Endpoints: map[string]params.ExposedEndpoint{
+				"server": {},
+			},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.IsNil)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `cannot expose endpoints of service "mysql": endpoint "admin" not found`)
+	c.Assert(results.Results[3].Error, jc.Satisfies, params.IsCodeNotFound)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsTrue)
+	c.Assert(s.service.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server":    {Ports: []string{"443/tcp"}, CIDRs: []string{"10.0.0.0/8"}},
+		"juju-info": {},
+	})
+}
+
+func (s *serviceSuite) TestUnexposeEndpoints(c *gc.C) {
+	err := s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"server":    {Ports: []string{"443/tcp"}},
+		"juju-info": {},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	unexpose := func(endpoints ...string) error {
+		results, err := s.serviceApi.UnexposeEndpoints(params.ServicesUnexposeEndpoints{
+			Services: []params.ServiceUnexposeEndpoints{{
+				ServiceName: s.service.Name(),
+				Endpoints:   endpoints,
+			}},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		return results.OneError()
+	}
+	err = unexpose("db")
+	c.Assert(err, gc.ErrorMatches, `endpoint "db" of service "mysql" is not exposed`)
+
+	err = unexpose("juju-info")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsTrue)
+	c.Assert(s.service.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server": {Ports: []string{"443/tcp"}},
+	})
+
+	err = unexpose("server")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsFalse)
+}
+
+func (s *serviceSuite) TestBlockExposeEndpoints(c *gc.C) {
+	s.BlockAllChanges(c, "TestBlockExposeEndpoints")
+	_, err := s.serviceApi.ExposeEndpoints(params.ServicesExposeEndpoints{
+		Services: []params.ServiceExposeEndpoints{{
+			ServiceName: s.service.Name(),
+			Endpoints:   map[string]params.ExposedEndpoint{"server": {}},
+		}},
+	})
+	s.AssertBlocked(c, err, "TestBlockExposeEndpoints")
+}
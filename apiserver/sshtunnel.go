@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/net/websocket"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+var (
+	// sshTunnelPort is the port on the target machine that
+	// tunnelled connections are forwarded to.
+	sshTunnelPort = 22
+
+	// sshTunnelDialTimeout bounds how long we wait for the
+	// target machine to accept a connection.
+	sshTunnelDialTimeout = 30 * time.Second
+)
+
+// sshTunnelHandler takes requests to tunnel an SSH connection to a
+// machine in the environment. This allows clients to reach machines
+// that only have addresses on private networks, by relaying the
+// connection through the API server.
+type sshTunnelHandler struct {
+	httpHandler
+}
+
+// ServeHTTP will serve up connections as a websocket.
+// Args for the HTTP request are as follows:
+//   address -> string - the address of the machine to connect to;
+//      it must be one of the addresses of a machine in the environment
+//
+// The first line sent on the socket is a JSON-encoded error result;
+// if the error is nil, the socket then carries the bytes of the SSH
+// connection in both directions until either end closes it.
+func (h *sshTunnelHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server := websocket.Server{
+		Handler: func(socket *websocket.Conn) {
+			defer socket.Close()
+			// Validate before authenticate because the authentication is
+			// dependent on the state connection that is determined during the
+			// validation.
+			stateWrapper, err := h.validateEnvironUUID(req)
+			if err != nil {
+				h.sendError(socket, err)
+				return
+			}
+			defer stateWrapper.cleanup()
+			if err := stateWrapper.authenticateUser(req); err != nil {
+				h.sendError(socket, fmt.Errorf("auth failed: %v", err))
+				return
+			}
+			address := req.URL.Query().Get("address")
+			if err := checkTunnelAddress(stateWrapper.state, address); err != nil {
+				h.sendError(socket, err)
+				return
+			}
+			target := net.JoinHostPort(address, strconv.Itoa(sshTunnelPort))
+			conn, err := net.DialTimeout("tcp", target, sshTunnelDialTimeout)
+			if err != nil {
+				h.sendError(socket, errors.Annotatef(err, "cannot connect to %s", target))
+				return
+			}
+			defer conn.Close()
+
+			// If we get to here, no more errors to report, so we report a nil
+			// error.  This way the first line of the socket is always a json
+			// formatted simple error.
+			if err := h.sendError(socket, nil); err != nil {
+				logger.Errorf("failed to send nil error at start of connection")
+				return
+			}
+			socket.PayloadType = websocket.BinaryFrame
+			logger.Debugf("tunnelling ssh connection to %s", target)
+			relay(socket, conn)
+		}}
+	server.ServeHTTP(w, req)
+}
+
+// relay copies data in both directions between the websocket
+// and the target connection, returning when either side is done.
+func relay(socket *websocket.Conn, conn net.Conn) {
+	done := make(chan struct{}, 2)
+	copyData := func(dst io.Writer, src io.Reader) {
+		if _, err := io.Copy(dst, src); err != nil {
+			logger.Debugf("ssh tunnel closed: %v", err)
+		}
+		done <- struct{}{}
+	}
+	go copyData(conn, socket)
+	go copyData(socket, conn)
+	<-done
+}
+
+// checkTunnelAddress returns an error unless the given address
+// belongs to a machine in the environment, so that the tunnel
+// cannot be used to reach arbitrary hosts.
+func checkTunnelAddress(st *state.State, address string) error {
+	if address == "" {
+		return errors.New("no address specified")
+	}
+	machines, err := st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, machine := range machines {
+		for _, addr := range machine.Addresses() {
+			if addr.Value == address {
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("address %q does not belong to a machine in the environment", address)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *sshTunnelHandler) sendError(w io.Writer, err error) error {
+	response := &params.ErrorResult{}
+	if err != nil {
+		response.Error = &params.Error{Message: err.Error()}
+	}
+	message, err := json.Marshal(response)
+	if err != nil {
+		// If we are having trouble marshalling the error, we are in big trouble.
+		logger.Errorf("failure to marshal SimpleError: %v", err)
+		return errors.Trace(err)
+	}
+	message = append(message, []byte("\n")...)
+	_, err = w.Write(message)
+	return errors.Trace(err)
+}
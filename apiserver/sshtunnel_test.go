@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"golang.org/x/net/websocket"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing/factory"
+)
+
+type sshTunnelSuite struct {
+	userAuthHttpSuite
+	listener net.Listener
+}
+
+var _ = gc.Suite(&sshTunnelSuite{})
+
+func (s *sshTunnelSuite) SetUpTest(c *gc.C) {
+	s.userAuthHttpSuite.SetUpTest(c)
+	var err error
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { s.listener.Close() })
+	port := s.listener.Addr().(*net.TCPAddr).Port
+	s.PatchValue(apiserver.SSHTunnelPort, port)
+
+	machine := s.Factory.MakeMachine(c, nil)
+	err = machine.SetProviderAddresses(network.NewAddress("127.0.0.1"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *sshTunnelSuite) tunnelURL(c *gc.C, address string) *url.URL {
+	return s.makeURL(c, "wss", "/environment/"+s.envUUID+"/sshtunnel", url.Values{
+		"address": {address},
+	})
+}
+
+func (s *sshTunnelSuite) dialTunnel(c *gc.C, address string, header http.Header) *websocket.Conn {
+	conn := s.dialWebsocketFromURL(c, s.tunnelURL(c, address).String(), header)
+	s.AddCleanup(func(*gc.C) { conn.Close() })
+	return conn
+}
+
+func (s *sshTunnelSuite) userHeader() http.Header {
+	return utils.BasicAuthHeader(s.userTag.String(), s.password)
+}
+
+func (s *sshTunnelSuite) TestNoAuth(c *gc.C) {
+	reader := bufio.NewReader(s.dialTunnel(c, "127.0.0.1", nil))
+	assertJSONError(c, reader, "auth failed: invalid request format")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *sshTunnelSuite) TestAgentLoginsRejected(c *gc.C) {
+	m, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "foo-nonce",
+	})
+	header := utils.BasicAuthHeader(m.Tag().String(), password)
+	header.Add("X-Juju-Nonce", "foo-nonce")
+	reader := bufio.NewReader(s.dialTunnel(c, "127.0.0.1", header))
+	assertJSONError(c, reader, "auth failed: invalid entity name or password")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *sshTunnelSuite) TestNoAddress(c *gc.C) {
+	reader := bufio.NewReader(s.dialTunnel(c, "", s.userHeader()))
+	assertJSONError(c, reader, "no address specified")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *sshTunnelSuite) TestUnknownAddress(c *gc.C) {
+	reader := bufio.NewReader(s.dialTunnel(c, "10.9.8.7", s.userHeader()))
+	assertJSONError(c, reader, `address "10.9.8.7" does not belong to a machine in the environment`)
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *sshTunnelSuite) TestConnectionRefused(c *gc.C) {
+	s.listener.Close()
+	reader := bufio.NewReader(s.dialTunnel(c, "127.0.0.1", s.userHeader()))
+	port := s.listener.Addr().(*net.TCPAddr).Port
+	assertJSONError(c, reader, "cannot connect to 127.0.0.1:"+strconv.Itoa(port)+": .*")
+	s.assertWebsocketClosed(c, reader)
+}
+
+func (s *sshTunnelSuite) TestRelaysData(c *gc.C) {
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := s.listener.Accept()
+		c.Check(err, jc.ErrorIsNil)
+		accepted <- conn
+	}()
+	conn := s.dialTunnel(c, "127.0.0.1", s.userHeader())
+	reader := bufio.NewReader(conn)
+	errResult := readJSONErrorLine(c, reader)
+	c.Assert(errResult.Error, gc.IsNil)
+
+	target := <-accepted
+	c.Assert(target, gc.NotNil)
+	defer target.Close()
+
+	_, err := target.Write([]byte("SSH-2.0-OpenSSH\r\n"))
+	c.Assert(err, jc.ErrorIsNil)
+	line, err := reader.ReadString('\n')
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(line, gc.Equals, "SSH-2.0-OpenSSH\r\n")
+
+	_, err = conn.Write([]byte("hello"))
+	c.Assert(err, jc.ErrorIsNil)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(target, buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(buf), gc.Equals, "hello")
+
+	// Closing the target end closes the tunnel.
+	target.Close()
+	s.assertWebsocketClosed(c, reader)
+}
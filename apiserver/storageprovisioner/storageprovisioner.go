@@ -329,6 +329,7 @@ func (s *StorageProvisionerAPI) Volumes(args params.Entities) (params.VolumeResu
 	results := params.VolumeResults{
 		Results: make([]params.VolumeResult, len(args.Entities)),
 	}
+	poolManager := poolmanager.New(s.settings)
 	one := func(arg params.Entity) (params.Volume, error) {
 		tag, err := names.ParseVolumeTag(arg.Tag)
 		if err != nil || !canAccess(tag) {
@@ -340,7 +341,21 @@ func (s *StorageProvisionerAPI) Volumes(args params.Entities) (params.VolumeResu
 		} else if err != nil {
 			return params.Volume{}, err
 		}
-		return common.VolumeFromState(volume)
+		result, err := common.VolumeFromState(volume)
+		if err != nil {
+			return params.Volume{}, err
+		}
+		// The provisioner needs the provider to destroy the volume.
+		info, err := volume.Info()
+		if err != nil {
+			return params.Volume{}, err
+		}
+		providerType, _, err := common.StoragePoolConfig(info.Pool, poolManager)
+		if err != nil {
+			return params.Volume{}, errors.Trace(err)
+		}
+		result.Provider = string(providerType)
+		return result, nil
 	}
 	for i, arg := range args.Entities {
 		var result params.VolumeResult
@@ -171,9 +171,9 @@ func (s *provisionerSuite) TestVolumesMachine(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.VolumeResults{
 		Results: []params.VolumeResult{
-			{Result: params.Volume{VolumeTag: "volume-0-0", VolumeId: "abc", HardwareId: "123", Size: 1024, Persistent: true}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Result: params.Volume{VolumeTag: "volume-0-0", VolumeId: "abc", HardwareId: "123", Size: 1024, Persistent: true, Provider: "machinescoped"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -193,10 +193,10 @@ func (s *provisionerSuite) TestVolumesEnviron(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.VolumeResults{
 		Results: []params.VolumeResult{
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 			{Error: common.ServerError(errors.NotProvisionedf(`volume "1"`))},
-			{Result: params.Volume{VolumeTag: "volume-2", VolumeId: "def", HardwareId: "456", Size: 4096}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Result: params.Volume{VolumeTag: "volume-2", VolumeId: "def", HardwareId: "456", Size: 4096, Provider: "environscoped"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -222,10 +222,10 @@ func (s *provisionerSuite) TestFilesystems(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.FilesystemResults{
 		Results: []params.FilesystemResult{
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 			{Error: common.ServerError(errors.NotProvisionedf(`filesystem "1"`))},
 			{Result: params.Filesystem{FilesystemTag: "filesystem-2", FilesystemId: "def", Size: 4096}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -263,7 +263,7 @@ func (s *provisionerSuite) TestVolumeAttachments(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `volume attachment "2" on "0" not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -303,7 +303,7 @@ func (s *provisionerSuite) TestFilesystemAttachments(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `filesystem attachment "2" on "0" not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -316,7 +316,7 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.VolumeParamsResults{
 		Results: []params.VolumeParamsResult{
-			{Error: &params.Error{`volume "0/0" is already provisioned`, ""}},
+			{Error: &params.Error{Message: `volume "0/0" is already provisioned`, Code: ""}},
 			{Result: params.VolumeParams{
 				VolumeTag: "volume-1",
 				Size:      2048,
@@ -328,7 +328,7 @@ func (s *provisionerSuite) TestVolumeParams(c *gc.C) {
 					InstanceId: "inst-id",
 				},
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -347,13 +347,13 @@ func (s *provisionerSuite) TestFilesystemParams(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, params.FilesystemParamsResults{
 		Results: []params.FilesystemParamsResult{
-			{Error: &params.Error{`filesystem "0/0" is already provisioned`, ""}},
+			{Error: &params.Error{Message: `filesystem "0/0" is already provisioned`, Code: ""}},
 			{Result: params.FilesystemParams{
 				FilesystemTag: "filesystem-1",
 				Size:          2048,
 				Provider:      "environscoped",
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -396,7 +396,7 @@ func (s *provisionerSuite) TestVolumeAttachmentParams(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `machine 2 not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -440,7 +440,7 @@ func (s *provisionerSuite) TestFilesystemAttachmentParams(c *gc.C) {
 				Code:    params.CodeNotProvisioned,
 				Message: `machine 2 not provisioned`,
 			}},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -474,7 +474,7 @@ func (s *provisionerSuite) TestSetVolumeAttachmentInfo(c *gc.C) {
 			{},
 			{}, // TODO(axw) this should fail, since volume is not provisioned
 			{}, // TODO(axw) this should fail, since machine is not provisioned
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -508,7 +508,7 @@ func (s *provisionerSuite) TestSetFilesystemAttachmentInfo(c *gc.C) {
 			{},
 			{}, // TODO(axw) this should fail, since filesystem is not provisioned
 			{}, // TODO(axw) this should fail, since machine is not provisioned
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
@@ -855,7 +855,7 @@ func (s *provisionerSuite) TestAttachmentLife(c *gc.C) {
 			{Life: params.Alive},
 			{Life: params.Alive},
 			{Life: params.Alive},
-			{Error: &params.Error{"permission denied", "unauthorized access"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
 		},
 	})
 }
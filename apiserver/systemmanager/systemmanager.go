@@ -0,0 +1,329 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The systemmanager package defines an API end point for functions
+// dealing with the whole system, across all of its environments.
+package systemmanager
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("SystemManager", 1, NewSystemManagerAPI)
+}
+
+// SystemManager defines the methods on the systemmanager API end point.
+type SystemManager interface {
+	AllEnvironments() (params.EnvironmentList, error)
+	HostedEnvironments() (params.HostedEnvironmentList, error)
+	EnvironmentSummaries() (params.EnvironmentSummaryList, error)
+	WatchAllEnvs() (params.AllWatcherId, error)
+	RotateServerCert() error
+	PublicAPIAddresses() (params.PublicAPIAddresses, error)
+	SetPublicAPIAddresses(args params.PublicAPIAddresses) error
+	ExportEnvironmentUsers(args params.Entity) (params.MigratedUsers, error)
+	ImportEnvironmentUsers(args params.ImportEnvironmentUsers) (params.UserImportResults, error)
+}
+
+// SystemManagerAPI implements the system manager interface and is
+// the concrete implementation of the api end point.
+type SystemManagerAPI struct {
+	state      *state.State
+	authorizer common.Authorizer
+	resources  *common.Resources
+}
+
+var _ SystemManager = (*SystemManagerAPI)(nil)
+
+// NewSystemManagerAPI creates a new api server endpoint for managing
+// the system. Only the owner of the state server environment may
+// use it.
+func NewSystemManagerAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*SystemManagerAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	// TODO: PERMISSIONS Change this permission check when we have
+	// real permissions. For now, only the owner of the initial environment is
+	// able to manage the system.
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+
+	return &SystemManagerAPI{
+		state:      st,
+		authorizer: authorizer,
+		resources:  resources,
+	}, nil
+}
+
+// AllEnvironments returns all environments in the system.
+func (s *SystemManagerAPI) AllEnvironments() (params.EnvironmentList, error) {
+	result := params.EnvironmentList{}
+	envs, err := s.state.AllEnvironments()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range envs {
+		result.Environments = append(result.Environments, params.Environment{
+			Name:       env.Name(),
+			UUID:       env.UUID(),
+			OwnerTag:   env.Owner().String(),
+			ServerUUID: env.ServerUUID(),
+		})
+	}
+	return result, nil
+}
+
+// HostedEnvironments summarises the environments hosted by the
+// system, other than the state server environment itself, with the
+// number of machines and services in each, so that clients can tell
+// whether workloads are still running on the system.
+func (s *SystemManagerAPI) HostedEnvironments() (params.HostedEnvironmentList, error) {
+	result := params.HostedEnvironmentList{}
+	envs, err := s.state.AllEnvironments()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range envs {
+		if env.UUID() == env.ServerUUID() {
+			continue
+		}
+		hosted, err := s.hostedEnvironment(env)
+		if err != nil {
+			return params.HostedEnvironmentList{}, errors.Annotatef(err, "cannot summarise environment %q", env.Name())
+		}
+		result.Environments = append(result.Environments, hosted)
+	}
+	return result, nil
+}
+
+func (s *SystemManagerAPI) hostedEnvironment(env *state.Environment) (params.HostedEnvironment, error) {
+	st, err := s.state.ForEnviron(env.EnvironTag())
+	if err != nil {
+		return params.HostedEnvironment{}, errors.Trace(err)
+	}
+	defer st.Close()
+	machines, err := st.AllMachines()
+	if err != nil {
+		return params.HostedEnvironment{}, errors.Trace(err)
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return params.HostedEnvironment{}, errors.Trace(err)
+	}
+	return params.HostedEnvironment{
+		Name:         env.Name(),
+		UUID:         env.UUID(),
+		OwnerTag:     env.Owner().String(),
+		Life:         params.Life(env.Life().String()),
+		MachineCount: len(machines),
+		ServiceCount: len(services),
+	}, nil
+}
+
+// EnvironmentSummaries returns the number of machines, services and
+// units in every environment in the system, the state server
+// environment included.
+func (s *SystemManagerAPI) EnvironmentSummaries() (params.EnvironmentSummaryList, error) {
+	result := params.EnvironmentSummaryList{}
+	envs, err := s.state.AllEnvironments()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for _, env := range envs {
+		summary, err := s.environmentSummary(env)
+		if err != nil {
+			return params.EnvironmentSummaryList{}, errors.Annotatef(err, "cannot summarise environment %q", env.Name())
+		}
+		result.Environments = append(result.Environments, summary)
+	}
+	return result, nil
+}
+
+func (s *SystemManagerAPI) environmentSummary(env *state.Environment) (params.EnvironmentSummary, error) {
+	st, err := s.state.ForEnviron(env.EnvironTag())
+	if err != nil {
+		return params.EnvironmentSummary{}, errors.Trace(err)
+	}
+	defer st.Close()
+	machines, err := st.AllMachines()
+	if err != nil {
+		return params.EnvironmentSummary{}, errors.Trace(err)
+	}
+	services, err := st.AllServices()
+	if err != nil {
+		return params.EnvironmentSummary{}, errors.Trace(err)
+	}
+	units := 0
+	for _, service := range services {
+		serviceUnits, err := service.AllUnits()
+		if err != nil {
+			return params.EnvironmentSummary{}, errors.Trace(err)
+		}
+		units += len(serviceUnits)
+	}
+	return params.EnvironmentSummary{
+		Name:         env.Name(),
+		UUID:         env.UUID(),
+		OwnerTag:     env.Owner().String(),
+		MachineCount: len(machines),
+		ServiceCount: len(services),
+		UnitCount:    units,
+	}, nil
+}
+
+// WatchAllEnvs starts watching events for all environments in the
+// system. The returned AllWatcherId should be used with Next on the
+// AllEnvWatcher endpoint to receive deltas.
+func (s *SystemManagerAPI) WatchAllEnvs() (params.AllWatcherId, error) {
+	w := s.state.WatchAllEnvs()
+	return params.AllWatcherId{
+		AllWatcherId: s.resources.Register(w),
+	}, nil
+}
+
+// RotateServerCert requests that every state server generate a new
+// server certificate, signed by the existing CA certificate, and start
+// serving it. Agents and clients already trust the CA certificate, so
+// they continue to connect without change. The CA certificate itself
+// is never replaced, so rotation is refused once it has expired.
+func (s *SystemManagerAPI) RotateServerCert() error {
+	cfg, err := s.state.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	caCertPEM, ok := cfg.CACert()
+	if !ok {
+		return errors.New("cannot rotate server certificate: no CA certificate")
+	}
+	caCert, err := cert.ParseCert(caCertPEM)
+	if err != nil {
+		return errors.Annotate(err, "cannot rotate server certificate")
+	}
+	if expiry := caCert.NotAfter; !time.Now().Before(expiry) {
+		return errors.Errorf("cannot rotate server certificate: CA certificate expired at %v", expiry.UTC())
+	}
+	_, err = s.state.RotateServerCert()
+	return errors.Trace(err)
+}
+
+// PublicAPIAddresses returns the addresses that agents and clients
+// are told to use in place of the addresses of the API servers. No
+// addresses are returned if the servers' own addresses are used.
+func (s *SystemManagerAPI) PublicAPIAddresses() (params.PublicAPIAddresses, error) {
+	hps, err := s.state.PublicAPIHostPorts()
+	if err != nil {
+		return params.PublicAPIAddresses{}, errors.Trace(err)
+	}
+	return params.PublicAPIAddresses{
+		Addresses: network.HostPortsToStrings(hps),
+	}, nil
+}
+
+// SetPublicAPIAddresses sets the host:port addresses, such as those of
+// a load balancer in front of the API servers, that agents and clients
+// are told to use in place of the addresses of the servers. Setting no
+// addresses restores the use of the servers' own addresses.
+func (s *SystemManagerAPI) SetPublicAPIAddresses(args params.PublicAPIAddresses) error {
+	hps, err := network.ParseHostPorts(args.Addresses...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(s.state.SetPublicAPIHostPorts(hps))
+}
+
+// ExportEnvironmentUsers returns the users with access to the given
+// environment, so that their access can be recreated on another system
+// when the environment moves to it.
+func (s *SystemManagerAPI) ExportEnvironmentUsers(args params.Entity) (params.MigratedUsers, error) {
+	envTag, err := names.ParseEnvironTag(args.Tag)
+	if err != nil {
+		return params.MigratedUsers{}, errors.Trace(err)
+	}
+	st, err := s.state.ForEnviron(envTag)
+	if err != nil {
+		return params.MigratedUsers{}, errors.Trace(err)
+	}
+	defer st.Close()
+	users, err := st.ExportEnvironmentUsers()
+	if err != nil {
+		return params.MigratedUsers{}, errors.Trace(err)
+	}
+	result := params.MigratedUsers{
+		EnvironTag: envTag.String(),
+		Users:      make([]params.MigratedUser, len(users)),
+	}
+	for i, user := range users {
+		result.Users[i] = params.MigratedUser{
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			CreatedBy:   user.CreatedBy,
+			DateCreated: user.DateCreated,
+			Disabled:    user.Disabled,
+		}
+	}
+	return result, nil
+}
+
+// ImportEnvironmentUsers gives users exported from another system
+// access to the given environment, creating or mapping them to local
+// users, and reports the users that could not be imported.
+func (s *SystemManagerAPI) ImportEnvironmentUsers(args params.ImportEnvironmentUsers) (params.UserImportResults, error) {
+	envTag, err := names.ParseEnvironTag(args.EnvironTag)
+	if err != nil {
+		return params.UserImportResults{}, errors.Trace(err)
+	}
+	st, err := s.state.ForEnviron(envTag)
+	if err != nil {
+		return params.UserImportResults{}, errors.Trace(err)
+	}
+	defer st.Close()
+	users := make([]state.MigratedUser, len(args.Users))
+	for i, user := range args.Users {
+		users[i] = state.MigratedUser{
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			CreatedBy:   user.CreatedBy,
+			DateCreated: user.DateCreated,
+			Disabled:    user.Disabled,
+		}
+	}
+	imports, err := st.ImportEnvironmentUsers(users, args.UserMap)
+	if err != nil {
+		return params.UserImportResults{}, errors.Trace(err)
+	}
+	result := params.UserImportResults{
+		Results: make([]params.UserImportResult, len(imports)),
+	}
+	for i, imported := range imports {
+		result.Results[i] = params.UserImportResult{
+			UserName: imported.UserName,
+			Target:   imported.Target,
+			Created:  imported.Created,
+			Conflict: imported.Conflict,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package systemmanager_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/systemmanager"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type systemManagerSuite struct {
+	jujutesting.JujuConnSuite
+
+	systemManager *systemmanager.SystemManagerAPI
+	resources     *common.Resources
+	authorizer    apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&systemManagerSuite{})
+
+func (s *systemManagerSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	systemManager, err := systemmanager.NewSystemManagerAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.systemManager = systemManager
+}
+
+func (s *systemManagerSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = names.NewUnitTag("mysql/0")
+	endPoint, err := systemmanager.NewSystemManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *systemManagerSuite) TestNewAPIRefusesNonAdmins(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{NoEnvUser: true})
+	anAuthoriser := s.authorizer
+	anAuthoriser.Tag = user.Tag()
+	endPoint, err := systemmanager.NewSystemManagerAPI(s.State, s.resources, anAuthoriser)
+	c.Assert(endPoint, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *systemManagerSuite) TestAllEnvironments(c *gc.C) {
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "hosted"})
+	defer st.Close()
+
+	result, err := s.systemManager.AllEnvironments()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, env := range result.Environments {
+		c.Check(env.ServerUUID, gc.Equals, s.State.EnvironUUID())
+		names = append(names, env.Name)
+	}
+	c.Assert(names, jc.SameContents, []string{"dummyenv", "hosted"})
+}
+
+func (s *systemManagerSuite) TestWatchAllEnvs(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	watcherId, err := s.systemManager.WatchAllEnvs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	watcher, ok := s.resources.Get(watcherId.AllWatcherId).(*state.AllEnvWatcher)
+	c.Assert(ok, jc.IsTrue)
+	defer func() {
+		err := watcher.Stop()
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+
+	deltas, err := watcher.Next()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(deltas, gc.Not(gc.HasLen), 0)
+	for _, delta := range deltas {
+		c.Check(delta.EnvUUID, gc.Equals, s.State.EnvironUUID())
+	}
+}
+
+func (s *systemManagerSuite) TestRotateServerCert(c *gc.C) {
+	err := s.systemManager.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	serial, err := s.State.ServerCertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 1)
+}
+
+func (s *systemManagerSuite) TestSetPublicAPIAddresses(c *gc.C) {
+	err := s.systemManager.SetPublicAPIAddresses(params.PublicAPIAddresses{
+		Addresses: []string{"api.example.com:443", "10.0.0.1:17070"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	hps, err := s.State.PublicAPIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hps, jc.DeepEquals, []network.HostPort{
+		network.NewHostPorts(443, "api.example.com")[0],
+		network.NewHostPorts(17070, "10.0.0.1")[0],
+	})
+
+	result, err := s.systemManager.PublicAPIAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Addresses, jc.DeepEquals, []string{"api.example.com:443", "10.0.0.1:17070"})
+}
+
+func (s *systemManagerSuite) TestSetPublicAPIAddressesInvalid(c *gc.C) {
+	err := s.systemManager.SetPublicAPIAddresses(params.PublicAPIAddresses{
+		Addresses: []string{"api.example.com"},
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot parse "api.example.com" as address:port: .*`)
+}
+
+func (s *systemManagerSuite) TestExportImportEnvironmentUsers(c *gc.C) {
+	alice := s.Factory.MakeUser(c, &factory.UserParams{Name: "alice", DisplayName: "Alice"})
+	source := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "source", Owner: alice.UserTag()})
+	defer source.Close()
+	target := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "target"})
+	defer target.Close()
+	sourceTag := names.NewEnvironTag(source.EnvironUUID())
+
+	exported, err := s.systemManager.ExportEnvironmentUsers(params.Entity{Tag: sourceTag.String()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exported.EnvironTag, gc.Equals, sourceTag.String())
+	c.Assert(exported.Users, gc.HasLen, 1)
+	c.Assert(exported.Users[0].UserName, gc.Equals, "alice@local")
+	c.Assert(exported.Users[0].DisplayName, gc.Equals, "Alice")
+
+	users := append(exported.Users, params.MigratedUser{UserName: "dave@local", CreatedBy: "admin@local"})
+	result, err := s.systemManager.ImportEnvironmentUsers(params.ImportEnvironmentUsers{
+		EnvironTag: names.NewEnvironTag(target.EnvironUUID()).String(),
+		Users:      users,
+		UserMap:    map[string]string{"alice@local": "alice", "dave@local": "dan"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, jc.DeepEquals, []params.UserImportResult{
+		{UserName: "alice@local", Target: "alice@local"},
+		{UserName: "dave@local", Target: "dan@local", Conflict: `user "dan@local" does not exist`},
+	})
+	_, err = target.EnvironmentUser(alice.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *systemManagerSuite) TestExportEnvironmentUsersInvalidTag(c *gc.C) {
+	_, err := s.systemManager.ExportEnvironmentUsers(params.Entity{Tag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid environment tag`)
+}
+
+func (s *systemManagerSuite) TestEnvironmentSummaries(c *gc.C) {
+	s.Factory.MakeUnit(c, nil)
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "hosted"})
+	defer st.Close()
+	f := factory.NewFactory(st)
+	service := f.MakeService(c, nil)
+	f.MakeUnit(c, &factory.UnitParams{Service: service})
+	f.MakeUnit(c, &factory.UnitParams{Service: service})
+	f.MakeMachine(c, nil)
+
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := s.systemManager.EnvironmentSummaries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, jc.SameContents, []params.EnvironmentSummary{{
+		Name:         env.Name(),
+		UUID:         env.UUID(),
+		OwnerTag:     s.AdminUserTag(c).String(),
+		MachineCount: 1,
+		ServiceCount: 1,
+		UnitCount:    1,
+	}, {
+		Name:         "hosted",
+		UUID:         st.EnvironUUID(),
+		OwnerTag:     s.AdminUserTag(c).String(),
+		MachineCount: 3,
+		ServiceCount: 1,
+		UnitCount:    2,
+	}})
+}
+
+func (s *systemManagerSuite) TestHostedEnvironments(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "hosted"})
+	defer st.Close()
+	f := factory.NewFactory(st)
+	f.MakeMachine(c, nil)
+	f.MakeMachine(c, nil)
+	f.MakeService(c, nil)
+
+	result, err := s.systemManager.HostedEnvironments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Environments, jc.DeepEquals, []params.HostedEnvironment{{
+		Name:         "hosted",
+		UUID:         st.EnvironUUID(),
+		OwnerTag:     s.AdminUserTag(c).String(),
+		Life:         params.Alive,
+		MachineCount: 2,
+		ServiceCount: 1,
+	}})
+}
@@ -0,0 +1,29 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// AppendActionOutput records output written by running actions, so
+// that it can be watched before the actions complete.
+func (u *UniterAPIV3) AppendActionOutput(args params.ActionOutputChunks) (params.ErrorResults, error) {
+	actionFn, err := u.authAndActionFromTagFn()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Chunks)),
+	}
+	for i, chunk := range args.Chunks {
+		action, err := actionFn(chunk.ActionTag)
+		if err == nil {
+			err = action.AppendOutput(chunk.Stream, chunk.Data)
+		}
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
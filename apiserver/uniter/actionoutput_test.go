@@ -0,0 +1,51 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+)
+
+func (s *uniterV3Suite) TestAppendActionOutput(c *gc.C) {
+	running, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	running, err = running.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.AppendActionOutput(params.ActionOutputChunks{
+		Chunks: []params.ActionOutputChunk{
+			{ActionTag: running.Tag().String(), Stream: "stdout", Data: "hello\n"},
+			{ActionTag: running.Tag().String(), Stream: "stderr", Data: "oops\n"},
+			{ActionTag: running.Tag().String(), Stream: "stdin", Data: "nope\n"},
+			{ActionTag: pending.Tag().String(), Stream: "stdout", Data: "early\n"},
+			{ActionTag: other.Tag().String(), Stream: "stdout", Data: "mine\n"},
+			{ActionTag: "unit-wordpress-0", Stream: "stdout", Data: "bad tag\n"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 6)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `.*output stream "stdin" not valid`)
+	c.Assert(result.Results[3].Error, gc.ErrorMatches, ".*action is not running")
+	c.Assert(result.Results[4].Error, gc.DeepEquals, apiservertesting.ErrUnauthorized)
+	c.Assert(result.Results[5].Error, gc.NotNil)
+
+	output, err := running.Output(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(output, gc.HasLen, 2)
+	c.Assert(output[0].Stream, gc.Equals, state.ActionOutputStdout)
+	c.Assert(output[0].Data, gc.Equals, "hello\n")
+	c.Assert(output[1].Stream, gc.Equals, state.ActionOutputStderr)
+	c.Assert(output[1].Data, gc.Equals, "oops\n")
+}
@@ -6,7 +6,8 @@ package uniter
 import "github.com/juju/juju/apiserver/common"
 
 var (
-	GetZone = &getZone
+	GetZone  = &getZone
+	IsLeader = &isLeader
 )
 
 type StorageStateInterface storageStateInterface
@@ -0,0 +1,149 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// Goal state statuses of entities that have no status of their own.
+const (
+	goalStateDying  = "dying"
+	goalStateJoined = "joined"
+)
+
+// GoalStates returns the goal state of each unit: the units and
+// relations its service is expected to have, whether or not they are
+// up yet.
+func (u *UniterAPIV3) GoalStates(args params.Entities) (params.GoalStateResults, error) {
+	result := params.GoalStateResults{
+		Results: make([]params.GoalStateResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.GoalStateResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var unit *state.Unit
+			unit, err = u.getUnit(tag)
+			if err == nil {
+				result.Results[i].Result, err = u.oneGoalState(unit)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (u *UniterAPIV3) oneGoalState(unit *state.Unit) (*params.GoalState, error) {
+	service, err := unit.Service()
+	if err != nil {
+		return nil, err
+	}
+	units, err := service.AllUnits()
+	if err != nil {
+		return nil, err
+	}
+	goalState := &params.GoalState{
+		Relations: make(map[string]params.UnitsGoalState),
+	}
+	if goalState.Units, err = unitsGoalState(units); err != nil {
+		return nil, err
+	}
+	relations, err := service.Relations()
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range relations {
+		ep, err := rel.Endpoint(service.Name())
+		if err != nil {
+			return nil, err
+		}
+		related, err := rel.RelatedEndpoints(service.Name())
+		if err != nil {
+			return nil, err
+		}
+		entities, ok := goalState.Relations[ep.Name]
+		if !ok {
+			entities = make(params.UnitsGoalState)
+			goalState.Relations[ep.Name] = entities
+		}
+		for _, relatedEp := range related {
+			relStatus := goalStateJoined
+			if rel.Life() != state.Alive {
+				relStatus = goalStateDying
+			}
+			entities[relatedEp.ServiceName] = params.GoalStateStatus{Status: relStatus}
+			relatedUnits, err := u.relatedGoalStateUnits(unit, relatedEp)
+			if err != nil {
+				return nil, err
+			}
+			unitStates, err := unitsGoalState(relatedUnits)
+			if err != nil {
+				return nil, err
+			}
+			for name, status := range unitStates {
+				entities[name] = status
+			}
+		}
+	}
+	return goalState, nil
+}
+
+// relatedGoalStateUnits returns the units at the other end of a relation
+// that the unit is expected to see: all the units of the related
+// service, except in container-scoped relations, where only the units
+// sharing the unit's container are seen.
+func (u *UniterAPIV3) relatedGoalStateUnits(unit *state.Unit, ep state.Endpoint) ([]*state.Unit, error) {
+	service, err := u.uniterBaseAPI.st.Service(ep.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	units, err := service.AllUnits()
+	if err != nil || ep.Scope != charm.ScopeContainer {
+		return units, err
+	}
+	var colocated []*state.Unit
+	principal, _ := unit.PrincipalName()
+	for _, other := range units {
+		otherPrincipal, _ := other.PrincipalName()
+		if otherPrincipal == unit.Name() || other.Name() == principal {
+			colocated = append(colocated, other)
+		}
+	}
+	return colocated, nil
+}
+
+// unitsGoalState returns the goal state status of each of the given
+// units: the unit's workload status, or dying if it is no longer alive.
+func unitsGoalState(units []*state.Unit) (params.UnitsGoalState, error) {
+	result := make(params.UnitsGoalState)
+	for _, unit := range units {
+		if unit.Life() != state.Alive {
+			result[unit.Name()] = params.GoalStateStatus{Status: goalStateDying}
+			continue
+		}
+		status, err := unit.Status()
+		if err != nil {
+			return nil, err
+		}
+		result[unit.Name()] = params.GoalStateStatus{
+			Status: string(status.Status),
+			Since:  status.Since,
+		}
+	}
+	return result, nil
+}
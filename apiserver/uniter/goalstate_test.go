@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+)
+
+// clearGoalStateSince checks that the status of every unit in the goal
+// state has a time, and clears it so the goal state can be compared.
+func clearGoalStateSince(c *gc.C, goalState *params.GoalState) {
+	clear := func(entities params.UnitsGoalState) {
+		for name, status := range entities {
+			if strings.Contains(name, "/") {
+				c.Check(status.Since, gc.NotNil, gc.Commentf("%s", name))
+			}
+			status.Since = nil
+			entities[name] = status
+		}
+	}
+	clear(goalState.Units)
+	for _, entities := range goalState.Relations {
+		clear(entities)
+	}
+}
+
+func (s *uniterV3Suite) TestGoalStates(c *gc.C) {
+	s.addRelation(c, "wordpress", "mysql")
+	s.addRelatedService(c, "wordpress", "logging", s.wordpressUnit)
+	_, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.GoalStates(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "service-wordpress"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0], gc.DeepEquals, params.GoalStateResult{Error: apiservertesting.ErrUnauthorized})
+	c.Assert(result.Results[2], gc.DeepEquals, params.GoalStateResult{Error: apiservertesting.ErrUnauthorized})
+
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	goalState := result.Results[1].Result
+	c.Assert(goalState, gc.NotNil)
+	clearGoalStateSince(c, goalState)
+	unknown := params.GoalStateStatus{Status: "unknown"}
+	joined := params.GoalStateStatus{Status: "joined"}
+	c.Assert(goalState, jc.DeepEquals, &params.GoalState{
+		Units: params.UnitsGoalState{
+			"wordpress/0": unknown,
+			"wordpress/1": unknown,
+		},
+		Relations: map[string]params.UnitsGoalState{
+			"db": {
+				"mysql":   joined,
+				"mysql/0": unknown,
+			},
+			// Only the subordinate in the unit's own container
+			// is expected.
+			"logging-dir": {
+				"logging":   joined,
+				"logging/0": unknown,
+			},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestGoalStatesDyingRelation(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	relUnit, err := rel.Unit(s.mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = relUnit.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.GoalStates(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-wordpress-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	goalState := result.Results[0].Result
+	clearGoalStateSince(c, goalState)
+	c.Assert(goalState.Relations["db"], jc.DeepEquals, params.UnitsGoalState{
+		"mysql":   {Status: "dying"},
+		"mysql/0": {Status: "unknown"},
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// NetworkInfo returns the addresses the unit should use for each of the
+// requested endpoint bindings, keyed on binding name. An endpoint bound
+// to a space uses the addresses of the unit's machine in that space; an
+// unbound endpoint uses the unit's private address.
+func (u *UniterAPIV3) NetworkInfo(args params.NetworkInfoParams) (params.NetworkInfoResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	tag, err := names.ParseUnitTag(args.Unit)
+	if err != nil || !canAccess(tag) {
+		return params.NetworkInfoResults{}, common.ErrPerm
+	}
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	service, err := unit.Service()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	endpoints, err := service.Endpoints()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	bindings, err := service.EndpointBindings()
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	machine, err := u.unitMachine(tag)
+	if err != nil {
+		return params.NetworkInfoResults{}, err
+	}
+	addresses := machine.Addresses()
+
+	known := make(map[string]bool)
+	for _, ep := range endpoints {
+		known[ep.Name] = true
+	}
+	result := params.NetworkInfoResults{
+		Results: make(map[string]params.NetworkInfoResult),
+	}
+	for _, binding := range args.Bindings {
+		if !known[binding] {
+			result.Results[binding] = params.NetworkInfoResult{
+				Error: common.ServerError(errors.NotValidf("binding name %q", binding)),
+			}
+			continue
+		}
+		info, err := u.bindingNetworkInfo(bindings[binding], addresses)
+		info.Error = common.ServerError(err)
+		result.Results[binding] = info
+	}
+	return result, nil
+}
+
+// bindingNetworkInfo returns the network information of an endpoint
+// bound to the given space, or of an unbound endpoint if the space is
+// empty, for a unit on a machine with the given addresses.
+func (u *UniterAPIV3) bindingNetworkInfo(space string, addresses []network.Address) (params.NetworkInfoResult, error) {
+	if space == "" {
+		address := network.SelectInternalAddress(addresses, false)
+		if address == "" {
+			return params.NetworkInfoResult{}, errors.NotFoundf("private address")
+		}
+		info := params.NetworkInfoResult{IngressAddresses: []string{address}}
+		if subnet := hostSubnet(address); subnet != "" {
+			info.EgressSubnets = []string{subnet}
+		}
+		return info, nil
+	}
+	subnets, err := u.uniterBaseAPI.st.SpaceSubnets(space)
+	if err != nil {
+		return params.NetworkInfoResult{}, err
+	}
+	info := params.NetworkInfoResult{Space: space}
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		ip := net.ParseIP(address.Value)
+		if ip == nil {
+			continue
+		}
+		for _, subnet := range subnets {
+			_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+			if err != nil || !ipNet.Contains(ip) {
+				continue
+			}
+			info.IngressAddresses = append(info.IngressAddresses, address.Value)
+			if !seen[subnet.CIDR()] {
+				seen[subnet.CIDR()] = true
+				info.EgressSubnets = append(info.EgressSubnets, subnet.CIDR())
+			}
+			break
+		}
+	}
+	if len(info.IngressAddresses) == 0 {
+		return params.NetworkInfoResult{}, errors.NotFoundf("address in space %q", space)
+	}
+	return info, nil
+}
+
+// hostSubnet returns the subnet holding only the given IP address, or
+// the empty string if the address is not an IP address.
+func hostSubnet(address string) string {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return address + "/32"
+	default:
+		return address + "/128"
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+func (s *uniterV3Suite) setUpNetworkInfo(c *gc.C) {
+	for cidr, space := range map[string]string{
+		"10.0.0.0/24":    "internal",
+		"192.168.0.0/24": "public",
+		"172.16.0.0/24":  "storage",
+	} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr, SpaceName: space})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.machine0.SetProviderAddresses(
+		network.NewScopedAddress("10.0.0.10", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.0.10", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.11", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{
+		"db":    "internal",
+		"url":   "public",
+		"cache": "storage",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *uniterV3Suite) TestNetworkInfo(c *gc.C) {
+	s.setUpNetworkInfo(c)
+	result, err := s.uniter.NetworkInfo(params.NetworkInfoParams{
+		Unit:     "unit-wordpress-0",
+		Bindings: []string{"db", "url", "juju-info", "cache", "unknown"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.NetworkInfoResults{
+		Results: map[string]params.NetworkInfoResult{
+			"db": {
+				Space:            "internal",
+				IngressAddresses: []string{"10.0.0.10", "10.0.0.11"},
+				EgressSubnets:    []string{"10.0.0.0/24"},
+			},
+			"url": {
+				Space:            "public",
+				IngressAddresses: []string{"192.168.0.10"},
+				EgressSubnets:    []string{"192.168.0.0/24"},
+			},
+			"juju-info": {
+				IngressAddresses: []string{"10.0.0.10"},
+				EgressSubnets:    []string{"10.0.0.10/32"},
+			},
+			"cache": {
+				Error: &params.Error{Message: `address in space "storage" not found`, Code: params.CodeNotFound},
+			},
+			"unknown": {
+				Error: &params.Error{Message: `binding name "unknown" not valid`},
+			},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestNetworkInfoUnauthorized(c *gc.C) {
+	for _, tag := range []string{"unit-mysql-0", "unit-foo-42", "machine-0"} {
+		_, err := s.uniter.NetworkInfo(params.NetworkInfoParams{
+			Unit:     tag,
+			Bindings: []string{"db"},
+		})
+		c.Assert(err, gc.ErrorMatches, "permission denied")
+	}
+}
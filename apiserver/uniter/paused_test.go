@@ -0,0 +1,35 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+)
+
+func (s *uniterV3Suite) TestUnitPaused(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "machine-0"},
+	}}
+	result, err := s.uniter.UnitPaused(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: false},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.wordpressUnit.SetPaused(true)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.UnitPaused(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], gc.DeepEquals, params.BoolResult{Result: true})
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+// isLeader reports whether the named unit is the leader of the named
+// service. It exists to be patched out in tests.
+var isLeader = func(serviceId, unitId string) bool {
+	return leadership.NewLeadershipManager(lease.Manager()).Leader(serviceId, unitId)
+}
+
+// ReadServiceRelationSettings returns the settings that each given
+// service shares with the other services in the given relation, as
+// seen by the given unit in the relation.
+func (u *UniterAPIV3) ReadServiceRelationSettings(args params.RelationUnitServices) (params.SettingsResults, error) {
+	result := params.SettingsResults{
+		Results: make([]params.SettingsResult, len(args.RelationUnitServices)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.SettingsResults{}, err
+	}
+	for i, arg := range args.RelationUnitServices {
+		unitTag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		serviceTag, err := names.ParseServiceTag(arg.Service)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unitTag)
+		if err == nil {
+			var settings map[string]interface{}
+			settings, err = relUnit.Relation().ServiceSettings(serviceTag.Id())
+			if err == nil {
+				result.Results[i].Settings, err = convertRelationSettings(settings)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UpdateServiceRelationSettings persists the changes made by each given
+// unit to the settings its service shares with the other services in
+// the given relation. Only the leader of the service may change them.
+// Keys with empty values are considered a signal to delete these
+// values.
+func (u *UniterAPIV3) UpdateServiceRelationSettings(args params.RelationUnitsSettings) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unitTag, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		var rel *state.Relation
+		var unit *state.Unit
+		rel, unit, err = u.getRelationAndUnit(canAccess, arg.Relation, unitTag)
+		if err == nil {
+			serviceName := unit.ServiceName()
+			if !isLeader(serviceName, unit.Name()) {
+				err = common.ErrPerm
+			} else {
+				changes := make(map[string]interface{})
+				for k, v := range arg.Settings {
+					if v == "" {
+						changes[k] = nil
+					} else {
+						changes[k] = v
+					}
+				}
+				err = rel.UpdateServiceSettings(serviceName, changes)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/uniter"
+)
+
+func (s *uniterV3Suite) TestReadServiceRelationSettings(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	err := rel.UpdateServiceSettings("mysql", map[string]interface{}{"database": "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitServices{RelationUnitServices: []params.RelationUnitService{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Service: "service-mysql"},
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Service: "service-wordpress"},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0", Service: "service-mysql"},
+		{Relation: "relation-42", Unit: "unit-wordpress-0", Service: "service-mysql"},
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Service: "unit-mysql-0"},
+	}}
+	result, err := s.uniter.ReadServiceRelationSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.SettingsResults{
+		Results: []params.SettingsResult{
+			{Settings: params.Settings{"database": "wordpress"}},
+			{Settings: params.Settings{}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestUpdateServiceRelationSettings(c *gc.C) {
+	leader := true
+	s.PatchValue(uniter.IsLeader, func(serviceId, unitId string) bool {
+		c.Check(serviceId, gc.Equals, "wordpress")
+		c.Check(unitId, gc.Equals, "wordpress/0")
+		return leader
+	})
+	rel := s.addRelation(c, "wordpress", "mysql")
+	err := rel.UpdateServiceSettings("wordpress", map[string]interface{}{"stale": "value"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnitsSettings{RelationUnits: []params.RelationUnitSettings{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0", Settings: params.Settings{
+			"url":   "http://example.com",
+			"stale": "",
+		}},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0", Settings: params.Settings{"foo": "bar"}},
+		{Relation: "relation-42", Unit: "unit-wordpress-0", Settings: params.Settings{"foo": "bar"}},
+	}}
+	result, err := s.uniter.UpdateServiceRelationSettings(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	settings, err := rel.ServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{"url": "http://example.com"})
+
+	// Only the leader may change the settings.
+	leader = false
+	result, err = s.uniter.UpdateServiceRelationSettings(params.RelationUnitsSettings{
+		RelationUnits: args.RelationUnits[:1],
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{{Error: apiservertesting.ErrUnauthorized}},
+	})
+}
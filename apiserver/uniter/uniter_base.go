@@ -1411,15 +1411,10 @@ func (u *uniterBaseAPI) watchOneUnitAddresses(tag names.UnitTag) (string, error)
 	if err != nil {
 		return "", err
 	}
-	machineId, err := unit.AssignedMachineId()
+	watch, err := unit.WatchAddresses()
 	if err != nil {
 		return "", err
 	}
-	machine, err := u.st.Machine(machineId)
-	if err != nil {
-		return "", err
-	}
-	watch := machine.WatchAddresses()
 	// Consume the initial event. Technically, API
 	// calls to Watch 'transmit' the initial event
 	// in the Watch response. But NotifyWatchers
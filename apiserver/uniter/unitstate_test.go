@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+)
+
+func (s *uniterV3Suite) TestUniterStates(c *gc.C) {
+	err := s.wordpressUnit.SetUniterState("kind: continue\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.UniterStates(params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: "kind: continue\nstep: pending\n"},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *uniterV3Suite) TestSetUniterStates(c *gc.C) {
+	result, err := s.uniter.SetUniterStates(params.SetUniterStateParams{
+		Params: []params.SetUniterStateParam{
+			{Entity: params.Entity{Tag: "unit-mysql-0"}, State: "kind: install\n"},
+			{Entity: params.Entity{Tag: "unit-wordpress-0"}, State: "kind: install\n"},
+			{Entity: params.Entity{Tag: "unit-foo-42"}, State: "kind: install\n"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	data, err := s.wordpressUnit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "kind: install\n")
+	data, err = s.mysqlUnit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "")
+}
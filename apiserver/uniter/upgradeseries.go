@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// WatchUpgradeSeriesNotifications returns a NotifyWatcher for observing
+// changes to the series upgrade of each unit's machine.
+func (u *UniterAPIV3) WatchUpgradeSeriesNotifications(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.NotifyWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		watcherId := ""
+		if canAccess(tag) {
+			watcherId, err = u.watchOneUnitUpgradeSeries(tag)
+		}
+		result.Results[i].NotifyWatcherId = watcherId
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// UpgradeSeriesUnitStatus returns each unit's progress in the series
+// upgrade of its machine. Units that are not taking part in a series
+// upgrade have an empty status.
+func (u *UniterAPIV3) UpgradeSeriesUnitStatus(args params.Entities) (params.UpgradeSeriesStatusResults, error) {
+	result := params.UpgradeSeriesStatusResults{
+		Results: make([]params.UpgradeSeriesStatusResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.UpgradeSeriesStatusResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var status state.UpgradeSeriesStatus
+			status, err = u.oneUnitUpgradeSeriesStatus(tag)
+			result.Results[i].Status = params.UpgradeSeriesStatus(status)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// SetUpgradeSeriesUnitStatus records that each unit has finished its
+// part of the current phase of its machine's series upgrade.
+func (u *UniterAPIV3) SetUpgradeSeriesUnitStatus(args params.UpgradeSeriesStatusParams) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Params)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	for i, p := range args.Params {
+		tag, err := names.ParseUnitTag(p.Entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			var machine *state.Machine
+			machine, err = u.unitMachine(tag)
+			if err == nil {
+				err = machine.SetUpgradeSeriesUnitStatus(tag.Id(), state.UpgradeSeriesStatus(p.Status))
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// unitMachine returns the machine the unit with the given tag is
+// assigned to, or the machine of its principal for a subordinate.
+func (u *UniterAPIV3) unitMachine(tag names.UnitTag) (*state.Machine, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return nil, err
+	}
+	machineId, err := unit.AssignedMachineId()
+	if err != nil {
+		return nil, err
+	}
+	return u.uniterBaseAPI.st.Machine(machineId)
+}
+
+func (u *UniterAPIV3) oneUnitUpgradeSeriesStatus(tag names.UnitTag) (state.UpgradeSeriesStatus, error) {
+	machine, err := u.unitMachine(tag)
+	if err != nil {
+		return "", err
+	}
+	status, err := machine.UpgradeSeriesUnitStatus(tag.Id())
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	return status, err
+}
+
+func (u *UniterAPIV3) watchOneUnitUpgradeSeries(tag names.UnitTag) (string, error) {
+	machine, err := u.unitMachine(tag)
+	if err != nil {
+		return "", err
+	}
+	watch := machine.WatchUpgradeSeriesNotifications()
+	// Consume the initial event. Technically, API
+	// calls to Watch 'transmit' the initial event
+	// in the Watch response. But NotifyWatchers
+	// have no state to transmit.
+	if _, ok := <-watch.Changes(); ok {
+		return u.resources.Register(watch), nil
+	}
+	return "", watcher.EnsureErr(watch)
+}
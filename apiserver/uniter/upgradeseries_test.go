@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+func (s *uniterV3Suite) TestUpgradeSeriesUnitStatus(c *gc.C) {
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "machine-0"},
+	}}
+	result, err := s.uniter.UpgradeSeriesUnitStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.UpgradeSeriesStatusResults{
+		Results: []params.UpgradeSeriesStatusResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.machine0.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.UpgradeSeriesUnitStatus(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[1], gc.DeepEquals, params.UpgradeSeriesStatusResult{
+		Status: params.UpgradeSeriesPrepareStarted,
+	})
+}
+
+func (s *uniterV3Suite) TestSetUpgradeSeriesUnitStatus(c *gc.C) {
+	err := s.machine0.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.uniter.SetUpgradeSeriesUnitStatus(params.UpgradeSeriesStatusParams{
+		Params: []params.UpgradeSeriesStatusParam{
+			{Entity: params.Entity{Tag: "unit-mysql-0"}, Status: params.UpgradeSeriesPrepareCompleted},
+			{Entity: params.Entity{Tag: "unit-wordpress-0"}, Status: params.UpgradeSeriesPrepareCompleted},
+			{Entity: params.Entity{Tag: "unit-foo-42"}, Status: params.UpgradeSeriesPrepareCompleted},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+	status, err := s.machine0.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *uniterV3Suite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.WatchUpgradeSeriesNotifications(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop when done
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned" in
+	// the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	err = s.machine0.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
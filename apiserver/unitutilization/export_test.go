@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitutilization
+
+import "github.com/juju/juju/apiserver/common"
+
+// NewFacadeForTest returns a Facade backed by the given Backend.
+func NewFacadeForTest(backend Backend, authorizer common.Authorizer) (*Facade, error) {
+	return newFacade(backend, authorizer)
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitutilization implements the API facade used by machine
+// agents to report the resources used by the processes of the units
+// on their machines.
+package unitutilization
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("UnitUtilization", 1, NewFacade)
+}
+
+// Backend defines the state methods this facade needs, so they can be
+// mocked for testing.
+type Backend interface {
+	UnitMachineId(unitName string) (string, error)
+	AddUnitUtilization([]state.UnitUtilization) error
+}
+
+type stateShim struct {
+	*state.State
+}
+
+// UnitMachineId returns the id of the machine to which the named unit
+// is assigned.
+func (s stateShim) UnitMachineId(unitName string) (string, error) {
+	unit, err := s.Unit(unitName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return unit.AssignedMachineId()
+}
+
+// Facade implements the UnitUtilization API.
+type Facade struct {
+	backend   Backend
+	machineId string
+}
+
+// NewFacade returns a new UnitUtilization facade. Only machine agents
+// may use it, and only to report units assigned to their machines.
+func NewFacade(st *state.State, _ *common.Resources, authorizer common.Authorizer) (*Facade, error) {
+	return newFacade(stateShim{st}, authorizer)
+}
+
+func newFacade(backend Backend, authorizer common.Authorizer) (*Facade, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	tag, ok := authorizer.GetAuthTag().(names.MachineTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	return &Facade{
+		backend:   backend,
+		machineId: tag.Id(),
+	}, nil
+}
+
+// SetUnitUtilization records the given samples of the resources used by
+// units.
+func (facade *Facade) SetUnitUtilization(args params.UnitUtilizationSamples) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Samples)),
+	}
+	var samples []state.UnitUtilization
+	var indexes []int
+	for i, arg := range args.Samples {
+		tag, err := names.ParseUnitTag(arg.UnitTag)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machineId, err := facade.backend.UnitMachineId(tag.Id())
+		if err != nil && !errors.IsNotFound(err) && !errors.IsNotAssigned(err) {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err != nil || machineId != facade.machineId {
+			results.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		samples = append(samples, state.UnitUtilization{
+			Unit:        tag.Id(),
+			Time:        arg.Time,
+			CPUPercent:  arg.CPUPercent,
+			MemoryBytes: arg.MemoryBytes,
+			Processes:   arg.Processes,
+		})
+		indexes = append(indexes, i)
+	}
+	if err := facade.backend.AddUnitUtilization(samples); err != nil {
+		for _, i := range indexes {
+			results.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return results, nil
+}
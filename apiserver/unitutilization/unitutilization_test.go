@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitutilization_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/unitutilization"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type facadeSuite struct {
+	coretesting.BaseSuite
+	backend *mockBackend
+	facade  *unitutilization.Facade
+}
+
+var _ = gc.Suite(&facadeSuite{})
+
+func (s *facadeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		machines: map[string]string{
+			"wordpress/0": "1",
+			"mysql/0":     "2",
+		},
+	}
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("1"),
+	}
+	var err error
+	s.facade, err = unitutilization.NewFacadeForTest(s.backend, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *facadeSuite) TestOnlyMachineAgents(c *gc.C) {
+	for _, tag := range []names.Tag{
+		names.NewUserTag("bob"),
+		names.NewUnitTag("ubuntu/0"),
+	} {
+		authorizer := apiservertesting.FakeAuthorizer{Tag: tag}
+		facade, err := unitutilization.NewFacadeForTest(s.backend, authorizer)
+		c.Check(facade, gc.IsNil)
+		c.Check(err, gc.Equals, common.ErrPerm)
+	}
+}
+
+func (s *facadeSuite) TestSetUnitUtilization(c *gc.C) {
+	now := time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC)
+	result, err := s.facade.SetUnitUtilization(params.UnitUtilizationSamples{
+		Samples: []params.UnitUtilizationSample{{
+			UnitTag:     names.NewUnitTag("wordpress/0").String(),
+			Time:        now,
+			CPUPercent:  12.5,
+			MemoryBytes: 1024,
+			Processes:   3,
+		}, {
+			UnitTag: names.NewUnitTag("mysql/0").String(),
+			Time:    now,
+		}, {
+			UnitTag: names.NewUnitTag("riak/0").String(),
+			Time:    now,
+		}, {
+			UnitTag: names.NewMachineTag("1").String(),
+			Time:    now,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+			{apiservertesting.ErrUnauthorized},
+		},
+	})
+	c.Assert(s.backend.samples, jc.DeepEquals, []state.UnitUtilization{{
+		Unit:        "wordpress/0",
+		Time:        now,
+		CPUPercent:  12.5,
+		MemoryBytes: 1024,
+		Processes:   3,
+	}})
+}
+
+func (s *facadeSuite) TestSetUnitUtilizationError(c *gc.C) {
+	s.backend.err = errors.New("boom")
+	result, err := s.facade.SetUnitUtilization(params.UnitUtilizationSamples{
+		Samples: []params.UnitUtilizationSample{{
+			UnitTag: names.NewUnitTag("wordpress/0").String(),
+			Time:    time.Now(),
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "boom")
+}
+
+type mockBackend struct {
+	machines map[string]string
+	samples  []state.UnitUtilization
+	err      error
+}
+
+func (b *mockBackend) UnitMachineId(unitName string) (string, error) {
+	machineId, ok := b.machines[unitName]
+	if !ok {
+		return "", errors.NotFoundf("unit %q", unitName)
+	}
+	return machineId, nil
+}
+
+func (b *mockBackend) AddUnitUtilization(samples []state.UnitUtilization) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.samples = append(b.samples, samples...)
+	return nil
+}
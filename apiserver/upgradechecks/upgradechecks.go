@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package upgradechecks implements the API facade used to check that
+// an environment is fit to be upgraded, before its agent version is
+// changed.
+package upgradechecks
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/upgrades"
+)
+
+func init() {
+	common.RegisterStandardFacade("UpgradeChecks", 1, NewUpgradeChecksAPI)
+}
+
+// runPreUpgradeChecks is called to make the checks, and may be
+// replaced in tests.
+var runPreUpgradeChecks = upgrades.RunPreUpgradeChecks
+
+// UpgradeChecksAPI implements the UpgradeChecks facade.
+type UpgradeChecksAPI struct {
+	st      *state.State
+	dataDir string
+}
+
+// NewUpgradeChecksAPI creates a new server-side UpgradeChecks API end
+// point.
+func NewUpgradeChecksAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*UpgradeChecksAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	api := &UpgradeChecksAPI{st: st}
+	if dataDir, ok := resources.Get("dataDir").(common.StringResource); ok {
+		api.dataDir = dataDir.String()
+	}
+	return api, nil
+}
+
+// Run makes the registered pre-upgrade checks for an upgrade of the
+// environment's agents to the given version, and returns the outcome
+// of each.
+func (api *UpgradeChecksAPI) Run(args params.RunUpgradeChecks) (params.UpgradeCheckResults, error) {
+	checks := runPreUpgradeChecks(upgrades.PreUpgradeParams{
+		State:         api.st,
+		DataDir:       api.dataDir,
+		TargetVersion: args.Version,
+	})
+	results := params.UpgradeCheckResults{
+		Results: make([]params.UpgradeCheckResult, len(checks)),
+	}
+	for i, check := range checks {
+		results.Results[i] = params.UpgradeCheckResult{
+			Name:        check.Name,
+			Description: check.Description,
+			Error:       common.ServerError(check.Error),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgradechecks_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/upgradechecks"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/version"
+)
+
+type upgradeChecksSuite struct {
+	jujutesting.JujuConnSuite
+	resources *common.Resources
+}
+
+var _ = gc.Suite(&upgradeChecksSuite{})
+
+func (s *upgradeChecksSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
+	err := s.resources.RegisterNamed("dataDir", common.StringResource("/var/lib/juju"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgradeChecksSuite) TestAgentRejected(c *gc.C) {
+	_, err := upgradechecks.NewUpgradeChecksAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *upgradeChecksSuite) TestRun(c *gc.C) {
+	var called upgrades.PreUpgradeParams
+	s.PatchValue(upgradechecks.RunPreUpgradeChecks, func(args upgrades.PreUpgradeParams) []upgrades.PreUpgradeCheckResult {
+		called = args
+		return []upgrades.PreUpgradeCheckResult{{
+			Name:        "disk-space",
+			Description: "state server has enough free disk space",
+		}, {
+			Name:        "deprecated-config",
+			Description: "environment config has no deprecated settings",
+			Error:       errors.New("deprecated settings in use: tools-url (no longer supported)"),
+		}}
+	})
+	api, err := upgradechecks.NewUpgradeChecksAPI(s.State, s.resources, apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := api.Run(params.RunUpgradeChecks{Version: version.MustParse("1.26.0")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called.State, gc.Equals, s.State)
+	c.Assert(called.DataDir, gc.Equals, "/var/lib/juju")
+	c.Assert(called.TargetVersion, gc.Equals, version.MustParse("1.26.0"))
+	c.Assert(results, jc.DeepEquals, params.UpgradeCheckResults{
+		Results: []params.UpgradeCheckResult{{
+			Name:        "disk-space",
+			Description: "state server has enough free disk space",
+		}, {
+			Name:        "deprecated-config",
+			Description: "environment config has no deprecated settings",
+			Error: &params.Error{
+				Message: "deprecated settings in use: tools-url (no longer supported)",
+			},
+		}},
+	})
+}
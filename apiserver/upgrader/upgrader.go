@@ -103,7 +103,7 @@ func (u *UpgraderAPI) WatchAPIVersion(args params.Entities) (params.NotifyWatchR
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
-			watch := u.st.WatchForEnvironConfigChanges()
+			watch := u.st.WatchAgentVersion()
 			// Consume the initial event. Technically, API
 			// calls to Watch 'transmit' the initial event
 			// in the Watch response. But NotifyWatchers
@@ -159,8 +159,14 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 	if err != nil {
 		return params.VersionResults{}, common.ServerError(err)
 	}
-	// Is the desired version greater than the current API server version?
-	isNewerVersion := agentVersion.Compare(version.Current.Number) > 0
+	rollout, err := u.st.AgentRollout()
+	if errors.IsNotFound(err) {
+		rollout = nil
+	} else if err != nil {
+		return params.VersionResults{}, common.ServerError(err)
+	} else if !rollout.InProgress() {
+		rollout = nil
+	}
 	for i, entity := range args.Entities {
 		tag, err := names.ParseTag(entity.Tag)
 		if err != nil {
@@ -169,6 +175,16 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 		}
 		err = common.ErrPerm
 		if u.authorizer.AuthOwner(tag) {
+			isManager := u.entityIsManager(tag)
+			// During a staged upgrade, the state servers and the
+			// canary machines are upgraded before the rest of
+			// the environment.
+			desiredVersion := agentVersion
+			if rollout != nil && (isManager || rollout.IsCanary(tag.Id())) {
+				desiredVersion = rollout.TargetVersion()
+			}
+			// Is the desired version greater than the current API server version?
+			isNewerVersion := desiredVersion.Compare(version.Current.Number) > 0
 			// Only return the globally desired agent version if the
 			// asking entity is a machine agent with JobManageEnviron or
 			// if this API server is running the globally desired agent
@@ -179,10 +195,10 @@ func (u *UpgraderAPI) DesiredVersion(args params.Entities) (params.VersionResult
 			// first - once they have restarted and are running the
 			// new version other agents will start to see the new
 			// agent version.
-			if !isNewerVersion || u.entityIsManager(tag) {
-				results[i].Version = &agentVersion
+			if !isNewerVersion || isManager {
+				results[i].Version = &desiredVersion
 			} else {
-				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", agentVersion, version.Current.Number)
+				logger.Debugf("desired version is %s, but current version is %s and agent is not a manager node", desiredVersion, version.Current.Number)
 				results[i].Version = &version.Current.Number
 			}
 			err = nil
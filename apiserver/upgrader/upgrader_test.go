@@ -5,6 +5,7 @@ package upgrader_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -326,3 +327,76 @@ func (s *upgraderSuite) TestDesiredVersionRestrictedForNonAPIAgents(c *gc.C) {
 	c.Assert(agentVersion, gc.NotNil)
 	c.Check(*agentVersion, gc.DeepEquals, version.Current.Number)
 }
+
+func (s *upgraderSuite) startAgentRollout(c *gc.C) version.Number {
+	s.apiMachine.SetAgentVersion(version.Current)
+	s.rawMachine.SetAgentVersion(version.Current)
+	target := version.Current.Number
+	target.Patch++
+	_, err := s.State.StartAgentRollout(target, []string{s.rawMachine.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	return target
+}
+
+func (s *upgraderSuite) desiredVersion(c *gc.C, machine *state.Machine) version.Number {
+	authorizer := apiservertesting.FakeAuthorizer{Tag: machine.Tag()}
+	upgraderAPI, err := upgrader.NewUpgraderAPI(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.Entities{Entities: []params.Entity{{Tag: machine.Tag().String()}}}
+	results, err := upgraderAPI.DesiredVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Version, gc.NotNil)
+	return *results.Results[0].Version
+}
+
+func (s *upgraderSuite) TestDesiredVersionDuringAgentRollout(c *gc.C) {
+	current := version.Current.Number
+	target := s.startAgentRollout(c)
+	other, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// State servers are upgraded first; the canary waits for them.
+	c.Check(s.desiredVersion(c, s.apiMachine), gc.Equals, target)
+	c.Check(s.desiredVersion(c, s.rawMachine), gc.Equals, current)
+	c.Check(s.desiredVersion(c, other), gc.Equals, current)
+
+	// Once the API server runs the new version, only the canary
+	// follows it.
+	s.PatchValue(&version.Current.Number, target)
+	c.Check(s.desiredVersion(c, s.apiMachine), gc.Equals, target)
+	c.Check(s.desiredVersion(c, s.rawMachine), gc.Equals, target)
+	c.Check(s.desiredVersion(c, other), gc.Equals, current)
+}
+
+func (s *upgraderSuite) TestDesiredVersionAfterAgentRollBack(c *gc.C) {
+	current := version.Current.Number
+	target := s.startAgentRollout(c)
+	s.PatchValue(&version.Current.Number, target)
+	rollout, err := s.State.AgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rollout.RollBack("testing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.desiredVersion(c, s.apiMachine), gc.Equals, current)
+	c.Check(s.desiredVersion(c, s.rawMachine), gc.Equals, current)
+}
+
+func (s *upgraderSuite) TestWatchAPIVersionNoticesAgentRollout(c *gc.C) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}},
+	}
+	results, err := s.upgrader.WatchAPIVersion(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	w := s.resources.Get(results.Results[0].NotifyWatcherId).(state.NotifyWatcher)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertNoChange()
+
+	s.startAgentRollout(c)
+	wc.AssertOneChange()
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
@@ -29,7 +29,9 @@ var allowedMethodsDuringUpgrades = set.NewStrings(
 	"EnvironmentGet", // for "juju ssh"
 	"PrivateAddress", // for "juju ssh"
 	"PublicAddress",  // for "juju ssh"
+	"SSHHostKeys",    // for "juju ssh"
 	"WatchDebugLog",  // for "juju debug-log"
+	"UpgradeInfo",    // for "juju show-upgrade"
 )
 
 func IsMethodAllowedDuringUpgrade(rootName, methodName string) bool {
@@ -26,6 +26,15 @@ func (r *upgradingRootSuite) TestFindAllowedMethod(c *gc.C) {
 	c.Assert(caller, gc.NotNil)
 }
 
+func (r *upgradingRootSuite) TestFindUpgradeInfoMethod(c *gc.C) {
+	root := apiserver.TestingUpgradingRoot(nil)
+
+	caller, err := root.FindMethod("Client", 0, "UpgradeInfo")
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(caller, gc.NotNil)
+}
+
 func (r *upgradingRootSuite) TestFindDisallowedMethod(c *gc.C) {
 	root := apiserver.TestingUpgradingRoot(nil)
 
@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
+)
+
+// usageFlushInterval holds how often the API usage counted by the
+// server is added to the totals recorded in state.
+var usageFlushInterval = time.Minute
+
+// usageRetention holds how long the hourly API usage totals are kept.
+const usageRetention = 7 * 24 * time.Hour
+
+// usageTracker counts the API calls made to each environment served,
+// and the size of the messages they exchange, between flushes to
+// state.
+type usageTracker struct {
+	mu    sync.Mutex
+	usage map[string]*state.APIUsage
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{usage: make(map[string]*state.APIUsage)}
+}
+
+// update applies f to the usage counted for the given environment.
+func (t *usageTracker) update(envUUID string, f func(*state.APIUsage)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.usage[envUUID]
+	if !ok {
+		u = &state.APIUsage{EnvUUID: envUUID}
+		t.usage[envUUID] = u
+	}
+	f(u)
+}
+
+// take returns the usage counted since it was last called.
+func (t *usageTracker) take() []state.APIUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage := make([]state.APIUsage, 0, len(t.usage))
+	for _, u := range t.usage {
+		usage = append(usage, *u)
+	}
+	t.usage = make(map[string]*state.APIUsage)
+	return usage
+}
+
+// restore adds back usage returned by take that could not be
+// recorded, so that it is recorded by the next flush.
+func (t *usageTracker) restore(usage []state.APIUsage) {
+	for _, u := range usage {
+		u := u
+		t.update(u.EnvUUID, func(total *state.APIUsage) {
+			total.Calls += u.Calls
+			total.BytesIn += u.BytesIn
+			total.BytesOut += u.BytesOut
+		})
+	}
+}
+
+// envUsageCounter counts the usage of a single connection to the
+// given environment. It implements jsoncodec.MessageCounter.
+type envUsageCounter struct {
+	tracker *usageTracker
+	envUUID string
+}
+
+func (c envUsageCounter) MessageSent(size int) {
+	c.tracker.update(c.envUUID, func(u *state.APIUsage) { u.BytesOut += int64(size) })
+}
+
+func (c envUsageCounter) MessageReceived(size int) {
+	c.tracker.update(c.envUUID, func(u *state.APIUsage) { u.BytesIn += int64(size) })
+}
+
+// usageNotifier counts each API call made on a connection, passing
+// all notifications to the next notifier, if any.
+type usageNotifier struct {
+	next    rpc.RequestNotifier
+	counter envUsageCounter
+}
+
+func (n *usageNotifier) ServerRequest(hdr *rpc.Header, body interface{}) {
+	n.counter.tracker.update(n.counter.envUUID, func(u *state.APIUsage) { u.Calls++ })
+	if n.next != nil {
+		n.next.ServerRequest(hdr, body)
+	}
+}
+
+func (n *usageNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}, timeSpent time.Duration) {
+	if n.next != nil {
+		n.next.ServerReply(req, hdr, body, timeSpent)
+	}
+}
+
+func (n *usageNotifier) ClientRequest(hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientRequest(hdr, body)
+	}
+}
+
+func (n *usageNotifier) ClientReply(req rpc.Request, hdr *rpc.Header, body interface{}) {
+	if n.next != nil {
+		n.next.ClientReply(req, hdr, body)
+	}
+}
+
+// usageFlusher periodically adds the API usage counted by the server
+// to the totals in state, and removes totals older than the retention
+// period, until the server is stopped.
+func (srv *Server) usageFlusher() error {
+	timer := time.NewTimer(usageFlushInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-srv.tomb.Dying():
+			srv.flushUsage()
+			return tomb.ErrDying
+		}
+		srv.flushUsage()
+		timer.Reset(usageFlushInterval)
+	}
+}
+
+// flushUsage adds the API usage counted since the last flush to the
+// totals in state. Failures are logged rather than stopping the
+// server; the usage is kept for the next flush.
+func (srv *Server) flushUsage() {
+	usage := srv.usage.take()
+	if len(usage) == 0 {
+		return
+	}
+	now := time.Now()
+	if err := srv.state.AddAPIUsage(now, usage); err != nil {
+		logger.Warningf("cannot record API usage: %v", err)
+		srv.usage.restore(usage)
+		return
+	}
+	if err := srv.state.PruneAPIUsage(now.Add(-usageRetention)); err != nil {
+		logger.Warningf("cannot prune API usage: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type usageSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&usageSuite{})
+
+func (s *usageSuite) SetUpSuite(c *gc.C) {
+	s.JujuConnSuite.SetUpSuite(c)
+	// The flush interval must be set before the server is started in
+	// test setup.
+	restore := gitjujutesting.PatchValue(apiserver.UsageFlushInterval, coretesting.ShortWait)
+	s.AddSuiteCleanup(func(*gc.C) { restore() })
+}
+
+// waitForUsage waits until the usage recorded for the given
+// environment includes at least the given number of calls.
+func (s *usageSuite) waitForUsage(c *gc.C, envUUID string, calls int64) state.APIUsage {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		usage, err := s.State.APIUsageSince(time.Now().Add(-time.Hour))
+		c.Assert(err, jc.ErrorIsNil)
+		for _, u := range usage {
+			if u.EnvUUID == envUUID && u.Calls >= calls {
+				return u
+			}
+		}
+	}
+	c.Fatalf("timed out waiting for usage of environment %q", envUUID)
+	panic("unreachable")
+}
+
+func (s *usageSuite) TestRecordsUsage(c *gc.C) {
+	for i := 0; i < 3; i++ {
+		_, err := s.APIState.Client().Status(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	usage := s.waitForUsage(c, s.State.EnvironUUID(), 3)
+	c.Assert(usage.BytesIn, jc.GreaterThan, int64(0))
+	c.Assert(usage.BytesOut, jc.GreaterThan, int64(0))
+}
+
+func (s *usageSuite) TestUsageIsPerEnvironment(c *gc.C) {
+	envState := s.Factory.MakeEnvironment(c, nil)
+	defer envState.Close()
+	info := s.APIInfo(c)
+	info.EnvironTag = envState.EnvironTag()
+	st, err := api.Open(info, api.DialOpts{})
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+	_, err = st.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage := s.waitForUsage(c, envState.EnvironUUID(), 1)
+	c.Assert(usage.Calls, jc.LessThan, int64(10))
+}
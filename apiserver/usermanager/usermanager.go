@@ -4,13 +4,17 @@
 package usermanager
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 
+	"github.com/juju/juju/apiserver/authentication"
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/utils/totp"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.usermanager")
@@ -26,6 +30,10 @@ type UserManager interface {
 	EnableUser(args params.Entities) (params.ErrorResults, error)
 	SetPassword(args params.EntityPasswords) (params.ErrorResults, error)
 	UserInfo(args params.UserInfoRequest) (params.UserInfoResults, error)
+	RefreshSession(args params.SessionToken) (params.SessionInfo, error)
+	Logout(args params.SessionToken) error
+	EnableTOTP(args params.EnableTOTP) error
+	DisableTOTP(args params.Entities) (params.ErrorResults, error)
 }
 
 // UserManagerAPI implements the user manager interface and is the concrete
@@ -248,6 +256,123 @@ func (api *UserManagerAPI) SetPassword(args params.EntityPasswords) (params.Erro
 	return result, nil
 }
 
+// RefreshSession replaces the token of one of the logged in user's
+// sessions with a new one that expires later, so that the user can keep
+// logging in with it. The old token can no longer be used.
+func (api *UserManagerAPI) RefreshSession(args params.SessionToken) (params.SessionInfo, error) {
+	if err := api.checkSessionOwner(args.Token); err != nil {
+		return params.SessionInfo{}, errors.Trace(err)
+	}
+	session, err := api.state.RefreshUserSession(args.Token)
+	if err != nil {
+		return params.SessionInfo{}, errors.Trace(err)
+	}
+	return params.SessionInfo{Token: session.Token, Expires: session.Expires}, nil
+}
+
+// Logout ends one of the logged in user's sessions, so that its token
+// can no longer be used to log in. Ending a session that has already
+// expired is not an error.
+func (api *UserManagerAPI) Logout(args params.SessionToken) error {
+	err := api.checkSessionOwner(args.Token)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(api.state.RemoveUserSession(args.Token))
+}
+
+// checkSessionOwner returns an error unless the session with the given
+// token is one of the logged in user's.
+func (api *UserManagerAPI) checkSessionOwner(token string) error {
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return common.ErrPerm
+	}
+	session, err := api.state.UserSession(token)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if session.User != loggedInUser {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// EnableTOTP enrols the logged in user for two-factor authentication,
+// so that they must give a time-based one-time password for the given
+// secret whenever they log in. A user who is already enrolled must give
+// a code for their current secret to replace it; otherwise
+// ErrSecondFactorRequired is returned.
+func (api *UserManagerAPI) EnableTOTP(args params.EnableTOTP) error {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return common.ErrPerm
+	}
+	step, ok := totp.Step(args.Secret, args.Code, time.Now())
+	if !ok {
+		return errors.New("invalid two-factor authentication code")
+	}
+	user, err := api.state.User(loggedInUser)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user.TOTPSecret() != "" {
+		switch err := authentication.CheckSecondFactor(user, args.CurrentCode); err {
+		case nil:
+		case common.ErrBadCreds:
+			return errors.New("invalid current two-factor authentication code")
+		default:
+			return errors.Trace(err)
+		}
+	}
+	if err := user.SetTOTPSecret(args.Secret); err != nil {
+		return errors.Trace(err)
+	}
+	// The code for the new secret may not be used to log in. It is
+	// already covered if the current code was of the same step.
+	if err := user.UseTOTPStep(step); err != nil && err != state.ErrTOTPCodeUsed {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// DisableTOTP removes the two-factor authentication enrollment of the
+// specified users. Users may disable their own enrollment; only an
+// administrator may disable another user's, such as when the user has
+// lost their authenticator.
+func (api *UserManagerAPI) DisableTOTP(args params.Entities) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	if len(args.Entities) == 0 {
+		return result, nil
+	}
+	loggedInUser, err := api.getLoggedInUser()
+	if err != nil {
+		return result, common.ErrPerm
+	}
+	adminUser := api.permissionCheck(loggedInUser) == nil
+	for i, arg := range args.Entities {
+		user, err := api.getUser(arg.Tag)
+		if err == nil && loggedInUser != user.UserTag() && !adminUser {
+			err = common.ErrPerm
+		}
+		if err == nil {
+			err = user.SetTOTPSecret("")
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
 func (api *UserManagerAPI) getLoggedInUser() (names.UserTag, error) {
 	switch tag := api.authorizer.GetAuthTag().(type) {
 	case names.UserTag:
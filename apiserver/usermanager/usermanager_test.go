@@ -4,17 +4,23 @@
 package usermanager_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/authentication"
+	"github.com/juju/juju/apiserver/common"
 	commontesting "github.com/juju/juju/apiserver/common/testing"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/usermanager"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/utils/totp"
 )
 
 type userManagerSuite struct {
@@ -481,3 +487,144 @@ func (s *userManagerSuite) TestSetPasswordForOther(c *gc.C) {
 
 	c.Assert(barb.PasswordValid("new-password"), jc.IsFalse)
 }
+
+func (s *userManagerSuite) TestRefreshSession(c *gc.C) {
+	session, err := s.State.AddUserSession(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.usermanager.RefreshSession(params.SessionToken{Token: session.Token})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Token, gc.Not(gc.Equals), session.Token)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.UserSession(result.Token)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.usermanager.RefreshSession(params.SessionToken{Token: session.Token})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *userManagerSuite) TestRefreshSessionOfOtherUser(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	session, err := s.State.AddUserSession(alex.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.usermanager.RefreshSession(params.SessionToken{Token: session.Token})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	err = s.usermanager.Logout(params.SessionToken{Token: session.Token})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userManagerSuite) TestLogout(c *gc.C) {
+	session, err := s.State.AddUserSession(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.usermanager.Logout(params.SessionToken{Token: session.Token})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Logging out again is not an error.
+	err = s.usermanager.Logout(params.SessionToken{Token: session.Token})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *userManagerSuite) TestEnableTOTP(c *gc.C) {
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	later, err := totp.Code(secret, time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(params.EnableTOTP{Secret: secret, Code: later})
+	c.Assert(err, gc.ErrorMatches, "invalid two-factor authentication code")
+
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(params.EnableTOTP{Secret: secret, Code: code})
+	c.Assert(err, jc.ErrorIsNil)
+
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, secret)
+
+	// The code used to enable two-factor authentication cannot be
+	// used to log in.
+	err = authentication.CheckSecondFactor(user, code)
+	c.Assert(err, gc.Equals, common.ErrBadCreds)
+}
+
+func (s *userManagerSuite) TestEnableTOTPReplacingSecret(c *gc.C) {
+	user, err := s.State.User(s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	current, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetTOTPSecret(current)
+	c.Assert(err, jc.ErrorIsNil)
+
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(params.EnableTOTP{Secret: secret, Code: code})
+	c.Assert(err, gc.Equals, common.ErrSecondFactorRequired)
+
+	err = s.usermanager.EnableTOTP(params.EnableTOTP{Secret: secret, Code: code, CurrentCode: "000000"})
+	c.Assert(err, gc.ErrorMatches, "invalid current two-factor authentication code")
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, current)
+
+	currentCode, err := totp.Code(current, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.usermanager.EnableTOTP(params.EnableTOTP{Secret: secret, Code: code, CurrentCode: currentCode})
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, secret)
+}
+
+func (s *userManagerSuite) TestEnableTOTPBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestEnableTOTPBlocked")
+	err := s.usermanager.EnableTOTP(params.EnableTOTP{Secret: "secret", Code: "000000"})
+	s.AssertBlocked(c, err, "TestEnableTOTPBlocked")
+}
+
+func (s *userManagerSuite) TestDisableTOTP(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex"})
+	barb := s.Factory.MakeUser(c, &factory.UserParams{Name: "barb"})
+	for _, user := range []*state.User{alex, barb} {
+		err := user.SetTOTPSecret("GEZDGNBVGY3TQOJQ")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, nil, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{alex.Tag().String()},
+		{barb.Tag().String()},
+	}}
+	results, err := usermanager.DisableTOTP(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: &params.Error{
+				Message: "permission denied",
+				Code:    params.CodeUnauthorized,
+			}},
+		},
+	})
+	err = alex.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(alex.TOTPSecret(), gc.Equals, "")
+
+	// An administrator can disable any user's enrollment.
+	results, err = s.usermanager.DisableTOTP(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Combine(), jc.ErrorIsNil)
+	err = barb.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(barb.TOTPSecret(), gc.Equals, "")
+}
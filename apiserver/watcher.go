@@ -16,6 +16,10 @@ func init() {
 		"AllWatcher", 0, newClientAllWatcher,
 		reflect.TypeOf((*srvClientAllWatcher)(nil)),
 	)
+	common.RegisterFacade(
+		"AllEnvWatcher", 1, newAllEnvWatcher,
+		reflect.TypeOf((*srvAllEnvWatcher)(nil)),
+	)
 	common.RegisterFacade(
 		"NotifyWatcher", 0, newNotifyWatcher,
 		reflect.TypeOf((*srvNotifyWatcher)(nil)),
@@ -73,6 +77,42 @@ func (w *srvClientAllWatcher) Stop() error {
 	return w.resources.Stop(w.id)
 }
 
+func newAllEnvWatcher(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	if !auth.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	watcher, ok := resources.Get(id).(*state.AllEnvWatcher)
+	if !ok {
+		return nil, common.ErrUnknownWatcher
+	}
+	return &srvAllEnvWatcher{
+		watcher:   watcher,
+		id:        id,
+		resources: resources,
+	}, nil
+}
+
+// srvAllEnvWatcher defines the API methods on a state.AllEnvWatcher,
+// which watches changes to the entities of every environment in the
+// system. The watcher can only be obtained through the SystemManager
+// facade, which restricts access to system administrators.
+type srvAllEnvWatcher struct {
+	watcher   *state.AllEnvWatcher
+	id        string
+	resources *common.Resources
+}
+
+func (aw *srvAllEnvWatcher) Next() (params.AllEnvWatcherNextResults, error) {
+	deltas, err := aw.watcher.Next()
+	return params.AllEnvWatcherNextResults{
+		Deltas: deltas,
+	}, err
+}
+
+func (aw *srvAllEnvWatcher) Stop() error {
+	return aw.resources.Stop(aw.id)
+}
+
 // srvNotifyWatcher defines the API access to methods on a state.NotifyWatcher.
 // Each client has its own current set of watchers, stored in resources.
 type srvNotifyWatcher struct {
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks implements the API facade used to manage the URLs
+// to which notifications of environment events are posted.
+package webhooks
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Webhooks", 1, NewWebhooksAPI)
+}
+
+// WebhooksAPI implements the Webhooks facade.
+type WebhooksAPI struct {
+	st    *state.State
+	check *common.BlockChecker
+}
+
+// NewWebhooksAPI creates a new server-side Webhooks API end point.
+// Only the owner of the environment, or of the state server
+// environment, may use it.
+func NewWebhooksAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*WebhooksAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	apiUser, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return nil, common.ErrPerm
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if apiUser != env.Owner() && apiUser != stateServerEnv.Owner() {
+		return nil, common.ErrPerm
+	}
+	return &WebhooksAPI{
+		st:    st,
+		check: common.NewBlockChecker(st),
+	}, nil
+}
+
+// AddWebhook records that notifications of the given events are to be
+// posted to the given URL.
+func (api *WebhooksAPI) AddWebhook(args params.AddWebhookArgs) (params.AddWebhookResult, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.AddWebhookResult{}, errors.Trace(err)
+	}
+	webhook, err := api.st.AddWebhook(args.URL, args.Secret, args.Events)
+	if err != nil {
+		return params.AddWebhookResult{Error: common.ServerError(err)}, nil
+	}
+	return params.AddWebhookResult{Id: webhook.Id()}, nil
+}
+
+// Webhooks returns the webhooks of the environment, without their
+// secrets.
+func (api *WebhooksAPI) Webhooks() (params.WebhooksResult, error) {
+	webhooks, err := api.st.Webhooks()
+	if err != nil {
+		return params.WebhooksResult{}, errors.Trace(err)
+	}
+	result := params.WebhooksResult{
+		Webhooks: make([]params.Webhook, len(webhooks)),
+	}
+	for i, webhook := range webhooks {
+		result.Webhooks[i] = params.Webhook{
+			Id:     webhook.Id(),
+			URL:    webhook.URL(),
+			Signed: webhook.Secret() != "",
+			Events: webhook.Events(),
+		}
+	}
+	return result, nil
+}
+
+// RemoveWebhook removes the webhook with the given id.
+func (api *WebhooksAPI) RemoveWebhook(args params.RemoveWebhookArgs) (params.ErrorResult, error) {
+	if err := api.check.RemoveAllowed(); err != nil {
+		return params.ErrorResult{}, errors.Trace(err)
+	}
+	err := api.st.RemoveWebhook(args.Id)
+	return params.ErrorResult{Error: common.ServerError(err)}, nil
+}
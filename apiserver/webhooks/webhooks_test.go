@@ -0,0 +1,137 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	commontesting "github.com/juju/juju/apiserver/common/testing"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/apiserver/webhooks"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type webhooksSuite struct {
+	jujutesting.JujuConnSuite
+	commontesting.BlockHelper
+	api *webhooks.WebhooksAPI
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.BlockHelper = commontesting.NewBlockHelper(s.APIState)
+	s.AddCleanup(func(*gc.C) { s.BlockHelper.Close() })
+	api, err := webhooks.NewWebhooksAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *webhooksSuite) TestNonOwnerRejected(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	_, err := webhooks.NewWebhooksAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: user.UserTag(),
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *webhooksSuite) TestEnvironOwnerAccepted(c *gc.C) {
+	owner := s.Factory.MakeUser(c, &factory.UserParams{Name: "fred"})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner.UserTag()})
+	defer st.Close()
+	_, err := webhooks.NewWebhooksAPI(st, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag: owner.UserTag(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *webhooksSuite) TestAgentRejected(c *gc.C) {
+	_, err := webhooks.NewWebhooksAPI(s.State, common.NewResources(), apiservertesting.FakeAuthorizer{
+		Tag:            names.NewMachineTag("0"),
+		EnvironManager: true,
+	})
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *webhooksSuite) TestAddWebhook(c *gc.C) {
+	result, err := s.api.AddWebhook(params.AddWebhookArgs{
+		URL:    "https://example.com/hook",
+		Secret: "s3cret",
+		Events: []string{"hook-failed"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AddWebhookResult{Id: "0"})
+
+	webhook, err := s.State.Webhook("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.URL(), gc.Equals, "https://example.com/hook")
+	c.Assert(webhook.Secret(), gc.Equals, "s3cret")
+	c.Assert(webhook.Events(), jc.DeepEquals, []string{"hook-failed"})
+}
+
+func (s *webhooksSuite) TestAddWebhookInvalid(c *gc.C) {
+	result, err := s.api.AddWebhook(params.AddWebhookArgs{
+		URL:    "https://example.com/hook",
+		Events: []string{"party"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.ErrorMatches, `cannot add webhook "https://example.com/hook": event "party" not valid`)
+}
+
+func (s *webhooksSuite) TestAddWebhookBlocked(c *gc.C) {
+	s.BlockAllChanges(c, "TestAddWebhookBlocked")
+	_, err := s.api.AddWebhook(params.AddWebhookArgs{URL: "https://example.com/hook"})
+	s.AssertBlocked(c, err, "TestAddWebhookBlocked")
+}
+
+func (s *webhooksSuite) TestWebhooks(c *gc.C) {
+	_, err := s.State.AddWebhook("https://example.com/hook", "s3cret", []string{"machine-down"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddWebhook("http://example.com/other", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.WebhooksResult{
+		Webhooks: []params.Webhook{{
+			Id:     "0",
+			URL:    "https://example.com/hook",
+			Signed: true,
+			Events: []string{"machine-down"},
+		}, {
+			Id:  "1",
+			URL: "http://example.com/other",
+		}},
+	})
+}
+
+func (s *webhooksSuite) TestRemoveWebhook(c *gc.C) {
+	_, err := s.State.AddWebhook("https://example.com/hook", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.api.RemoveWebhook(params.RemoveWebhookArgs{Id: "0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	webhooks, err := s.State.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhooks, gc.HasLen, 0)
+
+	result, err = s.api.RemoveWebhook(params.RemoveWebhookArgs{Id: "0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, jc.Satisfies, params.IsCodeNotFound)
+}
+
+func (s *webhooksSuite) TestRemoveWebhookBlocked(c *gc.C) {
+	s.BlockRemoveObject(c, "TestRemoveWebhookBlocked")
+	_, err := s.api.RemoveWebhook(params.RemoveWebhookArgs{Id: "0"})
+	s.AssertBlocked(c, err, "TestRemoveWebhookBlocked")
+}
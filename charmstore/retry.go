@@ -0,0 +1,166 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package charmstore holds helpers shared by the clients and servers
+// that talk to charm stores.
+package charmstore
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
+)
+
+var logger = loggo.GetLogger("juju.charmstore")
+
+// ErrAborted is returned by an operation of a retrying repository when
+// it is abandoned because its abort channel was closed.
+var ErrAborted = errors.New("charm store operation aborted")
+
+// RetryPolicy describes how charm store operations that fail with
+// transient errors are retried.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times an operation is tried.
+	Attempts int
+
+	// Delay is the approximate time waited after the first failed
+	// attempt. It doubles after each further failure, and is
+	// randomly shortened by up to half so that many clients do not
+	// all retry at once.
+	Delay time.Duration
+
+	// MaxDelay, if non-zero, bounds the time waited between attempts.
+	MaxDelay time.Duration
+
+	// Timeout, if non-zero, bounds the total time spent retrying an
+	// operation.
+	Timeout time.Duration
+
+	// Notify, if not nil, is called before each retry with the
+	// attempt that failed, its error and the time until the next
+	// attempt, so that callers can report progress.
+	Notify func(attempt int, err error, wait time.Duration)
+}
+
+// DefaultRetryPolicy is the policy used for charm store operations
+// unless the caller has reason to choose another.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts: 5,
+	Delay:    time.Second,
+	MaxDelay: 30 * time.Second,
+	Timeout:  5 * time.Minute,
+}
+
+// NewRetryingRepo returns a repository that performs the operations of
+// the given one, retrying them according to the policy when they fail
+// with transient errors. Retries stop when the abort channel is closed.
+//
+// Any specialization of the repository, such as enabling its test
+// mode, must be done before it is wrapped.
+func NewRetryingRepo(repo charmrepo.Interface, policy RetryPolicy, abort <-chan struct{}) charmrepo.Interface {
+	return &retryingRepo{
+		repo:   repo,
+		policy: policy,
+		abort:  abort,
+	}
+}
+
+type retryingRepo struct {
+	repo   charmrepo.Interface
+	policy RetryPolicy
+	abort  <-chan struct{}
+}
+
+// Get is part of the charmrepo.Interface interface.
+func (r *retryingRepo) Get(curl *charm.URL) (charm.Charm, error) {
+	var ch charm.Charm
+	err := r.retry(fmt.Sprintf("get charm %q", curl), func() (err error) {
+		ch, err = r.repo.Get(curl)
+		return err
+	})
+	return ch, err
+}
+
+// Latest is part of the charmrepo.Interface interface.
+func (r *retryingRepo) Latest(curls ...*charm.URL) ([]charmrepo.CharmRevision, error) {
+	var revisions []charmrepo.CharmRevision
+	err := r.retry(fmt.Sprintf("get latest revisions of %d charms", len(curls)), func() (err error) {
+		revisions, err = r.repo.Latest(curls...)
+		return err
+	})
+	return revisions, err
+}
+
+// Resolve is part of the charmrepo.Interface interface.
+func (r *retryingRepo) Resolve(ref *charm.Reference) (*charm.URL, error) {
+	var curl *charm.URL
+	err := r.retry(fmt.Sprintf("resolve charm %q", ref), func() (err error) {
+		curl, err = r.repo.Resolve(ref)
+		return err
+	})
+	return curl, err
+}
+
+// retry calls f until it succeeds, fails with an error that is not
+// transient, or the policy allows no more attempts.
+func (r *retryingRepo) retry(what string, f func() error) error {
+	var timeout <-chan time.Time
+	if r.policy.Timeout > 0 {
+		timeout = time.After(r.policy.Timeout)
+	}
+	delay := r.policy.Delay
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !IsTransientError(err) || attempt >= r.policy.Attempts {
+			return err
+		}
+		wait := jitter(delay)
+		logger.Warningf("cannot %s (attempt %d of %d), retrying in %v: %v", what, attempt, r.policy.Attempts, wait, err)
+		if r.policy.Notify != nil {
+			r.policy.Notify(attempt, err, wait)
+		}
+		select {
+		case <-r.abort:
+			return ErrAborted
+		case <-timeout:
+			return errors.Annotatef(err, "cannot %s: timed out after %d attempts", what, attempt)
+		case <-time.After(wait):
+		}
+		delay *= 2
+		if r.policy.MaxDelay > 0 && delay > r.policy.MaxDelay {
+			delay = r.policy.MaxDelay
+		}
+	}
+}
+
+// jitter returns a random duration between half the given delay and
+// the delay itself.
+func jitter(delay time.Duration) time.Duration {
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// IsTransientError reports whether the given error, returned by a charm
+// store operation, was caused by a failure to reach the charm store, so
+// that the operation may succeed if tried again.
+func IsTransientError(err error) bool {
+	for _, err := range []error{err, errors.Cause(err)} {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		if _, ok := err.(net.Error); ok {
+			return true
+		}
+	}
+	return false
+}
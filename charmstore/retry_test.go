@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmstore_test
+
+import (
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
+
+	"github.com/juju/juju/charmstore"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type retrySuite struct {
+	coretesting.BaseSuite
+	repo   *flakyRepo
+	policy charmstore.RetryPolicy
+}
+
+var _ = gc.Suite(&retrySuite{})
+
+var transientErr = &url.Error{
+	Op:  "Get",
+	URL: "https://api.jujucharms.com/charmstore/v4/meta/any",
+	Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+}
+
+func (s *retrySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.repo = &flakyRepo{}
+	s.policy = charmstore.RetryPolicy{
+		Attempts: 3,
+		Delay:    time.Millisecond,
+		MaxDelay: time.Millisecond,
+	}
+}
+
+func (s *retrySuite) TestRetriesTransientErrors(c *gc.C) {
+	s.repo.errs = []error{transientErr, transientErr}
+	var notified []int
+	s.policy.Notify = func(attempt int, err error, wait time.Duration) {
+		c.Check(err, gc.Equals, transientErr)
+		c.Check(wait <= time.Millisecond, jc.IsTrue)
+		notified = append(notified, attempt)
+	}
+	repo := charmstore.NewRetryingRepo(s.repo, s.policy, nil)
+	curl, err := repo.Resolve(charm.MustParseReference("cs:wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(curl, gc.DeepEquals, charm.MustParseURL("cs:trusty/wordpress-1"))
+	c.Assert(s.repo.calls, gc.Equals, 3)
+	c.Assert(notified, jc.DeepEquals, []int{1, 2})
+}
+
+func (s *retrySuite) TestGivesUpAfterAttempts(c *gc.C) {
+	s.repo.errs = []error{transientErr, transientErr, transientErr, transientErr}
+	repo := charmstore.NewRetryingRepo(s.repo, s.policy, nil)
+	_, err := repo.Get(charm.MustParseURL("cs:trusty/wordpress-1"))
+	c.Assert(err, gc.Equals, transientErr)
+	c.Assert(s.repo.calls, gc.Equals, 3)
+}
+
+func (s *retrySuite) TestDoesNotRetryOtherErrors(c *gc.C) {
+	s.repo.errs = []error{errors.NotFoundf("charm")}
+	repo := charmstore.NewRetryingRepo(s.repo, s.policy, nil)
+	_, err := repo.Latest(charm.MustParseURL("cs:trusty/wordpress"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.repo.calls, gc.Equals, 1)
+}
+
+func (s *retrySuite) TestAbort(c *gc.C) {
+	s.repo.errs = []error{transientErr, transientErr}
+	s.policy.Delay = coretesting.LongWait
+	s.policy.MaxDelay = 0
+	abort := make(chan struct{})
+	close(abort)
+	repo := charmstore.NewRetryingRepo(s.repo, s.policy, abort)
+	_, err := repo.Get(charm.MustParseURL("cs:trusty/wordpress-1"))
+	c.Assert(err, gc.Equals, charmstore.ErrAborted)
+	c.Assert(s.repo.calls, gc.Equals, 1)
+}
+
+func (s *retrySuite) TestTimeout(c *gc.C) {
+	s.repo.errs = []error{transientErr, transientErr}
+	s.policy.Delay = coretesting.LongWait
+	s.policy.MaxDelay = 0
+	s.policy.Timeout = time.Millisecond
+	repo := charmstore.NewRetryingRepo(s.repo, s.policy, nil)
+	_, err := repo.Get(charm.MustParseURL("cs:trusty/wordpress-1"))
+	c.Assert(err, gc.ErrorMatches, `cannot get charm "cs:trusty/wordpress-1": timed out after 1 attempts: .*connection refused`)
+	c.Assert(s.repo.calls, gc.Equals, 1)
+}
+
+func (s *retrySuite) TestIsTransientError(c *gc.C) {
+	c.Assert(charmstore.IsTransientError(transientErr), jc.IsTrue)
+	c.Assert(charmstore.IsTransientError(errors.Annotate(transientErr, "cannot get charm")), jc.IsTrue)
+	c.Assert(charmstore.IsTransientError(errors.New("bad request")), jc.IsFalse)
+	c.Assert(charmstore.IsTransientError(errors.Unauthorizedf("access denied")), jc.IsFalse)
+}
+
+// flakyRepo is a charm repository whose operations fail with each of
+// its errors in turn before succeeding.
+type flakyRepo struct {
+	errs  []error
+	calls int
+}
+
+func (r *flakyRepo) nextErr() error {
+	r.calls++
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+func (r *flakyRepo) Get(curl *charm.URL) (charm.Charm, error) {
+	if err := r.nextErr(); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (r *flakyRepo) Latest(curls ...*charm.URL) ([]charmrepo.CharmRevision, error) {
+	if err := r.nextErr(); err != nil {
+		return nil, err
+	}
+	return make([]charmrepo.CharmRevision, len(curls)), nil
+}
+
+func (r *flakyRepo) Resolve(ref *charm.Reference) (*charm.URL, error) {
+	if err := r.nextErr(); err != nil {
+		return nil, err
+	}
+	return charm.MustParseURL("cs:trusty/wordpress-1"), nil
+}
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/version"
 )
 
@@ -191,6 +193,110 @@ func (c *EnvCommandBase) ConnectionEndpoint(refresh bool) (configstore.APIEndpoi
 	return info.APIEndpoint(), nil
 }
 
+// LocalConnectionEndpoint returns the end point information used to
+// connect to the API for the specified environment without connecting
+// to it. The addresses cached for the environment are used if there are
+// any; otherwise those recorded in the client store for the controller
+// hosting a model of the same name are.
+func (c *EnvCommandBase) LocalConnectionEndpoint() (configstore.APIEndpoint, error) {
+	var emptyEndpoint configstore.APIEndpoint
+	if c.envName == "" {
+		return emptyEndpoint, errors.Trace(ErrNoEnvironmentSpecified)
+	}
+	info, err := ConnectionInfoForName(c.envName)
+	if err == nil {
+		if endpoint := info.APIEndpoint(); len(endpoint.Addresses) > 0 {
+			return endpoint, nil
+		}
+	} else if !errors.IsNotFound(err) {
+		return emptyEndpoint, errors.Trace(err)
+	}
+	_, controller, model, err := ClientStoreModel(getClientStore(), c.envName)
+	if err != nil {
+		return emptyEndpoint, errors.Trace(err)
+	}
+	if len(controller.APIEndpoints) == 0 {
+		return emptyEndpoint, errors.NotFoundf("cached API endpoints for environment %q", c.envName)
+	}
+	return configstore.APIEndpoint{
+		Addresses:   controller.APIEndpoints,
+		Hostnames:   controller.Servers,
+		CACert:      controller.CACert,
+		EnvironUUID: model.ModelUUID,
+		ServerUUID:  controller.ControllerUUID,
+	}, nil
+}
+
+// LocalConnectionCredentials returns the credentials used to connect to
+// the API for the specified environment, reading them from the client
+// store if the environment has no information of its own.
+func (c *EnvCommandBase) LocalConnectionCredentials() (configstore.APICredentials, error) {
+	var emptyCreds configstore.APICredentials
+	if c.envName == "" {
+		return emptyCreds, errors.Trace(ErrNoEnvironmentSpecified)
+	}
+	info, err := ConnectionInfoForName(c.envName)
+	if err == nil {
+		return info.APICredentials(), nil
+	} else if !errors.IsNotFound(err) {
+		return emptyCreds, errors.Trace(err)
+	}
+	store := getClientStore()
+	controllerName, _, _, err := ClientStoreModel(store, c.envName)
+	if err != nil {
+		return emptyCreds, errors.Trace(err)
+	}
+	account, err := store.AccountByName(controllerName)
+	if err != nil {
+		return emptyCreds, errors.Trace(err)
+	}
+	return configstore.APICredentials{
+		User:     account.User,
+		Password: account.Password,
+	}, nil
+}
+
+// ClientStoreModel returns the model with the given name from the
+// client store, with the controller hosting it. The name may be
+// qualified with the name of the controller, as in
+// "<controller>:<model>". If it is not, and more than one controller
+// hosts a model of that name, the first controller in name order is
+// used.
+func ClientStoreModel(store jujuclient.ClientStore, modelName string) (string, *jujuclient.ControllerDetails, *jujuclient.ModelDetails, error) {
+	if i := strings.Index(modelName, ":"); i >= 0 {
+		controllerName := modelName[:i]
+		controller, err := store.ControllerByName(controllerName)
+		if err != nil {
+			return "", nil, nil, errors.Trace(err)
+		}
+		model, err := store.ModelByName(controllerName, modelName[i+1:])
+		if err != nil {
+			return "", nil, nil, errors.Trace(err)
+		}
+		return controllerName, controller, model, nil
+	}
+	controllers, err := store.AllControllers()
+	if err != nil {
+		return "", nil, nil, errors.Trace(err)
+	}
+	controllerNames := make([]string, 0, len(controllers))
+	for name := range controllers {
+		controllerNames = append(controllerNames, name)
+	}
+	sort.Strings(controllerNames)
+	for _, controllerName := range controllerNames {
+		model, err := store.ModelByName(controllerName, modelName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return "", nil, nil, errors.Trace(err)
+		}
+		controller := controllers[controllerName]
+		return controllerName, &controller, model, nil
+	}
+	return "", nil, nil, errors.NotFoundf("environment %q", modelName)
+}
+
 // ConnectionWriter defines the methods needed to write information about
 // a given connection.  This is a subset of the methods in the interface
 // defined in configstore.EnvironInfo.
@@ -206,6 +312,10 @@ var endpointRefresher = func(c *EnvCommandBase) (io.Closer, error) {
 	return c.NewAPIRoot()
 }
 
+var getClientStore = func() jujuclient.ClientStore {
+	return jujuclient.NewFileClientStore()
+}
+
 var getConfigStore = func() (configstore.Storage, error) {
 	store, err := configstore.Default()
 	if err != nil {
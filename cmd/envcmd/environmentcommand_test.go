@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 )
@@ -262,6 +263,90 @@ func (s *ConnectionEndpointSuite) TestAPIEndpointRefresh(c *gc.C) {
 	c.Assert(endpoint, gc.DeepEquals, newEndpoint)
 }
 
+func (s *ConnectionEndpointSuite) TestLocalAPIEndpointInStoreCached(c *gc.C) {
+	s.PatchValue(envcmd.EndpointRefresher, func(_ *envcmd.EnvCommandBase) (io.Closer, error) {
+		c.Fatalf("unexpected API connection")
+		return nil, nil
+	})
+	cmd, err := initTestCommand(c, "-e", "env-name")
+	c.Assert(err, jc.ErrorIsNil)
+	endpoint, err := cmd.LocalConnectionEndpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoint, gc.DeepEquals, s.endpoint)
+	creds, err := cmd.LocalConnectionCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.Equals, configstore.APICredentials{User: "foo", Password: "foopass"})
+}
+
+func (s *ConnectionEndpointSuite) TestLocalAPIEndpointFromClientStore(c *gc.C) {
+	store := jujuclient.NewMemStore()
+	s.PatchValue(envcmd.GetClientStore, func() jujuclient.ClientStore { return store })
+	err := store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		Servers:        []string{"ctrl.example.com:17070"},
+		ControllerUUID: "ctrl-uuid",
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         "ctrl-cert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateModel("ctrl", "cached-env", jujuclient.ModelDetails{ModelUUID: "cached-uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "bob", Password: "bobpass"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cmd, err := initTestCommand(c, "-e", "cached-env")
+	c.Assert(err, jc.ErrorIsNil)
+	endpoint, err := cmd.LocalConnectionEndpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoint, jc.DeepEquals, configstore.APIEndpoint{
+		Addresses:   []string{"10.0.0.1:17070"},
+		Hostnames:   []string{"ctrl.example.com:17070"},
+		CACert:      "ctrl-cert",
+		EnvironUUID: "cached-uuid",
+		ServerUUID:  "ctrl-uuid",
+	})
+	creds, err := cmd.LocalConnectionCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.Equals, configstore.APICredentials{User: "bob", Password: "bobpass"})
+}
+
+func (s *ConnectionEndpointSuite) TestLocalAPIEndpointFromClientStoreQualified(c *gc.C) {
+	store := jujuclient.NewMemStore()
+	s.PatchValue(envcmd.GetClientStore, func() jujuclient.ClientStore { return store })
+	for _, name := range []string{"a-ctrl", "b-ctrl"} {
+		err := store.UpdateController(name, jujuclient.ControllerDetails{
+			ControllerUUID: name + "-uuid",
+			APIEndpoints:   []string{name + ".example.com:17070"},
+			CACert:         name + "-cert",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = store.UpdateModel(name, "cached-env", jujuclient.ModelDetails{ModelUUID: name + "-env-uuid"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// A name qualified with the controller selects the model on that
+	// controller, rather than the first controller hosting it.
+	cmd, err := initTestCommand(c, "-e", "b-ctrl:cached-env")
+	c.Assert(err, jc.ErrorIsNil)
+	endpoint, err := cmd.LocalConnectionEndpoint()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(endpoint.EnvironUUID, gc.Equals, "b-ctrl-env-uuid")
+	c.Assert(endpoint.ServerUUID, gc.Equals, "b-ctrl-uuid")
+
+	cmd, err = initTestCommand(c, "-e", "c-ctrl:cached-env")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmd.LocalConnectionEndpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConnectionEndpointSuite) TestLocalAPIEndpointNotCached(c *gc.C) {
+	s.PatchValue(envcmd.GetClientStore, jujuclient.NewMemStore)
+	cmd, err := initTestCommand(c, "-e", "no-such-env")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cmd.LocalConnectionEndpoint()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `environment "no-such-env" not found`)
+}
+
 type closer struct{}
 
 func (*closer) Close() error {
@@ -6,5 +6,6 @@ package envcmd
 var (
 	GetCurrentEnvironmentFilePath = getCurrentEnvironmentFilePath
 	GetConfigStore                = &getConfigStore
+	GetClientStore                = &getClientStore
 	EndpointRefresher             = &endpointRefresher
 )
@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// AcceptInviteCommand accepts invitations to access environments.
+type AcceptInviteCommand struct {
+	envcmd.EnvCommandBase
+	EnvUUIDs []string
+}
+
+// AcceptInviteAPI defines the API methods used by the accept-invite
+// command.
+type AcceptInviteAPI interface {
+	Close() error
+	ListInvitations(user string) ([]params.EnvironmentInvitation, error)
+	AcceptInvitations(envUUIDs ...string) error
+}
+
+var acceptInviteDoc = `
+Accepts invitations, made with "juju share-model", to access the
+environments with the given UUIDs. The invitations are accepted on behalf
+of the user of the current environment's connection, which must be to the
+same Juju server.
+
+With no arguments, the user's pending invitations are listed.
+`
+
+func (c *AcceptInviteCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "accept-invite",
+		Args:    "[<environment-uuid> ...]",
+		Purpose: "accept invitations to access environments",
+		Doc:     acceptInviteDoc,
+	}
+}
+
+func (c *AcceptInviteCommand) Init(args []string) error {
+	for _, arg := range args {
+		if !names.IsValidEnvironment(arg) {
+			return errors.Errorf("invalid environment UUID: %q", arg)
+		}
+	}
+	c.EnvUUIDs = args
+	return nil
+}
+
+// getAcceptInviteAPI returns the API used by the accept-invite
+// command; it is a variable so it can be replaced in tests.
+var getAcceptInviteAPI = func(c *AcceptInviteCommand) (AcceptInviteAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+func (c *AcceptInviteCommand) Run(ctx *cmd.Context) error {
+	client, err := getAcceptInviteAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if len(c.EnvUUIDs) > 0 {
+		return errors.Trace(client.AcceptInvitations(c.EnvUUIDs...))
+	}
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	invitations, err := client.ListInvitations(creds.User)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(invitations) == 0 {
+		fmt.Fprintln(ctx.Stdout, "No pending invitations.")
+		return nil
+	}
+	for _, invitation := range invitations {
+		envTag, err := names.ParseEnvironTag(invitation.EnvironTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		fmt.Fprintf(ctx.Stdout, "%s %s (invited by %s)\n", envTag.Id(), invitation.EnvironName, invitation.CreatedBy)
+	}
+	return nil
+}
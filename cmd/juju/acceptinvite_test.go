@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type AcceptInviteSuite struct {
+	jujutesting.JujuConnSuite
+	api *fakeAcceptInviteAPI
+}
+
+var _ = gc.Suite(&AcceptInviteSuite{})
+
+const testInviteUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *AcceptInviteSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.api = &fakeAcceptInviteAPI{}
+	s.PatchValue(&getAcceptInviteAPI, func(*AcceptInviteCommand) (AcceptInviteAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *AcceptInviteSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AcceptInviteCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *AcceptInviteSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&AcceptInviteCommand{}, []string{"not-a-uuid"})
+	c.Assert(err, gc.ErrorMatches, `invalid environment UUID: "not-a-uuid"`)
+}
+
+func (s *AcceptInviteSuite) TestAccept(c *gc.C) {
+	out, err := s.run(c, testInviteUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "")
+	c.Assert(s.api.accepted, jc.DeepEquals, []string{testInviteUUID})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *AcceptInviteSuite) TestList(c *gc.C) {
+	s.api.invitations = []params.EnvironmentInvitation{{
+		EnvironTag:  "environment-" + testInviteUUID,
+		EnvironName: "shared",
+		CreatedBy:   "bob@local",
+	}}
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.listedFor, gc.Equals, "admin")
+	c.Assert(out, gc.Equals, testInviteUUID+" shared (invited by bob@local)\n")
+}
+
+func (s *AcceptInviteSuite) TestListNone(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "No pending invitations.\n")
+}
+
+type fakeAcceptInviteAPI struct {
+	invitations []params.EnvironmentInvitation
+	listedFor   string
+	accepted    []string
+	closed      bool
+}
+
+func (f *fakeAcceptInviteAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeAcceptInviteAPI) ListInvitations(user string) ([]params.EnvironmentInvitation, error) {
+	f.listedFor = user
+	return f.invitations, nil
+}
+
+func (f *fakeAcceptInviteAPI) AcceptInvitations(envUUIDs ...string) error {
+	f.accepted = envUUIDs
+	return nil
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/action"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
@@ -78,6 +80,11 @@ type APIClient interface {
 	// FindActionTagsByPrefix takes a list of string prefixes and finds
 	// corresponding ActionTags that match that prefix.
 	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
+
+	// WatchActionOutput returns a stream of the output written by the
+	// action with the given tag while it runs, with one JSON-encoded
+	// params.ActionOutputMessage per line.
+	WatchActionOutput(names.ActionTag) (io.ReadCloser, error)
 }
 
 // ActionCommandBase is the base type for action sub-commands.
@@ -95,5 +102,20 @@ var newAPIClient = func(c *ActionCommandBase) (APIClient, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return action.NewClient(root), nil
+	return &actionAPIClient{
+		Client:    action.NewClient(root),
+		apiClient: root.Client(),
+	}, nil
+}
+
+// actionAPIClient adds the action output stream, which is served
+// outside the Action facade, to the action API client.
+type actionAPIClient struct {
+	*action.Client
+	apiClient *api.Client
+}
+
+// WatchActionOutput is part of the APIClient interface.
+func (c *actionAPIClient) WatchActionOutput(tag names.ActionTag) (io.ReadCloser, error) {
+	return c.apiClient.WatchActionOutput(tag)
 }
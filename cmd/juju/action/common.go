@@ -60,6 +60,23 @@ func conform(input interface{}) (interface{}, error) {
 	}
 }
 
+// actionIds returns the ids of the actions of the given results, for
+// --quiet output.
+func actionIds(results []params.ActionResult) []string {
+	var ids []string
+	for _, result := range results {
+		if result.Action == nil {
+			continue
+		}
+		if tag, err := names.ParseActionTag(result.Action.Tag); err == nil {
+			ids = append(ids, tag.Id())
+		} else {
+			ids = append(ids, result.Action.Tag)
+		}
+	}
+	return ids
+}
+
 // displayActionResult returns any error from an ActionResult and displays
 // its response values otherwise.
 func displayActionResult(result params.ActionResult, ctx *cmd.Context, out cmd.Output) error {
@@ -10,6 +10,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 // DefinedCommand lists actions defined by the charm of a given service.
@@ -17,7 +18,7 @@ type DefinedCommand struct {
 	ActionCommandBase
 	serviceTag names.ServiceTag
 	fullSchema bool
-	out        cmd.Output
+	out        output.Output
 }
 
 const definedDoc = `
@@ -73,14 +74,14 @@ func (c *DefinedCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	output := actions.ActionSpecs
-	if len(output) == 0 {
-		return c.out.Write(ctx, "No actions defined for "+c.serviceTag.Id())
+	specs := actions.ActionSpecs
+	if len(specs) == 0 {
+		return c.out.WriteWithIDs(ctx, "No actions defined for "+c.serviceTag.Id(), nil)
 	}
 
 	if c.fullSchema {
 		verboseSpecs := make(map[string]interface{})
-		for k, v := range output {
+		for k, v := range specs {
 			verboseSpecs[k] = v.Params
 		}
 
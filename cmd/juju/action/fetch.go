@@ -4,6 +4,8 @@
 package action
 
 import (
+	"encoding/json"
+	"io"
 	"regexp"
 	"time"
 
@@ -12,15 +14,17 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 // FetchCommand fetches the results of an action by ID.
 type FetchCommand struct {
 	ActionCommandBase
-	out         cmd.Output
+	out         output.Output
 	requestedId string
 	fullSchema  bool
 	wait        string
+	watch       bool
 }
 
 const fetchDoc = `
@@ -32,12 +36,17 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+To follow the output of a running action, use the --watch flag.  Anything
+the action writes to stdout or stderr is copied to the same stream as it is
+written, and the results are shown once the action has finished.
 `
 
 // Set up the output.
 func (c *FetchCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.StringVar(&c.wait, "wait", "-1s", "wait for results")
+	f.BoolVar(&c.watch, "watch", false, "show the output of the action while it runs")
 }
 
 func (c *FetchCommand) Info() *cmd.Info {
@@ -83,6 +92,16 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.watch {
+		err := watchActionOutput(ctx, api, c.requestedId)
+		if errors.IsNotSupported(err) {
+			ctx.Infof("cannot watch action output: %v; waiting for results", err)
+			waitDur = 0
+		} else if err != nil {
+			return err
+		}
+	}
+
 	// tick every two seconds, to delay the loop timer.
 	tick := time.NewTimer(2 * time.Second)
 	wait := time.NewTimer(0 * time.Second)
@@ -104,7 +123,38 @@ func (c *FetchCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
-	return c.out.Write(ctx, formatActionResult(result))
+	return c.out.WriteWithIDs(ctx, formatActionResult(result), actionIds([]params.ActionResult{result}))
+}
+
+// watchActionOutput copies the output of the action with the given ID
+// prefix to the command's stdout and stderr as it is written, returning
+// once the action has finished.
+func watchActionOutput(ctx *cmd.Context, api APIClient, requestedId string) error {
+	actionTag, err := getActionTagByPrefix(api, requestedId)
+	if err != nil {
+		return err
+	}
+	stream, err := api.WatchActionOutput(actionTag)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	decoder := json.NewDecoder(stream)
+	for {
+		var message params.ActionOutputMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "cannot read action output")
+		}
+		out := ctx.Stdout
+		if message.Stream == "stderr" {
+			out = ctx.Stderr
+		}
+		if _, err := io.WriteString(out, message.Data); err != nil {
+			return errors.Trace(err)
+		}
+	}
 }
 
 // timerLoop loops indefinitely to query the given API, until "wait" times
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	jujuerrors "github.com/juju/errors"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
@@ -287,6 +288,41 @@ timing:
 	}
 }
 
+func (s *FetchSuite) TestRunWatch(c *gc.C) {
+	client := makeFakeClient(0, 10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		[]params.ActionResult{{Status: "completed"}},
+		"",
+	)
+	client.actionOutput = `{"seq":0,"stream":"stdout","data":"starting\n"}
+{"seq":1,"stream":"stderr","data":"warning\n"}
+{"seq":2,"stream":"stdout","data":"done\n"}
+`
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+	ctx, err := testing.RunCommand(c, &action.FetchCommand{}, validActionId, "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(client.watchedTag.String(), gc.Equals, validActionTagString)
+	c.Check(testing.Stdout(ctx), gc.Equals, "starting\ndone\nstatus: completed\n")
+	c.Check(testing.Stderr(ctx), gc.Equals, "warning\n")
+}
+
+func (s *FetchSuite) TestRunWatchNotSupported(c *gc.C) {
+	client := makeFakeClient(0, 10*time.Second,
+		tagsForIdPrefix(validActionId, validActionTagString),
+		[]params.ActionResult{{Status: "completed"}},
+		"",
+	)
+	client.watchErr = jujuerrors.NotSupportedf("WatchActionOutput")
+	unpatch := s.BaseActionSuite.patchAPIClient(client)
+	defer unpatch()
+	ctx, err := testing.RunCommand(c, &action.FetchCommand{}, validActionId, "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "status: completed\n")
+	c.Check(testing.Stderr(ctx), gc.Equals,
+		"cannot watch action output: WatchActionOutput not supported; waiting for results\n")
+}
+
 func testRunHelper(c *gc.C, s *FetchSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
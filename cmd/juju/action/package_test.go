@@ -5,12 +5,15 @@ package action_test
 
 import (
 	"errors"
+	"io"
 	"io/ioutil"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/names"
 	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -136,6 +139,9 @@ type fakeAPIClient struct {
 	actionsByReceivers []params.ActionsByReceiver
 	actionTagMatches   params.FindTagsResults
 	charmActions       *charm.Actions
+	actionOutput       string
+	watchedTag         names.ActionTag
+	watchErr           error
 	apiErr             error
 }
 
@@ -214,3 +220,11 @@ func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, err
 func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsResults, error) {
 	return c.actionTagMatches, c.apiErr
 }
+
+func (c *fakeAPIClient) WatchActionOutput(tag names.ActionTag) (io.ReadCloser, error) {
+	c.watchedTag = tag
+	if c.watchErr != nil {
+		return nil, c.watchErr
+	}
+	return ioutil.NopCloser(strings.NewReader(c.actionOutput)), nil
+}
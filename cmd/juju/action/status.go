@@ -10,12 +10,13 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 // StatusCommand shows the status of an Action by ID.
 type StatusCommand struct {
 	ActionCommandBase
-	out         cmd.Output
+	out         output.Output
 	requestedId string
 }
 
@@ -84,7 +85,7 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("identifier %q matched action(s) %v, but found no results", c.requestedId, actionTags)
 	}
 
-	return c.out.Write(ctx, resultsToMap(actions.Results))
+	return c.out.WriteWithIDs(ctx, resultsToMap(actions.Results), actionIds(actions.Results))
 }
 
 func resultsToMap(results []params.ActionResult) map[string]interface{} {
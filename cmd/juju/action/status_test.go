@@ -103,3 +103,25 @@ type statusTestCase struct {
 	tags        params.FindTagsResults
 	results     []params.ActionResult
 }
+
+func (s *StatusSuite) TestRunQuiet(c *gc.C) {
+	fakeid := "deadbeef-0000-4000-8000-feedfacebeef"
+	fakeid2 := "deadbeef-0001-4000-8000-feedfacebeef"
+	results := []params.ActionResult{
+		{Action: &params.Action{Tag: "action-" + fakeid, Receiver: "unit-mysql-0"}, Status: "completed"},
+		{Action: &params.Action{Tag: "action-" + fakeid2, Receiver: "unit-mysql-1"}, Status: "running"},
+	}
+	fakeClient := makeFakeClient(
+		0*time.Second,
+		5*time.Second,
+		tagsForIdPrefix("", "action-"+fakeid, "action-"+fakeid2),
+		results,
+		"",
+	)
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, &action.StatusCommand{}, "--quiet")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, fakeid+"\n"+fakeid2+"\n")
+}
@@ -0,0 +1,147 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// adoptInstanceAPI defines the methods on the client API that the
+// adopt-instance command uses.
+type adoptInstanceAPI interface {
+	manual.ProvisioningClientAPI
+	ProviderInstance(id instance.Id) (*params.ProviderInstanceResult, error)
+	Close() error
+}
+
+var newAdoptInstanceAPI = func(c *AdoptInstanceCommand) (adoptInstanceAPI, error) {
+	return c.NewAPIClient()
+}
+
+var adoptInstance = manual.AdoptInstance
+
+// AdoptInstanceCommand brings an existing instance of the
+// environment's provider under the management of Juju.
+type AdoptInstanceCommand struct {
+	envcmd.EnvCommandBase
+	InstanceId instance.Id
+	User       string
+	Address    string
+}
+
+const adoptInstanceDoc = `
+Register an existing instance of the environment's provider as a Juju
+machine, so that an instance started outside Juju can be managed by it.
+
+The provider is asked for the instance's addresses and availability
+zone. The instance is then reached over SSH at its public address, or
+at the address given with --address, to detect its series and hardware
+and to install a machine agent, as for "juju machine add ssh:<host>".
+Use --ssh-user to log in as a user other than "ubuntu" when the
+instance is first reached; that user must be able to use sudo.
+
+Unlike a manually provisioned machine, an adopted machine is associated
+with the provider's instance, so the instance is stopped by the
+provider when the machine is removed. Instances that are already Juju
+machines cannot be adopted.
+
+Examples:
+    juju adopt-instance i-0a1b2c3d
+    juju adopt-instance i-0a1b2c3d --ssh-user ec2-user --address 10.0.0.12
+`
+
+func (c *AdoptInstanceCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "adopt-instance",
+		Args:    "<provider instance id>",
+		Purpose: "register an existing provider instance as a machine",
+		Doc:     adoptInstanceDoc,
+	}
+}
+
+func (c *AdoptInstanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.User, "ssh-user", "", "the user to log in as when first reaching the instance")
+	f.StringVar(&c.Address, "address", "", "the address at which to reach the instance over SSH")
+}
+
+func (c *AdoptInstanceCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no instance id specified")
+	case 1:
+		c.InstanceId = instance.Id(args[0])
+		return nil
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+}
+
+func (c *AdoptInstanceCommand) Run(ctx *cmd.Context) error {
+	client, err := newAdoptInstanceAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	info, err := client.ProviderInstance(c.InstanceId)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get instance %q", c.InstanceId)
+	}
+	if info.MachineId != "" {
+		return errors.Errorf("instance %q is already machine %s", c.InstanceId, info.MachineId)
+	}
+	addrs := params.NetworkAddresses(info.Addresses)
+	host := c.Address
+	if host == "" {
+		host = network.SelectPublicAddress(addrs)
+	}
+	if host == "" {
+		return errors.Errorf("no address found for instance %q; use --address", c.InstanceId)
+	}
+	if c.User != "" {
+		host = c.User + "@" + host
+	}
+
+	store, err := configstore.Default()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	config, err := c.Config(store)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	machineId, err := adoptInstance(manual.AdoptInstanceArgs{
+		ProvisionMachineArgs: manual.ProvisionMachineArgs{
+			Host:   host,
+			Client: client,
+			Stdin:  ctx.Stdin,
+			Stdout: ctx.Stdout,
+			Stderr: ctx.Stderr,
+			UpdateBehavior: &params.UpdateBehavior{
+				config.EnableOSRefreshUpdate(),
+				config.EnableOSUpgrade(),
+			},
+		},
+		InstanceId:       c.InstanceId,
+		Addresses:        addrs,
+		AvailabilityZone: info.AvailabilityZone,
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("adopted instance %q as machine %s", c.InstanceId, machineId)
+	return nil
+}
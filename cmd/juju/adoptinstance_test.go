@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/testing"
+)
+
+type AdoptInstanceSuite struct {
+	testing.FakeJujuHomeSuite
+	api     *fakeAdoptInstanceAPI
+	adopted []manual.AdoptInstanceArgs
+}
+
+var _ = gc.Suite(&AdoptInstanceSuite{})
+
+type fakeAdoptInstanceAPI struct {
+	manual.ProvisioningClientAPI
+	info   *params.ProviderInstanceResult
+	err    error
+	closed bool
+}
+
+func (f *fakeAdoptInstanceAPI) ProviderInstance(id instance.Id) (*params.ProviderInstanceResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.info, nil
+}
+
+func (f *fakeAdoptInstanceAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *AdoptInstanceSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAdoptInstanceAPI{
+		info: &params.ProviderInstanceResult{
+			InstanceId:       "i-legacy",
+			Addresses:        params.FromNetworkAddresses(network.NewAddresses("10.0.0.1", "203.0.113.7")),
+			AvailabilityZone: "zone-a",
+		},
+	}
+	s.adopted = nil
+	s.PatchValue(&newAdoptInstanceAPI, func(_ *AdoptInstanceCommand) (adoptInstanceAPI, error) {
+		return s.api, nil
+	})
+	s.PatchValue(&adoptInstance, func(args manual.AdoptInstanceArgs) (string, error) {
+		s.adopted = append(s.adopted, args)
+		return "42", nil
+	})
+}
+
+func (s *AdoptInstanceSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&AdoptInstanceCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no instance id specified")
+	err = testing.InitCommand(envcmd.Wrap(&AdoptInstanceCommand{}), []string{"i-1", "i-2"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["i-2"\]`)
+}
+
+func (s *AdoptInstanceSuite) TestAdoptInstance(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&AdoptInstanceCommand{}), "i-legacy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "adopted instance \"i-legacy\" as machine 42\n")
+	c.Assert(s.api.closed, jc.IsTrue)
+	c.Assert(s.adopted, gc.HasLen, 1)
+	args := s.adopted[0]
+	c.Assert(args.Host, gc.Equals, "203.0.113.7")
+	c.Assert(args.InstanceId, gc.Equals, instance.Id("i-legacy"))
+	c.Assert(args.Addresses, jc.DeepEquals, network.NewAddresses("10.0.0.1", "203.0.113.7"))
+	c.Assert(args.AvailabilityZone, gc.Equals, "zone-a")
+	c.Assert(args.Client, gc.Equals, s.api)
+	c.Assert(args.UpdateBehavior, gc.NotNil)
+}
+
+func (s *AdoptInstanceSuite) TestAdoptInstanceUserAndAddress(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AdoptInstanceCommand{}), "i-legacy", "--ssh-user", "ec2-user", "--address", "10.0.0.1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.adopted, gc.HasLen, 1)
+	c.Assert(s.adopted[0].Host, gc.Equals, "ec2-user@10.0.0.1")
+}
+
+func (s *AdoptInstanceSuite) TestAdoptInstanceNoAddress(c *gc.C) {
+	s.api.info.Addresses = nil
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AdoptInstanceCommand{}), "i-legacy")
+	c.Assert(err, gc.ErrorMatches, `no address found for instance "i-legacy"; use --address`)
+	c.Assert(s.adopted, gc.HasLen, 0)
+}
+
+func (s *AdoptInstanceSuite) TestAdoptInstanceAlreadyMachine(c *gc.C) {
+	s.api.info.MachineId = "3"
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AdoptInstanceCommand{}), "i-legacy")
+	c.Assert(err, gc.ErrorMatches, `instance "i-legacy" is already machine 3`)
+	c.Assert(s.adopted, gc.HasLen, 0)
+}
+
+func (s *AdoptInstanceSuite) TestAdoptInstanceNotFound(c *gc.C) {
+	s.api.err = errors.NotFoundf(`instance "i-legacy"`)
+	_, err := testing.RunCommand(c, envcmd.Wrap(&AdoptInstanceCommand{}), "i-legacy")
+	c.Assert(err, gc.ErrorMatches, `cannot get instance "i-legacy": instance "i-legacy" not found`)
+	c.Assert(s.adopted, gc.HasLen, 0)
+}
@@ -20,6 +20,7 @@ type APIInfoCommand struct {
 	envcmd.EnvCommandBase
 	out      cmd.Output
 	refresh  bool
+	local    bool
 	user     bool
 	password bool
 	cacert   bool
@@ -43,6 +44,10 @@ available fields are:
 If "password" is included as a field, or the --password option is given, the
 password value will be shown.
 
+With --local, api-info never connects to the API server, and answers from the
+information cached for the environment, or for its controller in the client
+store. It fails if nothing is cached, rather than connecting.
+
 
 Examples:
   $ juju api-info
@@ -78,6 +83,9 @@ func (c *APIInfoCommand) Info() *cmd.Info {
 
 func (c *APIInfoCommand) Init(args []string) error {
 	c.fields = args
+	if c.local && c.refresh {
+		return errors.New("--local cannot be used with --refresh")
+	}
 	if len(args) == 0 {
 		c.user = true
 		c.envuuid = true
@@ -121,6 +129,7 @@ func (c *APIInfoCommand) SetFlags(f *gnuflag.FlagSet) {
 	})
 	f.BoolVar(&c.refresh, "refresh", false, "connect to the API to ensure an up-to-date endpoint location")
 	f.BoolVar(&c.password, "password", false, "include the password in the output fields")
+	f.BoolVar(&c.local, "local", false, "do not connect to the API; use only cached information")
 }
 
 func connectionEndpoint(c envcmd.EnvCommandBase, refresh bool) (configstore.APIEndpoint, error) {
@@ -131,18 +140,39 @@ func connectionCredentials(c envcmd.EnvCommandBase) (configstore.APICredentials,
 	return c.ConnectionCredentials()
 }
 
+func localConnectionEndpoint(c envcmd.EnvCommandBase) (configstore.APIEndpoint, error) {
+	return c.LocalConnectionEndpoint()
+}
+
+func localConnectionCredentials(c envcmd.EnvCommandBase) (configstore.APICredentials, error) {
+	return c.LocalConnectionCredentials()
+}
+
 var (
-	endpoint = connectionEndpoint
-	creds    = connectionCredentials
+	endpoint      = connectionEndpoint
+	creds         = connectionCredentials
+	localEndpoint = localConnectionEndpoint
+	localCreds    = localConnectionCredentials
 )
 
 // Print out the addresses of the API server endpoints.
 func (c *APIInfoCommand) Run(ctx *cmd.Context) error {
-	apiendpoint, err := endpoint(c.EnvCommandBase, c.refresh)
+	var apiendpoint configstore.APIEndpoint
+	var credentials configstore.APICredentials
+	var err error
+	if c.local {
+		apiendpoint, err = localEndpoint(c.EnvCommandBase)
+	} else {
+		apiendpoint, err = endpoint(c.EnvCommandBase, c.refresh)
+	}
 	if err != nil {
 		return err
 	}
-	credentials, err := creds(c.EnvCommandBase)
+	if c.local {
+		credentials, err = localCreds(c.EnvCommandBase)
+	} else {
+		credentials, err = creds(c.EnvCommandBase)
+	}
 	if err != nil {
 		return err
 	}
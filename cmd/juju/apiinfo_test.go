@@ -25,6 +25,7 @@ func (s *APIInfoSuite) TestArgParsing(c *gc.C) {
 		message  string
 		args     []string
 		refresh  bool
+		local    bool
 		user     bool
 		password bool
 		cacert   bool
@@ -87,6 +88,19 @@ func (s *APIInfoSuite) TestArgParsing(c *gc.C) {
 			args:     []string{"user", "password"},
 			user:     true,
 			password: true,
+		}, {
+			message: "answer from the cache only",
+			args:    []string{"--local"},
+			local:   true,
+			user:    true,
+			cacert:  true,
+			servers: true,
+			envuuid: true,
+			srvuuid: true,
+		}, {
+			message:  "local and refresh conflict",
+			args:     []string{"--local", "--refresh"},
+			errMatch: "--local cannot be used with --refresh",
 		}, {
 			message:  "unknown field field",
 			args:     []string{"foo"},
@@ -103,6 +117,7 @@ func (s *APIInfoSuite) TestArgParsing(c *gc.C) {
 		if test.errMatch == "" {
 			c.Check(err, jc.ErrorIsNil)
 			c.Check(command.refresh, gc.Equals, test.refresh)
+			c.Check(command.local, gc.Equals, test.local)
 			c.Check(command.user, gc.Equals, test.user)
 			c.Check(command.password, gc.Equals, test.password)
 			c.Check(command.cacert, gc.Equals, test.cacert)
@@ -251,6 +266,29 @@ func (s *APIInfoSuite) TestOutputNoServerUUID(c *gc.C) {
 	c.Check(testing.Stdout(ctx), gc.Equals, expected)
 }
 
+func (s *APIInfoSuite) TestOutputLocal(c *gc.C) {
+	s.PatchValue(&endpoint, func(c envcmd.EnvCommandBase, refresh bool) (configstore.APIEndpoint, error) {
+		return configstore.APIEndpoint{}, fmt.Errorf("unexpected connection")
+	})
+	s.PatchValue(&creds, func(c envcmd.EnvCommandBase) (configstore.APICredentials, error) {
+		return configstore.APICredentials{}, fmt.Errorf("unexpected connection")
+	})
+	s.PatchValue(&localEndpoint, func(c envcmd.EnvCommandBase) (configstore.APIEndpoint, error) {
+		return configstore.APIEndpoint{
+			Addresses:   []string{"10.0.3.1:12345"},
+			EnvironUUID: "deadbeef-dead-beef-dead-deaddeaddead",
+		}, nil
+	})
+	s.PatchValue(&localCreds, func(c envcmd.EnvCommandBase) (configstore.APICredentials, error) {
+		return configstore.APICredentials{User: "tester"}, nil
+	})
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&APIInfoCommand{}), "--local", "user", "environ-uuid")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"user: tester\n"+
+		"environ-uuid: deadbeef-dead-beef-dead-deaddeaddead\n")
+}
+
 func (s *APIInfoSuite) TestEndpointError(c *gc.C) {
 	s.PatchValue(&endpoint, func(c envcmd.EnvCommandBase, refresh bool) (configstore.APIEndpoint, error) {
 		return configstore.APIEndpoint{}, fmt.Errorf("oops, no endpoint")
@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/apischema"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// apiSchemaAPI defines the methods on the APISchema API that the
+// api-schema command uses.
+type apiSchemaAPI interface {
+	Schema() (params.APISchemaResult, error)
+	Close() error
+}
+
+var newAPISchemaAPI = func(c *APISchemaCommand) (apiSchemaAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return apischema.NewClient(root), nil
+}
+
+// APISchemaCommand writes a description of the API served by the
+// state server.
+type APISchemaCommand struct {
+	envcmd.EnvCommandBase
+	out cmd.Output
+}
+
+const apiSchemaDoc = `
+Write a machine-readable description of the API served by the state
+server, for generating clients in other languages. Only the owner of
+the state server environment may do so.
+
+Every version of every facade is described, with its methods and the
+types of their arguments and results. The description is generated
+from the running API server, so it is always accurate for that server.
+
+Each type is described by its kind: one of bool, int, float, string,
+time (an RFC 3339 string), bytes (a base64 string), any, array or map
+(keyed by strings, with elem describing the elements), struct (with
+fields) or ref. Named struct types are defined once, under types, and
+referred to by name as refs. Optional fields are left out when empty.
+
+Examples:
+    juju api-schema > schema.json
+    juju api-schema --format yaml
+`
+
+func (c *APISchemaCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "api-schema",
+		Purpose: "describe the facades served by the API server",
+		Doc:     apiSchemaDoc,
+	}
+}
+
+func (c *APISchemaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "json", map[string]cmd.Formatter{
+		"json": cmd.FormatJson,
+		"yaml": formatAPISchemaYaml,
+	})
+}
+
+func (c *APISchemaCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *APISchemaCommand) Run(ctx *cmd.Context) error {
+	client, err := newAPISchemaAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	schema, err := client.Schema()
+	if err != nil {
+		return errors.Annotate(err, "cannot describe the API")
+	}
+	return c.out.Write(ctx, schema)
+}
+
+// formatAPISchemaYaml writes the schema as YAML with the same keys as
+// its JSON encoding, which the params types do not tag for YAML.
+func formatAPISchemaYaml(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cmd.FormatYaml(schema)
+}
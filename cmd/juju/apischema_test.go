@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type APISchemaSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeAPISchemaAPI
+}
+
+var _ = gc.Suite(&APISchemaSuite{})
+
+type fakeAPISchemaAPI struct {
+	schema params.APISchemaResult
+	err    error
+	closed bool
+}
+
+func (f *fakeAPISchemaAPI) Schema() (params.APISchemaResult, error) {
+	return f.schema, f.err
+}
+
+func (f *fakeAPISchemaAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *APISchemaSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAPISchemaAPI{
+		schema: params.APISchemaResult{
+			Facades: []params.FacadeSchema{{
+				Name:    "Pinger",
+				Version: 0,
+				Methods: []params.MethodSchema{{
+					Name:   "Ping",
+					Params: &params.TypeSchema{Kind: "ref", Ref: "params.Entity"},
+				}},
+			}},
+			Types: map[string]params.TypeSchema{
+				"params.Entity": {Kind: "struct", Fields: []params.FieldSchema{{
+					Name: "Tag",
+					Type: params.TypeSchema{Kind: "string"},
+				}}},
+			},
+		},
+	}
+	s.PatchValue(&newAPISchemaAPI, func(_ *APISchemaCommand) (apiSchemaAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *APISchemaSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&APISchemaCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *APISchemaSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&APISchemaCommand{}, []string{"Client"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["Client"\]`)
+}
+
+func (s *APISchemaSuite) TestJSON(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `{"facades":[{"name":"Pinger","version":0,"methods":[{"name":"Ping","params":{"kind":"ref","ref":"params.Entity"}}]}],"types":{"params.Entity":{"kind":"struct","fields":[{"name":"Tag","type":{"kind":"string"}}]}}}`+"\n")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *APISchemaSuite) TestYaml(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"facades:\n"+
+		"- methods:\n"+
+		"  - name: Ping\n"+
+		"    params:\n"+
+		"      kind: ref\n"+
+		"      ref: params.Entity\n"+
+		"  name: Pinger\n"+
+		"  version: 0\n"+
+		"types:\n"+
+		"  params.Entity:\n"+
+		"    fields:\n"+
+		"    - name: Tag\n"+
+		"      type:\n"+
+		"        kind: string\n"+
+		"    kind: struct\n",
+	)
+}
+
+func (s *APISchemaSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("permission denied")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "cannot describe the API: permission denied")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const listCommandDoc = `
@@ -25,7 +26,7 @@ For enabled blocks, block message is shown if it was specified.
 // ListCommand list blocks.
 type ListCommand struct {
 	envcmd.EnvCommandBase
-	out cmd.Output
+	out output.Output
 }
 
 // Init implements Command.Init.
@@ -84,6 +85,11 @@ type BlockInfo struct {
 	Message   *string `yaml:"message,omitempty" json:"message,omitempty"`
 }
 
+// ID implements output.Identifier.
+func (b BlockInfo) ID() string {
+	return b.Operation
+}
+
 // formatBlockInfo takes a set of Block and creates a
 // mapping to information structures.
 func formatBlockInfo(all []params.Block) []BlockInfo {
@@ -71,3 +71,24 @@ func (s *listCommandSuite) TestListJson(c *gc.C) {
 	c.Assert(testing.Stdout(ctx), gc.Equals, `[{"block":"destroy-environment","enabled":false},{"block":"remove-object","enabled":true,"message":"Test this one"},{"block":"all-changes","enabled":false}]
 `)
 }
+
+func (s *listCommandSuite) TestListJSONLines(c *gc.C) {
+	s.mockClient.SwitchBlockOn(string(multiwatcher.BlockRemove), "Test this one")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&block.ListCommand{}), "--format", "jsonl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+{"block":"destroy-environment","enabled":false}
+{"block":"remove-object","enabled":true,"message":"Test this one"}
+{"block":"all-changes","enabled":false}
+`[1:])
+}
+
+func (s *listCommandSuite) TestListQuiet(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&block.ListCommand{}), "--quiet")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+destroy-environment
+remove-object
+all-changes
+`[1:])
+}
@@ -11,6 +11,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const ListCommandDoc = `
@@ -37,7 +38,7 @@ Examples:
 // ListCommand shows the images in the Juju server.
 type ListCommand struct {
 	CachedImagesCommandBase
-	out                cmd.Output
+	out                output.Output
 	Kind, Series, Arch string
 }
 
@@ -86,6 +87,12 @@ type ImageInfo struct {
 	Created   string `yaml:"created" json:"created"`
 }
 
+// ID implements output.Identifier, identifying the image by the
+// values given to "juju cached-images delete".
+func (i ImageInfo) ID() string {
+	return i.Kind + "/" + i.Series + "/" + i.Arch
+}
+
 func (c *ListCommand) imageMetadataToImageInfo(images []params.ImageMetadata) []ImageInfo {
 	var output []ImageInfo
 	for _, metadata := range images {
@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/consistency"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+// consistencyAPI defines the methods on the consistency API that the
+// check-state command uses.
+type consistencyAPI interface {
+	CheckConsistency(repair bool) ([]params.Inconsistency, error)
+	Close() error
+}
+
+var newConsistencyAPI = func(c *CheckStateCommand) (consistencyAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return consistency.NewClient(root), nil
+}
+
+// CheckStateCommand checks the referential integrity of the state
+// database for an environment.
+type CheckStateCommand struct {
+	envcmd.EnvCommandBase
+	out    output.Output
+	repair bool
+}
+
+const checkStateDoc = `
+Check the documents recorded for the environment in the state database
+for problems such as units of missing services, ports opened on missing
+machines, mismatched environment UUIDs and settings or leadership left
+behind by removed services.
+
+With --repair, the problems that can safely be repaired are repaired.
+Only the administrator of the state server environment may check state.
+
+Examples:
+    juju check-state
+    juju check-state --repair --format yaml
+`
+
+func (c *CheckStateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "check-state",
+		Purpose: "check the state database for inconsistencies",
+		Doc:     checkStateDoc,
+	}
+}
+
+func (c *CheckStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.repair, "repair", false, "Repair the problems that can be safely repaired")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatInconsistenciesTabular,
+	})
+}
+
+func (c *CheckStateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// inconsistency describes a problem shown by the check-state command.
+type inconsistency struct {
+	Kind       string `json:"kind" yaml:"kind"`
+	Collection string `json:"collection" yaml:"collection"`
+	DocID      string `json:"doc-id" yaml:"doc-id"`
+	Detail     string `json:"detail" yaml:"detail"`
+	Repairable bool   `json:"repairable" yaml:"repairable"`
+	Repaired   bool   `json:"repaired" yaml:"repaired"`
+}
+
+// ID implements output.Identifier. Document ids are only unique
+// within their collection, so the id is qualified with it.
+func (p inconsistency) ID() string {
+	return p.Collection + ":" + p.DocID
+}
+
+func (c *CheckStateCommand) Run(ctx *cmd.Context) error {
+	client, err := newConsistencyAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	found, err := client.CheckConsistency(c.repair)
+	if err != nil {
+		return errors.Annotate(err, "cannot check state")
+	}
+	problems := make([]inconsistency, len(found))
+	for i, problem := range found {
+		problems[i] = inconsistency{
+			Kind:       problem.Kind,
+			Collection: problem.Collection,
+			DocID:      problem.DocID,
+			Detail:     problem.Detail,
+			Repairable: problem.Repairable,
+			Repaired:   problem.Repaired,
+		}
+	}
+	return c.out.Write(ctx, problems)
+}
+
+// formatInconsistenciesTabular returns a table of the problems found
+// by check-state.
+func formatInconsistenciesTabular(value interface{}) ([]byte, error) {
+	problems, ok := value.([]inconsistency)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", problems, value)
+	}
+	if len(problems) == 0 {
+		return []byte("no problems found"), nil
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tCOLLECTION\tID\tREPAIR\tDETAIL")
+	for _, p := range problems {
+		repair := "-"
+		if p.Repaired {
+			repair = "repaired"
+		} else if p.Repairable {
+			repair = "repairable"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", p.Kind, p.Collection, p.DocID, repair, p.Detail)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
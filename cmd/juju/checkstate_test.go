@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type CheckStateSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeConsistencyAPI
+}
+
+var _ = gc.Suite(&CheckStateSuite{})
+
+type fakeConsistencyAPI struct {
+	repair          bool
+	inconsistencies []params.Inconsistency
+	err             error
+	closed          bool
+}
+
+func (f *fakeConsistencyAPI) CheckConsistency(repair bool) ([]params.Inconsistency, error) {
+	f.repair = repair
+	return f.inconsistencies, f.err
+}
+
+func (f *fakeConsistencyAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *CheckStateSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeConsistencyAPI{
+		inconsistencies: []params.Inconsistency{{
+			Kind:       "unit-without-service",
+			Collection: "units",
+			DocID:      "uuid:wordpress/0",
+			Detail:     `unit "wordpress/0" belongs to missing service "wordpress"`,
+		}, {
+			Kind:       "ports-without-machine",
+			Collection: "openedPorts",
+			DocID:      "uuid:m#1#n#juju-public",
+			Detail:     `ports opened on missing machine "1"`,
+			Repairable: true,
+		}},
+	}
+	s.PatchValue(&newConsistencyAPI, func(_ *CheckStateCommand) (consistencyAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *CheckStateSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&CheckStateCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *CheckStateSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&CheckStateCommand{}, []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *CheckStateSuite) TestTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"KIND                  COLLECTION  ID                     REPAIR     DETAIL\n"+
+		"unit-without-service  units       uuid:wordpress/0       -          unit \"wordpress/0\" belongs to missing service \"wordpress\"\n"+
+		"ports-without-machine openedPorts uuid:m#1#n#juju-public repairable ports opened on missing machine \"1\"\n",
+	)
+	c.Assert(s.api.repair, jc.IsFalse)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *CheckStateSuite) TestQuiet(c *gc.C) {
+	out, err := s.run(c, "--quiet")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "units:uuid:wordpress/0\nopenedPorts:uuid:m#1#n#juju-public\n")
+}
+
+func (s *CheckStateSuite) TestNoProblems(c *gc.C) {
+	s.api.inconsistencies = nil
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "no problems found\n")
+}
+
+func (s *CheckStateSuite) TestRepair(c *gc.C) {
+	s.api.inconsistencies = s.api.inconsistencies[1:]
+	s.api.inconsistencies[0].Repaired = true
+	out, err := s.run(c, "--repair", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- kind: ports-without-machine\n"+
+		"  collection: openedPorts\n"+
+		"  doc-id: uuid:m#1#n#juju-public\n"+
+		"  detail: ports opened on missing machine \"1\"\n"+
+		"  repairable: true\n"+
+		"  repaired: true\n",
+	)
+	c.Assert(s.api.repair, jc.IsTrue)
+}
+
+func (s *CheckStateSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("permission denied")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "cannot check state: permission denied")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/charmstore"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
@@ -119,9 +120,12 @@ func environFromNameProductionFunc(
 // will be used to add any necessary attributes to the repo
 // and to resolve the default series if possible.
 //
+// Charm store operations that fail because the store cannot be
+// reached are retried according to the given policy.
+//
 // resolveCharmURL also returns the charm repository holding
 // the charm.
-func resolveCharmURL(curlStr string, csParams charmrepo.NewCharmStoreParams, repoPath string, conf *config.Config) (*charm.URL, charmrepo.Interface, error) {
+func resolveCharmURL(curlStr string, csParams charmrepo.NewCharmStoreParams, repoPath string, conf *config.Config, retry charmstore.RetryPolicy) (*charm.URL, charmrepo.Interface, error) {
 	ref, err := charm.ParseReference(curlStr)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
@@ -130,7 +134,7 @@ func resolveCharmURL(curlStr string, csParams charmrepo.NewCharmStoreParams, rep
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
-	repo = config.SpecializeCharmRepo(repo, conf)
+	repo = charmstore.NewRetryingRepo(config.SpecializeCharmRepo(repo, conf), retry, nil)
 	if ref.Series == "" {
 		if defaultSeries, ok := conf.DefaultSeries(); ok {
 			ref.Series = defaultSeries
@@ -195,6 +199,16 @@ func addCharmViaAPI(client *api.Client, ctx *cmd.Context, curl *charm.URL, repo
 	return curl, nil
 }
 
+// charmStoreRetryPolicy returns the policy for retrying charm store
+// operations run by a command, which reports each retry to the user.
+func charmStoreRetryPolicy(ctx *cmd.Context) charmstore.RetryPolicy {
+	policy := charmstore.DefaultRetryPolicy
+	policy.Notify = func(attempt int, err error, wait time.Duration) {
+		ctx.Infof("Cannot reach the charm store (attempt %d of %d), retrying in %v: %v", attempt, policy.Attempts, wait, err)
+	}
+	return policy
+}
+
 // csClient gives access to the charm store server and provides parameters
 // for connecting to the charm store.
 type csClient struct {
@@ -237,6 +251,15 @@ func newHTTPClient() (*cookiejar.Jar, *http.Client, error) {
 	return jar, client, nil
 }
 
+// useEnvironmentCharmStore points the client at the charm store
+// configured for the environment, if one has been set, so that charms
+// are resolved and authorized against the store the environment uses.
+func (c *csClient) useEnvironmentCharmStore(conf *config.Config) {
+	if csURL, ok := conf.CharmStoreURL(); ok {
+		c.params.URL = csURL
+	}
+}
+
 // authorize acquires and return the charm store delegatable macaroon to be
 // used to add the charm corresponding to the given URL.
 // The macaroon is properly attenuated so that it can only be used to deploy
@@ -5,11 +5,13 @@ package common
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/constraints"
@@ -49,6 +51,30 @@ See Also:
    juju help add-unit
 `
 
+const showConstraintsDoc = `
+show-constraints shows the constraints recorded for a unit when it was
+added. These are the environment and service constraints that were in
+force at the time.
+
+With --effective, it shows instead the constraints that would be used to
+provision a new machine for the unit now, and where each of them comes
+from. Constraints are resolved in order of increasing precedence:
+environment constraints, then service constraints, then constraints
+given with a placement directive. A constraint set at a more specific
+level overrides the same constraint, and any that conflict with it, at
+the less specific levels.
+
+Examples:
+
+   show-constraints wordpress/0
+   show-constraints --effective wordpress/0
+
+See Also:
+   juju help constraints
+   juju help get-constraints
+   juju help set-constraints
+`
+
 // GetConstraintsCommand shows the constraints for a service or environment.
 type GetConstraintsCommand struct {
 	envcmd.EnvCommandBase
@@ -175,3 +201,98 @@ func (c *SetConstraintsCommand) Run(_ *cmd.Context) (err error) {
 	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
+
+// UnitConstraintsAPI defines the methods on the client API that the
+// show-constraints command calls.
+type UnitConstraintsAPI interface {
+	Close() error
+	UnitConstraints(unit string) (params.UnitConstraintsResult, error)
+}
+
+// ShowConstraintsCommand shows the recorded or effective constraints
+// of a unit.
+type ShowConstraintsCommand struct {
+	envcmd.EnvCommandBase
+	UnitName  string
+	Effective bool
+	out       cmd.Output
+	api       UnitConstraintsAPI
+}
+
+// effectiveConstraints holds the effective constraints of a unit, as
+// written by show-constraints --effective.
+type effectiveConstraints struct {
+	Constraints constraints.Value `yaml:"constraints" json:"constraints"`
+	Sources     map[string]string `yaml:"sources,omitempty" json:"sources,omitempty"`
+}
+
+func (c *ShowConstraintsCommand) getAPI() (UnitConstraintsAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *ShowConstraintsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-constraints",
+		Args:    "<unit>",
+		Purpose: "view the recorded or effective constraints of a unit",
+		Doc:     showConstraintsDoc,
+	}
+}
+
+// formatUnitConstraints writes effective constraints one per line,
+// each followed by its source.
+func formatUnitConstraints(value interface{}) ([]byte, error) {
+	effective, ok := value.(effectiveConstraints)
+	if !ok {
+		return formatConstraints(value)
+	}
+	var lines []string
+	for _, attr := range strings.Fields(effective.Constraints.String()) {
+		name := strings.SplitN(attr, "=", 2)[0]
+		lines = append(lines, fmt.Sprintf("%s (%s)", attr, effective.Sources[name]))
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+func (c *ShowConstraintsCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Effective, "effective", false, "show the constraints that would be used now, and their sources")
+	c.out.AddFlags(f, "constraints", map[string]cmd.Formatter{
+		"constraints": formatUnitConstraints,
+		"yaml":        cmd.FormatYaml,
+		"json":        cmd.FormatJson,
+	})
+}
+
+func (c *ShowConstraintsCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no unit specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return fmt.Errorf("invalid unit name %q", args[0])
+	}
+	c.UnitName, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ShowConstraintsCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+
+	result, err := apiclient.UnitConstraints(c.UnitName)
+	if err != nil {
+		return err
+	}
+	if !c.Effective {
+		return c.out.Write(ctx, result.Constraints)
+	}
+	return c.out.Write(ctx, effectiveConstraints{
+		Constraints: result.Effective,
+		Sources:     result.Sources,
+	})
+}
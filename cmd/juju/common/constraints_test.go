@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	// TODO(dimitern): Don't ever import "." unless there's a GOOD
 	// reason to do it.
@@ -33,6 +34,7 @@ type fakeConstraintsClient struct {
 	err      error
 	envCons  constraints.Value
 	servCons map[string]constraints.Value
+	unitCons map[string]params.UnitConstraintsResult
 }
 
 func (f *fakeConstraintsClient) addTestingService(name string) {
@@ -60,6 +62,14 @@ func (f *fakeConstraintsClient) GetServiceConstraints(name string) (constraints.
 	return cons, nil
 }
 
+func (f *fakeConstraintsClient) UnitConstraints(name string) (params.UnitConstraintsResult, error) {
+	result, ok := f.unitCons[name]
+	if !ok {
+		return params.UnitConstraintsResult{}, errors.NotFoundf("unit %q", name)
+	}
+	return result, nil
+}
+
 func (f *fakeConstraintsClient) SetEnvironmentConstraints(cons constraints.Value) error {
 	if f.err != nil {
 		return f.err
@@ -122,7 +132,10 @@ func (s *ConstraintsCommandsSuite) assertSetBlocked(c *gc.C, args ...string) {
 
 func (s *ConstraintsCommandsSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuHomeSuite.SetUpTest(c)
-	s.fake = &fakeConstraintsClient{servCons: make(map[string]constraints.Value)}
+	s.fake = &fakeConstraintsClient{
+		servCons: make(map[string]constraints.Value),
+		unitCons: make(map[string]params.UnitConstraintsResult),
+	}
 }
 
 func (s *ConstraintsCommandsSuite) TestSetEnviron(c *gc.C) {
@@ -240,3 +253,55 @@ func (s *ConstraintsCommandsSuite) TestGetErrors(c *gc.C) {
 	s.assertGetError(c, 2, `unrecognized args: \["blether"\]`, "goodname", "blether")
 	s.assertGetError(c, 1, `service "missing" not found`, "missing")
 }
+
+func (s *ConstraintsCommandsSuite) assertShow(c *gc.C, stdout string, args ...string) {
+	command := NewShowConstraintsCommand(s.fake)
+	rcode, rstdout, rstderr := runCmdLine(c, envcmd.Wrap(command), args...)
+	c.Assert(rcode, gc.Equals, 0)
+	c.Assert(rstdout, gc.Equals, stdout)
+	c.Assert(rstderr, gc.Equals, "")
+}
+
+func (s *ConstraintsCommandsSuite) addTestingUnit() {
+	s.fake.unitCons["svc/0"] = params.UnitConstraintsResult{
+		Constraints: constraints.MustParse("mem=4G"),
+		Effective:   constraints.MustParse("arch=amd64 mem=8G"),
+		Sources: map[string]string{
+			"arch": "environment",
+			"mem":  "service",
+		},
+	}
+}
+
+func (s *ConstraintsCommandsSuite) TestShowRecorded(c *gc.C) {
+	s.addTestingUnit()
+	s.assertShow(c, "mem=4096M\n", "svc/0")
+}
+
+func (s *ConstraintsCommandsSuite) TestShowEffective(c *gc.C) {
+	s.addTestingUnit()
+	s.assertShow(c, "arch=amd64 (environment)\nmem=8192M (service)\n", "--effective", "svc/0")
+}
+
+func (s *ConstraintsCommandsSuite) TestShowEffectiveFormats(c *gc.C) {
+	s.addTestingUnit()
+	s.assertShow(c, `{"constraints":{"arch":"amd64","mem":8192},"sources":{"arch":"environment","mem":"service"}}`+"\n",
+		"--effective", "--format", "json", "svc/0")
+	s.assertShow(c, "constraints:\n  arch: amd64\n  mem: 8192\nsources:\n  arch: environment\n  mem: service\n",
+		"--effective", "--format", "yaml", "svc/0")
+}
+
+func (s *ConstraintsCommandsSuite) assertShowError(c *gc.C, code int, stderr string, args ...string) {
+	command := NewShowConstraintsCommand(s.fake)
+	rcode, rstdout, rstderr := runCmdLine(c, envcmd.Wrap(command), args...)
+	c.Assert(rcode, gc.Equals, code)
+	c.Assert(rstdout, gc.Equals, "")
+	c.Assert(rstderr, gc.Matches, "error: "+stderr+"\n")
+}
+
+func (s *ConstraintsCommandsSuite) TestShowErrors(c *gc.C) {
+	s.assertShowError(c, 2, `no unit specified`)
+	s.assertShowError(c, 2, `invalid unit name "svc"`, "svc")
+	s.assertShowError(c, 2, `unrecognized args: \["blether"\]`, "svc/0", "blether")
+	s.assertShowError(c, 1, `unit "svc/1" not found`, "svc/1")
+}
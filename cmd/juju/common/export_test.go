@@ -16,3 +16,10 @@ func NewSetConstraintsCommand(api ConstraintsAPI) *SetConstraintsCommand {
 		api: api,
 	}
 }
+
+// NewShowConstraintsCommand returns a ShowConstraintsCommand with the api provided as specified.
+func NewShowConstraintsCommand(api UnitConstraintsAPI) *ShowConstraintsCommand {
+	return &ShowConstraintsCommand{
+		api: api,
+	}
+}
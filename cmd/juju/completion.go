@@ -0,0 +1,549 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+// CompletionCommand prints a script that completes juju commands in a
+// shell, or the names the script completes their arguments from.
+type CompletionCommand struct {
+	cmd.CommandBase
+	shell   string
+	list    string
+	envName string
+
+	// store is overridden in tests.
+	store jujuclient.ClientStore
+}
+
+var completionDoc = `
+Prints a script that completes juju commands, their options, and the
+names of controllers, models, services, units and machines in bash or
+zsh. The script is generated from the commands of this juju client, so
+it should be generated again when juju is upgraded.
+
+The names of services, units and machines are those reported by the
+last "juju status" of the whole environment, so that completing them
+does not need to connect to the environment. Run "juju status" to
+bring them up to date.
+
+Examples:
+    juju completion bash > /etc/bash_completion.d/juju
+    source <(juju completion zsh)
+
+See Also:
+    juju help status
+`
+
+// completionLists holds the kinds of names printed by --list.
+var completionLists = []string{"controllers", "machines", "models", "services", "units"}
+
+func (c *CompletionCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "completion",
+		Args:    "bash|zsh",
+		Purpose: "print a shell completion script for juju",
+		Doc:     completionDoc,
+	}
+}
+
+func (c *CompletionCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.list, "list", "", "Print the cached names of the given kind, one of "+strings.Join(completionLists, ", "))
+	f.StringVar(&c.envName, "e", "", "The environment whose names --list prints")
+	f.StringVar(&c.envName, "environment", "", "")
+}
+
+func (c *CompletionCommand) Init(args []string) error {
+	if c.list != "" {
+		if !set.NewStrings(completionLists...).Contains(c.list) {
+			return errors.Errorf("cannot list %q: expected one of %s", c.list, strings.Join(completionLists, ", "))
+		}
+		return cmd.CheckEmpty(args)
+	}
+	if len(args) == 0 {
+		return errors.New("no shell specified")
+	}
+	c.shell = args[0]
+	if c.shell != "bash" && c.shell != "zsh" {
+		return errors.Errorf("unsupported shell %q: expected bash or zsh", c.shell)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *CompletionCommand) getStore() jujuclient.ClientStore {
+	if c.store != nil {
+		return c.store
+	}
+	return jujuclient.NewFileClientStore()
+}
+
+func (c *CompletionCommand) Run(ctx *cmd.Context) error {
+	if c.list != "" {
+		names, err := c.listNames()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, name := range names {
+			fmt.Fprintln(ctx.Stdout, name)
+		}
+		return nil
+	}
+	entries, err := completionEntries(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return writeCompletionScript(ctx.Stdout, c.shell, entries)
+}
+
+// listNames returns the names of the kind given to --list. The names
+// of controllers and models are read from the client store, and those
+// of the entities in the environment from the names cached by status.
+func (c *CompletionCommand) listNames() ([]string, error) {
+	store := c.getStore()
+	switch c.list {
+	case "controllers":
+		controllers, err := store.AllControllers()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		names := set.NewStrings()
+		for name := range controllers {
+			names.Add(name)
+		}
+		return names.SortedValues(), nil
+	case "models":
+		models, err := getClientStoreModels(store)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return models.SortedValues(), nil
+	}
+	envName := c.envName
+	if envName == "" {
+		var err error
+		if envName, err = envcmd.GetDefaultEnvironment(); err != nil {
+			return nil, errors.Trace(err)
+		} else if envName == "" {
+			return nil, nil
+		}
+	}
+	names, err := store.EntityNames(envName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch c.list {
+	case "machines":
+		return names.Machines, nil
+	case "services":
+		return names.Services, nil
+	}
+	return names.Units, nil
+}
+
+// completionEntry describes the words that can follow a command.
+type completionEntry struct {
+	// Path holds the name of the command, preceded by the names of
+	// the supercommands it belongs to, separated by spaces. The
+	// juju command itself has the empty path.
+	Path string
+
+	// Subcommands holds the names of the subcommands of a
+	// supercommand.
+	Subcommands []string
+
+	// Flags holds the flags accepted by the command.
+	Flags []string
+
+	// Arguments holds the kinds of names, as printed by --list, that
+	// the arguments of the command are completed from.
+	Arguments []string
+}
+
+// completionRegistry records the commands registered by
+// registerCommands, so that the completion script is generated from
+// the same commands as juju itself.
+type completionRegistry struct {
+	commands []cmd.Command
+	aliases  map[string]string
+}
+
+func (r *completionRegistry) Register(c cmd.Command) {
+	r.commands = append(r.commands, c)
+}
+
+func (r *completionRegistry) RegisterDeprecated(c cmd.Command, check cmd.DeprecationCheck) {
+	if check == nil || !check.Obsolete() {
+		r.commands = append(r.commands, c)
+	}
+}
+
+func (r *completionRegistry) RegisterSuperAlias(name, super, forName string, check cmd.DeprecationCheck) {
+	if check == nil || !check.Obsolete() {
+		if r.aliases == nil {
+			r.aliases = make(map[string]string)
+		}
+		r.aliases[name] = super + " " + forName
+	}
+}
+
+// completionEntries returns the completion entries of the juju command
+// and all its subcommands, ordered by path.
+func completionEntries(ctx *cmd.Context) ([]completionEntry, error) {
+	var registry completionRegistry
+	registerCommands(&registry, ctx)
+
+	byPath := make(map[string]completionEntry)
+	var names []string
+	for _, c := range registry.commands {
+		info := c.Info()
+		for _, name := range append([]string{info.Name}, info.Aliases...) {
+			entries, err := commandEntries(ctx, c, name)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, entry := range entries {
+				byPath[entry.Path] = entry
+			}
+			names = append(names, name)
+		}
+	}
+	for name, target := range registry.aliases {
+		entry, ok := byPath[target]
+		if !ok {
+			continue
+		}
+		entry.Path = name
+		byPath[name] = entry
+		names = append(names, name)
+	}
+	names = append(names, "help")
+	sort.Strings(names)
+	byPath["help"] = completionEntry{Path: "help", Subcommands: names}
+
+	// The options of the juju command may be given to any command.
+	global := commandFlags(jujucmd.NewSuperCommand(cmd.SuperCommandParams{Name: "juju"}))
+	byPath[""] = completionEntry{Subcommands: names, Flags: global}
+
+	var entries []completionEntry
+	for _, entry := range byPath {
+		if entry.Path != "" {
+			entry.Flags = set.NewStrings(append(entry.Flags, global...)...).SortedValues()
+		}
+		entries = append(entries, entry)
+	}
+	sort.Sort(entriesByPath(entries))
+	return entries, nil
+}
+
+type entriesByPath []completionEntry
+
+func (e entriesByPath) Len() int           { return len(e) }
+func (e entriesByPath) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e entriesByPath) Less(i, j int) bool { return e[i].Path < e[j].Path }
+
+// commandEntries returns the completion entries of the given command,
+// registered with the given name, and of its subcommands if it is a
+// supercommand.
+func commandEntries(ctx *cmd.Context, c cmd.Command, name string) ([]completionEntry, error) {
+	super, ok := c.(*cmd.SuperCommand)
+	if !ok {
+		return []completionEntry{{
+			Path:      name,
+			Flags:     commandFlags(c),
+			Arguments: argumentKinds(c.Info().Args),
+		}}, nil
+	}
+	// A supercommand does not expose its subcommands, so they are
+	// read from its help, in the same form that "juju help commands"
+	// prints them.
+	help, err := runHelp(ctx, super, "commands")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var subcommands []string
+	for _, line := range strings.Split(help, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			subcommands = append(subcommands, fields[0])
+		}
+	}
+	entries := []completionEntry{{
+		Path:        name,
+		Subcommands: subcommands,
+		Flags:       commandFlags(c),
+	}}
+	for _, subcommand := range subcommands {
+		path := name + " " + subcommand
+		if subcommand == "help" {
+			entries = append(entries, completionEntry{Path: path, Subcommands: subcommands})
+			continue
+		}
+		help, err := runHelp(ctx, super, subcommand)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		entries = append(entries, helpEntry(path, help))
+	}
+	return entries, nil
+}
+
+// runHelp returns the output of the help subcommand of the given
+// supercommand, run with the given topic.
+func runHelp(ctx *cmd.Context, super *cmd.SuperCommand, topic string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	helpCtx := &cmd.Context{
+		Dir:    ctx.Dir,
+		Stdin:  &bytes.Buffer{},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	if code := cmd.Main(super, helpCtx, []string{"help", topic}); code != 0 {
+		return "", errors.Errorf("cannot get help for %s %s: %s", super.Info().Name, topic, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// helpEntry returns the completion entry of the command with the given
+// path, read from its help: the arguments from the usage line and the
+// flags from the options section.
+func helpEntry(path, help string) completionEntry {
+	entry := completionEntry{Path: path}
+	lines := strings.Split(help, "\n")
+	words := path[strings.LastIndex(path, " ")+1:]
+	usage := strings.Fields(strings.TrimPrefix(lines[0], "usage:"))
+	for i, field := range usage {
+		if field == words {
+			usage = usage[i+1:]
+			break
+		}
+	}
+	if len(usage) > 0 && usage[0] == "[options]" {
+		usage = usage[1:]
+	}
+	entry.Arguments = argumentKinds(strings.Join(usage, " "))
+
+	inOptions := false
+	for _, line := range lines[1:] {
+		if line == "options:" {
+			inOptions = true
+			continue
+		}
+		if !inOptions {
+			continue
+		}
+		if line == "" {
+			break
+		}
+		for _, field := range strings.Fields(line) {
+			field = strings.TrimSuffix(field, ",")
+			if len(field) < 2 || field[0] != '-' {
+				break
+			}
+			entry.Flags = append(entry.Flags, field)
+		}
+	}
+	return entry
+}
+
+// commandFlags returns the flags accepted by the given command.
+func commandFlags(c cmd.Command) []string {
+	f := gnuflag.NewFlagSet(c.Info().Name, gnuflag.ContinueOnError)
+	c.SetFlags(f)
+	var flags []string
+	f.VisitAll(func(flag *gnuflag.Flag) {
+		if len(flag.Name) == 1 {
+			flags = append(flags, "-"+flag.Name)
+		} else {
+			flags = append(flags, "--"+flag.Name)
+		}
+	})
+	return flags
+}
+
+// argumentKinds returns the kinds of names that the first argument of
+// a command, as described by its Info.Args, is completed from.
+func argumentKinds(args string) []string {
+	first := strings.ToLower(args)
+	if i := strings.IndexAny(first, ">]"); i >= 0 {
+		first = first[:i]
+	}
+	switch {
+	case strings.Contains(first, "pattern"):
+		return []string{"services", "units", "machines"}
+	case strings.Contains(first, "target"):
+		return []string{"units", "machines"}
+	case strings.HasPrefix(first, "<file1"):
+		return []string{"files", "units", "machines"}
+	case strings.Contains(first, "file"), strings.Contains(first, "path"):
+		return []string{"files"}
+	case strings.Contains(first, "unit"):
+		return []string{"units"}
+	case strings.Contains(first, "service"):
+		return []string{"services"}
+	case strings.Contains(first, "machine"):
+		return []string{"machines"}
+	case strings.Contains(first, "environment name"):
+		return []string{"models"}
+	case strings.Contains(first, "controller"):
+		return []string{"controllers"}
+	}
+	return nil
+}
+
+// writeCompletionScript writes the completion script for the given
+// shell. The zsh script runs the bash script through zsh's emulation
+// of bash completion.
+func writeCompletionScript(w io.Writer, shell string, entries []completionEntry) error {
+	return errors.Trace(completionTemplate.Execute(w, struct {
+		Shell   string
+		Entries []completionEntry
+	}{shell, entries}))
+}
+
+var completionTemplate = template.Must(template.New("completion").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`
+# {{.Shell}} completion for juju, generated by "juju completion {{.Shell}}".
+{{if eq .Shell "zsh"}}
+(( $+functions[compdef] )) || { autoload -U +X compinit && compinit }
+autoload -U +X bashcompinit && bashcompinit
+{{end}}
+_juju_is_command() {
+    case "$1" in
+{{range .Entries}}{{if .Path}}    "{{.Path}}") return 0 ;;
+{{end}}{{end}}    esac
+    return 1
+}
+
+_juju_subcommands() {
+    case "$1" in
+{{range .Entries}}{{if .Subcommands}}    "{{.Path}}") echo "{{join .Subcommands " "}}" ;;
+{{end}}{{end}}    esac
+}
+
+_juju_flags() {
+    case "$1" in
+{{range .Entries}}{{if .Flags}}    "{{.Path}}") echo "{{join .Flags " "}}" ;;
+{{end}}{{end}}    esac
+}
+
+_juju_arguments() {
+    case "$1" in
+{{range .Entries}}{{if .Arguments}}    "{{.Path}}") echo "{{join .Arguments " "}}" ;;
+{{end}}{{end}}    esac
+}
+
+_juju() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local cmdpath="" env="" word kind candidates i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        word="${COMP_WORDS[i]}"
+        case "$word" in
+        -e|--environment)
+            env="${COMP_WORDS[i+1]}"
+            i=$((i + 1))
+            ;;
+        -*)
+            ;;
+        *)
+            if _juju_is_command "${cmdpath:+$cmdpath }$word"; then
+                cmdpath="${cmdpath:+$cmdpath }$word"
+            fi
+            ;;
+        esac
+    done
+    COMPREPLY=()
+    case "$prev" in
+    -e|--environment)
+        COMPREPLY=( $(compgen -W "$(juju completion --list models 2>/dev/null)" -- "$cur") )
+        return 0
+        ;;
+    esac
+    case "$cur" in
+    -*)
+        COMPREPLY=( $(compgen -W "$(_juju_flags "$cmdpath")" -- "$cur") )
+        return 0
+        ;;
+    esac
+    candidates="$(_juju_subcommands "$cmdpath")"
+    for kind in $(_juju_arguments "$cmdpath"); do
+        case "$kind" in
+        files)
+            COMPREPLY=( $(compgen -f -- "$cur") )
+            ;;
+        *)
+            candidates="$candidates $(juju completion --list "$kind" ${env:+-e "$env"} 2>/dev/null)"
+            ;;
+        esac
+    done
+    COMPREPLY+=( $(compgen -W "$candidates" -- "$cur") )
+    return 0
+}
+
+complete -F _juju juju
+`[1:]))
+
+// getEntityNameStore returns the store that the names of the entities
+// in environments are cached in; it is a variable so that tests can
+// replace it.
+var getEntityNameStore = func() jujuclient.EntityNameStore {
+	return jujuclient.NewFileClientStore()
+}
+
+// cacheEntityNames records the names of the entities in the complete
+// status of the named environment, for shell completion. Failing to
+// record them must not fail the status command, so errors are only
+// logged.
+func cacheEntityNames(envName string, status *api.Status) {
+	if err := getEntityNameStore().SetEntityNames(envName, statusEntityNames(status)); err != nil {
+		logger.Warningf("cannot cache entity names: %v", err)
+	}
+}
+
+// statusEntityNames returns the names of the entities in the given
+// status, including containers and subordinate units.
+func statusEntityNames(status *api.Status) jujuclient.EntityNames {
+	var names jujuclient.EntityNames
+	var addMachines func(map[string]api.MachineStatus)
+	addMachines = func(machines map[string]api.MachineStatus) {
+		for id, machine := range machines {
+			names.Machines = append(names.Machines, id)
+			addMachines(machine.Containers)
+		}
+	}
+	var addUnits func(map[string]api.UnitStatus)
+	addUnits = func(units map[string]api.UnitStatus) {
+		for name, unit := range units {
+			names.Units = append(names.Units, name)
+			addUnits(unit.Subordinates)
+		}
+	}
+	addMachines(status.Machines)
+	for name, service := range status.Services {
+		names.Services = append(names.Services, name)
+		addUnits(service.Units)
+	}
+	sort.Strings(names.Machines)
+	sort.Strings(names.Services)
+	sort.Strings(names.Units)
+	return names
+}
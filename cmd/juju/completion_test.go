@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type CompletionSuite struct {
+	testing.FakeJujuHomeSuite
+	store jujuclient.ClientStore
+}
+
+var _ = gc.Suite(&CompletionSuite{})
+
+func (s *CompletionSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	for _, name := range []string{"prod", "dev"} {
+		err := s.store.UpdateController(name, jujuclient.ControllerDetails{
+			ControllerUUID: name + "-uuid",
+			APIEndpoints:   []string{name + ".example.com:17070"},
+			CACert:         testing.CACert,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.store.UpdateAccount(name, jujuclient.AccountDetails{User: "admin", Password: "secret"})
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.store.UpdateModel(name, name+"-model", jujuclient.ModelDetails{ModelUUID: name + "-model-uuid"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := s.store.SetEntityNames("prod-model", jujuclient.EntityNames{
+		Machines: []string{"0", "0/lxc/0"},
+		Services: []string{"mysql", "wordpress"},
+		Units:    []string{"mysql/0", "wordpress/0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CompletionSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, &CompletionCommand{store: s.store}, args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *CompletionSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no shell specified",
+	}, {
+		args: []string{"tcsh"},
+		err:  `unsupported shell "tcsh": expected bash or zsh`,
+	}, {
+		args: []string{"bash", "zsh"},
+		err:  `unrecognized args: \["zsh"\]`,
+	}, {
+		args: []string{"--list", "relations"},
+		err:  `cannot list "relations": expected one of controllers, machines, models, services, units`,
+	}, {
+		args: []string{"--list", "units", "bash"},
+		err:  `unrecognized args: \["bash"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(&CompletionCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *CompletionSuite) TestBash(c *gc.C) {
+	out, err := s.run(c, "bash")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.HasPrefix, `# bash completion for juju, generated by "juju completion bash".`)
+	c.Assert(out, gc.Not(jc.Contains), "bashcompinit")
+	c.Assert(out, jc.Contains, "\ncomplete -F _juju juju\n")
+
+	// Commands, their subcommands and aliases are all completed.
+	for _, path := range []string{"status", "machine", "machine add", "add-machine", "env", "completion"} {
+		c.Check(out, jc.Contains, "\n    \""+path+"\") return 0 ;;\n")
+	}
+	subcommands := set.NewStrings(strings.Fields(caseLine(c, out, "_juju_subcommands", "machine"))...)
+	c.Check(subcommands.Contains("add"), jc.IsTrue)
+	c.Check(subcommands.Contains("remove"), jc.IsTrue)
+
+	// Commands accept their own flags and the global ones.
+	flags := strings.Fields(caseLine(c, out, "_juju_flags", "status"))
+	c.Check(flags, jc.DeepEquals, set.NewStrings(flags...).SortedValues())
+	for _, flag := range []string{"--format", "-e", "--environment", "--debug"} {
+		c.Check(set.NewStrings(flags...).Contains(flag), jc.IsTrue, gc.Commentf("flag %s", flag))
+	}
+	flags = strings.Fields(caseLine(c, out, "_juju_flags", "machine add"))
+	c.Check(set.NewStrings(flags...).Contains("--constraints"), jc.IsTrue)
+
+	// Arguments are completed from names of the right kind.
+	c.Check(caseLine(c, out, "_juju_arguments", "status"), gc.Equals, "services units machines")
+	c.Check(caseLine(c, out, "_juju_arguments", "add-unit"), gc.Equals, "services")
+	c.Check(caseLine(c, out, "_juju_arguments", "switch"), gc.Equals, "models")
+	c.Check(caseLine(c, out, "_juju_arguments", "machine remove"), gc.Equals, "machines")
+}
+
+func (s *CompletionSuite) TestZsh(c *gc.C) {
+	out, err := s.run(c, "zsh")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.HasPrefix, `
+# zsh completion for juju, generated by "juju completion zsh".
+
+(( $+functions[compdef] )) || { autoload -U +X compinit && compinit }
+autoload -U +X bashcompinit && bashcompinit
+`[1:])
+	c.Assert(out, jc.Contains, "\ncomplete -F _juju juju\n")
+}
+
+func (s *CompletionSuite) TestList(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		expect string
+	}{{
+		args:   []string{"--list", "controllers"},
+		expect: "dev\nprod\n",
+	}, {
+		args:   []string{"--list", "models"},
+		expect: "dev:dev-model\nprod:prod-model\n",
+	}, {
+		args:   []string{"--list", "machines", "-e", "prod-model"},
+		expect: "0\n0/lxc/0\n",
+	}, {
+		args:   []string{"--list", "services", "-e", "prod-model"},
+		expect: "mysql\nwordpress\n",
+	}, {
+		args:   []string{"--list", "units", "--environment", "prod-model"},
+		expect: "mysql/0\nwordpress/0\n",
+	}, {
+		args:   []string{"--list", "units", "-e", "dev-model"},
+		expect: "",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		out, err := s.run(c, test.args...)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(out, gc.Equals, test.expect)
+	}
+}
+
+func (s *CompletionSuite) TestListDefaultEnvironment(c *gc.C) {
+	s.PatchEnvironment(osenv.JujuEnvEnvKey, "prod-model")
+	out, err := s.run(c, "--list", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "mysql/0\nwordpress/0\n")
+}
+
+func (s *CompletionSuite) TestArgumentKinds(c *gc.C) {
+	for i, test := range []struct {
+		args  string
+		kinds []string
+	}{
+		{"[pattern ...]", []string{"services", "units", "machines"}},
+		{"<target> [<ssh args>...]", []string{"units", "machines"}},
+		{"<file1> ... <file2> [scp-option...]", []string{"files", "units", "machines"}},
+		{"<bundle file>", []string{"files"}},
+		{"<charm path>", []string{"files"}},
+		{"<unit> [...]", []string{"units"}},
+		{"[<service>]", []string{"services"}},
+		{"<machine> ...", []string{"machines"}},
+		{"[environment name]", []string{"models"}},
+		{"<controller name>", []string{"controllers"}},
+		{"<environment key> ...", nil},
+		{"", nil},
+	} {
+		c.Logf("test %d: %q", i, test.args)
+		c.Check(argumentKinds(test.args), jc.DeepEquals, test.kinds)
+	}
+}
+
+func (s *CompletionSuite) TestHelpEntry(c *gc.C) {
+	entry := helpEntry("machine remove", `
+usage: juju machine remove [options] <machine> ...
+purpose: remove machines from the environment
+
+options:
+-e, --environment (= "")
+    juju environment to operate in
+--force  (= false)
+    completely remove machine and all dependencies
+--grace-period  (= 0)
+    remove the machines after this period, unless cancelled
+
+Machines that are responsible for the environment cannot be removed.
+--force is also mentioned in the doc.
+`[1:])
+	c.Assert(entry, jc.DeepEquals, completionEntry{
+		Path:      "machine remove",
+		Flags:     []string{"-e", "--environment", "--force", "--grace-period"},
+		Arguments: []string{"machines"},
+	})
+}
+
+func (s *CompletionSuite) TestStatusEntityNames(c *gc.C) {
+	status := &api.Status{
+		Machines: map[string]api.MachineStatus{
+			"1": {},
+			"0": {Containers: map[string]api.MachineStatus{"0/lxc/0": {}}},
+		},
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {Units: map[string]api.UnitStatus{
+				"wordpress/0": {Subordinates: map[string]api.UnitStatus{"logging/0": {}}},
+			}},
+			"logging": {},
+		},
+	}
+	c.Assert(statusEntityNames(status), jc.DeepEquals, jujuclient.EntityNames{
+		Machines: []string{"0", "0/lxc/0", "1"},
+		Services: []string{"logging", "wordpress"},
+		Units:    []string{"logging/0", "wordpress/0"},
+	})
+}
+
+func (s *CompletionSuite) TestCacheEntityNames(c *gc.C) {
+	s.PatchValue(&getEntityNameStore, func() jujuclient.EntityNameStore {
+		return s.store
+	})
+	cacheEntityNames("dev-model", &api.Status{
+		Services: map[string]api.ServiceStatus{"mysql": {}},
+	})
+	names, err := s.store.EntityNames("dev-model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*names, jc.DeepEquals, jujuclient.EntityNames{Services: []string{"mysql"}})
+}
+
+// caseLine returns the words echoed for the given command path by the
+// named function of a generated completion script.
+func caseLine(c *gc.C, script, function, path string) string {
+	body := script[strings.Index(script, "\n"+function+"() {"):]
+	body = body[:strings.Index(body, "\n}\n")]
+	prefix := "    \"" + path + "\") echo \""
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSuffix(line[len(prefix):], "\" ;;")
+		}
+	}
+	c.Fatalf("%s has no case for %q", function, path)
+	return ""
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
+)
+
+// openControllerAPI connects to the named controller as the given
+// account. It logs in with the account's session token if it has one,
+// falling back to the account's password if the token has expired.
+// The token stored for the account is kept up to date as the login
+// session is started and refreshed.
+func openControllerAPI(
+	store jujuclient.AccountUpdater,
+	controllerName string,
+	controller jujuclient.ControllerDetails,
+	account jujuclient.AccountDetails,
+) (*api.State, error) {
+	info := &api.Info{
+		Addrs:  controller.APIEndpoints,
+		CACert: controller.CACert,
+		Tag:    names.NewUserTag(account.User),
+	}
+	saveSession := func(session params.SessionInfo) {
+		account.SessionToken = session.Token
+		if err := store.UpdateAccount(controllerName, account); err != nil {
+			logger.Warningf("cannot save login session for controller %q: %v", controllerName, err)
+		}
+	}
+	opts := api.DefaultDialOpts()
+	opts.SessionRefreshed = saveSession
+	if account.SessionToken != "" {
+		info.SessionToken = account.SessionToken
+		st, err := api.Open(info, opts)
+		if err == nil {
+			return st, nil
+		}
+		if account.Password == "" || !params.IsCodeUnauthorized(err) {
+			return nil, errors.Trace(err)
+		}
+		logger.Debugf("login session for controller %q has expired", controllerName)
+		info.SessionToken = ""
+	}
+	info.Password = account.Password
+	info.TOTPCode = os.Getenv(osenv.JujuTOTPCodeEnvKey)
+	opts.RequestSession = true
+	st, err := api.Open(info, opts)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if session, ok := st.Session(); ok {
+		saveSession(session)
+	}
+	return st, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/controllerusage"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+// controllerUsageAPI defines the methods on the ControllerUsage API
+// that the controller-usage command uses.
+type controllerUsageAPI interface {
+	EnvironmentUsage(hours int) ([]params.EnvironmentUsage, error)
+	Close() error
+}
+
+var newControllerUsageAPI = func(c *ControllerUsageCommand) (controllerUsageAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return controllerusage.NewClient(root), nil
+}
+
+// ControllerUsageCommand reports the API usage of each environment
+// hosted by the state server.
+type ControllerUsageCommand struct {
+	envcmd.EnvCommandBase
+	out   output.Output
+	hours int
+}
+
+const controllerUsageDoc = `
+Report the number of API calls made to each environment hosted by the
+state server, and the size of the messages they exchanged, busiest
+environment first. Calls made by both clients and agents are counted.
+
+Usage is reported over the past day, or over the number of hours given
+with --hours; usage older than a week is not kept. The API servers
+record usage once a minute, so the latest calls may not be reported
+yet. Only the administrator of the state server environment may report
+usage.
+
+Examples:
+    juju controller-usage
+    juju controller-usage --hours 1 --format yaml
+`
+
+func (c *ControllerUsageCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "controller-usage",
+		Purpose: "report the API usage of each environment",
+		Doc:     controllerUsageDoc,
+	}
+}
+
+func (c *ControllerUsageCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.IntVar(&c.hours, "hours", 24, "Number of hours to report usage over")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatEnvironmentUsageTabular,
+	})
+}
+
+func (c *ControllerUsageCommand) Init(args []string) error {
+	if c.hours < 1 {
+		return errors.Errorf("--hours must be at least 1")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// environmentUsage describes the usage of an environment shown by the
+// controller-usage command.
+type environmentUsage struct {
+	Name     string `json:"name" yaml:"name"`
+	UUID     string `json:"uuid" yaml:"uuid"`
+	Calls    int64  `json:"calls" yaml:"calls"`
+	BytesIn  int64  `json:"bytes-in" yaml:"bytes-in"`
+	BytesOut int64  `json:"bytes-out" yaml:"bytes-out"`
+}
+
+// ID implements output.Identifier.
+func (u environmentUsage) ID() string {
+	return u.UUID
+}
+
+func (c *ControllerUsageCommand) Run(ctx *cmd.Context) error {
+	client, err := newControllerUsageAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	found, err := client.EnvironmentUsage(c.hours)
+	if err != nil {
+		return errors.Annotate(err, "cannot get usage")
+	}
+	usage := make([]environmentUsage, len(found))
+	for i, u := range found {
+		tag, err := names.ParseEnvironTag(u.EnvironTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		usage[i] = environmentUsage{
+			Name:     u.Name,
+			UUID:     tag.Id(),
+			Calls:    u.Calls,
+			BytesIn:  u.BytesIn,
+			BytesOut: u.BytesOut,
+		}
+	}
+	return c.out.Write(ctx, usage)
+}
+
+// formatEnvironmentUsageTabular returns a table of the usage reported
+// by controller-usage.
+func formatEnvironmentUsageTabular(value interface{}) ([]byte, error) {
+	usage, ok := value.([]environmentUsage)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", usage, value)
+	}
+	if len(usage) == 0 {
+		return []byte("no usage recorded"), nil
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "ENVIRONMENT\tUUID\tCALLS\tIN\tOUT")
+	for _, u := range usage {
+		name := u.Name
+		if name == "" {
+			name = "(removed)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", name, u.UUID, u.Calls,
+			humanize.IBytes(uint64(u.BytesIn)), humanize.IBytes(uint64(u.BytesOut)))
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
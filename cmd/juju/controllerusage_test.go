@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ControllerUsageSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeControllerUsageAPI
+}
+
+var _ = gc.Suite(&ControllerUsageSuite{})
+
+type fakeControllerUsageAPI struct {
+	hours  int
+	usage  []params.EnvironmentUsage
+	err    error
+	closed bool
+}
+
+func (f *fakeControllerUsageAPI) EnvironmentUsage(hours int) ([]params.EnvironmentUsage, error) {
+	f.hours = hours
+	return f.usage, f.err
+}
+
+func (f *fakeControllerUsageAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *ControllerUsageSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeControllerUsageAPI{
+		usage: []params.EnvironmentUsage{{
+			EnvironTag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+			Name:       "busy",
+			Calls:      100,
+			BytesIn:    1000,
+			BytesOut:   10000,
+		}, {
+			EnvironTag: "environment-0badf00d-0bad-400d-8000-4b1d0d06f00d",
+			Calls:      1,
+			BytesIn:    10,
+			BytesOut:   100,
+		}},
+	}
+	s.PatchValue(&newControllerUsageAPI, func(_ *ControllerUsageCommand) (controllerUsageAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ControllerUsageSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ControllerUsageCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ControllerUsageSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ControllerUsageCommand{}, []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+	err = testing.InitCommand(&ControllerUsageCommand{}, []string{"--hours", "0"})
+	c.Assert(err, gc.ErrorMatches, `--hours must be at least 1`)
+}
+
+func (s *ControllerUsageSuite) TestTabular(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ENVIRONMENT UUID                                 CALLS IN     OUT\n"+
+		"busy        deadbeef-0bad-400d-8000-4b1d0d06f00d 100   1000 B 9.8 KiB\n"+
+		"(removed)   0badf00d-0bad-400d-8000-4b1d0d06f00d 1     10 B   100 B\n",
+	)
+	c.Assert(s.api.hours, gc.Equals, 24)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ControllerUsageSuite) TestNoUsage(c *gc.C) {
+	s.api.usage = nil
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "no usage recorded\n")
+}
+
+func (s *ControllerUsageSuite) TestYAML(c *gc.C) {
+	s.api.usage = s.api.usage[:1]
+	out, err := s.run(c, "--hours", "1", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- name: busy\n"+
+		"  uuid: deadbeef-0bad-400d-8000-4b1d0d06f00d\n"+
+		"  calls: 100\n"+
+		"  bytes-in: 1000\n"+
+		"  bytes-out: 10000\n",
+	)
+	c.Assert(s.api.hours, gc.Equals, 1)
+}
+
+func (s *ControllerUsageSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("permission denied")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "cannot get usage: permission denied")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5/hooks"
+	"launchpad.net/gnuflag"
 
 	unitdebug "github.com/juju/juju/worker/uniter/runner/debug"
 )
@@ -34,6 +35,11 @@ func (c *DebugHooksCommand) Info() *cmd.Info {
 	}
 }
 
+// SetFlags omits the flags that only make sense for "juju ssh".
+func (c *DebugHooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommon.SetFlags(f)
+}
+
 func (c *DebugHooksCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("no unit name specified")
@@ -6,6 +6,8 @@ package main
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/loggo"
@@ -31,6 +33,29 @@ const defaultLineCount = 10
 const debuglogDoc = `
 Stream the consolidated debug log file. This file contains the log messages
 from all nodes in the environment.
+
+With --format json, each log message is shown as a JSON object with the
+fields time, level, module, location, message, entity and env-uuid. The
+env-uuid field is only known for agents whose logging-format environment
+setting is "json".
+
+The filters are applied by the API server, so only matching messages are
+transmitted. The --include-message and --exclude-message options take
+regular expressions that are matched against the text of each message, e.g.
+    juju debug-log --include-message 'hook failed' --exclude-message '^cannot'
+
+The server sends at most a bounded number of lines from before the point
+at which the log is followed, even with --replay.
+
+The --field option shows only the log messages whose named field matches
+the given value, which may contain '*' wildcards, e.g.
+    juju debug-log --format json --field env-uuid=<uuid> --field module=juju.worker.*
+
+Only the log messages of the current environment are shown, where the
+messages record their environment. The owner of the state server
+environment may use --all-models to show those of all environments,
+which is best combined with --format json to tell them apart, e.g.
+    juju debug-log --all-models --format json --level ERROR
 `
 
 func (c *DebugLogCommand) Info() *cmd.Info {
@@ -48,6 +73,8 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeEntity), "exclude", "do not show log messages for these entities")
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModule), "include-module", "only show log messages for these logging modules")
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModule), "exclude-module", "do not show log messages for these logging modules")
+	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeMessage), "include-message", "only show log messages matching these regular expressions")
+	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeMessage), "exclude-message", "do not show log messages matching these regular expressions")
 
 	f.StringVar(&c.level, "l", "", "log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
@@ -56,6 +83,9 @@ func (c *DebugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "show at most this many lines")
 	f.BoolVar(&c.params.Replay, "replay", false, "start filtering from the start")
+	f.StringVar(&c.params.Format, "format", "", "specify output format (json|text)")
+	f.Var(cmd.NewAppendStringsValue(&c.params.Fields), "field", "only show log messages whose named field matches, as name=value")
+	f.BoolVar(&c.params.AllModels, "all-models", false, "show log messages of all environments")
 }
 
 func (c *DebugLogCommand) Init(args []string) error {
@@ -67,6 +97,23 @@ func (c *DebugLogCommand) Init(args []string) error {
 		}
 		c.params.Level = level
 	}
+	switch c.params.Format {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("format value %q is not one of %q, %q", c.params.Format, "text", "json")
+	}
+	for _, exprs := range [][]string{c.params.IncludeMessage, c.params.ExcludeMessage} {
+		for _, expr := range exprs {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid message pattern %q: %v", expr, err)
+			}
+		}
+	}
+	for _, field := range c.params.Fields {
+		if !strings.Contains(field, "=") {
+			return fmt.Errorf("field value %q is not of the form name=value", field)
+		}
+	}
 	return cmd.CheckEmpty(args)
 }
 
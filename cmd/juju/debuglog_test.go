@@ -85,6 +85,35 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--format", "json", "--field", "env-uuid=abc", "--field", "module=juju.*"},
+			expected: api.DebugLogParams{
+				Backlog: 10,
+				Format:  "json",
+				Fields:  []string{"env-uuid=abc", "module=juju.*"},
+			},
+		}, {
+			args: []string{"--include-message", "hook failed", "--exclude-message", "^cannot", "--exclude-message", "x+"},
+			expected: api.DebugLogParams{
+				IncludeMessage: []string{"hook failed"},
+				ExcludeMessage: []string{"^cannot", "x+"},
+				Backlog:        10,
+			},
+		}, {
+			args: []string{"--all-models"},
+			expected: api.DebugLogParams{
+				Backlog:   10,
+				AllModels: true,
+			},
+		}, {
+			args:     []string{"--include-message", "("},
+			errMatch: `invalid message pattern "\(": .*`,
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format value "yaml" is not one of "text", "json"`,
+		}, {
+			args:     []string{"--field", "entity"},
+			errMatch: `field value "entity" is not of the form name=value`,
 		},
 	} {
 		c.Logf("test %v", i)
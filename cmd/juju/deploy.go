@@ -29,6 +29,8 @@ type DeployCommand struct {
 	service.UnitCommandBase
 	CharmName    string
 	ServiceName  string
+	BundlePath   string
+	MachineMap   string
 	Config       cmd.FileVar
 	Constraints  constraints.Value
 	Networks     string
@@ -41,6 +43,11 @@ type DeployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// DryRun causes the deployment plan to be written out
+	// instead of being carried out.
+	DryRun bool
+	out    cmd.Output
 }
 
 const deployDoc = `
@@ -94,10 +101,15 @@ Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
    juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql --to lxc:kvm:25
+   (deploy to a new lxc container inside a new kvm container on host machine 25)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
 
+   juju deploy mysql -n 3 --dry-run --format json
+   (show, as JSON, what deploying 3 units of mysql would do)
+
    juju deploy mysql --networks=storage,mynet --constraints networks=^logging,db
    (deploy mysql on machines with "storage", "mynet" and "db" networks,
     but not on machines with "logging" network, also configure "storage" and
@@ -111,6 +123,30 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+With --dry-run, the charm is resolved and the deployment is planned,
+but nothing is changed in the environment: the charm is not added and
+neither the service nor any machines are created. Instead the plan is
+written out, in YAML or JSON according to --format. It holds the charm
+URL, service name, units, config settings, constraints, networks and
+storage, the machines and containers that would be added, and the
+endpoints of the charm along with the spaces they would be bound to;
+an empty binding means that the endpoint is not bound to any particular
+space. Machines with clean, unused instances may be used in place of
+new ones, depending on the environment's assignment policy.
+
+A bundle can be deployed by giving the path to its YAML file in place of
+<charm name>. The bundle's machines are added, its services deployed to
+them as its placement directives describe, and its relations added.
+Machines that already exist in the environment can be used in place of
+the bundle's machines with --map-machines, which takes a comma-separated
+list of mappings; "existing" uses any existing machine with the same id
+as a bundle machine, and <bundle machine>=<machine> uses the given
+existing machine for a bundle machine. For example:
+
+   juju deploy bundle.yaml --map-machines=existing,1=4
+   (use existing machines 0, 2, ... for the bundle machines with the
+    same ids, and existing machine 4 for bundle machine 1)
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -120,7 +156,7 @@ See Also:
 func (c *DeployCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "deploy",
-		Args:    "<charm name> [<service name>]",
+		Args:    "<charm name> [<service name>] | <bundle path>",
 		Purpose: "deploy a new service",
 		Doc:     deployDoc,
 	}
@@ -136,9 +172,21 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	f.Var(storageFlag{&c.Storage}, "storage", "charm storage constraints")
+	f.BoolVar(&c.DryRun, "dry-run", false, "show the deployment plan without deploying")
+	f.StringVar(&c.MachineMap, "map-machines", "", "use existing machines for the machines of a bundle")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
 }
 
 func (c *DeployCommand) Init(args []string) error {
+	if len(args) > 0 && strings.HasSuffix(args[0], ".yaml") {
+		return c.initBundle(args)
+	}
+	if c.MachineMap != "" {
+		return errors.New("--map-machines can only be used when deploying a bundle")
+	}
 	switch len(args) {
 	case 2:
 		if !names.IsValidService(args[1]) {
@@ -159,6 +207,21 @@ func (c *DeployCommand) Init(args []string) error {
 	return c.UnitCommandBase.Init(args)
 }
 
+func (c *DeployCommand) initBundle(args []string) error {
+	c.BundlePath = args[0]
+	if err := cmd.CheckEmpty(args[1:]); err != nil {
+		return errors.New("a service name cannot be given when deploying a bundle")
+	}
+	if c.NumUnits != 1 || c.ToMachineSpec != "" || c.Config.Path != "" ||
+		!constraints.IsEmpty(&c.Constraints) || c.Networks != "" || len(c.Storage) > 0 || c.DryRun {
+		return errors.New("-n, --to, --config, --constraints, --networks, --storage and --dry-run cannot be used when deploying a bundle")
+	}
+	if _, err := parseMachineMap(c.MachineMap); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
 func (c *DeployCommand) newServiceAPIClient() (*apiservice.Client, error) {
 	root, err := c.NewAPIRoot()
 	if err != nil {
@@ -188,18 +251,13 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer csClient.jar.Save()
-	curl, repo, err := resolveCharmURL(c.CharmName, csClient.params, ctx.AbsPath(c.RepoPath), conf)
-	if err != nil {
-		return errors.Trace(err)
+	csClient.useEnvironmentCharmStore(conf)
+	if c.BundlePath != "" {
+		return c.deployBundle(ctx, client, csClient, conf)
 	}
-
-	curl, err = addCharmViaAPI(client, ctx, curl, repo, csClient)
+	curl, repo, err := resolveCharmURL(c.CharmName, csClient.params, ctx.AbsPath(c.RepoPath), conf, charmStoreRetryPolicy(ctx))
 	if err != nil {
-		return block.ProcessBlockedError(err, block.BlockChange)
-	}
-
-	if c.BumpRevision {
-		ctx.Infof("--upgrade (or -u) is deprecated and ignored; charms are always deployed with a unique revision.")
+		return errors.Trace(err)
 	}
 
 	requestedNetworks, err := networkNamesToTags(parseNetworks(c.Networks))
@@ -217,21 +275,27 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 	haveNetworks := len(requestedNetworks) > 0 || c.Constraints.HaveNetworks()
 
+	if c.DryRun {
+		return c.writePlan(ctx, curl, repo)
+	}
+
+	curl, err = addCharmViaAPI(client, ctx, curl, repo, csClient)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	if c.BumpRevision {
+		ctx.Infof("--upgrade (or -u) is deprecated and ignored; charms are always deployed with a unique revision.")
+	}
+
 	charmInfo, err := client.CharmInfo(curl.String())
 	if err != nil {
 		return err
 	}
 
-	numUnits := c.NumUnits
-	if charmInfo.Meta.Subordinate {
-		if !constraints.IsEmpty(&c.Constraints) {
-			return errors.New("cannot use --constraints with subordinate service")
-		}
-		if numUnits == 1 && c.ToMachineSpec == "" {
-			numUnits = 0
-		} else {
-			return errors.New("cannot use --num-units or --to with subordinate service")
-		}
+	numUnits, err := c.unitCount(charmInfo.Meta)
+	if err != nil {
+		return err
 	}
 	serviceName := c.ServiceName
 	if serviceName == "" {
@@ -294,6 +358,21 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
 
+// unitCount returns the number of units to deploy of a charm with the
+// given metadata, checking that the command's flags suit the charm.
+func (c *DeployCommand) unitCount(meta *charm.Meta) (int, error) {
+	if !meta.Subordinate {
+		return c.NumUnits, nil
+	}
+	if !constraints.IsEmpty(&c.Constraints) {
+		return 0, errors.New("cannot use --constraints with subordinate service")
+	}
+	if c.NumUnits != 1 || c.ToMachineSpec != "" {
+		return 0, errors.New("cannot use --num-units or --to with subordinate service")
+	}
+	return 0, nil
+}
+
 // parseNetworks returns a list of network names by parsing the
 // comma-delimited string value of --networks argument.
 func parseNetworks(networksValue string) []string {
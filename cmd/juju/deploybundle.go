@@ -0,0 +1,357 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// machineMap holds the --map-machines mapping of the machines defined
+// in a bundle to machines that already exist in the environment.
+type machineMap struct {
+	// existing records whether bundle machines are to be mapped to
+	// existing machines with the same id.
+	existing bool
+
+	// machines maps bundle machine ids to environment machine ids.
+	machines map[string]string
+}
+
+// parseMachineMap parses the value of the --map-machines flag: a
+// comma-separated list of "existing" and "<bundle machine>=<machine>"
+// items.
+func parseMachineMap(value string) (machineMap, error) {
+	m := machineMap{machines: make(map[string]string)}
+	if value == "" {
+		return m, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "existing" {
+			m.existing = true
+			continue
+		}
+		parts := strings.Split(item, "=")
+		if len(parts) != 2 {
+			return machineMap{}, errors.Errorf("invalid machine mapping %q: expected \"existing\" or <bundle machine>=<machine>", item)
+		}
+		from, to := parts[0], parts[1]
+		if _, err := strconv.Atoi(from); err != nil {
+			return machineMap{}, errors.Errorf("invalid machine mapping %q: invalid bundle machine %q", item, from)
+		}
+		if !names.IsValidMachine(to) {
+			return machineMap{}, errors.Errorf("invalid machine mapping %q: invalid machine %q", item, to)
+		}
+		if _, ok := m.machines[from]; ok {
+			return machineMap{}, errors.Errorf("bundle machine %q mapped more than once", from)
+		}
+		m.machines[from] = to
+	}
+	return m, nil
+}
+
+// bundleDeployer deploys the machines, services and relations of a
+// bundle to an environment.
+type bundleDeployer struct {
+	ctx        *cmd.Context
+	client     *api.Client
+	csClient   *csClient
+	conf       *config.Config
+	repoPath   string
+	data       *charm.BundleData
+	machineMap machineMap
+
+	// machines maps bundle machine ids to the ids of the machines of
+	// the environment used for them.
+	machines map[string]string
+}
+
+// deployBundle deploys the bundle at c.BundlePath.
+func (c *DeployCommand) deployBundle(ctx *cmd.Context, client *api.Client, csClient *csClient, conf *config.Config) error {
+	machineMap, err := parseMachineMap(c.MachineMap)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	path := ctx.AbsPath(c.BundlePath)
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	data, err := charm.ReadBundleData(f)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read bundle %q", c.BundlePath)
+	}
+	if err := data.Verify(func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}); err != nil {
+		return errors.Annotatef(err, "invalid bundle %q", c.BundlePath)
+	}
+	d := &bundleDeployer{
+		ctx:        ctx,
+		client:     client,
+		csClient:   csClient,
+		conf:       conf,
+		repoPath:   ctx.AbsPath(c.RepoPath),
+		data:       data,
+		machineMap: machineMap,
+		machines:   make(map[string]string),
+	}
+	return d.deploy()
+}
+
+func (d *bundleDeployer) deploy() error {
+	if err := d.addMachines(); err != nil {
+		return errors.Trace(err)
+	}
+	var serviceNames []string
+	for name := range d.data.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+	for _, name := range serviceNames {
+		if err := d.deployService(name, d.data.Services[name]); err != nil {
+			return errors.Annotatef(err, "cannot deploy service %q", name)
+		}
+	}
+	for _, endpoints := range d.data.Relations {
+		if _, err := d.client.AddRelation(endpoints...); err != nil {
+			err = block.ProcessBlockedError(err, block.BlockChange)
+			return errors.Annotatef(err, "cannot add relation %s", strings.Join(endpoints, " "))
+		}
+		d.ctx.Infof("Added relation %s", strings.Join(endpoints, " "))
+	}
+	return nil
+}
+
+// addMachines records, for each machine of the bundle, the machine of
+// the environment to use for it, adding new machines for those that
+// are not mapped to existing ones.
+func (d *bundleDeployer) addMachines() error {
+	status, err := d.client.Status(nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for from, to := range d.machineMap.machines {
+		if _, ok := d.data.Machines[from]; !ok {
+			return errors.Errorf("cannot map machine %q: not defined in the bundle", from)
+		}
+		if !machineExists(status.Machines, to) {
+			return errors.Errorf("cannot map bundle machine %q to machine %q: machine not found", from, to)
+		}
+	}
+
+	var ids []string
+	for id := range d.data.Machines {
+		ids = append(ids, id)
+	}
+	sort.Sort(bundleMachineIds(ids))
+
+	var newIds []string
+	var newMachines []params.AddMachineParams
+	for _, id := range ids {
+		if to, ok := d.machineMap.machines[id]; ok {
+			d.machines[id] = to
+		} else if d.machineMap.existing && machineExists(status.Machines, id) {
+			d.machines[id] = id
+		} else {
+			p, err := d.machineParams(d.data.Machines[id])
+			if err != nil {
+				return errors.Annotatef(err, "cannot add bundle machine %q", id)
+			}
+			newIds = append(newIds, id)
+			newMachines = append(newMachines, p)
+			continue
+		}
+		d.ctx.Infof("Using machine %s for bundle machine %s", d.machines[id], id)
+	}
+	if len(newMachines) == 0 {
+		return nil
+	}
+	results, err := d.client.AddMachines(newMachines)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			return errors.Annotatef(result.Error, "cannot add bundle machine %q", newIds[i])
+		}
+		d.machines[newIds[i]] = result.Machine
+		d.ctx.Infof("Added machine %s for bundle machine %s", result.Machine, newIds[i])
+	}
+	return nil
+}
+
+func (d *bundleDeployer) machineParams(spec *charm.MachineSpec) (params.AddMachineParams, error) {
+	if spec == nil {
+		spec = &charm.MachineSpec{}
+	}
+	cons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return params.AddMachineParams{}, errors.Trace(err)
+	}
+	series := spec.Series
+	if series == "" {
+		series = d.data.Series
+	}
+	return params.AddMachineParams{
+		Series:      series,
+		Constraints: cons,
+		Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+	}, nil
+}
+
+// deployService deploys the service with its units placed as the
+// bundle directs; units without a placement go to new machines.
+func (d *bundleDeployer) deployService(name string, spec *charm.ServiceSpec) error {
+	charmName := spec.Charm
+	if d.data.Series != "" {
+		ref, err := charm.ParseReference(charmName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if ref.Series == "" {
+			ref.Series = d.data.Series
+			charmName = ref.String()
+		}
+	}
+	curl, repo, err := resolveCharmURL(charmName, d.csClient.params, d.repoPath, d.conf, charmStoreRetryPolicy(d.ctx))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	curl, err = addCharmViaAPI(d.client, d.ctx, curl, repo, d.csClient)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	cons, err := constraints.Parse(spec.Constraints)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var configYAML string
+	if len(spec.Options) > 0 {
+		data, err := goyaml.Marshal(map[string]interface{}{name: spec.Options})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		configYAML = string(data)
+	}
+	placements := make([]string, len(spec.To))
+	for i, to := range spec.To {
+		if placements[i], err = d.placement(to); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	// Only a single unit may be deployed with a placement directive,
+	// so the first placed unit is deployed with the service and the
+	// others are added one at a time.
+	if len(placements) == 0 || spec.NumUnits == 0 {
+		err = d.client.ServiceDeploy(curl.String(), name, spec.NumUnits, configYAML, cons, "")
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		d.ctx.Infof("Deployed service %q with %d unit(s)", name, spec.NumUnits)
+		return nil
+	}
+	if err := d.client.ServiceDeploy(curl.String(), name, 1, configYAML, cons, placements[0]); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	for i := 1; i < spec.NumUnits && i < len(placements); i++ {
+		if _, err := d.client.AddServiceUnits(name, 1, placements[i]); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	if remaining := spec.NumUnits - len(placements); remaining > 0 {
+		if _, err := d.client.AddServiceUnits(name, remaining, ""); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+	}
+	d.ctx.Infof("Deployed service %q with %d unit(s)", name, spec.NumUnits)
+	return nil
+}
+
+// placement returns the machine spec for a bundle placement directive
+// of the form [<container type>:]<bundle machine> or
+// [<container type>:]new, translating the bundle machine to the
+// environment machine used for it.
+func (d *bundleDeployer) placement(to string) (string, error) {
+	containerType, target := "", to
+	if i := strings.Index(to, ":"); i >= 0 {
+		containerType, target = to[:i], to[i+1:]
+		if _, err := instance.ParseContainerType(containerType); err != nil {
+			return "", errors.Errorf("invalid placement %q: %v", to, err)
+		}
+	}
+	if target == "new" && containerType != "" {
+		// A new container on a new machine cannot be asked for
+		// with a machine spec, so the container is added first.
+		p := params.AddMachineParams{
+			Series:        d.data.Series,
+			ContainerType: instance.ContainerType(containerType),
+			Jobs:          []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		}
+		results, err := d.client.AddMachines([]params.AddMachineParams{p})
+		if err != nil {
+			return "", block.ProcessBlockedError(err, block.BlockChange)
+		}
+		if results[0].Error != nil {
+			return "", errors.Annotatef(results[0].Error, "cannot add machine for placement %q", to)
+		}
+		return results[0].Machine, nil
+	}
+	if target == "new" {
+		return "", nil
+	}
+	if strings.Contains(target, "/") {
+		return "", errors.Errorf("invalid placement %q: placing units alongside other units is not supported", to)
+	}
+	machine, ok := d.machines[target]
+	if !ok {
+		return "", errors.Errorf("invalid placement %q: machine %q not defined in the bundle", to, target)
+	}
+	if containerType != "" {
+		return containerType + ":" + machine, nil
+	}
+	return machine, nil
+}
+
+// machineExists reports whether the machine with the given id is among
+// the machines, or their containers.
+func machineExists(machines map[string]api.MachineStatus, id string) bool {
+	for machineId, m := range machines {
+		if machineId == id || machineExists(m.Containers, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleMachineIds sorts bundle machine ids numerically.
+type bundleMachineIds []string
+
+func (ids bundleMachineIds) Len() int      { return len(ids) }
+func (ids bundleMachineIds) Swap(i, j int) { ids[i], ids[j] = ids[j], ids[i] }
+func (ids bundleMachineIds) Less(i, j int) bool {
+	a, _ := strconv.Atoi(ids[i])
+	b, _ := strconv.Atoi(ids[j])
+	return a < b
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DeployBundleSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&DeployBundleSuite{})
+
+var parseMachineMapTests = []struct {
+	value    string
+	existing bool
+	machines map[string]string
+	err      string
+}{{
+	machines: map[string]string{},
+}, {
+	value:    "existing",
+	existing: true,
+	machines: map[string]string{},
+}, {
+	value:    "existing,1=4, 2=4/lxc/0",
+	existing: true,
+	machines: map[string]string{"1": "4", "2": "4/lxc/0"},
+}, {
+	value: "1",
+	err:   `invalid machine mapping "1": expected "existing" or <bundle machine>=<machine>`,
+}, {
+	value: "foo=4",
+	err:   `invalid machine mapping "foo=4": invalid bundle machine "foo"`,
+}, {
+	value: "1=bar",
+	err:   `invalid machine mapping "1=bar": invalid machine "bar"`,
+}, {
+	value: "1=4,1=5",
+	err:   `bundle machine "1" mapped more than once`,
+}}
+
+func (s *DeployBundleSuite) TestParseMachineMap(c *gc.C) {
+	for i, test := range parseMachineMapTests {
+		c.Logf("test %d: %q", i, test.value)
+		m, err := parseMachineMap(test.value)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(m.existing, gc.Equals, test.existing)
+		c.Check(m.machines, jc.DeepEquals, test.machines)
+	}
+}
+
+var bundleInitErrorTests = []struct {
+	args []string
+	err  string
+}{{
+	args: []string{"bundle.yaml", "service-name"},
+	err:  `a service name cannot be given when deploying a bundle`,
+}, {
+	args: []string{"bundle.yaml", "-n", "2"},
+	err:  `-n, --to, --config, --constraints, --networks, --storage and --dry-run cannot be used when deploying a bundle`,
+}, {
+	args: []string{"bundle.yaml", "--map-machines", "1"},
+	err:  `invalid machine mapping "1": expected "existing" or <bundle machine>=<machine>`,
+}, {
+	args: []string{"local:dummy", "--map-machines", "existing"},
+	err:  `--map-machines can only be used when deploying a bundle`,
+}}
+
+func (s *DeployBundleSuite) TestInitErrors(c *gc.C) {
+	for i, t := range bundleInitErrorTests {
+		c.Logf("test %d", i)
+		err := coretesting.InitCommand(envcmd.Wrap(&DeployCommand{}), t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *DeployBundleSuite) writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *DeployBundleSuite) addMachines(c *gc.C, n int) {
+	for i := 0; i < n; i++ {
+		_, err := s.State.AddMachine(coretesting.FakeDefaultSeries, state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *DeployBundleSuite) assertUnitMachines(c *gc.C, service string, expected ...string) {
+	svc, err := s.State.Service(service)
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := svc.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	var machines []string
+	for _, unit := range units {
+		mid, err := unit.AssignedMachineId()
+		c.Assert(err, jc.ErrorIsNil)
+		machines = append(machines, mid)
+	}
+	sort.Strings(machines)
+	sort.Strings(expected)
+	c.Assert(machines, jc.DeepEquals, expected)
+}
+
+func (s *DeployBundleSuite) assertMachineCount(c *gc.C, n int) {
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, n)
+}
+
+const mapMachinesBundle = `
+services:
+    wordpress:
+        charm: local:wordpress
+        num_units: 2
+        to: ["0", "1"]
+    mysql:
+        charm: local:mysql
+        num_units: 1
+        to: ["lxc:1"]
+machines:
+    "0":
+    "1":
+relations:
+    - ["wordpress:db", "mysql:server"]
+`
+
+func (s *DeployBundleSuite) TestDeployBundleMapMachines(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	s.addMachines(c, 3)
+	path := s.writeBundle(c, mapMachinesBundle)
+
+	err := runDeploy(c, path, "--map-machines", "existing,1=2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitMachines(c, "wordpress", "0", "2")
+	s.assertUnitMachines(c, "mysql", "2/lxc/0")
+	s.assertMachineCount(c, 4)
+	eps, err := s.State.InferEndpoints("wordpress:db", "mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EndpointsRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleNewMachines(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	s.addMachines(c, 1)
+	path := s.writeBundle(c, mapMachinesBundle)
+
+	// Without a mapping, new machines are added for all of the
+	// bundle's machines.
+	err := runDeploy(c, path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitMachines(c, "wordpress", "1", "2")
+	s.assertUnitMachines(c, "mysql", "2/lxc/0")
+	s.assertMachineCount(c, 4)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleMapMachineNotFound(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "wordpress")
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "mysql")
+	path := s.writeBundle(c, mapMachinesBundle)
+
+	err := runDeploy(c, path, "--map-machines", "1=42")
+	c.Assert(err, gc.ErrorMatches, `cannot map bundle machine "1" to machine "42": machine not found`)
+	_, err = s.State.Service("wordpress")
+	c.Assert(err, gc.ErrorMatches, `service "wordpress" not found`)
+}
+
+func (s *DeployBundleSuite) TestDeployBundleMapMachineNotInBundle(c *gc.C) {
+	s.addMachines(c, 1)
+	path := s.writeBundle(c, mapMachinesBundle)
+
+	err := runDeploy(c, path, "--map-machines", "5=0")
+	c.Assert(err, gc.ErrorMatches, `cannot map machine "5": not defined in the bundle`)
+}
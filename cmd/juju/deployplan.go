@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/charmrepo"
+
+	"github.com/juju/juju/instance"
+)
+
+// deployPlan describes what deploying a charm would do, without
+// doing it.
+type deployPlan struct {
+	Charm            string                    `yaml:"charm" json:"charm"`
+	Service          string                    `yaml:"service" json:"service"`
+	Subordinate      bool                      `yaml:"subordinate,omitempty" json:"subordinate,omitempty"`
+	Units            int                       `yaml:"units" json:"units"`
+	To               string                    `yaml:"to,omitempty" json:"to,omitempty"`
+	Config           charm.Settings            `yaml:"config,omitempty" json:"config,omitempty"`
+	Constraints      string                    `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Networks         []string                  `yaml:"networks,omitempty" json:"networks,omitempty"`
+	Storage          map[string]plannedStorage `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Machines         []plannedMachine          `yaml:"machines-to-add,omitempty" json:"machines-to-add,omitempty"`
+	EndpointBindings map[string]string         `yaml:"endpoint-bindings" json:"endpoint-bindings"`
+}
+
+// plannedStorage describes the storage constraints that would be
+// set for a service.
+type plannedStorage struct {
+	Pool  string `yaml:"pool,omitempty" json:"pool,omitempty"`
+	Size  uint64 `yaml:"size" json:"size"`
+	Count uint64 `yaml:"count" json:"count"`
+}
+
+// plannedMachine describes a machine or container that would be
+// added to host a unit.
+type plannedMachine struct {
+	Series      string `yaml:"series" json:"series"`
+	Container   string `yaml:"container,omitempty" json:"container,omitempty"`
+	Host        string `yaml:"host,omitempty" json:"host,omitempty"`
+	Constraints string `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+}
+
+// writePlan writes out the plan for deploying the charm with the
+// given URL, read from the given repository, without changing the
+// environment.
+func (c *DeployCommand) writePlan(ctx *cmd.Context, curl *charm.URL, repo charmrepo.Interface) error {
+	ch, err := repo.Get(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := ch.Meta()
+	numUnits, err := c.unitCount(meta)
+	if err != nil {
+		return err
+	}
+	plan := deployPlan{
+		Charm:            curl.String(),
+		Service:          c.ServiceName,
+		Subordinate:      meta.Subordinate,
+		Units:            numUnits,
+		To:               c.ToMachineSpec,
+		Constraints:      c.Constraints.String(),
+		Networks:         parseNetworks(c.Networks),
+		EndpointBindings: make(map[string]string),
+	}
+	if plan.Service == "" {
+		plan.Service = meta.Name
+	}
+	if c.Config.Path != "" {
+		configYAML, err := c.Config.Read(ctx)
+		if err != nil {
+			return err
+		}
+		plan.Config, err = ch.Config().ParseSettingsYAML(configYAML, plan.Service)
+		if err != nil {
+			return err
+		}
+	}
+	if len(c.Storage) > 0 {
+		plan.Storage = make(map[string]plannedStorage)
+		for name, cons := range c.Storage {
+			if _, ok := meta.Storage[name]; !ok {
+				return errors.Errorf("charm %q has no store called %q", meta.Name, name)
+			}
+			plan.Storage[name] = plannedStorage{cons.Pool, cons.Size, cons.Count}
+		}
+	}
+	plan.Machines, err = c.plannedMachines(curl.Series, numUnits)
+	if err != nil {
+		return err
+	}
+	for _, relations := range []map[string]charm.Relation{meta.Provides, meta.Requires, meta.Peers} {
+		for name := range relations {
+			plan.EndpointBindings[name] = ""
+		}
+	}
+	return c.out.Write(ctx, plan)
+}
+
+// plannedMachines returns the machines and containers that would be
+// added to host the given number of units of the given series.
+func (c *DeployCommand) plannedMachines(series string, numUnits int) ([]plannedMachine, error) {
+	cons := c.Constraints.String()
+	spec := c.ToMachineSpec
+	if spec == "" {
+		machines := make([]plannedMachine, numUnits)
+		for i := range machines {
+			machines[i] = plannedMachine{Series: series, Constraints: cons}
+		}
+		return machines, nil
+	}
+	specParts := strings.SplitN(spec, ":", 2)
+	if len(specParts) == 1 {
+		// The unit would be placed on an existing machine.
+		return nil, nil
+	}
+	containerType, err := instance.ParseContainerType(specParts[0])
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hostId := specParts[1]
+	if strings.Contains(hostId, ":") {
+		hostType, nestedHostId, err := instance.ParseNestedContainer(containerType, hostId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []plannedMachine{{
+			Series:      series,
+			Container:   string(hostType),
+			Host:        nestedHostId,
+			Constraints: cons,
+		}, {
+			Series:      series,
+			Container:   string(containerType),
+			Host:        fmt.Sprintf("new %s container on machine %s", hostType, nestedHostId),
+			Constraints: cons,
+		}}, nil
+	}
+	return []plannedMachine{{
+		Series:      series,
+		Container:   string(containerType),
+		Host:        hostId,
+		Constraints: cons,
+	}}, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"encoding/json"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testcharms"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type DeployPlanSuite struct {
+	testing.RepoSuite
+}
+
+var _ = gc.Suite(&DeployPlanSuite{})
+
+func (s *DeployPlanSuite) runPlan(c *gc.C, args ...string) map[string]interface{} {
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), append(args, "--dry-run")...)
+	c.Assert(err, jc.ErrorIsNil)
+	var plan map[string]interface{}
+	err = goyaml.Unmarshal([]byte(coretesting.Stdout(ctx)), &plan)
+	c.Assert(err, jc.ErrorIsNil)
+	return plan
+}
+
+func (s *DeployPlanSuite) assertNothingDeployed(c *gc.C) {
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 0)
+	charms, err := s.State.AllCharms()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(charms, gc.HasLen, 0)
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *DeployPlanSuite) TestPlan(c *gc.C) {
+	testcharms.Repo.ClonedDirPath(s.SeriesPath, "wordpress")
+	plan := s.runPlan(c, "local:wordpress", "blog", "-n", "2", "--constraints", "mem=2G")
+	c.Assert(plan, jc.DeepEquals, map[string]interface{}{
+		"charm":       "local:trusty/wordpress-3",
+		"service":     "blog",
+		"units":       2,
+		"constraints": "mem=2048M",
+		"machines-to-add": []interface{}{
+			map[interface{}]interface{}{"series": "trusty", "constraints": "mem=2048M"},
+			map[interface{}]interface{}{"series": "trusty", "constraints": "mem=2048M"},
+		},
+		"endpoint-bindings": map[interface{}]interface{}{
+			"url":             "",
+			"logging-dir":     "",
+			"monitoring-port": "",
+			"db":              "",
+			"cache":           "",
+		},
+	})
+	s.assertNothingDeployed(c)
+}
+
+func (s *DeployPlanSuite) TestPlanConfig(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	path := setupConfigFile(c, c.MkDir())
+	plan := s.runPlan(c, "local:dummy", "dummy-service", "--config", path)
+	c.Assert(plan["config"], jc.DeepEquals, map[interface{}]interface{}{
+		"skill-level": 9000,
+		"username":    "admin001",
+	})
+	s.assertNothingDeployed(c)
+}
+
+func (s *DeployPlanSuite) TestPlanStorageJSON(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "storage-block")
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}),
+		"local:storage-block", "--dry-run", "--format", "json", "--storage", "data=loop-pool,1G",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	var plan deployPlan
+	err = json.Unmarshal([]byte(coretesting.Stdout(ctx)), &plan)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(plan.Service, gc.Equals, "storage-block")
+	c.Assert(plan.Storage, jc.DeepEquals, map[string]plannedStorage{
+		"data": {Pool: "loop-pool", Size: 1024, Count: 1},
+	})
+	c.Assert(plan.Machines, jc.DeepEquals, []plannedMachine{{Series: "trusty"}})
+	s.assertNothingDeployed(c)
+}
+
+func (s *DeployPlanSuite) TestPlanUnknownStorage(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "local:dummy", "--dry-run", "--storage", "data=1G")
+	c.Assert(err, gc.ErrorMatches, `charm "dummy" has no store called "data"`)
+	s.assertNothingDeployed(c)
+}
+
+func (s *DeployPlanSuite) TestPlanSubordinate(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
+	plan := s.runPlan(c, "local:logging")
+	c.Assert(plan["subordinate"], gc.Equals, true)
+	c.Assert(plan["units"], gc.Equals, 0)
+	c.Assert(plan["machines-to-add"], gc.IsNil)
+
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DeployCommand{}), "local:logging", "--dry-run", "-n", "2")
+	c.Assert(err, gc.ErrorMatches, "cannot use --num-units or --to with subordinate service")
+	s.assertNothingDeployed(c)
+}
+
+func (s *DeployPlanSuite) TestPlannedMachines(c *gc.C) {
+	for i, test := range []struct {
+		to       string
+		expected []plannedMachine
+	}{{
+		to: "0",
+	}, {
+		to: "0/lxc/1",
+	}, {
+		to:       "lxc:1",
+		expected: []plannedMachine{{Series: "trusty", Container: "lxc", Host: "1"}},
+	}, {
+		to: "lxc:kvm:2",
+		expected: []plannedMachine{
+			{Series: "trusty", Container: "kvm", Host: "2"},
+			{Series: "trusty", Container: "lxc", Host: "new kvm container on machine 2"},
+		},
+	}} {
+		c.Logf("test %d: --to %s", i, test.to)
+		deploy := &DeployCommand{}
+		deploy.ToMachineSpec = test.to
+		machines, err := deploy.plannedMachines("trusty", 1)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(machines, jc.DeepEquals, test.expected)
+	}
+}
@@ -31,9 +31,12 @@ var DoubleEnvironmentError = stderrors.New("you cannot supply both -e and the en
 type DestroyEnvironmentCommand struct {
 	envcmd.EnvCommandBase
 	cmd.CommandBase
-	envName   string
-	assumeYes bool
-	force     bool
+	envName        string
+	assumeYes      bool
+	force          bool
+	destroyStorage bool
+	releaseStorage bool
+	ignoreBlockers []string
 }
 
 func (c *DestroyEnvironmentCommand) Info() *cmd.Info {
@@ -41,18 +44,41 @@ func (c *DestroyEnvironmentCommand) Info() *cmd.Info {
 		Name:    "destroy-environment",
 		Args:    "<environment name>",
 		Purpose: "terminate all machines and other associated resources for an environment",
+		Doc:     destroyEnvDoc,
 	}
 }
 
+const destroyEnvDoc = `
+Destroys the given environment, terminating all of its machines.
+
+If the environment has persistent storage, such as volumes that outlive
+the machines they are attached to, you must say what should become of
+it: --destroy-storage destroys it along with the environment, while
+--release-storage detaches it and leaves it in the cloud.
+
+Before the environment is destroyed, Juju checks for anything that
+should stop it, and reports everything it finds. Some of these blockers
+can be ignored with --ignore-blockers, which takes a comma-separated
+list of:
+    manual-machines     leave manually provisioned machines running
+    persistent-storage  leave persistent storage in the cloud, unmanaged
+`
+
 func (c *DestroyEnvironmentCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation")
 	f.BoolVar(&c.assumeYes, "yes", false, "")
 	f.BoolVar(&c.force, "force", false, "Forcefully destroy the environment, directly through the environment provider")
+	f.BoolVar(&c.destroyStorage, "destroy-storage", false, "Destroy the environment's persistent storage")
+	f.BoolVar(&c.releaseStorage, "release-storage", false, "Release the environment's persistent storage, leaving it in the cloud")
+	f.Var(cmd.NewStringsValue(nil, &c.ignoreBlockers), "ignore-blockers", "Destroy the environment despite the listed kinds of blocker")
 	f.StringVar(&c.envName, "e", "", "juju environment to operate in")
 	f.StringVar(&c.envName, "environment", "", "juju environment to operate in")
 }
 
 func (c *DestroyEnvironmentCommand) Init(args []string) error {
+	if c.destroyStorage && c.releaseStorage {
+		return errors.New("--destroy-storage and --release-storage are mutually exclusive")
+	}
 	if c.envName != "" {
 		logger.Warningf("-e/--environment flag is deprecated in 1.18, " +
 			"please supply environment as a positional parameter")
@@ -171,7 +197,7 @@ func (c *DestroyEnvironmentCommand) destroyEnv(apiclient *api.Client) (result er
 	defer func() {
 		result = c.ensureUserFriendlyErrorLog(result)
 	}()
-	err := apiclient.DestroyEnvironment()
+	err := apiclient.DestroyEnvironment(c.storageChoice(), c.ignoreBlockers)
 	if cmdErr := processDestroyError(err); cmdErr != nil {
 		return cmdErr
 	}
@@ -179,6 +205,20 @@ func (c *DestroyEnvironmentCommand) destroyEnv(apiclient *api.Client) (result er
 	return nil
 }
 
+// storageChoice returns what should become of the environment's
+// persistent storage, or nil if the user did not say.
+func (c *DestroyEnvironmentCommand) storageChoice() *bool {
+	switch {
+	case c.destroyStorage:
+		destroyStorage := true
+		return &destroyStorage
+	case c.releaseStorage:
+		destroyStorage := false
+		return &destroyStorage
+	}
+	return nil
+}
+
 // processDestroyError determines how to format error message based on its code.
 // Note that CodeNotImplemented errors have not be propogated in previous implementation.
 // This behaviour was preserved.
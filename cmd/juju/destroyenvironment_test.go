@@ -107,6 +107,26 @@ func (s *destroyEnvSuite) TestForceDestroyUnlockedEnvironment(c *gc.C) {
 	s.checkDestroyEnvironment(c, false, true)
 }
 
+func (s *destroyEnvSuite) TestDestroyEnvironmentReleaseStorage(c *gc.C) {
+	s.startEnvironment(c, "dummyenv")
+	opc, errc := cmdtesting.RunCommand(cmdtesting.NullContext(c), new(DestroyEnvironmentCommand), "dummyenv", "--yes", "--release-storage")
+	c.Check(<-errc, gc.IsNil)
+	c.Check((<-opc).(dummy.OpDestroy).Env, gc.Equals, "dummyenv")
+}
+
+func (s *destroyEnvSuite) TestDestroyEnvironmentIgnoreBlockers(c *gc.C) {
+	s.startEnvironment(c, "dummyenv")
+	opc, errc := cmdtesting.RunCommand(cmdtesting.NullContext(c), new(DestroyEnvironmentCommand), "dummyenv", "--yes", "--ignore-blockers", "manual-machines,persistent-storage")
+	c.Check(<-errc, gc.IsNil)
+	c.Check((<-opc).(dummy.OpDestroy).Env, gc.Equals, "dummyenv")
+}
+
+func (s *destroyEnvSuite) TestDestroyEnvironmentStorageFlagsExclusive(c *gc.C) {
+	s.startEnvironment(c, "dummyenv")
+	_, errc := cmdtesting.RunCommand(cmdtesting.NullContext(c), new(DestroyEnvironmentCommand), "dummyenv", "--yes", "--destroy-storage", "--release-storage")
+	c.Check(<-errc, gc.ErrorMatches, "--destroy-storage and --release-storage are mutually exclusive")
+}
+
 func (s *destroyEnvSuite) TestDestroyEnvironmentCommandEFlag(c *gc.C) {
 	// Prepare the environment so we can destroy it.
 	_, err := environs.PrepareFromName("dummyenv", envcmd.BootstrapContext(cmdtesting.NullContext(c)), s.ConfigStore)
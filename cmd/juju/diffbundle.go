@@ -0,0 +1,344 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+)
+
+// diffBundleAPI defines the methods on the client API that the
+// diff-bundle command uses.
+type diffBundleAPI interface {
+	Status(patterns []string) (*api.Status, error)
+	ServiceGet(service string) (*params.ServiceGetResults, error)
+	Close() error
+}
+
+var newDiffBundleAPI = func(c *DiffBundleCommand) (diffBundleAPI, error) {
+	return c.NewAPIClient()
+}
+
+// DiffBundleCommand compares a bundle with the services and
+// relations of an environment.
+type DiffBundleCommand struct {
+	envcmd.EnvCommandBase
+	out        cmd.Output
+	bundlePath string
+}
+
+const diffBundleDoc = `
+Compare the services and relations described by a bundle with those of
+the environment, and show the differences.
+
+For each service, the differences shown are whether it is missing from
+the environment or from the bundle, and where its charm, number of
+units, constraints or configuration options differ. A charm URL in the
+bundle that omits the series or revision matches a charm of any series
+or revision. Only the options set in the bundle are compared.
+
+Relations are shown if they are in the bundle but not the environment
+("bundle-additions"), or in the environment but not the bundle
+("model-additions"). A bundle relation that names only a service matches
+a relation on any of the service's endpoints. Peer relations are not
+compared.
+
+No differences are shown if the environment matches the bundle.
+Nothing is changed in the environment.
+
+Examples:
+    juju diff-bundle bundle.yaml
+    juju diff-bundle bundle.yaml --format json
+`
+
+func (c *DiffBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "diff-bundle",
+		Args:    "<bundle file>",
+		Purpose: "compare a bundle with the environment",
+		Doc:     diffBundleDoc,
+	}
+}
+
+func (c *DiffBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+func (c *DiffBundleCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no bundle specified")
+	case 1:
+		c.bundlePath = args[0]
+		return nil
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+}
+
+// bundleDiff describes the differences between a bundle and an
+// environment.
+type bundleDiff struct {
+	Services  map[string]*serviceDiff `yaml:"services,omitempty" json:"services,omitempty"`
+	Relations *relationsDiff          `yaml:"relations,omitempty" json:"relations,omitempty"`
+}
+
+// serviceDiff describes the differences between a service in a bundle
+// and in an environment. Missing is "model" if the service is only in
+// the bundle, and "bundle" if the service is only in the environment.
+type serviceDiff struct {
+	Missing     string                `yaml:"missing,omitempty" json:"missing,omitempty"`
+	Charm       *stringDiff           `yaml:"charm,omitempty" json:"charm,omitempty"`
+	NumUnits    *intDiff              `yaml:"num-units,omitempty" json:"num-units,omitempty"`
+	Constraints *stringDiff           `yaml:"constraints,omitempty" json:"constraints,omitempty"`
+	Options     map[string]optionDiff `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+type stringDiff struct {
+	Bundle string `yaml:"bundle" json:"bundle"`
+	Model  string `yaml:"model" json:"model"`
+}
+
+type intDiff struct {
+	Bundle int `yaml:"bundle" json:"bundle"`
+	Model  int `yaml:"model" json:"model"`
+}
+
+type optionDiff struct {
+	Bundle interface{} `yaml:"bundle" json:"bundle"`
+	Model  interface{} `yaml:"model" json:"model"`
+}
+
+// relationsDiff holds the relations, as pairs of endpoints, that are
+// only in the bundle or only in the environment.
+type relationsDiff struct {
+	BundleAdditions [][]string `yaml:"bundle-additions,omitempty" json:"bundle-additions,omitempty"`
+	ModelAdditions  [][]string `yaml:"model-additions,omitempty" json:"model-additions,omitempty"`
+}
+
+func (c *DiffBundleCommand) Run(ctx *cmd.Context) error {
+	f, err := os.Open(ctx.AbsPath(c.bundlePath))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	data, err := charm.ReadBundleData(f)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read bundle %q", c.bundlePath)
+	}
+	if err := data.Verify(func(s string) error {
+		_, err := constraints.Parse(s)
+		return err
+	}); err != nil {
+		return errors.Annotatef(err, "invalid bundle %q", c.bundlePath)
+	}
+
+	client, err := newDiffBundleAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+	status, err := client.Status(nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot get environment status")
+	}
+	diff, err := diffBundle(data, status, client.ServiceGet)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return c.out.Write(ctx, diff)
+}
+
+// diffBundle returns the differences between the given bundle and the
+// environment with the given status, using serviceGet to read the
+// configuration of the environment's services.
+func diffBundle(
+	data *charm.BundleData,
+	status *api.Status,
+	serviceGet func(string) (*params.ServiceGetResults, error),
+) (*bundleDiff, error) {
+	diff := &bundleDiff{Services: make(map[string]*serviceDiff)}
+	for name, spec := range data.Services {
+		svcStatus, ok := status.Services[name]
+		if !ok {
+			diff.Services[name] = &serviceDiff{Missing: "model"}
+			continue
+		}
+		config, err := serviceGet(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot get configuration of service %q", name)
+		}
+		if svcDiff := diffService(spec, data.Series, svcStatus, config); svcDiff != nil {
+			diff.Services[name] = svcDiff
+		}
+	}
+	for name := range status.Services {
+		if _, ok := data.Services[name]; !ok {
+			diff.Services[name] = &serviceDiff{Missing: "bundle"}
+		}
+	}
+	if len(diff.Services) == 0 {
+		diff.Services = nil
+	}
+	diff.Relations = diffRelations(data.Relations, status.Relations)
+	return diff, nil
+}
+
+// diffService returns the differences between the given bundle service
+// and environment service, or nil if there are none.
+func diffService(spec *charm.ServiceSpec, defaultSeries string, svcStatus api.ServiceStatus, config *params.ServiceGetResults) *serviceDiff {
+	var diff serviceDiff
+	if !charmMatches(spec.Charm, defaultSeries, svcStatus.Charm) {
+		diff.Charm = &stringDiff{spec.Charm, svcStatus.Charm}
+	}
+	if spec.NumUnits != len(svcStatus.Units) {
+		diff.NumUnits = &intDiff{spec.NumUnits, len(svcStatus.Units)}
+	}
+	// The bundle constraints have been verified already.
+	bundleCons := constraints.MustParse(spec.Constraints)
+	if bundleCons.String() != config.Constraints.String() {
+		diff.Constraints = &stringDiff{bundleCons.String(), config.Constraints.String()}
+	}
+	for name, value := range spec.Options {
+		var modelValue interface{}
+		if info, ok := config.Config[name].(map[string]interface{}); ok {
+			modelValue = info["value"]
+		}
+		if !optionMatches(value, modelValue) {
+			if diff.Options == nil {
+				diff.Options = make(map[string]optionDiff)
+			}
+			diff.Options[name] = optionDiff{value, modelValue}
+		}
+	}
+	if reflect.DeepEqual(diff, serviceDiff{}) {
+		return nil
+	}
+	return &diff
+}
+
+// charmMatches reports whether the charm of a bundle service matches
+// the URL of an environment service's charm. Any series or revision
+// missing from the bundle charm matches.
+func charmMatches(bundleCharm, defaultSeries, modelCharm string) bool {
+	ref, err := charm.ParseReference(bundleCharm)
+	if err != nil {
+		return false
+	}
+	curl, err := charm.ParseURL(modelCharm)
+	if err != nil {
+		return false
+	}
+	if ref.Series == "" {
+		ref.Series = defaultSeries
+	}
+	switch {
+	case ref.Schema != curl.Schema, ref.User != curl.User, ref.Name != curl.Name:
+		return false
+	case ref.Series != "" && ref.Series != curl.Series:
+		return false
+	case ref.Revision != -1 && ref.Revision != curl.Revision:
+		return false
+	}
+	return true
+}
+
+// optionMatches reports whether a bundle option value matches the
+// value of an environment service's setting. Values are compared in
+// their formatted forms, because numbers read from the API and from
+// the bundle have different types.
+func optionMatches(bundleValue, modelValue interface{}) bool {
+	if bundleValue == nil || modelValue == nil {
+		return bundleValue == modelValue
+	}
+	return fmt.Sprint(bundleValue) == fmt.Sprint(modelValue)
+}
+
+// bundleEndpoint holds a service and, optionally, an endpoint
+// of a relation in a bundle.
+type bundleEndpoint struct {
+	service  string
+	endpoint string
+}
+
+func (ep bundleEndpoint) matches(status api.EndpointStatus) bool {
+	return ep.service == status.ServiceName && (ep.endpoint == "" || ep.endpoint == status.Name)
+}
+
+func parseBundleEndpoint(s string) bundleEndpoint {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) == 1 {
+		return bundleEndpoint{service: parts[0]}
+	}
+	return bundleEndpoint{parts[0], parts[1]}
+}
+
+// diffRelations returns the relations that are only in the bundle or
+// only in the environment, or nil if there are none.
+func diffRelations(bundleRelations [][]string, modelRelations []api.RelationStatus) *relationsDiff {
+	var candidates []api.RelationStatus
+	for _, rel := range modelRelations {
+		if len(rel.Endpoints) == 2 {
+			candidates = append(candidates, rel)
+		}
+	}
+	matched := make([]bool, len(candidates))
+	var diff relationsDiff
+	for _, pair := range bundleRelations {
+		// The bundle has been verified, so every relation is a pair.
+		ep0, ep1 := parseBundleEndpoint(pair[0]), parseBundleEndpoint(pair[1])
+		found := false
+		for i, rel := range candidates {
+			if matched[i] {
+				continue
+			}
+			m0, m1 := rel.Endpoints[0], rel.Endpoints[1]
+			if ep0.matches(m0) && ep1.matches(m1) || ep0.matches(m1) && ep1.matches(m0) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff.BundleAdditions = append(diff.BundleAdditions, []string{pair[0], pair[1]})
+		}
+	}
+	for i, rel := range candidates {
+		if !matched[i] {
+			eps := []string{rel.Endpoints[0].String(), rel.Endpoints[1].String()}
+			sort.Strings(eps)
+			diff.ModelAdditions = append(diff.ModelAdditions, eps)
+		}
+	}
+	if diff.BundleAdditions == nil && diff.ModelAdditions == nil {
+		return nil
+	}
+	sort.Sort(endpointPairs(diff.BundleAdditions))
+	sort.Sort(endpointPairs(diff.ModelAdditions))
+	return &diff
+}
+
+type endpointPairs [][]string
+
+func (p endpointPairs) Len() int      { return len(p) }
+func (p endpointPairs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p endpointPairs) Less(i, j int) bool {
+	return strings.Join(p[i], " ") < strings.Join(p[j], " ")
+}
@@ -0,0 +1,223 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type DiffBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeDiffBundleAPI
+}
+
+var _ = gc.Suite(&DiffBundleSuite{})
+
+type fakeDiffBundleAPI struct {
+	status  *api.Status
+	configs map[string]*params.ServiceGetResults
+	closed  bool
+}
+
+func (f *fakeDiffBundleAPI) Status(patterns []string) (*api.Status, error) {
+	return f.status, nil
+}
+
+func (f *fakeDiffBundleAPI) ServiceGet(service string) (*params.ServiceGetResults, error) {
+	config, ok := f.configs[service]
+	if !ok {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+	return config, nil
+}
+
+func (f *fakeDiffBundleAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+const diffBundleYAML = `
+series: trusty
+services:
+    wordpress:
+        charm: cs:wordpress
+        num_units: 2
+        constraints: mem=2G
+        options:
+            blog-title: My Blog
+            tuning: optimized
+    mysql:
+        charm: cs:trusty/mysql-38
+        num_units: 1
+        options:
+            max-connections: 200
+    haproxy:
+        charm: cs:trusty/haproxy-5
+        num_units: 1
+relations:
+    - ["wordpress:db", "mysql"]
+    - ["wordpress", "haproxy"]
+`
+
+func (s *DiffBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeDiffBundleAPI{
+		status: &api.Status{
+			Services: map[string]api.ServiceStatus{
+				"wordpress": {
+					Charm: "cs:trusty/wordpress-7",
+					Units: map[string]api.UnitStatus{"wordpress/0": {}},
+				},
+				"mysql": {
+					Charm: "cs:trusty/mysql-40",
+					Units: map[string]api.UnitStatus{"mysql/0": {}},
+				},
+				"varnish": {
+					Charm: "cs:trusty/varnish-1",
+					Units: map[string]api.UnitStatus{"varnish/0": {}},
+				},
+			},
+			Relations: []api.RelationStatus{{
+				Key: "wordpress:db mysql:server",
+				Endpoints: []api.EndpointStatus{
+					{ServiceName: "wordpress", Name: "db"},
+					{ServiceName: "mysql", Name: "server"},
+				},
+			}, {
+				Key: "wordpress:cache varnish:webcache",
+				Endpoints: []api.EndpointStatus{
+					{ServiceName: "wordpress", Name: "cache"},
+					{ServiceName: "varnish", Name: "webcache"},
+				},
+			}, {
+				Key:       "mysql:cluster",
+				Endpoints: []api.EndpointStatus{{ServiceName: "mysql", Name: "cluster"}},
+			}},
+		},
+		configs: map[string]*params.ServiceGetResults{
+			"wordpress": {
+				Constraints: constraints.MustParse("mem=2G"),
+				Config: map[string]interface{}{
+					"blog-title": map[string]interface{}{"value": "My Blog"},
+					"tuning":     map[string]interface{}{"value": "single", "default": true},
+				},
+			},
+			"mysql": {
+				Config: map[string]interface{}{
+					// Numbers come from the API as float64.
+					"max-connections": map[string]interface{}{"value": float64(200)},
+				},
+			},
+		},
+	}
+	s.PatchValue(&newDiffBundleAPI, func(_ *DiffBundleCommand) (diffBundleAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *DiffBundleSuite) writeBundle(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *DiffBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&DiffBundleCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no bundle specified")
+	err = testing.InitCommand(envcmd.Wrap(&DiffBundleCommand{}), []string{"a.yaml", "b.yaml"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b.yaml"\]`)
+}
+
+func (s *DiffBundleSuite) TestDiff(c *gc.C) {
+	path := s.writeBundle(c, diffBundleYAML)
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.closed, jc.IsTrue)
+	var diff map[string]interface{}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(ctx)), &diff)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(diff, jc.DeepEquals, map[string]interface{}{
+		"services": map[interface{}]interface{}{
+			"wordpress": map[interface{}]interface{}{
+				"num-units": map[interface{}]interface{}{"bundle": 2, "model": 1},
+				"options": map[interface{}]interface{}{
+					"tuning": map[interface{}]interface{}{"bundle": "optimized", "model": "single"},
+				},
+			},
+			"mysql": map[interface{}]interface{}{
+				"charm": map[interface{}]interface{}{"bundle": "cs:trusty/mysql-38", "model": "cs:trusty/mysql-40"},
+			},
+			"haproxy": map[interface{}]interface{}{"missing": "model"},
+			"varnish": map[interface{}]interface{}{"missing": "bundle"},
+		},
+		"relations": map[interface{}]interface{}{
+			"bundle-additions": []interface{}{
+				[]interface{}{"wordpress", "haproxy"},
+			},
+			"model-additions": []interface{}{
+				[]interface{}{"varnish:webcache", "wordpress:cache"},
+			},
+		},
+	})
+}
+
+func (s *DiffBundleSuite) TestNoDifferences(c *gc.C) {
+	path := s.writeBundle(c, `
+services:
+    mysql:
+        charm: cs:mysql
+        num_units: 1
+        options:
+            max-connections: 200
+`)
+	delete(s.api.status.Services, "wordpress")
+	delete(s.api.status.Services, "varnish")
+	s.api.status.Relations = s.api.status.Relations[2:]
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), path, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "{}\n")
+}
+
+func (s *DiffBundleSuite) TestInvalidBundle(c *gc.C) {
+	path := s.writeBundle(c, `
+services:
+    mysql:
+        charm: cs:mysql
+        num_units: 1
+relations:
+    - ["mysql:db", "wordpress:db"]
+`)
+	_, err := testing.RunCommand(c, envcmd.Wrap(&DiffBundleCommand{}), path)
+	c.Assert(err, gc.ErrorMatches, `invalid bundle ".*bundle.yaml": .*`)
+}
+
+func (s *DiffBundleSuite) TestCharmMatches(c *gc.C) {
+	for i, test := range []struct {
+		bundle, series, model string
+		matches               bool
+	}{
+		{"cs:trusty/mysql-38", "", "cs:trusty/mysql-38", true},
+		{"mysql", "", "cs:precise/mysql-40", true},
+		{"mysql", "trusty", "cs:precise/mysql-40", false},
+		{"cs:~bob/mysql", "", "cs:trusty/mysql-1", false},
+		{"cs:trusty/mysql-39", "", "cs:trusty/mysql-38", false},
+		{"local:trusty/mysql", "", "cs:trusty/mysql-38", false},
+	} {
+		c.Logf("test %d: %s, %s", i, test.bundle, test.model)
+		c.Check(charmMatches(test.bundle, test.series, test.model), gc.Equals, test.matches)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/environs/configstore"
 )
 
 // EndpointCommand returns the API endpoints
@@ -16,6 +17,7 @@ type EndpointCommand struct {
 	envcmd.EnvCommandBase
 	out     cmd.Output
 	refresh bool
+	local   bool
 	all     bool
 }
 
@@ -31,6 +33,10 @@ file before returning the first one. Example:
 $ juju api-endpoints
 10.0.3.1:17070
 
+With --local, api-endpoints never connects to the API server. When the
+environment has no cached endpoints, those cached in the client store for its
+controller are used, and an error is returned if there are none.
+
 If --all is given, api-endpoints returns all known endpoints. Example:
 $ juju api-endpoints --all
   10.0.3.1:17070
@@ -55,12 +61,26 @@ func (c *EndpointCommand) Info() *cmd.Info {
 func (c *EndpointCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 	f.BoolVar(&c.refresh, "refresh", false, "connect to the API to ensure an up-to-date endpoint location")
+	f.BoolVar(&c.local, "local", false, "do not connect to the API; use only cached endpoints")
 	f.BoolVar(&c.all, "all", false, "display all known endpoints, not just the first one")
 }
 
+func (c *EndpointCommand) Init(args []string) error {
+	if c.local && c.refresh {
+		return errors.New("--local cannot be used with --refresh")
+	}
+	return cmd.CheckEmpty(args)
+}
+
 // Print out the addresses of the API server endpoints.
 func (c *EndpointCommand) Run(ctx *cmd.Context) error {
-	apiendpoint, err := endpoint(c.EnvCommandBase, c.refresh)
+	var apiendpoint configstore.APIEndpoint
+	var err error
+	if c.local {
+		apiendpoint, err = localEndpoint(c.EnvCommandBase)
+	} else {
+		apiendpoint, err = endpoint(c.EnvCommandBase, c.refresh)
+	}
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
@@ -51,6 +51,23 @@ func (s *EndpointSuite) TestNoEndpoints(c *gc.C) {
 	s.assertCachedAddresses(c)
 }
 
+func (s *EndpointSuite) TestLocalDoesNotConnect(c *gc.C) {
+	s.setCachedAPIAddresses(c)
+	s.assertCachedAddresses(c)
+
+	_, _, err := s.runCommand(c, "--local")
+	c.Assert(err, gc.ErrorMatches, "no API endpoints available")
+
+	// Without --local, the command would have connected and
+	// cached the server's addresses.
+	s.assertCachedAddresses(c)
+}
+
+func (s *EndpointSuite) TestLocalWithRefresh(c *gc.C) {
+	_, _, err := s.runCommand(c, "--local", "--refresh")
+	c.Assert(err, gc.ErrorMatches, "--local cannot be used with --refresh")
+}
+
 func (s *EndpointSuite) TestCachedAddressesUsedIfAvailable(c *gc.C) {
 	addresses := network.NewHostPorts(1234,
 		"10.0.0.1:1234",
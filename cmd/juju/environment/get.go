@@ -11,15 +11,18 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+	"github.com/juju/juju/environs/config"
 )
 
 // GetCommand is able to output either the entire environment or
 // the requested value in a format of the user's choosing.
 type GetCommand struct {
 	envcmd.EnvCommandBase
-	api GetEnvironmentAPI
-	key string
-	out cmd.Output
+	api    GetEnvironmentAPI
+	key    string
+	schema bool
+	out    output.Output
 }
 
 const getEnvHelpDoc = `
@@ -29,9 +32,18 @@ for the environment are output using the selected formatter.
 A single environment value can be output by adding the environment key name to
 the end of the command line.
 
+Keys that hold secret values, such as provider credentials, are only output
+for administrators of the environment.
+
+With --schema, the schema of the environment's configuration is output
+instead: the type and description of each key, and whether it is immutable
+or holds a secret value. The schema of a single key can be output by adding
+the key name to the end of the command line.
+
 Example:
   
   juju environment get default-series  (returns the default series for the environment)
+  juju environment get --schema firewall-mode  (describes the firewall-mode key)
 `
 
 func (c *GetCommand) Info() *cmd.Info {
@@ -44,6 +56,7 @@ func (c *GetCommand) Info() *cmd.Info {
 }
 
 func (c *GetCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.schema, "schema", false, "output the configuration schema rather than the values")
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 }
 
@@ -76,12 +89,29 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 
+	if c.schema {
+		return c.writeSchema(ctx, attrs)
+	}
 	if c.key != "" {
 		if value, found := attrs[c.key]; found {
-			return c.out.Write(ctx, value)
+			return c.out.WriteWithIDs(ctx, value, []string{c.key})
 		}
 		return fmt.Errorf("key %q not found in %q environment.", c.key, attrs["name"])
 	}
 	// If key is empty, write out the whole lot.
 	return c.out.Write(ctx, attrs)
 }
+
+// writeSchema writes out the configuration schema for the environment
+// with the given attributes.
+func (c *GetCommand) writeSchema(ctx *cmd.Context, attrs map[string]interface{}) error {
+	providerType, _ := attrs["type"].(string)
+	schema := config.SchemaFor(providerType)
+	if c.key != "" {
+		if attr, found := schema[c.key]; found {
+			return c.out.WriteWithIDs(ctx, attr, []string{c.key})
+		}
+		return fmt.Errorf("key %q not found in %q environment schema.", c.key, attrs["name"])
+	}
+	return c.out.Write(ctx, schema)
+}
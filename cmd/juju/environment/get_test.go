@@ -4,6 +4,7 @@
 package environment_test
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -73,3 +74,54 @@ func (s *GetSuite) TestAllValuesJSON(c *gc.C) {
 	expected := `{"name":"test-env","running":true,"special":"special value"}`
 	c.Assert(output, gc.Equals, expected)
 }
+
+func (s *GetSuite) TestAllValuesQuiet(c *gc.C) {
+	context, err := s.run(c, "--quiet")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "name\nrunning\nspecial\n")
+}
+
+func (s *GetSuite) TestSchemaSingleKey(c *gc.C) {
+	context, err := s.run(c, "--schema", "firewall-mode")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output := strings.TrimSpace(testing.Stdout(context))
+	expected := "" +
+		"description: How ports are opened on the machines of the environment\n" +
+		"type: string\n" +
+		"immutable: true\n" +
+		"values:\n" +
+		"- instance\n" +
+		"- global\n" +
+		"- none"
+	c.Assert(output, gc.Equals, expected)
+}
+
+func (s *GetSuite) TestSchemaSingleKeyJSON(c *gc.C) {
+	context, err := s.run(c, "--schema", "--format=json", "admin-secret")
+	c.Assert(err, jc.ErrorIsNil)
+
+	output := strings.TrimSpace(testing.Stdout(context))
+	expected := `{"description":"The password for the administrator user","type":"string","secret":true}`
+	c.Assert(output, gc.Equals, expected)
+}
+
+func (s *GetSuite) TestSchemaUnknownKey(c *gc.C) {
+	_, err := s.run(c, "--schema", "special")
+	c.Assert(err, gc.ErrorMatches, `key "special" not found in "test-env" environment schema.`)
+}
+
+func (s *GetSuite) TestSchemaAllKeys(c *gc.C) {
+	context, err := s.run(c, "--schema", "--format=json")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var schema map[string]interface{}
+	err = json.Unmarshal([]byte(testing.Stdout(context)), &schema)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(schema["default-series"], jc.DeepEquals, map[string]interface{}{
+		"description": "The default series of Ubuntu to use for deploying charms",
+		"type":        "string",
+	})
+	c.Assert(schema["name"], gc.NotNil)
+	c.Assert(schema["special"], gc.IsNil)
+}
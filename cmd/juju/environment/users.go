@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
 	"github.com/juju/juju/cmd/juju/user"
 )
 
@@ -23,7 +24,7 @@ const ListCommandDoc = `List all users with access to the current environment`
 // UsersCommand shows all the users with access to the current environment.
 type UsersCommand struct {
 	envcmd.EnvCommandBase
-	out cmd.Output
+	out output.Output
 	api UsersAPI
 }
 
@@ -34,6 +35,11 @@ type UserInfo struct {
 	LastConnection string `yaml:"last-connection" json:"last-connection"`
 }
 
+// ID implements output.Identifier.
+func (u UserInfo) ID() string {
+	return u.Username
+}
+
 // UsersAPI defines the methods on the client API that the
 // users command calls.
 type UsersAPI interface {
@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// exportBundleAPI defines the methods on the client API that the
+// export-bundle command uses.
+type exportBundleAPI interface {
+	ExportBundle(includePlacement bool) (string, error)
+	Close() error
+}
+
+var newExportBundleAPI = func(c *ExportBundleCommand) (exportBundleAPI, error) {
+	return c.NewAPIClient()
+}
+
+// ExportBundleCommand writes the services and relations of an
+// environment as a bundle.
+type ExportBundleCommand struct {
+	envcmd.EnvCommandBase
+	includePlacement bool
+	filename         string
+}
+
+const exportBundleDoc = `
+Export the services and relations of the environment as a bundle, which
+can be deployed to reproduce the environment.
+
+For each service, the bundle holds its charm, number of units,
+constraints, annotations and the configuration options that have been
+set. Options whose names mark them as secret, such as passwords, tokens
+and keys, are left out and must be set again after deployment.
+
+With --include-placement, the bundle also holds the machines that units
+are assigned to, and the placement of the units on them. Units in
+nested containers are placed in a container on the top level machine.
+
+The bundle is written to standard output, or to the file given with
+--filename.
+
+Examples:
+    juju export-bundle
+    juju export-bundle --include-placement --filename bundle.yaml
+
+See Also:
+    juju help diff-bundle
+`
+
+func (c *ExportBundleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "export the environment as a bundle",
+		Doc:     exportBundleDoc,
+	}
+}
+
+func (c *ExportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.includePlacement, "include-placement", false, "include machines and unit placement")
+	f.StringVar(&c.filename, "filename", "", "write the bundle to this file")
+	f.StringVar(&c.filename, "o", "", "")
+}
+
+func (c *ExportBundleCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ExportBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := newExportBundleAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+	bundle, err := client.ExportBundle(c.includePlacement)
+	if err != nil {
+		return errors.Annotate(err, "cannot export bundle")
+	}
+	if c.filename == "" {
+		_, err := fmt.Fprint(ctx.Stdout, bundle)
+		return err
+	}
+	if err := ioutil.WriteFile(ctx.AbsPath(c.filename), []byte(bundle), 0644); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "bundle written to %q\n", c.filename)
+	return nil
+}
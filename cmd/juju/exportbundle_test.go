@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ExportBundleSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeExportBundleAPI
+}
+
+var _ = gc.Suite(&ExportBundleSuite{})
+
+type fakeExportBundleAPI struct {
+	bundle           string
+	err              error
+	includePlacement bool
+	closed           bool
+}
+
+func (f *fakeExportBundleAPI) ExportBundle(includePlacement bool) (string, error) {
+	f.includePlacement = includePlacement
+	return f.bundle, f.err
+}
+
+func (f *fakeExportBundleAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+const exportedBundleYAML = `services:
+  wordpress:
+    charm: cs:trusty/wordpress-3
+    num_units: 1
+`
+
+func (s *ExportBundleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeExportBundleAPI{bundle: exportedBundleYAML}
+	s.PatchValue(&newExportBundleAPI, func(_ *ExportBundleCommand) (exportBundleAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ExportBundleSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ExportBundleCommand{}), []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *ExportBundleSuite) TestExportBundle(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, exportedBundleYAML)
+	c.Assert(s.api.includePlacement, jc.IsFalse)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ExportBundleSuite) TestExportBundleToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "bundle.yaml")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}), "--include-placement", "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(s.api.includePlacement, jc.IsTrue)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, exportedBundleYAML)
+}
+
+func (s *ExportBundleSuite) TestExportBundleError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ExportBundleCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot export bundle: boom")
+}
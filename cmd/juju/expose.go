@@ -7,7 +7,10 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -16,12 +19,25 @@ import (
 type ExposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	Endpoints   []string
+	Ports       []string
+	CIDRs       []string
 }
 
 var jujuExposeHelp = `
 Adjusts firewall rules and similar security mechanisms of the provider, to
 allow the service to be accessed on its public address.
 
+By default every port opened by the service's units is exposed to everyone.
+With --endpoints, only the named charm endpoints are exposed, in addition to
+any endpoints exposed before; --ports restricts those endpoints to some of the
+opened ports, and --cidrs to some source networks. For example:
+
+    juju expose wordpress --endpoints website --ports 443/tcp
+
+exposes only port 443 of wordpress, even if its units open admin ports too.
+Providers that cannot restrict access by source network keep ports limited by
+--cidrs closed.
 `
 
 func (c *ExposeCommand) Info() *cmd.Info {
@@ -33,17 +49,29 @@ func (c *ExposeCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *ExposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.Endpoints), "endpoints", "expose only the listed endpoints")
+	f.Var(cmd.NewStringsValue(nil, &c.Ports), "ports", "expose only the listed port ranges of the endpoints")
+	f.Var(cmd.NewStringsValue(nil, &c.CIDRs), "cidrs", "expose the endpoints only to the listed networks")
+}
+
 func (c *ExposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
 	c.ServiceName = args[0]
+	if len(c.Endpoints) == 0 && (len(c.Ports) > 0 || len(c.CIDRs) > 0) {
+		return errors.New("--ports and --cidrs need --endpoints")
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
 // Run changes the juju-managed firewall to expose any
 // ports that were also explicitly marked by units as open.
 func (c *ExposeCommand) Run(_ *cmd.Context) error {
+	if len(c.Endpoints) > 0 {
+		return c.exposeEndpoints()
+	}
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
@@ -51,3 +79,20 @@ func (c *ExposeCommand) Run(_ *cmd.Context) error {
 	defer client.Close()
 	return block.ProcessBlockedError(client.ServiceExpose(c.ServiceName), block.BlockChange)
 }
+
+func (c *ExposeCommand) exposeEndpoints() error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return err
+	}
+	client := apiservice.NewClient(root)
+	defer client.Close()
+	endpoints := make(map[string]params.ExposedEndpoint)
+	for _, name := range c.Endpoints {
+		endpoints[name] = params.ExposedEndpoint{
+			Ports: c.Ports,
+			CIDRs: c.CIDRs,
+		}
+	}
+	return block.ProcessBlockedError(client.ExposeEndpoints(c.ServiceName, endpoints), block.BlockChange)
+}
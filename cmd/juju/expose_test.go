@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/juju/cmd/envcmd"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testcharms"
 	"github.com/juju/juju/testing"
 )
@@ -55,6 +56,39 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `service "nonexistent-service" not found`)
 }
 
+func (s *ExposeSuite) TestExposeEndpoints(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--endpoints", "juju-info", "--ports", "443/tcp,8000-8080/tcp", "--cidrs", "10.0.0.0/8")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name")
+	svc, err := s.State.Service("some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"juju-info": {
+			Ports: []string{"443/tcp", "8000-8080/tcp"},
+			CIDRs: []string{"10.0.0.0/8"},
+		},
+	})
+
+	// Exposing the whole service drops the endpoint restrictions.
+	err = runExpose(c, "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.ExposedEndpoints(), gc.HasLen, 0)
+
+	err = runExpose(c, "some-service-name", "--endpoints", "admin")
+	c.Assert(err, gc.ErrorMatches, `cannot expose endpoints of service "some-service-name": endpoint "admin" not found`)
+}
+
+func (s *ExposeSuite) TestExposePortsNeedEndpoints(c *gc.C) {
+	err := runExpose(c, "some-service-name", "--ports", "443/tcp")
+	c.Assert(err, gc.ErrorMatches, "--ports and --cidrs need --endpoints")
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
 	err := runDeploy(c, "local:dummy", "some-service-name")
@@ -10,13 +10,14 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 // GetCommand retrieves the configuration of a service.
 type GetCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
-	out         cmd.Output
+	out         output.Output
 }
 
 const getDoc = `
@@ -54,10 +55,7 @@ func (c *GetCommand) Info() *cmd.Info {
 }
 
 func (c *GetCommand) SetFlags(f *gnuflag.FlagSet) {
-	// TODO(dfc) add json formatting ?
-	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
-		"yaml": cmd.FormatYaml,
-	})
+	c.out.AddFlags(f, "yaml", nil)
 }
 
 func (c *GetCommand) Init(args []string) error {
@@ -88,5 +86,10 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 		"charm":    results.Charm,
 		"settings": results.Config,
 	}
-	return c.out.Write(ctx, resultsMap)
+	// With --quiet, only the names of the settings are written.
+	ids, err := output.IDs(results.Config)
+	if err != nil {
+		return err
+	}
+	return c.out.WriteWithIDs(ctx, resultsMap, ids)
 }
@@ -328,7 +328,12 @@ command by using the --constraints flag (for commands that support it).
 Constraints specified on the environment and service will be combined to
 determine the full list of constraints on the machine(s) to be provisioned by
 the command.  Service-specific constraints will override environment-specific
-constraints, which override the juju default constraints.
+constraints, which override the juju default constraints.  Constraints given
+with --constraints to a command that places a machine, such as add-machine,
+override both.  Environment constraints set after a machine was added are
+still used, when it is provisioned, for any constraints it does not specify.
+The constraints that would be used to provision a new machine for a unit,
+and where each comes from, can be viewed with show-constraints --effective.
 
 Constraints are specified as key value pairs separated by an equals sign, with
 multiple constraints delimited by a space.
@@ -0,0 +1,31 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"os"
+
+	"github.com/juju/cmd"
+	"golang.org/x/net/context"
+)
+
+// interruptContext returns a context that is cancelled when the command
+// is interrupted, so that API calls made with it are abandoned and
+// cancelled on the API server. The returned function must be called to
+// stop listening for interrupts once the calls are done.
+func interruptContext(ctx *cmd.Context) (context.Context, func()) {
+	callCtx, cancel := context.WithCancel(context.Background())
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	go func() {
+		if _, ok := <-interrupted; ok {
+			cancel()
+		}
+	}()
+	return callCtx, func() {
+		ctx.StopInterruptNotify(interrupted)
+		close(interrupted)
+		cancel()
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+)
+
+// LogoutCommand ends the login session of the account stored for a
+// controller, and removes the account's credentials from the client
+// store.
+type LogoutCommand struct {
+	cmd.CommandBase
+	controllerName string
+	force          bool
+
+	// store and newAPI are overridden in tests.
+	store  jujuclient.ClientStore
+	newAPI func(jujuclient.ControllerDetails, jujuclient.AccountDetails) (LogoutAPI, error)
+}
+
+// LogoutAPI defines the API methods used by the logout command.
+type LogoutAPI interface {
+	Close() error
+	Logout(token string) error
+}
+
+var logoutDoc = `
+Logs out of a controller, ending the login session of the account
+stored for it so that the session's token can no longer be used, and
+removing the account's password and token from this client. The
+controller stays registered with this client, and the account keeps
+its user name.
+
+Use --force to log out when the controller cannot be reached; the
+credentials are removed from this client, but the session is left to
+expire on the controller.
+
+Examples:
+    juju logout production
+    juju logout --force old-controller
+
+See Also:
+    juju help unregister
+`
+
+func (c *LogoutCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "logout",
+		Args:    "<controller name>",
+		Purpose: "log out of a controller, ending the login session",
+		Doc:     logoutDoc,
+	}
+}
+
+func (c *LogoutCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.force, "force", false, "Remove the credentials from this client even if the session cannot be ended")
+}
+
+func (c *LogoutCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no controller specified")
+	}
+	c.controllerName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *LogoutCommand) getStore() jujuclient.ClientStore {
+	if c.store != nil {
+		return c.store
+	}
+	return jujuclient.NewFileClientStore()
+}
+
+func (c *LogoutCommand) getAPI(controller jujuclient.ControllerDetails, account jujuclient.AccountDetails) (LogoutAPI, error) {
+	if c.newAPI != nil {
+		return c.newAPI(controller, account)
+	}
+	// Only the session token is used to log in: if it has expired
+	// there is no session to end.
+	info := &api.Info{
+		Addrs:        controller.APIEndpoints,
+		CACert:       controller.CACert,
+		Tag:          names.NewUserTag(account.User),
+		SessionToken: account.SessionToken,
+	}
+	st, err := api.Open(info, api.DefaultDialOpts())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return usermanager.NewClient(st), nil
+}
+
+func (c *LogoutCommand) Run(ctx *cmd.Context) error {
+	store := c.getStore()
+	controller, err := store.ControllerByName(c.controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	account, err := store.AccountByName(c.controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if account.SessionToken == "" && account.Password == "" {
+		return errors.Errorf("not logged in to controller %q", c.controllerName)
+	}
+	if account.SessionToken != "" {
+		err := c.endSession(*controller, *account)
+		if params.IsCodeUnauthorized(err) {
+			logger.Debugf("login session for controller %q has already expired", c.controllerName)
+		} else if err != nil && !c.force {
+			return errors.Annotatef(err, "cannot end session with controller %q (use --force to log out of this client only)", c.controllerName)
+		} else if err != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot end session with controller %q: %v\n", c.controllerName, err)
+		}
+	}
+	account.SessionToken = ""
+	account.Password = ""
+	if err := store.UpdateAccount(c.controllerName, *account); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "logged out of controller %q\n", c.controllerName)
+	return nil
+}
+
+// endSession ends the account's login session on the controller.
+func (c *LogoutCommand) endSession(controller jujuclient.ControllerDetails, account jujuclient.AccountDetails) error {
+	client, err := c.getAPI(controller, account)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	return errors.Trace(client.Logout(account.SessionToken))
+}
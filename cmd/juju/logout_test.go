@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type LogoutSuite struct {
+	testing.FakeJujuHomeSuite
+	store jujuclient.ClientStore
+	api   *fakeLogoutAPI
+}
+
+var _ = gc.Suite(&LogoutSuite{})
+
+type fakeLogoutAPI struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakeLogoutAPI) Close() error {
+	f.AddCall("Close")
+	return f.NextErr()
+}
+
+func (f *fakeLogoutAPI) Logout(token string) error {
+	f.AddCall("Logout", token)
+	return f.NextErr()
+}
+
+func (s *LogoutSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "ctrl-uuid",
+		CACert:         testing.CACert,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:         "admin",
+		Password:     "secret",
+		SessionToken: "token",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = &fakeLogoutAPI{}
+}
+
+func (s *LogoutSuite) run(c *gc.C, args ...string) (string, error) {
+	command := &LogoutCommand{
+		store: s.store,
+		newAPI: func(_ jujuclient.ControllerDetails, account jujuclient.AccountDetails) (LogoutAPI, error) {
+			s.api.AddCall("newAPI", account.SessionToken)
+			if err := s.api.NextErr(); err != nil {
+				return nil, err
+			}
+			return s.api, nil
+		},
+	}
+	ctx := testing.Context(c)
+	err := testing.InitCommand(command, args)
+	if err == nil {
+		err = command.Run(ctx)
+	}
+	return testing.Stderr(ctx), err
+}
+
+func (s *LogoutSuite) assertLoggedOut(c *gc.C, loggedOut bool) {
+	account, err := s.store.AccountByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	if loggedOut {
+		c.Assert(*account, jc.DeepEquals, jujuclient.AccountDetails{User: "admin"})
+	} else {
+		c.Assert(account.SessionToken, gc.Equals, "token")
+	}
+}
+
+func (s *LogoutSuite) TestInit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "no controller specified")
+	_, err = s.run(c, "ctrl", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *LogoutSuite) TestLogout(c *gc.C) {
+	stderr, err := s.run(c, "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "logged out of controller \"ctrl\"\n")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"newAPI", []interface{}{"token"}},
+		{"Logout", []interface{}{"token"}},
+		{"Close", nil},
+	})
+	s.assertLoggedOut(c, true)
+}
+
+func (s *LogoutSuite) TestLogoutSessionExpired(c *gc.C) {
+	s.api.SetErrors(&params.Error{Message: "invalid entity name or password", Code: params.CodeUnauthorized})
+	_, err := s.run(c, "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c, "newAPI")
+	s.assertLoggedOut(c, true)
+}
+
+func (s *LogoutSuite) TestLogoutControllerUnreachable(c *gc.C) {
+	s.api.SetErrors(errors.New("connection refused"))
+	_, err := s.run(c, "ctrl")
+	c.Assert(err, gc.ErrorMatches, `cannot end session with controller "ctrl" \(use --force to log out of this client only\): connection refused`)
+	s.assertLoggedOut(c, false)
+}
+
+func (s *LogoutSuite) TestLogoutForce(c *gc.C) {
+	s.api.SetErrors(errors.New("connection refused"))
+	stderr, err := s.run(c, "ctrl", "--force")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, ""+
+		"cannot end session with controller \"ctrl\": connection refused\n"+
+		"logged out of controller \"ctrl\"\n")
+	s.assertLoggedOut(c, true)
+}
+
+func (s *LogoutSuite) TestLogoutPasswordOnly(c *gc.C) {
+	err := s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "admin", Password: "secret"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.run(c, "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c)
+	s.assertLoggedOut(c, true)
+
+	_, err = s.run(c, "ctrl")
+	c.Assert(err, gc.ErrorMatches, `not logged in to controller "ctrl"`)
+}
@@ -68,6 +68,7 @@ Examples:
    juju machine add lxc                  (starts a new machine with an lxc container)
    juju machine add lxc -n 2             (starts 2 new machines with an lxc container)
    juju machine add lxc:4                (starts a new lxc container on machine 4)
+   juju machine add lxc:kvm:4            (starts a new lxc container inside a new kvm container on machine 4)
    juju machine add --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju machine add ssh:user@10.10.0.3   (manually provisions a machine with ssh)
    juju machine add zone=us-east-1a
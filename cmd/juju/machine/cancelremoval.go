@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// CancelRemovalCommand cancels the removal of machines scheduled with
+// "juju machine remove --grace-period".
+type CancelRemovalCommand struct {
+	envcmd.EnvCommandBase
+	api        CancelRemovalAPI
+	MachineIds []string
+}
+
+const cancelRemovalDoc = `
+Cancel the removal of machines that was scheduled with the --grace-period
+flag of "juju machine remove", leaving the machines in the environment.
+A removal can only be cancelled before its grace period has passed.
+
+Examples:
+	# Keep machine 7, whose removal was scheduled by mistake
+	$ juju cancel-removal 7
+
+See Also:
+	juju help machine remove
+`
+
+func (c *CancelRemovalCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "cancel-removal",
+		Args:    "<machine> ...",
+		Purpose: "cancel scheduled removals of machines",
+		Doc:     cancelRemovalDoc,
+	}
+}
+
+func (c *CancelRemovalCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return fmt.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+// CancelRemovalAPI defines the API methods used by the cancel-removal
+// command.
+type CancelRemovalAPI interface {
+	CancelMachineRemovals(machineIds ...string) ([]params.ErrorResult, error)
+	Close() error
+}
+
+func (c *CancelRemovalCommand) getCancelRemovalAPI() (CancelRemovalAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *CancelRemovalCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getCancelRemovalAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	results, err := client.CancelMachineRemovals(c.MachineIds...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	failed := false
+	for i, result := range results {
+		id := c.MachineIds[i]
+		if result.Error != nil {
+			logger.Errorf("cannot cancel removal of machine %s: %v", id, result.Error)
+			failed = true
+			continue
+		}
+		ctx.Infof("removal of machine %s cancelled", id)
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type CancelRemovalSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeCancelRemovalAPI
+}
+
+var _ = gc.Suite(&CancelRemovalSuite{})
+
+func (s *CancelRemovalSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeCancelRemovalAPI{}
+}
+
+func (s *CancelRemovalSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	cancel := machine.NewCancelRemovalCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(cancel), args...)
+}
+
+func (s *CancelRemovalSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args:     []string{"1", "2/lxc/0"},
+			machines: []string{"1", "2/lxc/0"},
+		}, {
+			args:        []string{"lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		cancelCmd := &machine.CancelRemovalCommand{}
+		err := testing.InitCommand(cancelCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(cancelCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *CancelRemovalSuite) TestCancelRemoval(c *gc.C) {
+	s.fake.results = []params.ErrorResult{{}}
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "removal of machine 1 cancelled\n")
+}
+
+func (s *CancelRemovalSuite) TestCancelRemovalFailure(c *gc.C) {
+	s.fake.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "scheduled removal of machine 2 not found", Code: params.CodeNotFound}},
+	}
+	ctx, err := s.run(c, "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "removal of machine 1 cancelled\n")
+	c.Assert(c.GetTestLog(), jc.Contains, "cannot cancel removal of machine 2: scheduled removal of machine 2 not found")
+}
+
+type fakeCancelRemovalAPI struct {
+	machines []string
+	results  []params.ErrorResult
+}
+
+func (f *fakeCancelRemovalAPI) Close() error {
+	return nil
+}
+
+func (f *fakeCancelRemovalAPI) CancelMachineRemovals(machines ...string) ([]params.ErrorResult, error) {
+	f.machines = machines
+	return f.results, nil
+}
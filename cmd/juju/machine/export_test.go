@@ -17,9 +17,25 @@ func NewAddCommand(api AddMachineAPI, mmApi MachineManagerAPI) *AddCommand {
 	}
 }
 
-// NewRemoveCommand returns an RemoveCommand with the api provided as specified.
-func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
+// NewRemoveCommand returns an RemoveCommand with the apis provided as specified.
+func NewRemoveCommand(api RemoveMachineAPI, scheduleAPI ScheduleRemovalAPI) *RemoveCommand {
 	return &RemoveCommand{
+		api:         api,
+		scheduleAPI: scheduleAPI,
+	}
+}
+
+// NewCancelRemovalCommand returns a CancelRemovalCommand with the api
+// provided as specified.
+func NewCancelRemovalCommand(api CancelRemovalAPI) *CancelRemovalCommand {
+	return &CancelRemovalCommand{
+		api: api,
+	}
+}
+
+// NewShowCommand returns a ShowCommand with the api provided as specified.
+func NewShowCommand(api ShowMachineAPI) *ShowCommand {
+	return &ShowCommand{
 		api: api,
 	}
 }
@@ -27,3 +43,19 @@ func NewRemoveCommand(api RemoveMachineAPI) *RemoveCommand {
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
+
+// NewUpgradeSeriesCommand returns an UpgradeSeriesCommand with the api
+// provided as specified.
+func NewUpgradeSeriesCommand(api UpgradeSeriesAPI) *UpgradeSeriesCommand {
+	return &UpgradeSeriesCommand{
+		api: api,
+	}
+}
+
+// NewRebootCommand returns a RebootCommand with the api provided as
+// specified.
+func NewRebootCommand(api RebootAPI) *RebootCommand {
+	return &RebootCommand{
+		api: api,
+	}
+}
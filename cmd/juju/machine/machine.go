@@ -13,7 +13,7 @@ import (
 var logger = loggo.GetLogger("juju.cmd.juju.machine")
 
 const machineCommandDoc = `
-"juju machine" provides commands to add and remove machines in the Juju environment.
+"juju machine" provides commands to add, remove, show, upgrade and reboot machines in the Juju environment.
 `
 
 const machineCommandPurpose = "manage machines"
@@ -29,5 +29,9 @@ func NewSuperCommand() cmd.Command {
 	})
 	machineCmd.Register(envcmd.Wrap(&AddCommand{}))
 	machineCmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	machineCmd.Register(envcmd.Wrap(&CancelRemovalCommand{}))
+	machineCmd.Register(envcmd.Wrap(&ShowCommand{}))
+	machineCmd.Register(envcmd.Wrap(&UpgradeSeriesCommand{}))
+	machineCmd.Register(envcmd.Wrap(&RebootCommand{}))
 	return machineCmd
 }
@@ -21,8 +21,12 @@ var _ = gc.Suite(&MachineCommandSuite{})
 
 var expectedCommmandNames = []string{
 	"add",
+	"cancel-removal",
 	"help",
+	"reboot",
 	"remove",
+	"show",
+	"upgrade-series",
 }
 
 func (s *MachineCommandSuite) TestHelp(c *gc.C) {
@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RebootCommand reboots a machine once the units on it are not
+// running hooks.
+type RebootCommand struct {
+	envcmd.EnvCommandBase
+	api       RebootAPI
+	MachineId string
+	Force     bool
+	Status    bool
+}
+
+const rebootDoc = `
+Reboot a machine without interrupting the hooks running on it. The machine
+agent waits until no hook is running, and stops any more from starting,
+before it reboots the machine. Containers on the machine are shut down
+before it reboots.
+
+A state server machine is not rebooted if the remaining state servers would
+not form a majority; use --force to reboot it anyway.
+
+With --status, the progress of a requested reboot is shown instead:
+    pending    the machine agent has not yet acted on the request
+    draining   the machine agent is waiting for running hooks to finish
+    rebooting  the machine is rebooting
+Nothing is shown if no reboot is pending.
+
+Examples:
+	# Reboot machine 3
+	$ juju machine reboot 3
+
+	# Show the progress of the reboot of machine 3
+	$ juju machine reboot --status 3
+`
+
+func (c *RebootCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "reboot",
+		Args:    "<machine>",
+		Purpose: "reboot a machine once no hooks are running on it",
+		Doc:     rebootDoc,
+	}
+}
+
+func (c *RebootCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Force, "force", false, "reboot even if the environment would lose its majority of state servers")
+	f.BoolVar(&c.Status, "status", false, "show the progress of a requested reboot")
+}
+
+func (c *RebootCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machine specified")
+	}
+	c.MachineId, args = args[0], args[1:]
+	if !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
+	if c.Force && c.Status {
+		return fmt.Errorf("--force cannot be used with --status")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// RebootAPI defines the API methods used by the reboot command.
+type RebootAPI interface {
+	RebootMachine(machineId string, force bool) error
+	RebootStatus(machineId string) (params.RebootStatus, error)
+	Close() error
+}
+
+func (c *RebootCommand) getRebootAPI() (RebootAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *RebootCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getRebootAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if c.Status {
+		status, err := client.RebootStatus(c.MachineId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if status != "" {
+			fmt.Fprintln(ctx.Stdout, status)
+		}
+		return nil
+	}
+	if err := client.RebootMachine(c.MachineId, c.Force); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("machine %s will reboot once no hooks are running on it", c.MachineId)
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type RebootSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeRebootAPI
+}
+
+var _ = gc.Suite(&RebootSuite{})
+
+func (s *RebootSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeRebootAPI{}
+}
+
+func (s *RebootSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	reboot := machine.NewRebootCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(reboot), args...)
+}
+
+func (s *RebootSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machine     string
+		force       bool
+		status      bool
+		errorString string
+	}{
+		{
+			errorString: "no machine specified",
+		}, {
+			args:    []string{"1"},
+			machine: "1",
+		}, {
+			args:    []string{"--force", "0"},
+			machine: "0",
+			force:   true,
+		}, {
+			args:    []string{"--status", "1/lxc/2"},
+			machine: "1/lxc/2",
+			status:  true,
+		}, {
+			args:        []string{"--status", "--force", "1"},
+			errorString: "--force cannot be used with --status",
+		}, {
+			args:        []string{"1", "2"},
+			errorString: `unrecognized args: \["2"\]`,
+		}, {
+			args:        []string{"lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		rebootCmd := &machine.RebootCommand{}
+		err := testing.InitCommand(rebootCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(rebootCmd.MachineId, gc.Equals, test.machine)
+			c.Check(rebootCmd.Force, gc.Equals, test.force)
+			c.Check(rebootCmd.Status, gc.Equals, test.status)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *RebootSuite) TestReboot(c *gc.C) {
+	ctx, err := s.run(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"RebootMachine 1 false"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "machine 1 will reboot once no hooks are running on it\n")
+}
+
+func (s *RebootSuite) TestRebootForce(c *gc.C) {
+	_, err := s.run(c, "--force", "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"RebootMachine 0 true"})
+}
+
+func (s *RebootSuite) TestStatus(c *gc.C) {
+	s.fake.status = params.RebootDraining
+	ctx, err := s.run(c, "--status", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"RebootStatus 1"})
+	c.Assert(testing.Stdout(ctx), gc.Equals, "draining\n")
+}
+
+func (s *RebootSuite) TestStatusNoReboot(c *gc.C) {
+	ctx, err := s.run(c, "--status", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+}
+
+func (s *RebootSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeRebootAPI struct {
+	calls  []string
+	status params.RebootStatus
+	err    error
+}
+
+func (f *fakeRebootAPI) Close() error {
+	return nil
+}
+
+func (f *fakeRebootAPI) RebootMachine(machineId string, force bool) error {
+	f.calls = append(f.calls, fmt.Sprintf("RebootMachine %s %v", machineId, force))
+	return f.err
+}
+
+func (f *fakeRebootAPI) RebootStatus(machineId string) (params.RebootStatus, error) {
+	f.calls = append(f.calls, "RebootStatus "+machineId)
+	return f.status, f.err
+}
@@ -5,11 +5,15 @@ package machine
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -17,9 +21,11 @@ import (
 // RemoveCommand causes an existing machine to be destroyed.
 type RemoveCommand struct {
 	envcmd.EnvCommandBase
-	api        RemoveMachineAPI
-	MachineIds []string
-	Force      bool
+	api         RemoveMachineAPI
+	scheduleAPI ScheduleRemovalAPI
+	MachineIds  []string
+	Force       bool
+	GracePeriod time.Duration
 }
 
 const destroyMachineDoc = `
@@ -28,12 +34,20 @@ running units or containers can only be removed with the --force flag; doing
 so will also remove all those units and containers without giving them any
 opportunity to shut down cleanly.
 
+With --grace-period, the machines are not removed straight away but once the
+given time has passed, so that a removal made by mistake can be undone with
+"juju cancel-removal". A machine that has been given units or containers by
+then is not removed.
+
 Examples:
 	# Remove machine number 5 which has no running units or containers
 	$ juju machine remove 5
 
 	# Remove machine 6 and any running units or containers
 	$ juju machine remove 6 --force
+
+	# Remove machine 7 in 24 hours, unless the removal is cancelled
+	$ juju machine remove 7 --grace-period 24h
 `
 
 func (c *RemoveCommand) Info() *cmd.Info {
@@ -47,6 +61,7 @@ func (c *RemoveCommand) Info() *cmd.Info {
 
 func (c *RemoveCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.Force, "force", false, "completely remove machine and all dependencies")
+	f.DurationVar(&c.GracePeriod, "grace-period", 0, "remove the machines after this long, unless cancelled")
 }
 
 func (c *RemoveCommand) Init(args []string) error {
@@ -58,6 +73,12 @@ func (c *RemoveCommand) Init(args []string) error {
 			return fmt.Errorf("invalid machine id %q", id)
 		}
 	}
+	if c.GracePeriod < 0 {
+		return fmt.Errorf("grace period must be positive")
+	}
+	if c.Force && c.GracePeriod > 0 {
+		return fmt.Errorf("--force cannot be used with --grace-period")
+	}
 	c.MachineIds = args
 	return nil
 }
@@ -75,7 +96,28 @@ func (c *RemoveCommand) getRemoveMachineAPI() (RemoveMachineAPI, error) {
 	return c.NewAPIClient()
 }
 
-func (c *RemoveCommand) Run(_ *cmd.Context) error {
+// ScheduleRemovalAPI defines the API methods used to remove machines
+// after a grace period.
+type ScheduleRemovalAPI interface {
+	ScheduleMachineRemovals(gracePeriod time.Duration, machineIds ...string) ([]params.ScheduledRemovalResult, error)
+	Close() error
+}
+
+func (c *RemoveCommand) getScheduleRemovalAPI() (ScheduleRemovalAPI, error) {
+	if c.scheduleAPI != nil {
+		return c.scheduleAPI, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	if c.GracePeriod > 0 {
+		return c.scheduleRemovals(ctx)
+	}
 	client, err := c.getRemoveMachineAPI()
 	if err != nil {
 		return err
@@ -88,3 +130,32 @@ func (c *RemoveCommand) Run(_ *cmd.Context) error {
 	}
 	return block.ProcessBlockedError(err, block.BlockRemove)
 }
+
+func (c *RemoveCommand) scheduleRemovals(ctx *cmd.Context) error {
+	client, err := c.getScheduleRemovalAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	results, err := client.ScheduleMachineRemovals(c.GracePeriod, c.MachineIds...)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	failed := false
+	for i, result := range results {
+		id := c.MachineIds[i]
+		if result.Error != nil {
+			logger.Errorf("cannot schedule removal of machine %s: %v", id, result.Error)
+			failed = true
+			continue
+		}
+		ctx.Infof(
+			"machine %s will be removed at %s unless cancelled with \"juju cancel-removal %s\"",
+			id, result.Due.Local().Format(time.RFC1123), id,
+		)
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
@@ -5,12 +5,14 @@ package machine_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/machine"
 	"github.com/juju/juju/testing"
@@ -18,7 +20,8 @@ import (
 
 type RemoveMachineSuite struct {
 	testing.FakeJujuHomeSuite
-	fake *fakeRemoveMachineAPI
+	fake         *fakeRemoveMachineAPI
+	fakeSchedule *fakeScheduleRemovalAPI
 }
 
 var _ = gc.Suite(&RemoveMachineSuite{})
@@ -26,10 +29,11 @@ var _ = gc.Suite(&RemoveMachineSuite{})
 func (s *RemoveMachineSuite) SetUpTest(c *gc.C) {
 	s.FakeJujuHomeSuite.SetUpTest(c)
 	s.fake = &fakeRemoveMachineAPI{}
+	s.fakeSchedule = &fakeScheduleRemovalAPI{}
 }
 
 func (s *RemoveMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
-	remove := machine.NewRemoveCommand(s.fake)
+	remove := machine.NewRemoveCommand(s.fake, s.fakeSchedule)
 	return testing.RunCommand(c, envcmd.Wrap(remove), args...)
 }
 
@@ -38,6 +42,7 @@ func (s *RemoveMachineSuite) TestInit(c *gc.C) {
 		args        []string
 		machines    []string
 		force       bool
+		gracePeriod time.Duration
 		errorString string
 	}{
 		{
@@ -62,6 +67,16 @@ func (s *RemoveMachineSuite) TestInit(c *gc.C) {
 		}, {
 			args:     []string{"1/lxc/2"},
 			machines: []string{"1/lxc/2"},
+		}, {
+			args:        []string{"--grace-period", "24h", "1"},
+			machines:    []string{"1"},
+			gracePeriod: 24 * time.Hour,
+		}, {
+			args:        []string{"--grace-period", "-1h", "1"},
+			errorString: "grace period must be positive",
+		}, {
+			args:        []string{"--grace-period", "1h", "--force", "1"},
+			errorString: "--force cannot be used with --grace-period",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -70,6 +85,7 @@ func (s *RemoveMachineSuite) TestInit(c *gc.C) {
 		if test.errorString == "" {
 			c.Check(err, jc.ErrorIsNil)
 			c.Check(removeCmd.Force, gc.Equals, test.force)
+			c.Check(removeCmd.GracePeriod, gc.Equals, test.gracePeriod)
 			c.Check(removeCmd.MachineIds, jc.DeepEquals, test.machines)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errorString)
@@ -111,6 +127,29 @@ func (s *RemoveMachineSuite) TestForceBlockedError(c *gc.C) {
 	c.Assert(stripped, gc.Matches, ".*TestForceBlockedError.*")
 }
 
+func (s *RemoveMachineSuite) TestRemoveGracePeriod(c *gc.C) {
+	s.fakeSchedule.results = []params.ScheduledRemovalResult{
+		{Due: time.Date(2015, 10, 14, 14, 0, 0, 0, time.UTC)},
+		{Error: &params.Error{Message: "boom"}},
+	}
+	ctx, err := s.run(c, "--grace-period", "2h", "1", "2")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(s.fake.machines, gc.HasLen, 0)
+	c.Assert(s.fakeSchedule.gracePeriod, gc.Equals, 2*time.Hour)
+	c.Assert(s.fakeSchedule.machines, jc.DeepEquals, []string{"1", "2"})
+	c.Assert(testing.Stderr(ctx), gc.Matches,
+		`machine 1 will be removed at .* unless cancelled with "juju cancel-removal 1"\n`)
+	c.Assert(c.GetTestLog(), jc.Contains, "cannot schedule removal of machine 2: boom")
+}
+
+func (s *RemoveMachineSuite) TestRemoveGracePeriodBlockedError(c *gc.C) {
+	s.fakeSchedule.err = common.ErrOperationBlocked("TestRemoveGracePeriodBlockedError")
+	_, err := s.run(c, "--grace-period", "2h", "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestRemoveGracePeriodBlockedError.*")
+}
+
 type fakeRemoveMachineAPI struct {
 	forced      bool
 	machines    []string
@@ -132,3 +171,20 @@ func (f *fakeRemoveMachineAPI) ForceDestroyMachines(machines ...string) error {
 	f.machines = machines
 	return f.removeError
 }
+
+type fakeScheduleRemovalAPI struct {
+	gracePeriod time.Duration
+	machines    []string
+	results     []params.ScheduledRemovalResult
+	err         error
+}
+
+func (f *fakeScheduleRemovalAPI) Close() error {
+	return nil
+}
+
+func (f *fakeScheduleRemovalAPI) ScheduleMachineRemovals(gracePeriod time.Duration, machines ...string) ([]params.ScheduledRemovalResult, error) {
+	f.gracePeriod = gracePeriod
+	f.machines = machines
+	return f.results, f.err
+}
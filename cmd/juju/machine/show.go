@@ -0,0 +1,156 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+const showMachineDoc = `
+Show details of one or more machines in the environment.
+
+With --host-keys, the SSH host keys reported by each machine's agent are
+included. These are the keys that "juju ssh" and "juju scp" verify the
+machines against, and can be compared with the fingerprints printed on
+a machine's console.
+
+Examples:
+	# Show machine 0
+	$ juju machine show 0
+
+	# Show machines 1 and 2, with their SSH host keys
+	$ juju machine show 1 2 --host-keys
+`
+
+// ShowCommand shows the details of machines.
+type ShowCommand struct {
+	envcmd.EnvCommandBase
+	api        ShowMachineAPI
+	out        output.Output
+	MachineIds []string
+	HostKeys   bool
+}
+
+// ShowMachineAPI defines the API methods that the show command uses.
+type ShowMachineAPI interface {
+	Status(patterns []string) (*api.Status, error)
+	SSHHostKeys(target string) ([]string, error)
+	Close() error
+}
+
+// MachineDetails holds the details of a machine shown by the show
+// command.
+type MachineDetails struct {
+	InstanceId string   `yaml:"instance-id,omitempty" json:"instance-id,omitempty"`
+	DNSName    string   `yaml:"dns-name,omitempty" json:"dns-name,omitempty"`
+	Series     string   `yaml:"series,omitempty" json:"series,omitempty"`
+	Life       string   `yaml:"life,omitempty" json:"life,omitempty"`
+	HostKeys   []string `yaml:"host-keys,omitempty" json:"host-keys,omitempty"`
+	Err        string   `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<machine> ...",
+		Purpose: "show details of machines in the environment",
+		Doc:     showMachineDoc,
+	}
+}
+
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.HostKeys, "host-keys", false, "include the machines' SSH host keys")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no machines specified")
+	}
+	for _, id := range args {
+		if !names.IsValidMachine(id) {
+			return fmt.Errorf("invalid machine id %q", id)
+		}
+	}
+	c.MachineIds = args
+	return nil
+}
+
+func (c *ShowCommand) getShowMachineAPI() (ShowMachineAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getShowMachineAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	status, err := client.Status(c.MachineIds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result := make(map[string]MachineDetails)
+	for _, id := range c.MachineIds {
+		machine, ok := findMachine(status.Machines, id)
+		if !ok {
+			result[id] = MachineDetails{Err: fmt.Sprintf("machine %s not found", id)}
+			continue
+		}
+		details := MachineDetails{
+			InstanceId: string(machine.InstanceId),
+			DNSName:    machine.DNSName,
+			Series:     machine.Series,
+			Life:       machine.Life,
+		}
+		if machine.Err != nil {
+			details.Err = machine.Err.Error()
+		}
+		if c.HostKeys {
+			keys, err := client.SSHHostKeys(id)
+			if err != nil {
+				details.Err = fmt.Sprintf("cannot get SSH host keys: %v", err)
+			}
+			details.HostKeys = keys
+		}
+		result[id] = details
+	}
+	ids := make([]string, 0, len(result))
+	for id := range result {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return c.out.WriteWithIDs(ctx, map[string]interface{}{"machines": result}, ids)
+}
+
+// findMachine returns the status of the machine with the given id,
+// looking through the containers of each machine.
+func findMachine(machines map[string]api.MachineStatus, id string) (api.MachineStatus, bool) {
+	if machine, ok := machines[id]; ok {
+		return machine, true
+	}
+	for _, machine := range machines {
+		if container, ok := findMachine(machine.Containers, id); ok {
+			return container, true
+		}
+	}
+	return api.MachineStatus{}, false
+}
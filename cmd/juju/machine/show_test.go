@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+type ShowMachineSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeShowMachineAPI
+}
+
+var _ = gc.Suite(&ShowMachineSuite{})
+
+func (s *ShowMachineSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeShowMachineAPI{
+		status: &api.Status{
+			Machines: map[string]api.MachineStatus{
+				"0": {
+					Id:         "0",
+					InstanceId: instance.Id("inst-0"),
+					DNSName:    "10.0.0.1",
+					Series:     "trusty",
+					Life:       "alive",
+					Containers: map[string]api.MachineStatus{
+						"0/lxc/0": {
+							Id:         "0/lxc/0",
+							InstanceId: instance.Id("inst-0-lxc-0"),
+							Series:     "trusty",
+						},
+					},
+				},
+			},
+		},
+		hostKeys: map[string][]string{
+			"0": {"ssh-rsa rsa-0", "ssh-dss dsa-0"},
+		},
+	}
+}
+
+func (s *ShowMachineSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	show := machine.NewShowCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(show), args...)
+}
+
+func (s *ShowMachineSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		machines    []string
+		hostKeys    bool
+		errorString string
+	}{
+		{
+			errorString: "no machines specified",
+		}, {
+			args:     []string{"1"},
+			machines: []string{"1"},
+		}, {
+			args:     []string{"1", "2/lxc/0", "--host-keys"},
+			machines: []string{"1", "2/lxc/0"},
+			hostKeys: true,
+		}, {
+			args:        []string{"lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		showCmd := &machine.ShowCommand{}
+		err := testing.InitCommand(showCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(showCmd.HostKeys, gc.Equals, test.hostKeys)
+			c.Check(showCmd.MachineIds, jc.DeepEquals, test.machines)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *ShowMachineSuite) TestShow(c *gc.C) {
+	ctx, err := s.run(c, "0", "0/lxc/0", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.patterns, jc.DeepEquals, []string{"0", "0/lxc/0", "1"})
+	c.Assert(s.fake.hostKeysCalled, jc.IsFalse)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"machines:\n"+
+		"  \"0\":\n"+
+		"    instance-id: inst-0\n"+
+		"    dns-name: 10.0.0.1\n"+
+		"    series: trusty\n"+
+		"    life: alive\n"+
+		"  0/lxc/0:\n"+
+		"    instance-id: inst-0-lxc-0\n"+
+		"    series: trusty\n"+
+		"  \"1\":\n"+
+		"    error: machine 1 not found\n",
+	)
+}
+
+func (s *ShowMachineSuite) TestShowHostKeys(c *gc.C) {
+	ctx, err := s.run(c, "0", "--host-keys", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.hostKeysCalled, jc.IsTrue)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `{"machines":{"0":{"instance-id":"inst-0","dns-name":"10.0.0.1","series":"trusty","life":"alive","host-keys":["ssh-rsa rsa-0","ssh-dss dsa-0"]}}}`+"\n")
+}
+
+func (s *ShowMachineSuite) TestShowHostKeysError(c *gc.C) {
+	ctx, err := s.run(c, "0/lxc/0", "--host-keys")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"machines:\n"+
+		"  0/lxc/0:\n"+
+		"    instance-id: inst-0-lxc-0\n"+
+		"    series: trusty\n"+
+		"    error: 'cannot get SSH host keys: SSH host keys for machine 0/lxc/0 not found'\n",
+	)
+}
+
+func (s *ShowMachineSuite) TestStatusError(c *gc.C) {
+	s.fake.err = errors.New("boom")
+	_, err := s.run(c, "0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeShowMachineAPI struct {
+	status         *api.Status
+	hostKeys       map[string][]string
+	patterns       []string
+	hostKeysCalled bool
+	err            error
+}
+
+func (f *fakeShowMachineAPI) Close() error {
+	return nil
+}
+
+func (f *fakeShowMachineAPI) Status(patterns []string) (*api.Status, error) {
+	f.patterns = patterns
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.status, nil
+}
+
+func (f *fakeShowMachineAPI) SSHHostKeys(target string) ([]string, error) {
+	f.hostKeysCalled = true
+	keys, ok := f.hostKeys[target]
+	if !ok {
+		return nil, errors.NotFoundf("SSH host keys for machine %s", target)
+	}
+	return keys, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const (
+	// PrepareCommand is the upgrade-series subcommand that starts an
+	// upgrade.
+	PrepareCommand = "prepare"
+
+	// CompleteCommand is the upgrade-series subcommand that finishes
+	// an upgrade.
+	CompleteCommand = "complete"
+)
+
+// UpgradeSeriesCommand upgrades the series of a machine in place.
+type UpgradeSeriesCommand struct {
+	envcmd.EnvCommandBase
+	api       UpgradeSeriesAPI
+	Command   string
+	MachineId string
+	Series    string
+}
+
+const upgradeSeriesDoc = `
+Upgrade the operating system of a machine in place, without redeploying the
+units it hosts. This happens in two steps.
+
+"prepare" asks every unit on the machine to run its pre-series-upgrade hook,
+so that charms can stop their workloads and ready themselves for the
+upgrade. Once they have done so, the operator upgrades the operating system,
+with do-release-upgrade for example.
+
+"complete" records that the operating system has been upgraded, and asks
+every unit on the machine to run its post-series-upgrade hook, so that
+charms can adapt to the new series and restart their workloads. It fails
+if any unit has not yet run its pre-series-upgrade hook.
+
+Examples:
+	# Prepare machine 3 for an upgrade to trusty
+	$ juju machine upgrade-series prepare 3 trusty
+
+	# Complete the upgrade once the operating system is upgraded
+	$ juju machine upgrade-series complete 3
+`
+
+func (c *UpgradeSeriesCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "upgrade-series",
+		Args:    "prepare <machine> <series> | complete <machine>",
+		Purpose: "upgrade the operating system of a machine in place",
+		Doc:     upgradeSeriesDoc,
+	}
+}
+
+func (c *UpgradeSeriesCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no upgrade-series command specified")
+	}
+	c.Command, args = args[0], args[1:]
+	switch c.Command {
+	case PrepareCommand:
+		if len(args) < 2 {
+			return fmt.Errorf("prepare requires a machine and a series")
+		}
+		c.MachineId, c.Series, args = args[0], args[1], args[2:]
+	case CompleteCommand:
+		if len(args) < 1 {
+			return fmt.Errorf("complete requires a machine")
+		}
+		c.MachineId, args = args[0], args[1:]
+	default:
+		return fmt.Errorf("unknown upgrade-series command %q: expected %q or %q", c.Command, PrepareCommand, CompleteCommand)
+	}
+	if !names.IsValidMachine(c.MachineId) {
+		return fmt.Errorf("invalid machine id %q", c.MachineId)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// UpgradeSeriesAPI defines the API methods used by the upgrade-series
+// command.
+type UpgradeSeriesAPI interface {
+	PrepareSeriesUpgrade(machineId, series string) error
+	CompleteSeriesUpgrade(machineId string) error
+	BestAPIVersion() int
+	Close() error
+}
+
+func (c *UpgradeSeriesCommand) getUpgradeSeriesAPI() (UpgradeSeriesAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+func (c *UpgradeSeriesCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getUpgradeSeriesAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if client.BestAPIVersion() < 1 {
+		return errors.New("cannot upgrade machine series: not supported by the API server")
+	}
+	switch c.Command {
+	case PrepareCommand:
+		err = client.PrepareSeriesUpgrade(c.MachineId, c.Series)
+	case CompleteCommand:
+		err = client.CompleteSeriesUpgrade(c.MachineId)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if c.Command == PrepareCommand {
+		ctx.Infof("preparing machine %s for upgrade to %s", c.MachineId, c.Series)
+	} else {
+		ctx.Infof("completing upgrade of machine %s", c.MachineId)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeUpgradeSeriesAPI
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeUpgradeSeriesAPI{version: 1}
+}
+
+func (s *UpgradeSeriesSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	upgradeSeries := machine.NewUpgradeSeriesCommand(s.fake)
+	return testing.RunCommand(c, envcmd.Wrap(upgradeSeries), args...)
+}
+
+func (s *UpgradeSeriesSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		command     string
+		machine     string
+		series      string
+		errorString string
+	}{
+		{
+			errorString: "no upgrade-series command specified",
+		}, {
+			args:    []string{"prepare", "1", "trusty"},
+			command: "prepare",
+			machine: "1",
+			series:  "trusty",
+		}, {
+			args:    []string{"complete", "1/lxc/2"},
+			command: "complete",
+			machine: "1/lxc/2",
+		}, {
+			args:        []string{"prepare", "1"},
+			errorString: "prepare requires a machine and a series",
+		}, {
+			args:        []string{"complete"},
+			errorString: "complete requires a machine",
+		}, {
+			args:        []string{"complete", "1", "trusty"},
+			errorString: `unrecognized args: \["trusty"\]`,
+		}, {
+			args:        []string{"finish", "1"},
+			errorString: `unknown upgrade-series command "finish": expected "prepare" or "complete"`,
+		}, {
+			args:        []string{"complete", "lxc"},
+			errorString: `invalid machine id "lxc"`,
+		},
+	} {
+		c.Logf("test %d", i)
+		upgradeSeriesCmd := &machine.UpgradeSeriesCommand{}
+		err := testing.InitCommand(upgradeSeriesCmd, test.args)
+		if test.errorString == "" {
+			c.Check(err, jc.ErrorIsNil)
+			c.Check(upgradeSeriesCmd.Command, gc.Equals, test.command)
+			c.Check(upgradeSeriesCmd.MachineId, gc.Equals, test.machine)
+			c.Check(upgradeSeriesCmd.Series, gc.Equals, test.series)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errorString)
+		}
+	}
+}
+
+func (s *UpgradeSeriesSuite) TestPrepare(c *gc.C) {
+	ctx, err := s.run(c, "prepare", "1", "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"PrepareSeriesUpgrade 1 trusty"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "preparing machine 1 for upgrade to trusty\n")
+}
+
+func (s *UpgradeSeriesSuite) TestComplete(c *gc.C) {
+	ctx, err := s.run(c, "complete", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.calls, jc.DeepEquals, []string{"CompleteSeriesUpgrade 1"})
+	c.Assert(testing.Stderr(ctx), gc.Equals, "completing upgrade of machine 1\n")
+}
+
+func (s *UpgradeSeriesSuite) TestUnsupportedAPIServer(c *gc.C) {
+	s.fake.version = 0
+	_, err := s.run(c, "prepare", "1", "trusty")
+	c.Assert(err, gc.ErrorMatches, "cannot upgrade machine series: not supported by the API server")
+	c.Assert(s.fake.calls, gc.HasLen, 0)
+}
+
+func (s *UpgradeSeriesSuite) TestBlockedError(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlockedError")
+	_, err := s.run(c, "prepare", "1", "trusty")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Assert(stripped, gc.Matches, ".*TestBlockedError.*")
+}
+
+type fakeUpgradeSeriesAPI struct {
+	version int
+	calls   []string
+	err     error
+}
+
+func (f *fakeUpgradeSeriesAPI) Close() error {
+	return nil
+}
+
+func (f *fakeUpgradeSeriesAPI) BestAPIVersion() int {
+	return f.version
+}
+
+func (f *fakeUpgradeSeriesAPI) PrepareSeriesUpgrade(machineId, series string) error {
+	f.calls = append(f.calls, "PrepareSeriesUpgrade "+machineId+" "+series)
+	return f.err
+}
+
+func (f *fakeUpgradeSeriesAPI) CompleteSeriesUpgrade(machineId string) error {
+	f.calls = append(f.calls, "CompleteSeriesUpgrade "+machineId)
+	return f.err
+}
@@ -23,9 +23,12 @@ import (
 	"github.com/juju/juju/cmd/juju/service"
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/cmd/juju/webhook"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
 	"github.com/juju/juju/version"
@@ -60,6 +63,7 @@ func Main(args []string) {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err)
 		os.Exit(2)
 	}
+	migrateLocalStore()
 	for i := range x {
 		x[i] ^= 255
 	}
@@ -71,6 +75,19 @@ func Main(args []string) {
 	os.Exit(cmd.Main(jcmd, ctx, args[1:]))
 }
 
+// migrateLocalStore performs the one-time migration of legacy
+// environment information into the client store. Failure to migrate
+// must not prevent juju from running, so errors are only logged.
+func migrateLocalStore() {
+	legacy, err := configstore.Default()
+	if err == nil {
+		err = jujuclient.MigrateLegacyStoreOnce(legacy, jujuclient.NewFileClientStore())
+	}
+	if err != nil {
+		logger.Warningf("cannot migrate local store: %v", err)
+	}
+}
+
 func NewJujuCommand(ctx *cmd.Context) cmd.Command {
 	jcmd := jujucmd.NewSuperCommand(cmd.SuperCommandParams{
 		Name:            "juju",
@@ -117,7 +134,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Creation commands.
 	r.Register(wrapEnvCommand(&BootstrapCommand{}))
 	r.Register(wrapEnvCommand(&DeployCommand{}))
+	r.Register(wrapEnvCommand(&ValidateCharmCommand{}))
 	r.Register(wrapEnvCommand(&AddRelationCommand{}))
+	r.Register(wrapEnvCommand(&AdoptInstanceCommand{}))
 
 	// Destruction commands.
 	r.Register(wrapEnvCommand(&RemoveRelationCommand{}))
@@ -128,28 +147,45 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Reporting commands.
 	r.Register(wrapEnvCommand(&StatusCommand{}))
 	r.Register(&SwitchCommand{})
+	r.Register(&RefreshModelsCommand{})
+	r.Register(&UnregisterCommand{})
+	r.Register(&LogoutCommand{})
+	r.Register(wrapEnvCommand(&ShareModelCommand{}))
+	r.Register(wrapEnvCommand(&AcceptInviteCommand{}))
+	r.Register(wrapEnvCommand(&TransferModelCommand{}))
+	r.Register(wrapEnvCommand(&ExportUsersCommand{}))
+	r.Register(wrapEnvCommand(&ImportUsersCommand{}))
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
 	r.Register(wrapEnvCommand(&StatusHistoryCommand{}))
+	r.Register(wrapEnvCommand(&DiffBundleCommand{}))
+	r.Register(wrapEnvCommand(&ExportBundleCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
 	r.Register(wrapEnvCommand(&SCPCommand{}))
 	r.Register(wrapEnvCommand(&SSHCommand{}))
 	r.Register(wrapEnvCommand(&ResolvedCommand{}))
+	r.Register(wrapEnvCommand(&PauseUnitCommand{}))
+	r.Register(wrapEnvCommand(&ResumeUnitCommand{}))
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
+	r.Register(&MigrateLocalStoreCommand{})
+	r.Register(&CompletionCommand{})
+	r.Register(&PluginsCommand{})
 	r.RegisterDeprecated(wrapEnvCommand(&common.GetConstraintsCommand{}),
 		twoDotOhDeprecation("environment get-constraints or service get-constraints"))
 	r.RegisterDeprecated(wrapEnvCommand(&common.SetConstraintsCommand{}),
 		twoDotOhDeprecation("environment set-constraints or service set-constraints"))
+	r.Register(wrapEnvCommand(&common.ShowConstraintsCommand{}))
 	r.Register(wrapEnvCommand(&ExposeCommand{}))
 	r.Register(wrapEnvCommand(&SyncToolsCommand{}))
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
+	r.Register(wrapEnvCommand(&ShowUpgradeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeCharmCommand{}))
 
 	// Charm publishing commands.
@@ -166,6 +202,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage users and access
 	r.Register(user.NewSuperCommand())
+	r.RegisterSuperAlias("enable-2fa", "user", "enable-2fa", nil)
+	r.RegisterSuperAlias("disable-2fa", "user", "disable-2fa", nil)
 
 	// Manage cached images
 	r.Register(cachedimages.NewSuperCommand())
@@ -176,6 +214,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterSuperAlias("remove-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("destroy-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
 	r.RegisterSuperAlias("terminate-machine", "machine", "remove", twoDotOhDeprecation("machine remove"))
+	r.RegisterSuperAlias("show-machine", "machine", "show", twoDotOhDeprecation("machine show"))
+	r.RegisterSuperAlias("cancel-removal", "machine", "cancel-removal", nil)
 
 	// Mangage environment
 	r.Register(environment.NewSuperCommand())
@@ -193,12 +233,27 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage state server availability
 	r.Register(wrapEnvCommand(&EnsureAvailabilityCommand{}))
 
+	// Inspect agents
+	r.Register(wrapEnvCommand(&ShowAgentCommand{}))
+
+	// Check the state database
+	r.Register(wrapEnvCommand(&CheckStateCommand{}))
+
+	// Report the API usage of each environment
+	r.Register(wrapEnvCommand(&ControllerUsageCommand{}))
+
+	// Describe the API for client developers
+	r.Register(wrapEnvCommand(&APISchemaCommand{}))
+
 	// Manage and control services
 	r.Register(service.NewSuperCommand())
 	r.RegisterSuperAlias("add-unit", "service", "add-unit", twoDotOhDeprecation("service add-unit"))
+	r.RegisterSuperAlias("scale-service", "service", "scale", nil)
 	r.RegisterSuperAlias("get", "service", "get", twoDotOhDeprecation("service get"))
 	r.RegisterSuperAlias("set", "service", "set", twoDotOhDeprecation("service set"))
 	r.RegisterSuperAlias("unset", "service", "unset", twoDotOhDeprecation("service unset"))
+	r.RegisterSuperAlias("get-config", "service", "get", nil)
+	r.RegisterSuperAlias("set-config", "service", "set", nil)
 
 	// Operation protection commands
 	r.Register(block.NewSuperBlockCommand())
@@ -206,6 +261,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Manage storage
 	r.Register(storage.NewSuperCommand())
+
+	// Manage notification webhooks
+	r.Register(webhook.NewSuperCommand())
 }
 
 // envCmdWrapper is a struct that wraps an environment command and lets us handle
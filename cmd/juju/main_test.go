@@ -188,18 +188,25 @@ func (s *MainSuite) TestActualRunJujuArgOrder(c *gc.C) {
 }
 
 var commandNames = []string{
+	"accept-invite",
 	"action",
 	"add-machine",
 	"add-relation",
 	"add-unit",
+	"adopt-instance",
 	"api-endpoints",
 	"api-info",
+	"api-schema",
 	"authorised-keys", // alias for authorized-keys
 	"authorized-keys",
 	"backups",
 	"block",
 	"bootstrap",
 	"cached-images",
+	"cancel-removal",
+	"check-state",
+	"completion",
+	"controller-usage",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -208,33 +215,53 @@ var commandNames = []string{
 	"destroy-relation",
 	"destroy-service",
 	"destroy-unit",
+	"diff-bundle",
+	"disable-2fa",
+	"enable-2fa",
 	"ensure-availability",
 	"env", // alias for switch
 	"environment",
+	"export-bundle",
+	"export-users",
 	"expose",
 	"generate-config", // alias for init
 	"get",
+	"get-config", // alias for service get
 	"get-constraints",
 	"get-env", // alias for get-environment
 	"get-environment",
 	"help",
 	"help-tool",
+	"import-users",
 	"init",
+	"logout",
 	"machine",
+	"migrate-local-store",
+	"pause-unit",
+	"plugins",
 	"publish",
+	"refresh-models",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
 	"remove-service",  // alias for destroy-service
 	"remove-unit",     // alias for destroy-unit
 	"resolved",
+	"resume-unit",
 	"retry-provisioning",
 	"run",
+	"scale-service",
 	"scp",
 	"service",
 	"set",
+	"set-config", // alias for service set
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"share-model",
+	"show-agent",
+	"show-constraints",
+	"show-machine",
+	"show-upgrade",
 	"ssh",
 	"stat", // alias for status
 	"status",
@@ -243,15 +270,19 @@ var commandNames = []string{
 	"switch",
 	"sync-tools",
 	"terminate-machine", // alias for destroy-machine
+	"transfer-model",
 	"unblock",
 	"unexpose",
+	"unregister",
 	"unset",
 	"unset-env", // alias for unset-environment
 	"unset-environment",
 	"upgrade-charm",
 	"upgrade-juju",
 	"user",
+	"validate-charm",
 	"version",
+	"webhook",
 }
 
 func (s *MainSuite) TestHelpCommands(c *gc.C) {
@@ -475,6 +506,7 @@ var obsoleteCommandNames = []string{
 	"set-constraints",
 	"set-env",
 	"set-environment",
+	"show-machine",
 	"terminate-machine",
 	"unset-env",
 	"unset-environment",
@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/jujuclient"
+)
+
+// MigrateLocalStoreCommand converts the legacy environment information
+// held in $JUJU_HOME/environments into the client store.
+type MigrateLocalStoreCommand struct {
+	cmd.CommandBase
+	DryRun bool
+
+	// legacyStore and store are overridden in tests.
+	legacyStore func() (configstore.Storage, error)
+	store       jujuclient.ClientStore
+}
+
+var migrateLocalStoreDoc = `
+Converts the environment information recorded in .jenv files and the
+environments cache file into the controllers, models and accounts files
+of the client store.
+
+The migration is run automatically the first time juju is run, but may
+be run again to pick up environments added with older versions of juju.
+Existing client store entries with the same names are overwritten.
+
+Use --dry-run to show the changes that would be made without making them.
+`
+
+func (c *MigrateLocalStoreCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "migrate-local-store",
+		Purpose: "convert legacy environment information into the client store",
+		Doc:     migrateLocalStoreDoc,
+	}
+}
+
+func (c *MigrateLocalStoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.DryRun, "dry-run", false, "show the changes without making them")
+}
+
+func (c *MigrateLocalStoreCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *MigrateLocalStoreCommand) Run(ctx *cmd.Context) error {
+	legacyStore := c.legacyStore
+	if legacyStore == nil {
+		legacyStore = configstore.Default
+	}
+	legacy, err := legacyStore()
+	if err != nil {
+		return errors.Annotate(err, "cannot open legacy environment store")
+	}
+	m, err := jujuclient.NewMigration(legacy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var skipped []string
+	for name := range m.Skipped {
+		skipped = append(skipped, name)
+	}
+	sort.Strings(skipped)
+	for _, name := range skipped {
+		fmt.Fprintf(ctx.Stderr, "skipping environment %q: %s\n", name, m.Skipped[name])
+	}
+
+	steps := m.Steps()
+	if len(steps) == 0 {
+		ctx.Infof("nothing to migrate")
+		return nil
+	}
+	prefix := ""
+	if c.DryRun {
+		prefix = "would "
+	}
+	for _, step := range steps {
+		fmt.Fprintf(ctx.Stdout, "%s%s\n", prefix, step)
+	}
+	if c.DryRun {
+		return nil
+	}
+
+	store := c.store
+	if store == nil {
+		store = jujuclient.NewFileClientStore()
+	}
+	if err := m.Apply(store); err != nil {
+		return errors.Trace(err)
+	}
+	return jujuclient.MarkLegacyStoreMigrated()
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type MigrateLocalStoreSuite struct {
+	testing.FakeJujuHomeSuite
+	legacy configstore.Storage
+	store  jujuclient.ClientStore
+}
+
+var _ = gc.Suite(&MigrateLocalStoreSuite{})
+
+func (s *MigrateLocalStoreSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.legacy = configstore.NewMem()
+	s.store = jujuclient.NewMemStore()
+
+	info := s.legacy.CreateInfo("local")
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"10.0.0.1:17070"},
+		CACert:      testing.CACert,
+		EnvironUUID: "env-uuid",
+		ServerUUID:  "env-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{User: "admin", Password: "secret"})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.legacy.CreateInfo("fresh").Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *MigrateLocalStoreSuite) run(c *gc.C, args ...string) (string, string, error) {
+	command := &MigrateLocalStoreCommand{
+		legacyStore: func() (configstore.Storage, error) {
+			return s.legacy, nil
+		},
+		store: s.store,
+	}
+	ctx, err := testing.RunCommand(c, command, args...)
+	return testing.Stdout(ctx), testing.Stderr(ctx), err
+}
+
+func (s *MigrateLocalStoreSuite) TestInit(c *gc.C) {
+	_, _, err := s.run(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *MigrateLocalStoreSuite) TestDryRun(c *gc.C) {
+	stdout, stderr, err := s.run(c, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		"would add account \"admin\" for controller \"local\"\n"+
+		"would add controller \"local\" (env-uuid)\n"+
+		"would add model \"local\" (env-uuid) to controller \"local\"\n")
+	c.Assert(stderr, gc.Equals, "skipping environment \"fresh\": environment has not been bootstrapped\n")
+
+	_, err = s.store.ControllerByName("local")
+	c.Assert(err, gc.ErrorMatches, "controller local not found")
+}
+
+func (s *MigrateLocalStoreSuite) TestMigrate(c *gc.C) {
+	stdout, _, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		"add account \"admin\" for controller \"local\"\n"+
+		"add controller \"local\" (env-uuid)\n"+
+		"add model \"local\" (env-uuid) to controller \"local\"\n")
+
+	details, err := s.store.ControllerByName("local")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.ControllerUUID, gc.Equals, "env-uuid")
+	account, err := s.store.AccountByName("local")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*account, jc.DeepEquals, jujuclient.AccountDetails{User: "admin", Password: "secret"})
+}
@@ -0,0 +1,299 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	goyaml "gopkg.in/yaml.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/systemmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// migrateUsersAPI defines the methods on the system manager API that
+// the export-users and import-users commands use.
+type migrateUsersAPI interface {
+	ExportEnvironmentUsers(env names.EnvironTag) (params.MigratedUsers, error)
+	ImportEnvironmentUsers(env names.EnvironTag, users []params.MigratedUser, userMap map[string]string) ([]params.UserImportResult, error)
+	Close() error
+}
+
+var newMigrateUsersAPI = func(c *envcmd.EnvCommandBase) (migrateUsersAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return systemmanager.NewClient(root), nil
+}
+
+// currentEnvironTag returns the tag of the environment the command
+// connects to.
+func currentEnvironTag(c *envcmd.EnvCommandBase) (names.EnvironTag, error) {
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return names.EnvironTag{}, errors.Trace(err)
+	}
+	if endpoint.EnvironUUID == "" {
+		return names.EnvironTag{}, errors.New("environment UUID not known")
+	}
+	return names.NewEnvironTag(endpoint.EnvironUUID), nil
+}
+
+// ExportUsersCommand writes the users with access to the current
+// environment to a file, to be imported into another system.
+type ExportUsersCommand struct {
+	envcmd.EnvCommandBase
+	filename string
+}
+
+const exportUsersDoc = `
+Export the users with access to the current environment, so that their
+access can be recreated with "juju import-users" when the environment is
+moved to another Juju system.
+
+Local users are exported with their display names, but not their
+credentials: users created on the other system by "juju import-users"
+have no password until one is set for them.
+
+Only the owner of the Juju system can export users. The users are
+written as YAML to standard output, or to the file given with
+--filename.
+
+Examples:
+    juju export-users -e production --filename users.yaml
+
+See Also:
+    juju help import-users
+`
+
+func (c *ExportUsersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-users",
+		Purpose: "export the users with access to the environment",
+		Doc:     exportUsersDoc,
+	}
+}
+
+func (c *ExportUsersCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.filename, "filename", "", "write the users to this file")
+	f.StringVar(&c.filename, "o", "", "")
+}
+
+func (c *ExportUsersCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *ExportUsersCommand) Run(ctx *cmd.Context) error {
+	envTag, err := currentEnvironTag(&c.EnvCommandBase)
+	if err != nil {
+		return errors.Annotate(err, "cannot export users")
+	}
+	client, err := newMigrateUsersAPI(&c.EnvCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	users, err := client.ExportEnvironmentUsers(envTag)
+	if err != nil {
+		return errors.Annotate(err, "cannot export users")
+	}
+	data, err := goyaml.Marshal(newMigratedUsersFile(users))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.filename == "" {
+		_, err := ctx.Stdout.Write(data)
+		return err
+	}
+	// The file lists who may access the environment, so only its
+	// owner may read it.
+	if err := ioutil.WriteFile(ctx.AbsPath(c.filename), data, 0600); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "%d users written to %q\n", len(users.Users), c.filename)
+	return nil
+}
+
+// ImportUsersCommand gives users exported from another system access
+// to the current environment.
+type ImportUsersCommand struct {
+	envcmd.EnvCommandBase
+	filename string
+	userMap  userMapValue
+}
+
+const importUsersDoc = `
+Give the users exported with "juju export-users" from another Juju
+system access to the current environment, typically once the environment
+has been moved to this system.
+
+External users are given access as they are. A local user is created on
+this system, without a password, if no user has its name. As passwords
+are not exported, a user that already exists with the same name is not
+assumed to be the same person; use --map to import a user as an existing
+user of this system, under the same name or another:
+
+    --map alice=alice --map bob=robert
+
+Users that cannot be imported are reported, and the others are imported
+regardless. Only the owner of the Juju system can import users.
+
+Examples:
+    juju import-users -e production users.yaml
+    juju import-users users.yaml --map alice=alice.smith --map bob=robert
+
+See Also:
+    juju help export-users
+`
+
+func (c *ImportUsersCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "import-users",
+		Args:    "<users file>",
+		Purpose: "give exported users access to the environment",
+		Doc:     importUsersDoc,
+	}
+}
+
+func (c *ImportUsersCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(&c.userMap, "map", "import an exported user as an existing user, as <exported>=<existing>")
+}
+
+func (c *ImportUsersCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no users file specified")
+	}
+	c.filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *ImportUsersCommand) Run(ctx *cmd.Context) error {
+	data, err := ioutil.ReadFile(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var file migratedUsersFile
+	if err := goyaml.Unmarshal(data, &file); err != nil {
+		return errors.Annotatef(err, "cannot read users from %q", c.filename)
+	}
+	users, err := file.users()
+	if err != nil {
+		return errors.Annotatef(err, "cannot read users from %q", c.filename)
+	}
+	envTag, err := currentEnvironTag(&c.EnvCommandBase)
+	if err != nil {
+		return errors.Annotate(err, "cannot import users")
+	}
+	client, err := newMigrateUsersAPI(&c.EnvCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	results, err := client.ImportEnvironmentUsers(envTag, users, c.userMap)
+	if err != nil {
+		return errors.Annotate(err, "cannot import users")
+	}
+	conflicts := 0
+	for _, result := range results {
+		switch {
+		case result.Conflict != "":
+			fmt.Fprintf(ctx.Stderr, "%s: not imported: %s\n", result.UserName, result.Conflict)
+			conflicts++
+		case result.Created:
+			fmt.Fprintf(ctx.Stdout, "%s: created as %s\n", result.UserName, result.Target)
+		default:
+			fmt.Fprintf(ctx.Stdout, "%s: imported as %s\n", result.UserName, result.Target)
+		}
+	}
+	if conflicts > 0 {
+		return errors.Errorf("%d of %d users not imported", conflicts, len(results))
+	}
+	return nil
+}
+
+// migratedUsersFile holds the users written by export-users and read
+// by import-users.
+type migratedUsersFile struct {
+	EnvironTag string             `yaml:"environ-tag"`
+	Users      []migratedUserFile `yaml:"users"`
+}
+
+type migratedUserFile struct {
+	UserName    string `yaml:"user-name"`
+	DisplayName string `yaml:"display-name,omitempty"`
+	CreatedBy   string `yaml:"created-by"`
+	DateCreated string `yaml:"date-created"`
+	Disabled    bool   `yaml:"disabled,omitempty"`
+}
+
+func newMigratedUsersFile(users params.MigratedUsers) migratedUsersFile {
+	file := migratedUsersFile{
+		EnvironTag: users.EnvironTag,
+		Users:      make([]migratedUserFile, len(users.Users)),
+	}
+	for i, user := range users.Users {
+		file.Users[i] = migratedUserFile{
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			CreatedBy:   user.CreatedBy,
+			DateCreated: user.DateCreated.UTC().Format(time.RFC3339),
+			Disabled:    user.Disabled,
+		}
+	}
+	return file
+}
+
+func (file migratedUsersFile) users() ([]params.MigratedUser, error) {
+	users := make([]params.MigratedUser, len(file.Users))
+	for i, user := range file.Users {
+		created, err := time.Parse(time.RFC3339, user.DateCreated)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid creation date for user %q", user.UserName)
+		}
+		users[i] = params.MigratedUser{
+			UserName:    user.UserName,
+			DisplayName: user.DisplayName,
+			CreatedBy:   user.CreatedBy,
+			DateCreated: created,
+			Disabled:    user.Disabled,
+		}
+	}
+	return users, nil
+}
+
+// userMapValue implements gnuflag.Value for the --map flag of
+// import-users, mapping the canonical names of exported users to the
+// names of existing users.
+type userMapValue map[string]string
+
+func (v *userMapValue) Set(s string) error {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || !names.IsValidUser(parts[0]) || !names.IsValidUser(parts[1]) {
+		return errors.Errorf("expected <exported user>=<existing user>, got %q", s)
+	}
+	if *v == nil {
+		*v = make(userMapValue)
+	}
+	(*v)[names.NewUserTag(parts[0]).Username()] = parts[1]
+	return nil
+}
+
+func (v *userMapValue) String() string {
+	var pairs []string
+	for from, to := range *v {
+		pairs = append(pairs, from+"="+to)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
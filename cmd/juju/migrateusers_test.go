@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type MigrateUsersSuite struct {
+	jujutesting.JujuConnSuite
+	api *fakeMigrateUsersAPI
+}
+
+var _ = gc.Suite(&MigrateUsersSuite{})
+
+type fakeMigrateUsersAPI struct {
+	envTag   names.EnvironTag
+	exported params.MigratedUsers
+	imported []params.MigratedUser
+	userMap  map[string]string
+	results  []params.UserImportResult
+	err      error
+	closed   bool
+}
+
+func (f *fakeMigrateUsersAPI) ExportEnvironmentUsers(env names.EnvironTag) (params.MigratedUsers, error) {
+	f.envTag = env
+	return f.exported, f.err
+}
+
+func (f *fakeMigrateUsersAPI) ImportEnvironmentUsers(env names.EnvironTag, users []params.MigratedUser, userMap map[string]string) ([]params.UserImportResult, error) {
+	f.envTag = env
+	f.imported = users
+	f.userMap = userMap
+	return f.results, f.err
+}
+
+func (f *fakeMigrateUsersAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+var migratedUsers = params.MigratedUsers{
+	EnvironTag: "environment-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	Users: []params.MigratedUser{{
+		UserName:    "alice@local",
+		DisplayName: "Alice",
+		CreatedBy:   "admin@local",
+		DateCreated: time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC),
+	}, {
+		UserName:    "fred@external",
+		CreatedBy:   "alice@local",
+		DateCreated: time.Date(2015, 6, 2, 12, 0, 0, 0, time.UTC),
+	}},
+}
+
+func (s *MigrateUsersSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.api = &fakeMigrateUsersAPI{exported: migratedUsers}
+	s.PatchValue(&newMigrateUsersAPI, func(*envcmd.EnvCommandBase) (migrateUsersAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *MigrateUsersSuite) TestExportUsers(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "users.yaml")
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ExportUsersCommand{}), "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "2 users written to \""+path+"\"\n")
+	c.Assert(s.api.envTag, gc.Equals, names.NewEnvironTag(s.State.EnvironUUID()))
+	c.Assert(s.api.closed, jc.IsTrue)
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	// The exported file can be imported as it is.
+	s.api.results = []params.UserImportResult{
+		{UserName: "alice@local", Target: "alice@local", Created: true},
+		{UserName: "fred@external", Target: "fred@external"},
+	}
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(&ImportUsersCommand{}), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.imported, jc.DeepEquals, migratedUsers.Users)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"alice@local: created as alice@local\n"+
+		"fred@external: imported as fred@external\n")
+}
+
+func (s *MigrateUsersSuite) TestExportUsersError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ExportUsersCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot export users: boom")
+}
+
+func (s *MigrateUsersSuite) TestImportUsersInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ImportUsersCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no users file specified")
+	err = testing.InitCommand(envcmd.Wrap(&ImportUsersCommand{}), []string{"users.yaml", "--map", "alice"})
+	c.Assert(err, gc.ErrorMatches, `invalid value "alice" for flag --map: expected <exported user>=<existing user>, got "alice"`)
+}
+
+func (s *MigrateUsersSuite) TestImportUsersConflicts(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "users.yaml")
+	err := ioutil.WriteFile(path, []byte(`
+environ-tag: environment-deadbeef-0bad-400d-8000-4b1d0d06f00d
+users:
+- user-name: alice@local
+  created-by: admin@local
+  date-created: "2015-06-01T12:00:00Z"
+- user-name: bob@local
+  created-by: admin@local
+  date-created: "2015-06-01T12:00:00Z"
+`), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.results = []params.UserImportResult{
+		{UserName: "alice@local", Target: "alison@local"},
+		{UserName: "bob@local", Target: "bob@local", Conflict: `user "bob@local" already exists`},
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ImportUsersCommand{}), path, "--map", "alice=alison")
+	c.Assert(err, gc.ErrorMatches, "1 of 2 users not imported")
+	c.Assert(s.api.userMap, jc.DeepEquals, map[string]string{"alice@local": "alison"})
+	c.Assert(s.api.imported, gc.HasLen, 2)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "alice@local: imported as alison@local\n")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "bob@local: not imported: user \"bob@local\" already exists\n")
+}
@@ -0,0 +1,233 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/errors"
+)
+
+// FormatJSONLines marshals value as JSON lines: each element of a slice
+// is written as JSON on a line of its own, and each entry of a map as a
+// single-entry object, in key order. Other values take a single line.
+func FormatJSONLines(value interface{}) ([]byte, error) {
+	var lines [][]byte
+	add := func(item interface{}) error {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		lines = append(lines, line)
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := add(v.Index(i).Interface()); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Map:
+		entries := mapEntries(v)
+		for _, key := range entries.keys {
+			if err := add(map[string]interface{}{key: entries.values[key].Interface()}); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := add(value); err != nil {
+			return nil, err
+		}
+	}
+	return bytes.Join(lines, []byte("\n")), nil
+}
+
+// FormatTabular writes value as a table with a row for each element of
+// a slice, or for each entry of a map in key order, headed by the key.
+// The columns of a struct element are its fields, named after their
+// yaml tags; scalar elements have a single VALUE column. Nested values
+// are written as compact JSON.
+func FormatTabular(value interface{}) ([]byte, error) {
+	var ids []string
+	var rows []reflect.Value
+	v := indirect(reflect.ValueOf(value))
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			rows = append(rows, v.Index(i))
+		}
+	case reflect.Map:
+		entries := mapEntries(v)
+		ids = entries.keys
+		for _, key := range entries.keys {
+			rows = append(rows, entries.values[key])
+		}
+	default:
+		rows = []reflect.Value{v}
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := tableColumns(rows)
+
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	var header []string
+	if ids != nil {
+		header = append(header, "ID")
+	}
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(strings.Replace(column.name, "-", " ", -1)))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for i, row := range rows {
+		var cells []string
+		if ids != nil {
+			cells = append(cells, ids[i])
+		}
+		for _, column := range columns {
+			cell, err := formatCell(column.value(row))
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
+
+type tableColumn struct {
+	name  string
+	value func(row reflect.Value) reflect.Value
+}
+
+// tableColumns returns the columns of a table of the given rows, taken
+// from the first row: the exported fields of a struct, the keys of a
+// map, or a single column for a scalar.
+func tableColumns(rows []reflect.Value) []tableColumn {
+	first := indirect(rows[0])
+	switch first.Kind() {
+	case reflect.Struct:
+		var columns []tableColumn
+		t := first.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			index := i
+			columns = append(columns, tableColumn{
+				name: name,
+				value: func(row reflect.Value) reflect.Value {
+					row = indirect(row)
+					if row.Kind() != reflect.Struct {
+						return reflect.Value{}
+					}
+					return row.Field(index)
+				},
+			})
+		}
+		return columns
+	case reflect.Map:
+		var columns []tableColumn
+		for _, key := range mapEntries(first).keys {
+			key := key
+			columns = append(columns, tableColumn{
+				name: key,
+				value: func(row reflect.Value) reflect.Value {
+					row = indirect(row)
+					if row.Kind() != reflect.Map {
+						return reflect.Value{}
+					}
+					return mapEntries(row).values[key]
+				},
+			})
+		}
+		return columns
+	}
+	return []tableColumn{{
+		name:  "value",
+		value: func(row reflect.Value) reflect.Value { return row },
+	}}
+}
+
+// formatCell returns the text of a table cell holding v.
+func formatCell(v reflect.Value) (string, error) {
+	v = indirect(v)
+	if !v.IsValid() {
+		return "", nil
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface()), nil
+	case reflect.Slice, reflect.Array:
+		var items []string
+		for i := 0; i < v.Len(); i++ {
+			item := indirect(v.Index(i))
+			if item.Kind() == reflect.Struct || item.Kind() == reflect.Map || item.Kind() == reflect.Slice {
+				items = nil
+				break
+			}
+			items = append(items, fmt.Sprint(item.Interface()))
+		}
+		if items != nil || v.Len() == 0 {
+			return strings.Join(items, ","), nil
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
+
+// indirect follows pointers and interfaces to the value they refer to,
+// returning the zero Value for nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+type entries struct {
+	keys   []string
+	values map[string]reflect.Value
+}
+
+// mapEntries returns the entries of the map v, keyed by the text of
+// their keys, with the keys in order.
+func mapEntries(v reflect.Value) entries {
+	e := entries{values: make(map[string]reflect.Value, v.Len())}
+	for _, key := range v.MapKeys() {
+		name := fmt.Sprint(key.Interface())
+		e.keys = append(e.keys, name)
+		e.values[name] = v.MapIndex(key)
+	}
+	sort.Strings(e.keys)
+	return e
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package output provides the output formats shared by the commands
+// that list and show entities, so that every such command supports the
+// same --format values and --quiet.
+package output
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// DefaultFormatters returns the formatters supported by every list and
+// show command. Commands with a tabular format of their own replace the
+// generic one.
+func DefaultFormatters() map[string]cmd.Formatter {
+	return map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"jsonl":   FormatJSONLines,
+		"tabular": FormatTabular,
+	}
+}
+
+// Output holds the --format and --quiet flags of a command that lists
+// or shows entities.
+type Output struct {
+	cmd.Output
+	quiet bool
+}
+
+// AddFlags adds the --format flag, offering the default formatters as
+// well as the given ones, which take precedence, and the --quiet flag.
+func (o *Output) AddFlags(f *gnuflag.FlagSet, defaultFormatter string, formatters map[string]cmd.Formatter) {
+	all := DefaultFormatters()
+	for name, formatter := range formatters {
+		all[name] = formatter
+	}
+	o.Output.AddFlags(f, defaultFormatter, all)
+	f.BoolVar(&o.quiet, "quiet", false, "print only the ids of the entities, one per line")
+}
+
+// Quiet reports whether --quiet was given.
+func (o *Output) Quiet() bool {
+	return o.quiet
+}
+
+// Write writes the given value in the chosen format or, with --quiet,
+// writes only the ids of the entities in it, as returned by IDs.
+func (o *Output) Write(ctx *cmd.Context, value interface{}) error {
+	if !o.quiet {
+		return o.Output.Write(ctx, value)
+	}
+	ids, err := IDs(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return o.WriteWithIDs(ctx, value, ids)
+}
+
+// WriteWithIDs is like Write, but with --quiet writes the given ids,
+// for values whose entities cannot be identified by IDs, such as maps
+// of entities grouped by their owners.
+func (o *Output) WriteWithIDs(ctx *cmd.Context, value interface{}, ids []string) error {
+	if !o.quiet {
+		return o.Output.Write(ctx, value)
+	}
+	for _, id := range ids {
+		fmt.Fprintln(ctx.Stdout, id)
+	}
+	return nil
+}
+
+// Identifier is implemented by the values describing entities, so that
+// --quiet can print their ids.
+type Identifier interface {
+	ID() string
+}
+
+// IDs returns the ids of the entities described by value. A value that
+// implements Identifier has its own id; a map is keyed by id; a slice
+// holds Identifiers or the ids themselves.
+func IDs(value interface{}) ([]string, error) {
+	if id, ok := value.(Identifier); ok {
+		return []string{id.ID()}, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map:
+		return mapEntries(v).keys, nil
+	case reflect.Slice, reflect.Array:
+		ids := make([]string, v.Len())
+		for i := range ids {
+			switch elem := v.Index(i).Interface().(type) {
+			case Identifier:
+				ids[i] = elem.ID()
+			case string:
+				ids[i] = elem
+			default:
+				return nil, errors.Errorf("--quiet is not supported for %T", value)
+			}
+		}
+		return ids, nil
+	case reflect.String:
+		return []string{v.String()}, nil
+	}
+	return nil, errors.Errorf("--quiet is not supported for %T", value)
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package output_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/output"
+	"github.com/juju/juju/testing"
+)
+
+type OutputSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&OutputSuite{})
+
+type entity struct {
+	Name   string   `yaml:"name" json:"name"`
+	Count  int      `yaml:"unit-count" json:"unit-count"`
+	Tags   []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	hidden string
+}
+
+func (e entity) ID() string {
+	return e.Name
+}
+
+var entities = []entity{
+	{Name: "mysql", Count: 1},
+	{Name: "wordpress", Count: 2, Tags: []string{"web", "php"}},
+}
+
+func (s *OutputSuite) write(c *gc.C, value interface{}, args ...string) (string, error) {
+	var out output.Output
+	f := gnuflag.NewFlagSet("test", gnuflag.ContinueOnError)
+	out.AddFlags(f, "yaml", nil)
+	err := f.Parse(true, args)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	err = out.Write(ctx, value)
+	return testing.Stdout(ctx), err
+}
+
+func (s *OutputSuite) TestFormats(c *gc.C) {
+	for i, test := range []struct {
+		format string
+		output string
+	}{{
+		format: "yaml",
+		output: "" +
+			"- name: mysql\n" +
+			"  unit-count: 1\n" +
+			"- name: wordpress\n" +
+			"  unit-count: 2\n" +
+			"  tags:\n" +
+			"  - web\n" +
+			"  - php\n",
+	}, {
+		format: "json",
+		output: `[{"name":"mysql","unit-count":1},{"name":"wordpress","unit-count":2,"tags":["web","php"]}]` + "\n",
+	}, {
+		format: "jsonl",
+		output: "" +
+			`{"name":"mysql","unit-count":1}` + "\n" +
+			`{"name":"wordpress","unit-count":2,"tags":["web","php"]}` + "\n",
+	}, {
+		format: "tabular",
+		output: "" +
+			"NAME       UNIT COUNT  TAGS\n" +
+			"mysql      1           \n" +
+			"wordpress  2           web,php\n",
+	}} {
+		c.Logf("test %d: %s", i, test.format)
+		stdout, err := s.write(c, entities, "--format", test.format)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(stdout, gc.Equals, test.output)
+	}
+}
+
+func (s *OutputSuite) TestMapFormats(c *gc.C) {
+	value := map[string]entity{
+		"b": {Name: "wordpress", Count: 2},
+		"a": {Name: "mysql", Count: 1},
+	}
+	stdout, err := s.write(c, value, "--format", "jsonl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		`{"a":{"name":"mysql","unit-count":1}}`+"\n"+
+		`{"b":{"name":"wordpress","unit-count":2}}`+"\n")
+
+	stdout, err = s.write(c, value, "--format", "tabular")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		"ID  NAME       UNIT COUNT  TAGS\n"+
+		"a   mysql      1           \n"+
+		"b   wordpress  2           \n")
+}
+
+func (s *OutputSuite) TestQuiet(c *gc.C) {
+	for i, test := range []struct {
+		value  interface{}
+		output string
+	}{{
+		value:  entities,
+		output: "mysql\nwordpress\n",
+	}, {
+		value:  entities[0],
+		output: "mysql\n",
+	}, {
+		value:  map[string]int{"b": 2, "a": 1},
+		output: "a\nb\n",
+	}, {
+		value:  []string{"0", "1/lxc/0"},
+		output: "0\n1/lxc/0\n",
+	}} {
+		c.Logf("test %d", i)
+		stdout, err := s.write(c, test.value, "--quiet", "--format", "json")
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(stdout, gc.Equals, test.output)
+	}
+}
+
+func (s *OutputSuite) TestWriteWithIDs(c *gc.C) {
+	var out output.Output
+	f := gnuflag.NewFlagSet("test", gnuflag.ContinueOnError)
+	out.AddFlags(f, "yaml", nil)
+	err := f.Parse(true, []string{"--quiet"})
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	err = out.WriteWithIDs(ctx, map[string][]int{"a": {1, 2}}, []string{"1", "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "1\n2\n")
+}
+
+func (s *OutputSuite) TestQuietUnsupported(c *gc.C) {
+	_, err := s.write(c, []int{1, 2}, "--quiet")
+	c.Assert(err, gc.ErrorMatches, `--quiet is not supported for \[\]int`)
+}
+
+func (s *OutputSuite) TestFormattersOverride(c *gc.C) {
+	var out output.Output
+	f := gnuflag.NewFlagSet("test", gnuflag.ContinueOnError)
+	out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": func(interface{}) ([]byte, error) { return []byte("custom"), nil },
+	})
+	ctx := testing.Context(c)
+	err := out.Write(ctx, entities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "custom\n")
+}
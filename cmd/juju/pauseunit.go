@@ -0,0 +1,145 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// pauseUnitAPI defines the methods on the client API that the
+// pause-unit and resume-unit commands use.
+type pauseUnitAPI interface {
+	PauseUnits(units ...names.UnitTag) ([]params.ErrorResult, error)
+	ResumeUnits(units ...names.UnitTag) ([]params.ErrorResult, error)
+	Close() error
+}
+
+var newPauseUnitAPI = func(c *unitsCommandBase) (pauseUnitAPI, error) {
+	return c.NewAPIClient()
+}
+
+// unitsCommandBase holds the units named on the command line of the
+// pause-unit and resume-unit commands.
+type unitsCommandBase struct {
+	envcmd.EnvCommandBase
+	Units []names.UnitTag
+}
+
+func (c *unitsCommandBase) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit specified")
+	}
+	c.Units = make([]names.UnitTag, len(args))
+	for i, arg := range args {
+		if !names.IsValidUnit(arg) {
+			return errors.Errorf("invalid unit name %q", arg)
+		}
+		c.Units[i] = names.NewUnitTag(arg)
+	}
+	return nil
+}
+
+func (c *unitsCommandBase) run(ctx *cmd.Context, call func(pauseUnitAPI) ([]params.ErrorResult, error)) error {
+	client, err := newPauseUnitAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	results, err := call(client)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	failed := false
+	for i, result := range results {
+		if result.Error != nil {
+			fmt.Fprintf(ctx.Stderr, "%s: %v\n", c.Units[i].Id(), result.Error)
+			failed = true
+		}
+	}
+	if failed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+// PauseUnitCommand pauses units for maintenance.
+type PauseUnitCommand struct {
+	unitsCommandBase
+}
+
+const pauseUnitDoc = `
+Pause one or more units, so that maintenance can be done on them without
+the charm reacting to the environment.
+
+While a unit is paused, its agent runs no update-status or
+collect-metrics hooks, and changes to the unit's relations are held back
+and handled only once the unit is resumed. Configuration changes,
+actions and commands run with "juju run" are still processed. Paused
+units are marked as such in the output of "juju status".
+
+The charm is not told that the unit is paused: to stop the unit's
+workload, use an action the charm provides for it.
+
+Examples:
+    juju pause-unit mysql/0
+    juju pause-unit mysql/0 mysql/1
+
+See Also:
+    juju help resume-unit
+`
+
+func (c *PauseUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "pause-unit",
+		Args:    "<unit> [...]",
+		Purpose: "pause periodic hooks and relation changes for units",
+		Doc:     pauseUnitDoc,
+	}
+}
+
+func (c *PauseUnitCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, func(client pauseUnitAPI) ([]params.ErrorResult, error) {
+		return client.PauseUnits(c.Units...)
+	})
+}
+
+// ResumeUnitCommand resumes paused units.
+type ResumeUnitCommand struct {
+	unitsCommandBase
+}
+
+const resumeUnitDoc = `
+Resume one or more paused units. Relation changes held back while the
+units were paused are handled, and periodic hooks are run again.
+
+Examples:
+    juju resume-unit mysql/0
+
+See Also:
+    juju help pause-unit
+`
+
+func (c *ResumeUnitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "resume-unit",
+		Args:    "<unit> [...]",
+		Purpose: "resume paused units",
+		Doc:     resumeUnitDoc,
+	}
+}
+
+func (c *ResumeUnitCommand) Run(ctx *cmd.Context) error {
+	return c.run(ctx, func(client pauseUnitAPI) ([]params.ErrorResult, error) {
+		return client.ResumeUnits(c.Units...)
+	})
+}
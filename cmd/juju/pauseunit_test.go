@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type PauseUnitSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakePauseUnitAPI
+}
+
+var _ = gc.Suite(&PauseUnitSuite{})
+
+type fakePauseUnitAPI struct {
+	paused  []names.UnitTag
+	resumed []names.UnitTag
+	results []params.ErrorResult
+	err     error
+	closed  bool
+}
+
+func (f *fakePauseUnitAPI) PauseUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	f.paused = append(f.paused, units...)
+	return f.result(units)
+}
+
+func (f *fakePauseUnitAPI) ResumeUnits(units ...names.UnitTag) ([]params.ErrorResult, error) {
+	f.resumed = append(f.resumed, units...)
+	return f.result(units)
+}
+
+func (f *fakePauseUnitAPI) result(units []names.UnitTag) ([]params.ErrorResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.results != nil {
+		return f.results, nil
+	}
+	return make([]params.ErrorResult, len(units)), nil
+}
+
+func (f *fakePauseUnitAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *PauseUnitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakePauseUnitAPI{}
+	s.PatchValue(&newPauseUnitAPI, func(_ *unitsCommandBase) (pauseUnitAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *PauseUnitSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&PauseUnitCommand{}), nil)
+	c.Assert(err, gc.ErrorMatches, "no unit specified")
+	err = testing.InitCommand(envcmd.Wrap(&ResumeUnitCommand{}), []string{"mysql/0", "mysql"})
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "mysql"`)
+}
+
+func (s *PauseUnitSuite) TestPauseUnit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&PauseUnitCommand{}), "mysql/0", "mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.paused, jc.DeepEquals, []names.UnitTag{
+		names.NewUnitTag("mysql/0"), names.NewUnitTag("mysql/1"),
+	})
+	c.Assert(s.api.resumed, gc.HasLen, 0)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *PauseUnitSuite) TestResumeUnit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ResumeUnitCommand{}), "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.resumed, jc.DeepEquals, []names.UnitTag{names.NewUnitTag("mysql/0")})
+	c.Assert(s.api.paused, gc.HasLen, 0)
+}
+
+func (s *PauseUnitSuite) TestPauseUnitErrors(c *gc.C) {
+	s.api.results = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: `unit "mysql/1" not found`, Code: params.CodeNotFound}},
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&PauseUnitCommand{}), "mysql/0", "mysql/1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "mysql/1: unit \"mysql/1\" not found\n")
+}
+
+func (s *PauseUnitSuite) TestPauseUnitBlocked(c *gc.C) {
+	s.api.err = common.ErrOperationBlocked("TestPauseUnitBlocked")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&PauseUnitCommand{}), "mysql/0")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,16 +14,149 @@ import (
 	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
 )
 
 const JujuPluginPrefix = "juju-"
 
+// PluginAPIVersion is the version of the plugin API supported by this
+// juju: the metadata printed by plugins run with --metadata, and the
+// environment that plugins declaring it are run with.
+const PluginAPIVersion = 1
+
+// PluginMetadata is printed, as YAML or JSON, by a plugin run with
+// --metadata. Plugins that print no metadata are run as before, with
+// all of juju's environment.
+type PluginMetadata struct {
+	// Description is a one line description of the plugin.
+	Description string `yaml:"description" json:"description"`
+
+	// APIVersion is the version of the plugin API that the plugin
+	// requires.
+	APIVersion int `yaml:"api-version" json:"api-version"`
+
+	// Environment holds the names of the environment variables that
+	// the plugin needs, beyond those passed to every plugin.
+	Environment []string `yaml:"environment,omitempty" json:"environment,omitempty"`
+}
+
+// pluginProbeTimeout is how long a plugin may take to print its
+// metadata or description when plugins are listed.
+var pluginProbeTimeout = 5 * time.Second
+
+// runPluginProbe runs the plugin with the given flag, killing it if it
+// takes longer than pluginProbeTimeout, and returns its output. If
+// combined is true, the output includes the plugin's standard error.
+func runPluginProbe(plugin, flag string, combined bool) ([]byte, error) {
+	var output bytes.Buffer
+	command := exec.Command(plugin, flag)
+	command.Stdout = &output
+	if combined {
+		command.Stderr = &output
+	}
+	if err := command.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Wait()
+	}()
+	select {
+	case err := <-done:
+		return output.Bytes(), err
+	case <-time.After(pluginProbeTimeout):
+		command.Process.Kill()
+		<-done
+		return nil, errors.Errorf("timed out after %v", pluginProbeTimeout)
+	}
+}
+
+// pluginMetadata runs the plugin with --metadata, returning nil if the
+// plugin does not declare any metadata.
+func pluginMetadata(plugin string) (*PluginMetadata, error) {
+	output, err := runPluginProbe(plugin, "--metadata", false)
+	if _, ok := err.(*exec.ExitError); ok {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var metadata PluginMetadata
+	if err := goyaml.Unmarshal(output, &metadata); err != nil || metadata.APIVersion == 0 {
+		return nil, nil
+	}
+	return &metadata, nil
+}
+
+// pluginMetadataCacheFile is the file in the juju home directory that
+// records the metadata declared by plugins when they were last listed,
+// so that plugins are not run with --metadata each time they are
+// invoked.
+const pluginMetadataCacheFile = "plugins.yaml"
+
+// cachedPluginMetadata records the metadata declared by a plugin, and
+// the modification time and size of the plugin when it declared it.
+type cachedPluginMetadata struct {
+	ModTime  time.Time       `yaml:"mod-time"`
+	Size     int64           `yaml:"size"`
+	Metadata *PluginMetadata `yaml:"metadata,omitempty"`
+}
+
+// readPluginMetadataCache returns the cached metadata of plugins, keyed
+// by their paths.
+func readPluginMetadataCache() (map[string]cachedPluginMetadata, error) {
+	data, err := ioutil.ReadFile(osenv.JujuHomePath(pluginMetadataCacheFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var cache map[string]cachedPluginMetadata
+	if err := goyaml.Unmarshal(data, &cache); err != nil {
+		return nil, errors.Annotate(err, "cannot parse plugin metadata cache")
+	}
+	return cache, nil
+}
+
+func writePluginMetadataCache(cache map[string]cachedPluginMetadata) error {
+	data, err := goyaml.Marshal(cache)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(utils.AtomicWriteFile(osenv.JujuHomePath(pluginMetadataCacheFile), data, 0600))
+}
+
+// cachedMetadata returns the metadata recorded for the plugin at the
+// given path when plugins were last listed, or nil if none was, or if
+// the plugin has changed since.
+func cachedMetadata(path string) (*PluginMetadata, error) {
+	cache, err := readPluginMetadataCache()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cached, ok := cache[path]
+	if !ok {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !info.ModTime().Equal(cached.ModTime) || info.Size() != cached.Size {
+		return nil, nil
+	}
+	return cached.Metadata, nil
+}
+
 // This is a very rudimentary method used to extract common Juju
 // arguments from the full list passed to the plugin. Currently,
 // there is only one such argument: -e env
@@ -90,18 +224,39 @@ func (c *PluginCommand) Init(args []string) error {
 }
 
 func (c *PluginCommand) Run(ctx *cmd.Context) error {
-	command := exec.Command(c.name, c.args...)
-	command.Env = append(os.Environ(), []string{
-		osenv.JujuHomeEnvKey + "=" + osenv.JujuHome(),
-		osenv.JujuEnvEnvKey + "=" + c.ConnectionName()}...,
-	)
+	path, err := exec.LookPath(c.name)
+	if err != nil {
+		return err
+	}
+	// Plugins are only run with --metadata when they are listed, so
+	// a plugin that has not been listed since it changed is run as a
+	// plugin without metadata.
+	metadata, err := cachedMetadata(path)
+	if err != nil {
+		logger.Warningf("cannot read metadata of plugin %q: %v", c.name, err)
+	}
+	command := exec.Command(path, c.args...)
+	if metadata == nil {
+		command.Env = append(os.Environ(), []string{
+			osenv.JujuHomeEnvKey + "=" + osenv.JujuHome(),
+			osenv.JujuEnvEnvKey + "=" + c.ConnectionName()}...,
+		)
+	} else {
+		if metadata.APIVersion > PluginAPIVersion {
+			return errors.Errorf("plugin %q requires plugin API version %d, but this juju supports version %d",
+				strings.TrimPrefix(c.name, JujuPluginPrefix), metadata.APIVersion, PluginAPIVersion)
+		}
+		if command.Env, err = c.pluginEnvironment(metadata); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	// Now hook up stdin, stdout, stderr
 	command.Stdin = ctx.Stdin
 	command.Stdout = ctx.Stdout
 	command.Stderr = ctx.Stderr
 	// And run it!
-	err := command.Run()
+	err = command.Run()
 
 	if exitError, ok := err.(*exec.ExitError); ok && exitError != nil {
 		status := exitError.ProcessState.Sys().(syscall.WaitStatus)
@@ -112,9 +267,110 @@ func (c *PluginCommand) Run(ctx *cmd.Context) error {
 	return err
 }
 
-type PluginDescription struct {
-	name        string
-	description string
+// pluginEnvironmentKeys holds the names of the environment variables
+// passed to every plugin that declares metadata.
+var pluginEnvironmentKeys = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "LANG", "LC_ALL", "TMPDIR", "SSH_AUTH_SOCK",
+	"SYSTEMROOT", "USERPROFILE", "APPDATA", "TEMP", "TMP", "PATHEXT", "COMSPEC",
+	osenv.JujuLoggingConfigEnvKey, osenv.JujuFeatureFlagEnvKey,
+}
+
+// pluginEnvironment returns the environment that a plugin declaring the
+// given metadata is run with: the variables set for juju that any
+// program needs or that the plugin asks for, and the juju variables,
+// including the details of the environment in JUJU_CONTEXT.
+func (c *PluginCommand) pluginEnvironment(metadata *PluginMetadata) ([]string, error) {
+	var env []string
+	keys := append(append([]string(nil), pluginEnvironmentKeys...), metadata.Environment...)
+	for _, key := range keys {
+		if value := os.Getenv(key); value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	pluginContext, err := newPluginContext(jujuclient.NewFileClientStore(), c.ConnectionName())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := json.Marshal(pluginContext)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return append(env,
+		osenv.JujuHomeEnvKey+"="+osenv.JujuHome(),
+		osenv.JujuEnvEnvKey+"="+c.ConnectionName(),
+		osenv.JujuContextEnvKey+"="+string(data),
+	), nil
+}
+
+// PluginContext is passed, as JSON in JUJU_CONTEXT, to plugins that
+// declare metadata, so that they can connect to the environment
+// without reading juju's files.
+type PluginContext struct {
+	// Controller holds the details of the controller hosting the
+	// environment, if it is in the client store.
+	Controller *PluginController `json:"controller,omitempty"`
+
+	// Model holds the details of the environment.
+	Model PluginModel `json:"model"`
+
+	// Account holds the details of the account the plugin should
+	// log in with, if it is in the client store.
+	Account *PluginAccount `json:"account,omitempty"`
+}
+
+// PluginController holds the details of a controller in a PluginContext.
+type PluginController struct {
+	Name         string   `json:"name"`
+	UUID         string   `json:"uuid"`
+	APIEndpoints []string `json:"api-endpoints"`
+	CACert       string   `json:"ca-cert"`
+}
+
+// PluginModel holds the details of a model in a PluginContext.
+type PluginModel struct {
+	Name  string `json:"name"`
+	UUID  string `json:"uuid,omitempty"`
+	Owner string `json:"owner,omitempty"`
+}
+
+// PluginAccount holds the details of an account in a PluginContext.
+// The account's password is never passed to plugins.
+type PluginAccount struct {
+	User         string `json:"user"`
+	SessionToken string `json:"session-token,omitempty"`
+}
+
+// newPluginContext returns the context of a plugin run against the
+// named environment, with the details recorded for it in the client
+// store. An environment that is not in the client store has only its
+// name.
+func newPluginContext(store jujuclient.ClientStore, envName string) (*PluginContext, error) {
+	pluginContext := &PluginContext{Model: PluginModel{Name: envName}}
+	controllerName, controller, model, err := envcmd.ClientStoreModel(store, envName)
+	if errors.IsNotFound(err) {
+		return pluginContext, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pluginContext.Controller = &PluginController{
+		Name:         controllerName,
+		UUID:         controller.ControllerUUID,
+		APIEndpoints: controller.APIEndpoints,
+		CACert:       controller.CACert,
+	}
+	pluginContext.Model.UUID = model.ModelUUID
+	pluginContext.Model.Owner = model.Owner
+	account, err := store.AccountByName(controllerName)
+	if errors.IsNotFound(err) {
+		return pluginContext, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pluginContext.Account = &PluginAccount{
+		User:         account.User,
+		SessionToken: account.SessionToken,
+	}
+	return pluginContext, nil
 }
 
 const PluginTopicText = `Juju Plugins
@@ -147,28 +403,62 @@ func PluginHelpTopic() string {
 	return output.String()
 }
 
-// GetPluginDescriptions runs each plugin with "--description".  The calls to
-// the plugins are run in parallel, so the function should only take as long
-// as the longest call.
+type PluginDescription struct {
+	name        string
+	description string
+
+	// metadata holds the metadata declared by the plugin, if any.
+	metadata *PluginMetadata
+}
+
+// GetPluginDescriptions runs each plugin with "--metadata", and with
+// "--description" if it declares no metadata, recording the metadata
+// declared for when the plugins are run. Each call is killed if it
+// takes longer than pluginProbeTimeout. The calls to the plugins are
+// run in parallel, so the function should only take as long as the
+// longest call.
 func GetPluginDescriptions() []PluginDescription {
 	plugins := findPlugins()
 	results := []PluginDescription{}
 	if len(plugins) == 0 {
 		return results
 	}
+	type probeResult struct {
+		PluginDescription
+		path   string
+		cached *cachedPluginMetadata
+	}
 	// create a channel with enough backing for each plugin
-	description := make(chan PluginDescription, len(plugins))
+	description := make(chan probeResult, len(plugins))
 
 	// exec the command, and wait only for the timeout before killing the process
 	for _, plugin := range plugins {
 		go func(plugin string) {
-			result := PluginDescription{name: plugin}
+			result := probeResult{PluginDescription: PluginDescription{name: plugin}}
 			defer func() {
 				description <- result
 			}()
-			desccmd := exec.Command(plugin, "--description")
-			output, err := desccmd.CombinedOutput()
-
+			path, err := exec.LookPath(plugin)
+			if err != nil {
+				result.description = fmt.Sprintf("error occurred finding '%s'", plugin)
+				logger.Errorf("'%s': %s", plugin, err)
+				return
+			}
+			result.path = path
+			// The plugin's details are taken before it is run, so
+			// that a plugin changed while it runs is probed again.
+			if info, err := os.Stat(path); err == nil {
+				result.cached = &cachedPluginMetadata{ModTime: info.ModTime(), Size: info.Size()}
+			}
+			if metadata, err := pluginMetadata(path); err == nil && metadata != nil {
+				result.description = metadata.Description
+				result.metadata = metadata
+				if result.cached != nil {
+					result.cached.Metadata = metadata
+				}
+				return
+			}
+			output, err := runPluginProbe(path, "--description", true)
 			if err == nil {
 				// trim to only get the first line
 				result.description = strings.SplitN(string(output), "\n", 2)[0]
@@ -178,16 +468,23 @@ func GetPluginDescriptions() []PluginDescription {
 			}
 		}(plugin)
 	}
-	resultMap := map[string]PluginDescription{}
+	resultMap := map[string]probeResult{}
+	cache := map[string]cachedPluginMetadata{}
 	// gather the results at the end
 	for _ = range plugins {
 		result := <-description
 		resultMap[result.name] = result
+		if result.cached != nil {
+			cache[result.path] = *result.cached
+		}
+	}
+	if err := writePluginMetadataCache(cache); err != nil {
+		logger.Warningf("cannot record plugin metadata: %v", err)
 	}
 	// plugins array is already sorted, use this to get the results in order
 	for _, plugin := range plugins {
 		// Strip the 'juju-' off the start of the plugin name in the results
-		result := resultMap[plugin]
+		result := resultMap[plugin].PluginDescription
 		result.name = result.name[len(JujuPluginPrefix):]
 		results = append(results, result)
 	}
@@ -17,6 +17,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
 )
 
@@ -119,6 +120,37 @@ func (suite *PluginSuite) TestGatherDescriptionsInParallel(c *gc.C) {
 	c.Assert(results[3].description, gc.Equals, "foo description")
 }
 
+func (suite *PluginSuite) TestPluginsWithNoPlugins(c *gc.C) {
+	output := badrun(c, 0, "plugins")
+	c.Assert(output, gc.Equals, "No plugins found.\n")
+}
+
+func (suite *PluginSuite) TestPluginsWithPlugins(c *gc.C) {
+	suite.makeFullPlugin(PluginParams{Name: "foo"})
+	suite.makeFullPlugin(PluginParams{Name: "bar"})
+	suite.makeMetadataPlugin("baz", `{"description": "baz description", "api-version": 1}`, "")
+	output := badrun(c, 0, "plugins")
+	c.Assert(output, gc.Equals, `
+PLUGIN  API  DESCRIPTION
+bar     -    bar description
+baz     1    baz description
+foo     -    foo description
+`[1:])
+}
+
+func (suite *PluginSuite) TestPluginsYAML(c *gc.C) {
+	suite.makeFullPlugin(PluginParams{Name: "foo"})
+	suite.makeMetadataPlugin("baz", "description: baz description\napi-version: 1", "")
+	output := badrun(c, 0, "plugins", "--format", "yaml")
+	c.Assert(output, gc.Equals, `
+- name: baz
+  description: baz description
+  api-version: 1
+- name: foo
+  description: foo description
+`[1:])
+}
+
 func (suite *PluginSuite) TestHelpPluginsWithNoPlugins(c *gc.C) {
 	output := badrun(c, 0, "help", "plugins")
 	c.Assert(output, jc.HasPrefix, PluginTopicText)
@@ -184,6 +216,110 @@ func (suite *PluginSuite) makePlugin(name string, perm os.FileMode) {
 	ioutil.WriteFile(filename, []byte(content), perm)
 }
 
+func (suite *PluginSuite) TestMetadataPluginEnvironment(c *gc.C) {
+	store := jujuclient.NewFileClientStore()
+	err := store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "ctrl-uuid",
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         "cert",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateModel("ctrl", "myenv", jujuclient.ModelDetails{ModelUUID: "env-uuid", Owner: "bob@local"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateAccount("ctrl", jujuclient.AccountDetails{
+		User:         "bob@local",
+		Password:     "secret",
+		SessionToken: "token",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	suite.PatchEnvironment("JUJU_TEST_PASSED", "passed")
+	suite.PatchEnvironment("JUJU_TEST_DROPPED", "dropped")
+
+	suite.makeMetadataPlugin("foo", `{"description": "foo", "api-version": 1, "environment": ["JUJU_TEST_PASSED"]}`, `
+echo "env is: $JUJU_ENV"
+echo "passed is: $JUJU_TEST_PASSED"
+echo "dropped is: $JUJU_TEST_DROPPED"
+echo "context is: $JUJU_CONTEXT"
+`)
+	badrun(c, 0, "plugins")
+	output := badrun(c, 0, "foo", "-e", "myenv")
+	c.Assert(output, gc.Equals, `
+env is: myenv
+passed is: passed
+dropped is: 
+context is: {"controller":{"name":"ctrl","uuid":"ctrl-uuid","api-endpoints":["10.0.0.1:17070"],"ca-cert":"cert"},"model":{"name":"myenv","uuid":"env-uuid","owner":"bob@local"},"account":{"user":"bob@local","session-token":"token"}}
+`[1:])
+}
+
+func (suite *PluginSuite) TestMetadataPluginNotInClientStore(c *gc.C) {
+	suite.makeMetadataPlugin("foo", `{"description": "foo", "api-version": 1}`, `
+echo "context is: $JUJU_CONTEXT"
+`)
+	badrun(c, 0, "plugins")
+	output := badrun(c, 0, "foo", "-e", "myenv")
+	c.Assert(output, gc.Equals, `context is: {"model":{"name":"myenv"}}`+"\n")
+}
+
+func (suite *PluginSuite) TestMetadataPluginUnsupportedAPIVersion(c *gc.C) {
+	suite.makeMetadataPlugin("foo", `{"description": "foo", "api-version": 99}`, `
+echo "should not run"
+`)
+	badrun(c, 0, "plugins")
+	output := badrun(c, 1, "foo")
+	c.Assert(output, gc.Matches, `ERROR plugin "foo" requires plugin API version 99, but this juju supports version 1\n`)
+}
+
+func (suite *PluginSuite) TestMetadataOnlyProbedWhenListed(c *gc.C) {
+	probed := gitjujutesting.HomePath("probed")
+	suite.PatchEnvironment("JUJU_TEST_PASSED", "passed")
+	suite.makeMetadataPlugin("foo", `{"description": "foo", "api-version": 1}'; echo probed > '`+probed+`'; echo '`, `
+echo "passed is: $JUJU_TEST_PASSED"
+`)
+	// A plugin that has not been listed is run without probing it,
+	// with all of juju's environment.
+	output := badrun(c, 0, "foo")
+	c.Assert(output, gc.Equals, "passed is: passed\n")
+	c.Assert(probed, jc.DoesNotExist)
+
+	badrun(c, 0, "plugins")
+	c.Assert(probed, jc.IsNonEmptyFile)
+}
+
+func (suite *PluginSuite) TestMetadataIgnoredWhenPluginChanged(c *gc.C) {
+	suite.PatchEnvironment("JUJU_TEST_DROPPED", "dropped")
+	suite.makeMetadataPlugin("foo", `{"description": "foo", "api-version": 1}`, `
+echo "dropped is: $JUJU_TEST_DROPPED"
+`)
+	badrun(c, 0, "plugins")
+	output := badrun(c, 0, "foo")
+	c.Assert(output, gc.Equals, "dropped is: \n")
+
+	suite.makePlugin("foo", 0755)
+	output = badrun(c, 0, "foo")
+	c.Assert(output, gc.Equals, "foo\n")
+}
+
+func (suite *PluginSuite) TestPluginProbeTimeout(c *gc.C) {
+	suite.PatchValue(&pluginProbeTimeout, 100*time.Millisecond)
+	content := "#!/bin/bash --norc\nsleep 10\n"
+	err := ioutil.WriteFile(gitjujutesting.HomePath(JujuPluginPrefix+"slow"), []byte(content), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+
+	start := time.Now()
+	results := GetPluginDescriptions()
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].description, gc.Equals, "error occurred running 'juju-slow --description'")
+}
+
+// makeMetadataPlugin makes a plugin that prints the given metadata when
+// run with --metadata, and otherwise runs the given script.
+func (suite *PluginSuite) makeMetadataPlugin(name, metadata, script string) {
+	content := fmt.Sprintf("#!/bin/bash --norc\nif [ \"$1\" = \"--metadata\" ]; then\n  echo '%s'\n  exit 0\nfi\n%s", metadata, script)
+	filename := gitjujutesting.HomePath(JujuPluginPrefix + name)
+	ioutil.WriteFile(filename, []byte(content), 0755)
+}
+
 func (suite *PluginSuite) makeFailingPlugin(name string, exitStatus int) {
 	content := fmt.Sprintf("#!/bin/bash --norc\necho failing\nexit %d", exitStatus)
 	filename := gitjujutesting.HomePath(JujuPluginPrefix + name)
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+// PluginsCommand lists the plugins found in the PATH.
+type PluginsCommand struct {
+	cmd.CommandBase
+	out output.Output
+}
+
+var pluginsDoc = `
+Plugins are implemented as stand-alone executable files somewhere in the
+user's PATH. The executable command must be of the format
+juju-<plugin name>, and is run as "juju <plugin name>".
+
+A plugin should describe itself by printing YAML (or JSON) metadata when
+run with --metadata, for example:
+
+    description: back up the environment to object storage
+    api-version: 1
+    environment: [AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY]
+
+where api-version is the version of the plugin API that the plugin
+requires; this juju supports version 1. A plugin requiring a later
+version is not run. Plugins are only run with --metadata when they are
+listed, by this command or "juju help plugins"; the metadata is recorded
+and used when the plugin is run, until the plugin changes.
+
+A plugin that declares metadata does not inherit all of juju's
+environment: it is run only with the variables that any program needs,
+such as PATH and HOME, those listed in its metadata, and the following:
+
+    JUJU_HOME     the juju home directory
+    JUJU_ENV      the name of the environment to operate in
+    JUJU_CONTEXT  the details of the controller, model and account of
+                  the environment, from the client store, as JSON
+
+For example:
+
+    {"controller": {"name": "prod", "uuid": "...",
+                    "api-endpoints": ["10.0.0.1:17070"], "ca-cert": "..."},
+     "model": {"name": "prod", "uuid": "...", "owner": "admin@local"},
+     "account": {"user": "admin@local", "session-token": "..."}}
+
+The controller and account are omitted for environments that are not in
+the client store; the account's password is never passed to plugins.
+Plugins that print no metadata are described by running them with
+--description, and inherit all of juju's environment, with JUJU_HOME
+and JUJU_ENV set.
+
+Examples:
+    juju plugins
+    juju plugins --format yaml
+`
+
+// PluginInfo describes a plugin listed by the plugins command.
+type PluginInfo struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description"`
+	APIVersion  int    `yaml:"api-version,omitempty" json:"api-version,omitempty"`
+}
+
+// ID implements output.Identifier.
+func (p PluginInfo) ID() string {
+	return p.Name
+}
+
+func (c *PluginsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "plugins",
+		Purpose: "list the juju plugins found in the PATH",
+		Doc:     pluginsDoc,
+	}
+}
+
+func (c *PluginsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatPluginsTabular,
+	})
+}
+
+func (c *PluginsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *PluginsCommand) Run(ctx *cmd.Context) error {
+	plugins := []PluginInfo{}
+	for _, plugin := range GetPluginDescriptions() {
+		info := PluginInfo{
+			Name:        plugin.name,
+			Description: plugin.description,
+		}
+		if plugin.metadata != nil {
+			info.APIVersion = plugin.metadata.APIVersion
+		}
+		plugins = append(plugins, info)
+	}
+	if len(plugins) == 0 && c.out.Name() == "tabular" && !c.out.Quiet() {
+		ctx.Infof("No plugins found.")
+		return nil
+	}
+	return c.out.Write(ctx, plugins)
+}
+
+// formatPluginsTabular writes the plugins as a table, showing "-" as
+// the API version of plugins that declare no metadata.
+func formatPluginsTabular(value interface{}) ([]byte, error) {
+	plugins, ok := value.([]PluginInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", plugins, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLUGIN\tAPI\tDESCRIPTION")
+	for _, plugin := range plugins {
+		version := "-"
+		if plugin.APIVersion != 0 {
+			version = strconv.Itoa(plugin.APIVersion)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", plugin.Name, version, plugin.Description)
+	}
+	tw.Flush()
+	return bytes.TrimRight(out.Bytes(), "\n"), nil
+}
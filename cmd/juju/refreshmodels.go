@@ -0,0 +1,184 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+)
+
+// RefreshModelsCommand updates the models recorded in the client store
+// with the environments currently hosted by each known controller.
+type RefreshModelsCommand struct {
+	cmd.CommandBase
+
+	// store and newAPI are overridden in tests.
+	store  jujuclient.ClientStore
+	newAPI func(jujuclient.ControllerDetails, jujuclient.AccountDetails) (RefreshModelsAPI, error)
+}
+
+// RefreshModelsAPI defines the API methods used by the refresh-models
+// command.
+type RefreshModelsAPI interface {
+	Close() error
+	ListEnvironments(user string) ([]params.Environment, error)
+}
+
+var refreshModelsDoc = `
+Connects to every controller recorded in the client store and lists the
+environments the controller's account has access to. Environments that
+are new are added to the client store, and those that no longer exist
+are marked as removed in it.
+
+Environments owned by the account's user are recorded by name, and those
+owned by other users as <owner>/<name>.
+`
+
+func (c *RefreshModelsCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "refresh-models",
+		Purpose: "update the locally cached models for all controllers",
+		Doc:     refreshModelsDoc,
+	}
+}
+
+func (c *RefreshModelsCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *RefreshModelsCommand) getStore() jujuclient.ClientStore {
+	if c.store != nil {
+		return c.store
+	}
+	return jujuclient.NewFileClientStore()
+}
+
+func (c *RefreshModelsCommand) getAPI(
+	store jujuclient.AccountUpdater, controllerName string,
+	controller jujuclient.ControllerDetails, account jujuclient.AccountDetails,
+) (RefreshModelsAPI, error) {
+	if c.newAPI != nil {
+		return c.newAPI(controller, account)
+	}
+	st, err := openControllerAPI(store, controllerName, controller, account)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return environmentmanager.NewClient(st), nil
+}
+
+func (c *RefreshModelsCommand) Run(ctx *cmd.Context) error {
+	store := c.getStore()
+	controllers, err := store.AllControllers()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var controllerNames []string
+	for name := range controllers {
+		controllerNames = append(controllerNames, name)
+	}
+	sort.Strings(controllerNames)
+
+	var failed []string
+	for _, name := range controllerNames {
+		if err := c.refreshController(ctx, store, name, controllers[name]); err != nil {
+			fmt.Fprintf(ctx.Stderr, "cannot refresh models for controller %q: %v\n", name, err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to refresh %d of %d controllers", len(failed), len(controllerNames))
+	}
+	return nil
+}
+
+func (c *RefreshModelsCommand) refreshController(
+	ctx *cmd.Context, store jujuclient.ClientStore, name string, details jujuclient.ControllerDetails,
+) error {
+	account, err := store.AccountByName(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.getAPI(store, name, details, *account)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	envs, err := client.ListEnvironments(account.User)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := store.AllModels(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+
+	accountUser := names.NewUserTag(account.User).Username()
+	known := make(map[string]bool)
+	for _, existing := range current {
+		if !existing.Removed {
+			known[existing.ModelUUID] = true
+		}
+	}
+
+	models := make(map[string]jujuclient.ModelDetails)
+	listed := make(map[string]bool)
+	var added, removed []string
+	for _, env := range envs {
+		key := env.Name
+		model := jujuclient.ModelDetails{ModelUUID: env.UUID}
+		if owner, err := names.ParseUserTag(env.OwnerTag); err == nil {
+			model.Owner = owner.Username()
+			if model.Owner != accountUser {
+				key = model.Owner + "/" + env.Name
+			}
+		}
+		models[key] = model
+		listed[env.UUID] = true
+		if !known[env.UUID] {
+			added = append(added, key)
+		}
+	}
+	// Models that are no longer listed are kept, marked as removed.
+	// One whose key has been taken by a listed model is kept under its
+	// UUID instead.
+	for key, existing := range current {
+		if listed[existing.ModelUUID] {
+			continue
+		}
+		if !existing.Removed {
+			removed = append(removed, key)
+		}
+		existing.Removed = true
+		if _, ok := models[key]; ok {
+			key = existing.ModelUUID
+		}
+		models[key] = existing
+	}
+	if err := store.SetModels(name, models); err != nil {
+		return errors.Trace(err)
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	if len(added) > 0 {
+		fmt.Fprintf(ctx.Stdout, "%s: added %s\n", name, strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(ctx.Stdout, "%s: removed %s\n", name, strings.Join(removed, ", "))
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintf(ctx.Stdout, "%s: up to date\n", name)
+	}
+	return nil
+}
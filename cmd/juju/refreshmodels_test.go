@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type RefreshModelsSuite struct {
+	testing.FakeJujuHomeSuite
+	store jujuclient.ClientStore
+	apis  map[string]*fakeRefreshModelsAPI
+}
+
+var _ = gc.Suite(&RefreshModelsSuite{})
+
+type fakeRefreshModelsAPI struct {
+	gitjujutesting.Stub
+	envs []params.Environment
+}
+
+func (f *fakeRefreshModelsAPI) Close() error {
+	f.AddCall("Close")
+	return f.NextErr()
+}
+
+func (f *fakeRefreshModelsAPI) ListEnvironments(user string) ([]params.Environment, error) {
+	f.AddCall("ListEnvironments", user)
+	return f.envs, f.NextErr()
+}
+
+func (s *RefreshModelsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	s.apis = make(map[string]*fakeRefreshModelsAPI)
+	for _, name := range []string{"ctrl-1", "ctrl-2"} {
+		err := s.store.UpdateController(name, jujuclient.ControllerDetails{
+			ControllerUUID: name + "-uuid",
+			CACert:         testing.CACert,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = s.store.UpdateAccount(name, jujuclient.AccountDetails{User: "bob"})
+		c.Assert(err, jc.ErrorIsNil)
+		s.apis[name+"-uuid"] = &fakeRefreshModelsAPI{}
+	}
+}
+
+func (s *RefreshModelsSuite) run(c *gc.C) (string, string, error) {
+	command := &RefreshModelsCommand{
+		store: s.store,
+		newAPI: func(details jujuclient.ControllerDetails, _ jujuclient.AccountDetails) (RefreshModelsAPI, error) {
+			return s.apis[details.ControllerUUID], nil
+		},
+	}
+	ctx, err := testing.RunCommand(c, command)
+	return testing.Stdout(ctx), testing.Stderr(ctx), err
+}
+
+func (s *RefreshModelsSuite) TestRefresh(c *gc.C) {
+	err := s.store.SetModels("ctrl-1", map[string]jujuclient.ModelDetails{
+		"kept":     {ModelUUID: "kept-uuid"},
+		"gone":     {ModelUUID: "gone-uuid"},
+		"replaced": {ModelUUID: "old-uuid"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.apis["ctrl-1-uuid"].envs = []params.Environment{
+		{Name: "kept", UUID: "kept-uuid"},
+		{Name: "replaced", UUID: "new-uuid"},
+		{Name: "new", UUID: "new-env-uuid", OwnerTag: "user-mary@local"},
+	}
+	s.apis["ctrl-2-uuid"].envs = []params.Environment{
+		{Name: "admin", UUID: "admin-uuid"},
+	}
+
+	stdout, _, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, ""+
+		"ctrl-1: added mary@local/new, replaced\n"+
+		"ctrl-1: removed gone, replaced\n"+
+		"ctrl-2: added admin\n")
+	s.apis["ctrl-1-uuid"].CheckCalls(c, []gitjujutesting.StubCall{
+		{FuncName: "ListEnvironments", Args: []interface{}{"bob"}},
+		{FuncName: "Close"},
+	})
+
+	// Models that are no longer listed are marked as removed; the
+	// replaced model is kept under its UUID as its name is taken.
+	models, err := s.store.AllModels("ctrl-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"kept":           {ModelUUID: "kept-uuid"},
+		"replaced":       {ModelUUID: "new-uuid"},
+		"mary@local/new": {ModelUUID: "new-env-uuid", Owner: "mary@local"},
+		"gone":           {ModelUUID: "gone-uuid", Removed: true},
+		"old-uuid":       {ModelUUID: "old-uuid", Removed: true},
+	})
+	models, err = s.store.AllModels("ctrl-2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"admin": {ModelUUID: "admin-uuid"},
+	})
+
+	// Refreshing again reports nothing new.
+	stdout, _, err = s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "ctrl-1: up to date\nctrl-2: up to date\n")
+}
+
+func (s *RefreshModelsSuite) TestRefreshSameNameDifferentOwners(c *gc.C) {
+	s.apis["ctrl-1-uuid"].envs = []params.Environment{
+		{Name: "dev", UUID: "bob-dev-uuid", OwnerTag: "user-bob@local"},
+		{Name: "dev", UUID: "mary-dev-uuid", OwnerTag: "user-mary@local"},
+	}
+	stdout, _, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "ctrl-1: added dev, mary@local/dev\nctrl-2: up to date\n")
+	models, err := s.store.AllModels("ctrl-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"dev":            {ModelUUID: "bob-dev-uuid", Owner: "bob@local"},
+		"mary@local/dev": {ModelUUID: "mary-dev-uuid", Owner: "mary@local"},
+	})
+}
+
+func (s *RefreshModelsSuite) TestRefreshRemovedModelReturns(c *gc.C) {
+	err := s.store.SetModels("ctrl-1", map[string]jujuclient.ModelDetails{
+		"back": {ModelUUID: "back-uuid", Removed: true},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.apis["ctrl-1-uuid"].envs = []params.Environment{
+		{Name: "back", UUID: "back-uuid"},
+	}
+	stdout, _, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "ctrl-1: added back\nctrl-2: up to date\n")
+	model, err := s.store.ModelByName("ctrl-1", "back")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model, jc.DeepEquals, &jujuclient.ModelDetails{ModelUUID: "back-uuid"})
+}
+
+func (s *RefreshModelsSuite) TestRefreshUpToDate(c *gc.C) {
+	stdout, _, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "ctrl-1: up to date\nctrl-2: up to date\n")
+}
+
+func (s *RefreshModelsSuite) TestRefreshControllerFails(c *gc.C) {
+	s.apis["ctrl-1-uuid"].Errors = []error{errors.New("boom")}
+	s.apis["ctrl-2-uuid"].envs = []params.Environment{
+		{Name: "admin", UUID: "admin-uuid"},
+	}
+	stdout, stderr, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "failed to refresh 1 of 2 controllers")
+	c.Assert(stdout, gc.Equals, "ctrl-2: added admin\n")
+	c.Assert(stderr, gc.Equals, `cannot refresh models for controller "ctrl-1": boom`+"\n")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
+	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -16,18 +17,36 @@ import (
 // RemoveUnitCommand is responsible for destroying service units.
 type RemoveUnitCommand struct {
 	envcmd.EnvCommandBase
-	UnitNames []string
+	UnitNames           []string
+	RemoveEmptyMachines bool
 }
 
+const removeUnitDoc = `
+Remove service units from the environment.
+
+A machine left with no units when a unit is removed is destroyed with
+it, unless it hosts containers. When the unit is in a container, the
+container is destroyed; with --remove-empty-machines, the machine
+hosting the container is then destroyed too once the container has
+gone, if nothing else is left on it. Manually provisioned machines are
+never destroyed this way. Set the remove-empty-machines environment
+setting to do this for every unit removed.
+`
+
 func (c *RemoveUnitCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "remove-unit",
 		Args:    "<unit> [...]",
 		Purpose: "remove service units from the environment",
+		Doc:     removeUnitDoc,
 		Aliases: []string{"destroy-unit"},
 	}
 }
 
+func (c *RemoveUnitCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.RemoveEmptyMachines, "remove-empty-machines", false, "Destroy the hosts of the units' containers if left empty")
+}
+
 func (c *RemoveUnitCommand) Init(args []string) error {
 	c.UnitNames = args
 	if len(c.UnitNames) == 0 {
@@ -49,5 +68,10 @@ func (c *RemoveUnitCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.DestroyServiceUnits(c.UnitNames...), block.BlockRemove)
+	if c.RemoveEmptyMachines {
+		err = client.DestroyServiceUnitsRemovingEmptyMachines(c.UnitNames...)
+	} else {
+		err = client.DestroyServiceUnits(c.UnitNames...)
+	}
+	return block.ProcessBlockedError(err, block.BlockRemove)
 }
@@ -56,6 +56,32 @@ func (s *RemoveUnitSuite) TestRemoveUnit(c *gc.C) {
 		c.Assert(u.Life(), gc.Equals, state.Dying)
 	}
 }
+
+func (s *RemoveUnitSuite) TestRemoveUnitRemovingEmptyMachines(c *gc.C) {
+	host, err := s.State.AddMachine(testing.FakeDefaultSeries, state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err = runDeploy(c, "--to", "lxc:"+host.Id(), "local:dummy", "dummy")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runRemoveUnit(c, "--remove-empty-machines", "dummy/0")
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.Machine(host.Id() + "/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Life(), gc.Equals, state.Dying)
+
+	// Once the container has gone, the empty host is destroyed.
+	err = container.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	err = host.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(host.Life(), gc.Equals, state.Dying)
+}
+
 func (s *RemoveUnitSuite) TestBlockRemoveUnit(c *gc.C) {
 	svc := s.setupUnitForRemove(c)
 
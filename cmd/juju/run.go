@@ -43,6 +43,13 @@ comma separated values.
 If the target is a machine, the command is run as the "ubuntu" user on
 the remote machine.
 
+A state server machine may be targeted as "controller/<id>", for example
+  --machine controller/0
+even when connected to an environment other than the state server's own.
+Only the owner of the state server environment can run commands on state
+server machines; this is intended for diagnosing the state servers
+themselves.
+
 If the target is a service, the command is run on all units for that
 service. For example, if there was a service "mysql" and that service
 had two units, "mysql/0" and "mysql/1", then
@@ -101,7 +108,7 @@ func (c *RunCommand) Init(args []string) error {
 
 	var nameErrors []string
 	for _, machineId := range c.machines {
-		if !names.IsValidMachine(machineId) {
+		if !validRunMachine(machineId) {
 			nameErrors = append(nameErrors, fmt.Sprintf("  %q is not a valid machine id", machineId))
 		}
 	}
@@ -123,6 +130,17 @@ func (c *RunCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
+// controllerMachinePrefix identifies a --machine target as one of the
+// state server machines.
+const controllerMachinePrefix = "controller/"
+
+func validRunMachine(machineId string) bool {
+	if strings.HasPrefix(machineId, controllerMachinePrefix) {
+		machineId = strings.TrimPrefix(machineId, controllerMachinePrefix)
+	}
+	return names.IsValidMachine(machineId)
+}
+
 func encodeBytes(input []byte) (value string, encoding string) {
 	if utf8.Valid(input) {
 		value = string(input)
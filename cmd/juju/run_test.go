@@ -61,13 +61,19 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		args:     []string{"--machine=1,2,1/kvm/0", "sudo reboot"},
 		commands: "sudo reboot",
 		machines: []string{"1", "2", "1/kvm/0"},
+	}, {
+		message:  "command to state server machines",
+		args:     []string{"--machine=controller/0,controller/2", "sudo reboot"},
+		commands: "sudo reboot",
+		machines: []string{"controller/0", "controller/2"},
 	}, {
 		message: "bad machine names",
-		args:    []string{"--machine=foo,machine-2", "sudo reboot"},
+		args:    []string{"--machine=foo,machine-2,controller/foo", "sudo reboot"},
 		errMatch: "" +
 			"The following run targets are not valid:\n" +
 			"  \"foo\" is not a valid machine id\n" +
-			"  \"machine-2\" is not a valid machine id",
+			"  \"machine-2\" is not a valid machine id\n" +
+			"  \"controller/foo\" is not a valid machine id",
 	}, {
 		message:  "all and defined services",
 		args:     []string{"--all", "--service=wordpress,mysql", "sudo reboot"},
@@ -49,6 +49,10 @@ server to the local directory remote-logs:
 Copy a local file to the second apache unit of the environment "testing":
 
     juju scp -e testing foo.txt apache2/1:
+
+As with "juju ssh", the SSH host keys of the machines are verified
+unless --no-host-key-checks is specified, or the keys of one of the
+machines are not known.
 `
 
 func (c *SCPCommand) Info() *cmd.Info {
@@ -108,5 +112,10 @@ func (c *SCPCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	cleanup, err := c.setHostKeyChecks(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	return ssh.Copy(args, options)
 }
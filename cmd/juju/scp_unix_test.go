@@ -147,7 +147,7 @@ func (s *SCPSuite) TestSCPCommand(c *gc.C) {
 			c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, "")
 			data, err := ioutil.ReadFile(filepath.Join(s.bin, "scp.args"))
 			c.Check(err, jc.ErrorIsNil)
-			actual := string(data)
+			actual := s.output(string(data))
 			if t.proxy {
 				actual = strings.Replace(actual, ".dns", ".internal", 2)
 			}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+
+	apiservice "github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// SetAddressPolicyCommand sets the policy used to choose the public
+// addresses of a service's units.
+type SetAddressPolicyCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Policy      string
+	api         SetAddressPolicyAPI
+}
+
+const setAddressPolicyDoc = `
+Sets the policy used to choose which of the addresses of a unit's machine
+is reported as the unit's public address, for every unit of the service.
+The policy is one of:
+
+    public        the most public address (the default)
+    private       the address used within the environment
+    space:<name>  an address on a subnet in the named space; the most
+                  public address is used if there is none
+
+The service's units run their config-changed hooks when the policy
+changes, as they do when their machines' addresses change, so that they
+can advertise the new address.
+
+Example:
+
+    juju service set-address-policy mysql space:db
+`
+
+func (c *SetAddressPolicyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set-address-policy",
+		Args:    "<service> <policy>",
+		Purpose: "choose which address a service's units advertise",
+		Doc:     setAddressPolicyDoc,
+	}
+}
+
+func (c *SetAddressPolicyCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service name specified")
+	case 1:
+		return errors.New("no address policy specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return fmt.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName, c.Policy = args[0], args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// SetAddressPolicyAPI defines the methods on the service API that the
+// set-address-policy command calls.
+type SetAddressPolicyAPI interface {
+	Close() error
+	SetAddressPolicy(service, policy string) error
+}
+
+func (c *SetAddressPolicyCommand) getAPI() (SetAddressPolicyAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return apiservice.NewClient(root), nil
+}
+
+// Run sets the address policy of the service.
+func (c *SetAddressPolicyCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+	err = apiclient.SetAddressPolicy(c.ServiceName, c.Policy)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type SetAddressPolicySuite struct {
+	coretesting.FakeJujuHomeSuite
+	fake *fakeAddressPolicyAPI
+}
+
+var _ = gc.Suite(&SetAddressPolicySuite{})
+
+type fakeAddressPolicyAPI struct {
+	service string
+	policy  string
+	err     error
+}
+
+func (f *fakeAddressPolicyAPI) Close() error {
+	return nil
+}
+
+func (f *fakeAddressPolicyAPI) SetAddressPolicy(service, policy string) error {
+	f.service, f.policy = service, policy
+	return f.err
+}
+
+func (s *SetAddressPolicySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeAddressPolicyAPI{}
+}
+
+func (s *SetAddressPolicySuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{args: nil, err: "no service name specified"},
+		{args: []string{"mysql"}, err: "no address policy specified"},
+		{args: []string{"my_sql", "private"}, err: `invalid service name "my_sql"`},
+		{args: []string{"mysql", "private", "extra"}, err: `unrecognized args: \["extra"\]`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		err := coretesting.InitCommand(&service.SetAddressPolicyCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *SetAddressPolicySuite) TestRun(c *gc.C) {
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(service.NewSetAddressPolicyCommand(s.fake)), "mysql", "space:db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.service, gc.Equals, "mysql")
+	c.Assert(s.fake.policy, gc.Equals, "space:db")
+}
+
+func (s *SetAddressPolicySuite) TestBlocked(c *gc.C) {
+	s.fake.err = common.ErrOperationBlocked("TestBlocked")
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(service.NewSetAddressPolicyCommand(s.fake)), "mysql", "private")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+}
@@ -92,6 +92,8 @@ Examples:
  juju service add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju service add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju service add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju service add-unit mysql --to lxc:kvm:25
+ (Add unit to a new lxc container inside a new kvm container on host machine 25)
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -159,7 +161,8 @@ func (c *AddUnitCommand) Run(_ *cmd.Context) error {
 }
 
 // deployTarget describes the format a machine or container target must match to be valid.
-const deployTarget = "^(" + names.ContainerTypeSnippet + ":)?" + names.MachineSnippet + "$"
+// Only lxc containers may be created inside new kvm containers.
+const deployTarget = "^(" + names.ContainerTypeSnippet + ":|lxc:kvm:)?" + names.MachineSnippet + "$"
 
 var validMachineOrNewContainer = regexp.MustCompile(deployTarget)
 
@@ -151,6 +151,13 @@ func (s *AddUnitSuite) TestForceMachineNewContainer(c *gc.C) {
 	c.Assert(s.fake.machineSpec, gc.Equals, "lxc:1")
 }
 
+func (s *AddUnitSuite) TestForceMachineNewNestedContainer(c *gc.C) {
+	err := s.runAddUnit(c, "some-service-name", "--to", "lxc:kvm:1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.numUnits, gc.Equals, 2)
+	c.Assert(s.fake.machineSpec, gc.Equals, "lxc:kvm:1")
+}
+
 func (s *AddUnitSuite) TestNameChecks(c *gc.C) {
 	assertMachineOrNewContainer := func(s string, expect bool) {
 		c.Logf("%s -> %v", s, expect)
@@ -162,6 +169,8 @@ func (s *AddUnitSuite) TestNameChecks(c *gc.C) {
 	assertMachineOrNewContainer("0/lxc/0", true)
 	assertMachineOrNewContainer("lxc:0", true)
 	assertMachineOrNewContainer("lxc:lxc:0", false)
+	assertMachineOrNewContainer("lxc:kvm:0", true)
+	assertMachineOrNewContainer("kvm:lxc:0", false)
 	assertMachineOrNewContainer("kvm:0/lxc/1", true)
 	assertMachineOrNewContainer("lxc:", false)
 	assertMachineOrNewContainer(":lxc", false)
@@ -30,3 +30,18 @@ func NewAddUnitCommand(api ServiceAddUnitAPI) *AddUnitCommand {
 		api: api,
 	}
 }
+
+// NewScaleCommand returns a ScaleCommand with the api provided as specified.
+func NewScaleCommand(api ScaleServiceAPI) *ScaleCommand {
+	return &ScaleCommand{
+		api: api,
+	}
+}
+
+// NewSetAddressPolicyCommand returns a SetAddressPolicyCommand with the api
+// provided as specified.
+func NewSetAddressPolicyCommand(api SetAddressPolicyAPI) *SetAddressPolicyCommand {
+	return &SetAddressPolicyCommand{
+		api: api,
+	}
+}
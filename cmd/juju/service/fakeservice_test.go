@@ -15,11 +15,13 @@ import (
 // get and unset commands.  It implements the following interfaces:
 // SetServiceAPI, UnsetServiceAPI and GetServiceAPI
 type fakeServiceAPI struct {
-	values    map[string]interface{}
-	servName  string
-	charmName string
-	config    string
-	err       error
+	values     map[string]interface{}
+	servName   string
+	charmName  string
+	config     string
+	err        error
+	revision   *params.ServiceConfigRevision
+	rolledBack int
 }
 
 func (f *fakeServiceAPI) Close() error {
@@ -98,3 +100,26 @@ func (f *fakeServiceAPI) ServiceUnset(service string, options []string) error {
 
 	return nil
 }
+
+func (f *fakeServiceAPI) ServiceGetConfigRevision(service string, revision int) (*params.ServiceConfigRevision, error) {
+	if service != f.servName {
+		return nil, errors.NotFoundf("service %q", service)
+	}
+	if f.revision == nil || f.revision.Revision != revision {
+		return nil, errors.NotFoundf("config revision %d of service %q", revision, service)
+	}
+	return f.revision, nil
+}
+
+func (f *fakeServiceAPI) ServiceRollbackConfig(service string, revision int) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	if service != f.servName {
+		return errors.NotFoundf("service %q", service)
+	}
+
+	f.rolledBack = revision
+	return nil
+}
@@ -5,19 +5,22 @@ package service
 
 import (
 	"errors"
+	"time"
 
 	"github.com/juju/cmd"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 // GetCommand retrieves the configuration of a service.
 type GetCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
-	out         cmd.Output
+	Revision    int
+	out         output.Output
 	api         GetServiceAPI
 }
 
@@ -44,6 +47,14 @@ settings:
 NOTE: In the example above the descriptions and most other settings were omitted for
 brevity. The "engine" setting was left at its default value ("nginx"), while the
 "tuning" setting was set to "optimized" (the default value is "single").
+
+Each change to a service's settings is recorded as a new config revision, shown
+as "config-revision" once the settings have been changed. With --revision, the
+settings as they were at that revision are shown instead, along with who made
+the change, when, and the settings that it changed. Only the most recent
+revisions are kept. See also "juju service set --rollback".
+
+With --quiet, only the names of the settings are output, one per line.
 `
 
 func (c *GetCommand) Info() *cmd.Info {
@@ -56,10 +67,8 @@ func (c *GetCommand) Info() *cmd.Info {
 }
 
 func (c *GetCommand) SetFlags(f *gnuflag.FlagSet) {
-	// TODO(dfc) add json formatting ?
-	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
-		"yaml": cmd.FormatYaml,
-	})
+	c.out.AddFlags(f, "yaml", nil)
+	f.IntVar(&c.Revision, "revision", 0, "show the settings at the given config revision")
 }
 
 func (c *GetCommand) Init(args []string) error {
@@ -67,6 +76,9 @@ func (c *GetCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
 	}
+	if c.Revision < 0 {
+		return errors.New("config revision must be positive")
+	}
 	c.ServiceName = args[0]
 	return cmd.CheckEmpty(args[1:])
 }
@@ -76,6 +88,7 @@ func (c *GetCommand) Init(args []string) error {
 type GetServiceAPI interface {
 	Close() error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
+	ServiceGetConfigRevision(service string, revision int) (*params.ServiceConfigRevision, error)
 }
 
 func (c *GetCommand) getAPI() (GetServiceAPI, error) {
@@ -94,6 +107,9 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.Revision > 0 {
+		return c.writeRevision(ctx, client)
+	}
 	results, err := client.ServiceGet(c.ServiceName)
 	if err != nil {
 		return err
@@ -104,5 +120,50 @@ func (c *GetCommand) Run(ctx *cmd.Context) error {
 		"charm":    results.Charm,
 		"settings": results.Config,
 	}
-	return c.out.Write(ctx, resultsMap)
+	if results.ConfigRevision > 0 {
+		resultsMap["config-revision"] = results.ConfigRevision
+	}
+	// With --quiet, only the names of the settings are written.
+	ids, err := output.IDs(results.Config)
+	if err != nil {
+		return err
+	}
+	return c.out.WriteWithIDs(ctx, resultsMap, ids)
+}
+
+// writeRevision fetches the configuration of the service at the
+// requested revision and formats it, with the changes that produced it.
+func (c *GetCommand) writeRevision(ctx *cmd.Context, client GetServiceAPI) error {
+	result, err := client.ServiceGetConfigRevision(c.ServiceName, c.Revision)
+	if err != nil {
+		return err
+	}
+	changes := make([]map[string]interface{}, len(result.Changes))
+	for i, change := range result.Changes {
+		changes[i] = map[string]interface{}{
+			"change": change.Type,
+			"key":    change.Key,
+		}
+		if change.OldValue != nil {
+			changes[i]["old"] = change.OldValue
+		}
+		if change.NewValue != nil {
+			changes[i]["new"] = change.NewValue
+		}
+	}
+	resultsMap := map[string]interface{}{
+		"service":         result.Service,
+		"config-revision": result.Revision,
+		"changed":         result.Changed.Format(time.RFC3339),
+		"changes":         changes,
+		"settings":        result.Settings,
+	}
+	if result.ChangedBy != "" {
+		resultsMap["changed-by"] = result.ChangedBy
+	}
+	ids, err := output.IDs(result.Settings)
+	if err != nil {
+		return err
+	}
+	return c.out.WriteWithIDs(ctx, resultsMap, ids)
 }
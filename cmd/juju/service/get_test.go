@@ -5,12 +5,14 @@ package service_test
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	goyaml "gopkg.in/yaml.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/service"
 	coretesting "github.com/juju/juju/testing"
@@ -76,6 +78,10 @@ func (s *GetSuite) TestGetCommandInit(c *gc.C) {
 	// missing args
 	err := coretesting.InitCommand(&service.GetCommand{}, []string{})
 	c.Assert(err, gc.ErrorMatches, "no service name specified")
+
+	// bad revision
+	err = coretesting.InitCommand(&service.GetCommand{}, []string{"dummy-service", "--revision", "-1"})
+	c.Assert(err, gc.ErrorMatches, "config revision must be positive")
 }
 
 func (s *GetSuite) TestGetConfig(c *gc.C) {
@@ -99,3 +105,48 @@ func (s *GetSuite) TestGetConfig(c *gc.C) {
 		c.Assert(actual, gc.DeepEquals, expected)
 	}
 }
+
+func (s *GetSuite) TestGetConfigRevision(c *gc.C) {
+	changed := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.fake.revision = &params.ServiceConfigRevision{
+		Service:   "dummy-service",
+		Revision:  3,
+		ChangedBy: "user-admin",
+		Changed:   changed,
+		Changes: []params.ServiceConfigChange{{
+			Type:     "modified",
+			Key:      "title",
+			OldValue: "Nearly There",
+			NewValue: "Arrived",
+		}},
+		Settings: map[string]interface{}{"title": "Arrived"},
+	}
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{"dummy-service", "--revision", "3"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+changed: "2015-06-01T12:00:00Z"
+changed-by: user-admin
+changes:
+- change: modified
+  key: title
+  new: Arrived
+  old: Nearly There
+config-revision: 3
+service: dummy-service
+settings:
+  title: Arrived
+`[1:])
+
+	ctx = coretesting.Context(c)
+	code = cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{"dummy-service", "--revision", "2"})
+	c.Assert(code, gc.Equals, 1)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "error: config revision 2 of service \"dummy-service\" not found\n")
+}
+
+func (s *GetSuite) TestGetConfigQuiet(c *gc.C) {
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(service.NewGetCommand(s.fake)), ctx, []string{"dummy-service", "--quiet"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "outlook\nskill-level\ntitle\nusername\n")
+}
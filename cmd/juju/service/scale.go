@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// ScaleCommand adds or removes units of a service so that it has a
+// given number of units.
+type ScaleCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName   string
+	NumUnits      int
+	ToMachineSpec string
+	api           ScaleServiceAPI
+}
+
+const scaleDoc = `
+Scale a service to the given number of units, adding or removing units
+as needed. Units that are already being removed are not counted. When
+scaling down, the most recently added units are removed first, and a
+service cannot be scaled below its minimum number of units.
+
+When scaling up by one unit, the new unit can be placed on a specific
+machine or container with the --to argument, as for add-unit.
+
+Examples:
+ juju service scale wordpress 5        (Scale wordpress to 5 units)
+ juju service scale wordpress 0        (Remove all wordpress units)
+ juju service scale mysql 3 --to 23    (Add a third mysql unit, on machine 23)
+
+See Also:
+ juju help service add-unit
+ juju help remove-unit
+`
+
+func (c *ScaleCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "scale",
+		Args:    "<service name> <number of units>",
+		Purpose: "add or remove units so that a service has the given number of units",
+		Doc:     scaleDoc,
+	}
+}
+
+func (c *ScaleCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.ToMachineSpec, "to", "", "the machine or container to deploy the added unit in, bypasses constraints")
+}
+
+func (c *ScaleCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service specified")
+	case 1:
+		return errors.New("no number of units specified")
+	}
+	c.ServiceName = args[0]
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return errors.Errorf("invalid number of units %q", args[1])
+	}
+	c.NumUnits = n
+	if c.ToMachineSpec != "" && !IsMachineOrNewContainer(c.ToMachineSpec) {
+		return errors.Errorf("invalid --to parameter %q", c.ToMachineSpec)
+	}
+	return cmd.CheckEmpty(args[2:])
+}
+
+// ScaleServiceAPI defines the methods on the client API that the
+// service scale command calls.
+type ScaleServiceAPI interface {
+	Close() error
+	ScaleService(service string, numUnits int, machineSpec string) (params.ScaleServiceResult, error)
+}
+
+func (c *ScaleCommand) getAPI() (ScaleServiceAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// Run connects to the environment specified on the command line and
+// calls ScaleService for the given service.
+func (c *ScaleCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer apiclient.Close()
+
+	result, err := apiclient.ScaleService(c.ServiceName, c.NumUnits, c.ToMachineSpec)
+	for _, unit := range result.Added {
+		ctx.Infof("added unit %s", unit)
+	}
+	for _, unit := range result.Removed {
+		ctx.Infof("removed unit %s", unit)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	if len(result.Added) == 0 && len(result.Removed) == 0 {
+		ctx.Infof("service %q already has %d units", c.ServiceName, c.NumUnits)
+	}
+	return nil
+}
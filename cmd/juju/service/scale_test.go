@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service_test
+
+import (
+	"strings"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/service"
+	"github.com/juju/juju/testing"
+)
+
+type ScaleSuite struct {
+	testing.FakeJujuHomeSuite
+	fake *fakeScaleServiceAPI
+}
+
+var _ = gc.Suite(&ScaleSuite{})
+
+type fakeScaleServiceAPI struct {
+	gitjujutesting.Stub
+	result params.ScaleServiceResult
+}
+
+func (f *fakeScaleServiceAPI) Close() error {
+	return nil
+}
+
+func (f *fakeScaleServiceAPI) ScaleService(service string, numUnits int, machineSpec string) (params.ScaleServiceResult, error) {
+	f.AddCall("ScaleService", service, numUnits, machineSpec)
+	return f.result, f.NextErr()
+}
+
+func (s *ScaleSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.fake = &fakeScaleServiceAPI{}
+}
+
+func (s *ScaleSuite) runScale(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(service.NewScaleCommand(s.fake)), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stderr(ctx), nil
+}
+
+func (s *ScaleSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{},
+		err:  "no service specified",
+	}, {
+		args: []string{"wordpress"},
+		err:  "no number of units specified",
+	}, {
+		args: []string{"wordpress", "five"},
+		err:  `invalid number of units "five"`,
+	}, {
+		args: []string{"wordpress", "-1"},
+		err:  `invalid number of units "-1"`,
+	}, {
+		args: []string{"wordpress", "5", "--to", "bigglesplop"},
+		err:  `invalid --to parameter "bigglesplop"`,
+	}, {
+		args: []string{"wordpress", "5", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := testing.InitCommand(envcmd.Wrap(&service.ScaleCommand{}), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ScaleSuite) TestScaleUp(c *gc.C) {
+	s.fake.result = params.ScaleServiceResult{Added: []string{"wordpress/3", "wordpress/4"}}
+	stderr, err := s.runScale(c, "wordpress", "5")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "added unit wordpress/3\nadded unit wordpress/4\n")
+	s.fake.CheckCall(c, 0, "ScaleService", "wordpress", 5, "")
+}
+
+func (s *ScaleSuite) TestScaleDown(c *gc.C) {
+	s.fake.result = params.ScaleServiceResult{Removed: []string{"wordpress/4"}}
+	stderr, err := s.runScale(c, "wordpress", "4")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "removed unit wordpress/4\n")
+}
+
+func (s *ScaleSuite) TestScaleToMachine(c *gc.C) {
+	_, err := s.runScale(c, "wordpress", "1", "--to", "lxc:0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "ScaleService", "wordpress", 1, "lxc:0")
+}
+
+func (s *ScaleSuite) TestScaleNoChange(c *gc.C) {
+	stderr, err := s.runScale(c, "wordpress", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stderr, gc.Equals, "service \"wordpress\" already has 2 units\n")
+}
+
+func (s *ScaleSuite) TestBlockScale(c *gc.C) {
+	s.fake.SetErrors(common.ErrOperationBlocked("TestBlockScale"))
+	s.runScale(c, "wordpress", "5")
+
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*TestBlockScale.*")
+}
@@ -31,7 +31,9 @@ func NewSuperCommand() cmd.Command {
 	environmentCmd.Register(envcmd.Wrap(&ServiceSetConstraintsCommand{}))
 	environmentCmd.Register(envcmd.Wrap(&GetCommand{}))
 	environmentCmd.Register(envcmd.Wrap(&SetCommand{}))
+	environmentCmd.Register(envcmd.Wrap(&SetAddressPolicyCommand{}))
 	environmentCmd.Register(envcmd.Wrap(&UnsetCommand{}))
+	environmentCmd.Register(envcmd.Wrap(&ScaleCommand{}))
 
 	return environmentCmd
 }
@@ -24,7 +24,9 @@ var expectedCommmandNames = []string{
 	"get",
 	"get-constraints",
 	"help",
+	"scale",
 	"set",
+	"set-address-policy",
 	"set-constraints",
 	"unset",
 }
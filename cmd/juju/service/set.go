@@ -26,6 +26,7 @@ type SetCommand struct {
 	ServiceName     string
 	SettingsStrings map[string]string
 	SettingsYAML    cmd.FileVar
+	Rollback        int
 	api             SetServiceAPI
 }
 
@@ -40,6 +41,10 @@ size of this value is 5M.
 
 Option values may be any UTF-8 encoded string. UTF-8 is accepted on the command
 line and in configuration files.
+
+With --rollback, the service's settings are restored to those recorded at the
+given config revision; see "juju service get --revision". The rollback is itself
+recorded as a new revision.
 `
 
 const maxValueSize = 5242880
@@ -55,6 +60,7 @@ func (c *SetCommand) Info() *cmd.Info {
 
 func (c *SetCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.SettingsYAML, "config", "path to yaml-formatted service config")
+	f.IntVar(&c.Rollback, "rollback", 0, "restore the settings recorded at the given config revision")
 }
 
 func (c *SetCommand) Init(args []string) error {
@@ -64,6 +70,12 @@ func (c *SetCommand) Init(args []string) error {
 	if c.SettingsYAML.Path != "" && len(args) > 1 {
 		return errors.New("cannot specify --config when using key=value arguments")
 	}
+	if c.Rollback < 0 {
+		return errors.New("config revision must be positive")
+	}
+	if c.Rollback > 0 && (c.SettingsYAML.Path != "" || len(args) > 1) {
+		return errors.New("cannot specify --rollback when setting options")
+	}
 	c.ServiceName = args[0]
 	settings, err := keyvalues.Parse(args[1:], true)
 	if err != nil {
@@ -80,6 +92,7 @@ type SetServiceAPI interface {
 	ServiceSetYAML(service string, yaml string) error
 	ServiceGet(service string) (*params.ServiceGetResults, error)
 	ServiceSet(service string, options map[string]string) error
+	ServiceRollbackConfig(service string, revision int) error
 }
 
 func (c *SetCommand) getAPI() (SetServiceAPI, error) {
@@ -97,6 +110,9 @@ func (c *SetCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.Rollback > 0 {
+		return block.ProcessBlockedError(api.ServiceRollbackConfig(c.ServiceName, c.Rollback), block.BlockChange)
+	}
 	if c.SettingsYAML.Path != "" {
 		b, err := c.SettingsYAML.Read(ctx)
 		if err != nil {
@@ -64,6 +64,14 @@ func (*SetSuite) TestSetCommandInit(c *gc.C) {
 	// --config and options specified
 	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--config", "testconfig.yaml", "bees="})
 	c.Assert(err, gc.ErrorMatches, "cannot specify --config when using key=value arguments")
+
+	// --rollback and options specified
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--rollback", "2", "bees="})
+	c.Assert(err, gc.ErrorMatches, "cannot specify --rollback when setting options")
+
+	// bad revision
+	err = coretesting.InitCommand(&service.SetCommand{}, []string{"service", "--rollback", "-1"})
+	c.Assert(err, gc.ErrorMatches, "config revision must be positive")
 }
 
 func (s *SetSuite) TestSetOptionSuccess(c *gc.C) {
@@ -141,6 +149,16 @@ func (s *SetSuite) TestSetConfig(c *gc.C) {
 	c.Check(s.fake.config, gc.Equals, yamlConfigValue)
 }
 
+func (s *SetSuite) TestSetRollback(c *gc.C) {
+	ctx := coretesting.ContextForDir(c, s.dir)
+	code := cmd.Main(envcmd.Wrap(service.NewSetCommand(s.fake)), ctx, []string{
+		"dummy-service",
+		"--rollback",
+		"2"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(s.fake.rolledBack, gc.Equals, 2)
+}
+
 func (s *SetSuite) TestBlockSetConfig(c *gc.C) {
 	// Block operation
 	s.fake.err = common.ErrOperationBlocked("TestBlockSetConfig")
@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// ShareModelCommand invites users to access the current environment.
+type ShareModelCommand struct {
+	envcmd.EnvCommandBase
+	Users []names.UserTag
+}
+
+var shareModelDoc = `
+Invites one or more users to access the current environment. Unlike
+"juju environment share", no access is granted straight away: each user
+must accept the invitation with "juju accept-invite", so users who do not
+yet exist can be invited, and nobody is given access without consenting.
+
+Examples:
+ juju share-model joe
+     Invite local user "joe" to the current environment
+
+ juju share-model user1 user2@ubuntuone
+     Invite a local user and a remote user to the current environment
+`
+
+func (c *ShareModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "share-model",
+		Args:    "<user> ...",
+		Purpose: "invite users to access the current environment",
+		Doc:     shareModelDoc,
+	}
+}
+
+func (c *ShareModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no users specified")
+	}
+	for _, arg := range args {
+		if !names.IsValidUser(arg) {
+			return errors.Errorf("invalid username: %q", arg)
+		}
+		c.Users = append(c.Users, names.NewUserTag(arg))
+	}
+	return nil
+}
+
+func (c *ShareModelCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.InviteEnvironmentUsers(c.Users...); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	uuid := client.EnvironmentUUID()
+	fmt.Fprintf(ctx.Stdout, "To accept, each invited user should run:\n  juju accept-invite %s\n", uuid)
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type ShareModelSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&ShareModelSuite{})
+
+func (s *ShareModelSuite) TestInit(c *gc.C) {
+	command := &ShareModelCommand{}
+	err := testing.InitCommand(command, nil)
+	c.Assert(err, gc.ErrorMatches, "no users specified")
+
+	err = testing.InitCommand(command, []string{"bob", "sam@ubuntuone"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(command.Users, jc.DeepEquals, []names.UserTag{
+		names.NewUserTag("bob"), names.NewUserTag("sam@ubuntuone"),
+	})
+
+	err = testing.InitCommand(&ShareModelCommand{}, []string{"not valid/0"})
+	c.Assert(err, gc.ErrorMatches, `invalid username: "not valid/0"`)
+}
+
+func (s *ShareModelSuite) TestShareModel(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShareModelCommand{}), "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		"To accept, each invited user should run:\n  juju accept-invite "+s.State.EnvironUUID()+"\n")
+
+	invitation, err := s.State.EnvironmentInvitation(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitation.CreatedBy(), gc.Equals, s.AdminUserTag(c).Username())
+	_, err = s.State.EnvironmentUser(names.NewUserTag("bob"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
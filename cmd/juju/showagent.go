@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/introspection"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+// introspectionAPI defines the methods on the introspection API that
+// the show-agent command uses.
+type introspectionAPI interface {
+	WorkerReports(tag names.Tag) ([]params.AgentWorkerReport, error)
+	Close() error
+}
+
+var newIntrospectionAPI = func(c *ShowAgentCommand) (introspectionAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return introspection.NewClient(root), nil
+}
+
+// ShowAgentCommand reports on the workers running in an agent.
+type ShowAgentCommand struct {
+	envcmd.EnvCommandBase
+	out output.Output
+	tag names.Tag
+}
+
+const showAgentDoc = `
+Show the workers running in an agent, with the state of each, how many
+times it has been started and the error it last stopped with. This helps
+to find out why an agent is stuck.
+
+The agent may be given as a machine id or tag. Only the agent of a
+state server machine running the API server connected to can be shown.
+
+Examples:
+    juju show-agent 0
+    juju show-agent machine-0 --format yaml
+`
+
+func (c *ShowAgentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-agent",
+		Args:    "<machine>",
+		Purpose: "show the workers running in an agent",
+		Doc:     showAgentDoc,
+	}
+}
+
+func (c *ShowAgentCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatAgentWorkersTabular,
+	})
+}
+
+func (c *ShowAgentCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no agent specified")
+	}
+	agent := args[0]
+	if names.IsValidMachine(agent) {
+		c.tag = names.NewMachineTag(agent)
+	} else {
+		tag, err := names.ParseMachineTag(agent)
+		if err != nil {
+			return errors.Errorf("invalid agent %q", agent)
+		}
+		c.tag = tag
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// agentWorker describes a worker shown by the show-agent command.
+type agentWorker struct {
+	Name       string   `json:"name" yaml:"name"`
+	State      string   `json:"state" yaml:"state"`
+	Inputs     []string `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	StartCount int      `json:"start-count" yaml:"start-count"`
+	LastError  string   `json:"last-error,omitempty" yaml:"last-error,omitempty"`
+}
+
+// ID implements output.Identifier.
+func (w agentWorker) ID() string {
+	return w.Name
+}
+
+func (c *ShowAgentCommand) Run(ctx *cmd.Context) error {
+	client, err := newIntrospectionAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	reports, err := client.WorkerReports(c.tag)
+	if err != nil {
+		return errors.Annotatef(err, "cannot show agent %q", c.tag)
+	}
+	workers := make([]agentWorker, len(reports))
+	for i, report := range reports {
+		workers[i] = agentWorker{
+			Name:       report.Name,
+			State:      report.State,
+			Inputs:     report.Inputs,
+			StartCount: report.StartCount,
+			LastError:  report.LastError,
+		}
+	}
+	return c.out.Write(ctx, workers)
+}
+
+// formatAgentWorkersTabular returns a table of the workers of an agent.
+func formatAgentWorkersTabular(value interface{}) ([]byte, error) {
+	workers, ok := value.([]agentWorker)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", workers, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "WORKER\tSTATE\tSTARTS\tINPUTS\tLAST ERROR")
+	for _, w := range workers {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", w.Name, w.State, w.StartCount, strings.Join(w.Inputs, ","), w.LastError)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type ShowAgentSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeIntrospectionAPI
+}
+
+var _ = gc.Suite(&ShowAgentSuite{})
+
+type fakeIntrospectionAPI struct {
+	tag     names.Tag
+	reports []params.AgentWorkerReport
+	err     error
+	closed  bool
+}
+
+func (f *fakeIntrospectionAPI) WorkerReports(tag names.Tag) ([]params.AgentWorkerReport, error) {
+	f.tag = tag
+	return f.reports, f.err
+}
+
+func (f *fakeIntrospectionAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *ShowAgentSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeIntrospectionAPI{
+		reports: []params.AgentWorkerReport{{
+			Name:       "api",
+			State:      "started",
+			StartCount: 1,
+		}, {
+			Name:       "uniter",
+			State:      "stopped",
+			Inputs:     []string{"api", "leadership"},
+			StartCount: 3,
+			LastError:  "hook failed",
+		}},
+	}
+	s.PatchValue(&newIntrospectionAPI, func(_ *ShowAgentCommand) (introspectionAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ShowAgentSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShowAgentCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *ShowAgentSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		tag  names.Tag
+		err  string
+	}{{
+		err: "no agent specified",
+	}, {
+		args: []string{"0"},
+		tag:  names.NewMachineTag("0"),
+	}, {
+		args: []string{"machine-1-lxc-0"},
+		tag:  names.NewMachineTag("1/lxc/0"),
+	}, {
+		args: []string{"wordpress/0"},
+		err:  `invalid agent "wordpress/0"`,
+	}, {
+		args: []string{"0", "1"},
+		err:  `unrecognized args: \["1"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := &ShowAgentCommand{}
+		err := testing.InitCommand(command, test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.tag, gc.Equals, test.tag)
+	}
+}
+
+func (s *ShowAgentSuite) TestTabular(c *gc.C) {
+	out, err := s.run(c, "0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"WORKER STATE   STARTS INPUTS         LAST ERROR\n"+
+		"api    started 1                     \n"+
+		"uniter stopped 3      api,leadership hook failed\n",
+	)
+	c.Assert(s.api.tag, gc.Equals, names.NewMachineTag("0"))
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ShowAgentSuite) TestQuiet(c *gc.C) {
+	out, err := s.run(c, "0", "--quiet")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "api\nuniter\n")
+}
+
+func (s *ShowAgentSuite) TestJSONLines(c *gc.C) {
+	out, err := s.run(c, "0", "--format", "jsonl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		`{"name":"api","state":"started","start-count":1}`+"\n"+
+		`{"name":"uniter","state":"stopped","inputs":["api","leadership"],"start-count":3,"last-error":"hook failed"}`+"\n",
+	)
+}
+
+func (s *ShowAgentSuite) TestYaml(c *gc.C) {
+	out, err := s.run(c, "machine-0", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"- name: api\n"+
+		"  state: started\n"+
+		"  start-count: 1\n"+
+		"- name: uniter\n"+
+		"  state: stopped\n"+
+		"  inputs:\n"+
+		"  - api\n"+
+		"  - leadership\n"+
+		"  start-count: 3\n"+
+		"  last-error: hook failed\n",
+	)
+}
+
+func (s *ShowAgentSuite) TestError(c *gc.C) {
+	s.api.err = errors.New(`agent "machine-1" on this API server not found`)
+	_, err := s.run(c, "1")
+	c.Assert(err, gc.ErrorMatches, `cannot show agent "machine-1": agent "machine-1" on this API server not found`)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+// upgradeInfoAPI defines the methods on the client API that the
+// show-upgrade command uses.
+type upgradeInfoAPI interface {
+	UpgradeInfo() (params.UpgradeInfo, error)
+	Close() error
+}
+
+var getUpgradeInfoAPI = func(c *ShowUpgradeCommand) (upgradeInfoAPI, error) {
+	return c.NewAPIClient()
+}
+
+// ShowUpgradeCommand reports how the state servers are coordinating
+// the upgrade in progress.
+type ShowUpgradeCommand struct {
+	envcmd.EnvCommandBase
+	out output.Output
+}
+
+const showUpgradeDoc = `
+Show the upgrade that the state servers of the environment are running.
+
+Each state server signals that it is ready once it is running the new
+version. When every provisioned state server is ready, the master state
+server upgrades the database while the others wait; then the others run
+their own upgrade steps. The state of each state server is one of:
+
+    pending   not yet running the new version
+    ready     waiting for the other state servers
+    done      finished upgrading
+
+An upgrade that does not complete in time is aborted, and may be reset
+with "juju upgrade-juju --reset-previous-upgrade".
+
+Examples:
+    juju show-upgrade
+    juju show-upgrade --format json
+`
+
+func (c *ShowUpgradeCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-upgrade",
+		Purpose: "show the progress of an upgrade across the state servers",
+		Doc:     showUpgradeDoc,
+	}
+}
+
+func (c *ShowUpgradeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *ShowUpgradeCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// upgradeInfo describes an upgrade shown by the show-upgrade command.
+type upgradeInfo struct {
+	PreviousVersion string            `json:"previous-version" yaml:"previous-version"`
+	TargetVersion   string            `json:"target-version" yaml:"target-version"`
+	Status          string            `json:"status" yaml:"status"`
+	Started         string            `json:"started" yaml:"started"`
+	StateServers    map[string]string `json:"state-servers" yaml:"state-servers"`
+}
+
+// ID implements output.Identifier.
+func (u upgradeInfo) ID() string {
+	return u.TargetVersion
+}
+
+func (c *ShowUpgradeCommand) Run(ctx *cmd.Context) error {
+	client, err := getUpgradeInfoAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	info, err := client.UpgradeInfo()
+	if params.IsCodeNotFound(err) {
+		ctx.Infof("no upgrade in progress")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot show upgrade")
+	}
+	stateServers := make(map[string]string)
+	for _, id := range info.StateServersPending {
+		stateServers[id] = "pending"
+	}
+	for _, id := range info.StateServersReady {
+		stateServers[id] = "ready"
+	}
+	for _, id := range info.StateServersDone {
+		stateServers[id] = "done"
+	}
+	return c.out.Write(ctx, upgradeInfo{
+		PreviousVersion: info.PreviousVersion.String(),
+		TargetVersion:   info.TargetVersion.String(),
+		Status:          info.Status,
+		Started:         info.Started.UTC().Format(time.RFC3339),
+		StateServers:    stateServers,
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+)
+
+type ShowUpgradeSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeUpgradeInfoAPI
+}
+
+var _ = gc.Suite(&ShowUpgradeSuite{})
+
+type fakeUpgradeInfoAPI struct {
+	info   params.UpgradeInfo
+	err    error
+	closed bool
+}
+
+func (f *fakeUpgradeInfoAPI) UpgradeInfo() (params.UpgradeInfo, error) {
+	return f.info, f.err
+}
+
+func (f *fakeUpgradeInfoAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *ShowUpgradeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeUpgradeInfoAPI{
+		info: params.UpgradeInfo{
+			PreviousVersion:     version.MustParse("1.24.7"),
+			TargetVersion:       version.MustParse("1.25.0"),
+			Status:              "running",
+			Started:             time.Date(2015, 10, 1, 9, 30, 0, 0, time.UTC),
+			StateServersReady:   []string{"0", "1"},
+			StateServersPending: []string{"2"},
+			StateServersDone:    []string{"0"},
+		},
+	}
+	s.PatchValue(&getUpgradeInfoAPI, func(_ *ShowUpgradeCommand) (upgradeInfoAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ShowUpgradeSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(envcmd.Wrap(&ShowUpgradeCommand{}), []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+func (s *ShowUpgradeSuite) TestShowUpgrade(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShowUpgradeCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"previous-version: 1.24.7\n"+
+		"target-version: 1.25.0\n"+
+		"status: running\n"+
+		"started: 2015-10-01T09:30:00Z\n"+
+		"state-servers:\n"+
+		"  \"0\": done\n"+
+		"  \"1\": ready\n"+
+		"  \"2\": pending\n")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ShowUpgradeSuite) TestShowUpgradeJSON(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShowUpgradeCommand{}), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `{"previous-version":"1.24.7","target-version":"1.25.0",`+
+		`"status":"running","started":"2015-10-01T09:30:00Z",`+
+		`"state-servers":{"0":"done","1":"ready","2":"pending"}}`+"\n")
+}
+
+func (s *ShowUpgradeSuite) TestNoUpgrade(c *gc.C) {
+	s.api.err = &params.Error{Code: params.CodeNotFound, Message: "current upgrade info not found"}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShowUpgradeCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no upgrade in progress\n")
+}
+
+func (s *ShowUpgradeSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&ShowUpgradeCommand{}))
+	c.Assert(err, gc.ErrorMatches, "cannot show upgrade: boom")
+}
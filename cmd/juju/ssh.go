@@ -5,6 +5,8 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
@@ -12,10 +14,12 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	"github.com/juju/utils"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/utils/ssh"
@@ -24,22 +28,36 @@ import (
 // SSHCommand is responsible for launching a ssh shell on a given unit or machine.
 type SSHCommand struct {
 	SSHCommon
+	tunnelStdio bool
 }
 
 // SSHCommon provides common methods for SSHCommand, SCPCommand and DebugHooksCommand.
 type SSHCommon struct {
 	envcmd.EnvCommandBase
-	proxy     bool
-	pty       bool
-	Target    string
-	Args      []string
-	apiClient sshAPIClient
-	apiAddr   string
+	proxy           bool
+	tunnel          bool
+	pty             bool
+	noHostKeyChecks bool
+	Target          string
+	Args            []string
+	apiClient       sshAPIClient
+	apiAddr         string
+
+	// knownHosts holds the known_hosts lines for the machines
+	// resolved by userHostFromTarget.
+	knownHosts []string
+
+	// hostKeysMissing records that the host keys of a machine
+	// resolved by userHostFromTarget are not known, so that they
+	// cannot be verified.
+	hostKeysMissing bool
 }
 
 func (c *SSHCommon) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.proxy, "proxy", true, "proxy through the API server")
+	f.BoolVar(&c.tunnel, "tunnel", false, "tunnel the connection through the API server's websocket endpoint")
 	f.BoolVar(&c.pty, "pty", true, "enable pseudo-tty allocation")
+	f.BoolVar(&c.noHostKeyChecks, "no-host-key-checks", false, "skip verification of the machine's SSH host keys (insecure)")
 }
 
 // setProxyCommand sets the proxy command option.
@@ -56,6 +74,21 @@ func (c *SSHCommon) setProxyCommand(options *ssh.Options) error {
 	return nil
 }
 
+// setTunnelCommand sets the proxy command option so that ssh
+// connects through a tunnel opened by the API server.
+func (c *SSHCommon) setTunnelCommand(options *ssh.Options) error {
+	juju, err := getJujuExecutable()
+	if err != nil {
+		return fmt.Errorf("failed to get juju executable path: %v", err)
+	}
+	args := []string{"ssh", "--tunnel-stdio"}
+	if envName := c.ConnectionName(); envName != "" {
+		args = append(args, "-e", envName)
+	}
+	options.SetProxyCommand(append([]string{juju}, append(args, "%h")...)...)
+	return nil
+}
+
 const sshDoc = `
 Launch an ssh shell on the machine identified by the <target> parameter.
 <target> can be either a machine id  as listed by "juju status" in the
@@ -83,6 +116,22 @@ Connect to the first mysql unit and run 'ls -la /var/log/juju':
 Connect to the first jenkins unit as the user jenkins:
 
     juju ssh jenkins@jenkins/0
+
+Connect to machine 2 through the API server, for example when it only
+has an address on a private network:
+
+    juju ssh --tunnel 2
+
+With --tunnel, the connection is relayed through a websocket to the API
+server, which forwards it to the SSH port on the machine's private
+address. Unlike --proxy, this does not require SSH access to the API
+server machine itself.
+
+The SSH host keys reported by the target machine's agent are used to
+verify the machine's identity, unless --no-host-key-checks is specified.
+If the machine has not yet reported its keys, or the API server cannot
+provide them, the machine's keys are accepted without verification as
+in earlier versions of juju.
 `
 
 func (c *SSHCommand) Info() *cmd.Info {
@@ -94,6 +143,11 @@ func (c *SSHCommand) Info() *cmd.Info {
 	}
 }
 
+func (c *SSHCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SSHCommon.SetFlags(f)
+	f.BoolVar(&c.tunnelStdio, "tunnel-stdio", false, "relay standard input and output to the SSH server at <target> through the API server (used by --tunnel)")
+}
+
 func (c *SSHCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("no target name specified")
@@ -119,7 +173,14 @@ func (c *SSHCommon) getSSHOptions(enablePty bool) (*ssh.Options, error) {
 		options.EnablePTY()
 	}
 	var err error
-	if c.proxy, err = c.proxySSH(); err != nil {
+	if c.tunnel {
+		// The tunnel is opened by the API server, so the
+		// machines' private addresses are used.
+		c.proxy = true
+		if err := c.setTunnelCommand(&options); err != nil {
+			return nil, err
+		}
+	} else if c.proxy, err = c.proxySSH(); err != nil {
 		return nil, err
 	} else if c.proxy {
 		if err := c.setProxyCommand(&options); err != nil {
@@ -142,6 +203,9 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 			}
 		}()
 	}
+	if c.tunnelStdio {
+		return c.relayTunnel(ctx)
+	}
 	options, err := c.getSSHOptions(c.pty)
 	if err != nil {
 		return err
@@ -151,6 +215,11 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 	if err != nil {
 		return err
 	}
+	cleanup, err := c.setHostKeyChecks(options)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 	cmd := ssh.Command(user+"@"+host, c.Args, options)
 	cmd.Stdin = ctx.Stdin
 	cmd.Stdout = ctx.Stdout
@@ -158,6 +227,32 @@ func (c *SSHCommand) Run(ctx *cmd.Context) error {
 	return cmd.Run()
 }
 
+// relayTunnel opens a tunnel through the API server to the SSH server
+// at the address c.Target, and copies data between it and the
+// command's standard input and output. It is run by ssh as the proxy
+// command when --tunnel is specified.
+func (c *SSHCommand) relayTunnel(ctx *cmd.Context) error {
+	client, err := c.ensureAPIClient()
+	if err != nil {
+		return err
+	}
+	conn, err := client.SSHTunnel(c.Target)
+	if err != nil {
+		return errors.Annotatef(err, "cannot open SSH tunnel to %s", c.Target)
+	}
+	defer conn.Close()
+	done := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(conn, ctx.Stdin)
+		done <- err
+	}()
+	go func() {
+		_, err := io.Copy(ctx.Stdout, conn)
+		done <- err
+	}()
+	return <-done
+}
+
 // proxySSH returns true iff both c.proxy and
 // the proxy-ssh environment configuration
 // are true.
@@ -203,6 +298,8 @@ type sshAPIClient interface {
 	EnvironmentGet() (map[string]interface{}, error)
 	PublicAddress(target string) (string, error)
 	PrivateAddress(target string) (string, error)
+	SSHHostKeys(target string) ([]string, error)
+	SSHTunnel(address string) (io.ReadWriteCloser, error)
 	ServiceCharmRelations(service string) ([]string, error)
 	Close() error
 }
@@ -255,12 +352,76 @@ func (c *SSHCommon) userHostFromTarget(target string) (user, host string, err er
 			addr, err = c.apiClient.PublicAddress(target)
 		}
 		if err == nil {
+			if err := c.addHostKeys(target, addr); err != nil {
+				return "", "", err
+			}
 			return user, addr, nil
 		}
 	}
 	return "", "", err
 }
 
+// addHostKeys records the SSH host keys of the machine identified by
+// target, which is reachable at host, so that they can be verified by
+// ssh. If the API server does not support host keys, or the machine
+// has not reported any, the keys are recorded as missing.
+func (c *SSHCommon) addHostKeys(target, host string) error {
+	if c.noHostKeyChecks {
+		return nil
+	}
+	keys, err := c.apiClient.SSHHostKeys(target)
+	if params.IsCodeNotImplemented(err) {
+		logger.Warningf("API server does not support SSH host keys; not verifying the host key of %q", target)
+		c.hostKeysMissing = true
+		return nil
+	} else if err != nil && !params.IsCodeNotFound(err) {
+		return errors.Annotatef(err, "cannot get SSH host keys for %q", target)
+	}
+	if len(keys) == 0 {
+		logger.Warningf("no SSH host keys known for %q; not verifying its host key", target)
+		c.hostKeysMissing = true
+		return nil
+	}
+	for _, key := range keys {
+		c.knownHosts = append(c.knownHosts, host+" "+key)
+	}
+	return nil
+}
+
+// newKnownHostsFile returns a new file in which to write the host keys
+// that ssh is to verify.
+var newKnownHostsFile = func() (*os.File, error) {
+	return ioutil.TempFile("", "juju-known-hosts")
+}
+
+// setHostKeyChecks configures options so that ssh verifies the host
+// keys recorded by userHostFromTarget. The returned function removes
+// the known hosts file once ssh has finished with it. If the keys of
+// any machine are missing, the options are left to accept whatever
+// keys the hosts present.
+func (c *SSHCommon) setHostKeyChecks(options *ssh.Options) (func(), error) {
+	if len(c.knownHosts) == 0 || c.hostKeysMissing {
+		return func() {}, nil
+	}
+	f, err := newKnownHostsFile()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create known hosts file")
+	}
+	defer f.Close()
+	cleanup := func() {
+		if err := os.Remove(f.Name()); err != nil {
+			logger.Warningf("cannot remove known hosts file: %v", err)
+		}
+	}
+	if _, err := f.WriteString(strings.Join(c.knownHosts, "\n") + "\n"); err != nil {
+		cleanup()
+		return nil, errors.Annotate(err, "cannot write known hosts file")
+	}
+	options.SetKnownHostsFile(f.Name())
+	options.EnableStrictHostKeyChecking()
+	return cleanup, nil
+}
+
 // AllowInterspersedFlags for ssh/scp is set to false so that
 // flags after the unit name are passed through to ssh, for eg.
 // `juju ssh -v service-name/0 uname -a`.
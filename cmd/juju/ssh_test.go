@@ -6,17 +6,21 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strings"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 
 	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
@@ -34,7 +38,8 @@ type SSHSuite struct {
 
 type SSHCommonSuite struct {
 	testing.JujuConnSuite
-	bin string
+	bin        string
+	knownHosts string
 }
 
 func (s *SSHCommonSuite) SetUpTest(c *gc.C) {
@@ -53,15 +58,29 @@ func (s *SSHCommonSuite) SetUpTest(c *gc.C) {
 	}
 	client, _ := ssh.NewOpenSSHClient()
 	s.PatchValue(&ssh.DefaultClient, client)
+
+	s.knownHosts = filepath.Join(c.MkDir(), "known_hosts")
+	s.PatchValue(&newKnownHostsFile, func() (*os.File, error) {
+		return os.Create(s.knownHosts)
+	})
+}
+
+// output returns the arguments recorded by the fake ssh or scp command,
+// with the path of the known hosts file replaced by "KNOWN_HOSTS".
+func (s *SSHCommonSuite) output(out string) string {
+	return strings.Replace(out, s.knownHosts, "KNOWN_HOSTS", -1)
 }
 
 const (
-	noProxy           = `-o StrictHostKeyChecking no -o PasswordAuthentication no -o ServerAliveInterval 30 `
-	args              = `-o StrictHostKeyChecking no -o ProxyCommand juju ssh --proxy=false --pty=false localhost nc %h %p -o PasswordAuthentication no -o ServerAliveInterval 30 `
-	commonArgsNoProxy = noProxy + `-o UserKnownHostsFile /dev/null `
-	commonArgs        = args + `-o UserKnownHostsFile /dev/null `
-	sshArgs           = args + `-t -t -o UserKnownHostsFile /dev/null `
-	sshArgsNoProxy    = noProxy + `-t -t -o UserKnownHostsFile /dev/null `
+	noProxy           = `-o StrictHostKeyChecking yes -o PasswordAuthentication no -o ServerAliveInterval 30 `
+	args              = `-o StrictHostKeyChecking yes -o ProxyCommand juju ssh --proxy=false --pty=false localhost nc %h %p -o PasswordAuthentication no -o ServerAliveInterval 30 `
+	commonArgsNoProxy = noProxy + `-o UserKnownHostsFile KNOWN_HOSTS `
+	commonArgs        = args + `-o UserKnownHostsFile KNOWN_HOSTS `
+	sshArgs           = args + `-t -t -o UserKnownHostsFile KNOWN_HOSTS `
+	sshArgsNoProxy    = noProxy + `-t -t -o UserKnownHostsFile KNOWN_HOSTS `
+	sshArgsTunnel     = `-o StrictHostKeyChecking yes -o ProxyCommand juju ssh --tunnel-stdio -e dummyenv %h -o PasswordAuthentication no -o ServerAliveInterval 30 -t -t -o UserKnownHostsFile KNOWN_HOSTS `
+
+	sshArgsNoHostKeyChecks = `-o StrictHostKeyChecking no -o PasswordAuthentication no -o ServerAliveInterval 30 -t -t -o UserKnownHostsFile /dev/null `
 )
 
 var sshTests = []struct {
@@ -99,6 +118,11 @@ var sshTests = []struct {
 		[]string{"ssh", "--proxy=false", "mysql/0"},
 		sshArgsNoProxy + "ubuntu@dummyenv-0.dns",
 	},
+	{
+		"connect to unit mysql/0 through a tunnel",
+		[]string{"ssh", "--tunnel", "mysql/0"},
+		sshArgsTunnel + "ubuntu@dummyenv-0.internal",
+	},
 }
 
 func (s *SSHSuite) TestSSHCommand(c *gc.C) {
@@ -125,7 +149,7 @@ func (s *SSHSuite) TestSSHCommand(c *gc.C) {
 		code := cmd.Main(jujucmd, ctx, t.args)
 		c.Check(code, gc.Equals, 0)
 		c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
-		c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, t.result)
+		c.Check(s.output(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n")), gc.Equals, t.result)
 	}
 }
 
@@ -140,7 +164,145 @@ func (s *SSHSuite) TestSSHCommandEnvironProxySSH(c *gc.C) {
 	code := cmd.Main(jujucmd, ctx, []string{"ssh", "0"})
 	c.Check(code, gc.Equals, 0)
 	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
-	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns")
+	c.Check(s.output(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n")), gc.Equals, sshArgsNoProxy+"ubuntu@dummyenv-0.dns")
+}
+
+func (s *SSHSuite) TestSSHCommandTunnel(c *gc.C) {
+	s.makeMachines(1, c, true)
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(&SSHCommand{}), ctx, []string{"--tunnel", "mongo@0", "uname", "-a"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	// ssh is told to reach the machine through "juju ssh --tunnel-stdio",
+	// which asks the API server for a tunnel to the machine's address.
+	c.Check(s.output(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n")), gc.Equals, sshArgsTunnel+"mongo@dummyenv-0.internal uname -a")
+}
+
+func (s *SSHSuite) TestSSHCommandNoHostKeys(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAddresses(m, c)
+
+	// The machine has not reported its keys, so they are accepted
+	// without verification.
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(&SSHCommand{}), ctx, []string{"--proxy=false", "0"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoHostKeyChecks+"ubuntu@dummyenv-0.dns")
+}
+
+type fakeHostKeysAPIClient struct {
+	sshAPIClient
+	keys []string
+	err  error
+}
+
+func (f *fakeHostKeysAPIClient) SSHHostKeys(target string) ([]string, error) {
+	return f.keys, f.err
+}
+
+func (s *SSHSuite) TestAddHostKeysFallback(c *gc.C) {
+	for i, test := range []struct {
+		about string
+		keys  []string
+		err   error
+	}{{
+		about: "API server without host keys",
+		err:   &params.Error{Code: params.CodeNotImplemented, Message: "no such request"},
+	}, {
+		about: "machine without host keys",
+		err:   &params.Error{Code: params.CodeNotFound, Message: "SSH host keys for machine 0 not found"},
+	}, {
+		about: "no host keys returned",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		sshCmd := &SSHCommon{
+			apiClient:  &fakeHostKeysAPIClient{keys: test.keys, err: test.err},
+			knownHosts: []string{"dummyenv-1.dns ssh-rsa rsa-1"},
+		}
+		err := sshCmd.addHostKeys("0", "dummyenv-0.dns")
+		c.Assert(err, jc.ErrorIsNil)
+
+		// The previous behaviour is kept, without strict host key
+		// checking, even for the machines whose keys are known.
+		var options ssh.Options
+		cleanup, err := sshCmd.setHostKeyChecks(&options)
+		c.Assert(err, jc.ErrorIsNil)
+		cleanup()
+		_, err = os.Stat(s.knownHosts)
+		c.Assert(err, jc.Satisfies, os.IsNotExist)
+	}
+}
+
+func (s *SSHSuite) TestAddHostKeysError(c *gc.C) {
+	sshCmd := &SSHCommon{
+		apiClient: &fakeHostKeysAPIClient{err: errors.New("boom")},
+	}
+	err := sshCmd.addHostKeys("0", "dummyenv-0.dns")
+	c.Assert(err, gc.ErrorMatches, `cannot get SSH host keys for "0": boom`)
+}
+
+func (s *SSHSuite) TestSSHCommandNoHostKeyChecks(c *gc.C) {
+	s.makeMachines(1, c, true)
+	ctx := coretesting.Context(c)
+	code := cmd.Main(envcmd.Wrap(&SSHCommand{}), ctx, []string{"--proxy=false", "--no-host-key-checks", "0"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(ctx.Stderr.(*bytes.Buffer).String(), gc.Equals, "")
+	c.Check(strings.TrimRight(ctx.Stdout.(*bytes.Buffer).String(), "\r\n"), gc.Equals, sshArgsNoHostKeyChecks+"ubuntu@dummyenv-0.dns")
+}
+
+func (s *SSHSuite) TestSetHostKeyChecks(c *gc.C) {
+	sshCmd := &SSHCommon{
+		knownHosts: []string{
+			"dummyenv-0.dns ssh-rsa rsa-0",
+			"dummyenv-0.dns ssh-dss dsa-0",
+		},
+	}
+	var options ssh.Options
+	cleanup, err := sshCmd.setHostKeyChecks(&options)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(s.knownHosts)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, ""+
+		"dummyenv-0.dns ssh-rsa rsa-0\n"+
+		"dummyenv-0.dns ssh-dss dsa-0\n",
+	)
+	cleanup()
+	_, err = os.Stat(s.knownHosts)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SSHSuite) TestSetHostKeyChecksNoKeys(c *gc.C) {
+	var options ssh.Options
+	cleanup, err := (&SSHCommon{}).setHostKeyChecks(&options)
+	c.Assert(err, jc.ErrorIsNil)
+	cleanup()
+	_, err = os.Stat(s.knownHosts)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *SSHSuite) TestSSHTunnelStdio(c *gc.C) {
+	tunnel := &fakeTunnel{Reader: strings.NewReader("from the machine")}
+	client := &fakeTunnelAPIClient{tunnel: tunnel}
+	sshCmd := &SSHCommand{SSHCommon: SSHCommon{apiClient: client}}
+	ctx := coretesting.Context(c)
+	ctx.Stdin = strings.NewReader("to the machine")
+	err := coretesting.InitCommand(sshCmd, []string{"--tunnel-stdio", "10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = sshCmd.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(client.address, gc.Equals, "10.0.0.1")
+	c.Assert(tunnel.closed, jc.IsTrue)
+}
+
+func (s *SSHSuite) TestSSHTunnelStdioError(c *gc.C) {
+	client := &fakeTunnelAPIClient{err: errors.New("boom")}
+	sshCmd := &SSHCommand{SSHCommon: SSHCommon{apiClient: client}}
+	err := coretesting.InitCommand(sshCmd, []string{"--tunnel-stdio", "10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = sshCmd.Run(coretesting.Context(c))
+	c.Assert(err, gc.ErrorMatches, "cannot open SSH tunnel to 10.0.0.1: boom")
 }
 
 func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
@@ -154,9 +316,10 @@ func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	for i := 0; i < t.NumMethod(); i++ {
 		name := t.Method(i).Name
 
-		// Close isn't an API method and ServiceCharmRelations is not
-		// relevant to "juju ssh".
-		if name == "Close" || name == "ServiceCharmRelations" {
+		// Close isn't an API method, SSHTunnel is a websocket
+		// stream rather than an API call, and ServiceCharmRelations
+		// is not relevant to "juju ssh".
+		if name == "Close" || name == "SSHTunnel" || name == "ServiceCharmRelations" {
 			continue
 		}
 		c.Logf("checking %q", name)
@@ -164,6 +327,35 @@ func (s *SSHSuite) TestSSHWillWorkInUpgrade(c *gc.C) {
 	}
 }
 
+type fakeTunnelAPIClient struct {
+	sshAPIClient
+	tunnel  *fakeTunnel
+	address string
+	err     error
+}
+
+func (f *fakeTunnelAPIClient) SSHTunnel(address string) (io.ReadWriteCloser, error) {
+	f.address = address
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.tunnel, nil
+}
+
+type fakeTunnel struct {
+	io.Reader
+	closed bool
+}
+
+func (t *fakeTunnel) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (t *fakeTunnel) Close() error {
+	t.closed = true
+	return nil
+}
+
 type callbackAttemptStarter struct {
 	next func() bool
 }
@@ -244,6 +436,11 @@ func (s *SSHCommonSuite) makeMachines(n int, c *gc.C, setAddresses bool) []*stat
 		// machine has been provisioned
 		inst, md := testing.AssertStartInstance(c, s.Environ, m.Id())
 		c.Assert(m.SetProvisioned(inst.Id(), "fake_nonce", md), gc.IsNil)
+		err = s.State.SetSSHHostKeys(m.MachineTag(), state.SSHHostKeys{
+			"ssh-rsa rsa-" + m.Id(),
+			"ssh-dss dsa-" + m.Id(),
+		})
+		c.Assert(err, jc.ErrorIsNil)
 		machines[i] = m
 	}
 	return machines
@@ -8,9 +8,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"golang.org/x/net/context"
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/api"
@@ -27,6 +31,13 @@ type StatusCommand struct {
 	out      cmd.Output
 	patterns []string
 	isoTime  bool
+	allEnvs  bool
+	summary  bool
+	include  []string
+	exclude  []string
+	sections []string
+
+	utilization bool
 }
 
 var statusDoc = `
@@ -58,6 +69,29 @@ will be displayed.
 Wildcards ('*') may be specified in service/unit names to match any sequence
 of characters. For example, 'nova-*' will match any service whose name begins
 with 'nova-': 'nova-compute', 'nova-volume', etc.
+
+The --all-models option reports a summary of every environment in the
+system instead: the number of machines, services and units in each.
+Only the system administrator may use it, and patterns may not be
+combined with it. The yaml, json and tabular formats are supported.
+
+The --include and --exclude options restrict the status reported to
+the listed sections, or to all but the listed sections: any of
+machines, services, relations and networks. Sections that are not
+reported are shown empty, and are not computed by the server, so
+narrow queries are faster. Service relations are only shown with the
+relations section.
+
+The --summary option reports one line per service instead of listing
+every unit: the service's most severe unit status, how many units and
+machines it has, and how many of its units are in each workload and
+agent status. It is intended for large environments, and is always
+displayed in tabular form.
+
+The --utilization option reports the CPU and memory used by the
+processes of each unit, as last sampled by the unit's machine agent
+within the past 15 minutes, so that units starving their neighbours
+can be spotted. CPU use is given as a percentage of one CPU.
 `
 
 func (c *StatusCommand) Info() *cmd.Info {
@@ -72,6 +106,11 @@ func (c *StatusCommand) Info() *cmd.Info {
 
 func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.isoTime, "utc", false, "display time as UTC in RFC3339 format")
+	f.BoolVar(&c.allEnvs, "all-models", false, "summarise every environment in the system")
+	f.BoolVar(&c.summary, "summary", false, "summarise each service on a single line")
+	f.BoolVar(&c.utilization, "utilization", false, "display the CPU and memory used by each unit")
+	f.Var(cmd.NewStringsValue(nil, &c.include), "include", "report only the listed status sections")
+	f.Var(cmd.NewStringsValue(nil, &c.exclude), "exclude", "report all but the listed status sections")
 
 	defaultFormat := "yaml"
 	if c.CompatVersion() > 1 {
@@ -89,6 +128,20 @@ func (c *StatusCommand) SetFlags(f *gnuflag.FlagSet) {
 }
 
 func (c *StatusCommand) Init(args []string) error {
+	if c.allEnvs && len(args) > 0 {
+		return errors.New("cannot specify patterns with --all-models")
+	}
+	if c.allEnvs && c.summary {
+		return errors.New("cannot specify --summary with --all-models")
+	}
+	sections, err := selectStatusSections(c.include, c.exclude)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.allEnvs && sections != nil {
+		return errors.New("cannot specify --include or --exclude with --all-models")
+	}
+	c.sections = sections
 	c.patterns = args
 	// If use of ISO time not specified on command line,
 	// check env var.
@@ -112,7 +165,7 @@ Error details:
 `
 
 type statusAPI interface {
-	Status(patterns []string) (*api.Status, error)
+	StatusContext(ctx context.Context, patterns, sections []string) (*api.Status, error)
 	Close() error
 }
 
@@ -121,6 +174,9 @@ var newApiClientForStatus = func(c *StatusCommand) (statusAPI, error) {
 }
 
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
+	if c.allEnvs {
+		return c.runAllEnvironments(ctx)
+	}
 
 	apiclient, err := newApiClientForStatus(c)
 	if err != nil {
@@ -128,7 +184,18 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
-	status, err := apiclient.Status(c.patterns)
+	// Gathering the status of a large environment can take a while,
+	// so let the user interrupt it.
+	callCtx, stop := interruptContext(ctx)
+	defer stop()
+	status, err := apiclient.StatusContext(callCtx, c.patterns, c.sections)
+	if err == context.Canceled {
+		return errors.New("status interrupted")
+	}
+	if c.sections != nil {
+		// Older servers report every section.
+		restrictStatusSections(status, c.sections)
+	}
 	if err != nil {
 		if status == nil {
 			// Status call completely failed, there is nothing to report
@@ -138,12 +205,77 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 		fmt.Fprintf(ctx.Stderr, "%v\n", err)
 	} else if status == nil {
 		return errors.Errorf("unable to obtain the current status")
+	} else if len(c.patterns) == 0 && c.sections == nil {
+		// The complete status names every entity in the environment.
+		cacheEntityNames(c.ConnectionName(), status)
 	}
 
-	result := newStatusFormatter(status, c.CompatVersion(), c.isoTime).format()
+	if c.summary {
+		_, err := ctx.Stdout.Write(formatServiceSummaries(summariseServices(status)))
+		return err
+	}
+	formatter := newStatusFormatter(status, c.CompatVersion(), c.isoTime)
+	formatter.utilization = c.utilization
+	result := formatter.format()
 	return c.out.Write(ctx, result)
 }
 
+// selectStatusSections returns the status sections to report, given
+// the values of the --include and --exclude options, or nil if every
+// section should be reported.
+func selectStatusSections(include, exclude []string) ([]string, error) {
+	if len(include) > 0 && len(exclude) > 0 {
+		return nil, errors.New("cannot specify both --include and --exclude")
+	}
+	all := set.NewStrings(params.AllStatusSections...)
+	for _, name := range append(include, exclude...) {
+		if !all.Contains(name) {
+			return nil, errors.Errorf("unknown status section %q; expected one of %s",
+				name, strings.Join(params.AllStatusSections, ", "))
+		}
+	}
+	if len(include) > 0 {
+		return include, nil
+	}
+	if len(exclude) == 0 {
+		return nil, nil
+	}
+	excluded := set.NewStrings(exclude...)
+	sections := []string{}
+	for _, name := range params.AllStatusSections {
+		if !excluded.Contains(name) {
+			sections = append(sections, name)
+		}
+	}
+	return sections, nil
+}
+
+// restrictStatusSections clears those sections of the given status
+// that are not named.
+func restrictStatusSections(status *api.Status, sections []string) {
+	if status == nil {
+		return
+	}
+	wanted := set.NewStrings(sections...)
+	if !wanted.Contains(params.StatusSectionMachines) {
+		status.Machines = nil
+	}
+	if !wanted.Contains(params.StatusSectionServices) {
+		status.Services = nil
+	} else if !wanted.Contains(params.StatusSectionRelations) {
+		for name, service := range status.Services {
+			service.Relations = nil
+			status.Services[name] = service
+		}
+	}
+	if !wanted.Contains(params.StatusSectionRelations) {
+		status.Relations = nil
+	}
+	if !wanted.Contains(params.StatusSectionNetworks) {
+		status.Networks = nil
+	}
+}
+
 type formattedStatus struct {
 	Environment string                   `json:"environment"`
 	Machines    map[string]machineStatus `json:"machines"`
@@ -169,6 +301,9 @@ type machineStatus struct {
 	Containers     map[string]machineStatus `json:"containers,omitempty" yaml:"containers,omitempty"`
 	Hardware       string                   `json:"hardware,omitempty" yaml:"hardware,omitempty"`
 	HAStatus       string                   `json:"state-server-member-status,omitempty" yaml:"state-server-member-status,omitempty"`
+
+	InstanceType          string `json:"instance-type,omitempty" yaml:"instance-type,omitempty"`
+	InstanceTypeRationale string `json:"instance-type-rationale,omitempty" yaml:"instance-type-rationale,omitempty"`
 }
 
 // A goyaml bug means we can't declare these types
@@ -195,20 +330,29 @@ func (s machineStatus) GetYAML() (tag string, value interface{}) {
 }
 
 type serviceStatus struct {
-	Err           error                 `json:"-" yaml:",omitempty"`
-	Charm         string                `json:"charm" yaml:"charm"`
-	CanUpgradeTo  string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
-	Exposed       bool                  `json:"exposed" yaml:"exposed"`
-	Life          string                `json:"life,omitempty" yaml:"life,omitempty"`
-	StatusInfo    statusInfoContents    `json:"service-status,omitempty" yaml:"service-status,omitempty"`
-	Relations     map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
-	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
-	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
-	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+	Err            error                            `json:"-" yaml:",omitempty"`
+	Charm          string                           `json:"charm" yaml:"charm"`
+	CanUpgradeTo   string                           `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
+	Exposed        bool                             `json:"exposed" yaml:"exposed"`
+	Life           string                           `json:"life,omitempty" yaml:"life,omitempty"`
+	ConfigRevision int                              `json:"config-revision,omitempty" yaml:"config-revision,omitempty"`
+	UnmappedConfig map[string]unmappedConfigSetting `json:"unmapped-config,omitempty" yaml:"unmapped-config,omitempty"`
+	StatusInfo     statusInfoContents               `json:"service-status,omitempty" yaml:"service-status,omitempty"`
+	Relations      map[string][]string              `json:"relations,omitempty" yaml:"relations,omitempty"`
+	Networks       map[string][]string              `json:"networks,omitempty" yaml:"networks,omitempty"`
+	SubordinateTo  []string                         `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
+	Units          map[string]unitStatus            `json:"units,omitempty" yaml:"units,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
 
+// unmappedConfigSetting describes a setting of a service that could not
+// be carried over when the service's charm was last changed.
+type unmappedConfigSetting struct {
+	Value  interface{} `json:"value" yaml:"value"`
+	Reason string      `json:"reason" yaml:"reason"`
+}
+
 func (s serviceStatus) MarshalJSON() ([]byte, error) {
 	if s.Err != nil {
 		return json.Marshal(errorStatus{s.Err.Error()})
@@ -242,6 +386,15 @@ type unitStatus struct {
 	OpenedPorts   []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
 	Subordinates  map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
+	Paused        bool                  `json:"paused,omitempty" yaml:"paused,omitempty"`
+	Utilization   *unitUtilization      `json:"utilization,omitempty" yaml:"utilization,omitempty"`
+}
+
+type unitUtilization struct {
+	CPU       string `json:"cpu" yaml:"cpu"`
+	Memory    string `json:"memory" yaml:"memory"`
+	Processes int    `json:"processes" yaml:"processes"`
+	Since     string `json:"since" yaml:"since"`
 }
 
 type statusInfoContents struct {
@@ -316,6 +469,7 @@ type statusFormatter struct {
 	relations     map[int]api.RelationStatus
 	isoTime       bool
 	compatVersion int
+	utilization   bool
 }
 
 func newStatusFormatter(status *api.Status, compatVersion int, isoTime bool) *statusFormatter {
@@ -391,6 +545,9 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 			Id:             machine.Id,
 			Containers:     make(map[string]machineStatus),
 			Hardware:       machine.Hardware,
+
+			InstanceType:          machine.InstanceType,
+			InstanceTypeRationale: machine.InstanceTypeRationale,
 		}
 	}
 
@@ -409,16 +566,26 @@ func (sf *statusFormatter) formatMachine(machine api.MachineStatus) machineStatu
 
 func (sf *statusFormatter) formatService(name string, service api.ServiceStatus) serviceStatus {
 	out := serviceStatus{
-		Err:           service.Err,
-		Charm:         service.Charm,
-		Exposed:       service.Exposed,
-		Life:          service.Life,
-		Relations:     service.Relations,
-		Networks:      make(map[string][]string),
-		CanUpgradeTo:  service.CanUpgradeTo,
-		SubordinateTo: service.SubordinateTo,
-		Units:         make(map[string]unitStatus),
-		StatusInfo:    sf.getServiceStatusInfo(service),
+		Err:            service.Err,
+		Charm:          service.Charm,
+		Exposed:        service.Exposed,
+		Life:           service.Life,
+		ConfigRevision: service.ConfigRevision,
+		Relations:      service.Relations,
+		Networks:       make(map[string][]string),
+		CanUpgradeTo:   service.CanUpgradeTo,
+		SubordinateTo:  service.SubordinateTo,
+		Units:          make(map[string]unitStatus),
+		StatusInfo:     sf.getServiceStatusInfo(service),
+	}
+	if len(service.UnmappedConfig) > 0 {
+		out.UnmappedConfig = make(map[string]unmappedConfigSetting)
+		for _, setting := range service.UnmappedConfig {
+			out.UnmappedConfig[setting.Key] = unmappedConfigSetting{
+				Value:  setting.Value,
+				Reason: setting.Reason,
+			}
+		}
 	}
 	if len(service.Networks.Enabled) > 0 {
 		out.Networks["enabled"] = service.Networks.Enabled
@@ -457,6 +624,15 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		PublicAddress:      unit.PublicAddress,
 		Charm:              unit.Charm,
 		Subordinates:       make(map[string]unitStatus),
+		Paused:             unit.Paused,
+	}
+	if sf.utilization && unit.Utilization != nil {
+		out.Utilization = &unitUtilization{
+			CPU:       fmt.Sprintf("%.1f%%", unit.Utilization.CPUPercent),
+			Memory:    humanize.IBytes(unit.Utilization.MemoryBytes),
+			Processes: unit.Utilization.Processes,
+			Since:     formatStatusTime(&unit.Utilization.Time, sf.isoTime),
+		}
 	}
 
 	// These legacy fields will be dropped for Juju 2.0.
@@ -88,6 +88,9 @@ func agentDoing(status statusInfoContents) string {
 // units. Any subordinate items are indented by two spaces beneath
 // their superior.
 func FormatTabular(value interface{}) ([]byte, error) {
+	if all, ok := value.(allEnvironmentsStatus); ok {
+		return formatAllEnvironmentsTabular(all)
+	}
 	fs, valueConverted := value.(formattedStatus)
 	if !valueConverted {
 		return nil, errors.Errorf("expected value of type %T, got %T", fs, value)
@@ -114,13 +117,23 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	}
 	tw.Flush()
 
+	// Utilization is only shown when requested, and then only
+	// reported by recent agents.
+	utilization := false
+	for _, u := range units {
+		if hasUtilization(u) {
+			utilization = true
+			break
+		}
+	}
+
 	pUnit := func(name string, u unitStatus, level int) {
 		message := u.WorkloadStatusInfo.Message
 		agentDoing := agentDoing(u.AgentStatusInfo)
 		if agentDoing != "" {
 			message = fmt.Sprintf("(%s) %s", agentDoing, message)
 		}
-		p(
+		values := []interface{}{
 			indent("", level*2, name),
 			u.WorkloadStatusInfo.Current,
 			u.AgentStatusInfo.Current,
@@ -128,8 +141,15 @@ func FormatTabular(value interface{}) ([]byte, error) {
 			u.Machine,
 			strings.Join(u.OpenedPorts, ","),
 			u.PublicAddress,
-			message,
-		)
+		}
+		if utilization {
+			cpu, memory := "", ""
+			if u.Utilization != nil {
+				cpu, memory = u.Utilization.CPU, u.Utilization.Memory
+			}
+			values = append(values, cpu, memory)
+		}
+		p(append(values, message)...)
 	}
 
 	// See if we have new or old data; that determines what data we can display.
@@ -142,7 +162,11 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	}
 	var header []string
 	if newStatus {
-		header = []string{"ID", "WORKLOAD-STATE", "AGENT-STATE", "VERSION", "MACHINE", "PORTS", "PUBLIC-ADDRESS", "MESSAGE"}
+		header = []string{"ID", "WORKLOAD-STATE", "AGENT-STATE", "VERSION", "MACHINE", "PORTS", "PUBLIC-ADDRESS"}
+		if utilization {
+			header = append(header, "CPU", "MEMORY")
+		}
+		header = append(header, "MESSAGE")
 	} else {
 		header = []string{"ID", "STATE", "VERSION", "MACHINE", "PORTS", "PUBLIC-ADDRESS"}
 	}
@@ -368,6 +392,20 @@ func recurseUnits(u unitStatus, il int, recurseMap func(string, unitStatus, int)
 	}
 }
 
+// hasUtilization reports whether the given unit or any of its
+// subordinates has a utilization sample.
+func hasUtilization(u unitStatus) bool {
+	if u.Utilization != nil {
+		return true
+	}
+	for _, sub := range u.Subordinates {
+		if hasUtilization(sub) {
+			return true
+		}
+	}
+	return false
+}
+
 // indent prepends a format string with the given number of spaces.
 func indent(prepend string, level int, append string) string {
 	return fmt.Sprintf("%s%*s%s", prepend, level, "", append)
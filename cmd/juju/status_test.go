@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
+	netcontext "golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 	goyaml "gopkg.in/yaml.v1"
@@ -2685,6 +2686,7 @@ func (s *StatusSuite) TestStatusAllFormats(c *gc.C) {
 type fakeApiClient struct {
 	statusReturn *api.Status
 	patternsUsed []string
+	sectionsUsed []string
 	closeCalled  bool
 }
 
@@ -2694,8 +2696,9 @@ func newFakeApiClient(statusReturn *api.Status) fakeApiClient {
 	}
 }
 
-func (a *fakeApiClient) Status(patterns []string) (*api.Status, error) {
+func (a *fakeApiClient) StatusContext(ctx netcontext.Context, patterns, sections []string) (*api.Status, error) {
 	a.patternsUsed = patterns
+	a.sectionsUsed = sections
 	return a.statusReturn, nil
 }
 
@@ -2704,6 +2707,89 @@ func (a *fakeApiClient) Close() error {
 	return nil
 }
 
+func (s *StatusSuite) TestStatusSections(c *gc.C) {
+	status := func() *api.Status {
+		return &api.Status{
+			EnvironmentName: "dummyenv",
+			Machines: map[string]api.MachineStatus{
+				"0": {Id: "0", AgentState: "started"},
+			},
+			Services: map[string]api.ServiceStatus{
+				"mysql": {
+					Charm:     "local:quantal/mysql-1",
+					Relations: map[string][]string{"server": {"wordpress"}},
+				},
+			},
+		}
+	}
+	for i, test := range []struct {
+		args     []string
+		sections []string
+		expected M
+	}{{
+		args:     []string{"--include", "machines"},
+		sections: []string{"machines"},
+		expected: M{
+			"environment": "dummyenv",
+			"machines":    M{"0": M{"agent-state": "started"}},
+			"services":    M{},
+		},
+	}, {
+		args:     []string{"--exclude", "machines,relations"},
+		sections: []string{"services", "networks"},
+		expected: M{
+			"environment": "dummyenv",
+			"machines":    M{},
+			"services": M{
+				"mysql": M{
+					"charm":          "local:quantal/mysql-1",
+					"exposed":        false,
+					"service-status": M{},
+				},
+			},
+		},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		client := newFakeApiClient(status())
+		s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+			return &client, nil
+		})
+		args := append([]string{"--format", "yaml"}, test.args...)
+		code, stdout, stderr := runStatus(c, args...)
+		c.Assert(code, gc.Equals, 0, gc.Commentf("stderr: %s", stderr))
+		c.Check(client.sectionsUsed, jc.DeepEquals, test.sections)
+		buf, err := goyaml.Marshal(test.expected)
+		c.Assert(err, jc.ErrorIsNil)
+		expected := make(M)
+		err = goyaml.Unmarshal(buf, &expected)
+		c.Assert(err, jc.ErrorIsNil)
+		actual := make(M)
+		err = goyaml.Unmarshal(stdout, &actual)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(actual, jc.DeepEquals, expected)
+	}
+}
+
+func (s *StatusSuite) TestStatusSectionsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--include", "machines", "--exclude", "services"},
+		err:  "cannot specify both --include and --exclude",
+	}, {
+		args: []string{"--include", "storage"},
+		err:  `unknown status section "storage"; expected one of machines, services, relations, networks`,
+	}, {
+		args: []string{"--all-models", "--include", "machines"},
+		err:  "cannot specify --include or --exclude with --all-models",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := coretesting.RunCommand(c, envcmd.Wrap(&StatusCommand{}), test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 // Check that the client works with an older server which doesn't
 // return the top level Relations field nor the unit and machine level
 // Agent field (they were introduced at the same time).
@@ -3132,8 +3218,8 @@ func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	}
 
 	client := fakeApiClient{}
-	var status = client.Status
-	s.PatchValue(&status, func(_ []string) (*api.Status, error) {
+	var status = client.StatusContext
+	s.PatchValue(&status, func(_ netcontext.Context, _, _ []string) (*api.Status, error) {
 		return nil, nil
 	})
 	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
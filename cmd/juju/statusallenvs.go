@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/api/systemmanager"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// allEnvironmentsAPI defines the methods on the system manager API that
+// the status command uses to report on every environment in the system.
+type allEnvironmentsAPI interface {
+	EnvironmentSummaries() ([]params.EnvironmentSummary, error)
+	Close() error
+}
+
+var newAllEnvironmentsAPIForStatus = func(c *StatusCommand) (allEnvironmentsAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return systemmanager.NewClient(root), nil
+}
+
+// environmentSummary holds the counts of the entities in a single
+// environment.
+type environmentSummary struct {
+	Name     string `json:"name" yaml:"name"`
+	UUID     string `json:"uuid" yaml:"uuid"`
+	Owner    string `json:"owner" yaml:"owner"`
+	Machines int    `json:"machines" yaml:"machines"`
+	Services int    `json:"services" yaml:"services"`
+	Units    int    `json:"units" yaml:"units"`
+}
+
+// allEnvironmentsStatus is the status reported by "juju status --all-models".
+type allEnvironmentsStatus struct {
+	Environments []environmentSummary `json:"environments" yaml:"environments"`
+}
+
+// runAllEnvironments reports a summary of every environment in the
+// system.
+func (c *StatusCommand) runAllEnvironments(ctx *cmd.Context) error {
+	client, err := newAllEnvironmentsAPIForStatus(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	envs, err := client.EnvironmentSummaries()
+	if err != nil {
+		return errors.Annotate(err, "cannot get environment status")
+	}
+	return c.out.Write(ctx, summariseEnvironments(envs))
+}
+
+// summariseEnvironments converts the environment summaries returned by
+// the API into the status to report, sorted by name.
+func summariseEnvironments(envs []params.EnvironmentSummary) allEnvironmentsStatus {
+	var result allEnvironmentsStatus
+	for _, env := range envs {
+		owner := env.OwnerTag
+		if tag, err := names.ParseUserTag(owner); err == nil {
+			owner = tag.Id()
+		}
+		result.Environments = append(result.Environments, environmentSummary{
+			Name:     env.Name,
+			UUID:     env.UUID,
+			Owner:    owner,
+			Machines: env.MachineCount,
+			Services: env.ServiceCount,
+			Units:    env.UnitCount,
+		})
+	}
+	sort.Sort(environmentSummariesByName(result.Environments))
+	return result
+}
+
+type environmentSummariesByName []environmentSummary
+
+func (s environmentSummariesByName) Len() int           { return len(s) }
+func (s environmentSummariesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s environmentSummariesByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+// formatAllEnvironmentsTabular returns a tabular summary of every
+// environment in the system.
+func formatAllEnvironmentsTabular(status allEnvironmentsStatus) ([]byte, error) {
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "ENVIRONMENT\tOWNER\tMACHINES\tSERVICES\tUNITS")
+	for _, env := range status.Environments {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", env.Name, env.Owner, env.Machines, env.Services, env.Units)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusAllEnvironmentsSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeAllEnvironmentsAPI
+}
+
+var _ = gc.Suite(&StatusAllEnvironmentsSuite{})
+
+type fakeAllEnvironmentsAPI struct {
+	envs   []params.EnvironmentSummary
+	err    error
+	closed bool
+}
+
+func (f *fakeAllEnvironmentsAPI) EnvironmentSummaries() ([]params.EnvironmentSummary, error) {
+	return f.envs, f.err
+}
+
+func (f *fakeAllEnvironmentsAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *StatusAllEnvironmentsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeAllEnvironmentsAPI{
+		envs: []params.EnvironmentSummary{{
+			Name:         "system",
+			UUID:         "system-uuid",
+			OwnerTag:     "user-admin@local",
+			MachineCount: 1,
+		}, {
+			Name:         "hosted",
+			UUID:         "hosted-uuid",
+			OwnerTag:     "user-bob@local",
+			MachineCount: 2,
+			ServiceCount: 1,
+			UnitCount:    1,
+		}},
+	}
+	s.PatchValue(&newAllEnvironmentsAPIForStatus, func(_ *StatusCommand) (allEnvironmentsAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *StatusAllEnvironmentsSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), append([]string{"--all-models"}, args...)...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *StatusAllEnvironmentsSuite) TestPatternsNotAllowed(c *gc.C) {
+	_, err := s.run(c, "wordpress")
+	c.Assert(err, gc.ErrorMatches, "cannot specify patterns with --all-models")
+}
+
+func (s *StatusAllEnvironmentsSuite) TestTabular(c *gc.C) {
+	out, err := s.run(c, "--format", "tabular")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ENVIRONMENT OWNER       MACHINES SERVICES UNITS\n"+
+		"hosted      bob@local   2        1        1\n"+
+		"system      admin@local 1        0        0\n",
+	)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *StatusAllEnvironmentsSuite) TestYaml(c *gc.C) {
+	out, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"environments:\n"+
+		"- name: hosted\n"+
+		"  uuid: hosted-uuid\n"+
+		"  owner: bob@local\n"+
+		"  machines: 2\n"+
+		"  services: 1\n"+
+		"  units: 1\n"+
+		"- name: system\n"+
+		"  uuid: system-uuid\n"+
+		"  owner: admin@local\n"+
+		"  machines: 1\n"+
+		"  services: 0\n"+
+		"  units: 0\n",
+	)
+}
+
+func (s *StatusAllEnvironmentsSuite) TestSummaryError(c *gc.C) {
+	s.api.err = errors.New("permission denied")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "cannot get environment status: permission denied")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// serviceSummaryRow holds the line reported for a single service by
+// "juju status --summary".
+type serviceSummaryRow struct {
+	Name     string
+	Status   params.Status
+	Units    int
+	Machines int
+	Workload map[params.Status]int
+	Agent    map[params.Status]int
+}
+
+// summariseServices returns one row for each service in the given
+// status, in natural order of service name. Subordinate services, whose
+// units are counted with their principals, are reported without
+// counts.
+func summariseServices(status *api.Status) []serviceSummaryRow {
+	names := make([]string, 0, len(status.Services))
+	for name := range status.Services {
+		names = append(names, name)
+	}
+	var rows []serviceSummaryRow
+	for _, name := range sortStringsNaturally(names) {
+		svc := status.Services[name]
+		summary := svc.Summary
+		row := serviceSummaryRow{
+			Name:     name,
+			Status:   summary.WorstStatus,
+			Units:    summary.UnitCount,
+			Machines: summary.MachineCount,
+			Workload: summary.WorkloadCounts,
+			Agent:    summary.AgentCounts,
+		}
+		if row.Status == "" {
+			row.Status = svc.Status.Status
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// formatServiceSummaries returns a table with one row per service,
+// showing how many of its units are in each status.
+func formatServiceSummaries(rows []serviceSummaryRow) []byte {
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tUNITS\tMACHINES\tWORKLOAD\tAGENT")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\n",
+			row.Name, row.Status, row.Units, row.Machines,
+			formatStatusCounts(row.Workload), formatStatusCounts(row.Agent),
+		)
+	}
+	tw.Flush()
+	return out.Bytes()
+}
+
+// formatStatusCounts returns the given counts as a comma-separated
+// list of status:count pairs, ordered by status.
+func formatStatusCounts(counts map[params.Status]int) string {
+	var pairs []string
+	for status, n := range counts {
+		pairs = append(pairs, fmt.Sprintf("%s:%d", status, n))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusSummarySuite struct {
+	testing.FakeJujuHomeSuite
+	client fakeApiClient
+}
+
+var _ = gc.Suite(&StatusSummarySuite{})
+
+func (s *StatusSummarySuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.client = newFakeApiClient(&api.Status{
+		EnvironmentName: "dummyenv",
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {
+				Summary: api.ServiceSummary{
+					UnitCount:      3,
+					MachineCount:   2,
+					WorkloadCounts: map[params.Status]int{"active": 2, "error": 1},
+					AgentCounts:    map[params.Status]int{"idle": 3},
+					WorstStatus:    "error",
+				},
+			},
+			"mysql": {
+				Summary: api.ServiceSummary{
+					UnitCount:      1,
+					MachineCount:   1,
+					WorkloadCounts: map[params.Status]int{"active": 1},
+					AgentCounts:    map[params.Status]int{"executing": 1},
+					WorstStatus:    "active",
+				},
+			},
+			"logging": {
+				Status: api.AgentStatus{Status: "unknown"},
+			},
+		},
+	})
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &s.client, nil
+	})
+}
+
+func (s *StatusSummarySuite) TestSummary(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--summary", "wordpress", "mysql", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME      STATUS  UNITS MACHINES WORKLOAD         AGENT\n"+
+		"logging   unknown 0     0                         \n"+
+		"mysql     active  1     1        active:1         executing:1\n"+
+		"wordpress error   3     2        active:2,error:1 idle:3\n",
+	)
+	c.Assert(s.client.patternsUsed, jc.DeepEquals, []string{"wordpress", "mysql", "logging"})
+	c.Assert(s.client.closeCalled, jc.IsTrue)
+}
+
+func (s *StatusSummarySuite) TestSummaryWithAllModels(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--summary", "--all-models")
+	c.Assert(err, gc.ErrorMatches, "cannot specify --summary with --all-models")
+}
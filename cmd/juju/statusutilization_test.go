@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type StatusUtilizationSuite struct {
+	testing.FakeJujuHomeSuite
+	client fakeApiClient
+}
+
+var _ = gc.Suite(&StatusUtilizationSuite{})
+
+func (s *StatusUtilizationSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.client = newFakeApiClient(&api.Status{
+		EnvironmentName: "dummyenv",
+		Services: map[string]api.ServiceStatus{
+			"wordpress": {
+				Charm: "cs:quantal/wordpress-3",
+				Units: map[string]api.UnitStatus{
+					"wordpress/0": {
+						Machine:   "1",
+						UnitAgent: api.AgentStatus{Status: params.StatusIdle},
+						Workload:  api.AgentStatus{Status: params.StatusActive},
+						Utilization: &api.UnitUtilization{
+							CPUPercent:  12.46,
+							MemoryBytes: 1024,
+							Processes:   3,
+							Time:        time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC),
+						},
+					},
+					"wordpress/1": {
+						Machine:   "2",
+						UnitAgent: api.AgentStatus{Status: params.StatusIdle},
+						Workload:  api.AgentStatus{Status: params.StatusActive},
+					},
+				},
+			},
+		},
+	})
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return &s.client, nil
+	})
+}
+
+func (s *StatusUtilizationSuite) unitStatus(c *gc.C, args ...string) map[string]interface{} {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), append(args, "--utc")...)
+	c.Assert(err, jc.ErrorIsNil)
+	var out struct {
+		Services map[string]struct {
+			Units map[string]map[string]interface{}
+		}
+	}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	return out.Services["wordpress"].Units["wordpress/0"]
+}
+
+func (s *StatusUtilizationSuite) TestUtilization(c *gc.C) {
+	unit := s.unitStatus(c, "--utilization")
+	c.Assert(unit["utilization"], jc.DeepEquals, map[interface{}]interface{}{
+		"cpu":       "12.5%",
+		"memory":    "1.0 KiB",
+		"processes": 3,
+		"since":     "2015-09-01T12:00:00Z",
+	})
+}
+
+func (s *StatusUtilizationSuite) TestUtilizationNotRequested(c *gc.C) {
+	unit := s.unitStatus(c)
+	_, ok := unit["utilization"]
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *StatusUtilizationSuite) TestUtilizationTabular(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--utilization", "--format", "tabular")
+	c.Assert(err, jc.ErrorIsNil)
+	out := testing.Stdout(ctx)
+	c.Assert(out, gc.Matches, `(?s).*\nID +WORKLOAD-STATE +AGENT-STATE +VERSION +MACHINE +PORTS +PUBLIC-ADDRESS +CPU +MEMORY +MESSAGE +\n`+
+		`wordpress/0 +active +idle +1 +12\.5% +1\.0 KiB +\n`+
+		`wordpress/1 +active +idle +2 +\n.*`)
+
+	ctx, err = testing.RunCommand(c, envcmd.Wrap(&StatusCommand{}), "--format", "tabular")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Not(gc.Matches), `(?s).*CPU.*`)
+}
@@ -10,6 +10,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const ListCommandDoc = `
@@ -27,7 +28,7 @@ options:
 // ListCommand attempts to release storage instance.
 type ListCommand struct {
 	StorageCommandBase
-	out cmd.Output
+	out output.Output
 }
 
 // Init implements Command.Init.
@@ -83,7 +84,7 @@ func (c *ListCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	return c.out.Write(ctx, output)
+	return c.out.WriteWithIDs(ctx, output, storageIds(valid))
 }
 
 var (
@@ -93,6 +93,32 @@ transcode/1:
 	)
 }
 
+func (s *ListSuite) TestListQuiet(c *gc.C) {
+	s.assertValidList(
+		c,
+		[]string{"--quiet"},
+		`
+db-dir/1000
+db-dir/1100
+shared-fs/0
+`[1:],
+		"",
+	)
+}
+
+func (s *ListSuite) TestListJSONLines(c *gc.C) {
+	s.assertValidList(
+		c,
+		[]string{"--format", "jsonl"},
+		`
+{"postgresql/0":{"db-dir/1100":{"storage":"db-dir","kind":"filesystem","status":"pending","persistent":false}}}
+{"transcode/0":{"db-dir/1000":{"storage":"db-dir","kind":"block","status":"pending","persistent":true},"db-dir/1100":{"storage":"db-dir","kind":"filesystem","status":"pending","persistent":false},"shared-fs/0":{"storage":"shared-fs","kind":"unknown","status":"pending","persistent":false}}}
+{"transcode/1":{"shared-fs/0":{"storage":"shared-fs","kind":"unknown","status":"pending","persistent":false}}}
+`[1:],
+		"",
+	)
+}
+
 func (s *ListSuite) TestListOwnerStorageIdSort(c *gc.C) {
 	s.mockAPI.lexicalChaos = true
 	s.assertValidList(
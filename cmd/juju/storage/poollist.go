@@ -8,6 +8,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const PoolListCommandDoc = `
@@ -42,7 +43,7 @@ type PoolListCommand struct {
 	PoolCommandBase
 	Providers []string
 	Names     []string
-	out       cmd.Output
+	out       output.Output
 }
 
 // Init implements Command.Init.
@@ -10,6 +10,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const ShowCommandDoc = `
@@ -32,7 +33,7 @@ options:
 type ShowCommand struct {
 	StorageCommandBase
 	ids []string
-	out cmd.Output
+	out output.Output
 }
 
 // Init implements Command.Init.
@@ -79,7 +80,7 @@ func (c *ShowCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	return c.out.Write(ctx, output)
+	return c.out.WriteWithIDs(ctx, output, storageIds(found))
 }
 
 func (c *ShowCommand) getStorageTags() ([]names.StorageTag, error) {
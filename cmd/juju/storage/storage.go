@@ -8,6 +8,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/api/storage"
 	"github.com/juju/juju/apiserver/params"
@@ -71,6 +72,18 @@ type StorageInfo struct {
 	Location    string `yaml:"location,omitempty" json:"location,omitempty"`
 }
 
+// storageIds returns the ids of the given storage instances, in order,
+// for --quiet output.
+func storageIds(storages []params.StorageDetails) []string {
+	ids := set.NewStrings()
+	for _, one := range storages {
+		if tag, err := names.ParseStorageTag(one.StorageTag); err == nil {
+			ids.Add(tag.Id())
+		}
+	}
+	return ids.SortedValues()
+}
+
 // formatStorageDetails takes a set of StorageDetail and creates a
 // mapping keyed on unit and storage id.
 func formatStorageDetails(storages []params.StorageDetails) (map[string]map[string]StorageInfo, error) {
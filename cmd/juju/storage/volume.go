@@ -7,6 +7,7 @@ import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
@@ -85,6 +86,20 @@ func convertVolumeItem(item params.VolumeItem, all map[string]map[string]map[str
 	return nil
 }
 
+// volumeIds returns the ids of the given volumes, in order, for --quiet
+// output.
+func volumeIds(all []params.VolumeItem) ([]string, error) {
+	ids := set.NewStrings()
+	for _, one := range all {
+		id, err := idFromTag(one.Volume.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ids.Add(id)
+	}
+	return ids.SortedValues(), nil
+}
+
 var idFromTag = func(s string) (string, error) {
 	tag, err := names.ParseTag(s)
 	if err != nil {
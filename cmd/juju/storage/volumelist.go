@@ -10,6 +10,7 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const VolumeListCommandDoc = `
@@ -29,7 +30,7 @@ options:
 type VolumeListCommand struct {
 	VolumeCommandBase
 	Ids []string
-	out cmd.Output
+	out output.Output
 }
 
 // Init implements Command.Init.
@@ -87,7 +88,11 @@ func (c *VolumeListCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
-	return c.out.Write(ctx, output)
+	ids, err := volumeIds(valid)
+	if err != nil {
+		return err
+	}
+	return c.out.WriteWithIDs(ctx, output, ids)
 }
 
 var getVolumeListAPI = (*VolumeListCommand).getVolumeListAPI
@@ -13,14 +13,17 @@ import (
 	"launchpad.net/gnuflag"
 
 	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/output"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/jujuclient"
 )
 
 type SwitchCommand struct {
 	cmd.CommandBase
 	EnvName string
 	List    bool
+	out     output.Output
 }
 
 var switchDoc = `
@@ -32,6 +35,12 @@ environment as defined by the file $JUJU_HOME/current-environment.
 If a command line parameter is passed in, that value will is stored in the
 current environment file if it represents a valid environment name as
 specified in the environments.yaml file.
+
+Models that juju knows of through a controller are named as
+<controller>:<model>.
+
+With --list, the names of all environments are output in the format given by
+--format.
 `
 
 func (c *SwitchCommand) Info() *cmd.Info {
@@ -47,6 +56,7 @@ func (c *SwitchCommand) Info() *cmd.Info {
 func (c *SwitchCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.List, "l", false, "list the environment names")
 	f.BoolVar(&c.List, "list", false, "")
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
 }
 
 func (c *SwitchCommand) Init(args []string) (err error) {
@@ -66,6 +76,40 @@ func getConfigstoreEnvironments() (set.Strings, error) {
 	return set.NewStrings(other...), nil
 }
 
+// getClientStoreModels returns the names, qualified as
+// "<controller>:<model>", of the models recorded in the client store
+// that environment commands can connect to: those that have not been
+// removed, and whose controller has known API endpoints and an account.
+func getClientStoreModels(store jujuclient.ClientStore) (set.Strings, error) {
+	controllers, err := store.AllControllers()
+	if err != nil {
+		return nil, errors.Annotate(err, "failed to list controllers in client store")
+	}
+	names := set.NewStrings()
+	for controllerName, controller := range controllers {
+		if len(controller.APIEndpoints) == 0 {
+			continue
+		}
+		if _, err := store.AccountByName(controllerName); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotate(err, "failed to read account in client store")
+		}
+		models, err := store.AllModels(controllerName)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Annotate(err, "failed to list models in client store")
+		}
+		for name, model := range models {
+			if !model.Removed {
+				names.Add(controllerName + ":" + name)
+			}
+		}
+	}
+	return names, nil
+}
+
 func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 	// Switch is an alternative way of dealing with environments than using
 	// the JUJU_ENV environment setting, and as such, doesn't play too well.
@@ -84,16 +128,18 @@ func (c *SwitchCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	names = names.Union(configEnvirons)
+	clientModels, err := getClientStoreModels(jujuclient.NewFileClientStore())
+	if err != nil {
+		return err
+	}
+	names = names.Union(clientModels)
 
 	if c.List {
 		// List all environments.
 		if c.EnvName != "" {
 			return errors.New("cannot switch and list at the same time")
 		}
-		for _, name := range names.SortedValues() {
-			fmt.Fprintf(ctx.Stdout, "%s\n", name)
-		}
-		return nil
+		return c.out.Write(ctx, names.SortedValues())
 	}
 
 	jujuEnv := os.Getenv("JUJU_ENV")
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	gitjujutesting "github.com/juju/testing"
@@ -13,6 +14,7 @@ import (
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/environs/configstore"
 	_ "github.com/juju/juju/juju"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/testing"
 )
 
@@ -100,6 +102,13 @@ func (*SwitchSimpleSuite) TestListEnvironments(c *gc.C) {
 	c.Assert(testing.Stdout(context), gc.Equals, expectedEnvironments)
 }
 
+func (*SwitchSimpleSuite) TestListEnvironmentsJSON(c *gc.C) {
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	context, err := testing.RunCommand(c, &SwitchCommand{}, "--list", "--format=json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `["erewhemos","erewhemos-2"]`+"\n")
+}
+
 func (s *SwitchSimpleSuite) TestListEnvironmentsWithConfigstore(c *gc.C) {
 	memstore := configstore.NewMem()
 	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
@@ -114,6 +123,50 @@ func (s *SwitchSimpleSuite) TestListEnvironmentsWithConfigstore(c *gc.C) {
 	c.Assert(testing.Stdout(context), gc.Equals, expected)
 }
 
+func (s *SwitchSimpleSuite) writeClientStore(c *gc.C) {
+	store := jujuclient.NewFileClientStore()
+	for _, name := range []string{"ctrl", "no-account"} {
+		err := store.UpdateController(name, jujuclient.ControllerDetails{
+			ControllerUUID: name + "-uuid",
+			APIEndpoints:   []string{"10.0.0.1:17070"},
+			CACert:         testing.CACert,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		err = store.UpdateModel(name, "hosted", jujuclient.ModelDetails{ModelUUID: name + "-hosted-uuid"})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	err := store.UpdateModel("ctrl", "gone", jujuclient.ModelDetails{ModelUUID: "gone-uuid", Removed: true})
+	c.Assert(err, jc.ErrorIsNil)
+	err = store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "admin", Password: "secret"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SwitchSimpleSuite) TestListEnvironmentsWithClientStore(c *gc.C) {
+	s.writeClientStore(c)
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	context, err := testing.RunCommand(c, &SwitchCommand{}, "--list")
+	c.Assert(err, jc.ErrorIsNil)
+	// Models are qualified with their controller. Removed models, and
+	// those on controllers without an account, cannot be connected to
+	// so are not listed.
+	expected := "ctrl:hosted\n" + expectedEnvironments
+	c.Assert(testing.Stdout(context), gc.Equals, expected)
+}
+
+func (s *SwitchSimpleSuite) TestSettingToClientStoreModel(c *gc.C) {
+	s.writeClientStore(c)
+	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
+	context, err := testing.RunCommand(c, &SwitchCommand{}, "ctrl:hosted")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "erewhemos -> ctrl:hosted\n")
+	c.Assert(envcmd.ReadCurrentEnvironment(), gc.Equals, "ctrl:hosted")
+
+	for _, name := range []string{"hosted", "no-account:hosted", "ctrl:gone"} {
+		_, err = testing.RunCommand(c, &SwitchCommand{}, name)
+		c.Assert(err, gc.ErrorMatches, fmt.Sprintf("%q is not a name of an existing defined environment", name))
+	}
+}
+
 func (*SwitchSimpleSuite) TestListEnvironmentsOSJujuEnvSet(c *gc.C) {
 	testing.WriteEnvironments(c, testing.MultipleEnvConfig)
 	os.Setenv("JUJU_ENV", "using-env")
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/environmentmanager"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// TransferModelCommand transfers the ownership of the current
+// environment to another user.
+type TransferModelCommand struct {
+	envcmd.EnvCommandBase
+	NewOwner       string
+	RevokePrevious bool
+}
+
+// TransferModelAPI defines the API methods used by the transfer-model
+// command.
+type TransferModelAPI interface {
+	Close() error
+	TransferEnvironmentOwnership(envUUID, newOwner string, revokePrevious bool) error
+}
+
+var transferModelDoc = `
+Makes the given user the owner of the current environment, giving them
+access to it if they do not have it already. This is typically used when
+the owner of an environment leaves a team.
+
+The new owner must not already own an environment with the same name.
+Only the current owner of the environment or the owner of the Juju server
+can transfer it, and the Juju server's own environment cannot be
+transferred.
+
+By default, the previous owner keeps access to the environment as an
+ordinary user; use --revoke-previous to remove it.
+
+Examples:
+ juju transfer-model joe
+     Make local user "joe" the owner of the current environment
+
+ juju transfer-model joe --revoke-previous
+     As above, and remove the previous owner's access
+`
+
+func (c *TransferModelCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "transfer-model",
+		Args:    "<user>",
+		Purpose: "transfer ownership of the current environment to another user",
+		Doc:     transferModelDoc,
+	}
+}
+
+func (c *TransferModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.RevokePrevious, "revoke-previous", false, "remove the previous owner's access to the environment")
+}
+
+func (c *TransferModelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no user specified")
+	}
+	if !names.IsValidUser(args[0]) {
+		return errors.Errorf("invalid username: %q", args[0])
+	}
+	c.NewOwner = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// getTransferModelAPI returns the API used by the transfer-model
+// command; it is a variable so it can be replaced in tests.
+var getTransferModelAPI = func(c *TransferModelCommand) (TransferModelAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return environmentmanager.NewClient(root), nil
+}
+
+func (c *TransferModelCommand) Run(ctx *cmd.Context) error {
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if endpoint.EnvironUUID == "" {
+		return errors.New("environment UUID not known, cannot transfer environment")
+	}
+	client, err := getTransferModelAPI(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.TransferEnvironmentOwnership(endpoint.EnvironUUID, c.NewOwner, c.RevokePrevious); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "The environment is now owned by %s.\n", names.NewUserTag(c.NewOwner).Username())
+	return nil
+}
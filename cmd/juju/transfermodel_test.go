@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type TransferModelSuite struct {
+	jujutesting.JujuConnSuite
+	api *fakeTransferModelAPI
+}
+
+var _ = gc.Suite(&TransferModelSuite{})
+
+func (s *TransferModelSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.api = &fakeTransferModelAPI{}
+	s.PatchValue(&getTransferModelAPI, func(*TransferModelCommand) (TransferModelAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *TransferModelSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&TransferModelCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+func (s *TransferModelSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args   []string
+		errMsg string
+	}{{
+		errMsg: "no user specified",
+	}, {
+		args:   []string{"not a user"},
+		errMsg: `invalid username: "not a user"`,
+	}, {
+		args:   []string{"bob", "extra"},
+		errMsg: `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d", i)
+		err := testing.InitCommand(&TransferModelCommand{}, test.args)
+		c.Check(err, gc.ErrorMatches, test.errMsg)
+	}
+}
+
+func (s *TransferModelSuite) TestTransfer(c *gc.C) {
+	out, err := s.run(c, "bob", "--revoke-previous")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "The environment is now owned by bob@local.\n")
+	c.Assert(s.api.envUUID, gc.Equals, s.State.EnvironUUID())
+	c.Assert(s.api.newOwner, gc.Equals, "bob")
+	c.Assert(s.api.revokePrevious, jc.IsTrue)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *TransferModelSuite) TestTransferError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c, "bob")
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(s.api.revokePrevious, jc.IsFalse)
+}
+
+type fakeTransferModelAPI struct {
+	envUUID        string
+	newOwner       string
+	revokePrevious bool
+	closed         bool
+	err            error
+}
+
+func (f *fakeTransferModelAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeTransferModelAPI) TransferEnvironmentOwnership(envUUID, newOwner string, revokePrevious bool) error {
+	f.envUUID = envUUID
+	f.newOwner = newOwner
+	f.revokePrevious = revokePrevious
+	return f.err
+}
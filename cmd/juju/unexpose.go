@@ -7,7 +7,9 @@ import (
 	"errors"
 
 	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
 
+	apiservice "github.com/juju/juju/api/service"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 )
@@ -16,16 +18,31 @@ import (
 type UnexposeCommand struct {
 	envcmd.EnvCommandBase
 	ServiceName string
+	Endpoints   []string
 }
 
+var jujuUnexposeHelp = `
+Adjusts firewall rules and similar security mechanisms of the provider, to
+stop the service being accessed on its public address.
+
+With --endpoints, only the named endpoints, exposed earlier with
+juju expose --endpoints, are unexposed. The service stays exposed on any
+other endpoints.
+`
+
 func (c *UnexposeCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "unexpose",
 		Args:    "<service>",
 		Purpose: "unexpose a service",
+		Doc:     jujuUnexposeHelp,
 	}
 }
 
+func (c *UnexposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(cmd.NewStringsValue(nil, &c.Endpoints), "endpoints", "unexpose only the listed endpoints")
+}
+
 func (c *UnexposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no service name specified")
@@ -37,6 +54,15 @@ func (c *UnexposeCommand) Init(args []string) error {
 // Run changes the juju-managed firewall to hide any
 // ports that were also explicitly marked by units as closed.
 func (c *UnexposeCommand) Run(_ *cmd.Context) error {
+	if len(c.Endpoints) > 0 {
+		root, err := c.NewAPIRoot()
+		if err != nil {
+			return err
+		}
+		client := apiservice.NewClient(root)
+		defer client.Close()
+		return block.ProcessBlockedError(client.UnexposeEndpoints(c.ServiceName, c.Endpoints...), block.BlockChange)
+	}
 	client, err := c.NewAPIClient()
 	if err != nil {
 		return err
@@ -71,3 +71,20 @@ func (s *UnexposeSuite) TestBlockUnexpose(c *gc.C) {
 	err = runExpose(c, "some-service-name")
 	s.AssertBlocked(c, err, ".*TestBlockUnexpose.*")
 }
+
+func (s *UnexposeSuite) TestUnexposeEndpoints(c *gc.C) {
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err := runDeploy(c, "local:dummy", "some-service-name")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runExpose(c, "some-service-name", "--endpoints", "juju-info")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name", true)
+
+	err = runUnexpose(c, "some-service-name", "--endpoints", "juju-info")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-service-name", false)
+
+	err = runUnexpose(c, "some-service-name", "--endpoints", "juju-info")
+	c.Assert(err, gc.ErrorMatches, `endpoint "juju-info" of service "some-service-name" is not exposed`)
+}
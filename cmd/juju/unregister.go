@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/systemmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+)
+
+// UnregisterCommand removes a controller from the client store,
+// leaving the controller itself running.
+type UnregisterCommand struct {
+	cmd.CommandBase
+	controllerName string
+	force          bool
+	assumeYes      bool
+
+	// store and newAPI are overridden in tests.
+	store  jujuclient.ClientStore
+	newAPI func(jujuclient.ControllerDetails, jujuclient.AccountDetails) (UnregisterAPI, error)
+}
+
+// UnregisterAPI defines the API methods used by the unregister
+// command.
+type UnregisterAPI interface {
+	Close() error
+	HostedEnvironments() ([]params.HostedEnvironment, error)
+}
+
+var unregisterDoc = `
+Removes the details of a controller, with its models and account, from
+this client. The controller is not destroyed: its environments, and the
+machines and services in them, keep running, and the controller can be
+registered again later.
+
+Before removing the controller, unregister connects to it and lists the
+environments it still hosts, asking for confirmation if any remain. Use
+--force to unregister a controller that cannot be reached, without
+contacting it.
+
+Examples:
+    juju unregister production
+    juju unregister --force old-controller
+
+See Also:
+    juju help refresh-models
+    juju help destroy-environment
+`
+
+func (c *UnregisterCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "unregister",
+		Args:    "<controller name>",
+		Purpose: "remove a controller from this client without destroying it",
+		Doc:     unregisterDoc,
+	}
+}
+
+func (c *UnregisterCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.force, "force", false, "Do not contact the controller before unregistering it")
+	f.BoolVar(&c.assumeYes, "y", false, "Do not ask for confirmation")
+	f.BoolVar(&c.assumeYes, "yes", false, "")
+}
+
+func (c *UnregisterCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no controller specified")
+	}
+	c.controllerName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *UnregisterCommand) getStore() jujuclient.ClientStore {
+	if c.store != nil {
+		return c.store
+	}
+	return jujuclient.NewFileClientStore()
+}
+
+func (c *UnregisterCommand) getAPI(
+	store jujuclient.AccountUpdater, controllerName string,
+	controller jujuclient.ControllerDetails, account jujuclient.AccountDetails,
+) (UnregisterAPI, error) {
+	if c.newAPI != nil {
+		return c.newAPI(controller, account)
+	}
+	st, err := openControllerAPI(store, controllerName, controller, account)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return systemmanager.NewClient(st), nil
+}
+
+func (c *UnregisterCommand) Run(ctx *cmd.Context) error {
+	store := c.getStore()
+	details, err := store.ControllerByName(c.controllerName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !c.force {
+		envs, err := c.hostedEnvironments(store, *details)
+		if err != nil {
+			return errors.Annotatef(err, "cannot check controller %q (use --force to unregister it without contacting it)", c.controllerName)
+		}
+		if len(envs) > 0 {
+			writeHostedEnvironments(ctx.Stdout, c.controllerName, envs)
+			if !c.assumeYes {
+				fmt.Fprint(ctx.Stdout, "Continue [y/N]? ")
+				if !readYes(ctx.Stdin) {
+					return errors.New("unregister aborted")
+				}
+			}
+		}
+	}
+	if err := store.RemoveController(c.controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "controller %q removed from this client\n", c.controllerName)
+	return nil
+}
+
+// hostedEnvironments returns the environments still hosted by the
+// controller, other than its own, ignoring those being destroyed.
+func (c *UnregisterCommand) hostedEnvironments(store jujuclient.ClientStore, details jujuclient.ControllerDetails) ([]params.HostedEnvironment, error) {
+	account, err := store.AccountByName(c.controllerName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	client, err := c.getAPI(store, c.controllerName, details, *account)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer client.Close()
+	all, err := client.HostedEnvironments()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var envs []params.HostedEnvironment
+	for _, env := range all {
+		if env.Life == params.Alive {
+			envs = append(envs, env)
+		}
+	}
+	return envs, nil
+}
+
+func writeHostedEnvironments(w io.Writer, controllerName string, envs []params.HostedEnvironment) {
+	fmt.Fprintf(w, "Controller %q is still running and hosts %d environments:\n\n", controllerName, len(envs))
+	tw := tabwriter.NewWriter(w, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENVIRONMENT\tOWNER\tMACHINES\tSERVICES")
+	for _, env := range envs {
+		owner := env.OwnerTag
+		if tag, err := names.ParseUserTag(env.OwnerTag); err == nil {
+			owner = tag.Username()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\n", env.Name, owner, env.MachineCount, env.ServiceCount)
+	}
+	tw.Flush()
+	fmt.Fprint(w, `
+Unregistering only removes the controller from this client; the
+environments above, and their machines, will keep running. Use
+"juju destroy-environment" to destroy them.
+
+`[1:])
+}
+
+// readYes reads a line from r and reports whether it answers yes.
+func readYes(r io.Reader) bool {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan()
+	answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type UnregisterSuite struct {
+	testing.FakeJujuHomeSuite
+	store jujuclient.ClientStore
+	api   *fakeUnregisterAPI
+}
+
+var _ = gc.Suite(&UnregisterSuite{})
+
+type fakeUnregisterAPI struct {
+	gitjujutesting.Stub
+	envs []params.HostedEnvironment
+}
+
+func (f *fakeUnregisterAPI) Close() error {
+	f.AddCall("Close")
+	return f.NextErr()
+}
+
+func (f *fakeUnregisterAPI) HostedEnvironments() ([]params.HostedEnvironment, error) {
+	f.AddCall("HostedEnvironments")
+	return f.envs, f.NextErr()
+}
+
+func (s *UnregisterSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = jujuclient.NewMemStore()
+	err := s.store.UpdateController("ctrl", jujuclient.ControllerDetails{
+		ControllerUUID: "ctrl-uuid",
+		CACert:         testing.CACert,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = &fakeUnregisterAPI{}
+}
+
+func (s *UnregisterSuite) run(c *gc.C, stdin string, args ...string) (string, string, error) {
+	command := &UnregisterCommand{
+		store: s.store,
+		newAPI: func(jujuclient.ControllerDetails, jujuclient.AccountDetails) (UnregisterAPI, error) {
+			return s.api, nil
+		},
+	}
+	ctx := testing.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	err := testing.InitCommand(command, args)
+	if err == nil {
+		err = command.Run(ctx)
+	}
+	return testing.Stdout(ctx), testing.Stderr(ctx), err
+}
+
+func (s *UnregisterSuite) assertRemoved(c *gc.C, removed bool) {
+	_, err := s.store.ControllerByName("ctrl")
+	if removed {
+		c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	} else {
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *UnregisterSuite) TestInit(c *gc.C) {
+	_, _, err := s.run(c, "")
+	c.Assert(err, gc.ErrorMatches, "no controller specified")
+	_, _, err = s.run(c, "", "ctrl", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *UnregisterSuite) TestUnregisterNoHostedEnvironments(c *gc.C) {
+	s.api.envs = []params.HostedEnvironment{{Name: "dying", Life: params.Dying}}
+	stdout, stderr, err := s.run(c, "", "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Equals, "")
+	c.Assert(stderr, gc.Equals, "controller \"ctrl\" removed from this client\n")
+	s.api.CheckCallNames(c, "HostedEnvironments", "Close")
+	s.assertRemoved(c, true)
+}
+
+func (s *UnregisterSuite) TestUnregisterConfirmsHostedEnvironments(c *gc.C) {
+	s.api.envs = []params.HostedEnvironment{{
+		Name:         "prod",
+		OwnerTag:     "user-bob@local",
+		Life:         params.Alive,
+		MachineCount: 3,
+		ServiceCount: 2,
+	}}
+	stdout, _, err := s.run(c, "n\n", "ctrl")
+	c.Assert(err, gc.ErrorMatches, "unregister aborted")
+	c.Assert(stdout, gc.Equals, `
+Controller "ctrl" is still running and hosts 1 environments:
+
+ENVIRONMENT  OWNER      MACHINES  SERVICES
+prod         bob@local  3         2
+
+Unregistering only removes the controller from this client; the
+environments above, and their machines, will keep running. Use
+"juju destroy-environment" to destroy them.
+
+Continue [y/N]? `[1:])
+	s.assertRemoved(c, false)
+
+	_, _, err = s.run(c, "y\n", "ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRemoved(c, true)
+}
+
+func (s *UnregisterSuite) TestUnregisterAssumeYes(c *gc.C) {
+	s.api.envs = []params.HostedEnvironment{{Name: "prod", Life: params.Alive}}
+	stdout, _, err := s.run(c, "", "ctrl", "-y")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stdout, gc.Not(jc.Contains), "Continue")
+	s.assertRemoved(c, true)
+}
+
+func (s *UnregisterSuite) TestUnregisterControllerUnreachable(c *gc.C) {
+	s.api.SetErrors(errors.New("connection refused"))
+	_, _, err := s.run(c, "", "ctrl")
+	c.Assert(err, gc.ErrorMatches, `cannot check controller "ctrl" \(use --force to unregister it without contacting it\): connection refused`)
+	s.assertRemoved(c, false)
+}
+
+func (s *UnregisterSuite) TestUnregisterForce(c *gc.C) {
+	_, _, err := s.run(c, "", "ctrl", "--force")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCallNames(c)
+	s.assertRemoved(c, true)
+}
+
+func (s *UnregisterSuite) TestUnregisterUnknownController(c *gc.C) {
+	_, _, err := s.run(c, "", "other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
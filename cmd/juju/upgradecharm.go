@@ -134,7 +134,8 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 	defer csClient.jar.Save()
-	newURL, repo, err := resolveCharmURL(newRef.String(), csClient.params, ctx.AbsPath(c.RepoPath), conf)
+	csClient.useEnvironmentCharmStore(conf)
+	newURL, repo, err := resolveCharmURL(newRef.String(), csClient.params, ctx.AbsPath(c.RepoPath), conf, charmStoreRetryPolicy(ctx))
 	if err != nil {
 		return errors.Trace(err)
 	}
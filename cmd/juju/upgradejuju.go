@@ -11,11 +11,14 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/upgradechecks"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
@@ -34,9 +37,27 @@ type UpgradeJujuCommand struct {
 	DryRun        bool
 	ResetPrevious bool
 	AssumeYes     bool
+	IgnoreChecks  bool
 	Series        []string
+	Stage         string
+	Canaries      []string
+	BakePeriod    time.Duration
+	canaries      string
 }
 
+// The stages of a staged upgrade that may be requested with --stage.
+const (
+	stageCanary   = "canary"
+	stageStatus   = "status"
+	stagePromote  = "promote"
+	stageRollBack = "rollback"
+)
+
+// defaultBakePeriod is the time for which the canaries of a staged
+// upgrade must run healthily before the rest of the environment is
+// upgraded, if --bake-period is not specified.
+const defaultBakePeriod = time.Hour
+
 var upgradeJujuDoc = `
 The upgrade-juju command upgrades a running environment by setting a version
 number for all juju agents to run. By default, it chooses the most recent
@@ -74,7 +95,39 @@ progress. It will also abort if a previous upgrade was partially
 completed - this can happen if one of the state servers in a high
 availability environment failed to upgrade. If a failed upgrade has
 been resolved, the --reset-previous-upgrade flag can be used to reset
-the environment's upgrade tracking state, allowing further upgrades.`
+the environment's upgrade tracking state, allowing further upgrades.
+
+To recover from a bad patch release, --version may name an earlier patch
+release of the current major.minor version (for example --version 1.25.1
+when running 1.25.2). The downgrade is refused if upgrade steps have
+changed the database since that release.
+
+The --stage flag controls staged upgrades, which upgrade a set of canary
+machines before the rest of the environment:
+
+ - "--stage canary --canaries 1,2" starts a staged upgrade. The state
+   servers are upgraded as usual, then the canary machines and their
+   units. Once every canary agent runs the new version, and has stayed
+   healthy for the bake period (--bake-period, one hour by default),
+   the rest of the environment is upgraded automatically. If any canary
+   agent reports an error, the staged upgrade is rolled back and the
+   canaries return to the previous version. Only upgrades within the
+   current major and minor version can be staged.
+ - "--stage status" reports the progress of the current staged upgrade.
+ - "--stage promote" upgrades the rest of the environment immediately.
+ - "--stage rollback" abandons the current staged upgrade.
+
+Before the agent version is changed, the API server runs a set of
+pre-upgrade checks: that the state servers have enough free disk space,
+that the mongo server version is supported, that no units refer to
+missing machines or principals, and that the environment config has no
+deprecated settings. The upgrade is refused if any check fails; use
+--ignore-checks to upgrade regardless, once you are sure the failures
+are harmless.
+
+Agents can only be rolled back to an earlier patch release of the same
+major.minor version; use --version to stage an upgrade between patch
+releases if you may need to roll it back.`
 
 func (c *UpgradeJujuCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -91,7 +144,11 @@ func (c *UpgradeJujuCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.ResetPrevious, "reset-previous-upgrade", false, "clear the previous (incomplete) upgrade status (use with care)")
 	f.BoolVar(&c.AssumeYes, "y", false, "answer 'yes' to confirmation prompts")
 	f.BoolVar(&c.AssumeYes, "yes", false, "")
+	f.BoolVar(&c.IgnoreChecks, "ignore-checks", false, "upgrade even if pre-upgrade checks fail")
 	f.Var(newSeriesValue(nil, &c.Series), "series", "upload tools for supplied comma-separated series list (OBSOLETE)")
+	f.StringVar(&c.Stage, "stage", "", "control a staged upgrade: canary, status, promote or rollback")
+	f.StringVar(&c.canaries, "canaries", "", "comma-separated list of machines to upgrade first (with --stage canary)")
+	f.DurationVar(&c.BakePeriod, "bake-period", defaultBakePeriod, "time for which the canaries must be healthy (with --stage canary)")
 }
 
 func (c *UpgradeJujuCommand) Init(args []string) error {
@@ -117,9 +174,45 @@ func (c *UpgradeJujuCommand) Init(args []string) error {
 	if len(c.Series) > 0 && !c.UploadTools {
 		return fmt.Errorf("--series requires --upload-tools")
 	}
+	if err := c.initStage(); err != nil {
+		return err
+	}
 	return cmd.CheckEmpty(args)
 }
 
+func (c *UpgradeJujuCommand) initStage() error {
+	if c.canaries != "" {
+		for _, id := range strings.Split(c.canaries, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				c.Canaries = append(c.Canaries, id)
+			}
+		}
+	}
+	switch c.Stage {
+	case "":
+		if len(c.Canaries) > 0 {
+			return fmt.Errorf("--canaries requires --stage canary")
+		}
+	case stageCanary:
+		if len(c.Canaries) == 0 {
+			return fmt.Errorf("--stage canary requires --canaries")
+		}
+		if c.BakePeriod < 0 {
+			return fmt.Errorf("--bake-period must not be negative")
+		}
+		if c.ResetPrevious {
+			return fmt.Errorf("--reset-previous-upgrade cannot be used with --stage")
+		}
+	case stageStatus, stagePromote, stageRollBack:
+		if c.vers != "" || c.UploadTools || c.DryRun || c.ResetPrevious || len(c.Canaries) > 0 {
+			return fmt.Errorf("--stage %s cannot be used with other upgrade options", c.Stage)
+		}
+	default:
+		return fmt.Errorf("invalid --stage %q (expected canary, status, promote or rollback)", c.Stage)
+	}
+	return nil
+}
+
 var errUpToDate = stderrors.New("no upgrades available")
 
 func formatTools(tools coretools.List) string {
@@ -136,11 +229,34 @@ type upgradeJujuAPI interface {
 	UploadTools(r io.Reader, vers version.Binary, additionalSeries ...string) (*coretools.Tools, error)
 	AbortCurrentUpgrade() error
 	SetEnvironAgentVersion(version version.Number) error
+	StartAgentRollout(version version.Number, canaries []string, bakePeriod time.Duration) (params.AgentRolloutStatus, error)
+	AgentRolloutStatus() (params.AgentRolloutStatus, error)
+	PromoteAgentRollout() error
+	RollBackAgentRollout() error
+	RunUpgradeChecks(version version.Number) ([]params.UpgradeCheckResult, error)
 	Close() error
 }
 
+// upgradeJujuClient combines the Client and UpgradeChecks facades used
+// by the upgrade-juju command.
+type upgradeJujuClient struct {
+	*api.Client
+	checks *upgradechecks.Client
+}
+
+func (c upgradeJujuClient) RunUpgradeChecks(vers version.Number) ([]params.UpgradeCheckResult, error) {
+	return c.checks.RunUpgradeChecks(vers)
+}
+
 var getUpgradeJujuAPI = func(c *UpgradeJujuCommand) (upgradeJujuAPI, error) {
-	return c.NewAPIClient()
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return upgradeJujuClient{
+		Client: root.Client(),
+		checks: upgradechecks.NewClient(root),
+	}, nil
 }
 
 // Run changes the version proposed for the juju envtools.
@@ -161,6 +277,26 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 		}
 	}()
 
+	switch c.Stage {
+	case stageStatus:
+		status, err := client.AgentRolloutStatus()
+		if err != nil {
+			return err
+		}
+		printAgentRolloutStatus(ctx.Stdout, status)
+		return nil
+	case stagePromote:
+		if err := client.PromoteAgentRollout(); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return nil
+	case stageRollBack:
+		if err := client.RollBackAgentRollout(); err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		return nil
+	}
+
 	// Determine the version to upgrade to, uploading tools if necessary.
 	attrs, err := client.EnvironmentGet()
 	if err != nil {
@@ -185,8 +321,18 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	// TODO(fwereade): this list may be incomplete, pending envtools.Upload change.
 	ctx.Infof("available tools:\n%s", formatTools(context.tools))
 	ctx.Infof("best version:\n    %s", context.chosen)
+	if err := c.runUpgradeChecks(ctx, client, context.chosen); err != nil {
+		return err
+	}
 	if c.DryRun {
 		ctx.Infof("upgrade to this version by running\n    juju upgrade-juju --version=\"%s\"\n", context.chosen)
+	} else if c.Stage == stageCanary {
+		status, err := client.StartAgentRollout(context.chosen, c.Canaries, c.BakePeriod)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		logger.Infof("started staged upgrade to %s", context.chosen)
+		printAgentRolloutStatus(ctx.Stdout, status)
 	} else {
 		if c.ResetPrevious {
 			if ok, err := c.confirmResetPreviousUpgrade(ctx); !ok || err != nil {
@@ -216,6 +362,47 @@ func (c *UpgradeJujuCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// runUpgradeChecks asks the API server to make the pre-upgrade checks
+// for an upgrade to the given version, reporting any that fail. An
+// error is returned if a check failed, unless the checks are ignored
+// or this is a dry run.
+func (c *UpgradeJujuCommand) runUpgradeChecks(ctx *cmd.Context, client upgradeJujuAPI, vers version.Number) error {
+	results, err := client.RunUpgradeChecks(vers)
+	if params.IsCodeNotImplemented(err) {
+		logger.Warningf("API server does not support pre-upgrade checks")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot run pre-upgrade checks")
+	}
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			ctx.Infof("pre-upgrade check %q failed: %s", result.Name, result.Error.Message)
+			failed++
+		}
+	}
+	if failed == 0 || c.DryRun {
+		return nil
+	}
+	if c.IgnoreChecks {
+		ctx.Infof("ignoring %d failed pre-upgrade check(s)", failed)
+		return nil
+	}
+	return errors.Errorf("%d pre-upgrade check(s) failed; resolve the problems or use --ignore-checks", failed)
+}
+
+func printAgentRolloutStatus(w io.Writer, status params.AgentRolloutStatus) {
+	fmt.Fprintf(w, "staged upgrade from %s to %s: %s\n", status.PreviousVersion, status.TargetVersion, status.Phase)
+	fmt.Fprintf(w, "canaries: %s\n", strings.Join(status.Canaries, ", "))
+	fmt.Fprintf(w, "bake period: %s\n", status.BakePeriod)
+	if status.BakeStarted != nil {
+		fmt.Fprintf(w, "baking since: %s\n", status.BakeStarted.Format(time.RFC3339))
+	}
+	if status.Message != "" {
+		fmt.Fprintf(w, "message: %s\n", status.Message)
+	}
+}
+
 const resetPreviousUpgradeMessage = `
 WARNING! using --reset-previous-upgrade when an upgrade is in progress
 will cause the upgrade to fail. Only use this option to clear an
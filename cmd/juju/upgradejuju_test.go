@@ -10,6 +10,7 @@ import (
 	"io"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -108,6 +109,31 @@ var upgradeJujuTests = []struct {
 	currentVersion: "4.2.0-quantal-amd64",
 	args:           []string{"--series", "precise,quantal"},
 	expectInitErr:  "--series requires --upload-tools",
+}, {
+	about:          "invalid --stage",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--stage", "bake"},
+	expectInitErr:  `invalid --stage "bake" \(expected canary, status, promote or rollback\)`,
+}, {
+	about:          "--stage canary without --canaries",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--stage", "canary"},
+	expectInitErr:  "--stage canary requires --canaries",
+}, {
+	about:          "--canaries without --stage canary",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--canaries", "1,2"},
+	expectInitErr:  "--canaries requires --stage canary",
+}, {
+	about:          "negative --bake-period",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--stage", "canary", "--canaries", "1", "--bake-period", "-1h"},
+	expectInitErr:  "--bake-period must not be negative",
+}, {
+	about:          "--stage promote with --version",
+	currentVersion: "4.2.0-quantal-amd64",
+	args:           []string{"--stage", "promote", "--version", "4.2.1"},
+	expectInitErr:  "--stage promote cannot be used with other upgrade options",
 }, {
 	about:          "--upload-tools with inappropriate version 1",
 	currentVersion: "4.2.0-quantal-amd64",
@@ -635,6 +661,110 @@ func (s *UpgradeJujuSuite) TestResetPreviousUpgrade(c *gc.C) {
 	}
 }
 
+func (s *UpgradeJujuSuite) TestStagedUpgradeCanary(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	cmd := &UpgradeJujuCommand{}
+	err := coretesting.InitCommand(envcmd.Wrap(cmd),
+		[]string{"--stage", "canary", "--canaries", "1, 2", "--bake-period", "30m"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd.Canaries, jc.DeepEquals, []string{"1", "2"})
+
+	ctx := coretesting.Context(c)
+	err = cmd.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(fakeAPI.rolloutStartedWith, jc.DeepEquals, params.StartAgentRollout{
+		Version:    fakeAPI.nextVersion.Number,
+		Canaries:   []string{"1", "2"},
+		BakePeriod: 30 * time.Minute,
+	})
+	c.Assert(coretesting.Stdout(ctx), gc.Matches, "staged upgrade from .* to "+fakeAPI.nextVersion.Number.String()+": canary\n"+
+		"canaries: 1, 2\n"+
+		"bake period: 30m0s\n")
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgradeStatus(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	bakeStarted := time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC)
+	fakeAPI.rolloutStatus = params.AgentRolloutStatus{
+		PreviousVersion: version.MustParse("1.25.0"),
+		TargetVersion:   version.MustParse("1.25.1"),
+		Canaries:        []string{"3"},
+		BakePeriod:      time.Hour,
+		Phase:           "rolled-back",
+		BakeStarted:     &bakeStarted,
+		Message:         "unit wordpress/0: hook failed: install",
+	}
+	fakeAPI.patch(s)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--stage", "status")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, ""+
+		"staged upgrade from 1.25.0 to 1.25.1: rolled-back\n"+
+		"canaries: 3\n"+
+		"bake period: 1h0m0s\n"+
+		"baking since: 2015-09-01T12:00:00Z\n"+
+		"message: unit wordpress/0: hook failed: install\n")
+}
+
+func (s *UpgradeJujuSuite) TestStagedUpgradePromoteAndRollBack(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.patch(s)
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--stage", "promote")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.promoteCalled, jc.IsTrue)
+	c.Assert(fakeAPI.rollBackCalled, jc.IsFalse)
+
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--stage", "rollback")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.rollBackCalled, jc.IsTrue)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeChecksFailed(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.checkResults = []params.UpgradeCheckResult{{
+		Name: "disk-space",
+	}, {
+		Name:  "deprecated-config",
+		Error: &params.Error{Message: "deprecated settings in use: tools-url (no longer supported)"},
+	}}
+	fakeAPI.patch(s)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}))
+	c.Assert(err, gc.ErrorMatches, `1 pre-upgrade check\(s\) failed; resolve the problems or use --ignore-checks`)
+	c.Assert(fakeAPI.checksCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+	c.Assert(coretesting.Stderr(ctx), jc.Contains,
+		`pre-upgrade check "deprecated-config" failed: deprecated settings in use: tools-url (no longer supported)`)
+
+	// A dry run reports the failures without failing.
+	_, err = coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, version.Number{})
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeChecksIgnored(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.checkResults = []params.UpgradeCheckResult{{
+		Name:  "disk-space",
+		Error: &params.Error{Message: "only 100MiB free in /var/lib/juju, at least 1024MiB needed"},
+	}}
+	fakeAPI.patch(s)
+	ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}), "--ignore-checks")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+	c.Assert(coretesting.Stderr(ctx), jc.Contains, "ignoring 1 failed pre-upgrade check(s)")
+}
+
+func (s *UpgradeJujuSuite) TestUpgradeChecksNotImplemented(c *gc.C) {
+	fakeAPI := NewFakeUpgradeJujuAPI(c, s.State)
+	fakeAPI.checksErr = &params.Error{Code: params.CodeNotImplemented, Message: "unknown object type"}
+	fakeAPI.patch(s)
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&UpgradeJujuCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeAPI.setVersionCalledWith, gc.Equals, fakeAPI.nextVersion.Number)
+}
+
 func NewFakeUpgradeJujuAPI(c *gc.C, st *state.State) *fakeUpgradeJujuAPI {
 	nextVersion := version.Current
 	nextVersion.Minor++
@@ -652,6 +782,13 @@ type fakeUpgradeJujuAPI struct {
 	setVersionErr             error
 	abortCurrentUpgradeCalled bool
 	setVersionCalledWith      version.Number
+	rolloutStartedWith        params.StartAgentRollout
+	rolloutStatus             params.AgentRolloutStatus
+	promoteCalled             bool
+	rollBackCalled            bool
+	checksCalledWith          version.Number
+	checkResults              []params.UpgradeCheckResult
+	checksErr                 error
 }
 
 func (a *fakeUpgradeJujuAPI) reset() {
@@ -700,6 +837,40 @@ func (a *fakeUpgradeJujuAPI) SetEnvironAgentVersion(v version.Number) error {
 	return a.setVersionErr
 }
 
+func (a *fakeUpgradeJujuAPI) StartAgentRollout(v version.Number, canaries []string, bakePeriod time.Duration) (params.AgentRolloutStatus, error) {
+	a.rolloutStartedWith = params.StartAgentRollout{
+		Version:    v,
+		Canaries:   canaries,
+		BakePeriod: bakePeriod,
+	}
+	return params.AgentRolloutStatus{
+		PreviousVersion: version.Current.Number,
+		TargetVersion:   v,
+		Canaries:        canaries,
+		BakePeriod:      bakePeriod,
+		Phase:           "canary",
+	}, nil
+}
+
+func (a *fakeUpgradeJujuAPI) AgentRolloutStatus() (params.AgentRolloutStatus, error) {
+	return a.rolloutStatus, nil
+}
+
+func (a *fakeUpgradeJujuAPI) PromoteAgentRollout() error {
+	a.promoteCalled = true
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) RollBackAgentRollout() error {
+	a.rollBackCalled = true
+	return nil
+}
+
+func (a *fakeUpgradeJujuAPI) RunUpgradeChecks(v version.Number) ([]params.UpgradeCheckResult, error) {
+	a.checksCalledWith = v
+	return a.checkResults, a.checksErr
+}
+
 func (a *fakeUpgradeJujuAPI) Close() error {
 	return nil
 }
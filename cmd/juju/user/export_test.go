@@ -18,6 +18,9 @@ var (
 	GetConnectionCredentials = &getConnectionCredentials
 	// disable and enable
 	GetDisableUserAPI = &getDisableUserAPI
+	// two-factor authentication
+	GetTwoFactorAPI = &getTwoFactorAPI
+	NewTOTPSecret   = &newTOTPSecret
 )
 
 // DisenableCommand is used for testing both Disable and Enable user commands.
@@ -13,6 +13,7 @@ import (
 
 	"github.com/juju/juju/api/usermanager"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/output"
 )
 
 const InfoCommandDoc = `
@@ -59,7 +60,7 @@ type InfoCommandBase struct {
 	UserCommandBase
 	api       UserInfoAPI
 	exactTime bool
-	out       cmd.Output
+	out       output.Output
 }
 
 func (c *InfoCommandBase) SetFlags(f *gnuflag.FlagSet) {
@@ -81,6 +82,11 @@ type UserInfo struct {
 	Disabled       bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
 }
 
+// ID implements output.Identifier.
+func (u UserInfo) ID() string {
+	return u.Username
+}
+
 // Info implements Command.Info.
 func (c *InfoCommand) Info() *cmd.Info {
 	return &cmd.Info{
@@ -0,0 +1,192 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/utils/totp"
+)
+
+const enableTwoFactorDoc = `
+Enrols you for two-factor authentication. A new secret is shown, for
+you to add to an authenticator app such as Google Authenticator, either
+by typing it in or by reading the otpauth URI from a QR code. You are
+then asked for the code the app shows, to check that it has been set up.
+If you are already enrolled, you are also asked for a code for your
+current secret before it is replaced.
+
+Once enrolled, you must give the current code from the app whenever
+you log in, with your password or a session token started by an
+earlier login, by setting the %s environment
+variable. Each code may only be used once.
+
+Examples:
+  juju user enable-2fa
+
+See Also:
+  juju user disable-2fa
+`
+
+const disableTwoFactorDoc = `
+Removes the two-factor authentication enrollment of the user you are
+currently logged in as, or as an admin, of another user, such as one
+who has lost their authenticator. If the user is not enrolled, this
+command succeeds silently.
+
+Examples:
+  juju user disable-2fa
+
+  # Disable two-factor authentication for bob
+  juju user disable-2fa bob
+
+See Also:
+  juju user enable-2fa
+`
+
+// TwoFactorAPI defines the usermanager API methods that the two-factor
+// authentication commands use.
+type TwoFactorAPI interface {
+	EnableTOTP(secret, code, currentCode string) error
+	DisableTOTP(username string) error
+	Close() error
+}
+
+func (c *UserCommandBase) getTwoFactorAPI() (TwoFactorAPI, error) {
+	return c.NewUserManagerClient()
+}
+
+var (
+	getTwoFactorAPI = (*UserCommandBase).getTwoFactorAPI
+	newTOTPSecret   = totp.NewSecret
+)
+
+// EnableTwoFactorCommand enrols the current user for two-factor
+// authentication.
+type EnableTwoFactorCommand struct {
+	UserCommandBase
+}
+
+// Info implements Command.Info.
+func (c *EnableTwoFactorCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "enable-2fa",
+		Purpose: "enrol for two-factor authentication",
+		Doc:     fmt.Sprintf(enableTwoFactorDoc, osenv.JujuTOTPCodeEnvKey),
+	}
+}
+
+// Init implements Command.Init.
+func (c *EnableTwoFactorCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *EnableTwoFactorCommand) Run(ctx *cmd.Context) error {
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := getTwoFactorAPI(&c.UserCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "Add this secret to your authenticator app:\n\n")
+	fmt.Fprintf(ctx.Stdout, "  secret: %s\n", secret)
+	fmt.Fprintf(ctx.Stdout, "  uri:    %s\n\n", totp.URI("juju", creds.User, secret))
+	scanner := bufio.NewScanner(ctx.Stdin)
+	code, err := readCode(ctx, scanner, "Enter the code shown by the app: ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = client.EnableTOTP(secret, code, "")
+	if params.IsCodeSecondFactorRequired(err) {
+		// The user is already enrolled, and must show that they
+		// hold the current secret to replace it.
+		currentCode, err := readCode(ctx, scanner, "Enter the code for your current enrollment: ")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		err = client.EnableTOTP(secret, code, currentCode)
+	}
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Two-factor authentication enabled for %q", creds.User)
+	return nil
+}
+
+// readCode prompts for and reads a two-factor authentication code.
+func readCode(ctx *cmd.Context, scanner *bufio.Scanner, prompt string) (string, error) {
+	fmt.Fprint(ctx.Stdout, prompt)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil {
+		return "", errors.Trace(err)
+	}
+	code := strings.TrimSpace(scanner.Text())
+	if code == "" {
+		return "", errors.New("no code entered, two-factor authentication not enabled")
+	}
+	return code, nil
+}
+
+// DisableTwoFactorCommand removes the two-factor authentication
+// enrollment of a user.
+type DisableTwoFactorCommand struct {
+	UserCommandBase
+	User string
+}
+
+// Info implements Command.Info.
+func (c *DisableTwoFactorCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "disable-2fa",
+		Args:    "[username]",
+		Purpose: "disable two-factor authentication for a user",
+		Doc:     disableTwoFactorDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *DisableTwoFactorCommand) Init(args []string) error {
+	var err error
+	c.User, err = cmd.ZeroOrOneArgs(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *DisableTwoFactorCommand) Run(ctx *cmd.Context) error {
+	username := c.User
+	if username == "" {
+		creds, err := c.ConnectionCredentials()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		username = creds.User
+	}
+	client, err := getTwoFactorAPI(&c.UserCommandBase)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+	if err := client.DisableTOTP(username); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Two-factor authentication disabled for %q", username)
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/testing"
+)
+
+type TwoFactorSuite struct {
+	BaseSuite
+	api *mockTwoFactorAPI
+}
+
+var _ = gc.Suite(&TwoFactorSuite{})
+
+type mockTwoFactorAPI struct {
+	gitjujutesting.Stub
+}
+
+func (m *mockTwoFactorAPI) EnableTOTP(secret, code, currentCode string) error {
+	m.AddCall("EnableTOTP", secret, code, currentCode)
+	return m.NextErr()
+}
+
+func (m *mockTwoFactorAPI) DisableTOTP(username string) error {
+	m.AddCall("DisableTOTP", username)
+	return m.NextErr()
+}
+
+func (m *mockTwoFactorAPI) Close() error {
+	m.AddCall("Close")
+	return nil
+}
+
+func (s *TwoFactorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = &mockTwoFactorAPI{}
+	s.PatchValue(user.GetTwoFactorAPI, func(*user.UserCommandBase) (user.TwoFactorAPI, error) {
+		return s.api, nil
+	})
+	s.PatchValue(user.NewTOTPSecret, func() (string, error) {
+		return "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", nil
+	})
+}
+
+func (s *TwoFactorSuite) run(c *gc.C, command cmd.Command, stdin string, args ...string) (*cmd.Context, error) {
+	ctx := testing.Context(c)
+	ctx.Stdin = strings.NewReader(stdin)
+	err := testing.InitCommand(command, args)
+	if err == nil {
+		err = command.Run(ctx)
+	}
+	return ctx, err
+}
+
+func (s *TwoFactorSuite) TestEnable(c *gc.C) {
+	ctx, err := s.run(c, envcmd.Wrap(&user.EnableTwoFactorCommand{}), "123456\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+Add this secret to your authenticator app:
+
+  secret: GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ
+  uri:    otpauth://totp/juju:user-test?issuer=juju&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ
+
+Enter the code shown by the app: `[1:])
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Two-factor authentication enabled for \"user-test\"\n")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"EnableTOTP", []interface{}{"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "123456", ""}},
+		{"Close", nil},
+	})
+}
+
+func (s *TwoFactorSuite) TestEnableReplacing(c *gc.C) {
+	s.api.SetErrors(&params.Error{
+		Message: "two-factor authentication code required",
+		Code:    params.CodeSecondFactorRequired,
+	})
+	ctx, err := s.run(c, envcmd.Wrap(&user.EnableTwoFactorCommand{}), "123456\n654321\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), jc.HasSuffix, "Enter the code shown by the app: Enter the code for your current enrollment: ")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"EnableTOTP", []interface{}{"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "123456", ""}},
+		{"EnableTOTP", []interface{}{"GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", "123456", "654321"}},
+		{"Close", nil},
+	})
+}
+
+func (s *TwoFactorSuite) TestEnableNoCode(c *gc.C) {
+	_, err := s.run(c, envcmd.Wrap(&user.EnableTwoFactorCommand{}), "\n")
+	c.Assert(err, gc.ErrorMatches, "no code entered, two-factor authentication not enabled")
+	s.api.CheckCallNames(c, "Close")
+}
+
+func (s *TwoFactorSuite) TestEnableBadCode(c *gc.C) {
+	s.api.SetErrors(errors.New("invalid two-factor authentication code"))
+	_, err := s.run(c, envcmd.Wrap(&user.EnableTwoFactorCommand{}), "000000\n")
+	c.Assert(err, gc.ErrorMatches, "invalid two-factor authentication code")
+}
+
+func (s *TwoFactorSuite) TestEnableInit(c *gc.C) {
+	err := testing.InitCommand(&user.EnableTwoFactorCommand{}, []string{"bob"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bob"\]`)
+}
+
+func (s *TwoFactorSuite) TestDisableSelf(c *gc.C) {
+	ctx, err := s.run(c, envcmd.Wrap(&user.DisableTwoFactorCommand{}), "")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Two-factor authentication disabled for \"user-test\"\n")
+	s.api.CheckCall(c, 0, "DisableTOTP", "user-test")
+}
+
+func (s *TwoFactorSuite) TestDisableOther(c *gc.C) {
+	_, err := s.run(c, envcmd.Wrap(&user.DisableTwoFactorCommand{}), "", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "DisableTOTP", "bob")
+}
+
+func (s *TwoFactorSuite) TestDisableInit(c *gc.C) {
+	err := testing.InitCommand(&user.DisableTwoFactorCommand{}, []string{"bob", "fred"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["fred"\]`)
+}
@@ -25,7 +25,9 @@ var expectedUserCommmandNames = []string{
 	"add",
 	"change-password",
 	"disable",
+	"disable-2fa",
 	"enable",
+	"enable-2fa",
 	"help",
 	"info",
 	"list",
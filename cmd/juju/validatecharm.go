@@ -0,0 +1,176 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"text/tabwriter"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api/charmvalidation"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// charmValidationAPI defines the methods on the CharmValidation API
+// that the validate-charm command uses.
+type charmValidationAPI interface {
+	ValidateCharm(archive []byte, series string) (params.ValidateCharmResult, error)
+	Close() error
+}
+
+var newCharmValidationAPI = func(c *ValidateCharmCommand) (charmValidationAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charmvalidation.NewClient(root), nil
+}
+
+// ValidateCharmCommand checks a local charm against the features
+// supported by the controller.
+type ValidateCharmCommand struct {
+	envcmd.EnvCommandBase
+	out    cmd.Output
+	path   string
+	series string
+}
+
+const validateCharmDoc = `
+Check a charm against the features supported by the controller before
+deploying it, and warn about each feature the charm uses that will not
+work: an unsupported series, metadata fields and resources the
+controller does not understand, unsupported kinds of storage, and files
+in the hooks directory that will never be run as hooks.
+
+The charm may be given as a charm directory or archive. It is checked
+for the series given with --series, or the environment's default series.
+
+Examples:
+    juju validate-charm ~/charms/trusty/mysql
+    juju validate-charm --series precise mysql.zip
+`
+
+func (c *ValidateCharmCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "validate-charm",
+		Args:    "<charm path>",
+		Purpose: "check a charm against the features supported by the controller",
+		Doc:     validateCharmDoc,
+	}
+}
+
+func (c *ValidateCharmCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCharmValidationTabular,
+	})
+	f.StringVar(&c.series, "series", "", "the series the charm is to be deployed on")
+}
+
+func (c *ValidateCharmCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no charm specified")
+	}
+	c.path = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// charmValidation describes the result of validating a charm.
+type charmValidation struct {
+	Charm    string         `json:"charm" yaml:"charm"`
+	Series   string         `json:"series" yaml:"series"`
+	Warnings []charmWarning `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// charmWarning describes a feature of a charm that the controller does
+// not support.
+type charmWarning struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	Name    string `json:"name" yaml:"name"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (c *ValidateCharmCommand) Run(ctx *cmd.Context) error {
+	path := ctx.AbsPath(c.path)
+	ch, err := charm.ReadCharm(path)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read charm %q", c.path)
+	}
+	archive, err := charmArchiveBytes(ch)
+	if err != nil {
+		return errors.Annotatef(err, "cannot read charm %q", c.path)
+	}
+
+	client, err := newCharmValidationAPI(c)
+	if err != nil {
+		return fmt.Errorf(connectionError, c.ConnectionName(), err)
+	}
+	defer client.Close()
+
+	result, err := client.ValidateCharm(archive, c.series)
+	if err != nil {
+		return errors.Annotatef(err, "cannot validate charm %q", c.path)
+	}
+	validation := charmValidation{
+		Charm:  ch.Meta().Name,
+		Series: result.Series,
+	}
+	for _, warning := range result.Warnings {
+		validation.Warnings = append(validation.Warnings, charmWarning{
+			Kind:    warning.Kind,
+			Name:    warning.Name,
+			Message: warning.Message,
+		})
+	}
+	if len(validation.Warnings) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("Charm %q is compatible with this controller on series %q.", validation.Charm, validation.Series)
+		return nil
+	}
+	return c.out.Write(ctx, validation)
+}
+
+// charmArchiveBytes returns the zipped content of the given charm,
+// archiving a charm directory as it would be archived on deployment.
+func charmArchiveBytes(ch charm.Charm) ([]byte, error) {
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		var buf bytes.Buffer
+		if err := ch.ArchiveTo(&buf); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return buf.Bytes(), nil
+	case *charm.CharmArchive:
+		data, err := ioutil.ReadFile(ch.Path)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return data, nil
+	}
+	return nil, errors.Errorf("unknown charm type %T", ch)
+}
+
+// formatCharmValidationTabular returns a table of the warnings found
+// when validating a charm.
+func formatCharmValidationTabular(value interface{}) ([]byte, error) {
+	validation, ok := value.(charmValidation)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", validation, value)
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAME\tWARNING")
+	for _, w := range validation.Warnings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", w.Kind, w.Name, w.Message)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
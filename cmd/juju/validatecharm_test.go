@@ -0,0 +1,151 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/set"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type ValidateCharmSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeCharmValidationAPI
+}
+
+var _ = gc.Suite(&ValidateCharmSuite{})
+
+type fakeCharmValidationAPI struct {
+	archive []byte
+	series  string
+	result  params.ValidateCharmResult
+	err     error
+	closed  bool
+}
+
+func (f *fakeCharmValidationAPI) ValidateCharm(archive []byte, series string) (params.ValidateCharmResult, error) {
+	f.archive = archive
+	f.series = series
+	return f.result, f.err
+}
+
+func (f *fakeCharmValidationAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+func (s *ValidateCharmSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeCharmValidationAPI{
+		result: params.ValidateCharmResult{
+			Series: "trusty",
+			Warnings: []params.CharmCompatibilityWarning{{
+				Kind:    "storage",
+				Name:    "data",
+				Message: "shared storage is not supported by this controller",
+			}, {
+				Kind:    "hook",
+				Name:    "common.sh",
+				Message: `"common.sh" is not a hook known to this controller and will never be run`,
+			}},
+		},
+	}
+	s.PatchValue(&newCharmValidationAPI, func(_ *ValidateCharmCommand) (charmValidationAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *ValidateCharmSuite) run(c *gc.C, args ...string) (string, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ValidateCharmCommand{}), args...)
+	if err != nil {
+		return "", err
+	}
+	return testing.Stdout(ctx), nil
+}
+
+// archiveFiles returns the names of the files in the given charm archive.
+func archiveFiles(c *gc.C, archive []byte) []string {
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	c.Assert(err, jc.ErrorIsNil)
+	var files []string
+	for _, f := range r.File {
+		files = append(files, f.Name)
+	}
+	return files
+}
+
+func (s *ValidateCharmSuite) TestInit(c *gc.C) {
+	err := testing.InitCommand(&ValidateCharmCommand{}, nil)
+	c.Assert(err, gc.ErrorMatches, "no charm specified")
+	err = testing.InitCommand(&ValidateCharmCommand{}, []string{"mysql", "wordpress"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["wordpress"\]`)
+}
+
+func (s *ValidateCharmSuite) TestCharmDir(c *gc.C) {
+	out, err := s.run(c, testcharms.Repo.CharmDirPath("dummy"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"KIND    NAME      WARNING\n"+
+		"storage data      shared storage is not supported by this controller\n"+
+		"hook    common.sh \"common.sh\" is not a hook known to this controller and will never be run\n",
+	)
+	c.Assert(set.NewStrings(archiveFiles(c, s.api.archive)...).Contains("metadata.yaml"), jc.IsTrue)
+	c.Assert(s.api.series, gc.Equals, "")
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ValidateCharmSuite) TestCharmArchive(c *gc.C) {
+	path := testcharms.Repo.CharmArchivePath(c.MkDir(), "dummy")
+	_, err := s.run(c, path, "--series", "precise")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.archive, jc.DeepEquals, data)
+	c.Assert(s.api.series, gc.Equals, "precise")
+}
+
+func (s *ValidateCharmSuite) TestYaml(c *gc.C) {
+	s.api.result.Warnings = s.api.result.Warnings[:1]
+	out, err := s.run(c, testcharms.Repo.CharmDirPath("dummy"), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"charm: dummy\n"+
+		"series: trusty\n"+
+		"warnings:\n"+
+		"- kind: storage\n"+
+		"  name: data\n"+
+		"  message: shared storage is not supported by this controller\n",
+	)
+}
+
+func (s *ValidateCharmSuite) TestCompatible(c *gc.C) {
+	s.api.result.Warnings = nil
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ValidateCharmCommand{}), testcharms.Repo.CharmDirPath("dummy"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "Charm \"dummy\" is compatible with this controller on series \"trusty\".\n")
+}
+
+func (s *ValidateCharmSuite) TestNotACharm(c *gc.C) {
+	_, err := s.run(c, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, `cannot read charm ".*": .*`)
+	c.Assert(s.api.archive, gc.IsNil)
+}
+
+func (s *ValidateCharmSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("invalid charm archive: no metadata.yaml")
+	_, err := s.run(c, testcharms.Repo.CharmDirPath("dummy"))
+	c.Assert(err, gc.ErrorMatches, `cannot validate charm ".*": invalid charm archive: no metadata.yaml`)
+	c.Assert(s.api.closed, jc.IsTrue)
+}
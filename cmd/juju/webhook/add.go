@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const addCommandDoc = `
+Add a URL to which notifications of events in the Juju environment are
+posted, and print the id of the new webhook.
+
+By default the webhook is notified of all events; --events restricts
+it to a comma-separated list of events. See "juju help webhook" for
+the events.
+
+Examples:
+
+  # Post all notifications to a Slack-compatible endpoint.
+  juju webhook add https://hooks.example.com/T000/B000
+
+  # Post signed hook failure notifications only.
+  juju webhook add https://alerts.example.com/juju --secret s3cret --events hook-failed
+`
+
+// AddCommand adds a webhook to the environment.
+type AddCommand struct {
+	WebhookCommandBase
+	URL    string
+	Secret string
+	Events []string
+	events string
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<url>",
+		Purpose: "add a notification webhook",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.WebhookCommandBase.SetFlags(f)
+	f.StringVar(&c.Secret, "secret", "", "the secret with which to sign notifications")
+	f.StringVar(&c.events, "events", "", "the comma-separated events to notify")
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no URL specified")
+	}
+	c.URL, args = args[0], args[1:]
+	c.Events = nil
+	for _, event := range strings.Split(c.events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			c.Events = append(c.Events, event)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) error {
+	client, err := getWebhooksAPI(&c.WebhookCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	id, err := client.AddWebhook(c.URL, c.Secret, c.Events)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	fmt.Fprintln(ctx.Stdout, id)
+	return nil
+}
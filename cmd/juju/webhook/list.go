@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/juju/output"
+)
+
+const listCommandDoc = `
+List the webhooks of the Juju environment. Their secrets are not shown.
+
+Examples:
+
+  juju webhook list
+  juju webhook list --format json
+`
+
+// ListCommand shows the webhooks of the environment.
+type ListCommand struct {
+	WebhookCommandBase
+	out output.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list notification webhooks",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.WebhookCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// WebhookInfo defines the serialization behaviour of a webhook.
+type WebhookInfo struct {
+	Id     string   `yaml:"id" json:"id"`
+	URL    string   `yaml:"url" json:"url"`
+	Signed bool     `yaml:"signed" json:"signed"`
+	Events []string `yaml:"events" json:"events"`
+}
+
+// ID implements output.Identifier.
+func (w WebhookInfo) ID() string {
+	return w.Id
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := getWebhooksAPI(&c.WebhookCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	webhooks, err := client.Webhooks()
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		fmt.Fprintf(ctx.Stderr, "no webhooks\n")
+		return nil
+	}
+	output := make([]WebhookInfo, len(webhooks))
+	for i, webhook := range webhooks {
+		events := webhook.Events
+		if len(events) == 0 {
+			events = []string{"all"}
+		}
+		output[i] = WebhookInfo{
+			Id:     webhook.Id,
+			URL:    webhook.URL,
+			Signed: webhook.Signed,
+			Events: events,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const removeCommandDoc = `
+Remove a webhook from the Juju environment, so that no more
+notifications are posted to it. Webhooks are identified by the ids
+shown by "juju webhook list".
+
+Examples:
+
+  juju webhook remove 3
+`
+
+// RemoveCommand removes a webhook from the environment.
+type RemoveCommand struct {
+	WebhookCommandBase
+	Id string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<id>",
+		Purpose: "remove a notification webhook",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook id specified")
+	}
+	c.Id, args = args[0], args[1:]
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	client, err := getWebhooksAPI(&c.WebhookCommandBase)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.RemoveWebhook(c.Id); err != nil {
+		return block.ProcessBlockedError(err, block.BlockRemove)
+	}
+	return nil
+}
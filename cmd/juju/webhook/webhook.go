@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const webhookCommandDoc = `
+"juju webhook" is used to manage the URLs to which notifications of
+events in the Juju environment are posted.
+
+The events are:
+  hook-failed       a hook of a unit failed
+  machine-down      the agent of a machine stopped communicating
+  service-scaled    an external scaler changed the units of a service
+  upgrade-complete  every machine agent is running the upgraded version
+
+Each notification is posted as a JSON document. When a webhook has a
+secret, the hex-encoded HMAC-SHA256 of the document, keyed with the
+secret, is sent in the X-Juju-Signature header.
+`
+
+const webhookCommandPurpose = "manage notification webhooks"
+
+// NewSuperCommand creates the webhook supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	webhookcmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "webhook",
+		Doc:         webhookCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     webhookCommandPurpose,
+	})
+	webhookcmd.Register(envcmd.Wrap(&AddCommand{}))
+	webhookcmd.Register(envcmd.Wrap(&ListCommand{}))
+	webhookcmd.Register(envcmd.Wrap(&RemoveCommand{}))
+	return webhookcmd
+}
+
+// WebhooksAPI defines the webhooks API methods used by the webhook
+// commands.
+type WebhooksAPI interface {
+	AddWebhook(url, secret string, events []string) (string, error)
+	Webhooks() ([]params.Webhook, error)
+	RemoveWebhook(id string) error
+	Close() error
+}
+
+// WebhookCommandBase is a helper base structure that has a method to
+// get the webhooks client.
+type WebhookCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+var getWebhooksAPI = func(c *WebhookCommandBase) (WebhooksAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return webhooks.NewClient(root), nil
+}
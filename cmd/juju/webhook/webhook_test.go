@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/webhook"
+	"github.com/juju/juju/testing"
+)
+
+type webhookSuite struct {
+	testing.FakeJujuHomeSuite
+	api *fakeWebhooksAPI
+}
+
+var _ = gc.Suite(&webhookSuite{})
+
+type fakeWebhooksAPI struct {
+	added    []params.AddWebhookArgs
+	removed  []string
+	webhooks []params.Webhook
+	err      error
+}
+
+func (f *fakeWebhooksAPI) AddWebhook(url, secret string, events []string) (string, error) {
+	f.added = append(f.added, params.AddWebhookArgs{URL: url, Secret: secret, Events: events})
+	return "7", f.err
+}
+
+func (f *fakeWebhooksAPI) Webhooks() ([]params.Webhook, error) {
+	return f.webhooks, f.err
+}
+
+func (f *fakeWebhooksAPI) RemoveWebhook(id string) error {
+	f.removed = append(f.removed, id)
+	return f.err
+}
+
+func (f *fakeWebhooksAPI) Close() error {
+	return nil
+}
+
+func (s *webhookSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.api = &fakeWebhooksAPI{}
+	s.PatchValue(webhook.GetWebhooksAPI, func(*webhook.WebhookCommandBase) (webhook.WebhooksAPI, error) {
+		return s.api, nil
+	})
+}
+
+func (s *webhookSuite) TestAdd(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&webhook.AddCommand{}),
+		"https://example.com/hook", "--secret", "s3cret", "--events", "hook-failed, machine-down")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "7\n")
+	c.Assert(s.api.added, jc.DeepEquals, []params.AddWebhookArgs{{
+		URL:    "https://example.com/hook",
+		Secret: "s3cret",
+		Events: []string{"hook-failed", "machine-down"},
+	}})
+}
+
+func (s *webhookSuite) TestAddAllEvents(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.AddCommand{}), "https://example.com/hook")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.added, jc.DeepEquals, []params.AddWebhookArgs{{
+		URL: "https://example.com/hook",
+	}})
+}
+
+func (s *webhookSuite) TestAddInit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.AddCommand{}))
+	c.Assert(err, gc.ErrorMatches, "no URL specified")
+	_, err = testing.RunCommand(c, envcmd.Wrap(&webhook.AddCommand{}), "https://example.com/hook", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *webhookSuite) TestAddBlocked(c *gc.C) {
+	s.api.err = common.ErrOperationBlocked("TestAddBlocked")
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.AddCommand{}), "https://example.com/hook")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+}
+
+func (s *webhookSuite) TestList(c *gc.C) {
+	s.api.webhooks = []params.Webhook{{
+		Id:     "0",
+		URL:    "https://example.com/hook",
+		Signed: true,
+		Events: []string{"hook-failed"},
+	}, {
+		Id:  "1",
+		URL: "http://example.com/other",
+	}}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&webhook.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, ""+
+		"- id: \"0\"\n"+
+		"  url: https://example.com/hook\n"+
+		"  signed: true\n"+
+		"  events:\n"+
+		"  - hook-failed\n"+
+		"- id: \"1\"\n"+
+		"  url: http://example.com/other\n"+
+		"  signed: false\n"+
+		"  events:\n"+
+		"  - all\n",
+	)
+}
+
+func (s *webhookSuite) TestListNone(c *gc.C) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&webhook.ListCommand{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "no webhooks\n")
+}
+
+func (s *webhookSuite) TestRemove(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.RemoveCommand{}), "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.removed, jc.DeepEquals, []string{"3"})
+}
+
+func (s *webhookSuite) TestRemoveInit(c *gc.C) {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.RemoveCommand{}))
+	c.Assert(err, gc.ErrorMatches, "no webhook id specified")
+}
+
+func (s *webhookSuite) TestRemoveError(c *gc.C) {
+	s.api.err = errors.NotFoundf(`webhook "3"`)
+	_, err := testing.RunCommand(c, envcmd.Wrap(&webhook.RemoveCommand{}), "3")
+	c.Assert(err, gc.ErrorMatches, `webhook "3" not found`)
+}
@@ -46,12 +46,14 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/introspection"
 	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/juju/paths"
 	"github.com/juju/juju/lease"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
@@ -61,6 +63,7 @@ import (
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/addresser"
+	"github.com/juju/juju/worker/agentrollout"
 	"github.com/juju/juju/worker/apiaddressupdater"
 	"github.com/juju/juju/worker/authenticationworker"
 	"github.com/juju/juju/worker/certupdater"
@@ -70,17 +73,26 @@ import (
 	"github.com/juju/juju/worker/dblogpruner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/dnsregistrar"
 	"github.com/juju/juju/worker/envworkermanager"
 	"github.com/juju/juju/worker/firewaller"
+	"github.com/juju/juju/worker/hostkeyreporter"
+	"github.com/juju/juju/worker/instancedrift"
 	"github.com/juju/juju/worker/instancepoller"
+	introspectionworker "github.com/juju/juju/worker/introspection"
 	"github.com/juju/juju/worker/localstorage"
 	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/machiner"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/minunitsworker"
+	"github.com/juju/juju/worker/mongosessionupdater"
 	"github.com/juju/juju/worker/networker"
+	"github.com/juju/juju/worker/notifications"
+	"github.com/juju/juju/worker/orphancollector"
+	"github.com/juju/juju/worker/passwordrotator"
 	"github.com/juju/juju/worker/peergrouper"
 	"github.com/juju/juju/worker/provisioner"
+	"github.com/juju/juju/worker/provisioningerrors"
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
 	"github.com/juju/juju/worker/resumer"
@@ -89,6 +101,8 @@ import (
 	"github.com/juju/juju/worker/statushistorypruner"
 	"github.com/juju/juju/worker/storageprovisioner"
 	"github.com/juju/juju/worker/terminationworker"
+	"github.com/juju/juju/worker/txnpruner"
+	"github.com/juju/juju/worker/unitutilization"
 	"github.com/juju/juju/worker/upgrader"
 )
 
@@ -279,6 +293,7 @@ func NewMachineAgent(
 		workersStarted:       make(chan struct{}),
 		upgradeWorkerContext: upgradeWorkerContext,
 		runner:               runner,
+		hub:                  pubsub.NewHub(),
 	}
 }
 
@@ -300,6 +315,10 @@ type MachineAgent struct {
 
 	mongoInitMutex   sync.Mutex
 	mongoInitialized bool
+
+	// hub carries events between the API server and the other
+	// state server workers run by the agent.
+	hub *pubsub.Hub
 }
 
 // IsRestorePreparing returns bool representing if we are in restore mode
@@ -410,6 +429,14 @@ func (a *MachineAgent) Run(*cmd.Context) error {
 	a.runner.StartWorker("termination", func() (worker.Worker, error) {
 		return terminationworker.NewWorker(), nil
 	})
+	if reporter := a.agentReporter(); reporter != nil && runtime.GOOS == "linux" {
+		a.runner.StartWorker("introspection", func() (worker.Worker, error) {
+			return introspectionworker.NewSocketWorker(introspectionworker.SocketConfig{
+				SocketName: introspectionworker.SocketName(a.Tag().String()),
+				Reporter:   reporter,
+			})
+		})
+	}
 	// At this point, all workers will have been configured to start
 	close(a.workersStarted)
 	err := a.runner.Wait()
@@ -709,8 +736,22 @@ func (a *MachineAgent) postUpgradeAPIWorker(
 		runner.StartWorker("stateconverter", func() (worker.Worker, error) {
 			return worker.NewNotifyWorker(conv2state.New(st.Machiner(), a)), nil
 		})
+		// State server machines share their API password with mongo,
+		// so it cannot be replaced while they are running.
+		runner.StartWorker("passwordrotator", func() (worker.Worker, error) {
+			return passwordrotator.New(entity, a, passwordrotator.DefaultRotationInterval), nil
+		})
 	}
 
+	// Windows machines do not run an SSH server.
+	if version.Current.OS != version.Windows {
+		runner.StartWorker("hostkeyreporter", func() (worker.Worker, error) {
+			return hostkeyreporter.New(st.HostKeyReporter(), agentConfig.Tag().Id(), "/"), nil
+		})
+	}
+	runner.StartWorker("unitutilization", func() (worker.Worker, error) {
+		return unitutilization.New(st.UnitUtilization(), "/proc"), nil
+	})
 	runner.StartWorker("diskmanager", func() (worker.Worker, error) {
 		api, err := st.DiskManager()
 		if err != nil {
@@ -950,8 +991,11 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 			a.startWorkerAfterUpgrade(runner, "env worker manager", func() (worker.Worker, error) {
 				return envworkermanager.NewEnvWorkerManager(st, a.startEnvWorkers), nil
 			})
+			a.startWorkerAfterUpgrade(runner, "mongosessionupdater", func() (worker.Worker, error) {
+				return mongosessionupdater.New(st), nil
+			})
 			a.startWorkerAfterUpgrade(runner, "peergrouper", func() (worker.Worker, error) {
-				return peergrouperNew(st)
+				return peergrouperNew(st, a.hub)
 			})
 			a.startWorkerAfterUpgrade(runner, "restore", func() (worker.Worker, error) {
 				return a.newRestoreStateWatcherWorker(st)
@@ -971,23 +1015,33 @@ func (a *MachineAgent) StateWorker() (worker.Worker, error) {
 				})
 			}
 			a.startWorkerAfterUpgrade(runner, "certupdater", func() (worker.Worker, error) {
-				return newCertificateUpdater(m, agentConfig, st, stateServingSetter, certChangedChan), nil
+				return newCertificateUpdater(m, st, agentConfig, st, stateServingSetter, certChangedChan), nil
 			})
 
 			if featureflag.Enabled(feature.DbLog) {
 				a.startWorkerAfterUpgrade(singularRunner, "dblogpruner", func() (worker.Worker, error) {
-					return dblogpruner.New(st, dblogpruner.NewLogPruneParams()), nil
+					params := dblogpruner.NewLogPruneParams()
+					params.FromEnvironConfig = true
+					return dblogpruner.New(st, params), nil
 				})
 			}
 			a.startWorkerAfterUpgrade(singularRunner, "statushistorypruner", func() (worker.Worker, error) {
 				return statushistorypruner.New(st, statushistorypruner.NewHistoryPrunerParams()), nil
 			})
 
+			a.startWorkerAfterUpgrade(singularRunner, "orphancollector", func() (worker.Worker, error) {
+				return orphancollector.New(st, orphancollector.DefaultCollectInterval), nil
+			})
+
+			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
+				return txnpruner.New(st, txnpruner.DefaultPruneInterval), nil
+			})
+
 			a.startWorkerAfterUpgrade(singularRunner, "resumer", func() (worker.Worker, error) {
 				// The action of resumer is so subtle that it is not tested,
 				// because we can't figure out how to do so without brutalising
 				// the transaction log.
-				return resumer.NewResumer(st), nil
+				return resumer.NewResumer(st, m), nil
 			})
 
 		case state.JobManageStateDeprecated:
@@ -1018,10 +1072,17 @@ func (a *MachineAgent) startEnvWorkers(
 		return nil, errors.Trace(err)
 	}
 
+	// Workers that must run only once for this environment run on
+	// whichever state server holds the environment's singular lease,
+	// so that the environments are shared out between the state
+	// servers and another takes over when the holder goes away.
+	singularConn := newSingularLeaseConn(st, envUUID, a.machineId)
+
 	// Create a runner for workers specific to this
-	// environment. Either the State or API connection failing will be
-	// considered fatal, killing the runner and all its workers.
-	runner = newConnRunner(st, apiSt)
+	// environment. Either the State or API connection failing, or
+	// the singular lease changing hands, will be considered fatal,
+	// killing the runner and all its workers.
+	runner = newConnRunner(st, apiSt, singularConn)
 	defer func() {
 		if err != nil && runner != nil {
 			runner.Kill()
@@ -1038,13 +1099,9 @@ func (a *MachineAgent) startEnvWorkers(
 	}()
 
 	// Create a singular runner for this environment.
-	machine, err := ssSt.Machine(a.machineId)
+	singularRunner, err := newSingularRunner(runner, singularConn)
 	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	singularRunner, err := newSingularStateRunner(runner, ssSt, machine)
-	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, errors.Annotate(err, "cannot make singular environment runner")
 	}
 	defer func() {
 		if err != nil && singularRunner != nil {
@@ -1062,9 +1119,24 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("cleaner", func() (worker.Worker, error) {
 		return cleaner.NewCleaner(st), nil
 	})
+	singularRunner.StartWorker("cleanup-timer", func() (worker.Worker, error) {
+		return cleaner.NewTimer(st), nil
+	})
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("agentrollout", func() (worker.Worker, error) {
+		return agentrollout.NewWorker(st), nil
+	})
+	singularRunner.StartWorker("dnsregistrar", func() (worker.Worker, error) {
+		return dnsregistrar.NewWorker(st), nil
+	})
+	singularRunner.StartWorker("notifications", func() (worker.Worker, error) {
+		return notifications.NewWorker(st), nil
+	})
+	singularRunner.StartWorker("instancedrift", func() (worker.Worker, error) {
+		return instancedrift.NewWorker(st), nil
+	})
 	singularRunner.StartWorker("addresserworker", func() (worker.Worker, error) {
 		return addresser.NewWorker(st)
 	})
@@ -1081,6 +1153,9 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("charm-revision-updater", func() (worker.Worker, error) {
 		return charmrevisionworker.NewRevisionUpdateWorker(apiSt.CharmRevisionUpdater()), nil
 	})
+	singularRunner.StartWorker("provisioningerrors", func() (worker.Worker, error) {
+		return provisioningerrors.NewWorker(apiSt.ProvisioningErrors()), nil
+	})
 	runner.StartWorker("metricmanagerworker", func() (worker.Worker, error) {
 		return metricworker.NewMetricsManager(getMetricAPI(apiSt))
 	})
@@ -1125,6 +1200,13 @@ func (a *MachineAgent) apiserverWorkerStarter(st *state.State, certChanged chan
 	return func() (worker.Worker, error) { return a.newApiserverWorker(st, certChanged) }
 }
 
+// agentReporter returns the reporter on the agent's top level workers,
+// or nil if its runner cannot report on them.
+func (a *MachineAgent) agentReporter() worker.Reporter {
+	reporter, _ := a.runner.(worker.Reporter)
+	return reporter
+}
+
 func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan params.StateServingInfo) (worker.Worker, error) {
 	agentConfig := a.CurrentConfig()
 	// If the configuration does not have the required information,
@@ -1150,14 +1232,21 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	if err != nil {
 		return nil, err
 	}
+	// The API server adds its own metrics to the registry, and
+	// serves them along with the ones registered here.
+	metrics := introspection.NewRegistry()
+	metrics.Register(mongo.NewStatsCollector(st.MongoSession()))
 	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
-		Cert:        cert,
-		Key:         key,
-		Tag:         tag,
-		DataDir:     dataDir,
-		LogDir:      logDir,
-		Validator:   a.limitLogins,
-		CertChanged: certChanged,
+		Cert:          cert,
+		Key:           key,
+		Tag:           tag,
+		DataDir:       dataDir,
+		LogDir:        logDir,
+		Validator:     a.limitLogins,
+		CertChanged:   certChanged,
+		Metrics:       metrics,
+		Hub:           a.hub,
+		AgentReporter: a.agentReporter(),
 	})
 }
 
@@ -1622,6 +1711,18 @@ func (c singularStateConn) Ping() error {
 	return c.session.Ping()
 }
 
+// newSingularLeaseConn returns a singular.Conn that is master while the
+// given machine holds the singular lease for the environment. The lease
+// is claimed through the database shared by all the state servers, so
+// it is held by at most one of them at a time.
+var newSingularLeaseConn = func(st *state.State, envUUID, machineId string) singular.Conn {
+	return singular.NewLeaseConn(
+		st,
+		"singular-"+envUUID,
+		names.NewMachineTag(machineId).String(),
+	)
+}
+
 func metricAPI(st *api.State) metricsmanager.MetricsManagerClient {
 	return metricsmanager.NewClient(st)
 }
@@ -47,9 +47,11 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/service/upstart"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -131,7 +133,7 @@ func (s *commonMachineSuite) SetUpTest(c *gc.C) {
 
 	s.singularRecord = newSingularRunnerRecord()
 	s.AgentSuite.PatchValue(&newSingularRunner, s.singularRecord.newSingularRunner)
-	s.AgentSuite.PatchValue(&peergrouperNew, func(st *state.State) (worker.Worker, error) {
+	s.AgentSuite.PatchValue(&peergrouperNew, func(st *state.State, hub *pubsub.Hub) (worker.Worker, error) {
 		return newDummyWorker(), nil
 	})
 
@@ -240,10 +242,16 @@ type MachineSuite struct {
 
 var perEnvSingularWorkers = []string{
 	"cleaner",
+	"cleanup-timer",
 	"minunitsworker",
+	"agentrollout",
+	"dnsregistrar",
+	"notifications",
+	"instancedrift",
 	"addresserworker",
 	"environ-provisioner",
 	"charm-revision-updater",
+	"provisioningerrors",
 	"firewaller",
 }
 
@@ -652,7 +660,7 @@ func (s *MachineSuite) TestManageEnvironRunsInstancePoller(c *gc.C) {
 
 func (s *MachineSuite) TestManageEnvironRunsPeergrouper(c *gc.C) {
 	started := make(chan struct{}, 1)
-	s.AgentSuite.PatchValue(&peergrouperNew, func(st *state.State) (worker.Worker, error) {
+	s.AgentSuite.PatchValue(&peergrouperNew, func(st *state.State, hub *pubsub.Hub) (worker.Worker, error) {
 		c.Check(st, gc.NotNil)
 		select {
 		case started <- struct{}{}:
@@ -708,6 +716,26 @@ func (s *MachineSuite) TestManageEnvironRunsStatusHistoryPruner(c *gc.C) {
 	runner.waitForWorker(c, "statushistorypruner")
 }
 
+func (s *MachineSuite) TestManageEnvironRunsOrphanCollector(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "orphancollector")
+}
+
+func (s *MachineSuite) TestManageEnvironRunsTxnPruner(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+
+	runner := s.singularRecord.nextRunner(c)
+	runner.waitForWorker(c, "txnpruner")
+}
+
 func (s *MachineSuite) TestManageEnvironCallsUseMultipleCPUs(c *gc.C) {
 	// If it has been enabled, the JobManageEnviron agent should call utils.UseMultipleCPUs
 	usefulVersion := version.Current
@@ -1359,8 +1387,8 @@ func (s *MachineSuite) TestMachineAgentRunsEnvironStorageWorker(c *gc.C) {
 
 func (s *MachineSuite) TestMachineAgentRunsCertificateUpdateWorkerForStateServer(c *gc.C) {
 	started := make(chan struct{})
-	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.EnvironConfigGetter,
-		certupdater.StateServingInfoSetter, chan params.StateServingInfo,
+	newUpdater := func(certupdater.AddressWatcher, certupdater.CertRotationWatcher, certupdater.StateServingInfoGetter,
+		certupdater.EnvironConfigGetter, certupdater.StateServingInfoSetter, chan params.StateServingInfo,
 	) worker.Worker {
 		close(started)
 		return worker.NewNoOpWorker()
@@ -1383,8 +1411,8 @@ func (s *MachineSuite) TestMachineAgentRunsCertificateUpdateWorkerForStateServer
 
 func (s *MachineSuite) TestMachineAgentDoesNotRunsCertificateUpdateWorkerForNonStateServer(c *gc.C) {
 	started := make(chan struct{})
-	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.EnvironConfigGetter,
-		certupdater.StateServingInfoSetter, chan params.StateServingInfo,
+	newUpdater := func(certupdater.AddressWatcher, certupdater.CertRotationWatcher, certupdater.StateServingInfoGetter,
+		certupdater.EnvironConfigGetter, certupdater.StateServingInfoSetter, chan params.StateServingInfo,
 	) worker.Worker {
 		close(started)
 		return worker.NewNoOpWorker()
@@ -1442,8 +1470,8 @@ func (s *MachineSuite) TestCertificateUpdateWorkerUpdatesCertificate(c *gc.C) {
 
 func (s *MachineSuite) TestCertificateDNSUpdated(c *gc.C) {
 	// Disable the certificate work so it doesn't update the certificate.
-	newUpdater := func(certupdater.AddressWatcher, certupdater.StateServingInfoGetter, certupdater.EnvironConfigGetter,
-		certupdater.StateServingInfoSetter, chan params.StateServingInfo,
+	newUpdater := func(certupdater.AddressWatcher, certupdater.CertRotationWatcher, certupdater.StateServingInfoGetter,
+		certupdater.EnvironConfigGetter, certupdater.StateServingInfoSetter, chan params.StateServingInfo,
 	) worker.Worker {
 		return worker.NewNoOpWorker()
 	}
@@ -1677,6 +1705,22 @@ func (s *MachineSuite) TestNewEnvironmentStartsNewWorkers(c *gc.C) {
 	c.Assert(workers, jc.DeepEquals, expectedWorkers)
 }
 
+func (s *MachineSuite) TestEnvironmentSingularWorkersHoldLease(c *gc.C) {
+	m, _, _ := s.primeAgent(c, version.Current, state.JobManageEnviron)
+	a := s.newAgent(c, m)
+	go func() { c.Check(a.Run(nil), jc.ErrorIsNil) }()
+	defer func() { c.Check(a.Stop(), jc.ErrorIsNil) }()
+
+	_ = s.singularRecord.nextRunner(c) // Don't care about this one for this test.
+	r := s.singularRecord.nextRunner(c)
+	r.waitForWorker(c, "firewaller")
+
+	namespace := "singular-" + s.State.EnvironUUID()
+	owner, err := s.State.ClaimLease(namespace, "machine-99", time.Minute)
+	c.Assert(err, gc.Equals, lease.LeaseClaimDeniedErr)
+	c.Assert(owner, gc.Equals, m.Tag().String())
+}
+
 func (s *MachineSuite) TestReplicasetInitiation(c *gc.C) {
 	if runtime.GOOS == "windows" {
 		c.Skip("state servers on windows aren't supported")
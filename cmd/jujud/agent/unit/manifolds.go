@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unit declares the workers run by a unit agent, and the
+// dependencies between them.
+package unit
+
+import (
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/apiaddressupdater"
+	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/machinelock"
+	"github.com/juju/juju/worker/passwordrotator"
+	"github.com/juju/juju/worker/proxyupdater"
+	"github.com/juju/juju/worker/rsyslog"
+	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/upgrader"
+)
+
+// ManifoldsConfig allows specialisation of the result of Manifolds.
+type ManifoldsConfig struct {
+	// Agent contains the agent that will be wrapped and made available to
+	// its dependencies via a dependency.Engine.
+	Agent agent.Agent
+}
+
+// Manifolds returns a set of co-configured manifolds covering the various
+// responsibilities of a standalone unit agent. Manifolds must refer to one
+// another only via the names declared below, never via string literals.
+func Manifolds(config ManifoldsConfig) dependency.Manifolds {
+	return dependency.Manifolds{
+
+		// The agent manifold references the enclosing agent, and is the
+		// foundation stone on which most other manifolds ultimately depend.
+		// (Currently, that is "all manifolds", but consider a shared clock.)
+		AgentName: agent.Manifold(config.Agent),
+
+		// The machine lock manifold is a single "worker" that simply creates
+		// a lock used to serialise hook executions (and other operations)
+		// across every agent on the machine.
+		MachineLockName: machinelock.Manifold(machinelock.ManifoldConfig{
+			AgentName: AgentName,
+		}),
+
+		// The api caller is a thin concurrent wrapper around a connection
+		// to some API server. It's used by many other manifolds, which all
+		// select their own desired facades; if the connection breaks, only
+		// the workers that use it are restarted.
+		APICallerName: apicaller.Manifold(apicaller.ManifoldConfig{
+			AgentName: AgentName,
+		}),
+
+		// The upgrader is a leaf worker that returns a specific error type
+		// recognised by the unit agent, causing other workers to be stopped
+		// and the agent to be restarted running the new tools. It records
+		// the running tools version before it starts watching for changes.
+		UpgraderName: upgrader.Manifold(upgrader.ManifoldConfig{
+			AgentName:     AgentName,
+			ApiCallerName: APICallerName,
+		}),
+
+		// The logging config updater is a leaf worker that indirectly
+		// controls the messages sent via the log sender or rsyslog,
+		// according to changes in environment config. We should only need
+		// one of these in a consolidated agent.
+		LoggingConfigUpdaterName: logger.Manifold(logger.ManifoldConfig{
+			AgentName:     AgentName,
+			ApiCallerName: APICallerName,
+		}),
+
+		// The proxy config updater is a leaf worker that sets http/https/apt/etc
+		// proxy settings.
+		ProxyConfigUpdaterName: proxyupdater.Manifold(proxyupdater.ManifoldConfig{
+			ApiCallerName: APICallerName,
+		}),
+
+		// The api address updater is a leaf worker that rewrites agent config
+		// as the controller addresses change. We should only need one of
+		// these in a consolidated agent.
+		APIAddressUpdaterName: apiaddressupdater.Manifold(apiaddressupdater.ManifoldConfig{
+			AgentName:     AgentName,
+			ApiCallerName: APICallerName,
+		}),
+
+		// The rsyslog config updater is a leaf worker that causes rsyslog
+		// to send messages to the state servers. We should only need one of
+		// these in a consolidated agent.
+		RsyslogConfigUpdaterName: rsyslog.Manifold(rsyslog.ManifoldConfig{
+			AgentName:     AgentName,
+			ApiCallerName: APICallerName,
+		}),
+
+		// The password rotator is a leaf worker that periodically replaces
+		// the password the agent uses to connect to the API.
+		PasswordRotatorName: passwordrotator.Manifold(passwordrotator.ManifoldConfig{
+			AgentName:     AgentName,
+			ApiCallerName: APICallerName,
+		}),
+
+		// The uniter installs charms; manages the unit's presence in its
+		// relations; creates subordinate units; runs all the hooks; sends
+		// metrics; and so on. It holds the machine lock while it runs hooks.
+		UniterName: uniter.Manifold(uniter.ManifoldConfig{
+			AgentName:       AgentName,
+			ApiCallerName:   APICallerName,
+			MachineLockName: MachineLockName,
+		}),
+	}
+}
+
+// The names of the manifolds returned by Manifolds.
+const (
+	AgentName                = "agent"
+	APIAddressUpdaterName    = "api-address-updater"
+	APICallerName            = "api-caller"
+	LoggingConfigUpdaterName = "logging-config-updater"
+	MachineLockName          = "machine-lock"
+	PasswordRotatorName      = "password-rotator"
+	ProxyConfigUpdaterName   = "proxy-config-updater"
+	RsyslogConfigUpdaterName = "rsyslog-config-updater"
+	UniterName               = "uniter"
+	UpgraderName             = "upgrader"
+)
@@ -0,0 +1,71 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unit_test
+
+import (
+	"sort"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/jujud/agent/unit"
+	"github.com/juju/juju/worker/agent"
+)
+
+type ManifoldsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ManifoldsSuite{})
+
+func (s *ManifoldsSuite) TestManifoldNames(c *gc.C) {
+	manifolds := unit.Manifolds(unit.ManifoldsConfig{
+		Agent: fakeAgent{},
+	})
+	keys := make([]string, 0, len(manifolds))
+	for k := range manifolds {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	c.Check(keys, jc.DeepEquals, []string{
+		unit.AgentName,
+		unit.APIAddressUpdaterName,
+		unit.APICallerName,
+		unit.LoggingConfigUpdaterName,
+		unit.MachineLockName,
+		unit.PasswordRotatorName,
+		unit.ProxyConfigUpdaterName,
+		unit.RsyslogConfigUpdaterName,
+		unit.UniterName,
+		unit.UpgraderName,
+	})
+}
+
+func (s *ManifoldsSuite) TestInputsDeclared(c *gc.C) {
+	manifolds := unit.Manifolds(unit.ManifoldsConfig{
+		Agent: fakeAgent{},
+	})
+	for name, manifold := range manifolds {
+		for _, input := range manifold.Inputs {
+			_, found := manifolds[input]
+			c.Check(found, jc.IsTrue, gc.Commentf("%q depends on unknown %q", name, input))
+		}
+	}
+}
+
+func (s *ManifoldsSuite) TestUniterInputs(c *gc.C) {
+	manifolds := unit.Manifolds(unit.ManifoldsConfig{
+		Agent: fakeAgent{},
+	})
+	c.Check(manifolds[unit.UniterName].Inputs, jc.DeepEquals, []string{
+		unit.AgentName,
+		unit.APICallerName,
+		unit.MachineLockName,
+	})
+}
+
+type fakeAgent struct {
+	agent.Agent
+}
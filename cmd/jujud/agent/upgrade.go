@@ -227,6 +227,7 @@ func (c *upgradeWorkerContext) runUpgrades() error {
 	}
 
 	if err := c.agent.ChangeConfig(c.runUpgradeSteps); err != nil {
+		c.abortAfterMasterFailure(upgradeInfo, err)
 		return err
 	}
 
@@ -283,7 +284,11 @@ func (c *upgradeWorkerContext) waitForOtherStateServers(info *state.UpgradeInfo)
 	for {
 		select {
 		case <-watcher.Changes():
-			if err := info.Refresh(); err != nil {
+			if err := info.Refresh(); errors.IsNotFound(err) {
+				// The master, or the user, has abandoned the
+				// upgrade.
+				return errors.New("upgrade was aborted")
+			} else if err != nil {
 				return errors.Trace(err)
 			}
 			if c.isMaster {
@@ -314,6 +319,23 @@ func (c *upgradeWorkerContext) waitForOtherStateServers(info *state.UpgradeInfo)
 	}
 }
 
+// abortAfterMasterFailure abandons the upgrade if the master state
+// server's upgrade steps failed, so that the other state servers stop
+// waiting for it to finish. The master is left reporting the error
+// until the problem is resolved; it is not rolled back, because its
+// steps may have partially changed the database. A lost API
+// connection is not treated as a failure: the agent restarts and the
+// new master carries on with the upgrade.
+func (c *upgradeWorkerContext) abortAfterMasterFailure(info *state.UpgradeInfo, err error) {
+	if !c.isMaster || info == nil || isAPILostDuringUpgrade(err) {
+		return
+	}
+	logger.Errorf("aborting upgrade to %v on all state servers: %v", c.toVersion, err)
+	if abortErr := info.Abort(); abortErr != nil {
+		logger.Errorf("unable to abort upgrade: %v", abortErr)
+	}
+}
+
 // runUpgradeSteps runs the required upgrade steps for the machine
 // agent, retrying on failure. The agent's UpgradedToVersion is set
 // once the upgrade is complete.
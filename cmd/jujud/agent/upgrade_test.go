@@ -343,6 +343,71 @@ func (s *UpgradeSuite) TestAbortWhenOtherStateServerDoesntStartUpgrade(c *gc.C)
 	}})
 }
 
+func (s *UpgradeSuite) TestSecondaryStopsWaitingWhenUpgradeAborted(c *gc.C) {
+	// This test checks that a secondary state server waiting for the
+	// master gives up as soon as the upgrade is aborted, rather than
+	// waiting until it times out.
+	s.machineIsMaster = false
+	s.PatchValue(&upgradeStartTimeoutSecondary, coretesting.LongWait)
+
+	_, machineIdB, _ := s.createUpgradingStateServers(c)
+	info, err := s.State.EnsureUpgradeInfo(machineIdB, s.oldVersion.Number, version.Current.Number)
+	c.Assert(err, jc.ErrorIsNil)
+	attemptsP := s.countUpgradeAttempts(nil)
+
+	// Abort the upgrade once this state server has signalled that it
+	// is ready.
+	go func() {
+		for a := coretesting.LongAttempt.Start(); a.Next(); {
+			if err := info.Refresh(); err != nil {
+				return
+			}
+			if len(info.StateServersReady()) == 2 {
+				info.Abort()
+				return
+			}
+		}
+	}()
+
+	workerErr, config, agent, context := s.runUpgradeWorker(c, multiwatcher.JobManageEnviron)
+
+	c.Check(workerErr, gc.IsNil)
+	c.Check(*attemptsP, gc.Equals, 0)
+	c.Check(config.Version, gc.Equals, s.oldVersion.Number) // Upgrade didn't happen
+	assertUpgradeNotComplete(c, context)
+	c.Assert(agent.MachineStatusCalls, jc.DeepEquals, []MachineStatusCall{{
+		params.StatusError,
+		fmt.Sprintf(
+			"upgrade to %s failed (giving up): aborted wait for other state servers: upgrade was aborted",
+			version.Current.Number),
+	}})
+}
+
+func (s *UpgradeSuite) TestMasterStepsFailureAbortsUpgrade(c *gc.C) {
+	// This test checks that when the master's upgrade steps fail the
+	// upgrade is aborted, so that the other state servers stop
+	// waiting for the master to finish.
+	s.machineIsMaster = true
+	_, machineIdB, machineIdC := s.createUpgradingStateServers(c)
+	info, err := s.State.EnsureUpgradeInfo(machineIdB, s.oldVersion.Number, version.Current.Number)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnsureUpgradeInfo(machineIdC, s.oldVersion.Number, version.Current.Number)
+	c.Assert(err, jc.ErrorIsNil)
+	attemptsP := s.countUpgradeAttempts(errors.New("boom"))
+
+	workerErr, config, _, context := s.runUpgradeWorker(c, multiwatcher.JobManageEnviron)
+
+	c.Check(workerErr, gc.IsNil)
+	c.Check(*attemptsP, gc.Equals, maxUpgradeRetries)
+	c.Check(config.Version, gc.Equals, s.oldVersion.Number) // Upgrade didn't finish
+	assertUpgradeNotComplete(c, context)
+
+	err = info.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	// The master is not rolled back.
+	s.assertEnvironAgentVersion(c, version.Current.Number)
+}
+
 func (s *UpgradeSuite) TestWorkerAbortsIfAgentDies(c *gc.C) {
 	s.machineIsMaster = false
 	s.captureLogs(c)
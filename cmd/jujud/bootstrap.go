@@ -245,6 +245,9 @@ func (c *BootstrapCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 
+	// Record the subnets in which instances may be started.
+	c.populateSubnets(st, env)
+
 	// bootstrap machine always gets the vote
 	return m.SetHasVote(true)
 }
@@ -303,6 +306,37 @@ func (c *BootstrapCommand) populateDefaultStoragePools(st *state.State) error {
 	return poolmanager.AddDefaultStoragePools(settings)
 }
 
+// populateSubnets records in state the subnets in which the
+// environment's instances may be started, if the provider can list
+// them. Failures are only logged, as the subnets are not needed to
+// complete the bootstrap.
+func (c *BootstrapCommand) populateSubnets(st *state.State, env environs.Environ) {
+	discoverer, ok := environs.SupportsSubnetDiscovery(env)
+	if !ok {
+		return
+	}
+	subnets, err := discoverer.AllSubnets()
+	if err != nil {
+		logger.Warningf("cannot discover subnets: %v", err)
+		return
+	}
+	for _, subnet := range subnets {
+		info := state.SubnetInfo{
+			ProviderId:       string(subnet.ProviderId),
+			CIDR:             subnet.CIDR,
+			VLANTag:          subnet.VLANTag,
+			AvailabilityZone: subnet.AvailabilityZone,
+		}
+		if subnet.AllocatableIPLow != nil && subnet.AllocatableIPHigh != nil {
+			info.AllocatableIPLow = subnet.AllocatableIPLow.String()
+			info.AllocatableIPHigh = subnet.AllocatableIPHigh.String()
+		}
+		if _, err := st.AddSubnet(info); err != nil && !errors.IsAlreadyExists(err) {
+			logger.Warningf("cannot add subnet %q: %v", subnet.CIDR, err)
+		}
+	}
+}
+
 // populateTools stores uploaded tools in provider storage
 // and updates the tools metadata.
 func (c *BootstrapCommand) populateTools(st *state.State, env environs.Environ) error {
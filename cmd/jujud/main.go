@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/juju/sockets"
 	// Import the providers.
 	_ "github.com/juju/juju/provider/all"
+	workerlogger "github.com/juju/juju/worker/logger"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
 
@@ -183,7 +184,9 @@ type jujudWriter struct {
 var _ loggo.Writer = (*jujudWriter)(nil)
 
 func (w *jujudWriter) Write(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) {
-	if strings.HasPrefix(module, "unit.") {
+	if workerlogger.JSONFormat() {
+		fmt.Fprintln(w.target, workerlogger.FormatJSON(level, module, filename, line, timestamp, message))
+	} else if strings.HasPrefix(module, "unit.") {
 		fmt.Fprintln(w.target, w.unitFormatter.Format(level, module, timestamp, message))
 	} else {
 		fmt.Fprintln(w.target, w.defaultFormatter.Format(level, module, filename, line, timestamp, message))
@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"runtime"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -17,23 +18,35 @@ import (
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/agent"
-	"github.com/juju/juju/api/leadership"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
+	"github.com/juju/juju/cmd/jujud/agent/unit"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/network"
-	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
-	"github.com/juju/juju/worker/apiaddressupdater"
-	workerlogger "github.com/juju/juju/worker/logger"
-	"github.com/juju/juju/worker/proxyupdater"
-	"github.com/juju/juju/worker/rsyslog"
-	"github.com/juju/juju/worker/uniter"
-	"github.com/juju/juju/worker/upgrader"
+	"github.com/juju/juju/worker/dependency"
+	introspectionworker "github.com/juju/juju/worker/introspection"
 )
 
 var agentLogger = loggo.GetLogger("juju.jujud")
 
+// unitManifolds exists to be patched out in tests.
+var unitManifolds = unit.Manifolds
+
+const (
+	// engineErrorDelay is how long the unit agent's dependency engine
+	// waits before restarting a worker that failed.
+	engineErrorDelay = 3 * time.Second
+
+	// engineBounceDelay is how long the unit agent's dependency engine
+	// waits before restarting a worker whose dependencies changed.
+	engineBounceDelay = 10 * time.Millisecond
+
+	// introspectionName names the worker that serves reports on the
+	// unit agent's workers.
+	introspectionName = "introspection"
+)
+
 // UnitAgent is a cmd.Command responsible for running a unit agent.
 type UnitAgent struct {
 	cmd.CommandBase
@@ -118,88 +131,40 @@ func (a *UnitAgent) Run(ctx *cmd.Context) error {
 	}
 
 	network.InitializeFromConfig(agentConfig)
-	a.runner.StartWorker("api", a.APIWorkers)
+	a.runner.StartWorker("engine", a.newEngine)
 	err := cmdutil.AgentDone(logger, a.runner.Wait())
 	a.tomb.Kill(err)
 	return err
 }
 
-func (a *UnitAgent) APIWorkers() (worker.Worker, error) {
-	agentConfig := a.CurrentConfig()
-	dataDir := agentConfig.DataDir()
-	hookLock, err := cmdutil.HookExecutionLock(dataDir)
-	if err != nil {
-		return nil, err
-	}
-	st, entity, err := agentcmd.OpenAPIState(agentConfig, a)
-	if err != nil {
-		return nil, err
-	}
-	unitTag, err := names.ParseUnitTag(entity.Tag())
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	// Ensure that the environment uuid is stored in the agent config.
-	// Luckily the API has it recorded for us after we connect.
-	if agentConfig.Environment().Id() == "" {
-		err := a.ChangeConfig(func(setter agent.ConfigSetter) error {
-			environTag, err := st.EnvironTag()
-			if err != nil {
-				return errors.Annotate(err, "no environment uuid set on api")
-			}
-
-			return setter.Migrate(agent.MigrateParams{
-				Environment: environTag,
-			})
-		})
-		if err != nil {
-			logger.Warningf("unable to save environment uuid: %v", err)
-			// Not really fatal, just annoying.
-		}
-	}
-
-	// Before starting any workers, ensure we record the Juju version this unit
-	// agent is running.
-	currentTools := &tools.Tools{Version: version.Current}
-	if err := st.Upgrader().SetVersion(agentConfig.Tag().String(), currentTools.Version); err != nil {
-		return nil, errors.Annotate(err, "cannot set unit agent version")
-	}
-
-	runner := worker.NewRunner(cmdutil.ConnectionIsFatal(logger, st), cmdutil.MoreImportant)
-	// start proxyupdater first to ensure proxy settings are correct
-	runner.StartWorker("proxyupdater", func() (worker.Worker, error) {
-		return proxyupdater.New(st.Environment(), false), nil
+// newEngine returns a dependency engine running the unit agent's
+// workers, as declared by unitManifolds. A worker that fails is
+// restarted on its own after engineErrorDelay; only errors that are
+// fatal to the agent stop the engine, and with it the agent. On linux
+// the engine also runs a worker that reports on the engine's workers
+// over the agent's introspection socket.
+func (a *UnitAgent) newEngine() (worker.Worker, error) {
+	engine := dependency.NewEngine(cmdutil.IsFatal, engineErrorDelay, engineBounceDelay)
+	manifolds := unitManifolds(unit.ManifoldsConfig{
+		Agent: a,
 	})
-	runner.StartWorker("upgrader", func() (worker.Worker, error) {
-		return upgrader.NewUpgrader(
-			st.Upgrader(),
-			agentConfig,
-			agentConfig.UpgradedToVersion(),
-			func() bool { return false },
-		), nil
-	})
-	runner.StartWorker("logger", func() (worker.Worker, error) {
-		return workerlogger.NewLogger(st.Logger(), agentConfig), nil
-	})
-	runner.StartWorker("uniter", func() (worker.Worker, error) {
-		uniterFacade, err := st.Uniter()
-		if err != nil {
-			return nil, errors.Trace(err)
+	if runtime.GOOS == "linux" {
+		manifolds[introspectionName] = dependency.Manifold{
+			Start: func(dependency.GetResourceFunc) (worker.Worker, error) {
+				return introspectionworker.NewSocketWorker(introspectionworker.SocketConfig{
+					SocketName: introspectionworker.SocketName(a.Tag().String()),
+					Reporter:   engine,
+				})
+			},
 		}
-		return uniter.NewUniter(uniterFacade, unitTag, leadership.NewClient(st), dataDir, hookLock), nil
-	})
-
-	runner.StartWorker("apiaddressupdater", func() (worker.Worker, error) {
-		uniterFacade, err := st.Uniter()
-		if err != nil {
-			return nil, errors.Trace(err)
+	}
+	if err := dependency.Install(engine, manifolds); err != nil {
+		if err := worker.Stop(engine); err != nil {
+			logger.Errorf("while stopping engine with bad manifolds: %v", err)
 		}
-		return apiaddressupdater.NewAPIAddressUpdater(uniterFacade, a), nil
-	})
-	runner.StartWorker("rsyslog", func() (worker.Worker, error) {
-		return cmdutil.NewRsyslogConfigWorker(st.Rsyslog(), agentConfig, rsyslog.RsyslogModeForwarding)
-	})
-	return cmdutil.NewCloseWorker(logger, runner, st), nil
+		return nil, errors.Trace(err)
+	}
+	return engine, nil
 }
 
 func (a *UnitAgent) Tag() names.Tag {
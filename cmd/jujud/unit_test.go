@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -23,7 +24,7 @@ import (
 	apirsyslog "github.com/juju/juju/api/rsyslog"
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	agenttesting "github.com/juju/juju/cmd/jujud/agent/testing"
-	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/cmd/jujud/agent/unit"
 	envtesting "github.com/juju/juju/environs/testing"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/lease"
@@ -33,6 +34,7 @@ import (
 	"github.com/juju/juju/tools"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/upgrader"
 )
@@ -334,7 +336,7 @@ func (s *UnitSuite) TestOpenStateFails(c *gc.C) {
 
 func (s *UnitSuite) TestRsyslogConfigWorker(c *gc.C) {
 	created := make(chan rsyslog.RsyslogMode, 1)
-	s.PatchValue(&cmdutil.NewRsyslogConfigWorker, func(_ *apirsyslog.State, _ agent.Config, mode rsyslog.RsyslogMode) (worker.Worker, error) {
+	s.PatchValue(&rsyslog.NewRsyslogConfigWorker, func(_ *apirsyslog.State, mode rsyslog.RsyslogMode, _ names.Tag, _ string, _ []string) (worker.Worker, error) {
 		created <- mode
 		return newDummyWorker(), nil
 	})
@@ -352,6 +354,35 @@ func (s *UnitSuite) TestRsyslogConfigWorker(c *gc.C) {
 	}
 }
 
+func (s *UnitSuite) TestWorkerFailureDoesNotStopAgent(c *gc.C) {
+	failed := make(chan struct{}, 1)
+	s.PatchValue(&unitManifolds, func(config unit.ManifoldsConfig) dependency.Manifolds {
+		manifolds := unit.Manifolds(config)
+		manifolds["failing"] = dependency.Manifold{
+			Start: func(dependency.GetResourceFunc) (worker.Worker, error) {
+				select {
+				case failed <- struct{}{}:
+				default:
+				}
+				return nil, errors.New("boom")
+			},
+		}
+		return manifolds
+	})
+
+	_, u, _, _ := s.primeAgent(c)
+	a := s.newAgent(c, u)
+	go func() { c.Check(a.Run(nil), gc.IsNil) }()
+	defer func() { c.Check(a.Stop(), gc.IsNil) }()
+
+	select {
+	case <-failed:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timeout while waiting for failing worker to start")
+	}
+	waitForUnitActive(s.State, u, c)
+}
+
 func (s *UnitSuite) TestAgentSetsToolsVersion(c *gc.C) {
 	_, unit, _, _ := s.primeAgent(c)
 	vers := version.Current
@@ -416,6 +416,13 @@ func (s *ConstraintsSuite) TestIsEmpty(c *gc.C) {
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 }
 
+func (s *ConstraintsSuite) TestAttributes(c *gc.C) {
+	con := constraints.Value{}
+	c.Check(con.Attributes(), gc.HasLen, 0)
+	con = constraints.MustParse("mem=4G arch=amd64 tags=")
+	c.Check(con.Attributes(), jc.DeepEquals, []string{"arch", "mem", "tags"})
+}
+
 func uint64p(i uint64) *uint64 {
 	return &i
 }
@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package resolver combines the constraints specified at each level
+// of an environment into the effective constraints used to choose or
+// provision a machine.
+//
+// Constraints are resolved in order of increasing precedence:
+// environment defaults, then service constraints, then any constraints
+// supplied along with a placement directive (for example by
+// add-machine). An attribute set at a more specific level overrides the
+// same attribute, and any attributes that conflict with it, at the less
+// specific levels.
+package resolver
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/constraints"
+)
+
+// Source identifies the level at which an effective constraint
+// attribute was specified.
+type Source string
+
+const (
+	// Environment identifies attributes taken from the environment
+	// constraints.
+	Environment Source = "environment"
+
+	// Service identifies attributes taken from the service
+	// constraints.
+	Service Source = "service"
+
+	// Placement identifies attributes taken from the constraints
+	// supplied with a placement directive.
+	Placement Source = "placement"
+)
+
+// Layers holds the constraints specified at each level.
+type Layers struct {
+	Environment constraints.Value
+	Service     constraints.Value
+	Placement   constraints.Value
+}
+
+// Effective holds the result of resolving constraints.
+type Effective struct {
+	// Value holds the effective constraints.
+	Value constraints.Value
+
+	// Sources records, for every attribute set in Value, the level
+	// from which it was taken.
+	Sources map[string]Source
+}
+
+// Resolve merges the given layers of constraints, with more specific
+// layers taking precedence, using the validator to decide which
+// attributes conflict.
+func Resolve(validator constraints.Validator, layers Layers) (Effective, error) {
+	ordered := []struct {
+		source Source
+		cons   constraints.Value
+	}{
+		{Environment, layers.Environment},
+		{Service, layers.Service},
+		{Placement, layers.Placement},
+	}
+	result := Effective{Sources: make(map[string]Source)}
+	for _, layer := range ordered {
+		merged, err := validator.Merge(result.Value, layer.cons)
+		if err != nil {
+			return Effective{}, errors.Annotatef(err, "invalid %s constraints", layer.source)
+		}
+		result.Value = merged
+		for _, attr := range layer.cons.Attributes() {
+			result.Sources[attr] = layer.source
+		}
+	}
+	// Attributes overridden by conflicting ones at a more specific
+	// level no longer have a source.
+	present := set.NewStrings(result.Value.Attributes()...)
+	for attr := range result.Sources {
+		if !present.Contains(attr) {
+			delete(result.Sources, attr)
+		}
+	}
+	return result, nil
+}
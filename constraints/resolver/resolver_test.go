@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resolver_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
+)
+
+type resolverSuite struct{}
+
+var _ = gc.Suite(&resolverSuite{})
+
+var resolveTests = []struct {
+	about       string
+	environment string
+	service     string
+	placement   string
+	expected    string
+	sources     map[string]resolver.Source
+}{{
+	about: "nothing set",
+}, {
+	about:       "environment only",
+	environment: "mem=4G arch=amd64",
+	expected:    "arch=amd64 mem=4G",
+	sources: map[string]resolver.Source{
+		"arch": resolver.Environment,
+		"mem":  resolver.Environment,
+	},
+}, {
+	about:       "service overrides environment",
+	environment: "mem=4G arch=amd64",
+	service:     "mem=8G",
+	expected:    "arch=amd64 mem=8G",
+	sources: map[string]resolver.Source{
+		"arch": resolver.Environment,
+		"mem":  resolver.Service,
+	},
+}, {
+	about:       "placement overrides service and environment",
+	environment: "mem=4G arch=amd64",
+	service:     "mem=8G cpu-cores=2",
+	placement:   "mem=16G arch=i386",
+	expected:    "arch=i386 cpu-cores=2 mem=16G",
+	sources: map[string]resolver.Source{
+		"arch":      resolver.Placement,
+		"cpu-cores": resolver.Service,
+		"mem":       resolver.Placement,
+	},
+}, {
+	about:       "empty values still override",
+	environment: "tags=foo",
+	service:     "tags=",
+	expected:    "tags=",
+	sources: map[string]resolver.Source{
+		"tags": resolver.Service,
+	},
+}, {
+	about:       "conflicting attributes are dropped",
+	environment: "mem=4G cpu-cores=2",
+	service:     "instance-type=m1.small",
+	expected:    "cpu-cores=2 instance-type=m1.small",
+	sources: map[string]resolver.Source{
+		"cpu-cores":     resolver.Environment,
+		"instance-type": resolver.Service,
+	},
+}}
+
+func (s *resolverSuite) TestResolve(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
+	for i, test := range resolveTests {
+		c.Logf("test %d: %s", i, test.about)
+		effective, err := resolver.Resolve(validator, resolver.Layers{
+			Environment: constraints.MustParse(test.environment),
+			Service:     constraints.MustParse(test.service),
+			Placement:   constraints.MustParse(test.placement),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(effective.Value, jc.DeepEquals, constraints.MustParse(test.expected))
+		if test.sources == nil {
+			c.Check(effective.Sources, gc.HasLen, 0)
+		} else {
+			c.Check(effective.Sources, jc.DeepEquals, test.sources)
+		}
+	}
+}
+
+func (s *resolverSuite) TestResolveInvalid(c *gc.C) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts([]string{constraints.InstanceType}, []string{constraints.Mem})
+	_, err := resolver.Resolve(validator, resolver.Layers{
+		Service: constraints.MustParse("mem=4G instance-type=m1.small"),
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid service constraints: ambiguous constraints: .*`)
+}
@@ -5,6 +5,7 @@ package container
 
 import (
 	"fmt"
+	"net/url"
 	"os/exec"
 	"path"
 	"strings"
@@ -44,7 +45,7 @@ func NewImageURLGetter(serverRoot, envuuid string, caCert []byte) ImageURLGetter
 
 // ImageURL is specified on the NewImageURLGetter interface.
 func (ug *imageURLGetter) ImageURL(kind instance.ContainerType, series, arch string) (string, error) {
-	imageURL, err := ImageDownloadURL(kind, series, arch)
+	imageURL, err := ImageDownloadURL(kind, series, arch, "", "")
 	if err != nil {
 		return "", errors.Annotatef(err, "cannot determine LXC image URL: %v", err)
 	}
@@ -61,22 +62,61 @@ func (ug *imageURLGetter) CACert() []byte {
 	return ug.caCert
 }
 
+type imageDownloadURLGetter struct {
+	stream    string
+	sourceURL string
+}
+
+// NewImageDownloadURLGetter returns an ImageURLGetter which fetches
+// images directly from the given stream and image source, rather than
+// through the state servers' image cache. See ImageDownloadURL.
+func NewImageDownloadURLGetter(stream, sourceURL string) ImageURLGetter {
+	return &imageDownloadURLGetter{
+		stream:    stream,
+		sourceURL: sourceURL,
+	}
+}
+
+// ImageURL is specified on the NewImageURLGetter interface.
+func (ug *imageDownloadURLGetter) ImageURL(kind instance.ContainerType, series, arch string) (string, error) {
+	return ImageDownloadURL(kind, series, arch, ug.stream, ug.sourceURL)
+}
+
+// CACert is specified on the NewImageURLGetter interface.
+func (ug *imageDownloadURLGetter) CACert() []byte {
+	return nil
+}
+
 // ImageDownloadURL determines the public URL which can be used to obtain an
-// image blob with the specified parameters.
-func ImageDownloadURL(kind instance.ContainerType, series, arch string) (string, error) {
+// image blob with the specified parameters. The image is taken from the
+// given simplestreams stream ("released" if empty) and, if sourceURL is
+// not empty, from the mirror of http://cloud-images.ubuntu.com at that URL.
+func ImageDownloadURL(kind instance.ContainerType, series, arch, stream, sourceURL string) (string, error) {
 	// TODO - we currently only need to support LXC images - kind is ignored.
 	if kind != instance.LXC {
 		return "", errors.Errorf("unsupported container type: %v", kind)
 	}
+	if stream == "" {
+		stream = "released"
+	}
 
 	// Use the ubuntu-cloudimg-query command to get the url from which to fetch the image.
 	// This will be somewhere on http://cloud-images.ubuntu.com.
-	cmd := exec.Command("ubuntu-cloudimg-query", series, "released", arch, "--format", "%{url}")
+	cmd := exec.Command("ubuntu-cloudimg-query", series, stream, arch, "--format", "%{url}")
 	urlBytes, err := cmd.CombinedOutput()
 	if err != nil {
 		stderr := string(urlBytes)
 		return "", errors.Annotatef(err, "cannot determine LXC image URL: %v", stderr)
 	}
-	imageURL := strings.Replace(string(urlBytes), ".tar.gz", "-root.tar.gz", -1)
+	imageURL := strings.Replace(strings.TrimSpace(string(urlBytes)), ".tar.gz", "-root.tar.gz", -1)
+	if sourceURL != "" {
+		// Mirrors share the layout of cloud-images.ubuntu.com, so
+		// only the location of the image changes.
+		u, err := url.Parse(imageURL)
+		if err != nil {
+			return "", errors.Annotatef(err, "cannot parse LXC image URL %q", imageURL)
+		}
+		imageURL = strings.TrimSuffix(sourceURL, "/") + u.Path
+	}
 	return imageURL, nil
 }
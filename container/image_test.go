@@ -34,12 +34,32 @@ func (s *imageURLSuite) TestImageURL(c *gc.C) {
 }
 
 func (s *imageURLSuite) TestImageDownloadURL(c *gc.C) {
-	imageDownloadURL, err := container.ImageDownloadURL(instance.LXC, "trusty", "amd64")
+	imageDownloadURL, err := container.ImageDownloadURL(instance.LXC, "trusty", "amd64", "", "")
 	c.Assert(err, gc.IsNil)
 	c.Assert(imageDownloadURL, gc.Equals, "test://cloud-images/trusty-released-amd64-root.tar.gz")
 }
 
+func (s *imageURLSuite) TestImageDownloadURLStream(c *gc.C) {
+	imageDownloadURL, err := container.ImageDownloadURL(instance.LXC, "trusty", "amd64", "daily", "")
+	c.Assert(err, gc.IsNil)
+	c.Assert(imageDownloadURL, gc.Equals, "test://cloud-images/trusty-daily-amd64-root.tar.gz")
+}
+
+func (s *imageURLSuite) TestImageDownloadURLSource(c *gc.C) {
+	imageDownloadURL, err := container.ImageDownloadURL(instance.LXC, "trusty", "amd64", "", "http://mirror.example.com/ubuntu/")
+	c.Assert(err, gc.IsNil)
+	c.Assert(imageDownloadURL, gc.Equals, "http://mirror.example.com/ubuntu/trusty-released-amd64-root.tar.gz")
+}
+
+func (s *imageURLSuite) TestImageDownloadURLGetter(c *gc.C) {
+	imageURLGetter := container.NewImageDownloadURLGetter("daily", "http://mirror.example.com")
+	imageURL, err := imageURLGetter.ImageURL(instance.LXC, "trusty", "amd64")
+	c.Assert(err, gc.IsNil)
+	c.Assert(imageURL, gc.Equals, "http://mirror.example.com/trusty-daily-amd64-root.tar.gz")
+	c.Assert(imageURLGetter.CACert(), gc.IsNil)
+}
+
 func (s *imageURLSuite) TestImageDownloadURLUnsupportedContainer(c *gc.C) {
-	_, err := container.ImageDownloadURL(instance.KVM, "trusty", "amd64")
+	_, err := container.ImageDownloadURL(instance.KVM, "trusty", "amd64", "", "")
 	c.Assert(err, gc.ErrorMatches, "unsupported container .*")
 }
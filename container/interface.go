@@ -18,6 +18,17 @@ const (
 	// supports networking.
 	ConfigIPForwarding = "ip-forwarding"
 
+	// ConfigImageStream and ConfigImageSourceURL, if set, hold the
+	// simplestreams stream and the base URL of the cloud images
+	// mirror from which the container manager fetches images.
+	ConfigImageStream    = "image-stream"
+	ConfigImageSourceURL = "image-source-url"
+
+	// ConfigImageProxy, if set to "false", instructs the container
+	// manager to fetch images directly from the image source rather
+	// than through the image cache hosted by the state servers.
+	ConfigImageProxy = "image-proxy"
+
 	DefaultNamespace = "juju"
 )
 
@@ -84,16 +84,25 @@ func NewContainerManager(conf container.ManagerConfig) (container.Manager, error
 	if logDir == "" {
 		logDir = agent.DefaultLogDir
 	}
+	imageStream := conf.PopValue(container.ConfigImageStream)
+	imageSourceURL := conf.PopValue(container.ConfigImageSourceURL)
 	conf.WarnAboutUnused()
-	return &containerManager{name: name, logdir: logDir}, nil
+	return &containerManager{
+		name:           name,
+		logdir:         logDir,
+		imageStream:    imageStream,
+		imageSourceURL: imageSourceURL,
+	}, nil
 }
 
 // containerManager handles all of the business logic at the juju specific
 // level. It makes sure that the necessary directories are in place, that the
 // user-data is written out in the right place.
 type containerManager struct {
-	name   string
-	logdir string
+	name           string
+	logdir         string
+	imageStream    string
+	imageSourceURL string
 }
 
 var _ container.Manager = (*containerManager)(nil)
@@ -141,11 +150,7 @@ func (manager *containerManager) CreateContainer(
 	startParams.Network = networkConfig
 	startParams.UserDataFile = userDataFilename
 
-	// If the Simplestream requested is anything but released, update
-	// our StartParams to request it.
-	if instanceConfig.ImageStream != imagemetadata.ReleasedStream {
-		startParams.ImageDownloadUrl = imagemetadata.UbuntuCloudImagesURL + "/" + instanceConfig.ImageStream
-	}
+	startParams.ImageDownloadUrl = manager.imageDownloadURL(instanceConfig.ImageStream)
 
 	var hardware instance.HardwareCharacteristics
 	hardware, err = instance.ParseHardware(
@@ -165,6 +170,30 @@ func (manager *containerManager) CreateContainer(
 	return &kvmInstance{kvmContainer, name}, &hardware, nil
 }
 
+// imageDownloadURL returns the simplestreams source from which images
+// are synchronised, or "" if the default source should be used. The
+// stream configured for the manager takes precedence over the one
+// requested by the instance config.
+func (manager *containerManager) imageDownloadURL(stream string) string {
+	if manager.imageStream != "" {
+		stream = manager.imageStream
+	}
+	if manager.imageSourceURL == "" {
+		// If the Simplestream requested is anything but released,
+		// request it explicitly.
+		if stream == "" || stream == imagemetadata.ReleasedStream {
+			return ""
+		}
+		return imagemetadata.UbuntuCloudImagesURL + "/" + stream
+	}
+	// Mirrors share the layout of cloud-images.ubuntu.com, where
+	// released images are found under "releases".
+	if stream == "" || stream == imagemetadata.ReleasedStream {
+		stream = "releases"
+	}
+	return strings.TrimSuffix(manager.imageSourceURL, "/") + "/" + stream
+}
+
 func (manager *containerManager) IsInitialized() bool {
 	requiredBinaries := []string{
 		"virsh",
@@ -152,6 +152,41 @@ func (s *KVMSuite) TestCreateContainerUtilizesDailySimpleStream(c *gc.C) {
 	c.Assert(kvm.TestStartParams.ImageDownloadUrl, gc.Equals, "http://cloud-images.ubuntu.com/daily")
 }
 
+func (s *KVMSuite) TestCreateContainerUtilizesConfiguredImageSource(c *gc.C) {
+	manager, err := kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigName:           "test",
+		container.ConfigImageStream:    "daily",
+		container.ConfigImageSourceURL: "http://mirror.example.com/ubuntu/",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.ImageStream = "released"
+
+	// CreateContainer sets TestStartParams internally; we call this
+	// purely for the side-effect.
+	containertesting.CreateContainerWithMachineConfig(c, manager, instanceConfig)
+
+	c.Assert(kvm.TestStartParams.ImageDownloadUrl, gc.Equals, "http://mirror.example.com/ubuntu/daily")
+}
+
+func (s *KVMSuite) TestCreateContainerUtilizesConfiguredImageSourceReleased(c *gc.C) {
+	manager, err := kvm.NewContainerManager(container.ManagerConfig{
+		container.ConfigName:           "test",
+		container.ConfigImageSourceURL: "http://mirror.example.com/ubuntu",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	instanceConfig, err := containertesting.MockMachineConfig("1/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.ImageStream = "released"
+
+	containertesting.CreateContainerWithMachineConfig(c, manager, instanceConfig)
+
+	c.Assert(kvm.TestStartParams.ImageDownloadUrl, gc.Equals, "http://mirror.example.com/ubuntu/releases")
+}
+
 func (s *KVMSuite) TestStartContainerUtilizesSimpleStream(c *gc.C) {
 
 	const libvirtBinName = "uvt-simplestreams-libvirt"
@@ -26,3 +26,7 @@ var (
 func GetCreateWithCloneValue(mgr container.Manager) bool {
 	return mgr.(*containerManager).createWithClone
 }
+
+func GetImageURLGetter(mgr container.Manager) container.ImageURLGetter {
+	return mgr.(*containerManager).imageURLGetter
+}
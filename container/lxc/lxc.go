@@ -163,6 +163,18 @@ func NewContainerManager(conf container.ManagerConfig, imageURLGetter container.
 		backingFS = "unknown"
 	}
 	logger.Tracef("backing filesystem: %q", backingFS)
+	imageStream := conf.PopValue(container.ConfigImageStream)
+	imageSourceURL := conf.PopValue(container.ConfigImageSourceURL)
+	if useProxy, err := strconv.ParseBool(conf.PopValue(container.ConfigImageProxy)); err == nil && !useProxy {
+		imageURLGetter = nil
+	}
+	if imageURLGetter == nil && (imageSourceURL != "" || imageStream != "" && imageStream != "released") {
+		// Without the state servers' image cache, the LXC template
+		// only knows how to fetch released images from
+		// cloud-images.ubuntu.com, so any other image must be
+		// located explicitly.
+		imageURLGetter = container.NewImageDownloadURLGetter(imageStream, imageSourceURL)
+	}
 	conf.WarnAboutUnused()
 	return &containerManager{
 		name:              name,
@@ -176,6 +176,50 @@ func (s *LxcSuite) TestContainerManagerLXCClone(c *gc.C) {
 	}
 }
 
+func (s *LxcSuite) TestContainerManagerImageURLGetter(c *gc.C) {
+	gitjujutesting.PatchExecutable(c, s, "ubuntu-cloudimg-query", containertesting.FakeLxcURLScript)
+	proxyGetter := &containertesting.MockURLGetter{}
+	tests := []struct {
+		about       string
+		config      container.ManagerConfig
+		expectProxy bool
+		expectURL   string
+	}{{
+		about:       "proxy used by default",
+		config:      container.ManagerConfig{container.ConfigImageStream: "daily"},
+		expectProxy: true,
+	}, {
+		about:  "proxy disabled",
+		config: container.ManagerConfig{container.ConfigImageProxy: "false"},
+	}, {
+		about: "proxy disabled with image source",
+		config: container.ManagerConfig{
+			container.ConfigImageProxy:     "false",
+			container.ConfigImageStream:    "daily",
+			container.ConfigImageSourceURL: "http://mirror.example.com",
+		},
+		expectURL: "http://mirror.example.com/trusty-daily-amd64-root.tar.gz",
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		test.config[container.ConfigName] = "juju"
+		mgr, err := lxc.NewContainerManager(test.config, proxyGetter)
+		c.Assert(err, jc.ErrorIsNil)
+		getter := lxc.GetImageURLGetter(mgr)
+		switch {
+		case test.expectProxy:
+			c.Check(getter, gc.Equals, proxyGetter)
+		case test.expectURL == "":
+			c.Check(getter, gc.IsNil)
+		default:
+			c.Assert(getter, gc.NotNil)
+			imageURL, err := getter.ImageURL(instance.LXC, "trusty", "amd64")
+			c.Assert(err, jc.ErrorIsNil)
+			c.Check(imageURL, gc.Equals, test.expectURL)
+		}
+	}
+}
+
 func (s *LxcSuite) TestContainerDirFilesystem(c *gc.C) {
 	for i, test := range []struct {
 		message    string
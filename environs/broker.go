@@ -39,6 +39,13 @@ type StartInstanceParams struct {
 	// high availability.
 	DistributionGroup func() ([]instance.Id, error)
 
+	// DistributionGroupName, if non-empty, names the distribution
+	// group of the machine being provisioned; machines hosting
+	// units of the same service share the same name. Providers
+	// may use it to name the group, such as an availability set,
+	// in which the instance is started.
+	DistributionGroupName string
+
 	// Volumes is a set of parameters for volumes that should be created.
 	//
 	// StartInstance need not check the value of the Attachment field,
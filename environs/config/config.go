@@ -4,8 +4,10 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +21,7 @@ import (
 	"github.com/juju/utils/proxy"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/juju/charm.v5/charmrepo"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/juju/osenv"
@@ -84,6 +87,50 @@ const (
 	// Object here is a juju artifact - machine, service, unit or relation.
 	DefaultPreventRemoveObject = false
 
+	// DefaultMaxLogsAge is the default maximum age, in hours, of the
+	// log records kept in the state server's log database.
+	DefaultMaxLogsAge int = 72
+
+	// DefaultMaxLogsSize is the default maximum size, in megabytes,
+	// of the state server's log database.
+	DefaultMaxLogsSize int = 4 * 1024
+
+	// DefaultLogSinkRateLimit is the default number of log messages
+	// per second that each agent may send to the state server.
+	DefaultLogSinkRateLimit int = 1000
+
+	// DefaultTxnPruneWindow is the default age, in hours, beyond which
+	// completed transactions are removed from the state database.
+	DefaultTxnPruneWindow int = 24
+
+	// DefaultProvisioningAlertDelay is the default time, in minutes,
+	// that a machine stays in a provisioning error before it is
+	// reported.
+	DefaultProvisioningAlertDelay int = 15
+
+	// LoggingFormatText is the logging format that writes agent log
+	// messages as plain lines of text. It is the default.
+	LoggingFormatText = "text"
+
+	// LoggingFormatJSON is the logging format that writes agent log
+	// messages as JSON objects, one per line.
+	LoggingFormatJSON = "json"
+
+	// UnitPlacementSpread is the unit placement strategy that assigns
+	// each unit to a clean, empty machine, launching a new one if
+	// there is none. It is the default.
+	UnitPlacementSpread = "spread"
+
+	// UnitPlacementPack is the unit placement strategy that assigns
+	// each unit to the busiest existing machine not already hosting a
+	// unit of the same service.
+	UnitPlacementPack = "pack"
+
+	// UnitPlacementZoneBalanced is the unit placement strategy that
+	// assigns each unit to a clean, empty machine in the availability
+	// zone hosting the fewest units of the same service.
+	UnitPlacementZoneBalanced = "zone-balanced"
+
 	// DefaultPreventAllChanges should not be used by default.
 	// Only prevent all-changes from running
 	// if user specifically requests it. Otherwise, let them run.
@@ -101,6 +148,33 @@ const (
 	// ProvisionerHarvestModeKey stores the key for this setting.
 	ProvisionerHarvestModeKey = "provisioner-harvest-mode"
 
+	// LoggingFormatKey stores the key for this setting.
+	LoggingFormatKey = "logging-format"
+
+	// MaxLogsAgeKey stores the key for this setting.
+	MaxLogsAgeKey = "max-logs-age"
+
+	// MaxLogsSizeKey stores the key for this setting.
+	MaxLogsSizeKey = "max-logs-size"
+
+	// LogSinkRateLimitKey stores the key for this setting.
+	LogSinkRateLimitKey = "logsink-rate-limit"
+
+	// TxnPruneWindowKey stores the key for this setting.
+	TxnPruneWindowKey = "txn-prune-window"
+
+	// MongoPoolLimitKey stores the key for this setting.
+	MongoPoolLimitKey = "mongo-pool-limit"
+
+	// MongoSocketTimeoutKey stores the key for this setting.
+	MongoSocketTimeoutKey = "mongo-socket-timeout"
+
+	// MongoSyncTimeoutKey stores the key for this setting.
+	MongoSyncTimeoutKey = "mongo-sync-timeout"
+
+	// UnitPlacementKey stores the key for this setting.
+	UnitPlacementKey = "unit-placement"
+
 	// AgentStreamKey stores the key for this setting.
 	AgentStreamKey = "agent-stream"
 
@@ -128,6 +202,12 @@ const (
 	// NoProxyKey stores the key for this setting.
 	NoProxyKey = "no-proxy"
 
+	// CharmStoreURLKey stores the key for this setting.
+	CharmStoreURLKey = "charm-store-url"
+
+	// CharmStoreAuthKey stores the key for this setting.
+	CharmStoreAuthKey = "charm-store-auth"
+
 	// LxcClone stores the value for this setting.
 	LxcClone = "lxc-clone"
 
@@ -155,6 +235,36 @@ const (
 	// allowed by the user.
 	AllowLXCLoopMounts = "allow-lxc-loop-mounts"
 
+	// ContainerImageStreamKey stores the key for this setting.
+	ContainerImageStreamKey = "container-image-stream"
+
+	// ContainerImageSourceURLKey stores the key for this setting.
+	ContainerImageSourceURLKey = "container-image-source-url"
+
+	// ContainerImageProxyKey stores the key for this setting.
+	ContainerImageProxyKey = "container-image-proxy"
+
+	// HookEnvironmentKey stores the key for this setting.
+	HookEnvironmentKey = "hook-environment"
+
+	// DNSRegistrarKey stores the key for this setting.
+	DNSRegistrarKey = "dns-registrar"
+
+	// DNSDomainKey stores the key for this setting.
+	DNSDomainKey = "dns-domain"
+
+	// DNSWebhookURLKey stores the key for this setting.
+	DNSWebhookURLKey = "dns-webhook-url"
+
+	// ProvisioningAlertDelayKey stores the key for this setting.
+	ProvisioningAlertDelayKey = "provisioning-alert-delay"
+
+	// ProvisioningAlertURLKey stores the key for this setting.
+	ProvisioningAlertURLKey = "provisioning-alert-url"
+
+	// RemoveEmptyMachinesKey stores the key for this setting.
+	RemoveEmptyMachinesKey = "remove-empty-machines"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -567,6 +677,65 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	// If the logging format is set, make sure it is valid.
+	if v, ok := cfg.defined[LoggingFormatKey].(string); ok {
+		if err := validateLoggingFormat(v); err != nil {
+			return err
+		}
+	}
+
+	// If the unit placement strategy is set, make sure it is valid.
+	if v, ok := cfg.defined[UnitPlacementKey].(string); ok {
+		if err := validateUnitPlacement(v); err != nil {
+			return err
+		}
+	}
+
+	// If a charm store is configured, make sure it can be reached.
+	if v, ok := cfg.defined[CharmStoreURLKey].(string); ok && v != "" {
+		if err := validateCharmStoreURL(v); err != nil {
+			return err
+		}
+	}
+	if v, ok := cfg.defined[CharmStoreAuthKey].(string); ok && v != "" {
+		if _, err := parseCharmStoreAuth(v); err != nil {
+			return err
+		}
+	}
+
+	for _, attr := range []string{
+		MaxLogsAgeKey, MaxLogsSizeKey, LogSinkRateLimitKey, TxnPruneWindowKey,
+		MongoPoolLimitKey, MongoSocketTimeoutKey, MongoSyncTimeoutKey,
+		ProvisioningAlertDelayKey,
+	} {
+		if v, ok := cfg.defined[attr].(int); ok && v < 0 {
+			return fmt.Errorf("%s: expected a non-negative number, got %d", attr, v)
+		}
+	}
+
+	// If a container image source is configured, make sure it is a URL.
+	if v, ok := cfg.defined[ContainerImageSourceURLKey].(string); ok && v != "" {
+		if err := validateContainerImageSourceURL(v); err != nil {
+			return err
+		}
+	}
+
+	if v, ok := cfg.defined[HookEnvironmentKey].(string); ok && v != "" {
+		if _, err := parseHookEnvironment(v); err != nil {
+			return err
+		}
+	}
+
+	if err := cfg.validateDNS(); err != nil {
+		return err
+	}
+
+	if hook := cfg.ProvisioningAlertURL(); hook != "" {
+		if err := validateWebhookURL(ProvisioningAlertURLKey, hook); err != nil {
+			return err
+		}
+	}
+
 	// Check firewall mode.
 	switch mode := cfg.FirewallMode(); mode {
 	case FwInstance, FwGlobal, FwNone:
@@ -1041,6 +1210,144 @@ func (c *Config) LoggingConfig() string {
 	return c.asString("logging-config")
 }
 
+// LoggingFormat returns the format in which agents write their log
+// messages, either LoggingFormatText or LoggingFormatJSON.
+func (c *Config) LoggingFormat() string {
+	if v, ok := c.defined[LoggingFormatKey].(string); ok && v != "" {
+		return v
+	}
+	return LoggingFormatText
+}
+
+// MaxLogsAge returns the age beyond which log records are removed from
+// the state server's log database.
+func (c *Config) MaxLogsAge() time.Duration {
+	hours, ok := c.defined[MaxLogsAgeKey].(int)
+	if !ok || hours == 0 {
+		hours = DefaultMaxLogsAge
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// MaxLogsSizeMB returns the size, in megabytes, beyond which the oldest
+// log records are removed from the state server's log database.
+func (c *Config) MaxLogsSizeMB() int {
+	if size, ok := c.defined[MaxLogsSizeKey].(int); ok && size > 0 {
+		return size
+	}
+	return DefaultMaxLogsSize
+}
+
+// LogSinkRateLimit returns the number of log messages per second that
+// each agent may send to the state server. Zero means that there is no
+// limit.
+func (c *Config) LogSinkRateLimit() int {
+	if limit, ok := c.defined[LogSinkRateLimitKey].(int); ok {
+		return limit
+	}
+	return DefaultLogSinkRateLimit
+}
+
+// TxnPruneWindow returns the age beyond which completed transactions
+// are removed from the state database.
+func (c *Config) TxnPruneWindow() time.Duration {
+	hours, ok := c.defined[TxnPruneWindowKey].(int)
+	if !ok || hours == 0 {
+		hours = DefaultTxnPruneWindow
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// MongoPoolLimit returns the maximum number of sockets that a state
+// server's MongoDB sessions may hold open to each server, and whether
+// it has been set.
+func (c *Config) MongoPoolLimit() (int, bool) {
+	limit, ok := c.defined[MongoPoolLimitKey].(int)
+	return limit, ok && limit > 0
+}
+
+// MongoSocketTimeout returns how long a state server waits for a
+// non-responding MongoDB socket before closing it, and whether it has
+// been set.
+func (c *Config) MongoSocketTimeout() (time.Duration, bool) {
+	secs, ok := c.defined[MongoSocketTimeoutKey].(int)
+	return time.Duration(secs) * time.Second, ok && secs > 0
+}
+
+// MongoSyncTimeout returns how long a state server's MongoDB operations
+// wait for a suitable server to become available, and whether it has
+// been set.
+func (c *Config) MongoSyncTimeout() (time.Duration, bool) {
+	secs, ok := c.defined[MongoSyncTimeoutKey].(int)
+	return time.Duration(secs) * time.Second, ok && secs > 0
+}
+
+// CharmStoreURL returns the URL of the charm store used by the
+// environment, and whether it has been set. When it is not set, the
+// public charm store is used.
+func (c *Config) CharmStoreURL() (string, bool) {
+	v, ok := c.defined[CharmStoreURLKey].(string)
+	return v, ok && v != ""
+}
+
+// CharmStoreAuth returns the macaroons that authorize the environment
+// to fetch charms from its charm store, if any have been set.
+func (c *Config) CharmStoreAuth() ([]*macaroon.Macaroon, error) {
+	v, _ := c.defined[CharmStoreAuthKey].(string)
+	if v == "" {
+		return nil, nil
+	}
+	return parseCharmStoreAuth(v)
+}
+
+func validateCharmStoreURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return errors.Annotatef(err, "invalid charm store URL %q", value)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid charm store URL %q: expected an http or https URL", value)
+	}
+	return nil
+}
+
+// parseCharmStoreAuth parses the JSON-encoded list of macaroons held
+// in the charm-store-auth attribute.
+func parseCharmStoreAuth(value string) ([]*macaroon.Macaroon, error) {
+	var ms []*macaroon.Macaroon
+	if err := json.Unmarshal([]byte(value), &ms); err != nil {
+		return nil, errors.Annotatef(err, "invalid %s", CharmStoreAuthKey)
+	}
+	return ms, nil
+}
+
+// UnitPlacement returns the strategy used to choose machines for units
+// added without a placement directive: one of UnitPlacementSpread,
+// UnitPlacementPack or UnitPlacementZoneBalanced.
+func (c *Config) UnitPlacement() string {
+	if v, ok := c.defined[UnitPlacementKey].(string); ok && v != "" {
+		return v
+	}
+	return UnitPlacementSpread
+}
+
+func validateUnitPlacement(strategy string) error {
+	switch strategy {
+	case UnitPlacementSpread, UnitPlacementPack, UnitPlacementZoneBalanced:
+		return nil
+	}
+	return fmt.Errorf("invalid unit placement %q: expected one of %q, %q, %q",
+		strategy, UnitPlacementSpread, UnitPlacementPack, UnitPlacementZoneBalanced)
+}
+
+func validateLoggingFormat(format string) error {
+	switch format {
+	case LoggingFormatText, LoggingFormatJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid logging format %q: expected one of %q, %q", format, LoggingFormatText, LoggingFormatJSON)
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -1121,6 +1428,207 @@ func (c *Config) AllowLXCLoopMounts() (bool, bool) {
 	return v, ok
 }
 
+// ContainerImageStream returns the simplestreams stream used to
+// identify which images to use when creating containers. If it is
+// not set then the image-stream setting is used.
+func (c *Config) ContainerImageStream() string {
+	v, _ := c.defined[ContainerImageStreamKey].(string)
+	if v != "" {
+		return v
+	}
+	return c.ImageStream()
+}
+
+// ContainerImageSourceURL returns the base URL of the mirror of
+// http://cloud-images.ubuntu.com from which container images are
+// fetched, if one has been set.
+func (c *Config) ContainerImageSourceURL() (string, bool) {
+	v, _ := c.defined[ContainerImageSourceURLKey].(string)
+	return v, v != ""
+}
+
+// ContainerImageProxy reports whether machines should fetch container
+// images through the image cache hosted by the state servers, rather
+// than directly from the image source. It defaults to true.
+func (c *Config) ContainerImageProxy() bool {
+	v, ok := c.defined[ContainerImageProxyKey].(bool)
+	return v || !ok
+}
+
+func validateContainerImageSourceURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return errors.Annotatef(err, "invalid container image source URL %q", value)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid container image source URL %q: expected an http or https URL", value)
+	}
+	return nil
+}
+
+// HookEnvironment returns the extra environment variables set in the
+// context of every hook, keyed by name.
+func (c *Config) HookEnvironment() map[string]string {
+	v, _ := c.defined[HookEnvironmentKey].(string)
+	vars, err := parseHookEnvironment(v)
+	if err != nil {
+		// The setting is validated, so this should never happen.
+		logger.Errorf("ignoring invalid %s: %v", HookEnvironmentKey, err)
+		return nil
+	}
+	return vars
+}
+
+const (
+	// DNSRegistrarNone disables DNS registration.
+	DNSRegistrarNone = ""
+
+	// DNSRegistrarProvider registers names with the provider's
+	// native DNS service.
+	DNSRegistrarProvider = "provider"
+
+	// DNSRegistrarWebhook registers names by posting them to the
+	// URL held in dns-webhook-url.
+	DNSRegistrarWebhook = "webhook"
+)
+
+// DNSRegistrar returns the mechanism used to register DNS names for
+// the environment's services; DNSRegistrarNone means that no names
+// are registered.
+func (c *Config) DNSRegistrar() string {
+	v, _ := c.defined[DNSRegistrarKey].(string)
+	return v
+}
+
+// DNSDomain returns the domain under which service names are
+// registered.
+func (c *Config) DNSDomain() string {
+	v, _ := c.defined[DNSDomainKey].(string)
+	return v
+}
+
+// DNSWebhookURL returns the URL to which DNS registrations are posted
+// when the webhook registrar is used.
+func (c *Config) DNSWebhookURL() string {
+	v, _ := c.defined[DNSWebhookURLKey].(string)
+	return v
+}
+
+func (c *Config) validateDNS() error {
+	registrar := c.DNSRegistrar()
+	switch registrar {
+	case DNSRegistrarNone:
+		return nil
+	case DNSRegistrarProvider, DNSRegistrarWebhook:
+	default:
+		return fmt.Errorf("invalid %s %q: expected %q or %q",
+			DNSRegistrarKey, registrar, DNSRegistrarProvider, DNSRegistrarWebhook)
+	}
+	if c.DNSDomain() == "" {
+		return fmt.Errorf("%s must be set when %s is %q", DNSDomainKey, DNSRegistrarKey, registrar)
+	}
+	if registrar != DNSRegistrarWebhook {
+		return nil
+	}
+	hook := c.DNSWebhookURL()
+	if hook == "" {
+		return fmt.Errorf("%s must be set when %s is %q", DNSWebhookURLKey, DNSRegistrarKey, registrar)
+	}
+	return validateWebhookURL(DNSWebhookURLKey, hook)
+}
+
+// validateWebhookURL returns an error unless the value of the named
+// setting is an http or https URL.
+func validateWebhookURL(key, hook string) error {
+	u, err := url.Parse(hook)
+	if err != nil {
+		return errors.Annotatef(err, "invalid %s %q", key, hook)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s %q: expected an http or https URL", key, hook)
+	}
+	return nil
+}
+
+// ProvisioningAlertDelay returns how long a machine must have been in
+// a provisioning error before it is reported.
+func (c *Config) ProvisioningAlertDelay() time.Duration {
+	minutes, ok := c.defined[ProvisioningAlertDelayKey].(int)
+	if !ok || minutes == 0 {
+		minutes = DefaultProvisioningAlertDelay
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ProvisioningAlertURL returns the URL to which reports of machines
+// stuck in provisioning errors are posted. It is empty if they are
+// only logged.
+func (c *Config) ProvisioningAlertURL() string {
+	v, _ := c.defined[ProvisioningAlertURLKey].(string)
+	return v
+}
+
+// RemoveEmptyMachines reports whether machines left empty by the
+// removal of units and their containers are destroyed automatically.
+// Manually provisioned machines are never destroyed this way.
+func (c *Config) RemoveEmptyMachines() bool {
+	v, _ := c.defined[RemoveEmptyMachinesKey].(bool)
+	return v
+}
+
+// reservedHookVariables holds the variables that are always set by
+// juju in hook contexts, and cannot be set with hook-environment.
+var reservedHookVariables = []string{"CHARM_DIR", "PATH", "PSModulePath"}
+
+// parseHookEnvironment parses the whitespace-separated list of
+// NAME=value pairs held in the hook-environment attribute.
+func parseHookEnvironment(value string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, field := range strings.Fields(value) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid %s entry %q: expected NAME=value", HookEnvironmentKey, field)
+		}
+		name := parts[0]
+		if !validHookVariable(name) {
+			return nil, fmt.Errorf("invalid %s variable name %q", HookEnvironmentKey, name)
+		}
+		if strings.HasPrefix(name, "JUJU_") || isReservedHookVariable(name) {
+			return nil, fmt.Errorf("%s cannot set %s: it is set by juju", HookEnvironmentKey, name)
+		}
+		if _, ok := vars[name]; ok {
+			return nil, fmt.Errorf("%s sets %s more than once", HookEnvironmentKey, name)
+		}
+		vars[name] = parts[1]
+	}
+	return vars, nil
+}
+
+func validHookVariable(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isReservedHookVariable(name string) bool {
+	for _, reserved := range reservedHookVariables {
+		// Windows environment variable names are case insensitive.
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1186,11 +1694,22 @@ var fields = schema.Fields{
 	"rsyslog-ca-cert":            schema.String(),
 	"rsyslog-ca-key":             schema.String(),
 	"logging-config":             schema.String(),
+	LoggingFormatKey:             schema.String(),
+	MaxLogsAgeKey:                schema.ForceInt(),
+	MaxLogsSizeKey:               schema.ForceInt(),
+	LogSinkRateLimitKey:          schema.ForceInt(),
+	TxnPruneWindowKey:            schema.ForceInt(),
+	MongoPoolLimitKey:            schema.ForceInt(),
+	MongoSocketTimeoutKey:        schema.ForceInt(),
+	MongoSyncTimeoutKey:          schema.ForceInt(),
+	UnitPlacementKey:             schema.String(),
 	ProvisionerHarvestModeKey:    schema.String(),
 	HttpProxyKey:                 schema.String(),
 	HttpsProxyKey:                schema.String(),
 	FtpProxyKey:                  schema.String(),
 	NoProxyKey:                   schema.String(),
+	CharmStoreURLKey:             schema.String(),
+	CharmStoreAuthKey:            schema.String(),
 	AptHttpProxyKey:              schema.String(),
 	AptHttpsProxyKey:             schema.String(),
 	AptFtpProxyKey:               schema.String(),
@@ -1212,6 +1731,16 @@ var fields = schema.Fields{
 	PreventAllChangesKey:         schema.Bool(),
 	StorageDefaultBlockSourceKey: schema.String(),
 	AllowLXCLoopMounts:           schema.Bool(),
+	ContainerImageStreamKey:      schema.String(),
+	ContainerImageSourceURLKey:   schema.String(),
+	ContainerImageProxyKey:       schema.Bool(),
+	HookEnvironmentKey:           schema.String(),
+	DNSRegistrarKey:              schema.String(),
+	DNSDomainKey:                 schema.String(),
+	DNSWebhookURLKey:             schema.String(),
+	ProvisioningAlertDelayKey:    schema.ForceInt(),
+	ProvisioningAlertURLKey:      schema.String(),
+	RemoveEmptyMachinesKey:       schema.Bool(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1236,6 +1765,15 @@ var alwaysOptional = schema.Defaults{
 	"ca-cert-path":               schema.Omit,
 	"ca-private-key-path":        schema.Omit,
 	"logging-config":             schema.Omit,
+	LoggingFormatKey:             schema.Omit,
+	MaxLogsAgeKey:                schema.Omit,
+	MaxLogsSizeKey:               schema.Omit,
+	LogSinkRateLimitKey:          schema.Omit,
+	TxnPruneWindowKey:            schema.Omit,
+	MongoPoolLimitKey:            schema.Omit,
+	MongoSocketTimeoutKey:        schema.Omit,
+	MongoSyncTimeoutKey:          schema.Omit,
+	UnitPlacementKey:             schema.Omit,
 	ProvisionerHarvestModeKey:    schema.Omit,
 	"bootstrap-timeout":          schema.Omit,
 	"bootstrap-retry-delay":      schema.Omit,
@@ -1246,6 +1784,8 @@ var alwaysOptional = schema.Defaults{
 	HttpsProxyKey:                schema.Omit,
 	FtpProxyKey:                  schema.Omit,
 	NoProxyKey:                   schema.Omit,
+	CharmStoreURLKey:             schema.Omit,
+	CharmStoreAuthKey:            schema.Omit,
 	AptHttpProxyKey:              schema.Omit,
 	AptHttpsProxyKey:             schema.Omit,
 	AptFtpProxyKey:               schema.Omit,
@@ -1255,6 +1795,16 @@ var alwaysOptional = schema.Defaults{
 	AgentStreamKey:               schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 	AllowLXCLoopMounts:           false,
+	ContainerImageStreamKey:      schema.Omit,
+	ContainerImageSourceURLKey:   schema.Omit,
+	ContainerImageProxyKey:       schema.Omit,
+	HookEnvironmentKey:           schema.Omit,
+	DNSRegistrarKey:              schema.Omit,
+	DNSDomainKey:                 schema.Omit,
+	DNSWebhookURLKey:             schema.Omit,
+	ProvisioningAlertDelayKey:    schema.Omit,
+	ProvisioningAlertURLKey:      schema.Omit,
+	RemoveEmptyMachinesKey:       schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1351,21 +1901,7 @@ var mandatoryWithoutDefaults = []string{
 // immutableAttributes holds those attributes
 // which are not allowed to change in the lifetime
 // of an environment.
-var immutableAttributes = []string{
-	"name",
-	"type",
-	"uuid",
-	"firewall-mode",
-	"state-port",
-	"api-port",
-	"bootstrap-timeout",
-	"bootstrap-retry-delay",
-	"bootstrap-addresses-delay",
-	LxcClone,
-	"lxc-clone-aufs",
-	"syslog-port",
-	"prefer-ipv6",
-}
+var immutableAttributes = coreSchema.immutable()
 
 var (
 	withDefaultsChecker = schema.FieldMap(fields, defaults)
@@ -1385,7 +1921,7 @@ func (cfg *Config) ValidateUnknownAttrs(fields schema.Fields, defaults schema.De
 	coerced, err := checker.Coerce(attrs, nil)
 	if err != nil {
 		// TODO(ericsnow) Drop this?
-		logger.Debugf("coercion failed attributes: %#v, checker: %#v, %v", attrs, checker, err)
+		DebugAttrs(logger, cfg.Type(), attrs, "coercion failed (checker: %#v, %v), attributes", checker, err)
 		return nil, err
 	}
 	result := coerced.(map[string]interface{})
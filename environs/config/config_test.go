@@ -4,6 +4,7 @@
 package config_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"regexp"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/utils/proxy"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5/charmrepo"
+	"gopkg.in/macaroon.v1"
 
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
@@ -772,6 +774,42 @@ var configTests = []configTest{
 			"logging-config": "foo=bar",
 		},
 		err: `unknown severity level "bar"`,
+	}, {
+		about:       "Invalid logging format",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"logging-format": "xml",
+		},
+		err: `invalid logging format "xml": expected one of "text", "json"`,
+	}, {
+		about:       "Invalid unit placement",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":           "my-type",
+			"name":           "my-name",
+			"unit-placement": "scatter",
+		},
+		err: `invalid unit placement "scatter": expected one of "spread", "pack", "zone-balanced"`,
+	}, {
+		about:       "Negative logsink rate limit",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":               "my-type",
+			"name":               "my-name",
+			"logsink-rate-limit": -1,
+		},
+		err: `logsink-rate-limit: expected a non-negative number, got -1`,
+	}, {
+		about:       "Invalid max logs size",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":          "my-type",
+			"name":          "my-name",
+			"max-logs-size": "big",
+		},
+		err: `max-logs-size: expected number, got string\("big"\)`,
 	}, {
 		about:       "Sample configuration",
 		useDefaults: config.UseDefaults,
@@ -1592,6 +1630,321 @@ func (s *ConfigSuite) TestLoggingConfigWithUnit(c *gc.C) {
 	c.Assert(config.LoggingConfig(), gc.Equals, "<root>=WARNING;unit=INFO")
 }
 
+func (s *ConfigSuite) TestLoggingFormatDefault(c *gc.C) {
+	s.addJujuFiles(c)
+	config := newTestConfig(c, nil)
+	c.Assert(config.LoggingFormat(), gc.Equals, "text")
+}
+
+func (s *ConfigSuite) TestLoggingFormat(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{"logging-format": "json"})
+	c.Assert(cfg.LoggingFormat(), gc.Equals, config.LoggingFormatJSON)
+}
+
+func (s *ConfigSuite) TestUnitPlacementDefault(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.UnitPlacement(), gc.Equals, config.UnitPlacementSpread)
+}
+
+func (s *ConfigSuite) TestUnitPlacement(c *gc.C) {
+	s.addJujuFiles(c)
+	for _, strategy := range []string{"spread", "pack", "zone-balanced"} {
+		cfg := newTestConfig(c, testing.Attrs{"unit-placement": strategy})
+		c.Assert(cfg.UnitPlacement(), gc.Equals, strategy)
+	}
+}
+
+func (s *ConfigSuite) TestCharmStoreDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	_, ok := cfg.CharmStoreURL()
+	c.Assert(ok, jc.IsFalse)
+	ms, err := cfg.CharmStoreAuth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ms, gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestCharmStore(c *gc.C) {
+	s.addJujuFiles(c)
+	m, err := macaroon.New([]byte("root key"), "id", "charmstore")
+	c.Assert(err, jc.ErrorIsNil)
+	auth, err := json.Marshal([]*macaroon.Macaroon{m})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := newTestConfig(c, testing.Attrs{
+		"charm-store-url":  "https://charmstore.example.com/v4",
+		"charm-store-auth": string(auth),
+	})
+	csURL, ok := cfg.CharmStoreURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(csURL, gc.Equals, "https://charmstore.example.com/v4")
+	ms, err := cfg.CharmStoreAuth()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ms, gc.HasLen, 1)
+	c.Assert(ms[0].Id(), gc.Equals, "id")
+}
+
+func (s *ConfigSuite) TestCharmStoreInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"charm-store-url": "charmstore.example.com"},
+		err:   `invalid charm store URL "charmstore.example.com": expected an http or https URL`,
+	}, {
+		attrs: testing.Attrs{"charm-store-url": "ftp://charmstore.example.com"},
+		err:   `invalid charm store URL "ftp://charmstore.example.com": expected an http or https URL`,
+	}, {
+		attrs: testing.Attrs{"charm-store-auth": "not json"},
+		err:   `invalid charm-store-auth: .*`,
+	}} {
+		c.Logf("test %d", i)
+		final := testing.Attrs{"type": "my-type", "name": "my-name"}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, final)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestContainerImageDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{"image-stream": "daily"})
+	c.Assert(cfg.ContainerImageStream(), gc.Equals, "daily")
+	_, ok := cfg.ContainerImageSourceURL()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.ContainerImageProxy(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestContainerImage(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"image-stream":               "released",
+		"container-image-stream":     "daily",
+		"container-image-source-url": "http://mirror.example.com/ubuntu",
+		"container-image-proxy":      false,
+	})
+	c.Assert(cfg.ContainerImageStream(), gc.Equals, "daily")
+	sourceURL, ok := cfg.ContainerImageSourceURL()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sourceURL, gc.Equals, "http://mirror.example.com/ubuntu")
+	c.Assert(cfg.ContainerImageProxy(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestContainerImageSourceURLInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	final := testing.Attrs{
+		"type":                       "my-type",
+		"name":                       "my-name",
+		"container-image-source-url": "mirror.example.com",
+	}
+	_, err := config.New(config.UseDefaults, final)
+	c.Assert(err, gc.ErrorMatches, `invalid container image source URL "mirror.example.com": expected an http or https URL`)
+}
+
+func (s *ConfigSuite) TestHookEnvironment(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.HookEnvironment(), gc.HasLen, 0)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"hook-environment": "LANG=en_US.UTF-8 no_proxy=localhost,10.0.0.1\tEMPTY=",
+	})
+	c.Assert(cfg.HookEnvironment(), jc.DeepEquals, map[string]string{
+		"LANG":     "en_US.UTF-8",
+		"no_proxy": "localhost,10.0.0.1",
+		"EMPTY":    "",
+	})
+}
+
+func (s *ConfigSuite) TestHookEnvironmentInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "LANG",
+		err:   `invalid hook-environment entry "LANG": expected NAME=value`,
+	}, {
+		value: "1LANG=C",
+		err:   `invalid hook-environment variable name "1LANG"`,
+	}, {
+		value: "=C",
+		err:   `invalid hook-environment variable name ""`,
+	}, {
+		value: "JUJU_UNIT_NAME=foo/0",
+		err:   "hook-environment cannot set JUJU_UNIT_NAME: it is set by juju",
+	}, {
+		value: "path=/tmp",
+		err:   "hook-environment cannot set path: it is set by juju",
+	}, {
+		value: "LANG=C LANG=en_US.UTF-8",
+		err:   "hook-environment sets LANG more than once",
+	}} {
+		c.Logf("test %d: %q", i, test.value)
+		_, err := config.New(config.UseDefaults, testing.Attrs{
+			"type":             "my-type",
+			"name":             "my-name",
+			"hook-environment": test.value,
+		})
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestDNSDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.DNSRegistrar(), gc.Equals, config.DNSRegistrarNone)
+	c.Assert(cfg.DNSDomain(), gc.Equals, "")
+	c.Assert(cfg.DNSWebhookURL(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestDNS(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"dns-registrar":   "webhook",
+		"dns-domain":      "apps.example.com",
+		"dns-webhook-url": "https://dns.example.com/register",
+	})
+	c.Assert(cfg.DNSRegistrar(), gc.Equals, config.DNSRegistrarWebhook)
+	c.Assert(cfg.DNSDomain(), gc.Equals, "apps.example.com")
+	c.Assert(cfg.DNSWebhookURL(), gc.Equals, "https://dns.example.com/register")
+}
+
+func (s *ConfigSuite) TestDNSInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"dns-registrar": "route53", "dns-domain": "example.com"},
+		err:   `invalid dns-registrar "route53": expected "provider" or "webhook"`,
+	}, {
+		attrs: testing.Attrs{"dns-registrar": "provider"},
+		err:   `dns-domain must be set when dns-registrar is "provider"`,
+	}, {
+		attrs: testing.Attrs{"dns-registrar": "webhook", "dns-domain": "example.com"},
+		err:   `dns-webhook-url must be set when dns-registrar is "webhook"`,
+	}, {
+		attrs: testing.Attrs{
+			"dns-registrar":   "webhook",
+			"dns-domain":      "example.com",
+			"dns-webhook-url": "dns.example.com",
+		},
+		err: `invalid dns-webhook-url "dns.example.com": expected an http or https URL`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestProvisioningAlertDefaults(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.ProvisioningAlertDelay(), gc.Equals, 15*time.Minute)
+	c.Assert(cfg.ProvisioningAlertURL(), gc.Equals, "")
+}
+
+func (s *ConfigSuite) TestProvisioningAlert(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"provisioning-alert-delay": 30,
+		"provisioning-alert-url":   "https://alerts.example.com/juju",
+	})
+	c.Assert(cfg.ProvisioningAlertDelay(), gc.Equals, 30*time.Minute)
+	c.Assert(cfg.ProvisioningAlertURL(), gc.Equals, "https://alerts.example.com/juju")
+}
+
+func (s *ConfigSuite) TestRemoveEmptyMachines(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.RemoveEmptyMachines(), jc.IsFalse)
+	cfg = newTestConfig(c, testing.Attrs{"remove-empty-machines": true})
+	c.Assert(cfg.RemoveEmptyMachines(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestProvisioningAlertInvalid(c *gc.C) {
+	s.addJujuFiles(c)
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"provisioning-alert-delay": -1},
+		err:   `provisioning-alert-delay: expected a non-negative number, got -1`,
+	}, {
+		attrs: testing.Attrs{"provisioning-alert-url": "alerts.example.com"},
+		err:   `invalid provisioning-alert-url "alerts.example.com": expected an http or https URL`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		attrs := testing.Attrs{
+			"type": "my-type",
+			"name": "my-name",
+		}.Merge(test.attrs)
+		_, err := config.New(config.UseDefaults, attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ConfigSuite) TestLogLimitsDefault(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.MaxLogsAge(), gc.Equals, 72*time.Hour)
+	c.Assert(cfg.MaxLogsSizeMB(), gc.Equals, 4096)
+	c.Assert(cfg.LogSinkRateLimit(), gc.Equals, 1000)
+}
+
+func (s *ConfigSuite) TestLogLimits(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, testing.Attrs{
+		"max-logs-age":       24,
+		"max-logs-size":      "512",
+		"logsink-rate-limit": 0,
+	})
+	c.Assert(cfg.MaxLogsAge(), gc.Equals, 24*time.Hour)
+	c.Assert(cfg.MaxLogsSizeMB(), gc.Equals, 512)
+	c.Assert(cfg.LogSinkRateLimit(), gc.Equals, 0)
+}
+
+func (s *ConfigSuite) TestTxnPruneWindow(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	c.Assert(cfg.TxnPruneWindow(), gc.Equals, 24*time.Hour)
+
+	cfg = newTestConfig(c, testing.Attrs{"txn-prune-window": "6"})
+	c.Assert(cfg.TxnPruneWindow(), gc.Equals, 6*time.Hour)
+}
+
+func (s *ConfigSuite) TestMongoSessionSettings(c *gc.C) {
+	s.addJujuFiles(c)
+	cfg := newTestConfig(c, nil)
+	_, ok := cfg.MongoPoolLimit()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.MongoSocketTimeout()
+	c.Assert(ok, jc.IsFalse)
+	_, ok = cfg.MongoSyncTimeout()
+	c.Assert(ok, jc.IsFalse)
+
+	cfg = newTestConfig(c, testing.Attrs{
+		"mongo-pool-limit":     100,
+		"mongo-socket-timeout": "30",
+		"mongo-sync-timeout":   120,
+	})
+	limit, ok := cfg.MongoPoolLimit()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(limit, gc.Equals, 100)
+	timeout, ok := cfg.MongoSocketTimeout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(timeout, gc.Equals, 30*time.Second)
+	timeout, ok = cfg.MongoSyncTimeout()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(timeout, gc.Equals, 2*time.Minute)
+}
+
 func (s *ConfigSuite) TestLoggingConfigFromEnvironment(c *gc.C) {
 	s.addJujuFiles(c)
 	s.PatchEnvironment(osenv.JujuLoggingConfigEnvKey, "<root>=INFO")
@@ -4,7 +4,11 @@
 package config
 
 var (
-	DistroLtsSeries = &distroLtsSeries
+	DistroLtsSeries     = &distroLtsSeries
+	ProviderSchemas     = &providerSchemas
+	ImmutableAttributes = &immutableAttributes
+	CoreSchema          = coreSchema
+	Fields              = fields
 )
 
 func ResetCachedLtsSeries() {
@@ -0,0 +1,595 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/schema"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/version"
+)
+
+// FieldType describes the type of the value of a configuration
+// attribute.
+type FieldType string
+
+const (
+	Tstring FieldType = "string"
+	Tbool   FieldType = "bool"
+	Tint    FieldType = "int"
+)
+
+// checker returns the schema checker used to coerce values
+// of the field type.
+func (t FieldType) checker() (schema.Checker, error) {
+	switch t {
+	case Tstring:
+		return schema.String(), nil
+	case Tbool:
+		return schema.Bool(), nil
+	case Tint:
+		return schema.ForceInt(), nil
+	}
+	return nil, errors.NotValidf("field type %q", t)
+}
+
+// Attr describes a single environment configuration attribute.
+type Attr struct {
+	// Description holds a human readable description of the attribute.
+	Description string `yaml:"description" json:"description"`
+
+	// Type holds the type of the attribute value.
+	Type FieldType `yaml:"type" json:"type"`
+
+	// Immutable specifies whether the attribute may not be changed
+	// once the environment has been created.
+	Immutable bool `yaml:"immutable,omitempty" json:"immutable,omitempty"`
+
+	// Secret specifies whether the attribute holds a value, such as
+	// a credential, that should not be shown to everyone. Secret
+	// values are only revealed to environment administrators, and
+	// are redacted from logs.
+	Secret bool `yaml:"secret,omitempty" json:"secret,omitempty"`
+
+	// Values holds the permitted values of the attribute. If it is
+	// empty, any value of the right type is permitted.
+	Values []interface{} `yaml:"values,omitempty" json:"values,omitempty"`
+
+	// Validate, if not nil, is called to check a value of the
+	// attribute after it has been coerced to the attribute's type.
+	Validate func(value interface{}) error `yaml:"-" json:"-"`
+}
+
+// Schema holds the attributes known for an environment configuration,
+// keyed by attribute name.
+type Schema map[string]Attr
+
+// coerce coerces the given value of the named attribute to the
+// attribute's type and checks that it is valid.
+func (attr Attr) coerce(name string, value interface{}) (interface{}, error) {
+	invalid := func(reason error) error {
+		return &InvalidConfigValueError{
+			Key:    name,
+			Value:  fmt.Sprint(value),
+			Reason: reason,
+		}
+	}
+	checker, err := attr.Type.checker()
+	if err != nil {
+		return nil, errors.Annotatef(err, "attribute %q", name)
+	}
+	v, err := checker.Coerce(value, nil)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	if len(attr.Values) > 0 && !attr.permits(v) {
+		return nil, invalid(fmt.Errorf("expected one of %v", attr.Values))
+	}
+	if attr.Validate != nil {
+		if err := attr.Validate(v); err != nil {
+			return nil, invalid(err)
+		}
+	}
+	return v, nil
+}
+
+func (attr Attr) permits(value interface{}) bool {
+	for _, v := range attr.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAttrs checks that each of the given attributes that is
+// known to the schema has a valid value of the right type. Attributes
+// that are not in the schema are left for the provider to validate.
+// The immutability of attributes is checked by Validate, which has
+// access to the previous configuration.
+func (s Schema) ValidateAttrs(attrs map[string]interface{}) error {
+	for _, name := range sortedKeys(attrs) {
+		attr, ok := s[name]
+		if !ok {
+			continue
+		}
+		if _, err := attr.coerce(name, attrs[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// immutable returns the names of the immutable attributes in the
+// schema, sorted.
+func (s Schema) immutable() []string {
+	var names []string
+	for name, attr := range s {
+		if attr.Immutable {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RedactedValue is shown in place of the values of secret attributes
+// where they must not be revealed.
+const RedactedValue = "<redacted>"
+
+// SecretAttrs returns the names of the secret attributes in the
+// schema, sorted.
+func (s Schema) SecretAttrs() []string {
+	var names []string
+	for name, attr := range s {
+		if attr.Secret {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redact returns a copy of attrs with the value of each secret
+// attribute replaced by RedactedValue. It is intended for attributes
+// that are to be logged.
+func (s Schema) Redact(attrs map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		if s[name].Secret {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// DebugAttrs logs the configuration attributes at debug level after
+// the formatted message. The values of the secret attributes of the
+// given provider type are redacted.
+func DebugAttrs(logger loggo.Logger, providerType string, attrs map[string]interface{}, format string, args ...interface{}) {
+	if !logger.IsDebugEnabled() {
+		return
+	}
+	logger.Debugf("%s: %#v", fmt.Sprintf(format, args...), SchemaFor(providerType).Redact(attrs))
+}
+
+func sortedKeys(attrs map[string]interface{}) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var providerSchemas = make(map[string]Schema)
+
+// RegisterProviderSchema records the schema of the attributes that
+// are specific to the given provider type. It is intended to be
+// called from the init function of the provider's package, alongside
+// environs.RegisterProvider.
+func RegisterProviderSchema(providerType string, s Schema) {
+	if _, ok := providerSchemas[providerType]; ok {
+		panic(fmt.Errorf("juju: duplicate schema for provider type %q", providerType))
+	}
+	for name := range s {
+		if _, ok := coreSchema[name]; ok {
+			panic(fmt.Errorf("juju: provider type %q redefines attribute %q", providerType, name))
+		}
+	}
+	providerSchemas[providerType] = s
+}
+
+// SchemaFor returns the schema of the configuration of environments
+// with the given provider type: the attributes common to all
+// environments, and any registered for the provider type.
+func SchemaFor(providerType string) Schema {
+	s := make(Schema)
+	for name, attr := range coreSchema {
+		s[name] = attr
+	}
+	for name, attr := range providerSchemas[providerType] {
+		s[name] = attr
+	}
+	return s
+}
+
+// CredentialAttrs returns the names of the attributes that hold the
+// credentials of the given provider type: the secret attributes
+// described by the provider's own schema, sorted.
+func CredentialAttrs(providerType string) []string {
+	return providerSchemas[providerType].SecretAttrs()
+}
+
+func validatePort(value interface{}) error {
+	if port := value.(int); port <= 0 || port > 65535 {
+		return errors.New("port out of range")
+	}
+	return nil
+}
+
+// coreSchema describes the attributes that are common to all
+// environments. It must describe every attribute in fields.
+var coreSchema = Schema{
+	"type": {
+		Description: "Type of the environment's provider",
+		Type:        Tstring,
+		Immutable:   true,
+	},
+	"name": {
+		Description: "The name of the environment",
+		Type:        Tstring,
+		Immutable:   true,
+		Validate: func(value interface{}) error {
+			if strings.ContainsAny(value.(string), "/\\") {
+				return errors.New("contains unsafe characters")
+			}
+			return nil
+		},
+	},
+	"uuid": {
+		Description: "The UUID of the environment",
+		Type:        Tstring,
+		Immutable:   true,
+		Validate: func(value interface{}) error {
+			if !utils.IsValidUUIDString(value.(string)) {
+				return errors.New("not a valid UUID")
+			}
+			return nil
+		},
+	},
+	"default-series": {
+		Description: "The default series of Ubuntu to use for deploying charms",
+		Type:        Tstring,
+	},
+	AgentMetadataURLKey: {
+		Description: "URL of private stream of agent binaries",
+		Type:        Tstring,
+	},
+	"image-metadata-url": {
+		Description: "URL of private stream of image metadata",
+		Type:        Tstring,
+	},
+	"image-stream": {
+		Description: "The simplestreams stream used to identify which image ids to search when starting an instance",
+		Type:        Tstring,
+	},
+	AgentStreamKey: {
+		Description: "Version of agent binaries to use for Juju agents",
+		Type:        Tstring,
+	},
+	"authorized-keys": {
+		Description: "Any authorized SSH public keys for the environment, as found in a ~/.ssh/authorized_keys file",
+		Type:        Tstring,
+	},
+	"authorized-keys-path": {
+		Description: "Path to file containing SSH authorized keys",
+		Type:        Tstring,
+	},
+	"firewall-mode": {
+		Description: "How ports are opened on the machines of the environment",
+		Type:        Tstring,
+		Immutable:   true,
+		Values:      []interface{}{FwInstance, FwGlobal, FwNone},
+	},
+	"agent-version": {
+		Description: "The desired Juju agent version to use",
+		Type:        Tstring,
+		Validate: func(value interface{}) error {
+			_, err := version.Parse(value.(string))
+			return err
+		},
+	},
+	"development": {
+		Description: "Whether the environment is in development mode",
+		Type:        Tbool,
+	},
+	"admin-secret": {
+		Description: "The password for the administrator user",
+		Type:        Tstring,
+		Secret:      true,
+	},
+	"ca-cert": {
+		Description: "The certificate of the CA that signed the state server certificate, in PEM format",
+		Type:        Tstring,
+	},
+	"ca-cert-path": {
+		Description: "Path to file containing CA certificate",
+		Type:        Tstring,
+	},
+	"ca-private-key": {
+		Description: "The private key of the CA that signed the state server certificate, in PEM format",
+		Type:        Tstring,
+		Secret:      true,
+	},
+	"ca-private-key-path": {
+		Description: "Path to file containing CA private key",
+		Type:        Tstring,
+	},
+	"ssl-hostname-verification": {
+		Description: "Whether SSL hostname verification is enabled",
+		Type:        Tbool,
+	},
+	"state-port": {
+		Description: "Port for the API server to listen on for mongo connections",
+		Type:        Tint,
+		Immutable:   true,
+		Validate:    validatePort,
+	},
+	"api-port": {
+		Description: "The TCP port for the API servers to listen on",
+		Type:        Tint,
+		Immutable:   true,
+		Validate:    validatePort,
+	},
+	"syslog-port": {
+		Description: "Port for the syslog UDP/TCP listener to listen on",
+		Type:        Tint,
+		Immutable:   true,
+		Validate:    validatePort,
+	},
+	"rsyslog-ca-cert": {
+		Description: "The certificate of the CA that signed the rsyslog certificate, in PEM format",
+		Type:        Tstring,
+	},
+	"rsyslog-ca-key": {
+		Description: "The private key of the CA that signed the rsyslog certificate, in PEM format",
+		Type:        Tstring,
+		Secret:      true,
+	},
+	"logging-config": {
+		Description: "The configuration string to use when configuring Juju agent logging",
+		Type:        Tstring,
+		Validate: func(value interface{}) error {
+			_, err := loggo.ParseConfigurationString(value.(string))
+			return err
+		},
+	},
+	LoggingFormatKey: {
+		Description: "The format of Juju agent log messages: one of text or json",
+		Type:        Tstring,
+		Values:      []interface{}{LoggingFormatText, LoggingFormatJSON},
+	},
+	MaxLogsAgeKey: {
+		Description: "The maximum age, in hours, of the log records kept by the state server (default 72)",
+		Type:        Tint,
+	},
+	MaxLogsSizeKey: {
+		Description: "The size, in megabytes, beyond which the state server removes the oldest log records of the environment with the most logs (default 4096)",
+		Type:        Tint,
+	},
+	LogSinkRateLimitKey: {
+		Description: "The number of log messages per second that each agent may send to the state server, or 0 for no limit (default 1000)",
+		Type:        Tint,
+	},
+	TxnPruneWindowKey: {
+		Description: "The age, in hours, beyond which the state server removes completed transactions from its database (default 24)",
+		Type:        Tint,
+	},
+	MongoPoolLimitKey: {
+		Description: "The maximum number of sockets that each state server MongoDB session may hold open to each server (default 4096)",
+		Type:        Tint,
+	},
+	MongoSocketTimeoutKey: {
+		Description: "The time, in seconds, that a state server waits for a non-responding MongoDB socket before closing it (default 21)",
+		Type:        Tint,
+	},
+	MongoSyncTimeoutKey: {
+		Description: "The time, in seconds, that state server MongoDB operations wait for a suitable server to become available (default 60)",
+		Type:        Tint,
+	},
+	UnitPlacementKey: {
+		Description: "How machines are chosen for units added without a placement directive: one of spread, pack or zone-balanced",
+		Type:        Tstring,
+		Values:      []interface{}{UnitPlacementSpread, UnitPlacementPack, UnitPlacementZoneBalanced},
+	},
+	ProvisionerHarvestModeKey: {
+		Description: "What to do with unknown machines: one of all, none, unknown or destroyed",
+		Type:        Tstring,
+		Validate: func(value interface{}) error {
+			_, err := ParseHarvestMode(value.(string))
+			return err
+		},
+	},
+	HttpProxyKey: {
+		Description: "The HTTP proxy value to configure on instances, in the HTTP_PROXY environment variable",
+		Type:        Tstring,
+	},
+	HttpsProxyKey: {
+		Description: "The HTTPS proxy value to configure on instances, in the HTTPS_PROXY environment variable",
+		Type:        Tstring,
+	},
+	FtpProxyKey: {
+		Description: "The FTP proxy value to configure on instances, in the FTP_PROXY environment variable",
+		Type:        Tstring,
+	},
+	NoProxyKey: {
+		Description: "List of domain addresses not to be proxied (comma-separated)",
+		Type:        Tstring,
+	},
+	CharmStoreURLKey: {
+		Description: "The URL of the charm store from which charms are fetched (default the public charm store)",
+		Type:        Tstring,
+	},
+	CharmStoreAuthKey: {
+		Description: "JSON-encoded macaroons authorizing the environment to fetch charms from a private charm store",
+		Type:        Tstring,
+		Secret:      true,
+	},
+	AptHttpProxyKey: {
+		Description: "The APT HTTP proxy for the environment",
+		Type:        Tstring,
+	},
+	AptHttpsProxyKey: {
+		Description: "The APT HTTPS proxy for the environment",
+		Type:        Tstring,
+	},
+	AptFtpProxyKey: {
+		Description: "The APT FTP proxy for the environment",
+		Type:        Tstring,
+	},
+	"apt-mirror": {
+		Description: "The APT mirror for the environment",
+		Type:        Tstring,
+	},
+	"bootstrap-timeout": {
+		Description: "The amount of time to wait contacting a state server, in seconds",
+		Type:        Tint,
+		Immutable:   true,
+	},
+	"bootstrap-retry-delay": {
+		Description: "The amount of time between attempts to connect to an address, in seconds",
+		Type:        Tint,
+		Immutable:   true,
+	},
+	"bootstrap-addresses-delay": {
+		Description: "The amount of time between refreshing the addresses, in seconds",
+		Type:        Tint,
+		Immutable:   true,
+	},
+	"test-mode": {
+		Description: "Whether the environment is intended for testing",
+		Type:        Tbool,
+	},
+	"proxy-ssh": {
+		Description: "Whether SSH commands should be proxied through the API server",
+		Type:        Tbool,
+	},
+	LxcClone: {
+		Description: "Whether to use lxc-clone to create new LXC containers",
+		Type:        Tbool,
+		Immutable:   true,
+	},
+	"lxc-clone-aufs": {
+		Description: "Whether the LXC provisioner should create an LXC clone using AUFS if available",
+		Type:        Tbool,
+		Immutable:   true,
+	},
+	"prefer-ipv6": {
+		Description: "Whether to prefer IPv6 over IPv4 addresses for API endpoints and machines",
+		Type:        Tbool,
+		Immutable:   true,
+	},
+	"enable-os-refresh-update": {
+		Description: "Whether newly provisioned instances should run their respective OS's update capability",
+		Type:        Tbool,
+	},
+	"enable-os-upgrade": {
+		Description: "Whether newly provisioned instances should run their respective OS's upgrade capability",
+		Type:        Tbool,
+	},
+	"disable-network-management": {
+		Description: "Whether the provider should control networks (on MAAS environments, set to true for MAAS to control networks)",
+		Type:        Tbool,
+	},
+	SetNumaControlPolicyKey: {
+		Description: "Tune Juju controller to work with NUMA if present",
+		Type:        Tbool,
+	},
+	PreventDestroyEnvironmentKey: {
+		Description: "Whether the environment is blocked from being destroyed",
+		Type:        Tbool,
+	},
+	PreventRemoveObjectKey: {
+		Description: "Whether machines, services, units and relations are blocked from being removed",
+		Type:        Tbool,
+	},
+	PreventAllChangesKey: {
+		Description: "Whether all changes to the environment are blocked",
+		Type:        Tbool,
+	},
+	StorageDefaultBlockSourceKey: {
+		Description: "The default block storage source for the environment",
+		Type:        Tstring,
+	},
+	AllowLXCLoopMounts: {
+		Description: "Whether loop devices are allowed to be mounted inside LXC containers",
+		Type:        Tbool,
+	},
+	ContainerImageStreamKey: {
+		Description: "The simplestreams stream from which container images are fetched (defaults to image-stream)",
+		Type:        Tstring,
+	},
+	ContainerImageSourceURLKey: {
+		Description: "The base URL of a mirror of http://cloud-images.ubuntu.com from which container images are fetched",
+		Type:        Tstring,
+	},
+	ContainerImageProxyKey: {
+		Description: "Whether machines fetch container images through the image cache hosted by the state servers",
+		Type:        Tbool,
+	},
+	HookEnvironmentKey: {
+		Description: "Whitespace-separated NAME=value pairs set as environment variables in every hook context",
+		Type:        Tstring,
+	},
+	DNSRegistrarKey: {
+		Description: `How DNS names are registered for services ("provider", "webhook" or empty to disable)`,
+		Type:        Tstring,
+	},
+	DNSDomainKey: {
+		Description: "The domain under which service DNS names are registered",
+		Type:        Tstring,
+	},
+	DNSWebhookURLKey: {
+		Description: "The URL to which DNS registrations are posted when dns-registrar is webhook",
+		Type:        Tstring,
+	},
+	ProvisioningAlertDelayKey: {
+		Description: "The time, in minutes, that a machine stays in a provisioning error before it is reported (default 15)",
+		Type:        Tint,
+	},
+	ProvisioningAlertURLKey: {
+		Description: "The URL to which reports of machines stuck in provisioning errors are posted",
+		Type:        Tstring,
+	},
+	RemoveEmptyMachinesKey: {
+		Description: "Whether machines left empty by the removal of units and their containers are destroyed, unless manually provisioned",
+		Type:        Tbool,
+	},
+
+	// Deprecated attributes.
+	ToolsMetadataURLKey: {
+		Description: "Deprecated: use " + AgentMetadataURLKey,
+		Type:        Tstring,
+	},
+	LxcUseClone: {
+		Description: "Deprecated: use " + LxcClone,
+		Type:        Tbool,
+	},
+	ProvisionerSafeModeKey: {
+		Description: "Deprecated: use " + ProvisionerHarvestModeKey,
+		Type:        Tbool,
+	},
+	ToolsStreamKey: {
+		Description: "Deprecated: use " + AgentStreamKey,
+		Type:        Tstring,
+	},
+}
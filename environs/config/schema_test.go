@@ -0,0 +1,242 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config_test
+
+import (
+	"errors"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/testing"
+)
+
+type SchemaSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SchemaSuite{})
+
+func (s *SchemaSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.PatchValue(config.ProviderSchemas, make(map[string]config.Schema))
+}
+
+func (s *SchemaSuite) TestCoreSchemaDescribesAllFields(c *gc.C) {
+	for name := range config.Fields {
+		attr, ok := config.CoreSchema[name]
+		if c.Check(ok, jc.IsTrue, gc.Commentf("attribute %q", name)) {
+			c.Check(attr.Description, gc.Not(gc.Equals), "", gc.Commentf("attribute %q", name))
+		}
+	}
+	for name := range config.CoreSchema {
+		_, ok := config.Fields[name]
+		c.Check(ok, jc.IsTrue, gc.Commentf("attribute %q", name))
+	}
+}
+
+func (s *SchemaSuite) TestImmutableAttributes(c *gc.C) {
+	c.Assert(*config.ImmutableAttributes, jc.DeepEquals, []string{
+		"api-port",
+		"bootstrap-addresses-delay",
+		"bootstrap-retry-delay",
+		"bootstrap-timeout",
+		"firewall-mode",
+		"lxc-clone",
+		"lxc-clone-aufs",
+		"name",
+		"prefer-ipv6",
+		"state-port",
+		"syslog-port",
+		"type",
+		"uuid",
+	})
+}
+
+func (s *SchemaSuite) TestSecretAttributes(c *gc.C) {
+	var secrets []string
+	for name, attr := range config.CoreSchema {
+		if attr.Secret {
+			secrets = append(secrets, name)
+		}
+	}
+	c.Assert(secrets, jc.SameContents, []string{
+		"admin-secret",
+		"ca-private-key",
+		"rsyslog-ca-key",
+	})
+}
+
+var validateAttrsTests = []struct {
+	about string
+	attrs map[string]interface{}
+	err   string
+}{{
+	about: "valid values",
+	attrs: map[string]interface{}{
+		"default-series": "trusty",
+		"development":    true,
+		"state-port":     1234,
+		"firewall-mode":  config.FwGlobal,
+		"logging-config": "juju=INFO",
+		"unknown-attr":   42,
+	},
+}, {
+	about: "wrong type",
+	attrs: map[string]interface{}{"test-mode": "sometimes"},
+	err:   `invalid config value for test-mode: "sometimes": expected bool, got string\("sometimes"\)`,
+}, {
+	about: "missing value",
+	attrs: map[string]interface{}{"default-series": nil},
+	err:   `invalid config value for default-series: "<nil>": expected string, got nothing`,
+}, {
+	about: "value not permitted",
+	attrs: map[string]interface{}{"firewall-mode": "sometimes"},
+	err:   `invalid config value for firewall-mode: "sometimes": expected one of \[instance global none\]`,
+}, {
+	about: "port out of range",
+	attrs: map[string]interface{}{"api-port": 123456},
+	err:   `invalid config value for api-port: "123456": port out of range`,
+}, {
+	about: "invalid agent version",
+	attrs: map[string]interface{}{"agent-version": "1.2.x"},
+	err:   `invalid config value for agent-version: "1.2.x": invalid version "1.2.x"`,
+}, {
+	about: "invalid harvest mode",
+	attrs: map[string]interface{}{config.ProvisionerHarvestModeKey: "some"},
+	err:   `invalid config value for provisioner-harvest-mode: "some": unknown harvesting method: some`,
+}, {
+	about: "unsafe name",
+	attrs: map[string]interface{}{"name": "foo/bar"},
+	err:   `invalid config value for name: "foo/bar": contains unsafe characters`,
+}, {
+	about: "invalid uuid",
+	attrs: map[string]interface{}{"uuid": "not-a-uuid"},
+	err:   `invalid config value for uuid: "not-a-uuid": not a valid UUID`,
+}, {
+	about: "first invalid attribute reported",
+	attrs: map[string]interface{}{"test-mode": 1, "development": 2},
+	err:   `invalid config value for development: "2": .*`,
+}}
+
+func (s *SchemaSuite) TestValidateAttrs(c *gc.C) {
+	schema := config.SchemaFor("dummy")
+	for i, test := range validateAttrsTests {
+		c.Logf("test %d: %s", i, test.about)
+		err := schema.ValidateAttrs(test.attrs)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+			continue
+		}
+		c.Check(err, gc.ErrorMatches, test.err)
+		_, ok := err.(*config.InvalidConfigValueError)
+		c.Check(ok, jc.IsTrue)
+	}
+}
+
+func (s *SchemaSuite) TestProviderSchema(c *gc.C) {
+	config.RegisterProviderSchema("foo", config.Schema{
+		"region": {
+			Description: "The region",
+			Type:        config.Tstring,
+			Immutable:   true,
+			Validate: func(value interface{}) error {
+				if value != "somewhere" {
+					return errors.New("unknown region")
+				}
+				return nil
+			},
+		},
+	})
+
+	schema := config.SchemaFor("foo")
+	c.Assert(schema["region"].Description, gc.Equals, "The region")
+	c.Assert(schema["name"].Type, gc.Equals, config.Tstring)
+	err := schema.ValidateAttrs(map[string]interface{}{"region": "somewhere"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = schema.ValidateAttrs(map[string]interface{}{"region": "elsewhere"})
+	c.Assert(err, gc.ErrorMatches, `invalid config value for region: "elsewhere": unknown region`)
+
+	// Other provider types do not see the attribute.
+	_, ok := config.SchemaFor("bar")["region"]
+	c.Assert(ok, jc.IsFalse)
+	err = config.SchemaFor("bar").ValidateAttrs(map[string]interface{}{"region": "elsewhere"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SchemaSuite) TestRegisterProviderSchemaTwice(c *gc.C) {
+	config.RegisterProviderSchema("foo", config.Schema{})
+	c.Assert(func() {
+		config.RegisterProviderSchema("foo", config.Schema{})
+	}, gc.PanicMatches, `juju: duplicate schema for provider type "foo"`)
+}
+
+func (s *SchemaSuite) TestRegisterProviderSchemaRedefinesCore(c *gc.C) {
+	c.Assert(func() {
+		config.RegisterProviderSchema("foo", config.Schema{
+			"name": {Type: config.Tstring},
+		})
+	}, gc.PanicMatches, `juju: provider type "foo" redefines attribute "name"`)
+}
+
+func (s *SchemaSuite) TestSecretAttrs(c *gc.C) {
+	config.RegisterProviderSchema("foo", config.Schema{
+		"password": {Type: config.Tstring, Secret: true},
+		"username": {Type: config.Tstring},
+	})
+	c.Assert(config.SchemaFor("foo").SecretAttrs(), jc.DeepEquals, []string{
+		"admin-secret",
+		"ca-private-key",
+		"password",
+		"rsyslog-ca-key",
+	})
+}
+
+func (s *SchemaSuite) TestRedact(c *gc.C) {
+	attrs := map[string]interface{}{
+		"name":         "foo",
+		"admin-secret": "sekrit",
+		"unknown":      "value",
+	}
+	redacted := config.CoreSchema.Redact(attrs)
+	c.Assert(redacted, jc.DeepEquals, map[string]interface{}{
+		"name":         "foo",
+		"admin-secret": config.RedactedValue,
+		"unknown":      "value",
+	})
+	// The original attributes are left alone.
+	c.Assert(attrs["admin-secret"], gc.Equals, "sekrit")
+}
+
+func (s *SchemaSuite) TestDebugAttrsRedactsSecrets(c *gc.C) {
+	config.RegisterProviderSchema("foo", config.Schema{
+		"password": {Type: config.Tstring, Secret: true},
+	})
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("schema-tester", &tw, loggo.DEBUG), gc.IsNil)
+	defer loggo.RemoveWriter("schema-tester")
+	logger := loggo.GetLogger("juju.environs.config.schema-tester")
+	logger.SetLogLevel(loggo.DEBUG)
+
+	config.DebugAttrs(logger, "foo", map[string]interface{}{
+		"password":       "hunter2",
+		"ca-private-key": "key",
+	}, "found %d attributes", 2)
+	c.Assert(tw.Log(), jc.LogMatches, []jc.SimpleMessage{{
+		loggo.DEBUG,
+		`found 2 attributes: map\[string\]interface {}{"ca-private-key":"<redacted>", "password":"<redacted>"}`,
+	}})
+}
+
+func (s *SchemaSuite) TestCredentialAttrs(c *gc.C) {
+	config.RegisterProviderSchema("foo", config.Schema{
+		"password": {Type: config.Tstring, Secret: true},
+		"username": {Type: config.Tstring, Secret: true},
+		"region":   {Type: config.Tstring},
+	})
+	c.Assert(config.CredentialAttrs("foo"), jc.DeepEquals, []string{"password", "username"})
+	c.Assert(config.CredentialAttrs("bar"), gc.HasLen, 0)
+}
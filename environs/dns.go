@@ -0,0 +1,17 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+// DNSRegistrar is implemented by environments whose provider offers a
+// native DNS service (such as Route53 or Designate) in which juju can
+// register names for the environment's services.
+type DNSRegistrar interface {
+	// RegisterDNS creates or replaces the records for the given fully
+	// qualified name, so that it resolves to the given addresses.
+	RegisterDNS(name string, addresses []string) error
+
+	// UnregisterDNS removes the records for the given fully qualified
+	// name. It is not an error if there are none.
+	UnregisterDNS(name string) error
+}
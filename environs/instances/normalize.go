@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instances
+
+import (
+	"fmt"
+
+	"github.com/juju/juju/constraints"
+)
+
+// Normalization describes how a set of constraints maps onto one of
+// a cloud's instance types.
+type Normalization struct {
+	// InstanceType holds the name of the instance type that would be
+	// chosen to satisfy the constraints.
+	InstanceType string
+
+	// Hardware holds the abstract constraints that the instance type
+	// satisfies, so that it can be compared with those of instance
+	// types in other clouds.
+	Hardware constraints.Value
+
+	// Rationale explains why the instance type was chosen.
+	Rationale string
+}
+
+// Normalize returns the instance type from allInstanceTypes that would
+// be chosen to satisfy cons in region, following the same rules as
+// MatchingInstanceTypes: the cheapest of the matching instance types is
+// chosen.
+func Normalize(allInstanceTypes []InstanceType, region string, cons constraints.Value) (Normalization, error) {
+	itypes, err := MatchingInstanceTypes(allInstanceTypes, region, cons)
+	if err != nil {
+		return Normalization{}, err
+	}
+	chosen := itypes[0]
+	var rationale string
+	matching := "default constraints"
+	if !constraints.IsEmpty(&cons) {
+		matching = fmt.Sprintf("%q", cons.String())
+	}
+	switch {
+	case cons.HasInstanceType():
+		rationale = fmt.Sprintf("instance type %q requested", chosen.Name)
+	case len(itypes) == 1:
+		rationale = fmt.Sprintf("only instance type matching %s", matching)
+	default:
+		rationale = fmt.Sprintf("cheapest of %d instance types matching %s", len(itypes), matching)
+	}
+	return Normalization{
+		InstanceType: chosen.Name,
+		Hardware:     InstanceTypeConstraints(chosen),
+		Rationale:    rationale,
+	}, nil
+}
+
+// InstanceTypeConstraints returns the abstract constraints satisfied
+// by the given instance type.
+func InstanceTypeConstraints(itype InstanceType) constraints.Value {
+	cores, mem := itype.CpuCores, itype.Mem
+	cons := constraints.Value{
+		CpuCores: &cores,
+		Mem:      &mem,
+	}
+	if itype.CpuPower != nil {
+		power := *itype.CpuPower
+		cons.CpuPower = &power
+	}
+	if itype.RootDisk > 0 {
+		disk := itype.RootDisk
+		cons.RootDisk = &disk
+	}
+	if len(itype.Arches) == 1 {
+		arch := itype.Arches[0]
+		cons.Arch = &arch
+	}
+	return cons
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instances
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/testing"
+)
+
+type normalizeSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&normalizeSuite{})
+
+var normalizeTests = []struct {
+	cons      string
+	itype     string
+	hardware  string
+	rationale string
+}{{
+	cons:      "",
+	itype:     "m1.small",
+	hardware:  "cpu-cores=1 cpu-power=100 mem=1740M root-disk=8192M",
+	rationale: "cheapest of 8 instance types matching default constraints",
+}, {
+	cons:      "mem=4G",
+	itype:     "m1.large",
+	hardware:  "arch=amd64 cpu-cores=2 cpu-power=400 mem=7680M root-disk=32768M",
+	rationale: `cheapest of 5 instance types matching "mem=4096M"`,
+}, {
+	cons:      "cpu-power=500 arch=armhf",
+	itype:     "c1.medium",
+	hardware:  "arch=armhf cpu-cores=2 cpu-power=500 mem=1740M root-disk=8192M",
+	rationale: `only instance type matching "arch=armhf cpu-power=500"`,
+}, {
+	cons:      "instance-type=m1.xlarge",
+	itype:     "m1.xlarge",
+	hardware:  "arch=amd64 cpu-cores=4 cpu-power=800 mem=15360M",
+	rationale: `instance type "m1.xlarge" requested`,
+}}
+
+func (s *normalizeSuite) TestNormalize(c *gc.C) {
+	for i, test := range normalizeTests {
+		c.Logf("test %d: %q", i, test.cons)
+		normalized, err := Normalize(instanceTypes, "test", constraints.MustParse(test.cons))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(normalized, jc.DeepEquals, Normalization{
+			InstanceType: test.itype,
+			Hardware:     constraints.MustParse(test.hardware),
+			Rationale:    test.rationale,
+		})
+	}
+}
+
+func (s *normalizeSuite) TestNormalizeNoMatch(c *gc.C) {
+	_, err := Normalize(instanceTypes, "test", constraints.MustParse("cpu-cores=9000"))
+	c.Assert(err, gc.ErrorMatches, `no instance types in test matching constraints "cpu-cores=9000"`)
+}
+
+func (s *normalizeSuite) TestInstanceTypeConstraints(c *gc.C) {
+	cons := InstanceTypeConstraints(InstanceType{
+		Name:     "it-1",
+		Arches:   []string{"amd64", "i386"},
+		CpuCores: 2,
+		Mem:      2048,
+	})
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("cpu-cores=2 mem=2048M"))
+}
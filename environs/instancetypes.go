@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs/instances"
+)
+
+// ConstraintsNormalizer is implemented by environments that choose
+// instance types to satisfy constraints. It allows the abstract
+// constraints used throughout juju to be related to the instance types
+// of each cloud.
+type ConstraintsNormalizer interface {
+	// NormalizeConstraints returns the instance type that the
+	// environment would choose to satisfy cons, applying the same
+	// defaults as StartInstance, along with the abstract constraints
+	// that instance type satisfies.
+	NormalizeConstraints(cons constraints.Value) (instances.Normalization, error)
+}
+
+// SupportsConstraintsNormalization is a convenience helper to check
+// if an environment can normalize constraints to instance types.
+func SupportsConstraintsNormalization(environ Environ) (ConstraintsNormalizer, bool) {
+	normalizer, ok := environ.(ConstraintsNormalizer)
+	return normalizer, ok
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+)
+
+// AdoptInstanceArgs holds the arguments for AdoptInstance.
+type AdoptInstanceArgs struct {
+	ProvisionMachineArgs
+
+	// InstanceId is the provider's id for the instance to adopt.
+	InstanceId instance.Id
+
+	// Addresses holds the addresses of the instance known to the
+	// provider. If empty, the address of the SSH host is used.
+	Addresses []network.Address
+
+	// AvailabilityZone holds the name of the availability zone of
+	// the instance, if known.
+	AvailabilityZone string
+}
+
+// AdoptInstance provisions a machine agent to an existing instance of
+// the environment's provider, via an SSH connection to the specified
+// host, as ProvisionMachine does. Unlike a manually provisioned
+// machine, the machine entered into state is associated with the
+// provider's instance, so the provider manages it from then on.
+func AdoptInstance(args AdoptInstanceArgs) (machineId string, err error) {
+	if args.InstanceId == "" {
+		return "", errors.New("no instance id specified")
+	}
+	return provisionMachine(args.ProvisionMachineArgs, func(machineParams *params.AddMachineParams) error {
+		uuid, err := utils.NewUUID()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		machineParams.InstanceId = args.InstanceId
+		machineParams.Nonce = fmt.Sprintf("%s:%s", args.InstanceId, uuid.String())
+		if len(args.Addresses) > 0 {
+			machineParams.Addrs = params.FromNetworkAddresses(args.Addresses)
+		}
+		if args.AvailabilityZone != "" {
+			zone := args.AvailabilityZone
+			machineParams.HardwareCharacteristics.AvailabilityZone = &zone
+		}
+		return nil
+	})
+}
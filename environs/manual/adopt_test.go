@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func (s *provisionerSuite) TestAdoptInstance(c *gc.C) {
+	defer fakeSSH{
+		Series:         coretesting.FakeDefaultSeries,
+		Arch:           "amd64",
+		InitUbuntuUser: true,
+	}.install(c).Restore()
+
+	machineId, err := manual.AdoptInstance(manual.AdoptInstanceArgs{
+		ProvisionMachineArgs: s.getArgs(c),
+		InstanceId:           "i-legacy",
+		Addresses:            network.NewAddresses("10.0.0.1"),
+		AvailabilityZone:     "zone-a",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceId, err := m.InstanceId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceId, gc.Equals, instance.Id("i-legacy"))
+	c.Assert(m.Addresses(), jc.DeepEquals, network.NewAddresses("10.0.0.1"))
+	hc, err := m.HardwareCharacteristics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*hc.Arch, gc.Equals, "amd64")
+	c.Assert(*hc.AvailabilityZone, gc.Equals, "zone-a")
+}
+
+func (s *provisionerSuite) TestAdoptInstanceNoId(c *gc.C) {
+	_, err := manual.AdoptInstance(manual.AdoptInstanceArgs{
+		ProvisionMachineArgs: s.getArgs(c),
+	})
+	c.Assert(err, gc.ErrorMatches, "no instance id specified")
+}
@@ -73,6 +73,13 @@ var ErrProvisioned = errors.New("machine is already provisioned")
 // On successful completion, this function will return the id of the state.Machine
 // that was entered into state.
 func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
+	return provisionMachine(args, nil)
+}
+
+// provisionMachine provisions a machine agent as ProvisionMachine
+// does. If amend is not nil, it is called with the parameters gathered
+// for the machine before the machine is entered into state.
+func provisionMachine(args ProvisionMachineArgs, amend func(*params.AddMachineParams) error) (machineId string, err error) {
 	defer func() {
 		if machineId != "" && err != nil {
 			logger.Errorf("provisioning failed, removing machine %v: %v", machineId, err)
@@ -97,6 +104,11 @@ func ProvisionMachine(args ProvisionMachineArgs) (machineId string, err error) {
 	if err != nil {
 		return "", err
 	}
+	if amend != nil {
+		if err := amend(machineParams); err != nil {
+			return "", err
+		}
+	}
 
 	// Inform Juju that the machine exists.
 	machineId, err = recordMachineInState(args.Client, *machineParams)
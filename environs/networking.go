@@ -56,6 +56,22 @@ func SupportsNetworking(environ Environ) (NetworkingEnviron, bool) {
 	return ne, ok
 }
 
+// SubnetDiscoverer is implemented by networking environments that can
+// list all of the subnets in which they start instances, so that the
+// subnets can be recorded in state when the environment is bootstrapped.
+type SubnetDiscoverer interface {
+	// AllSubnets returns information about all of the subnets in
+	// which the environment's instances may be started.
+	AllSubnets() ([]network.SubnetInfo, error)
+}
+
+// SupportsSubnetDiscovery is a convenience helper to check if an
+// environment can list all of its subnets.
+func SupportsSubnetDiscovery(environ Environ) (SubnetDiscoverer, bool) {
+	discoverer, ok := environ.(SubnetDiscoverer)
+	return discoverer, ok
+}
+
 // AddressAllocationEnabled is a shortcut for checking if the
 // AddressAllocation feature flag is enabled.
 func AddressAllocationEnabled() bool {
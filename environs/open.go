@@ -67,7 +67,8 @@ func ConfigForName(name string, store configstore.Storage) (*config.Config, Conf
 		if len(info.BootstrapConfig()) == 0 {
 			return nil, ConfigFromNowhere, EmptyConfig{fmt.Errorf("environment has no bootstrap configuration data")}
 		}
-		logger.Debugf("ConfigForName found bootstrap config %#v", info.BootstrapConfig())
+		providerType, _ := info.BootstrapConfig()["type"].(string)
+		config.DebugAttrs(logger, providerType, info.BootstrapConfig(), "ConfigForName found bootstrap config")
 		cfg, err := config.New(config.NoDefaults, info.BootstrapConfig())
 		return cfg, ConfigFromInfo, err
 	} else if !errors.IsNotFound(err) {
@@ -113,3 +113,13 @@ func APIInfo(env Environ) (*api.Info, error) {
 	apiInfo := &api.Info{Addrs: apiAddrs, CACert: cert, EnvironTag: envTag}
 	return apiInfo, nil
 }
+
+// CheckCredentials makes a request to the provider API using the
+// environ's credentials, and returns an error if the provider rejects
+// them.
+func CheckCredentials(env Environ) error {
+	if _, err := env.AllInstances(); err != nil && err != ErrNoInstances {
+		return errors.Annotate(err, "cannot make provider request")
+	}
+	return nil
+}
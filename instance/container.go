@@ -5,6 +5,9 @@ package instance
 
 import (
 	"fmt"
+	"strings"
+
+	"github.com/juju/names"
 )
 
 type ContainerType string
@@ -41,3 +44,34 @@ func ParseContainerType(ctype string) (ContainerType, error) {
 	}
 	return "", fmt.Errorf("invalid container type %q", ctype)
 }
+
+// CanHostContainer reports whether a new container of type ctype may
+// be created inside a container of type hostType. Only LXC containers
+// may be nested, inside KVM containers.
+func CanHostContainer(hostType, ctype ContainerType) bool {
+	return hostType == KVM && ctype == LXC
+}
+
+// ParseNestedContainer parses the directive of a placement in the
+// scope of the container type ctype, when that directive describes a
+// new container to host it rather than an existing machine. For
+// example, the directive "kvm:1" of the placement "lxc:kvm:1" places
+// an LXC container inside a new KVM container on machine 1. The type
+// of the new host container and the ID of its machine are returned.
+func ParseNestedContainer(ctype ContainerType, directive string) (ContainerType, string, error) {
+	parts := strings.SplitN(directive, ":", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid nested container %q: expected container-type:machine-id", directive)
+	}
+	hostType, err := ParseContainerType(parts[0])
+	if err != nil {
+		return "", "", err
+	}
+	if !CanHostContainer(hostType, ctype) {
+		return "", "", fmt.Errorf("cannot create %s containers inside %s containers", ctype, hostType)
+	}
+	if !names.IsValidMachine(parts[1]) {
+		return "", "", fmt.Errorf("invalid nested container %q: expected container-type:machine-id", directive)
+	}
+	return hostType, parts[1], nil
+}
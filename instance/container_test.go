@@ -52,3 +52,25 @@ func (s *InstanceSuite) TestParseContainerTypeOrNone(c *gc.C) {
 	ctype, err = instance.ParseContainerTypeOrNone("omg")
 	c.Assert(err, gc.ErrorMatches, `invalid container type "omg"`)
 }
+
+func (s *InstanceSuite) TestParseNestedContainer(c *gc.C) {
+	hostType, machineId, err := instance.ParseNestedContainer(instance.LXC, "kvm:1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hostType, gc.Equals, instance.KVM)
+	c.Assert(machineId, gc.Equals, "1")
+
+	_, _, err = instance.ParseNestedContainer(instance.LXC, "1")
+	c.Assert(err, gc.ErrorMatches, `invalid nested container "1": expected container-type:machine-id`)
+
+	_, _, err = instance.ParseNestedContainer(instance.LXC, "kvm:x")
+	c.Assert(err, gc.ErrorMatches, `invalid nested container "kvm:x": expected container-type:machine-id`)
+
+	_, _, err = instance.ParseNestedContainer(instance.LXC, "omg:1")
+	c.Assert(err, gc.ErrorMatches, `invalid container type "omg"`)
+
+	_, _, err = instance.ParseNestedContainer(instance.LXC, "lxc:1")
+	c.Assert(err, gc.ErrorMatches, `cannot create lxc containers inside lxc containers`)
+
+	_, _, err = instance.ParseNestedContainer(instance.KVM, "kvm:1")
+	c.Assert(err, gc.ErrorMatches, `cannot create kvm containers inside kvm containers`)
+}
@@ -31,7 +31,9 @@ type Placement struct {
 	// Directive is a scope-specific placement directive.
 	//
 	// For MachineScope or a container scope, this may be empty or
-	// the ID of an existing machine.
+	// the ID of an existing machine. For a container scope, it may
+	// also describe a new container to host the placed container;
+	// see ParseNestedContainer.
 	Directive string
 }
 
@@ -44,6 +46,14 @@ func isContainerType(s string) bool {
 	return err == nil
 }
 
+func isNestedContainer(scope, directive string) bool {
+	if scope == MachineScope {
+		return false
+	}
+	_, _, err := ParseNestedContainer(ContainerType(scope), directive)
+	return err == nil
+}
+
 // ParsePlacement attempts to parse the specified string and create a
 // corresponding Placement structure.
 //
@@ -61,7 +71,9 @@ func ParsePlacement(directive string) (*Placement, error) {
 		}
 		// Sanity check: machine/container scopes require a machine ID as the value.
 		if (scope == MachineScope || isContainerType(scope)) && !names.IsValidMachine(directive) {
-			return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
+			if !isNestedContainer(scope, directive) {
+				return nil, fmt.Errorf("invalid value %q for %q scope: expected machine-id", directive, scope)
+			}
 		}
 		return &Placement{Scope: scope, Directive: directive}, nil
 	}
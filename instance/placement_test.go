@@ -42,6 +42,16 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		arg:             "kvm:123",
 		expectScope:     string(instance.KVM),
 		expectDirective: "123",
+	}, {
+		arg:             "lxc:kvm:1",
+		expectScope:     string(instance.LXC),
+		expectDirective: "kvm:1",
+	}, {
+		arg: "lxc:lxc:1",
+		err: `invalid value "lxc:1" for "lxc" scope: expected machine-id`,
+	}, {
+		arg: "kvm:lxc:1",
+		err: `invalid value "lxc:1" for "kvm" scope: expected machine-id`,
 	}, {
 		arg:         "lxc",
 		expectScope: string(instance.LXC),
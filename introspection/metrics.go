@@ -0,0 +1,307 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection provides a registry of metrics describing the
+// internals of a running agent, and an HTTP handler that serves them
+// in the Prometheus text exposition format.
+package introspection
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.introspection")
+
+// MetricType identifies the kind of a metric family.
+type MetricType string
+
+const (
+	CounterType   MetricType = "counter"
+	GaugeType     MetricType = "gauge"
+	HistogramType MetricType = "histogram"
+)
+
+// Labels holds the label values that identify a sample within a
+// metric family.
+type Labels map[string]string
+
+// Sample holds a single value of a metric.
+type Sample struct {
+	// Suffix is appended to the family name when the sample is
+	// written, as used by histograms for their _bucket, _sum and
+	// _count series.
+	Suffix string
+	Labels Labels
+	Value  float64
+}
+
+// Family holds the samples of one metric.
+type Family struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []Sample
+}
+
+// Collector is implemented by anything that can report metrics.
+type Collector interface {
+	// Collect returns the current values of the collector's
+	// metrics.
+	Collect() []Family
+}
+
+// CollectorFunc adapts a function to the Collector interface.
+type CollectorFunc func() []Family
+
+// Collect is part of the Collector interface.
+func (f CollectorFunc) Collect() []Family {
+	return f()
+}
+
+// Registry holds the collectors whose metrics are served.
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the given collector to the registry.
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Gather returns the metrics of all the registered collectors, ordered
+// by name.
+func (r *Registry) Gather() []Family {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	var families []Family
+	for _, c := range collectors {
+		families = append(families, c.Collect()...)
+	}
+	sort.Sort(byName(families))
+	return families
+}
+
+type byName []Family
+
+func (f byName) Len() int           { return len(f) }
+func (f byName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f byName) Less(i, j int) bool { return f[i].Name < f[j].Name }
+
+// Counter is a metric whose value only increases.
+type Counter struct {
+	name  string
+	help  string
+	value uint64
+}
+
+// NewCounter returns a new counter with the given name and help text.
+func NewCounter(name, help string) *Counter {
+	return &Counter{name: name, help: help}
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	atomic.AddUint64(&c.value, 1)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() uint64 {
+	return atomic.LoadUint64(&c.value)
+}
+
+// Collect is part of the Collector interface.
+func (c *Counter) Collect() []Family {
+	return []Family{{
+		Name:    c.name,
+		Help:    c.help,
+		Type:    CounterType,
+		Samples: []Sample{{Value: float64(c.Value())}},
+	}}
+}
+
+// Gauge is a metric whose value may go up and down.
+type Gauge struct {
+	name  string
+	help  string
+	value int64
+}
+
+// NewGauge returns a new gauge with the given name and help text.
+func NewGauge(name, help string) *Gauge {
+	return &Gauge{name: name, help: help}
+}
+
+// Inc adds one to the gauge.
+func (g *Gauge) Inc() {
+	atomic.AddInt64(&g.value, 1)
+}
+
+// Dec subtracts one from the gauge.
+func (g *Gauge) Dec() {
+	atomic.AddInt64(&g.value, -1)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+// Collect is part of the Collector interface.
+func (g *Gauge) Collect() []Family {
+	return []Family{{
+		Name:    g.name,
+		Help:    g.help,
+		Type:    GaugeType,
+		Samples: []Sample{{Value: float64(g.Value())}},
+	}}
+}
+
+// GaugeFunc returns a collector reporting a gauge whose value is
+// obtained by calling the given function.
+func GaugeFunc(name, help string, value func() float64) Collector {
+	return CollectorFunc(func() []Family {
+		return []Family{{
+			Name:    name,
+			Help:    help,
+			Type:    GaugeType,
+			Samples: []Sample{{Value: value()}},
+		}}
+	})
+}
+
+// DefaultLatencyBuckets holds the upper bounds, in seconds, of the
+// buckets used for request latency histograms.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+// HistogramVec records observations in buckets, separately for each
+// distinct set of values of its labels.
+type HistogramVec struct {
+	name      string
+	help      string
+	labels    []string
+	buckets   []float64
+	mu        sync.Mutex
+	histogram map[string]*histogram
+}
+
+type histogram struct {
+	labels Labels
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec returns a new histogram with the given name, help
+// text, label names and bucket upper bounds, which must be sorted.
+func NewHistogramVec(name, help string, labels []string, buckets []float64) *HistogramVec {
+	return &HistogramVec{
+		name:      name,
+		help:      help,
+		labels:    labels,
+		buckets:   buckets,
+		histogram: make(map[string]*histogram),
+	}
+}
+
+// Observe records the given value for the given label values, which
+// must be given in the same order as the histogram's label names.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := ""
+	for _, v := range labelValues {
+		key += v + "\x00"
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	hist, ok := h.histogram[key]
+	if !ok {
+		labels := make(Labels)
+		for i, name := range h.labels {
+			if i < len(labelValues) {
+				labels[name] = labelValues[i]
+			}
+		}
+		hist = &histogram{
+			labels: labels,
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.histogram[key] = hist
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			hist.counts[i]++
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// ObserveDuration records the given duration in seconds.
+func (h *HistogramVec) ObserveDuration(d time.Duration, labelValues ...string) {
+	h.Observe(d.Seconds(), labelValues...)
+}
+
+// Collect is part of the Collector interface.
+func (h *HistogramVec) Collect() []Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.histogram))
+	for key := range h.histogram {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	family := Family{
+		Name: h.name,
+		Help: h.help,
+		Type: HistogramType,
+	}
+	for _, key := range keys {
+		hist := h.histogram[key]
+		for i, bound := range h.buckets {
+			family.Samples = append(family.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: withLabel(hist.labels, "le", formatFloat(bound)),
+				Value:  float64(hist.counts[i]),
+			})
+		}
+		family.Samples = append(family.Samples, Sample{
+			Suffix: "_bucket",
+			Labels: withLabel(hist.labels, "le", formatFloat(math.Inf(1))),
+			Value:  float64(hist.count),
+		}, Sample{
+			Suffix: "_sum",
+			Labels: hist.labels,
+			Value:  hist.sum,
+		}, Sample{
+			Suffix: "_count",
+			Labels: hist.labels,
+			Value:  float64(hist.count),
+		})
+	}
+	return []Family{family}
+}
+
+// withLabel returns a copy of the labels with the given label added.
+func withLabel(labels Labels, name, value string) Labels {
+	result := make(Labels, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[name] = value
+	return result
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/introspection"
+	"github.com/juju/juju/testing"
+)
+
+type metricsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&metricsSuite{})
+
+func (s *metricsSuite) TestCounter(c *gc.C) {
+	counter := introspection.NewCounter("things_total", "Things seen.")
+	counter.Inc()
+	counter.Inc()
+	c.Assert(counter.Value(), gc.Equals, uint64(2))
+	c.Assert(counter.Collect(), jc.DeepEquals, []introspection.Family{{
+		Name:    "things_total",
+		Help:    "Things seen.",
+		Type:    introspection.CounterType,
+		Samples: []introspection.Sample{{Value: 2}},
+	}})
+}
+
+func (s *metricsSuite) TestGauge(c *gc.C) {
+	gauge := introspection.NewGauge("things", "Things held.")
+	gauge.Inc()
+	gauge.Inc()
+	gauge.Dec()
+	c.Assert(gauge.Value(), gc.Equals, int64(1))
+	c.Assert(gauge.Collect(), jc.DeepEquals, []introspection.Family{{
+		Name:    "things",
+		Help:    "Things held.",
+		Type:    introspection.GaugeType,
+		Samples: []introspection.Sample{{Value: 1}},
+	}})
+}
+
+func (s *metricsSuite) TestGaugeFunc(c *gc.C) {
+	value := 3.0
+	gauge := introspection.GaugeFunc("things", "Things held.", func() float64 { return value })
+	value = 4
+	c.Assert(gauge.Collect(), jc.DeepEquals, []introspection.Family{{
+		Name:    "things",
+		Help:    "Things held.",
+		Type:    introspection.GaugeType,
+		Samples: []introspection.Sample{{Value: 4}},
+	}})
+}
+
+func (s *metricsSuite) TestHistogramVec(c *gc.C) {
+	h := introspection.NewHistogramVec("latency_seconds", "Latency.", []string{"facade"}, []float64{0.1, 1})
+	h.Observe(0.05, "Client")
+	h.ObserveDuration(500*time.Millisecond, "Client")
+	h.Observe(2, "Agent")
+
+	var buf bytes.Buffer
+	err := introspection.WriteText(&buf, h.Collect())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"# HELP latency_seconds Latency.\n"+
+		"# TYPE latency_seconds histogram\n"+
+		"latency_seconds_bucket{facade=\"Agent\",le=\"0.1\"} 0\n"+
+		"latency_seconds_bucket{facade=\"Agent\",le=\"1\"} 0\n"+
+		"latency_seconds_bucket{facade=\"Agent\",le=\"+Inf\"} 1\n"+
+		"latency_seconds_sum{facade=\"Agent\"} 2\n"+
+		"latency_seconds_count{facade=\"Agent\"} 1\n"+
+		"latency_seconds_bucket{facade=\"Client\",le=\"0.1\"} 1\n"+
+		"latency_seconds_bucket{facade=\"Client\",le=\"1\"} 2\n"+
+		"latency_seconds_bucket{facade=\"Client\",le=\"+Inf\"} 2\n"+
+		"latency_seconds_sum{facade=\"Client\"} 0.55\n"+
+		"latency_seconds_count{facade=\"Client\"} 2\n",
+	)
+}
+
+func (s *metricsSuite) TestWriteTextEscapes(c *gc.C) {
+	var buf bytes.Buffer
+	err := introspection.WriteText(&buf, []introspection.Family{{
+		Name: "odd",
+		Help: "A help\nwith \\ in it.",
+		Type: introspection.GaugeType,
+		Samples: []introspection.Sample{{
+			Labels: introspection.Labels{"b": `say "hi"`, "a": "x"},
+			Value:  1.5,
+		}},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"# HELP odd A help\\nwith \\\\ in it.\n"+
+		"# TYPE odd gauge\n"+
+		"odd{a=\"x\",b=\"say \\\"hi\\\"\"} 1.5\n",
+	)
+}
+
+func (s *metricsSuite) TestRegistryGatherSortsByName(c *gc.C) {
+	r := introspection.NewRegistry()
+	r.Register(introspection.NewCounter("b_total", ""))
+	r.Register(introspection.NewGauge("a", ""))
+	families := r.Gather()
+	c.Assert(families, gc.HasLen, 2)
+	c.Assert(families[0].Name, gc.Equals, "a")
+	c.Assert(families[1].Name, gc.Equals, "b_total")
+}
+
+func (s *metricsSuite) TestHandler(c *gc.C) {
+	r := introspection.NewRegistry()
+	counter := introspection.NewCounter("things_total", "Things seen.")
+	counter.Inc()
+	r.Register(counter)
+
+	server := httptest.NewServer(introspection.Handler(r))
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, introspection.TextContentType)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(body), gc.Equals, ""+
+		"# HELP things_total Things seen.\n"+
+		"# TYPE things_total counter\n"+
+		"things_total 1\n",
+	)
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TextContentType is the content type of the Prometheus text
+// exposition format.
+const TextContentType = "text/plain; version=0.0.4"
+
+// WriteText writes the given metric families to w in the Prometheus
+// text exposition format.
+func WriteText(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, family := range families {
+		if family.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", family.Name, helpEscaper.Replace(family.Help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			bw.WriteString(family.Name)
+			bw.WriteString(sample.Suffix)
+			writeLabels(bw, sample.Labels)
+			bw.WriteString(" ")
+			bw.WriteString(formatFloat(sample.Value))
+			bw.WriteString("\n")
+		}
+	}
+	return bw.Flush()
+}
+
+func writeLabels(w *bufio.Writer, labels Labels) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		fmt.Fprintf(w, `%s="%s"`, name, labelEscaper.Replace(labels[name]))
+	}
+	w.WriteString("}")
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// Handler returns an HTTP handler that serves the metrics in the
+// registry. It does no authentication; callers are expected to wrap
+// it as required.
+func Handler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", TextContentType)
+		if err := WriteText(w, r.Gather()); err != nil {
+			logger.Debugf("cannot write metrics: %v", err)
+		}
+	})
+}
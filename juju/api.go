@@ -6,6 +6,7 @@ package juju
 import (
 	"fmt"
 	"io"
+	"os"
 	"time"
 
 	"github.com/juju/errors"
@@ -17,6 +18,8 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/network"
 )
 
@@ -26,6 +29,9 @@ var logger = loggo.GetLogger("juju.api")
 // changed by tests.
 var (
 	providerConnectDelay = 2 * time.Second
+	addressHealthStore   = func() jujuclient.AddressHealthStore {
+		return jujuclient.NewFileClientStore()
+	}
 )
 
 // apiState provides a subset of api.State's public
@@ -291,9 +297,10 @@ func apiInfoConnect(store configstore.Storage, info configstore.EnvironInfo, api
 		CACert:     endpoint.CACert,
 		Tag:        environInfoUserTag(info),
 		Password:   info.APICredentials().Password,
+		TOTPCode:   os.Getenv(osenv.JujuTOTPCodeEnvKey),
 		EnvironTag: environTag,
 	}
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	st, err := apiOpen(apiInfo, dialOpts())
 	if err != nil {
 		return nil, &infoConnectError{err}
 	}
@@ -319,7 +326,7 @@ func apiConfigConnect(cfg *config.Config, apiOpen apiOpenFunc, stop <-chan struc
 	if err != nil {
 		return nil, err
 	}
-	st, err := apiOpen(apiInfo, api.DefaultDialOpts())
+	st, err := apiOpen(apiInfo, dialOpts())
 	// TODO(rog): handle errUnauthorized when the API handles passwords.
 	if err != nil {
 		return nil, err
@@ -327,6 +334,30 @@ func apiConfigConnect(cfg *config.Config, apiOpen apiOpenFunc, stop <-chan struc
 	return apiStateCachedInfo{st, apiInfo}, nil
 }
 
+// dialOpts returns the options used to dial the API server: the
+// defaults, with addresses that recently accepted a connection tried
+// before those that did not, and the outcome of each dial recorded for
+// next time. Problems with the address health store are logged but
+// never prevent a connection.
+func dialOpts() api.DialOpts {
+	opts := api.DefaultDialOpts()
+	store := addressHealthStore()
+	opts.OrderAddresses = func(addrs []string) []string {
+		health, err := store.AddressHealth(addrs)
+		if err != nil {
+			logger.Warningf("cannot read API address health: %v", err)
+			return addrs
+		}
+		return jujuclient.SortAddressesByHealth(addrs, health, time.Now())
+	}
+	opts.RecordDial = func(addr string, err error) {
+		if err := store.RecordAddressHealth(addr, err == nil, time.Now()); err != nil {
+			logger.Warningf("cannot record health of API address %q: %v", addr, err)
+		}
+	}
+	return opts
+}
+
 // getConfig looks for configuration info on the given environment
 func getConfig(info configstore.EnvironInfo, envs *environs.Environs, envName string) (*config.Config, error) {
 	if info != nil && len(info.BootstrapConfig()) > 0 {
@@ -358,6 +389,7 @@ func environAPIInfo(environ environs.Environ, user names.UserTag) (*api.Info, er
 	}
 	info.Tag = user
 	info.Password = password
+	info.TOTPCode = os.Getenv(osenv.JujuTOTPCodeEnvKey)
 	return info, nil
 }
 
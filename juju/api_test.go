@@ -28,6 +28,7 @@ import (
 	"github.com/juju/juju/juju"
 	"github.com/juju/juju/juju/osenv"
 	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/jujuclient"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	coretesting "github.com/juju/juju/testing"
@@ -102,6 +103,7 @@ type NewAPIClientSuite struct {
 	coretesting.FakeJujuHomeSuite
 	testing.MgoSuite
 	envtesting.ToolsFixture
+	healthStore jujuclient.ClientStore
 }
 
 var _ = gc.Suite(&NewAPIClientSuite{})
@@ -134,6 +136,10 @@ func (cs *NewAPIClientSuite) SetUpTest(c *gc.C) {
 	cs.ToolsFixture.SetUpTest(c)
 	cs.FakeJujuHomeSuite.SetUpTest(c)
 	cs.MgoSuite.SetUpTest(c)
+	cs.healthStore = jujuclient.NewMemStore()
+	cs.PatchValue(juju.AddressHealthStore, func() jujuclient.AddressHealthStore {
+		return cs.healthStore
+	})
 }
 
 func (cs *NewAPIClientSuite) TearDownTest(c *gc.C) {
@@ -274,7 +280,7 @@ func (s *NewAPIClientSuite) TestWithConfigAndNoInfo(c *gc.C) {
 		c.Check(apiInfo.Password, gc.Equals, "adminpass")
 		// EnvironTag wasn't in regular Config
 		c.Check(apiInfo.EnvironTag.Id(), gc.Equals, "")
-		c.Check(opts, gc.DeepEquals, api.DefaultDialOpts())
+		checkDialOpts(c, opts)
 		called++
 		return expectState, nil
 	}
@@ -370,7 +376,48 @@ func checkCommonAPIInfoAttrs(c *gc.C, apiInfo *api.Info, opts api.DialOpts) {
 	c.Check(apiInfo.Tag, gc.Equals, names.NewUserTag("foo"))
 	c.Check(string(apiInfo.CACert), gc.Equals, "certificated")
 	c.Check(apiInfo.Password, gc.Equals, "foopass")
-	c.Check(opts, gc.DeepEquals, api.DefaultDialOpts())
+	checkDialOpts(c, opts)
+}
+
+func checkDialOpts(c *gc.C, opts api.DialOpts) {
+	defaults := api.DefaultDialOpts()
+	c.Check(opts.DialAddressInterval, gc.Equals, defaults.DialAddressInterval)
+	c.Check(opts.Timeout, gc.Equals, defaults.Timeout)
+	c.Check(opts.RetryDelay, gc.Equals, defaults.RetryDelay)
+	c.Check(opts.OrderAddresses, gc.NotNil)
+	c.Check(opts.RecordDial, gc.NotNil)
+}
+
+func (s *NewAPIClientSuite) TestWithInfoUsesAddressHealth(c *gc.C) {
+	store := newConfigStore("noconfig", &environInfo{
+		creds: dummyStoreInfo.creds,
+		endpoint: configstore.APIEndpoint{
+			Addresses:   []string{"0.1.2.3:1234", "0.1.2.4:1234", "0.1.2.5:1234"},
+			CACert:      "certificated",
+			EnvironUUID: fakeUUID,
+		},
+	})
+	now := time.Now()
+	err := s.healthStore.RecordAddressHealth("0.1.2.3:1234", false, now)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.healthStore.RecordAddressHealth("0.1.2.5:1234", true, now)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectState := mockedAPIState(mockedHostPort | mockedEnvironTag)
+	apiOpen := func(apiInfo *api.Info, opts api.DialOpts) (juju.APIState, error) {
+		c.Check(opts.OrderAddresses(apiInfo.Addrs), jc.DeepEquals, []string{
+			"0.1.2.5:1234", "0.1.2.4:1234", "0.1.2.3:1234",
+		})
+		opts.RecordDial("0.1.2.4:1234", errors.New("connection refused"))
+		return expectState, nil
+	}
+	st, err := juju.NewAPIFromStore("noconfig", store, apiOpen)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(st, gc.Equals, expectState)
+
+	health, err := s.healthStore.AddressHealth([]string{"0.1.2.4:1234"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health["0.1.2.4:1234"].ConsecutiveFailures, gc.Equals, 1)
 }
 
 func (s *NewAPIClientSuite) TestWithInfoNoEnvironTag(c *gc.C) {
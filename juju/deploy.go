@@ -111,8 +111,10 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
 	units := make([]*state.Unit, n)
-	// Hard code for now till we implement a different approach.
-	policy := state.AssignCleanEmpty
+	policy, err := st.EnvironAssignmentPolicy()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	// All units should have the same networks as the service.
 	networks, err := svc.Networks()
 	if err != nil {
@@ -128,16 +130,20 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 			if n != 1 {
 				return nil, fmt.Errorf("cannot add multiple units of service %q to a single machine", svc.Name())
 			}
-			// machineIdSpec may be an existing machine or container, eg 3/lxc/2
-			// or a new container on a machine, eg lxc:3
+			// machineIdSpec may be an existing machine or container, eg 3/lxc/2,
+			// a new container on a machine, eg lxc:3, or a new container
+			// inside a new container on a machine, eg lxc:kvm:3
 			mid := machineIdSpec
-			var containerType instance.ContainerType
+			var containerType, hostType instance.ContainerType
 			specParts := strings.SplitN(machineIdSpec, ":", 2)
 			if len(specParts) > 1 {
 				firstPart := specParts[0]
 				var err error
 				if containerType, err = instance.ParseContainerType(firstPart); err == nil {
 					mid = specParts[1]
+					if nestedType, hostId, err := instance.ParseNestedContainer(containerType, mid); err == nil {
+						hostType, mid = nestedType, hostId
+					}
 				} else {
 					mid = machineIdSpec
 				}
@@ -164,7 +170,11 @@ func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string)
 					Constraints:       *unitCons,
 					RequestedNetworks: networks,
 				}
-				m, err = st.AddMachineInsideMachine(template, mid, containerType)
+				if hostType != "" {
+					m, err = st.AddMachineInsideNewContainer(template, template, mid, hostType, containerType)
+				} else {
+					m, err = st.AddMachineInsideMachine(template, mid, containerType)
+				}
 			} else {
 				m, err = st.Machine(mid)
 			}
@@ -146,6 +146,33 @@ func (s *DeployLocalSuite) TestDeployNumUnits(c *gc.C) {
 	s.assertMachines(c, service, constraints.MustParse("mem=2G cpu-cores=2"), "0", "1")
 }
 
+func (s *DeployLocalSuite) TestDeployNumUnitsWithUnitPlacement(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Id(), gc.Equals, "0")
+	_, err = juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "alice",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"unit-placement": "pack"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			NumUnits:    2,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	// The first unit joins alice/0 on the used machine, rather than
+	// being spread onto a clean one.
+	s.assertMachines(c, service, constraints.Value{}, "0", "1")
+}
+
 func (s *DeployLocalSuite) TestDeployWithForceMachineRejectsTooManyUnits(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -214,6 +241,30 @@ func (s *DeployLocalSuite) TestDeployForceMachineIdWithContainer(c *gc.C) {
 	c.Assert(machineCons, gc.DeepEquals, *unitCons)
 }
 
+func (s *DeployLocalSuite) TestDeployForceMachineIdWithNestedContainer(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Id(), gc.Equals, "0")
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:   "bob",
+			Charm:         s.charm,
+			NumUnits:      1,
+			ToMachineSpec: "lxc:kvm:0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := service.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+
+	id, err := units[0].AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "0/kvm/0/lxc/0")
+	containers, err := machine.Containers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(containers, jc.DeepEquals, []string{"0/kvm/0"})
+}
+
 func (s *DeployLocalSuite) assertCharm(c *gc.C, service *state.Service, expect *charm.URL) {
 	curl, force := service.CharmURL()
 	c.Assert(curl, gc.DeepEquals, expect)
@@ -14,6 +14,7 @@ var (
 	MaybePreferIPv6        = &maybePreferIPv6
 	ResolveOrDropHostnames = &resolveOrDropHostnames
 	ServerAddress          = &serverAddress
+	AddressHealthStore     = &addressHealthStore
 )
 
 type APIState apiState
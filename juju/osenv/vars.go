@@ -32,6 +32,16 @@ const (
 	// This includes args and output.
 	// Default is 1.
 	JujuCLIVersion = "JUJU_CLI_VERSION"
+
+	// JujuTOTPCodeEnvKey is the env var holding the two-factor
+	// authentication code sent when logging in, for users enrolled
+	// for two-factor authentication.
+	JujuTOTPCodeEnvKey = "JUJU_2FA_CODE"
+
+	// JujuContextEnvKey is the env var holding the details of the
+	// controller, model and account that a plugin runs against, as
+	// JSON.
+	JujuContextEnvKey = "JUJU_CONTEXT"
 )
 
 // FeatureFlags returns a map that can be merged with os.Environ.
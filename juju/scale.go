@@ -0,0 +1,121 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package juju
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+)
+
+// ScalePlan describes the units to add to or remove from a service so
+// that it has a desired number of live units.
+type ScalePlan struct {
+	// Service is the service to scale.
+	Service *state.Service
+
+	// Units holds the live units of the service, most recently added
+	// first. Units that are already dying are not counted.
+	Units []*state.Unit
+
+	// Delta is the number of units to add, or if negative, to remove.
+	Delta int
+
+	// MachineIdSpec is the placement of the unit to add, if any.
+	MachineIdSpec string
+}
+
+// PlanScale checks that the given service can be scaled to n live
+// units, placing any unit added on the given machine, and returns the
+// plan for doing so. The service is not scaled below its minimum
+// number of units, and a placement can only be given when adding a
+// single unit.
+func PlanScale(svc *state.Service, n int, machineIdSpec string) (*ScalePlan, error) {
+	if !svc.IsPrincipal() {
+		return nil, errors.Errorf("cannot scale subordinate service %q", svc.Name())
+	}
+	if n < 0 {
+		return nil, errors.New("cannot scale to a negative number of units")
+	}
+	if minUnits := svc.MinUnits(); n < minUnits {
+		return nil, errors.Errorf("cannot scale service %q to %d units: minimum is %d", svc.Name(), n, minUnits)
+	}
+	units, err := AliveUnits(svc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	delta := n - len(units)
+	if machineIdSpec != "" && delta != 1 {
+		return nil, errors.Errorf("cannot use a placement when scaling service %q by %d units", svc.Name(), delta)
+	}
+	return &ScalePlan{
+		Service:       svc,
+		Units:         units,
+		Delta:         delta,
+		MachineIdSpec: machineIdSpec,
+	}, nil
+}
+
+// Apply adds or removes the units described by the plan, returning
+// the names of the units added and removed. When scaling down, the
+// most recently added units are removed first.
+func (p *ScalePlan) Apply(st *state.State) (added, removed []string, err error) {
+	switch {
+	case p.Delta > 0:
+		if names.IsValidMachine(p.MachineIdSpec) {
+			if _, err := st.Machine(p.MachineIdSpec); err != nil {
+				return nil, nil, errors.Annotatef(err, `cannot add units for service "%v" to machine %v`, p.Service.Name(), p.MachineIdSpec)
+			}
+		}
+		units, err := AddUnits(st, p.Service, p.Delta, p.MachineIdSpec)
+		for _, unit := range units {
+			added = append(added, unit.Name())
+		}
+		if err != nil {
+			return added, nil, errors.Trace(err)
+		}
+	case p.Delta < 0:
+		for _, unit := range p.Units[:-p.Delta] {
+			if err := unit.Destroy(); err != nil {
+				return nil, removed, errors.Annotatef(err, "cannot remove unit %q", unit.Name())
+			}
+			removed = append(removed, unit.Name())
+		}
+	}
+	return added, removed, nil
+}
+
+// AliveUnits returns the live units of the service, most recently
+// added first.
+func AliveUnits(svc *state.Service) ([]*state.Unit, error) {
+	all, err := svc.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var units []*state.Unit
+	for _, unit := range all {
+		if unit.Life() == state.Alive {
+			units = append(units, unit)
+		}
+	}
+	sort.Sort(sort.Reverse(unitsByNumber(units)))
+	return units, nil
+}
+
+type unitsByNumber []*state.Unit
+
+func (u unitsByNumber) Len() int           { return len(u) }
+func (u unitsByNumber) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unitsByNumber) Less(i, j int) bool { return unitNumber(u[i]) < unitNumber(u[j]) }
+
+func unitNumber(unit *state.Unit) int {
+	name := unit.Name()
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
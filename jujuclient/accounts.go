@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// JujuAccountsPath is the location where accounts information is
+// expected to be found.
+func JujuAccountsPath() string {
+	return osenv.JujuHomePath("accounts.yaml")
+}
+
+// AccountsFile represents the YAML structure of the file
+// $JUJU_HOME/accounts.yaml.
+type AccountsFile struct {
+	// Controllers maps the name of a controller to the account
+	// used to connect to it.
+	Controllers map[string]AccountDetails `yaml:"controllers"`
+}
+
+// ReadAccountsFile loads all accounts defined in a given file.
+// If the file is not found, it is not an error.
+func ReadAccountsFile(file string) (map[string]AccountDetails, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	accounts, err := ParseAccounts(data)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// WriteAccountsFile marshals to YAML details of the given accounts
+// and writes it to the accounts file.
+func WriteAccountsFile(accounts map[string]AccountDetails) error {
+	data, err := goyaml.Marshal(AccountsFile{accounts})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal accounts")
+	}
+	return utils.AtomicWriteFile(JujuAccountsPath(), data, os.FileMode(0600))
+}
+
+// ParseAccounts parses the given YAML bytes into accounts metadata.
+func ParseAccounts(data []byte) (map[string]AccountDetails, error) {
+	var result AccountsFile
+	if err := goyaml.Unmarshal(data, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal accounts")
+	}
+	return result.Controllers, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// AddressFailureExpiry is how long a failure to connect to an API
+// address is held against it. After this time an address that failed
+// is treated as if nothing was known about it, so that it is tried
+// again in turn.
+const AddressFailureExpiry = time.Hour
+
+// AddressHealth records the outcome of recent attempts to connect to
+// an API address.
+type AddressHealth struct {
+	// LastSuccess holds the time of the most recent successful
+	// connection to the address.
+	LastSuccess time.Time
+
+	// LastFailure holds the time of the most recent failed attempt
+	// to connect to the address.
+	LastFailure time.Time
+
+	// ConsecutiveFailures holds the number of failed attempts since
+	// the last successful connection.
+	ConsecutiveFailures int
+}
+
+// Record returns the health updated with the outcome of an attempt
+// to connect, made at the given time.
+func (h AddressHealth) Record(success bool, when time.Time) AddressHealth {
+	if success {
+		h.LastSuccess = when
+		h.ConsecutiveFailures = 0
+	} else {
+		h.LastFailure = when
+		h.ConsecutiveFailures++
+	}
+	return h
+}
+
+// healthy reports whether the last attempt to connect succeeded.
+func (h AddressHealth) healthy() bool {
+	return !h.LastSuccess.IsZero() && h.LastSuccess.After(h.LastFailure)
+}
+
+// failing reports whether the last attempt to connect failed within
+// AddressFailureExpiry of now.
+func (h AddressHealth) failing(now time.Time) bool {
+	return !h.LastFailure.IsZero() && !h.LastFailure.Before(h.LastSuccess) &&
+		now.Sub(h.LastFailure) < AddressFailureExpiry
+}
+
+// SortAddressesByHealth returns the given addresses ordered so that
+// those most likely to accept a connection come first: addresses that
+// were last connected to successfully, most recent first, then those
+// with no recent history, then those that recently failed, fewest
+// failures first. Addresses of equal standing keep their original
+// order. The given slice is not modified.
+func SortAddressesByHealth(addrs []string, health map[string]AddressHealth, now time.Time) []string {
+	sorted := &addressesByHealth{
+		addrs:  append([]string(nil), addrs...),
+		health: health,
+		now:    now,
+	}
+	sort.Stable(sorted)
+	return sorted.addrs
+}
+
+type addressesByHealth struct {
+	addrs  []string
+	health map[string]AddressHealth
+	now    time.Time
+}
+
+func (a *addressesByHealth) Len() int {
+	return len(a.addrs)
+}
+
+func (a *addressesByHealth) Swap(i, j int) {
+	a.addrs[i], a.addrs[j] = a.addrs[j], a.addrs[i]
+}
+
+func (a *addressesByHealth) Less(i, j int) bool {
+	hi, hj := a.health[a.addrs[i]], a.health[a.addrs[j]]
+	ri, rj := a.rank(hi), a.rank(hj)
+	if ri != rj {
+		return ri < rj
+	}
+	switch ri {
+	case 0:
+		return hi.LastSuccess.After(hj.LastSuccess)
+	case 2:
+		return hi.ConsecutiveFailures < hj.ConsecutiveFailures
+	}
+	return false
+}
+
+func (a *addressesByHealth) rank(h AddressHealth) int {
+	switch {
+	case h.healthy():
+		return 0
+	case h.failing(a.now):
+		return 2
+	}
+	return 1
+}
+
+// JujuAddressHealthPath is the location where API address health
+// information is expected to be found.
+func JujuAddressHealthPath() string {
+	return osenv.JujuHomePath("address-health.yaml")
+}
+
+// addressHealthFile represents the YAML structure of the file
+// $JUJU_HOME/address-health.yaml.
+type addressHealthFile struct {
+	// Addresses maps an API address to the outcome of recent
+	// attempts to connect to it.
+	Addresses map[string]addressHealthDoc `yaml:"addresses"`
+}
+
+// addressHealthDoc is the serialised form of AddressHealth.
+type addressHealthDoc struct {
+	LastSuccess         string `yaml:"last-success,omitempty"`
+	LastFailure         string `yaml:"last-failure,omitempty"`
+	ConsecutiveFailures int    `yaml:"consecutive-failures,omitempty"`
+}
+
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseHealthTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// ReadAddressHealthFile loads the address health held in a given file.
+// If the file is not found, it is not an error.
+func ReadAddressHealthFile(file string) (map[string]AddressHealth, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	health, err := ParseAddressHealth(data)
+	if err != nil {
+		return nil, err
+	}
+	return health, nil
+}
+
+// WriteAddressHealthFile marshals to YAML the given address health
+// and writes it to the address health file.
+func WriteAddressHealthFile(health map[string]AddressHealth) error {
+	docs := make(map[string]addressHealthDoc)
+	for addr, h := range health {
+		docs[addr] = addressHealthDoc{
+			LastSuccess:         formatHealthTime(h.LastSuccess),
+			LastFailure:         formatHealthTime(h.LastFailure),
+			ConsecutiveFailures: h.ConsecutiveFailures,
+		}
+	}
+	data, err := goyaml.Marshal(addressHealthFile{docs})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal address health")
+	}
+	return utils.AtomicWriteFile(JujuAddressHealthPath(), data, os.FileMode(0600))
+}
+
+// ParseAddressHealth parses the given YAML bytes into address health.
+func ParseAddressHealth(data []byte) (map[string]AddressHealth, error) {
+	var result addressHealthFile
+	if err := goyaml.Unmarshal(data, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal address health")
+	}
+	if result.Addresses == nil {
+		return nil, nil
+	}
+	health := make(map[string]AddressHealth)
+	for addr, doc := range result.Addresses {
+		lastSuccess, err := parseHealthTime(doc.LastSuccess)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot parse last success of %q", addr)
+		}
+		lastFailure, err := parseHealthTime(doc.LastFailure)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot parse last failure of %q", addr)
+		}
+		health[addr] = AddressHealth{
+			LastSuccess:         lastSuccess,
+			LastFailure:         lastFailure,
+			ConsecutiveFailures: doc.ConsecutiveFailures,
+		}
+	}
+	return health, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type addressHealthSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&addressHealthSuite{})
+
+func (s *addressHealthSuite) TestSortAddressesByHealth(c *gc.C) {
+	now := time.Now()
+	addrs := []string{"unknown:1", "failing:1", "old-success:1", "stale-failure:1", "failing:2", "new-success:1", "unknown:2"}
+	health := map[string]jujuclient.AddressHealth{
+		"failing:1": {
+			LastSuccess:         now.Add(-time.Hour),
+			LastFailure:         now.Add(-time.Minute),
+			ConsecutiveFailures: 3,
+		},
+		"failing:2": {
+			LastFailure:         now.Add(-time.Minute),
+			ConsecutiveFailures: 1,
+		},
+		"old-success:1": {
+			LastSuccess: now.Add(-time.Hour),
+			LastFailure: now.Add(-2 * time.Hour),
+		},
+		"new-success:1": {
+			LastSuccess: now.Add(-time.Minute),
+		},
+		"stale-failure:1": {
+			LastFailure:         now.Add(-2 * jujuclient.AddressFailureExpiry),
+			ConsecutiveFailures: 10,
+		},
+	}
+	sorted := jujuclient.SortAddressesByHealth(addrs, health, now)
+	c.Assert(sorted, jc.DeepEquals, []string{
+		"new-success:1", "old-success:1",
+		"unknown:1", "stale-failure:1", "unknown:2",
+		"failing:2", "failing:1",
+	})
+	// The original slice is untouched.
+	c.Assert(addrs[0], gc.Equals, "unknown:1")
+}
+
+func (s *addressHealthSuite) TestRecord(c *gc.C) {
+	now := time.Now()
+	var health jujuclient.AddressHealth
+	health = health.Record(false, now)
+	health = health.Record(false, now)
+	c.Assert(health.ConsecutiveFailures, gc.Equals, 2)
+	health = health.Record(true, now.Add(time.Second))
+	c.Assert(health, jc.DeepEquals, jujuclient.AddressHealth{
+		LastSuccess: now.Add(time.Second),
+		LastFailure: now,
+	})
+}
+
+func (s *addressHealthSuite) TestParseAddressHealth(c *gc.C) {
+	health, err := jujuclient.ParseAddressHealth([]byte(`
+addresses:
+  10.0.0.1:17070:
+    last-success: 2015-10-01T12:00:00Z
+    last-failure: 2015-10-01T11:00:00Z
+    consecutive-failures: 0
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, gc.HasLen, 1)
+	expect := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	c.Assert(health["10.0.0.1:17070"].LastSuccess.Equal(expect), jc.IsTrue)
+
+	_, err = jujuclient.ParseAddressHealth([]byte(`
+addresses:
+  10.0.0.1:17070:
+    last-success: yesterday
+`))
+	c.Assert(err, gc.ErrorMatches, `cannot parse last success of "10.0.0.1:17070": .*`)
+}
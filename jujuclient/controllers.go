@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// JujuControllersPath is the location where controllers information is
+// expected to be found.
+func JujuControllersPath() string {
+	return osenv.JujuHomePath("controllers.yaml")
+}
+
+// ControllersFile represents the YAML structure of the file
+// $JUJU_HOME/controllers.yaml.
+type ControllersFile struct {
+	// Controllers is the collection of controllers known to the client,
+	// keyed by controller name.
+	Controllers map[string]ControllerDetails `yaml:"controllers"`
+}
+
+// ReadControllersFile loads all controllers defined in a given file.
+// If the file is not found, it is not an error.
+func ReadControllersFile(file string) (map[string]ControllerDetails, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	controllers, err := ParseControllers(data)
+	if err != nil {
+		return nil, err
+	}
+	return controllers, nil
+}
+
+// WriteControllersFile marshals to YAML details of the given controllers
+// and writes it to the controllers file.
+func WriteControllersFile(controllers map[string]ControllerDetails) error {
+	data, err := goyaml.Marshal(ControllersFile{controllers})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal yaml controllers")
+	}
+	return utils.AtomicWriteFile(JujuControllersPath(), data, os.FileMode(0600))
+}
+
+// ParseControllers parses the given YAML bytes into controllers metadata.
+func ParseControllers(data []byte) (map[string]ControllerDetails, error) {
+	var result ControllersFile
+	if err := goyaml.Unmarshal(data, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal yaml controllers metadata")
+	}
+	return result.Controllers, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// EntityNames holds the names of the entities in a model, as last
+// reported by status.
+type EntityNames struct {
+	// Machines holds the ids of the machines and containers.
+	Machines []string `yaml:"machines,omitempty"`
+
+	// Services holds the names of the services.
+	Services []string `yaml:"services,omitempty"`
+
+	// Units holds the names of the units, including subordinates.
+	Units []string `yaml:"units,omitempty"`
+}
+
+// JujuEntityNamesPath is the location where the cached names of model
+// entities are expected to be found.
+func JujuEntityNamesPath() string {
+	return osenv.JujuHomePath("entity-names.yaml")
+}
+
+// entityNamesFile represents the YAML structure of the file
+// $JUJU_HOME/entity-names.yaml.
+type entityNamesFile struct {
+	// Models maps a model name to the names of its entities.
+	Models map[string]EntityNames `yaml:"models"`
+}
+
+// ReadEntityNamesFile loads the entity names held in a given file.
+// If the file is not found, it is not an error.
+func ReadEntityNamesFile(file string) (map[string]EntityNames, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var result entityNamesFile
+	if err := goyaml.Unmarshal(data, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal entity names")
+	}
+	return result.Models, nil
+}
+
+// WriteEntityNamesFile marshals to YAML the given entity names and
+// writes them to the entity names file.
+func WriteEntityNamesFile(models map[string]EntityNames) error {
+	data, err := goyaml.Marshal(entityNamesFile{models})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal entity names")
+	}
+	return utils.AtomicWriteFile(JujuEntityNamesPath(), data, os.FileMode(0600))
+}
@@ -0,0 +1,449 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package jujuclient provides functionality to support
+// connections to Juju such as controllers cache, accounts cache, etc.
+package jujuclient
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/fslock"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+var logger = loggo.GetLogger("juju.jujuclient")
+
+const lockName = "client.lock"
+
+// A second should be way more than enough to write or read any files.
+var lockTimeout = time.Second
+
+var _ ClientStore = (*store)(nil)
+
+// NewFileClientStore returns a new filesystem-based client store
+// that manages files in $JUJU_HOME.
+func NewFileClientStore() ClientStore {
+	return &store{}
+}
+
+// store is a filesystem-based implementation of ClientStore.
+type store struct{}
+
+func (s *store) lock(operation string) (*fslock.Lock, error) {
+	lock, err := fslock.NewLock(osenv.JujuHome(), lockName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := lock.LockWithTimeout(lockTimeout, operation); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return lock, nil
+}
+
+// AllControllers implements ControllerGetter.
+func (s *store) AllControllers() (map[string]ControllerDetails, error) {
+	lock, err := s.lock("read all controllers")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read all controllers")
+	}
+	defer lock.Unlock()
+	return ReadControllersFile(JujuControllersPath())
+}
+
+// ControllerByName implements ControllerGetter.
+func (s *store) ControllerByName(controllerName string) (*ControllerDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := s.lock("read controller by name")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read controller %v", controllerName)
+	}
+	defer lock.Unlock()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if result, ok := controllers[controllerName]; ok {
+		return &result, nil
+	}
+	return nil, errors.NotFoundf("controller %s", controllerName)
+}
+
+// UpdateController implements ControllerUpdater.
+func (s *store) UpdateController(controllerName string, details ControllerDetails) error {
+	if err := validateControllerDetails(controllerName, details); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("update controller")
+	if err != nil {
+		return errors.Annotate(err, "cannot update controller")
+	}
+	defer lock.Unlock()
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get controllers")
+	}
+	if controllers == nil {
+		controllers = make(map[string]ControllerDetails)
+	}
+	controllers[controllerName] = details
+	return WriteControllersFile(controllers)
+}
+
+// RemoveController implements ControllerRemover.
+func (s *store) RemoveController(controllerName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("remove controller")
+	if err != nil {
+		return errors.Annotate(err, "cannot remove controller")
+	}
+	defer lock.Unlock()
+
+	// Remove the controller's models and account first, so that
+	// a failure part way through never leaves orphaned entries.
+	models, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get models")
+	}
+	if _, ok := models[controllerName]; ok {
+		delete(models, controllerName)
+		if err := WriteModelsFile(models); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get accounts")
+	}
+	if _, ok := accounts[controllerName]; ok {
+		delete(accounts, controllerName)
+		if err := WriteAccountsFile(accounts); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	controllers, err := ReadControllersFile(JujuControllersPath())
+	if err != nil {
+		return errors.Annotate(err, "cannot get controllers")
+	}
+	if _, ok := controllers[controllerName]; !ok {
+		return nil
+	}
+	delete(controllers, controllerName)
+	return WriteControllersFile(controllers)
+}
+
+// AllModels implements ModelGetter.
+func (s *store) AllModels(controllerName string) (map[string]ModelDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := s.lock("read all models")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read all models")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerModels, ok := all[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	return controllerModels.Models, nil
+}
+
+// ModelByName implements ModelGetter.
+func (s *store) ModelByName(controllerName, modelName string) (*ModelDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := s.lock("read model by name")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read model %v:%v", controllerName, modelName)
+	}
+	defer lock.Unlock()
+
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerModels, ok := all[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	details, ok := controllerModels.Models[modelName]
+	if !ok {
+		return nil, errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	return &details, nil
+}
+
+// UpdateModel implements ModelUpdater.
+func (s *store) UpdateModel(controllerName, modelName string, details ModelDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("update model")
+	if err != nil {
+		return errors.Annotate(err, "cannot update model")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if all == nil {
+		all = make(map[string]ControllerModels)
+	}
+	controllerModels, ok := all[controllerName]
+	if !ok {
+		controllerModels = ControllerModels{}
+	}
+	if controllerModels.Models == nil {
+		controllerModels.Models = make(map[string]ModelDetails)
+	}
+	controllerModels.Models[modelName] = details
+	all[controllerName] = controllerModels
+	return WriteModelsFile(all)
+}
+
+// SetModels implements ModelUpdater.
+func (s *store) SetModels(controllerName string, models map[string]ModelDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	for modelName, details := range models {
+		if err := validateModelName(modelName); err != nil {
+			return errors.Trace(err)
+		}
+		if err := validateModelDetails(details); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	lock, err := s.lock("set models")
+	if err != nil {
+		return errors.Annotate(err, "cannot set models")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if all == nil {
+		all = make(map[string]ControllerModels)
+	}
+	controllerModels := ControllerModels{
+		Models: make(map[string]ModelDetails),
+	}
+	for modelName, details := range models {
+		controllerModels.Models[modelName] = details
+	}
+	all[controllerName] = controllerModels
+	return WriteModelsFile(all)
+}
+
+// RemoveModel implements ModelRemover.
+func (s *store) RemoveModel(controllerName, modelName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("remove model")
+	if err != nil {
+		return errors.Annotate(err, "cannot remove model")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadModelsFile(JujuModelsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	controllerModels, ok := all[controllerName]
+	if !ok {
+		return nil
+	}
+	if _, ok := controllerModels.Models[modelName]; !ok {
+		return nil
+	}
+	delete(controllerModels.Models, modelName)
+	all[controllerName] = controllerModels
+	return WriteModelsFile(all)
+}
+
+// AccountByName implements AccountGetter.
+func (s *store) AccountByName(controllerName string) (*AccountDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := s.lock("read account by name")
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read account for controller %v", controllerName)
+	}
+	defer lock.Unlock()
+
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	details, ok := accounts[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("account for controller %s", controllerName)
+	}
+	return &details, nil
+}
+
+// UpdateAccount implements AccountUpdater.
+func (s *store) UpdateAccount(controllerName string, details AccountDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateAccountDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("update account")
+	if err != nil {
+		return errors.Annotate(err, "cannot update account")
+	}
+	defer lock.Unlock()
+
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if accounts == nil {
+		accounts = make(map[string]AccountDetails)
+	}
+	accounts[controllerName] = details
+	return WriteAccountsFile(accounts)
+}
+
+// RemoveAccount implements AccountRemover.
+func (s *store) RemoveAccount(controllerName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("remove account")
+	if err != nil {
+		return errors.Annotate(err, "cannot remove account")
+	}
+	defer lock.Unlock()
+
+	accounts, err := ReadAccountsFile(JujuAccountsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := accounts[controllerName]; !ok {
+		return nil
+	}
+	delete(accounts, controllerName)
+	return WriteAccountsFile(accounts)
+}
+
+// AddressHealth implements AddressHealthStore.
+func (s *store) AddressHealth(addrs []string) (map[string]AddressHealth, error) {
+	lock, err := s.lock("read address health")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read address health")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadAddressHealthFile(JujuAddressHealthPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]AddressHealth)
+	for _, addr := range addrs {
+		if health, ok := all[addr]; ok {
+			result[addr] = health
+		}
+	}
+	return result, nil
+}
+
+// RecordAddressHealth implements AddressHealthStore.
+func (s *store) RecordAddressHealth(addr string, success bool, when time.Time) error {
+	lock, err := s.lock("record address health")
+	if err != nil {
+		return errors.Annotate(err, "cannot record address health")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadAddressHealthFile(JujuAddressHealthPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if all == nil {
+		all = make(map[string]AddressHealth)
+	}
+	all[addr] = all[addr].Record(success, when)
+	return WriteAddressHealthFile(all)
+}
+
+// EntityNames implements EntityNameStore.
+func (s *store) EntityNames(modelName string) (*EntityNames, error) {
+	if err := validateModelName(modelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock, err := s.lock("read entity names")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read entity names")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadEntityNamesFile(JujuEntityNamesPath())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	names, ok := all[modelName]
+	if !ok {
+		return nil, errors.NotFoundf("entity names for model %s", modelName)
+	}
+	return &names, nil
+}
+
+// SetEntityNames implements EntityNameStore.
+func (s *store) SetEntityNames(modelName string, names EntityNames) error {
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	lock, err := s.lock("set entity names")
+	if err != nil {
+		return errors.Annotate(err, "cannot set entity names")
+	}
+	defer lock.Unlock()
+
+	all, err := ReadEntityNamesFile(JujuEntityNamesPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if all == nil {
+		all = make(map[string]EntityNames)
+	}
+	all[modelName] = names
+	return WriteEntityNamesFile(all)
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import "time"
+
+// ControllerDetails holds the details needed to connect to a controller.
+type ControllerDetails struct {
+	// Servers contains the addresses of hosts that form the controller's
+	// API server. Addresses may hold unresolved hostnames.
+	Servers []string `yaml:"servers,flow"`
+
+	// ControllerUUID is the unique ID for the controller.
+	ControllerUUID string `yaml:"uuid"`
+
+	// APIEndpoints is the collection of API endpoints running in this
+	// controller.
+	APIEndpoints []string `yaml:"api-endpoints,flow"`
+
+	// CACert is a security certificate for this controller.
+	CACert string `yaml:"ca-cert"`
+}
+
+// ModelDetails holds details of a model hosted by a controller.
+type ModelDetails struct {
+	// ModelUUID is the unique ID for the model.
+	ModelUUID string `yaml:"uuid"`
+
+	// Owner is the name of the user that owns the model, if known.
+	// It lets commands describe the model without connecting to the
+	// controller.
+	Owner string `yaml:"owner,omitempty"`
+
+	// Removed records that the controller no longer reported the
+	// model when the models were last refreshed.
+	Removed bool `yaml:"removed,omitempty"`
+}
+
+// AccountDetails holds details of an account on a controller.
+type AccountDetails struct {
+	// User is the username for the account.
+	User string `yaml:"user"`
+
+	// Password is the password for the account.
+	Password string `yaml:"password,omitempty"`
+
+	// SessionToken is the token of the account's login session with
+	// the controller, which is used to log in in place of the
+	// password until it expires.
+	SessionToken string `yaml:"session-token,omitempty"`
+}
+
+// ControllerUpdater stores controller details.
+type ControllerUpdater interface {
+	// UpdateController adds the given controller to the controller
+	// collection, or updates the stored details if a controller with
+	// the same name already exists.
+	UpdateController(controllerName string, details ControllerDetails) error
+}
+
+// ControllerRemover removes controllers.
+type ControllerRemover interface {
+	// RemoveController removes the controller with the given name,
+	// along with any models and accounts stored against it.
+	// Removing a controller that does not exist is not an error.
+	RemoveController(controllerName string) error
+}
+
+// ControllerGetter gets controllers.
+type ControllerGetter interface {
+	// AllControllers gets all controllers.
+	AllControllers() (map[string]ControllerDetails, error)
+
+	// ControllerByName returns the controller with the specified name.
+	// If there exists no controller with the specified name, an
+	// error satisfying errors.IsNotFound will be returned.
+	ControllerByName(controllerName string) (*ControllerDetails, error)
+}
+
+// ControllerStore is an amalgamation of ControllerUpdater,
+// ControllerRemover and ControllerGetter.
+type ControllerStore interface {
+	ControllerUpdater
+	ControllerRemover
+	ControllerGetter
+}
+
+// ModelUpdater stores model details.
+type ModelUpdater interface {
+	// UpdateModel adds the given model to the model collection
+	// for the named controller, or updates the stored details if
+	// a model with the same name already exists.
+	UpdateModel(controllerName, modelName string, details ModelDetails) error
+
+	// SetModels replaces the collection of models for the named
+	// controller with the given models. Models stored for the
+	// controller that are not in the given collection are removed.
+	SetModels(controllerName string, models map[string]ModelDetails) error
+}
+
+// ModelRemover removes models.
+type ModelRemover interface {
+	// RemoveModel removes the model with the given controller and
+	// model names from the models collection. Removing a model that
+	// does not exist is not an error.
+	RemoveModel(controllerName, modelName string) error
+}
+
+// ModelGetter gets models.
+type ModelGetter interface {
+	// AllModels gets all models for the specified controller.
+	AllModels(controllerName string) (map[string]ModelDetails, error)
+
+	// ModelByName returns the model with the specified controller
+	// and model names. If there exists no model with the specified
+	// names, an error satisfying errors.IsNotFound will be returned.
+	ModelByName(controllerName, modelName string) (*ModelDetails, error)
+}
+
+// ModelStore is an amalgamation of ModelUpdater, ModelRemover and
+// ModelGetter.
+type ModelStore interface {
+	ModelUpdater
+	ModelRemover
+	ModelGetter
+}
+
+// AccountUpdater stores account details.
+type AccountUpdater interface {
+	// UpdateAccount adds or updates the account associated with
+	// the named controller.
+	UpdateAccount(controllerName string, details AccountDetails) error
+}
+
+// AccountRemover removes accounts.
+type AccountRemover interface {
+	// RemoveAccount removes the account associated with the named
+	// controller. Removing an account that does not exist is not
+	// an error.
+	RemoveAccount(controllerName string) error
+}
+
+// AccountGetter gets accounts.
+type AccountGetter interface {
+	// AccountByName returns the account associated with the named
+	// controller. If there is no such account, an error satisfying
+	// errors.IsNotFound will be returned.
+	AccountByName(controllerName string) (*AccountDetails, error)
+}
+
+// AccountStore is an amalgamation of AccountUpdater, AccountRemover
+// and AccountGetter.
+type AccountStore interface {
+	AccountUpdater
+	AccountRemover
+	AccountGetter
+}
+
+// AddressHealthStore stores the outcome of attempts to connect to
+// API addresses, so that addresses known to work can be tried first.
+type AddressHealthStore interface {
+	// AddressHealth returns the recorded health of each of the given
+	// addresses that has any. Addresses with no recorded health are
+	// omitted from the result.
+	AddressHealth(addrs []string) (map[string]AddressHealth, error)
+
+	// RecordAddressHealth records the outcome of an attempt to
+	// connect to the given address, made at the given time.
+	RecordAddressHealth(addr string, success bool, when time.Time) error
+}
+
+// EntityNameStore caches the names of the entities in models, so that
+// they can be completed in a shell without connecting to the API.
+type EntityNameStore interface {
+	// EntityNames returns the cached names of the entities in the
+	// named model. If no names are cached for the model, an error
+	// satisfying errors.IsNotFound will be returned.
+	EntityNames(modelName string) (*EntityNames, error)
+
+	// SetEntityNames replaces the cached names of the entities in
+	// the named model.
+	SetEntityNames(modelName string, names EntityNames) error
+}
+
+// ClientStore is an amalgamation of ControllerStore, ModelStore,
+// AccountStore, AddressHealthStore and EntityNameStore, providing
+// access to all of the client-side state.
+type ClientStore interface {
+	ControllerStore
+	ModelStore
+	AccountStore
+	AddressHealthStore
+	EntityNameStore
+}
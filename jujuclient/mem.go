@@ -0,0 +1,266 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+type memStore struct {
+	mu            sync.Mutex
+	controllers   map[string]ControllerDetails
+	models        map[string]map[string]ModelDetails
+	accounts      map[string]AccountDetails
+	addressHealth map[string]AddressHealth
+	entityNames   map[string]EntityNames
+}
+
+// NewMemStore returns a ClientStore implementation that
+// stores details in memory.
+func NewMemStore() ClientStore {
+	return &memStore{
+		controllers:   make(map[string]ControllerDetails),
+		models:        make(map[string]map[string]ModelDetails),
+		accounts:      make(map[string]AccountDetails),
+		addressHealth: make(map[string]AddressHealth),
+		entityNames:   make(map[string]EntityNames),
+	}
+}
+
+// AllControllers implements ControllerGetter.
+func (m *memStore) AllControllers() (map[string]ControllerDetails, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]ControllerDetails)
+	for name, details := range m.controllers {
+		result[name] = details
+	}
+	return result, nil
+}
+
+// ControllerByName implements ControllerGetter.
+func (m *memStore) ControllerByName(controllerName string) (*ControllerDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	details, ok := m.controllers[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("controller %s", controllerName)
+	}
+	return &details, nil
+}
+
+// UpdateController implements ControllerUpdater.
+func (m *memStore) UpdateController(controllerName string, details ControllerDetails) error {
+	if err := validateControllerDetails(controllerName, details); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.controllers[controllerName] = details
+	return nil
+}
+
+// RemoveController implements ControllerRemover.
+func (m *memStore) RemoveController(controllerName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.models, controllerName)
+	delete(m.accounts, controllerName)
+	delete(m.controllers, controllerName)
+	return nil
+}
+
+// AllModels implements ModelGetter.
+func (m *memStore) AllModels(controllerName string) (map[string]ModelDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	models, ok := m.models[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	result := make(map[string]ModelDetails)
+	for name, details := range models {
+		result[name] = details
+	}
+	return result, nil
+}
+
+// ModelByName implements ModelGetter.
+func (m *memStore) ModelByName(controllerName, modelName string) (*ModelDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	models, ok := m.models[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("models for controller %s", controllerName)
+	}
+	details, ok := models[modelName]
+	if !ok {
+		return nil, errors.NotFoundf("model %s:%s", controllerName, modelName)
+	}
+	return &details, nil
+}
+
+// UpdateModel implements ModelUpdater.
+func (m *memStore) UpdateModel(controllerName, modelName string, details ModelDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	models, ok := m.models[controllerName]
+	if !ok {
+		models = make(map[string]ModelDetails)
+		m.models[controllerName] = models
+	}
+	models[modelName] = details
+	return nil
+}
+
+// SetModels implements ModelUpdater.
+func (m *memStore) SetModels(controllerName string, models map[string]ModelDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	for modelName, details := range models {
+		if err := validateModelName(modelName); err != nil {
+			return errors.Trace(err)
+		}
+		if err := validateModelDetails(details); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	controllerModels := make(map[string]ModelDetails)
+	for modelName, details := range models {
+		controllerModels[modelName] = details
+	}
+	m.models[controllerName] = controllerModels
+	return nil
+}
+
+// RemoveModel implements ModelRemover.
+func (m *memStore) RemoveModel(controllerName, modelName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if models, ok := m.models[controllerName]; ok {
+		delete(models, modelName)
+	}
+	return nil
+}
+
+// AccountByName implements AccountGetter.
+func (m *memStore) AccountByName(controllerName string) (*AccountDetails, error) {
+	if err := validateControllerName(controllerName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	details, ok := m.accounts[controllerName]
+	if !ok {
+		return nil, errors.NotFoundf("account for controller %s", controllerName)
+	}
+	return &details, nil
+}
+
+// UpdateAccount implements AccountUpdater.
+func (m *memStore) UpdateAccount(controllerName string, details AccountDetails) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateAccountDetails(details); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[controllerName] = details
+	return nil
+}
+
+// RemoveAccount implements AccountRemover.
+func (m *memStore) RemoveAccount(controllerName string) error {
+	if err := validateControllerName(controllerName); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.accounts, controllerName)
+	return nil
+}
+
+// AddressHealth implements AddressHealthStore.
+func (m *memStore) AddressHealth(addrs []string) (map[string]AddressHealth, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make(map[string]AddressHealth)
+	for _, addr := range addrs {
+		if health, ok := m.addressHealth[addr]; ok {
+			result[addr] = health
+		}
+	}
+	return result, nil
+}
+
+// RecordAddressHealth implements AddressHealthStore.
+func (m *memStore) RecordAddressHealth(addr string, success bool, when time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addressHealth[addr] = m.addressHealth[addr].Record(success, when)
+	return nil
+}
+
+// EntityNames implements EntityNameStore.
+func (m *memStore) EntityNames(modelName string) (*EntityNames, error) {
+	if err := validateModelName(modelName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names, ok := m.entityNames[modelName]
+	if !ok {
+		return nil, errors.NotFoundf("entity names for model %s", modelName)
+	}
+	return &names, nil
+}
+
+// SetEntityNames implements EntityNameStore.
+func (m *memStore) SetEntityNames(modelName string, names EntityNames) error {
+	if err := validateModelName(modelName); err != nil {
+		return errors.Trace(err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entityNames[modelName] = names
+	return nil
+}
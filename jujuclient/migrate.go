@@ -0,0 +1,241 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// migratedMarker is the name of the file, relative to $JUJU_HOME, whose
+// existence records that the legacy configstore entries have already
+// been migrated into the client store.
+const migratedMarker = ".client-store-migrated"
+
+// Migration holds the client store entries derived from the legacy
+// configstore environment information.
+type Migration struct {
+	// Controllers maps controller names to the details to be stored.
+	Controllers map[string]ControllerDetails
+
+	// Models maps controller names to the models to be stored
+	// for each of them, keyed by model name.
+	Models map[string]map[string]ModelDetails
+
+	// Accounts maps controller names to the account to be stored.
+	Accounts map[string]AccountDetails
+
+	// Skipped lists the names of legacy environments that could not
+	// be migrated, along with the reason why.
+	Skipped map[string]string
+}
+
+// NewMigration reads all of the environment information held in the
+// legacy store and computes the equivalent client store entries.
+//
+// Every environment that is its own server (that is, a state server
+// environment) becomes a controller with a model of the same name.
+// Hosted environments become models of the controller whose UUID
+// matches their server UUID. Environments that have never been
+// bootstrapped have no API details, and are skipped, as are those
+// whose details the client store would reject, such as environments
+// recorded without a CA certificate.
+func NewMigration(legacy configstore.Storage) (*Migration, error) {
+	names, err := legacy.List()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list legacy environments")
+	}
+	sort.Strings(names)
+
+	m := &Migration{
+		Controllers: make(map[string]ControllerDetails),
+		Models:      make(map[string]map[string]ModelDetails),
+		Accounts:    make(map[string]AccountDetails),
+		Skipped:     make(map[string]string),
+	}
+	infos := make(map[string]configstore.EnvironInfo)
+	for _, name := range names {
+		info, err := legacy.ReadInfo(name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read legacy environment %q", name)
+		}
+		endpoint := info.APIEndpoint()
+		if endpoint.EnvironUUID == "" || len(endpoint.Addresses) == 0 {
+			m.Skipped[name] = "environment has not been bootstrapped"
+			continue
+		}
+		infos[name] = info
+	}
+
+	// First record the state server environments as controllers, so
+	// that hosted environments can be attached to them by UUID.
+	controllerNames := make(map[string]string)
+	for _, name := range names {
+		info, ok := infos[name]
+		if !ok {
+			continue
+		}
+		endpoint := info.APIEndpoint()
+		if serverUUID(endpoint) != endpoint.EnvironUUID {
+			continue
+		}
+		if err := m.addController(name, endpoint, info.APICredentials()); err != nil {
+			m.Skipped[name] = err.Error()
+			delete(infos, name)
+			continue
+		}
+		controllerNames[endpoint.EnvironUUID] = name
+	}
+
+	for _, name := range names {
+		info, ok := infos[name]
+		if !ok {
+			continue
+		}
+		endpoint := info.APIEndpoint()
+		uuid := serverUUID(endpoint)
+		controllerName, ok := controllerNames[uuid]
+		if !ok {
+			// The state server environment for this hosted
+			// environment is not known locally, so we name the
+			// controller after the first hosted environment seen.
+			controllerName = name
+			if _, exists := m.Controllers[controllerName]; exists {
+				m.Skipped[name] = fmt.Sprintf("controller name %q already in use", name)
+				continue
+			}
+			if err := m.addController(controllerName, endpoint, info.APICredentials()); err != nil {
+				m.Skipped[name] = err.Error()
+				continue
+			}
+			controllerNames[uuid] = controllerName
+		}
+		models, ok := m.Models[controllerName]
+		if !ok {
+			models = make(map[string]ModelDetails)
+			m.Models[controllerName] = models
+		}
+		models[name] = ModelDetails{ModelUUID: endpoint.EnvironUUID}
+	}
+	return m, nil
+}
+
+func serverUUID(endpoint configstore.APIEndpoint) string {
+	// Older servers do not report their server UUID, in which
+	// case the environment can only be a state server environment.
+	if endpoint.ServerUUID == "" {
+		return endpoint.EnvironUUID
+	}
+	return endpoint.ServerUUID
+}
+
+// addController records a controller for the given legacy environment
+// details. It returns an error, and records nothing, if the client
+// store would reject the controller.
+func (m *Migration) addController(name string, endpoint configstore.APIEndpoint, creds configstore.APICredentials) error {
+	details := ControllerDetails{
+		Servers:        endpoint.Hostnames,
+		ControllerUUID: serverUUID(endpoint),
+		APIEndpoints:   endpoint.Addresses,
+		CACert:         endpoint.CACert,
+	}
+	if err := validateControllerDetails(name, details); err != nil {
+		return errors.Trace(err)
+	}
+	m.Controllers[name] = details
+	user := creds.User
+	if user == "" {
+		user = configstore.DefaultAdminUsername
+	}
+	m.Accounts[name] = AccountDetails{
+		User:     user,
+		Password: creds.Password,
+	}
+	return nil
+}
+
+// Steps returns a sorted, human readable description of each change
+// the migration will make to the client store.
+func (m *Migration) Steps() []string {
+	var steps []string
+	for name, details := range m.Controllers {
+		steps = append(steps, fmt.Sprintf("add controller %q (%s)", name, details.ControllerUUID))
+	}
+	for name, details := range m.Accounts {
+		steps = append(steps, fmt.Sprintf("add account %q for controller %q", details.User, name))
+	}
+	for controllerName, models := range m.Models {
+		for name, details := range models {
+			steps = append(steps, fmt.Sprintf("add model %q (%s) to controller %q", name, details.ModelUUID, controllerName))
+		}
+	}
+	sort.Strings(steps)
+	return steps
+}
+
+// Apply writes the migrated entries into the given client store.
+// Existing entries with the same names are overwritten.
+func (m *Migration) Apply(store ClientStore) error {
+	for name, details := range m.Controllers {
+		if err := store.UpdateController(name, details); err != nil {
+			return errors.Annotatef(err, "cannot migrate controller %q", name)
+		}
+	}
+	for name, details := range m.Accounts {
+		if err := store.UpdateAccount(name, details); err != nil {
+			return errors.Annotatef(err, "cannot migrate account for controller %q", name)
+		}
+	}
+	for controllerName, models := range m.Models {
+		for name, details := range models {
+			if err := store.UpdateModel(controllerName, name, details); err != nil {
+				return errors.Annotatef(err, "cannot migrate model %q", name)
+			}
+		}
+	}
+	return nil
+}
+
+// MigrateLegacyStoreOnce migrates the legacy configstore entries into
+// the client store, unless that has already been done for the current
+// $JUJU_HOME.
+func MigrateLegacyStoreOnce(legacy configstore.Storage, store ClientStore) error {
+	marker := osenv.JujuHomePath(migratedMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	m, err := NewMigration(legacy)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := m.Apply(store); err != nil {
+		return errors.Trace(err)
+	}
+	for _, step := range m.Steps() {
+		logger.Debugf("migrated local store: %s", step)
+	}
+	for name, reason := range m.Skipped {
+		logger.Debugf("not migrating environment %q: %s", name, reason)
+	}
+	return MarkLegacyStoreMigrated()
+}
+
+// MarkLegacyStoreMigrated records that the legacy configstore entries
+// have been migrated, so that MigrateLegacyStoreOnce does nothing.
+func MarkLegacyStoreMigrated() error {
+	marker := osenv.JujuHomePath(migratedMarker)
+	f, err := os.OpenFile(marker, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Annotate(err, "cannot record local store migration")
+	}
+	return f.Close()
+}
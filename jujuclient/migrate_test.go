@@ -0,0 +1,153 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type migrateSuite struct {
+	testing.FakeJujuHomeSuite
+	legacy configstore.Storage
+}
+
+var _ = gc.Suite(&migrateSuite{})
+
+func (s *migrateSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.legacy = configstore.NewMem()
+}
+
+func (s *migrateSuite) writeLegacy(c *gc.C, name, envUUID, serverUUID, user string) {
+	info := s.legacy.CreateInfo(name)
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"10.0.0.1:17070"},
+		Hostnames:   []string{"example.com:17070"},
+		CACert:      testing.CACert,
+		EnvironUUID: envUUID,
+		ServerUUID:  serverUUID,
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     user,
+		Password: "secret",
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *migrateSuite) TestNewMigration(c *gc.C) {
+	s.writeLegacy(c, "local", "server-uuid", "server-uuid", "admin")
+	s.writeLegacy(c, "hosted", "hosted-uuid", "server-uuid", "bob")
+	s.writeLegacy(c, "orphan", "orphan-uuid", "other-server-uuid", "")
+	err := s.legacy.CreateInfo("unbootstrapped").Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := jujuclient.NewMigration(s.legacy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Controllers, jc.DeepEquals, map[string]jujuclient.ControllerDetails{
+		"local": {
+			Servers:        []string{"example.com:17070"},
+			ControllerUUID: "server-uuid",
+			APIEndpoints:   []string{"10.0.0.1:17070"},
+			CACert:         testing.CACert,
+		},
+		"orphan": {
+			Servers:        []string{"example.com:17070"},
+			ControllerUUID: "other-server-uuid",
+			APIEndpoints:   []string{"10.0.0.1:17070"},
+			CACert:         testing.CACert,
+		},
+	})
+	c.Assert(m.Models, jc.DeepEquals, map[string]map[string]jujuclient.ModelDetails{
+		"local": {
+			"local":  {ModelUUID: "server-uuid"},
+			"hosted": {ModelUUID: "hosted-uuid"},
+		},
+		"orphan": {
+			"orphan": {ModelUUID: "orphan-uuid"},
+		},
+	})
+	c.Assert(m.Accounts, jc.DeepEquals, map[string]jujuclient.AccountDetails{
+		"local":  {User: "admin", Password: "secret"},
+		"orphan": {User: "admin", Password: "secret"},
+	})
+	c.Assert(m.Skipped, jc.DeepEquals, map[string]string{
+		"unbootstrapped": "environment has not been bootstrapped",
+	})
+	c.Assert(m.Steps(), jc.DeepEquals, []string{
+		`add account "admin" for controller "local"`,
+		`add account "admin" for controller "orphan"`,
+		`add controller "local" (server-uuid)`,
+		`add controller "orphan" (other-server-uuid)`,
+		`add model "hosted" (hosted-uuid) to controller "local"`,
+		`add model "local" (server-uuid) to controller "local"`,
+		`add model "orphan" (orphan-uuid) to controller "orphan"`,
+	})
+}
+
+func (s *migrateSuite) TestServerUUIDMissing(c *gc.C) {
+	s.writeLegacy(c, "old", "env-uuid", "", "admin")
+	m, err := jujuclient.NewMigration(s.legacy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Controllers["old"].ControllerUUID, gc.Equals, "env-uuid")
+	c.Assert(m.Models["old"], jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"old": {ModelUUID: "env-uuid"},
+	})
+}
+
+func (s *migrateSuite) TestCACertMissing(c *gc.C) {
+	s.writeLegacy(c, "local", "server-uuid", "server-uuid", "admin")
+	s.writeLegacy(c, "hosted", "hosted-uuid", "server-uuid", "admin")
+	s.writeLegacy(c, "old", "old-uuid", "old-uuid", "admin")
+	for _, name := range []string{"local", "hosted"} {
+		info, err := s.legacy.ReadInfo(name)
+		c.Assert(err, jc.ErrorIsNil)
+		endpoint := info.APIEndpoint()
+		endpoint.CACert = ""
+		info.SetAPIEndpoint(endpoint)
+		err = info.Write()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	m, err := jujuclient.NewMigration(s.legacy)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Skipped, jc.DeepEquals, map[string]string{
+		"local":  "missing ca-cert, controller details not valid",
+		"hosted": "missing ca-cert, controller details not valid",
+	})
+	c.Assert(m.Steps(), jc.DeepEquals, []string{
+		`add account "admin" for controller "old"`,
+		`add controller "old" (old-uuid)`,
+		`add model "old" (old-uuid) to controller "old"`,
+	})
+
+	store := jujuclient.NewMemStore()
+	err = jujuclient.MigrateLegacyStoreOnce(s.legacy, store)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = store.ControllerByName("old")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *migrateSuite) TestMigrateLegacyStoreOnce(c *gc.C) {
+	s.writeLegacy(c, "local", "server-uuid", "server-uuid", "admin")
+	store := jujuclient.NewMemStore()
+	err := jujuclient.MigrateLegacyStoreOnce(s.legacy, store)
+	c.Assert(err, jc.ErrorIsNil)
+	model, err := store.ModelByName("local", "local")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.ModelUUID, gc.Equals, "server-uuid")
+
+	// A second migration does nothing, even if there are
+	// new legacy entries.
+	s.writeLegacy(c, "another", "another-uuid", "another-uuid", "admin")
+	err = jujuclient.MigrateLegacyStoreOnce(s.legacy, store)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = store.ControllerByName("another")
+	c.Assert(err, gc.ErrorMatches, "controller another not found")
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// JujuModelsPath is the location where models information is
+// expected to be found.
+func JujuModelsPath() string {
+	return osenv.JujuHomePath("models.yaml")
+}
+
+// ModelsFile represents the YAML structure of the file
+// $JUJU_HOME/models.yaml.
+type ModelsFile struct {
+	// Controllers maps the name of a controller to the models
+	// known to be hosted by it.
+	Controllers map[string]ControllerModels `yaml:"controllers"`
+}
+
+// ControllerModels stores per-controller models information.
+type ControllerModels struct {
+	// Models is the collection of models for the controller,
+	// keyed by model name.
+	Models map[string]ModelDetails `yaml:"models"`
+}
+
+// ReadModelsFile loads all models defined in a given file.
+// If the file is not found, it is not an error.
+func ReadModelsFile(file string) (map[string]ControllerModels, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	models, err := ParseModels(data)
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// WriteModelsFile marshals to YAML details of the given models
+// and writes it to the models file.
+func WriteModelsFile(models map[string]ControllerModels) error {
+	data, err := goyaml.Marshal(ModelsFile{models})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal models")
+	}
+	return utils.AtomicWriteFile(JujuModelsPath(), data, os.FileMode(0600))
+}
+
+// ParseModels parses the given YAML bytes into models metadata.
+func ParseModels(data []byte) (map[string]ControllerModels, error) {
+	var result ModelsFile
+	if err := goyaml.Unmarshal(data, &result); err != nil {
+		return nil, errors.Annotate(err, "cannot unmarshal models")
+	}
+	return result.Controllers, nil
+}
@@ -0,0 +1,275 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+// storeSuite holds tests that are run against every ClientStore
+// implementation.
+type storeSuite struct {
+	testing.FakeJujuHomeSuite
+	NewStore func() jujuclient.ClientStore
+	store    jujuclient.ClientStore
+}
+
+var _ = gc.Suite(&fileStoreSuite{})
+var _ = gc.Suite(&memStoreSuite{})
+
+type fileStoreSuite struct {
+	storeSuite
+}
+
+func (s *fileStoreSuite) SetUpTest(c *gc.C) {
+	s.NewStore = jujuclient.NewFileClientStore
+	s.storeSuite.SetUpTest(c)
+}
+
+func (s *fileStoreSuite) TestPersistence(c *gc.C) {
+	err := s.store.UpdateController("ctrl", testControllerDetails)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel("ctrl", "admin", jujuclient.ModelDetails{ModelUUID: "model-uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	other := jujuclient.NewFileClientStore()
+	details, err := other.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*details, jc.DeepEquals, testControllerDetails)
+	model, err := other.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.ModelUUID, gc.Equals, "model-uuid")
+}
+
+func (s *fileStoreSuite) TestAddressHealthPersistence(c *gc.C) {
+	// Times are stored with limited precision.
+	now := time.Now().Truncate(time.Second)
+	err := s.store.RecordAddressHealth("10.0.0.1:17070", true, now)
+	c.Assert(err, jc.ErrorIsNil)
+
+	other := jujuclient.NewFileClientStore()
+	health, err := other.AddressHealth([]string{"10.0.0.1:17070"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, gc.HasLen, 1)
+	c.Assert(health["10.0.0.1:17070"].LastSuccess.Equal(now), jc.IsTrue)
+}
+
+type memStoreSuite struct {
+	storeSuite
+}
+
+func (s *memStoreSuite) SetUpTest(c *gc.C) {
+	s.NewStore = jujuclient.NewMemStore
+	s.storeSuite.SetUpTest(c)
+}
+
+var testControllerDetails = jujuclient.ControllerDetails{
+	Servers:        []string{"hostname1:17070"},
+	ControllerUUID: "controller-uuid",
+	APIEndpoints:   []string{"10.0.0.1:17070"},
+	CACert:         testing.CACert,
+}
+
+func (s *storeSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.store = s.NewStore()
+}
+
+func (s *storeSuite) TestControllerByNameNotFound(c *gc.C) {
+	_, err := s.store.ControllerByName("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storeSuite) TestUpdateController(c *gc.C) {
+	err := s.store.UpdateController("ctrl", testControllerDetails)
+	c.Assert(err, jc.ErrorIsNil)
+	details, err := s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*details, jc.DeepEquals, testControllerDetails)
+
+	all, err := s.store.AllControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, jc.DeepEquals, map[string]jujuclient.ControllerDetails{
+		"ctrl": testControllerDetails,
+	})
+}
+
+func (s *storeSuite) TestUpdateControllerInvalid(c *gc.C) {
+	err := s.store.UpdateController("", testControllerDetails)
+	c.Assert(err, gc.ErrorMatches, "empty controller name not valid")
+	details := testControllerDetails
+	details.ControllerUUID = ""
+	err = s.store.UpdateController("ctrl", details)
+	c.Assert(err, gc.ErrorMatches, "missing uuid, controller details not valid")
+}
+
+func (s *storeSuite) TestRemoveController(c *gc.C) {
+	err := s.store.UpdateController("ctrl", testControllerDetails)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel("ctrl", "admin", jujuclient.ModelDetails{ModelUUID: "model-uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{User: "admin"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ControllerByName("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.store.AllModels("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.store.AccountByName("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing again is not an error.
+	err = s.store.RemoveController("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storeSuite) TestModels(c *gc.C) {
+	_, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.store.UpdateModel("ctrl", "admin", jujuclient.ModelDetails{ModelUUID: "uuid-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel("ctrl", "other", jujuclient.ModelDetails{ModelUUID: "uuid-2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	models, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"admin": {ModelUUID: "uuid-1"},
+		"other": {ModelUUID: "uuid-2"},
+	})
+
+	err = s.store.RemoveModel("ctrl", "other")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.ModelByName("ctrl", "other")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	model, err := s.store.ModelByName("ctrl", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.ModelUUID, gc.Equals, "uuid-1")
+}
+
+func (s *storeSuite) TestSetModels(c *gc.C) {
+	err := s.store.UpdateModel("ctrl", "stale", jujuclient.ModelDetails{ModelUUID: "uuid-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.UpdateModel("other-ctrl", "kept", jujuclient.ModelDetails{ModelUUID: "uuid-3"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.store.SetModels("ctrl", map[string]jujuclient.ModelDetails{
+		"admin": {ModelUUID: "uuid-1"},
+		"other": {ModelUUID: "uuid-2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	models, err := s.store.AllModels("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"admin": {ModelUUID: "uuid-1"},
+		"other": {ModelUUID: "uuid-2"},
+	})
+	models, err = s.store.AllModels("other-ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(models, jc.DeepEquals, map[string]jujuclient.ModelDetails{
+		"kept": {ModelUUID: "uuid-3"},
+	})
+}
+
+func (s *storeSuite) TestSetModelsInvalid(c *gc.C) {
+	err := s.store.SetModels("ctrl", map[string]jujuclient.ModelDetails{
+		"admin": {},
+	})
+	c.Assert(err, gc.ErrorMatches, "missing uuid, model details not valid")
+}
+
+func (s *storeSuite) TestUpdateModelInvalid(c *gc.C) {
+	err := s.store.UpdateModel("ctrl", "", jujuclient.ModelDetails{ModelUUID: "uuid"})
+	c.Assert(err, gc.ErrorMatches, "empty model name not valid")
+	err = s.store.UpdateModel("ctrl", "admin", jujuclient.ModelDetails{})
+	c.Assert(err, gc.ErrorMatches, "missing uuid, model details not valid")
+}
+
+func (s *storeSuite) TestAccounts(c *gc.C) {
+	_, err := s.store.AccountByName("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	account := jujuclient.AccountDetails{User: "admin", Password: "secret", SessionToken: "token"}
+	err = s.store.UpdateAccount("ctrl", account)
+	c.Assert(err, jc.ErrorIsNil)
+	details, err := s.store.AccountByName("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*details, jc.DeepEquals, account)
+
+	err = s.store.RemoveAccount("ctrl")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.AccountByName("ctrl")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storeSuite) TestUpdateAccountInvalid(c *gc.C) {
+	err := s.store.UpdateAccount("ctrl", jujuclient.AccountDetails{})
+	c.Assert(err, gc.ErrorMatches, "missing user, account details not valid")
+}
+
+func (s *storeSuite) TestAddressHealth(c *gc.C) {
+	health, err := s.store.AddressHealth([]string{"10.0.0.1:17070"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, gc.HasLen, 0)
+
+	now := time.Now().Truncate(time.Second)
+	err = s.store.RecordAddressHealth("10.0.0.1:17070", false, now)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.RecordAddressHealth("10.0.0.1:17070", false, now.Add(time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.RecordAddressHealth("10.0.0.2:17070", true, now)
+	c.Assert(err, jc.ErrorIsNil)
+
+	health, err = s.store.AddressHealth([]string{"10.0.0.1:17070", "10.0.0.3:17070"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health, gc.HasLen, 1)
+	failed := health["10.0.0.1:17070"]
+	c.Assert(failed.LastFailure.Equal(now.Add(time.Second)), jc.IsTrue)
+	c.Assert(failed.LastSuccess.IsZero(), jc.IsTrue)
+	c.Assert(failed.ConsecutiveFailures, gc.Equals, 2)
+
+	err = s.store.RecordAddressHealth("10.0.0.1:17070", true, now.Add(2*time.Second))
+	c.Assert(err, jc.ErrorIsNil)
+	health, err = s.store.AddressHealth([]string{"10.0.0.1:17070"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(health["10.0.0.1:17070"].ConsecutiveFailures, gc.Equals, 0)
+}
+
+func (s *storeSuite) TestEntityNames(c *gc.C) {
+	_, err := s.store.EntityNames("admin")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	names := jujuclient.EntityNames{
+		Machines: []string{"0", "0/lxc/0"},
+		Services: []string{"mysql"},
+		Units:    []string{"mysql/0"},
+	}
+	err = s.store.SetEntityNames("admin", names)
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := s.store.EntityNames("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*got, jc.DeepEquals, names)
+
+	err = s.store.SetEntityNames("admin", jujuclient.EntityNames{Machines: []string{"1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	got, err = s.store.EntityNames("admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*got, jc.DeepEquals, jujuclient.EntityNames{Machines: []string{"1"}})
+}
+
+func (s *storeSuite) TestSetEntityNamesInvalid(c *gc.C) {
+	err := s.store.SetEntityNames("", jujuclient.EntityNames{})
+	c.Assert(err, gc.ErrorMatches, "empty model name not valid")
+}
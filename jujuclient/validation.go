@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuclient
+
+import (
+	"github.com/juju/errors"
+)
+
+func validateControllerName(name string) error {
+	if name == "" {
+		return errors.NotValidf("empty controller name")
+	}
+	return nil
+}
+
+func validateControllerDetails(name string, details ControllerDetails) error {
+	if err := validateControllerName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if details.ControllerUUID == "" {
+		return errors.NotValidf("missing uuid, controller details")
+	}
+	if details.CACert == "" {
+		return errors.NotValidf("missing ca-cert, controller details")
+	}
+	return nil
+}
+
+func validateModelName(name string) error {
+	if name == "" {
+		return errors.NotValidf("empty model name")
+	}
+	return nil
+}
+
+func validateModelDetails(details ModelDetails) error {
+	if details.ModelUUID == "" {
+		return errors.NotValidf("missing uuid, model details")
+	}
+	return nil
+}
+
+func validateAccountDetails(details AccountDetails) error {
+	if details.User == "" {
+		return errors.NotValidf("missing user, account details")
+	}
+	return nil
+}
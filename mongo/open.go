@@ -55,6 +55,36 @@ type DialOpts struct {
 	PostDial func(*mgo.Session) error
 }
 
+// SessionConfig holds settings that tune the behaviour of an established
+// mgo.Session. Zero values leave the corresponding settings unchanged.
+type SessionConfig struct {
+	// PoolLimit limits the number of sockets the session may hold
+	// open to each server.
+	PoolLimit int
+
+	// SocketTimeout is the amount of time to wait for a
+	// non-responding socket before it is forcefully closed.
+	SocketTimeout time.Duration
+
+	// SyncTimeout is the amount of time an operation waits for a
+	// suitable server to become available.
+	SyncTimeout time.Duration
+}
+
+// Apply applies the non-zero settings in c to the given session.
+// Sessions subsequently copied from it share the same settings.
+func (c SessionConfig) Apply(session *mgo.Session) {
+	if c.PoolLimit != 0 {
+		session.SetPoolLimit(c.PoolLimit)
+	}
+	if c.SocketTimeout != 0 {
+		session.SetSocketTimeout(c.SocketTimeout)
+	}
+	if c.SyncTimeout != 0 {
+		session.SetSyncTimeout(c.SyncTimeout)
+	}
+}
+
 // DefaultDialOpts returns a DialOpts representing the default
 // parameters for contacting a state server.
 func DefaultDialOpts() DialOpts {
@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo
+
+import (
+	"sort"
+
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/introspection"
+)
+
+// serverStatus holds the parts of the output of mongo's serverStatus
+// command that are reported as metrics.
+type serverStatus struct {
+	Connections struct {
+		Current   int64 `bson:"current"`
+		Available int64 `bson:"available"`
+	} `bson:"connections"`
+	Opcounters map[string]int64 `bson:"opcounters"`
+}
+
+// NewStatsCollector returns a collector that reports the connection
+// and operation counts of the mongo server that the given session is
+// connected to.
+func NewStatsCollector(session *mgo.Session) introspection.Collector {
+	return introspection.CollectorFunc(func() []introspection.Family {
+		s := session.Copy()
+		defer s.Close()
+		var status serverStatus
+		if err := s.Run(bson.D{{"serverStatus", 1}}, &status); err != nil {
+			logger.Debugf("cannot get mongo server status: %v", err)
+			return nil
+		}
+		ops := make([]string, 0, len(status.Opcounters))
+		for op := range status.Opcounters {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		opcounters := introspection.Family{
+			Name: "juju_mongo_operations_total",
+			Help: "Operations run by the mongo server since it started.",
+			Type: introspection.CounterType,
+		}
+		for _, op := range ops {
+			opcounters.Samples = append(opcounters.Samples, introspection.Sample{
+				Labels: introspection.Labels{"op": op},
+				Value:  float64(status.Opcounters[op]),
+			})
+		}
+		return []introspection.Family{{
+			Name: "juju_mongo_connections",
+			Help: "Connections to the mongo server.",
+			Type: introspection.GaugeType,
+			Samples: []introspection.Sample{{
+				Labels: introspection.Labels{"state": "current"},
+				Value:  float64(status.Connections.Current),
+			}, {
+				Labels: introspection.Labels{"state": "available"},
+				Value:  float64(status.Connections.Available),
+			}},
+		}, opcounters}
+	})
+}
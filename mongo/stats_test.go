@@ -0,0 +1,43 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongo_test
+
+import (
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/introspection"
+	"github.com/juju/juju/mongo"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type statsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&statsSuite{})
+
+func (s *statsSuite) TestStatsCollector(c *gc.C) {
+	inst := &gitjujutesting.MgoInstance{}
+	err := inst.Start(coretesting.Certs)
+	c.Assert(err, jc.ErrorIsNil)
+	defer inst.DestroyWithLog()
+	session := inst.MustDial()
+	defer session.Close()
+
+	families := mongo.NewStatsCollector(session).Collect()
+	c.Assert(families, gc.HasLen, 2)
+	c.Assert(families[0].Name, gc.Equals, "juju_mongo_connections")
+	c.Assert(families[0].Type, gc.Equals, introspection.GaugeType)
+	c.Assert(families[0].Samples, gc.HasLen, 2)
+	c.Assert(families[0].Samples[0].Value > 0, jc.IsTrue)
+	c.Assert(families[1].Name, gc.Equals, "juju_mongo_operations_total")
+	ops := make(map[string]bool)
+	for _, sample := range families[1].Samples {
+		ops[sample.Labels["op"]] = true
+	}
+	c.Assert(ops["query"], jc.IsTrue)
+	c.Assert(ops["insert"], jc.IsTrue)
+}
@@ -56,6 +56,11 @@ type SubnetInfo struct {
 	// allocatable.
 	AllocatableIPLow  net.IP
 	AllocatableIPHigh net.IP
+
+	// AvailabilityZone is the name of the availability zone in which
+	// the subnet resides. It is empty if the provider does not
+	// support availability zones or has not reported it.
+	AvailabilityZone string
 }
 
 // InterfaceConfigType defines valid network interface configuration
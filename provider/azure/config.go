@@ -24,6 +24,15 @@ var configFields = schema.Fields{
 	"force-image-name":            schema.String(),
 	"availability-sets-enabled":   schema.Bool(),
 }
+
+var configSchema = config.Schema{
+	"management-certificate": {
+		Description: "The PEM-encoded certificate used to manage the Azure subscription",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configDefaults = schema.Defaults{
 	"location":                    "",
 	"management-certificate":      "",
@@ -94,6 +94,7 @@ type azureEnviron struct {
 
 // azureEnviron implements Environ and HasRegion.
 var _ environs.Environ = (*azureEnviron)(nil)
+var _ environs.ConstraintsNormalizer = (*azureEnviron)(nil)
 var _ simplestreams.HasRegion = (*azureEnviron)(nil)
 var _ state.Prechecker = (*azureEnviron)(nil)
 
@@ -569,6 +570,12 @@ var unsupportedConstraints = []string{
 	constraints.Tags,
 }
 
+// NormalizeConstraints is specified in the environs.ConstraintsNormalizer
+// interface.
+func (env *azureEnviron) NormalizeConstraints(cons constraints.Value) (instances.Normalization, error) {
+	return normalizeConstraints(env, cons)
+}
+
 // ConstraintsValidator is defined on the Environs interface.
 func (env *azureEnviron) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
@@ -763,6 +770,9 @@ func (env *azureEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 	// All other machines get an auto-generated public port for SSH.
 	stateServer := multiwatcher.AnyJobNeedsState(args.InstanceConfig.Jobs...)
 	role := env.newRole(instanceType.Id, vhd, userData, stateServer)
+	if snapshot.ecfg.availabilitySetsEnabled() {
+		role.AvailabilitySetName = availabilitySetName(args.DistributionGroupName)
+	}
 	inst, err := createInstance(env, snapshot.api, role, cloudServiceName, stateServer)
 	if err != nil {
 		return nil, err
@@ -904,6 +914,17 @@ func (env *azureEnviron) newRole(roleSize string, vhd *gwacl.OSVirtualHardDisk,
 	return role
 }
 
+// availabilitySetName returns the name of the availability set in which
+// to place the instances of the named distribution group, so that the
+// units of a service share an availability set. Instances with no
+// distribution group, such as state servers, share the "juju" set.
+func availabilitySetName(distributionGroupName string) string {
+	if distributionGroupName == "" {
+		return "juju"
+	}
+	return "juju-" + distributionGroupName
+}
+
 // StopInstances is specified in the InstanceBroker interface.
 func (env *azureEnviron) StopInstances(ids ...instance.Id) error {
 	snap := env.getSnapshot()
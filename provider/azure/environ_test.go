@@ -1569,6 +1569,31 @@ func (s *startInstanceSuite) TestStartInstanceDistributionGroup(c *gc.C) {
 	c.Assert(serviceName, gc.Equals, "juju-testenv-whatever")
 }
 
+func (s *startInstanceSuite) TestStartInstanceAvailabilitySetName(c *gc.C) {
+	var availabilitySetName string
+	s.PatchValue(&createInstance, func(env *azureEnviron, azure *gwacl.ManagementAPI, role *gwacl.Role, serviceName string, stateServer bool) (instance.Instance, error) {
+		availabilitySetName = role.AvailabilitySetName
+		return nil, nil
+	})
+	s.env.ecfg.attrs["availability-sets-enabled"] = true
+	_, err := s.env.StartInstance(s.params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(availabilitySetName, gc.Equals, "juju")
+
+	// Units of a service share an availability set.
+	s.params.DistributionGroupName = "wordpress"
+	_, err = s.env.StartInstance(s.params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(availabilitySetName, gc.Equals, "juju-wordpress")
+
+	// The distribution group has no effect if availability sets
+	// are disabled.
+	s.env.ecfg.attrs["availability-sets-enabled"] = false
+	_, err = s.env.StartInstance(s.params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(availabilitySetName, gc.Equals, "juju")
+}
+
 func (s *startInstanceSuite) TestStartInstanceStateServerJobs(c *gc.C) {
 	// If the machine has the JobManagesEnviron job,
 	// we should see stateServer==true.
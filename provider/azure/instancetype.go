@@ -208,3 +208,15 @@ func findInstanceSpec(env *azureEnviron, constraint *instances.InstanceConstrain
 	}
 	return instances.FindInstanceSpec(images, constraint, instanceTypes)
 }
+
+// normalizeConstraints returns the instance type that would be chosen
+// to satisfy cons, applying the same baseline as findInstanceSpec.
+func normalizeConstraints(env *azureEnviron, cons constraints.Value) (instances.Normalization, error) {
+	cons = defaultToBaselineSpec(cons)
+	instanceTypes, err := listInstanceTypes(env)
+	if err != nil {
+		return instances.Normalization{}, err
+	}
+	region := env.getSnapshot().ecfg.location()
+	return instances.Normalize(instanceTypes, region, cons)
+}
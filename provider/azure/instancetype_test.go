@@ -111,6 +111,30 @@ func (s *instanceTypeSuite) TestSelectMachineTypeReturnsCheapestMatch(c *gc.C) {
 	c.Check(choice.Name, gc.Equals, "Lambo")
 }
 
+func (s *instanceTypeSuite) TestNormalizeConstraintsAppliesBaseline(c *gc.C) {
+	s.PatchValue(&getAvailableRoleSizes, func(*azureEnviron) (set.Strings, error) {
+		return set.NewStrings("Small", "Medium"), nil
+	})
+	costs := map[string]uint64{
+		"Small":  10,
+		"Medium": 20,
+	}
+	s.PatchValue(&roleSizeCost, func(region, roleSize string) (uint64, error) {
+		return costs[roleSize], nil
+	})
+	s.PatchValue(&gwacl.RoleSizes, []gwacl.RoleSize{
+		// Cheapest, but below the baseline memory.
+		{Name: "Small", CpuCores: 1, Mem: defaultMem / 2},
+		{Name: "Medium", CpuCores: 2, Mem: defaultMem},
+	})
+
+	env := s.setupEnvWithDummyMetadata(c)
+	normalized, err := env.NormalizeConstraints(constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(normalized.InstanceType, gc.Equals, "Medium")
+	c.Check(*normalized.Hardware.Mem, gc.Equals, uint64(defaultMem))
+}
+
 func (s *instanceTypeSuite) setupEnvWithDummyMetadata(c *gc.C) *azureEnviron {
 	envAttrs := makeAzureConfigMap(c)
 	envAttrs["location"] = "West US"
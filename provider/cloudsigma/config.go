@@ -48,6 +48,14 @@ var configSecretFields = []string{
 	"password",
 }
 
+var configSchema = config.Schema{
+	"password": {
+		Description: "The CloudSigma account password",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configImmutableFields = []string{
 	"region",
 }
@@ -45,6 +45,7 @@ func init() {
 	// package; please do *not* import individual providers anywhere else,
 	// except in direct tests for that provider.
 	environs.RegisterProvider("cloudsigma", providerInstance)
+	config.RegisterProviderSchema("cloudsigma", configSchema)
 	environs.RegisterImageDataSourceFunc("Image source", getImageSource)
 	registry.RegisterEnvironStorageProviders(providerType)
 }
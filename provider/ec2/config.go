@@ -57,6 +57,12 @@ amazon:
     #
     # enable-os-upgrade: true
 
+    # vpc-id specifies the id of the VPC in which instances are
+    # started. It defaults to the region's default VPC, if it has
+    # one. The VPC cannot be changed after bootstrap.
+    #
+    # vpc-id: vpc-a1b2c3d4
+
 `
 
 var configFields = schema.Fields{
@@ -64,6 +70,20 @@ var configFields = schema.Fields{
 	"secret-key":     schema.String(),
 	"region":         schema.String(),
 	"control-bucket": schema.String(),
+	"vpc-id":         schema.String(),
+}
+
+var configSchema = config.Schema{
+	"access-key": {
+		Description: "The EC2 access key",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	"secret-key": {
+		Description: "The EC2 secret key",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
 }
 
 var configDefaults = schema.Defaults{
@@ -71,6 +91,7 @@ var configDefaults = schema.Defaults{
 	"secret-key":     "",
 	"region":         "us-east-1",
 	"control-bucket": "",
+	"vpc-id":         "",
 }
 
 type environConfig struct {
@@ -86,6 +107,12 @@ func (c *environConfig) controlBucket() string {
 	return c.attrs["control-bucket"].(string)
 }
 
+// vpcID returns the id of the VPC in which instances are started,
+// or the empty string if the region's default VPC is used.
+func (c *environConfig) vpcID() string {
+	return c.attrs["vpc-id"].(string)
+}
+
 func (c *environConfig) accessKey() string {
 	return c.attrs["access-key"].(string)
 }
@@ -146,6 +173,9 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 		if bucket, _ := attrs["control-bucket"].(string); ecfg.controlBucket() != bucket {
 			return nil, fmt.Errorf("cannot change control-bucket from %q to %q", bucket, ecfg.controlBucket())
 		}
+		if vpcID, _ := attrs["vpc-id"].(string); ecfg.vpcID() != vpcID {
+			return nil, fmt.Errorf("cannot change vpc-id from %q to %q", vpcID, ecfg.vpcID())
+		}
 	}
 
 	// ssl-hostname-verification cannot be disabled
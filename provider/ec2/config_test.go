@@ -179,6 +179,21 @@ var configTests = []configTest{
 			"control-bucket": "new-x",
 		},
 		err: `.*cannot change control-bucket from "x" to "new-x"`,
+	}, {
+		config: attrs{
+			"vpc-id": "vpc-1",
+		},
+		expect: attrs{
+			"vpc-id": "vpc-1",
+		},
+	}, {
+		config: attrs{
+			"vpc-id": "vpc-1",
+		},
+		change: attrs{
+			"vpc-id": "vpc-2",
+		},
+		err: `.*cannot change vpc-id from "vpc-1" to "vpc-2"`,
 	}, {
 		config: attrs{
 			"access-key": "jujuer",
@@ -75,6 +75,8 @@ type environ struct {
 
 // Ensure EC2 provider supports environs.NetworkingEnviron.
 var _ environs.NetworkingEnviron = (*environ)(nil)
+var _ environs.ConstraintsNormalizer = (*environ)(nil)
+var _ environs.SubnetDiscoverer = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
@@ -192,6 +194,11 @@ func (e *environ) Storage() envstorage.Storage {
 }
 
 func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
+	if vpcID := e.ecfg().vpcID(); vpcID != "" {
+		if err := validateVPC(e.ec2(), vpcID); err != nil {
+			return "", "", nil, errors.Annotate(err, "invalid vpc-id")
+		}
+	}
 	return common.Bootstrap(ctx, e, args)
 }
 
@@ -321,6 +328,7 @@ func (e *environ) InstanceAvailabilityZoneNames(ids []instance.Id) ([]string, er
 
 type ec2Placement struct {
 	availabilityZone ec2.AvailabilityZoneInfo
+	subnet           *ec2.Subnet
 }
 
 func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
@@ -338,11 +346,17 @@ func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
 		for _, z := range zones {
 			if z.Name() == availabilityZone {
 				return &ec2Placement{
-					z.(*ec2AvailabilityZone).AvailabilityZoneInfo,
+					availabilityZone: z.(*ec2AvailabilityZone).AvailabilityZoneInfo,
 				}, nil
 			}
 		}
 		return nil, fmt.Errorf("invalid availability zone %q", availabilityZone)
+	case "subnet":
+		subnet, err := e.subnetById(value)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid subnet")
+		}
+		return &ec2Placement{subnet: subnet}, nil
 	}
 	return nil, fmt.Errorf("unknown placement directive: %v", placement)
 }
@@ -372,6 +386,12 @@ func (e *environ) PrecheckInstance(series string, cons constraints.Value, placem
 	return fmt.Errorf("invalid AWS instance type %q and arch %q specified", *cons.InstanceType, *cons.Arch)
 }
 
+// NormalizeConstraints is specified in the environs.ConstraintsNormalizer
+// interface.
+func (e *environ) NormalizeConstraints(cons constraints.Value) (instances.Normalization, error) {
+	return normalizeConstraints(e.ecfg().region(), cons)
+}
+
 // MetadataLookupParams returns parameters which are used to query simplestreams metadata.
 func (e *environ) MetadataLookupParams(region string) (*simplestreams.MetadataLookupParams, error) {
 	if region == "" {
@@ -425,15 +445,33 @@ func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
 // StartInstance is specified in the InstanceBroker interface.
 func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
 	var availabilityZones []string
+	var zoneSubnets map[string]string
 	if args.Placement != "" {
 		placement, err := e.parsePlacement(args.Placement)
 		if err != nil {
 			return nil, err
 		}
-		if placement.availabilityZone.State != "available" {
-			return nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
+		if placement.subnet != nil {
+			if placement.subnet.State != availableState {
+				return nil, errors.Errorf("subnet %q is %s", placement.subnet.Id, placement.subnet.State)
+			}
+			availabilityZones = append(availabilityZones, placement.subnet.AvailZone)
+			zoneSubnets = map[string]string{placement.subnet.AvailZone: placement.subnet.Id}
+		} else {
+			if placement.availabilityZone.State != "available" {
+				return nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
+			}
+			availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
+		}
+	}
+	if zoneSubnets == nil {
+		// Instances in a configured VPC must be started in one
+		// of its subnets, so only zones with a subnet may be used.
+		var err error
+		zoneSubnets, err = e.zoneSubnets()
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
-		availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
 	}
 
 	// If no availability zone is specified, then automatically spread across
@@ -453,9 +491,15 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			return nil, err
 		}
 		for _, z := range zoneInstances {
+			if zoneSubnets != nil && zoneSubnets[z.ZoneName] == "" {
+				continue
+			}
 			availabilityZones = append(availabilityZones, z.ZoneName)
 		}
 		if len(availabilityZones) == 0 {
+			if zoneSubnets != nil {
+				return nil, errors.Errorf("no availability zone has a subnet in VPC %q", e.ecfg().vpcID())
+			}
 			return nil, errors.New("failed to determine availability zones")
 		}
 	}
@@ -511,6 +555,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	for _, availZone := range availabilityZones {
 		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
 			AvailZone:           availZone,
+			SubnetId:            zoneSubnets[availZone],
 			ImageId:             spec.Image.Id,
 			MinCount:            1,
 			MaxCount:            1,
@@ -587,10 +632,7 @@ func (e *environ) StopInstances(ids ...instance.Id) error {
 // groupInfoByName returns information on the security group
 // with the given name including rules and other details.
 func (e *environ) groupInfoByName(groupName string) (ec2.SecurityGroupInfo, error) {
-	// Non-default VPC does not support name-based group lookups, can
-	// use a filter by group name instead when support is needed.
-	limitToGroups := []ec2.SecurityGroup{{Name: groupName}}
-	resp, err := e.ec2().SecurityGroups(limitToGroups, nil)
+	resp, err := e.securityGroupsByName(groupName)
 	if err != nil {
 		return ec2.SecurityGroupInfo{}, err
 	}
@@ -600,6 +642,20 @@ func (e *environ) groupInfoByName(groupName string) (ec2.SecurityGroupInfo, erro
 	return resp.Groups[0], nil
 }
 
+// securityGroupsByName returns the security groups with the given
+// name. Groups in a non-default VPC cannot be looked up by name, so
+// they are filtered by name within the configured VPC instead.
+func (e *environ) securityGroupsByName(groupName string) (*ec2.SecurityGroupsResp, error) {
+	vpcID := e.ecfg().vpcID()
+	if vpcID == "" {
+		return e.ec2().SecurityGroups(ec2.SecurityGroupNames(groupName), nil)
+	}
+	filter := ec2.NewFilter()
+	filter.Add("group-name", groupName)
+	filter.Add("vpc-id", vpcID)
+	return e.ec2().SecurityGroups(nil, filter)
+}
+
 // groupByName returns the security group with the given name.
 func (e *environ) groupByName(groupName string) (ec2.SecurityGroup, error) {
 	groupInfo, err := e.groupInfoByName(groupName)
@@ -898,36 +954,11 @@ func (e *environ) Subnets(_ instance.Id, subnetIds []network.Id) ([]network.Subn
 		}
 		subIdSet[subnet.Id] = true
 
-		cidr := subnet.CIDRBlock
-		ip, ipnet, err := net.ParseCIDR(cidr)
+		info, err := makeSubnetInfo(subnet)
 		if err != nil {
-			logger.Warningf("skipping subnet %q, invalid CIDR: %v", cidr, err)
+			logger.Warningf("skipping subnet %q: %v", subnet.Id, err)
 			continue
 		}
-		// ec2 only uses IPv4 addresses for subnets
-		start, err := network.IPv4ToDecimal(ip)
-		if err != nil {
-			logger.Warningf("skipping subnet %q, invalid IP: %v", cidr, err)
-			continue
-		}
-		// First four addresses in a subnet are reserved, see
-		// http://goo.gl/rrWTIo
-		allocatableLow := network.DecimalToIPv4(start + 4)
-
-		ones, bits := ipnet.Mask.Size()
-		zeros := bits - ones
-		numIPs := uint32(1) << uint32(zeros)
-		highIP := start + numIPs - 1
-		// The last address in a subnet is also reserved (see same ref).
-		allocatableHigh := network.DecimalToIPv4(highIP - 1)
-
-		info := network.SubnetInfo{
-			CIDR:              cidr,
-			ProviderId:        network.Id(subnet.Id),
-			VLANTag:           0, // Not supported on EC2
-			AllocatableIPLow:  allocatableLow,
-			AllocatableIPHigh: allocatableHigh,
-		}
 		logger.Tracef("found subnet with info %#v", info)
 		results = append(results, info)
 	}
@@ -945,6 +976,39 @@ func (e *environ) Subnets(_ instance.Id, subnetIds []network.Id) ([]network.Subn
 	return results, nil
 }
 
+// makeSubnetInfo returns the network.SubnetInfo describing the given
+// EC2 subnet.
+func makeSubnetInfo(subnet ec2.Subnet) (network.SubnetInfo, error) {
+	cidr := subnet.CIDRBlock
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return network.SubnetInfo{}, errors.Annotatef(err, "invalid CIDR %q", cidr)
+	}
+	// ec2 only uses IPv4 addresses for subnets
+	start, err := network.IPv4ToDecimal(ip)
+	if err != nil {
+		return network.SubnetInfo{}, errors.Annotatef(err, "invalid IP in CIDR %q", cidr)
+	}
+	// First four addresses in a subnet are reserved, see
+	// http://goo.gl/rrWTIo
+	allocatableLow := network.DecimalToIPv4(start + 4)
+
+	ones, bits := ipnet.Mask.Size()
+	zeros := bits - ones
+	numIPs := uint32(1) << uint32(zeros)
+	highIP := start + numIPs - 1
+	// The last address in a subnet is also reserved (see same ref).
+	allocatableHigh := network.DecimalToIPv4(highIP - 1)
+
+	return network.SubnetInfo{
+		CIDR:              cidr,
+		ProviderId:        network.Id(subnet.Id),
+		VLANTag:           0, // Not supported on EC2
+		AllocatableIPLow:  allocatableLow,
+		AllocatableIPHigh: allocatableHigh,
+	}, nil
+}
+
 func (e *environ) AllInstances() ([]instance.Instance, error) {
 	filter := ec2.NewFilter()
 	filter.Add("instance-state-name", "pending", "running")
@@ -1208,7 +1272,7 @@ var zeroGroup ec2.SecurityGroup
 // the named group only.
 func (e *environ) ensureGroup(name string, perms []ec2.IPPerm) (g ec2.SecurityGroup, err error) {
 	ec2inst := e.ec2()
-	resp, err := ec2inst.CreateSecurityGroup("", name, "juju group")
+	resp, err := ec2inst.CreateSecurityGroup(e.ecfg().vpcID(), name, "juju group")
 	if err != nil && ec2ErrCode(err) != "InvalidGroup.Duplicate" {
 		return zeroGroup, err
 	}
@@ -1217,10 +1281,13 @@ func (e *environ) ensureGroup(name string, perms []ec2.IPPerm) (g ec2.SecurityGr
 	if err == nil {
 		g = resp.SecurityGroup
 	} else {
-		resp, err := ec2inst.SecurityGroups(ec2.SecurityGroupNames(name), nil)
+		resp, err := e.securityGroupsByName(name)
 		if err != nil {
 			return zeroGroup, err
 		}
+		if len(resp.Groups) == 0 {
+			return zeroGroup, errors.NotFoundf("security group %q", name)
+		}
 		info := resp.Groups[0]
 		// It's possible that the old group has the wrong
 		// description here, but if it does it's probably due
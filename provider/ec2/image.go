@@ -6,6 +6,7 @@ package ec2
 import (
 	"fmt"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
@@ -67,10 +68,20 @@ func findInstanceSpec(
 	suitableImages := filterImages(matchingImages, ic)
 	images := instances.ImageMetadataToImages(suitableImages)
 
-	// Make a copy of the known EC2 instance types, filling in the cost for the specified region.
-	regionCosts := allRegionCosts[ic.Region]
+	itypesWithCosts, err := regionInstanceTypes(ic.Region)
+	if err != nil {
+		return nil, err
+	}
+	return instances.FindInstanceSpec(images, ic, itypesWithCosts)
+}
+
+// regionInstanceTypes returns a copy of the known EC2 instance types
+// available in the given region, with the cost for that region filled
+// in.
+func regionInstanceTypes(region string) ([]instances.InstanceType, error) {
+	regionCosts := allRegionCosts[region]
 	if len(regionCosts) == 0 && len(allRegionCosts) > 0 {
-		return nil, fmt.Errorf("no instance types found in %s", ic.Region)
+		return nil, fmt.Errorf("no instance types found in %s", region)
 	}
 
 	var itypesWithCosts []instances.InstanceType
@@ -83,5 +94,18 @@ func findInstanceSpec(
 		itWithCost.Cost = cost
 		itypesWithCosts = append(itypesWithCosts, itWithCost)
 	}
-	return instances.FindInstanceSpec(images, ic, itypesWithCosts)
+	return itypesWithCosts, nil
+}
+
+// normalizeConstraints returns the instance type that would be chosen
+// to satisfy cons in the given region.
+func normalizeConstraints(region string, cons constraints.Value) (instances.Normalization, error) {
+	if cons.CpuPower == nil {
+		cons.CpuPower = instances.CpuPower(defaultCpuPower)
+	}
+	itypes, err := regionInstanceTypes(region)
+	if err != nil {
+		return instances.Normalization{}, err
+	}
+	return instances.Normalize(itypes, region, cons)
 }
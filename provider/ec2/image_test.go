@@ -173,6 +173,26 @@ func (s *specSuite) TestFindInstanceSpec(c *gc.C) {
 	}
 }
 
+func (s *specSuite) TestNormalizeConstraints(c *gc.C) {
+	for i, test := range []struct {
+		cons  string
+		itype string
+	}{
+		{"", "m1.small"},
+		{"cpu-cores=4", "m1.xlarge"},
+		{"instance-type=m1.large", "m1.large"},
+	} {
+		c.Logf("test %d: %q", i, test.cons)
+		normalized, err := normalizeConstraints("test", constraints.MustParse(test.cons))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(normalized.InstanceType, gc.Equals, test.itype)
+	}
+	// The default cpu-power used by StartInstance is applied.
+	normalized, err := normalizeConstraints("test", constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(normalized.Rationale, gc.Matches, `cheapest of \d+ instance types matching "cpu-power=100"`)
+}
+
 var findInstanceSpecErrorTests = []struct {
 	series string
 	arches []string
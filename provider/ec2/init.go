@@ -5,6 +5,7 @@ package ec2
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -14,6 +15,7 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, environProvider{})
+	config.RegisterProviderSchema(providerType, configSchema)
 
 	//Register the AWS specific providers.
 	registry.RegisterProvider(EBS_ProviderType, &ebsProvider{})
@@ -754,6 +754,51 @@ func (t *localServerSuite) setUpInstanceWithDefaultVpc(c *gc.C) (environs.Networ
 	return env, instanceIds[0]
 }
 
+func (t *localServerSuite) TestBootstrapInvalidVPC(c *gc.C) {
+	t.TestConfig = t.TestConfig.Merge(coretesting.Attrs{"vpc-id": "vpc-missing"})
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, gc.ErrorMatches, `invalid vpc-id: VPC "vpc-missing" not found`)
+}
+
+func (t *localServerSuite) TestAllSubnets(c *gc.C) {
+	env, _ := t.setUpInstanceWithDefaultVpc(c)
+	discoverer, ok := environs.SupportsSubnetDiscovery(env)
+	c.Assert(ok, jc.IsTrue)
+
+	subnets, err := discoverer.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.Not(gc.HasLen), 0)
+	var found bool
+	for _, subnet := range subnets {
+		c.Check(subnet.AvailabilityZone, gc.Not(gc.Equals), "")
+		if subnet.ProviderId == "subnet-0" {
+			found = true
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
+
+func (t *localServerSuite) TestAllSubnetsNoVPC(c *gc.C) {
+	t.srv.ec2srv.SetInitialAttributes(map[string][]string{
+		"default-vpc": {"none"},
+	})
+	env := t.prepareEnviron(c)
+	discoverer, ok := environs.SupportsSubnetDiscovery(env)
+	c.Assert(ok, jc.IsTrue)
+
+	subnets, err := discoverer.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 0)
+}
+
+func (t *localServerSuite) TestStartInstanceSubnetPlacementUnknown(c *gc.C) {
+	env, _ := t.setUpInstanceWithDefaultVpc(c)
+	params := environs.StartInstanceParams{Placement: "subnet=subnet-missing"}
+	_, err := testing.StartInstanceWithParams(env, "1", params, nil)
+	c.Assert(err, gc.ErrorMatches, `invalid subnet: subnet "subnet-missing" not found`)
+}
+
 func (t *localServerSuite) TestAllocateAddress(c *gc.C) {
 	env, instId := t.setUpInstanceWithDefaultVpc(c)
 
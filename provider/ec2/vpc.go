@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/network"
+)
+
+const availableState = "available"
+
+// validateVPC checks that the VPC with the given id exists, is
+// available and has at least one available subnet in which instances
+// can be started.
+func validateVPC(ec2inst *ec2.EC2, vpcID string) error {
+	resp, err := ec2inst.VPCs([]string{vpcID}, nil)
+	if err != nil {
+		if ec2ErrCode(err) == "InvalidVpcID.NotFound" {
+			return errors.NotFoundf("VPC %q", vpcID)
+		}
+		return errors.Annotatef(err, "cannot get VPC %q", vpcID)
+	}
+	if len(resp.VPCs) != 1 {
+		return errors.NotFoundf("VPC %q", vpcID)
+	}
+	if state := resp.VPCs[0].State; state != availableState {
+		return errors.Errorf("VPC %q is %s", vpcID, state)
+	}
+	subnets, err := vpcSubnets(ec2inst, vpcID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(subnets) == 0 {
+		return errors.Errorf("VPC %q has no available subnets", vpcID)
+	}
+	return nil
+}
+
+// vpcSubnets returns the available subnets in the VPC with the given
+// id, ordered by id.
+func vpcSubnets(ec2inst *ec2.EC2, vpcID string) ([]ec2.Subnet, error) {
+	filter := ec2.NewFilter()
+	filter.Add("vpc-id", vpcID)
+	resp, err := ec2inst.Subnets(nil, filter)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets of VPC %q", vpcID)
+	}
+	var subnets []ec2.Subnet
+	for _, subnet := range resp.Subnets {
+		if subnet.State == availableState {
+			subnets = append(subnets, subnet)
+		}
+	}
+	sort.Sort(subnetsById(subnets))
+	return subnets, nil
+}
+
+type subnetsById []ec2.Subnet
+
+func (s subnetsById) Len() int           { return len(s) }
+func (s subnetsById) Less(i, j int) bool { return s[i].Id < s[j].Id }
+func (s subnetsById) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// zoneSubnets returns, for each availability zone with an available
+// subnet in the configured VPC, the id of the subnet in which to start
+// instances in that zone. It returns nil if no VPC is configured.
+func (e *environ) zoneSubnets() (map[string]string, error) {
+	vpcID := e.ecfg().vpcID()
+	if vpcID == "" {
+		return nil, nil
+	}
+	subnets, err := vpcSubnets(e.ec2(), vpcID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]string)
+	for _, subnet := range subnets {
+		if _, ok := result[subnet.AvailZone]; !ok {
+			result[subnet.AvailZone] = subnet.Id
+		}
+	}
+	return result, nil
+}
+
+// subnetById returns the subnet with the given id, which must be in
+// the configured VPC if there is one.
+func (e *environ) subnetById(subnetID string) (*ec2.Subnet, error) {
+	resp, err := e.ec2().Subnets([]string{subnetID}, nil)
+	if err != nil {
+		if ec2ErrCode(err) == "InvalidSubnetID.NotFound" {
+			return nil, errors.NotFoundf("subnet %q", subnetID)
+		}
+		return nil, errors.Annotatef(err, "cannot get subnet %q", subnetID)
+	}
+	if len(resp.Subnets) != 1 {
+		return nil, errors.NotFoundf("subnet %q", subnetID)
+	}
+	subnet := resp.Subnets[0]
+	if vpcID := e.ecfg().vpcID(); vpcID != "" && subnet.VPCId != vpcID {
+		return nil, errors.Errorf("subnet %q is not in VPC %q", subnetID, vpcID)
+	}
+	return &subnet, nil
+}
+
+// AllSubnets is specified on the environs.SubnetDiscoverer interface.
+// It returns the subnets of the configured VPC or, if none is
+// configured, of the region's default VPC.
+func (e *environ) AllSubnets() ([]network.SubnetInfo, error) {
+	vpcID := e.ecfg().vpcID()
+	if vpcID == "" {
+		id, hasDefault, err := e.defaultVpc()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !hasDefault {
+			return nil, nil
+		}
+		vpcID = string(id)
+	}
+	subnets, err := vpcSubnets(e.ec2(), vpcID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results []network.SubnetInfo
+	for _, subnet := range subnets {
+		info, err := makeSubnetInfo(subnet)
+		if err != nil {
+			logger.Warningf("skipping subnet %q: %v", subnet.Id, err)
+			continue
+		}
+		info.AvailabilityZone = subnet.AvailZone
+		results = append(results, info)
+	}
+	return results, nil
+}
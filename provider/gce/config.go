@@ -100,6 +100,14 @@ var configSecretFields = []string{
 	cfgPrivateKey,
 }
 
+var configSchema = config.Schema{
+	cfgPrivateKey: {
+		Description: "The private key of the GCE service account",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configImmutableFields = []string{
 	cfgAuthFile,
 	cfgPrivateKey,
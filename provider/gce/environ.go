@@ -16,8 +16,6 @@ import (
 	"github.com/juju/juju/provider/gce/google"
 )
 
-// Note: This provider/environment does *not* implement storage.
-
 type gceConnection interface {
 	VerifyCredentials() error
 
@@ -33,6 +31,12 @@ type gceConnection interface {
 	ClosePorts(fwname string, ports ...network.PortRange) error
 
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
+
+	CreateDisk(zone, name string, sizeGB uint64) (*google.Disk, error)
+	Disk(zone, name string) (*google.Disk, error)
+	RemoveDisk(zone, name string) error
+	AttachDisk(zone, name, instanceID string, autoDelete bool) error
+	DetachDisk(zone, name, instanceID string) error
 }
 
 type environ struct {
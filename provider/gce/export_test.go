@@ -8,6 +8,7 @@ import (
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
 )
 
 var (
@@ -74,3 +75,7 @@ func GetHardwareCharacteristics(env *environ, spec *instances.InstanceSpec, inst
 func GetInstances(env *environ) ([]instance.Instance, error) {
 	return env.instances()
 }
+
+func NewVolumeSource(env *environ) storage.VolumeSource {
+	return &volumeSource{env}
+}
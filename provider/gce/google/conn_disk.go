@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"github.com/juju/errors"
+	"google.golang.org/api/compute/v1"
+)
+
+// CreateDisk sends a request to the GCE API to create a new persistent
+// disk with the given name and size in the given zone. The call blocks
+// until the disk is created or the request fails.
+func (gce *Connection) CreateDisk(zone, name string, sizeGB uint64) (*Disk, error) {
+	spec := &compute.Disk{
+		Name:   name,
+		SizeGb: int64(sizeGB),
+	}
+	if err := gce.raw.AddDisk(gce.projectID, zone, spec); err != nil {
+		return nil, errors.Annotatef(err, "while creating disk %q", name)
+	}
+	return gce.Disk(zone, name)
+}
+
+// Disk gets the up-to-date info about the named disk in the given
+// zone and returns it. If the disk does not exist then errors.NotFound
+// is returned.
+func (gce *Connection) Disk(zone, name string) (*Disk, error) {
+	raw, err := gce.raw.GetDisk(gce.projectID, zone, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDisk(raw), nil
+}
+
+// RemoveDisk sends a request to the GCE API to remove the named disk
+// from the given zone. If the disk does not exist then this is a noop.
+// The call blocks until the disk is removed or the request fails.
+func (gce *Connection) RemoveDisk(zone, name string) error {
+	err := gce.raw.RemoveDisk(gce.projectID, zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+// AttachDisk sends a request to the GCE API to attach the named disk
+// to the identified instance in the given zone. The disk's name is
+// used as its device name. If the disk is already attached to the
+// instance then this is a noop. The call blocks until the disk is
+// attached or the request fails.
+func (gce *Connection) AttachDisk(zone, name, instanceID string, autoDelete bool) error {
+	inst, err := gce.raw.GetInstance(gce.projectID, zone, instanceID)
+	if err != nil {
+		return errors.Annotatef(err, "while attaching disk %q", name)
+	}
+	for _, attached := range inst.Disks {
+		if attached.DeviceName == name {
+			return nil
+		}
+	}
+	disk, err := gce.Disk(zone, name)
+	if err != nil {
+		return errors.Annotatef(err, "while attaching disk %q", name)
+	}
+
+	attached := &compute.AttachedDisk{
+		Type:       diskTypePersistent,
+		Mode:       diskModeRW,
+		Source:     disk.selfLink,
+		DeviceName: name,
+		AutoDelete: autoDelete,
+	}
+	if err := gce.raw.AttachDisk(gce.projectID, zone, instanceID, attached); err != nil {
+		return errors.Annotatef(err, "while attaching disk %q to %q", name, instanceID)
+	}
+	return nil
+}
+
+// DetachDisk sends a request to the GCE API to detach the named disk
+// from the identified instance in the given zone. If the disk does
+// not exist or is not attached to the instance then this is a noop.
+// The call blocks until the disk is detached or the request fails.
+func (gce *Connection) DetachDisk(zone, name, instanceID string) error {
+	disk, err := gce.Disk(zone, name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Annotatef(err, "while detaching disk %q", name)
+	}
+	if !disk.AttachedTo(instanceID) {
+		return nil
+	}
+
+	err = gce.raw.DetachDisk(gce.projectID, zone, instanceID, name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Annotatef(err, "while detaching disk %q from %q", name, instanceID)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/gce/google"
+)
+
+func (s *connSuite) TestConnectionCreateDisk(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{
+		Name:     "spam",
+		Zone:     "zones/a-zone",
+		SizeGb:   15,
+		Status:   "READY",
+		SelfLink: "projects/spam/zones/a-zone/disks/spam",
+	}
+
+	disk, err := s.Conn.CreateDisk("a-zone", "spam", 15)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disk.Name, gc.Equals, "spam")
+	c.Check(disk.ZoneName, gc.Equals, "a-zone")
+	c.Check(disk.SizeGB, gc.Equals, uint64(15))
+	c.Check(disk.Status, gc.Equals, "READY")
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AddDisk")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].Disk, jc.DeepEquals, &compute.Disk{
+		Name:   "spam",
+		SizeGb: 15,
+	})
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+}
+
+func (s *connSuite) TestConnectionRemoveDiskNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("disk")
+
+	err := s.Conn.RemoveDisk("a-zone", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, "spam")
+}
+
+func (s *connSuite) TestConnectionAttachDisk(c *gc.C) {
+	s.FakeConn.Instance = &s.RawInstanceFull
+	s.FakeConn.Disk = &compute.Disk{
+		Name:     "eggs",
+		Zone:     "zones/a-zone",
+		SelfLink: "projects/spam/zones/a-zone/disks/eggs",
+	}
+
+	err := s.Conn.AttachDisk("a-zone", "eggs", "spam", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 3)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetInstance")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[2].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[2].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[2].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[2].Attached, jc.DeepEquals, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     "projects/spam/zones/a-zone/disks/eggs",
+		DeviceName: "eggs",
+		AutoDelete: true,
+	})
+}
+
+func (s *connSuite) TestConnectionAttachDiskAlreadyAttached(c *gc.C) {
+	inst := s.RawInstanceFull
+	inst.Disks = []*compute.AttachedDisk{{DeviceName: "eggs"}}
+	s.FakeConn.Instance = &inst
+
+	err := s.Conn.AttachDisk("a-zone", "eggs", "spam", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetInstance")
+}
+
+func (s *connSuite) TestConnectionDetachDisk(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{
+		Name:  "eggs",
+		Zone:  "zones/a-zone",
+		Users: []string{"projects/spam/zones/a-zone/instances/spam"},
+	}
+
+	err := s.Conn.DetachDisk("a-zone", "eggs", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "eggs")
+}
+
+func (s *connSuite) TestConnectionDetachDiskNotAttached(c *gc.C) {
+	s.FakeConn.Disk = &compute.Disk{
+		Name: "eggs",
+		Zone: "zones/a-zone",
+	}
+
+	err := s.Conn.DetachDisk("a-zone", "eggs", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetDisk")
+}
+
+func (s *connSuite) TestDiskAttachedTo(c *gc.C) {
+	disk := google.Disk{
+		Users: []string{"projects/spam/zones/a-zone/instances/spam"},
+	}
+
+	c.Check(disk.AttachedTo("spam"), jc.IsTrue)
+	c.Check(disk.AttachedTo("eggs"), jc.IsFalse)
+}
@@ -4,6 +4,8 @@
 package google
 
 import (
+	"path"
+
 	"google.golang.org/api/compute/v1"
 )
 
@@ -100,3 +102,43 @@ func (ds *DiskSpec) newAttached() *compute.AttachedDisk {
 	}
 	return &disk
 }
+
+// Disk holds the information about a persistent disk in GCE.
+type Disk struct {
+	// Name is the disk's name, unique within its zone.
+	Name string
+	// ZoneName is the name of the zone in which the disk exists.
+	ZoneName string
+	// SizeGB is the size of the disk in Gigabytes.
+	SizeGB uint64
+	// Status is the disk's current status.
+	Status string
+	// Users holds the URLs of the instances to which the disk is
+	// attached.
+	Users []string
+
+	selfLink string
+}
+
+// newDisk builds a Disk from the raw disk data returned by the GCE API.
+func newDisk(raw *compute.Disk) *Disk {
+	return &Disk{
+		Name:     raw.Name,
+		ZoneName: path.Base(raw.Zone),
+		SizeGB:   uint64(raw.SizeGb),
+		Status:   raw.Status,
+		Users:    raw.Users,
+		selfLink: raw.SelfLink,
+	}
+}
+
+// AttachedTo reports whether the disk is attached to the instance
+// with the given ID.
+func (d *Disk) AttachedTo(instanceID string) bool {
+	for _, user := range d.Users {
+		if path.Base(user) == instanceID {
+			return true
+		}
+	}
+	return false
+}
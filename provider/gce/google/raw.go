@@ -187,6 +187,56 @@ func (rc *rawConn) ListAvailabilityZones(projectID, region string) ([]*compute.Z
 	return results, nil
 }
 
+func (rc *rawConn) GetDisk(projectID, zone, name string) (*compute.Disk, error) {
+	call := rc.Disks.Get(projectID, zone, name)
+	disk, err := call.Do()
+	return disk, errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) AddDisk(projectID, zone string, spec *compute.Disk) error {
+	call := rc.Disks.Insert(projectID, zone, spec)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Annotate(err, "sending new disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveDisk(projectID, zone, name string) error {
+	call := rc.Disks.Delete(projectID, zone, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(convertRawAPIError(err))
+}
+
+func (rc *rawConn) AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error {
+	call := rc.Instances.AttachDisk(projectID, zone, instanceID, disk)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) DetachDisk(projectID, zone, instanceID, deviceName string) error {
+	call := rc.Instances.DetachDisk(projectID, zone, instanceID, deviceName)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(convertRawAPIError(err))
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
@@ -153,6 +153,8 @@ type fakeCall struct {
 	Instance  *compute.Instance
 	InstValue compute.Instance
 	Firewall  *compute.Firewall
+	Disk      *compute.Disk
+	Attached  *compute.AttachedDisk
 }
 
 type fakeConn struct {
@@ -163,6 +165,7 @@ type fakeConn struct {
 	Instances  []*compute.Instance
 	Firewall   *compute.Firewall
 	Zones      []*compute.Zone
+	Disk       *compute.Disk
 	Err        error
 	FailOnCall int
 }
@@ -321,3 +324,85 @@ func (rc *fakeConn) ListAvailabilityZones(projectID, region string) ([]*compute.
 	}
 	return rc.Zones, err
 }
+
+func (rc *fakeConn) GetDisk(projectID, zone, name string) (*compute.Disk, error) {
+	call := fakeCall{
+		FuncName:  "GetDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Disk, err
+}
+
+func (rc *fakeConn) AddDisk(projectID, zone string, spec *compute.Disk) error {
+	call := fakeCall{
+		FuncName:  "AddDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Disk:      spec,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveDisk(projectID, zone, name string) error {
+	call := fakeCall{
+		FuncName:  "RemoveDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error {
+	call := fakeCall{
+		FuncName:  "AttachDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        instanceID,
+		Attached:  disk,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) DetachDisk(projectID, zone, instanceID, deviceName string) error {
+	call := fakeCall{
+		FuncName:  "DetachDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		ID:        instanceID,
+		Name:      deviceName,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
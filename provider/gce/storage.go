@@ -0,0 +1,294 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	storageProviderType = storage.ProviderType("gce")
+
+	// volumeSizeMaxGB is the maximum size (in gigabytes) of a GCE
+	// persistent disk.
+	volumeSizeMaxGB = 64 * 1024
+
+	// volumeIdSeparator separates the zone name from the disk name
+	// in provider volume IDs. GCE disk names are only unique within
+	// a zone, so the zone is needed to identify a volume.
+	volumeIdSeparator = "--"
+
+	// hardwareIdPrefix is the prefix of the hardware ID reported for
+	// GCE persistent disks by the kernel. It is followed by the
+	// disk's device name.
+	hardwareIdPrefix = "scsi-0Google_PersistentDisk_"
+)
+
+// storageProvider creates volume sources which use GCE persistent disks.
+type storageProvider struct{}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+var storageConfigChecker = schema.FieldMap(
+	schema.Fields{
+		storage.Persistent: schema.Bool(),
+	},
+	schema.Defaults{
+		storage.Persistent: false,
+	},
+)
+
+func newStorageConfig(attrs map[string]interface{}) (persistent bool, _ error) {
+	out, err := storageConfigChecker.Coerce(attrs, nil)
+	if err != nil {
+		return false, errors.Annotate(err, "validating GCE storage config")
+	}
+	coerced := out.(map[string]interface{})
+	return coerced[storage.Persistent].(bool), nil
+}
+
+// ValidateConfig is defined on the Provider interface.
+func (g *storageProvider) ValidateConfig(cfg *storage.Config) error {
+	_, err := newStorageConfig(cfg.Attrs())
+	return errors.Trace(err)
+}
+
+// Supports is defined on the Provider interface.
+func (g *storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is defined on the Provider interface.
+func (g *storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is defined on the Provider interface.
+func (g *storageProvider) Dynamic() bool {
+	return true
+}
+
+// VolumeSource is defined on the Provider interface.
+func (g *storageProvider) VolumeSource(environConfig *config.Config, cfg *storage.Config) (storage.VolumeSource, error) {
+	env, err := newEnviron(environConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating GCE environ")
+	}
+	return &volumeSource{env}, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (g *storageProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+type volumeSource struct {
+	env *environ
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// diskName returns the name of the GCE disk for the given volume
+// params. The environment's UUID is included so that disk names do
+// not collide across environments in the same project.
+func (v *volumeSource) diskName(p storage.VolumeParams) string {
+	return fmt.Sprintf("juju-%s-volume-%s", v.env.uuid, strings.Replace(p.Tag.Id(), "/", "-", -1))
+}
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(params))
+	volumeAttachments := make([]storage.VolumeAttachment, 0, len(params))
+
+	// If there's an error, we delete any ones that are created.
+	defer func() {
+		if err != nil && len(volumes) > 0 {
+			volIds := make([]string, len(volumes))
+			for i, vol := range volumes {
+				volIds[i] = vol.VolumeId
+			}
+			for i, volErr := range v.DestroyVolumes(volIds) {
+				if volErr == nil {
+					continue
+				}
+				logger.Warningf("error cleaning up volume %v: %v", volumes[i].Tag, volErr)
+			}
+		}
+	}()
+
+	for _, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+
+	for _, p := range params {
+		persistent, _ := newStorageConfig(p.Attributes)
+		instId := string(p.Attachment.InstanceId)
+		zone, err := v.instanceZone(instId)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+
+		// Juju size is MiB, GCE size is GB.
+		sizeGB := mibToGB(p.Size)
+		if sizeGB < google.MinDiskSizeGB {
+			sizeGB = google.MinDiskSizeGB
+		}
+		name := v.diskName(p)
+		disk, err := v.env.gce.CreateDisk(zone, name, sizeGB)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %v", p.Tag)
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:        p.Tag,
+			VolumeId:   makeVolumeId(zone, disk.Name),
+			HardwareId: hardwareIdPrefix + disk.Name,
+			Size:       gbToMib(disk.SizeGB),
+			Persistent: persistent,
+		})
+
+		// Non-persistent disks are removed along with the instance
+		// they are attached to, so they must be attached now.
+		if err := v.env.gce.AttachDisk(zone, disk.Name, instId, !persistent); err != nil {
+			return nil, nil, errors.Annotatef(err, "attaching %v to %v", p.Tag, instId)
+		}
+		volumeAttachments = append(volumeAttachments, storage.VolumeAttachment{
+			Volume:  p.Tag,
+			Machine: p.Attachment.Machine,
+		})
+	}
+	return volumes, volumeAttachments, nil
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	vols := make([]storage.Volume, len(volIds))
+	for i, volId := range volIds {
+		zone, name, err := parseVolumeId(volId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		disk, err := v.env.gce.Disk(zone, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "describing volume %q", volId)
+		}
+		vols[i] = storage.Volume{
+			VolumeId:   volId,
+			HardwareId: hardwareIdPrefix + disk.Name,
+			Size:       gbToMib(disk.SizeGB),
+		}
+	}
+	return vols, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volId := range volIds {
+		zone, name, err := parseVolumeId(volId)
+		if err != nil {
+			results[i] = errors.Trace(err)
+			continue
+		}
+		if err := v.env.gce.RemoveDisk(zone, name); err != nil {
+			results[i] = errors.Annotatef(err, "destroying volume %q", volId)
+		}
+	}
+	return results
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if _, err := newStorageConfig(params.Attributes); err != nil {
+		return errors.Trace(err)
+	}
+	if mibToGB(params.Size) > volumeSizeMaxGB {
+		return errors.Errorf(
+			"%d GB exceeds the maximum of %d GB",
+			mibToGB(params.Size),
+			volumeSizeMaxGB,
+		)
+	}
+	// GCE disks must be created in the zone of the instance to which
+	// they will be attached.
+	if params.Attachment == nil || params.Attachment.InstanceId == "" {
+		return storage.ErrVolumeNeedsInstance
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(attachParams []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(attachParams))
+	for i, p := range attachParams {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		instId := string(p.InstanceId)
+		if err := v.env.gce.AttachDisk(zone, name, instId, false); err != nil {
+			return nil, errors.Annotatef(err, "attaching %v to %v", p.VolumeId, instId)
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:  p.Volume,
+			Machine: p.Machine,
+		}
+	}
+	return attachments, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(attachParams []storage.VolumeAttachmentParams) error {
+	for _, p := range attachParams {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		instId := string(p.InstanceId)
+		if err := v.env.gce.DetachDisk(zone, name, instId); err != nil {
+			return errors.Annotatef(err, "detaching %v from %v", p.VolumeId, instId)
+		}
+	}
+	return nil
+}
+
+// instanceZone returns the name of the availability zone of the
+// instance with the given ID.
+func (v *volumeSource) instanceZone(instId string) (string, error) {
+	zones, err := v.env.InstanceAvailabilityZoneNames([]instance.Id{instance.Id(instId)})
+	if err != nil {
+		return "", errors.Annotatef(err, "getting zone of instance %q", instId)
+	}
+	return zones[0], nil
+}
+
+func makeVolumeId(zone, name string) string {
+	return zone + volumeIdSeparator + name
+}
+
+func parseVolumeId(volId string) (zone, name string, _ error) {
+	parts := strings.SplitN(volId, volumeIdSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("volume ID %q", volId)
+	}
+	return parts[0], parts[1], nil
+}
+
+func mibToGB(m uint64) uint64 {
+	return (m + 1023) / 1024
+}
+
+func gbToMib(g uint64) uint64 {
+	return g * 1024
+}
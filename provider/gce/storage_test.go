@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+type storageSuite struct {
+	gce.BaseSuite
+
+	source storage.VolumeSource
+	params storage.VolumeParams
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.source = gce.NewVolumeSource(s.Env)
+	s.params = storage.VolumeParams{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     20 * 1024,
+		Provider: "gce",
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instance.Id("spam"),
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}
+}
+
+func (s *storageSuite) TestValidateVolumeParamsNeedsInstance(c *gc.C) {
+	s.params.Attachment.InstanceId = ""
+
+	err := s.source.ValidateVolumeParams(s.params)
+	c.Check(err, gc.Equals, storage.ErrVolumeNeedsInstance)
+}
+
+func (s *storageSuite) TestValidateVolumeParamsTooLarge(c *gc.C) {
+	s.params.Size = 65537 * 1024
+
+	err := s.source.ValidateVolumeParams(s.params)
+	c.Check(err, gc.ErrorMatches, "65537 GB exceeds the maximum of 65536 GB")
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	s.FakeEnviron.Insts = []instance.Instance{s.Instance}
+	s.FakeConn.DiskResult = &google.Disk{
+		Name:     "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-volume-0",
+		ZoneName: "home-zone",
+		SizeGB:   20,
+	}
+
+	volumes, attachments, err := s.source.CreateVolumes([]storage.VolumeParams{s.params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:        names.NewVolumeTag("0"),
+		VolumeId:   "home-zone--juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-volume-0",
+		HardwareId: "scsi-0Google_PersistentDisk_juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba-volume-0",
+		Size:       20 * 1024,
+	}})
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+	}})
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "CreateDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].SizeGB, gc.Equals, uint64(20))
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[1].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[1].AutoDelete, jc.IsTrue)
+}
+
+func (s *storageSuite) TestCreateVolumesMinimumSize(c *gc.C) {
+	s.FakeEnviron.Insts = []instance.Instance{s.Instance}
+	s.FakeConn.DiskResult = &google.Disk{Name: "spam", SizeGB: 10}
+	s.params.Size = 1024
+
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{s.params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls[0].SizeGB, gc.Equals, google.MinDiskSizeGB)
+}
+
+func (s *storageSuite) TestDescribeVolumes(c *gc.C) {
+	s.FakeConn.DiskResult = &google.Disk{Name: "spam", ZoneName: "home-zone", SizeGB: 15}
+
+	volumes, err := s.source.DescribeVolumes([]string{"home-zone--spam"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		VolumeId:   "home-zone--spam",
+		HardwareId: "scsi-0Google_PersistentDisk_spam",
+		Size:       15 * 1024,
+	}})
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, "spam")
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	errs := s.source.DestroyVolumes([]string{"home-zone--spam", "eggs"})
+	c.Assert(errs, gc.HasLen, 2)
+	c.Check(errs[0], jc.ErrorIsNil)
+	c.Check(errs[1], gc.ErrorMatches, `volume ID "eggs" not valid`)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, "spam")
+}
+
+func (s *storageSuite) TestAttachVolumes(c *gc.C) {
+	params := *s.params.Attachment
+	params.VolumeId = "home-zone--spam"
+
+	attachments, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+	}})
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].AutoDelete, jc.IsFalse)
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	params := *s.params.Attachment
+	params.VolumeId = "home-zone--spam"
+
+	err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+}
@@ -422,6 +422,9 @@ type fakeConnCall struct {
 	FirewallName string
 	PortRanges   []network.PortRange
 	Region       string
+	DiskName     string
+	SizeGB       uint64
+	AutoDelete   bool
 }
 
 type fakeConn struct {
@@ -431,6 +434,7 @@ type fakeConn struct {
 	Insts      []google.Instance
 	PortRanges []network.PortRange
 	Zones      []google.AvailabilityZone
+	DiskResult *google.Disk
 	Err        error
 	FailOnCall int
 }
@@ -519,6 +523,55 @@ func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone,
 	return fc.Zones, fc.err()
 }
 
+func (fc *fakeConn) CreateDisk(zone, name string, sizeGB uint64) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "CreateDisk",
+		ZoneName: zone,
+		DiskName: name,
+		SizeGB:   sizeGB,
+	})
+	return fc.DiskResult, fc.err()
+}
+
+func (fc *fakeConn) Disk(zone, name string) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.DiskResult, fc.err()
+}
+
+func (fc *fakeConn) RemoveDisk(zone, name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveDisk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) AttachDisk(zone, name, instanceID string, autoDelete bool) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:   "AttachDisk",
+		ZoneName:   zone,
+		DiskName:   name,
+		ID:         instanceID,
+		AutoDelete: autoDelete,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) DetachDisk(zone, name, instanceID string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "DetachDisk",
+		ZoneName: zone,
+		DiskName: name,
+		ID:       instanceID,
+	})
+	return fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
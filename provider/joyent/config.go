@@ -137,6 +137,34 @@ var configSecretFields = []string{
 	privateKey,
 }
 
+var configSchema = config.Schema{
+	sdcUser: {
+		Description: "The Joyent SDC account name",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	sdcKeyId: {
+		Description: "The fingerprint of the key used to access the SDC API",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	mantaUser: {
+		Description: "The Joyent Manta account name",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	mantaKeyId: {
+		Description: "The fingerprint of the key used to access the Manta API",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	privateKey: {
+		Description: "The private key used to sign Joyent API requests",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configImmutableFields = []string{
 	sdcUrl,
 	mantaUrl,
@@ -0,0 +1,222 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+// The LXD-specific config keys.
+const (
+	cfgRemoteURL     = "remote-url"
+	cfgClientCert    = "client-cert"
+	cfgClientKey     = "client-key"
+	cfgServerCert    = "server-cert"
+	cfgNetworkBridge = "network-bridge"
+	cfgStoragePool   = "storage-pool"
+	cfgImageServer   = "image-server"
+)
+
+// defaultNetworkBridge is the bridge created by the LXD packages, to
+// which containers are attached by default.
+const defaultNetworkBridge = "lxdbr0"
+
+// boilerplateConfig will be shown in help output, so please keep it up to
+// date when you change environment configuration below.
+var boilerplateConfig = `
+lxd:
+  type: lxd
+
+  # remote-url is the URL of the API of a remote LXD host. If it is
+  # not set then containers are created by the local LXD daemon, which
+  # is made to serve its API over HTTPS on network-bridge at bootstrap
+  # so that the state server can reach it.
+  #
+  # remote-url: https://10.0.0.1:8443
+
+  # client-cert and client-key hold the PEM-encoded certificate and key
+  # with which juju authenticates to a remote LXD host. The certificate
+  # must be trusted by the host (see "lxc config trust add").
+  #
+  # client-cert:
+  # client-key:
+
+  # server-cert holds the PEM-encoded certificate of a remote LXD host.
+  # It is only needed if the certificate is self-signed.
+  #
+  # server-cert:
+
+  # network-bridge is the host bridge to which the containers' network
+  # interfaces are attached.
+  #
+  # network-bridge: lxdbr0
+
+  # storage-pool is the LXD storage pool in which the containers' root
+  # disks are created. If it is not set then LXD's default is used.
+  #
+  # storage-pool:
+
+  # image-server is the URL of the simplestreams server from which
+  # container images are fetched.
+  #
+  # image-server: https://cloud-images.ubuntu.com/releases
+
+`[1:]
+
+// configFields is the spec for each LXD config value's type.
+var configFields = schema.Fields{
+	cfgRemoteURL:     schema.String(),
+	cfgClientCert:    schema.String(),
+	cfgClientKey:     schema.String(),
+	cfgServerCert:    schema.String(),
+	cfgNetworkBridge: schema.String(),
+	cfgStoragePool:   schema.String(),
+	cfgImageServer:   schema.String(),
+}
+
+var configDefaults = schema.Defaults{
+	cfgRemoteURL:     "",
+	cfgClientCert:    "",
+	cfgClientKey:     "",
+	cfgServerCert:    "",
+	cfgNetworkBridge: defaultNetworkBridge,
+	cfgStoragePool:   "",
+	cfgImageServer:   lxdclient.DefaultImageServer,
+}
+
+var configSecretFields = []string{
+	cfgClientKey,
+}
+
+var configSchema = config.Schema{
+	cfgClientKey: {
+		Description: "The key with which juju authenticates to a remote LXD host",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
+var configImmutableFields = []string{
+	cfgRemoteURL,
+	cfgNetworkBridge,
+	cfgStoragePool,
+}
+
+type environConfig struct {
+	*config.Config
+	attrs map[string]interface{}
+}
+
+// newConfig builds a new environConfig from the provided Config and
+// returns it.
+func newConfig(cfg *config.Config) *environConfig {
+	return &environConfig{
+		Config: cfg,
+		attrs:  cfg.UnknownAttrs(),
+	}
+}
+
+// newValidConfig builds a new environConfig from the provided Config
+// and returns it. The resulting config values are validated.
+func newValidConfig(cfg *config.Config, defaults map[string]interface{}) (*environConfig, error) {
+	// Ensure that the provided config is valid.
+	if err := config.Validate(cfg, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Apply the defaults and coerce/validate the custom config attrs.
+	validated, err := cfg.ValidateUnknownAttrs(configFields, defaults)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validCfg, err := cfg.Apply(validated)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Build the config.
+	ecfg := newConfig(validCfg)
+
+	// Do final validation.
+	if err := ecfg.validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return ecfg, nil
+}
+
+func (c *environConfig) networkBridge() string {
+	return c.attrs[cfgNetworkBridge].(string)
+}
+
+func (c *environConfig) storagePool() string {
+	return c.attrs[cfgStoragePool].(string)
+}
+
+func (c *environConfig) imageServer() string {
+	return c.attrs[cfgImageServer].(string)
+}
+
+// clientConfig returns the configuration with which to connect to the
+// LXD host.
+func (c *environConfig) clientConfig() lxdclient.Config {
+	return lxdclient.Config{
+		Remote:     c.attrs[cfgRemoteURL].(string),
+		ClientCert: c.attrs[cfgClientCert].(string),
+		ClientKey:  c.attrs[cfgClientKey].(string),
+		ServerCert: c.attrs[cfgServerCert].(string),
+	}
+}
+
+// secret gathers the "secret" config values and returns them.
+func (c *environConfig) secret() map[string]string {
+	secretAttrs := make(map[string]string, len(configSecretFields))
+	for _, key := range configSecretFields {
+		secretAttrs[key] = c.attrs[key].(string)
+	}
+	return secretAttrs
+}
+
+// validate checks LXD-specific config values.
+func (c environConfig) validate() error {
+	if c.networkBridge() == "" {
+		return errors.Errorf("%s: must not be empty", cfgNetworkBridge)
+	}
+	if err := c.clientConfig().Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// update applies changes from the provided config to the env config.
+// Changes to any immutable attributes result in an error.
+func (c *environConfig) update(cfg *config.Config) error {
+	// Validate the updates. newValidConfig does not modify the "known"
+	// config attributes so it is safe to call Validate here first.
+	if err := config.Validate(cfg, c.Config); err != nil {
+		return errors.Trace(err)
+	}
+
+	updates, err := newValidConfig(cfg, configDefaults)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Check that no immutable fields have changed.
+	attrs := updates.UnknownAttrs()
+	for _, field := range configImmutableFields {
+		if attrs[field] != c.attrs[field] {
+			return errors.Errorf("%s: cannot change from %v to %v", field, c.attrs[field], attrs[field])
+		}
+	}
+
+	// Apply the updates.
+	c.Config = cfg
+	c.attrs = cfg.UnknownAttrs()
+	return nil
+}
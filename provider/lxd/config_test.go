@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/testing"
+)
+
+type configSuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func (s *configSuite) TestValidateDefaults(c *gc.C) {
+	validCfg, err := lxd.Provider.Validate(s.Config, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	attrs := validCfg.UnknownAttrs()
+	c.Check(attrs["remote-url"], gc.Equals, "")
+	c.Check(attrs["network-bridge"], gc.Equals, lxd.DefaultNetworkBridge)
+	c.Check(attrs["storage-pool"], gc.Equals, "")
+	c.Check(attrs["image-server"], gc.Equals, "https://cloud-images.ubuntu.com/releases")
+}
+
+func (s *configSuite) TestValidateRemote(c *gc.C) {
+	for i, test := range []struct {
+		attrs testing.Attrs
+		err   string
+	}{{
+		attrs: testing.Attrs{"remote-url": "https://10.0.0.1:8443", "client-cert": "cert", "client-key": "key"},
+	}, {
+		attrs: testing.Attrs{"remote-url": "10.0.0.1:8443", "client-cert": "cert", "client-key": "key"},
+		err:   `invalid config: remote "10.0.0.1:8443" \(must use https\) not valid`,
+	}, {
+		attrs: testing.Attrs{"remote-url": "https://10.0.0.1:8443"},
+		err:   "invalid config: remote without client certificate and key not valid",
+	}, {
+		attrs: testing.Attrs{"network-bridge": ""},
+		err:   "invalid config: network-bridge: must not be empty",
+	}} {
+		c.Logf("test %d", i)
+		cfg := s.NewConfig(c, test.attrs)
+		_, err := lxd.Provider.Validate(cfg, nil)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *configSuite) TestValidateImmutable(c *gc.C) {
+	old, err := lxd.Provider.Validate(s.Config, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, field := range lxd.ConfigImmutable {
+		c.Logf("field %q", field)
+		value := "spam"
+		if field == "remote-url" {
+			value = "https://10.0.0.1:8443"
+		}
+		cfg, err := old.Apply(testing.Attrs{
+			field:         value,
+			"client-cert": "cert",
+			"client-key":  "key",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+
+		_, err = lxd.Provider.Validate(cfg, old)
+		c.Check(err, gc.ErrorMatches, "invalid config change: "+field+": cannot change from .* to "+value)
+	}
+}
+
+func (s *configSuite) TestSecretAttrs(c *gc.C) {
+	cfg := s.NewConfig(c, testing.Attrs{
+		"remote-url":  "https://10.0.0.1:8443",
+		"client-cert": "cert",
+		"client-key":  "key",
+	})
+	validCfg, err := lxd.Provider.Validate(cfg, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	secrets, err := lxd.Provider.SecretAttrs(validCfg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(secrets, jc.DeepEquals, map[string]string{"client-key": "key"})
+}
+
+func (s *configSuite) TestSetConfig(c *gc.C) {
+	cfg, err := s.Config.Apply(testing.Attrs{"image-server": "https://example.com/images"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Env.SetConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.Env.Config().UnknownAttrs()["image-server"], gc.Equals, "https://example.com/images")
+}
+
+func (s *configSuite) TestBoilerplateConfig(c *gc.C) {
+	c.Check(lxd.Provider.BoilerplateConfig(), gc.Matches, "(?s)lxd:\n  type: lxd\n.*")
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+// Note: This provider/environment does *not* implement juju storage.
+// The storage-pool setting only selects where LXD creates the
+// containers' root disks.
+
+type lxdConnection interface {
+	Architectures() ([]string, error)
+
+	Instance(name string) (*lxdclient.Instance, error)
+	Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error)
+	AddInstance(spec lxdclient.InstanceSpec) (*lxdclient.Instance, error)
+	RemoveInstances(names ...string) error
+	Addresses(name string) ([]network.Address, error)
+
+	HasProfile(name string) (bool, error)
+	CreateProfile(spec lxdclient.ProfileSpec) error
+}
+
+type environ struct {
+	common.SupportsUnitPlacementPolicy
+
+	name string
+	uuid string
+	raw  lxdConnection
+
+	lock sync.Mutex
+	ecfg *environConfig
+
+	archLock               sync.Mutex
+	supportedArchitectures []string
+}
+
+func newEnviron(cfg *config.Config) (*environ, error) {
+	ecfg, err := newValidConfig(cfg, configDefaults)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+
+	uuid, ok := ecfg.UUID()
+	if !ok {
+		return nil, errors.New("UUID not set")
+	}
+
+	raw, err := newConnection(ecfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to LXD")
+	}
+
+	env := &environ{
+		name: ecfg.Name(),
+		uuid: uuid,
+		ecfg: ecfg,
+		raw:  raw,
+	}
+	return env, nil
+}
+
+var newConnection = func(ecfg *environConfig) (lxdConnection, error) {
+	return lxdclient.Connect(ecfg.clientConfig())
+}
+
+// Name returns the name of the environment.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider returns the environment provider that created this env.
+func (*environ) Provider() environs.EnvironProvider {
+	return providerInstance
+}
+
+// SetConfig updates the env's configuration.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	if env.ecfg == nil {
+		return errors.New("cannot set config on uninitialized env")
+	}
+
+	if err := env.ecfg.update(cfg); err != nil {
+		return errors.Annotate(err, "invalid config change")
+	}
+	return nil
+}
+
+// getSnapshot returns a copy of the environment. This is useful for
+// ensuring the env you are using does not get changed by other code
+// while you are using it.
+func (env environ) getSnapshot() *environ {
+	return &env
+}
+
+// Config returns the configuration data with which the env was created.
+func (env *environ) Config() *config.Config {
+	return env.getSnapshot().ecfg.Config
+}
+
+var bootstrap = common.Bootstrap
+
+// Bootstrap creates a new instance, chosing the series and arch out of
+// available tools. The series and arch are returned along with a func
+// that must be called to finalize the bootstrap process by transferring
+// the tools and installing the initial juju state server.
+func (env *environ) Bootstrap(ctx environs.BootstrapContext, params environs.BootstrapParams) (arch, series string, _ environs.BootstrapFinalizer, _ error) {
+	return bootstrap(ctx, env, params)
+}
+
+var destroyEnv = common.Destroy
+
+// Destroy shuts down all known machines and destroys the rest of the
+// known environment.
+func (env *environ) Destroy() error {
+	return destroyEnv(env)
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/tools"
+)
+
+func isStateServer(icfg *instancecfg.InstanceConfig) bool {
+	return multiwatcher.AnyJobNeedsState(icfg.Jobs...)
+}
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker.
+func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	env = env.getSnapshot()
+
+	if args.InstanceConfig.HasNetworks() {
+		return nil, errors.New("starting instances with networks is not supported yet")
+	}
+
+	if err := env.finishInstanceConfig(args); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	raw, err := env.newRawInstance(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("started instance %q", raw.Name)
+	inst := newInstance(raw, env)
+
+	result := environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: env.getHardwareCharacteristics(args, inst),
+	}
+	return &result, nil
+}
+
+// finishInstanceConfig updates args.InstanceConfig in place, choosing
+// tools for one of the architectures that the LXD host supports and
+// setting up the API, StateServing, and SSHkeys information.
+func (env *environ) finishInstanceConfig(args environs.StartInstanceParams) error {
+	arches, err := env.SupportedArchitectures()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, a := range arches {
+		if args.Constraints.Arch != nil && *args.Constraints.Arch != a {
+			continue
+		}
+		envTools, err := args.Tools.Match(tools.Filter{Arch: a})
+		if err == tools.ErrNoMatches {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		args.InstanceConfig.Tools = envTools[0]
+		return instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config())
+	}
+	return errors.Errorf("no tools available for architectures %v", arches)
+}
+
+// newRawInstance is where the new container is actually created,
+// relative to the provided args. Info for that low-level instance is
+// returned.
+func (env *environ) newRawInstance(args environs.StartInstanceParams) (*lxdclient.Instance, error) {
+	machineID := common.MachineFullName(env, args.InstanceConfig.MachineId)
+
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	// LXD passes the user data to cloud-init as is, so it must not
+	// be compressed.
+	userData, err = utils.Gunzip(userData)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Debugf("LXD user data; %d bytes", len(userData))
+
+	profiles, err := env.instanceProfiles(args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	metadata := map[string]string{
+		metadataKeyIsState: metadataValueFalse,
+	}
+	if isStateServer(args.InstanceConfig) {
+		metadata[metadataKeyIsState] = metadataValueTrue
+	}
+
+	spec := lxdclient.InstanceSpec{
+		Name:        machineID,
+		Series:      args.InstanceConfig.Series,
+		ImageServer: env.ecfg.imageServer(),
+		Profiles:    profiles,
+		UserData:    string(userData),
+		Metadata:    metadata,
+	}
+	raw, err := env.raw.AddInstance(spec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return raw, nil
+}
+
+// getHardwareCharacteristics compiles hardware-related details about
+// the given instance, relative to the provided args, and returns it.
+func (env *environ) getHardwareCharacteristics(args environs.StartInstanceParams, inst *environInstance) *instance.HardwareCharacteristics {
+	hwc := &instance.HardwareCharacteristics{
+		CpuCores: args.Constraints.CpuCores,
+		Mem:      args.Constraints.Mem,
+	}
+	if inst.raw.Architecture != "" {
+		a := arch.NormaliseArch(inst.raw.Architecture)
+		hwc.Arch = &a
+	}
+	return hwc
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances() ([]instance.Instance, error) {
+	instances, err := env.instances()
+	return instances, errors.Trace(err)
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(instances ...instance.Id) error {
+	env = env.getSnapshot()
+
+	var names []string
+	for _, id := range instances {
+		names = append(names, string(id))
+	}
+	err := env.raw.RemoveInstances(names...)
+	return errors.Trace(err)
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+)
+
+type environBrokerSuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
+	s.FakeConn.Inst = s.RawInstance
+
+	raw, err := lxd.NewRawInstance(s.Env, s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(raw, jc.DeepEquals, s.RawInstance)
+
+	called, calls := s.FakeConn.WasCalled("AddInstance")
+	c.Assert(called, jc.IsTrue)
+	spec := calls[0].InstanceSpec
+	c.Check(spec.Name, gc.Equals, s.Prefix+"machine-0")
+	c.Check(spec.Series, gc.Equals, "trusty")
+	c.Check(spec.ImageServer, gc.Equals, lxdclient.DefaultImageServer)
+	c.Check(spec.Profiles, jc.DeepEquals, []string{"default", "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba"})
+	c.Check(spec.Metadata, jc.DeepEquals, map[string]string{
+		lxd.MetadataKeyIsState: lxd.MetadataValueTrue,
+	})
+	c.Check(spec.UserData, gc.Matches, "(?s)#cloud-config\n.*")
+}
+
+func (s *environBrokerSuite) TestInstanceProfilesCreatesProfiles(c *gc.C) {
+	cons := constraints.MustParse("cpu-cores=2 mem=2G")
+
+	profiles, err := lxd.InstanceProfiles(s.Env, cons)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(profiles, jc.DeepEquals, []string{
+		"default",
+		"juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+		"juju-cpu-2-mem-2048mb",
+	})
+	_, calls := s.FakeConn.WasCalled("CreateProfile")
+	c.Assert(calls, gc.HasLen, 2)
+	c.Check(calls[0].ProfileSpec.Devices, jc.DeepEquals, map[string]lxdclient.Device{
+		"eth0": {"type": "nic", "nictype": "bridged", "parent": "lxdbr0"},
+	})
+	c.Check(calls[1].ProfileSpec.Config, jc.DeepEquals, map[string]string{
+		"limits.cpu":    "2",
+		"limits.memory": "2048MB",
+	})
+}
+
+func (s *environBrokerSuite) TestInstanceProfilesExisting(c *gc.C) {
+	s.FakeConn.Profiles = map[string]bool{
+		"juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba": true,
+	}
+
+	profiles, err := lxd.InstanceProfiles(s.Env, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(profiles, jc.DeepEquals, []string{"default", "juju-2d02eeac-9dbb-11e4-89d3-123b93f75cba"})
+	called, _ := s.FakeConn.WasCalled("CreateProfile")
+	c.Check(called, jc.IsFalse)
+}
+
+func (s *environBrokerSuite) TestProfileSpecStoragePool(c *gc.C) {
+	s.UpdateConfig(c, testing.Attrs{"storage-pool": "fast", "network-bridge": "br0"})
+
+	spec := lxd.ProfileSpec(s.Env)
+	c.Check(spec.Devices, jc.DeepEquals, map[string]lxdclient.Device{
+		"eth0": {"type": "nic", "nictype": "bridged", "parent": "br0"},
+		"root": {"type": "disk", "path": "/", "pool": "fast"},
+	})
+}
+
+func (s *environBrokerSuite) TestConstraintsProfileSpecEmpty(c *gc.C) {
+	_, ok := lxd.ConstraintsProfileSpec(constraints.MustParse("arch=amd64"))
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance}
+
+	insts, err := s.Env.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 1)
+	c.Check(insts[0].Id(), gc.Equals, s.Instance.Id())
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	err := s.Env.StopInstances(s.Instance.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveInstances")
+	c.Check(s.FakeConn.Calls[0].Names, jc.DeepEquals, []string{string(s.Instance.Id())})
+}
+
+func (s *environBrokerSuite) TestStopInstancesNoInstances(c *gc.C) {
+	err := s.Env.StopInstances()
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+// The metadata keys recorded on the containers of the environment.
+const (
+	metadataKeyIsState = "juju-is-state"
+
+	metadataValueTrue  = "true"
+	metadataValueFalse = "false"
+)
+
+// Instances returns the available instances in the environment that
+// match the provided instance IDs. For IDs that did not match any
+// instances, the result at the corresponding index will be nil. In that
+// case the error will be environs.ErrPartialInstances (or
+// ErrNoInstances if none of the IDs match an instance).
+func (env *environ) Instances(ids []instance.Id) ([]instance.Instance, error) {
+	if len(ids) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+
+	instances, err := env.instances()
+	if err != nil {
+		// We don't return the error since we need to pack one instance
+		// for each ID into the result. If there is a problem then we
+		// will return either ErrPartialInstances or ErrNoInstances.
+		logger.Errorf("failed to get instances from LXD: %v", err)
+		err = errors.Trace(err)
+	}
+
+	// Build the result, matching the provided instance IDs.
+	numFound := 0 // This will never be greater than len(ids).
+	results := make([]instance.Instance, len(ids))
+	for i, id := range ids {
+		inst := findInst(id, instances)
+		if inst != nil {
+			numFound++
+		}
+		results[i] = inst
+	}
+
+	if numFound == 0 {
+		if err == nil {
+			err = environs.ErrNoInstances
+		}
+	} else if numFound != len(ids) {
+		err = environs.ErrPartialInstances
+	}
+	return results, err
+}
+
+// instances returns a list of all "alive" instances in the environment.
+// This means only instances where the IDs match
+// "juju-<env uuid>-machine-*". This is important because otherwise juju
+// will see they are not tracked in state, assume they're stale/rogue,
+// and shut them down.
+func (env *environ) instances() ([]instance.Instance, error) {
+	env = env.getSnapshot()
+
+	prefix := common.MachineFullName(env, "")
+	instances, err := env.raw.Instances(prefix)
+	err = errors.Trace(err)
+
+	// Turn lxdclient.Instance values into *environInstance values,
+	// whether or not we got an error.
+	var results []instance.Instance
+	for _, raw := range instances {
+		// If we don't make a copy then the same pointer is used for the
+		// raw data of all resulting instances.
+		copied := raw
+		inst := newInstance(&copied, env)
+		results = append(results, inst)
+	}
+
+	return results, err
+}
+
+// StateServerInstances returns the IDs of the instances corresponding
+// to juju state servers.
+func (env *environ) StateServerInstances() ([]instance.Id, error) {
+	env = env.getSnapshot()
+
+	prefix := common.MachineFullName(env, "")
+	instances, err := env.raw.Instances(prefix, lxdclient.StatusRunning)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var results []instance.Id
+	for _, inst := range instances {
+		if inst.Metadata[metadataKeyIsState] == metadataValueTrue {
+			results = append(results, instance.Id(inst.Name))
+		}
+	}
+	if len(results) == 0 {
+		return nil, environs.ErrNotBootstrapped
+	}
+	return results, nil
+}
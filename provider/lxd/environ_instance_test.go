@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+type environInstSuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&environInstSuite{})
+
+func (s *environInstSuite) TestInstances(c *gc.C) {
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance}
+
+	ids := []instance.Id{s.Instance.Id()}
+	insts, err := s.Env.Instances(ids)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(insts, gc.HasLen, 1)
+	c.Check(insts[0].Id(), gc.Equals, s.Instance.Id())
+	c.Check(insts[0].Status(), gc.Equals, lxdclient.StatusRunning)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, s.Prefix+"machine-")
+}
+
+func (s *environInstSuite) TestInstancesEmptyArg(c *gc.C) {
+	insts, err := s.Env.Instances(nil)
+
+	c.Check(insts, gc.HasLen, 0)
+	c.Check(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environInstSuite) TestInstancesPartialMatch(c *gc.C) {
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance}
+
+	ids := []instance.Id{s.Instance.Id(), "eggs"}
+	insts, err := s.Env.Instances(ids)
+
+	c.Check(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts, gc.HasLen, 2)
+	c.Check(insts[0].Id(), gc.Equals, s.Instance.Id())
+	c.Check(insts[1], gc.IsNil)
+}
+
+func (s *environInstSuite) TestInstancesNoMatch(c *gc.C) {
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance}
+
+	_, err := s.Env.Instances([]instance.Id{"eggs"})
+	c.Check(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environInstSuite) TestStateServerInstances(c *gc.C) {
+	other := lxdclient.Instance{
+		Name:     s.Prefix + "machine-eggs",
+		Metadata: map[string]string{lxd.MetadataKeyIsState: lxd.MetadataValueFalse},
+	}
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance, other}
+
+	ids, err := s.Env.StateServerInstances()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids, jc.DeepEquals, []instance.Id{s.Instance.Id()})
+	c.Check(s.FakeConn.Calls[0].Statuses, jc.DeepEquals, []string{lxdclient.StatusRunning})
+}
+
+func (s *environInstSuite) TestStateServerInstancesNotBootstrapped(c *gc.C) {
+	_, err := s.Env.StateServerInstances()
+	c.Check(err, gc.Equals, environs.ErrNotBootstrapped)
+}
+
+func (s *environInstSuite) TestInstanceAddresses(c *gc.C) {
+	s.FakeConn.Insts = []lxdclient.Instance{*s.RawInstance}
+	insts, err := s.Env.Instances([]instance.Id{s.Instance.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	s.FakeConn.Calls = nil
+
+	_, err = insts[0].Addresses()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Addresses")
+	c.Check(s.FakeConn.Calls[0].Name, gc.Equals, string(s.Instance.Id()))
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
+)
+
+// PrecheckInstance verifies that the provided series and constraints
+// are valid for use in creating an instance in this environment.
+func (env *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
+	if placement != "" {
+		return errors.Errorf("unknown placement directive: %s", placement)
+	}
+	return nil
+}
+
+// SupportedArchitectures returns the image architectures which can
+// be hosted by this environment.
+func (env *environ) SupportedArchitectures() ([]string, error) {
+	env.archLock.Lock()
+	defer env.archLock.Unlock()
+
+	if env.supportedArchitectures != nil {
+		return env.supportedArchitectures, nil
+	}
+
+	raw, err := env.raw.Architectures()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	arches := set.NewStrings()
+	for _, a := range raw {
+		a = arch.NormaliseArch(a)
+		if arch.IsSupportedArch(a) {
+			arches.Add(a)
+		}
+	}
+	env.supportedArchitectures = arches.SortedValues()
+	return env.supportedArchitectures, nil
+}
+
+var unsupportedConstraints = []string{
+	constraints.CpuPower,
+	constraints.RootDisk,
+	constraints.InstanceType,
+	constraints.Tags,
+	constraints.Networks,
+}
+
+// ConstraintsValidator returns a Validator value which is used to
+// validate and merge constraints.
+func (env *environ) ConstraintsValidator() (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+
+	// unsupported
+	validator.RegisterUnsupported(unsupportedConstraints)
+
+	// vocab
+	supportedArches, err := env.SupportedArchitectures()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+
+	return validator, nil
+}
+
+// SupportsUnitPlacement implement via common.SupportsUnitPlacementPolicy
+
+// SupportNetworks returns whether the environment has support to
+// specify networks for services and machines.
+func (env *environ) SupportNetworks() bool {
+	return false
+}
+
+// SupportAddressAllocation takes a network.Id and returns a bool
+// and an error. The bool indicates whether that network supports
+// static ip address allocation.
+func (env *environ) SupportAddressAllocation(netID network.Id) (bool, error) {
+	return false, nil
+}
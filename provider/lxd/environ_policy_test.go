@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/lxd"
+)
+
+type environPolicySuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&environPolicySuite{})
+
+func (s *environPolicySuite) TestSupportedArchitectures(c *gc.C) {
+	arches, err := s.Env.SupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arches, jc.DeepEquals, []string{"amd64", "i386"})
+
+	// The result is cached.
+	_, err = s.Env.SupportedArchitectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+}
+
+func (s *environPolicySuite) TestPrecheckInstance(c *gc.C) {
+	err := s.Env.PrecheckInstance("trusty", constraints.Value{}, "")
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *environPolicySuite) TestPrecheckInstancePlacement(c *gc.C) {
+	err := s.Env.PrecheckInstance("trusty", constraints.Value{}, "zone=a-zone")
+	c.Check(err, gc.ErrorMatches, "unknown placement directive: zone=a-zone")
+}
+
+func (s *environPolicySuite) TestConstraintsValidator(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("arch=amd64 cpu-cores=2 mem=1G root-disk=10G cpu-power=100")
+	unsupported, err := validator.Validate(cons)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unsupported, jc.SameContents, []string{"root-disk", "cpu-power"})
+}
+
+func (s *environPolicySuite) TestConstraintsValidatorVocab(c *gc.C) {
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = validator.Validate(constraints.MustParse("arch=ppc64el"))
+	c.Check(err, gc.ErrorMatches, "invalid constraint value: arch=ppc64el\nvalid values are:.*")
+}
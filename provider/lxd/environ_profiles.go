@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+// defaultProfile is the profile that LXD creates and applies to all
+// containers by default.
+const defaultProfile = "default"
+
+// instanceProfiles returns the names of the profiles to apply to a new
+// container with the given constraints, creating any that do not yet
+// exist. The environment's profile attaches the container to the
+// network bridge and storage pool; constraints are applied by a
+// profile shared by all containers with the same constraints.
+func (env *environ) instanceProfiles(cons constraints.Value) ([]string, error) {
+	envProfile := env.profileSpec()
+	if err := env.ensureProfile(envProfile); err != nil {
+		return nil, errors.Trace(err)
+	}
+	profiles := []string{defaultProfile, envProfile.Name}
+
+	if consProfile, ok := constraintsProfileSpec(cons); ok {
+		if err := env.ensureProfile(consProfile); err != nil {
+			return nil, errors.Trace(err)
+		}
+		profiles = append(profiles, consProfile.Name)
+	}
+	return profiles, nil
+}
+
+// profileSpec returns the spec of the profile applied to all of the
+// environment's containers.
+func (env *environ) profileSpec() lxdclient.ProfileSpec {
+	devices := map[string]lxdclient.Device{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  env.ecfg.networkBridge(),
+		},
+	}
+	if pool := env.ecfg.storagePool(); pool != "" {
+		devices["root"] = lxdclient.Device{
+			"type": "disk",
+			"path": "/",
+			"pool": pool,
+		}
+	}
+	return lxdclient.ProfileSpec{
+		Name:        common.EnvFullName(env),
+		Description: fmt.Sprintf("juju environment %q", env.name),
+		Devices:     devices,
+	}
+}
+
+// constraintsProfileSpec returns the spec of the profile that limits a
+// container's resources to those requested by the given constraints.
+// It returns false if no such limits are needed.
+func constraintsProfileSpec(cons constraints.Value) (lxdclient.ProfileSpec, bool) {
+	config := make(map[string]string)
+	var parts []string
+	if cons.CpuCores != nil {
+		config["limits.cpu"] = fmt.Sprint(*cons.CpuCores)
+		parts = append(parts, fmt.Sprintf("cpu-%d", *cons.CpuCores))
+	}
+	if cons.Mem != nil {
+		config["limits.memory"] = fmt.Sprintf("%dMB", *cons.Mem)
+		parts = append(parts, fmt.Sprintf("mem-%dmb", *cons.Mem))
+	}
+	if len(parts) == 0 {
+		return lxdclient.ProfileSpec{}, false
+	}
+	return lxdclient.ProfileSpec{
+		Name:        "juju-" + strings.Join(parts, "-"),
+		Description: fmt.Sprintf("juju constraints %q", cons.String()),
+		Config:      config,
+	}, true
+}
+
+// ensureProfile creates the described profile if it does not already
+// exist.
+func (env *environ) ensureProfile(spec lxdclient.ProfileSpec) error {
+	found, err := env.raw.HasProfile(spec.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if found {
+		return nil
+	}
+	if err := env.raw.CreateProfile(spec); err != nil {
+		// The profile may have been created concurrently.
+		if found, _ := env.raw.HasProfile(spec.Name); found {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	return nil
+}
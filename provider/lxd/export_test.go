@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+var (
+	Provider               environs.EnvironProvider = providerInstance
+	ConstraintsProfileSpec                          = constraintsProfileSpec
+	ConfigImmutable                                 = configImmutableFields
+	DefaultNetworkBridge                            = defaultNetworkBridge
+	MetadataKeyIsState                              = metadataKeyIsState
+	MetadataValueTrue                               = metadataValueTrue
+	MetadataValueFalse                              = metadataValueFalse
+)
+
+func NewRawInstance(env *environ, args environs.StartInstanceParams) (*lxdclient.Instance, error) {
+	return env.newRawInstance(args)
+}
+
+func InstanceProfiles(env *environ, cons constraints.Value) ([]string, error) {
+	return env.instanceProfiles(cons)
+}
+
+func ProfileSpec(env *environ) lxdclient.ProfileSpec {
+	return env.profileSpec()
+}
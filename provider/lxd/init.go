@@ -0,0 +1,20 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/storage/provider/registry"
+)
+
+const (
+	providerType = "lxd"
+)
+
+func init() {
+	environs.RegisterProvider(providerType, providerInstance)
+	config.RegisterProviderSchema(providerType, configSchema)
+	registry.RegisterEnvironStorageProviders(providerType)
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+type environInstance struct {
+	raw lxdclient.Instance
+	env *environ
+}
+
+var _ instance.Instance = (*environInstance)(nil)
+
+func newInstance(raw *lxdclient.Instance, env *environ) *environInstance {
+	return &environInstance{
+		raw: *raw,
+		env: env,
+	}
+}
+
+// Id implements instance.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.raw.Name)
+}
+
+// Status implements instance.Instance.
+func (inst *environInstance) Status() string {
+	return inst.raw.Status
+}
+
+// Refresh implements instance.Instance.
+func (inst *environInstance) Refresh() error {
+	env := inst.env.getSnapshot()
+	raw, err := env.raw.Instance(inst.raw.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	inst.raw = *raw
+	return nil
+}
+
+// Addresses implements instance.Instance.
+func (inst *environInstance) Addresses() ([]network.Address, error) {
+	env := inst.env.getSnapshot()
+	addrs, err := env.raw.Addresses(inst.raw.Name)
+	return addrs, errors.Trace(err)
+}
+
+func findInst(id instance.Id, instances []instance.Instance) instance.Instance {
+	for _, inst := range instances {
+		if id == inst.Id() {
+			return inst
+		}
+	}
+	return nil
+}
+
+// firewall stuff
+
+// Containers are attached directly to the host's bridge, so there is
+// no firewall between them and the host.
+
+// OpenPorts opens the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) OpenPorts(machineID string, ports []network.PortRange) error {
+	logger.Infof("OpenPorts called for %s:%v", machineID, ports)
+	return nil
+}
+
+// ClosePorts closes the given ports on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) ClosePorts(machineID string, ports []network.PortRange) error {
+	logger.Infof("ClosePorts called for %s:%v", machineID, ports)
+	return nil
+}
+
+// Ports returns the set of ports open on the instance, which
+// should have been started with the given machine id.
+func (inst *environInstance) Ports(machineID string) ([]network.PortRange, error) {
+	return nil, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package lxdclient provides a minimal client for the LXD REST API,
+// limited to the operations needed by the juju LXD provider.
+package lxdclient
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.provider.lxd.lxdclient")
+
+// LocalSocket is the path of the unix socket on which a local LXD
+// daemon serves its API.
+var LocalSocket = "/var/lib/lxd/unix.socket"
+
+const apiVersion = "/1.0"
+
+// Config holds the information needed to connect to an LXD server.
+type Config struct {
+	// Remote is the URL of a remote LXD server's API, for example
+	// "https://10.0.0.1:8443". If it is empty then the local LXD
+	// daemon is used, via its unix socket.
+	Remote string
+
+	// ClientCert and ClientKey hold the PEM-encoded certificate and
+	// key with which the client authenticates to a remote server.
+	ClientCert string
+	ClientKey  string
+
+	// ServerCert holds the PEM-encoded certificate of a remote
+	// server. If it is empty then the server's certificate must be
+	// signed by a trusted authority.
+	ServerCert string
+}
+
+// Validate checks the config, returning an error if it cannot be used
+// to connect to an LXD server.
+func (cfg Config) Validate() error {
+	if cfg.Remote == "" {
+		return nil
+	}
+	remote, err := url.Parse(cfg.Remote)
+	if err != nil {
+		return errors.Annotate(err, "invalid remote")
+	}
+	if remote.Scheme != "https" {
+		return errors.NotValidf("remote %q (must use https)", cfg.Remote)
+	}
+	if cfg.ClientCert == "" || cfg.ClientKey == "" {
+		return errors.NotValidf("remote without client certificate and key")
+	}
+	return nil
+}
+
+// Client provides methods for interacting with an LXD server.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// Connect returns a Client for the LXD server described by the config.
+func Connect(cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cfg.Remote == "" {
+		transport := &http.Transport{
+			Dial: func(string, string) (net.Conn, error) {
+				return net.Dial("unix", LocalSocket)
+			},
+		}
+		return newClient("http://unix.socket", &http.Client{Transport: transport}), nil
+	}
+
+	cert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid client certificate")
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+	if cfg.ServerCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.ServerCert)) {
+			return nil, errors.New("invalid server certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport := &http.Transport{TLSClientConfig: tlsConfig}
+	return newClient(cfg.Remote, &http.Client{Transport: transport}), nil
+}
+
+func newClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		http:    httpClient,
+	}
+}
+
+// response is the envelope in which LXD returns the result of every
+// API call.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// The response types returned by LXD.
+const (
+	responseSync  = "sync"
+	responseAsync = "async"
+	responseError = "error"
+)
+
+// call sends a request to the LXD API and returns its response. Error
+// responses are returned as errors; a missing resource results in an
+// error satisfying errors.IsNotFound.
+func (c *Client) call(method, path string, body interface{}) (*response, error) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reqBody).Encode(body); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, &reqBody)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	logger.Tracef("%s %s", method, path)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot send %s %s", method, path)
+	}
+	defer resp.Body.Close()
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode response to %s %s", method, path)
+	}
+	if result.Type == responseError {
+		if result.ErrorCode == http.StatusNotFound {
+			return nil, errors.NotFoundf(path)
+		}
+		return nil, errors.Errorf("LXD %s %s failed: %s", method, path, result.Error)
+	}
+	return &result, nil
+}
+
+// get sends a GET request to the LXD API and decodes the response's
+// metadata into result.
+func (c *Client) get(path string, result interface{}) error {
+	resp, err := c.call("GET", path, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(json.Unmarshal(resp.Metadata, result))
+}
+
+// operation holds the metadata of an asynchronous LXD operation.
+type operation struct {
+	ID         string `json:"id"`
+	StatusCode int    `json:"status_code"`
+	Err        string `json:"err"`
+}
+
+// The status code of a successful operation.
+const statusSuccess = 200
+
+// do sends a request to the LXD API and, if it started an asynchronous
+// operation, waits for the operation to finish.
+func (c *Client) do(method, path string, body interface{}) error {
+	resp, err := c.call(method, path, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.Type != responseAsync {
+		return nil
+	}
+
+	var op operation
+	if err := c.get(resp.Operation+"/wait", &op); err != nil {
+		return errors.Annotatef(err, "waiting for %s %s", method, path)
+	}
+	if op.StatusCode != statusSuccess {
+		return errors.Errorf("LXD operation %q failed: %s", op.ID, op.Err)
+	}
+	return nil
+}
+
+// serverInfo holds the parts of the LXD server's description used by
+// the client.
+type serverInfo struct {
+	Environment struct {
+		Architectures []string `json:"architectures"`
+	} `json:"environment"`
+}
+
+// Architectures returns the architectures of the containers that the
+// server can host, as reported by LXD (for example "x86_64").
+func (c *Client) Architectures() ([]string, error) {
+	var info serverInfo
+	if err := c.get(apiVersion, &info); err != nil {
+		return nil, errors.Annotate(err, "cannot get LXD server info")
+	}
+	return info.Environment.Architectures, nil
+}
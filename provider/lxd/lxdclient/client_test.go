@@ -0,0 +1,295 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+)
+
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+// fakeServer serves canned LXD API responses, keyed by method and
+// path, and records the requests it receives.
+type fakeServer struct {
+	*httptest.Server
+
+	Requests  []fakeRequest
+	Responses map[string]string
+}
+
+func newFakeServer() *fakeServer {
+	s := &fakeServer{Responses: make(map[string]string)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *fakeServer) serve(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	var body map[string]interface{}
+	data, _ := ioutil.ReadAll(req.Body)
+	if len(data) > 0 {
+		json.Unmarshal(data, &body)
+	}
+	s.Requests = append(s.Requests, fakeRequest{req.Method, path, body})
+
+	resp, ok := s.Responses[req.Method+" "+path]
+	if !ok {
+		resp = `{"type": "error", "error": "not found", "error_code": 404}`
+	}
+	w.Write([]byte(resp))
+}
+
+type clientSuite struct {
+	testing.BaseSuite
+
+	server *fakeServer
+	client *lxdclient.Client
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.server = newFakeServer()
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.client = lxdclient.NewClient(s.server.URL)
+}
+
+func (s *clientSuite) TestConfigValidate(c *gc.C) {
+	for i, test := range []struct {
+		cfg lxdclient.Config
+		err string
+	}{{
+		cfg: lxdclient.Config{},
+	}, {
+		cfg: lxdclient.Config{Remote: "https://10.0.0.1:8443", ClientCert: "cert", ClientKey: "key"},
+	}, {
+		cfg: lxdclient.Config{Remote: "http://10.0.0.1:8443", ClientCert: "cert", ClientKey: "key"},
+		err: `remote "http://10.0.0.1:8443" \(must use https\) not valid`,
+	}, {
+		cfg: lxdclient.Config{Remote: "https://10.0.0.1:8443"},
+		err: "remote without client certificate and key not valid",
+	}} {
+		c.Logf("test %d", i)
+		err := test.cfg.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *clientSuite) TestArchitectures(c *gc.C) {
+	s.server.Responses["GET /1.0"] = `{"type": "sync", "metadata": {"environment": {"architectures": ["x86_64", "i686"]}}}`
+
+	arches, err := s.client.Architectures()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(arches, jc.DeepEquals, []string{"x86_64", "i686"})
+}
+
+func (s *clientSuite) TestInstances(c *gc.C) {
+	s.server.Responses["GET /1.0/containers?recursion=1"] = `{"type": "sync", "metadata": [
+		{"name": "juju-machine-0", "status": "Running", "architecture": "x86_64",
+		 "profiles": ["default"], "config": {"user.juju-is-state": "true", "user.user-data": "#cloud-config", "limits.cpu": "2"}},
+		{"name": "juju-machine-1", "status": "Stopped"},
+		{"name": "other", "status": "Running"}
+	]}`
+
+	insts, err := s.client.Instances("juju-", lxdclient.StatusRunning)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(insts, jc.DeepEquals, []lxdclient.Instance{{
+		Name:         "juju-machine-0",
+		Status:       lxdclient.StatusRunning,
+		Architecture: "x86_64",
+		Profiles:     []string{"default"},
+		Metadata:     map[string]string{"juju-is-state": "true"},
+	}})
+}
+
+func (s *clientSuite) TestInstanceNotFound(c *gc.C) {
+	_, err := s.client.Instance("missing")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *clientSuite) TestAddInstance(c *gc.C) {
+	s.server.Responses["POST /1.0/containers"] = `{"type": "async", "operation": "/1.0/operations/1"}`
+	s.server.Responses["GET /1.0/operations/1/wait"] = `{"type": "sync", "metadata": {"id": "1", "status_code": 200}}`
+	s.server.Responses["PUT /1.0/containers/spam/state"] = `{"type": "async", "operation": "/1.0/operations/1"}`
+	s.server.Responses["GET /1.0/containers/spam"] = `{"type": "sync", "metadata": {"name": "spam", "status": "Running"}}`
+
+	inst, err := s.client.AddInstance(lxdclient.InstanceSpec{
+		Name:     "spam",
+		Series:   "trusty",
+		Profiles: []string{"default", "juju"},
+		UserData: "#cloud-config",
+		Metadata: map[string]string{"juju-is-state": "false"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(inst.Name, gc.Equals, "spam")
+
+	c.Assert(s.server.Requests, gc.HasLen, 5)
+	req := s.server.Requests[0]
+	c.Check(req.Method+" "+req.Path, gc.Equals, "POST /1.0/containers")
+	c.Check(req.Body, jc.DeepEquals, map[string]interface{}{
+		"name":     "spam",
+		"profiles": []interface{}{"default", "juju"},
+		"config": map[string]interface{}{
+			"user.user-data":     "#cloud-config",
+			"user.juju-is-state": "false",
+		},
+		"source": map[string]interface{}{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   lxdclient.DefaultImageServer,
+			"protocol": "simplestreams",
+			"alias":    "trusty",
+		},
+	})
+	c.Check(s.server.Requests[2].Body["action"], gc.Equals, "start")
+}
+
+func (s *clientSuite) TestAddInstanceOperationFailed(c *gc.C) {
+	s.server.Responses["POST /1.0/containers"] = `{"type": "async", "operation": "/1.0/operations/1"}`
+	s.server.Responses["GET /1.0/operations/1/wait"] = `{"type": "sync", "metadata": {"id": "1", "status_code": 400, "err": "no such image"}}`
+
+	_, err := s.client.AddInstance(lxdclient.InstanceSpec{Name: "spam", Series: "trusty"})
+	c.Check(err, gc.ErrorMatches, `cannot create container "spam": LXD operation "1" failed: no such image`)
+}
+
+func (s *clientSuite) TestRemoveInstances(c *gc.C) {
+	s.server.Responses["GET /1.0/containers/spam"] = `{"type": "sync", "metadata": {"name": "spam", "status": "Running"}}`
+	s.server.Responses["PUT /1.0/containers/spam/state"] = `{"type": "sync"}`
+	s.server.Responses["DELETE /1.0/containers/spam"] = `{"type": "sync"}`
+
+	err := s.client.RemoveInstances("spam", "missing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	var calls []string
+	for _, req := range s.server.Requests {
+		calls = append(calls, req.Method+" "+req.Path)
+	}
+	c.Check(calls, jc.DeepEquals, []string{
+		"GET /1.0/containers/spam",
+		"PUT /1.0/containers/spam/state",
+		"DELETE /1.0/containers/spam",
+		"GET /1.0/containers/missing",
+	})
+}
+
+func (s *clientSuite) TestAddresses(c *gc.C) {
+	s.server.Responses["GET /1.0/containers/spam/state"] = `{"type": "sync", "metadata": {"network": {
+		"lo": {"addresses": [{"family": "inet", "address": "127.0.0.1", "scope": "local"}]},
+		"eth0": {"addresses": [
+			{"family": "inet", "address": "10.0.3.5", "scope": "global"},
+			{"family": "inet6", "address": "fe80::1", "scope": "link"}
+		]}
+	}}}`
+
+	addrs, err := s.client.Addresses("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(addrs, jc.DeepEquals, []network.Address{network.NewAddress("10.0.3.5")})
+}
+
+func (s *clientSuite) TestHasProfile(c *gc.C) {
+	s.server.Responses["GET /1.0/profiles/juju"] = `{"type": "sync", "metadata": {"name": "juju"}}`
+
+	found, err := s.client.HasProfile("juju")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(found, jc.IsTrue)
+
+	found, err = s.client.HasProfile("missing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(found, jc.IsFalse)
+}
+
+func (s *clientSuite) TestCreateProfile(c *gc.C) {
+	s.server.Responses["POST /1.0/profiles"] = `{"type": "sync"}`
+
+	err := s.client.CreateProfile(lxdclient.ProfileSpec{
+		Name:   "juju",
+		Config: map[string]string{"limits.cpu": "2"},
+		Devices: map[string]lxdclient.Device{
+			"eth0": {"type": "nic", "nictype": "bridged", "parent": "lxdbr0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.server.Requests, gc.HasLen, 1)
+	c.Check(s.server.Requests[0].Body, jc.DeepEquals, map[string]interface{}{
+		"name":        "juju",
+		"description": "",
+		"config":      map[string]interface{}{"limits.cpu": "2"},
+		"devices": map[string]interface{}{
+			"eth0": map[string]interface{}{"type": "nic", "nictype": "bridged", "parent": "lxdbr0"},
+		},
+	})
+}
+
+func (s *clientSuite) TestServerConfig(c *gc.C) {
+	s.server.Responses["GET /1.0"] = `{"type": "sync", "metadata": {
+		"config": {"core.https_address": "[::]:8443"},
+		"environment": {"certificate": "server-cert"}
+	}}`
+
+	cfg, err := s.client.ServerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg, jc.DeepEquals, lxdclient.ServerConfig{
+		HTTPSAddress: "[::]:8443",
+		Certificate:  "server-cert",
+	})
+}
+
+func (s *clientSuite) TestSetHTTPSAddress(c *gc.C) {
+	s.server.Responses["GET /1.0"] = `{"type": "sync", "metadata": {"config": {"images.remote_cache_expiry": "10"}}}`
+	s.server.Responses["PUT /1.0"] = `{"type": "sync"}`
+
+	err := s.client.SetHTTPSAddress("10.0.3.1:8443")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The rest of the server's config is preserved.
+	c.Assert(s.server.Requests, gc.HasLen, 2)
+	c.Check(s.server.Requests[1].Body, jc.DeepEquals, map[string]interface{}{
+		"config": map[string]interface{}{
+			"images.remote_cache_expiry": "10",
+			"core.https_address":         "10.0.3.1:8443",
+		},
+	})
+}
+
+func (s *clientSuite) TestAddTrustedCert(c *gc.C) {
+	s.server.Responses["POST /1.0/certificates"] = `{"type": "sync"}`
+
+	err := s.client.AddTrustedCert("juju", testing.CACert)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.server.Requests, gc.HasLen, 1)
+	body := s.server.Requests[0].Body
+	c.Check(body["type"], gc.Equals, "client")
+	c.Check(body["name"], gc.Equals, "juju")
+	c.Check(body["certificate"], gc.Not(gc.Equals), "")
+}
+
+func (s *clientSuite) TestAddTrustedCertInvalid(c *gc.C) {
+	err := s.client.AddTrustedCert("juju", "not a certificate")
+	c.Check(err, gc.ErrorMatches, "invalid client certificate")
+}
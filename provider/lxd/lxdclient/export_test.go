@@ -0,0 +1,12 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"net/http"
+)
+
+func NewClient(baseURL string) *Client {
+	return newClient(baseURL, http.DefaultClient)
+}
@@ -0,0 +1,253 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/network"
+)
+
+// The container statuses reported by LXD.
+const (
+	StatusRunning = "Running"
+	StatusStopped = "Stopped"
+)
+
+// UserDataKey is the container config key holding the cloud-init user
+// data with which a container is initialised.
+const UserDataKey = "user.user-data"
+
+// metadataPrefix is prepended to the keys of instance metadata to make
+// them valid container config keys.
+const metadataPrefix = "user."
+
+// DefaultImageServer is the simplestreams server from which container
+// images are fetched if none is specified.
+const DefaultImageServer = "https://cloud-images.ubuntu.com/releases"
+
+// InstanceSpec holds the information needed to create a new container.
+type InstanceSpec struct {
+	// Name is the name of the container.
+	Name string
+	// Series is the series of the container's image, which is used as
+	// the image's alias on the image server.
+	Series string
+	// ImageServer is the URL of the simplestreams server from which
+	// the image is fetched. DefaultImageServer is used if it is empty.
+	ImageServer string
+	// Profiles are the names of the profiles to apply to the
+	// container, in order.
+	Profiles []string
+	// UserData is the cloud-init user data with which the container
+	// is initialised.
+	UserData string
+	// Metadata holds additional information recorded on the container.
+	Metadata map[string]string
+}
+
+// Instance holds the information about an LXD container.
+type Instance struct {
+	// Name is the name of the container.
+	Name string
+	// Status is the container's current status.
+	Status string
+	// Architecture is the container's architecture, as reported by
+	// LXD (for example "x86_64").
+	Architecture string
+	// Profiles are the names of the profiles applied to the container.
+	Profiles []string
+	// Metadata holds the information recorded on the container.
+	Metadata map[string]string
+}
+
+type containerInfo struct {
+	Name         string            `json:"name"`
+	Status       string            `json:"status"`
+	Architecture string            `json:"architecture"`
+	Profiles     []string          `json:"profiles"`
+	Config       map[string]string `json:"config"`
+}
+
+func newInstance(info containerInfo) Instance {
+	metadata := make(map[string]string)
+	for key, value := range info.Config {
+		if key == UserDataKey || !strings.HasPrefix(key, metadataPrefix) {
+			continue
+		}
+		metadata[strings.TrimPrefix(key, metadataPrefix)] = value
+	}
+	return Instance{
+		Name:         info.Name,
+		Status:       info.Status,
+		Architecture: info.Architecture,
+		Profiles:     info.Profiles,
+		Metadata:     metadata,
+	}
+}
+
+func containerPath(name string) string {
+	return apiVersion + "/containers/" + url.QueryEscape(name)
+}
+
+// Instance returns the named container.
+func (c *Client) Instance(name string) (*Instance, error) {
+	var info containerInfo
+	if err := c.get(containerPath(name), &info); err != nil {
+		return nil, errors.Trace(err)
+	}
+	inst := newInstance(info)
+	return &inst, nil
+}
+
+// Instances returns the containers for which the name starts with the
+// provided prefix. The result is also limited to those containers with
+// one of the specified statuses (if any).
+func (c *Client) Instances(prefix string, statuses ...string) ([]Instance, error) {
+	var infos []containerInfo
+	if err := c.get(apiVersion+"/containers?recursion=1", &infos); err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+
+	var results []Instance
+	for _, info := range infos {
+		if !strings.HasPrefix(info.Name, prefix) {
+			continue
+		}
+		if !checkStatus(info.Status, statuses) {
+			continue
+		}
+		results = append(results, newInstance(info))
+	}
+	return results, nil
+}
+
+func checkStatus(status string, statuses []string) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+	for _, s := range statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// AddInstance creates a new container as described by the spec and
+// starts it. The call blocks until the container is started or the
+// request fails.
+func (c *Client) AddInstance(spec InstanceSpec) (*Instance, error) {
+	server := spec.ImageServer
+	if server == "" {
+		server = DefaultImageServer
+	}
+	config := map[string]string{
+		UserDataKey: spec.UserData,
+	}
+	for key, value := range spec.Metadata {
+		config[metadataPrefix+key] = value
+	}
+	req := map[string]interface{}{
+		"name":     spec.Name,
+		"profiles": spec.Profiles,
+		"config":   config,
+		"source": map[string]string{
+			"type":     "image",
+			"mode":     "pull",
+			"server":   server,
+			"protocol": "simplestreams",
+			"alias":    spec.Series,
+		},
+	}
+	if err := c.do("POST", apiVersion+"/containers", req); err != nil {
+		return nil, errors.Annotatef(err, "cannot create container %q", spec.Name)
+	}
+	if err := c.changeState(spec.Name, "start"); err != nil {
+		return nil, errors.Annotatef(err, "cannot start container %q", spec.Name)
+	}
+	return c.Instance(spec.Name)
+}
+
+func (c *Client) changeState(name, action string) error {
+	req := map[string]interface{}{
+		"action":  action,
+		"timeout": -1,
+		"force":   true,
+	}
+	return errors.Trace(c.do("PUT", containerPath(name)+"/state", req))
+}
+
+// RemoveInstances stops and deletes the named containers. Containers
+// that do not exist are ignored. The call blocks until all the
+// containers are removed or the request fails.
+func (c *Client) RemoveInstances(names ...string) error {
+	var failed []string
+	for _, name := range names {
+		if err := c.removeInstance(name); err != nil {
+			logger.Errorf("while removing container %q: %v", name, err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("some containers were not removed: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (c *Client) removeInstance(name string) error {
+	inst, err := c.Instance(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if inst.Status != StatusStopped {
+		if err := c.changeState(name, "stop"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	err = c.do("DELETE", containerPath(name), nil)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
+
+type containerState struct {
+	Network map[string]struct {
+		Addresses []struct {
+			Family  string `json:"family"`
+			Address string `json:"address"`
+			Scope   string `json:"scope"`
+		} `json:"addresses"`
+	} `json:"network"`
+}
+
+// Addresses returns the addresses of the named container's network
+// interfaces, excluding loopback and link-local addresses.
+func (c *Client) Addresses(name string) ([]network.Address, error) {
+	var state containerState
+	if err := c.get(containerPath(name)+"/state", &state); err != nil {
+		return nil, errors.Annotatef(err, "cannot get state of container %q", name)
+	}
+
+	var addresses []network.Address
+	for iface, info := range state.Network {
+		if iface == "lo" {
+			continue
+		}
+		for _, addr := range info.Addresses {
+			if addr.Scope == "link" || addr.Scope == "local" {
+				continue
+			}
+			addresses = append(addresses, network.NewAddress(addr.Address))
+		}
+	}
+	network.SortAddresses(addresses, false)
+	return addresses, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+)
+
+// Device holds the properties of a device, such as a network interface
+// or a disk, defined in a profile.
+type Device map[string]string
+
+// ProfileSpec holds the information needed to create a new profile.
+type ProfileSpec struct {
+	// Name is the name of the profile.
+	Name string
+	// Description describes the purpose of the profile.
+	Description string
+	// Config holds the container config set by the profile, for
+	// example "limits.cpu".
+	Config map[string]string
+	// Devices holds the devices defined by the profile, keyed by
+	// device name.
+	Devices map[string]Device
+}
+
+func profilePath(name string) string {
+	return apiVersion + "/profiles/" + url.QueryEscape(name)
+}
+
+// HasProfile reports whether the named profile exists.
+func (c *Client) HasProfile(name string) (bool, error) {
+	var profile map[string]interface{}
+	err := c.get(profilePath(name), &profile)
+	if errors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Annotatef(err, "cannot get profile %q", name)
+	}
+	return true, nil
+}
+
+// CreateProfile creates a new profile as described by the spec. If the
+// profile already exists then an error is returned.
+func (c *Client) CreateProfile(spec ProfileSpec) error {
+	config := spec.Config
+	if config == nil {
+		config = map[string]string{}
+	}
+	devices := spec.Devices
+	if devices == nil {
+		devices = map[string]Device{}
+	}
+	req := map[string]interface{}{
+		"name":        spec.Name,
+		"description": spec.Description,
+		"config":      config,
+		"devices":     devices,
+	}
+	if err := c.do("POST", apiVersion+"/profiles", req); err != nil {
+		return errors.Annotatef(err, "cannot create profile %q", spec.Name)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxdclient
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+
+	"github.com/juju/errors"
+)
+
+// httpsAddressKey is the server config key holding the address on
+// which LXD serves its API over HTTPS.
+const httpsAddressKey = "core.https_address"
+
+// ServerConfig holds the parts of the LXD server's configuration used
+// to reach it remotely.
+type ServerConfig struct {
+	// HTTPSAddress is the address on which the server serves its API
+	// over HTTPS. It is empty if the API is only served locally.
+	HTTPSAddress string
+
+	// Certificate is the PEM-encoded certificate of the server.
+	Certificate string
+}
+
+// serverConfigInfo holds the parts of the LXD server's description
+// used to build a ServerConfig.
+type serverConfigInfo struct {
+	Config      map[string]interface{} `json:"config"`
+	Environment struct {
+		Certificate string `json:"certificate"`
+	} `json:"environment"`
+}
+
+// ServerConfig returns the server's remote access configuration.
+func (c *Client) ServerConfig() (ServerConfig, error) {
+	var info serverConfigInfo
+	if err := c.get(apiVersion, &info); err != nil {
+		return ServerConfig{}, errors.Annotate(err, "cannot get LXD server info")
+	}
+	address, _ := info.Config[httpsAddressKey].(string)
+	return ServerConfig{
+		HTTPSAddress: address,
+		Certificate:  info.Environment.Certificate,
+	}, nil
+}
+
+// SetHTTPSAddress makes the server serve its API over HTTPS on the
+// given address. The rest of the server's config is left unchanged.
+func (c *Client) SetHTTPSAddress(address string) error {
+	var info serverConfigInfo
+	if err := c.get(apiVersion, &info); err != nil {
+		return errors.Annotate(err, "cannot get LXD server info")
+	}
+	config := info.Config
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	config[httpsAddressKey] = address
+	req := map[string]interface{}{"config": config}
+	if err := c.do("PUT", apiVersion, req); err != nil {
+		return errors.Annotatef(err, "cannot set LXD HTTPS address to %q", address)
+	}
+	return nil
+}
+
+// AddTrustedCert adds the PEM-encoded client certificate to the
+// server's trust store under the given name, so that clients presenting
+// it may use the API remotely.
+func (c *Client) AddTrustedCert(name, certPEM string) error {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("invalid client certificate")
+	}
+	req := map[string]interface{}{
+		"type":        "client",
+		"name":        name,
+		"certificate": base64.StdEncoding.EncodeToString(block.Bytes),
+	}
+	if err := c.do("POST", apiVersion+"/certificates", req); err != nil {
+		return errors.Annotatef(err, "cannot add certificate %q to LXD trust store", name)
+	}
+	return nil
+}
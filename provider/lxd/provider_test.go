@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/provider/lxd"
+	"github.com/juju/juju/testing"
+)
+
+type providerSuite struct {
+	lxd.BaseSuite
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) prepare(c *gc.C, attrs testing.Attrs) map[string]interface{} {
+	ctx := envtesting.BootstrapContext(c)
+	env, err := lxd.Provider.PrepareForBootstrap(ctx, s.NewConfig(c, attrs))
+	c.Assert(err, jc.ErrorIsNil)
+	return env.Config().AllAttrs()
+}
+
+func (s *providerSuite) TestPrepareForBootstrapLocal(c *gc.C) {
+	attrs := s.prepare(c, nil)
+
+	// The local daemon is made to serve HTTPS on the bridge, and the
+	// environment uses it as a remote so that the state server can
+	// reach it from its container.
+	c.Check(s.FakeLocalServer.HTTPSAddress, gc.Equals, "10.0.3.1:8443")
+	c.Check(attrs["remote-url"], gc.Equals, "https://10.0.3.1:8443")
+	c.Check(attrs["server-cert"], gc.Equals, "server-cert")
+	c.Assert(s.FakeLocalServer.TrustedCerts, gc.HasLen, 1)
+	c.Check(attrs["client-cert"], gc.Equals, s.FakeLocalServer.TrustedCerts[0])
+	c.Check(attrs["client-key"], gc.Not(gc.Equals), "")
+}
+
+func (s *providerSuite) TestPrepareForBootstrapLocalListening(c *gc.C) {
+	s.FakeLocalServer.Config.HTTPSAddress = "[::]:9443"
+	attrs := s.prepare(c, nil)
+
+	c.Check(s.FakeLocalServer.HTTPSAddress, gc.Equals, "")
+	c.Check(attrs["remote-url"], gc.Equals, "https://10.0.3.1:9443")
+}
+
+func (s *providerSuite) TestPrepareForBootstrapLocalUnreachable(c *gc.C) {
+	s.FakeLocalServer.Config.HTTPSAddress = "127.0.0.1:8443"
+	ctx := envtesting.BootstrapContext(c)
+	_, err := lxd.Provider.PrepareForBootstrap(ctx, s.NewConfig(c, nil))
+	c.Assert(err, gc.ErrorMatches, `cannot prepare local LXD: LXD serves HTTPS on "127.0.0.1:8443", which is not reachable on bridge "lxdbr0"`)
+}
+
+func (s *providerSuite) TestPrepareForBootstrapRemote(c *gc.C) {
+	attrs := s.prepare(c, testing.Attrs{
+		"remote-url":  "https://10.0.0.1:8443",
+		"client-cert": "cert",
+		"client-key":  "key",
+	})
+
+	c.Check(attrs["remote-url"], gc.Equals, "https://10.0.0.1:8443")
+	c.Check(s.FakeLocalServer.TrustedCerts, gc.HasLen, 0)
+}
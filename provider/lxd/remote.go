@@ -0,0 +1,120 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cert"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+)
+
+// defaultHTTPSPort is the port on which the local LXD daemon is made
+// to serve its API if it does not already do so.
+const defaultHTTPSPort = 8443
+
+// localServer is the part of the local LXD daemon's API used to make
+// it reachable from the state server.
+type localServer interface {
+	ServerConfig() (lxdclient.ServerConfig, error)
+	SetHTTPSAddress(address string) error
+	AddTrustedCert(name, certPEM string) error
+}
+
+var newLocalServer = func() (localServer, error) {
+	return lxdclient.Connect(lxdclient.Config{})
+}
+
+// bridgeAddress returns the IPv4 address of the named network bridge
+// on the host.
+var bridgeAddress = func(name string) (string, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "", errors.NotFoundf("IPv4 address on %q", name)
+}
+
+// prepareLocalRemote makes the local LXD daemon reachable over HTTPS
+// from the containers on its network bridge, and returns the config
+// updated to use it as a remote. The local unix socket is not
+// available inside the state server's container, so an environment
+// using the local daemon could not be opened there after bootstrap.
+func prepareLocalRemote(ecfg *environConfig) (*config.Config, error) {
+	server, err := newLocalServer()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot connect to local LXD")
+	}
+	bridgeAddr, err := bridgeAddress(ecfg.networkBridge())
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get address of bridge %q", ecfg.networkBridge())
+	}
+	serverConfig, err := server.ServerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	port := defaultHTTPSPort
+	if serverConfig.HTTPSAddress == "" {
+		address := net.JoinHostPort(bridgeAddr, strconv.Itoa(port))
+		if err := server.SetHTTPSAddress(address); err != nil {
+			return nil, errors.Trace(err)
+		}
+	} else {
+		host, portString, err := net.SplitHostPort(serverConfig.HTTPSAddress)
+		if err != nil {
+			// LXD listens on its default port if none is given.
+			host, portString = serverConfig.HTTPSAddress, strconv.Itoa(port)
+		}
+		if port, err = strconv.Atoi(portString); err != nil {
+			return nil, errors.Errorf("invalid LXD HTTPS address %q", serverConfig.HTTPSAddress)
+		}
+		// An empty or unspecified host means that LXD listens on
+		// every address, including the bridge's.
+		listensOnAll := host == "" || net.ParseIP(host).IsUnspecified()
+		if !listensOnAll && host != bridgeAddr {
+			return nil, errors.Errorf(
+				"LXD serves HTTPS on %q, which is not reachable on bridge %q",
+				serverConfig.HTTPSAddress, ecfg.networkBridge(),
+			)
+		}
+	}
+
+	expiry := time.Now().UTC().AddDate(10, 0, 0)
+	caCert, caKey, err := cert.NewCA(ecfg.Name(), expiry)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate client certificate")
+	}
+	clientCert, clientKey, err := cert.NewClient(caCert, caKey, expiry)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot generate client certificate")
+	}
+	if err := server.AddTrustedCert("juju-"+ecfg.Name(), clientCert); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cfg, err := ecfg.Config.Apply(map[string]interface{}{
+		cfgRemoteURL:  "https://" + net.JoinHostPort(bridgeAddr, strconv.Itoa(port)),
+		cfgClientCert: clientCert,
+		cfgClientKey:  clientKey,
+		cfgServerCert: serverConfig.Certificate,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cfg, nil
+}
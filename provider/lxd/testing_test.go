@@ -0,0 +1,265 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/lxd/lxdclient"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+// These are fake config values for use in tests.
+var (
+	ConfigAttrs = testing.FakeConfig().Merge(testing.Attrs{
+		"type": "lxd",
+		"uuid": "2d02eeac-9dbb-11e4-89d3-123b93f75cba",
+	})
+)
+
+var _ environs.Environ = (*environ)(nil)
+var _ instance.Instance = (*environInstance)(nil)
+var _ lxdConnection = (*lxdclient.Client)(nil)
+
+type BaseSuite struct {
+	gitjujutesting.IsolationSuite
+
+	Config    *config.Config
+	EnvConfig *environConfig
+	Env       *environ
+	Prefix    string
+
+	RawInstance   *lxdclient.Instance
+	Instance      *environInstance
+	StartInstArgs environs.StartInstanceParams
+
+	FakeConn        *fakeConn
+	FakeLocalServer *FakeLocalServer
+}
+
+func (s *BaseSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.FakeConn = &fakeConn{
+		Arches: []string{"x86_64", "i686"},
+	}
+	s.PatchValue(&newConnection, func(*environConfig) (lxdConnection, error) {
+		return s.FakeConn, nil
+	})
+	s.FakeLocalServer = &FakeLocalServer{
+		Config: lxdclient.ServerConfig{Certificate: "server-cert"},
+	}
+	s.PatchValue(&newLocalServer, func() (localServer, error) {
+		return s.FakeLocalServer, nil
+	})
+	s.PatchValue(&bridgeAddress, func(string) (string, error) {
+		return "10.0.3.1", nil
+	})
+
+	s.Env = &environ{
+		name: "lxd",
+		raw:  s.FakeConn,
+	}
+	s.setConfig(c, s.NewConfig(c, nil))
+	s.initInst(c)
+}
+
+func (s *BaseSuite) initInst(c *gc.C) {
+	tools := []*tools.Tools{{
+		Version: version.Binary{Arch: arch.AMD64, Series: "trusty"},
+		URL:     "https://example.org",
+	}}
+
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(constraints.Value{}, "trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Tools = tools[0]
+	instanceConfig.AuthorizedKeys = s.Config.AuthorizedKeys()
+
+	s.StartInstArgs = environs.StartInstanceParams{
+		InstanceConfig: instanceConfig,
+		Tools:          tools,
+	}
+
+	s.RawInstance = &lxdclient.Instance{
+		Name:         s.Prefix + "machine-spam",
+		Status:       lxdclient.StatusRunning,
+		Architecture: "x86_64",
+		Metadata: map[string]string{
+			metadataKeyIsState: metadataValueTrue,
+		},
+	}
+	s.Instance = newInstance(s.RawInstance, s.Env)
+}
+
+func (s *BaseSuite) setConfig(c *gc.C, cfg *config.Config) {
+	s.Config = cfg
+	ecfg, err := newValidConfig(cfg, configDefaults)
+	c.Assert(err, jc.ErrorIsNil)
+	s.EnvConfig = ecfg
+	uuid, _ := cfg.UUID()
+	s.Env.uuid = uuid
+	s.Env.ecfg = s.EnvConfig
+	s.Prefix = "juju-" + uuid + "-"
+}
+
+func (s *BaseSuite) NewConfig(c *gc.C, updates testing.Attrs) *config.Config {
+	var err error
+	cfg := testing.EnvironConfig(c)
+	cfg, err = cfg.Apply(ConfigAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = cfg.Apply(updates)
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func (s *BaseSuite) UpdateConfig(c *gc.C, attrs map[string]interface{}) {
+	cfg, err := s.Config.Apply(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setConfig(c, cfg)
+}
+
+type fakeConnCall struct {
+	FuncName string
+
+	Name         string
+	Names        []string
+	Prefix       string
+	Statuses     []string
+	InstanceSpec lxdclient.InstanceSpec
+	ProfileSpec  lxdclient.ProfileSpec
+}
+
+type fakeConn struct {
+	Calls []fakeConnCall
+
+	Arches     []string
+	Inst       *lxdclient.Instance
+	Insts      []lxdclient.Instance
+	Addrs      []network.Address
+	Profiles   map[string]bool
+	Err        error
+	FailOnCall int
+}
+
+func (fc *fakeConn) err() error {
+	if len(fc.Calls) != fc.FailOnCall+1 {
+		return nil
+	}
+	return fc.Err
+}
+
+func (fc *fakeConn) Architectures() ([]string, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Architectures",
+	})
+	return fc.Arches, fc.err()
+}
+
+func (fc *fakeConn) Instance(name string) (*lxdclient.Instance, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Instance",
+		Name:     name,
+	})
+	return fc.Inst, fc.err()
+}
+
+func (fc *fakeConn) Instances(prefix string, statuses ...string) ([]lxdclient.Instance, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Instances",
+		Prefix:   prefix,
+		Statuses: statuses,
+	})
+	return fc.Insts, fc.err()
+}
+
+func (fc *fakeConn) AddInstance(spec lxdclient.InstanceSpec) (*lxdclient.Instance, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:     "AddInstance",
+		InstanceSpec: spec,
+	})
+	return fc.Inst, fc.err()
+}
+
+func (fc *fakeConn) RemoveInstances(names ...string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveInstances",
+		Names:    names,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) Addresses(name string) ([]network.Address, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Addresses",
+		Name:     name,
+	})
+	return fc.Addrs, fc.err()
+}
+
+func (fc *fakeConn) HasProfile(name string) (bool, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "HasProfile",
+		Name:     name,
+	})
+	return fc.Profiles[name], fc.err()
+}
+
+func (fc *fakeConn) CreateProfile(spec lxdclient.ProfileSpec) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:    "CreateProfile",
+		ProfileSpec: spec,
+	})
+	if err := fc.err(); err != nil {
+		return err
+	}
+	if fc.Profiles == nil {
+		fc.Profiles = make(map[string]bool)
+	}
+	fc.Profiles[spec.Name] = true
+	return nil
+}
+
+func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
+	var calls []fakeConnCall
+	called := false
+	for _, call := range fc.Calls {
+		if call.FuncName == funcName {
+			called = true
+			calls = append(calls, call)
+		}
+	}
+	return called, calls
+}
+
+// FakeLocalServer stands in for the local LXD daemon during bootstrap.
+type FakeLocalServer struct {
+	Config       lxdclient.ServerConfig
+	HTTPSAddress string
+	TrustedCerts []string
+}
+
+func (s *FakeLocalServer) ServerConfig() (lxdclient.ServerConfig, error) {
+	return s.Config, nil
+}
+
+func (s *FakeLocalServer) SetHTTPSAddress(address string) error {
+	s.HTTPSAddress = address
+	return nil
+}
+
+func (s *FakeLocalServer) AddTrustedCert(name, certPEM string) error {
+	s.TrustedCerts = append(s.TrustedCerts, certPEM)
+	return nil
+}
@@ -23,6 +23,15 @@ var configFields = schema.Fields{
 	// acquired from MAAS, to support multiple environments per MAAS user.
 	"maas-agent-name": schema.String(),
 }
+
+var configSchema = config.Schema{
+	"maas-oauth": {
+		Description: "The OAuth credentials used to connect to the MAAS API",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configDefaults = schema.Defaults{
 	// For backward-compatibility, maas-agent-name is the empty string
 	// by default. However, new environments should all use a UUID.
@@ -5,6 +5,7 @@ package maas
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -14,6 +15,7 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, maasEnvironProvider{})
+	config.RegisterProviderSchema(providerType, configSchema)
 
 	//Register the MAAS specific storage providers.
 	registry.RegisterProvider(maasStorageProviderType, &maasStorageProvider{})
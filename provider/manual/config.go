@@ -28,6 +28,13 @@ var (
 		"storage-port":      defaultStoragePort,
 		"use-sshstorage":    true,
 	}
+	configSchema = config.Schema{
+		"storage-auth-key": {
+			Description: "The key used to authenticate with the bootstrap machine's storage",
+			Type:        config.Tstring,
+			Secret:      true,
+		},
+	}
 )
 
 type environConfig struct {
@@ -5,6 +5,7 @@ package manual
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -15,6 +16,7 @@ const (
 func init() {
 	p := manualProvider{}
 	environs.RegisterProvider(providerType, p, "null")
+	config.RegisterProviderSchema(providerType, configSchema)
 
 	registry.RegisterEnvironStorageProviders(providerType)
 }
@@ -27,6 +27,25 @@ var configFields = schema.Fields{
 	"use-default-secgroup": schema.Bool(),
 	"network":              schema.String(),
 }
+
+var configSchema = config.Schema{
+	"password": {
+		Description: "The OpenStack password",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	"access-key": {
+		Description: "The OpenStack access key, used with keypair authentication",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+	"secret-key": {
+		Description: "The OpenStack secret key, used with keypair authentication",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configDefaults = schema.Defaults{
 	"username":             "",
 	"password":             "",
@@ -315,6 +315,10 @@ func EnsureGroup(e environs.Environ, name string, rules []nova.RuleInfo) (nova.S
 	return e.(*environ).ensureGroup(name, rules)
 }
 
+func OpenPortsInGroup(e environs.Environ, name string, ports []network.PortRange) error {
+	return e.(*environ).openPortsInGroup(name, ports)
+}
+
 // ImageMetadataStorage returns a Storage object pointing where the goose
 // infrastructure sets up its keystone entry for image metadata
 func ImageMetadataStorage(e environs.Environ) envstorage.Storage {
@@ -4,6 +4,7 @@
 package openstack
 
 import (
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
@@ -14,24 +15,10 @@ import (
 // The instance type comes from querying the flavors supported by the deployment.
 func findInstanceSpec(e *environ, ic *instances.InstanceConstraint) (*instances.InstanceSpec, error) {
 	// first construct all available instance types from the supported flavors.
-	nova := e.nova()
-	flavors, err := nova.ListFlavorsDetail()
+	allInstanceTypes, err := flavorInstanceTypes(e, ic.Arches)
 	if err != nil {
 		return nil, err
 	}
-	allInstanceTypes := []instances.InstanceType{}
-	for _, flavor := range flavors {
-		instanceType := instances.InstanceType{
-			Id:       flavor.Id,
-			Name:     flavor.Name,
-			Arches:   ic.Arches,
-			Mem:      uint64(flavor.RAM),
-			CpuCores: uint64(flavor.VCPUs),
-			RootDisk: uint64(flavor.Disk * 1024),
-			// tags not currently supported on openstack
-		}
-		allInstanceTypes = append(allInstanceTypes, instanceType)
-	}
 
 	imageConstraint := imagemetadata.NewImageConstraint(simplestreams.LookupParams{
 		CloudSpec: simplestreams.CloudSpec{ic.Region, e.ecfg().authURL()},
@@ -55,3 +42,41 @@ func findInstanceSpec(e *environ, ic *instances.InstanceConstraint) (*instances.
 	}
 	return spec, nil
 }
+
+// flavorInstanceTypes returns an instance type for each of the flavors
+// supported by the deployment, each able to run the given arches.
+func flavorInstanceTypes(e *environ, arches []string) ([]instances.InstanceType, error) {
+	nova := e.nova()
+	flavors, err := nova.ListFlavorsDetail()
+	if err != nil {
+		return nil, err
+	}
+	allInstanceTypes := []instances.InstanceType{}
+	for _, flavor := range flavors {
+		instanceType := instances.InstanceType{
+			Id:       flavor.Id,
+			Name:     flavor.Name,
+			Arches:   arches,
+			Mem:      uint64(flavor.RAM),
+			CpuCores: uint64(flavor.VCPUs),
+			RootDisk: uint64(flavor.Disk * 1024),
+			// tags not currently supported on openstack
+		}
+		allInstanceTypes = append(allInstanceTypes, instanceType)
+	}
+	return allInstanceTypes, nil
+}
+
+// normalizeConstraints returns the flavor that would be chosen to
+// satisfy cons.
+func normalizeConstraints(e *environ, cons constraints.Value) (instances.Normalization, error) {
+	arches, err := e.SupportedArchitectures()
+	if err != nil {
+		return instances.Normalization{}, err
+	}
+	allInstanceTypes, err := flavorInstanceTypes(e, arches)
+	if err != nil {
+		return instances.Normalization{}, err
+	}
+	return instances.Normalize(allInstanceTypes, e.ecfg().region(), cons)
+}
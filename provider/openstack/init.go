@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/tools"
 	"github.com/juju/juju/storage/provider/registry"
 )
@@ -15,6 +16,7 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, environProvider{})
+	config.RegisterProviderSchema(providerType, configSchema)
 	environs.RegisterImageDataSourceFunc("keystone catalog", getKeystoneImageSource)
 	tools.RegisterToolsDataSourceFunc("keystone catalog", getKeystoneToolsSource)
 
@@ -874,6 +874,16 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	c.Assert(unsupported, jc.SameContents, []string{"cpu-power"})
 }
 
+func (s *localServerSuite) TestNormalizeConstraints(c *gc.C) {
+	env := s.Open(c)
+	normalizer, ok := environs.SupportsConstraintsNormalization(env)
+	c.Assert(ok, jc.IsTrue)
+	normalized, err := normalizer.NormalizeConstraints(constraints.MustParse("mem=1G"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(normalized.InstanceType, gc.Equals, "m1.small")
+	c.Check(*normalized.Hardware.Mem, gc.Equals, uint64(2048))
+}
+
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
 	env := s.Open(c)
 	validator, err := env.ConstraintsValidator()
@@ -1057,6 +1067,29 @@ func (s *localServerSuite) TestEnsureGroup(c *gc.C) {
 	assertRule(group)
 }
 
+func (s *localServerSuite) TestOpenPortsInGroupOnlyAddsMissingRules(c *gc.C) {
+	env := s.Prepare(c)
+	_, err := openstack.EnsureGroup(env, "test group", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ports := []network.PortRange{{FromPort: 80, ToPort: 80, Protocol: "tcp"}}
+	err = openstack.OpenPortsInGroup(env, "test group", ports)
+	c.Assert(err, jc.ErrorIsNil)
+	// Opening an already open port range does not add another rule.
+	ports = append(ports, network.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"})
+	err = openstack.OpenPortsInGroup(env, "test group", ports)
+	c.Assert(err, jc.ErrorIsNil)
+
+	group, err := openstack.GetNovaClient(env).SecurityGroupByName("test group")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(group.Rules, gc.HasLen, 2)
+	var opened []int
+	for _, rule := range group.Rules {
+		opened = append(opened, *rule.FromPort)
+	}
+	c.Assert(opened, jc.SameContents, []int{80, 443})
+}
+
 // localHTTPSServerSuite contains tests that run against an Openstack service
 // double connected on an HTTPS port with a self-signed certificate. This
 // service is set up and torn down for every test.  This should only test
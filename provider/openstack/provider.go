@@ -363,6 +363,7 @@ type environ struct {
 }
 
 var _ environs.Environ = (*environ)(nil)
+var _ environs.ConstraintsNormalizer = (*environ)(nil)
 var _ simplestreams.HasRegion = (*environ)(nil)
 var _ state.Prechecker = (*environ)(nil)
 var _ state.InstanceDistributor = (*environ)(nil)
@@ -577,6 +578,12 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 }
 
+// NormalizeConstraints is specified in the environs.ConstraintsNormalizer
+// interface.
+func (e *environ) NormalizeConstraints(cons constraints.Value) (instances.Normalization, error) {
+	return normalizeConstraints(e, cons)
+}
+
 // ConstraintsValidator is defined on the Environs interface.
 func (e *environ) ConstraintsValidator() (constraints.Validator, error) {
 	validator := constraints.NewValidator()
@@ -1350,23 +1357,43 @@ func portsToRuleInfo(groupId string, ports []network.PortRange) []nova.RuleInfo
 	return rules
 }
 
+// openPortsInGroup adds rules to the named security group for those
+// of the given port ranges that it does not already open, so that
+// only the changed rules are sent to the provider.
 func (e *environ) openPortsInGroup(name string, portRanges []network.PortRange) error {
 	novaclient := e.nova()
 	group, err := novaclient.SecurityGroupByName(name)
 	if err != nil {
 		return err
 	}
-	rules := portsToRuleInfo(group.Id, portRanges)
+	var missing []network.PortRange
+	for _, portRange := range portRanges {
+		if !groupOpensPortRange(group, portRange) {
+			missing = append(missing, portRange)
+		}
+	}
+	rules := portsToRuleInfo(group.Id, missing)
 	for _, rule := range rules {
 		_, err := novaclient.CreateSecurityGroupRule(rule)
-		if err != nil {
-			// TODO: if err is not rule already exists, raise?
-			logger.Debugf("error creating security group rule: %v", err.Error())
+		if err != nil && !gooseerrors.IsDuplicateValue(err) {
+			return errors.Annotatef(err, "cannot open ports %d-%d/%s in group %q",
+				rule.FromPort, rule.ToPort, rule.IPProtocol, name)
 		}
 	}
 	return nil
 }
 
+// groupOpensPortRange reports whether the security group already has
+// a rule opening the port range to all addresses.
+func groupOpensPortRange(group *nova.SecurityGroup, portRange network.PortRange) bool {
+	for _, rule := range group.Rules {
+		if ruleMatchesPortRange(rule, portRange) && rule.IPRange["cidr"] == "0.0.0.0/0" {
+			return true
+		}
+	}
+	return false
+}
+
 // ruleMatchesPortRange checks if supplied nova security group rule matches the port range
 func ruleMatchesPortRange(rule nova.SecurityGroupRule, portRange network.PortRange) bool {
 	if rule.IPProtocol == nil || rule.FromPort == nil || rule.ToPort == nil {
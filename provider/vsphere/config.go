@@ -70,6 +70,14 @@ var configSecretFields = []string{
 	cfgPassword,
 }
 
+var configSchema = config.Schema{
+	cfgPassword: {
+		Description: "The password of the vSphere user",
+		Type:        config.Tstring,
+		Secret:      true,
+	},
+}
+
 var configImmutableFields = []string{
 	cfgHost,
 	cfgDatacenter,
@@ -7,6 +7,7 @@ package vsphere
 
 import (
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage/provider/registry"
 )
 
@@ -16,5 +17,6 @@ const (
 
 func init() {
 	environs.RegisterProvider(providerType, providerInstance)
+	config.RegisterProviderSchema(providerType, configSchema)
 	registry.RegisterEnvironStorageProviders(providerType)
 }
@@ -0,0 +1,169 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package pubsub provides a hub through which the components of a
+// state server publish events to each other, so that workers and API
+// facades running in the same agent learn of changes as they happen,
+// without each of them watching the database for them.
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/juju/loggo"
+)
+
+var logger = loggo.GetLogger("juju.pubsub")
+
+// Topic identifies a kind of event published on a hub.
+type Topic string
+
+// Handler is called with the topic and data of each event published
+// on a topic it is subscribed to.
+type Handler func(topic Topic, data interface{})
+
+// Hub delivers the events published on each topic to the handlers
+// subscribed to that topic. Each subscriber receives events in the
+// order they were published, and a slow subscriber does not delay
+// either publishers or other subscribers.
+type Hub struct {
+	mu          sync.Mutex
+	nextId      int
+	subscribers map[Topic]map[int]*subscriber
+}
+
+// NewHub returns a new Hub with no subscribers.
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[Topic]map[int]*subscriber),
+	}
+}
+
+// Publish delivers data to every handler subscribed to the topic. It
+// returns a channel that is closed once all those handlers have been
+// called, or have been unsubscribed.
+func (h *Hub) Publish(topic Topic, data interface{}) <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, sub := range h.subscribers[topic] {
+		wg.Add(1)
+		sub.deliver(&event{topic: topic, data: data, done: wg.Done})
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// Subscribe arranges for the handler to be called with each event
+// subsequently published on the topic, until the returned function
+// is called to unsubscribe it. Calls to the handler are made in a
+// goroutine of its own, one at a time.
+func (h *Hub) Subscribe(topic Topic, handler Handler) (unsubscribe func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.nextId
+	h.nextId++
+	sub := newSubscriber(handler)
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[int]*subscriber)
+	}
+	h.subscribers[topic][id] = sub
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[topic], id)
+			if len(h.subscribers[topic]) == 0 {
+				delete(h.subscribers, topic)
+			}
+			h.mu.Unlock()
+			sub.close()
+		})
+	}
+}
+
+// event holds an event waiting to be delivered to a subscriber.
+type event struct {
+	topic Topic
+	data  interface{}
+	done  func()
+}
+
+// subscriber queues the events for a handler and calls the handler
+// with each of them in turn.
+type subscriber struct {
+	handler Handler
+
+	mu      sync.Mutex
+	pending []*event
+	closed  bool
+	wake    chan struct{}
+}
+
+func newSubscriber(handler Handler) *subscriber {
+	sub := &subscriber{
+		handler: handler,
+		wake:    make(chan struct{}, 1),
+	}
+	go sub.loop()
+	return sub
+}
+
+func (s *subscriber) deliver(e *event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		e.done()
+		return
+	}
+	s.pending = append(s.pending, e)
+	s.signal()
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.signal()
+}
+
+func (s *subscriber) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) loop() {
+	for {
+		<-s.wake
+		s.mu.Lock()
+		pending, closed := s.pending, s.closed
+		s.pending = nil
+		s.mu.Unlock()
+		for _, e := range pending {
+			if !closed {
+				s.call(e)
+			}
+			e.done()
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+func (s *subscriber) call(e *event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("handler for %q panicked: %v", e.topic, r)
+		}
+	}()
+	s.handler(e.topic, e.data)
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package pubsub_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/pubsub"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type hubSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&hubSuite{})
+
+func waitDone(c *gc.C, done <-chan struct{}) {
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for event delivery")
+	}
+}
+
+func (s *hubSuite) TestPublishNoSubscribers(c *gc.C) {
+	hub := pubsub.NewHub()
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, nil))
+}
+
+func (s *hubSuite) TestSubscribersReceiveEventsInOrder(c *gc.C) {
+	hub := pubsub.NewHub()
+	var first, second []interface{}
+	unsub1 := hub.Subscribe(pubsub.MachineRemovedTopic, func(topic pubsub.Topic, data interface{}) {
+		c.Check(topic, gc.Equals, pubsub.MachineRemovedTopic)
+		first = append(first, data)
+	})
+	defer unsub1()
+	unsub2 := hub.Subscribe(pubsub.MachineRemovedTopic, func(topic pubsub.Topic, data interface{}) {
+		second = append(second, data)
+	})
+	defer unsub2()
+	hub.Subscribe(pubsub.EnvironmentAddedTopic, func(pubsub.Topic, interface{}) {
+		c.Errorf("unexpected event")
+	})
+
+	hub.Publish(pubsub.MachineRemovedTopic, pubsub.MachineRemoved{MachineId: "1"})
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, pubsub.MachineRemoved{MachineId: "2"}))
+	expected := []interface{}{
+		pubsub.MachineRemoved{MachineId: "1"},
+		pubsub.MachineRemoved{MachineId: "2"},
+	}
+	c.Assert(first, jc.DeepEquals, expected)
+	c.Assert(second, jc.DeepEquals, expected)
+}
+
+func (s *hubSuite) TestSlowSubscriberDoesNotBlock(c *gc.C) {
+	hub := pubsub.NewHub()
+	release := make(chan struct{})
+	defer close(release)
+	hub.Subscribe(pubsub.EnvironmentAddedTopic, func(pubsub.Topic, interface{}) {
+		<-release
+	})
+	received := make(chan interface{}, 2)
+	hub.Subscribe(pubsub.EnvironmentAddedTopic, func(_ pubsub.Topic, data interface{}) {
+		received <- data
+	})
+
+	done1 := hub.Publish(pubsub.EnvironmentAddedTopic, "one")
+	done2 := hub.Publish(pubsub.EnvironmentAddedTopic, "two")
+	for _, expected := range []string{"one", "two"} {
+		select {
+		case data := <-received:
+			c.Assert(data, gc.Equals, expected)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for %q", expected)
+		}
+	}
+	select {
+	case <-done1:
+		c.Fatalf("publish completed before slow subscriber handled it")
+	case <-done2:
+		c.Fatalf("publish completed before slow subscriber handled it")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *hubSuite) TestUnsubscribe(c *gc.C) {
+	hub := pubsub.NewHub()
+	called := 0
+	unsub := hub.Subscribe(pubsub.MachineRemovedTopic, func(pubsub.Topic, interface{}) {
+		called++
+	})
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, nil))
+	unsub()
+	unsub()
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, nil))
+	c.Assert(called, gc.Equals, 1)
+}
+
+func (s *hubSuite) TestUnsubscribeCompletesPendingPublish(c *gc.C) {
+	hub := pubsub.NewHub()
+	release := make(chan struct{})
+	unsub := hub.Subscribe(pubsub.MachineRemovedTopic, func(pubsub.Topic, interface{}) {
+		<-release
+	})
+	hub.Publish(pubsub.MachineRemovedTopic, "first")
+	done := hub.Publish(pubsub.MachineRemovedTopic, "second")
+	unsub()
+	close(release)
+	waitDone(c, done)
+}
+
+func (s *hubSuite) TestHandlerPanicIsContained(c *gc.C) {
+	hub := pubsub.NewHub()
+	calls := 0
+	hub.Subscribe(pubsub.MachineRemovedTopic, func(pubsub.Topic, interface{}) {
+		calls++
+		panic("blam")
+	})
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, nil))
+	waitDone(c, hub.Publish(pubsub.MachineRemovedTopic, nil))
+	c.Assert(calls, gc.Equals, 2)
+}
@@ -0,0 +1,28 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package pubsub
+
+// The topics of the events published by state server components.
+const (
+	// EnvironmentAddedTopic is published when an environment is
+	// created. Its data is an EnvironmentAdded.
+	EnvironmentAddedTopic Topic = "environment.added"
+
+	// MachineRemovedTopic is published when a machine is removed
+	// from state. Its data is a MachineRemoved.
+	MachineRemovedTopic Topic = "machine.removed"
+)
+
+// EnvironmentAdded describes an environment that has been created.
+type EnvironmentAdded struct {
+	UUID  string
+	Name  string
+	Owner string
+}
+
+// MachineRemoved describes a machine that has been removed.
+type MachineRemoved struct {
+	EnvironUUID string
+	MachineId   string
+}
@@ -6,6 +6,8 @@ package rpc
 import (
 	"errors"
 	"strings"
+
+	"golang.org/x/net/context"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -17,12 +19,17 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// reqId holds the id the request was sent with, so
+	// that the call can be cancelled.
+	reqId uint64
 }
 
 // RequestError represents an error returned from an RPC request.
 type RequestError struct {
 	Message string
 	Code    string
+	Info    map[string]interface{}
 }
 
 func (e *RequestError) Error() string {
@@ -37,6 +44,10 @@ func (e *RequestError) ErrorCode() string {
 	return e.Code
 }
 
+func (e *RequestError) ErrorInfo() map[string]interface{} {
+	return e.Info
+}
+
 func (conn *Conn) send(call *Call) {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
@@ -54,6 +65,7 @@ func (conn *Conn) send(call *Call) {
 	}
 	conn.reqId++
 	reqId := conn.reqId
+	call.reqId = reqId
 	conn.clientPending[reqId] = call
 	conn.mutex.Unlock()
 
@@ -111,6 +123,7 @@ func (conn *Conn) handleResponse(hdr *Header) error {
 		call.Error = &RequestError{
 			Message: hdr.Error,
 			Code:    hdr.ErrorCode,
+			Info:    hdr.ErrorInfo,
 		}
 		err = conn.readBody(nil, false)
 		if conn.notifier != nil {
@@ -149,6 +162,57 @@ func (conn *Conn) Call(req Request, params, response interface{}) error {
 	return call.Error
 }
 
+// CallContext is like Call, but abandons the call if ctx is done before
+// the response arrives, asking the other side to cancel the request and
+// returning ctx.Err().
+func (conn *Conn) CallContext(ctx context.Context, req Request, params, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	call := conn.Go(req, params, response, make(chan *Call, 1))
+	select {
+	case call = <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+	}
+	if !conn.cancel(call) {
+		// The response is already being read into the
+		// call, so wait for it rather than leave it to be
+		// written behind the caller's back.
+		call = <-call.Done
+		return call.Error
+	}
+	return ctx.Err()
+}
+
+// cancel abandons the given call and sends a cancel request for it to
+// the other side, which will stop serving the request if it can. It
+// returns false if the call is no longer pending.
+func (conn *Conn) cancel(call *Call) bool {
+	conn.mutex.Lock()
+	pending := conn.clientPending[call.reqId] == call
+	if pending {
+		delete(conn.clientPending, call.reqId)
+	}
+	conn.mutex.Unlock()
+	if !pending {
+		return false
+	}
+	conn.sending.Lock()
+	defer conn.sending.Unlock()
+	hdr := &Header{
+		RequestId: call.reqId,
+		Request:   Request{Action: cancelAction},
+	}
+	if conn.notifier != nil {
+		conn.notifier.ClientRequest(hdr, struct{}{})
+	}
+	if err := conn.codec.WriteMessage(hdr, struct{}{}); err != nil {
+		logger.Debugf("cannot cancel request %d: %v", call.reqId, err)
+	}
+	return true
+}
+
 // Go invokes the request asynchronously.  It returns the Call structure representing
 // the invocation.  The done channel will signal when the call is complete by returning
 // the same Call object.  If done is nil, Go will allocate a new channel.
@@ -70,20 +70,22 @@ type inMsg struct {
 	Params    json.RawMessage
 	Error     string
 	ErrorCode string
+	ErrorInfo map[string]interface{}
 	Response  json.RawMessage
 }
 
 // outMsg holds an outgoing message.
 type outMsg struct {
 	RequestId uint64
-	Type      string      `json:",omitempty"`
-	Version   int         `json:",omitempty"`
-	Id        string      `json:",omitempty"`
-	Request   string      `json:",omitempty"`
-	Params    interface{} `json:",omitempty"`
-	Error     string      `json:",omitempty"`
-	ErrorCode string      `json:",omitempty"`
-	Response  interface{} `json:",omitempty"`
+	Type      string                 `json:",omitempty"`
+	Version   int                    `json:",omitempty"`
+	Id        string                 `json:",omitempty"`
+	Request   string                 `json:",omitempty"`
+	Params    interface{}            `json:",omitempty"`
+	Error     string                 `json:",omitempty"`
+	ErrorCode string                 `json:",omitempty"`
+	ErrorInfo map[string]interface{} `json:",omitempty"`
+	Response  interface{}            `json:",omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -131,6 +133,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.ErrorInfo = c.msg.ErrorInfo
 	return nil
 }
 
@@ -191,6 +194,7 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.ErrorInfo = hdr.ErrorInfo
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
@@ -4,12 +4,17 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"net/http/httptest"
 	"reflect"
 	"regexp"
+	"strings"
+	"sync"
 	stdtesting "testing"
+	"time"
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/websocket"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/rpc"
@@ -54,6 +59,15 @@ var readTests = []struct {
 		ErrorCode: "a code",
 	},
 	expectBody: new(map[string]interface{}),
+}, {
+	msg: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "ErrorInfo": {"limit": 3}}`,
+	expectHdr: rpc.Header{
+		RequestId: 2,
+		Error:     "an error",
+		ErrorCode: "a code",
+		ErrorInfo: map[string]interface{}{"limit": 3.0},
+	},
+	expectBody: new(map[string]interface{}),
 }, {
 	msg: `{"RequestId": 3, "Response": {"X": "result"}}`,
 	expectHdr: rpc.Header{
@@ -237,6 +251,14 @@ var writeTests = []struct {
 		ErrorCode: "a code",
 	},
 	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code"}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 2,
+		Error:     "an error",
+		ErrorCode: "a code",
+		ErrorInfo: map[string]interface{}{"limit": 3},
+	},
+	expect: `{"RequestId": 2, "Error": "an error", "ErrorCode": "a code", "ErrorInfo": {"limit": 3}}`,
 }, {
 	hdr: &rpc.Header{
 		RequestId: 3,
@@ -331,6 +353,82 @@ func (*suite) TestDumpRequest(c *gc.C) {
 	}
 }
 
+func (*suite) TestWebsocketCounted(c *gc.C) {
+	const reply = `{"RequestId":1,"Response":{"X":"result"}}`
+	received := make(chan string, 1)
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			c.Errorf("cannot receive message: %v", err)
+			return
+		}
+		received <- msg
+		if err := websocket.Message.Send(ws, reply); err != nil {
+			c.Errorf("cannot send message: %v", err)
+		}
+	}))
+	defer srv.Close()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	c.Assert(err, jc.ErrorIsNil)
+
+	counter := &testCounter{}
+	codec := jsoncodec.NewWebsocketCounted(ws, counter)
+	defer codec.Close()
+	err = codec.WriteMessage(&rpc.Header{
+		RequestId: 1,
+		Request:   rpc.Request{Type: "Foo", Action: "Bar"},
+	}, &value{X: "param"})
+	c.Assert(err, jc.ErrorIsNil)
+	var msg string
+	select {
+	case msg = <-received:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for message")
+	}
+	assertJSONEqual(c, msg, `{"RequestId":1,"Type":"Foo","Request":"Bar","Params":{"X":"param"}}`)
+
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr.RequestId, gc.Equals, uint64(1))
+	var body value
+	err = codec.ReadBody(&body, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body, gc.Equals, value{X: "result"})
+	c.Assert(counter.sent(), jc.DeepEquals, []int{len(msg)})
+	c.Assert(counter.received(), jc.DeepEquals, []int{len(reply)})
+}
+
+type testCounter struct {
+	mu            sync.Mutex
+	sentSizes     []int
+	receivedSizes []int
+}
+
+func (c *testCounter) MessageSent(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sentSizes = append(c.sentSizes, size)
+}
+
+func (c *testCounter) MessageReceived(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receivedSizes = append(c.receivedSizes, size)
+}
+
+func (c *testCounter) sent() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sentSizes
+}
+
+func (c *testCounter) received() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receivedSizes
+}
+
 // assertJSONEqual compares the json strings v0
 // and v1 ignoring white space.
 func assertJSONEqual(c *gc.C, v0, v1 string) {
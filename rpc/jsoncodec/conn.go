@@ -29,6 +29,57 @@ func (conn wsJSONConn) Close() error {
 	return conn.conn.Close()
 }
 
+// MessageCounter is informed of the size of each message sent and
+// received by a codec. Its methods may be called concurrently.
+type MessageCounter interface {
+	// MessageSent is called after a message of the given size
+	// has been sent.
+	MessageSent(size int)
+
+	// MessageReceived is called after a message of the given size
+	// has been received.
+	MessageReceived(size int)
+}
+
+// NewWebsocketCounted returns an rpc codec like that returned by
+// NewWebsocket, that also informs the given counter of the size of
+// each message.
+func NewWebsocketCounted(conn *websocket.Conn, counter MessageCounter) *Codec {
+	return New(wsCountedJSONConn{conn, counter})
+}
+
+// wsCountedJSONConn marshals messages itself, rather than using
+// websocket.JSON, so that their sizes are known.
+type wsCountedJSONConn struct {
+	conn    *websocket.Conn
+	counter MessageCounter
+}
+
+func (conn wsCountedJSONConn) Send(msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := websocket.Message.Send(conn.conn, string(data)); err != nil {
+		return err
+	}
+	conn.counter.MessageSent(len(data))
+	return nil
+}
+
+func (conn wsCountedJSONConn) Receive(msg interface{}) error {
+	var data []byte
+	if err := websocket.Message.Receive(conn.conn, &data); err != nil {
+		return err
+	}
+	conn.counter.MessageReceived(len(data))
+	return json.Unmarshal(data, msg)
+}
+
+func (conn wsCountedJSONConn) Close() error {
+	return conn.conn.Close()
+}
+
 // NewNet returns an rpc codec that uses the given net
 // connection to send and receive messages.
 func NewNet(conn net.Conn) *Codec {
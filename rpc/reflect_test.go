@@ -7,6 +7,7 @@ import (
 	"reflect"
 
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/rpc/rpcreflect"
@@ -30,12 +31,13 @@ func (*reflectSuite) TestTypeOf(c *gc.C) {
 		"Discard3",
 	})
 	expect := map[string]reflect.Type{
-		"CallbackMethods":  reflect.TypeOf(&CallbackMethods{}),
-		"ChangeAPIMethods": reflect.TypeOf(&ChangeAPIMethods{}),
-		"DelayedMethods":   reflect.TypeOf(&DelayedMethods{}),
-		"ErrorMethods":     reflect.TypeOf(&ErrorMethods{}),
-		"InterfaceMethods": reflect.TypeOf((*InterfaceMethods)(nil)).Elem(),
-		"SimpleMethods":    reflect.TypeOf(&SimpleMethods{}),
+		"CallbackMethods":    reflect.TypeOf(&CallbackMethods{}),
+		"CancellableMethods": reflect.TypeOf(&CancellableMethods{}),
+		"ChangeAPIMethods":   reflect.TypeOf(&ChangeAPIMethods{}),
+		"DelayedMethods":     reflect.TypeOf(&DelayedMethods{}),
+		"ErrorMethods":       reflect.TypeOf(&ErrorMethods{}),
+		"InterfaceMethods":   reflect.TypeOf((*InterfaceMethods)(nil)).Elem(),
+		"SimpleMethods":      reflect.TypeOf(&SimpleMethods{}),
 	}
 	c.Assert(rtype.MethodNames(), gc.HasLen, len(expect))
 	for name, expectGoType := range expect {
@@ -94,6 +96,33 @@ func (*reflectSuite) TestObjTypeOf(c *gc.C) {
 	c.Check(m, gc.DeepEquals, rpcreflect.ObjMethod{})
 }
 
+func (*reflectSuite) TestObjTypeOfContextMethods(c *gc.C) {
+	objType := rpcreflect.ObjTypeOf(reflect.TypeOf(&CancellableMethods{}))
+	c.Check(objType.MethodNames(), gc.DeepEquals, []string{"Echo", "Wait"})
+
+	m, err := objType.Method("Wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.IsNil)
+	c.Check(m.Result, gc.IsNil)
+
+	m, err = objType.Method("Echo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Params, gc.Equals, reflect.TypeOf(stringVal{}))
+	c.Check(m.Result, gc.Equals, reflect.TypeOf(stringVal{}))
+
+	// Call passes a background context; CallContext passes the
+	// context given.
+	rcvr := reflect.ValueOf(&CancellableMethods{})
+	arg := reflect.ValueOf(stringVal{"hello"})
+	rv, err := m.Call(rcvr, arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rv.Interface(), gc.Equals, stringVal{"hello"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = m.CallContext(ctx, rcvr, arg)
+	c.Check(err, gc.Equals, context.Canceled)
+}
+
 func (*reflectSuite) TestValueOf(c *gc.C) {
 	v := rpcreflect.ValueOf(reflect.ValueOf(nil))
 	c.Check(v.IsValid(), jc.IsFalse)
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
+	"golang.org/x/net/context"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/rpc"
@@ -59,6 +60,8 @@ type Root struct {
 	simple    map[string]*SimpleMethods
 	delayed   map[string]*DelayedMethods
 	errorInst *ErrorMethods
+
+	cancellable *CancellableMethods
 }
 
 func (r *Root) callError(rcvr interface{}, name string, arg interface{}) error {
@@ -93,6 +96,13 @@ func (r *Root) ErrorMethods(id string) (*ErrorMethods, error) {
 	return r.errorInst, nil
 }
 
+func (r *Root) CancellableMethods(id string) (*CancellableMethods, error) {
+	if r.cancellable == nil {
+		return nil, fmt.Errorf("no cancellable methods")
+	}
+	return r.cancellable, nil
+}
+
 func (r *Root) Discard1() {}
 
 func (r *Root) Discard2(id string) error { return nil }
@@ -211,6 +221,22 @@ func (a *DelayedMethods) Delay() (stringVal, error) {
 	}
 }
 
+type CancellableMethods struct {
+	ready     chan struct{}
+	cancelled chan error
+}
+
+func (a *CancellableMethods) Wait(ctx context.Context) error {
+	a.ready <- struct{}{}
+	<-ctx.Done()
+	a.cancelled <- ctx.Err()
+	return ctx.Err()
+}
+
+func (a *CancellableMethods) Echo(ctx context.Context, s stringVal) (stringVal, error) {
+	return s, ctx.Err()
+}
+
 type ErrorMethods struct {
 	err error
 }
@@ -749,6 +775,69 @@ func (*rpcSuite) TestConcurrentCalls(c *gc.C) {
 	chanRead(c, done2, "method 2 done")
 }
 
+func newCancellableRoot() *Root {
+	return &Root{
+		cancellable: &CancellableMethods{
+			ready:     make(chan struct{}),
+			cancelled: make(chan error, 1),
+		},
+	}
+}
+
+func (*rpcSuite) TestCallContextCancel(c *gc.C) {
+	root := newCancellableRoot()
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- client.CallContext(ctx, rpc.Request{"CancellableMethods", 0, "", "Wait"}, nil, nil)
+	}()
+	chanRead(c, root.cancellable.ready, "method ready")
+	cancel()
+	err := chanReadError(c, done, "call done")
+	c.Assert(err, gc.Equals, context.Canceled)
+
+	// The server was asked to cancel the request.
+	err = chanReadError(c, root.cancellable.cancelled, "method cancelled")
+	c.Assert(err, gc.Equals, context.Canceled)
+
+	// The connection can still be used.
+	var r stringVal
+	err = client.CallContext(context.Background(), rpc.Request{"CancellableMethods", 0, "", "Echo"}, stringVal{"hello"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r, gc.Equals, stringVal{"hello"})
+}
+
+func (*rpcSuite) TestCallContextAlreadyDone(c *gc.C) {
+	root := newCancellableRoot()
+	client, srvDone, clientNotifier, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := client.CallContext(ctx, rpc.Request{"CancellableMethods", 0, "", "Echo"}, stringVal{"hello"}, nil)
+	c.Assert(err, gc.Equals, context.Canceled)
+	c.Assert(clientNotifier.clientRequests, gc.HasLen, 0)
+}
+
+func (*rpcSuite) TestCloseCancelsServerRequests(c *gc.C) {
+	root := newCancellableRoot()
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Call(rpc.Request{"CancellableMethods", 0, "", "Wait"}, nil, nil)
+	}()
+	chanRead(c, root.cancellable.ready, "method ready")
+	closeClient(c, client, srvDone)
+	err := chanReadError(c, root.cancellable.cancelled, "method cancelled")
+	c.Assert(err, gc.Equals, context.Canceled)
+	err = chanReadError(c, done, "call done")
+	c.Assert(err, gc.Equals, rpc.ErrShutdown)
+}
+
 type codedError struct {
 	m    string
 	code string
@@ -773,6 +862,27 @@ func (*rpcSuite) TestErrorCode(c *gc.C) {
 	c.Assert(err.(rpc.ErrorCoder).ErrorCode(), gc.Equals, "code")
 }
 
+type infoError struct {
+	codedError
+	info map[string]interface{}
+}
+
+func (e *infoError) ErrorInfo() map[string]interface{} {
+	return e.info
+}
+
+func (*rpcSuite) TestErrorInfo(c *gc.C) {
+	info := map[string]interface{}{"limit": 3.0}
+	root := &Root{
+		errorInst: &ErrorMethods{&infoError{codedError{"message", "code"}, info}},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	err := client.Call(rpc.Request{"ErrorMethods", 0, "", "Call"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `request error: message \(code\)`)
+	c.Assert(err.(rpc.ErrorInfoer).ErrorInfo(), jc.DeepEquals, info)
+}
+
 func (*rpcSuite) TestTransformErrors(c *gc.C) {
 	root := &Root{
 		errorInst: &ErrorMethods{&codedError{"message", "code"}},
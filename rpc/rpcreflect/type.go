@@ -8,11 +8,14 @@ import (
 	"reflect"
 	"sort"
 	"sync"
+
+	"golang.org/x/net/context"
 )
 
 var (
-	errorType  = reflect.TypeOf((*error)(nil)).Elem()
-	stringType = reflect.TypeOf("")
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	stringType  = reflect.TypeOf("")
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 )
 
 var (
@@ -216,6 +219,12 @@ type ObjMethod struct {
 	// on the given receiver value. If the method does
 	// not return a value, the returned value will not be valid.
 	Call func(rcvr, arg reflect.Value) (reflect.Value, error)
+
+	// CallContext is like Call, but also passes the given
+	// context to methods that take a context.Context as
+	// their first argument, so that they can stop work
+	// when the request is cancelled.
+	CallContext func(ctx context.Context, rcvr, arg reflect.Value) (reflect.Value, error)
 }
 
 // ObjTypeOf returns information on all RPC methods
@@ -269,7 +278,7 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 		return nil
 	}
 	var p ObjMethod
-	var assemble func(arg reflect.Value) []reflect.Value
+	var assemble func(ctx context.Context, arg reflect.Value) []reflect.Value
 	// N.B. The method type has the receiver as its first argument
 	// unless the receiver is an interface.
 	receiverArgCount := 1
@@ -280,15 +289,26 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	switch {
 	case t.NumIn() == 0+receiverArgCount:
 		// Method() ...
-		assemble = func(arg reflect.Value) []reflect.Value {
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
 			return nil
 		}
+	case t.NumIn() == 1+receiverArgCount && t.In(receiverArgCount) == contextType:
+		// Method(context.Context) ...
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(&ctx).Elem()}
+		}
 	case t.NumIn() == 1+receiverArgCount:
 		// Method(T) ...
 		p.Params = t.In(receiverArgCount)
-		assemble = func(arg reflect.Value) []reflect.Value {
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
 			return []reflect.Value{arg}
 		}
+	case t.NumIn() == 2+receiverArgCount && t.In(receiverArgCount) == contextType:
+		// Method(context.Context, T) ...
+		p.Params = t.In(receiverArgCount + 1)
+		assemble = func(ctx context.Context, arg reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.ValueOf(&ctx).Elem(), arg}
+		}
 	default:
 		return nil
 	}
@@ -296,14 +316,14 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	switch {
 	case t.NumOut() == 0:
 		// Method(...)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallContext = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			return
 		}
 	case t.NumOut() == 1 && t.Out(0) == errorType:
 		// Method(...) error
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallContext = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			if !out[0].IsNil() {
 				err = out[0].Interface().(error)
 			}
@@ -312,15 +332,15 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	case t.NumOut() == 1:
 		// Method(...) R
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (reflect.Value, error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallContext = func(ctx context.Context, rcvr, arg reflect.Value) (reflect.Value, error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			return out[0], nil
 		}
 	case t.NumOut() == 2 && t.Out(1) == errorType:
 		// Method(...) (R, error)
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallContext = func(ctx context.Context, rcvr, arg reflect.Value) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(ctx, arg))
 			r = out[0]
 			if !out[1].IsNil() {
 				err = out[1].Interface().(error)
@@ -330,6 +350,10 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	default:
 		return nil
 	}
+	callContext := p.CallContext
+	p.Call = func(rcvr, arg reflect.Value) (reflect.Value, error) {
+		return callContext(context.Background(), rcvr, arg)
+	}
 	// The parameters and return value must be of struct type.
 	if p.Params != nil && p.Params.Kind() != reflect.Struct {
 		return nil
@@ -6,6 +6,8 @@ package rpcreflect
 import (
 	"fmt"
 	"reflect"
+
+	"golang.org/x/net/context"
 )
 
 // CallNotImplementedError is an error, returned an attempt to call to
@@ -115,6 +117,14 @@ func (caller methodCaller) Call(objId string, arg reflect.Value) (reflect.Value,
 	return caller.objMethod.Call(obj, arg)
 }
 
+func (caller methodCaller) CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	obj, err := caller.rootMethod.Call(caller.rootValue, objId)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return caller.objMethod.CallContext(ctx, obj, arg)
+}
+
 func (caller methodCaller) ParamsType() reflect.Type {
 	return caller.objMethod.Params
 }
@@ -134,3 +144,14 @@ type MethodCaller interface {
 	// call the method on that instance.
 	Call(objId string, arg reflect.Value) (reflect.Value, error)
 }
+
+// ContextMethodCaller is implemented by a MethodCaller that can pass a
+// context to the methods it calls. The RPC server cancels the context
+// when the client cancels the request or the connection closes.
+type ContextMethodCaller interface {
+	MethodCaller
+
+	// CallContext is like Call, but passes ctx to methods that
+	// take a context.Context as their first argument.
+	CallContext(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error)
+}
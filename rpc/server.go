@@ -11,12 +11,19 @@ import (
 	"time"
 
 	"github.com/juju/loggo"
+	"golang.org/x/net/context"
 
 	"github.com/juju/juju/rpc/rpcreflect"
 )
 
 const CodeNotImplemented = "not implemented"
 
+// cancelAction is the action of a request, with no type, that asks the
+// server to cancel the request with the same request id. No reply is
+// sent to it. Servers that predate cancellation reply with an error,
+// which the client discards as it is no longer waiting for the call.
+const cancelAction = "Cancel"
+
 var logger = loggo.GetLogger("juju.rpc")
 
 // A Codec implements reading and writing of messages in an RPC
@@ -62,6 +69,9 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// ErrorInfo holds structured data about the error, if any.
+	ErrorInfo map[string]interface{}
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -85,6 +95,12 @@ func (hdr *Header) IsRequest() bool {
 	return hdr.Request.Type != "" || hdr.Request.Action != ""
 }
 
+// isCancel returns whether the header represents a request to cancel
+// an outstanding request.
+func (hdr *Header) isCancel() bool {
+	return hdr.Request.Type == "" && hdr.Request.Action == cancelAction
+}
+
 // Note that we use "client request" and "server request" to name
 // requests initiated locally and remotely respectively.
 
@@ -102,6 +118,11 @@ type Conn struct {
 	// srvPending represents the current server requests.
 	srvPending sync.WaitGroup
 
+	// srvCancels holds the functions that cancel the contexts
+	// of the current server requests, by request id. It is
+	// guarded by mutex.
+	srvCancels map[uint64]context.CancelFunc
+
 	// sending guards the write side of the codec - it ensures
 	// that codec.WriteMessage is not called concurrently.
 	// It also guards shutdown.
@@ -194,6 +215,7 @@ func NewConn(codec Codec, notifier RequestNotifier) *Conn {
 	return &Conn{
 		codec:         codec,
 		clientPending: make(map[uint64]*Call),
+		srvCancels:    make(map[uint64]context.CancelFunc),
 		notifier:      notifier,
 	}
 }
@@ -240,6 +262,11 @@ func (conn *Conn) Start() {
 //	Method(T) (R, error)
 //	Method(T) error
 //
+// Any of these forms may also take a context.Context as their first
+// argument. The context is cancelled when the client cancels the
+// request (see Conn.CallContext) or the connection is closed, so
+// that long-running methods can stop early.
+//
 // If transformErrors is non-nil, it will be called on all returned
 // non-nil errors, for example to transform the errors into ServerErrors
 // with specified codes.  There will be a panic if transformErrors
@@ -339,6 +366,9 @@ func (conn *Conn) Close() error {
 // Kill server requests if appropriate. Client requests will be
 // terminated when the input loop finishes.
 func (conn *Conn) killRequests() {
+	for _, cancel := range conn.srvCancels {
+		cancel()
+	}
 	if killer, ok := conn.root.(Killer); ok {
 		killer.Kill()
 	}
@@ -357,6 +387,13 @@ type ErrorCoder interface {
 	ErrorCode() string
 }
 
+// ErrorInfoer represents an error that has structured data
+// associated with it, such as the limit that was exceeded, which
+// is sent to the client along with the error code.
+type ErrorInfoer interface {
+	ErrorInfo() map[string]interface{}
+}
+
 // MethodFinder represents a type that can be used to lookup a Method and place
 // calls on that method.
 type MethodFinder interface {
@@ -409,7 +446,9 @@ func (conn *Conn) loop() error {
 		if err != nil {
 			return err
 		}
-		if hdr.IsRequest() {
+		if hdr.isCancel() {
+			err = conn.handleCancel(&hdr)
+		} else if hdr.IsRequest() {
 			err = conn.handleRequest(&hdr)
 		} else {
 			err = conn.handleResponse(&hdr)
@@ -427,6 +466,24 @@ func (conn *Conn) readBody(resp interface{}, isRequest bool) error {
 	return conn.codec.ReadBody(resp, isRequest)
 }
 
+// handleCancel cancels the context of the server request with the
+// header's request id, if it is still running.
+func (conn *Conn) handleCancel(hdr *Header) error {
+	if conn.notifier != nil {
+		conn.notifier.ServerRequest(hdr, struct{}{})
+	}
+	if err := conn.readBody(nil, true); err != nil {
+		return err
+	}
+	conn.mutex.Lock()
+	cancel := conn.srvCancels[hdr.RequestId]
+	conn.mutex.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
 func (conn *Conn) handleRequest(hdr *Header) error {
 	startTime := time.Now()
 	req, err := conn.bindRequest(hdr)
@@ -477,8 +534,10 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	conn.mutex.Lock()
 	closing := conn.closing
 	if !closing {
+		ctx, cancel := context.WithCancel(context.Background())
+		conn.srvCancels[hdr.RequestId] = cancel
 		conn.srvPending.Add(1)
-		go conn.runRequest(req, arg, startTime)
+		go conn.runRequest(ctx, req, arg, startTime)
 	}
 	conn.mutex.Unlock()
 	if closing {
@@ -499,6 +558,9 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.T
 	} else {
 		hdr.ErrorCode = ""
 	}
+	if err, ok := err.(ErrorInfoer); ok {
+		hdr.ErrorInfo = err.ErrorInfo()
+	}
 	hdr.Error = err.Error()
 	if conn.notifier != nil {
 		conn.notifier.ServerReply(reqHdr.Request, hdr, struct{}{}, time.Since(startTime))
@@ -547,9 +609,16 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 }
 
 // runRequest runs the given request and sends the reply.
-func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time.Time) {
+func (conn *Conn) runRequest(ctx context.Context, req boundRequest, arg reflect.Value, startTime time.Time) {
 	defer conn.srvPending.Done()
-	rv, err := req.Call(req.hdr.Request.Id, arg)
+	defer conn.requestDone(req.hdr.RequestId)
+	var rv reflect.Value
+	var err error
+	if caller, ok := req.MethodCaller.(rpcreflect.ContextMethodCaller); ok {
+		rv, err = caller.CallContext(ctx, req.hdr.Request.Id, arg)
+	} else {
+		rv, err = req.Call(req.hdr.Request.Id, arg)
+	}
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), startTime)
 	} else {
@@ -574,6 +643,17 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time
 	}
 }
 
+// requestDone releases the context of the server request with the
+// given id.
+func (conn *Conn) requestDone(reqId uint64) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if cancel := conn.srvCancels[reqId]; cancel != nil {
+		cancel()
+		delete(conn.srvCancels, reqId)
+	}
+}
+
 type serverError RequestError
 
 func (e *serverError) Error() string {
@@ -583,3 +663,7 @@ func (e *serverError) Error() string {
 func (e *serverError) ErrorCode() string {
 	return e.Code
 }
+
+func (e *serverError) ErrorInfo() map[string]interface{} {
+	return e.Info
+}
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// The streams of output recorded for a running action.
+const (
+	ActionOutputStdout = "stdout"
+	ActionOutputStderr = "stderr"
+)
+
+// actionOutputDoc holds a chunk of the output written by an action
+// while it runs, so that the output can be watched before the action
+// completes.
+type actionOutputDoc struct {
+	DocId     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	ActionId  string    `bson:"action-id"`
+	Seq       int       `bson:"seq"`
+	Stream    string    `bson:"stream"`
+	Data      string    `bson:"data"`
+	Timestamp time.Time `bson:"timestamp"`
+}
+
+// ActionOutput holds a chunk of the output written by an action.
+type ActionOutput struct {
+	// Seq orders the chunks of an action's output.
+	Seq int
+
+	// Stream is ActionOutputStdout or ActionOutputStderr.
+	Stream string
+
+	// Data holds the output.
+	Data string
+
+	// Timestamp records when the output was recorded.
+	Timestamp time.Time
+}
+
+// AppendOutput records a chunk of output written by the action to the
+// given stream. The action must be running.
+func (a *Action) AppendOutput(stream, data string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record output of action %q", a.Id())
+	if stream != ActionOutputStdout && stream != ActionOutputStderr {
+		return errors.NotValidf("output stream %q", stream)
+	}
+	seq, err := a.st.sequence("actionoutput-" + a.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: bson.D{{"status", ActionRunning}},
+	}, {
+		C:      actionOutputC,
+		Id:     a.st.docID(fmt.Sprintf("%s#%d", a.Id(), seq)),
+		Assert: txn.DocMissing,
+		Insert: &actionOutputDoc{
+			EnvUUID:   a.st.EnvironUUID(),
+			ActionId:  a.Id(),
+			Seq:       seq,
+			Stream:    stream,
+			Data:      data,
+			Timestamp: time.Now(),
+		},
+	}}
+	err = a.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.New("action is not running")
+	}
+	return errors.Trace(err)
+}
+
+// Output returns the chunks of output recorded for the action with a
+// sequence number greater than after, in order. Pass -1 to get all the
+// recorded output.
+func (a *Action) Output(after int) ([]ActionOutput, error) {
+	outputs, closer := a.st.getCollection(actionOutputC)
+	defer closer()
+
+	var docs []actionOutputDoc
+	err := outputs.Find(bson.D{
+		{"action-id", a.Id()},
+		{"seq", bson.D{{"$gt", after}}},
+	}).Sort("seq").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get output of action %q", a.Id())
+	}
+	result := make([]ActionOutput, len(docs))
+	for i, doc := range docs {
+		result[i] = ActionOutput{
+			Seq:       doc.Seq,
+			Stream:    doc.Stream,
+			Data:      doc.Data,
+			Timestamp: doc.Timestamp,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionOutputSuite struct {
+	ConnSuite
+	action *state.Action
+}
+
+var _ = gc.Suite(&ActionOutputSuite{})
+
+func (s *ActionOutputSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.action, err = unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionOutputSuite) streams(outputs []state.ActionOutput) [][2]string {
+	var result [][2]string
+	for _, output := range outputs {
+		result = append(result, [2]string{output.Stream, output.Data})
+	}
+	return result
+}
+
+func (s *ActionOutputSuite) TestAppendOutputRequiresRunning(c *gc.C) {
+	err := s.action.AppendOutput(state.ActionOutputStdout, "hello\n")
+	c.Assert(err, gc.ErrorMatches, `cannot record output of action ".*": action is not running`)
+
+	action, err := s.action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.AppendOutput(state.ActionOutputStdout, "hello\n")
+	c.Assert(err, gc.ErrorMatches, `cannot record output of action ".*": action is not running`)
+}
+
+func (s *ActionOutputSuite) TestAppendOutputInvalidStream(c *gc.C) {
+	action, err := s.action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.AppendOutput("stdin", "hello\n")
+	c.Assert(err, gc.ErrorMatches, `cannot record output of action ".*": output stream "stdin" not valid`)
+}
+
+func (s *ActionOutputSuite) TestOutput(c *gc.C) {
+	action, err := s.action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	outputs, err := action.Output(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(outputs, gc.HasLen, 0)
+
+	err = action.AppendOutput(state.ActionOutputStdout, "one\n")
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.AppendOutput(state.ActionOutputStderr, "two\n")
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.AppendOutput(state.ActionOutputStdout, "three\n")
+	c.Assert(err, jc.ErrorIsNil)
+
+	outputs, err = action.Output(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.streams(outputs), jc.DeepEquals, [][2]string{
+		{"stdout", "one\n"},
+		{"stderr", "two\n"},
+		{"stdout", "three\n"},
+	})
+
+	outputs, err = action.Output(outputs[0].Seq)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.streams(outputs), jc.DeepEquals, [][2]string{
+		{"stderr", "two\n"},
+		{"stdout", "three\n"},
+	})
+
+	// The output remains once the action has finished.
+	action, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	outputs, err = action.Output(-1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(outputs, gc.HasLen, 3)
+}
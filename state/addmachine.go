@@ -15,6 +15,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 )
@@ -90,6 +91,10 @@ type MachineTemplate struct {
 	// principals holds the principal units that will
 	// associated with the machine.
 	principals []string
+
+	// distributionGroup holds the name of the distribution
+	// group that the machine will belong to.
+	distributionGroup string
 }
 
 // MachineVolumeParams holds the parameters for creating a volume and
@@ -127,6 +132,21 @@ func (st *State) AddMachineInsideMachine(template MachineTemplate, parentId stri
 	return st.addMachine(mdoc, ops)
 }
 
+// AddMachineInsideNewContainer creates a new machine within a container
+// of the given type inside a new container of type hostType on the
+// existing machine with id=hostId. The two given templates specify the
+// form of the child and of its new host container respectively.
+func (st *State) AddMachineInsideNewContainer(
+	template, parentTemplate MachineTemplate,
+	hostId string, hostType, containerType instance.ContainerType,
+) (*Machine, error) {
+	mdoc, ops, err := st.addMachineInsideNewContainerOps(template, parentTemplate, hostId, hostType, containerType)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot add a new machine")
+	}
+	return st.addMachine(mdoc, ops)
+}
+
 // AddMachine adds a machine with the given series and jobs.
 // It is deprecated and around for testing purposes only.
 func (st *State) AddMachine(series string, jobs ...MachineJob) (*Machine, error) {
@@ -228,7 +248,7 @@ func (st *State) effectiveMachineTemplate(p MachineTemplate, allowStateServer bo
 		return tmpl, errors.New("cannot specify a nonce without an instance id")
 	}
 
-	p.Constraints, err = st.resolveConstraints(p.Constraints)
+	p.Constraints, err = st.resolveConstraints(resolver.Layers{Placement: p.Constraints})
 	if err != nil {
 		return tmpl, err
 	}
@@ -436,6 +456,82 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 	return mdoc, append(prereqOps, parentOp, machineOp), nil
 }
 
+// addMachineInsideNewContainerOps returns operations to create a new
+// machine within a container of the given type inside a new container
+// of type hostType on the existing machine with id=hostId. The two
+// given templates specify the form of the child and of its new host
+// container respectively.
+func (st *State) addMachineInsideNewContainerOps(
+	template, parentTemplate MachineTemplate,
+	hostId string, hostType, containerType instance.ContainerType,
+) (*machineDoc, []txn.Op, error) {
+	if template.InstanceId != "" || parentTemplate.InstanceId != "" {
+		return nil, nil, errors.New("cannot specify instance id for a new container")
+	}
+	if containerType == "" || hostType == "" {
+		return nil, nil, errors.New("no container type specified")
+	}
+	if !instance.CanHostContainer(hostType, containerType) {
+		return nil, nil, errors.Errorf("cannot create %s containers inside %s containers", containerType, hostType)
+	}
+	// Adding a machine within a machine implies add-machine or placement.
+	if err := st.supportsUnitPlacement(); err != nil {
+		return nil, nil, err
+	}
+
+	// Make sure the host machine exists and can support the
+	// requested type of the new parent container.
+	host, err := st.Machine(hostId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !host.supportsContainerType(hostType) {
+		return nil, nil, errors.Errorf("machine %s cannot host %s containers", hostId, hostType)
+	}
+
+	parentTemplate, err = st.effectiveMachineTemplate(parentTemplate, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentId, err := st.newContainerId(hostId, hostType)
+	if err != nil {
+		return nil, nil, err
+	}
+	parentDoc := st.machineDocForTemplate(parentTemplate, parentId)
+	parentDoc.ContainerType = string(hostType)
+
+	template, err = st.effectiveMachineTemplate(template, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	newId, err := st.newContainerId(parentId, containerType)
+	if err != nil {
+		return nil, nil, err
+	}
+	mdoc := st.machineDocForTemplate(template, newId)
+	mdoc.ContainerType = string(containerType)
+
+	parentPrereqOps, parentOp, err := st.insertNewMachineOps(parentDoc, parentTemplate)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	prereqOps, machineOp, err := st.insertNewMachineOps(mdoc, template)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	prereqOps = append(prereqOps, parentPrereqOps...)
+	prereqOps = append(prereqOps,
+		// Update containers record for host machine.
+		st.addChildToContainerRefOp(hostId, parentId),
+		// Create a containers reference document for the new parent
+		// container, which already holds the new machine.
+		st.insertNewContainerRefOp(parentId, newId),
+		// Create a containers reference document for the container itself.
+		st.insertNewContainerRefOp(newId),
+	)
+	return mdoc, append(prereqOps, parentOp, machineOp), nil
+}
+
 func (st *State) machineDocForTemplate(template MachineTemplate, id string) *machineDoc {
 	return &machineDoc{
 		DocID:      st.docID(id),
@@ -450,6 +546,8 @@ func (st *State) machineDocForTemplate(template MachineTemplate, id string) *mac
 		Addresses:  fromNetworkAddresses(template.Addresses),
 		NoVote:     template.NoVote,
 		Placement:  template.Placement,
+
+		DistributionGroup: template.distributionGroup,
 	}
 }
 
@@ -102,7 +102,8 @@ func (st *State) APIAddressesFromMachines() ([]string, error) {
 const apiHostPortsKey = "apiHostPorts"
 
 type apiHostPortsDoc struct {
-	APIHostPorts [][]hostPort `bson:"apihostports"`
+	APIHostPorts       [][]hostPort `bson:"apihostports"`
+	PublicAPIHostPorts []hostPort   `bson:"publicapihostports,omitempty"`
 }
 
 // SetAPIHostPorts sets the addresses of the API server instances.
@@ -112,7 +113,7 @@ func (st *State) SetAPIHostPorts(netHostsPorts [][]network.HostPort) error {
 		APIHostPorts: fromNetworkHostsPorts(netHostsPorts),
 	}
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		existing, err := st.APIHostPorts()
+		existing, err := st.serverAPIHostPorts()
 		if err != nil {
 			return nil, err
 		}
@@ -137,8 +138,32 @@ func (st *State) SetAPIHostPorts(netHostsPorts [][]network.HostPort) error {
 	return nil
 }
 
-// APIHostPorts returns the API addresses as set by SetAPIHostPorts.
+// APIHostPorts returns the API addresses that agents and clients
+// should connect to. These are the addresses set by SetAPIHostPorts,
+// unless they are overridden by addresses set with
+// SetPublicAPIHostPorts.
 func (st *State) APIHostPorts() ([][]network.HostPort, error) {
+	doc, err := st.apiHostPortsDoc()
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.PublicAPIHostPorts) > 0 {
+		return [][]network.HostPort{networkHostPorts(doc.PublicAPIHostPorts)}, nil
+	}
+	return networkHostsPorts(doc.APIHostPorts), nil
+}
+
+// serverAPIHostPorts returns the API addresses as set by
+// SetAPIHostPorts, ignoring any public override.
+func (st *State) serverAPIHostPorts() ([][]network.HostPort, error) {
+	doc, err := st.apiHostPortsDoc()
+	if err != nil {
+		return nil, err
+	}
+	return networkHostsPorts(doc.APIHostPorts), nil
+}
+
+func (st *State) apiHostPortsDoc() (*apiHostPortsDoc, error) {
 	var doc apiHostPortsDoc
 	stateServers, closer := st.getCollection(stateServersC)
 	defer closer()
@@ -146,7 +171,47 @@ func (st *State) APIHostPorts() ([][]network.HostPort, error) {
 	if err != nil {
 		return nil, err
 	}
-	return networkHostsPorts(doc.APIHostPorts), nil
+	return &doc, nil
+}
+
+// PublicAPIHostPorts returns the addresses set by
+// SetPublicAPIHostPorts, if any.
+func (st *State) PublicAPIHostPorts() ([]network.HostPort, error) {
+	doc, err := st.apiHostPortsDoc()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return networkHostPorts(doc.PublicAPIHostPorts), nil
+}
+
+// SetPublicAPIHostPorts sets the API addresses, such as those of a
+// load balancer or NAT gateway in front of the API servers, that
+// agents and clients are told to connect to in place of the addresses
+// of the API server instances. If no addresses are given, the
+// addresses of the instances are used again.
+func (st *State) SetPublicAPIHostPorts(hps []network.HostPort) error {
+	for _, hp := range hps {
+		if hp.Value == "" || hp.Port <= 0 || hp.Port > 65535 {
+			return errors.NotValidf("API address %q", hp.NetAddr())
+		}
+	}
+	var update bson.D
+	if len(hps) == 0 {
+		update = bson.D{{"$unset", bson.D{{"publicapihostports", nil}}}}
+	} else {
+		update = bson.D{{"$set", bson.D{{"publicapihostports", fromNetworkHostPorts(hps)}}}}
+	}
+	ops := []txn.Op{{
+		C:      stateServersC,
+		Id:     apiHostPortsKey,
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return errors.Annotate(err, "cannot set public API addresses")
+	}
+	logger.Debugf("setting public API hostPorts: %v", hps)
+	return nil
 }
 
 type DeployerConnectionValues struct {
@@ -266,6 +331,29 @@ func (hp *hostPort) networkHostPort() network.HostPort {
 	}
 }
 
+// fromNetworkHostPorts is a helper to create a state type
+// out of the network type, here for a slice of HostPort.
+func fromNetworkHostPorts(netHostPorts []network.HostPort) []hostPort {
+	hps := make([]hostPort, len(netHostPorts))
+	for i, netHostPort := range netHostPorts {
+		hps[i] = fromNetworkHostPort(netHostPort)
+	}
+	return hps
+}
+
+// networkHostPorts is a convenience helper to return the state type
+// as network type, here for a slice of HostPort.
+func networkHostPorts(hps []hostPort) []network.HostPort {
+	if len(hps) == 0 {
+		return nil
+	}
+	netHostPorts := make([]network.HostPort, len(hps))
+	for i, hp := range hps {
+		netHostPorts[i] = hp.networkHostPort()
+	}
+	return netHostPorts
+}
+
 // fromNetworkHostsPorts is a helper to create a state type
 // out of the network type, here for a nested slice of HostPort.
 func fromNetworkHostsPorts(netHostsPorts [][]network.HostPort) [][]hostPort {
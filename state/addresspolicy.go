@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// AddressPolicy determines which of the addresses of a unit's machine
+// is reported as the unit's public address.
+type AddressPolicy string
+
+const (
+	// AddressPolicyPublic selects the most public address; this is
+	// the default.
+	AddressPolicyPublic AddressPolicy = "public"
+
+	// AddressPolicyPrivate selects the address used for communication
+	// within the environment.
+	AddressPolicyPrivate AddressPolicy = "private"
+)
+
+// addressPolicySpacePrefix starts policies that select an address in
+// a space.
+const addressPolicySpacePrefix = "space:"
+
+// SpaceAddressPolicy returns the policy that selects an address on one
+// of the subnets in the named space.
+func SpaceAddressPolicy(space string) AddressPolicy {
+	return AddressPolicy(addressPolicySpacePrefix + space)
+}
+
+// ParseAddressPolicy parses an address policy: one of "public",
+// "private" or "space:<name>". The empty string is taken as "public".
+func ParseAddressPolicy(s string) (AddressPolicy, error) {
+	policy := AddressPolicy(s)
+	if policy == "" {
+		policy = AddressPolicyPublic
+	}
+	if err := policy.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	return policy, nil
+}
+
+// Validate returns an error if the policy is not valid.
+func (p AddressPolicy) Validate() error {
+	switch p {
+	case AddressPolicyPublic, AddressPolicyPrivate:
+		return nil
+	}
+	if space, ok := p.Space(); ok {
+		if !validSpaceName.MatchString(space) {
+			return errors.NotValidf("space name %q", space)
+		}
+		return nil
+	}
+	return errors.NotValidf("address policy %q", string(p))
+}
+
+// Space returns the name of the space in which an address is selected
+// by the policy, and whether the policy is space-scoped.
+func (p AddressPolicy) Space() (string, bool) {
+	if !strings.HasPrefix(string(p), addressPolicySpacePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(p), addressPolicySpacePrefix), true
+}
+
+// AddressPolicy returns the policy used to select the public addresses
+// of the service's units.
+func (s *Service) AddressPolicy() AddressPolicy {
+	if s.doc.AddressPolicy == "" {
+		return AddressPolicyPublic
+	}
+	return s.doc.AddressPolicy
+}
+
+// SetAddressPolicy changes the policy used to select the public
+// addresses of the service's units.
+func (s *Service) SetAddressPolicy(policy AddressPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set address policy of service %q", s)
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	stored := policy
+	if stored == AddressPolicyPublic {
+		stored = ""
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"address-policy", stored}}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("service is not alive")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.AddressPolicy = stored
+	return nil
+}
+
+// selectAddress returns the address chosen by the policy from the
+// given machine addresses. If a space-scoped policy finds no address in
+// the space, the most public address is returned.
+func (p AddressPolicy) selectAddress(st *State, addresses []network.Address) (string, error) {
+	if p == AddressPolicyPrivate {
+		return network.SelectInternalAddress(addresses, false), nil
+	}
+	space, ok := p.Space()
+	if !ok {
+		return network.SelectPublicAddress(addresses), nil
+	}
+	inSpace, err := addressesInSpace(st, space, addresses)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if address := network.SelectPublicAddress(inSpace); address != "" {
+		return address, nil
+	}
+	logger.Debugf("no address in space %q, using the public address", space)
+	return network.SelectPublicAddress(addresses), nil
+}
+
+// addressesInSpace returns those of the given addresses that are on a
+// subnet in the named space.
+func addressesInSpace(st *State, space string, addresses []network.Address) ([]network.Address, error) {
+	subnets, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	if err := subnets.Find(bson.D{{"spacename", space}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets in space %q", space)
+	}
+	var nets []*net.IPNet
+	for _, doc := range docs {
+		_, ipNet, err := net.ParseCIDR(doc.CIDR)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		nets = append(nets, ipNet)
+	}
+	var result []network.Address
+	for _, address := range addresses {
+		ip := net.ParseIP(address.Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				result = append(result, address)
+				break
+			}
+		}
+	}
+	return result, nil
+}
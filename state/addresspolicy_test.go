@@ -0,0 +1,139 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type AddressPolicySuite struct {
+	ConnSuite
+	service *state.Service
+	unit    *state.Unit
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&AddressPolicySuite{})
+
+func (s *AddressPolicySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetProviderAddresses(
+		network.NewScopedAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedAddress("10.0.0.5", network.ScopeCloudLocal),
+		network.NewScopedAddress("192.168.1.5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AddressPolicySuite) TestParseAddressPolicy(c *gc.C) {
+	for i, test := range []struct {
+		input  string
+		expect state.AddressPolicy
+		err    string
+	}{
+		{input: "", expect: state.AddressPolicyPublic},
+		{input: "public", expect: state.AddressPolicyPublic},
+		{input: "private", expect: state.AddressPolicyPrivate},
+		{input: "space:db-net", expect: state.SpaceAddressPolicy("db-net")},
+		{input: "space:Bad_Name", err: `space name "Bad_Name" not valid`},
+		{input: "space:", err: `space name "" not valid`},
+		{input: "elsewhere", err: `address policy "elsewhere" not valid`},
+	} {
+		c.Logf("test %d: %q", i, test.input)
+		policy, err := state.ParseAddressPolicy(test.input)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(policy, gc.Equals, test.expect)
+	}
+}
+
+func (s *AddressPolicySuite) TestSetAddressPolicy(c *gc.C) {
+	c.Assert(s.service.AddressPolicy(), gc.Equals, state.AddressPolicyPublic)
+	err := s.service.SetAddressPolicy(state.AddressPolicyPrivate)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.AddressPolicy(), gc.Equals, state.AddressPolicyPrivate)
+
+	service, err := s.State.Service("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.AddressPolicy(), gc.Equals, state.AddressPolicyPrivate)
+
+	err = s.service.SetAddressPolicy("nowhere")
+	c.Assert(err, gc.ErrorMatches, `cannot set address policy of service "wordpress": address policy "nowhere" not valid`)
+}
+
+func (s *AddressPolicySuite) TestSetAddressPolicyNotAlive(c *gc.C) {
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetAddressPolicy(state.AddressPolicyPrivate)
+	c.Assert(err, gc.ErrorMatches, `cannot set address policy of service "wordpress": service is not alive`)
+}
+
+func (s *AddressPolicySuite) assertPublicAddress(c *gc.C, policy state.AddressPolicy, expect string) {
+	err := s.service.SetAddressPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+	address, ok := s.unit.PublicAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, expect)
+}
+
+func (s *AddressPolicySuite) TestPublicAddress(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:      "192.168.1.0/24",
+		SpaceName: "db-net",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPublicAddress(c, state.AddressPolicyPublic, "8.8.8.8")
+	s.assertPublicAddress(c, state.AddressPolicyPrivate, "10.0.0.5")
+	s.assertPublicAddress(c, state.SpaceAddressPolicy("db-net"), "192.168.1.5")
+
+	// With no address in the space, the public address is used.
+	s.assertPublicAddress(c, state.SpaceAddressPolicy("other"), "8.8.8.8")
+}
+
+func (s *AddressPolicySuite) TestWatchUnitAddresses(c *gc.C) {
+	w, err := s.unit.WatchAddresses()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Change the machine's addresses: reported.
+	err = s.machine.SetProviderAddresses(network.NewScopedAddress("8.8.4.4", network.ScopePublic))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Change the address policy: reported.
+	err = s.service.SetAddressPolicy(state.AddressPolicyPrivate)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Change something else about the service: not reported.
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
+
+func (s *AddressPolicySuite) TestWatchUnitAddressesNotAssigned(c *gc.C) {
+	unit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = unit.WatchAddresses()
+	c.Assert(err, gc.ErrorMatches, `unit "wordpress/1" is not assigned to a machine`)
+}
@@ -0,0 +1,466 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+/*
+A staged agent upgrade (an "agent rollout") upgrades the agents of an
+environment in steps, so that a bad release can be caught before it
+reaches every machine:
+
+1. StartAgentRollout records the target version and a set of canary
+machines. The environment's agent-version is left unchanged, but the
+state servers and the canaries (and the units and containers they
+host) are told to upgrade to the target version.
+
+2. Once every canary agent runs the target version, the rollout starts
+baking: the canaries must stay healthy for the bake period.
+
+3. At the end of the bake period, the environment's agent-version is
+set to the target version, upgrading the remaining agents. If a canary
+reports an error at any point, the rollout is rolled back instead, and
+the upgraded agents return to the environment's agent-version.
+
+AdvanceAgentRollout moves a rollout through these phases; the operator
+may also promote or roll back a rollout explicitly.
+*/
+
+// AgentRolloutPhase describes the progress of a staged agent upgrade.
+type AgentRolloutPhase string
+
+const (
+	// AgentRolloutCanary indicates that the canary agents are
+	// upgrading to the target version.
+	AgentRolloutCanary AgentRolloutPhase = "canary"
+
+	// AgentRolloutBaking indicates that the canary agents run the
+	// target version, and are being watched for problems until the
+	// bake period ends.
+	AgentRolloutBaking AgentRolloutPhase = "baking"
+
+	// AgentRolloutPromoted indicates that the environment's agent
+	// version has been set to the target version.
+	AgentRolloutPromoted AgentRolloutPhase = "promoted"
+
+	// AgentRolloutRolledBack indicates that the rollout was abandoned,
+	// and the canary agents told to return to the environment's agent
+	// version.
+	AgentRolloutRolledBack AgentRolloutPhase = "rolled-back"
+
+	// currentAgentRolloutId is the local id of the agent rollout
+	// document of an environment.
+	currentAgentRolloutId = "current"
+)
+
+// inProgressAgentRolloutPhases holds the phases of a rollout that has
+// not yet finished.
+var inProgressAgentRolloutPhases = []AgentRolloutPhase{
+	AgentRolloutCanary,
+	AgentRolloutBaking,
+}
+
+// agentRolloutDoc records the progress of an environment's staged
+// agent upgrade. It remains after the rollout finishes, until the next
+// rollout is started.
+type agentRolloutDoc struct {
+	DocID           string            `bson:"_id"`
+	EnvUUID         string            `bson:"env-uuid"`
+	PreviousVersion version.Number    `bson:"previous-version"`
+	TargetVersion   version.Number    `bson:"target-version"`
+	Canaries        []string          `bson:"canaries"`
+	BakePeriod      time.Duration     `bson:"bake-period"`
+	Phase           AgentRolloutPhase `bson:"phase"`
+	Started         time.Time         `bson:"started"`
+	BakeStarted     time.Time         `bson:"bake-started,omitempty"`
+	Message         string            `bson:"message,omitempty"`
+}
+
+// AgentRollout holds the progress of a staged agent upgrade.
+type AgentRollout struct {
+	st  *State
+	doc agentRolloutDoc
+}
+
+// PreviousVersion returns the environment's agent version when the
+// rollout was started.
+func (r *AgentRollout) PreviousVersion() version.Number {
+	return r.doc.PreviousVersion
+}
+
+// TargetVersion returns the version to which the agents are being
+// upgraded.
+func (r *AgentRollout) TargetVersion() version.Number {
+	return r.doc.TargetVersion
+}
+
+// Canaries returns the ids of the machines that are upgraded first.
+func (r *AgentRollout) Canaries() []string {
+	result := make([]string, len(r.doc.Canaries))
+	copy(result, r.doc.Canaries)
+	return result
+}
+
+// BakePeriod returns how long the canaries must stay healthy, once
+// upgraded, before the rest of the environment is upgraded.
+func (r *AgentRollout) BakePeriod() time.Duration {
+	return r.doc.BakePeriod
+}
+
+// Phase returns the phase the rollout is in.
+func (r *AgentRollout) Phase() AgentRolloutPhase {
+	return r.doc.Phase
+}
+
+// Started returns the time at which the rollout was started.
+func (r *AgentRollout) Started() time.Time {
+	return r.doc.Started
+}
+
+// BakeStarted returns the time at which all the canaries were first
+// seen running the target version. It is zero if that has not
+// happened yet.
+func (r *AgentRollout) BakeStarted() time.Time {
+	return r.doc.BakeStarted
+}
+
+// Message returns the reason the rollout was rolled back, if it was.
+func (r *AgentRollout) Message() string {
+	return r.doc.Message
+}
+
+// InProgress reports whether the rollout has yet to be promoted or
+// rolled back.
+func (r *AgentRollout) InProgress() bool {
+	for _, phase := range inProgressAgentRolloutPhases {
+		if r.doc.Phase == phase {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCanary reports whether the agent of the given machine is upgraded
+// along with the canaries. This is true of the canaries themselves and
+// of any containers they host.
+func (r *AgentRollout) IsCanary(machineId string) bool {
+	for _, id := range r.doc.Canaries {
+		if machineId == id || strings.HasPrefix(machineId, id+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Refresh updates the contents of the AgentRollout from underlying
+// state.
+func (r *AgentRollout) Refresh() error {
+	doc, err := currentAgentRolloutDoc(r.st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.doc = *doc
+	return nil
+}
+
+func currentAgentRolloutDoc(st *State) (*agentRolloutDoc, error) {
+	rollouts, closer := st.getCollection(agentRolloutsC)
+	defer closer()
+
+	var doc agentRolloutDoc
+	err := rollouts.FindId(currentAgentRolloutId).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("agent rollout")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read agent rollout")
+	}
+	return &doc, nil
+}
+
+// AgentRollout returns the environment's current or most recent
+// staged agent upgrade. An error satisfying errors.IsNotFound is
+// returned if there has been none.
+func (st *State) AgentRollout() (*AgentRollout, error) {
+	doc, err := currentAgentRolloutDoc(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentRollout{st: st, doc: *doc}, nil
+}
+
+// StartAgentRollout starts a staged upgrade of the environment's agents
+// to the given version. The state servers and the given canary machines
+// are upgraded first; the rest of the environment is upgraded once the
+// canaries have run the new version without problems for the bake
+// period. Only upgrades within the current major and minor version may
+// be staged. A rollout cannot be started while another is in progress,
+// or while any agent runs a version other than the environment's agent
+// version.
+func (st *State) StartAgentRollout(target version.Number, canaries []string, bakePeriod time.Duration) (_ *AgentRollout, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot start staged upgrade to %s", target)
+	if len(canaries) == 0 {
+		return nil, errors.New("no canary machines specified")
+	}
+	if bakePeriod < 0 {
+		return nil, errors.NotValidf("negative bake period")
+	}
+	for _, id := range canaries {
+		m, err := st.Machine(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if m.IsManager() {
+			return nil, errors.Errorf("machine %s is a state server; state servers are always upgraded first", id)
+		}
+		if m.Life() != Alive {
+			return nil, errors.Errorf("machine %s is not alive", id)
+		}
+	}
+
+	settings, err := readSettings(st, environGlobalKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	agentVersion, ok := settings.Get("agent-version")
+	if !ok {
+		return nil, errors.Errorf("no agent version set in the environment")
+	}
+	current, err := version.Parse(fmt.Sprint(agentVersion))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if target.Compare(current) <= 0 {
+		return nil, errors.Errorf("target version must be newer than the current version %s", current)
+	}
+	// Agents of different major or minor versions may not be able to
+	// work together, so only patch and build upgrades are staged.
+	if target.Major != current.Major || target.Minor != current.Minor {
+		return nil, errors.Errorf("target version must have the same major and minor version as the current version %s", current)
+	}
+	if err := st.checkCanUpgrade(current.String(), current.String()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, problems, err := st.agentRolloutCanaryStatus(canaries, target); err != nil {
+		return nil, errors.Trace(err)
+	} else if len(problems) > 0 {
+		return nil, errors.Errorf("canaries are unhealthy: %s", strings.Join(problems, "; "))
+	}
+
+	doc := agentRolloutDoc{
+		DocID:           st.docID(currentAgentRolloutId),
+		EnvUUID:         st.EnvironUUID(),
+		PreviousVersion: current,
+		TargetVersion:   target,
+		Canaries:        canaries,
+		BakePeriod:      bakePeriod,
+		Phase:           AgentRolloutCanary,
+		Started:         time.Now().UTC(),
+	}
+	ops := []txn.Op{{
+		C:      settingsC,
+		Id:     st.docID(environGlobalKey),
+		Assert: bson.D{{"txn-revno", settings.txnRevno}},
+	}, {
+		// A staged upgrade cannot start while the state servers
+		// are upgrading.
+		C:      upgradeInfoC,
+		Id:     currentUpgradeId,
+		Assert: txn.DocMissing,
+	}}
+	previous, err := currentAgentRolloutDoc(st)
+	switch {
+	case errors.IsNotFound(err):
+		ops = append(ops, txn.Op{
+			C:      agentRolloutsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		})
+	case err != nil:
+		return nil, errors.Trace(err)
+	default:
+		rollout := &AgentRollout{st: st, doc: *previous}
+		if rollout.InProgress() {
+			return nil, errors.AlreadyExistsf("staged upgrade to %s", previous.TargetVersion)
+		}
+		ops = append(ops, txn.Op{
+			C:  agentRolloutsC,
+			Id: doc.DocID,
+			Assert: bson.D{{"phase", bson.D{
+				{"$nin", inProgressAgentRolloutPhases},
+			}}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"previous-version", doc.PreviousVersion},
+					{"target-version", doc.TargetVersion},
+					{"canaries", doc.Canaries},
+					{"bake-period", doc.BakePeriod},
+					{"phase", doc.Phase},
+					{"started", doc.Started},
+				}},
+				{"$unset", bson.D{
+					{"bake-started", nil},
+					{"message", nil},
+				}},
+			},
+		})
+	}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.New("environment changed while starting the upgrade")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &AgentRollout{st: st, doc: doc}, nil
+}
+
+// agentRolloutCanaryStatus reports whether every agent on the given
+// canary machines runs the target version, and describes any problems
+// reported by those agents.
+func (st *State) agentRolloutCanaryStatus(canaries []string, target version.Number) (upgraded bool, problems []string, _ error) {
+	upgraded = true
+	isUpgraded := func(agent interface {
+		AgentTools() (*tools.Tools, error)
+	}) (bool, error) {
+		agentTools, err := agent.AgentTools()
+		if errors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		return agentTools.Version.Number == target, nil
+	}
+	for _, id := range canaries {
+		m, err := st.Machine(id)
+		if errors.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("machine %s has been removed", id))
+			continue
+		} else if err != nil {
+			return false, nil, errors.Trace(err)
+		}
+		if ok, err := isUpgraded(m); err != nil {
+			return false, nil, errors.Trace(err)
+		} else if !ok {
+			upgraded = false
+		}
+		status, err := m.Status()
+		if err != nil {
+			return false, nil, errors.Trace(err)
+		}
+		if status.Status == StatusError {
+			problems = append(problems, fmt.Sprintf("machine %s: %s", id, status.Message))
+		}
+		units, err := m.Units()
+		if err != nil {
+			return false, nil, errors.Trace(err)
+		}
+		for _, u := range units {
+			if ok, err := isUpgraded(u); err != nil {
+				return false, nil, errors.Trace(err)
+			} else if !ok {
+				upgraded = false
+			}
+			agentStatus, err := u.AgentStatus()
+			if err != nil {
+				return false, nil, errors.Trace(err)
+			}
+			if agentStatus.Status == StatusFailed || agentStatus.Status == StatusLost {
+				problems = append(problems, fmt.Sprintf("unit %s agent is %s", u.Name(), agentStatus.Status))
+			}
+			status, err := u.Status()
+			if err != nil {
+				return false, nil, errors.Trace(err)
+			}
+			if status.Status == StatusError {
+				problems = append(problems, fmt.Sprintf("unit %s: %s", u.Name(), status.Message))
+			}
+		}
+	}
+	return upgraded, problems, nil
+}
+
+// AdvanceAgentRollout moves the environment's staged agent upgrade, if
+// one is in progress, on to its next phase. The rollout is rolled back
+// if any canary agent reports a problem; it starts baking once every
+// canary agent runs the target version; and it is promoted once the
+// bake period has passed.
+func (st *State) AdvanceAgentRollout(now time.Time) (*AgentRollout, error) {
+	rollout, err := st.AgentRollout()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !rollout.InProgress() {
+		return rollout, nil
+	}
+	upgraded, problems, err := st.agentRolloutCanaryStatus(rollout.doc.Canaries, rollout.doc.TargetVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch {
+	case len(problems) > 0:
+		err = rollout.RollBack(strings.Join(problems, "; "))
+	case rollout.doc.Phase == AgentRolloutCanary && upgraded:
+		err = rollout.setPhase(AgentRolloutBaking, bson.D{{"bake-started", now.UTC()}})
+	case rollout.doc.Phase == AgentRolloutBaking && !now.Before(rollout.doc.BakeStarted.Add(rollout.doc.BakePeriod)):
+		err = rollout.Promote()
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rollout, nil
+}
+
+// Promote ends the rollout by setting the environment's agent version to
+// the target version, upgrading every agent.
+func (r *AgentRollout) Promote() error {
+	if !r.InProgress() {
+		return errors.Errorf("staged upgrade to %s is %s", r.doc.TargetVersion, r.doc.Phase)
+	}
+	if err := r.st.SetEnvironAgentVersion(r.doc.TargetVersion); err != nil {
+		return errors.Annotate(err, "cannot promote staged upgrade")
+	}
+	return r.setPhase(AgentRolloutPromoted, nil)
+}
+
+// RollBack ends the rollout without upgrading the rest of the
+// environment. The upgraded agents are told to return to the
+// environment's agent version.
+func (r *AgentRollout) RollBack(reason string) error {
+	if !r.InProgress() {
+		return errors.Errorf("staged upgrade to %s is %s", r.doc.TargetVersion, r.doc.Phase)
+	}
+	logger.Infof("rolling back staged upgrade to %s: %s", r.doc.TargetVersion, reason)
+	return r.setPhase(AgentRolloutRolledBack, bson.D{{"message", reason}})
+}
+
+// setPhase moves the rollout, which must be in progress, to the given
+// phase, setting the given fields as well.
+func (r *AgentRollout) setPhase(phase AgentRolloutPhase, fields bson.D) error {
+	set := append(bson.D{{"phase", phase}}, fields...)
+	ops := []txn.Op{{
+		C:  agentRolloutsC,
+		Id: r.doc.DocID,
+		Assert: bson.D{{"phase", bson.D{
+			{"$in", inProgressAgentRolloutPhases},
+		}}},
+		Update: bson.D{{"$set", set}},
+	}}
+	if err := r.st.runTransaction(ops); err == txn.ErrAborted {
+		return errors.New("staged upgrade has already finished")
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set staged upgrade phase to %q", phase)
+	}
+	return errors.Trace(r.Refresh())
+}
@@ -0,0 +1,226 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+type AgentRolloutSuite struct {
+	ConnSuite
+	current version.Number
+	target  version.Number
+	canary  *state.Machine
+	other   *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&AgentRolloutSuite{})
+
+func (s *AgentRolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	current, ok := envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	s.current = current
+	s.target = current
+	s.target.Patch++
+
+	s.canary, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	s.other, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.unit, err = wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.canary)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.setAgentVersions(c, s.current, s.canary, s.other)
+	s.setAgentVersions(c, s.current, s.unit)
+}
+
+func (s *AgentRolloutSuite) setAgentVersions(c *gc.C, vers version.Number, agents ...interface {
+	SetAgentVersion(version.Binary) error
+}) {
+	binary := version.Binary{Number: vers, Series: "quantal", Arch: "amd64"}
+	for _, agent := range agents {
+		err := agent.SetAgentVersion(binary)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *AgentRolloutSuite) assertAgentVersion(c *gc.C, expect version.Number) {
+	envConfig, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	agentVersion, ok := envConfig.AgentVersion()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(agentVersion, gc.Equals, expect)
+}
+
+func (s *AgentRolloutSuite) TestNoRollout(c *gc.C) {
+	_, err := s.State.AgentRollout()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *AgentRolloutSuite) TestStartAgentRollout(c *gc.C) {
+	rollout, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.PreviousVersion(), gc.Equals, s.current)
+	c.Assert(rollout.TargetVersion(), gc.Equals, s.target)
+	c.Assert(rollout.Canaries(), jc.DeepEquals, []string{s.canary.Id()})
+	c.Assert(rollout.BakePeriod(), gc.Equals, time.Hour)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutCanary)
+	c.Assert(rollout.InProgress(), jc.IsTrue)
+	c.Assert(rollout.IsCanary(s.canary.Id()), jc.IsTrue)
+	c.Assert(rollout.IsCanary(s.canary.Id()+"/lxc/0"), jc.IsTrue)
+	c.Assert(rollout.IsCanary(s.other.Id()), jc.IsFalse)
+
+	// The environment's agent version is unchanged.
+	s.assertAgentVersion(c, s.current)
+
+	stored, err := s.State.AgentRollout()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored.TargetVersion(), gc.Equals, s.target)
+	c.Assert(stored.Phase(), gc.Equals, state.AgentRolloutCanary)
+}
+
+func (s *AgentRolloutSuite) TestStartAgentRolloutErrors(c *gc.C) {
+	manager, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAgentVersions(c, s.current, manager)
+
+	_, err = s.State.StartAgentRollout(s.target, nil, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: no canary machines specified")
+	_, err = s.State.StartAgentRollout(s.target, []string{"42"}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: machine 42 not found")
+	_, err = s.State.StartAgentRollout(s.target, []string{manager.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: machine 2 is a state server; state servers are always upgraded first")
+	_, err = s.State.StartAgentRollout(s.current, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: target version must be newer than the current version .*")
+	nextMinor := s.current
+	nextMinor.Minor++
+	nextMinor.Patch = 0
+	_, err = s.State.StartAgentRollout(nextMinor, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: target version must have the same major and minor version as the current version .*")
+	nextMajor := s.current
+	nextMajor.Major++
+	_, err = s.State.StartAgentRollout(nextMajor, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: target version must have the same major and minor version as the current version .*")
+
+	s.setAgentVersions(c, s.target, s.other)
+	_, err = s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: some agents have not upgraded to the current environment version .*: machine-1")
+}
+
+func (s *AgentRolloutSuite) TestStartAgentRolloutUnhealthyCanary(c *gc.C) {
+	err := s.unit.SetAgentStatus(state.StatusError, "hook failed: install", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: canaries are unhealthy: unit wordpress/0: hook failed: install")
+}
+
+func (s *AgentRolloutSuite) TestStartAgentRolloutInProgress(c *gc.C) {
+	_, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.StartAgentRollout(s.target, []string{s.other.Id()}, time.Hour)
+	c.Assert(err, gc.ErrorMatches, ".*: staged upgrade to .* already exists")
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *AgentRolloutSuite) TestStartAgentRolloutAfterRollBack(c *gc.C) {
+	rollout, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rollout.RollBack("testing")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Message(), gc.Equals, "testing")
+
+	rollout, err = s.State.StartAgentRollout(s.target, []string{s.other.Id()}, time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rollout.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutCanary)
+	c.Assert(rollout.Canaries(), jc.DeepEquals, []string{s.other.Id()})
+	c.Assert(rollout.BakePeriod(), gc.Equals, time.Minute)
+	c.Assert(rollout.Message(), gc.Equals, "")
+}
+
+func (s *AgentRolloutSuite) TestAdvanceAgentRollout(c *gc.C) {
+	_, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	// Times are stored with limited precision.
+	now := time.Now().Truncate(time.Second)
+
+	// Nothing happens until every agent on the canary is upgraded.
+	s.setAgentVersions(c, s.target, s.canary)
+	rollout, err := s.State.AdvanceAgentRollout(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutCanary)
+
+	s.setAgentVersions(c, s.target, s.unit)
+	rollout, err = s.State.AdvanceAgentRollout(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutBaking)
+	c.Assert(rollout.BakeStarted().Equal(now), jc.IsTrue)
+
+	rollout, err = s.State.AdvanceAgentRollout(now.Add(59 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutBaking)
+	s.assertAgentVersion(c, s.current)
+
+	rollout, err = s.State.AdvanceAgentRollout(now.Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutPromoted)
+	c.Assert(rollout.InProgress(), jc.IsFalse)
+	s.assertAgentVersion(c, s.target)
+}
+
+func (s *AgentRolloutSuite) TestAdvanceAgentRolloutRollsBack(c *gc.C) {
+	_, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	s.setAgentVersions(c, s.target, s.canary)
+	s.setAgentVersions(c, s.target, s.unit)
+	_, err = s.State.AdvanceAgentRollout(time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.SetAgentStatus(state.StatusError, "hook failed: config-changed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rollout, err := s.State.AdvanceAgentRollout(time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutRolledBack)
+	c.Assert(rollout.Message(), gc.Equals, "unit wordpress/0: hook failed: config-changed")
+	s.assertAgentVersion(c, s.current)
+}
+
+func (s *AgentRolloutSuite) TestPromote(c *gc.C) {
+	rollout, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	err = rollout.Promote()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollout.Phase(), gc.Equals, state.AgentRolloutPromoted)
+	s.assertAgentVersion(c, s.target)
+
+	err = rollout.Promote()
+	c.Assert(err, gc.ErrorMatches, "staged upgrade to .* is promoted")
+	err = rollout.RollBack("too late")
+	c.Assert(err, gc.ErrorMatches, "staged upgrade to .* is promoted")
+}
+
+func (s *AgentRolloutSuite) TestSetEnvironAgentVersionDuringRollout(c *gc.C) {
+	_, err := s.State.StartAgentRollout(s.target, []string{s.canary.Id()}, time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.target
+	other.Patch++
+	err = s.State.SetEnvironAgentVersion(other)
+	c.Assert(err, gc.ErrorMatches, "a staged upgrade to .* is in progress; promote or roll it back first")
+	s.assertAgentVersion(c, s.current)
+}
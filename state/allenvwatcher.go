@@ -0,0 +1,181 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/watcher"
+)
+
+// AllEnvWatcher watches changes to the entities of every environment
+// in the system, tagging each change with its environment's UUID.
+// It runs a Multiwatcher for each live environment, starting and
+// stopping them as environments come and go.
+type AllEnvWatcher struct {
+	tomb     tomb.Tomb
+	st       *State
+	envs     map[string]*envMultiwatcher
+	deltas   chan []multiwatcher.EnvironDelta
+	requests chan chan []multiwatcher.EnvironDelta
+}
+
+// envMultiwatcher holds the Multiwatcher for a single environment.
+type envMultiwatcher struct {
+	st      *State
+	w       *Multiwatcher
+	removed chan struct{}
+}
+
+// WatchAllEnvs returns a watcher that reports changes to the entities
+// of all environments in the system. It should only be called on the
+// State for the state server environment.
+func (st *State) WatchAllEnvs() *AllEnvWatcher {
+	aw := &AllEnvWatcher{
+		st:       st,
+		envs:     make(map[string]*envMultiwatcher),
+		deltas:   make(chan []multiwatcher.EnvironDelta),
+		requests: make(chan chan []multiwatcher.EnvironDelta),
+	}
+	go func() {
+		defer aw.tomb.Done()
+		aw.tomb.Kill(aw.loop())
+	}()
+	return aw
+}
+
+// Next retrieves all changes that have happened since the last
+// time it was called, blocking until there are some changes available.
+func (aw *AllEnvWatcher) Next() ([]multiwatcher.EnvironDelta, error) {
+	reply := make(chan []multiwatcher.EnvironDelta, 1)
+	select {
+	case aw.requests <- reply:
+		return <-reply, nil
+	case <-aw.tomb.Dead():
+	}
+	err := aw.tomb.Err()
+	if err == nil {
+		err = errors.Trace(ErrStopped)
+	}
+	return nil, err
+}
+
+// Stop stops the watcher.
+func (aw *AllEnvWatcher) Stop() error {
+	aw.tomb.Kill(nil)
+	return aw.tomb.Wait()
+}
+
+func (aw *AllEnvWatcher) loop() error {
+	defer aw.stopAll()
+	envsWatcher := aw.st.WatchEnvironments()
+	defer watcher.Stop(envsWatcher, &aw.tomb)
+
+	var pending []multiwatcher.EnvironDelta
+	for {
+		// Only accept requests when there is something to send.
+		var requests chan chan []multiwatcher.EnvironDelta
+		if len(pending) > 0 {
+			requests = aw.requests
+		}
+		select {
+		case <-aw.tomb.Dying():
+			return tomb.ErrDying
+		case uuids, ok := <-envsWatcher.Changes():
+			if !ok {
+				return watcher.EnsureErr(envsWatcher)
+			}
+			for _, uuid := range uuids {
+				if err := aw.envChanged(uuid); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case deltas := <-aw.deltas:
+			pending = append(pending, deltas...)
+		case reply := <-requests:
+			reply <- pending
+			pending = nil
+		}
+	}
+}
+
+// envChanged starts or stops watching the given environment according
+// to its current life.
+func (aw *AllEnvWatcher) envChanged(uuid string) error {
+	env, err := aw.st.GetEnvironment(names.NewEnvironTag(uuid))
+	if errors.IsNotFound(err) || err == nil && env.Life() == Dead {
+		return aw.stopEnv(uuid)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := aw.envs[uuid]; ok {
+		return nil
+	}
+	envSt, err := aw.st.ForEnviron(env.EnvironTag())
+	if err != nil {
+		return errors.Annotatef(err, "cannot open environment %q", uuid)
+	}
+	ew := &envMultiwatcher{
+		st:      envSt,
+		w:       envSt.Watch(),
+		removed: make(chan struct{}),
+	}
+	aw.envs[uuid] = ew
+	go aw.watchEnv(uuid, ew)
+	return nil
+}
+
+// watchEnv forwards the changes reported by the environment's
+// Multiwatcher to the main loop.
+func (aw *AllEnvWatcher) watchEnv(uuid string, ew *envMultiwatcher) {
+	for {
+		deltas, err := ew.w.Next()
+		if err != nil {
+			select {
+			case <-ew.removed:
+			default:
+				aw.tomb.Kill(errors.Annotatef(err, "watching environment %q", uuid))
+			}
+			return
+		}
+		envDeltas := make([]multiwatcher.EnvironDelta, len(deltas))
+		for i, delta := range deltas {
+			envDeltas[i] = multiwatcher.EnvironDelta{
+				EnvUUID: uuid,
+				Delta:   delta,
+			}
+		}
+		select {
+		case aw.deltas <- envDeltas:
+		case <-aw.tomb.Dying():
+			return
+		case <-ew.removed:
+			return
+		}
+	}
+}
+
+func (aw *AllEnvWatcher) stopEnv(uuid string) error {
+	ew, ok := aw.envs[uuid]
+	if !ok {
+		return nil
+	}
+	delete(aw.envs, uuid)
+	close(ew.removed)
+	if err := ew.w.Stop(); err != nil {
+		logger.Warningf("cannot stop watcher for environment %q: %v", uuid, err)
+	}
+	return errors.Trace(ew.st.Close())
+}
+
+func (aw *AllEnvWatcher) stopAll() {
+	for uuid := range aw.envs {
+		if err := aw.stopEnv(uuid); err != nil {
+			logger.Warningf("cannot close state for environment %q: %v", uuid, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/testing"
+)
+
+type allEnvWatcherSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&allEnvWatcherSuite{})
+
+// machineIdsByEnv reads deltas from the watcher until machine changes
+// for the given number of environments have been seen.
+func machineIdsByEnv(c *gc.C, w *state.AllEnvWatcher, envCount int) map[string][]string {
+	result := make(map[string][]string)
+	timeout := time.After(testing.LongWait)
+	for len(result) < envCount {
+		done := make(chan []multiwatcher.EnvironDelta)
+		go func() {
+			deltas, err := w.Next()
+			c.Check(err, jc.ErrorIsNil)
+			done <- deltas
+		}()
+		select {
+		case deltas := <-done:
+			for _, d := range deltas {
+				if info, ok := d.Delta.Entity.(*multiwatcher.MachineInfo); ok {
+					result[d.EnvUUID] = append(result[d.EnvUUID], info.Id)
+				}
+			}
+		case <-timeout:
+			c.Fatalf("timed out waiting for machine changes")
+		}
+	}
+	return result
+}
+
+func (s *allEnvWatcherSuite) TestWatchAllEnvs(c *gc.C) {
+	s.factory.MakeMachine(c, nil)
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+	_, err := otherSt.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchAllEnvs()
+	defer func() {
+		c.Assert(w.Stop(), jc.ErrorIsNil)
+	}()
+
+	c.Assert(machineIdsByEnv(c, w, 2), jc.DeepEquals, map[string][]string{
+		s.State.EnvironUUID(): {"0"},
+		otherSt.EnvironUUID(): {"0"},
+	})
+
+	// Changes made after the initial deltas are also reported.
+	_, err = otherSt.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineIdsByEnv(c, w, 1), jc.DeepEquals, map[string][]string{
+		otherSt.EnvironUUID(): {"1"},
+	})
+}
+
+func (s *allEnvWatcherSuite) TestNextAfterStop(c *gc.C) {
+	w := s.State.WatchAllEnvs()
+	err := w.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Next()
+	c.Assert(err, gc.ErrorMatches, "watcher was stopped")
+}
+
+func (s *allEnvWatcherSuite) TestAllEnvironments(c *gc.C) {
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+
+	envs, err := s.State.AllEnvironments()
+	c.Assert(err, jc.ErrorIsNil)
+	var uuids []string
+	for _, env := range envs {
+		uuids = append(uuids, env.UUID())
+	}
+	c.Assert(uuids, jc.SameContents, []string{s.State.EnvironUUID(), otherSt.EnvironUUID()})
+}
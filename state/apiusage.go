@@ -0,0 +1,117 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// APIUsage holds the API calls made to an environment, and the size of
+// the messages exchanged by them.
+type APIUsage struct {
+	// EnvUUID holds the UUID of the environment.
+	EnvUUID string
+
+	// Calls holds the number of API calls made.
+	Calls int64
+
+	// BytesIn holds the size of the messages received by the API
+	// server.
+	BytesIn int64
+
+	// BytesOut holds the size of the messages sent by the API server.
+	BytesOut int64
+}
+
+// apiUsageDoc holds the API usage of an environment during one hour.
+// The totals are incremented in place by each API server, so they are
+// written directly rather than through transactions.
+type apiUsageDoc struct {
+	DocID    string    `bson:"_id"`
+	EnvUUID  string    `bson:"env-uuid"`
+	Hour     time.Time `bson:"hour"`
+	Calls    int64     `bson:"calls"`
+	BytesIn  int64     `bson:"bytes-in"`
+	BytesOut int64     `bson:"bytes-out"`
+}
+
+// AddAPIUsage adds the given API usage to the totals of the hour
+// holding the given time.
+func (st *State) AddAPIUsage(when time.Time, usage []APIUsage) error {
+	coll, closer := st.getRawCollection(apiUsageC)
+	defer closer()
+
+	hour := when.UTC().Truncate(time.Hour)
+	for _, u := range usage {
+		id := fmt.Sprintf("%s#%d", u.EnvUUID, hour.Unix())
+		_, err := coll.UpsertId(id, bson.D{
+			{"$set", bson.D{{"env-uuid", u.EnvUUID}, {"hour", hour}}},
+			{"$inc", bson.D{{"calls", u.Calls}, {"bytes-in", u.BytesIn}, {"bytes-out", u.BytesOut}}},
+		})
+		if err != nil {
+			return errors.Annotatef(err, "cannot add API usage of environment %q", u.EnvUUID)
+		}
+	}
+	return nil
+}
+
+// APIUsageSince returns the API usage of each environment during the
+// hours since the given time, starting with the hour that holds it.
+// The results are ordered by number of calls, busiest first.
+func (st *State) APIUsageSince(since time.Time) ([]APIUsage, error) {
+	coll, closer := st.getRawCollection(apiUsageC)
+	defer closer()
+
+	query := bson.D{{"hour", bson.D{{"$gte", since.UTC().Truncate(time.Hour)}}}}
+	var docs []apiUsageDoc
+	if err := coll.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get API usage")
+	}
+	totals := make(map[string]*APIUsage)
+	for _, doc := range docs {
+		total, ok := totals[doc.EnvUUID]
+		if !ok {
+			total = &APIUsage{EnvUUID: doc.EnvUUID}
+			totals[doc.EnvUUID] = total
+		}
+		total.Calls += doc.Calls
+		total.BytesIn += doc.BytesIn
+		total.BytesOut += doc.BytesOut
+	}
+	usage := make([]APIUsage, 0, len(totals))
+	for _, total := range totals {
+		usage = append(usage, *total)
+	}
+	sort.Sort(apiUsageByCalls(usage))
+	return usage, nil
+}
+
+// PruneAPIUsage removes the API usage recorded for hours that ended
+// before the given time.
+func (st *State) PruneAPIUsage(before time.Time) error {
+	coll, closer := st.getRawCollection(apiUsageC)
+	defer closer()
+
+	query := bson.D{{"hour", bson.D{{"$lt", before.UTC().Add(-time.Hour)}}}}
+	if _, err := coll.RemoveAll(query); err != nil {
+		return errors.Annotate(err, "cannot prune API usage")
+	}
+	return nil
+}
+
+type apiUsageByCalls []APIUsage
+
+func (u apiUsageByCalls) Len() int      { return len(u) }
+func (u apiUsageByCalls) Swap(i, j int) { u[i], u[j] = u[j], u[i] }
+func (u apiUsageByCalls) Less(i, j int) bool {
+	if u[i].Calls != u[j].Calls {
+		return u[i].Calls > u[j].Calls
+	}
+	return u[i].EnvUUID < u[j].EnvUUID
+}
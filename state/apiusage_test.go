@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type APIUsageSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&APIUsageSuite{})
+
+func (s *APIUsageSuite) TestAPIUsageSince(c *gc.C) {
+	now := time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC)
+	err := s.State.AddAPIUsage(now.Add(-2*time.Hour), []state.APIUsage{
+		{EnvUUID: "env-a", Calls: 100, BytesIn: 1000, BytesOut: 5000},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddAPIUsage(now.Add(-time.Hour), []state.APIUsage{
+		{EnvUUID: "env-a", Calls: 10, BytesIn: 100, BytesOut: 500},
+		{EnvUUID: "env-b", Calls: 20, BytesIn: 200, BytesOut: 1000},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// Usage added within the same hour is combined.
+	err = s.State.AddAPIUsage(now, []state.APIUsage{
+		{EnvUUID: "env-a", Calls: 1, BytesIn: 10, BytesOut: 50},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddAPIUsage(now.Add(time.Minute), []state.APIUsage{
+		{EnvUUID: "env-a", Calls: 2, BytesIn: 20, BytesOut: 100},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	usage, err := s.State.APIUsageSince(now.Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.APIUsage{
+		{EnvUUID: "env-b", Calls: 20, BytesIn: 200, BytesOut: 1000},
+		{EnvUUID: "env-a", Calls: 13, BytesIn: 130, BytesOut: 650},
+	})
+
+	usage, err = s.State.APIUsageSince(now.Add(-3 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.APIUsage{
+		{EnvUUID: "env-a", Calls: 113, BytesIn: 1130, BytesOut: 5650},
+		{EnvUUID: "env-b", Calls: 20, BytesIn: 200, BytesOut: 1000},
+	})
+}
+
+func (s *APIUsageSuite) TestAPIUsageSinceNone(c *gc.C) {
+	usage, err := s.State.APIUsageSince(time.Now().Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, gc.HasLen, 0)
+}
+
+func (s *APIUsageSuite) TestPruneAPIUsage(c *gc.C) {
+	now := time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC)
+	for _, when := range []time.Time{now.Add(-3 * time.Hour), now.Add(-time.Hour), now} {
+		err := s.State.AddAPIUsage(when, []state.APIUsage{{EnvUUID: "env-a", Calls: 1}})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The hour holding the given time is kept.
+	err := s.State.PruneAPIUsage(now.Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	usage, err := s.State.APIUsageSince(now.Add(-24 * time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, []state.APIUsage{{EnvUUID: "env-a", Calls: 2}})
+}
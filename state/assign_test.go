@@ -412,6 +412,41 @@ func (s *AssignSuite) TestAssignToNewMachineMakesDirty(c *gc.C) {
 	c.Assert(machine.Clean(), jc.IsFalse)
 }
 
+func (s *AssignSuite) TestAssignToNewMachineSetsDistributionGroup(c *gc.C) {
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	mid, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(mid)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.DistributionGroupName(), gc.Equals, "wordpress")
+}
+
+func (s *AssignSuite) TestAssignToMachineSetsDistributionGroup(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.DistributionGroupName(), gc.Equals, "")
+
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.DistributionGroupName(), gc.Equals, "wordpress")
+
+	// A later unit of another service does not change the group.
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	unit, err = mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.DistributionGroupName(), gc.Equals, "wordpress")
+}
+
 func (s *AssignSuite) TestAssignUnitToNewMachineSetsConstraints(c *gc.C) {
 	// Set up constraints.
 	scons := constraints.MustParse("mem=2G cpu-power=400")
@@ -0,0 +1,269 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+)
+
+// AssignmentStrategy assigns principal units to machines on behalf of
+// an AssignmentPolicy.
+type AssignmentStrategy interface {
+	// AssignUnit assigns the given principal unit to a machine,
+	// launching a new machine if necessary.
+	AssignUnit(st *State, u *Unit) error
+}
+
+// AssignmentStrategyFunc adapts a function to the AssignmentStrategy
+// interface.
+type AssignmentStrategyFunc func(st *State, u *Unit) error
+
+// AssignUnit is part of the AssignmentStrategy interface.
+func (f AssignmentStrategyFunc) AssignUnit(st *State, u *Unit) error {
+	return f(st, u)
+}
+
+var (
+	assignmentStrategiesMu sync.Mutex
+	assignmentStrategies   = make(map[AssignmentPolicy]AssignmentStrategy)
+)
+
+// RegisterAssignmentStrategy makes the given strategy available to
+// AssignUnit under the given policy, replacing any strategy previously
+// registered for that policy.
+func RegisterAssignmentStrategy(policy AssignmentPolicy, strategy AssignmentStrategy) {
+	assignmentStrategiesMu.Lock()
+	defer assignmentStrategiesMu.Unlock()
+	assignmentStrategies[policy] = strategy
+}
+
+func assignmentStrategy(policy AssignmentPolicy) (AssignmentStrategy, bool) {
+	assignmentStrategiesMu.Lock()
+	defer assignmentStrategiesMu.Unlock()
+	strategy, ok := assignmentStrategies[policy]
+	return strategy, ok
+}
+
+func init() {
+	RegisterAssignmentStrategy(AssignLocal, AssignmentStrategyFunc(assignLocal))
+	RegisterAssignmentStrategy(AssignClean, assignOrAddMachine((*Unit).AssignToCleanMachine))
+	RegisterAssignmentStrategy(AssignCleanEmpty, assignOrAddMachine((*Unit).AssignToCleanEmptyMachine))
+	RegisterAssignmentStrategy(AssignNew, AssignmentStrategyFunc(assignNew))
+	RegisterAssignmentStrategy(AssignPack, assignOrAddMachine((*Unit).AssignToPackedMachine))
+	RegisterAssignmentStrategy(AssignSpread, assignOrAddMachine((*Unit).AssignToCleanEmptyMachine))
+	RegisterAssignmentStrategy(AssignZoneBalanced, assignOrAddMachine((*Unit).AssignToZoneBalancedMachine))
+}
+
+func assignLocal(st *State, u *Unit) error {
+	m, err := st.Machine("0")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return u.AssignToMachine(m)
+}
+
+func assignNew(st *State, u *Unit) error {
+	return errors.Trace(u.AssignToNewMachine())
+}
+
+// assignOrAddMachine returns a strategy that assigns units to existing
+// machines with the given function, and to a new machine or container
+// when the function finds no eligible machine.
+func assignOrAddMachine(assign func(*Unit) (*Machine, error)) AssignmentStrategy {
+	return AssignmentStrategyFunc(func(st *State, u *Unit) error {
+		if _, err := assign(u); err != noCleanMachines {
+			return errors.Trace(err)
+		}
+		return u.AssignToNewMachineOrContainer()
+	})
+}
+
+// EnvironAssignmentPolicy returns the policy used to assign units that
+// are added without a placement directive, as selected by the
+// unit-placement environment setting.
+func (st *State) EnvironAssignmentPolicy() (AssignmentPolicy, error) {
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return AssignmentPolicy(cfg.UnitPlacement()), nil
+}
+
+// assignmentCandidates returns the machines that satisfy the unit's
+// constraints and that may be clean or empty as required. It returns
+// no machines if the unit has storage constraints, which can only be
+// satisfied by a new machine.
+func (u *Unit) assignmentCandidates(requireClean, requireEmpty bool) ([]*Machine, error) {
+	storageCons, err := u.StorageConstraints()
+	if err != nil {
+		return nil, err
+	}
+	if len(storageCons) > 0 {
+		return nil, nil
+	}
+	cons, err := u.Constraints()
+	if err != nil {
+		return nil, err
+	}
+	query, closer, err := u.findMachineQuery(requireClean, requireEmpty, cons)
+	if err != nil {
+		return nil, err
+	}
+	defer closer()
+	var mdocs []*machineDoc
+	if err := query.All(&mdocs); err != nil {
+		return nil, err
+	}
+	machines := make([]*Machine, len(mdocs))
+	for i, mdoc := range mdocs {
+		machines[i] = newMachine(u.st, mdoc)
+	}
+	return machines, nil
+}
+
+// AssignToPackedMachine assigns u to the existing machine hosting the
+// most principal units, other than machines already hosting a unit of
+// the same service. If there is no such machine besides any machine(s)
+// running JobHostEnviron, an error is returned.
+func (u *Unit) AssignToPackedMachine() (m *Machine, err error) {
+	const context = "packed machine"
+	candidates, err := u.assignmentCandidates(false, false)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	var machines []*Machine
+	for _, m := range candidates {
+		if !hostsServiceUnit(m, u.doc.Service) {
+			machines = append(machines, m)
+		}
+	}
+	sort.Stable(byPrincipalsDescending(machines))
+	for _, m := range machines {
+		err := u.assignToMachine(m, false)
+		if err == nil {
+			return m, nil
+		}
+		if err != machineNotAliveErr {
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+	}
+	return nil, noCleanMachines
+}
+
+// hostsServiceUnit reports whether the machine hosts a principal unit
+// of the named service.
+func hostsServiceUnit(m *Machine, service string) bool {
+	for _, name := range m.doc.Principals {
+		if strings.HasPrefix(name, service+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+type byPrincipalsDescending []*Machine
+
+func (m byPrincipalsDescending) Len() int      { return len(m) }
+func (m byPrincipalsDescending) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m byPrincipalsDescending) Less(i, j int) bool {
+	return len(m[i].doc.Principals) > len(m[j].doc.Principals)
+}
+
+// AssignToZoneBalancedMachine assigns u to a clean, empty machine in
+// the availability zone hosting the fewest units of the same service.
+// Machines without a known availability zone are only chosen when no
+// machine in a zone is available. If there are no clean, empty machines
+// besides any machine(s) running JobHostEnviron, an error is returned.
+func (u *Unit) AssignToZoneBalancedMachine() (m *Machine, err error) {
+	const context = "zone-balanced machine"
+	candidates, err := u.assignmentCandidates(true, true)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, noCleanMachines
+	}
+	unitsInZone, err := serviceUnitsByZone(u.st, u.doc.Service)
+	if err != nil {
+		assignContextf(&err, u, context)
+		return nil, err
+	}
+	machines := make([]zonedMachine, len(candidates))
+	for i, m := range candidates {
+		zone, err := m.AvailabilityZone()
+		if err != nil && !errors.IsNotProvisioned(err) {
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+		machines[i] = zonedMachine{m, zone, unitsInZone[zone]}
+	}
+	sort.Stable(byZoneUnits(machines))
+	for _, zm := range machines {
+		err := u.assignToMachine(zm.machine, true)
+		if err == nil {
+			return zm.machine, nil
+		}
+		if err != inUseErr && err != machineNotAliveErr {
+			assignContextf(&err, u, context)
+			return nil, err
+		}
+	}
+	return nil, noCleanMachines
+}
+
+// serviceUnitsByZone returns the number of units of the named service
+// assigned to machines in each availability zone.
+func serviceUnitsByZone(st *State, service string) (map[string]int, error) {
+	units, err := allUnits(st, service)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		machine, err := st.Machine(machineId)
+		if err != nil {
+			return nil, err
+		}
+		zone, err := machine.AvailabilityZone()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if zone != "" {
+			counts[zone]++
+		}
+	}
+	return counts, nil
+}
+
+type zonedMachine struct {
+	machine *Machine
+	zone    string
+	units   int
+}
+
+type byZoneUnits []zonedMachine
+
+func (m byZoneUnits) Len() int      { return len(m) }
+func (m byZoneUnits) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+func (m byZoneUnits) Less(i, j int) bool {
+	if (m[i].zone == "") != (m[j].zone == "") {
+		return m[j].zone == ""
+	}
+	return m[i].units < m[j].units
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+)
+
+type AssignmentStrategySuite struct {
+	ConnSuite
+	wordpress *state.Service
+	mysql     *state.Service
+}
+
+var _ = gc.Suite(&AssignmentStrategySuite{})
+
+func (s *AssignmentStrategySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.mysql = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	_, err := s.State.AddMachine("quantal", state.JobManageEnviron) // bootstrap machine
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *AssignmentStrategySuite) addMachine(c *gc.C, zone string) *state.Machine {
+	if zone == "" {
+		m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+		return m
+	}
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:     "quantal",
+		Jobs:       []state.MachineJob{state.JobHostUnits},
+		InstanceId: instance.Id("i-" + zone),
+		Nonce:      "fake_nonce",
+		HardwareCharacteristics: instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return m
+}
+
+func (s *AssignmentStrategySuite) assertAssigned(c *gc.C, svc *state.Service, policy state.AssignmentPolicy, expectId string) {
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, policy)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, expectId)
+}
+
+func (s *AssignmentStrategySuite) TestEnvironAssignmentPolicyDefault(c *gc.C) {
+	policy, err := s.State.EnvironAssignmentPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(policy, gc.Equals, state.AssignSpread)
+}
+
+func (s *AssignmentStrategySuite) TestEnvironAssignmentPolicy(c *gc.C) {
+	for _, policy := range []state.AssignmentPolicy{
+		state.AssignPack, state.AssignSpread, state.AssignZoneBalanced,
+	} {
+		err := s.State.UpdateEnvironConfig(map[string]interface{}{
+			"unit-placement": string(policy),
+		}, nil, nil)
+		c.Assert(err, jc.ErrorIsNil)
+		obtained, err := s.State.EnvironAssignmentPolicy()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(obtained, gc.Equals, policy)
+	}
+}
+
+func (s *AssignmentStrategySuite) TestRegisterAssignmentStrategy(c *gc.C) {
+	m := s.addMachine(c, "")
+	var called []string
+	state.RegisterAssignmentStrategy("test-strategy", state.AssignmentStrategyFunc(
+		func(st *state.State, u *state.Unit) error {
+			called = append(called, u.Name())
+			return u.AssignToMachine(m)
+		},
+	))
+	s.assertAssigned(c, s.wordpress, "test-strategy", m.Id())
+	c.Assert(called, jc.DeepEquals, []string{"wordpress/0"})
+}
+
+func (s *AssignmentStrategySuite) TestSpreadPolicy(c *gc.C) {
+	m1 := s.addMachine(c, "")
+	s.assertAssigned(c, s.mysql, state.AssignSpread, m1.Id())
+	// m1 is no longer clean, so a new machine is added.
+	s.assertAssigned(c, s.wordpress, state.AssignSpread, "2")
+	assertMachineCount(c, s.State, 3)
+}
+
+func (s *AssignmentStrategySuite) TestPackPolicy(c *gc.C) {
+	m1 := s.addMachine(c, "")
+	m2 := s.addMachine(c, "")
+	unit, err := s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m2)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The busiest machine is chosen first.
+	s.assertAssigned(c, s.wordpress, state.AssignPack, m2.Id())
+	// m2 hosts a wordpress unit now, so m1 is chosen next.
+	s.assertAssigned(c, s.wordpress, state.AssignPack, m1.Id())
+	// Both machines host a wordpress unit, so a new machine is added.
+	s.assertAssigned(c, s.wordpress, state.AssignPack, "3")
+	assertMachineCount(c, s.State, 4)
+	// The remaining service still packs onto the busiest machine.
+	s.assertAssigned(c, s.mysql, state.AssignPack, m1.Id())
+}
+
+func (s *AssignmentStrategySuite) TestPackPolicyIgnoresStateServers(c *gc.C) {
+	s.assertAssigned(c, s.wordpress, state.AssignPack, "1")
+	assertMachineCount(c, s.State, 2)
+}
+
+func (s *AssignmentStrategySuite) TestZoneBalancedPolicy(c *gc.C) {
+	m1 := s.addMachine(c, "zone-a")
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(m1)
+	c.Assert(err, jc.ErrorIsNil)
+	unprovisioned := s.addMachine(c, "")
+	// Dying machines are never chosen.
+	dying := s.addMachine(c, "zone-c")
+	err = dying.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	zoneB := s.addMachine(c, "zone-b")
+
+	// zone-b hosts no wordpress units, unlike zone-a.
+	s.assertAssigned(c, s.wordpress, state.AssignZoneBalanced, zoneB.Id())
+	// Unprovisioned machines are chosen once no machine in a zone is
+	// available.
+	s.assertAssigned(c, s.wordpress, state.AssignZoneBalanced, unprovisioned.Id())
+	// Then new machines are added.
+	s.assertAssigned(c, s.wordpress, state.AssignZoneBalanced, "5")
+}
+
+func (s *AssignmentStrategySuite) TestZoneBalancedPolicyPrefersEmptiestZone(c *gc.C) {
+	var machines []*state.Machine
+	for _, zone := range []string{"zone-a", "zone-b", "zone-c"} {
+		machines = append(machines, s.addMachine(c, zone))
+	}
+	for _, m := range machines[:2] {
+		unit, err := s.wordpress.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.AssignToMachine(m)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// Another machine in zone-a, which is busier than zone-c.
+	zone := "zone-a"
+	m, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:     "quantal",
+		Jobs:       []state.MachineJob{state.JobHostUnits},
+		InstanceId: "i-zone-a-2",
+		Nonce:      "fake_nonce",
+		HardwareCharacteristics: instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertAssigned(c, s.wordpress, state.AssignZoneBalanced, machines[2].Id())
+	s.assertAssigned(c, s.wordpress, state.AssignZoneBalanced, m.Id())
+}
+
+func (s *AssignmentStrategySuite) TestPlacementPoliciesRejectSubordinates(c *gc.C) {
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	eps, err := s.State.InferEndpoints("logging", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	subUnit, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	for _, policy := range []state.AssignmentPolicy{
+		state.AssignPack, state.AssignSpread, state.AssignZoneBalanced,
+	} {
+		err = s.State.AssignUnit(subUnit, policy)
+		c.Assert(err, gc.ErrorMatches, `subordinate unit "logging/0" cannot be assigned directly to a machine`)
+	}
+}
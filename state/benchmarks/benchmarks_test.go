@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package benchmarks_test
+
+import (
+	"fmt"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/benchmarks"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+var (
+	smallLoad = benchmarks.Load{Environments: 1, Machines: 10, Units: 10}
+	largeLoad = benchmarks.Load{Environments: 5, Machines: 50, Units: 100}
+)
+
+// churnInterval is the interval between the changes made in the
+// background while benchmarks run.
+const churnInterval = 5 * time.Millisecond
+
+type benchmarkSuite struct{}
+
+var _ = gc.Suite(&benchmarkSuite{})
+
+// setUp sets up a state suite for a benchmark, and populates it with
+// the given load. gocheck does not call fixture methods for benchmark
+// functions, so each benchmark must do this itself; the returned
+// function tears everything down again.
+func setUp(c *gc.C, load benchmarks.Load) (*statetesting.StateSuite, *benchmarks.Population, func()) {
+	s := &statetesting.StateSuite{}
+	s.SetUpSuite(c)
+	s.SetUpTest(c)
+	p := benchmarks.Populate(c, s.State, load)
+	c.Logf("populated %v", load)
+	return s, p, func() {
+		p.Close(c)
+		s.TearDownTest(c)
+		s.TearDownSuite(c)
+	}
+}
+
+// logTransactions logs the number of transactions run per operation
+// since the given transaction count was recorded.
+func logTransactions(c *gc.C, start uint64) {
+	if c.N > 0 {
+		c.Logf("%.2f transactions/op", float64(state.TransactionCount()-start)/float64(c.N))
+	}
+}
+
+func (*benchmarkSuite) BenchmarkAddMachineSmall(c *gc.C) { benchmarkAddMachine(c, smallLoad) }
+func (*benchmarkSuite) BenchmarkAddMachineLarge(c *gc.C) { benchmarkAddMachine(c, largeLoad) }
+
+func benchmarkAddMachine(c *gc.C, load benchmarks.Load) {
+	s, _, tearDown := setUp(c, load)
+	defer tearDown()
+	start := state.TransactionCount()
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.StopTimer()
+	logTransactions(c, start)
+}
+
+func (*benchmarkSuite) BenchmarkSetPasswordSmall(c *gc.C) { benchmarkSetPassword(c, smallLoad) }
+func (*benchmarkSuite) BenchmarkSetPasswordLarge(c *gc.C) { benchmarkSetPassword(c, largeLoad) }
+
+func benchmarkSetPassword(c *gc.C, load benchmarks.Load) {
+	s, p, tearDown := setUp(c, load)
+	defer tearDown()
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	churn := benchmarks.StartChurn(p, 0, churnInterval)
+	start := state.TransactionCount()
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		err := m.SetPassword(fmt.Sprintf("benchmark-password-%08d", i))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.StopTimer()
+	count, err := churn.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Logf("%d changes made by churn", count)
+	logTransactions(c, start)
+}
+
+func (*benchmarkSuite) BenchmarkWatcherLatencySmall(c *gc.C) { benchmarkWatcherLatency(c, smallLoad) }
+func (*benchmarkSuite) BenchmarkWatcherLatencyLarge(c *gc.C) { benchmarkWatcherLatency(c, largeLoad) }
+
+// benchmarkWatcherLatency measures the time between a change to a
+// machine and the delivery of the corresponding event to a watcher of
+// that machine, while other machines are changing.
+func benchmarkWatcherLatency(c *gc.C, load benchmarks.Load) {
+	s, p, tearDown := setUp(c, load)
+	defer tearDown()
+	m, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	w := m.Watch()
+	defer statetesting.AssertStop(c, w)
+	statetesting.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
+
+	churn := benchmarks.StartChurn(p, 0, churnInterval)
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		err := m.SetPassword(fmt.Sprintf("benchmark-password-%08d", i))
+		c.Assert(err, jc.ErrorIsNil)
+		s.State.StartSync()
+		select {
+		case _, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("watcher did not see change %d", i)
+		}
+	}
+	c.StopTimer()
+	_, err = churn.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package benchmarks provides reproducible load generators for state,
+// and the benchmarks that use them to measure transaction throughput
+// and watcher latency. Run the benchmarks with:
+//
+//	go test github.com/juju/juju/state/benchmarks -check.b
+package benchmarks
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+// Load describes the contents of the environments created by Populate.
+type Load struct {
+	// Environments holds the number of environments, including the
+	// initial one.
+	Environments int
+
+	// Machines holds the number of machines in each environment.
+	Machines int
+
+	// Units holds the number of units of a single service in each
+	// environment, assigned to the machines in turn.
+	Units int
+}
+
+// String returns a short description of the load, suitable for
+// logging.
+func (load Load) String() string {
+	return fmt.Sprintf("%d environments, %d machines, %d units", load.Environments, load.Machines, load.Units)
+}
+
+// Environment holds the entities created in one environment by
+// Populate.
+type Environment struct {
+	State    *state.State
+	Machines []*state.Machine
+	Units    []*state.Unit
+}
+
+// Population holds the environments created by Populate.
+type Population struct {
+	initial      *state.State
+	Environments []*Environment
+}
+
+// Populate creates the given load, starting with the environment of
+// the given state.
+func Populate(c *gc.C, st *state.State, load Load) *Population {
+	p := &Population{initial: st}
+	for i := 0; i < load.Environments; i++ {
+		envSt := st
+		if i > 0 {
+			envSt = factory.NewFactory(st).MakeEnvironment(c, nil)
+		}
+		p.Environments = append(p.Environments, populateEnvironment(c, envSt, load))
+	}
+	return p
+}
+
+func populateEnvironment(c *gc.C, st *state.State, load Load) *Environment {
+	f := factory.NewFactory(st)
+	env := &Environment{State: st}
+	for i := 0; i < load.Machines; i++ {
+		env.Machines = append(env.Machines, f.MakeMachine(c, nil))
+	}
+	if load.Units == 0 {
+		return env
+	}
+	service := f.MakeService(c, nil)
+	for i := 0; i < load.Units; i++ {
+		params := &factory.UnitParams{Service: service}
+		if len(env.Machines) > 0 {
+			params.Machine = env.Machines[i%len(env.Machines)]
+		}
+		env.Units = append(env.Units, f.MakeUnit(c, params))
+	}
+	return env
+}
+
+// Close closes the states opened by Populate for the environments
+// after the initial one.
+func (p *Population) Close(c *gc.C) {
+	for _, env := range p.Environments {
+		if env.State != p.initial {
+			c.Check(env.State.Close(), jc.ErrorIsNil)
+		}
+	}
+}
+
+// machines returns all the machines in the population.
+func (p *Population) machines() []*state.Machine {
+	var machines []*state.Machine
+	for _, env := range p.Environments {
+		machines = append(machines, env.Machines...)
+	}
+	return machines
+}
+
+// Churn changes the machines of a population in the background, so
+// that watchers have other events to sift through.
+type Churn struct {
+	tomb    tomb.Tomb
+	changes chan int
+}
+
+// StartChurn starts changing the passwords of machines in the given
+// population, chosen with a random source seeded with seed, once every
+// interval. The population must have at least one machine.
+func StartChurn(p *Population, seed int64, interval time.Duration) *Churn {
+	ch := &Churn{changes: make(chan int, 1)}
+	machines := p.machines()
+	go func() {
+		defer ch.tomb.Done()
+		ch.tomb.Kill(ch.loop(machines, rand.New(rand.NewSource(seed)), interval))
+	}()
+	return ch
+}
+
+func (ch *Churn) loop(machines []*state.Machine, source *rand.Rand, interval time.Duration) error {
+	count := 0
+	defer func() {
+		ch.changes <- count
+	}()
+	for {
+		select {
+		case <-ch.tomb.Dying():
+			return nil
+		case <-time.After(interval):
+		}
+		m := machines[source.Intn(len(machines))]
+		if err := m.SetPassword(fmt.Sprintf("churn-password-%08d", count)); err != nil {
+			return err
+		}
+		count++
+	}
+}
+
+// Stop stops the churn, returning the number of changes made.
+func (ch *Churn) Stop() (int, error) {
+	ch.tomb.Kill(nil)
+	err := ch.tomb.Wait()
+	return <-ch.changes, err
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package benchmarks_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/benchmarks"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type loadSuite struct {
+	statetesting.StateSuite
+}
+
+var _ = gc.Suite(&loadSuite{})
+
+func (s *loadSuite) TestPopulate(c *gc.C) {
+	load := benchmarks.Load{Environments: 2, Machines: 3, Units: 4}
+	p := benchmarks.Populate(c, s.State, load)
+	defer p.Close(c)
+
+	c.Assert(p.Environments, gc.HasLen, 2)
+	c.Assert(p.Environments[0].State, gc.Equals, s.State)
+	uuids := make(map[string]bool)
+	for _, env := range p.Environments {
+		uuids[env.State.EnvironUUID()] = true
+		machines, err := env.State.AllMachines()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(machines, gc.HasLen, 3)
+		c.Assert(env.Machines, gc.HasLen, 3)
+		c.Assert(env.Units, gc.HasLen, 4)
+		for i, unit := range env.Units {
+			id, err := unit.AssignedMachineId()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(id, gc.Equals, env.Machines[i%3].Id())
+		}
+	}
+	c.Assert(uuids, gc.HasLen, 2)
+}
+
+func (s *loadSuite) TestPopulateWithoutUnits(c *gc.C) {
+	p := benchmarks.Populate(c, s.State, benchmarks.Load{Environments: 1, Machines: 2})
+	defer p.Close(c)
+
+	c.Assert(p.Environments, gc.HasLen, 1)
+	c.Assert(p.Environments[0].Machines, gc.HasLen, 2)
+	c.Assert(p.Environments[0].Units, gc.HasLen, 0)
+	services, err := s.State.AllServices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 0)
+}
+
+func (s *loadSuite) TestLoadString(c *gc.C) {
+	load := benchmarks.Load{Environments: 1, Machines: 2, Units: 3}
+	c.Assert(load.String(), gc.Equals, "1 environments, 2 machines, 3 units")
+}
+
+func (s *loadSuite) TestChurn(c *gc.C) {
+	p := benchmarks.Populate(c, s.State, benchmarks.Load{Environments: 1, Machines: 2})
+	defer p.Close(c)
+
+	w := p.Environments[0].Machines[0].Watch()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	churn := benchmarks.StartChurn(p, 0, time.Millisecond)
+	timeout := time.After(coretesting.LongWait)
+	for changed := false; !changed; {
+		s.State.StartSync()
+		select {
+		case _, ok := <-w.Changes():
+			c.Assert(ok, jc.IsTrue)
+			changed = true
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("churn did not change the machine")
+		}
+	}
+	count, err := churn.Stop()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count > 0, jc.IsTrue)
+}
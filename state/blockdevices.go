@@ -4,6 +4,8 @@
 package state
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
@@ -93,6 +95,65 @@ func setMachineBlockDevices(st *State, machineId string, newInfo []BlockDeviceIn
 	return st.run(buildTxn)
 }
 
+// setProvisionedBlockDevices records the block devices corresponding
+// to volumes attached to a machine when its instance is provisioned,
+// so that storage created along with the instance (as on MAAS) can be
+// matched to block devices before the machine agent reports them.
+// Nothing is recorded if the machine's block devices have already been
+// reported.
+func setProvisionedBlockDevices(
+	st *State, machineId string,
+	volumes map[names.VolumeTag]VolumeInfo,
+	volumeAttachments map[names.VolumeTag]VolumeAttachmentInfo,
+) error {
+	tags := make([]string, 0, len(volumeAttachments))
+	for tag := range volumeAttachments {
+		tags = append(tags, tag.Id())
+	}
+	sort.Strings(tags)
+	var newInfo []BlockDeviceInfo
+	for _, id := range tags {
+		tag := names.NewVolumeTag(id)
+		volumeInfo, ok := volumes[tag]
+		if !ok {
+			continue
+		}
+		attachmentInfo := volumeAttachments[tag]
+		if volumeInfo.HardwareId == "" && attachmentInfo.DeviceName == "" {
+			// There is nothing with which to identify the device.
+			continue
+		}
+		newInfo = append(newInfo, BlockDeviceInfo{
+			DeviceName: attachmentInfo.DeviceName,
+			HardwareId: volumeInfo.HardwareId,
+			Size:       volumeInfo.Size,
+		})
+	}
+	if len(newInfo) == 0 {
+		return nil
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		oldInfo, err := st.blockDevices(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(oldInfo) > 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     machineId,
+			Assert: isAliveDoc,
+		}, {
+			C:      blockDevicesC,
+			Id:     machineId,
+			Assert: bson.D{{"blockdevices", oldInfo}},
+			Update: bson.D{{"$set", bson.D{{"blockdevices", newInfo}}}},
+		}}, nil
+	}
+	return st.run(buildTxn)
+}
+
 func createMachineBlockDevicesOp(machineId string) txn.Op {
 	return txn.Op{
 		C:      blockDevicesC,
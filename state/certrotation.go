@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const serverCertRotationKey = "serverCertRotation"
+
+// serverCertRotationDoc records requests for the state servers to
+// rotate their server certificates.
+type serverCertRotationDoc struct {
+	// Serial is incremented each time a rotation is requested.
+	Serial int `bson:"serial"`
+
+	// Requested holds the time of the most recent request.
+	Requested time.Time `bson:"requested"`
+}
+
+// ServerCertRotation returns the serial number of the most recent
+// request to rotate the state server certificates, or zero if no
+// rotation has ever been requested.
+func (st *State) ServerCertRotation() (int, error) {
+	doc, err := st.serverCertRotation()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return doc.Serial, nil
+}
+
+func (st *State) serverCertRotation() (serverCertRotationDoc, error) {
+	stateServers, closer := st.getCollection(stateServersC)
+	defer closer()
+
+	var doc serverCertRotationDoc
+	err := stateServers.Find(bson.D{{"_id", serverCertRotationKey}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return serverCertRotationDoc{}, nil
+	} else if err != nil {
+		return doc, errors.Annotate(err, "cannot read server certificate rotation")
+	}
+	return doc, nil
+}
+
+// RotateServerCert requests that every state server generate a new
+// server certificate, signed by the existing CA, and start serving it.
+// It returns the serial number of the request.
+func (st *State) RotateServerCert() (int, error) {
+	var serial int
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := st.serverCertRotation()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		serial = doc.Serial + 1
+		newDoc := serverCertRotationDoc{
+			Serial:    serial,
+			Requested: time.Now(),
+		}
+		if doc.Serial == 0 {
+			return []txn.Op{{
+				C:      stateServersC,
+				Id:     serverCertRotationKey,
+				Assert: txn.DocMissing,
+				Insert: &newDoc,
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      stateServersC,
+			Id:     serverCertRotationKey,
+			Assert: bson.D{{"serial", doc.Serial}},
+			Update: bson.D{{"$set", newDoc}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err == jujutxn.ErrExcessiveContention {
+		return 0, errors.New("cannot request server certificate rotation: state changing too quickly; try again soon")
+	} else if err != nil {
+		return 0, errors.Annotate(err, "cannot request server certificate rotation")
+	}
+	return serial, nil
+}
+
+// WatchServerCertRotation returns a NotifyWatcher that notifies when
+// a rotation of the state server certificates is requested.
+func (st *State) WatchServerCertRotation() NotifyWatcher {
+	return newEntityWatcher(st, stateServersC, serverCertRotationKey)
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type certRotationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&certRotationSuite{})
+
+func (s *certRotationSuite) TestServerCertRotationNeverRequested(c *gc.C) {
+	serial, err := s.State.ServerCertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 0)
+}
+
+func (s *certRotationSuite) TestRotateServerCert(c *gc.C) {
+	serial, err := s.State.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 1)
+
+	serial, err = s.State.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 2)
+
+	serial, err = s.State.ServerCertRotation()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(serial, gc.Equals, 2)
+}
+
+func (s *certRotationSuite) TestWatchServerCertRotation(c *gc.C) {
+	w := s.State.WatchServerCertRotation()
+	defer statetesting.AssertStop(c, w)
+
+	// Initial event.
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	_, err := s.State.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	_, err = s.State.RotateServerCert()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
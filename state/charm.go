@@ -20,6 +20,10 @@ type charmDoc struct {
 	Actions *charm.Actions
 	Metrics *charm.Metrics
 
+	// ConfigRenames holds the config option renames provided by the
+	// charm, sorted by old option name.
+	ConfigRenames []configRenameDoc `bson:"configrenames,omitempty"`
+
 	// DEPRECATED: BundleURL is deprecated, and exists here
 	// only for migration purposes. We should remove this
 	// when migrations are no longer necessary.
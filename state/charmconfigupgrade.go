@@ -0,0 +1,189 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v5"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// CharmConfigRenamesFile is the name of the file in which a charm may
+// map the names of config options removed or renamed since an earlier
+// revision of the charm to the names of the options that replace them.
+// The file holds a YAML map of old option names to new ones.
+const CharmConfigRenamesFile = "config-renames.yaml"
+
+// configRenameDoc records that the config option named Old in earlier
+// revisions of a charm is replaced by the option named New.
+type configRenameDoc struct {
+	Old string `bson:"old"`
+	New string `bson:"new"`
+}
+
+// readCharmConfigRenames returns the config option renames provided by
+// the given charm, sorted by old option name. Only charm directories
+// and archives can provide renames.
+func readCharmConfigRenames(ch charm.Charm) ([]configRenameDoc, error) {
+	var data []byte
+	var err error
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		data, err = ioutil.ReadFile(filepath.Join(ch.Path, CharmConfigRenamesFile))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+	case *charm.CharmArchive:
+		data, err = readArchiveFile(ch.Path, CharmConfigRenamesFile)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot read %s", CharmConfigRenamesFile)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var renames map[string]string
+	if err := goyaml.Unmarshal(data, &renames); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %s", CharmConfigRenamesFile)
+	}
+	options := ch.Config().Options
+	docs := make([]configRenameDoc, 0, len(renames))
+	for oldName, newName := range renames {
+		if _, ok := options[newName]; !ok {
+			return nil, errors.Errorf("config option %q renamed to unknown option %q", oldName, newName)
+		}
+		if _, ok := options[oldName]; ok {
+			return nil, errors.Errorf("config option %q renamed to %q but still defined", oldName, newName)
+		}
+		docs = append(docs, configRenameDoc{Old: oldName, New: newName})
+	}
+	sort.Sort(configRenameDocsByOld(docs))
+	return docs, nil
+}
+
+// readArchiveFile returns the contents of the named file in the zip
+// archive at the given path, or nil if the archive holds no such file.
+func readArchiveFile(path, name string) ([]byte, error) {
+	zipr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer zipr.Close()
+	for _, f := range zipr.File {
+		if filepath.Clean(f.Name) != name {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer r.Close()
+		return ioutil.ReadAll(r)
+	}
+	return nil, nil
+}
+
+type configRenameDocsByOld []configRenameDoc
+
+func (d configRenameDocsByOld) Len() int           { return len(d) }
+func (d configRenameDocsByOld) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d configRenameDocsByOld) Less(i, j int) bool { return d[i].Old < d[j].Old }
+
+// ConfigRenames returns the names of config options of earlier
+// revisions of the charm, mapped to the names of the options of this
+// revision that replace them.
+func (c *Charm) ConfigRenames() map[string]string {
+	if len(c.doc.ConfigRenames) == 0 {
+		return nil
+	}
+	renames := make(map[string]string)
+	for _, rename := range c.doc.ConfigRenames {
+		renames[rename.Old] = rename.New
+	}
+	return renames
+}
+
+const (
+	// ConfigRemoved indicates that a setting was dropped because the
+	// new charm has no option with its name, nor a replacement for it.
+	ConfigRemoved = "removed"
+
+	// ConfigInvalid indicates that a setting was dropped because its
+	// value is not valid for the new charm's option of the same name,
+	// or for the option that replaces it.
+	ConfigInvalid = "invalid"
+)
+
+// UnmappedConfigSetting describes a configuration setting of a service
+// that could not be carried over when the service's charm was changed.
+type UnmappedConfigSetting struct {
+	// Key holds the name of the setting under the old charm.
+	Key string `bson:"key"`
+
+	// Value holds the value of the setting under the old charm.
+	Value interface{} `bson:"value"`
+
+	// Reason holds why the setting was dropped; it is either
+	// ConfigRemoved or ConfigInvalid.
+	Reason string `bson:"reason"`
+}
+
+// UnmappedConfig returns the configuration settings of the service
+// that could not be carried over when its charm was last changed,
+// sorted by key.
+func (s *Service) UnmappedConfig() []UnmappedConfigSetting {
+	return s.doc.UnmappedConfig
+}
+
+// remapConfigSettings returns the settings that carry over from the
+// given settings of a service's old charm to the new charm, using the
+// new charm's config option renames, and the settings that do not.
+// A renamed setting never overrides a setting of the new name.
+func remapConfigSettings(ch *Charm, oldSettings map[string]interface{}) (charm.Settings, []UnmappedConfigSetting) {
+	options := ch.Config().Options
+	renames := ch.ConfigRenames()
+	mapped := make(charm.Settings)
+	sources := make(map[string]string)
+	var unmapped []UnmappedConfigSetting
+	for key, value := range oldSettings {
+		if _, ok := options[key]; ok {
+			mapped[key] = value
+			sources[key] = key
+			continue
+		}
+		newKey, ok := renames[key]
+		if !ok {
+			unmapped = append(unmapped, UnmappedConfigSetting{key, value, ConfigRemoved})
+			continue
+		}
+		if _, ok := oldSettings[newKey]; ok {
+			unmapped = append(unmapped, UnmappedConfigSetting{key, value, ConfigRemoved})
+			continue
+		}
+		mapped[newKey] = value
+		sources[newKey] = key
+	}
+	settings := ch.Config().FilterSettings(mapped)
+	for key, value := range mapped {
+		if _, ok := settings[key]; !ok && value != nil {
+			unmapped = append(unmapped, UnmappedConfigSetting{sources[key], value, ConfigInvalid})
+		}
+	}
+	sort.Sort(unmappedConfigByKey(unmapped))
+	return settings, unmapped
+}
+
+type unmappedConfigByKey []UnmappedConfigSetting
+
+func (u unmappedConfigByKey) Len() int           { return len(u) }
+func (u unmappedConfigByKey) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u unmappedConfigByKey) Less(i, j int) bool { return u[i].Key < u[j].Key }
@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+)
+
+type CharmConfigUpgradeSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CharmConfigUpgradeSuite{})
+
+var oldRenameConfig = `
+options:
+  title: {default: My Title, description: Desc, type: string}
+  colour: {default: red, description: Desc, type: string}
+  size: {default: 1, description: Desc, type: int}
+  legacy: {default: "", description: Desc, type: string}
+`
+
+var newRenameConfig = `
+options:
+  blog-title: {default: My Title, description: Desc, type: string}
+  color: {default: red, description: Desc, type: string}
+  size: {default: small, description: Desc, type: string}
+`
+
+// addRenameCharm clones the wordpress testing charm, replaces its
+// config with the given YAML and its config renames with those given,
+// and adds it to the state using the given revision.
+func (s *CharmConfigUpgradeSuite) addRenameCharm(c *gc.C, configYaml, renamesYaml string, revision int) *state.Charm {
+	path := testcharms.Repo.ClonedDirPath(c.MkDir(), "wordpress")
+	err := ioutil.WriteFile(filepath.Join(path, "config.yaml"), []byte(configYaml), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	if renamesYaml != "" {
+		err = ioutil.WriteFile(filepath.Join(path, state.CharmConfigRenamesFile), []byte(renamesYaml), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	ch, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	ch.SetRevision(revision)
+	curl := charm.MustParseURL(fmt.Sprintf("local:quantal/quantal-wordpress-%d", revision))
+	sch, err := s.State.AddCharm(ch, curl, "dummy-path", fmt.Sprintf("wordpress-%d-sha256", revision))
+	c.Assert(err, jc.ErrorIsNil)
+	return sch
+}
+
+func (s *CharmConfigUpgradeSuite) TestConfigRenames(c *gc.C) {
+	ch := s.addRenameCharm(c, newRenameConfig, "title: blog-title\ncolour: color\n", 2)
+	c.Assert(ch.ConfigRenames(), jc.DeepEquals, map[string]string{
+		"title":  "blog-title",
+		"colour": "color",
+	})
+
+	ch, err := s.State.Charm(ch.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.ConfigRenames(), jc.DeepEquals, map[string]string{
+		"title":  "blog-title",
+		"colour": "color",
+	})
+
+	ch = s.addRenameCharm(c, newRenameConfig, "", 3)
+	c.Assert(ch.ConfigRenames(), gc.HasLen, 0)
+}
+
+func (s *CharmConfigUpgradeSuite) TestConfigRenamesInvalid(c *gc.C) {
+	path := testcharms.Repo.ClonedDirPath(c.MkDir(), "wordpress")
+	err := ioutil.WriteFile(filepath.Join(path, "config.yaml"), []byte(newRenameConfig), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(path, state.CharmConfigRenamesFile), []byte("title: headline\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	ch, err := charm.ReadCharmDir(path)
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:quantal/quantal-wordpress-2")
+	_, err = s.State.AddCharm(ch, curl, "dummy-path", "wordpress-2-sha256")
+	c.Assert(err, gc.ErrorMatches, `cannot add charm "local:quantal/quantal-wordpress-2": config option "title" renamed to unknown option "headline"`)
+}
+
+func (s *CharmConfigUpgradeSuite) TestSetCharmRemapsConfig(c *gc.C) {
+	oldCh := s.addRenameCharm(c, oldRenameConfig, "", 1)
+	svc := s.AddTestingService(c, "wordpress", oldCh)
+	err := svc.UpdateConfigSettings(charm.Settings{
+		"title":  "Rhubarb",
+		"colour": "blue",
+		"size":   5,
+		"legacy": "obsolete",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newCh := s.addRenameCharm(c, newRenameConfig, "title: blog-title\ncolour: color\n", 2)
+	err = svc.SetCharm(newCh, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{
+		"blog-title": "Rhubarb",
+		"color":      "blue",
+	})
+	expectUnmapped := []state.UnmappedConfigSetting{
+		{Key: "legacy", Value: "obsolete", Reason: state.ConfigRemoved},
+		{Key: "size", Value: int64(5), Reason: state.ConfigInvalid},
+	}
+	c.Assert(svc.UnmappedConfig(), jc.DeepEquals, expectUnmapped)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.UnmappedConfig(), jc.DeepEquals, expectUnmapped)
+
+	// The report is replaced on the next upgrade.
+	nextCh := s.addRenameCharm(c, newRenameConfig, "", 3)
+	err = svc.SetCharm(nextCh, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.UnmappedConfig(), gc.HasLen, 0)
+	err = svc.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(svc.UnmappedConfig(), gc.HasLen, 0)
+}
+
+func (s *CharmConfigUpgradeSuite) TestSetCharmRenameDoesNotOverride(c *gc.C) {
+	oldCh := s.addRenameCharm(c, oldRenameConfig+"  color: {default: red, description: Desc, type: string}\n", "", 1)
+	svc := s.AddTestingService(c, "wordpress", oldCh)
+	err := svc.UpdateConfigSettings(charm.Settings{
+		"colour": "blue",
+		"color":  "green",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	newCh := s.addRenameCharm(c, newRenameConfig, "colour: color\n", 2)
+	err = svc.SetCharm(newCh, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := svc.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"color": "green"})
+	c.Assert(svc.UnmappedConfig(), jc.DeepEquals, []state.UnmappedConfigSetting{
+		{Key: "colour", Value: "blue", Reason: state.ConfigRemoved},
+	})
+}
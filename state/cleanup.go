@@ -6,9 +6,11 @@ package state
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 )
@@ -24,8 +26,14 @@ const (
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
 	cleanupAttachmentsForDyingStorage  cleanupKind = "storageAttachments"
+	cleanupEmptyMachine                cleanupKind = "emptyMachine"
+	cleanupScheduledMachineRemoval     cleanupKind = "scheduledMachine"
 )
 
+// cleanupNow returns the current time; it is a variable so that tests
+// can move time forward.
+var cleanupNow = time.Now
+
 // cleanupDoc represents a potentially large set of documents that should be
 // removed.
 type cleanupDoc struct {
@@ -33,6 +41,16 @@ type cleanupDoc struct {
 	EnvUUID string `bson:"env-uuid"`
 	Kind    cleanupKind
 	Prefix  string
+	// Due, if set, is the time before which the cleanup is not run.
+	Due time.Time `bson:",omitempty"`
+}
+
+// dueCleanups selects the cleanups that can be run now.
+func dueCleanups() bson.D {
+	return bson.D{{"$or", []bson.D{
+		{{"due", bson.D{{"$exists", false}}}},
+		{{"due", bson.D{{"$lte", cleanupNow()}}}},
+	}}}
 }
 
 // newCleanupOp returns a txn.Op that creates a cleanup document with a unique
@@ -51,11 +69,28 @@ func (st *State) newCleanupOp(kind cleanupKind, prefix string) txn.Op {
 	}
 }
 
-// NeedsCleanup returns true if documents previously marked for removal exist.
+// NextScheduledCleanup returns the time at which the earliest cleanup
+// scheduled for later falls due. It returns an error satisfying
+// errors.IsNotFound if there is none.
+func (st *State) NextScheduledCleanup() (time.Time, error) {
+	cleanups, closer := st.getCollection(cleanupsC)
+	defer closer()
+	var doc cleanupDoc
+	err := cleanups.Find(bson.D{{"due", bson.D{{"$exists", true}}}}).Sort("due").One(&doc)
+	if err == mgo.ErrNotFound {
+		return time.Time{}, errors.NotFoundf("scheduled cleanup")
+	} else if err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot read scheduled cleanups")
+	}
+	return doc.Due, nil
+}
+
+// NeedsCleanup returns true if documents previously marked for removal exist,
+// ignoring cleanups scheduled for later.
 func (st *State) NeedsCleanup() (bool, error) {
 	cleanups, closer := st.getCollection(cleanupsC)
 	defer closer()
-	count, err := cleanups.Count()
+	count, err := cleanups.Find(dueCleanups()).Count()
 	if err != nil {
 		return false, err
 	}
@@ -64,12 +99,12 @@ func (st *State) NeedsCleanup() (bool, error) {
 
 // Cleanup removes all documents that were previously marked for removal, if
 // any such exist. It should be called periodically by at least one element
-// of the system.
+// of the system. Cleanups scheduled for later are left until they are due.
 func (st *State) Cleanup() error {
 	var doc cleanupDoc
 	cleanups, closer := st.getCollection(cleanupsC)
 	defer closer()
-	iter := cleanups.Find(nil).Iter()
+	iter := cleanups.Find(dueCleanups()).Iter()
 	for iter.Next(&doc) {
 		var err error
 		logger.Debugf("running %q cleanup: %q", doc.Kind, doc.Prefix)
@@ -88,6 +123,10 @@ func (st *State) Cleanup() error {
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
 		case cleanupAttachmentsForDyingStorage:
 			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
+		case cleanupEmptyMachine:
+			err = st.cleanupEmptyMachine(doc.Prefix)
+		case cleanupScheduledMachineRemoval:
+			err = st.cleanupScheduledMachineRemoval(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -114,14 +153,16 @@ func (st *State) cleanupRelationSettings(prefix string) error {
 	// Documents marked for cleanup are not otherwise referenced in the
 	// system, and will not be under watch, and are therefore safe to
 	// delete directly.
-	settings, closer := st.getCollection(settingsC)
-	defer closer()
 	sel := bson.D{{"_id", bson.D{{"$regex", "^" + st.docID(prefix)}}}}
-	if count, err := settings.Find(sel).Count(); err != nil {
-		return fmt.Errorf("cannot detect cleanup targets: %v", err)
-	} else if count != 0 {
-		if _, err := settings.RemoveAll(sel); err != nil {
-			return fmt.Errorf("cannot remove documents marked for cleanup: %v", err)
+	for _, name := range []string{settingsC, relationSettingsSnapshotsC, relationServiceSettingsC} {
+		coll, closer := st.getCollection(name)
+		defer closer()
+		if count, err := coll.Find(sel).Count(); err != nil {
+			return fmt.Errorf("cannot detect cleanup targets: %v", err)
+		} else if count != 0 {
+			if _, err := coll.RemoveAll(sel); err != nil {
+				return fmt.Errorf("cannot remove documents marked for cleanup: %v", err)
+			}
 		}
 	}
 	return nil
@@ -243,10 +284,88 @@ func (st *State) cleanupRemovedUnit(unitId string) error {
 	return nil
 }
 
+// cleanupEmptyMachine destroys the supplied machine, whose last container
+// has been removed after being left empty itself, unless the machine
+// hosts units or other containers, is manually provisioned, or is
+// required by the environment. If the machine is itself a container, its
+// host is considered in turn once it is removed.
+func (st *State) cleanupEmptyMachine(machineId string) error {
+	machine, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if machine.Life() != Alive || len(machine.doc.Principals) > 0 {
+		return nil
+	}
+	if hasJob(machine.doc.Jobs, JobManageEnviron) || machine.doc.HasVote {
+		return nil
+	}
+	if manual, err := machine.IsManual(); err != nil {
+		return err
+	} else if manual {
+		logger.Debugf("not destroying empty machine %s: it was manually provisioned", machineId)
+		return nil
+	}
+	if containers, err := machine.Containers(); err != nil {
+		return err
+	} else if len(containers) > 0 {
+		return nil
+	}
+	if machine.IsContainer() {
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     machine.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"removeemptyparent", true}}}},
+		}}
+		if err := st.runTransaction(ops); err == txn.ErrAborted {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	err = machine.Destroy()
+	if IsHasAssignedUnitsError(err) || IsHasContainersError(err) {
+		// The machine was given new work since it was checked.
+		return nil
+	}
+	return err
+}
+
+// cleanupScheduledMachineRemoval destroys the supplied machine, whose
+// removal was scheduled with ScheduleDestroy and has not been cancelled.
+// If the machine cannot be destroyed, because it has been given units or
+// containers since, the removal is abandoned.
+func (st *State) cleanupScheduledMachineRemoval(machineId string) error {
+	machine, err := st.Machine(machineId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	err = machine.Destroy()
+	if IsHasAssignedUnitsError(err) || IsHasContainersError(err) {
+		logger.Warningf("scheduled removal of machine %s abandoned: %v", machineId, err)
+		return nil
+	}
+	return err
+}
+
 // cleanupForceDestroyedMachine systematically destroys and removes all entities
 // that depend upon the supplied machine, and removes the machine from state. It's
-// expected to be used in response to destroy-machine --force.
+// expected to be used in response to destroy-machine --force, when the agents
+// involved are not expected to come back; everything done in their place is
+// recorded in the cleanup audit trail.
 func (st *State) cleanupForceDestroyedMachine(machineId string) error {
+	audit := &cleanupAudit{st: st, kind: cleanupForceDestroyedMachine, prefix: machineId}
+	return st.obliterateMachine(machineId, audit)
+}
+
+// obliterateMachine does the work of cleanupForceDestroyedMachine, recording
+// each operation it performs with the supplied audit.
+func (st *State) obliterateMachine(machineId string, audit *cleanupAudit) error {
 	machine, err := st.Machine(machineId)
 	if errors.IsNotFound(err) {
 		return nil
@@ -258,11 +377,11 @@ func (st *State) cleanupForceDestroyedMachine(machineId string) error {
 	// But machine destruction is unsophisticated, and doesn't allow for
 	// destruction while dependencies exist; so we just have to deal with that
 	// possibility below.
-	if err := st.cleanupContainers(machine); err != nil {
+	if err := st.cleanupContainers(machine, audit); err != nil {
 		return err
 	}
 	for _, unitName := range machine.doc.Principals {
-		if err := st.obliterateUnit(unitName); err != nil {
+		if err := st.obliterateUnit(unitName, audit); err != nil {
 			return err
 		}
 	}
@@ -280,23 +399,53 @@ func (st *State) cleanupForceDestroyedMachine(machineId string) error {
 	// again -- which it *probably* will anyway -- the issue can be resolved by
 	// force-destroying the machine again; that's better than adding layer
 	// upon layer of complication here.
-	if err := machine.EnsureDead(); err != nil {
-		return err
+	if machine.Life() != Dead {
+		if err := machine.EnsureDead(); err != nil {
+			return err
+		}
+		if err := audit.record(machine.Tag(), "set machine to dead"); err != nil {
+			return err
+		}
 	}
 	removePortsOps, err := machine.removePortsOps()
 	if err != nil {
 		return err
 	}
-	return st.runTransaction(removePortsOps)
+	if len(removePortsOps) > 0 {
+		if err := st.runTransaction(removePortsOps); err != nil {
+			return err
+		}
+		if err := audit.record(machine.Tag(), "closed all ports"); err != nil {
+			return err
+		}
+	}
+	// Nothing will release the addresses allocated to the machine while
+	// it exists, so mark them dead for the addresser to release.
+	addresses, err := st.AllocatedIPAddresses(machine.Id())
+	if err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if address.Life() == Dead {
+			continue
+		}
+		if err := address.EnsureDead(); err != nil {
+			return err
+		}
+		if err := audit.record(machine.Tag(), "released address %s", address.Value()); err != nil {
+			return err
+		}
+	}
+	return nil
 
 	// Note that we do *not* remove the machine entirely: we leave it for the
 	// provisioner to clean up, so that we don't end up with an unreferenced
 	// instance that would otherwise be ignored when in provisioner-safe-mode.
 }
 
-// cleanupContainers recursively calls cleanupForceDestroyedMachine on the supplied
+// cleanupContainers recursively calls obliterateMachine on the supplied
 // machine's containers, and removes them from state entirely.
-func (st *State) cleanupContainers(machine *Machine) error {
+func (st *State) cleanupContainers(machine *Machine, audit *cleanupAudit) error {
 	containerIds, err := machine.Containers()
 	if errors.IsNotFound(err) {
 		return nil
@@ -304,7 +453,7 @@ func (st *State) cleanupContainers(machine *Machine) error {
 		return err
 	}
 	for _, containerId := range containerIds {
-		if err := st.cleanupForceDestroyedMachine(containerId); err != nil {
+		if err := st.obliterateMachine(containerId, audit); err != nil {
 			return err
 		}
 		container, err := st.Machine(containerId)
@@ -316,6 +465,9 @@ func (st *State) cleanupContainers(machine *Machine) error {
 		if err := container.Remove(); err != nil {
 			return err
 		}
+		if err := audit.record(container.Tag(), "removed container"); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -324,7 +476,7 @@ func (st *State) cleanupContainers(machine *Machine) error {
 // sane to obliterate any unit in isolation; its only reasonable use is in
 // the context of machine obliteration, in which we can be sure that unclean
 // shutdown of units is not going to leave a machine in a difficult state.
-func (st *State) obliterateUnit(unitName string) error {
+func (st *State) obliterateUnit(unitName string, audit *cleanupAudit) error {
 	unit, err := st.Unit(unitName)
 	if errors.IsNotFound(err) {
 		return nil
@@ -338,19 +490,40 @@ func (st *State) obliterateUnit(unitName string) error {
 		return err
 	}
 	if err := unit.Refresh(); errors.IsNotFound(err) {
-		return nil
+		return audit.record(unit.Tag(), "removed unit")
 	} else if err != nil {
 		return err
 	}
 	for _, subName := range unit.SubordinateNames() {
-		if err := st.obliterateUnit(subName); err != nil {
+		if err := st.obliterateUnit(subName, audit); err != nil {
+			return err
+		}
+	}
+	// The unit agent will never detach the unit's storage, so remove the
+	// attachments directly; otherwise the unit could never become Dead.
+	storageAttachments, err := st.UnitStorageAttachments(unit.UnitTag())
+	if err != nil {
+		return err
+	}
+	for _, storageAttachment := range storageAttachments {
+		storageTag := storageAttachment.StorageInstance()
+		if err := st.DestroyStorageAttachment(storageTag, unit.UnitTag()); err != nil {
+			return err
+		}
+		if err := st.RemoveStorageAttachment(storageTag, unit.UnitTag()); err != nil {
+			return err
+		}
+		if err := audit.record(unit.Tag(), "detached storage %s", storageTag.Id()); err != nil {
 			return err
 		}
 	}
 	if err := unit.EnsureDead(); err != nil {
 		return err
 	}
-	return unit.Remove()
+	if err := unit.Remove(); err != nil {
+		return err
+	}
+	return audit.record(unit.Tag(), "removed unit")
 }
 
 // cleanupAttachmentsForDyingStorage sets all storage attachments related
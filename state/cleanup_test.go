@@ -5,6 +5,7 @@ package state_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -252,6 +253,49 @@ func (s *CleanupSuite) TestCleanupForceDestroyedMachineWithContainer(c *gc.C) {
 	assertLife(c, machine, state.Dead)
 }
 
+func (s *CleanupSuite) TestCleanupForceDestroyedMachineDetachesStorage(c *gc.C) {
+	s.assertDoesNotNeedCleanup(c)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddTestingCharm(c, "storage-block")
+	storage := map[string]state.StorageConstraints{
+		"data": makeStorageCons("loop", 1024, 1),
+	}
+	service := s.AddTestingServiceWithStorage(c, "storage-block", ch, storage)
+	u, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertDoesNotNeedCleanup(c)
+
+	// Force machine destruction; the unit agent will never detach the
+	// storage, so the cleanup does it instead.
+	err = machine.ForceDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupCount(c, 2)
+	assertRemoved(c, u)
+	_, err = s.State.StorageAttachment(names.NewStorageTag("data/0"), u.UnitTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	assertLife(c, machine, state.Dead)
+
+	// Everything done in place of the agents is in the audit trail.
+	entries, err := s.State.CleanupAudit()
+	c.Assert(err, jc.ErrorIsNil)
+	var operations []string
+	for _, entry := range entries {
+		c.Check(entry.Cleanup, gc.Equals, "machine")
+		c.Check(entry.Prefix, gc.Equals, machine.Id())
+		operations = append(operations, entry.Entity+": "+entry.Operation)
+	}
+	c.Assert(operations, gc.HasLen, 3)
+	c.Assert(operations, jc.DeepEquals, []string{
+		"unit-storage-block-0: detached storage data/0",
+		"unit-storage-block-0: removed unit",
+		"machine-" + machine.Id() + ": set machine to dead",
+	})
+}
+
 func (s *CleanupSuite) TestCleanupDyingUnit(c *gc.C) {
 	// Create active unit, in a relation.
 	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
@@ -436,6 +480,201 @@ func (s *CleanupSuite) TestCleanupStorageInstances(c *gc.C) {
 	s.assertDoesNotNeedCleanup(c)
 }
 
+func (s *CleanupSuite) addContainerUnit(c *gc.C, template state.MachineTemplate) (host, container *state.Machine, unit *state.Unit) {
+	host, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	unit, err = mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	return host, container, unit
+}
+
+// removeContainer removes the container, left dying by the removal of
+// its unit, as the provisioner would.
+func (s *CleanupSuite) removeContainer(c *gc.C, container *state.Machine) {
+	assertLife(c, container, state.Dying)
+	err := container.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+var hostTemplate = state.MachineTemplate{
+	Series: "quantal",
+	Jobs:   []state.MachineJob{state.JobHostUnits},
+}
+
+func (s *CleanupSuite) TestCleanupEmptyMachine(c *gc.C) {
+	host, container, unit := s.addContainerUnit(c, hostTemplate)
+	err := unit.DestroyRemovingEmptyMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	assertRemoved(c, unit)
+	s.assertCleanupRuns(c)
+
+	s.removeContainer(c, container)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, host, state.Dying)
+}
+
+func (s *CleanupSuite) TestCleanupEmptyMachineEnvironSetting(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"remove-empty-machines": true}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	host, container, unit := s.addContainerUnit(c, hostTemplate)
+	err = unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupRuns(c)
+
+	s.removeContainer(c, container)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, host, state.Dying)
+}
+
+func (s *CleanupSuite) TestCleanupEmptyMachineNotRequested(c *gc.C) {
+	host, container, unit := s.addContainerUnit(c, hostTemplate)
+	err := unit.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupRuns(c)
+
+	s.removeContainer(c, container)
+	s.assertDoesNotNeedCleanup(c)
+	assertLife(c, host, state.Alive)
+}
+
+func (s *CleanupSuite) TestCleanupEmptyMachineKeepsManualMachine(c *gc.C) {
+	template := hostTemplate
+	template.InstanceId = "manual-host"
+	template.Nonce = "manual:10.0.0.1"
+	host, container, unit := s.addContainerUnit(c, template)
+	err := unit.DestroyRemovingEmptyMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupRuns(c)
+
+	s.removeContainer(c, container)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, host, state.Alive)
+}
+
+func (s *CleanupSuite) TestCleanupEmptyMachineKeepsBusyMachine(c *gc.C) {
+	host, container, unit := s.addContainerUnit(c, hostTemplate)
+	other, err := s.State.AddMachineInsideMachine(hostTemplate, host.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.DestroyRemovingEmptyMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertCleanupRuns(c)
+
+	s.removeContainer(c, container)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, host, state.Alive)
+	assertLife(c, other, state.Alive)
+}
+
+func (s *CleanupSuite) patchCleanupNow(c *gc.C) *time.Time {
+	now := time.Date(2015, 10, 14, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(state.CleanupNow, func() time.Time { return now })
+	return &now
+}
+
+func (s *CleanupSuite) TestCleanupScheduledMachineRemoval(c *gc.C) {
+	now := s.patchCleanupNow(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	due, err := machine.ScheduleDestroy(2 * time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.Equals, now.Add(2*time.Hour))
+	next, err := s.State.NextScheduledCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Equal(due), jc.IsTrue)
+
+	// Nothing happens before the grace period has passed.
+	*now = now.Add(time.Hour)
+	s.assertDoesNotNeedCleanup(c)
+	s.assertCleanupRuns(c)
+	assertLife(c, machine, state.Alive)
+
+	*now = now.Add(time.Hour)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, machine, state.Dying)
+	_, err = s.State.NextScheduledCleanup()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CleanupSuite) TestCancelScheduledMachineRemoval(c *gc.C) {
+	now := s.patchCleanupNow(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.CancelScheduledDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduledDestroy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = machine.CancelScheduledDestroy()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	*now = now.Add(2 * time.Hour)
+	s.assertDoesNotNeedCleanup(c)
+	assertLife(c, machine, state.Alive)
+}
+
+func (s *CleanupSuite) TestScheduleMachineRemovalTwice(c *gc.C) {
+	s.patchCleanupNow(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	due, err := machine.ScheduleDestroy(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = machine.ScheduleDestroy(2 * time.Hour)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	scheduled, err := machine.ScheduledDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(scheduled.Equal(due), jc.IsTrue)
+}
+
+func (s *CleanupSuite) TestScheduleMachineRemovalInvalid(c *gc.C) {
+	manager, err := s.State.AddMachine("quantal", state.JobManageEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = manager.ScheduleDestroy(time.Hour)
+	c.Assert(err, gc.ErrorMatches, "cannot schedule removal of machine 0: machine 0 is required by the environment")
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(0)
+	c.Assert(err, gc.ErrorMatches, "cannot schedule removal of machine 1: grace period must be positive")
+
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(time.Hour)
+	c.Assert(err, gc.ErrorMatches, "cannot schedule removal of machine 1: machine is not alive")
+}
+
+func (s *CleanupSuite) TestCleanupScheduledMachineRemovalAbandoned(c *gc.C) {
+	now := s.patchCleanupNow(c)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The machine is given a unit during the grace period.
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	*now = now.Add(time.Hour)
+	s.assertCleanupCount(c, 1)
+	assertLife(c, machine, state.Alive)
+}
+
 func (s *CleanupSuite) TestNothingToCleanup(c *gc.C) {
 	s.assertDoesNotNeedCleanup(c)
 	s.assertCleanupRuns(c)
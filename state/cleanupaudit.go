@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// CleanupAuditEntry records an operation that a cleanup performed in
+// place of an agent that was not expected to come back, such as when a
+// machine is force-destroyed.
+type CleanupAuditEntry struct {
+	// Cleanup identifies the kind of cleanup that performed the
+	// operation.
+	Cleanup string
+
+	// Prefix holds the id of the entity whose cleanup performed the
+	// operation; for a force-destroyed machine, the machine id.
+	Prefix string
+
+	// Entity holds the tag of the entity the operation affected.
+	Entity string
+
+	// Operation describes what was done to the entity.
+	Operation string
+
+	// Time holds when the operation was performed.
+	Time time.Time
+}
+
+// cleanupAuditDoc is the persistent form of a CleanupAuditEntry.
+type cleanupAuditDoc struct {
+	DocID     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	Cleanup   string    `bson:"cleanup"`
+	Prefix    string    `bson:"prefix"`
+	Entity    string    `bson:"entity"`
+	Operation string    `bson:"operation"`
+	Time      time.Time `bson:"time"`
+}
+
+// cleanupAudit records the operations performed by a single cleanup.
+type cleanupAudit struct {
+	st     *State
+	kind   cleanupKind
+	prefix string
+}
+
+// record adds an entry to the cleanup audit trail, describing an
+// operation that has been performed on the given entity.
+func (a *cleanupAudit) record(entity names.Tag, format string, args ...interface{}) error {
+	doc := &cleanupAuditDoc{
+		DocID:     a.st.docID(fmt.Sprint(bson.NewObjectId())),
+		EnvUUID:   a.st.EnvironUUID(),
+		Cleanup:   string(a.kind),
+		Prefix:    a.prefix,
+		Entity:    entity.String(),
+		Operation: fmt.Sprintf(format, args...),
+		Time:      time.Now(),
+	}
+	ops := []txn.Op{{
+		C:      cleanupAuditC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := a.st.runTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot record cleanup of %s", entity)
+	}
+	return nil
+}
+
+// CleanupAudit returns the operations performed by cleanups in the
+// environment, oldest first.
+func (st *State) CleanupAudit() ([]CleanupAuditEntry, error) {
+	coll, closer := st.getCollection(cleanupAuditC)
+	defer closer()
+
+	var docs []cleanupAuditDoc
+	if err := coll.Find(nil).Sort("time", "_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read cleanup audit trail")
+	}
+	entries := make([]CleanupAuditEntry, len(docs))
+	for i, doc := range docs {
+		entries[i] = CleanupAuditEntry{
+			Cleanup:   doc.Cleanup,
+			Prefix:    doc.Prefix,
+			Entity:    doc.Entity,
+			Operation: doc.Operation,
+			Time:      doc.Time,
+		}
+	}
+	return entries, nil
+}
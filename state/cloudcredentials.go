@@ -0,0 +1,372 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// validCloudCredentialName matches the names that may be given to
+// cloud credentials.
+var validCloudCredentialName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9.@_-]*$`)
+
+// CloudCredential holds a set of provider credentials. Credentials are
+// stored once for the whole state server, and environments refer to
+// the credentials they use by name, so that the credentials are kept
+// out of each environment's settings.
+type CloudCredential struct {
+	st  *State
+	doc cloudCredentialDoc
+}
+
+// cloudCredentialDoc records a set of provider credentials.
+type cloudCredentialDoc struct {
+	Name         string            `bson:"_id"`
+	Owner        string            `bson:"owner"`
+	ProviderType string            `bson:"provider-type"`
+	Attributes   map[string]string `bson:"attributes"`
+}
+
+// Name returns the name of the credential.
+func (c *CloudCredential) Name() string {
+	return c.doc.Name
+}
+
+// Owner returns the tag of the user that added the credential.
+func (c *CloudCredential) Owner() names.UserTag {
+	return names.NewUserTag(c.doc.Owner)
+}
+
+// ProviderType returns the type of provider that the credential is
+// used with.
+func (c *CloudCredential) ProviderType() string {
+	return c.doc.ProviderType
+}
+
+// Attributes returns the environment configuration attributes that
+// hold the credential.
+func (c *CloudCredential) Attributes() map[string]string {
+	attrs := make(map[string]string, len(c.doc.Attributes))
+	for k, v := range c.doc.Attributes {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+// AttributeNames returns the names of the attributes that hold the
+// credential, sorted.
+func (c *CloudCredential) AttributeNames() []string {
+	names := make([]string, 0, len(c.doc.Attributes))
+	for name := range c.doc.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Refresh refreshes the contents of the credential from the underlying
+// state.
+func (c *CloudCredential) Refresh() error {
+	cred, err := c.st.CloudCredential(c.doc.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.doc = cred.doc
+	return nil
+}
+
+// AddCloudCredential adds a credential with the given name, for use by
+// environments of the given provider type.
+func (st *State) AddCloudCredential(name string, owner names.UserTag, providerType string, attrs map[string]string) (*CloudCredential, error) {
+	if !validCloudCredentialName.MatchString(name) {
+		return nil, errors.NotValidf("cloud credential name %q", name)
+	}
+	if providerType == "" {
+		return nil, errors.Errorf("cannot add cloud credential %q: no provider type specified", name)
+	}
+	if len(attrs) == 0 {
+		return nil, errors.Errorf("cannot add cloud credential %q: no attributes specified", name)
+	}
+	doc := cloudCredentialDoc{
+		Name:         name,
+		Owner:        owner.Username(),
+		ProviderType: providerType,
+		Attributes:   attrs,
+	}
+	ops := []txn.Op{{
+		C:      cloudCredentialsC,
+		Id:     name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("cloud credential %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add cloud credential %q", name)
+	}
+	return &CloudCredential{st: st, doc: doc}, nil
+}
+
+// Environments returns the environments that use the credential.
+func (c *CloudCredential) Environments() ([]*Environment, error) {
+	environments, closer := c.st.getCollection(environmentsC)
+	defer closer()
+
+	var docs []environmentDoc
+	err := environments.Find(bson.D{{"cloud-credential", c.doc.Name}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get environments using cloud credential %q", c.doc.Name)
+	}
+	envs := make([]*Environment, len(docs))
+	for i, doc := range docs {
+		envs[i] = &Environment{st: c.st, doc: doc}
+	}
+	return envs, nil
+}
+
+// UpdateCloudCredential replaces the attributes of the named credential.
+// The new attributes must have the same names as the old ones, so that
+// no environment using the credential is left without a value that it
+// relies on. The config watchers of the environments that use the
+// credential are notified of the change, so that workers holding
+// environs for them pick up the new credential.
+func (st *State) UpdateCloudCredential(name string, attrs map[string]string) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		cred, err := st.CloudCredential(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(attrs) != len(cred.doc.Attributes) {
+			return nil, errors.Errorf("attributes must be %v", cred.AttributeNames())
+		}
+		for attr := range attrs {
+			if _, ok := cred.doc.Attributes[attr]; !ok {
+				return nil, errors.Errorf("attributes must be %v", cred.AttributeNames())
+			}
+		}
+		return []txn.Op{{
+			C:      cloudCredentialsC,
+			Id:     name,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"attributes", attrs}}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot update cloud credential %q", name)
+	}
+	cred, err := st.CloudCredential(name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	envs, err := cred.Environments()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, env := range envs {
+		if err := touchEnvironSettings(st, env.EnvironTag()); err != nil {
+			return errors.Annotatef(err, "cannot notify environment %q of cloud credential change", env.UUID())
+		}
+	}
+	return nil
+}
+
+// touchEnvironSettings updates the revision of the settings document of
+// the given environment without changing its contents, so that
+// watchers of the environment's config see a change.
+func touchEnvironSettings(st *State, tag names.EnvironTag) error {
+	envSt, err := st.ForEnviron(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer envSt.Close()
+	ops := []txn.Op{{
+		C:      settingsC,
+		Id:     envSt.docID(environGlobalKey),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"env-uuid", tag.Id()}}}},
+	}}
+	return envSt.runTransactionNoEnvAliveAssert(ops)
+}
+
+// CloudCredential returns the credential with the given name.
+func (st *State) CloudCredential(name string) (*CloudCredential, error) {
+	coll, closer := st.getCollection(cloudCredentialsC)
+	defer closer()
+
+	var doc cloudCredentialDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("cloud credential %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get cloud credential %q", name)
+	}
+	return &CloudCredential{st: st, doc: doc}, nil
+}
+
+// AllCloudCredentials returns all the credentials known to the state
+// server, ordered by name.
+func (st *State) AllCloudCredentials() ([]*CloudCredential, error) {
+	coll, closer := st.getCollection(cloudCredentialsC)
+	defer closer()
+
+	var docs []cloudCredentialDoc
+	if err := coll.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get cloud credentials")
+	}
+	creds := make([]*CloudCredential, len(docs))
+	for i, doc := range docs {
+		creds[i] = &CloudCredential{st: st, doc: doc}
+	}
+	return creds, nil
+}
+
+// SetEnvironCloudCredential makes the environment use the named
+// credential. Any values of the credential's attributes held in the
+// environment's settings are removed, so that the credential is the
+// only place they are recorded.
+func (st *State) SetEnvironCloudCredential(name string) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		cred, err := st.CloudCredential(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cfg, err := st.EnvironConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if cfg.Type() != cred.ProviderType() {
+			return nil, errors.Errorf(
+				"credential is for provider type %q, environment has type %q",
+				cred.ProviderType(), cfg.Type(),
+			)
+		}
+		ops := []txn.Op{{
+			C:      cloudCredentialsC,
+			Id:     name,
+			Assert: txn.DocExists,
+		}}
+		return append(ops, useCloudCredentialOps(st, cred.doc, isEnvAliveDoc)...), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set cloud credential %q", name)
+	}
+	return nil
+}
+
+// useCloudCredentialOps returns the operations needed to make the
+// environment of the given state use the given credential, with the
+// given assertion on the environment document.
+func useCloudCredentialOps(st *State, cred cloudCredentialDoc, envAssert interface{}) []txn.Op {
+	unset := make(bson.M)
+	for attr := range cred.Attributes {
+		unset[escapeReplacer.Replace(attr)] = 1
+	}
+	return []txn.Op{{
+		C:      environmentsC,
+		Id:     st.EnvironUUID(),
+		Assert: envAssert,
+		Update: bson.D{{"$set", bson.D{{"cloud-credential", cred.Name}}}},
+	}, {
+		C:      settingsC,
+		Id:     st.docID(environGlobalKey),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", unset}},
+	}}
+}
+
+// environCloudCredential returns the credential used by the
+// environment, or nil if the environment holds its credentials in its
+// own settings.
+func (st *State) environCloudCredential() (*CloudCredential, error) {
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var doc struct {
+		CloudCredential string `bson:"cloud-credential"`
+	}
+	err := environments.FindId(st.EnvironUUID()).Select(bson.D{{"cloud-credential", 1}}).One(&doc)
+	if err == mgo.ErrNotFound || err == nil && doc.CloudCredential == "" {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.CloudCredential(doc.CloudCredential)
+}
+
+// MoveCloudCredentialsFromSettings moves the credentials held in each
+// environment's settings into cloud credential documents, and makes
+// the environments refer to them. credentialAttrs returns the names of
+// the attributes that hold credentials for the given provider type.
+// The credential for an environment is named after the environment's
+// owner and name.
+func MoveCloudCredentialsFromSettings(st *State, credentialAttrs func(providerType string) []string) error {
+	envs, err := st.AllEnvironments()
+	if err != nil {
+		return errors.Annotate(err, "failed to read environments")
+	}
+	for _, env := range envs {
+		if env.doc.CloudCredential != "" {
+			continue
+		}
+		if err := moveEnvironCloudCredentials(st, env, credentialAttrs); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// moveEnvironCloudCredentials moves the credentials held in the
+// environment's settings into a cloud credential document. The
+// environment's State is closed before returning, so that one is not
+// held open for every environment until the migration ends.
+func moveEnvironCloudCredentials(st *State, env *Environment, credentialAttrs func(providerType string) []string) error {
+	envSt, err := st.ForEnviron(env.EnvironTag())
+	if err != nil {
+		return errors.Annotatef(err, "failed to open environment %q", env.UUID())
+	}
+	defer envSt.Close()
+
+	settings, err := readSettings(envSt, environGlobalKey)
+	if err != nil {
+		return errors.Annotatef(err, "failed to read settings for environment %q", env.UUID())
+	}
+	providerType, _ := settings.Map()["type"].(string)
+	attrs := make(map[string]string)
+	for _, attr := range credentialAttrs(providerType) {
+		if value, ok := settings.Map()[attr].(string); ok {
+			attrs[attr] = value
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	doc := cloudCredentialDoc{
+		Name:         env.Owner().Name() + "-" + env.Name(),
+		Owner:        env.Owner().Username(),
+		ProviderType: providerType,
+		Attributes:   attrs,
+	}
+	ops := []txn.Op{{
+		C:      cloudCredentialsC,
+		Id:     doc.Name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	ops = append(ops, useCloudCredentialOps(envSt, doc, txn.DocExists)...)
+	if err := envSt.runTransactionNoEnvAliveAssert(ops); err == txn.ErrAborted {
+		return errors.Errorf("cannot move credentials for environment %q: cloud credential %q already exists", env.UUID(), doc.Name)
+	} else if err != nil {
+		return errors.Annotatef(err, "failed to move credentials for environment %q", env.UUID())
+	}
+	logger.Infof("moved credentials for environment %q to cloud credential %q", env.UUID(), doc.Name)
+	return nil
+}
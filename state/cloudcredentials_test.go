@@ -0,0 +1,213 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type cloudCredentialsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&cloudCredentialsSuite{})
+
+func (s *cloudCredentialsSuite) TestAddCloudCredential(c *gc.C) {
+	attrs := map[string]string{"access-key": "x", "secret-key": "y"}
+	cred, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Name(), gc.Equals, "shared")
+	c.Assert(cred.Owner(), gc.Equals, s.Owner)
+	c.Assert(cred.ProviderType(), gc.Equals, "someprovider")
+	c.Assert(cred.Attributes(), jc.DeepEquals, attrs)
+	c.Assert(cred.AttributeNames(), jc.DeepEquals, []string{"access-key", "secret-key"})
+
+	cred, err = s.State.CloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Attributes(), jc.DeepEquals, attrs)
+}
+
+func (s *cloudCredentialsSuite) TestAddCloudCredentialDuplicate(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"a": "c"})
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `cloud credential "shared" already exists`)
+}
+
+func (s *cloudCredentialsSuite) TestAddCloudCredentialInvalid(c *gc.C) {
+	_, err := s.State.AddCloudCredential("no way", s.Owner, "someprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	_, err = s.State.AddCloudCredential("shared", s.Owner, "", map[string]string{"a": "b"})
+	c.Assert(err, gc.ErrorMatches, `cannot add cloud credential "shared": no provider type specified`)
+	_, err = s.State.AddCloudCredential("shared", s.Owner, "someprovider", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add cloud credential "shared": no attributes specified`)
+}
+
+func (s *cloudCredentialsSuite) TestCloudCredentialNotFound(c *gc.C) {
+	_, err := s.State.CloudCredential("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `cloud credential "missing" not found`)
+}
+
+func (s *cloudCredentialsSuite) TestUpdateCloudCredential(c *gc.C) {
+	cred, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"a": "c"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = cred.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Attributes(), jc.DeepEquals, map[string]string{"a": "c"})
+}
+
+func (s *cloudCredentialsSuite) TestUpdateCloudCredentialChangesAttributeNames(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"x": "y"})
+	c.Assert(err, gc.ErrorMatches, `cannot update cloud credential "shared": attributes must be \[a\]`)
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"a": "b", "x": "y"})
+	c.Assert(err, gc.ErrorMatches, `cannot update cloud credential "shared": attributes must be \[a\]`)
+}
+
+func (s *cloudCredentialsSuite) TestAllCloudCredentials(c *gc.C) {
+	_, err := s.State.AddCloudCredential("b", s.Owner, "someprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloudCredential("a", s.Owner, "otherprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	creds, err := s.State.AllCloudCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.HasLen, 2)
+	c.Assert(creds[0].Name(), gc.Equals, "a")
+	c.Assert(creds[1].Name(), gc.Equals, "b")
+}
+
+func (s *cloudCredentialsSuite) TestSetEnvironCloudCredential(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"secret-key": "old"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"secret-key": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	name, ok := env.CloudCredential()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "shared")
+
+	// The value in the settings is gone, and the credential's is used.
+	settings, err := s.State.ReadSettings("e")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = settings.Get("secret-key")
+	c.Assert(ok, jc.IsFalse)
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret-key"], gc.Equals, "new")
+
+	// Updates to the credential are seen by the environment.
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"secret-key": "newer"})
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err = s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret-key"], gc.Equals, "newer")
+}
+
+func (s *cloudCredentialsSuite) TestSetEnvironCloudCredentialWrongProviderType(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "otherprovider", map[string]string{"a": "b"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud credential "shared": credential is for provider type "otherprovider", environment has type "someprovider"`)
+}
+
+func (s *cloudCredentialsSuite) TestSetEnvironCloudCredentialNotFound(c *gc.C) {
+	err := s.State.SetEnvironCloudCredential("missing")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud credential "missing": cloud credential "missing" not found`)
+}
+
+func (s *cloudCredentialsSuite) TestUpdateEnvironConfigRejectsCredentialAttrs(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"secret-key": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"secret-key": "other"}, nil, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot change "secret-key": it is held by cloud credential "shared"`)
+	err = s.State.UpdateEnvironConfig(nil, []string{"secret-key"}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot change "secret-key": it is held by cloud credential "shared"`)
+
+	// Other attributes can still be changed, and the credential's
+	// attributes are not copied into the settings.
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"default-series": "precise"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := s.State.ReadSettings("e")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings.Map()["default-series"], gc.Equals, "precise")
+	_, ok := settings.Get("secret-key")
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *cloudCredentialsSuite) TestCloudCredentialEnvironments(c *gc.C) {
+	cred, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"secret-key": "x"})
+	c.Assert(err, jc.ErrorIsNil)
+	envs, err := cred.Environments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 0)
+
+	err = s.State.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+	err = otherSt.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	envs, err = cred.Environments()
+	c.Assert(err, jc.ErrorIsNil)
+	uuids := make([]string, len(envs))
+	for i, env := range envs {
+		uuids[i] = env.UUID()
+	}
+	c.Assert(uuids, jc.SameContents, []string{s.State.EnvironUUID(), otherSt.EnvironUUID()})
+}
+
+func (s *cloudCredentialsSuite) TestUpdateCloudCredentialNotifiesEnvironments(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"secret-key": "old"})
+	c.Assert(err, jc.ErrorIsNil)
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+	err = otherSt.SetEnvironCloudCredential("shared")
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := otherSt.WatchForEnvironConfigChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, otherSt, w)
+	wc.AssertOneChange()
+
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"secret-key": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	cfg, err := otherSt.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret-key"], gc.Equals, "new")
+}
+
+func (s *cloudCredentialsSuite) TestUpdateCloudCredentialDoesNotNotifyOtherEnvironments(c *gc.C) {
+	_, err := s.State.AddCloudCredential("shared", s.Owner, "someprovider", map[string]string{"secret-key": "old"})
+	c.Assert(err, jc.ErrorIsNil)
+	otherSt := s.factory.MakeEnvironment(c, nil)
+	defer otherSt.Close()
+
+	w := otherSt.WatchForEnvironConfigChanges()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, otherSt, w)
+	wc.AssertOneChange()
+
+	err = s.State.UpdateCloudCredential("shared", map[string]string{"secret-key": "new"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
@@ -0,0 +1,244 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// validCloudName matches the names that may be given to clouds.
+var validCloudName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Cloud describes a cloud that environments may be hosted in. A state
+// server can host environments in any of the clouds it knows about,
+// each of which may use a different provider.
+type Cloud struct {
+	st  *State
+	doc cloudDoc
+}
+
+// cloudDoc records a cloud.
+type cloudDoc struct {
+	Name         string           `bson:"_id"`
+	ProviderType string           `bson:"provider-type"`
+	Regions      []cloudRegionDoc `bson:"regions"`
+}
+
+// cloudRegionDoc records a region of a cloud.
+type cloudRegionDoc struct {
+	Name     string `bson:"name"`
+	Endpoint string `bson:"endpoint,omitempty"`
+}
+
+// CloudRegion describes a region of a cloud.
+type CloudRegion struct {
+	// Name is the name of the region.
+	Name string
+
+	// Endpoint is the provider API endpoint used for the region, if
+	// the provider does not derive it from the region's name.
+	Endpoint string
+}
+
+// Name returns the name of the cloud.
+func (c *Cloud) Name() string {
+	return c.doc.Name
+}
+
+// ProviderType returns the type of provider used to manage the cloud.
+func (c *Cloud) ProviderType() string {
+	return c.doc.ProviderType
+}
+
+// Regions returns the regions of the cloud.
+func (c *Cloud) Regions() []CloudRegion {
+	regions := make([]CloudRegion, len(c.doc.Regions))
+	for i, region := range c.doc.Regions {
+		regions[i] = CloudRegion{
+			Name:     region.Name,
+			Endpoint: region.Endpoint,
+		}
+	}
+	return regions
+}
+
+// Region returns the named region of the cloud.
+func (c *Cloud) Region(name string) (CloudRegion, error) {
+	for _, region := range c.doc.Regions {
+		if region.Name == name {
+			return CloudRegion{
+				Name:     region.Name,
+				Endpoint: region.Endpoint,
+			}, nil
+		}
+	}
+	return CloudRegion{}, errors.NotFoundf("region %q in cloud %q", name, c.doc.Name)
+}
+
+// Environments returns the environments hosted in the cloud.
+func (c *Cloud) Environments() ([]*Environment, error) {
+	environments, closer := c.st.getCollection(environmentsC)
+	defer closer()
+
+	var docs []environmentDoc
+	err := environments.Find(bson.D{{"cloud", c.doc.Name}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get environments in cloud %q", c.doc.Name)
+	}
+	envs := make([]*Environment, len(docs))
+	for i, doc := range docs {
+		envs[i] = &Environment{st: c.st, doc: doc}
+	}
+	return envs, nil
+}
+
+// AddCloud adds a cloud with the given name, managed by the given
+// type of provider.
+func (st *State) AddCloud(name, providerType string, regions []CloudRegion) (*Cloud, error) {
+	if !validCloudName.MatchString(name) {
+		return nil, errors.NotValidf("cloud name %q", name)
+	}
+	if providerType == "" {
+		return nil, errors.Errorf("cannot add cloud %q: no provider type specified", name)
+	}
+	doc := cloudDoc{
+		Name:         name,
+		ProviderType: providerType,
+	}
+	seen := make(map[string]bool)
+	for _, region := range regions {
+		if region.Name == "" {
+			return nil, errors.Errorf("cannot add cloud %q: empty region name", name)
+		}
+		if seen[region.Name] {
+			return nil, errors.Errorf("cannot add cloud %q: duplicate region %q", name, region.Name)
+		}
+		seen[region.Name] = true
+		doc.Regions = append(doc.Regions, cloudRegionDoc{
+			Name:     region.Name,
+			Endpoint: region.Endpoint,
+		})
+	}
+	ops := []txn.Op{{
+		C:      cloudsC,
+		Id:     name,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return nil, errors.AlreadyExistsf("cloud %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add cloud %q", name)
+	}
+	return &Cloud{st: st, doc: doc}, nil
+}
+
+// Cloud returns the cloud with the given name.
+func (st *State) Cloud(name string) (*Cloud, error) {
+	coll, closer := st.getCollection(cloudsC)
+	defer closer()
+
+	var doc cloudDoc
+	err := coll.FindId(name).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("cloud %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get cloud %q", name)
+	}
+	return &Cloud{st: st, doc: doc}, nil
+}
+
+// AllClouds returns all the clouds known to the state server, ordered
+// by name.
+func (st *State) AllClouds() ([]*Cloud, error) {
+	coll, closer := st.getCollection(cloudsC)
+	defer closer()
+
+	var docs []cloudDoc
+	if err := coll.Find(nil).Sort("_id").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get clouds")
+	}
+	clouds := make([]*Cloud, len(docs))
+	for i, doc := range docs {
+		clouds[i] = &Cloud{st: st, doc: doc}
+	}
+	return clouds, nil
+}
+
+// SetEnvironCloud records that the environment is hosted in the named
+// cloud and region. The environment's provider type must match the
+// cloud's, and the cloud of an environment cannot be changed once set.
+// The region may be empty for clouds without regions. New environments
+// should be given their cloud by NewEnvironmentInCloud instead.
+func (st *State) SetEnvironCloud(name, region string) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		env, err := st.Environment()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if current, currentRegion, ok := env.Cloud(); ok {
+			if current == name && currentRegion == region {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return nil, errors.Errorf("environment is already hosted in cloud %q", current)
+		}
+		cfg, err := st.EnvironConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cloudOp, err := st.assertEnvironCloudOp(cfg.Type(), name, region)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{cloudOp, {
+			C:  environmentsC,
+			Id: st.EnvironUUID(),
+			Assert: bson.D{
+				{"life", bson.D{{"$in", []interface{}{Alive, nil}}}},
+				{"cloud", bson.D{{"$exists", false}}},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"cloud", name},
+				{"cloud-region", region},
+			}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot set cloud %q", name)
+	}
+	return nil
+}
+
+// assertEnvironCloudOp checks that an environment with the given
+// provider type may be hosted in the named cloud and region, and
+// returns an operation asserting that the cloud still exists.
+func (st *State) assertEnvironCloudOp(providerType, name, region string) (txn.Op, error) {
+	cloud, err := st.Cloud(name)
+	if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	if region != "" || len(cloud.doc.Regions) > 0 {
+		if _, err := cloud.Region(region); err != nil {
+			return txn.Op{}, errors.Trace(err)
+		}
+	}
+	if providerType != cloud.ProviderType() {
+		return txn.Op{}, errors.Errorf(
+			"cloud has provider type %q, environment has type %q",
+			cloud.ProviderType(), providerType,
+		)
+	}
+	return txn.Op{
+		C:      cloudsC,
+		Id:     name,
+		Assert: txn.DocExists,
+	}, nil
+}
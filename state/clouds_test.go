@@ -0,0 +1,182 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type cloudsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&cloudsSuite{})
+
+var testRegions = []state.CloudRegion{
+	{Name: "north"},
+	{Name: "south", Endpoint: "https://south.example.com"},
+}
+
+func (s *cloudsSuite) TestAddCloud(c *gc.C) {
+	cloud, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloud.Name(), gc.Equals, "other")
+	c.Assert(cloud.ProviderType(), gc.Equals, "someprovider")
+	c.Assert(cloud.Regions(), jc.DeepEquals, testRegions)
+
+	cloud, err = s.State.Cloud("other")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloud.Regions(), jc.DeepEquals, testRegions)
+	region, err := cloud.Region("south")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(region, jc.DeepEquals, testRegions[1])
+	_, err = cloud.Region("east")
+	c.Assert(err, gc.ErrorMatches, `region "east" in cloud "other" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *cloudsSuite) TestAddCloudDuplicate(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloud("other", "otherprovider", nil)
+	c.Assert(err, gc.ErrorMatches, `cloud "other" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *cloudsSuite) TestAddCloudInvalid(c *gc.C) {
+	_, err := s.State.AddCloud("-bad", "someprovider", nil)
+	c.Assert(err, gc.ErrorMatches, `cloud name "-bad" not valid`)
+	_, err = s.State.AddCloud("other", "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add cloud "other": no provider type specified`)
+	_, err = s.State.AddCloud("other", "someprovider", []state.CloudRegion{{Name: ""}})
+	c.Assert(err, gc.ErrorMatches, `cannot add cloud "other": empty region name`)
+	_, err = s.State.AddCloud("other", "someprovider", []state.CloudRegion{{Name: "a"}, {Name: "a"}})
+	c.Assert(err, gc.ErrorMatches, `cannot add cloud "other": duplicate region "a"`)
+}
+
+func (s *cloudsSuite) TestCloudNotFound(c *gc.C) {
+	_, err := s.State.Cloud("missing")
+	c.Assert(err, gc.ErrorMatches, `cloud "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *cloudsSuite) TestAllClouds(c *gc.C) {
+	_, err := s.State.AddCloud("b", "someprovider", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCloud("a", "otherprovider", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	clouds, err := s.State.AllClouds()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clouds, gc.HasLen, 2)
+	c.Assert(clouds[0].Name(), gc.Equals, "a")
+	c.Assert(clouds[1].Name(), gc.Equals, "b")
+}
+
+func (s *cloudsSuite) TestSetEnvironCloud(c *gc.C) {
+	cloud, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, ok := env.Cloud()
+	c.Assert(ok, jc.IsFalse)
+
+	err = s.State.SetEnvironCloud("other", "south")
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	name, region, ok := env.Cloud()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "other")
+	c.Assert(region, gc.Equals, "south")
+
+	envs, err := cloud.Environments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envs, gc.HasLen, 1)
+	c.Assert(envs[0].UUID(), gc.Equals, s.State.EnvironUUID())
+
+	// Setting the same cloud again is a no-op.
+	err = s.State.SetEnvironCloud("other", "south")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cloudsSuite) TestSetEnvironCloudCannotChange(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloud("other", "south")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloud("other", "north")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud "other": environment is already hosted in cloud "other"`)
+}
+
+func (s *cloudsSuite) TestSetEnvironCloudInvalidRegion(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloud("other", "east")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud "other": region "east" in cloud "other" not found`)
+	err = s.State.SetEnvironCloud("other", "")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud "other": region "" in cloud "other" not found`)
+}
+
+func (s *cloudsSuite) TestSetEnvironCloudWithoutRegions(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloud("other", "")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *cloudsSuite) TestSetEnvironCloudWrongProviderType(c *gc.C) {
+	_, err := s.State.AddCloud("other", "otherprovider", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetEnvironCloud("other", "")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud "other": cloud has provider type "otherprovider", environment has type "someprovider"`)
+}
+
+func (s *cloudsSuite) TestSetEnvironCloudNotFound(c *gc.C) {
+	err := s.State.SetEnvironCloud("missing", "")
+	c.Assert(err, gc.ErrorMatches, `cannot set cloud "missing": cloud "missing" not found`)
+}
+
+func (s *cloudsSuite) newEnvironConfig(c *gc.C) *config.Config {
+	uuid, err := utils.NewUUID()
+	c.Assert(err, jc.ErrorIsNil)
+	return testing.CustomEnvironConfig(c, testing.Attrs{
+		"name": "hosted",
+		"uuid": uuid.String(),
+	})
+}
+
+func (s *cloudsSuite) TestNewEnvironmentInCloud(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	owner := s.factory.MakeUser(c, nil).UserTag()
+	env, st, err := s.State.NewEnvironmentInCloud(s.newEnvironConfig(c), owner, "other", "south")
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	name, region, ok := env.Cloud()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "other")
+	c.Assert(region, gc.Equals, "south")
+}
+
+func (s *cloudsSuite) TestNewEnvironmentInCloudInvalidRegion(c *gc.C) {
+	_, err := s.State.AddCloud("other", "someprovider", testRegions)
+	c.Assert(err, jc.ErrorIsNil)
+	owner := s.factory.MakeUser(c, nil).UserTag()
+	cfg := s.newEnvironConfig(c)
+	_, _, err = s.State.NewEnvironmentInCloud(cfg, owner, "other", "east")
+	c.Assert(err, gc.ErrorMatches, `cannot create environment in cloud "other": region "east" in cloud "other" not found`)
+
+	// The environment is not created without its cloud.
+	_, err = s.State.GetEnvironment(names.NewEnvironTag(cfg.AllAttrs()["uuid"].(string)))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
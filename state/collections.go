@@ -62,20 +62,30 @@ type stateCollection interface {
 // these collections.
 var multiEnvCollections = set.NewStrings(
 	actionNotificationsC,
+	actionOutputC,
 	actionsC,
+	agentRolloutsC,
 	annotationsC,
 	blockDevicesC,
 	blocksC,
 	charmsC,
 	cleanupsC,
+	cleanupAuditC,
+	configRevisionsC,
 	constraintsC,
 	containerRefsC,
+	endpointBindingsC,
+	envInvitationsC,
 	envUsersC,
 	filesystemsC,
 	filesystemAttachmentsC,
+	idempotencyTokensC,
 	instanceDataC,
+	instanceTypeMappingsC,
 	ipaddressesC,
+	machineHostKeysC,
 	machinesC,
+	machineUpgradeSeriesLocksC,
 	meterStatusC,
 	minUnitsC,
 	networkInterfacesC,
@@ -83,8 +93,11 @@ var multiEnvCollections = set.NewStrings(
 	openedPortsC,
 	rebootC,
 	relationScopesC,
+	relationServiceSettingsC,
+	relationSettingsSnapshotsC,
 	relationsC,
 	requestedNetworksC,
+	scaleEventsC,
 	sequenceC,
 	servicesC,
 	settingsC,
@@ -95,9 +108,11 @@ var multiEnvCollections = set.NewStrings(
 	storageConstraintsC,
 	storageInstancesC,
 	subnetsC,
+	unitStatesC,
 	unitsC,
 	volumesC,
 	volumeAttachmentsC,
+	webhooksC,
 )
 
 func newStateCollection(coll *mgo.Collection, envUUID string) stateCollection {
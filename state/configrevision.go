@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v5"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MaxConfigRevisions is the number of revisions of a service's
+// configuration settings that are kept; older revisions are discarded
+// as new ones are recorded.
+const MaxConfigRevisions = 20
+
+// ConfigRevision describes a change made to the configuration
+// settings of a service.
+type ConfigRevision struct {
+	// Revision is the revision of the service's configuration
+	// settings that the change produced.
+	Revision int
+
+	// ChangedBy holds the tag of the entity that made the change,
+	// if known.
+	ChangedBy string
+
+	// Changed holds the time of the change.
+	Changed time.Time
+
+	// Changes holds the settings that were changed, sorted by key.
+	Changes []ItemChange
+
+	// Settings holds all the configuration settings of the service
+	// as they were after the change.
+	Settings charm.Settings
+}
+
+// configRevisionDoc records a single revision of a service's
+// configuration settings.
+type configRevisionDoc struct {
+	DocID       string                 `bson:"_id"`
+	EnvUUID     string                 `bson:"env-uuid"`
+	ServiceName string                 `bson:"service"`
+	Revision    int                    `bson:"revision"`
+	ChangedBy   string                 `bson:"changedby,omitempty"`
+	Changed     time.Time              `bson:"changed"`
+	Changes     []configChangeDoc      `bson:"changes"`
+	Settings    map[string]interface{} `bson:"settings"`
+}
+
+type configChangeDoc struct {
+	Type     int         `bson:"type"`
+	Key      string      `bson:"key"`
+	OldValue interface{} `bson:"old,omitempty"`
+	NewValue interface{} `bson:"new,omitempty"`
+}
+
+func configRevisionKey(serviceName string, revision int) string {
+	return fmt.Sprintf("%s#%d", serviceName, revision)
+}
+
+func (doc *configRevisionDoc) revision() *ConfigRevision {
+	changes := make([]ItemChange, len(doc.Changes))
+	for i, change := range doc.Changes {
+		changes[i] = ItemChange{
+			Type:     change.Type,
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	return &ConfigRevision{
+		Revision:  doc.Revision,
+		ChangedBy: doc.ChangedBy,
+		Changed:   doc.Changed.UTC(),
+		Changes:   changes,
+		Settings:  copyMap(doc.Settings, unescapeReplacer.Replace),
+	}
+}
+
+// ConfigRevision returns the revision of the service's configuration
+// settings. It is incremented each time the settings are changed, and
+// is zero if they have never been changed since the service was
+// deployed.
+func (s *Service) ConfigRevision() int {
+	return s.doc.ConfigRevision
+}
+
+// ConfigHistory returns the recorded revisions of the service's
+// configuration settings, oldest first. At most MaxConfigRevisions
+// revisions are kept.
+func (s *Service) ConfigHistory() ([]*ConfigRevision, error) {
+	revisions, closer := s.st.getCollection(configRevisionsC)
+	defer closer()
+
+	var docs []configRevisionDoc
+	err := revisions.Find(bson.D{{"service", s.doc.Name}}).Sort("revision").All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get config history of service %q", s.doc.Name)
+	}
+	result := make([]*ConfigRevision, len(docs))
+	for i := range docs {
+		result[i] = docs[i].revision()
+	}
+	return result, nil
+}
+
+// ConfigAtRevision returns the given revision of the service's
+// configuration settings, if it is still recorded.
+func (s *Service) ConfigAtRevision(revision int) (*ConfigRevision, error) {
+	revisions, closer := s.st.getCollection(configRevisionsC)
+	defer closer()
+
+	var doc configRevisionDoc
+	err := revisions.FindId(s.st.docID(configRevisionKey(s.doc.Name, revision))).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("config revision %d of service %q", revision, s.doc.Name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get config revision %d of service %q", revision, s.doc.Name)
+	}
+	return doc.revision(), nil
+}
+
+// RollbackConfigSettings restores the service's configuration settings
+// to those recorded at the given revision. The rollback is itself
+// recorded as a new revision.
+func (s *Service) RollbackConfigSettings(revision int, changedBy names.Tag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot roll back service %q to config revision %d", s.doc.Name, revision)
+
+	target, err := s.ConfigAtRevision(revision)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := s.ConfigSettings()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes := make(charm.Settings)
+	for name := range current {
+		if _, ok := target.Settings[name]; !ok {
+			changes[name] = nil
+		}
+	}
+	for name, value := range target.Settings {
+		changes[name] = value
+	}
+	return s.UpdateConfigSettingsBy(changes, changedBy)
+}
+
+// configRevisionOps returns the operations that record the given
+// changes to the service's configuration settings, which result in
+// the given settings, as the next revision. The oldest revision is
+// discarded if more than MaxConfigRevisions would otherwise be kept.
+func (s *Service) configRevisionOps(changes []ItemChange, settings map[string]interface{}, changedBy names.Tag) []txn.Op {
+	revision := s.doc.ConfigRevision + 1
+	doc := &configRevisionDoc{
+		DocID:       s.st.docID(configRevisionKey(s.doc.Name, revision)),
+		EnvUUID:     s.st.EnvironUUID(),
+		ServiceName: s.doc.Name,
+		Revision:    revision,
+		Changed:     nowToTheSecond(),
+		Changes:     make([]configChangeDoc, len(changes)),
+		Settings:    copyMap(settings, escapeReplacer.Replace),
+	}
+	if changedBy != nil {
+		doc.ChangedBy = changedBy.String()
+	}
+	for i, change := range changes {
+		doc.Changes[i] = configChangeDoc{
+			Type:     change.Type,
+			Key:      change.Key,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"configrevision", revision}}}},
+	}, {
+		// Concurrent changes will attempt to record the same
+		// revision, so only one of them can succeed.
+		C:      configRevisionsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if oldest := revision - MaxConfigRevisions; oldest > 0 {
+		ops = append(ops, txn.Op{
+			C:      configRevisionsC,
+			Id:     s.st.docID(configRevisionKey(s.doc.Name, oldest)),
+			Remove: true,
+		})
+	}
+	return ops
+}
+
+// removeConfigRevisionsOps returns the operations that remove the
+// recorded revisions of the named service's configuration settings,
+// the latest of which is given.
+func removeConfigRevisionsOps(st *State, serviceName string, latest int) []txn.Op {
+	var ops []txn.Op
+	for revision := latest; revision > 0 && revision > latest-MaxConfigRevisions; revision-- {
+		ops = append(ops, txn.Op{
+			C:      configRevisionsC,
+			Id:     st.docID(configRevisionKey(serviceName, revision)),
+			Remove: true,
+		})
+	}
+	return ops
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v5"
+
+	"github.com/juju/juju/state"
+)
+
+type ConfigRevisionSuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+}
+
+var _ = gc.Suite(&ConfigRevisionSuite{})
+
+func (s *ConfigRevisionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "dummy")
+	s.service = s.AddTestingService(c, "dummy", s.charm)
+}
+
+func (s *ConfigRevisionSuite) TestUpdateConfigSettingsRecordsRevision(c *gc.C) {
+	c.Assert(s.service.ConfigRevision(), gc.Equals, 0)
+	admin := names.NewUserTag("admin")
+	err := s.service.UpdateConfigSettingsBy(charm.Settings{"title": "first"}, admin)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": "second", "outlook": "sunny"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.service.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.service.ConfigRevision(), gc.Equals, 2)
+
+	first, err := s.service.ConfigAtRevision(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(first.Revision, gc.Equals, 1)
+	c.Assert(first.ChangedBy, gc.Equals, admin.String())
+	c.Assert(first.Changed.IsZero(), jc.IsFalse)
+	c.Assert(first.Changes, jc.DeepEquals, []state.ItemChange{
+		{Type: state.ItemAdded, Key: "title", NewValue: "first"},
+	})
+	c.Assert(first.Settings, jc.DeepEquals, charm.Settings{"title": "first"})
+
+	second, err := s.service.ConfigAtRevision(2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(second.ChangedBy, gc.Equals, "")
+	c.Assert(second.Changes, jc.DeepEquals, []state.ItemChange{
+		{Type: state.ItemAdded, Key: "outlook", NewValue: "sunny"},
+		{Type: state.ItemModified, Key: "title", OldValue: "first", NewValue: "second"},
+	})
+	c.Assert(second.Settings, jc.DeepEquals, charm.Settings{"title": "second", "outlook": "sunny"})
+
+	_, err = s.service.ConfigAtRevision(3)
+	c.Assert(err, gc.ErrorMatches, `config revision 3 of service "dummy" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigRevisionSuite) TestUpdateConfigSettingsNoChange(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.service.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.service.ConfigRevision(), gc.Equals, 1)
+}
+
+func (s *ConfigRevisionSuite) TestConfigHistoryIsBounded(c *gc.C) {
+	for i := 0; i <= state.MaxConfigRevisions; i++ {
+		err := s.service.UpdateConfigSettings(charm.Settings{"title": fmt.Sprintf("title %d", i)})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	history, err := s.service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, state.MaxConfigRevisions)
+	c.Assert(history[0].Revision, gc.Equals, 2)
+	c.Assert(history[len(history)-1].Revision, gc.Equals, state.MaxConfigRevisions+1)
+
+	_, err = s.service.ConfigAtRevision(1)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigRevisionSuite) TestRollbackConfigSettings(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.UpdateConfigSettings(charm.Settings{"title": "second", "outlook": "sunny"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	admin := names.NewUserTag("admin")
+	err = s.service.RollbackConfigSettings(1, admin)
+	c.Assert(err, jc.ErrorIsNil)
+
+	settings, err := s.service.ConfigSettings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, charm.Settings{"title": "first"})
+
+	// The rollback is recorded as a revision of its own.
+	c.Assert(s.service.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.service.ConfigRevision(), gc.Equals, 3)
+	rollback, err := s.service.ConfigAtRevision(3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rollback.ChangedBy, gc.Equals, admin.String())
+	c.Assert(rollback.Changes, jc.DeepEquals, []state.ItemChange{
+		{Type: state.ItemDeleted, Key: "outlook", OldValue: "sunny"},
+		{Type: state.ItemModified, Key: "title", OldValue: "second", NewValue: "first"},
+	})
+
+	err = s.service.RollbackConfigSettings(7, admin)
+	c.Assert(err, gc.ErrorMatches, `cannot roll back service "dummy" to config revision 7: config revision 7 of service "dummy" not found`)
+}
+
+func (s *ConfigRevisionSuite) TestConfigHistoryRemovedWithService(c *gc.C) {
+	err := s.service.UpdateConfigSettings(charm.Settings{"title": "first"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Refresh(), jc.ErrorIsNil)
+	c.Assert(s.service.Destroy(), jc.ErrorIsNil)
+
+	// A new service of the same name starts afresh.
+	service := s.AddTestingService(c, "dummy", s.charm)
+	history, err := service.ConfigHistory()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(history, gc.HasLen, 0)
+	err = service.UpdateConfigSettings(charm.Settings{"title": "again"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.Refresh(), jc.ErrorIsNil)
+	c.Assert(service.ConfigRevision(), gc.Equals, 1)
+}
@@ -114,3 +114,25 @@ func (s *ConfigValidatorSuite) TestConfigValidateNoPolicy(c *gc.C) {
 	err := s.updateEnvironConfig(c)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *ConfigValidatorSuite) TestUpdateEnvironConfigChecksSchema(c *gc.C) {
+	for i, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"development": "maybe"},
+		err:   `invalid config value for development: "maybe": expected bool, got string\("maybe"\)`,
+	}, {
+		attrs: map[string]interface{}{"firewall-mode": "sometimes"},
+		err:   `invalid config value for firewall-mode: "sometimes": expected one of \[instance global none\]`,
+	}, {
+		attrs: map[string]interface{}{"logging-config": "juju=BOGUS"},
+		err:   `invalid config value for logging-config: "juju=BOGUS": unknown severity level "BOGUS"`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		s.configValidator = mockConfigValidator{}
+		err := s.State.UpdateEnvironConfig(test.attrs, nil, nil)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(s.configValidator.validateCfg, gc.IsNil)
+	}
+}
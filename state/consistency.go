@@ -0,0 +1,517 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// InconsistencyKind identifies a class of problem found by
+// CheckConsistency.
+type InconsistencyKind string
+
+const (
+	// UnitWithoutService is reported for a unit whose service does
+	// not exist.
+	UnitWithoutService InconsistencyKind = "unit-without-service"
+
+	// PortsWithoutMachine is reported for opened ports on a machine
+	// that is dead or does not exist. It can be repaired by removing
+	// the ports.
+	PortsWithoutMachine InconsistencyKind = "ports-without-machine"
+
+	// EnvUUIDMismatch is reported for a document whose env-uuid field
+	// does not match the environment its id belongs to.
+	EnvUUIDMismatch InconsistencyKind = "env-uuid-mismatch"
+
+	// OrphanedSettings is reported for the charm settings of a
+	// service that does not exist. It can be repaired by removing
+	// the settings.
+	OrphanedSettings InconsistencyKind = "orphaned-settings"
+
+	// OrphanedLeadershipSettings is reported for the leadership
+	// settings of a service that does not exist. It can be repaired
+	// by removing the settings.
+	OrphanedLeadershipSettings InconsistencyKind = "orphaned-leadership-settings"
+
+	// ZeroRefSettings is reported for settings whose reference count
+	// has dropped to zero without them being removed. It can be
+	// repaired by removing the settings.
+	ZeroRefSettings InconsistencyKind = "zero-ref-settings"
+
+	// OrphanedStatus is reported for the status of a machine, unit or
+	// service that does not exist. It can be repaired by removing the
+	// status.
+	OrphanedStatus InconsistencyKind = "orphaned-status"
+
+	// OrphanedLeadership is reported for a leadership lease held for a
+	// service that does not exist in any environment. It can be
+	// repaired by removing the lease.
+	OrphanedLeadership InconsistencyKind = "orphaned-leadership"
+)
+
+// Inconsistency describes a single problem found by CheckConsistency.
+type Inconsistency struct {
+	Kind InconsistencyKind
+
+	// Collection and DocID identify the document with the problem.
+	Collection string
+	DocID      string
+
+	// Detail describes the problem.
+	Detail string
+
+	// Repairable is true if the problem is one that can be safely
+	// repaired, and Repaired is true if it has been.
+	Repairable bool
+	Repaired   bool
+}
+
+// ConsistencyReport holds the results of CheckConsistency.
+type ConsistencyReport struct {
+	Inconsistencies []Inconsistency
+}
+
+// CheckConsistencyParams holds the parameters for CheckConsistency.
+type CheckConsistencyParams struct {
+	// Repair causes any problems found that can be safely repaired
+	// to be repaired.
+	Repair bool
+
+	// Kinds, if not empty, restricts the problems reported and
+	// repaired to those of the given kinds.
+	Kinds []InconsistencyKind
+}
+
+// consistencyCheck finds inconsistencies of one or more kinds, and
+// returns for each repairable one the transaction operations that
+// will repair it.
+type consistencyCheck func(st *State) ([]Inconsistency, [][]txn.Op, error)
+
+// consistencyChecks holds the checks run by CheckConsistency, in the
+// order in which their results are reported.
+var consistencyChecks = []consistencyCheck{
+	(*State).checkUnitServices,
+	(*State).checkPortsMachines,
+	(*State).checkEnvUUIDs,
+	(*State).checkServiceSettings,
+	(*State).checkSettingsRefs,
+	(*State).checkStatuses,
+	(*State).checkLeadershipLeases,
+}
+
+// CheckConsistency verifies the referential integrity of the documents
+// in the environment, and reports any problems found. If args.Repair
+// is set, problems that can be safely repaired are repaired. Leadership
+// leases are not specific to an environment, so they are only checked
+// when st is connected to the state server environment.
+func (st *State) CheckConsistency(args CheckConsistencyParams) (*ConsistencyReport, error) {
+	kinds := make(map[InconsistencyKind]bool)
+	for _, kind := range args.Kinds {
+		kinds[kind] = true
+	}
+	report := &ConsistencyReport{}
+	for _, check := range consistencyChecks {
+		found, repairs, err := check(st)
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot check consistency")
+		}
+		for i, problem := range found {
+			if len(kinds) > 0 && !kinds[problem.Kind] {
+				continue
+			}
+			if args.Repair && problem.Repairable {
+				// A repair is aborted if the problem has gone away
+				// since it was found, in which case it is still
+				// reported, but not as repaired.
+				err := st.runTransaction(repairs[i])
+				if err == nil {
+					problem.Repaired = true
+				} else if err != txn.ErrAborted {
+					return nil, errors.Annotatef(err, "cannot repair %s %q", problem.Kind, problem.DocID)
+				}
+			}
+			report.Inconsistencies = append(report.Inconsistencies, problem)
+		}
+	}
+	return report, nil
+}
+
+// checkUnitServices reports units whose service does not exist.
+func (st *State) checkUnitServices() ([]Inconsistency, [][]txn.Op, error) {
+	services, err := st.docNames(servicesC, "name")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	units, closer := st.getCollection(unitsC)
+	defer closer()
+
+	var found []Inconsistency
+	var doc unitDoc
+	iter := units.Find(nil).Select(bson.D{{"_id", 1}, {"name", 1}, {"service", 1}}).Iter()
+	for iter.Next(&doc) {
+		if services.Contains(doc.Service) {
+			continue
+		}
+		found = append(found, Inconsistency{
+			Kind:       UnitWithoutService,
+			Collection: unitsC,
+			DocID:      doc.DocID,
+			Detail:     fmt.Sprintf("unit %q belongs to missing service %q", doc.Name, doc.Service),
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read units")
+	}
+	return found, make([][]txn.Op, len(found)), nil
+}
+
+// checkPortsMachines reports opened ports on machines that are dead or
+// do not exist.
+func (st *State) checkPortsMachines() ([]Inconsistency, [][]txn.Op, error) {
+	// The ports are read before the machines, so that ports opened on
+	// machines added in between are not reported.
+	openedPorts, closer := st.getCollection(openedPortsC)
+	defer closer()
+	var pdocs []portsDoc
+	err := openedPorts.Find(nil).Select(bson.D{{"_id", 1}, {"machine-id", 1}}).All(&pdocs)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read opened ports")
+	}
+
+	machines, closer := st.getRawCollection(machinesC)
+	defer closer()
+	machineLife := make(map[string]Life)
+	var mdoc machineDoc
+	iter := machines.Find(st.envIdsSelector()).Select(bson.D{{"machineid", 1}, {"life", 1}}).Iter()
+	for iter.Next(&mdoc) {
+		machineLife[mdoc.Id] = mdoc.Life
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read machines")
+	}
+
+	var found []Inconsistency
+	var repairs [][]txn.Op
+	for _, pdoc := range pdocs {
+		machineOp := txn.Op{
+			C:  machinesC,
+			Id: st.docID(pdoc.MachineID),
+		}
+		var detail string
+		if life, ok := machineLife[pdoc.MachineID]; !ok {
+			detail = fmt.Sprintf("ports opened on missing machine %q", pdoc.MachineID)
+			machineOp.Assert = txn.DocMissing
+		} else if life == Dead {
+			detail = fmt.Sprintf("ports opened on dead machine %q", pdoc.MachineID)
+			machineOp.Assert = isDeadDoc
+		} else {
+			continue
+		}
+		found = append(found, Inconsistency{
+			Kind:       PortsWithoutMachine,
+			Collection: openedPortsC,
+			DocID:      pdoc.DocID,
+			Detail:     detail,
+			Repairable: true,
+		})
+		repairs = append(repairs, []txn.Op{machineOp, {
+			C:      openedPortsC,
+			Id:     pdoc.DocID,
+			Remove: true,
+		}})
+	}
+	return found, repairs, nil
+}
+
+// checkEnvUUIDs reports documents whose ids belong to the environment
+// but whose env-uuid fields do not match it.
+func (st *State) checkEnvUUIDs() ([]Inconsistency, [][]txn.Op, error) {
+	uuid := st.EnvironUUID()
+	sel := append(st.envIdsSelector(), bson.DocElem{"env-uuid", bson.D{{"$ne", uuid}}})
+	names := multiEnvCollections.SortedValues()
+	var found []Inconsistency
+	for _, name := range names {
+		coll, closer := st.getRawCollection(name)
+		var docs []struct {
+			DocID   string `bson:"_id"`
+			EnvUUID string `bson:"env-uuid"`
+		}
+		err := coll.Find(sel).Select(bson.D{{"_id", 1}, {"env-uuid", 1}}).All(&docs)
+		closer()
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot read %s", name)
+		}
+		for _, doc := range docs {
+			found = append(found, Inconsistency{
+				Kind:       EnvUUIDMismatch,
+				Collection: name,
+				DocID:      doc.DocID,
+				Detail:     fmt.Sprintf("env-uuid is %q, expected %q", doc.EnvUUID, uuid),
+			})
+		}
+	}
+	return found, make([][]txn.Op, len(found)), nil
+}
+
+// checkServiceSettings reports charm and leadership settings of
+// services that do not exist.
+func (st *State) checkServiceSettings() ([]Inconsistency, [][]txn.Op, error) {
+	// The settings are read before the services, so that settings
+	// of services added in between are not reported.
+	settingsIds, err := st.docIds(settingsC)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	services, err := st.docNames(servicesC, "name")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	var found []Inconsistency
+	var repairs [][]txn.Op
+	for _, docID := range settingsIds {
+		// Service settings are keyed by serviceSettingsKey, or by
+		// settingsKey for leadership settings.
+		parts := strings.SplitN(st.localID(docID), "#", 3)
+		if len(parts) != 3 || parts[0] != "s" || services.Contains(parts[1]) {
+			continue
+		}
+		problem := Inconsistency{
+			Kind:       OrphanedSettings,
+			Collection: settingsC,
+			DocID:      docID,
+			Detail:     fmt.Sprintf("settings of missing service %q", parts[1]),
+			Repairable: true,
+		}
+		if parts[2] == "leader" {
+			problem.Kind = OrphanedLeadershipSettings
+			problem.Detail = fmt.Sprintf("leadership settings of missing service %q", parts[1])
+		}
+		found = append(found, problem)
+		repairs = append(repairs, []txn.Op{{
+			C:      servicesC,
+			Id:     st.docID(parts[1]),
+			Assert: txn.DocMissing,
+		}, {
+			C:      settingsC,
+			Id:     docID,
+			Remove: true,
+		}, {
+			C:      settingsrefsC,
+			Id:     docID,
+			Remove: true,
+		}})
+	}
+	return found, repairs, nil
+}
+
+// checkSettingsRefs reports settings reference counts that have
+// dropped to zero without being removed.
+func (st *State) checkSettingsRefs() ([]Inconsistency, [][]txn.Op, error) {
+	settingsrefs, closer := st.getCollection(settingsrefsC)
+	defer closer()
+
+	var found []Inconsistency
+	var repairs [][]txn.Op
+	var doc struct {
+		DocID    string `bson:"_id"`
+		RefCount int    `bson:"refcount"`
+	}
+	noRefs := bson.D{{"refcount", bson.D{{"$lte", 0}}}}
+	iter := settingsrefs.Find(noRefs).Select(bson.D{{"_id", 1}, {"refcount", 1}}).Iter()
+	for iter.Next(&doc) {
+		found = append(found, Inconsistency{
+			Kind:       ZeroRefSettings,
+			Collection: settingsrefsC,
+			DocID:      doc.DocID,
+			Detail:     fmt.Sprintf("settings have %d references", doc.RefCount),
+			Repairable: true,
+		})
+		repairs = append(repairs, []txn.Op{{
+			C:      settingsrefsC,
+			Id:     doc.DocID,
+			Assert: noRefs,
+			Remove: true,
+		}, {
+			C:      settingsC,
+			Id:     doc.DocID,
+			Remove: true,
+		}})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read settings references")
+	}
+	return found, repairs, nil
+}
+
+// checkStatuses reports the statuses of machines, units and services
+// that do not exist.
+func (st *State) checkStatuses() ([]Inconsistency, [][]txn.Op, error) {
+	// The statuses are read before their entities, so that statuses
+	// of entities added in between are not reported.
+	statusIds, err := st.docIds(statusesC)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	entities := make(map[string]set.Strings)
+	for _, coll := range []struct{ name, field string }{
+		{machinesC, "machineid"},
+		{unitsC, "name"},
+		{servicesC, "name"},
+	} {
+		names, err := st.docNames(coll.name, coll.field)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		entities[coll.name] = names
+	}
+
+	var found []Inconsistency
+	var repairs [][]txn.Op
+	for _, docID := range statusIds {
+		// Statuses are keyed by the global key of their entity:
+		// machineGlobalKey, serviceGlobalKey, or the unitGlobalKey
+		// and agent key of a unit.
+		parts := strings.SplitN(st.localID(docID), "#", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		var collName string
+		switch {
+		case parts[0] == "m" && len(parts) == 2:
+			collName = machinesC
+		case parts[0] == "u" && (len(parts) == 2 || parts[2] == "charm"):
+			collName = unitsC
+		case parts[0] == "s" && len(parts) == 2:
+			collName = servicesC
+		default:
+			continue
+		}
+		if entities[collName].Contains(parts[1]) {
+			continue
+		}
+		found = append(found, Inconsistency{
+			Kind:       OrphanedStatus,
+			Collection: statusesC,
+			DocID:      docID,
+			Detail:     fmt.Sprintf("status of missing %s %q", strings.TrimSuffix(collName, "s"), parts[1]),
+			Repairable: true,
+		})
+		repairs = append(repairs, []txn.Op{{
+			C:      collName,
+			Id:     st.docID(parts[1]),
+			Assert: txn.DocMissing,
+		}, {
+			C:      statusesC,
+			Id:     docID,
+			Remove: true,
+		}})
+	}
+	return found, repairs, nil
+}
+
+// checkLeadershipLeases reports leadership leases held for services
+// that do not exist in any environment.
+func (st *State) checkLeadershipLeases() ([]Inconsistency, [][]txn.Op, error) {
+	if !st.IsStateServer() {
+		return nil, nil, nil
+	}
+	// Leases are shared between environments, so look for services
+	// in all of them.
+	services, closer := st.getRawCollection(servicesC)
+	var serviceNames []string
+	err := services.Find(nil).Distinct("name", &serviceNames)
+	closer()
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read services")
+	}
+	allServices := set.NewStrings(serviceNames...)
+
+	leases, closer := st.getRawCollection(leaseC)
+	defer closer()
+	var found []Inconsistency
+	var repairs [][]txn.Op
+	var doc struct {
+		DocID string `bson:"_id"`
+	}
+	// Lease documents are keyed by namespace, and the namespace of a
+	// leadership lease is the service name with a "-leadership" suffix.
+	iter := leases.Find(nil).Select(bson.D{{"_id", 1}}).Iter()
+	for iter.Next(&doc) {
+		if !strings.HasSuffix(doc.DocID, "-leadership") {
+			continue
+		}
+		serviceName := strings.TrimSuffix(doc.DocID, "-leadership")
+		if allServices.Contains(serviceName) {
+			continue
+		}
+		found = append(found, Inconsistency{
+			Kind:       OrphanedLeadership,
+			Collection: leaseC,
+			DocID:      doc.DocID,
+			Detail:     fmt.Sprintf("leadership of missing service %q", serviceName),
+			Repairable: true,
+		})
+		repairs = append(repairs, []txn.Op{{
+			C:      leaseC,
+			Id:     doc.DocID,
+			Remove: true,
+		}})
+	}
+	if err := iter.Close(); err != nil {
+		return nil, nil, errors.Annotate(err, "cannot read leases")
+	}
+	return found, repairs, nil
+}
+
+// docIds returns the ids of all the environment's documents in the
+// named collection.
+func (st *State) docIds(collName string) ([]string, error) {
+	coll, closer := st.getCollection(collName)
+	defer closer()
+	var docs []struct {
+		DocID string `bson:"_id"`
+	}
+	if err := coll.Find(nil).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot read %s", collName)
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.DocID
+	}
+	return ids, nil
+}
+
+// envIdsSelector returns a selector that matches the documents whose
+// ids belong to the environment, whatever their env-uuid fields hold.
+func (st *State) envIdsSelector() bson.D {
+	return bson.D{{"_id", bson.D{{"$regex", "^" + st.EnvironUUID() + ":"}}}}
+}
+
+// docNames returns the values of the given string field of all the
+// environment's documents in the named collection. Documents are
+// matched by id rather than env-uuid, so that a document with a
+// mismatched env-uuid does not make its dependents look orphaned.
+func (st *State) docNames(collName, field string) (set.Strings, error) {
+	coll, closer := st.getRawCollection(collName)
+	defer closer()
+	var docs []bson.M
+	if err := coll.Find(st.envIdsSelector()).Select(bson.D{{field, 1}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot read %s", collName)
+	}
+	names := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		if name, ok := doc[field].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return set.NewStrings(names...), nil
+}
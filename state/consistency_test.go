@@ -0,0 +1,205 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state"
+)
+
+type ConsistencySuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ConsistencySuite{})
+
+func (s *ConsistencySuite) checkConsistency(c *gc.C, repair bool) []state.Inconsistency {
+	report, err := s.State.CheckConsistency(state.CheckConsistencyParams{Repair: repair})
+	c.Assert(err, jc.ErrorIsNil)
+	return report.Inconsistencies
+}
+
+func (s *ConsistencySuite) docID(id string) string {
+	return s.State.EnvironUUID() + ":" + id
+}
+
+func (s *ConsistencySuite) TestConsistentEnvironment(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.checkConsistency(c, false), gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) TestUnitWithoutService(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	_, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	services, closer := state.GetRawCollection(s.State, "services")
+	defer closer()
+	err = services.RemoveId(s.docID("wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	found := s.checkConsistency(c, true)
+	c.Assert(found, gc.HasLen, 4)
+	c.Check(found[0], jc.DeepEquals, state.Inconsistency{
+		Kind:       state.UnitWithoutService,
+		Collection: "units",
+		DocID:      s.docID("wordpress/0"),
+		Detail:     `unit "wordpress/0" belongs to missing service "wordpress"`,
+	})
+	var details []string
+	for _, problem := range found[1:] {
+		c.Check(problem.Repaired, jc.IsTrue)
+		details = append(details, string(problem.Kind)+": "+problem.Detail)
+	}
+	c.Check(details, jc.SameContents, []string{
+		`orphaned-settings: settings of missing service "wordpress"`,
+		`orphaned-leadership-settings: leadership settings of missing service "wordpress"`,
+		`orphaned-status: status of missing service "wordpress"`,
+	})
+
+	// Only the unit remains a problem, because it can't be repaired.
+	found = s.checkConsistency(c, false)
+	c.Assert(found, gc.HasLen, 1)
+	c.Check(found[0].Kind, gc.Equals, state.UnitWithoutService)
+}
+
+func (s *ConsistencySuite) TestPortsWithoutMachine(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machines, closer := state.GetRawCollection(s.State, "machines")
+	defer closer()
+	err = machines.RemoveId(s.docID(machineId))
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := state.CheckConsistencyParams{
+		Kinds: []state.InconsistencyKind{state.PortsWithoutMachine},
+	}
+	report, err := s.State.CheckConsistency(args)
+	c.Assert(err, jc.ErrorIsNil)
+	found := report.Inconsistencies
+	c.Assert(found, gc.HasLen, 1)
+	c.Check(found[0].Kind, gc.Equals, state.PortsWithoutMachine)
+	c.Check(found[0].Detail, gc.Equals, `ports opened on missing machine "`+machineId+`"`)
+	c.Check(found[0].Repairable, jc.IsTrue)
+	c.Check(found[0].Repaired, jc.IsFalse)
+
+	args.Repair = true
+	report, err = s.State.CheckConsistency(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Inconsistencies, gc.HasLen, 1)
+	c.Check(report.Inconsistencies[0].Repaired, jc.IsTrue)
+	args.Repair = false
+	report, err = s.State.CheckConsistency(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Inconsistencies, gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) TestOrphanedMachineStatus(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	machines, closer := state.GetRawCollection(s.State, "machines")
+	defer closer()
+	err = machines.RemoveId(s.docID(machine.Id()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	found := s.checkConsistency(c, true)
+	c.Assert(found, gc.HasLen, 1)
+	c.Check(found[0], jc.DeepEquals, state.Inconsistency{
+		Kind:       state.OrphanedStatus,
+		Collection: "statuses",
+		DocID:      s.docID("m#" + machine.Id()),
+		Detail:     `status of missing machine "` + machine.Id() + `"`,
+		Repairable: true,
+		Repaired:   true,
+	})
+	c.Assert(s.checkConsistency(c, false), gc.HasLen, 0)
+}
+
+func (s *ConsistencySuite) TestZeroRefSettings(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	settingsrefs, closer := state.GetRawCollection(s.State, "settingsrefs")
+	defer closer()
+	curl, _ := wordpress.CharmURL()
+	key := s.docID("s#wordpress#" + curl.String())
+	err := settingsrefs.UpdateId(key, bson.D{{"$set", bson.D{{"refcount", 0}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := state.CheckConsistencyParams{
+		Repair: true,
+		Kinds:  []state.InconsistencyKind{state.ZeroRefSettings},
+	}
+	report, err := s.State.CheckConsistency(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Inconsistencies, jc.DeepEquals, []state.Inconsistency{{
+		Kind:       state.ZeroRefSettings,
+		Collection: "settingsrefs",
+		DocID:      key,
+		Detail:     "settings have 0 references",
+		Repairable: true,
+		Repaired:   true,
+	}})
+	n, err := settingsrefs.FindId(key).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+}
+
+func (s *ConsistencySuite) TestEnvUUIDMismatch(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	machines, closer := state.GetRawCollection(s.State, "machines")
+	defer closer()
+	err = machines.UpdateId(s.docID(machine.Id()), bson.D{{"$set", bson.D{{"env-uuid", "wrong"}}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	found := s.checkConsistency(c, true)
+	c.Assert(found, gc.HasLen, 1)
+	c.Check(found[0], jc.DeepEquals, state.Inconsistency{
+		Kind:       state.EnvUUIDMismatch,
+		Collection: "machines",
+		DocID:      s.docID(machine.Id()),
+		Detail:     `env-uuid is "wrong", expected "` + s.State.EnvironUUID() + `"`,
+	})
+}
+
+func (s *ConsistencySuite) TestOrphanedLeadership(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	for _, id := range []string{"wordpress-leadership", "ghost-leadership"} {
+		err := state.RunTransaction(s.State, []txn.Op{{
+			C:      "lease",
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: bson.D{},
+		}})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	found := s.checkConsistency(c, true)
+	c.Assert(found, gc.HasLen, 1)
+	c.Check(found[0], jc.DeepEquals, state.Inconsistency{
+		Kind:       state.OrphanedLeadership,
+		Collection: "lease",
+		DocID:      "ghost-leadership",
+		Detail:     `leadership of missing service "ghost"`,
+		Repairable: true,
+		Repaired:   true,
+	})
+	c.Assert(s.checkConsistency(c, false), gc.HasLen, 0)
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// DestroyBlockerKind identifies a class of condition that prevents an
+// environment from being destroyed.
+type DestroyBlockerKind string
+
+const (
+	// ManualMachinesBlocker is reported when the environment has
+	// manually provisioned machines that are not state servers.
+	// Forcing past it leaves those machines running, with their
+	// agents orphaned.
+	ManualMachinesBlocker DestroyBlockerKind = "manual-machines"
+
+	// PersistentStorageBlocker is reported when the environment has
+	// persistent volumes and the caller has not said whether they
+	// should be destroyed or released. Forcing past it leaves the
+	// volumes in the cloud, no longer managed by Juju.
+	PersistentStorageBlocker DestroyBlockerKind = "persistent-storage"
+
+	// HostedEnvironmentsBlocker is reported when the state server
+	// environment still hosts other environments. It cannot be
+	// forced past.
+	HostedEnvironmentsBlocker DestroyBlockerKind = "hosted-environments"
+)
+
+// forceableDestroyBlockers holds the kinds of blocker that callers of
+// DestroyWithParams may choose to ignore.
+var forceableDestroyBlockers = map[DestroyBlockerKind]bool{
+	ManualMachinesBlocker:    true,
+	PersistentStorageBlocker: true,
+}
+
+// DestroyBlocker describes a single reason why an environment cannot
+// be destroyed.
+type DestroyBlocker struct {
+	Kind DestroyBlockerKind
+	Err  error
+}
+
+// DestroyBlockedError is returned when an environment cannot be
+// destroyed. It holds every blocker found, so that they can all be
+// dealt with at once.
+type DestroyBlockedError struct {
+	Blockers []DestroyBlocker
+}
+
+// Error is part of the error interface. When there is only one
+// blocker its message is reported unchanged.
+func (e *DestroyBlockedError) Error() string {
+	if len(e.Blockers) == 1 {
+		return e.Blockers[0].Err.Error()
+	}
+	msgs := make([]string, len(e.Blockers))
+	for i, blocker := range e.Blockers {
+		msgs[i] = fmt.Sprintf("%s: %s", blocker.Kind, strings.TrimSpace(blocker.Err.Error()))
+	}
+	return fmt.Sprintf("environment has %d blockers:\n%s", len(msgs), strings.Join(msgs, "\n"))
+}
+
+// Cause returns the error of the only blocker, if there is exactly one,
+// so that errors.Cause can be compared against errors like
+// ErrPersistentVolumesExist. Otherwise it returns nil, and errors.Cause
+// returns the DestroyBlockedError itself.
+func (e *DestroyBlockedError) Cause() error {
+	if len(e.Blockers) == 1 {
+		return e.Blockers[0].Err
+	}
+	return nil
+}
+
+// destroyCheck looks for blockers of a single kind. Its check func
+// returns a non-nil blocker error describing what prevents the
+// environment from being destroyed, or a non-nil err if it could not
+// find out.
+type destroyCheck struct {
+	kind  DestroyBlockerKind
+	check func(e *Environment, args DestroyEnvironmentParams) (blocker error, err error)
+}
+
+// destroyChecks holds the checks run before an environment is
+// destroyed, in the order in which their blockers are reported.
+var destroyChecks = []destroyCheck{
+	{ManualMachinesBlocker, (*Environment).checkNoManualMachines},
+	{PersistentStorageBlocker, (*Environment).checkNoUnhandledPersistentVolumes},
+	{HostedEnvironmentsBlocker, (*Environment).checkNoHostedEnvironments},
+}
+
+// validateForce returns an error if any of the kinds of blocker that
+// the caller has asked to ignore is unknown or cannot be forced past.
+func validateForce(force []DestroyBlockerKind) error {
+	for _, kind := range force {
+		if !forceableDestroyBlockers[kind] {
+			return errors.NotValidf("forcing past %q blocker", kind)
+		}
+	}
+	return nil
+}
+
+// destroyBlockers runs each destroy check that the caller has not
+// asked to ignore, and returns a *DestroyBlockedError holding all the
+// blockers found, or nil if there are none.
+func (e *Environment) destroyBlockers(args DestroyEnvironmentParams) error {
+	ignored := make(map[DestroyBlockerKind]bool)
+	for _, kind := range args.Force {
+		ignored[kind] = true
+	}
+	var blockers []DestroyBlocker
+	for _, check := range destroyChecks {
+		if ignored[check.kind] {
+			continue
+		}
+		blocker, err := check.check(e, args)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if blocker != nil {
+			blockers = append(blockers, DestroyBlocker{check.kind, blocker})
+		}
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+	return &DestroyBlockedError{Blockers: blockers}
+}
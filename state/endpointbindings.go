@@ -0,0 +1,140 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// endpointBindingsDoc records the spaces to which the endpoints of a
+// service are bound. Endpoints without a binding are not bound to any
+// particular space.
+type endpointBindingsDoc struct {
+	DocID    string            `bson:"_id"`
+	EnvUUID  string            `bson:"env-uuid"`
+	Bindings map[string]string `bson:"bindings"`
+}
+
+// SpaceSubnets returns the subnets in the named space. An error
+// satisfying errors.IsNotFound is returned if there are none.
+func (st *State) SpaceSubnets(spaceName string) ([]*Subnet, error) {
+	subnets, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	if err := subnets.Find(bson.D{{"spacename", spaceName}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets in space %q", spaceName)
+	}
+	if len(docs) == 0 {
+		return nil, errors.NotFoundf("space %q", spaceName)
+	}
+	result := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		result[i] = &Subnet{st, doc}
+	}
+	return result, nil
+}
+
+// EndpointBindings returns the names of the spaces to which the
+// service's endpoints are bound, keyed on endpoint name.
+func (s *Service) EndpointBindings() (map[string]string, error) {
+	bindings, closer := s.st.getCollection(endpointBindingsC)
+	defer closer()
+
+	var doc endpointBindingsDoc
+	err := bindings.FindId(s.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get endpoint bindings for service %q", s)
+	}
+	if doc.Bindings == nil {
+		doc.Bindings = map[string]string{}
+	}
+	return doc.Bindings, nil
+}
+
+// SetEndpointBindings replaces the service's endpoint bindings with the
+// given ones, which map the names of the service's endpoints to the
+// spaces they are bound to. Every space must contain at least one
+// subnet.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		endpoints, err := s.Endpoints()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		known := make(map[string]bool)
+		for _, ep := range endpoints {
+			known[ep.Name] = true
+		}
+		for endpoint, space := range bindings {
+			if !known[endpoint] {
+				return nil, errors.NotValidf("endpoint %q", endpoint)
+			}
+			if _, err := s.st.SpaceSubnets(space); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"charmurl", s.doc.CharmURL}},
+		}}
+		exists, err := s.hasEndpointBindings()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		switch {
+		case !exists && len(bindings) == 0:
+			return nil, jujutxn.ErrNoOperations
+		case !exists:
+			ops = append(ops, txn.Op{
+				C:      endpointBindingsC,
+				Id:     s.globalKey(),
+				Assert: txn.DocMissing,
+				Insert: &endpointBindingsDoc{Bindings: bindings},
+			})
+		case len(bindings) == 0:
+			ops = append(ops, removeEndpointBindingsOp(s.globalKey()))
+		default:
+			ops = append(ops, txn.Op{
+				C:      endpointBindingsC,
+				Id:     s.globalKey(),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"bindings", bindings}}}},
+			})
+		}
+		return ops, nil
+	}
+	return s.st.run(buildTxn)
+}
+
+func (s *Service) hasEndpointBindings() (bool, error) {
+	bindings, closer := s.st.getCollection(endpointBindingsC)
+	defer closer()
+	count, err := bindings.FindId(s.globalKey()).Count()
+	return count > 0, err
+}
+
+func removeEndpointBindingsOp(key string) txn.Op {
+	return txn.Op{
+		C:      endpointBindingsC,
+		Id:     key,
+		Remove: true,
+	}
+}
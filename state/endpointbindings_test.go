@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type EndpointBindingsSuite struct {
+	ConnSuite
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&EndpointBindingsSuite{})
+
+func (s *EndpointBindingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	for cidr, space := range map[string]string{
+		"10.0.0.0/24":    "internal",
+		"10.0.1.0/24":    "internal",
+		"192.168.0.0/24": "public",
+	} {
+		_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: cidr, SpaceName: space})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *EndpointBindingsSuite) TestSpaceSubnets(c *gc.C) {
+	subnets, err := s.State.SpaceSubnets("internal")
+	c.Assert(err, jc.ErrorIsNil)
+	var cidrs []string
+	for _, subnet := range subnets {
+		cidrs = append(cidrs, subnet.CIDR())
+	}
+	c.Assert(cidrs, jc.SameContents, []string{"10.0.0.0/24", "10.0.1.0/24"})
+
+	_, err = s.State.SpaceSubnets("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EndpointBindingsSuite) TestNoBindings(c *gc.C) {
+	bindings, err := s.wordpress.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, gc.HasLen, 0)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindings(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{
+		"db":  "internal",
+		"url": "public",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	bindings, err := s.wordpress.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, jc.DeepEquals, map[string]string{
+		"db":  "internal",
+		"url": "public",
+	})
+
+	// Setting bindings again replaces them.
+	err = s.wordpress.SetEndpointBindings(map[string]string{"juju-info": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	bindings, err = s.wordpress.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, jc.DeepEquals, map[string]string{"juju-info": "internal"})
+
+	err = s.wordpress.SetEndpointBindings(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	bindings, err = s.wordpress.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, gc.HasLen, 0)
+}
+
+func (s *EndpointBindingsSuite) TestSetEndpointBindingsErrors(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{"foo": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": endpoint "foo" not valid`)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "missing"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": space "missing" not found`)
+
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, gc.ErrorMatches, `cannot set endpoint bindings for service "wordpress": not found or not alive`)
+}
+
+func (s *EndpointBindingsSuite) TestRemoveServiceRemovesBindings(c *gc.C) {
+	err := s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A new service of the same name starts without bindings.
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	bindings, err := wordpress.EndpointBindings()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bindings, gc.HasLen, 0)
+}
@@ -0,0 +1,193 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// EnvironmentInvitation represents an offer of access to an
+// environment that has not yet been accepted. The invited user is
+// only given access, as an EnvironmentUser, once they accept it.
+type EnvironmentInvitation struct {
+	st  *State
+	doc envInvitationDoc
+}
+
+type envInvitationDoc struct {
+	ID          string    `bson:"_id"`
+	EnvUUID     string    `bson:"env-uuid"`
+	UserName    string    `bson:"user"`
+	CreatedBy   string    `bson:"createdby"`
+	DateCreated time.Time `bson:"datecreated"`
+}
+
+// EnvironmentTag returns the environment tag of the invitation.
+func (i *EnvironmentInvitation) EnvironmentTag() names.EnvironTag {
+	return names.NewEnvironTag(i.doc.EnvUUID)
+}
+
+// UserTag returns the tag of the invited user.
+func (i *EnvironmentInvitation) UserTag() names.UserTag {
+	return names.NewUserTag(i.doc.UserName)
+}
+
+// UserName returns the user name of the invited user.
+func (i *EnvironmentInvitation) UserName() string {
+	return i.doc.UserName
+}
+
+// CreatedBy returns the user who created the invitation.
+func (i *EnvironmentInvitation) CreatedBy() string {
+	return i.doc.CreatedBy
+}
+
+// DateCreated returns the date the invitation was created in UTC.
+func (i *EnvironmentInvitation) DateCreated() time.Time {
+	return i.doc.DateCreated.UTC()
+}
+
+// InviteEnvironmentUser records an invitation for the given user to
+// access the environment. Unlike AddEnvironmentUser, the user need not
+// yet exist; no access is granted until the invitation is accepted
+// with AcceptEnvironmentInvitation.
+func (st *State) InviteEnvironmentUser(user, createdBy names.UserTag) (_ *EnvironmentInvitation, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot invite user %q", user.Username())
+
+	if createdBy.IsLocal() {
+		if _, err := st.User(createdBy); err != nil {
+			return nil, errors.Annotate(err, fmt.Sprintf("createdBy user %q does not exist locally", createdBy.Name()))
+		}
+	}
+	if _, err := st.EnvironmentUser(user); err == nil {
+		return nil, errors.AlreadyExistsf("environment user %q", user.Username())
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+
+	id := strings.ToLower(user.Username())
+	doc := &envInvitationDoc{
+		ID:          id,
+		EnvUUID:     st.EnvironUUID(),
+		UserName:    user.Username(),
+		CreatedBy:   createdBy.Username(),
+		DateCreated: nowToTheSecond(),
+	}
+	ops := []txn.Op{{
+		C:      envUsersC,
+		Id:     id,
+		Assert: txn.DocMissing,
+	}, {
+		C:      envInvitationsC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.EnvironmentUser(user); err == nil {
+			return nil, errors.AlreadyExistsf("environment user %q", user.Username())
+		}
+		return nil, errors.AlreadyExistsf("invitation for %q", user.Username())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &EnvironmentInvitation{st: st, doc: *doc}, nil
+}
+
+// EnvironmentInvitation returns the pending invitation for the given
+// user to access the environment.
+func (st *State) EnvironmentInvitation(user names.UserTag) (*EnvironmentInvitation, error) {
+	invitations, closer := st.getCollection(envInvitationsC)
+	defer closer()
+
+	invitation := &EnvironmentInvitation{st: st}
+	err := invitations.FindId(strings.ToLower(user.Username())).One(&invitation.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("invitation for %q", user.Username())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return invitation, nil
+}
+
+// AcceptEnvironmentInvitation accepts the pending invitation for the
+// given user, removing it and adding the user to the environment.
+func (st *State) AcceptEnvironmentInvitation(user names.UserTag) (_ *EnvironmentUser, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot accept invitation for %q", user.Username())
+
+	invitation, err := st.EnvironmentInvitation(user)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var displayName string
+	if user.IsLocal() {
+		localUser, err := st.User(user)
+		if err != nil {
+			return nil, errors.Annotate(err, fmt.Sprintf("user %q does not exist locally", user.Name()))
+		}
+		displayName = localUser.DisplayName()
+	}
+
+	createdBy := names.NewUserTag(invitation.CreatedBy())
+	op, doc := createEnvUserOpAndDoc(st.EnvironUUID(), user, createdBy, displayName)
+	ops := []txn.Op{{
+		C:      envInvitationsC,
+		Id:     strings.ToLower(user.Username()),
+		Assert: txn.DocExists,
+		Remove: true,
+	}, op}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		if _, err := st.EnvironmentUser(user); err == nil {
+			return nil, errors.AlreadyExistsf("environment user %q", user.Username())
+		}
+		return nil, errors.NotFoundf("invitation for %q", user.Username())
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &EnvironmentUser{st: st, doc: *doc}, nil
+}
+
+// RemoveEnvironmentInvitation withdraws the pending invitation for the
+// given user.
+func (st *State) RemoveEnvironmentInvitation(user names.UserTag) error {
+	ops := []txn.Op{{
+		C:      envInvitationsC,
+		Id:     strings.ToLower(user.Username()),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("invitation for %q", user.Username())
+	}
+	return errors.Trace(err)
+}
+
+// InvitationsForUser returns the pending invitations for the given user
+// across all environments.
+func (st *State) InvitationsForUser(user names.UserTag) ([]*EnvironmentInvitation, error) {
+	// A raw collection is required to support queries across
+	// multiple environments.
+	invitations, closer := st.getRawCollection(envInvitationsC)
+	defer closer()
+
+	var docs []envInvitationDoc
+	err := invitations.Find(bson.D{{"user", user.Username()}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*EnvironmentInvitation, len(docs))
+	for i, doc := range docs {
+		result[i] = &EnvironmentInvitation{st: st, doc: doc}
+	}
+	return result, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing/factory"
+)
+
+type EnvInvitationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&EnvInvitationSuite{})
+
+func (s *EnvInvitationSuite) TestInviteEnvironmentUser(c *gc.C) {
+	createdBy := s.factory.MakeUser(c, &factory.UserParams{Name: "createdby"})
+	// The invited user need not exist yet.
+	bob := names.NewUserTag("bob")
+	invitation, err := s.State.InviteEnvironmentUser(bob, createdBy.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitation.EnvironmentTag(), gc.Equals, s.envTag)
+	c.Assert(invitation.UserTag(), gc.Equals, bob)
+	c.Assert(invitation.UserName(), gc.Equals, "bob@local")
+	c.Assert(invitation.CreatedBy(), gc.Equals, "createdby@local")
+
+	invitation, err = s.State.EnvironmentInvitation(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitation.UserName(), gc.Equals, "bob@local")
+
+	// No access is granted until the invitation is accepted.
+	_, err = s.State.EnvironmentUser(bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvInvitationSuite) TestInviteEnvironmentUserErrors(c *gc.C) {
+	owner := s.Owner
+	user := s.factory.MakeUser(c, &factory.UserParams{Name: "shared"})
+	_, err := s.State.InviteEnvironmentUser(user.UserTag(), owner)
+	c.Assert(err, gc.ErrorMatches, `cannot invite user "shared@local": environment user "shared@local" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+
+	bob := names.NewUserTag("bob")
+	_, err = s.State.InviteEnvironmentUser(bob, owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.InviteEnvironmentUser(bob, owner)
+	c.Assert(err, gc.ErrorMatches, `cannot invite user "bob@local": invitation for "bob@local" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+
+	_, err = s.State.InviteEnvironmentUser(names.NewUserTag("sam"), names.NewUserTag("nobody"))
+	c.Assert(err, gc.ErrorMatches, `cannot invite user "sam@local": createdBy user "nobody" does not exist locally: .*`)
+}
+
+func (s *EnvInvitationSuite) TestAcceptEnvironmentInvitation(c *gc.C) {
+	user := s.factory.MakeUser(c, &factory.UserParams{
+		Name:        "bob",
+		DisplayName: "Bob Brown",
+		NoEnvUser:   true,
+	})
+	_, err := s.State.InviteEnvironmentUser(user.UserTag(), s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	envUser, err := s.State.AcceptEnvironmentInvitation(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.UserName(), gc.Equals, "bob@local")
+	c.Assert(envUser.DisplayName(), gc.Equals, "Bob Brown")
+	c.Assert(envUser.CreatedBy(), gc.Equals, s.Owner.Username())
+
+	_, err = s.State.EnvironmentUser(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnvironmentInvitation(user.UserTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.AcceptEnvironmentInvitation(user.UserTag())
+	c.Assert(err, gc.ErrorMatches, `cannot accept invitation for "bob@local": invitation for "bob@local" not found`)
+}
+
+func (s *EnvInvitationSuite) TestAcceptEnvironmentInvitationUnknownUser(c *gc.C) {
+	bob := names.NewUserTag("bob")
+	_, err := s.State.InviteEnvironmentUser(bob, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AcceptEnvironmentInvitation(bob)
+	c.Assert(err, gc.ErrorMatches, `cannot accept invitation for "bob@local": user "bob" does not exist locally: .*`)
+
+	// The invitation is left in place for once the user exists.
+	_, err = s.State.EnvironmentInvitation(bob)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *EnvInvitationSuite) TestRemoveEnvironmentInvitation(c *gc.C) {
+	bob := names.NewUserTag("bob")
+	_, err := s.State.InviteEnvironmentUser(bob, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveEnvironmentInvitation(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnvironmentInvitation(bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.RemoveEnvironmentInvitation(bob)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvInvitationSuite) TestInvitationsForUser(c *gc.C) {
+	bob := names.NewUserTag("bob")
+	invitations, err := s.State.InvitationsForUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitations, gc.HasLen, 0)
+
+	_, err = s.State.InviteEnvironmentUser(bob, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	otherState := s.factory.MakeEnvironment(c, nil)
+	defer otherState.Close()
+	_, err = otherState.InviteEnvironmentUser(bob, s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+
+	invitations, err = s.State.InvitationsForUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(invitations, gc.HasLen, 2)
+	envUUIDs := []string{
+		invitations[0].EnvironmentTag().Id(),
+		invitations[1].EnvironmentTag().Id(),
+	}
+	c.Assert(envUUIDs, jc.SameContents, []string{s.State.EnvironUUID(), otherState.EnvironUUID()})
+}
@@ -32,6 +32,16 @@ type environmentDoc struct {
 	Life       Life
 	Owner      string `bson:"owner"`
 	ServerUUID string `bson:"server-uuid"`
+
+	// CloudCredential holds the name of the cloud credential used by
+	// the environment, if its credentials are not held in its settings.
+	CloudCredential string `bson:"cloud-credential,omitempty"`
+
+	// Cloud and CloudRegion hold the names of the cloud and region the
+	// environment is hosted in, if it is not hosted in the same cloud
+	// as the state server.
+	Cloud       string `bson:"cloud,omitempty"`
+	CloudRegion string `bson:"cloud-region,omitempty"`
 }
 
 // StateServerEnvironment returns the environment that was bootstrapped.
@@ -81,6 +91,22 @@ func (st *State) GetEnvironment(tag names.EnvironTag) (*Environment, error) {
 	return env, nil
 }
 
+// AllEnvironments returns all the environments in the system.
+func (st *State) AllEnvironments() ([]*Environment, error) {
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var envDocs []environmentDoc
+	if err := environments.Find(nil).All(&envDocs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]*Environment, len(envDocs))
+	for i, doc := range envDocs {
+		result[i] = &Environment{st: st, doc: doc}
+	}
+	return result, nil
+}
+
 // NewEnvironment creates a new environment with its own UUID and
 // prepares it for use. Environment and State instances for the new
 // environment are returned.
@@ -90,7 +116,18 @@ func (st *State) GetEnvironment(tag names.EnvironTag) (*Environment, error) {
 // environment document means that we have a way to represent external
 // environments, perhaps for future use around cross environment
 // relations.
-func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Environment, _ *State, err error) {
+func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (*Environment, *State, error) {
+	return st.NewEnvironmentInCloud(cfg, owner, "", "")
+}
+
+// NewEnvironmentInCloud creates a new environment, as NewEnvironment
+// does, hosted in the named cloud and region. The cloud is recorded in
+// the same transaction that creates the environment. If cloudName is
+// empty, the environment is hosted in the same cloud as the state
+// server.
+func (st *State) NewEnvironmentInCloud(
+	cfg *config.Config, owner names.UserTag, cloudName, region string,
+) (_ *Environment, _ *State, err error) {
 	if owner.IsLocal() {
 		if _, err := st.User(owner); err != nil {
 			return nil, nil, errors.Annotate(err, "cannot create environment")
@@ -116,10 +153,17 @@ func (st *State) NewEnvironment(cfg *config.Config, owner names.UserTag) (_ *Env
 		}
 	}()
 
-	ops, err := newState.envSetupOps(cfg, uuid, ssEnv.UUID(), owner)
+	ops, err := newState.envSetupOps(cfg, uuid, ssEnv.UUID(), owner, cloudName, region)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "failed to create new environment")
 	}
+	if cloudName != "" {
+		cloudOp, err := st.assertEnvironCloudOp(cfg.Type(), cloudName, region)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "cannot create environment in cloud %q", cloudName)
+		}
+		ops = append(ops, cloudOp)
+	}
 	err = newState.runTransactionNoEnvAliveAssert(ops)
 	if err == txn.ErrAborted {
 
@@ -198,6 +242,19 @@ func (e *Environment) Owner() names.UserTag {
 	return names.NewUserTag(e.doc.Owner)
 }
 
+// CloudCredential returns the name of the cloud credential used by
+// the environment, and whether it uses one.
+func (e *Environment) CloudCredential() (string, bool) {
+	return e.doc.CloudCredential, e.doc.CloudCredential != ""
+}
+
+// Cloud returns the names of the cloud and region the environment is
+// hosted in, and whether they are set. Environments without a cloud
+// are hosted in the same cloud as the state server.
+func (e *Environment) Cloud() (cloud, region string, ok bool) {
+	return e.doc.Cloud, e.doc.CloudRegion, e.doc.Cloud != ""
+}
+
 // Config returns the config for the environment.
 func (e *Environment) Config() (*config.Config, error) {
 	if e.st.environTag.Id() == e.UUID() {
@@ -254,15 +311,42 @@ func (e *Environment) Users() ([]*EnvironmentUser, error) {
 	return envUsers, nil
 }
 
+// DestroyEnvironmentParams contains parameters for destroying an
+// environment.
+type DestroyEnvironmentParams struct {
+	// DestroyStorage controls what happens to the environment's
+	// persistent volumes. If it is nil, the environment cannot be
+	// destroyed while there are any. Otherwise the volumes are made
+	// Dying along with the environment: if it points to true they are
+	// destroyed, and if false they are released, which detaches them
+	// and leaves them in the cloud.
+	DestroyStorage *bool
+
+	// Force holds the kinds of blocker that should not prevent the
+	// environment from being destroyed. HostedEnvironmentsBlocker
+	// cannot be forced past.
+	Force []DestroyBlockerKind
+}
+
 // Destroy sets the environment's lifecycle to Dying, preventing
-// addition of services or machines to state.
-func (e *Environment) Destroy() (err error) {
+// addition of services or machines to state. It fails with
+// a *DestroyBlockedError if anything prevents that, such as
+// persistent volumes; use DestroyWithParams to say what should become
+// of them, or to force past blockers.
+func (e *Environment) Destroy() error {
+	return e.DestroyWithParams(DestroyEnvironmentParams{})
+}
+
+// DestroyWithParams sets the environment's lifecycle to Dying,
+// preventing addition of services or machines to state, and deals
+// with the environment's persistent volumes as args specifies.
+func (e *Environment) DestroyWithParams(args DestroyEnvironmentParams) (err error) {
 	defer errors.DeferredAnnotatef(&err, "failed to destroy environment")
 	if e.Life() != Alive {
 		return nil
 	}
 
-	if err := e.ensureDestroyable(); err != nil {
+	if err := e.ensureDestroyable(args); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -275,14 +359,14 @@ func (e *Environment) Destroy() (err error) {
 
 	// Check that no new environments or machines were added between the first
 	// check and the Environment.startDestroy().
-	if err := e.ensureDestroyable(); err != nil {
+	if err := e.ensureDestroyable(args); err != nil {
 		if abortErr := e.abortDestroy(); abortErr != nil {
 			return errors.Annotate(abortErr, err.Error())
 		}
 		return errors.Trace(err)
 	}
 
-	if err := e.finishDestroy(); err != nil {
+	if err := e.finishDestroy(args); err != nil {
 		if abortErr := e.abortDestroy(); abortErr != nil {
 			return errors.Annotate(abortErr, err.Error())
 		}
@@ -309,7 +393,20 @@ func (e *Environment) startDestroy() error {
 	return nil
 }
 
-func (e *Environment) finishDestroy() error {
+func (e *Environment) finishDestroy(args DestroyEnvironmentParams) error {
+	var ops []txn.Op
+
+	// If the caller has said what should become of persistent volumes,
+	// make them Dying too; the storage provisioner will destroy or
+	// release them.
+	if args.DestroyStorage != nil {
+		volumeOps, err := e.st.destroyPersistentVolumesOps(!*args.DestroyStorage)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ops = append(ops, volumeOps...)
+	}
+
 	// We add a cleanup for services, but not for machines; machines are
 	// destroyed via the provider interface. The exception to this rule is
 	// manual machines; the API prevents destroy-environment from succeeding
@@ -319,10 +416,12 @@ func (e *Environment) finishDestroy() error {
 	// RemoveAllEnvironDocs() at the end of apiserver/client.Destroy() removes
 	// these documents for us.
 	if e.UUID() == e.doc.ServerUUID {
-		ops := []txn.Op{e.st.newCleanupOp(cleanupServicesForDyingEnvironment, "")}
-		return e.st.runTransaction(ops)
+		ops = append(ops, e.st.newCleanupOp(cleanupServicesForDyingEnvironment, ""))
 	}
-	return nil
+	if len(ops) == 0 {
+		return nil
+	}
+	return e.st.runTransaction(ops)
 }
 
 func (e *Environment) abortDestroy() error {
@@ -346,7 +445,7 @@ func (e *Environment) abortDestroy() error {
 // manually provisioned, and are non-manager machines. These machines
 // must (currently) be manually destroyed via destroy-machine before
 // destroy-environment can successfully complete.
-func checkManualMachines(machines []*Machine) error {
+func checkManualMachines(machines []*Machine) (blocker error, err error) {
 	var ids []string
 	for _, m := range machines {
 		if m.IsManager() {
@@ -354,71 +453,88 @@ func checkManualMachines(machines []*Machine) error {
 		}
 		manual, err := m.IsManual()
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		if manual {
 			ids = append(ids, m.Id())
 		}
 	}
 	if len(ids) > 0 {
-		return errors.Errorf("manually provisioned machines must first be destroyed with `juju destroy-machine %s`", strings.Join(ids, " "))
+		return errors.Errorf("manually provisioned machines must first be destroyed with `juju destroy-machine %s`", strings.Join(ids, " ")), nil
 	}
-	return nil
+	return nil, nil
 }
 
-// ensureDestroyable returns an error if there is more than one environment and the
-// environment to be destroyed is the state server environment.
-func (e *Environment) ensureDestroyable() error {
-	// after another client checks. Destroy-environment will
-	// still fail, but the environment will be in a state where
-	// entities can only be destroyed.
-
-	// First, check for manual machines. We bail out if there are any,
-	// to stop the user from prematurely hobbling the environment.
+// checkNoManualMachines reports a blocker if there are any manual
+// machines, to stop the user from prematurely hobbling the environment.
+func (e *Environment) checkNoManualMachines(args DestroyEnvironmentParams) (error, error) {
 	machines, err := e.st.AllMachines()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
+	return checkManualMachines(machines)
+}
 
-	if err := checkManualMachines(machines); err != nil {
-		return errors.Trace(err)
+// checkNoUnhandledPersistentVolumes reports a blocker if there are any
+// persistent volumes and the caller has not said what should become
+// of them.
+func (e *Environment) checkNoUnhandledPersistentVolumes(args DestroyEnvironmentParams) (error, error) {
+	if args.DestroyStorage != nil {
+		return nil, nil
 	}
-
-	// If there are any persistent volumes, the environment can't be destroyed.
 	volumes, err := e.st.PersistentVolumes()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if len(volumes) > 0 {
-		return ErrPersistentVolumesExist
+		return ErrPersistentVolumesExist, nil
 	}
+	return nil, nil
+}
 
-	// If this is not the state server environment, it can be destroyed
+// checkNoHostedEnvironments reports a blocker if the environment is
+// the state server environment and there are any other environments.
+func (e *Environment) checkNoHostedEnvironments(args DestroyEnvironmentParams) (error, error) {
 	if e.doc.UUID != e.doc.ServerUUID {
-		return nil
+		return nil, nil
 	}
-
 	environments, closer := e.st.getCollection(environmentsC)
 	defer closer()
 	n, err := environments.Count()
 	if err != nil {
-		return errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 	if n > 1 {
-		return errors.Errorf("state server environment cannot be destroyed before all other environments are destroyed")
+		return errors.Errorf("state server environment cannot be destroyed before all other environments are destroyed"), nil
 	}
-	return nil
+	return nil, nil
+}
+
+// ensureDestroyable returns a *DestroyBlockedError if anything that
+// the caller has not chosen to force past prevents the environment
+// from being destroyed.
+func (e *Environment) ensureDestroyable(args DestroyEnvironmentParams) error {
+	// This is called both before and after the environment is made
+	// Dying, to catch entities added after another client checks.
+	// Destroy-environment will still fail, but the environment will
+	// be in a state where entities can only be destroyed.
+	if err := validateForce(args.Force); err != nil {
+		return errors.Trace(err)
+	}
+	return e.destroyBlockers(args)
 }
 
 // createEnvironmentOp returns the operation needed to create
 // an environment document with the given name and UUID.
-func createEnvironmentOp(st *State, owner names.UserTag, name, uuid, server string) txn.Op {
+func createEnvironmentOp(st *State, owner names.UserTag, name, uuid, server, cloud, region string) txn.Op {
 	doc := &environmentDoc{
-		UUID:       uuid,
-		Name:       name,
-		Life:       Alive,
-		Owner:      owner.Username(),
-		ServerUUID: server,
+		UUID:        uuid,
+		Name:        name,
+		Life:        Alive,
+		Owner:       owner.Username(),
+		ServerUUID:  server,
+		Cloud:       cloud,
+		CloudRegion: region,
 	}
 	return txn.Op{
 		C:      environmentsC,
@@ -294,3 +294,103 @@ func (s *EnvironSuite) TestDestroyEnvironmentWithPersistentVolumesFails(c *gc.C)
 	// TODO(wallyworld) when we can destroy/remove volume, ensure env can then be destroyed
 	c.Assert(errors.Cause(env.Destroy()), gc.Equals, state.ErrPersistentVolumesExist)
 }
+
+func (s *EnvironSuite) addPersistentVolume(c *gc.C) names.VolumeTag {
+	registry.RegisterEnvironStorageProviders("someprovider", ec2.EBS_ProviderType)
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("persistent-block", ec2.EBS_ProviderType, map[string]interface{}{"persistent": "true"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "storage-block2")
+	storage := map[string]state.StorageConstraints{
+		"multi1to10": makeStorageCons("persistent-block", 1024, 1),
+	}
+	service := s.AddTestingServiceWithStorage(c, "storage-block2", ch, storage)
+	unit, err := service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	volume, err := s.State.StorageInstanceVolume(names.NewStorageTag("multi1to10/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{Size: 123, Persistent: true})
+	c.Assert(err, jc.ErrorIsNil)
+	return volume.VolumeTag()
+}
+
+func (s *EnvironSuite) testDestroyEnvironmentWithPersistentVolumes(c *gc.C, destroyStorage bool) {
+	volumeTag := s.addPersistentVolume(c)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.DestroyWithParams(state.DestroyEnvironmentParams{
+		DestroyStorage: &destroyStorage,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dying)
+
+	volume, err := s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.Life(), gc.Equals, state.Dying)
+	c.Assert(volume.Releasing(), gc.Equals, !destroyStorage)
+}
+
+func (s *EnvironSuite) TestDestroyEnvironmentDestroyingPersistentVolumes(c *gc.C) {
+	s.testDestroyEnvironmentWithPersistentVolumes(c, true)
+}
+
+func (s *EnvironSuite) TestDestroyEnvironmentReleasingPersistentVolumes(c *gc.C) {
+	s.testDestroyEnvironmentWithPersistentVolumes(c, false)
+}
+
+func (s *EnvironSuite) TestDestroyEnvironmentReportsAllBlockers(c *gc.C) {
+	s.addPersistentVolume(c)
+	st2 := s.factory.MakeEnvironment(c, nil)
+	defer st2.Close()
+
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.Destroy()
+	c.Assert(err, gc.ErrorMatches, "(?s)failed to destroy environment: environment has 2 blockers:\n"+
+		"persistent-storage: Environment cannot be destroyed until all persistent volumes have been destroyed,.*\n"+
+		"hosted-environments: state server environment cannot be destroyed before all other environments are destroyed")
+	blocked, ok := errors.Cause(err).(*state.DestroyBlockedError)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(blocked.Blockers, gc.HasLen, 2)
+	c.Check(blocked.Blockers[0].Kind, gc.Equals, state.PersistentStorageBlocker)
+	c.Check(blocked.Blockers[0].Err, gc.Equals, state.ErrPersistentVolumesExist)
+	c.Check(blocked.Blockers[1].Kind, gc.Equals, state.HostedEnvironmentsBlocker)
+
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Alive)
+}
+
+func (s *EnvironSuite) TestDestroyEnvironmentForcePastPersistentStorage(c *gc.C) {
+	volumeTag := s.addPersistentVolume(c)
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.DestroyWithParams(state.DestroyEnvironmentParams{
+		Force: []state.DestroyBlockerKind{state.PersistentStorageBlocker},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Dying)
+
+	// The volume is left alone.
+	volume, err := s.State.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volume.Life(), gc.Equals, state.Alive)
+}
+
+func (s *EnvironSuite) TestDestroyEnvironmentCannotForcePastHostedEnvironments(c *gc.C) {
+	st2 := s.factory.MakeEnvironment(c, nil)
+	defer st2.Close()
+	env, err := s.State.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	err = env.DestroyWithParams(state.DestroyEnvironmentParams{
+		Force: []state.DestroyBlockerKind{state.HostedEnvironmentsBlocker},
+	})
+	c.Assert(err, gc.ErrorMatches, `failed to destroy environment: forcing past "hosted-environments" blocker not valid`)
+	c.Assert(env.Refresh(), jc.ErrorIsNil)
+	c.Assert(env.Life(), gc.Equals, state.Alive)
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// TransferEnvironmentOwnership makes the given user the owner of the
+// environment, in a single transaction that also moves the unique
+// owner and name record of the environment to the new owner and gives
+// the new owner access to the environment if they do not already have
+// it. If revokePrevious is true, the previous owner's access to the
+// environment is removed too.
+//
+// The new owner must not already own an environment with the same
+// name, and the state server environment cannot be transferred, as its
+// owner administers the whole server.
+func (st *State) TransferEnvironmentOwnership(newOwner names.UserTag, revokePrevious bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot transfer environment to %q", newOwner.Username())
+
+	if st.IsStateServer() {
+		return errors.New("the state server environment cannot be transferred")
+	}
+	var displayName string
+	if newOwner.IsLocal() {
+		user, err := st.User(newOwner)
+		if err != nil {
+			return errors.Annotate(err, fmt.Sprintf("user %q does not exist locally", newOwner.Name()))
+		}
+		displayName = user.DisplayName()
+	}
+
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := env.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if env.Life() != Alive {
+			return nil, errors.Errorf("environment is no longer alive")
+		}
+		oldOwner := env.Owner()
+		if strings.ToLower(oldOwner.Username()) == strings.ToLower(newOwner.Username()) {
+			return nil, errors.Errorf("environment is already owned by %q", oldOwner.Username())
+		}
+		taken, err := st.ownerEnvNameExists(newOwner, env.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if taken {
+			return nil, errors.AlreadyExistsf("environment %q for %q", env.Name(), newOwner.Username())
+		}
+
+		ops := []txn.Op{{
+			C:      environmentsC,
+			Id:     env.UUID(),
+			Assert: bson.D{{"life", Alive}, {"owner", oldOwner.Username()}},
+			Update: bson.D{{"$set", bson.D{{"owner", newOwner.Username()}}}},
+		}, {
+			C:      userenvnameC,
+			Id:     userEnvNameIndex(oldOwner.Username(), env.Name()),
+			Assert: txn.DocExists,
+			Remove: true,
+		},
+			createUniqueOwnerEnvNameOp(newOwner, env.Name()),
+		}
+
+		envUserOps, err := st.transferEnvUserOps(oldOwner, newOwner, displayName, revokePrevious)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return append(ops, envUserOps...), nil
+	}
+	return st.run(buildTxn)
+}
+
+// ownerEnvNameExists reports whether the given user owns an
+// environment with the given name.
+func (st *State) ownerEnvNameExists(owner names.UserTag, envName string) (bool, error) {
+	userenvnames, closer := st.getCollection(userenvnameC)
+	defer closer()
+
+	count, err := userenvnames.FindId(userEnvNameIndex(owner.Username(), envName)).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return count > 0, nil
+}
+
+// transferEnvUserOps returns the operations needed to make sure the new
+// owner of the environment has access to it and, if revokePrevious is
+// true, that the old owner does not.
+func (st *State) transferEnvUserOps(oldOwner, newOwner names.UserTag, displayName string, revokePrevious bool) ([]txn.Op, error) {
+	var ops []txn.Op
+	newID := strings.ToLower(newOwner.Username())
+	if _, err := st.EnvironmentUser(newOwner); errors.IsNotFound(err) {
+		op, _ := createEnvUserOpAndDoc(st.EnvironUUID(), newOwner, oldOwner, displayName)
+		ops = append(ops, op)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	} else {
+		ops = append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     newID,
+			Assert: txn.DocExists,
+		})
+	}
+	if !revokePrevious {
+		return ops, nil
+	}
+	oldID := strings.ToLower(oldOwner.Username())
+	if _, err := st.EnvironmentUser(oldOwner); errors.IsNotFound(err) {
+		ops = append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     oldID,
+			Assert: txn.DocMissing,
+		})
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	} else {
+		ops = append(ops, txn.Op{
+			C:      envUsersC,
+			Id:     oldID,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+	}
+	return ops, nil
+}
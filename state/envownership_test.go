@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type EnvOwnershipSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&EnvOwnershipSuite{})
+
+func (s *EnvOwnershipSuite) makeEnvironment(c *gc.C, name string, owner names.UserTag) *state.State {
+	return s.factory.MakeEnvironment(c, &factory.EnvParams{Name: name, Owner: owner})
+}
+
+func (s *EnvOwnershipSuite) TestTransferEnvironmentOwnership(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true}).UserTag()
+	bob := s.factory.MakeUser(c, &factory.UserParams{
+		Name:        "bob",
+		DisplayName: "Bob Brown",
+		NoEnvUser:   true,
+	}).UserTag()
+	st := s.makeEnvironment(c, "shared", alice)
+	defer st.Close()
+
+	err := st.TransferEnvironmentOwnership(bob, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Owner().Username(), gc.Equals, "bob@local")
+
+	envUser, err := st.EnvironmentUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.DisplayName(), gc.Equals, "Bob Brown")
+	c.Assert(envUser.CreatedBy(), gc.Equals, alice.Username())
+	_, err = st.EnvironmentUser(alice)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The old owner is free to reuse the environment's name.
+	other := s.makeEnvironment(c, "shared", alice)
+	other.Close()
+}
+
+func (s *EnvOwnershipSuite) TestTransferEnvironmentOwnershipRevokePrevious(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true}).UserTag()
+	bob := s.factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true}).UserTag()
+	st := s.makeEnvironment(c, "shared", alice)
+	defer st.Close()
+	// The new owner may already have access to the environment.
+	_, err := st.AddEnvironmentUser(bob, alice, "")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = st.TransferEnvironmentOwnership(bob, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = st.EnvironmentUser(bob)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.EnvironmentUser(alice)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *EnvOwnershipSuite) TestTransferEnvironmentOwnershipNameClash(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true}).UserTag()
+	bob := s.factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true}).UserTag()
+	st := s.makeEnvironment(c, "shared", alice)
+	defer st.Close()
+	other := s.makeEnvironment(c, "shared", bob)
+	defer other.Close()
+
+	err := st.TransferEnvironmentOwnership(bob, false)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer environment to "bob@local": environment "shared" for "bob@local" already exists`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsAlreadyExists)
+
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Owner().Username(), gc.Equals, "alice@local")
+}
+
+func (s *EnvOwnershipSuite) TestTransferEnvironmentOwnershipErrors(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true}).UserTag()
+	st := s.makeEnvironment(c, "shared", alice)
+	defer st.Close()
+
+	err := st.TransferEnvironmentOwnership(alice, false)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer environment to "alice@local": environment is already owned by "alice@local"`)
+
+	err = st.TransferEnvironmentOwnership(names.NewUserTag("nobody"), false)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer environment to "nobody@local": user "nobody" does not exist locally: .*`)
+
+	err = s.State.TransferEnvironmentOwnership(alice, false)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer environment to "alice@local": the state server environment cannot be transferred`)
+}
+
+func (s *EnvOwnershipSuite) TestTransferEnvironmentOwnershipDyingEnvironment(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true}).UserTag()
+	bob := s.factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true}).UserTag()
+	st := s.makeEnvironment(c, "shared", alice)
+	defer st.Close()
+	env, err := st.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env.Destroy(), jc.ErrorIsNil)
+
+	err = st.TransferEnvironmentOwnership(bob, false)
+	c.Assert(err, gc.ErrorMatches, `cannot transfer environment to "bob@local": environment is no longer alive`)
+}
@@ -50,6 +50,9 @@ var (
 	AddVolumeOp            = (*State).addVolumeOp
 	CombineMeterStatus     = combineMeterStatus
 	NewStatusNotFound      = newStatusNotFound
+	IdempotencyNow         = &idempotencyNow
+	SessionNow             = &sessionNow
+	CleanupNow             = &cleanupNow
 )
 
 type (
@@ -198,6 +201,7 @@ func GetPasswordHash(e Authenticator) string {
 
 func init() {
 	txnLogSize = txnLogSizeTests
+	unitUtilizationSize = txnLogSizeTests
 }
 
 // TxnRevno returns the txn-revno field of the document
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// ExposedEndpoint restricts the exposure of a service to part of the
+// ports opened by its units, and to the given source networks.
+type ExposedEndpoint struct {
+	// Ports holds the port ranges, such as "443/tcp", that are exposed
+	// for the endpoint. If empty, every opened port is exposed.
+	Ports []string `bson:"ports,omitempty"`
+
+	// CIDRs holds the networks from which the ports may be accessed.
+	// If empty, they may be accessed from anywhere.
+	CIDRs []string `bson:"cidrs,omitempty"`
+}
+
+// Validate returns an error if the endpoint's ports or networks are
+// not valid.
+func (e ExposedEndpoint) Validate() error {
+	for _, ports := range e.Ports {
+		if _, err := network.ParsePortRange(ports); err != nil {
+			return errors.Annotatef(err, "invalid port range %q", ports)
+		}
+	}
+	for _, cidr := range e.CIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("invalid CIDR %q", cidr)
+		}
+	}
+	return nil
+}
+
+// ExposedEndpoints returns the endpoints to which the service's
+// exposure is restricted, keyed by endpoint name. If the service is
+// exposed and no endpoints are returned, every opened port is exposed
+// to everyone.
+func (s *Service) ExposedEndpoints() map[string]ExposedEndpoint {
+	if len(s.doc.ExposedEndpoints) == 0 {
+		return nil
+	}
+	result := make(map[string]ExposedEndpoint, len(s.doc.ExposedEndpoints))
+	for name, endpoint := range s.doc.ExposedEndpoints {
+		result[name] = endpoint
+	}
+	return result
+}
+
+// SetExposedEndpoints marks the service as exposed, but only on the
+// given endpoints. Previously exposed endpoints that are not given are
+// no longer exposed. If no endpoints are given, the whole service is
+// exposed, as with SetExposed.
+func (s *Service) SetExposedEndpoints(endpoints map[string]ExposedEndpoint) (err error) {
+	if len(endpoints) == 0 {
+		return s.SetExposed()
+	}
+	defer errors.DeferredAnnotatef(&err, "cannot expose endpoints of service %q", s)
+	eps, err := s.Endpoints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	known := make(map[string]bool)
+	for _, ep := range eps {
+		known[ep.Name] = true
+	}
+	for name, endpoint := range endpoints {
+		if !known[name] {
+			return errors.NotFoundf("endpoint %q", name)
+		}
+		if err := endpoint.Validate(); err != nil {
+			return errors.Annotatef(err, "endpoint %q", name)
+		}
+	}
+	ops := []txn.Op{{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", s.doc.CharmURL}},
+		Update: bson.D{{"$set", bson.D{
+			{"exposed", true},
+			{"exposed-endpoints", endpoints},
+		}}},
+	}}
+	if err := s.st.runTransaction(ops); err == txn.ErrAborted {
+		if err := s.Refresh(); err != nil {
+			return errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return errors.New("service is not alive")
+		}
+		return errors.New("service charm has changed")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	s.doc.Exposed = true
+	s.doc.ExposedEndpoints = endpoints
+	return nil
+}
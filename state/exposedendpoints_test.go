@@ -0,0 +1,98 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ExposedEndpointsSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&ExposedEndpointsSuite{})
+
+func (s *ExposedEndpointsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *ExposedEndpointsSuite) TestSetExposedEndpoints(c *gc.C) {
+	c.Assert(s.service.ExposedEndpoints(), gc.HasLen, 0)
+	endpoints := map[string]state.ExposedEndpoint{
+		"url": {
+			Ports: []string{"443/tcp"},
+			CIDRs: []string{"10.0.0.0/8"},
+		},
+		"monitoring-port": {},
+	}
+	err := s.service.SetExposedEndpoints(endpoints)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsTrue)
+	c.Assert(s.service.ExposedEndpoints(), jc.DeepEquals, endpoints)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsTrue)
+	c.Assert(s.service.ExposedEndpoints(), jc.DeepEquals, endpoints)
+}
+
+func (s *ExposedEndpointsSuite) TestSetExposedClearsEndpoints(c *gc.C) {
+	err := s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"url": {Ports: []string{"443/tcp"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.ExposedEndpoints(), gc.HasLen, 0)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsTrue)
+	c.Assert(s.service.ExposedEndpoints(), gc.HasLen, 0)
+
+	err = s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"url": {Ports: []string{"443/tcp"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.IsExposed(), jc.IsFalse)
+	c.Assert(s.service.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ExposedEndpointsSuite) TestSetExposedEndpointsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		endpoints map[string]state.ExposedEndpoint
+		err       string
+	}{{
+		endpoints: map[string]state.ExposedEndpoint{"admin": {}},
+		err:       `cannot expose endpoints of service "wordpress": endpoint "admin" not found`,
+	}, {
+		endpoints: map[string]state.ExposedEndpoint{"url": {Ports: []string{"http"}}},
+		err:       `cannot expose endpoints of service "wordpress": endpoint "url": invalid port range "http": .*`,
+	}, {
+		endpoints: map[string]state.ExposedEndpoint{"url": {CIDRs: []string{"10.0.0.0"}}},
+		err:       `cannot expose endpoints of service "wordpress": endpoint "url": invalid CIDR "10.0.0.0"`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.service.SetExposedEndpoints(test.endpoints)
+		c.Check(err, gc.ErrorMatches, test.err)
+		c.Check(s.service.IsExposed(), jc.IsFalse)
+	}
+}
+
+func (s *ExposedEndpointsSuite) TestSetExposedEndpointsNotAlive(c *gc.C) {
+	_, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetExposedEndpoints(map[string]state.ExposedEndpoint{"url": {}})
+	c.Assert(err, gc.ErrorMatches, `cannot expose endpoints of service "wordpress": service is not alive`)
+}
@@ -0,0 +1,178 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// IdempotencyWindow is how long the idempotency token of an API call is
+// remembered, so that a client retrying the call after a timeout gets
+// the result of the first call rather than making it again.
+const IdempotencyWindow = 10 * time.Minute
+
+// idempotencyNow returns the current time; it is a variable so that
+// tests can move time forward.
+var idempotencyNow = time.Now
+
+// IdempotentCall describes the call that holds an idempotency token.
+type IdempotentCall struct {
+	// Action names the call, such as "Client.AddMachines".
+	Action string
+
+	// Done is true if the call has completed, with the given
+	// Result; otherwise the call is still being made.
+	Done   bool
+	Result []byte
+}
+
+type idempotencyTokenDoc struct {
+	DocID   string    `bson:"_id"`
+	EnvUUID string    `bson:"env-uuid"`
+	Token   string    `bson:"token"`
+	Action  string    `bson:"action"`
+	Done    bool      `bson:"done"`
+	Result  []byte    `bson:"result,omitempty"`
+	Expires time.Time `bson:"expires"`
+}
+
+// ClaimIdempotencyToken claims the given token for a call of the given
+// action. It returns nil if the token was claimed, because it had not
+// been used or its window had passed. Otherwise it returns the call
+// that holds the token, and the caller should report that call's
+// result rather than make its own.
+//
+// The claim must be followed by CompleteIdempotencyToken once the
+// call has succeeded, or by ReleaseIdempotencyToken if it failed.
+func (st *State) ClaimIdempotencyToken(token, action string) (_ *IdempotentCall, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot claim idempotency token %q", token)
+	if token == "" {
+		return nil, errors.New("empty token")
+	}
+	tokens, closer := st.getCollection(idempotencyTokensC)
+	defer closer()
+
+	var held *IdempotentCall
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		held = nil
+		now := idempotencyNow()
+		var doc idempotencyTokenDoc
+		err := tokens.FindId(token).One(&doc)
+		if err == mgo.ErrNotFound {
+			return []txn.Op{{
+				C:      idempotencyTokensC,
+				Id:     token,
+				Assert: txn.DocMissing,
+				Insert: &idempotencyTokenDoc{
+					Token:   token,
+					Action:  action,
+					Expires: now.Add(IdempotencyWindow),
+				},
+			}}, nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if now.Before(doc.Expires) {
+			held = &IdempotentCall{
+				Action: doc.Action,
+				Done:   doc.Done,
+				Result: doc.Result,
+			}
+			return nil, jujutxn.ErrNoOperations
+		}
+		// The token has expired, so it can be claimed again.
+		return []txn.Op{{
+			C:      idempotencyTokensC,
+			Id:     token,
+			Assert: bson.D{{"expires", doc.Expires}},
+			Update: bson.D{
+				{"$set", bson.D{
+					{"action", action},
+					{"done", false},
+					{"expires", now.Add(IdempotencyWindow)},
+				}},
+				{"$unset", bson.D{{"result", nil}}},
+			},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if held == nil {
+		if err := st.pruneIdempotencyTokens(); err != nil {
+			logger.Warningf("%v", err)
+		}
+	}
+	return held, nil
+}
+
+// CompleteIdempotencyToken records the result of the call that claimed
+// the given token, to be returned to calls retried with the token
+// within the idempotency window.
+func (st *State) CompleteIdempotencyToken(token string, result []byte) error {
+	ops := []txn.Op{{
+		C:      idempotencyTokensC,
+		Id:     token,
+		Assert: bson.D{{"done", false}},
+		Update: bson.D{{"$set", bson.D{
+			{"done", true},
+			{"result", result},
+			{"expires", idempotencyNow().Add(IdempotencyWindow)},
+		}}},
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("pending call with idempotency token %q", token)
+	}
+	return errors.Annotatef(err, "cannot complete idempotency token %q", token)
+}
+
+// ReleaseIdempotencyToken releases the given token, claimed for a call
+// that failed, so that the call can be retried with it.
+func (st *State) ReleaseIdempotencyToken(token string) error {
+	ops := []txn.Op{{
+		C:      idempotencyTokensC,
+		Id:     token,
+		Assert: bson.D{{"done", false}},
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("pending call with idempotency token %q", token)
+	}
+	return errors.Annotatef(err, "cannot release idempotency token %q", token)
+}
+
+// pruneIdempotencyTokens removes the idempotency tokens of the
+// environment whose windows have passed, so that they do not
+// accumulate.
+func (st *State) pruneIdempotencyTokens() error {
+	tokens, closer := st.getCollection(idempotencyTokensC)
+	defer closer()
+
+	var docs []idempotencyTokenDoc
+	sel := bson.D{{"expires", bson.D{{"$lte", idempotencyNow()}}}}
+	if err := tokens.Find(sel).Select(bson.D{{"_id", 1}, {"expires", 1}}).All(&docs); err != nil {
+		return errors.Annotate(err, "cannot read idempotency tokens")
+	}
+	for _, doc := range docs {
+		ops := []txn.Op{{
+			C:      idempotencyTokensC,
+			Id:     st.localID(doc.DocID),
+			Assert: bson.D{{"expires", doc.Expires}},
+			Remove: true,
+		}}
+		// A token claimed again since it was read is left alone.
+		if err := st.runTransaction(ops); err != nil && err != txn.ErrAborted {
+			return errors.Annotate(err, "cannot prune idempotency tokens")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type IdempotencySuite struct {
+	ConnSuite
+	now time.Time
+}
+
+var _ = gc.Suite(&IdempotencySuite{})
+
+func (s *IdempotencySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.now = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(state.IdempotencyNow, func() time.Time { return s.now })
+}
+
+func (s *IdempotencySuite) TestClaimAndComplete(c *gc.C) {
+	held, err := s.State.ClaimIdempotencyToken("tok", "AddMachines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, gc.IsNil)
+
+	// A second claim finds the call in progress.
+	held, err = s.State.ClaimIdempotencyToken("tok", "AddMachines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, jc.DeepEquals, &state.IdempotentCall{Action: "AddMachines"})
+
+	err = s.State.CompleteIdempotencyToken("tok", []byte(`{"Machines":[]}`))
+	c.Assert(err, jc.ErrorIsNil)
+	held, err = s.State.ClaimIdempotencyToken("tok", "AddMachines")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, jc.DeepEquals, &state.IdempotentCall{
+		Action: "AddMachines",
+		Done:   true,
+		Result: []byte(`{"Machines":[]}`),
+	})
+
+	// A completed call cannot be released or completed again.
+	err = s.State.ReleaseIdempotencyToken("tok")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.CompleteIdempotencyToken("tok", nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *IdempotencySuite) TestRelease(c *gc.C) {
+	_, err := s.State.ClaimIdempotencyToken("tok", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.ReleaseIdempotencyToken("tok")
+	c.Assert(err, jc.ErrorIsNil)
+	held, err := s.State.ClaimIdempotencyToken("tok", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, gc.IsNil)
+}
+
+func (s *IdempotencySuite) TestExpiredTokenCanBeClaimed(c *gc.C) {
+	_, err := s.State.ClaimIdempotencyToken("tok", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.CompleteIdempotencyToken("tok", []byte("null"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.now = s.now.Add(state.IdempotencyWindow - time.Second)
+	held, err := s.State.ClaimIdempotencyToken("tok", "AddRelation")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, gc.NotNil)
+
+	s.now = s.now.Add(time.Second)
+	held, err = s.State.ClaimIdempotencyToken("tok", "AddRelation")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, gc.IsNil)
+	held, err = s.State.ClaimIdempotencyToken("tok", "AddRelation")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, jc.DeepEquals, &state.IdempotentCall{Action: "AddRelation"})
+}
+
+func (s *IdempotencySuite) TestTokensAreEnvironmentSpecific(c *gc.C) {
+	_, err := s.State.ClaimIdempotencyToken("tok", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	held, err := st.ClaimIdempotencyToken("tok", "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(held, gc.IsNil)
+}
+
+func (s *IdempotencySuite) TestClaimEmptyToken(c *gc.C) {
+	_, err := s.State.ClaimIdempotencyToken("", "Deploy")
+	c.Assert(err, gc.ErrorMatches, `cannot claim idempotency token "": empty token`)
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/constraints"
+)
+
+// InstanceTypeMapping records the instance type to which the provider
+// normalized a set of constraints, so that the normalization does not
+// need to be repeated and can be reported.
+type InstanceTypeMapping struct {
+	// Constraints holds the constraints that were normalized.
+	Constraints constraints.Value
+
+	// InstanceType holds the name of the chosen instance type.
+	InstanceType string
+
+	// Hardware holds the abstract constraints that the instance type
+	// satisfies.
+	Hardware constraints.Value
+
+	// Rationale explains why the instance type was chosen.
+	Rationale string
+
+	// Updated holds when the mapping was recorded.
+	Updated time.Time
+}
+
+// instanceTypeMappingDoc is the persistent form of an
+// InstanceTypeMapping. It is keyed on the string form of the
+// normalized constraints.
+type instanceTypeMappingDoc struct {
+	DocID        string    `bson:"_id"`
+	EnvUUID      string    `bson:"env-uuid"`
+	Constraints  string    `bson:"constraints"`
+	InstanceType string    `bson:"instance-type"`
+	Hardware     string    `bson:"hardware"`
+	Rationale    string    `bson:"rationale"`
+	Updated      time.Time `bson:"updated"`
+}
+
+func (doc *instanceTypeMappingDoc) mapping() (InstanceTypeMapping, error) {
+	cons, err := constraints.Parse(doc.Constraints)
+	if err != nil {
+		return InstanceTypeMapping{}, errors.Trace(err)
+	}
+	hardware, err := constraints.Parse(doc.Hardware)
+	if err != nil {
+		return InstanceTypeMapping{}, errors.Trace(err)
+	}
+	return InstanceTypeMapping{
+		Constraints:  cons,
+		InstanceType: doc.InstanceType,
+		Hardware:     hardware,
+		Rationale:    doc.Rationale,
+		Updated:      doc.Updated,
+	}, nil
+}
+
+// InstanceTypeMapping returns the recorded mapping of the given
+// constraints to an instance type. An error satisfying
+// errors.IsNotFound is returned if none has been recorded.
+func (st *State) InstanceTypeMapping(cons constraints.Value) (InstanceTypeMapping, error) {
+	coll, closer := st.getCollection(instanceTypeMappingsC)
+	defer closer()
+
+	var doc instanceTypeMappingDoc
+	err := coll.FindId(cons.String()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return InstanceTypeMapping{}, errors.NotFoundf("instance type mapping for %q", cons)
+	} else if err != nil {
+		return InstanceTypeMapping{}, errors.Annotate(err, "cannot get instance type mapping")
+	}
+	return doc.mapping()
+}
+
+// SetInstanceTypeMapping records the given mapping of constraints to
+// an instance type, replacing any mapping previously recorded for the
+// same constraints.
+func (st *State) SetInstanceTypeMapping(m InstanceTypeMapping) error {
+	coll, closer := st.getCollection(instanceTypeMappingsC)
+	defer closer()
+
+	key := m.Constraints.String()
+	id := st.docID(key)
+	doc := &instanceTypeMappingDoc{
+		Constraints:  key,
+		InstanceType: m.InstanceType,
+		Hardware:     m.Hardware.String(),
+		Rationale:    m.Rationale,
+		Updated:      m.Updated,
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		count, err := coll.FindId(key).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return []txn.Op{{
+				C:      instanceTypeMappingsC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: doc,
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      instanceTypeMappingsC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{
+				{"instance-type", doc.InstanceType},
+				{"hardware", doc.Hardware},
+				{"rationale", doc.Rationale},
+				{"updated", doc.Updated},
+			}}},
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot set instance type mapping")
+	}
+	return nil
+}
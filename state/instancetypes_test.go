@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state"
+)
+
+type instanceTypeMappingSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&instanceTypeMappingSuite{})
+
+func (s *instanceTypeMappingSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.State.InstanceTypeMapping(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `instance type mapping for "mem=4096M" not found`)
+}
+
+func (s *instanceTypeMappingSuite) TestSetGet(c *gc.C) {
+	cons := constraints.MustParse("mem=4G")
+	for _, itype := range []string{"m1.large", "m3.large"} {
+		mapping := state.InstanceTypeMapping{
+			Constraints:  cons,
+			InstanceType: itype,
+			Hardware:     constraints.MustParse("cpu-cores=2 mem=7680M"),
+			Rationale:    "cheapest of 5 instance types matching",
+			Updated:      time.Now().UTC().Truncate(time.Second),
+		}
+		err := s.State.SetInstanceTypeMapping(mapping)
+		c.Assert(err, jc.ErrorIsNil)
+
+		found, err := s.State.InstanceTypeMapping(cons)
+		c.Assert(err, jc.ErrorIsNil)
+		found.Updated = found.Updated.UTC()
+		c.Assert(found, jc.DeepEquals, mapping)
+	}
+}
+
+func (s *instanceTypeMappingSuite) TestEmptyConstraints(c *gc.C) {
+	mapping := state.InstanceTypeMapping{
+		InstanceType: "m1.small",
+		Rationale:    "cheapest of 8 instance types matching default constraints",
+	}
+	err := s.State.SetInstanceTypeMapping(mapping)
+	c.Assert(err, jc.ErrorIsNil)
+	found, err := s.State.InstanceTypeMapping(constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.InstanceType, gc.Equals, "m1.small")
+}
+
+func (s *instanceTypeMappingSuite) TestMachineInstanceTypeMapping(c *gc.C) {
+	machine := s.factory.MakeMachine(c, nil)
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=4G"))
+	c.Assert(err, jc.ErrorIsNil)
+	// The mapping is keyed on the constraints the machine would be
+	// provisioned with now.
+	cons, err := machine.ProvisioningConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=4G"))
+	err = s.State.SetInstanceTypeMapping(state.InstanceTypeMapping{
+		Constraints:  cons,
+		InstanceType: "m1.large",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	found, err := machine.InstanceTypeMapping()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.InstanceType, gc.Equals, "m1.large")
+}
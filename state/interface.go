@@ -89,6 +89,19 @@ var (
 	_ Authenticator = (*User)(nil)
 )
 
+// PasswordRotator represents agent entities whose passwords can be
+// replaced without a window in which neither the old nor the new
+// password is valid.
+type PasswordRotator interface {
+	SetNextPassword(pass string) error
+	ConfirmPassword(pass string) error
+}
+
+var (
+	_ PasswordRotator = (*Machine)(nil)
+	_ PasswordRotator = (*Unit)(nil)
+)
+
 // NotifyWatcherFactory represents an entity that
 // can be watched.
 type NotifyWatcherFactory interface {
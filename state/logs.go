@@ -23,7 +23,9 @@ const logsC = "logs"
 // be called as state is opened. It is idempotent.
 func InitDbLogs(session *mgo.Session) error {
 	logsColl := session.DB(logsDB).C(logsC)
-	for _, key := range [][]string{{"e", "t"}, {"e", "n"}} {
+	// The time-first index serves reading the logs of all environments
+	// in order, optionally filtered by environment.
+	for _, key := range [][]string{{"e", "t"}, {"e", "n"}, {"t", "e"}} {
 		err := logsColl.EnsureIndex(mgo.Index{Key: key})
 		if err != nil {
 			return errors.Annotate(err, "cannot create index for logs collection")
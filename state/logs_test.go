@@ -43,6 +43,7 @@ func (s *LogsSuite) TestIndexesCreated(c *gc.C) {
 		"_id", // default index
 		"e-t", // env-uuid and timestamp
 		"e-n", // env-uuid and entity
+		"t-e", // timestamp and env-uuid
 	})
 }
 
@@ -19,6 +19,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
@@ -108,7 +109,10 @@ type machineDoc struct {
 	NoVote        bool
 	HasVote       bool
 	PasswordHash  string
-	Clean         bool
+	// NextPasswordHash holds the hash of a password that is being
+	// rotated in; see SetNextPassword.
+	NextPasswordHash string `bson:",omitempty"`
+	Clean            bool
 	// We store 2 different sets of addresses for the machine, obtained
 	// from different sources.
 	// Addresses is the set of addresses obtained by asking the provider.
@@ -122,6 +126,15 @@ type machineDoc struct {
 	// Placement is the placement directive that should be used when provisioning
 	// an instance for the machine.
 	Placement string `bson:",omitempty"`
+	// DistributionGroup is the name of the group of machines across
+	// which the provider should distribute instances for high
+	// availability. It is the name of the service of the first
+	// principal unit assigned to the machine.
+	DistributionGroup string `bson:"distributiongroup,omitempty"`
+	// RemoveEmptyParent is set on a container destroyed because it
+	// was left empty, and makes its removal destroy its host if that
+	// is left empty in turn.
+	RemoveEmptyParent bool `bson:"removeemptyparent,omitempty"`
 }
 
 func newMachine(st *State, doc *machineDoc) *Machine {
@@ -363,7 +376,10 @@ func (m *Machine) setPasswordHash(passwordHash string) error {
 		C:      machinesC,
 		Id:     m.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"passwordhash", passwordHash}}},
+			{"$unset", bson.D{{"nextpasswordhash", nil}}},
+		},
 	}}
 	// A "raw" transaction is used here because this code has to work
 	// before the machine env UUID DB migration has run. In this case
@@ -373,6 +389,7 @@ func (m *Machine) setPasswordHash(passwordHash string) error {
 		return fmt.Errorf("cannot set password of machine %v: %v", m, onAbort(err, ErrDead))
 	}
 	m.doc.PasswordHash = passwordHash
+	m.doc.NextPasswordHash = ""
 	return nil
 }
 
@@ -383,6 +400,43 @@ func (m *Machine) getPasswordHash() string {
 	return m.doc.PasswordHash
 }
 
+// SetNextPassword records a new password for the machine's agent. Both
+// the current and the new password remain valid until the new one is
+// confirmed with ConfirmPassword, so the agent can safely store the new
+// password before it stops using the old one.
+func (m *Machine) SetNextPassword(password string) error {
+	if len(password) < utils.MinAgentPasswordLength {
+		return fmt.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
+	}
+	nextHash := utils.AgentPasswordHash(password)
+	ops := setNextPasswordOps(machinesC, m.doc.DocID, nextHash)
+	if err := m.st.runRawTransaction(ops); err != nil {
+		return fmt.Errorf("cannot set next password of machine %v: %v", m, onAbort(err, ErrDead))
+	}
+	m.doc.NextPasswordHash = nextHash
+	return nil
+}
+
+// ConfirmPassword makes the given password, previously recorded with
+// SetNextPassword, the only valid password for the machine's agent.
+// Confirming the current password does nothing.
+func (m *Machine) ConfirmPassword(password string) error {
+	agentHash := utils.AgentPasswordHash(password)
+	if agentHash == m.doc.PasswordHash {
+		return nil
+	}
+	if m.doc.NextPasswordHash == "" || agentHash != m.doc.NextPasswordHash {
+		return fmt.Errorf("cannot confirm password of machine %v: password does not match", m)
+	}
+	ops := confirmPasswordOps(machinesC, m.doc.DocID, agentHash)
+	if err := m.st.runRawTransaction(ops); err != nil {
+		return fmt.Errorf("cannot confirm password of machine %v: %v", m, onAbort(err, errNextPasswordChanged))
+	}
+	m.doc.PasswordHash = agentHash
+	m.doc.NextPasswordHash = ""
+	return nil
+}
+
 // PasswordValid returns whether the given password is valid
 // for the given machine.
 func (m *Machine) PasswordValid(password string) bool {
@@ -390,6 +444,9 @@ func (m *Machine) PasswordValid(password string) bool {
 	if agentHash == m.doc.PasswordHash {
 		return true
 	}
+	if m.doc.NextPasswordHash != "" && agentHash == m.doc.NextPasswordHash {
+		return true
+	}
 	// In Juju 1.16 and older we used the slower password hash for unit
 	// agents. So check to see if the supplied password matches the old
 	// path, and if so, update it to the new mechanism.
@@ -429,6 +486,88 @@ func (m *Machine) ForceDestroy() error {
 	return fmt.Errorf("machine %s is required by the environment", m.doc.Id)
 }
 
+// scheduledRemovalDocID returns the id of the cleanup document that
+// records the scheduled removal of the machine.
+func (m *Machine) scheduledRemovalDocID() string {
+	return m.st.docID("scheduled-machine-removal#" + m.doc.Id)
+}
+
+// ScheduleDestroy schedules the machine to be destroyed, as by Destroy,
+// once the given grace period has passed, unless the removal is
+// cancelled with CancelScheduledDestroy first. It returns the time at
+// which the machine will be destroyed. ScheduleDestroy fails if the
+// machine is not Alive, if it has JobManageEnviron, or if its removal
+// is already scheduled.
+func (m *Machine) ScheduleDestroy(gracePeriod time.Duration) (_ time.Time, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot schedule removal of machine %s", m.doc.Id)
+	if gracePeriod <= 0 {
+		return time.Time{}, errors.New("grace period must be positive")
+	}
+	if m.IsManager() {
+		return time.Time{}, errors.Errorf("machine %s is required by the environment", m.doc.Id)
+	}
+	due := cleanupNow().Add(gracePeriod).Round(time.Second)
+	ops := []txn.Op{{
+		C:      machinesC,
+		Id:     m.doc.DocID,
+		Assert: isAliveDoc,
+	}, {
+		C:      cleanupsC,
+		Id:     m.scheduledRemovalDocID(),
+		Assert: txn.DocMissing,
+		Insert: &cleanupDoc{
+			DocID:   m.scheduledRemovalDocID(),
+			EnvUUID: m.st.EnvironUUID(),
+			Kind:    cleanupScheduledMachineRemoval,
+			Prefix:  m.doc.Id,
+			Due:     due,
+		},
+	}}
+	if err := m.st.runTransaction(ops); err != txn.ErrAborted {
+		return due, err
+	}
+	if err := m.Refresh(); err != nil {
+		return time.Time{}, err
+	}
+	if m.Life() != Alive {
+		return time.Time{}, errors.Errorf("machine is not alive")
+	}
+	return time.Time{}, errors.AlreadyExistsf("scheduled removal")
+}
+
+// ScheduledDestroy returns the time at which the machine is scheduled to
+// be destroyed. It returns an error satisfying errors.IsNotFound if no
+// removal is scheduled.
+func (m *Machine) ScheduledDestroy() (time.Time, error) {
+	cleanups, closer := m.st.getCollection(cleanupsC)
+	defer closer()
+	var doc cleanupDoc
+	err := cleanups.FindId(m.scheduledRemovalDocID()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return time.Time{}, errors.NotFoundf("scheduled removal of machine %s", m.doc.Id)
+	} else if err != nil {
+		return time.Time{}, errors.Annotatef(err, "cannot read scheduled removal of machine %s", m.doc.Id)
+	}
+	return doc.Due, nil
+}
+
+// CancelScheduledDestroy cancels the removal of the machine scheduled
+// with ScheduleDestroy. It returns an error satisfying errors.IsNotFound
+// if no removal is scheduled, including when it has already been made.
+func (m *Machine) CancelScheduledDestroy() error {
+	ops := []txn.Op{{
+		C:      cleanupsC,
+		Id:     m.scheduledRemovalDocID(),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := m.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("scheduled removal of machine %s", m.doc.Id)
+	}
+	return errors.Annotatef(err, "cannot cancel removal of machine %s", m.doc.Id)
+}
+
 // EnsureDead sets the machine lifecycle to Dead if it is Alive or Dying.
 // It does nothing otherwise. EnsureDead will fail if the machine has
 // principal units assigned, or if the machine has JobManageEnviron.
@@ -665,6 +804,8 @@ func (m *Machine) Remove() (err error) {
 		removeRequestedNetworksOp(m.st, m.globalKey()),
 		annotationRemoveOp(m.st, m.globalKey()),
 		removeRebootDocOp(m.st, m.globalKey()),
+		removeSSHHostKeysOp(m.st, m.globalKey()),
+		removeUpgradeSeriesLockOp(m.st, m.Id()),
 		removeMachineBlockDevicesOp(m.Id()),
 	}
 	ifacesOps, err := m.removeNetworkInterfacesOps()
@@ -678,6 +819,9 @@ func (m *Machine) Remove() (err error) {
 	ops = append(ops, ifacesOps...)
 	ops = append(ops, portsOps...)
 	ops = append(ops, removeContainerRefOps(m.st, m.Id())...)
+	if parentId, ok := m.ParentId(); ok && m.doc.RemoveEmptyParent {
+		ops = append(ops, m.st.newCleanupOp(cleanupEmptyMachine, parentId))
+	}
 	// The only abort conditions in play indicate that the machine has already
 	// been removed.
 	return onAbort(m.st.runTransaction(ops), nil)
@@ -936,6 +1080,9 @@ func (m *Machine) SetInstanceInfo(
 	if err := setMachineVolumeAttachmentInfo(m.st, m.Id(), volumeAttachments); err != nil {
 		return errors.Trace(err)
 	}
+	if err := setProvisionedBlockDevices(m.st, m.Id(), volumes, volumeAttachments); err != nil {
+		return errors.Annotate(err, "cannot record block devices")
+	}
 	return m.SetProvisioned(id, nonce, characteristics)
 }
 
@@ -1224,12 +1371,51 @@ func (m *Machine) Placement() string {
 	return m.doc.Placement
 }
 
+// DistributionGroupName returns the name of the distribution group
+// to which the machine belongs, or the empty string if no principal
+// unit has been assigned to it. Providers may use this name to place
+// the instances of a service's units in the same availability set.
+func (m *Machine) DistributionGroupName() string {
+	return m.doc.DistributionGroup
+}
+
 // Constraints returns the exact constraints that should apply when provisioning
 // an instance for the machine.
 func (m *Machine) Constraints() (constraints.Value, error) {
 	return readConstraints(m.st, m.globalKey())
 }
 
+// ProvisioningConstraints returns the constraints with which an
+// instance should be provisioned for the machine now. The machine's
+// constraints were resolved when it was added; they are resolved again
+// against the current environment constraints, so that environment
+// defaults set since then fill in any attributes the machine does not
+// specify.
+func (m *Machine) ProvisioningConstraints() (constraints.Value, error) {
+	cons, err := m.Constraints()
+	if err != nil {
+		return constraints.Value{}, errors.Trace(err)
+	}
+	effective, err := m.st.ResolveConstraints(resolver.Layers{Placement: cons})
+	if err != nil {
+		return constraints.Value{}, errors.Annotatef(err, "cannot resolve constraints for machine %q", m.Id())
+	}
+	// Machines are never provisioned as containers by constraint.
+	effective.Value.Container = nil
+	return effective.Value, nil
+}
+
+// InstanceTypeMapping returns the recorded normalization of the
+// machine's provisioning constraints to an instance type. An error
+// satisfying errors.IsNotFound is returned if none has been recorded.
+func (m *Machine) InstanceTypeMapping() (InstanceTypeMapping, error) {
+	cons, err := m.ProvisioningConstraints()
+	if err != nil {
+		return InstanceTypeMapping{}, errors.Trace(err)
+	}
+	return m.st.InstanceTypeMapping(cons)
+}
+
 // SetConstraints sets the exact constraints to apply when provisioning an
 // instance for the machine. It will fail if the machine is Dead, or if it
 // is already provisioned.
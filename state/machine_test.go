@@ -552,6 +552,12 @@ func (s *MachineSuite) TestSetPasswordPreEnvUUID(c *gc.C) {
 	c.Assert(m.PasswordValid(goodPassword), jc.IsTrue)
 }
 
+func (s *MachineSuite) TestRotatePassword(c *gc.C) {
+	testRotatePassword(c, func() (passwordRotatingEntity, error) {
+		return s.State.Machine(s.machine.Id())
+	})
+}
+
 func (s *MachineSuite) TestSetAgentCompatPassword(c *gc.C) {
 	e, err := s.State.Machine(s.machine.Id())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1004,6 +1010,42 @@ func (s *MachineSuite) TestMachineSetInstanceInfoSuccess(c *gc.C) {
 	c.Assert(info, gc.Equals, volumeInfo)
 }
 
+func (s *MachineSuite) TestMachineSetInstanceInfoRecordsBlockDevices(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State))
+	_, err := pm.Create("loop-pool", provider.LoopProviderType, map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	registry.RegisterEnvironStorageProviders("someprovider", provider.LoopProviderType)
+
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+		Volumes: []state.MachineVolumeParams{{
+			Volume: state.VolumeParams{Size: 1000, Pool: "loop-pool"},
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	volumeAttachments, err := machine.VolumeAttachments()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeAttachments, gc.HasLen, 1)
+	volumeTag := volumeAttachments[0].Volume()
+
+	volumes := map[names.VolumeTag]state.VolumeInfo{
+		volumeTag: {VolumeId: "vol-0", HardwareId: "hw-0", Size: 1024},
+	}
+	attachments := map[names.VolumeTag]state.VolumeAttachmentInfo{
+		volumeTag: {DeviceName: "sdb"},
+	}
+	err = machine.SetInstanceInfo("umbrella/0", "fake_nonce", nil, nil, nil, volumes, attachments)
+	c.Assert(err, jc.ErrorIsNil)
+	blockDevices, err := s.State.BlockDevices(machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockDevices, jc.DeepEquals, []state.BlockDeviceInfo{{
+		DeviceName: "sdb",
+		HardwareId: "hw-0",
+		Size:       1024,
+	}})
+}
+
 func (s *MachineSuite) TestMachineSetProvisionedWhenNotAlive(c *gc.C) {
 	testWhenDying(c, s.machine, notAliveErr, notAliveErr, func() error {
 		return s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
@@ -1590,6 +1632,17 @@ func (s *MachineSuite) TestGetSetStatusWhileAlive(c *gc.C) {
 	})
 }
 
+func (s *MachineSuite) TestSetStatusInstanceMissing(c *gc.C) {
+	err := s.machine.SetProvisioned("umbrella/0", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetStatus(state.StatusInstanceMissing, `instance "umbrella/0" not found`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	statusInfo, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statusInfo.Status, gc.Equals, state.StatusInstanceMissing)
+	c.Assert(statusInfo.Message, gc.Equals, `instance "umbrella/0" not found`)
+}
+
 func (s *MachineSuite) TestSetStatusPending(c *gc.C) {
 	err := s.machine.SetStatus(state.StatusPending, "", nil)
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/mongo"
+)
+
+// mongoSessionConfig returns the MongoDB session settings held in the
+// given state server environment config.
+func mongoSessionConfig(cfg *config.Config) mongo.SessionConfig {
+	var sc mongo.SessionConfig
+	if limit, ok := cfg.MongoPoolLimit(); ok {
+		sc.PoolLimit = limit
+	}
+	if timeout, ok := cfg.MongoSocketTimeout(); ok {
+		sc.SocketTimeout = timeout
+	}
+	if timeout, ok := cfg.MongoSyncTimeout(); ok {
+		sc.SyncTimeout = timeout
+	}
+	return sc
+}
+
+// UpdateMongoSessionConfig applies the MongoDB session settings in the
+// given state server environment config to the State's session. They
+// take effect for every session subsequently copied from it, and for
+// States subsequently opened with ForEnviron. Settings that are not
+// defined in the config are left unchanged.
+func (st *State) UpdateMongoSessionConfig(cfg *config.Config) {
+	st.applyMongoSessionConfig(mongoSessionConfig(cfg))
+}
+
+func (st *State) applyMongoSessionConfig(sc mongo.SessionConfig) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	sc.Apply(st.db.Session)
+	if sc.PoolLimit != 0 {
+		st.sessionConfig.PoolLimit = sc.PoolLimit
+	}
+	if sc.SocketTimeout != 0 {
+		st.sessionConfig.SocketTimeout = sc.SocketTimeout
+	}
+	if sc.SyncTimeout != 0 {
+		st.sessionConfig.SyncTimeout = sc.SyncTimeout
+	}
+}
+
+// MongoSessionConfig returns the MongoDB session settings most recently
+// applied to the State's session.
+func (st *State) MongoSessionConfig() mongo.SessionConfig {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.sessionConfig
+}
@@ -0,0 +1,44 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/mongo"
+)
+
+type MongoSessionSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&MongoSessionSuite{})
+
+func (s *MongoSessionSuite) TestUpdateMongoSessionConfig(c *gc.C) {
+	c.Assert(s.State.MongoSessionConfig(), gc.Equals, mongo.SessionConfig{})
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"mongo-pool-limit":     64,
+		"mongo-socket-timeout": 30,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.State.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.UpdateMongoSessionConfig(cfg)
+	c.Assert(s.State.MongoSessionConfig(), gc.Equals, mongo.SessionConfig{
+		PoolLimit:     64,
+		SocketTimeout: 30 * time.Second,
+	})
+
+	// Hosted environments share the state server's settings.
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	c.Assert(st.MongoSessionConfig(), gc.Equals, mongo.SessionConfig{
+		PoolLimit:     64,
+		SocketTimeout: 30 * time.Second,
+	})
+}
@@ -105,6 +105,17 @@ func (d *Delta) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(elements[2], &d.Entity)
 }
 
+// EnvironDelta holds details of a change to an entity within a
+// particular environment, as reported by a watcher that observes
+// all the environments in a system.
+type EnvironDelta struct {
+	// EnvUUID holds the UUID of the environment that the
+	// changed entity belongs to.
+	EnvUUID string
+	// Delta holds the change to the entity.
+	Delta Delta
+}
+
 // When remote units leave scope, their ids will be noted in the
 // Departed field, and no further events will be sent for those units.
 type RelationUnitsChange struct {
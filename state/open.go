@@ -39,6 +39,12 @@ func Open(info *mongo.MongoInfo, opts mongo.DialOpts, policy Policy) (*State, er
 	}
 	st.environTag = ssInfo.EnvironmentTag
 	st.serverTag = ssInfo.EnvironmentTag
+	cfg, err := st.EnvironConfig()
+	if err != nil {
+		st.Close()
+		return nil, errors.Annotate(err, "could not read state server environment config")
+	}
+	st.UpdateMongoSessionConfig(cfg)
 	st.startPresenceWatcher()
 	return st, nil
 }
@@ -80,6 +86,7 @@ func Initialize(owner names.UserTag, info *mongo.MongoInfo, cfg *config.Config,
 	envTag := names.NewEnvironTag(uuid)
 	st.environTag = envTag
 	st.serverTag = envTag
+	st.UpdateMongoSessionConfig(cfg)
 
 	// A valid environment is used as a signal that the
 	// state has already been initalized. If this is the case
@@ -96,7 +103,7 @@ func Initialize(owner names.UserTag, info *mongo.MongoInfo, cfg *config.Config,
 
 	// When creating the state server environment, the new environment
 	// UUID is also used as the state server UUID.
-	ops, err := st.envSetupOps(cfg, uuid, uuid, owner)
+	ops, err := st.envSetupOps(cfg, uuid, uuid, owner, "", "")
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -130,7 +137,7 @@ func Initialize(owner names.UserTag, info *mongo.MongoInfo, cfg *config.Config,
 	return st, nil
 }
 
-func (st *State) envSetupOps(cfg *config.Config, envUUID, serverUUID string, owner names.UserTag) ([]txn.Op, error) {
+func (st *State) envSetupOps(cfg *config.Config, envUUID, serverUUID string, owner names.UserTag, cloud, region string) ([]txn.Op, error) {
 	if err := checkEnvironConfig(cfg); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -144,7 +151,7 @@ func (st *State) envSetupOps(cfg *config.Config, envUUID, serverUUID string, own
 	ops := []txn.Op{
 		createConstraintsOp(st, environGlobalKey, constraints.Value{}),
 		createSettingsOp(st, environGlobalKey, cfg.AllAttrs()),
-		createEnvironmentOp(st, owner, cfg.Name(), envUUID, serverUUID),
+		createEnvironmentOp(st, owner, cfg.Name(), envUUID, serverUUID, cloud, region),
 		createUniqueOwnerEnvNameOp(owner, cfg.Name()),
 		envUserOp,
 	}
@@ -182,6 +189,9 @@ var indexes = []struct {
 	{volumesC, []string{"env-uuid", "storageid"}, false, false},
 	{filesystemsC, []string{"env-uuid", "storageid"}, false, false},
 	{statusesHistoryC, []string{"env-uuid", "entityid"}, false, false},
+	{actionOutputC, []string{"env-uuid", "action-id", "seq"}, false, false},
+	{unitUtilizationC, []string{"env-uuid", "time"}, false, false},
+	{apiUsageC, []string{"hour"}, false, false},
 }
 
 // The capped collection used for transaction logs defaults to 10MB.
@@ -192,6 +202,11 @@ var (
 	txnLogSizeTests = 1000000
 )
 
+// The capped collection used for unit utilization samples defaults to
+// 10MB, enough for a day of samples from a few hundred units. Like the
+// transaction log, it is reduced to 1MB in export_test.go.
+var unitUtilizationSize = 10000000
+
 func maybeUnauthorized(err error, msg string) error {
 	if err == nil {
 		return nil
@@ -246,6 +261,12 @@ func newState(session *mgo.Session, mongoInfo *mongo.MongoInfo, policy Policy) (
 	if isCollectionExistsError(err) {
 		return nil, maybeUnauthorized(err, "cannot create transaction collection")
 	}
+	unitUtilization := db.C(unitUtilizationC)
+	unitUtilizationInfo := mgo.CollectionInfo{Capped: true, MaxBytes: unitUtilizationSize}
+	err = unitUtilization.Create(&unitUtilizationInfo)
+	if isCollectionExistsError(err) {
+		return nil, maybeUnauthorized(err, "cannot create unit utilization collection")
+	}
 
 	// Create and set up State.
 	st := &State{
@@ -0,0 +1,41 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// errNextPasswordChanged is returned when a password cannot be
+// confirmed because the entity's next password has changed or the
+// entity has died.
+var errNextPasswordChanged = errors.New("next password changed or entity is dead")
+
+// setNextPasswordOps returns the operations that record the hash of
+// an agent's next password. Until it is confirmed, either the current
+// or the next password may be used to log in.
+func setNextPasswordOps(collection string, docID string, nextHash string) []txn.Op {
+	return []txn.Op{{
+		C:      collection,
+		Id:     docID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"nextpasswordhash", nextHash}}}},
+	}}
+}
+
+// confirmPasswordOps returns the operations that make an agent's next
+// password its only valid password.
+func confirmPasswordOps(collection string, docID string, nextHash string) []txn.Op {
+	return []txn.Op{{
+		C:      collection,
+		Id:     docID,
+		Assert: append(bson.D{{"nextpasswordhash", nextHash}}, notDeadDoc...),
+		Update: bson.D{
+			{"$set", bson.D{{"passwordhash", nextHash}}},
+			{"$unset", bson.D{{"nextpasswordhash", nil}}},
+		},
+	}}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/instance"
 )
@@ -122,18 +123,32 @@ func (st *State) constraintsValidator() (constraints.Validator, error) {
 	return validator, nil
 }
 
-// resolveConstraints combines the given constraints with the environ constraints to get
-// a constraints which will be used to create a new instance.
-func (st *State) resolveConstraints(cons constraints.Value) (constraints.Value, error) {
+// ResolveConstraints combines the given service and placement
+// constraints with the environment constraints, using the rules
+// defined by the constraints resolver, to get the constraints which
+// will be used to choose or create an instance. The Environment field
+// of layers is ignored; the current environment constraints are always
+// used.
+func (st *State) ResolveConstraints(layers resolver.Layers) (resolver.Effective, error) {
 	validator, err := st.constraintsValidator()
 	if err != nil {
-		return constraints.Value{}, err
+		return resolver.Effective{}, err
+	}
+	layers.Environment, err = st.EnvironConstraints()
+	if err != nil {
+		return resolver.Effective{}, err
 	}
-	envCons, err := st.EnvironConstraints()
+	return resolver.Resolve(validator, layers)
+}
+
+// resolveConstraints combines the given constraints with the environ constraints to get
+// a constraints which will be used to create a new instance.
+func (st *State) resolveConstraints(layers resolver.Layers) (constraints.Value, error) {
+	effective, err := st.ResolveConstraints(layers)
 	if err != nil {
 		return constraints.Value{}, err
 	}
-	return validator.Merge(envCons, cons)
+	return effective.Value, nil
 }
 
 // validateConstraints returns an error if the given constraints are not valid for the
@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ProvisioningError describes a machine that is stuck in an error state
+// because no instance could be provisioned for it.
+type ProvisioningError struct {
+	// MachineId holds the id of the machine.
+	MachineId string
+
+	// Message holds the machine's status message.
+	Message string
+
+	// Data holds the machine's status data, which includes the raw
+	// error reported by the provider when it is known.
+	Data map[string]interface{}
+
+	// Since holds when the machine entered the error state.
+	Since time.Time
+}
+
+// ProvisioningErrors returns the alive machines of the environment that
+// have no instance and whose status has been StatusError for at least
+// the given duration, ordered by machine id.
+func (st *State) ProvisioningErrors(threshold time.Duration) ([]ProvisioningError, error) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cutoff := time.Now().Add(-threshold)
+	var result []ProvisioningError
+	for _, m := range machines {
+		if m.Life() != Alive {
+			continue
+		}
+		if _, err := m.InstanceId(); err == nil {
+			continue
+		} else if !errors.IsNotProvisioned(err) {
+			return nil, errors.Trace(err)
+		}
+		statusInfo, err := m.Status()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if statusInfo.Status != StatusError || statusInfo.Since == nil {
+			continue
+		}
+		if statusInfo.Since.After(cutoff) {
+			continue
+		}
+		result = append(result, ProvisioningError{
+			MachineId: m.Id(),
+			Message:   statusInfo.Message,
+			Data:      statusInfo.Data,
+			Since:     *statusInfo.Since,
+		})
+	}
+	return result, nil
+}
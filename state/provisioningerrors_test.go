@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type ProvisioningErrorsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ProvisioningErrorsSuite{})
+
+func (s *ProvisioningErrorsSuite) TestProvisioningErrors(c *gc.C) {
+	m0, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	m1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	m2, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data := map[string]interface{}{"provider-error": "quota exceeded"}
+	err = m0.SetStatus(state.StatusError, "cannot start instance", data)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m1.SetStatus(state.StatusPending, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m2.SetProvisioned(instance.Id("i-2"), "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = m2.SetStatus(state.StatusError, "agent failed", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	errs, err := s.State.ProvisioningErrors(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0].MachineId, gc.Equals, m0.Id())
+	c.Assert(errs[0].Message, gc.Equals, "cannot start instance")
+	c.Assert(errs[0].Data, jc.DeepEquals, data)
+	c.Assert(errs[0].Since.IsZero(), jc.IsFalse)
+
+	// Machines are only reported once they have been stuck for the
+	// given duration.
+	errs, err = s.State.ProvisioningErrors(time.Hour)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 0)
+
+	// Dying machines are not reported.
+	err = m0.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	errs, err = s.State.ProvisioningErrors(0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 0)
+}
+
+func (s *ProvisioningErrorsSuite) TestWatchMachineStatuses(c *gc.C) {
+	m0, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.State.WatchMachineStatuses()
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(m0.Id())
+	wc.AssertNoChange()
+
+	err = m0.SetStatus(state.StatusError, "cannot start instance", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(m0.Id())
+	wc.AssertNoChange()
+
+	m1, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(m1.Id())
+	wc.AssertNoChange()
+
+	// Unit statuses are not reported.
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusIdle, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+}
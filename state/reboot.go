@@ -11,6 +11,7 @@ import (
 
 var _ RebootFlagSetter = (*Machine)(nil)
 var _ RebootActionGetter = (*Machine)(nil)
+var _ RebootStatusSetter = (*Machine)(nil)
 
 // RebootAction defines the action a machine should
 // take when a hook needs to reboot
@@ -30,11 +31,38 @@ const (
 	ShouldShutdown RebootAction = "shutdown"
 )
 
+// RebootStatus records how far a machine has got in carrying out a
+// requested reboot.
+type RebootStatus string
+
+const (
+	// RebootPending indicates that a reboot has been requested but
+	// the machine agent has not yet acted on it.
+	RebootPending RebootStatus = "pending"
+	// RebootDraining indicates that the machine agent is waiting for
+	// running hooks to finish before rebooting.
+	RebootDraining RebootStatus = "draining"
+	// RebootStarted indicates that no hooks are running, and that the
+	// machine agent has stopped its workers in order to reboot.
+	RebootStarted RebootStatus = "rebooting"
+)
+
+// validRebootStatus reports whether status is one of the known
+// reboot statuses.
+func validRebootStatus(status RebootStatus) bool {
+	switch status {
+	case RebootPending, RebootDraining, RebootStarted:
+		return true
+	}
+	return false
+}
+
 // rebootDoc will hold the reboot flag for a machine.
 type rebootDoc struct {
-	DocID   string `bson:"_id"`
-	Id      string `bson:"machineid"`
-	EnvUUID string `bson:"env-uuid"`
+	DocID   string       `bson:"_id"`
+	Id      string       `bson:"machineid"`
+	EnvUUID string       `bson:"env-uuid"`
+	Status  RebootStatus `bson:"status,omitempty"`
 }
 
 func (m *Machine) setFlag() error {
@@ -48,7 +76,7 @@ func (m *Machine) setFlag() error {
 	}, {
 		C:      rebootC,
 		Id:     m.doc.DocID,
-		Insert: &rebootDoc{Id: m.Id()},
+		Insert: &rebootDoc{Id: m.Id(), Status: RebootPending},
 	}}
 	err := m.st.runTransaction(ops)
 	if err == txn.ErrAborted {
@@ -110,6 +138,49 @@ func (m *Machine) GetRebootFlag() (bool, error) {
 	return true, nil
 }
 
+// RebootStatus returns the progress of the reboot requested for this
+// machine. An error satisfying errors.IsNotFound is returned if no
+// reboot has been requested.
+func (m *Machine) RebootStatus() (RebootStatus, error) {
+	rebootCol, closer := m.st.getCollection(rebootC)
+	defer closer()
+
+	var doc rebootDoc
+	err := rebootCol.FindId(m.doc.DocID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("reboot request for machine %v", m.Id())
+	} else if err != nil {
+		return "", errors.Annotate(err, "cannot get reboot status")
+	}
+	if doc.Status == "" {
+		// Reboot flags set before reboot statuses were recorded.
+		return RebootPending, nil
+	}
+	return doc.Status, nil
+}
+
+// SetRebootStatus records the progress of the reboot requested for
+// this machine. An error satisfying errors.IsNotFound is returned if
+// no reboot has been requested.
+func (m *Machine) SetRebootStatus(status RebootStatus) error {
+	if !validRebootStatus(status) {
+		return errors.NotValidf("reboot status %q", status)
+	}
+	ops := []txn.Op{{
+		C:      rebootC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"status", status}}}},
+	}}
+	err := m.st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		return errors.NotFoundf("reboot request for machine %v", m.Id())
+	} else if err != nil {
+		return errors.Annotate(err, "cannot set reboot status")
+	}
+	return nil
+}
+
 func (m *Machine) machinesToCareAboutRebootsFor() []string {
 	var possibleIds []string
 	for currentId := m.Id(); currentId != ""; {
@@ -154,3 +225,10 @@ type RebootFlagSetter interface {
 type RebootActionGetter interface {
 	ShouldRebootOrShutdown() (RebootAction, error)
 }
+
+// RebootStatusSetter is implemented by entities which record the
+// progress of a requested reboot.
+type RebootStatusSetter interface {
+	RebootStatus() (RebootStatus, error)
+	SetRebootStatus(status RebootStatus) error
+}
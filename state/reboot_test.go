@@ -5,6 +5,7 @@
 package state_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -228,3 +229,45 @@ func (s *RebootSuite) TestWatchRebootHappensOnContainer3(c *gc.C) {
 	statetesting.AssertStop(c, s.wC3)
 	s.wcC3.AssertClosed()
 }
+
+func (s *RebootSuite) TestRebootStatus(c *gc.C) {
+	_, err := s.machine.RebootStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.machine.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.RebootPending)
+
+	err = s.machine.SetRebootStatus(state.RebootDraining)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.RebootDraining)
+
+	// Requesting the reboot again does not reset its progress.
+	err = s.machine.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.RebootDraining)
+
+	err = s.machine.SetRebootFlag(false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.machine.RebootStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RebootSuite) TestSetRebootStatusWithoutRequest(c *gc.C) {
+	err := s.machine.SetRebootStatus(state.RebootStarted)
+	c.Assert(err, gc.ErrorMatches, "reboot request for machine 0 not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RebootSuite) TestSetRebootStatusInvalid(c *gc.C) {
+	err := s.machine.SetRebootFlag(true)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetRebootStatus("bouncing")
+	c.Assert(err, gc.ErrorMatches, `reboot status "bouncing" not valid`)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -44,6 +45,12 @@ type relationDoc struct {
 	Endpoints []Endpoint
 	Life      Life
 	UnitCount int
+
+	// ScopeFilters holds, for principal services in a container-scoped
+	// relation, the names of the units that are to host units of the
+	// subordinate service. Every unit of a principal service without a
+	// filter hosts one.
+	ScopeFilters map[string][]string `bson:",omitempty"`
 }
 
 // Relation represents a relation between one or two service endpoints.
@@ -294,3 +301,147 @@ func (r *Relation) Unit(u *Unit) (*RelationUnit, error) {
 		scope:    strings.Join(scope, "#"),
 	}, nil
 }
+
+// ScopeFilter returns the names of the units of the named principal
+// service that are to host units of the subordinate service in a
+// container-scoped relation. If there is no filter, every unit of the
+// principal service hosts one, and ok is false.
+func (r *Relation) ScopeFilter(serviceName string) (unitNames []string, ok bool) {
+	unitNames, ok = r.doc.ScopeFilters[serviceName]
+	return unitNames, ok
+}
+
+// SetScopeFilter restricts the units of the named principal service
+// that host units of the subordinate service in a container-scoped
+// relation to those named. Units of the principal service that are
+// already in scope and are newly included gain a subordinate unit, and
+// the subordinate units of those that are excluded are destroyed.
+func (r *Relation) SetScopeFilter(serviceName string, unitNames []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set scope filter for service %q in relation %q", serviceName, r)
+	filter := set.NewStrings()
+	for _, name := range unitNames {
+		if !names.IsValidUnit(name) {
+			return errors.NotValidf("unit name %q", name)
+		}
+		if unitService, _ := names.UnitService(name); unitService != serviceName {
+			return errors.Errorf("unit %q is not a unit of service %q", name, serviceName)
+		}
+		filter.Add(name)
+	}
+	update := bson.D{{"$set", bson.D{{"scopefilters." + serviceName, filter.SortedValues()}}}}
+	return r.updateScopeFilter(serviceName, update)
+}
+
+// ClearScopeFilter removes the scope filter for the named principal
+// service, so that every unit of the service hosts a unit of the
+// subordinate service.
+func (r *Relation) ClearScopeFilter(serviceName string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot clear scope filter for service %q in relation %q", serviceName, r)
+	update := bson.D{{"$unset", bson.D{{"scopefilters." + serviceName, nil}}}}
+	return r.updateScopeFilter(serviceName, update)
+}
+
+func (r *Relation) updateScopeFilter(serviceName string, update bson.D) error {
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return err
+	}
+	if ep.Scope != charm.ScopeContainer {
+		return errors.Errorf("relation does not have container scope")
+	}
+	service, err := r.st.Service(serviceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !service.IsPrincipal() {
+		return errors.Errorf("service %q is not a principal service", serviceName)
+	}
+	ops := []txn.Op{{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: isAliveDoc,
+		Update: update,
+	}}
+	if err := r.st.runTransaction(ops); err != nil {
+		return onAbort(err, errNotAlive)
+	}
+	if err := r.Refresh(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(r.applyScopeFilter(service))
+}
+
+// applyScopeFilter brings the subordinate units hosted by the units of
+// the given principal service in line with the relation's scope filter.
+func (r *Relation) applyScopeFilter(service *Service) error {
+	related, err := r.RelatedEndpoints(service.Name())
+	if err != nil {
+		return err
+	}
+	if len(related) != 1 {
+		return fmt.Errorf("expected single related endpoint, got %v", related)
+	}
+	subordinateService := related[0].ServiceName
+	units, err := service.AllUnits()
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if r.hostsSubordinate(service.Name(), unit.Name()) {
+			ru, err := r.Unit(unit)
+			if err != nil {
+				return err
+			}
+			if err := ru.ensureSubordinate(); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, name := range unit.SubordinateNames() {
+			if unitService, _ := names.UnitService(name); unitService != subordinateService {
+				continue
+			}
+			subordinate, err := r.st.Unit(name)
+			if errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if err := subordinate.Destroy(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// hostsSubordinate reports whether the relation's scope filter allows
+// the named unit of the named principal service to host a unit of the
+// subordinate service.
+func (r *Relation) hostsSubordinate(serviceName, unitName string) bool {
+	filter, ok := r.doc.ScopeFilters[serviceName]
+	if !ok {
+		return true
+	}
+	for _, name := range filter {
+		if name == unitName {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeFilterAssertOp returns an operation that asserts that the
+// relation's scope filter for the named service has not changed.
+func (r *Relation) scopeFilterAssertOp(serviceName string) txn.Op {
+	field := "scopefilters." + serviceName
+	assert := bson.D{{field, bson.D{{"$exists", false}}}}
+	if filter, ok := r.doc.ScopeFilters[serviceName]; ok {
+		assert = bson.D{{field, filter}}
+	}
+	return txn.Op{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: assert,
+	}
+}
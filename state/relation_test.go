@@ -315,3 +315,144 @@ func assertOneRelation(c *gc.C, srv *state.Service, relId int, endpoints ...stat
 	c.Assert(eps, gc.DeepEquals, []state.Endpoint{expectEp})
 	return rel
 }
+
+func (s *RelationSuite) addContainerRelation(c *gc.C) (*state.Relation, *state.Service, *state.Service) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	logging := s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("wordpress", "logging")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	return rel, wordpress, logging
+}
+
+func (s *RelationSuite) enterScope(c *gc.C, rel *state.Relation, unit *state.Unit) {
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RelationSuite) assertSubordinates(c *gc.C, unit *state.Unit, expect ...string) {
+	err := unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unit.SubordinateNames(), jc.SameContents, expect)
+}
+
+func (s *RelationSuite) TestScopeFilter(c *gc.C) {
+	rel, wordpress, logging := s.addContainerRelation(c)
+	_, ok := rel.ScopeFilter("wordpress")
+	c.Assert(ok, jc.IsFalse)
+
+	err := rel.SetScopeFilter("wordpress", []string{"wordpress/1", "wordpress/0", "wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+	filter, ok := rel.ScopeFilter("wordpress")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(filter, jc.DeepEquals, []string{"wordpress/0", "wordpress/1"})
+
+	err = rel.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	filter, ok = rel.ScopeFilter("wordpress")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(filter, jc.DeepEquals, []string{"wordpress/0", "wordpress/1"})
+
+	err = rel.ClearScopeFilter("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = rel.ScopeFilter("wordpress")
+	c.Assert(ok, jc.IsFalse)
+
+	// Services without a filter are unaffected.
+	units, err := logging.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+	units, err = wordpress.AllUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+}
+
+func (s *RelationSuite) TestScopeFilterErrors(c *gc.C) {
+	rel, _, _ := s.addContainerRelation(c)
+	err := rel.SetScopeFilter("logging", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set scope filter for service "logging" in relation "logging:info wordpress:juju-info": service "logging" is not a principal service`)
+	err = rel.SetScopeFilter("mysql", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set scope filter for service "mysql" in relation .*: service "mysql" is not a member of .*`)
+	err = rel.SetScopeFilter("wordpress", []string{"mysql/0"})
+	c.Assert(err, gc.ErrorMatches, `cannot set scope filter .*: unit "mysql/0" is not a unit of service "wordpress"`)
+	err = rel.SetScopeFilter("wordpress", []string{"wordpress"})
+	c.Assert(err, gc.ErrorMatches, `cannot set scope filter .*: unit name "wordpress" not valid`)
+
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	globalRel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	err = globalRel.SetScopeFilter("wordpress", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set scope filter .*: relation does not have container scope`)
+
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.ClearScopeFilter("wordpress")
+	c.Assert(err, gc.ErrorMatches, `cannot clear scope filter .*: not found or not alive`)
+}
+
+func (s *RelationSuite) TestScopeFilterRestrictsSubordinates(c *gc.C) {
+	rel, wordpress, _ := s.addContainerRelation(c)
+	wordpress0, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress1, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	// The relation used to enter scope need not be up to date.
+	staleRel, err := s.State.Relation(rel.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetScopeFilter("wordpress", []string{"wordpress/1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.enterScope(c, staleRel, wordpress0)
+	s.assertSubordinates(c, wordpress0)
+	s.enterScope(c, staleRel, wordpress1)
+	s.assertSubordinates(c, wordpress1, "logging/0")
+}
+
+func (s *RelationSuite) TestSetScopeFilterUpdatesSubordinates(c *gc.C) {
+	rel, wordpress, _ := s.addContainerRelation(c)
+	wordpress0, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress1, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress2, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetScopeFilter("wordpress", []string{"wordpress/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.enterScope(c, rel, wordpress0)
+	s.enterScope(c, rel, wordpress1)
+	s.assertSubordinates(c, wordpress0, "logging/0")
+	s.assertSubordinates(c, wordpress1)
+
+	// wordpress/1 is in scope, so it gains a subordinate; wordpress/2 is
+	// not, so it does not.
+	err = rel.SetScopeFilter("wordpress", []string{"wordpress/1", "wordpress/2"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubordinates(c, wordpress1, "logging/1")
+	s.assertSubordinates(c, wordpress2)
+
+	// The subordinate of the excluded wordpress/0 is destroyed.
+	logging0, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(logging0.Life(), gc.Equals, state.Dying)
+	logging1, err := s.State.Unit("logging/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(logging1.Life(), gc.Equals, state.Alive)
+
+	// Including wordpress/0 again fails until the old subordinate has
+	// been removed.
+	err = rel.ClearScopeFilter("wordpress")
+	c.Assert(err, gc.ErrorMatches, `cannot clear scope filter .*: subordinate of unit "wordpress/0" is not alive; retry once it has been removed`)
+	err = logging0.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = logging0.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.ClearScopeFilter("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertSubordinates(c, wordpress0, "logging/2")
+	s.assertSubordinates(c, wordpress1, "logging/1")
+}
@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// relationServiceSettingsSchemaVersion is the version of the layout of
+// the settings held in relationServiceSettingsDoc. Documents written
+// with any other version are neither read nor changed.
+const relationServiceSettingsSchemaVersion = 1
+
+// relationServiceSettingsDoc holds the settings that a service shares
+// with the other services in a relation, as distinct from the settings
+// of each of its units. Its key is the relation settings prefix of the
+// relation followed by the service name, so that it is removed along
+// with the relation's unit settings.
+type relationServiceSettingsDoc struct {
+	DocID         string                 `bson:"_id"`
+	EnvUUID       string                 `bson:"env-uuid"`
+	Relation      string                 `bson:"relation"`
+	Service       string                 `bson:"service"`
+	SchemaVersion int                    `bson:"schema-version"`
+	Settings      map[string]interface{} `bson:"settings"`
+}
+
+func (r *Relation) serviceSettingsKey(serviceName string) string {
+	return fmt.Sprintf("r#%d#%s", r.Id(), serviceName)
+}
+
+// readServiceSettingsDoc returns the service settings document of the
+// named service in the relation, or nil if none has been written.
+func (r *Relation) readServiceSettingsDoc(serviceName string) (*relationServiceSettingsDoc, error) {
+	coll, closer := r.st.getCollection(relationServiceSettingsC)
+	defer closer()
+
+	var doc relationServiceSettingsDoc
+	err := coll.FindId(r.st.docID(r.serviceSettingsKey(serviceName))).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if doc.SchemaVersion != relationServiceSettingsSchemaVersion {
+		return nil, errors.NotSupportedf("settings schema version %d", doc.SchemaVersion)
+	}
+	return &doc, nil
+}
+
+// ServiceSettings returns the settings that the named service shares
+// with the other services in the relation. Only the leader of a service
+// is expected to write them, using UpdateServiceSettings; they are
+// distinct from the settings of the service's units.
+func (r *Relation) ServiceSettings(serviceName string) (_ map[string]interface{}, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot read settings for service %q in relation %q", serviceName, r)
+	if _, err := r.Endpoint(serviceName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc, err := r.readServiceSettingsDoc(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if doc == nil {
+		return make(map[string]interface{}), nil
+	}
+	return copyMap(doc.Settings, unescapeReplacer.Replace), nil
+}
+
+// UpdateServiceSettings applies the given changes to the settings that
+// the named service shares with the other services in the relation.
+// Settings with nil values are deleted. It is the caller's
+// responsibility to ensure that only the service's leader does so.
+func (r *Relation) UpdateServiceSettings(serviceName string, changes map[string]interface{}) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot write settings for service %q in relation %q", serviceName, r)
+	if _, err := r.Endpoint(serviceName); err != nil {
+		return errors.Trace(err)
+	}
+	key := r.serviceSettingsKey(serviceName)
+	relationExists := txn.Op{
+		C:      relationsC,
+		Id:     r.doc.DocID,
+		Assert: txn.DocExists,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := r.readServiceSettingsDoc(serviceName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if doc == nil {
+			settings := make(map[string]interface{})
+			for name, value := range changes {
+				if value != nil {
+					settings[escapeReplacer.Replace(name)] = value
+				}
+			}
+			if len(settings) == 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			return []txn.Op{relationExists, {
+				C:      relationServiceSettingsC,
+				Id:     r.st.docID(key),
+				Assert: txn.DocMissing,
+				Insert: &relationServiceSettingsDoc{
+					DocID:         r.st.docID(key),
+					EnvUUID:       r.st.EnvironUUID(),
+					Relation:      r.doc.Key,
+					Service:       serviceName,
+					SchemaVersion: relationServiceSettingsSchemaVersion,
+					Settings:      settings,
+				},
+			}}, nil
+		}
+		updates := bson.M{}
+		deletions := bson.M{}
+		for name, value := range changes {
+			field := "settings." + escapeReplacer.Replace(name)
+			if value != nil {
+				updates[field] = value
+			} else if _, ok := doc.Settings[escapeReplacer.Replace(name)]; ok {
+				deletions[field] = 1
+			}
+		}
+		if len(updates) == 0 && len(deletions) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{relationExists, {
+			C:      relationServiceSettingsC,
+			Id:     r.st.docID(key),
+			Assert: bson.D{{"schema-version", relationServiceSettingsSchemaVersion}},
+			Update: setUnsetUpdate(updates, deletions),
+		}}, nil
+	}
+	return r.st.run(buildTxn)
+}
@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type RelationServiceSettingsSuite struct {
+	ConnSuite
+	rel *state.Relation
+}
+
+var _ = gc.Suite(&RelationServiceSettingsSuite{})
+
+func (s *RelationServiceSettingsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.rel, err = s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RelationServiceSettingsSuite) TestServiceSettingsInitiallyEmpty(c *gc.C) {
+	settings, err := s.rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *RelationServiceSettingsSuite) TestUpdateServiceSettings(c *gc.C) {
+	err := s.rel.UpdateServiceSettings("mysql", map[string]interface{}{
+		"database": "wordpress",
+		"a.b":      "dotted",
+		"unset":    nil,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := s.rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{
+		"database": "wordpress",
+		"a.b":      "dotted",
+	})
+
+	err = s.rel.UpdateServiceSettings("mysql", map[string]interface{}{
+		"database": "blog",
+		"a.b":      nil,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err = s.rel.ServiceSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]interface{}{
+		"database": "blog",
+	})
+
+	// Each service's settings are distinct.
+	settings, err = s.rel.ServiceSettings("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, gc.HasLen, 0)
+}
+
+func (s *RelationServiceSettingsSuite) TestServiceSettingsUnknownService(c *gc.C) {
+	_, err := s.rel.ServiceSettings("riak")
+	c.Assert(err, gc.ErrorMatches, `cannot read settings for service "riak" in relation "wordpress:db mysql:server": service "riak" is not a member of "wordpress:db mysql:server"`)
+	err = s.rel.UpdateServiceSettings("riak", map[string]interface{}{"foo": "bar"})
+	c.Assert(err, gc.ErrorMatches, `cannot write settings for service "riak" in relation "wordpress:db mysql:server": service "riak" is not a member of "wordpress:db mysql:server"`)
+}
+
+func (s *RelationServiceSettingsSuite) TestServiceSettingsRemovedWithRelation(c *gc.C) {
+	err := s.rel.UpdateServiceSettings("mysql", map[string]interface{}{"database": "wordpress"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll := s.MgoSuite.Session.DB("juju").C("relationservicesettings")
+	count, err := coll.Find(nil).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 0)
+
+	err = s.rel.UpdateServiceSettings("mysql", map[string]interface{}{"database": "wordpress"})
+	c.Assert(err, gc.ErrorMatches, `cannot write settings for service "mysql" in relation "wordpress:db mysql:server": state changing too quickly; try again soon`)
+}
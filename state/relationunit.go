@@ -219,6 +219,17 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 		return nil, "", fmt.Errorf("expected single related endpoint, got %v", related)
 	}
 	serviceName, unitName := related[0].ServiceName, ru.unit.doc.Name
+
+	// The scope filter may have changed since the relation was read; use
+	// the current one, and make sure it is still current when the ops run.
+	rel := &Relation{st: ru.st, doc: ru.relation.doc}
+	if err := rel.Refresh(); err != nil && !errors.IsNotFound(err) {
+		return nil, "", err
+	}
+	filterOp := rel.scopeFilterAssertOp(ru.endpoint.ServiceName)
+	if !rel.hostsSubordinate(ru.endpoint.ServiceName, unitName) {
+		return []txn.Op{filterOp}, "", nil
+	}
 	selSubordinate := bson.D{{"service", serviceName}, {"principal", unitName}}
 	var lDoc lifeDoc
 	if err := units.Find(selSubordinate).One(&lDoc); err == mgo.ErrNotFound {
@@ -227,7 +238,7 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 			return nil, "", err
 		}
 		_, ops, err := service.addUnitOps(unitName, nil)
-		return ops, "", err
+		return append(ops, filterOp), "", err
 	} else if err != nil {
 		return nil, "", err
 	} else if lDoc.Life != Alive {
@@ -237,7 +248,37 @@ func (ru *RelationUnit) subordinateOps() ([]txn.Op, string, error) {
 		C:      unitsC,
 		Id:     lDoc.Id,
 		Assert: isAliveDoc,
-	}}, lDoc.Id, nil
+	}, filterOp}, lDoc.Id, nil
+}
+
+// ensureSubordinate creates the subordinate unit required by a principal
+// unit that is already in scope, if it does not already exist.
+func (ru *RelationUnit) ensureSubordinate() error {
+	key, err := ru.key(ru.unit.Name())
+	if err != nil {
+		return err
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if inScope, err := ru.InScope(); err != nil {
+			return nil, err
+		} else if !inScope {
+			return nil, jujutxn.ErrNoOperations
+		}
+		subOps, subName, err := ru.subordinateOps()
+		if err == ErrCannotEnterScopeYet {
+			return nil, errors.Errorf("subordinate of unit %q is not alive; retry once it has been removed", ru.unit)
+		} else if err != nil {
+			return nil, err
+		} else if subName != "" {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(subOps, txn.Op{
+			C:      relationScopesC,
+			Id:     ru.st.docID(key),
+			Assert: txn.DocExists,
+		}), nil
+	}
+	return ru.st.run(buildTxn)
 }
 
 // PrepareLeaveScope causes the unit to be reported as departed by watchers,
@@ -261,7 +302,94 @@ func (ru *RelationUnit) PrepareLeaveScope() error {
 		Id:     ru.st.docID(key),
 		Update: bson.D{{"$set", bson.D{{"departing", true}}}},
 	}}
-	return ru.st.runTransaction(ops)
+	snapshotOp, err := ru.settingsSnapshotOp(key)
+	if err != nil {
+		return err
+	} else if snapshotOp != nil {
+		ops = append(ops, *snapshotOp)
+	}
+	if err := ru.st.runTransaction(ops); err == txn.ErrAborted {
+		// Another snapshot was written concurrently; that one will do.
+		return ru.st.runTransaction(ops[:1])
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// relationSettingsSnapshotDoc holds the settings of the counterpart units
+// in a unit's relation scope, as they were when the unit began to depart
+// the relation. It is keyed on the departing unit's relation key, and
+// lives until that unit leaves scope or the relation is removed.
+type relationSettingsSnapshotDoc struct {
+	DocID    string                            `bson:"_id"`
+	EnvUUID  string                            `bson:"env-uuid"`
+	Settings map[string]map[string]interface{} `bson:"settings"`
+}
+
+// settingsSnapshotOp returns an op that records the current settings of
+// the counterpart units in the unit's scope, or nil if a snapshot was
+// already recorded.
+func (ru *RelationUnit) settingsSnapshotOp(key string) (*txn.Op, error) {
+	snapshots, closer := ru.st.getCollection(relationSettingsSnapshotsC)
+	defer closer()
+	if count, err := snapshots.FindId(key).Count(); err != nil {
+		return nil, err
+	} else if count > 0 {
+		return nil, nil
+	}
+
+	relationScopes, closer := ru.st.getCollection(relationScopesC)
+	defer closer()
+	prefix := ru.scope + "#" + string(counterpartRole(ru.endpoint.Role)) + "#"
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + prefix}}}}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, err
+	}
+	settings := make(map[string]map[string]interface{})
+	for _, doc := range docs {
+		uname := doc.unitName()
+		if uname == ru.unit.Name() {
+			continue
+		}
+		node, err := readSettings(ru.st, doc.Key)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		settings[uname] = copyMap(node.Map(), escapeReplacer.Replace)
+	}
+	return &txn.Op{
+		C:      relationSettingsSnapshotsC,
+		Id:     ru.st.docID(key),
+		Assert: txn.DocMissing,
+		Insert: &relationSettingsSnapshotDoc{Settings: settings},
+	}, nil
+}
+
+// snapshotSettings returns the settings of the named unit recorded when
+// this unit began to depart the relation, and whether there were any.
+func (ru *RelationUnit) snapshotSettings(uname string) (map[string]interface{}, bool, error) {
+	snapshots, closer := ru.st.getCollection(relationSettingsSnapshotsC)
+	defer closer()
+
+	key, err := ru.key(ru.unit.Name())
+	if err != nil {
+		return nil, false, err
+	}
+	var doc relationSettingsSnapshotDoc
+	if err := snapshots.FindId(key).One(&doc); err == mgo.ErrNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	settings, ok := doc.Settings[uname]
+	if !ok {
+		return nil, false, nil
+	}
+	return copyMap(settings, unescapeReplacer.Replace), true, nil
 }
 
 // LeaveScope signals that the unit has left its scope in the relation.
@@ -319,6 +447,10 @@ func (ru *RelationUnit) LeaveScope() error {
 			Id:     ru.st.docID(key),
 			Assert: txn.DocExists,
 			Remove: true,
+		}, {
+			C:      relationSettingsSnapshotsC,
+			Id:     ru.st.docID(key),
+			Remove: true,
 		}}
 		if ru.relation.doc.Life == Alive {
 			ops = append(ops, txn.Op{
@@ -403,6 +535,11 @@ func (ru *RelationUnit) Settings() (*Settings, error) {
 // unit is not grounds for an error, because the unit settings are
 // guaranteed to persist for the lifetime of the relation, regardless
 // of the lifetime of the unit.
+//
+// Once this unit has been prepared to leave scope, the settings of the
+// units that were in scope at the time are read from the snapshot taken
+// then, so that its departed and broken hooks see the relation as it was
+// when it began to depart.
 func (ru *RelationUnit) ReadSettings(uname string) (m map[string]interface{}, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot read settings for unit %q in relation %q", uname, ru.relation)
 	if !names.IsValidUnit(uname) {
@@ -412,6 +549,11 @@ func (ru *RelationUnit) ReadSettings(uname string) (m map[string]interface{}, er
 	if err != nil {
 		return nil, err
 	}
+	if settings, ok, err := ru.snapshotSettings(uname); err != nil {
+		return nil, err
+	} else if ok {
+		return settings, nil
+	}
 	node, err := readSettings(ru.st, key)
 	if err != nil {
 		return nil, err
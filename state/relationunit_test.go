@@ -717,6 +717,94 @@ func (s *RelationUnitSuite) TestPrepareLeaveScope(c *gc.C) {
 	c.Assert(err, jc.Satisfies, errors.IsNotFound)
 }
 
+func (s *RelationUnitSuite) TestPrepareLeaveScopeSnapshotsSettings(c *gc.C) {
+	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	err := prr.pru0.EnterScope(map[string]interface{}{"host": "mysql-0.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru0.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.rru1.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// rru0 begins to depart; the settings it sees are fixed from now on,
+	// however often it prepares to leave.
+	err = prr.rru0.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	setHost := func(ru *state.RelationUnit, host string) {
+		node, err := ru.Settings()
+		c.Assert(err, jc.ErrorIsNil)
+		node.Set("host", host)
+		_, err = node.Write()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	setHost(prr.pru0, "mysql-0.example.net")
+	err = prr.rru0.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertHost := func(ru *state.RelationUnit, uname, host string) {
+		m, err := ru.ReadSettings(uname)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(m["host"], gc.Equals, host)
+	}
+	assertHost(prr.rru0, "mysql/0", "mysql-0.example.com")
+	assertHost(prr.rru1, "mysql/0", "mysql-0.example.net")
+
+	// Units that enter scope after the snapshot are read directly.
+	err = prr.pru1.EnterScope(map[string]interface{}{"host": "mysql-1.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	assertHost(prr.rru0, "mysql/1", "mysql-1.example.com")
+
+	// The snapshot survives the relation's destruction until rru0
+	// leaves scope.
+	err = prr.rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	assertHost(prr.rru0, "mysql/0", "mysql-0.example.com")
+	err = prr.rru0.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	assertHost(prr.rru0, "mysql/0", "mysql-0.example.net")
+}
+
+func (s *RelationUnitSuite) TestPrepareLeaveScopeSnapshotPeers(c *gc.C) {
+	pr := NewPeerRelation(c, s.State, s.Owner)
+	err := pr.ru0.EnterScope(map[string]interface{}{"value": 0})
+	c.Assert(err, jc.ErrorIsNil)
+	err = pr.ru1.EnterScope(map[string]interface{}{"value": 0})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = pr.ru0.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	changeSettings(c, pr.ru0)
+	changeSettings(c, pr.ru1)
+
+	// The departing unit's own settings are never snapshotted.
+	m, err := pr.ru0.ReadSettings("riak/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m["value"], gc.Equals, 1)
+	m, err = pr.ru0.ReadSettings("riak/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m["value"], gc.Equals, 0)
+	m, err = pr.ru2.ReadSettings("riak/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m["value"], gc.Equals, 1)
+}
+
+func (s *RelationUnitSuite) TestPrepareLeaveScopeNotInScope(c *gc.C) {
+	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	err := prr.pru0.EnterScope(map[string]interface{}{"host": "mysql-0.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A unit that is not in scope takes no snapshot.
+	err = prr.rru0.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	node, err := prr.pru0.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	node.Set("host", "mysql-0.example.net")
+	_, err = node.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := prr.rru0.ReadSettings("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m["host"], gc.Equals, "mysql-0.example.net")
+}
+
 func (s *RelationUnitSuite) assertScopeChange(c *gc.C, w *state.RelationScopeWatcher, entered, left []string) {
 	s.State.StartSync()
 	select {
@@ -0,0 +1,142 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// ScaleEvent records a change made to the number of units of a
+// service, and why it was made.
+type ScaleEvent struct {
+	// Service is the name of the scaled service.
+	Service string
+
+	// Previous and Desired hold the number of live units of the
+	// service before and after it was scaled.
+	Previous int
+	Desired  int
+
+	// Reason describes why the service was scaled.
+	Reason string
+
+	// Requester is the tag of the entity that scaled the service.
+	Requester string
+
+	// Time is when the service was scaled.
+	Time time.Time
+}
+
+type scaleEventDoc struct {
+	DocID     string    `bson:"_id"`
+	EnvUUID   string    `bson:"env-uuid"`
+	Seq       int       `bson:"seq"`
+	Service   string    `bson:"service"`
+	Previous  int       `bson:"previous"`
+	Desired   int       `bson:"desired"`
+	Reason    string    `bson:"reason"`
+	Requester string    `bson:"requester"`
+	Time      time.Time `bson:"time"`
+}
+
+func (doc *scaleEventDoc) event() ScaleEvent {
+	return ScaleEvent{
+		Service:   doc.Service,
+		Previous:  doc.Previous,
+		Desired:   doc.Desired,
+		Reason:    doc.Reason,
+		Requester: doc.Requester,
+		Time:      doc.Time,
+	}
+}
+
+// AddScaleEvent records that the given service was scaled. The time
+// of the event is that at which it is recorded.
+func (st *State) AddScaleEvent(serviceName string, previous, desired int, reason string, requester names.Tag) (_ *ScaleEvent, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot record scaling of service %q", serviceName)
+	if reason == "" {
+		return nil, errors.New("empty reason")
+	}
+	seq, err := st.sequence("scaleevent")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	doc := &scaleEventDoc{
+		DocID:     st.docID(fmt.Sprint(seq)),
+		EnvUUID:   st.EnvironUUID(),
+		Seq:       seq,
+		Service:   serviceName,
+		Previous:  previous,
+		Desired:   desired,
+		Reason:    reason,
+		Requester: requester.String(),
+		// Times are stored to the millisecond, so round this one to
+		// match the time that is read back.
+		Time: time.Now().Round(time.Millisecond),
+	}
+	ops := []txn.Op{{
+		C:      scaleEventsC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Trace(err)
+	}
+	event := doc.event()
+	return &event, nil
+}
+
+// ScaleEvents returns the scale events recorded after the given time,
+// oldest first. If serviceName is not empty, only the events of that
+// service are returned.
+func (st *State) ScaleEvents(serviceName string, since time.Time) ([]ScaleEvent, error) {
+	events, closer := st.getCollection(scaleEventsC)
+	defer closer()
+
+	sel := bson.D{{"time", bson.D{{"$gt", since}}}}
+	if serviceName != "" {
+		sel = append(sel, bson.DocElem{"service", serviceName})
+	}
+	var docs []scaleEventDoc
+	if err := events.Find(sel).Sort("seq").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get scale events")
+	}
+	result := make([]ScaleEvent, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.event()
+	}
+	return result, nil
+}
+
+// LastScaleEvent returns the most recent scale event of the given
+// service, or of any service if serviceName is empty.
+func (st *State) LastScaleEvent(serviceName string) (*ScaleEvent, error) {
+	events, closer := st.getCollection(scaleEventsC)
+	defer closer()
+
+	var sel bson.D
+	if serviceName != "" {
+		sel = bson.D{{"service", serviceName}}
+	}
+	var doc scaleEventDoc
+	err := events.Find(sel).Sort("-seq").One(&doc)
+	if err == mgo.ErrNotFound {
+		if serviceName == "" {
+			return nil, errors.NotFoundf("scale event")
+		}
+		return nil, errors.NotFoundf("scale event of service %q", serviceName)
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get scale events")
+	}
+	event := doc.event()
+	return &event, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type ScaleEventsSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ScaleEventsSuite{})
+
+func (s *ScaleEventsSuite) TestAddScaleEvent(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	admin := names.NewLocalUserTag("admin")
+	event, err := s.State.AddScaleEvent("wordpress", 1, 3, "load is high", admin)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(event.Service, gc.Equals, "wordpress")
+	c.Assert(event.Previous, gc.Equals, 1)
+	c.Assert(event.Desired, gc.Equals, 3)
+	c.Assert(event.Reason, gc.Equals, "load is high")
+	c.Assert(event.Requester, gc.Equals, "user-admin@local")
+
+	last, err := s.State.LastScaleEvent("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(last.Desired, gc.Equals, 3)
+	c.Assert(last.Time.Equal(event.Time), jc.IsTrue)
+
+	events, err := s.State.ScaleEvents("", before)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 1)
+	c.Assert(events[0].Reason, gc.Equals, "load is high")
+}
+
+func (s *ScaleEventsSuite) TestAddScaleEventNoReason(c *gc.C) {
+	_, err := s.State.AddScaleEvent("wordpress", 1, 3, "", names.NewLocalUserTag("admin"))
+	c.Assert(err, gc.ErrorMatches, `cannot record scaling of service "wordpress": empty reason`)
+}
+
+func (s *ScaleEventsSuite) TestScaleEvents(c *gc.C) {
+	before := time.Now().Add(-time.Second)
+	admin := names.NewLocalUserTag("admin")
+	_, err := s.State.AddScaleEvent("wordpress", 1, 3, "up", admin)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddScaleEvent("mysql", 1, 2, "replicas", admin)
+	c.Assert(err, jc.ErrorIsNil)
+	last, err := s.State.AddScaleEvent("wordpress", 3, 2, "down", admin)
+	c.Assert(err, jc.ErrorIsNil)
+
+	events, err := s.State.ScaleEvents("wordpress", before)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 2)
+	c.Assert(events[0].Reason, gc.Equals, "up")
+	c.Assert(events[1].Reason, gc.Equals, "down")
+
+	events, err = s.State.ScaleEvents("", before)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 3)
+
+	events, err = s.State.ScaleEvents("", last.Time)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 0)
+}
+
+func (s *ScaleEventsSuite) TestLastScaleEventNotFound(c *gc.C) {
+	_, err := s.State.LastScaleEvent("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `scale event of service "wordpress" not found`)
+}
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 )
 
 // Service represents the state of a service.
@@ -31,21 +32,25 @@ type Service struct {
 // serviceDoc represents the internal state of a service in MongoDB.
 // Note the correspondence with ServiceInfo in apiserver.
 type serviceDoc struct {
-	DocID             string     `bson:"_id"`
-	Name              string     `bson:"name"`
-	EnvUUID           string     `bson:"env-uuid"`
-	Series            string     `bson:"series"`
-	Subordinate       bool       `bson:"subordinate"`
-	CharmURL          *charm.URL `bson:"charmurl"`
-	ForceCharm        bool       `bson:forcecharm"`
-	Life              Life       `bson:"life"`
-	UnitCount         int        `bson:"unitcount"`
-	RelationCount     int        `bson:"relationcount"`
-	Exposed           bool       `bson:"exposed"`
-	MinUnits          int        `bson:"minunits"`
-	OwnerTag          string     `bson:"ownertag"`
-	TxnRevno          int64      `bson:"txn-revno"`
-	MetricCredentials []byte     `bson:"metric-credentials"`
+	DocID             string                     `bson:"_id"`
+	Name              string                     `bson:"name"`
+	EnvUUID           string                     `bson:"env-uuid"`
+	Series            string                     `bson:"series"`
+	Subordinate       bool                       `bson:"subordinate"`
+	CharmURL          *charm.URL                 `bson:"charmurl"`
+	ForceCharm        bool                       `bson:forcecharm"`
+	Life              Life                       `bson:"life"`
+	UnitCount         int                        `bson:"unitcount"`
+	RelationCount     int                        `bson:"relationcount"`
+	Exposed           bool                       `bson:"exposed"`
+	MinUnits          int                        `bson:"minunits"`
+	OwnerTag          string                     `bson:"ownertag"`
+	TxnRevno          int64                      `bson:"txn-revno"`
+	MetricCredentials []byte                     `bson:"metric-credentials"`
+	AddressPolicy     AddressPolicy              `bson:"address-policy,omitempty"`
+	ExposedEndpoints  map[string]ExposedEndpoint `bson:"exposed-endpoints,omitempty"`
+	ConfigRevision    int                        `bson:"configrevision,omitempty"`
+	UnmappedConfig    []UnmappedConfigSetting    `bson:"unmappedconfig,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -227,10 +232,11 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeRequestedNetworksOp(s.st, s.globalKey()),
 		removeStorageConstraintsOp(s.globalKey()),
 		removeConstraintsOp(s.st, s.globalKey()),
+		removeEndpointBindingsOp(s.globalKey()),
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
 	}
-	return ops
+	return append(ops, removeConfigRevisionsOps(s.st, s.doc.Name, s.doc.ConfigRevision)...)
 }
 
 // IsExposed returns whether this service is exposed. The explicitly open
@@ -240,14 +246,14 @@ func (s *Service) IsExposed() bool {
 	return s.doc.Exposed
 }
 
-// SetExposed marks the service as exposed.
-// See ClearExposed and IsExposed.
+// SetExposed marks the service as exposed, on all of its endpoints.
+// See ClearExposed, IsExposed and SetExposedEndpoints.
 func (s *Service) SetExposed() error {
 	return s.setExposed(true)
 }
 
-// ClearExposed removes the exposed flag from the service.
-// See SetExposed and IsExposed.
+// ClearExposed removes the exposed flag, and any exposed endpoints,
+// from the service. See SetExposed and IsExposed.
 func (s *Service) ClearExposed() error {
 	return s.setExposed(false)
 }
@@ -257,12 +263,16 @@ func (s *Service) setExposed(exposed bool) (err error) {
 		C:      servicesC,
 		Id:     s.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"exposed", exposed}}},
+			{"$unset", bson.D{{"exposed-endpoints", nil}}},
+		},
 	}}
 	if err := s.st.runTransaction(ops); err != nil {
 		return fmt.Errorf("cannot set exposed flag for service %q to %v: %v", s, exposed, onAbort(err, errNotAlive))
 	}
 	s.doc.Exposed = exposed
+	s.doc.ExposedEndpoints = nil
 	return nil
 }
 
@@ -373,19 +383,22 @@ func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op,
 }
 
 // changeCharmOps returns the operations necessary to set a service's
-// charm URL to a new value.
-func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
+// charm URL to a new value, and the service's settings that cannot be
+// carried over to the new charm.
+func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, []UnmappedConfigSetting, error) {
 	// Build the new service config from what can be used of the old one.
 	var newSettings charm.Settings
+	var unmapped []UnmappedConfigSetting
 	oldSettings, err := readSettings(s.st, s.settingsKey())
 	if err == nil {
-		// Filter the old settings through to get the new settings.
-		newSettings = ch.Config().FilterSettings(oldSettings.Map())
+		// Map the old settings through to get the new settings,
+		// noting those that cannot be carried over.
+		newSettings, unmapped = remapConfigSettings(ch, oldSettings.Map())
 	} else if errors.IsNotFound(err) {
 		// No old settings, start with empty new settings.
 		newSettings = make(charm.Settings)
 	} else {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	// Create or replace service settings.
@@ -395,26 +408,26 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		// No settings for this key yet, create it.
 		settingsOp = createSettingsOp(s.st, newKey, newSettings)
 	} else if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	} else {
 		// Settings exist, just replace them with the new ones.
 		settingsOp, _, err = replaceSettingsOp(s.st, newKey, newSettings)
 		if err != nil {
-			return nil, errors.Trace(err)
+			return nil, nil, errors.Trace(err)
 		}
 	}
 
 	// Add or create a reference to the new settings doc.
 	incOp, err := settingsIncRefOp(s.st, s.doc.Name, ch.URL(), true)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	var decOps []txn.Op
 	// Drop the reference to the old settings doc (if they exist).
 	if oldSettings != nil {
 		decOps, err = settingsDecRefOps(s.st, s.doc.Name, s.doc.CharmURL) // current charm
 		if err != nil {
-			return nil, errors.Trace(err)
+			return nil, nil, errors.Trace(err)
 		}
 	}
 
@@ -430,25 +443,30 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		settingsOp,
 		// Increment the ref count.
 		incOp,
-		// Update the charm URL and force flag (if relevant).
+		// Update the charm URL and force flag (if relevant), and
+		// record any settings that could not be carried over.
 		{
 			C:      servicesC,
 			Id:     s.doc.DocID,
 			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Update: bson.D{{"$set", bson.D{
+				{"charmurl", ch.URL()},
+				{"forcecharm", force},
+				{"unmappedconfig", unmapped},
+			}}},
 		},
 	}...)
 	// Add any extra peer relations that need creation.
 	newPeers := s.extraPeerRelations(ch.Meta())
 	peerOps, err := s.st.addPeerRelationsOps(s.doc.Name, newPeers)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	// Get all relations - we need to check them later.
 	relations, err := s.Relations()
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	// Make sure the relation count does not change.
 	sameRelCount := bson.D{{"relationcount", len(relations)}}
@@ -464,12 +482,12 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 	// Check relations to ensure no active relations are removed.
 	relOps, err := s.checkRelationsOps(ch, relations)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	ops = append(ops, relOps...)
 
 	// And finally, decrement the old settings.
-	return append(ops, decOps...), nil
+	return append(ops, decOps...), unmapped, nil
 }
 
 // SetCharm changes the charm for the service. New units will be started with
@@ -486,6 +504,7 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	services, closer := s.st.getCollection(servicesC)
 	defer closer()
 
+	var unmapped []UnmappedConfigSetting
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
 			// NOTE: We're explicitly allowing SetCharm to succeed
@@ -513,9 +532,10 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 				Assert: append(notDeadDoc, sameCharm...),
 				Update: bson.D{{"$set", bson.D{{"forcecharm", force}}}},
 			}}
+			unmapped = s.doc.UnmappedConfig
 		} else {
 			// Change the charm URL.
-			ops, err = s.changeCharmOps(ch, force)
+			ops, unmapped, err = s.changeCharmOps(ch, force)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
@@ -526,6 +546,7 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 	if err == nil {
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
+		s.doc.UnmappedConfig = unmapped
 	}
 	return err
 }
@@ -651,7 +672,7 @@ func (s *Service) addUnitOps(principalName string, asserts bson.D) (string, []tx
 		if err != nil {
 			return "", nil, err
 		}
-		cons, err := s.st.resolveConstraints(scons)
+		cons, err := s.st.resolveConstraints(resolver.Layers{Service: scons})
 		if err != nil {
 			return "", nil, err
 		}
@@ -748,6 +769,7 @@ func (s *Service) removeUnitOps(u *Unit, asserts bson.D) ([]txn.Op, error) {
 		removeStatusOp(s.st, u.globalKey()),
 		removeConstraintsOp(s.st, u.globalAgentKey()),
 		annotationRemoveOp(s.st, u.globalKey()),
+		removeUnitStateOp(u.globalKey()),
 		s.st.newCleanupOp(cleanupRemovedUnit, u.doc.Name),
 	)
 	ops = append(ops, portsOps...)
@@ -840,6 +862,14 @@ func (s *Service) ConfigSettings() (charm.Settings, error) {
 // UpdateConfigSettings changes a service's charm config settings. Values set
 // to nil will be deleted; unknown and invalid values will return an error.
 func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
+	return s.UpdateConfigSettingsBy(changes, nil)
+}
+
+// UpdateConfigSettingsBy changes a service's charm config settings like
+// UpdateConfigSettings, recording the given entity as having made the
+// change in the service's config history. Any change to the settings
+// increments the service's config revision.
+func (s *Service) UpdateConfigSettingsBy(changes charm.Settings, changedBy names.Tag) error {
 	charm, _, err := s.Charm()
 	if err != nil {
 		return err
@@ -848,23 +878,43 @@ func (s *Service) UpdateConfigSettings(changes charm.Settings) error {
 	if err != nil {
 		return err
 	}
-	// TODO(fwereade) state.Settings is itself really problematic in just
-	// about every use case. This needs to be resolved some time; but at
-	// least the settings docs are keyed by charm url as well as service
-	// name, so the actual impact of a race is non-threatening.
-	node, err := readSettings(s.st, s.settingsKey())
-	if err != nil {
+	var revision int
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		revision = 0
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		// TODO(fwereade) state.Settings is itself really problematic in just
+		// about every use case. This needs to be resolved some time; but at
+		// least the settings docs are keyed by charm url as well as service
+		// name, so the actual impact of a race is non-threatening.
+		node, err := readSettings(s.st, s.settingsKey())
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range changes {
+			if value == nil {
+				node.Delete(name)
+			} else {
+				node.Set(name, value)
+			}
+		}
+		itemChanges, ops := node.writeOps()
+		if len(itemChanges) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		revision = s.doc.ConfigRevision + 1
+		return append(ops, s.configRevisionOps(itemChanges, node.Map(), changedBy)...), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
 		return err
 	}
-	for name, value := range changes {
-		if value == nil {
-			node.Delete(name)
-		} else {
-			node.Set(name, value)
-		}
+	if revision > 0 {
+		s.doc.ConfigRevision = revision
 	}
-	_, err = node.Write()
-	return err
+	return nil
 }
 
 var ErrSubordinateConstraints = stderrors.New("constraints do not apply to subordinate services")
@@ -1095,3 +1145,15 @@ var statusServerities = map[Status]int{
 	StatusActive:      50,
 	StatusUnknown:     40,
 }
+
+// WorstStatus returns the most severe of the given statuses, by the
+// same measure used to derive a service's status from its units'.
+func WorstStatus(statuses ...Status) Status {
+	var worst Status
+	for _, status := range statuses {
+		if statusServerities[status] > statusServerities[worst] {
+			worst = status
+		}
+	}
+	return worst
+}
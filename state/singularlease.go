@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/lease"
+)
+
+// singularLeaseDoc records the holder of a singular lease. Leases are
+// stored in the state servers collection, which every state server
+// shares, so that a lease can only be held by one of them at a time.
+type singularLeaseDoc struct {
+	DocID  string    `bson:"_id"`
+	Holder string    `bson:"holder"`
+	Expiry time.Time `bson:"expiry"`
+}
+
+func singularLeaseKey(namespace string) string {
+	return "singularLease#" + namespace
+}
+
+// ClaimLease claims, or renews, the lease for the namespace on
+// behalf of holder for the given duration, and returns the holder of
+// the lease. If the lease is held by another holder and has not yet
+// expired, lease.LeaseClaimDeniedErr is returned.
+//
+// Each claim is made with a transaction that asserts the lease has not
+// changed since it was read, so concurrent claims from different state
+// servers cannot both succeed.
+func (st *State) ClaimLease(namespace, holder string, forDur time.Duration) (string, error) {
+	key := singularLeaseKey(namespace)
+	owner := holder
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		owner = holder
+		doc, err := st.singularLease(key)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		now := time.Now()
+		newDoc := singularLeaseDoc{
+			DocID:  key,
+			Holder: holder,
+			Expiry: now.Add(forDur),
+		}
+		if errors.IsNotFound(err) {
+			return []txn.Op{{
+				C:      stateServersC,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: &newDoc,
+			}}, nil
+		}
+		if doc.Holder != holder && now.Before(doc.Expiry) {
+			owner = doc.Holder
+			return nil, lease.LeaseClaimDeniedErr
+		}
+		return []txn.Op{{
+			C:  stateServersC,
+			Id: key,
+			Assert: bson.D{
+				{"holder", doc.Holder},
+				{"expiry", doc.Expiry},
+			},
+			Update: bson.D{{"$set", bson.D{
+				{"holder", newDoc.Holder},
+				{"expiry", newDoc.Expiry},
+			}}},
+		}}, nil
+	}
+	switch err := st.run(buildTxn); {
+	case err == lease.LeaseClaimDeniedErr:
+		return owner, err
+	case err == jujutxn.ErrExcessiveContention:
+		return "", errors.Errorf("cannot claim lease for %q: state changing too quickly; try again soon", namespace)
+	case err != nil:
+		return "", errors.Annotatef(err, "cannot claim lease for %q", namespace)
+	}
+	return owner, nil
+}
+
+func (st *State) singularLease(key string) (singularLeaseDoc, error) {
+	stateServers, closer := st.getCollection(stateServersC)
+	defer closer()
+
+	var doc singularLeaseDoc
+	err := stateServers.Find(bson.D{{"_id", key}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return doc, errors.NotFoundf("lease %q", key)
+	} else if err != nil {
+		return doc, errors.Annotatef(err, "cannot read lease %q", key)
+	}
+	return doc, nil
+}
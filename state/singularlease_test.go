@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+type singularLeaseSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&singularLeaseSuite{})
+
+func (s *singularLeaseSuite) TestClaimLease(c *gc.C) {
+	owner, err := s.State.ClaimLease("singular-env", "machine-0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner, gc.Equals, "machine-0")
+
+	// Renewing the lease succeeds.
+	owner, err = s.State.ClaimLease("singular-env", "machine-0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner, gc.Equals, "machine-0")
+}
+
+func (s *singularLeaseSuite) TestClaimLeaseSharedBetweenStates(c *gc.C) {
+	// Each state server has its own State; the lease must only be
+	// granted to one of them.
+	st0 := s.State
+	st1, err := s.State.ForEnviron(s.State.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer st1.Close()
+
+	owner, err := st0.ClaimLease("singular-env", "machine-0", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner, gc.Equals, "machine-0")
+
+	owner, err = st1.ClaimLease("singular-env", "machine-1", time.Minute)
+	c.Assert(err, gc.Equals, lease.LeaseClaimDeniedErr)
+	c.Assert(owner, gc.Equals, "machine-0")
+
+	// Other namespaces are independent.
+	owner, err = st1.ClaimLease("singular-other", "machine-1", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner, gc.Equals, "machine-1")
+}
+
+func (s *singularLeaseSuite) TestClaimLeaseAfterExpiry(c *gc.C) {
+	st1, err := s.State.ForEnviron(s.State.EnvironTag())
+	c.Assert(err, jc.ErrorIsNil)
+	defer st1.Close()
+
+	_, err = s.State.ClaimLease("singular-env", "machine-0", time.Millisecond)
+	c.Assert(err, jc.ErrorIsNil)
+	time.Sleep(10 * time.Millisecond)
+
+	owner, err := st1.ClaimLease("singular-env", "machine-1", time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(owner, gc.Equals, "machine-1")
+
+	// The previous holder cannot renew the lease once it has passed on.
+	owner, err = s.State.ClaimLease("singular-env", "machine-0", time.Minute)
+	c.Assert(err, gc.Equals, lease.LeaseClaimDeniedErr)
+	c.Assert(owner, gc.Equals, "machine-1")
+}
+
+func (s *singularLeaseSuite) TestClaimLeaseConcurrent(c *gc.C) {
+	defer state.SetBeforeHooks(c, s.State, func() {
+		owner, err := s.State.ClaimLease("singular-env", "machine-1", time.Minute)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(owner, gc.Equals, "machine-1")
+	}).Check()
+
+	owner, err := s.State.ClaimLease("singular-env", "machine-0", time.Minute)
+	c.Assert(err, gc.Equals, lease.LeaseClaimDeniedErr)
+	c.Assert(owner, gc.Equals, "machine-1")
+}
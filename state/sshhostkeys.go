@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// SSHHostKeys holds the public SSH host keys of a machine, each in
+// the format of a line of an OpenSSH public key file.
+type SSHHostKeys []string
+
+// sshHostKeysDoc records the SSH host keys reported by a machine.
+type sshHostKeysDoc struct {
+	DocID   string   `bson:"_id"`
+	EnvUUID string   `bson:"env-uuid"`
+	Keys    []string `bson:"keys"`
+}
+
+// GetSSHHostKeys returns the SSH host keys recorded for the machine
+// with the given tag. An error satisfying errors.IsNotFound is returned
+// if the machine has not reported any keys.
+func (st *State) GetSSHHostKeys(tag names.MachineTag) (SSHHostKeys, error) {
+	coll, closer := st.getCollection(machineHostKeysC)
+	defer closer()
+
+	var doc sshHostKeysDoc
+	err := coll.FindId(machineGlobalKey(tag.Id())).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("SSH host keys for %s", names.ReadableString(tag))
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get SSH host keys")
+	}
+	return SSHHostKeys(doc.Keys), nil
+}
+
+// SetSSHHostKeys records the SSH host keys of the machine with the
+// given tag, replacing any keys recorded previously.
+func (st *State) SetSSHHostKeys(tag names.MachineTag, keys SSHHostKeys) error {
+	coll, closer := st.getCollection(machineHostKeysC)
+	defer closer()
+
+	id := st.docID(machineGlobalKey(tag.Id()))
+	buildTxn := func(int) ([]txn.Op, error) {
+		machine, err := st.Machine(tag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if machine.Life() == Dead {
+			return nil, errors.Errorf("machine %s is dead", tag.Id())
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     st.docID(tag.Id()),
+			Assert: notDeadDoc,
+		}}
+		count, err := coll.FindId(id).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count == 0 {
+			return append(ops, txn.Op{
+				C:      machineHostKeysC,
+				Id:     id,
+				Assert: txn.DocMissing,
+				Insert: &sshHostKeysDoc{Keys: keys},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      machineHostKeysC,
+			Id:     id,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"keys", keys}}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot set SSH host keys")
+	}
+	return nil
+}
+
+func removeSSHHostKeysOp(st *State, globalKey string) txn.Op {
+	return txn.Op{
+		C:      machineHostKeysC,
+		Id:     st.docID(globalKey),
+		Remove: true,
+	}
+}
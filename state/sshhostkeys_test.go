@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type sshHostKeysSuite struct {
+	ConnSuite
+	machine *state.Machine
+}
+
+var _ = gc.Suite(&sshHostKeysSuite{})
+
+func (s *sshHostKeysSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.factory.MakeMachine(c, nil)
+}
+
+func (s *sshHostKeysSuite) TestGetWithNoKeys(c *gc.C) {
+	_, err := s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `SSH host keys for machine `+s.machine.Id()+` not found`)
+}
+
+func (s *sshHostKeysSuite) TestSetGet(c *gc.C) {
+	tag := s.machine.MachineTag()
+	for i := 0; i < 3; i++ {
+		keys := state.SSHHostKeys{"rsa foo", "dsa bar"}
+		for j := 0; j < i; j++ {
+			keys = append(keys, "ecdsa baz")
+		}
+		err := s.State.SetSSHHostKeys(tag, keys)
+		c.Assert(err, jc.ErrorIsNil)
+		keysGot, err := s.State.GetSSHHostKeys(tag)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(keysGot, gc.DeepEquals, keys)
+	}
+}
+
+func (s *sshHostKeysSuite) TestKeysIsolatedPerMachine(c *gc.C) {
+	other := s.factory.MakeMachine(c, nil)
+	err := s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSSHHostKeys(other.MachineTag(), state.SSHHostKeys{"rsa bar"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	keys, err := s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.DeepEquals, state.SSHHostKeys{"rsa foo"})
+	keys, err = s.State.GetSSHHostKeys(other.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.DeepEquals, state.SSHHostKeys{"rsa bar"})
+}
+
+func (s *sshHostKeysSuite) TestSetUnknownMachine(c *gc.C) {
+	err := s.State.SetSSHHostKeys(names.NewMachineTag("42"), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, gc.ErrorMatches, "cannot set SSH host keys: machine 42 not found")
+}
+
+func (s *sshHostKeysSuite) TestSetDeadMachine(c *gc.C) {
+	err := s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, gc.ErrorMatches, "cannot set SSH host keys: machine .* is dead")
+}
+
+func (s *sshHostKeysSuite) TestRemovedWithMachine(c *gc.C) {
+	err := s.State.SetSSHHostKeys(s.machine.MachineTag(), state.SSHHostKeys{"rsa foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetSSHHostKeys(s.machine.MachineTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/juju/names"
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils"
+	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -71,6 +72,7 @@ const (
 	envUsersC              = "envusers"
 	presenceC              = "presence"
 	cleanupsC              = "cleanups"
+	cleanupAuditC          = "cleanupaudit"
 	annotationsC           = "annotations"
 	statusesC              = "statuses"
 	statusesHistoryC       = "statuseshistory"
@@ -114,6 +116,85 @@ const (
 	// blocksC is used to identify collection of environment blocks.
 	blocksC = "blocks"
 
+	// machineHostKeysC holds the SSH host keys reported by machines.
+	machineHostKeysC = "machinehostkeys"
+
+	// machineUpgradeSeriesLocksC records the progress of in-place
+	// series upgrades of machines.
+	machineUpgradeSeriesLocksC = "machineUpgradeSeriesLocks"
+
+	// actionOutputC holds the output written by actions while they
+	// run.
+	actionOutputC = "actionoutput"
+
+	// agentRolloutsC records the progress of staged agent upgrades.
+	agentRolloutsC = "agentrollouts"
+
+	// envInvitationsC holds invitations to access environments that
+	// have not yet been accepted.
+	envInvitationsC = "envinvitations"
+
+	// endpointBindingsC holds the spaces to which the endpoints of
+	// services are bound.
+	endpointBindingsC = "endpointbindings"
+
+	// relationSettingsSnapshotsC holds the settings of related units
+	// as they were when a unit began to depart a relation.
+	relationSettingsSnapshotsC = "relationSettingsSnapshots"
+
+	// relationServiceSettingsC holds the settings that each service
+	// in a relation shares with the other, as distinct from those of
+	// its units.
+	relationServiceSettingsC = "relationservicesettings"
+
+	// unitStatesC holds the operation state recorded by the uniters
+	// of units, so that a replaced agent can resume where it left off.
+	unitStatesC = "unitstates"
+
+	// instanceTypeMappingsC caches the instance types to which the
+	// provider normalizes constraints.
+	instanceTypeMappingsC = "instancetypemappings"
+
+	// cloudCredentialsC holds the provider credentials used by
+	// environments. It is not environment specific.
+	cloudCredentialsC = "cloudcredentials"
+
+	// cloudsC holds the clouds that environments may be hosted in.
+	// It is not environment specific.
+	cloudsC = "clouds"
+
+	// configRevisionsC holds a bounded history of the changes made
+	// to the configuration settings of services.
+	configRevisionsC = "configrevisions"
+
+	// webhooksC holds the URLs to which notifications of environment
+	// events are posted.
+	webhooksC = "webhooks"
+
+	// idempotencyTokensC records the idempotency tokens of recent
+	// API calls, so that retried calls are not made twice.
+	idempotencyTokensC = "idempotencytokens"
+
+	// userSessionsC holds the sessions that let users log in with a
+	// token in place of their password. It is not environment
+	// specific, as users are not.
+	userSessionsC = "usersessions"
+
+	// scaleEventsC records the changes made to the number of units
+	// of services, with the reasons given for them.
+	scaleEventsC = "scaleevents"
+
+	// unitUtilizationC is a capped collection holding samples of the
+	// resources used by the processes of units. It is not in
+	// multiEnvCollections because documents cannot be removed from a
+	// capped collection; old samples simply age out.
+	unitUtilizationC = "unitutilization"
+
+	// apiUsageC holds hourly totals of the API calls made to each
+	// environment by its clients and agents. It is not environment
+	// specific.
+	apiUsageC = "apiusage"
+
 	// The following mongo collections are used as unique key restraints. The
 	// _id field of each collection is a concatenation of multiple fields
 	// that form a compound index.
@@ -135,11 +216,12 @@ type State struct {
 	db                *mgo.Database
 	watcher           *watcher.Watcher
 	pwatcher          *presence.Watcher
-	// mu guards allManager.
-	mu         sync.Mutex
-	allManager *storeManager
-	environTag names.EnvironTag
-	serverTag  names.EnvironTag
+	// mu guards allManager and sessionConfig.
+	mu            sync.Mutex
+	allManager    *storeManager
+	sessionConfig mongo.SessionConfig
+	environTag    names.EnvironTag
+	serverTag     names.EnvironTag
 }
 
 // StateServingInfo holds information needed by a state server.
@@ -214,6 +296,7 @@ func (st *State) ForEnviron(env names.EnvironTag) (*State, error) {
 	}
 	newState.environTag = env
 	newState.serverTag = st.serverTag
+	newState.applyMongoSessionConfig(st.MongoSessionConfig())
 	newState.startPresenceWatcher()
 	return newState, nil
 }
@@ -320,6 +403,15 @@ func (st *State) EnvironConfig() (*config.Config, error) {
 		return nil, errors.Trace(err)
 	}
 	attrs := settings.Map()
+	cred, err := st.environCloudCredential()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if cred != nil {
+		for k, v := range cred.doc.Attributes {
+			attrs[k] = v
+		}
+	}
 	return config.New(config.NoDefaults, attrs)
 }
 
@@ -442,6 +534,16 @@ func (st *State) SetEnvironAgentVersion(newVersion version.Number) (err error) {
 		if err := st.checkCanUpgrade(currentVersion, newVersion.String()); err != nil {
 			return nil, errors.Trace(err)
 		}
+		if rollout, err := st.AgentRollout(); err == nil {
+			if rollout.InProgress() && newVersion != rollout.TargetVersion() {
+				return nil, errors.Errorf(
+					"a staged upgrade to %s is in progress; promote or roll it back first",
+					rollout.TargetVersion(),
+				)
+			}
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
 
 		ops := []txn.Op{
 			// Can't set agent-version if there's an active upgradeInfo doc.
@@ -509,8 +611,30 @@ func (st *State) UpdateEnvironConfig(updateAttrs map[string]interface{}, removeA
 		return errors.Trace(err)
 	}
 
+	// The credential attributes of an environment that uses a cloud
+	// credential are held in the credential, and must be changed there.
+	cred, err := st.environCloudCredential()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	oldAttrs := settings.Map()
+	if cred != nil {
+		changed := set.NewStrings(removeAttrs...)
+		for attr := range updateAttrs {
+			changed.Add(attr)
+		}
+		for _, attr := range changed.SortedValues() {
+			if _, ok := cred.doc.Attributes[attr]; ok {
+				return errors.Errorf("cannot change %q: it is held by cloud credential %q", attr, cred.Name())
+			}
+		}
+		for k, v := range cred.doc.Attributes {
+			oldAttrs[k] = v
+		}
+	}
+
 	// Get the existing environment config from state.
-	oldConfig, err := config.New(config.NoDefaults, settings.Map())
+	oldConfig, err := config.New(config.NoDefaults, oldAttrs)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -520,12 +644,22 @@ func (st *State) UpdateEnvironConfig(updateAttrs map[string]interface{}, removeA
 			return errors.Trace(err)
 		}
 	}
+	// Check the new values against the configuration schema first,
+	// so that invalid values are reported precisely.
+	if err := config.SchemaFor(oldConfig.Type()).ValidateAttrs(updateAttrs); err != nil {
+		return errors.Trace(err)
+	}
 	validCfg, err := st.buildAndValidateEnvironConfig(updateAttrs, removeAttrs, oldConfig)
 	if err != nil {
 		return errors.Trace(err)
 	}
 
 	validAttrs := validCfg.AllAttrs()
+	if cred != nil {
+		for k := range cred.doc.Attributes {
+			delete(validAttrs, k)
+		}
+	}
 	for k := range oldConfig.AllAttrs() {
 		if _, ok := validAttrs[k]; !ok {
 			settings.Delete(k)
@@ -789,16 +923,21 @@ func (st *State) AddCharm(ch charm.Charm, curl *charm.URL, storagePath, bundleSh
 
 	err = charms.Find(bson.D{{"_id", curl.String()}, {"placeholder", true}}).One(&existing)
 	if err == mgo.ErrNotFound {
+		renames, err := readCharmConfigRenames(ch)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot add charm %q", curl)
+		}
 		cdoc := &charmDoc{
-			DocID:        st.docID(curl.String()),
-			URL:          curl,
-			EnvUUID:      st.EnvironTag().Id(),
-			Meta:         ch.Meta(),
-			Config:       ch.Config(),
-			Metrics:      ch.Metrics(),
-			Actions:      ch.Actions(),
-			BundleSha256: bundleSha256,
-			StoragePath:  storagePath,
+			DocID:         st.docID(curl.String()),
+			URL:           curl,
+			EnvUUID:       st.EnvironTag().Id(),
+			Meta:          ch.Meta(),
+			Config:        ch.Config(),
+			Metrics:       ch.Metrics(),
+			Actions:       ch.Actions(),
+			ConfigRenames: renames,
+			BundleSha256:  bundleSha256,
+			StoragePath:   storagePath,
 		}
 		err = charms.Insert(cdoc)
 		if err != nil {
@@ -1175,11 +1314,16 @@ func (st *State) updateCharmDoc(
 		escapedName := escapeReplacer.Replace(optionName)
 		escapedConfig.Options[escapedName] = option
 	}
+	renames, err := readCharmConfigRenames(ch)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot update charm %q", curl)
+	}
 	updateFields := bson.D{{"$set", bson.D{
 		{"meta", ch.Meta()},
 		{"config", escapedConfig},
 		{"actions", ch.Actions()},
 		{"metrics", ch.Metrics()},
+		{"configrenames", renames},
 		{"storagepath", storagePath},
 		{"bundlesha256", bundleSha256},
 		{"pendingupload", false},
@@ -1445,6 +1589,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		AllocatableIPHigh: args.AllocatableIPHigh,
 		AllocatableIPLow:  args.AllocatableIPLow,
 		AvailabilityZone:  args.AvailabilityZone,
+		SpaceName:         args.SpaceName,
 	}
 	subnet = &Subnet{doc: subDoc, st: st}
 	err = subnet.Validate()
@@ -1976,34 +2121,18 @@ func (st *State) UnitsFor(machineId string) ([]*Unit, error) {
 
 // AssignUnit places the unit on a machine. Depending on the policy, and the
 // state of the environment, this may lead to new instances being launched
-// within the environment.
+// within the environment. The policy selects a strategy registered with
+// RegisterAssignmentStrategy.
 func (st *State) AssignUnit(u *Unit, policy AssignmentPolicy) (err error) {
 	if !u.IsPrincipal() {
 		return errors.Errorf("subordinate unit %q cannot be assigned directly to a machine", u)
 	}
 	defer errors.DeferredAnnotatef(&err, "cannot assign unit %q to machine", u)
-	var m *Machine
-	switch policy {
-	case AssignLocal:
-		m, err = st.Machine("0")
-		if err != nil {
-			return errors.Trace(err)
-		}
-		return u.AssignToMachine(m)
-	case AssignClean:
-		if _, err = u.AssignToCleanMachine(); err != noCleanMachines {
-			return errors.Trace(err)
-		}
-		return u.AssignToNewMachineOrContainer()
-	case AssignCleanEmpty:
-		if _, err = u.AssignToCleanEmptyMachine(); err != noCleanMachines {
-			return errors.Trace(err)
-		}
-		return u.AssignToNewMachineOrContainer()
-	case AssignNew:
-		return errors.Trace(u.AssignToNewMachine())
+	strategy, ok := assignmentStrategy(policy)
+	if !ok {
+		return errors.Errorf("unknown unit assignment policy: %q", policy)
 	}
-	return errors.Errorf("unknown unit assignment policy: %q", policy)
+	return strategy.AssignUnit(st, u)
 }
 
 // StartSync forces watchers to resynchronize their state with the
@@ -1324,6 +1324,65 @@ func (s *StateSuite) TestAddContainerToExistingMachine(c *gc.C) {
 	s.assertMachineContainers(c, m1, []string{"1/lxc/0", "1/lxc/1"})
 }
 
+func (s *StateSuite) TestAddContainerToNewContainer(c *gc.C) {
+	oneJob := []state.MachineJob{state.JobHostUnits}
+	host, err := s.State.AddMachine("quantal", oneJob...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   oneJob,
+	}
+	parentTemplate := state.MachineTemplate{
+		Series: "trusty",
+		Jobs:   oneJob,
+	}
+	m, err := s.State.AddMachineInsideNewContainer(template, parentTemplate, "0", instance.KVM, instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "0/kvm/0/lxc/0")
+	c.Assert(m.Series(), gc.Equals, "quantal")
+	c.Assert(m.ContainerType(), gc.Equals, instance.LXC)
+	c.Assert(m.Jobs(), gc.DeepEquals, oneJob)
+	s.assertMachineContainers(c, host, []string{"0/kvm/0"})
+
+	parent, err := s.State.Machine("0/kvm/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parent.Series(), gc.Equals, "trusty")
+	c.Assert(parent.ContainerType(), gc.Equals, instance.KVM)
+	s.assertMachineContainers(c, parent, []string{"0/kvm/0/lxc/0"})
+
+	m, err = s.State.Machine("0/kvm/0/lxc/0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertMachineContainers(c, m, nil)
+}
+
+func (s *StateSuite) TestAddContainerToNewContainerInvalidNesting(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideNewContainer(template, template, "0", instance.LXC, instance.LXC)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: cannot create lxc containers inside lxc containers")
+}
+
+func (s *StateSuite) TestAddContainerToNewContainerHostUnsupported(c *gc.C) {
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = host.SetSupportedContainers([]instance.ContainerType{instance.LXC})
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachineInsideNewContainer(template, template, "0", instance.KVM, instance.LXC)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: machine 0 cannot host kvm containers")
+	s.assertMachineContainers(c, host, nil)
+}
+
 func (s *StateSuite) TestAddContainerToMachineWithKnownSupportedContainers(c *gc.C) {
 	oneJob := []state.MachineJob{state.JobHostUnits}
 	host, err := s.State.AddMachine("quantal", oneJob...)
@@ -3004,6 +3063,67 @@ func testSetAgentCompatPassword(c *gc.C, entity state.Authenticator) {
 	c.Assert(entity.PasswordValid("short"), jc.IsTrue)
 }
 
+type passwordRotatingEntity interface {
+	state.Authenticator
+	state.PasswordRotator
+}
+
+func testRotatePassword(c *gc.C, getEntity func() (passwordRotatingEntity, error)) {
+	e, err := getEntity()
+	c.Assert(err, jc.ErrorIsNil)
+	err = e.SetPassword(goodPassword)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both the current and next passwords are valid until the next
+	// password is confirmed.
+	err = e.SetNextPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e.PasswordValid(goodPassword), jc.IsTrue)
+	c.Assert(e.PasswordValid(alternatePassword), jc.IsTrue)
+
+	e2, err := getEntity()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e2.PasswordValid(goodPassword), jc.IsTrue)
+	c.Assert(e2.PasswordValid(alternatePassword), jc.IsTrue)
+
+	// Only the next password can be confirmed.
+	err = e.ConfirmPassword("arble-farble-dying-yarble")
+	c.Assert(err, gc.ErrorMatches, "cannot confirm password of .*: password does not match")
+
+	err = e.ConfirmPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e.PasswordValid(goodPassword), jc.IsFalse)
+	c.Assert(e.PasswordValid(alternatePassword), jc.IsTrue)
+
+	err = e2.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e2.PasswordValid(goodPassword), jc.IsFalse)
+	c.Assert(e2.PasswordValid(alternatePassword), jc.IsTrue)
+
+	// Confirming the current password again is a no-op.
+	err = e.ConfirmPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Setting the password directly discards any next password.
+	err = e.SetNextPassword(goodPassword)
+	c.Assert(err, jc.ErrorIsNil)
+	err = e.SetPassword(alternatePassword)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(e.PasswordValid(goodPassword), jc.IsFalse)
+	err = e.ConfirmPassword(goodPassword)
+	c.Assert(err, gc.ErrorMatches, "cannot confirm password of .*: password does not match")
+
+	// Agents are unable to set short passwords.
+	err = e.SetNextPassword("short")
+	c.Assert(err, gc.ErrorMatches, "password is only 5 bytes long, and is not a valid Agent password")
+
+	if le, ok := e.(lifer); ok {
+		testWhenDying(c, le, noErr, deadErr, func() error {
+			return e.SetNextPassword("arble-farble-dying-yarble")
+		})
+	}
+}
+
 type entity interface {
 	state.Entity
 	state.Lifer
@@ -4271,6 +4391,63 @@ func (s *StateSuite) TestWatchAPIHostPorts(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *StateSuite) TestSetPublicAPIHostPorts(c *gc.C) {
+	serverHostPorts := [][]network.HostPort{
+		network.NewHostPorts(17070, "10.0.0.1"),
+		network.NewHostPorts(17070, "10.0.0.2"),
+	}
+	err := s.State.SetAPIHostPorts(serverHostPorts)
+	c.Assert(err, jc.ErrorIsNil)
+
+	public := network.NewHostPorts(443, "api.example.com")
+	err = s.State.SetPublicAPIHostPorts(public)
+	c.Assert(err, jc.ErrorIsNil)
+	gotPublic, err := s.State.PublicAPIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotPublic, jc.DeepEquals, public)
+	gotHostPorts, err := s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotHostPorts, jc.DeepEquals, [][]network.HostPort{public})
+
+	// The servers' own addresses can still be updated underneath.
+	serverHostPorts = [][]network.HostPort{network.NewHostPorts(17070, "10.0.0.3")}
+	err = s.State.SetAPIHostPorts(serverHostPorts)
+	c.Assert(err, jc.ErrorIsNil)
+	gotHostPorts, err = s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotHostPorts, jc.DeepEquals, [][]network.HostPort{public})
+
+	// Clearing the override restores the servers' addresses.
+	err = s.State.SetPublicAPIHostPorts(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	gotPublic, err = s.State.PublicAPIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotPublic, gc.HasLen, 0)
+	gotHostPorts, err = s.State.APIHostPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(gotHostPorts, jc.DeepEquals, serverHostPorts)
+}
+
+func (s *StateSuite) TestSetPublicAPIHostPortsInvalid(c *gc.C) {
+	err := s.State.SetPublicAPIHostPorts(network.NewHostPorts(0, "api.example.com"))
+	c.Assert(err, gc.ErrorMatches, `API address "api.example.com:0" not valid`)
+}
+
+func (s *StateSuite) TestWatchAPIHostPortsPublic(c *gc.C) {
+	w := s.State.WatchAPIHostPorts()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.State.SetPublicAPIHostPorts(network.NewHostPorts(443, "api.example.com"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.State.SetPublicAPIHostPorts(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
 func (s *StateSuite) TestWatchMachineAddresses(c *gc.C) {
 	// Add a machine: reported.
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
@@ -51,6 +51,9 @@ const (
 	// The machine ought to be signalling activity, but it cannot be
 	// detected.
 	StatusDown Status = "down"
+
+	// The machine's instance can no longer be found in the provider.
+	StatusInstanceMissing Status = "instance-missing"
 )
 
 const (
@@ -156,7 +159,8 @@ func (status Status) ValidAgentStatus() bool {
 		StatusFailed,
 		StatusRebooting,
 		StatusExecuting,
-		StatusIdle:
+		StatusIdle,
+		StatusInstanceMissing:
 		return true
 	case //Deprecated status vales
 		StatusPending,
@@ -351,7 +355,8 @@ func machineStatusValid(status Status) bool {
 		StatusStarted,
 		StatusStopped,
 		StatusError,
-		StatusDown:
+		StatusDown,
+		StatusInstanceMissing:
 		return true
 	default:
 		return false
@@ -24,7 +24,8 @@ import (
 )
 
 var ErrPersistentVolumesExist = errors.New(`
-Environment cannot be destroyed until all persistent volumes have been destroyed.
+Environment cannot be destroyed until all persistent volumes have been destroyed,
+unless you choose to destroy or release them along with the environment.
 Run "juju storage list" to display persistent storage volumes.
 `[1:])
 
@@ -6,6 +6,7 @@ package state
 import (
 	"math/rand"
 	"net"
+	"regexp"
 
 	"github.com/juju/errors"
 	"gopkg.in/mgo.v2"
@@ -15,6 +16,10 @@ import (
 	"github.com/juju/juju/network"
 )
 
+// validSpaceName matches the names of spaces, which are lower case
+// words separated by hyphens.
+var validSpaceName = regexp.MustCompile("^[a-z0-9]+(-[a-z0-9]+)*$")
+
 // SubnetInfo describes a single subnet.
 type SubnetInfo struct {
 	// ProviderId is a provider-specific network id. This may be empty.
@@ -38,6 +43,10 @@ type SubnetInfo struct {
 	// AvailabilityZone describes which availability zone this subnet is in. It can
 	// be empty if the provider does not support availability zones.
 	AvailabilityZone string
+
+	// SpaceName is the name of the space the subnet belongs to. It may
+	// be empty if the subnet is not part of any space.
+	SpaceName string
 }
 
 type Subnet struct {
@@ -55,6 +64,7 @@ type subnetDoc struct {
 	AllocatableIPLow  string `bson:"allocatableiplow,omitempty"`
 	VLANTag           int    `bson:"vlantag,omitempty"`
 	AvailabilityZone  string `bson:"availabilityzone,omitempty"`
+	SpaceName         string `bson:"spacename,omitempty"`
 }
 
 // Life returns whether the subnet is Alive, Dying or Dead.
@@ -168,8 +178,14 @@ func (s *Subnet) AvailabilityZone() string {
 	return s.doc.AvailabilityZone
 }
 
-// Validate validates the subnet, checking the CIDR, VLANTag and
-// AllocatableIPHigh and Low, if present.
+// SpaceName returns the name of the space the subnet belongs to. If the
+// subnet is not part of any space it will be the empty string.
+func (s *Subnet) SpaceName() string {
+	return s.doc.SpaceName
+}
+
+// Validate validates the subnet, checking the CIDR, VLANTag, SpaceName
+// and AllocatableIPHigh and Low, if present.
 func (s *Subnet) Validate() error {
 	var mask *net.IPNet
 	var err error
@@ -184,6 +200,9 @@ func (s *Subnet) Validate() error {
 	if s.doc.VLANTag < 0 || s.doc.VLANTag > 4094 {
 		return errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", s.doc.VLANTag)
 	}
+	if s.doc.SpaceName != "" && !validSpaceName.MatchString(s.doc.SpaceName) {
+		return errors.Errorf("invalid space name %q", s.doc.SpaceName)
+	}
 	present := func(str string) bool {
 		return str != ""
 	}
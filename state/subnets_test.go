@@ -30,6 +30,7 @@ func (s *SubnetSuite) TestAddSubnet(c *gc.C) {
 		AllocatableIPLow:  "192.168.1.0",
 		AllocatableIPHigh: "192.168.1.1",
 		AvailabilityZone:  "Timbuktu",
+		SpaceName:         "dmz",
 	}
 
 	assertSubnet := func(subnet *state.Subnet) {
@@ -39,6 +40,7 @@ func (s *SubnetSuite) TestAddSubnet(c *gc.C) {
 		c.Assert(subnet.AllocatableIPLow(), gc.Equals, "192.168.1.0")
 		c.Assert(subnet.AllocatableIPHigh(), gc.Equals, "192.168.1.1")
 		c.Assert(subnet.AvailabilityZone(), gc.Equals, "Timbuktu")
+		c.Assert(subnet.SpaceName(), gc.Equals, "dmz")
 		c.Assert(subnet.String(), gc.Equals, "192.168.1.0/24")
 		c.Assert(subnet.GoString(), gc.Equals, "192.168.1.0/24")
 	}
@@ -72,6 +74,12 @@ func (s *SubnetSuite) TestAddSubnetErrors(c *gc.C) {
 		errPrefix+"invalid VLAN tag 4095: must be between 0 and 4094",
 	)
 
+	subnetInfo.VLANTag = 0
+	subnetInfo.SpaceName = "Not A Space"
+	_, err = s.State.AddSubnet(subnetInfo)
+	c.Assert(err, gc.ErrorMatches, errPrefix+`invalid space name "Not A Space"`)
+	subnetInfo.SpaceName = ""
+
 	eitherOrMsg := errPrefix + "either both AllocatableIPLow and AllocatableIPHigh must be set or neither set"
 	subnetInfo.VLANTag = 0
 	subnetInfo.AllocatableIPHigh = "192.168.0.1"
@@ -0,0 +1,179 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// The states recorded in the "s" field of mgo/txn transaction
+// documents.
+const (
+	txnPreparing = 1
+	txnPrepared  = 2
+	txnAborting  = 3
+	txnApplying  = 4
+	txnAborted   = 5
+	txnApplied   = 6
+)
+
+// txnStashC holds the documents that mgo/txn uses to track
+// transactions on documents that have been removed, or that are about
+// to be inserted.
+const txnStashC = txnsC + ".stash"
+
+// txnPruneBatchSize is the number of transactions removed at a time.
+const txnPruneBatchSize = 1000
+
+// PruneTransactions removes completed transactions that started before
+// minTxnTime from the txns collection, along with any txns.stash
+// documents that no transaction refers to. Transactions that are still
+// named in the txn-queue of any document are kept, whatever their age,
+// because mgo/txn may need them to resolve that queue.
+func PruneTransactions(st *State, minTxnTime time.Time) error {
+	session := st.db.Session.Copy()
+	defer session.Close()
+	db := st.db.With(session)
+
+	referenced, err := referencedTxnIds(db)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	txns := db.C(txnsC)
+	iter := txns.Find(bson.D{
+		{"_id", bson.D{{"$lt", bson.NewObjectIdWithTime(minTxnTime)}}},
+		{"s", bson.D{{"$in", []int{txnAborted, txnApplied}}}},
+	}).Select(bson.D{{"_id", 1}}).Iter()
+	var doc struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	var batch []bson.ObjectId
+	removed := 0
+	removeBatch := func() error {
+		info, err := txns.RemoveAll(bson.D{{"_id", bson.D{{"$in", batch}}}})
+		if err != nil {
+			return errors.Annotate(err, "cannot remove transactions")
+		}
+		removed += info.Removed
+		batch = batch[:0]
+		return nil
+	}
+	for iter.Next(&doc) {
+		if referenced[doc.Id] {
+			continue
+		}
+		batch = append(batch, doc.Id)
+		if len(batch) == txnPruneBatchSize {
+			if err := removeBatch(); err != nil {
+				iter.Close()
+				return errors.Trace(err)
+			}
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Annotate(err, "cannot read transactions")
+	}
+	if len(batch) > 0 {
+		if err := removeBatch(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	info, err := db.C(txnStashC).RemoveAll(bson.D{{"txn-queue", bson.D{{"$size", 0}}}})
+	if err != nil {
+		return errors.Annotate(err, "cannot remove unused transaction stash documents")
+	}
+	logger.Debugf("pruned %d transactions and %d stash documents", removed, info.Removed)
+	return nil
+}
+
+// referencedTxnIds returns the ids of all transactions named in the
+// txn-queue fields of documents in the database.
+func referencedTxnIds(db *mgo.Database) (map[bson.ObjectId]bool, error) {
+	names, err := db.CollectionNames()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list collections")
+	}
+	referenced := make(map[bson.ObjectId]bool)
+	for _, name := range names {
+		if strings.HasPrefix(name, "system.") || name == txnsC || name == txnLogC {
+			continue
+		}
+		iter := db.C(name).Find(bson.D{
+			{"txn-queue.0", bson.D{{"$exists", true}}},
+		}).Select(bson.D{{"txn-queue", 1}}).Iter()
+		var doc struct {
+			Queue []string `bson:"txn-queue"`
+		}
+		for iter.Next(&doc) {
+			for _, token := range doc.Queue {
+				// Tokens are of the form <txn id>_<nonce>.
+				if id := strings.SplitN(token, "_", 2)[0]; bson.IsObjectIdHex(id) {
+					referenced[bson.ObjectIdHex(id)] = true
+				}
+			}
+		}
+		if err := iter.Close(); err != nil {
+			return nil, errors.Annotatef(err, "cannot read transaction queues in %s", name)
+		}
+	}
+	return referenced, nil
+}
+
+// PendingTransaction describes a transaction that has been neither
+// applied nor aborted.
+type PendingTransaction struct {
+	// Id holds the transaction's id, in hex.
+	Id string
+
+	// Started holds the time at which the transaction was created.
+	Started time.Time
+}
+
+// PendingTransactions returns the transactions that have been neither
+// applied nor aborted, oldest first.
+func (st *State) PendingTransactions() ([]PendingTransaction, error) {
+	session := st.db.Session.Copy()
+	defer session.Close()
+
+	var docs []struct {
+		Id bson.ObjectId `bson:"_id"`
+	}
+	err := st.db.With(session).C(txnsC).Find(bson.D{
+		{"s", bson.D{{"$in", []int{txnPreparing, txnPrepared, txnAborting, txnApplying}}}},
+	}).Select(bson.D{{"_id", 1}}).Sort("_id").All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read pending transactions")
+	}
+	pending := make([]PendingTransaction, len(docs))
+	for i, doc := range docs {
+		pending[i] = PendingTransaction{
+			Id:      doc.Id.Hex(),
+			Started: doc.Id.Time(),
+		}
+	}
+	return pending, nil
+}
+
+// ResumeTransaction resumes the pending transaction with the given id,
+// taking it through to completion.
+func (st *State) ResumeTransaction(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.NotValidf("transaction id %q", id)
+	}
+	session := st.db.Session.Copy()
+	defer session.Close()
+	runner := txn.NewRunner(st.db.With(session).C(txnsC))
+	if err := runner.Resume(bson.ObjectIdHex(id)); err != nil {
+		return errors.Annotatef(err, "cannot resume transaction %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type TxnPruneSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&TxnPruneSuite{})
+
+func (s *TxnPruneSuite) countTxns(c *gc.C) int {
+	txns, closer := state.GetRawCollection(s.State, "txns")
+	defer closer()
+	n, err := txns.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	return n
+}
+
+func (s *TxnPruneSuite) TestPruneKeepsRecentTransactions(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	before := s.countTxns(c)
+
+	err = state.PruneTransactions(s.State, time.Now().Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.countTxns(c), gc.Equals, before)
+}
+
+func (s *TxnPruneSuite) TestPruneRemovesCompletedTransactions(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProvisioned("i-foo", "fake-nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	txns, closer := state.GetRawCollection(s.State, "txns")
+	defer closer()
+	pendingId := bson.NewObjectIdWithTime(time.Now().Add(-time.Hour))
+	err = txns.Insert(bson.D{{"_id", pendingId}, {"s", 2}})
+	c.Assert(err, jc.ErrorIsNil)
+	stash, closer := state.GetRawCollection(s.State, "txns.stash")
+	defer closer()
+	err = stash.Insert(bson.D{
+		{"_id", bson.D{{"c", "machines"}, {"id", "gone"}}},
+		{"txn-queue", []string{}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	before := s.countTxns(c)
+
+	err = state.PruneTransactions(s.State, time.Now().Add(time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.countTxns(c) < before, jc.IsTrue)
+	n, err := txns.FindId(pendingId).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 1)
+	n, err = stash.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, 0)
+
+	// Transactions still work after pruning.
+	err = txns.RemoveId(pendingId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Life(), gc.Equals, state.Dying)
+}
+
+func (s *TxnPruneSuite) TestPendingTransactions(c *gc.C) {
+	pending, err := s.State.PendingTransactions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+
+	txns, closer := state.GetRawCollection(s.State, "txns")
+	defer closer()
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+	id := bson.NewObjectIdWithTime(started)
+	err = txns.Insert(bson.D{{"_id", id}, {"s", 2}, {"o", []bson.D{}}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pending, err = s.State.PendingTransactions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 1)
+	c.Check(pending[0].Id, gc.Equals, id.Hex())
+	c.Check(pending[0].Started.Equal(started), jc.IsTrue)
+
+	err = s.State.ResumeTransaction(id.Hex())
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err = s.State.PendingTransactions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+}
+
+func (s *TxnPruneSuite) TestResumeTransactionInvalidId(c *gc.C) {
+	err := s.State.ResumeTransaction("foo")
+	c.Assert(err, gc.ErrorMatches, `transaction id "foo" not valid`)
+}
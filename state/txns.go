@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"reflect"
+	"sync/atomic"
 
 	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils/set"
@@ -19,6 +20,16 @@ const (
 	txnAssertEnvIsNotAlive = false
 )
 
+// txnCount holds the number of transactions run by this process.
+var txnCount uint64
+
+// TransactionCount returns the number of transactions that have been
+// run against the state database by this process, including each
+// attempt of a retried transaction.
+func TransactionCount() uint64 {
+	return atomic.LoadUint64(&txnCount)
+}
+
 // txnRunner returns a jujutxn.Runner instance.
 //
 // If st.transactionRunner is non-nil, then that will be
@@ -94,6 +105,7 @@ type multiEnvRunner struct {
 // to ensure correct interaction with these collections.
 func (r *multiEnvRunner) RunTransaction(ops []txn.Op) error {
 	ops = r.updateOps(ops)
+	atomic.AddUint64(&txnCount, 1)
 	return r.rawRunner.RunTransaction(ops)
 }
 
@@ -110,6 +122,7 @@ func (r *multiEnvRunner) Run(transactions jujutxn.TransactionSource) error {
 			return nil, err
 		}
 		ops = r.updateOps(ops)
+		atomic.AddUint64(&txnCount, 1)
 		return ops, nil
 	})
 }
@@ -198,6 +198,17 @@ func (s *MultiEnvRunnerSuite) TestRunTransaction(c *gc.C) {
 	}
 }
 
+func (s *MultiEnvRunnerSuite) TestTransactionCount(c *gc.C) {
+	before := TransactionCount()
+	err := s.multiEnvRunner.RunTransaction([]txn.Op{{C: machinesC, Id: "1"}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.multiEnvRunner.Run(func(int) ([]txn.Op, error) {
+		return []txn.Op{{C: machinesC, Id: "2"}}, nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(TransactionCount()-before, gc.Equals, uint64(2))
+}
+
 func (s *MultiEnvRunnerSuite) TestMultipleOps(c *gc.C) {
 	var inOps []txn.Op
 	var expectedOps []txn.Op
@@ -20,6 +20,7 @@ import (
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state/presence"
@@ -50,6 +51,23 @@ const (
 	// AssignNew indicates that every service unit should be assigned to a new
 	// dedicated machine.  A new machine will be launched for each new unit.
 	AssignNew AssignmentPolicy = "new"
+
+	// AssignPack indicates that every service unit should be assigned to
+	// the existing machine hosting the most units that does not already
+	// host a unit of the same service, and that new machines should be
+	// launched if required.
+	AssignPack AssignmentPolicy = "pack"
+
+	// AssignSpread indicates that every service unit should be assigned
+	// to a clean, empty machine, and that new machines should be launched
+	// if required. It is equivalent to AssignCleanEmpty.
+	AssignSpread AssignmentPolicy = "spread"
+
+	// AssignZoneBalanced indicates that every service unit should be
+	// assigned to a clean, empty machine in the availability zone
+	// hosting the fewest units of the same service, and that new
+	// machines should be launched if required.
+	AssignZoneBalanced AssignmentPolicy = "zone-balanced"
 )
 
 // ResolvedMode describes the way state transition errors
@@ -95,10 +113,17 @@ type unitDoc struct {
 	StorageAttachmentCount int `bson:"storageattachmentcount"`
 	MachineId              string
 	Resolved               ResolvedMode
+	Paused                 bool         `bson:"paused,omitempty"`
 	Tools                  *tools.Tools `bson:",omitempty"`
 	Life                   Life
 	TxnRevno               int64 `bson:"txn-revno"`
 	PasswordHash           string
+	// NextPasswordHash holds the hash of a password that is being
+	// rotated in; see SetNextPassword.
+	NextPasswordHash string `bson:",omitempty"`
+	// RemoveEmptyMachine is set when the unit was destroyed with
+	// DestroyRemovingEmptyMachines.
+	RemoveEmptyMachine bool `bson:"removeemptymachine,omitempty"`
 
 	// TODO(mue) No longer actively used, only in upgrades.go.
 	// To be removed later.
@@ -243,13 +268,17 @@ func (u *Unit) setPasswordHash(passwordHash string) error {
 		C:      unitsC,
 		Id:     u.doc.DocID,
 		Assert: notDeadDoc,
-		Update: bson.D{{"$set", bson.D{{"passwordhash", passwordHash}}}},
+		Update: bson.D{
+			{"$set", bson.D{{"passwordhash", passwordHash}}},
+			{"$unset", bson.D{{"nextpasswordhash", nil}}},
+		},
 	}}
 	err := u.st.runTransaction(ops)
 	if err != nil {
 		return fmt.Errorf("cannot set password of unit %q: %v", u, onAbort(err, ErrDead))
 	}
 	u.doc.PasswordHash = passwordHash
+	u.doc.NextPasswordHash = ""
 	return nil
 }
 
@@ -260,6 +289,43 @@ func (u *Unit) getPasswordHash() string {
 	return u.doc.PasswordHash
 }
 
+// SetNextPassword records a new password for the unit's agent. Both
+// the current and the new password remain valid until the new one is
+// confirmed with ConfirmPassword, so the agent can safely store the new
+// password before it stops using the old one.
+func (u *Unit) SetNextPassword(password string) error {
+	if len(password) < utils.MinAgentPasswordLength {
+		return fmt.Errorf("password is only %d bytes long, and is not a valid Agent password", len(password))
+	}
+	nextHash := utils.AgentPasswordHash(password)
+	ops := setNextPasswordOps(unitsC, u.doc.DocID, nextHash)
+	if err := u.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot set next password of unit %q: %v", u, onAbort(err, ErrDead))
+	}
+	u.doc.NextPasswordHash = nextHash
+	return nil
+}
+
+// ConfirmPassword makes the given password, previously recorded with
+// SetNextPassword, the only valid password for the unit's agent.
+// Confirming the current password does nothing.
+func (u *Unit) ConfirmPassword(password string) error {
+	agentHash := utils.AgentPasswordHash(password)
+	if agentHash == u.doc.PasswordHash {
+		return nil
+	}
+	if u.doc.NextPasswordHash == "" || agentHash != u.doc.NextPasswordHash {
+		return fmt.Errorf("cannot confirm password of unit %q: password does not match", u)
+	}
+	ops := confirmPasswordOps(unitsC, u.doc.DocID, agentHash)
+	if err := u.st.runTransaction(ops); err != nil {
+		return fmt.Errorf("cannot confirm password of unit %q: %v", u, onAbort(err, errNextPasswordChanged))
+	}
+	u.doc.PasswordHash = agentHash
+	u.doc.NextPasswordHash = ""
+	return nil
+}
+
 // PasswordValid returns whether the given password is valid
 // for the given unit.
 func (u *Unit) PasswordValid(password string) bool {
@@ -267,6 +333,9 @@ func (u *Unit) PasswordValid(password string) bool {
 	if agentHash == u.doc.PasswordHash {
 		return true
 	}
+	if u.doc.NextPasswordHash != "" && agentHash == u.doc.NextPasswordHash {
+		return true
+	}
 	// In Juju 1.16 and older we used the slower password hash for unit
 	// agents. So check to see if the supplied password matches the old
 	// path, and if so, update it to the new mechanism.
@@ -287,6 +356,18 @@ func (u *Unit) PasswordValid(password string) bool {
 // to a provisioned machine is Destroyed, it will be removed from state
 // directly.
 func (u *Unit) Destroy() (err error) {
+	return u.destroy(false)
+}
+
+// DestroyRemovingEmptyMachines destroys the unit as Destroy does, and
+// also arranges for the hosts of the unit's container to be destroyed
+// when the container's removal leaves them empty, as if the
+// environment's remove-empty-machines setting were true.
+func (u *Unit) DestroyRemovingEmptyMachines() error {
+	return u.destroy(true)
+}
+
+func (u *Unit) destroy(removeEmptyMachines bool) (err error) {
 	defer func() {
 		if err == nil {
 			// This is a white lie; the document might actually be removed.
@@ -302,6 +383,9 @@ func (u *Unit) Destroy() (err error) {
 				return nil, err
 			}
 		}
+		if removeEmptyMachines {
+			unit.doc.RemoveEmptyMachine = true
+		}
 		switch ops, err := unit.destroyOps(); err {
 		case errRefresh:
 		case errAlreadyDying:
@@ -373,11 +457,15 @@ func (u *Unit) destroyOps() ([]txn.Op, error) {
 	// its own CL.
 	minUnitsOp := minUnitsTriggerOp(u.st, u.ServiceName())
 	cleanupOp := u.st.newCleanupOp(cleanupDyingUnit, u.doc.Name)
+	setDying := bson.D{{"life", Dying}}
+	if u.doc.RemoveEmptyMachine {
+		setDying = append(setDying, bson.DocElem{"removeemptymachine", true})
+	}
 	setDyingOps := []txn.Op{{
 		C:      unitsC,
 		Id:     u.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		Update: bson.D{{"$set", setDying}},
 	}, cleanupOp, minUnitsOp}
 	if u.doc.Principal != "" {
 		return setDyingOps, nil
@@ -510,7 +598,17 @@ func (u *Unit) destroyHostOps(s *Service) (ops []txn.Op, err error) {
 	// If removal conditions satisfied by machine & container docs, we can
 	// destroy it, in addition to removing the unit principal.
 	if machineCheck && containerCheck {
-		machineUpdate = append(machineUpdate, bson.D{{"$set", bson.D{{"life", Dying}}}}...)
+		setDying := bson.D{{"life", Dying}}
+		if m.IsContainer() {
+			removeParent, err := u.removeEmptyMachines()
+			if err != nil {
+				return nil, err
+			}
+			if removeParent {
+				setDying = append(setDying, bson.DocElem{"removeemptyparent", true})
+			}
+		}
+		machineUpdate = append(machineUpdate, bson.DocElem{"$set", setDying})
 	}
 
 	ops = append(ops, txn.Op{
@@ -522,6 +620,19 @@ func (u *Unit) destroyHostOps(s *Service) (ops []txn.Op, err error) {
 	return ops, nil
 }
 
+// removeEmptyMachines reports whether the machines left empty by the
+// removal of the unit are to be destroyed.
+func (u *Unit) removeEmptyMachines() (bool, error) {
+	if u.doc.RemoveEmptyMachine {
+		return true, nil
+	}
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return false, err
+	}
+	return cfg.RemoveEmptyMachines(), nil
+}
+
 var errAlreadyRemoved = stderrors.New("entity has already been removed")
 
 // removeOps returns the operations necessary to remove the unit, assuming
@@ -756,15 +867,32 @@ func (u *Unit) addressesOfMachine() []network.Address {
 }
 
 // PublicAddress returns the public address of the unit and whether it is valid.
+// The address is chosen from those of the unit's machine according to the
+// address policy of the unit's service.
 func (u *Unit) PublicAddress() (string, bool) {
 	var publicAddress string
 	addresses := u.addressesOfMachine()
 	if len(addresses) > 0 {
-		publicAddress = network.SelectPublicAddress(addresses)
+		var err error
+		publicAddress, err = u.addressPolicy().selectAddress(u.st, addresses)
+		if err != nil {
+			unitLogger.Errorf("%v", err)
+			publicAddress = network.SelectPublicAddress(addresses)
+		}
 	}
 	return publicAddress, publicAddress != ""
 }
 
+// addressPolicy returns the address policy of the unit's service.
+func (u *Unit) addressPolicy() AddressPolicy {
+	service, err := u.Service()
+	if err != nil {
+		unitLogger.Errorf("%v", err)
+		return AddressPolicyPublic
+	}
+	return service.AddressPolicy()
+}
+
 // PrivateAddress returns the private address of the unit and whether it is valid.
 func (u *Unit) PrivateAddress() (string, bool) {
 	var privateAddress string
@@ -1207,6 +1335,10 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 	if unused {
 		massert = append(massert, bson.D{{"clean", bson.D{{"$ne", false}}}}...)
 	}
+	mset := bson.D{{"clean", false}}
+	if m.doc.DistributionGroup == "" {
+		mset = append(mset, bson.DocElem{"distributiongroup", u.doc.Service})
+	}
 	ops := []txn.Op{{
 		C:      unitsC,
 		Id:     u.doc.DocID,
@@ -1216,13 +1348,16 @@ func (u *Unit) assignToMachine(m *Machine, unused bool) (err error) {
 		C:      machinesC,
 		Id:     m.doc.DocID,
 		Assert: massert,
-		Update: bson.D{{"$addToSet", bson.D{{"principals", u.doc.Name}}}, {"$set", bson.D{{"clean", false}}}},
+		Update: bson.D{{"$addToSet", bson.D{{"principals", u.doc.Name}}}, {"$set", mset}},
 	}}
 	ops = append(ops, storageOps...)
 	err = u.st.runTransaction(ops)
 	if err == nil {
 		u.doc.MachineId = m.doc.Id
 		m.doc.Clean = false
+		if m.doc.DistributionGroup == "" {
+			m.doc.DistributionGroup = u.doc.Service
+		}
 		return nil
 	}
 	if err != txn.ErrAborted {
@@ -1340,6 +1475,7 @@ func (u *Unit) AssignToMachine(m *Machine) (err error) {
 // the supplied params, with the supplied constraints.
 func (u *Unit) assignToNewMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) error {
 	template.principals = []string{u.doc.Name}
+	template.distributionGroup = u.doc.Service
 	template.Dirty = true
 
 	var (
@@ -1446,6 +1582,30 @@ func (u *Unit) Constraints() (*constraints.Value, error) {
 	return &cons, nil
 }
 
+// EffectiveConstraints returns the constraints that would be used to
+// provision a new machine for the unit now, resolved from the current
+// environment and service constraints, along with the source of each
+// attribute. These may differ from the unit's recorded constraints,
+// which were resolved when the unit was created.
+func (u *Unit) EffectiveConstraints() (resolver.Effective, error) {
+	if u.doc.Principal != "" {
+		return resolver.Effective{}, errors.Errorf("unit %q is a subordinate", u.Name())
+	}
+	svc, err := u.Service()
+	if err != nil {
+		return resolver.Effective{}, errors.Trace(err)
+	}
+	scons, err := svc.Constraints()
+	if err != nil {
+		return resolver.Effective{}, errors.Trace(err)
+	}
+	effective, err := u.st.ResolveConstraints(resolver.Layers{Service: scons})
+	if err != nil {
+		return resolver.Effective{}, errors.Annotatef(err, "cannot resolve constraints for unit %q", u.Name())
+	}
+	return effective, nil
+}
+
 // AssignToNewMachineOrContainer assigns the unit to a new machine,
 // with constraints determined according to the service and
 // environment constraints at the time of unit creation. If a
@@ -1690,7 +1850,13 @@ var hasNoContainersTerm = bson.DocElem{
 
 // findCleanMachineQuery returns a Mongo query to find clean (and possibly empty) machines with
 // characteristics matching the specified constraints.
-func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (_ *mgo.Query, _ func(), err error) {
+func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value) (*mgo.Query, func(), error) {
+	return u.findMachineQuery(true, requireEmpty, cons)
+}
+
+// findMachineQuery returns a Mongo query to find machines, clean or otherwise, and possibly
+// empty, with characteristics matching the specified constraints.
+func (u *Unit) findMachineQuery(requireClean, requireEmpty bool, cons *constraints.Value) (_ *mgo.Query, _ func(), err error) {
 	db, closer := u.st.newDB()
 	defer func() {
 		if err != nil {
@@ -1699,7 +1865,7 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 	}()
 	containerRefsCollection := db.C(containerRefsC)
 
-	// Select all machines that can accept principal units and are clean.
+	// Select all machines that can accept principal units and, if required, are clean.
 	var containerRefs []machineContainers
 	// If we need empty machines, first build up a list of machine ids which have containers
 	// so we can exclude those.
@@ -1717,9 +1883,11 @@ func (u *Unit) findCleanMachineQuery(requireEmpty bool, cons *constraints.Value)
 		{"life", Alive},
 		{"series", u.doc.Series},
 		{"jobs", []MachineJob{JobHostUnits}},
-		{"clean", true},
 		{"machineid", bson.D{{"$nin", machinesWithContainers}}},
 	}
+	if requireClean {
+		terms = append(terms, bson.DocElem{"clean", true})
+	}
 	// Add the container filter term if necessary.
 	var containerType instance.ContainerType
 	if cons.Container != nil {
@@ -2073,6 +2241,31 @@ func (u *Unit) ClearResolved() error {
 	return nil
 }
 
+// IsPaused returns whether the unit has been paused, in which case its
+// agent runs no periodic hooks and handles no relation changes until the
+// unit is resumed.
+func (u *Unit) IsPaused() bool {
+	return u.doc.Paused
+}
+
+// SetPaused pauses or resumes the unit.
+func (u *Unit) SetPaused(paused bool) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set paused for unit %q", u)
+	ops := []txn.Op{{
+		C:      unitsC,
+		Id:     u.doc.DocID,
+		Assert: notDeadDoc,
+		Update: bson.D{{"$set", bson.D{{"paused", paused}}}},
+	}}
+	if err := u.st.runTransaction(ops); err == nil {
+		u.doc.Paused = paused
+		return nil
+	} else if err != txn.ErrAborted {
+		return err
+	}
+	return ErrDead
+}
+
 // AddMetrics adds a new batch of metrics to the database.
 func (u *Unit) AddMetrics(batchUUID string, created time.Time, charmURLRaw string, metrics []Metric) (*MetricBatch, error) {
 	var charmURL *charm.URL
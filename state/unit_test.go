@@ -14,6 +14,8 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/constraints/resolver"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -97,6 +99,43 @@ func (s *UnitSuite) TestService(c *gc.C) {
 	c.Assert(svc.Name(), gc.Equals, s.unit.ServiceName())
 }
 
+func (s *UnitSuite) TestEffectiveConstraints(c *gc.C) {
+	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=4G arch=amd64"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetConstraints(constraints.MustParse("mem=8G"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit's recorded constraints were resolved when it was added,
+	// but its effective constraints reflect the current settings.
+	recorded, err := s.unit.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*recorded, jc.DeepEquals, constraints.Value{})
+	effective, err := s.unit.EffectiveConstraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(effective.Value, jc.DeepEquals, constraints.MustParse("arch=amd64 mem=8G"))
+	c.Assert(effective.Sources, jc.DeepEquals, map[string]resolver.Source{
+		"arch": resolver.Environment,
+		"mem":  resolver.Service,
+	})
+}
+
+func (s *UnitSuite) TestEffectiveConstraintsSubordinate(c *gc.C) {
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("logging", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	sub, err := s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = sub.EffectiveConstraints()
+	c.Assert(err, gc.ErrorMatches, `unit "logging/0" is a subordinate`)
+}
+
 func (s *UnitSuite) TestConfigSettingsNeedCharmURLSet(c *gc.C) {
 	_, err := s.unit.ConfigSettings()
 	c.Assert(err, gc.ErrorMatches, "unit charm not set")
@@ -1108,6 +1147,13 @@ func (s *UnitSuite) TestSetPassword(c *gc.C) {
 	})
 }
 
+func (s *UnitSuite) TestRotatePassword(c *gc.C) {
+	preventUnitDestroyRemove(c, s.unit)
+	testRotatePassword(c, func() (passwordRotatingEntity, error) {
+		return s.State.Unit(s.unit.Name())
+	})
+}
+
 func (s *UnitSuite) TestSetAgentCompatPassword(c *gc.C) {
 	e, err := s.State.Unit(s.unit.Name())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1215,6 +1261,28 @@ func (s *UnitSuite) TestGetSetClearResolved(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `cannot set resolved mode for unit "wordpress/0": invalid error resolution mode: "foo"`)
 }
 
+func (s *UnitSuite) TestSetPaused(c *gc.C) {
+	c.Assert(s.unit.IsPaused(), jc.IsFalse)
+
+	err := s.unit.SetPaused(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.IsPaused(), jc.IsTrue)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.IsPaused(), jc.IsTrue)
+
+	err = s.unit.SetPaused(false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.unit.IsPaused(), jc.IsFalse)
+
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetPaused(true)
+	c.Assert(err, gc.ErrorMatches, `cannot set paused for unit "wordpress/0": not found or dead`)
+}
+
 func (s *UnitSuite) TestOpenedPorts(c *gc.C) {
 	// Verify ports can be opened and closed only when the unit has
 	// assigned machine.
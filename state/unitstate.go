@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// unitStateDoc records the operation state of a unit's uniter. The
+// state is opaque to the controller; it is stored on behalf of the
+// unit agent, so that an agent running on a fresh machine or container
+// can resume exactly where its predecessor stopped.
+type unitStateDoc struct {
+	DocID       string `bson:"_id"`
+	EnvUUID     string `bson:"env-uuid"`
+	UniterState string `bson:"uniter-state"`
+}
+
+// UniterState returns the operation state last recorded by the unit's
+// uniter, or an empty string if none has been recorded.
+func (u *Unit) UniterState() (string, error) {
+	states, closer := u.st.getCollection(unitStatesC)
+	defer closer()
+
+	var doc unitStateDoc
+	err := states.FindId(u.globalKey()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Annotatef(err, "cannot get uniter state for unit %q", u)
+	}
+	return doc.UniterState, nil
+}
+
+// SetUniterState records the operation state of the unit's uniter.
+// The unit must not be dead.
+func (u *Unit) SetUniterState(data string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set uniter state for unit %q", u)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if u.doc.Life == Dead {
+			return nil, ErrDead
+		}
+		current, err := u.UniterState()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		exists, err := u.hasUniterState()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if exists && current == data {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:      unitsC,
+			Id:     u.doc.DocID,
+			Assert: notDeadDoc,
+		}}
+		if exists {
+			ops = append(ops, txn.Op{
+				C:      unitStatesC,
+				Id:     u.globalKey(),
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"uniter-state", data}}}},
+			})
+		} else {
+			ops = append(ops, txn.Op{
+				C:      unitStatesC,
+				Id:     u.globalKey(),
+				Assert: txn.DocMissing,
+				Insert: &unitStateDoc{UniterState: data},
+			})
+		}
+		return ops, nil
+	}
+	return u.st.run(buildTxn)
+}
+
+func (u *Unit) hasUniterState() (bool, error) {
+	states, closer := u.st.getCollection(unitStatesC)
+	defer closer()
+	count, err := states.FindId(u.globalKey()).Count()
+	return count > 0, err
+}
+
+func removeUnitStateOp(key string) txn.Op {
+	return txn.Op{
+		C:      unitStatesC,
+		Id:     key,
+		Remove: true,
+	}
+}
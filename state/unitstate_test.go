@@ -0,0 +1,82 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitStateSuite struct {
+	ConnSuite
+	unit *state.Unit
+}
+
+var _ = gc.Suite(&UnitStateSuite{})
+
+func (s *UnitStateSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	service := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.unit, err = service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitStateSuite) TestNoUniterState(c *gc.C) {
+	data, err := s.unit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "")
+}
+
+func (s *UnitStateSuite) TestSetUniterState(c *gc.C) {
+	err := s.unit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := s.unit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "kind: install\nstep: pending\n")
+
+	err = s.unit.SetUniterState("kind: continue\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = s.unit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "kind: continue\nstep: pending\n")
+
+	// Setting the same state again is a no-op.
+	err = s.unit.SetUniterState("kind: continue\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UnitStateSuite) TestSetUniterStateDeadUnit(c *gc.C) {
+	err := s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, gc.ErrorMatches, `cannot set uniter state for unit "wordpress/0": not found or dead`)
+}
+
+func (s *UnitStateSuite) TestSetUniterStateRemovedUnit(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, gc.ErrorMatches, `cannot set uniter state for unit "wordpress/0": unit "wordpress/0" not found`)
+}
+
+func (s *UnitStateSuite) TestRemoveUnitRemovesUniterState(c *gc.C) {
+	err := s.unit.SetUniterState("kind: install\nstep: pending\n")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err := s.unit.UniterState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "")
+}
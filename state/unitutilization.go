@@ -0,0 +1,101 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// UnitUtilization holds a sample of the resources used by the
+// processes of a unit, as measured on the unit's machine.
+type UnitUtilization struct {
+	// Unit holds the name of the unit.
+	Unit string
+
+	// Time holds when the sample was taken.
+	Time time.Time
+
+	// CPUPercent holds the CPU time used by the unit's processes
+	// since the previous sample, as a percentage of one CPU.
+	CPUPercent float64
+
+	// MemoryBytes holds the resident memory of the unit's processes.
+	MemoryBytes uint64
+
+	// Processes holds the number of the unit's processes.
+	Processes int
+}
+
+// unitUtilizationDoc represents a UnitUtilization sample in the capped
+// unit utilization collection. The documents are never updated, so
+// they are written directly rather than through transactions.
+type unitUtilizationDoc struct {
+	EnvUUID     string    `bson:"env-uuid"`
+	Unit        string    `bson:"unit"`
+	Time        time.Time `bson:"time"`
+	CPUPercent  float64   `bson:"cpu-percent"`
+	MemoryBytes int64     `bson:"memory-bytes"`
+	Processes   int       `bson:"processes"`
+}
+
+// AddUnitUtilization records the given samples of the resources used
+// by units of the environment.
+func (st *State) AddUnitUtilization(samples []UnitUtilization) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(samples))
+	for i, sample := range samples {
+		docs[i] = &unitUtilizationDoc{
+			EnvUUID:     st.EnvironUUID(),
+			Unit:        sample.Unit,
+			Time:        sample.Time.UTC(),
+			CPUPercent:  sample.CPUPercent,
+			MemoryBytes: int64(sample.MemoryBytes),
+			Processes:   sample.Processes,
+		}
+	}
+	// The collection is capped, so it is not in multiEnvCollections
+	// and the environment must be filtered by hand.
+	coll, closer := st.getRawCollection(unitUtilizationC)
+	defer closer()
+	if err := coll.Insert(docs...); err != nil {
+		return errors.Annotate(err, "cannot add unit utilization")
+	}
+	return nil
+}
+
+// LatestUnitUtilization returns the latest sample of each unit of the
+// environment that was taken no earlier than the given time, keyed by
+// unit name.
+func (st *State) LatestUnitUtilization(since time.Time) (map[string]UnitUtilization, error) {
+	coll, closer := st.getRawCollection(unitUtilizationC)
+	defer closer()
+
+	query := bson.D{
+		{"env-uuid", st.EnvironUUID()},
+		{"time", bson.D{{"$gte", since.UTC()}}},
+	}
+	var docs []unitUtilizationDoc
+	if err := coll.Find(query).Sort("-time").All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get unit utilization")
+	}
+	latest := make(map[string]UnitUtilization)
+	for _, doc := range docs {
+		if _, ok := latest[doc.Unit]; ok {
+			continue
+		}
+		latest[doc.Unit] = UnitUtilization{
+			Unit:        doc.Unit,
+			Time:        doc.Time.UTC(),
+			CPUPercent:  doc.CPUPercent,
+			MemoryBytes: uint64(doc.MemoryBytes),
+			Processes:   doc.Processes,
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type UnitUtilizationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UnitUtilizationSuite{})
+
+func (s *UnitUtilizationSuite) TestLatestUnitUtilization(c *gc.C) {
+	now := time.Date(2015, 10, 1, 12, 0, 0, 0, time.UTC)
+	err := s.State.AddUnitUtilization([]state.UnitUtilization{{
+		Unit:        "mysql/0",
+		Time:        now.Add(-10 * time.Minute),
+		CPUPercent:  50,
+		MemoryBytes: 1 << 20,
+		Processes:   2,
+	}, {
+		Unit:        "wordpress/0",
+		Time:        now.Add(-10 * time.Minute),
+		CPUPercent:  1.5,
+		MemoryBytes: 1 << 30,
+		Processes:   3,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AddUnitUtilization([]state.UnitUtilization{{
+		Unit:        "mysql/0",
+		Time:        now.Add(-time.Minute),
+		CPUPercent:  12.5,
+		MemoryBytes: 2 << 20,
+		Processes:   4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	latest, err := s.State.LatestUnitUtilization(now.Add(-time.Hour))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(latest, jc.DeepEquals, map[string]state.UnitUtilization{
+		"mysql/0": {
+			Unit:        "mysql/0",
+			Time:        now.Add(-time.Minute),
+			CPUPercent:  12.5,
+			MemoryBytes: 2 << 20,
+			Processes:   4,
+		},
+		"wordpress/0": {
+			Unit:        "wordpress/0",
+			Time:        now.Add(-10 * time.Minute),
+			CPUPercent:  1.5,
+			MemoryBytes: 1 << 30,
+			Processes:   3,
+		},
+	})
+
+	// Older samples are not returned.
+	latest, err = s.State.LatestUnitUtilization(now.Add(-5 * time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(latest, gc.HasLen, 1)
+	c.Assert(latest["mysql/0"].CPUPercent, gc.Equals, 12.5)
+}
+
+func (s *UnitUtilizationSuite) TestUnitUtilizationIsEnvironmentSpecific(c *gc.C) {
+	now := time.Now()
+	err := s.State.AddUnitUtilization([]state.UnitUtilization{{
+		Unit: "mysql/0",
+		Time: now,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	latest, err := st.LatestUnitUtilization(now.Add(-time.Minute))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(latest, gc.HasLen, 0)
+}
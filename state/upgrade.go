@@ -150,13 +150,23 @@ func (info *UpgradeInfo) Watch() NotifyWatcher {
 // When this returns true the master state state server can begin it's
 // own upgrade.
 func (info *UpgradeInfo) AllProvisionedStateServersReady() (bool, error) {
-	provisioned, err := info.getProvisionedStateServers()
+	pending, err := info.StateServersPending()
 	if err != nil {
 		return false, errors.Trace(err)
 	}
+	return len(pending) == 0, nil
+}
+
+// StateServersPending returns the machine ids for state servers that
+// have been started by the provisioner but have not yet signalled
+// that they are ready for upgrade.
+func (info *UpgradeInfo) StateServersPending() ([]string, error) {
+	provisioned, err := info.getProvisionedStateServers()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	ready := set.NewStrings(info.doc.StateServersReady...)
-	missing := set.NewStrings(provisioned...).Difference(ready)
-	return missing.IsEmpty(), nil
+	return set.NewStrings(provisioned...).Difference(ready).SortedValues(), nil
 }
 
 func (info *UpgradeInfo) getProvisionedStateServers() ([]string, error) {
@@ -452,6 +462,17 @@ func (info *UpgradeInfo) makeArchiveOps(doc *upgradeInfoDoc, status UpgradeStatu
 	}}
 }
 
+// CurrentUpgradeInfo returns the UpgradeInfo describing the upgrade
+// currently in progress. An error satisfying errors.IsNotFound is
+// returned if there is no such upgrade.
+func (st *State) CurrentUpgradeInfo() (*UpgradeInfo, error) {
+	doc, err := currentUpgradeInfoDoc(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UpgradeInfo{st: st, doc: *doc}, nil
+}
+
 // IsUpgrading returns true if an upgrade is currently in progress.
 func (st *State) IsUpgrading() (bool, error) {
 	doc, err := currentUpgradeInfoDoc(st)
@@ -404,6 +404,23 @@ func (s *UpgradeSuite) TestAllProvisionedStateServersReady(c *gc.C) {
 	assertReady(true)
 }
 
+func (s *UpgradeSuite) TestStateServersPending(c *gc.C) {
+	serverIdB, serverIdC := s.addStateServers(c)
+	s.provision(c, serverIdB, serverIdC)
+
+	info, err := s.State.EnsureUpgradeInfo(serverIdB, vers("1.1.1"), vers("1.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err := info.StateServersPending()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, []string{s.serverIdA, serverIdC})
+
+	info, err = s.State.EnsureUpgradeInfo(s.serverIdA, vers("1.1.1"), vers("1.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	pending, err = info.StateServersPending()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, []string{serverIdC})
+}
+
 func (s *UpgradeSuite) TestAllProvisionedStateServersReadyWithPreEnvUUIDSchema(c *gc.C) {
 	serverIdB, serverIdC := s.addStateServers(c)
 
@@ -668,6 +685,25 @@ func (s *UpgradeSuite) TestAbortCurrentUpgrade(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *UpgradeSuite) TestCurrentUpgradeInfo(c *gc.C) {
+	_, err := s.State.CurrentUpgradeInfo()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.EnsureUpgradeInfo(s.serverIdA, vers("1.1.1"), vers("1.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := s.State.CurrentUpgradeInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.PreviousVersion(), gc.Equals, vers("1.1.1"))
+	c.Assert(info.TargetVersion(), gc.Equals, vers("1.2.3"))
+	c.Assert(info.Status(), gc.Equals, state.UpgradePending)
+	c.Assert(info.StateServersReady(), jc.DeepEquals, []string{s.serverIdA})
+
+	err = info.Abort()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.CurrentUpgradeInfo()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *UpgradeSuite) TestClearUpgradeInfo(c *gc.C) {
 	v111 := vers("1.1.1")
 	v123 := vers("1.2.3")
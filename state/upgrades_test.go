@@ -344,6 +344,7 @@ func (s *upgradesSuite) userEnvNameSetup(c *gc.C, userEnvNamePairs [][]string) {
 			userEnvNamePair[1],
 			uuid.String(),
 			s.state.EnvironUUID(),
+			"", "",
 		))
 	}
 	err := s.state.runRawTransaction(ops)
@@ -2842,3 +2843,61 @@ func (s *upgradesSuite) TestMoveServiceUnitSeqToSequenceWithPreExistingSequence(
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(count, gc.Equals, 7)
 }
+
+func (s *upgradesSuite) TestMoveCloudCredentialsFromSettings(c *gc.C) {
+	err := s.state.UpdateEnvironConfig(map[string]interface{}{
+		"access-key": "x",
+		"secret-key": "y",
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	credentialAttrs := func(providerType string) []string {
+		c.Check(providerType, gc.Equals, "someprovider")
+		return []string{"access-key", "secret-key", "unset-key"}
+	}
+
+	err = MoveCloudCredentialsFromSettings(s.state, credentialAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	cred, err := s.state.CloudCredential("test-admin-testenv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cred.Owner(), gc.Equals, s.owner)
+	c.Assert(cred.ProviderType(), gc.Equals, "someprovider")
+	c.Assert(cred.Attributes(), jc.DeepEquals, map[string]string{
+		"access-key": "x",
+		"secret-key": "y",
+	})
+	env, err := s.state.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	name, ok := env.CloudCredential()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(name, gc.Equals, "test-admin-testenv")
+
+	settings, err := readSettings(s.state, environGlobalKey)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = settings.Get("access-key")
+	c.Assert(ok, jc.IsFalse)
+	cfg, err := s.state.EnvironConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["access-key"], gc.Equals, "x")
+
+	// Running the step again changes nothing.
+	err = MoveCloudCredentialsFromSettings(s.state, credentialAttrs)
+	c.Assert(err, jc.ErrorIsNil)
+	creds, err := s.state.AllCloudCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.HasLen, 1)
+}
+
+func (s *upgradesSuite) TestMoveCloudCredentialsFromSettingsNoCredentials(c *gc.C) {
+	err := MoveCloudCredentialsFromSettings(s.state, func(string) []string {
+		return []string{"access-key"}
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	creds, err := s.state.AllCloudCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.HasLen, 0)
+	env, err := s.state.Environment()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := env.CloudCredential()
+	c.Assert(ok, jc.IsFalse)
+}
@@ -0,0 +1,306 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// UpgradeSeriesStatus describes the progress of an in-place series
+// upgrade, either of a machine as a whole or of one of the units it
+// hosts.
+type UpgradeSeriesStatus string
+
+const (
+	// UpgradeSeriesPrepareStarted indicates that the units on the
+	// machine have been asked to run their pre-series-upgrade hooks.
+	UpgradeSeriesPrepareStarted UpgradeSeriesStatus = "prepare-started"
+
+	// UpgradeSeriesPrepareCompleted indicates that the
+	// pre-series-upgrade hooks have run, and that the operator may
+	// upgrade the machine's operating system.
+	UpgradeSeriesPrepareCompleted UpgradeSeriesStatus = "prepare-completed"
+
+	// UpgradeSeriesCompleteStarted indicates that the operating system
+	// has been upgraded, and that the units on the machine have been
+	// asked to run their post-series-upgrade hooks.
+	UpgradeSeriesCompleteStarted UpgradeSeriesStatus = "complete-started"
+
+	// UpgradeSeriesCompleted indicates that the post-series-upgrade
+	// hooks have run, and that the upgrade is finished.
+	UpgradeSeriesCompleted UpgradeSeriesStatus = "completed"
+)
+
+// upgradeSeriesLockDoc records the progress of a machine's series
+// upgrade. It remains after the upgrade completes, until the next
+// upgrade of the machine is prepared or the machine is removed.
+type upgradeSeriesLockDoc struct {
+	DocID        string                         `bson:"_id"`
+	EnvUUID      string                         `bson:"env-uuid"`
+	MachineId    string                         `bson:"machineid"`
+	FromSeries   string                         `bson:"from-series"`
+	ToSeries     string                         `bson:"to-series"`
+	Status       UpgradeSeriesStatus            `bson:"status"`
+	UnitStatuses map[string]UpgradeSeriesStatus `bson:"unit-statuses"`
+}
+
+// upgradeSeriesOrder holds the order in which a series upgrade moves
+// through the statuses.
+var upgradeSeriesOrder = map[UpgradeSeriesStatus]int{
+	UpgradeSeriesPrepareStarted:   1,
+	UpgradeSeriesPrepareCompleted: 2,
+	UpgradeSeriesCompleteStarted:  3,
+	UpgradeSeriesCompleted:        4,
+}
+
+func (m *Machine) upgradeSeriesLock() (*upgradeSeriesLockDoc, error) {
+	locks, closer := m.st.getCollection(machineUpgradeSeriesLocksC)
+	defer closer()
+
+	var doc upgradeSeriesLockDoc
+	err := locks.FindId(m.doc.DocID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("series upgrade for machine %s", m.Id())
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get series upgrade for machine %s", m.Id())
+	}
+	return &doc, nil
+}
+
+// PrepareUpgradeSeries starts an in-place upgrade of the machine to the
+// given series, by asking every unit on the machine to run its
+// pre-series-upgrade hook. Once all the units have done so, the
+// machine's status is UpgradeSeriesPrepareCompleted, and the operator
+// may upgrade the machine's operating system before calling
+// CompleteUpgradeSeries. An upgrade cannot be prepared while another
+// upgrade of the same machine is in progress.
+func (m *Machine) PrepareUpgradeSeries(series string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot prepare series upgrade for machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.doc.Life != Alive {
+			return nil, machineNotAliveErr
+		}
+		if series == m.doc.Series {
+			return nil, errors.Errorf("machine is already running series %q", series)
+		}
+		lock, err := m.upgradeSeriesLock()
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if lock != nil && lock.Status != UpgradeSeriesCompleted {
+			return nil, errors.AlreadyExistsf("series upgrade to %q", lock.ToSeries)
+		}
+		units, err := m.Units()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unitStatuses := make(map[string]UpgradeSeriesStatus)
+		for _, unit := range units {
+			unitStatuses[unit.Name()] = UpgradeSeriesPrepareStarted
+		}
+		status := UpgradeSeriesPrepareStarted
+		if len(unitStatuses) == 0 {
+			status = UpgradeSeriesPrepareCompleted
+		}
+		ops := []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"series", m.doc.Series}},
+		}}
+		if lock == nil {
+			return append(ops, txn.Op{
+				C:      machineUpgradeSeriesLocksC,
+				Id:     m.doc.DocID,
+				Assert: txn.DocMissing,
+				Insert: &upgradeSeriesLockDoc{
+					MachineId:    m.Id(),
+					FromSeries:   m.doc.Series,
+					ToSeries:     series,
+					Status:       status,
+					UnitStatuses: unitStatuses,
+				},
+			}), nil
+		}
+		return append(ops, txn.Op{
+			C:      machineUpgradeSeriesLocksC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"status", UpgradeSeriesCompleted}},
+			Update: bson.D{{"$set", bson.D{
+				{"from-series", m.doc.Series},
+				{"to-series", series},
+				{"status", status},
+				{"unit-statuses", unitStatuses},
+			}}},
+		}), nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// CompleteUpgradeSeries records that the machine's operating system has
+// been upgraded to the series given to PrepareUpgradeSeries, and asks
+// every unit on the machine to run its post-series-upgrade hook. The
+// series of the units themselves is not changed.
+func (m *Machine) CompleteUpgradeSeries() (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot complete series upgrade for machine %s", m)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		lock, err := m.upgradeSeriesLock()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if lock.Status != UpgradeSeriesPrepareCompleted {
+			return nil, errors.Errorf("series upgrade is %s, not %s", lock.Status, UpgradeSeriesPrepareCompleted)
+		}
+		unitStatuses := make(map[string]UpgradeSeriesStatus)
+		for name := range lock.UnitStatuses {
+			unitStatuses[name] = UpgradeSeriesCompleteStarted
+		}
+		status := UpgradeSeriesCompleteStarted
+		if len(unitStatuses) == 0 {
+			status = UpgradeSeriesCompleted
+		}
+		return []txn.Op{{
+			C:      machinesC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"life", bson.D{{"$ne", Dead}}}, {"series", lock.FromSeries}},
+			Update: bson.D{{"$set", bson.D{{"series", lock.ToSeries}}}},
+		}, {
+			C:      machineUpgradeSeriesLocksC,
+			Id:     m.doc.DocID,
+			Assert: bson.D{{"status", UpgradeSeriesPrepareCompleted}},
+			Update: bson.D{{"$set", bson.D{
+				{"status", status},
+				{"unit-statuses", unitStatuses},
+			}}},
+		}}, nil
+	}
+	if err := m.st.run(buildTxn); err != nil {
+		return err
+	}
+	return m.Refresh()
+}
+
+// UpgradeSeriesStatus returns the status of the machine's series
+// upgrade. An error satisfying errors.IsNotFound is returned if no
+// upgrade of the machine has been prepared.
+func (m *Machine) UpgradeSeriesStatus() (UpgradeSeriesStatus, error) {
+	lock, err := m.upgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return lock.Status, nil
+}
+
+// UpgradeSeriesTarget returns the series to which the machine is being,
+// or was last, upgraded.
+func (m *Machine) UpgradeSeriesTarget() (string, error) {
+	lock, err := m.upgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return lock.ToSeries, nil
+}
+
+// UpgradeSeriesUnitStatus returns the progress of the named unit in the
+// machine's series upgrade. An error satisfying errors.IsNotFound is
+// returned if no upgrade has been prepared, or if the unit was not on
+// the machine when it was.
+func (m *Machine) UpgradeSeriesUnitStatus(unitName string) (UpgradeSeriesStatus, error) {
+	lock, err := m.upgradeSeriesLock()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	status, ok := lock.UnitStatuses[unitName]
+	if !ok {
+		return "", errors.NotFoundf("unit %q in series upgrade for machine %s", unitName, m.Id())
+	}
+	return status, nil
+}
+
+// SetUpgradeSeriesUnitStatus records that the named unit has finished
+// its part of the current phase of the machine's series upgrade; the
+// status must be UpgradeSeriesPrepareCompleted while the upgrade is
+// being prepared, and UpgradeSeriesCompleted while it is being
+// completed. Once every unit has finished a phase, so has the machine.
+// Recording a phase the unit has already finished has no effect.
+func (m *Machine) SetUpgradeSeriesUnitStatus(unitName string, status UpgradeSeriesStatus) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set series upgrade status of unit %q", unitName)
+	if _, ok := upgradeSeriesOrder[status]; !ok {
+		return errors.NotValidf("series upgrade status %q", status)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		lock, err := m.upgradeSeriesLock()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		current, ok := lock.UnitStatuses[unitName]
+		if !ok {
+			return nil, errors.NotFoundf("unit %q in series upgrade for machine %s", unitName, m.Id())
+		}
+		if upgradeSeriesOrder[current] >= upgradeSeriesOrder[status] {
+			// The unit has already finished this phase; this
+			// happens if a unit agent restarts while recording it.
+			return nil, jujutxn.ErrNoOperations
+		}
+		var expect UpgradeSeriesStatus
+		switch lock.Status {
+		case UpgradeSeriesPrepareStarted:
+			expect = UpgradeSeriesPrepareCompleted
+		case UpgradeSeriesCompleteStarted:
+			expect = UpgradeSeriesCompleted
+		}
+		if status != expect {
+			return nil, errors.Errorf("unexpected status %q while series upgrade is %s", status, lock.Status)
+		}
+
+		names := make([]string, 0, len(lock.UnitStatuses))
+		for name := range lock.UnitStatuses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		assert := bson.D{{"status", lock.Status}}
+		finished := true
+		for _, name := range names {
+			assert = append(assert, bson.DocElem{"unit-statuses." + name, lock.UnitStatuses[name]})
+			if name != unitName && lock.UnitStatuses[name] != status {
+				finished = false
+			}
+		}
+		set := bson.D{{"unit-statuses." + unitName, status}}
+		if finished {
+			set = append(set, bson.DocElem{"status", status})
+		}
+		return []txn.Op{{
+			C:      machineUpgradeSeriesLocksC,
+			Id:     m.doc.DocID,
+			Assert: assert,
+			Update: bson.D{{"$set", set}},
+		}}, nil
+	}
+	return m.st.run(buildTxn)
+}
+
+// WatchUpgradeSeriesNotifications returns a watcher that notifies of
+// changes to the machine's series upgrade.
+func (m *Machine) WatchUpgradeSeriesNotifications() NotifyWatcher {
+	return newEntityWatcher(m.st, machineUpgradeSeriesLocksC, m.doc.DocID)
+}
+
+func removeUpgradeSeriesLockOp(st *State, machineId string) txn.Op {
+	return txn.Op{
+		C:      machineUpgradeSeriesLocksC,
+		Id:     st.docID(machineId),
+		Remove: true,
+	}
+}
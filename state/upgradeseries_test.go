@@ -0,0 +1,221 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type UpgradeSeriesSuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&UpgradeSeriesSuite{})
+
+func (s *UpgradeSeriesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	var err error
+	s.machine, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.unit, err = wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UpgradeSeriesSuite) assertStatus(c *gc.C, expect state.UpgradeSeriesStatus) {
+	status, err := s.machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, expect)
+}
+
+func (s *UpgradeSeriesSuite) assertUnitStatus(c *gc.C, unitName string, expect state.UpgradeSeriesStatus) {
+	status, err := s.machine.UpgradeSeriesUnitStatus(unitName)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, expect)
+}
+
+func (s *UpgradeSeriesSuite) TestNoUpgrade(c *gc.C) {
+	_, err := s.machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.machine.UpgradeSeriesUnitStatus("wordpress/0")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, gc.ErrorMatches, `cannot complete series upgrade for machine 0: series upgrade for machine 0 not found`)
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeries(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareStarted)
+	s.assertUnitStatus(c, "wordpress/0", state.UpgradeSeriesPrepareStarted)
+	target, err := s.machine.UpgradeSeriesTarget()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.Equals, "trusty")
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareCompleted)
+	s.assertUnitStatus(c, "wordpress/0", state.UpgradeSeriesPrepareCompleted)
+
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.machine.Series(), gc.Equals, "trusty")
+	s.assertStatus(c, state.UpgradeSeriesCompleteStarted)
+	s.assertUnitStatus(c, "wordpress/0", state.UpgradeSeriesCompleteStarted)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesCompleted)
+
+	// Another upgrade may be prepared once the last is completed.
+	err = s.machine.PrepareUpgradeSeries("quantal")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareStarted)
+}
+
+func (s *UpgradeSeriesSuite) TestUpgradeSeriesWaitsForAllUnits(c *gc.C) {
+	s.AddTestingService(c, "logging", s.AddTestingCharm(c, "logging"))
+	eps, err := s.State.InferEndpoints("logging", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(s.unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Unit("logging/0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitStatus(c, "logging/0", state.UpgradeSeriesPrepareStarted)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareStarted)
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, gc.ErrorMatches, `cannot complete series upgrade for machine 0: series upgrade is prepare-started, not prepare-completed`)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("logging/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesNoUnits(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	status, err := machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.UpgradeSeriesPrepareCompleted)
+
+	err = machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	status, err = machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.UpgradeSeriesCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesSameSeries(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("quantal")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade for machine 0: machine is already running series "quantal"`)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesInProgress(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.PrepareUpgradeSeries("precise")
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade for machine 0: series upgrade to "trusty" already exists`)
+}
+
+func (s *UpgradeSeriesSuite) TestPrepareUpgradeSeriesDyingMachine(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, gc.ErrorMatches, `cannot prepare series upgrade for machine 1: machine is not alive`)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusUnexpected(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesCompleted)
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit "wordpress/0": unexpected status "completed" while series upgrade is prepare-started`)
+	err = s.machine.SetUpgradeSeriesUnitStatus("mysql/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusIdempotent(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 2; i++ {
+		err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.assertStatus(c, state.UpgradeSeriesPrepareCompleted)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusFinishedPhase(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitStatus(c, "wordpress/0", state.UpgradeSeriesCompleteStarted)
+}
+
+func (s *UpgradeSeriesSuite) TestSetUpgradeSeriesUnitStatusInvalid(c *gc.C) {
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", "bogus")
+	c.Assert(err, gc.ErrorMatches, `cannot set series upgrade status of unit "wordpress/0": series upgrade status "bogus" not valid`)
+}
+
+func (s *UpgradeSeriesSuite) TestWatchUpgradeSeriesNotifications(c *gc.C) {
+	w := s.machine.WatchUpgradeSeriesNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.machine.SetUpgradeSeriesUnitStatus("wordpress/0", state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *UpgradeSeriesSuite) TestRemoveMachineRemovesUpgradeSeriesLock(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.UpgradeSeriesStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
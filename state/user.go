@@ -173,6 +173,13 @@ type userDoc struct {
 	// It is really informational only as far as everyone except the
 	// api server is concerned.
 	LastLogin *time.Time `bson:"lastlogin"`
+	// TOTPSecret is the secret of the time-based one-time passwords
+	// the user must give as a second factor when logging in with a
+	// password. It is empty unless the user has enrolled.
+	TOTPSecret string `bson:"totpsecret,omitempty"`
+	// TOTPLastStep is the time step of the last one-time password
+	// the user gave, so that no code is accepted twice.
+	TOTPLastStep int64 `bson:"totplaststep,omitempty"`
 }
 
 // String returns "<name>@local" where <name> is the Name of the user.
@@ -268,14 +275,20 @@ func (u *User) SetPassword(password string) error {
 	return u.SetPasswordHash(utils.UserPasswordHash(password, salt), salt)
 }
 
-// SetPasswordHash stores the hash and the salt of the password.
+// SetPasswordHash stores the hash and the salt of the password. The
+// user's sessions are ended, so that they must log in with the new
+// password.
 func (u *User) SetPasswordHash(pwHash string, pwSalt string) error {
-	ops := []txn.Op{{
+	sessionOps, err := u.st.removeUserSessionsOps(u.Name())
+	if err != nil {
+		return errors.Annotatef(err, "cannot set password of user %q", u.Name())
+	}
+	ops := append([]txn.Op{{
 		C:      usersC,
 		Id:     u.Name(),
 		Assert: txn.DocExists,
 		Update: bson.D{{"$set", bson.D{{"passwordhash", pwHash}, {"passwordsalt", pwSalt}}}},
-	}}
+	}}, sessionOps...)
 	if err := u.st.runTransaction(ops); err != nil {
 		return errors.Annotatef(err, "cannot set password of user %q", u.Name())
 	}
@@ -365,6 +378,83 @@ func (u *User) IsDisabled() bool {
 	return u.doc.Deactivated
 }
 
+// SetTOTPSecret enrols the user for two-factor authentication with the
+// given time-based one-time password secret, replacing any secret they
+// were enrolled with before. An empty secret removes the enrollment.
+// The user's sessions are ended when they are enrolled, so that they
+// must log in with the new secret.
+func (u *User) SetTOTPSecret(secret string) error {
+	update := bson.D{{"$set", bson.D{{"totpsecret", secret}}}}
+	if secret == "" {
+		update = bson.D{{"$unset", bson.D{{"totpsecret", nil}}}}
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     u.Name(),
+		Assert: txn.DocExists,
+		Update: update,
+	}}
+	if secret != "" {
+		sessionOps, err := u.st.removeUserSessionsOps(u.Name())
+		if err != nil {
+			return errors.Annotatef(err, "cannot set two-factor authentication secret of user %q", u.Name())
+		}
+		ops = append(ops, sessionOps...)
+	}
+	if err := u.st.runTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = fmt.Errorf("user no longer exists")
+		}
+		return errors.Annotatef(err, "cannot set two-factor authentication secret of user %q", u.Name())
+	}
+	u.doc.TOTPSecret = secret
+	return nil
+}
+
+// ErrTOTPCodeUsed is returned by UseTOTPStep when a one-time password
+// of the same or a later time step has already been accepted.
+var ErrTOTPCodeUsed = errors.New("two-factor authentication code already used")
+
+// UseTOTPStep records that a one-time password of the given time step
+// was accepted for the user, returning ErrTOTPCodeUsed if one of the
+// same or a later step already was. Concurrent logins cannot both use
+// the same code.
+func (u *User) UseTOTPStep(step int64) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if step <= u.doc.TOTPLastStep {
+			return nil, ErrTOTPCodeUsed
+		}
+		return []txn.Op{{
+			C:  usersC,
+			Id: u.Name(),
+			Assert: bson.D{{"$or", []bson.D{
+				{{"totplaststep", bson.D{{"$exists", false}}}},
+				{{"totplaststep", bson.D{{"$lt", step}}}},
+			}}},
+			Update: bson.D{{"$set", bson.D{{"totplaststep", step}}}},
+		}}, nil
+	}
+	if err := u.st.run(buildTxn); err == ErrTOTPCodeUsed {
+		return err
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot record two-factor authentication code of user %q", u.Name())
+	}
+	u.doc.TOTPLastStep = step
+	return nil
+}
+
+// TOTPSecret returns the time-based one-time password secret the user
+// is enrolled with for two-factor authentication, or "" if the user is
+// not enrolled.
+func (u *User) TOTPSecret() string {
+	return u.doc.TOTPSecret
+}
+
 // userList type is used to provide the methods for sorting.
 type userList []*User
 
@@ -138,6 +138,58 @@ func (s *UserSuite) TestDisable(c *gc.C) {
 	c.Assert(user.PasswordValid("a-password"), jc.IsTrue)
 }
 
+func (s *UserSuite) TestSetTOTPSecret(c *gc.C) {
+	user := s.factory.MakeUser(c, nil)
+	c.Assert(user.TOTPSecret(), gc.Equals, "")
+
+	err := user.SetTOTPSecret("GEZDGNBVGY3TQOJQ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, "GEZDGNBVGY3TQOJQ")
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, "GEZDGNBVGY3TQOJQ")
+
+	err = user.SetTOTPSecret("")
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.TOTPSecret(), gc.Equals, "")
+}
+
+func (s *UserSuite) TestUseTOTPStep(c *gc.C) {
+	user := s.factory.MakeUser(c, nil)
+	err := user.UseTOTPStep(100)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The same step, and earlier ones, cannot be used again.
+	err = user.UseTOTPStep(100)
+	c.Assert(err, gc.Equals, state.ErrTOTPCodeUsed)
+	err = user.UseTOTPStep(99)
+	c.Assert(err, gc.Equals, state.ErrTOTPCodeUsed)
+
+	// Nor through another copy of the user.
+	other, err := s.State.User(user.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = other.UseTOTPStep(100)
+	c.Assert(err, gc.Equals, state.ErrTOTPCodeUsed)
+
+	err = user.UseTOTPStep(101)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSuite) TestUseTOTPStepConcurrent(c *gc.C) {
+	user := s.factory.MakeUser(c, nil)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		other, err := s.State.User(user.UserTag())
+		c.Assert(err, jc.ErrorIsNil)
+		err = other.UseTOTPStep(100)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err := user.UseTOTPStep(100)
+	c.Assert(err, gc.Equals, state.ErrTOTPCodeUsed)
+}
+
 func (s *UserSuite) TestSetPasswordHash(c *gc.C) {
 	user := s.factory.MakeUser(c, nil)
 
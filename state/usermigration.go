@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// MigratedUser describes a user with access to an environment, as
+// exported from one state server so that the user's access can be
+// recreated on another when the environment moves between them.
+type MigratedUser struct {
+	// UserName is the canonical name of the user, such as
+	// "bob@local".
+	UserName    string
+	DisplayName string
+	CreatedBy   string
+	DateCreated time.Time
+
+	// Disabled records whether a local user is disabled. Credentials
+	// are never migrated: a local user created by an import has no
+	// password until one is set for it.
+	Disabled bool
+}
+
+// UserImport reports the outcome of importing a migrated user. Target
+// holds the name of the user on this state server. Created is true if
+// a local user had to be created. If Conflict is not empty, the user
+// was not given access to the environment, and Conflict says why.
+type UserImport struct {
+	UserName string
+	Target   string
+	Created  bool
+	Conflict string
+}
+
+// ExportEnvironmentUsers returns the users with access to the
+// environment, sorted by name.
+func (st *State) ExportEnvironmentUsers() ([]MigratedUser, error) {
+	env, err := st.Environment()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	envUsers, err := env.Users()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]MigratedUser, len(envUsers))
+	for i, envUser := range envUsers {
+		user := MigratedUser{
+			UserName:    envUser.UserName(),
+			DisplayName: envUser.DisplayName(),
+			CreatedBy:   envUser.CreatedBy(),
+			DateCreated: envUser.DateCreated(),
+		}
+		if tag := envUser.UserTag(); tag.IsLocal() {
+			var doc userDoc
+			if err := st.getUser(tag.Name(), &doc); err != nil {
+				return nil, errors.Annotatef(err, "cannot export user %q", tag.Username())
+			}
+			user.Disabled = doc.Deactivated
+		}
+		result[i] = user
+	}
+	sort.Sort(migratedUsers(result))
+	return result, nil
+}
+
+type migratedUsers []MigratedUser
+
+func (u migratedUsers) Len() int           { return len(u) }
+func (u migratedUsers) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u migratedUsers) Less(i, j int) bool { return u[i].UserName < u[j].UserName }
+
+// ImportEnvironmentUsers gives the migrated users access to the
+// environment. Users are imported under the names given in userMap, or
+// under their own names if they are not in it.
+//
+// A mapped local user must exist on this state server. An unmapped
+// local user is created if no user has its name; since credentials are
+// not migrated, an existing user with its name may be a different
+// person, and is reported as a conflict unless the user is mapped to
+// it. Users which already have access to the environment are left as
+// they are.
+func (st *State) ImportEnvironmentUsers(users []MigratedUser, userMap map[string]string) (_ []UserImport, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot import environment users")
+
+	result := make([]UserImport, len(users))
+	for i, user := range users {
+		if !names.IsValidUser(user.UserName) {
+			return nil, errors.Errorf("invalid user name %q", user.UserName)
+		}
+		target, mapped := userMap[user.UserName]
+		if !mapped {
+			target = user.UserName
+		} else if !names.IsValidUser(target) {
+			return nil, errors.Errorf("invalid user name %q for %q", target, user.UserName)
+		}
+		targetTag := names.NewUserTag(target)
+		result[i] = UserImport{
+			UserName: user.UserName,
+			Target:   targetTag.Username(),
+		}
+		createdBy := user.CreatedBy
+		if name, ok := userMap[createdBy]; ok {
+			createdBy = name
+		}
+		created, conflict, err := st.importUser(user, targetTag, createdBy, mapped)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot import user %q", user.UserName)
+		}
+		result[i].Created = created
+		result[i].Conflict = conflict
+	}
+	return result, nil
+}
+
+// importUser gives the migrated user access to the environment as the
+// target user, creating the target user first if needed. It returns
+// whether it created the user, or why the user could not be imported.
+func (st *State) importUser(user MigratedUser, target names.UserTag, createdBy string, mapped bool) (bool, string, error) {
+	if _, err := st.EnvironmentUser(target); err == nil {
+		return false, "", nil
+	} else if !errors.IsNotFound(err) {
+		return false, "", errors.Trace(err)
+	}
+	var ops []txn.Op
+	var created bool
+	displayName := user.DisplayName
+	if target.IsLocal() {
+		var doc userDoc
+		err := st.getUser(target.Name(), &doc)
+		switch {
+		case errors.IsNotFound(err) && mapped:
+			return false, fmt.Sprintf("user %q does not exist", target.Username()), nil
+		case errors.IsNotFound(err):
+			ops = append(ops, migratedUserOp(user, target, createdBy))
+			created = true
+		case err != nil:
+			return false, "", errors.Trace(err)
+		case doc.Deactivated:
+			return false, fmt.Sprintf("user %q is disabled", target.Username()), nil
+		case !mapped:
+			return false, fmt.Sprintf("user %q already exists", target.Username()), nil
+		default:
+			displayName = doc.DisplayName
+		}
+	}
+	op, doc := createEnvUserOpAndDoc(st.EnvironUUID(), target, names.NewUserTag(createdBy), displayName)
+	doc.DateCreated = user.DateCreated
+	ops = append(ops, op)
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return false, "", errors.Errorf("user %q changed during import", target.Username())
+	} else if err != nil {
+		return false, "", errors.Trace(err)
+	}
+	return created, "", nil
+}
+
+// migratedUserOp returns the operation that creates the local target
+// user from the migrated user, without a password.
+func migratedUserOp(user MigratedUser, target names.UserTag, createdBy string) txn.Op {
+	nameToLower := strings.ToLower(target.Name())
+	return txn.Op{
+		C:      usersC,
+		Id:     nameToLower,
+		Assert: txn.DocMissing,
+		Insert: &userDoc{
+			DocID:       nameToLower,
+			Name:        target.Name(),
+			DisplayName: user.DisplayName,
+			Deactivated: user.Disabled,
+			CreatedBy:   names.NewUserTag(createdBy).Name(),
+			DateCreated: user.DateCreated,
+		},
+	}
+}
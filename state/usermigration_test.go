@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserMigrationSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&UserMigrationSuite{})
+
+func (s *UserMigrationSuite) TestExportEnvironmentUsers(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{
+		Name:        "alice",
+		DisplayName: "Alice Allen",
+		NoEnvUser:   true,
+	}).UserTag()
+	bob := s.factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true, Disabled: true}).UserTag()
+	st := s.factory.MakeEnvironment(c, &factory.EnvParams{Name: "shared", Owner: alice})
+	defer st.Close()
+	_, err := st.AddEnvironmentUser(bob, alice, "")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.AddEnvironmentUser(names.NewUserTag("fred@external"), alice, "Fred")
+	c.Assert(err, jc.ErrorIsNil)
+
+	users, err := st.ExportEnvironmentUsers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 3)
+	c.Assert(users[0].UserName, gc.Equals, "alice@local")
+	c.Assert(users[0].DisplayName, gc.Equals, "Alice Allen")
+	c.Assert(users[0].Disabled, jc.IsFalse)
+	c.Assert(users[1].UserName, gc.Equals, "bob@local")
+	c.Assert(users[1].CreatedBy, gc.Equals, "alice@local")
+	c.Assert(users[1].Disabled, jc.IsTrue)
+	c.Assert(users[2], jc.DeepEquals, state.MigratedUser{
+		UserName:    "fred@external",
+		DisplayName: "Fred",
+		CreatedBy:   "alice@local",
+		DateCreated: users[2].DateCreated,
+	})
+}
+
+func (s *UserMigrationSuite) TestImportEnvironmentUsersCreatesUsers(c *gc.C) {
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	created := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	results, err := st.ImportEnvironmentUsers([]state.MigratedUser{{
+		UserName:    "carol@local",
+		DisplayName: "Carol Cole",
+		CreatedBy:   "admin@local",
+		DateCreated: created,
+	}, {
+		UserName:    "fred@external",
+		CreatedBy:   "admin@local",
+		DateCreated: created,
+	}}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.UserImport{
+		{UserName: "carol@local", Target: "carol@local", Created: true},
+		{UserName: "fred@external", Target: "fred@external"},
+	})
+
+	carol := names.NewUserTag("carol")
+	user, err := st.User(carol)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.DisplayName(), gc.Equals, "Carol Cole")
+	c.Assert(user.CreatedBy(), gc.Equals, "admin")
+	c.Assert(user.DateCreated(), gc.Equals, created)
+	// Credentials are not migrated, so carol cannot log in until a
+	// password is set.
+	c.Assert(user.PasswordValid(""), jc.IsFalse)
+	envUser, err := st.EnvironmentUser(carol)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.DisplayName(), gc.Equals, "Carol Cole")
+	c.Assert(envUser.DateCreated(), gc.Equals, created)
+	_, err = st.EnvironmentUser(names.NewUserTag("fred@external"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserMigrationSuite) TestImportEnvironmentUsersReusesMappedUser(c *gc.C) {
+	alice := s.factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+	users, err := s.State.ExportEnvironmentUsers()
+	c.Assert(err, jc.ErrorIsNil)
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+
+	// Existing users are only reused when the migrated users are
+	// mapped to them.
+	userMap := make(map[string]string)
+	for _, user := range users {
+		userMap[user.UserName] = user.UserName
+	}
+	results, err := st.ImportEnvironmentUsers(users, userMap)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, result := range results {
+		c.Check(result.Created, jc.IsFalse)
+		c.Check(result.Conflict, gc.Equals, "")
+	}
+	_, err = st.EnvironmentUser(alice)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Importing the users again leaves them as they are.
+	results, err = st.ImportEnvironmentUsers(users, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, len(users))
+	for _, result := range results {
+		c.Check(result.Conflict, gc.Equals, "")
+	}
+}
+
+func (s *UserMigrationSuite) TestImportEnvironmentUsersConflicts(c *gc.C) {
+	s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true})
+	s.factory.MakeUser(c, &factory.UserParams{Name: "bob", NoEnvUser: true, Disabled: true})
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	users := []state.MigratedUser{
+		{UserName: "alice@local", CreatedBy: "admin@local"},
+		{UserName: "bob@local", CreatedBy: "admin@local"},
+		{UserName: "dave@local", CreatedBy: "admin@local"},
+	}
+
+	results, err := st.ImportEnvironmentUsers(users, map[string]string{
+		"bob@local":  "bob",
+		"dave@local": "dan",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.UserImport{
+		{UserName: "alice@local", Target: "alice@local", Conflict: `user "alice@local" already exists`},
+		{UserName: "bob@local", Target: "bob@local", Conflict: `user "bob@local" is disabled`},
+		{UserName: "dave@local", Target: "dan@local", Conflict: `user "dan@local" does not exist`},
+	})
+	for _, name := range []string{"alice", "bob", "dan"} {
+		_, err := st.EnvironmentUser(names.NewUserTag(name))
+		c.Check(err, jc.Satisfies, errors.IsNotFound)
+	}
+}
+
+func (s *UserMigrationSuite) TestImportEnvironmentUsersMapped(c *gc.C) {
+	s.factory.MakeUser(c, &factory.UserParams{Name: "alice", NoEnvUser: true})
+	s.factory.MakeUser(c, &factory.UserParams{Name: "alison", DisplayName: "Alison", NoEnvUser: true})
+	st := s.factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	users := []state.MigratedUser{
+		{UserName: "alice@local", DisplayName: "Alice", CreatedBy: "admin@local"},
+	}
+
+	results, err := st.ImportEnvironmentUsers(users, map[string]string{"alice@local": "alison"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []state.UserImport{
+		{UserName: "alice@local", Target: "alison@local"},
+	})
+	envUser, err := st.EnvironmentUser(names.NewUserTag("alison"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(envUser.DisplayName(), gc.Equals, "Alison")
+	_, err = st.EnvironmentUser(names.NewUserTag("alice"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserMigrationSuite) TestImportEnvironmentUsersInvalidName(c *gc.C) {
+	_, err := s.State.ImportEnvironmentUsers([]state.MigratedUser{{UserName: "not/valid"}}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot import environment users: invalid user name "not/valid"`)
+}
@@ -0,0 +1,257 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const (
+	// SessionTokenLifetime is how long a session token can be used
+	// to log in before it must be refreshed.
+	SessionTokenLifetime = time.Hour
+
+	// MaxSessionLifetime is how long a session can be kept alive by
+	// refreshing its token, after which the user must log in with
+	// their password again.
+	MaxSessionLifetime = 7 * 24 * time.Hour
+)
+
+// sessionNow returns the current time; it is a variable so that tests
+// can move time forward.
+var sessionNow = time.Now
+
+// UserSession holds a token that lets a user log in without their
+// password until it expires.
+type UserSession struct {
+	// Token is the secret the user presents to log in.
+	Token string
+
+	// User is the user that logs in with the token.
+	User names.UserTag
+
+	// Expires is when the token can no longer be used, unless it
+	// has been refreshed.
+	Expires time.Time
+}
+
+// userSessionDoc records a session. Only a hash of the token is stored,
+// so that the tokens cannot be read from the database.
+type userSessionDoc struct {
+	DocID   string    `bson:"_id"`
+	User    string    `bson:"user"`
+	Created time.Time `bson:"created"`
+	Expires time.Time `bson:"expires"`
+}
+
+func newSessionToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Annotate(err, "cannot generate session token")
+	}
+	return base64.URLEncoding.EncodeToString(buf), nil
+}
+
+func sessionTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newUserSessionOp returns a new session token for the given user, with
+// the operation that records it.
+func newUserSessionOp(user names.UserTag, created, now time.Time) (*UserSession, txn.Op, error) {
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, txn.Op{}, errors.Trace(err)
+	}
+	expires := now.Add(SessionTokenLifetime)
+	if limit := created.Add(MaxSessionLifetime); expires.After(limit) {
+		expires = limit
+	}
+	op := txn.Op{
+		C:      userSessionsC,
+		Id:     sessionTokenHash(token),
+		Assert: txn.DocMissing,
+		Insert: &userSessionDoc{
+			User:    user.Name(),
+			Created: created,
+			Expires: expires,
+		},
+	}
+	return &UserSession{Token: token, User: user, Expires: expires}, op, nil
+}
+
+// AddUserSession starts a session for the given local user, returning
+// the token that the user can log in with in place of their password.
+func (st *State) AddUserSession(user names.UserTag) (*UserSession, error) {
+	if !user.IsLocal() {
+		return nil, errors.Errorf("cannot add session for non-local user %q", user.Username())
+	}
+	now := sessionNow()
+	session, op, err := newUserSessionOp(user, now, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     strings.ToLower(user.Name()),
+		Assert: txn.DocExists,
+	}, op}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		return nil, errors.NotFoundf("user %q", user.Username())
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot add session for user %q", user.Username())
+	}
+	if err := st.pruneUserSessions(); err != nil {
+		logger.Warningf("%v", err)
+	}
+	return session, nil
+}
+
+// userSession returns the document of the unexpired session with the
+// given token.
+func (st *State) userSession(token string) (*userSessionDoc, error) {
+	sessions, closer := st.getCollection(userSessionsC)
+	defer closer()
+
+	var doc userSessionDoc
+	err := sessions.FindId(sessionTokenHash(token)).One(&doc)
+	if err == mgo.ErrNotFound || err == nil && !sessionNow().Before(doc.Expires) {
+		return nil, errors.NotFoundf("session")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot get session")
+	}
+	return &doc, nil
+}
+
+// UserSession returns the unexpired session with the given token.
+func (st *State) UserSession(token string) (*UserSession, error) {
+	doc, err := st.userSession(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &UserSession{
+		Token:   token,
+		User:    names.NewLocalUserTag(doc.User),
+		Expires: doc.Expires,
+	}, nil
+}
+
+// RefreshUserSession replaces the given unexpired session token with a
+// new one, extending the session by SessionTokenLifetime up to
+// MaxSessionLifetime. The old token can no longer be used.
+func (st *State) RefreshUserSession(token string) (*UserSession, error) {
+	doc, err := st.userSession(token)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := sessionNow()
+	if !now.Before(doc.Created.Add(MaxSessionLifetime)) {
+		return nil, errors.NotFoundf("session")
+	}
+	session, op, err := newUserSessionOp(names.NewLocalUserTag(doc.User), doc.Created, now)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      userSessionsC,
+		Id:     sessionTokenHash(token),
+		Assert: txn.DocExists,
+		Remove: true,
+	}, op}
+	if err := st.runTransaction(ops); err == txn.ErrAborted {
+		// The session was refreshed or ended concurrently.
+		return nil, errors.NotFoundf("session")
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot refresh session")
+	}
+	return session, nil
+}
+
+// RemoveUserSession ends the session with the given token, so that it
+// can no longer be used to log in. Removing a session that does not
+// exist is not an error.
+func (st *State) RemoveUserSession(token string) error {
+	ops := []txn.Op{{
+		C:      userSessionsC,
+		Id:     sessionTokenHash(token),
+		Remove: true,
+	}}
+	return errors.Annotate(st.runTransaction(ops), "cannot remove session")
+}
+
+// removeUserSessionsOps returns the operations that remove all the
+// sessions of the named user, so that they must log in again after
+// their credentials change.
+func (st *State) removeUserSessionsOps(userName string) ([]txn.Op, error) {
+	sessions, closer := st.getCollection(userSessionsC)
+	defer closer()
+
+	var docs []userSessionDoc
+	if err := sessions.Find(bson.D{{"user", userName}}).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read sessions")
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      userSessionsC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
+
+// pruneUserSessions removes the sessions whose tokens have expired, so
+// that they do not accumulate.
+func (st *State) pruneUserSessions() error {
+	sessions, closer := st.getCollection(userSessionsC)
+	defer closer()
+
+	var docs []userSessionDoc
+	sel := bson.D{{"expires", bson.D{{"$lte", sessionNow()}}}}
+	if err := sessions.Find(sel).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return errors.Annotate(err, "cannot read sessions")
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      userSessionsC,
+			Id:     doc.DocID,
+			Remove: true,
+		}
+	}
+	return errors.Annotate(st.runTransaction(ops), "cannot prune sessions")
+}
+
+// SessionValid reports whether the given token is that of an unexpired
+// session of the user. As with PasswordValid, a disabled user's
+// sessions are never valid.
+func (u *User) SessionValid(token string) bool {
+	if u.IsDisabled() || token == "" {
+		return false
+	}
+	session, err := u.st.UserSession(token)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Warningf("cannot check session of user %q: %v", u.Name(), err)
+		}
+		return false
+	}
+	return session.User.Name() == u.Name()
+}
@@ -0,0 +1,164 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type UserSessionSuite struct {
+	ConnSuite
+	now  time.Time
+	user names.UserTag
+}
+
+var _ = gc.Suite(&UserSessionSuite{})
+
+func (s *UserSessionSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.now = time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(state.SessionNow, func() time.Time { return s.now })
+	s.user = s.factory.MakeUser(c, &factory.UserParams{Name: "alice"}).UserTag()
+}
+
+func (s *UserSessionSuite) TestAddUserSession(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(session.Token, gc.Not(gc.Equals), "")
+	c.Assert(session.User, gc.Equals, s.user)
+	c.Assert(session.Expires, gc.Equals, s.now.Add(state.SessionTokenLifetime))
+
+	found, err := s.State.UserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, session)
+
+	_, err = s.State.UserSession("not-a-token")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserSessionSuite) TestAddUserSessionNoUser(c *gc.C) {
+	_, err := s.State.AddUserSession(names.NewLocalUserTag("nobody"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.AddUserSession(names.NewUserTag("fred@external"))
+	c.Assert(err, gc.ErrorMatches, `cannot add session for non-local user "fred@external"`)
+}
+
+func (s *UserSessionSuite) TestSessionExpires(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	s.now = session.Expires
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.RefreshUserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserSessionSuite) TestRefreshUserSession(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	s.now = s.now.Add(50 * time.Minute)
+	refreshed, err := s.State.RefreshUserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(refreshed.Token, gc.Not(gc.Equals), session.Token)
+	c.Assert(refreshed.Expires, gc.Equals, s.now.Add(state.SessionTokenLifetime))
+
+	// The old token no longer works.
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	found, err := s.State.UserSession(refreshed.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found.User, gc.Equals, s.user)
+}
+
+func (s *UserSessionSuite) TestRefreshLimitedByMaxLifetime(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	limit := s.now.Add(state.MaxSessionLifetime)
+	token := session.Token
+	for s.now.Add(state.SessionTokenLifetime).Before(limit) {
+		s.now = s.now.Add(state.SessionTokenLifetime - time.Minute)
+		session, err = s.State.RefreshUserSession(token)
+		c.Assert(err, jc.ErrorIsNil)
+		token = session.Token
+	}
+	c.Assert(session.Expires, gc.Equals, limit)
+	s.now = limit
+	_, err = s.State.RefreshUserSession(token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *UserSessionSuite) TestRemoveUserSession(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveUserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing it again is not an error.
+	err = s.State.RemoveUserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSessionSuite) TestSessionValid(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	user, err := s.State.User(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SessionValid(session.Token), jc.IsTrue)
+	c.Assert(user.SessionValid(""), jc.IsFalse)
+
+	other := s.factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	c.Assert(other.SessionValid(session.Token), jc.IsFalse)
+
+	err = user.Disable()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(user.SessionValid(session.Token), jc.IsFalse)
+}
+
+func (s *UserSessionSuite) TestSetPasswordRemovesSessions(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	otherSession, err := s.State.AddUserSession(other.UserTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	user, err := s.State.User(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetPassword("new-password")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The sessions of other users are kept.
+	_, err = s.State.UserSession(otherSession.Token)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *UserSessionSuite) TestSetTOTPSecretRemovesSessions(c *gc.C) {
+	session, err := s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	user, err := s.State.User(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetTOTPSecret("JBSWY3DPEHPK3PXP")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing the secret does not end the new sessions.
+	session, err = s.State.AddUserSession(s.user)
+	c.Assert(err, jc.ErrorIsNil)
+	err = user.SetTOTPSecret("")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UserSession(session.Token)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -43,6 +43,11 @@ type Volume interface {
 	// if it has not already been provisioned. Params returns true if the
 	// returned parameters are usable for provisioning, otherwise false.
 	Params() (VolumeParams, bool)
+
+	// Releasing reports whether the volume is to be released, rather
+	// than destroyed, once it is Dead: detached and left in the cloud,
+	// and only removed from state.
+	Releasing() bool
 }
 
 // VolumeAttachment describes an attachment of a volume to a machine.
@@ -86,6 +91,7 @@ type volumeDoc struct {
 	StorageId string        `bson:"storageid,omitempty"`
 	Info      *VolumeInfo   `bson:"info,omitempty"`
 	Params    *VolumeParams `bson:"params,omitempty"`
+	Releasing bool          `bson:"releasing,omitempty"`
 }
 
 // volumeAttachmentDoc records information about a volume attachment.
@@ -131,6 +137,11 @@ type VolumeAttachmentParams struct {
 	ReadOnly bool `bson:"read-only"`
 }
 
+// Releasing is required to implement Volume.
+func (v *volume) Releasing() bool {
+	return v.doc.Releasing
+}
+
 // Tag is required to implement Entity.
 func (v *volume) Tag() names.Tag {
 	return v.VolumeTag()
@@ -236,6 +247,32 @@ func (st *State) PersistentVolumes() ([]Volume, error) {
 	return v, nil
 }
 
+// destroyPersistentVolumesOps returns txn.Ops to make every alive
+// persistent volume Dying, and to record whether it is to be released
+// or destroyed once Dead.
+func (st *State) destroyPersistentVolumesOps(releasing bool) ([]txn.Op, error) {
+	volumes, err := st.PersistentVolumes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ops []txn.Op
+	for _, v := range volumes {
+		if v.Life() != Alive {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      volumesC,
+			Id:     v.VolumeTag().Id(),
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{
+				{"life", Dying},
+				{"releasing", releasing},
+			}}},
+		})
+	}
+	return ops, nil
+}
+
 // StorageInstanceVolume returns the Volume assigned to the specified
 // storage instance.
 func (st *State) StorageInstanceVolume(tag names.StorageTag) (Volume, error) {
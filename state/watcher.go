@@ -361,6 +361,13 @@ func (st *State) WatchEnvironMachines() StringsWatcher {
 	return newLifecycleWatcher(st, machinesC, members, filter, nil)
 }
 
+// WatchMachineStatuses returns a StringsWatcher that notifies of the ids
+// of the machines and containers in the environment whose statuses
+// change, so that those stuck in provisioning errors can be found.
+func (st *State) WatchMachineStatuses() StringsWatcher {
+	return newMachineStatusWatcher(st)
+}
+
 // WatchContainers returns a StringsWatcher that notifies of changes to the
 // lifecycles of containers of the specified type on a machine.
 func (m *Machine) WatchContainers(ctype instance.ContainerType) StringsWatcher {
@@ -577,6 +584,106 @@ func newMinUnitsWatcher(st *State) StringsWatcher {
 	return w
 }
 
+// machineStatusWatcher notifies of the ids of machines whose status
+// documents change.
+type machineStatusWatcher struct {
+	commonWatcher
+	out chan []string
+}
+
+var _ StringsWatcher = (*machineStatusWatcher)(nil)
+
+func newMachineStatusWatcher(st *State) StringsWatcher {
+	w := &machineStatusWatcher{
+		commonWatcher: commonWatcher{st: st},
+		out:           make(chan []string),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the machineStatusWatcher.
+func (w *machineStatusWatcher) Changes() <-chan []string {
+	return w.out
+}
+
+// machineId returns the id of the machine whose status document has the
+// given local key, or false if the key does not belong to a machine.
+func (w *machineStatusWatcher) machineId(localKey string) (string, bool) {
+	prefix := machineGlobalKey("")
+	if !strings.HasPrefix(localKey, prefix) {
+		return "", false
+	}
+	id := localKey[len(prefix):]
+	return id, names.IsValidMachine(id)
+}
+
+func (w *machineStatusWatcher) initial() (set.Strings, error) {
+	statuses, closer := w.st.getCollection(statusesC)
+	defer closer()
+
+	ids := make(set.Strings)
+	var doc struct {
+		DocID string `bson:"_id"`
+	}
+	query := bson.D{{"_id", bson.D{{"$regex", "^" + w.st.docID(machineGlobalKey(""))}}}}
+	iter := statuses.Find(query).Select(bson.D{{"_id", 1}}).Iter()
+	for iter.Next(&doc) {
+		if id, ok := w.machineId(w.st.localID(doc.DocID)); ok {
+			ids.Add(id)
+		}
+	}
+	return ids, iter.Close()
+}
+
+func (w *machineStatusWatcher) loop() error {
+	in := make(chan watcher.Change)
+	filter := func(key interface{}) bool {
+		k, err := w.st.strictLocalID(key.(string))
+		if err != nil {
+			return false
+		}
+		_, ok := w.machineId(k)
+		return ok
+	}
+	w.st.watcher.WatchCollectionWithFilter(statusesC, in, filter)
+	defer w.st.watcher.UnwatchCollection(statusesC, in)
+
+	ids, err := w.initial()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-in:
+			updates, ok := collect(ch, in, w.tomb.Dying())
+			if !ok {
+				return tomb.ErrDying
+			}
+			for key := range updates {
+				if id, ok := w.machineId(w.st.localID(key.(string))); ok {
+					ids.Add(id)
+				}
+			}
+			if len(ids) > 0 {
+				out = w.out
+			}
+		case out <- ids.SortedValues():
+			ids = make(set.Strings)
+			out = nil
+		}
+	}
+}
+
 // WatchMinUnits returns a StringsWatcher for the minUnits collection
 func (st *State) WatchMinUnits() StringsWatcher {
 	return newMinUnitsWatcher(st)
@@ -1404,6 +1511,17 @@ func (st *State) WatchForEnvironConfigChanges() NotifyWatcher {
 	return newEntityWatcher(st, settingsC, st.docID(environGlobalKey))
 }
 
+// WatchAgentVersion returns a NotifyWatcher that notifies when the
+// version the environment's agents should run may have changed: when
+// the environment's config changes, or a staged agent upgrade
+// progresses.
+func (st *State) WatchAgentVersion() NotifyWatcher {
+	return newDocWatcher(st, []docKey{
+		{settingsC, st.docID(environGlobalKey)},
+		{agentRolloutsC, st.docID(currentAgentRolloutId)},
+	})
+}
+
 // WatchAPIHostPorts returns a NotifyWatcher that notifies
 // when the set of API addresses changes.
 func (st *State) WatchAPIHostPorts() NotifyWatcher {
@@ -1772,6 +1890,103 @@ func (w *machineAddressesWatcher) loop() error {
 	}
 }
 
+// unitAddressesWatcher notifies about changes to the addresses of a
+// unit's machine, and to the address policy of the unit's service,
+// either of which may change the unit's public address.
+type unitAddressesWatcher struct {
+	commonWatcher
+	machine *Machine
+	service *Service
+	out     chan struct{}
+}
+
+var _ Watcher = (*unitAddressesWatcher)(nil)
+
+// WatchAddresses returns a new NotifyWatcher watching the addresses of
+// u's assigned machine and the address policy of its service.
+func (u *Unit) WatchAddresses() (NotifyWatcher, error) {
+	machineId, err := u.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine, err := u.st.Machine(machineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	service, err := u.Service()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &unitAddressesWatcher{
+		commonWatcher: commonWatcher{st: u.st},
+		out:           make(chan struct{}),
+		machine:       machine,
+		service:       service,
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w, nil
+}
+
+// Changes returns the event channel for w.
+func (w *unitAddressesWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *unitAddressesWatcher) loop() error {
+	machines, closer := w.st.getCollection(machinesC)
+	machineRevno, err := getTxnRevno(machines, w.machine.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	services, closer := w.st.getCollection(servicesC)
+	serviceRevno, err := getTxnRevno(services, w.service.doc.DocID)
+	closer()
+	if err != nil {
+		return err
+	}
+	machineCh := make(chan watcher.Change)
+	w.st.watcher.Watch(machinesC, w.machine.doc.DocID, machineRevno, machineCh)
+	defer w.st.watcher.Unwatch(machinesC, w.machine.doc.DocID, machineCh)
+	serviceCh := make(chan watcher.Change)
+	w.st.watcher.Watch(servicesC, w.service.doc.DocID, serviceRevno, serviceCh)
+	defer w.st.watcher.Unwatch(servicesC, w.service.doc.DocID, serviceCh)
+	addresses := w.machine.Addresses()
+	policy := w.service.AddressPolicy()
+	out := w.out
+	for {
+		select {
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-machineCh:
+			if err := w.machine.Refresh(); err != nil {
+				return err
+			}
+			newAddresses := w.machine.Addresses()
+			if !addressesEqual(newAddresses, addresses) {
+				addresses = newAddresses
+				out = w.out
+			}
+		case <-serviceCh:
+			if err := w.service.Refresh(); err != nil {
+				return err
+			}
+			if newPolicy := w.service.AddressPolicy(); newPolicy != policy {
+				policy = newPolicy
+				out = w.out
+			}
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // cleanupWatcher notifies of changes in the cleanups collection.
 type cleanupWatcher struct {
 	commonWatcher
@@ -0,0 +1,255 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// The events of which webhooks may be notified.
+const (
+	// WebhookHookFailed is the event raised when a hook of a unit
+	// fails.
+	WebhookHookFailed = "hook-failed"
+
+	// WebhookMachineDown is the event raised when the agent of a
+	// machine stops communicating with the state servers.
+	WebhookMachineDown = "machine-down"
+
+	// WebhookServiceScaled is the event raised when the number of
+	// units of a service is changed through the Scale facade.
+	WebhookServiceScaled = "service-scaled"
+
+	// WebhookUpgradeComplete is the event raised when every machine
+	// agent in the environment is running the environment's agent
+	// version after it changed.
+	WebhookUpgradeComplete = "upgrade-complete"
+)
+
+// WebhookEvents holds all the events of which webhooks may be
+// notified.
+var WebhookEvents = set.NewStrings(
+	WebhookHookFailed,
+	WebhookMachineDown,
+	WebhookServiceScaled,
+	WebhookUpgradeComplete,
+)
+
+// Webhook represents a URL to which notifications of events in the
+// environment are posted.
+type Webhook struct {
+	st  *State
+	doc webhookDoc
+}
+
+type webhookDoc struct {
+	DocID   string   `bson:"_id"`
+	EnvUUID string   `bson:"env-uuid"`
+	Id      string   `bson:"id"`
+	URL     string   `bson:"url"`
+	Secret  string   `bson:"secret"`
+	Events  []string `bson:"events"`
+}
+
+// Id returns the id of the webhook, unique within the environment.
+func (w *Webhook) Id() string {
+	return w.doc.Id
+}
+
+// URL returns the URL to which notifications are posted.
+func (w *Webhook) URL() string {
+	return w.doc.URL
+}
+
+// Secret returns the secret used to sign the notifications, or the
+// empty string if they are not signed.
+func (w *Webhook) Secret() string {
+	return w.doc.Secret
+}
+
+// Events returns the events of which the webhook is notified. No
+// events means all events.
+func (w *Webhook) Events() []string {
+	return w.doc.Events
+}
+
+// Wants reports whether the webhook is notified of the given event.
+func (w *Webhook) Wants(event string) bool {
+	if len(w.doc.Events) == 0 {
+		return true
+	}
+	for _, e := range w.doc.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// privateNetworks holds the networks of private addresses, to which
+// notifications may not be posted.
+var privateNetworks = []*net.IPNet{
+	mustParseCIDR("10.0.0.0/8"),
+	mustParseCIDR("172.16.0.0/12"),
+	mustParseCIDR("192.168.0.0/16"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("fc00::/7"),
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return ipNet
+}
+
+// WebhookAddressAllowed reports whether notifications may be posted to
+// the given address. Loopback, link-local, unspecified and private
+// addresses are not allowed, so that webhooks cannot be used to reach
+// the state servers or the networks they are on.
+func WebhookAddressAllowed(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, ipNet := range privateNetworks {
+		if ipNet.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// webhookHostAllowed reports whether the host of a webhook URL, with
+// any port, may be posted to. Host names are resolved only when
+// notifications are posted.
+func webhookHostAllowed(hostPort string) bool {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host = strings.Trim(hostPort, "[]")
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return WebhookAddressAllowed(ip)
+	}
+	return true
+}
+
+// AddWebhook records that notifications of the given events are to be
+// posted to the given URL, signed with the given secret if it is not
+// empty. No events means all events.
+func (st *State) AddWebhook(hookURL, secret string, events []string) (_ *Webhook, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add webhook %q", hookURL)
+
+	u, err := url.Parse(hookURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.NotValidf("URL %q", hookURL)
+	}
+	if !webhookHostAllowed(u.Host) {
+		return nil, errors.NotValidf("URL %q on a loopback, link-local or private address", hookURL)
+	}
+	for _, event := range events {
+		if !WebhookEvents.Contains(event) {
+			return nil, errors.NotValidf("event %q", event)
+		}
+	}
+	seq, err := st.sequence("webhook")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	doc := &webhookDoc{
+		DocID:   st.docID(id),
+		EnvUUID: st.EnvironUUID(),
+		Id:      id,
+		URL:     hookURL,
+		Secret:  secret,
+		Events:  set.NewStrings(events...).SortedValues(),
+	}
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     doc.DocID,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}
+	if err := st.runTransaction(ops); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Webhook{st: st, doc: *doc}, nil
+}
+
+// Webhook returns the webhook with the given id.
+func (st *State) Webhook(id string) (*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	webhook := &Webhook{st: st}
+	err := webhooks.FindId(st.docID(id)).One(&webhook.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("webhook %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get webhook %q", id)
+	}
+	return webhook, nil
+}
+
+// Webhooks returns all the webhooks of the environment, in the order
+// in which they were added.
+func (st *State) Webhooks() ([]*Webhook, error) {
+	webhooks, closer := st.getCollection(webhooksC)
+	defer closer()
+
+	var docs []webhookDoc
+	if err := webhooks.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get webhooks")
+	}
+	result := make([]*Webhook, len(docs))
+	for i, doc := range docs {
+		result[i] = &Webhook{st: st, doc: doc}
+	}
+	sort.Sort(webhooksById(result))
+	return result, nil
+}
+
+// RemoveWebhook removes the webhook with the given id.
+func (st *State) RemoveWebhook(id string) error {
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	err := st.runTransaction(ops)
+	if err == txn.ErrAborted {
+		err = errors.NotFoundf("webhook %q", id)
+	}
+	return errors.Trace(err)
+}
+
+type webhooksById []*Webhook
+
+func (w webhooksById) Len() int      { return len(w) }
+func (w webhooksById) Swap(i, j int) { w[i], w[j] = w[j], w[i] }
+func (w webhooksById) Less(i, j int) bool {
+	// Ids are sequence numbers, so compare them numerically.
+	a, _ := strconv.Atoi(w[i].doc.Id)
+	b, _ := strconv.Atoi(w[j].doc.Id)
+	return a < b
+}
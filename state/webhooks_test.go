@@ -0,0 +1,116 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type WebhooksSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&WebhooksSuite{})
+
+func (s *WebhooksSuite) TestAddWebhook(c *gc.C) {
+	webhook, err := s.State.AddWebhook("https://example.com/hook", "s3cret", []string{
+		state.WebhookMachineDown, state.WebhookHookFailed, state.WebhookMachineDown,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Id(), gc.Equals, "0")
+	c.Assert(webhook.URL(), gc.Equals, "https://example.com/hook")
+	c.Assert(webhook.Secret(), gc.Equals, "s3cret")
+	c.Assert(webhook.Events(), jc.DeepEquals, []string{"hook-failed", "machine-down"})
+	c.Assert(webhook.Wants(state.WebhookHookFailed), jc.IsTrue)
+	c.Assert(webhook.Wants(state.WebhookUpgradeComplete), jc.IsFalse)
+
+	webhook, err = s.State.Webhook("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.URL(), gc.Equals, "https://example.com/hook")
+	c.Assert(webhook.Events(), jc.DeepEquals, []string{"hook-failed", "machine-down"})
+}
+
+func (s *WebhooksSuite) TestAddWebhookAllEvents(c *gc.C) {
+	webhook, err := s.State.AddWebhook("http://example.com/hook", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhook.Events(), gc.HasLen, 0)
+	for _, event := range state.WebhookEvents.Values() {
+		c.Check(webhook.Wants(event), jc.IsTrue)
+	}
+}
+
+func (s *WebhooksSuite) TestAddWebhookInvalid(c *gc.C) {
+	_, err := s.State.AddWebhook("ftp://example.com/hook", "", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add webhook "ftp://example.com/hook": URL "ftp://example.com/hook" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = s.State.AddWebhook("http://example.com/hook", "", []string{"party"})
+	c.Assert(err, gc.ErrorMatches, `cannot add webhook "http://example.com/hook": event "party" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WebhooksSuite) TestAddWebhookPrivateAddress(c *gc.C) {
+	for i, hookURL := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.3.1/hook",
+		"https://172.20.0.1:17070/hook",
+		"http://192.168.1.1/hook",
+		"http://[fd00::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		c.Logf("test %d: %s", i, hookURL)
+		_, err := s.State.AddWebhook(hookURL, "", nil)
+		c.Check(err, gc.ErrorMatches, `cannot add webhook ".*": URL ".*" on a loopback, link-local or private address not valid`)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+	_, err := s.State.AddWebhook("http://8.8.8.8:8080/hook", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WebhooksSuite) TestWebhooks(c *gc.C) {
+	webhooks, err := s.State.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhooks, gc.HasLen, 0)
+
+	for i := 0; i < 12; i++ {
+		_, err := s.State.AddWebhook("http://example.com/hook", "", nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	webhooks, err = s.State.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhooks, gc.HasLen, 12)
+	c.Assert(webhooks[2].Id(), gc.Equals, "2")
+	c.Assert(webhooks[11].Id(), gc.Equals, "11")
+}
+
+func (s *WebhooksSuite) TestRemoveWebhook(c *gc.C) {
+	webhook, err := s.State.AddWebhook("http://example.com/hook", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RemoveWebhook(webhook.Id())
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Webhook(webhook.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.State.RemoveWebhook(webhook.Id())
+	c.Assert(err, gc.ErrorMatches, `webhook "0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *WebhooksSuite) TestWebhooksAreEnvironmentSpecific(c *gc.C) {
+	_, err := s.State.AddWebhook("http://example.com/hook", "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	webhooks, err := st.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(webhooks, gc.HasLen, 0)
+}
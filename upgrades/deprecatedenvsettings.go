@@ -3,17 +3,20 @@
 
 package upgrades
 
+// removedEnvSettings holds the environment settings that are no longer
+// supported, and which are removed from the environment config.
+var removedEnvSettings = []string{
+	"public-bucket",
+	"public-bucket-region",
+	"public-bucket-url",
+	"default-image-id",
+	"default-instance-type",
+	"shared-storage-port",
+	"tools-url",
+}
+
 func processDeprecatedEnvSettings(context Context) error {
 	st := context.State()
-	removeAttrs := []string{
-		"public-bucket",
-		"public-bucket-region",
-		"public-bucket-url",
-		"default-image-id",
-		"default-instance-type",
-		"shared-storage-port",
-		"tools-url",
-	}
 	// TODO (wallyworld) - delete lxc-use-clone in 1.22
-	return st.UpdateEnvironConfig(map[string]interface{}{}, removeAttrs, nil)
+	return st.UpdateEnvironConfig(map[string]interface{}{}, removedEnvSettings, nil)
 }
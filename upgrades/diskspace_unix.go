@@ -0,0 +1,22 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package upgrades
+
+import (
+	"syscall"
+
+	"github.com/juju/errors"
+)
+
+// freeDiskSpace returns the number of bytes available to unprivileged
+// users on the filesystem holding the given path.
+var freeDiskSpace = func(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
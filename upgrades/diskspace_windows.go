@@ -0,0 +1,15 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build windows
+
+package upgrades
+
+import "github.com/juju/errors"
+
+// freeDiskSpace returns the number of bytes available on the
+// filesystem holding the given path. State servers do not run on
+// windows, so it is not supported there.
+var freeDiskSpace = func(path string) (uint64, error) {
+	return 0, errors.NotSupportedf("checking free disk space on windows")
+}
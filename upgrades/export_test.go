@@ -15,6 +15,8 @@ var (
 	NewStateStorage           = &newStateStorage
 	StateToolsStorage         = &stateToolsStorage
 	AddAZToInstData           = &addAZToInstData
+	FreeDiskSpace             = &freeDiskSpace
+	PreUpgradeChecksRegistry  = &preUpgradeChecks
 
 	ChownPath      = &chownPath
 	IsLocalEnviron = &isLocalEnviron
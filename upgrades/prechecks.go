@@ -0,0 +1,228 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/version"
+)
+
+// PreUpgradeCheck holds a check that is made before the environment's
+// agents are upgraded, to catch problems that would otherwise cause
+// the upgrade to fail part way through.
+type PreUpgradeCheck struct {
+	// Name identifies the check.
+	Name string
+
+	// Description is a human readable description of what the check
+	// verifies.
+	Description string
+
+	// Run makes the check, returning an error describing why the
+	// upgrade should not go ahead.
+	Run func(PreUpgradeParams) error
+}
+
+// PreUpgradeParams holds the information available to pre-upgrade
+// checks.
+type PreUpgradeParams struct {
+	// State is the environment's state.
+	State *state.State
+
+	// DataDir is the data directory of the state server making the
+	// checks.
+	DataDir string
+
+	// TargetVersion is the version to which the environment's agents
+	// are to be upgraded.
+	TargetVersion version.Number
+}
+
+// PreUpgradeCheckResult holds the outcome of a single pre-upgrade
+// check.
+type PreUpgradeCheckResult struct {
+	Name        string
+	Description string
+	Error       error
+}
+
+var preUpgradeChecks []PreUpgradeCheck
+
+// RegisterPreUpgradeCheck adds a check to those made by
+// RunPreUpgradeChecks. It panics if a check with the same name is
+// already registered.
+func RegisterPreUpgradeCheck(check PreUpgradeCheck) {
+	for _, existing := range preUpgradeChecks {
+		if existing.Name == check.Name {
+			panic(fmt.Sprintf("pre-upgrade check %q already registered", check.Name))
+		}
+	}
+	preUpgradeChecks = append(preUpgradeChecks, check)
+}
+
+// PreUpgradeChecks returns the registered pre-upgrade checks, in the
+// order in which they are made.
+func PreUpgradeChecks() []PreUpgradeCheck {
+	checks := make([]PreUpgradeCheck, len(preUpgradeChecks))
+	copy(checks, preUpgradeChecks)
+	return checks
+}
+
+// RunPreUpgradeChecks makes every registered pre-upgrade check and
+// returns their results. A check that fails does not prevent the
+// remaining checks from being made.
+func RunPreUpgradeChecks(args PreUpgradeParams) []PreUpgradeCheckResult {
+	results := make([]PreUpgradeCheckResult, len(preUpgradeChecks))
+	for i, check := range preUpgradeChecks {
+		logger.Debugf("running pre-upgrade check %q", check.Name)
+		results[i] = PreUpgradeCheckResult{
+			Name:        check.Name,
+			Description: check.Description,
+			Error:       check.Run(args),
+		}
+	}
+	return results
+}
+
+func init() {
+	RegisterPreUpgradeCheck(PreUpgradeCheck{
+		Name:        "disk-space",
+		Description: "state server has enough free disk space",
+		Run:         checkDiskSpace,
+	})
+	RegisterPreUpgradeCheck(PreUpgradeCheck{
+		Name:        "mongo-version",
+		Description: "mongo server version is supported",
+		Run:         checkMongoVersion,
+	})
+	RegisterPreUpgradeCheck(PreUpgradeCheck{
+		Name:        "orphaned-documents",
+		Description: "units refer to existing machines and principals",
+		Run:         checkOrphanedDocuments,
+	})
+	RegisterPreUpgradeCheck(PreUpgradeCheck{
+		Name:        "deprecated-config",
+		Description: "environment config has no deprecated settings",
+		Run:         checkDeprecatedConfig,
+	})
+}
+
+// minFreeDiskSpace is the free space, in bytes, needed in the data
+// directory of the state server for the upgrade's database changes
+// and the new agent binaries.
+var minFreeDiskSpace uint64 = 1 << 30
+
+func checkDiskSpace(args PreUpgradeParams) error {
+	free, err := freeDiskSpace(args.DataDir)
+	if errors.IsNotSupported(err) {
+		logger.Debugf("not checking free disk space: %v", err)
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot determine free disk space")
+	}
+	if free < minFreeDiskSpace {
+		return errors.Errorf(
+			"only %dMiB free in %s, at least %dMiB needed",
+			free>>20, args.DataDir, minFreeDiskSpace>>20,
+		)
+	}
+	return nil
+}
+
+// minMongoVersion is the oldest mongo server version that supports
+// the upgrade steps.
+var minMongoVersion = []int{2, 4}
+
+func checkMongoVersion(args PreUpgradeParams) error {
+	session := args.State.MongoSession().Copy()
+	defer session.Close()
+	info, err := session.BuildInfo()
+	if err != nil {
+		return errors.Annotate(err, "cannot get mongo server version")
+	}
+	if !info.VersionAtLeast(minMongoVersion...) {
+		return errors.Errorf("mongo server version %s is too old", info.Version)
+	}
+	return nil
+}
+
+func checkOrphanedDocuments(args PreUpgradeParams) error {
+	st := args.State
+	services, err := st.AllServices()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var orphaned []string
+	for _, service := range services {
+		units, err := service.AllUnits()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, unit := range units {
+			if principal, ok := unit.PrincipalName(); ok {
+				_, err := st.Unit(principal)
+				if errors.IsNotFound(err) {
+					orphaned = append(orphaned, fmt.Sprintf("unit %s (principal %s not found)", unit, principal))
+				} else if err != nil {
+					return errors.Trace(err)
+				}
+				continue
+			}
+			id, err := unit.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+			_, err = st.Machine(id)
+			if errors.IsNotFound(err) {
+				orphaned = append(orphaned, fmt.Sprintf("unit %s (machine %s not found)", unit, id))
+			} else if err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	if len(orphaned) > 0 {
+		return errors.Errorf("orphaned documents found: %s", strings.Join(orphaned, ", "))
+	}
+	return nil
+}
+
+// replacedEnvSettings maps deprecated environment settings to the
+// settings that replace them.
+var replacedEnvSettings = map[string]string{
+	config.LxcUseClone:            config.LxcClone,
+	config.ProvisionerSafeModeKey: config.ProvisionerHarvestModeKey,
+}
+
+func checkDeprecatedConfig(args PreUpgradeParams) error {
+	cfg, err := args.State.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	attrs := cfg.AllAttrs()
+	var found []string
+	for _, key := range removedEnvSettings {
+		if _, ok := attrs[key]; ok {
+			found = append(found, fmt.Sprintf("%s (no longer supported)", key))
+		}
+	}
+	for key, replacement := range replacedEnvSettings {
+		if _, ok := attrs[key]; ok {
+			found = append(found, fmt.Sprintf("%s (use %s)", key, replacement))
+		}
+	}
+	if len(found) > 0 {
+		sort.Strings(found)
+		return errors.Errorf("deprecated settings in use: %s", strings.Join(found, ", "))
+	}
+	return nil
+}
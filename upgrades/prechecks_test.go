@@ -0,0 +1,118 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package upgrades_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/upgrades"
+	"github.com/juju/juju/version"
+)
+
+type preUpgradeChecksSuite struct {
+	jujutesting.JujuConnSuite
+	args upgrades.PreUpgradeParams
+}
+
+var _ = gc.Suite(&preUpgradeChecksSuite{})
+
+func (s *preUpgradeChecksSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(upgrades.FreeDiskSpace, func(string) (uint64, error) {
+		return 10 << 30, nil
+	})
+	s.args = upgrades.PreUpgradeParams{
+		State:         s.State,
+		DataDir:       s.DataDir(),
+		TargetVersion: version.MustParse("1.26.0"),
+	}
+}
+
+func (s *preUpgradeChecksSuite) checkErrors(c *gc.C) map[string]error {
+	errs := make(map[string]error)
+	for _, result := range upgrades.RunPreUpgradeChecks(s.args) {
+		if result.Error != nil {
+			errs[result.Name] = result.Error
+		}
+	}
+	return errs
+}
+
+func (s *preUpgradeChecksSuite) TestRegisteredChecks(c *gc.C) {
+	var names []string
+	for _, check := range upgrades.PreUpgradeChecks() {
+		c.Check(check.Description, gc.Not(gc.Equals), "")
+		names = append(names, check.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"disk-space",
+		"mongo-version",
+		"orphaned-documents",
+		"deprecated-config",
+	})
+}
+
+func (s *preUpgradeChecksSuite) TestRegisterDuplicatePanics(c *gc.C) {
+	s.PatchValue(upgrades.PreUpgradeChecksRegistry, upgrades.PreUpgradeChecks())
+	c.Assert(func() {
+		upgrades.RegisterPreUpgradeCheck(upgrades.PreUpgradeCheck{Name: "disk-space"})
+	}, gc.PanicMatches, `pre-upgrade check "disk-space" already registered`)
+}
+
+func (s *preUpgradeChecksSuite) TestAllChecksPass(c *gc.C) {
+	results := upgrades.RunPreUpgradeChecks(s.args)
+	c.Assert(results, gc.HasLen, 4)
+	for _, result := range results {
+		c.Check(result.Error, jc.ErrorIsNil, gc.Commentf("check %q", result.Name))
+	}
+}
+
+func (s *preUpgradeChecksSuite) TestDiskSpace(c *gc.C) {
+	s.PatchValue(upgrades.FreeDiskSpace, func(string) (uint64, error) {
+		return 100 << 20, nil
+	})
+	errs := s.checkErrors(c)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs["disk-space"], gc.ErrorMatches, "only 100MiB free in .*, at least 1024MiB needed")
+
+	s.PatchValue(upgrades.FreeDiskSpace, func(string) (uint64, error) {
+		return 0, errors.NotSupportedf("statfs")
+	})
+	c.Assert(s.checkErrors(c), gc.HasLen, 0)
+}
+
+func (s *preUpgradeChecksSuite) TestOrphanedDocuments(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.checkErrors(c), gc.HasLen, 0)
+
+	machines := s.State.MongoSession().DB("juju").C("machines")
+	err = machines.RemoveId(s.State.EnvironUUID() + ":" + machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	errs := s.checkErrors(c)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs["orphaned-documents"], gc.ErrorMatches,
+		`orphaned documents found: unit wordpress/0 \(machine 0 not found\)`)
+}
+
+func (s *preUpgradeChecksSuite) TestDeprecatedConfig(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"tools-url":     "some.special.url.com",
+		"lxc-use-clone": true,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	errs := s.checkErrors(c)
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs["deprecated-config"], gc.ErrorMatches,
+		`deprecated settings in use: lxc-use-clone \(use lxc-clone\), tools-url \(no longer supported\)`)
+}
@@ -6,6 +6,7 @@ package upgrades
 import (
 	"fmt"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/version"
@@ -91,6 +92,24 @@ func AreUpgradesDefined(from version.Number) bool {
 	return newUpgradeOpsIterator(from).Next() || newStateUpgradeOpsIterator(from).Next()
 }
 
+// CheckDowngrade returns an error if agents running the "from"
+// version cannot be safely downgraded to the "to" version. Downgrades
+// are only supported within a major.minor series, and only if no
+// state upgrade steps are defined for the versions being backed out,
+// because those steps may have changed the database in ways that the
+// older version does not understand.
+func CheckDowngrade(from, to version.Number) error {
+	if from.Major != to.Major || from.Minor != to.Minor {
+		return errors.Errorf("cannot downgrade from %s to %s: only downgrades within a patch series are supported", from, to)
+	}
+	ops := newOpsIterator(to, from, stateUpgradeOperations())
+	if ops.Next() {
+		return errors.Errorf("cannot downgrade from %s to %s: upgrade steps for %s have changed the database",
+			from, to, ops.Get().TargetVersion())
+	}
+	return nil
+}
+
 // PerformUpgrade runs the business logic needed to upgrade the current "from" version to this
 // version of Juju on the "target" type of machine.
 func PerformUpgrade(from version.Number, targets []Target, context Context) error {
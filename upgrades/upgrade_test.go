@@ -365,6 +365,40 @@ func (s *upgradeSuite) TestAreUpgradesDefined(c *gc.C) {
 	}
 }
 
+func (s *upgradeSuite) TestCheckDowngrade(c *gc.C) {
+	s.PatchValue(upgrades.StateUpgradeOperations, func() []upgrades.Operation {
+		return []upgrades.Operation{
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.0"),
+				steps:         []upgrades.Step{newUpgradeStep("state step - 1.21.0", upgrades.StateServer)},
+			},
+			&mockUpgradeOperation{
+				targetVersion: version.MustParse("1.21.3"),
+				steps:         []upgrades.Step{newUpgradeStep("state step - 1.21.3", upgrades.StateServer)},
+			},
+		}
+	})
+	for i, test := range []struct {
+		from, to string
+		err      string
+	}{
+		{from: "1.21.2", to: "1.21.1"},
+		{from: "1.21.5", to: "1.21.3"},
+		{from: "1.21.3", to: "1.21.2", err: "cannot downgrade from 1.21.3 to 1.21.2: upgrade steps for 1.21.3 have changed the database"},
+		{from: "1.21.5", to: "1.21.1", err: "cannot downgrade from 1.21.5 to 1.21.1: upgrade steps for 1.21.3 have changed the database"},
+		{from: "1.22.0", to: "1.21.5", err: "cannot downgrade from 1.22.0 to 1.21.5: only downgrades within a patch series are supported"},
+		{from: "2.21.0", to: "1.21.0", err: "cannot downgrade from 2.21.0 to 1.21.0: only downgrades within a patch series are supported"},
+	} {
+		c.Logf("%d: %s -> %s", i, test.from, test.to)
+		err := upgrades.CheckDowngrade(version.MustParse(test.from), version.MustParse(test.to))
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
 type upgradeTest struct {
 	about         string
 	fromVersion   string
@@ -666,7 +700,7 @@ func (s *upgradeSuite) TestUpgradeOperationsOrdered(c *gc.C) {
 
 func (s *upgradeSuite) TestStateUpgradeOperationsVersions(c *gc.C) {
 	versions := extractUpgradeVersions(c, (*upgrades.StateUpgradeOperations)())
-	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.21.0", "1.22.0", "1.23.0", "1.24.0"})
+	c.Assert(versions, gc.DeepEquals, []string{"1.18.0", "1.21.0", "1.22.0", "1.23.0", "1.24.0", "1.25.0"})
 }
 
 func (s *upgradeSuite) TestUpgradeOperationsVersions(c *gc.C) {
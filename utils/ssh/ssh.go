@@ -36,6 +36,9 @@ type Options struct {
 	// knownHostsFile is a path to a file in which to save the host's
 	// fingerprint.
 	knownHostsFile string
+	// strictHostKeyChecking, if true, refuses connections to hosts
+	// whose keys are not already known.
+	strictHostKeyChecking bool
 }
 
 // SetProxyCommand sets a command to execute to proxy traffic through.
@@ -63,6 +66,15 @@ func (o *Options) SetKnownHostsFile(file string) {
 	o.knownHostsFile = file
 }
 
+// EnableStrictHostKeyChecking causes the connection to fail unless
+// the host's key is found in the known hosts file, rather than
+// accepting and recording whatever key the host presents.
+//
+// This is currently only honoured by the OpenSSH client.
+func (o *Options) EnableStrictHostKeyChecking() {
+	o.strictHostKeyChecking = true
+}
+
 // AllowPasswordAuthentication allows the SSH
 // client to prompt the user for a password.
 //
@@ -0,0 +1,133 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package totp implements the time-based one-time passwords of RFC
+// 6238, as generated by common authenticator apps, for use as a second
+// factor when users log in.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// Period is how long each code is valid for.
+	Period = 30 * time.Second
+
+	// Digits is the number of digits in each code.
+	Digits = 6
+
+	// modulus is 10 to the power of Digits.
+	modulus = 1000000
+
+	// secretSize is the size in bytes of generated secrets, as
+	// recommended by RFC 4226.
+	secretSize = 20
+
+	// skew is the number of periods either side of the current one
+	// whose codes are accepted, to allow for clock differences.
+	skew = 1
+)
+
+// NewSecret returns a new random secret, encoded in base32 as expected
+// by authenticator apps.
+func NewSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Annotate(err, "cannot generate secret")
+	}
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
+
+// Code returns the code for the given secret at the given time.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return code(key, uint64(t.Unix()/int64(Period/time.Second))), nil
+}
+
+// Valid reports whether the given code is valid for the secret at the
+// given time. Codes of the adjacent periods are also accepted.
+func Valid(secret, code string, t time.Time) bool {
+	_, ok := Step(secret, code, t)
+	return ok
+}
+
+// Step returns the time step, the number of periods since the Unix
+// epoch, of the given code for the secret, and whether the code is
+// valid at the given time. Codes of the adjacent periods are also
+// accepted. Recording the step of each code accepted allows codes to
+// be used only once.
+func Step(secret, code string, t time.Time) (int64, bool) {
+	key, err := decodeSecret(secret)
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+	counter := t.Unix() / int64(Period/time.Second)
+	for i := counter - skew; i <= counter+skew; i++ {
+		if subtle.ConstantTimeCompare([]byte(codeAt(key, i)), []byte(code)) == 1 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// URI returns the otpauth URI for the secret, which authenticator apps
+// can read, typically from a QR code, to enrol the given account.
+func URI(issuer, account, secret string) string {
+	query := url.Values{
+		"secret": {strings.TrimRight(secret, "=")},
+		"issuer": {issuer},
+	}
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	if n := len(secret) % 8; n != 0 {
+		secret += strings.Repeat("=", 8-n)
+	}
+	key, err := base32.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, errors.NotValidf("secret")
+	}
+	return key, nil
+}
+
+func codeAt(key []byte, counter int64) string {
+	if counter < 0 {
+		return ""
+	}
+	return code(key, uint64(counter))
+}
+
+// code implements the HOTP algorithm of RFC 4226.
+func code(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%modulus)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package totp_test
+
+import (
+	"encoding/base32"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/utils/totp"
+)
+
+type totpSuite struct{}
+
+var _ = gc.Suite(&totpSuite{})
+
+// rfcSecret is the secret of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func (*totpSuite) TestCode(c *gc.C) {
+	// The RFC 6238 test vectors, truncated to six digits.
+	for i, test := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		c.Logf("test %d: %d", i, test.unix)
+		code, err := totp.Code(rfcSecret, time.Unix(test.unix, 0))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(code, gc.Equals, test.code)
+	}
+}
+
+func (*totpSuite) TestCodeBadSecret(c *gc.C) {
+	_, err := totp.Code("not base32!", time.Now())
+	c.Assert(err, gc.ErrorMatches, "secret not valid")
+}
+
+func (*totpSuite) TestValid(c *gc.C) {
+	t := time.Unix(1111111109, 0)
+	c.Assert(totp.Valid(rfcSecret, "081804", t), jc.IsTrue)
+	// Codes of the adjacent periods are accepted, but no further.
+	c.Assert(totp.Valid(rfcSecret, "081804", t.Add(totp.Period)), jc.IsTrue)
+	c.Assert(totp.Valid(rfcSecret, "081804", t.Add(-totp.Period)), jc.IsTrue)
+	c.Assert(totp.Valid(rfcSecret, "081804", t.Add(3*totp.Period)), jc.IsFalse)
+	c.Assert(totp.Valid(rfcSecret, "000000", t), jc.IsFalse)
+	c.Assert(totp.Valid(rfcSecret, "81804", t), jc.IsFalse)
+	c.Assert(totp.Valid("not base32!", "081804", t), jc.IsFalse)
+}
+
+func (*totpSuite) TestStep(c *gc.C) {
+	t := time.Unix(1111111109, 0)
+	step, ok := totp.Step(rfcSecret, "081804", t)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(step, gc.Equals, int64(1111111109/30))
+	// The step is that of the code, not of the time it is checked.
+	step, ok = totp.Step(rfcSecret, "081804", t.Add(totp.Period))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(step, gc.Equals, int64(1111111109/30))
+	_, ok = totp.Step(rfcSecret, "000000", t)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (*totpSuite) TestNewSecret(c *gc.C) {
+	secret, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	other, err := totp.NewSecret()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(secret, gc.Not(gc.Equals), other)
+
+	code, err := totp.Code(secret, time.Now())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(totp.Valid(secret, code, time.Now()), jc.IsTrue)
+}
+
+func (*totpSuite) TestURI(c *gc.C) {
+	uri := totp.URI("juju", "bob@local", rfcSecret)
+	c.Assert(uri, gc.Equals, "otpauth://totp/juju:bob@local?issuer=juju&secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+}
@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package agentrollout provides a worker that moves staged agent
+// upgrades through their phases: it promotes a rollout once its
+// canaries have been healthy for the bake period, and rolls it back
+// as soon as any of them fail.
+package agentrollout
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.agentrollout")
+
+// checkPeriod is the interval at which the current rollout's canaries
+// are checked.
+var checkPeriod = time.Minute
+
+// State defines the state methods used by the worker.
+type State interface {
+	AdvanceAgentRollout(now time.Time) (*state.AgentRollout, error)
+}
+
+// NewWorker returns a worker that periodically advances the
+// environment's staged agent upgrade, if there is one.
+func NewWorker(st State) worker.Worker {
+	f := func(stop <-chan struct{}) error {
+		rollout, err := st.AdvanceAgentRollout(time.Now())
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return errors.Annotate(err, "cannot advance staged upgrade")
+		}
+		logger.Tracef("staged upgrade to %s is %s", rollout.TargetVersion(), rollout.Phase())
+		return nil
+	}
+	return worker.NewPeriodicWorker(f, checkPeriod)
+}
@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agentrollout_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agentrollout"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type mockState struct {
+	calls chan time.Time
+	err   error
+}
+
+func (st *mockState) AdvanceAgentRollout(now time.Time) (*state.AgentRollout, error) {
+	st.calls <- now
+	return nil, st.err
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.PatchValue(agentrollout.CheckPeriod, coretesting.ShortWait)
+}
+
+func (s *workerSuite) waitForCall(c *gc.C, st *mockState) {
+	select {
+	case <-st.calls:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("rollout was not advanced")
+	}
+}
+
+func (s *workerSuite) TestAdvancesRollout(c *gc.C) {
+	st := &mockState{calls: make(chan time.Time, 10), err: errors.NotFoundf("agent rollout")}
+	w := agentrollout.NewWorker(st)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// A missing rollout does not stop the worker.
+	s.waitForCall(c, st)
+	s.waitForCall(c, st)
+}
+
+func (s *workerSuite) TestError(c *gc.C) {
+	st := &mockState{calls: make(chan time.Time, 10), err: errors.New("boom")}
+	w := agentrollout.NewWorker(st)
+	s.waitForCall(c, st)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot advance staged upgrade: boom")
+}
@@ -23,11 +23,12 @@ var logger = loggo.GetLogger("juju.worker.certupdater")
 // that server's machines addresses in state, and write a new certificate to the
 // agent's config file.
 type CertificateUpdater struct {
-	addressWatcher AddressWatcher
-	getter         StateServingInfoGetter
-	setter         StateServingInfoSetter
-	configGetter   EnvironConfigGetter
-	certChanged    chan params.StateServingInfo
+	addressWatcher  AddressWatcher
+	rotationWatcher CertRotationWatcher
+	getter          StateServingInfoGetter
+	setter          StateServingInfoSetter
+	configGetter    EnvironConfigGetter
+	certChanged     chan params.StateServingInfo
 }
 
 // AddressWatcher is an interface that is provided to NewCertificateUpdater
@@ -37,6 +38,13 @@ type AddressWatcher interface {
 	Addresses() (addresses []network.Address)
 }
 
+// CertRotationWatcher is an interface that is provided to NewCertificateUpdater
+// which can be used to watch for requests to rotate the state server
+// certificates.
+type CertRotationWatcher interface {
+	WatchServerCertRotation() state.NotifyWatcher
+}
+
 // EnvironConfigGetter is an interface that is provided to NewCertificateUpdater
 // which can be used to get environment config.
 type EnvironConfigGetter interface {
@@ -54,23 +62,29 @@ type StateServingInfoGetter interface {
 type StateServingInfoSetter func(info params.StateServingInfo) error
 
 // NewCertificateUpdater returns a worker.Worker that watches for changes to
-// machine addresses and then generates a new state server certificate with those
-// addresses in the certificate's SAN value.
-func NewCertificateUpdater(addressWatcher AddressWatcher, getter StateServingInfoGetter,
-	configGetter EnvironConfigGetter, setter StateServingInfoSetter, certChanged chan params.StateServingInfo,
+// machine addresses, or requests to rotate the certificate, and then generates
+// a new state server certificate with those addresses in the certificate's
+// SAN value.
+func NewCertificateUpdater(addressWatcher AddressWatcher, rotationWatcher CertRotationWatcher,
+	getter StateServingInfoGetter, configGetter EnvironConfigGetter, setter StateServingInfoSetter,
+	certChanged chan params.StateServingInfo,
 ) worker.Worker {
 	return worker.NewNotifyWorker(&CertificateUpdater{
-		addressWatcher: addressWatcher,
-		configGetter:   configGetter,
-		getter:         getter,
-		setter:         setter,
-		certChanged:    certChanged,
+		addressWatcher:  addressWatcher,
+		rotationWatcher: rotationWatcher,
+		configGetter:    configGetter,
+		getter:          getter,
+		setter:          setter,
+		certChanged:     certChanged,
 	})
 }
 
 // SetUp is defined on the NotifyWatchHandler interface.
 func (c *CertificateUpdater) SetUp() (watcher.NotifyWatcher, error) {
-	return c.addressWatcher.WatchAddresses(), nil
+	return newCombinedWatcher(
+		c.addressWatcher.WatchAddresses(),
+		c.rotationWatcher.WatchServerCertRotation(),
+	), nil
 }
 
 // Handle is defined on the NotifyWatchHandler interface.
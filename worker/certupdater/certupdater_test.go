@@ -70,6 +70,14 @@ func (m *mockMachine) Addresses() (addresses []network.Address) {
 	}}
 }
 
+type mockRotationWatcher struct {
+	changes chan struct{}
+}
+
+func (r *mockRotationWatcher) WatchServerCertRotation() state.NotifyWatcher {
+	return newMockNotifyWatcher(r.changes)
+}
+
 type mockStateServingGetter struct{}
 
 func (g *mockStateServingGetter) StateServingInfo() (params.StateServingInfo, bool) {
@@ -96,7 +104,7 @@ func (s *CertUpdaterSuite) TestStartStop(c *gc.C) {
 	changes := make(chan struct{})
 	certChangedChan := make(chan params.StateServingInfo)
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, &mockStateServingGetter{}, &mockConfigGetter{}, setter, certChangedChan,
+		&mockMachine{changes}, &mockRotationWatcher{}, &mockStateServingGetter{}, &mockConfigGetter{}, setter, certChangedChan,
 	)
 	worker.Kill()
 	c.Assert(worker.Wait(), gc.IsNil)
@@ -121,7 +129,7 @@ func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 	changes := make(chan struct{})
 	certChangedChan := make(chan params.StateServingInfo)
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, &mockStateServingGetter{}, &mockConfigGetter{}, setter, certChangedChan,
+		&mockMachine{changes}, &mockRotationWatcher{}, &mockStateServingGetter{}, &mockConfigGetter{}, setter, certChangedChan,
 	)
 	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
 	defer worker.Kill()
@@ -142,6 +150,35 @@ func (s *CertUpdaterSuite) TestAddressChange(c *gc.C) {
 		[]string{"localhost", "juju-apiserver", "juju-mongodb"})
 }
 
+func (s *CertUpdaterSuite) TestRotationRequested(c *gc.C) {
+	updated := make(chan string, 1)
+	setter := func(info params.StateServingInfo) error {
+		updated <- info.PrivateKey
+		return nil
+	}
+	rotations := make(chan struct{})
+	certChangedChan := make(chan params.StateServingInfo)
+	worker := certupdater.NewCertificateUpdater(
+		&mockMachine{}, &mockRotationWatcher{rotations}, &mockStateServingGetter{}, &mockConfigGetter{}, setter, certChangedChan,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	var keys []string
+	for i := 0; i < 2; i++ {
+		rotations <- struct{}{}
+		// A new certificate and key should be generated each time.
+		select {
+		case key := <-updated:
+			c.Assert(key, gc.Not(gc.Equals), coretesting.ServerKey)
+			keys = append(keys, key)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for certificate to be rotated")
+		}
+	}
+	c.Assert(keys[0], gc.Not(gc.Equals), keys[1])
+}
+
 type mockStateServingGetterNoCAKey struct{}
 
 func (g *mockStateServingGetterNoCAKey) StateServingInfo() (params.StateServingInfo, bool) {
@@ -163,7 +200,7 @@ func (s *CertUpdaterSuite) TestAddressChangeNoCAKey(c *gc.C) {
 	changes := make(chan struct{})
 	certChangedChan := make(chan params.StateServingInfo)
 	worker := certupdater.NewCertificateUpdater(
-		&mockMachine{changes}, &mockStateServingGetterNoCAKey{}, &mockConfigGetter{}, setter, certChangedChan,
+		&mockMachine{changes}, &mockRotationWatcher{}, &mockStateServingGetterNoCAKey{}, &mockConfigGetter{}, setter, certChangedChan,
 	)
 	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
 	defer worker.Kill()
@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package certupdater
+
+import (
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	statewatcher "github.com/juju/juju/state/watcher"
+)
+
+// combinedWatcher notifies whenever either of the watchers it
+// combines does. Events are coalesced until they have been received.
+type combinedWatcher struct {
+	tomb    tomb.Tomb
+	a, b    state.NotifyWatcher
+	changes chan struct{}
+}
+
+func newCombinedWatcher(a, b state.NotifyWatcher) *combinedWatcher {
+	w := &combinedWatcher{
+		a:       a,
+		b:       b,
+		changes: make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.changes)
+		defer statewatcher.Stop(w.a, &w.tomb)
+		defer statewatcher.Stop(w.b, &w.tomb)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+func (w *combinedWatcher) loop() error {
+	var out chan struct{}
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case _, ok := <-w.a.Changes():
+			if !ok {
+				return statewatcher.EnsureErr(w.a)
+			}
+			out = w.changes
+		case _, ok := <-w.b.Changes():
+			if !ok {
+				return statewatcher.EnsureErr(w.b)
+			}
+			out = w.changes
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
+// Changes returns the event channel for the watcher.
+func (w *combinedWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Stop stops the watcher and returns any error encountered while
+// watching.
+func (w *combinedWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
+
+// Err returns any error encountered while watching.
+func (w *combinedWatcher) Err() error {
+	return w.tomb.Err()
+}
@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleaner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// timerPeriod is the longest the timer waits before checking for
+// scheduled cleanups again, so that it notices cleanups scheduled
+// after it last looked.
+var timerPeriod = time.Minute
+
+// NewTimer returns a worker.Worker that runs state.Cleanup() when
+// cleanups scheduled for later, such as machine removals with a grace
+// period, fall due. Cleanups scheduled for later do not change the
+// documents watched by the Cleaner when they fall due, so they need a
+// timer of their own.
+func NewTimer(st *state.State) worker.Worker {
+	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+		for {
+			wait := timerPeriod
+			due, err := st.NextScheduledCleanup()
+			if err == nil {
+				if until := due.Sub(time.Now()); until < wait {
+					wait = until
+				}
+			} else if !errors.IsNotFound(err) {
+				logger.Errorf("cannot read scheduled cleanups: %v", err)
+			}
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(wait):
+			}
+			if err == nil && !time.Now().Before(due) {
+				if err := st.Cleanup(); err != nil {
+					logger.Errorf("cannot cleanup state: %v", err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cleaner_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/cleaner"
+)
+
+type TimerSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&TimerSuite{})
+
+func (s *TimerSuite) TestTimerRunsScheduledCleanup(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+
+	t := cleaner.NewTimer(s.State)
+	defer func() { c.Assert(worker.Stop(t), gc.IsNil) }()
+
+	timeout := time.After(coretesting.LongWait)
+	for {
+		select {
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for scheduled removal")
+		}
+		err := machine.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		if machine.Life() == state.Dying {
+			break
+		}
+	}
+}
+
+func (s *TimerSuite) TestTimerLeavesCancelledCleanup(c *gc.C) {
+	s.PatchValue(cleaner.TimerPeriod, coretesting.ShortWait)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = machine.ScheduleDestroy(time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.CancelScheduledDestroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	t := cleaner.NewTimer(s.State)
+	time.Sleep(time.Second + coretesting.ShortWait)
+	c.Assert(worker.Stop(t), gc.IsNil)
+
+	err = machine.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.Life(), gc.Equals, state.Alive)
+}
@@ -18,6 +18,11 @@ type LogPruneParams struct {
 	MaxLogAge       time.Duration
 	MaxCollectionMB int
 	PruneInterval   time.Duration
+
+	// FromEnvironConfig causes MaxLogAge and MaxCollectionMB to be
+	// replaced, before each prune, by the max-logs-age and
+	// max-logs-size settings of the environment.
+	FromEnvironConfig bool
 }
 
 const DefaultMaxLogAge = 3 * 24 * time.Hour // 3 days
@@ -57,8 +62,16 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 		case <-stopCh:
 			return tomb.ErrDying
 		case <-time.After(p.PruneInterval):
-			minLogTime := time.Now().Add(-p.MaxLogAge)
-			err := state.PruneLogs(w.st, minLogTime, p.MaxCollectionMB)
+			maxLogAge, maxCollectionMB := p.MaxLogAge, p.MaxCollectionMB
+			if p.FromEnvironConfig {
+				cfg, err := w.st.EnvironConfig()
+				if err != nil {
+					return errors.Annotate(err, "cannot read environment config")
+				}
+				maxLogAge, maxCollectionMB = cfg.MaxLogsAge(), cfg.MaxLogsSizeMB()
+			}
+			minLogTime := time.Now().Add(-maxLogAge)
+			err := state.PruneLogs(w.st, minLogTime, maxCollectionMB)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -106,6 +106,40 @@ func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesOldLogsFromEnvironConfig(c *gc.C) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"max-logs-age": 1}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	params := &dblogpruner.LogPruneParams{
+		// These would prune nothing; the environment's settings
+		// must be used instead.
+		MaxLogAge:         999 * time.Hour,
+		MaxCollectionMB:   int(1e9),
+		PruneInterval:     time.Millisecond,
+		FromEnvironConfig: true,
+	}
+	s.pruner = dblogpruner.New(s.State, params)
+	s.AddCleanup(func(c *gc.C) {
+		s.pruner.Kill()
+		c.Assert(s.pruner.Wait(), jc.ErrorIsNil)
+	})
+
+	now := time.Now()
+	s.addLogs(c, now, "keep", 5)
+	s.addLogs(c, now.Add(-2*time.Hour), "prune", 5)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := s.logsColl.Find(bson.M{"x": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			keepCount, err := s.logsColl.Find(bson.M{"x": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 5)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
 func (s *suite) addLogs(c *gc.C, t0 time.Time, text string, count int) {
 	dbLogger := state.NewDbLogger(s.State, names.NewMachineTag("0"))
 	defer dbLogger.Close()
@@ -33,6 +33,7 @@ func NewEngine(isFatal IsFatalFunc, errorDelay, bounceDelay time.Duration) Engin
 		install: make(chan installTicket),
 		started: make(chan startedTicket),
 		stopped: make(chan stoppedTicket),
+		report:  make(chan chan []worker.WorkerReport),
 	}
 	go func() {
 		defer engine.tomb.Done()
@@ -66,11 +67,12 @@ type engine struct {
 	// current holds the active worker information for each installed manifold.
 	current map[string]workerInfo
 
-	// install, started, and stopped each communicate requests and changes into
-	// the loop goroutine.
+	// install, started, stopped and report each communicate requests and
+	// changes into the loop goroutine.
 	install chan installTicket
 	started chan startedTicket
 	stopped chan stoppedTicket
+	report  chan chan []worker.WorkerReport
 }
 
 // loop serializes manifold install operations and worker start/stop notifications.
@@ -97,6 +99,8 @@ func (engine *engine) loop() error {
 			engine.gotStarted(ticket.name, ticket.worker)
 		case ticket := <-engine.stopped:
 			engine.gotStopped(ticket.name, ticket.error)
+		case reply := <-engine.report:
+			reply <- engine.reports()
 		}
 		if engine.isDying() {
 			if engine.allStopped() {
@@ -116,6 +120,39 @@ func (engine *engine) Wait() error {
 	return engine.tomb.Wait()
 }
 
+// Report is part of the worker.Reporter interface. It returns no reports
+// once the engine has stopped.
+func (engine *engine) Report() []worker.WorkerReport {
+	reply := make(chan []worker.WorkerReport, 1)
+	select {
+	case <-engine.tomb.Dead():
+		return nil
+	case engine.report <- reply:
+		return <-reply
+	}
+}
+
+// reports returns a report on the worker for each installed manifold. It
+// must only be called from the loop goroutine.
+func (engine *engine) reports() []worker.WorkerReport {
+	reports := make([]worker.WorkerReport, 0, len(engine.manifolds))
+	for name, manifold := range engine.manifolds {
+		info := engine.current[name]
+		report := worker.WorkerReport{
+			Name:       name,
+			State:      info.state(),
+			Inputs:     manifold.Inputs,
+			StartCount: info.startCount,
+		}
+		if info.lastErr != nil {
+			report.LastError = info.lastErr.Error()
+		}
+		reports = append(reports, report)
+	}
+	worker.SortReports(reports)
+	return reports
+}
+
 // Install is part of the Engine interface.
 func (engine *engine) Install(name string, manifold Manifold) error {
 	result := make(chan error)
@@ -301,6 +338,7 @@ func (engine *engine) gotStarted(name string, worker worker.Worker) {
 		logger.Infof("%q manifold worker started", name)
 		info.starting = false
 		info.worker = worker
+		info.startCount++
 		engine.current[name] = info
 
 		// Any manifold that declares this one as an input needs to be restarted.
@@ -321,8 +359,16 @@ func (engine *engine) gotStopped(name string, err error) {
 		engine.tomb.Kill(err)
 	}
 
-	// Reset engine info; and bail out if we can be sure there's no need to bounce.
-	engine.current[name] = workerInfo{}
+	// Reset engine info, keeping what we report about the worker; and bail
+	// out if we can be sure there's no need to bounce.
+	lastErr := info.lastErr
+	if err != nil {
+		lastErr = err
+	}
+	engine.current[name] = workerInfo{
+		startCount: info.startCount,
+		lastErr:    lastErr,
+	}
 	if engine.isDying() {
 		logger.Debugf("permanently stopped %q manifold worker (shutting down)", name)
 		return
@@ -416,6 +462,25 @@ type workerInfo struct {
 	starting bool
 	stopping bool
 	worker   worker.Worker
+
+	// startCount and lastErr record the number of times a worker has
+	// been started for the manifold, and the error with which one
+	// last stopped, for reporting.
+	startCount int
+	lastErr    error
+}
+
+// state returns the worker.State* value describing the worker.
+func (info workerInfo) state() string {
+	switch {
+	case info.stopping:
+		return worker.StateStopping
+	case info.worker != nil:
+		return worker.StateStarted
+	case info.starting:
+		return worker.StateStarting
+	}
+	return worker.StateStopped
 }
 
 // stopped returns true unless the worker is either assigned or starting.
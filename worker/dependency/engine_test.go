@@ -400,3 +400,39 @@ func (s *EngineSuite) TestErrMissing(c *gc.C) {
 	mh1.AssertNoStart(c)
 	mh2.AssertOneStart(c)
 }
+
+func (s *EngineSuite) TestReport(c *gc.C) {
+
+	// Install a task, and make it restart once.
+	mh1 := newManifoldHarness()
+	err := s.engine.Install("some-task", mh1.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh1.AssertOneStart(c)
+	mh1.InjectError(c, errors.New("kerrang"))
+	mh1.AssertOneStart(c)
+
+	// Install a task that cannot start without a missing dependency.
+	mh2 := newManifoldHarness("missing-task")
+	err = s.engine.Install("unmet-task", mh2.Manifold())
+	c.Assert(err, jc.ErrorIsNil)
+	mh2.AssertNoStart(c)
+
+	c.Assert(s.engine.Report(), jc.DeepEquals, []worker.WorkerReport{{
+		Name:       "some-task",
+		State:      worker.StateStarted,
+		StartCount: 2,
+		LastError:  "kerrang",
+	}, {
+		Name:      "unmet-task",
+		State:     worker.StateStopped,
+		Inputs:    []string{"missing-task"},
+		LastError: dependency.ErrMissing.Error(),
+	}})
+}
+
+func (s *EngineSuite) TestReportStoppedEngine(c *gc.C) {
+	s.stopEngine(c)
+	engine := dependency.NewEngine(nothingFatal, coretesting.ShortWait/2, coretesting.ShortWait/10)
+	c.Assert(worker.Stop(engine), jc.ErrorIsNil)
+	c.Assert(engine.Report(), gc.HasLen, 0)
+}
@@ -20,6 +20,9 @@ type Engine interface {
 
 	// Engine is just another Worker.
 	worker.Worker
+
+	// Report describes the worker of each installed manifold.
+	worker.Reporter
 }
 
 // Manifold defines the behaviour of a node in an Engine's dependency graph. It's
@@ -0,0 +1,168 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package dnsregistrar provides a worker that keeps a DNS name for
+// each of the environment's services pointing at the public addresses
+// of its units, using the registrar configured by the environment's
+// dns-registrar setting.
+package dnsregistrar
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.dnsregistrar")
+
+// checkPeriod is the interval at which unit addresses are compared
+// with the registered names.
+var checkPeriod = 30 * time.Second
+
+// State defines the state methods used by the worker.
+type State interface {
+	// EnvironConfig returns the environment's configuration.
+	EnvironConfig() (*config.Config, error)
+
+	// ServiceAddresses returns the sorted public addresses of the
+	// units of each service, keyed by service name.
+	ServiceAddresses() (map[string][]string, error)
+}
+
+// NewWorker returns a worker that periodically registers a DNS name
+// for each service in the environment, and removes the names of
+// services that no longer have any addressable units.
+func NewWorker(st *state.State) worker.Worker {
+	return newWorker(stateShim{st})
+}
+
+func newWorker(st State) worker.Worker {
+	r := &registrar{
+		st:         st,
+		registered: make(map[string][]string),
+	}
+	return worker.NewPeriodicWorker(r.update, checkPeriod)
+}
+
+type registrar struct {
+	st State
+
+	// registered holds the addresses last registered for each name.
+	registered map[string][]string
+}
+
+func (r *registrar) update(stop <-chan struct{}) error {
+	cfg, err := r.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.DNSRegistrar() == config.DNSRegistrarNone {
+		return nil
+	}
+	dns, err := newRegistrar(cfg)
+	if err != nil {
+		return errors.Annotate(err, "cannot create DNS registrar")
+	}
+	services, err := r.st.ServiceAddresses()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	wanted := make(map[string][]string)
+	for service, addresses := range services {
+		if len(addresses) > 0 {
+			wanted[dnsName(service, cfg.DNSDomain())] = addresses
+		}
+	}
+	for name := range r.registered {
+		if _, ok := wanted[name]; ok {
+			continue
+		}
+		logger.Infof("unregistering %s", name)
+		if err := dns.UnregisterDNS(name); err != nil {
+			return errors.Annotatef(err, "cannot unregister %s", name)
+		}
+		delete(r.registered, name)
+	}
+	for name, addresses := range wanted {
+		if reflect.DeepEqual(r.registered[name], addresses) {
+			continue
+		}
+		logger.Infof("registering %s with addresses %v", name, addresses)
+		if err := dns.RegisterDNS(name, addresses); err != nil {
+			return errors.Annotatef(err, "cannot register %s", name)
+		}
+		r.registered[name] = addresses
+	}
+	return nil
+}
+
+// dnsName returns the name registered for the given service.
+func dnsName(service, domain string) string {
+	return fmt.Sprintf("%s.%s", service, domain)
+}
+
+// newRegistrar returns the DNS registrar selected by the given
+// environment configuration. It may be replaced in tests.
+var newRegistrar = func(cfg *config.Config) (environs.DNSRegistrar, error) {
+	switch kind := cfg.DNSRegistrar(); kind {
+	case config.DNSRegistrarProvider:
+		env, err := environs.New(cfg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		dns, ok := env.(environs.DNSRegistrar)
+		if !ok {
+			return nil, errors.NotSupportedf("DNS registration by provider %q", cfg.Type())
+		}
+		return dns, nil
+	case config.DNSRegistrarWebhook:
+		return &webhookRegistrar{url: cfg.DNSWebhookURL()}, nil
+	default:
+		return nil, errors.NotValidf("DNS registrar %q", kind)
+	}
+}
+
+// stateShim adapts a *state.State to the State interface.
+type stateShim struct {
+	*state.State
+}
+
+// ServiceAddresses is part of the State interface.
+func (st stateShim) ServiceAddresses() (map[string][]string, error) {
+	services, err := st.AllServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string][]string)
+	for _, service := range services {
+		units, err := service.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		seen := make(map[string]bool)
+		addresses := []string{}
+		for _, unit := range units {
+			if unit.Life() != state.Alive {
+				continue
+			}
+			address, ok := unit.PublicAddress()
+			if !ok || seen[address] {
+				continue
+			}
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+		sort.Strings(addresses)
+		result[service.Name()] = addresses
+	}
+	return result, nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dnsregistrar_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dnsregistrar"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	testing.IsolationSuite
+	calls chan string
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type mockState struct {
+	mu        sync.Mutex
+	cfg       *config.Config
+	addresses map[string][]string
+}
+
+func (st *mockState) EnvironConfig() (*config.Config, error) {
+	return st.cfg, nil
+}
+
+func (st *mockState) ServiceAddresses() (map[string][]string, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	result := make(map[string][]string)
+	for service, addresses := range st.addresses {
+		result[service] = addresses
+	}
+	return result, nil
+}
+
+func (st *mockState) setAddresses(service string, addresses ...string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.addresses[service] = addresses
+}
+
+type mockRegistrar struct {
+	calls chan<- string
+}
+
+func (r *mockRegistrar) RegisterDNS(name string, addresses []string) error {
+	r.calls <- fmt.Sprintf("register %s %v", name, addresses)
+	return nil
+}
+
+func (r *mockRegistrar) UnregisterDNS(name string) error {
+	r.calls <- fmt.Sprintf("unregister %s", name)
+	return nil
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.calls = make(chan string, 10)
+	s.PatchValue(dnsregistrar.CheckPeriod, coretesting.ShortWait)
+	s.PatchValue(dnsregistrar.NewRegistrar, func(*config.Config) (environs.DNSRegistrar, error) {
+		return &mockRegistrar{s.calls}, nil
+	})
+}
+
+func (s *workerSuite) newState(c *gc.C) *mockState {
+	return &mockState{
+		cfg: coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+			"dns-registrar":   "webhook",
+			"dns-domain":      "example.com",
+			"dns-webhook-url": "http://dns.example.com/",
+		}),
+		addresses: make(map[string][]string),
+	}
+}
+
+func (s *workerSuite) assertCall(c *gc.C, expect string) {
+	select {
+	case call := <-s.calls:
+		c.Assert(call, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %q", expect)
+	}
+}
+
+func (s *workerSuite) assertNoCall(c *gc.C) {
+	select {
+	case call := <-s.calls:
+		c.Fatalf("unexpected call %q", call)
+	case <-time.After(coretesting.ShortWait * 5):
+	}
+}
+
+func (s *workerSuite) TestRegistersServices(c *gc.C) {
+	st := s.newState(c)
+	st.setAddresses("wordpress", "10.0.0.1", "10.0.0.2")
+	st.setAddresses("mysql")
+	w := dnsregistrar.NewStateWorker(st)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertCall(c, "register wordpress.example.com [10.0.0.1 10.0.0.2]")
+	// Unchanged names are not registered again.
+	s.assertNoCall(c)
+
+	st.setAddresses("wordpress", "10.0.0.1")
+	st.setAddresses("mysql", "10.0.0.3")
+	calls := []string{<-s.calls, <-s.calls}
+	c.Assert(calls, jc.SameContents, []string{
+		"register wordpress.example.com [10.0.0.1]",
+		"register mysql.example.com [10.0.0.3]",
+	})
+
+	st.setAddresses("wordpress")
+	s.assertCall(c, "unregister wordpress.example.com")
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestDisabled(c *gc.C) {
+	st := s.newState(c)
+	st.cfg = coretesting.EnvironConfig(c)
+	st.setAddresses("wordpress", "10.0.0.1")
+	w := dnsregistrar.NewStateWorker(st)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestRegistrarError(c *gc.C) {
+	s.PatchValue(dnsregistrar.NewRegistrar, func(*config.Config) (environs.DNSRegistrar, error) {
+		return nil, errors.NotSupportedf("DNS registration by provider %q", "dummy")
+	})
+	st := s.newState(c)
+	w := dnsregistrar.NewStateWorker(st)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, `cannot create DNS registrar: DNS registration by provider "dummy" not supported`)
+}
+
+func (s *workerSuite) TestWebhook(c *gc.C) {
+	requests := make(chan dnsregistrar.WebhookRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req dnsregistrar.WebhookRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/json")
+		requests <- req
+		if req.Name == "bad.example.com" {
+			http.Error(w, "no", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	cfg := coretesting.CustomEnvironConfig(c, coretesting.Attrs{
+		"dns-registrar":   "webhook",
+		"dns-domain":      "example.com",
+		"dns-webhook-url": server.URL,
+	})
+	dns, err := dnsregistrar.DefaultNewRegistrar(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = dns.RegisterDNS("wordpress.example.com", []string{"10.0.0.1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(<-requests, jc.DeepEquals, dnsregistrar.WebhookRequest{
+		Action:    "register",
+		Name:      "wordpress.example.com",
+		Addresses: []string{"10.0.0.1"},
+	})
+	err = dns.UnregisterDNS("wordpress.example.com")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(<-requests, jc.DeepEquals, dnsregistrar.WebhookRequest{
+		Action: "unregister",
+		Name:   "wordpress.example.com",
+	})
+	err = dns.UnregisterDNS("bad.example.com")
+	c.Assert(err, gc.ErrorMatches, "DNS webhook returned 403 Forbidden")
+}
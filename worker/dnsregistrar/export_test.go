@@ -0,0 +1,11 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dnsregistrar
+
+var (
+	CheckPeriod         = &checkPeriod
+	NewRegistrar        = &newRegistrar
+	DefaultNewRegistrar = newRegistrar
+	NewStateWorker      = newWorker
+)
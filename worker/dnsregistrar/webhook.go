@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dnsregistrar
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/juju/errors"
+)
+
+// WebhookRequest is the body of the request posted to the URL held in
+// the dns-webhook-url setting for each registration change.
+type WebhookRequest struct {
+	// Action is "register" or "unregister".
+	Action string `json:"action"`
+
+	// Name is the fully qualified name being changed.
+	Name string `json:"name"`
+
+	// Addresses holds the addresses to which the name should resolve.
+	// It is empty when the name is unregistered.
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// webhookRegistrar is a DNS registrar that delegates registration to
+// an external service by posting each change to a URL.
+type webhookRegistrar struct {
+	url string
+}
+
+// RegisterDNS is part of the environs.DNSRegistrar interface.
+func (w *webhookRegistrar) RegisterDNS(name string, addresses []string) error {
+	return w.post(WebhookRequest{
+		Action:    "register",
+		Name:      name,
+		Addresses: addresses,
+	})
+}
+
+// UnregisterDNS is part of the environs.DNSRegistrar interface.
+func (w *webhookRegistrar) UnregisterDNS(name string) error {
+	return w.post(WebhookRequest{
+		Action: "unregister",
+		Name:   name,
+	})
+}
+
+func (w *webhookRegistrar) post(req WebhookRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := http.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("DNS webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package firewaller
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+)
+
+// exposesPortRange reports whether a port range opened by one of the
+// service's units should be opened in the firewall. Ports of a service
+// exposed on all its endpoints are always opened; otherwise the range
+// must fall within the ports of one of the exposed endpoints.
+//
+// The providers' firewalls cannot restrict access by source network,
+// so ports of endpoints exposed only to some networks are kept closed
+// rather than being opened to everyone.
+func (sd *serviceData) exposesPortRange(portRange network.PortRange) bool {
+	if !sd.exposed {
+		return false
+	}
+	if len(sd.endpoints) == 0 {
+		return true
+	}
+	for name, endpoint := range sd.endpoints {
+		if !openToAll(endpoint.CIDRs) {
+			logger.Warningf(
+				"not opening %v for endpoint %q of %q: restricting access by source network is not supported",
+				portRange, name, sd.service.Name(),
+			)
+			continue
+		}
+		if endpointIncludes(endpoint, portRange) {
+			return true
+		}
+	}
+	return false
+}
+
+// openToAll reports whether the given source networks allow access
+// from anywhere.
+func openToAll(cidrs []string) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+	for _, cidr := range cidrs {
+		if cidr == "0.0.0.0/0" || cidr == "::/0" {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointIncludes reports whether the given port range falls within
+// the ports of the exposed endpoint.
+func endpointIncludes(endpoint params.ExposedEndpoint, portRange network.PortRange) bool {
+	if len(endpoint.Ports) == 0 {
+		return true
+	}
+	for _, ports := range endpoint.Ports {
+		allowed, err := network.ParsePortRange(ports)
+		if err != nil {
+			// The ports are validated when they are set.
+			logger.Errorf("ignoring invalid exposed port range %q: %v", ports, err)
+			continue
+		}
+		if allowed.Protocol == portRange.Protocol &&
+			allowed.FromPort <= portRange.FromPort &&
+			allowed.ToPort >= portRange.ToPort {
+			return true
+		}
+	}
+	return false
+}
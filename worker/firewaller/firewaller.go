@@ -4,6 +4,7 @@
 package firewaller
 
 import (
+	"reflect"
 	"strings"
 
 	"github.com/juju/errors"
@@ -163,6 +164,7 @@ func (fw *Firewaller) loop() error {
 			}
 		case change := <-fw.exposedChange:
 			change.serviced.exposed = change.exposed
+			change.serviced.endpoints = change.endpoints
 			unitds := []*unitData{}
 			for _, unitd := range change.serviced.unitds {
 				unitds = append(unitds, unitd)
@@ -272,14 +274,19 @@ func (fw *Firewaller) startService(service *apifirewaller.Service) error {
 	if err != nil {
 		return err
 	}
+	endpoints, err := service.ExposedEndpoints()
+	if err != nil {
+		return err
+	}
 	serviced := &serviceData{
-		fw:      fw,
-		service: service,
-		exposed: exposed,
-		unitds:  make(map[names.UnitTag]*unitData),
+		fw:        fw,
+		service:   service,
+		exposed:   exposed,
+		endpoints: endpoints,
+		unitds:    make(map[names.UnitTag]*unitData),
 	}
 	fw.serviceds[service.Tag()] = serviced
-	go serviced.watchLoop(serviced.exposed)
+	go serviced.watchLoop(serviced.exposed, serviced.endpoints)
 	return nil
 }
 
@@ -299,7 +306,7 @@ func (fw *Firewaller) reconcileGlobal() error {
 				delete(machined.unitds, unitTag)
 				continue
 			}
-			if unitd.serviced.exposed {
+			if unitd.serviced.exposesPortRange(portRange) {
 				collector[portRange] = true
 			}
 		}
@@ -506,7 +513,7 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 			delete(machined.unitds, unitTag)
 			continue
 		}
-		if unitd.serviced.exposed {
+		if unitd.serviced.exposesPortRange(portRange) {
 			want = append(want, portRange)
 		}
 	}
@@ -762,23 +769,27 @@ type unitData struct {
 	machined *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific service.
+// exposedChange contains the changed exposed flag and exposed
+// endpoints for one specific service.
 type exposedChange struct {
-	serviced *serviceData
-	exposed  bool
+	serviced  *serviceData
+	exposed   bool
+	endpoints map[string]params.ExposedEndpoint
 }
 
 // serviceData holds service details and watches exposure changes.
 type serviceData struct {
-	tomb    tomb.Tomb
-	fw      *Firewaller
-	service *apifirewaller.Service
-	exposed bool
-	unitds  map[names.UnitTag]*unitData
+	tomb      tomb.Tomb
+	fw        *Firewaller
+	service   *apifirewaller.Service
+	exposed   bool
+	endpoints map[string]params.ExposedEndpoint
+	unitds    map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the service's exposed flag for changes.
-func (sd *serviceData) watchLoop(exposed bool) {
+// watchLoop watches the service's exposed flag and exposed endpoints
+// for changes.
+func (sd *serviceData) watchLoop(exposed bool, endpoints map[string]params.ExposedEndpoint) {
 	defer sd.tomb.Done()
 	w, err := sd.service.Watch()
 	if err != nil {
@@ -806,12 +817,18 @@ func (sd *serviceData) watchLoop(exposed bool) {
 				sd.fw.tomb.Kill(err)
 				return
 			}
-			if change == exposed {
+			changedEndpoints, err := sd.service.ExposedEndpoints()
+			if err != nil {
+				sd.fw.tomb.Kill(err)
+				return
+			}
+			if change == exposed && reflect.DeepEqual(changedEndpoints, endpoints) {
 				continue
 			}
 			exposed = change
+			endpoints = changedEndpoints
 			select {
-			case sd.fw.exposedChange <- &exposedChange{sd, change}:
+			case sd.fw.exposedChange <- &exposedChange{sd, change, changedEndpoints}:
 			case <-sd.tomb.Dying():
 				return
 			}
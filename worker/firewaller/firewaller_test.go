@@ -199,6 +199,42 @@ func (s *InstanceModeSuite) TestExposedService(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), []network.PortRange{{8080, 8080, "tcp"}})
 }
 
+func (s *InstanceModeSuite) TestExposedEndpoints(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"juju-info": {Ports: []string{"443/tcp", "8000-8100/tcp"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	u, m := s.addUnit(c, svc)
+	inst := s.startInstance(c, m)
+
+	err = u.OpenPort("tcp", 443)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("tcp", 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("tcp", 9000)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only ports of the exposed endpoint are opened.
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{443, 443, "tcp"}, {8080, 8080, "tcp"}})
+
+	// Ports restricted to source networks are kept closed.
+	err = svc.SetExposedEndpoints(map[string]state.ExposedEndpoint{
+		"juju-info": {Ports: []string{"443/tcp"}, CIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), nil)
+
+	// Exposing the whole service opens everything.
+	err = svc.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, m.Id(), []network.PortRange{{443, 443, "tcp"}, {8080, 8080, "tcp"}, {9000, 9000, "tcp"}})
+}
+
 func (s *InstanceModeSuite) TestMultipleExposedServices(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
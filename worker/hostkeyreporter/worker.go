@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hostkeyreporter provides a worker that reports a machine's
+// SSH host keys to the API server, so that clients connecting to the
+// machine can verify its identity.
+package hostkeyreporter
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.hostkeyreporter")
+
+// Facade is the API the worker uses to report the keys.
+type Facade interface {
+	ReportKeys(machineId string, publicKeys []string) error
+}
+
+// New returns a worker that reads the public SSH host keys of the
+// machine from under rootDir, reports them once, and then waits to be
+// stopped. If there are no keys to report, the worker reports nothing
+// and just waits to be stopped.
+func New(facade Facade, machineId, rootDir string) worker.Worker {
+	return worker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
+		keys, err := readSSHKeys(rootDir)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(keys) == 0 {
+			logger.Warningf("no SSH host keys found for machine %s", machineId)
+		} else if err := facade.ReportKeys(machineId, keys); err != nil {
+			return errors.Annotate(err, "cannot report SSH host keys")
+		} else {
+			logger.Infof("%d SSH host keys reported for machine %s", len(keys), machineId)
+		}
+		<-stopCh
+		return tomb.ErrDying
+	})
+}
+
+func readSSHKeys(rootDir string) ([]string, error) {
+	filenames, err := filepath.Glob(filepath.Join(rootDir, "etc", "ssh", "ssh_host_*_key.pub"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	keys := make([]string, 0, len(filenames))
+	for _, filename := range filenames {
+		key, err := ioutil.ReadFile(filename)
+		if err != nil {
+			logger.Warningf("cannot read SSH host key: %v", err)
+			continue
+		}
+		keys = append(keys, strings.TrimSpace(string(key)))
+	}
+	return keys, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hostkeyreporter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/hostkeyreporter"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	rootDir string
+	facade  *fakeFacade
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.rootDir = c.MkDir()
+	sshDir := filepath.Join(s.rootDir, "etc", "ssh")
+	err := os.MkdirAll(sshDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, name := range []string{"rsa", "dsa", "ecdsa"} {
+		filename := filepath.Join(sshDir, "ssh_host_"+name+"_key")
+		err := ioutil.WriteFile(filename, []byte("private"), 0600)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(filename+".pub", []byte(name+"\n"), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	s.facade = &fakeFacade{reported: make(chan []string, 1)}
+}
+
+func (s *workerSuite) TestReportsKeys(c *gc.C) {
+	w := hostkeyreporter.New(s.facade, "42", s.rootDir)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+	select {
+	case keys := <-s.facade.reported:
+		c.Assert(keys, jc.SameContents, []string{"rsa", "dsa", "ecdsa"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for keys to be reported")
+	}
+	c.Assert(s.facade.machineId, gc.Equals, "42")
+}
+
+func (s *workerSuite) TestNoKeys(c *gc.C) {
+	w := hostkeyreporter.New(s.facade, "42", c.MkDir())
+	select {
+	case keys := <-s.facade.reported:
+		c.Fatalf("unexpected keys reported: %v", keys)
+	case <-time.After(coretesting.ShortWait):
+	}
+	w.Kill()
+	c.Assert(w.Wait(), jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestReportError(c *gc.C) {
+	s.facade.err = errors.New("boom")
+	w := hostkeyreporter.New(s.facade, "42", s.rootDir)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot report SSH host keys: boom")
+}
+
+type fakeFacade struct {
+	machineId string
+	reported  chan []string
+	err       error
+}
+
+func (f *fakeFacade) ReportKeys(machineId string, publicKeys []string) error {
+	f.machineId = machineId
+	f.reported <- publicKeys
+	return f.err
+}
@@ -0,0 +1,10 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancedrift
+
+var (
+	CheckPeriod       = &checkPeriod
+	NewInstanceLister = &newInstanceLister
+	NewStateWorker    = newWorker
+)
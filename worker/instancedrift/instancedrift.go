@@ -0,0 +1,216 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package instancedrift provides a worker that compares the instances
+// recorded for the environment's machines with the instances the
+// provider knows of, marking machines whose instances have gone
+// missing and reporting instances that no machine accounts for.
+package instancedrift
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.instancedrift")
+
+// checkPeriod is the interval at which the recorded instances are
+// compared with the provider's.
+var checkPeriod = 5 * time.Minute
+
+// missedChecks is the number of consecutive checks in which an
+// instance must be missing from the provider, or from state, before it
+// is reported, so that instances that are still being started or
+// stopped, or that the provider is slow to list, are not reported.
+const missedChecks = 2
+
+// State defines the state methods used by the worker.
+type State interface {
+	// EnvironConfig returns the environment's configuration.
+	EnvironConfig() (*config.Config, error)
+
+	// ProviderMachines returns the alive machines whose instances
+	// are expected to be known to the provider.
+	ProviderMachines() ([]Machine, error)
+}
+
+// Machine defines the machine methods used by the worker.
+type Machine interface {
+	Id() string
+	InstanceId() (instance.Id, error)
+	Status() (state.StatusInfo, error)
+	SetStatus(status state.Status, info string, data map[string]interface{}) error
+}
+
+// InstanceLister lists the provider's instances for the environment.
+type InstanceLister interface {
+	AllInstances() ([]instance.Instance, error)
+}
+
+// newInstanceLister returns the provider's instance lister for the
+// given configuration. It may be replaced in tests.
+var newInstanceLister = func(cfg *config.Config) (InstanceLister, error) {
+	return environs.New(cfg)
+}
+
+// NewWorker returns a worker that periodically compares the instances
+// of the environment's machines with those of the provider. A machine
+// whose instance is missing is given the status "instance-missing",
+// until the instance is found again; instances carrying the
+// environment's tags that belong to no machine are logged.
+func NewWorker(st *state.State) worker.Worker {
+	return newWorker(stateShim{st})
+}
+
+func newWorker(st State) worker.Worker {
+	d := &detector{
+		st:        st,
+		missing:   make(map[string]int),
+		untracked: make(map[instance.Id]int),
+	}
+	return worker.NewPeriodicWorker(d.check, checkPeriod)
+}
+
+type detector struct {
+	st State
+
+	// missing holds the number of consecutive checks in which the
+	// instance of each machine was not found, keyed by machine id.
+	missing map[string]int
+
+	// untracked holds the number of consecutive checks in which each
+	// instance was found without a machine.
+	untracked map[instance.Id]int
+}
+
+func (d *detector) check(stop <-chan struct{}) error {
+	cfg, err := d.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	lister, err := newInstanceLister(cfg)
+	if err != nil {
+		return errors.Annotate(err, "cannot open environment")
+	}
+	insts, err := lister.AllInstances()
+	if err != nil && err != environs.ErrNoInstances {
+		return errors.Annotate(err, "cannot list instances")
+	}
+	live := make(map[instance.Id]bool)
+	for _, inst := range insts {
+		live[inst.Id()] = true
+	}
+	machines, err := d.st.ProviderMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tracked := make(map[instance.Id]bool)
+	missing := make(map[string]int)
+	for _, m := range machines {
+		id, err := m.InstanceId()
+		if errors.IsNotProvisioned(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if isManual(id) {
+			continue
+		}
+		tracked[id] = true
+		if live[id] {
+			if err := d.found(m, id); err != nil {
+				return errors.Trace(err)
+			}
+			continue
+		}
+		missing[m.Id()] = d.missing[m.Id()] + 1
+		if missing[m.Id()] < missedChecks {
+			continue
+		}
+		if err := d.lost(m, id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	d.missing = missing
+
+	untracked := make(map[instance.Id]int)
+	for id := range live {
+		if tracked[id] || isManual(id) {
+			continue
+		}
+		untracked[id] = d.untracked[id] + 1
+		if untracked[id] == missedChecks {
+			logger.Warningf("instance %q belongs to the environment but not to any machine", id)
+		}
+	}
+	d.untracked = untracked
+	return nil
+}
+
+// isManual reports whether the instance with the given id belongs to
+// a manually provisioned machine, which the provider does not manage.
+func isManual(id instance.Id) bool {
+	return strings.HasPrefix(string(id), "manual:")
+}
+
+// lost marks the given machine as having lost its instance, unless it
+// is already marked.
+func (d *detector) lost(m Machine, id instance.Id) error {
+	status, err := m.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status.Status == state.StatusInstanceMissing {
+		return nil
+	}
+	logger.Warningf("instance %q of machine %s not found in the provider", id, m.Id())
+	info := fmt.Sprintf("instance %q not found in the provider", id)
+	return m.SetStatus(state.StatusInstanceMissing, info, nil)
+}
+
+// found clears the mark of the given machine if it was marked as
+// having lost its instance.
+func (d *detector) found(m Machine, id instance.Id) error {
+	status, err := m.Status()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status.Status != state.StatusInstanceMissing {
+		return nil
+	}
+	logger.Infof("instance %q of machine %s found again", id, m.Id())
+	return m.SetStatus(state.StatusStarted, "", nil)
+}
+
+// stateShim adapts a *state.State to the State interface.
+type stateShim struct {
+	*state.State
+}
+
+// ProviderMachines is part of the State interface. Containers are
+// left out, because the provider does not know of their instances.
+func (st stateShim) ProviderMachines() ([]Machine, error) {
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Machine
+	for _, m := range machines {
+		if m.Life() != state.Alive || names.IsContainerMachine(m.Id()) {
+			continue
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instancedrift_test
+
+import (
+	"fmt"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/instancedrift"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	testing.IsolationSuite
+	calls  chan string
+	lister *mockLister
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type mockState struct {
+	cfg      *config.Config
+	machines []instancedrift.Machine
+}
+
+func (st *mockState) EnvironConfig() (*config.Config, error) {
+	return st.cfg, nil
+}
+
+func (st *mockState) ProviderMachines() ([]instancedrift.Machine, error) {
+	return st.machines, nil
+}
+
+type mockMachine struct {
+	mu         sync.Mutex
+	id         string
+	instanceId instance.Id
+	status     state.Status
+	calls      chan<- string
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if m.instanceId == "" {
+		return "", errors.NotProvisionedf("machine %s", m.id)
+	}
+	return m.instanceId, nil
+}
+
+func (m *mockMachine) Status() (state.StatusInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return state.StatusInfo{Status: m.status}, nil
+}
+
+func (m *mockMachine) SetStatus(status state.Status, info string, data map[string]interface{}) error {
+	m.mu.Lock()
+	m.status = status
+	m.mu.Unlock()
+	m.calls <- fmt.Sprintf("%s %s %s", m.id, status, info)
+	return nil
+}
+
+type mockInstance struct {
+	instance.Instance
+	id instance.Id
+}
+
+func (inst mockInstance) Id() instance.Id {
+	return inst.id
+}
+
+type mockLister struct {
+	mu  sync.Mutex
+	ids []instance.Id
+	err error
+}
+
+func (l *mockLister) AllInstances() ([]instance.Instance, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return nil, l.err
+	}
+	if len(l.ids) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+	insts := make([]instance.Instance, len(l.ids))
+	for i, id := range l.ids {
+		insts[i] = mockInstance{id: id}
+	}
+	return insts, nil
+}
+
+func (l *mockLister) setInstances(ids ...instance.Id) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.ids = ids
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.calls = make(chan string, 10)
+	s.lister = &mockLister{}
+	s.PatchValue(instancedrift.CheckPeriod, coretesting.ShortWait)
+	s.PatchValue(instancedrift.NewInstanceLister, func(*config.Config) (instancedrift.InstanceLister, error) {
+		return s.lister, nil
+	})
+}
+
+func (s *workerSuite) newState(c *gc.C) *mockState {
+	return &mockState{
+		cfg: coretesting.EnvironConfig(c),
+		machines: []instancedrift.Machine{
+			&mockMachine{id: "0", instanceId: "i-0", status: state.StatusStarted, calls: s.calls},
+			&mockMachine{id: "1", instanceId: "i-1", status: state.StatusStarted, calls: s.calls},
+			&mockMachine{id: "2", status: state.StatusPending, calls: s.calls},
+			&mockMachine{id: "3", instanceId: "manual:10.0.0.3", status: state.StatusStarted, calls: s.calls},
+		},
+	}
+}
+
+func (s *workerSuite) assertCall(c *gc.C, expect string) {
+	select {
+	case call := <-s.calls:
+		c.Assert(call, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %q", expect)
+	}
+}
+
+func (s *workerSuite) assertNoCall(c *gc.C) {
+	select {
+	case call := <-s.calls:
+		c.Fatalf("unexpected call %q", call)
+	case <-time.After(coretesting.ShortWait * 5):
+	}
+}
+
+func (s *workerSuite) TestMarksMissingInstances(c *gc.C) {
+	s.lister.setInstances("i-0", "i-untracked")
+	w := instancedrift.NewStateWorker(s.newState(c))
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.assertCall(c, `1 instance-missing instance "i-1" not found in the provider`)
+	// A machine already marked is not marked again.
+	s.assertNoCall(c)
+
+	s.lister.setInstances("i-0", "i-1")
+	s.assertCall(c, "1 started ")
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestNoInstances(c *gc.C) {
+	st := s.newState(c)
+	st.machines = st.machines[2:]
+	w := instancedrift.NewStateWorker(st)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestListError(c *gc.C) {
+	s.lister.err = errors.New("boom")
+	w := instancedrift.NewStateWorker(s.newState(c))
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot list instances: boom")
+}
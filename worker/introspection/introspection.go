@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package introspection serves reports on the workers of an agent, over
+// a unix socket local to the agent's machine, to help debug agents that
+// are stuck.
+package introspection
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"runtime"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.introspection")
+
+// WorkersHandler returns an HTTP handler that serves the reports of
+// the given reporter as a JSON list.
+func WorkersHandler(reporter worker.Reporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reports := reporter.Report()
+		if reports == nil {
+			reports = []worker.WorkerReport{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(reports); err != nil {
+			logger.Debugf("cannot write worker reports: %v", err)
+		}
+	})
+}
+
+// SocketName returns the name of the introspection socket for the
+// agent with the given tag. On linux the socket is in the abstract
+// namespace, so it has no file and needs no cleaning up.
+func SocketName(agentTag string) string {
+	return "@jujud-" + agentTag
+}
+
+// SocketConfig holds the configuration of an introspection socket
+// worker.
+type SocketConfig struct {
+	// SocketName is the name of the unix socket to listen on.
+	SocketName string
+
+	// Reporter reports on the workers of the agent.
+	Reporter worker.Reporter
+}
+
+// NewSocketWorker returns a worker that serves the agent's worker
+// reports at /workers, over a unix socket, until it is stopped.
+func NewSocketWorker(config SocketConfig) (worker.Worker, error) {
+	if runtime.GOOS != "linux" {
+		return nil, errors.NotSupportedf("introspection socket on %s", runtime.GOOS)
+	}
+	if config.Reporter == nil {
+		return nil, errors.NotValidf("nil Reporter")
+	}
+	listener, err := net.Listen("unix", config.SocketName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot listen on %q", config.SocketName)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/workers", WorkersHandler(config.Reporter))
+	w := &socketWorker{listener: listener}
+	go func() {
+		defer w.tomb.Done()
+		w.tomb.Kill(w.serve(mux))
+	}()
+	return w, nil
+}
+
+type socketWorker struct {
+	tomb     tomb.Tomb
+	listener net.Listener
+}
+
+func (w *socketWorker) serve(handler http.Handler) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- http.Serve(w.listener, handler)
+	}()
+	select {
+	case <-w.tomb.Dying():
+		w.listener.Close()
+		<-served
+		return tomb.ErrDying
+	case err := <-served:
+		return errors.Annotate(err, "introspection socket failed")
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *socketWorker) Kill() {
+	w.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *socketWorker) Wait() error {
+	return w.tomb.Wait()
+}
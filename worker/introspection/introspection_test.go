@@ -0,0 +1,108 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/introspection"
+)
+
+type workersSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&workersSuite{})
+
+var testReports = []worker.WorkerReport{{
+	Name:       "api-caller",
+	State:      worker.StateStarted,
+	StartCount: 1,
+}, {
+	Name:       "uniter",
+	State:      worker.StateStopped,
+	Inputs:     []string{"api-caller"},
+	StartCount: 3,
+	LastError:  "hook failed",
+}}
+
+type reporterFunc func() []worker.WorkerReport
+
+func (f reporterFunc) Report() []worker.WorkerReport {
+	return f()
+}
+
+func getReports(c *gc.C, client *http.Client, url string) []worker.WorkerReport {
+	resp, err := client.Get(url)
+	c.Assert(err, jc.ErrorIsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), gc.Equals, "application/json")
+	var reports []worker.WorkerReport
+	err = json.NewDecoder(resp.Body).Decode(&reports)
+	c.Assert(err, jc.ErrorIsNil)
+	return reports
+}
+
+func (s *workersSuite) TestWorkersHandler(c *gc.C) {
+	reporter := reporterFunc(func() []worker.WorkerReport { return testReports })
+	server := httptest.NewServer(introspection.WorkersHandler(reporter))
+	defer server.Close()
+	reports := getReports(c, http.DefaultClient, server.URL)
+	c.Assert(reports, jc.DeepEquals, testReports)
+}
+
+func (s *workersSuite) TestWorkersHandlerNoReports(c *gc.C) {
+	reporter := reporterFunc(func() []worker.WorkerReport { return nil })
+	server := httptest.NewServer(introspection.WorkersHandler(reporter))
+	defer server.Close()
+	reports := getReports(c, http.DefaultClient, server.URL)
+	c.Assert(reports, gc.NotNil)
+	c.Assert(reports, gc.HasLen, 0)
+}
+
+func (s *workersSuite) TestSocketName(c *gc.C) {
+	c.Assert(introspection.SocketName("machine-0"), gc.Equals, "@jujud-machine-0")
+}
+
+func (s *workersSuite) TestSocketWorker(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("introspection socket is only supported on linux")
+	}
+	socketName := fmt.Sprintf("@jujud-introspection-test-%d", os.Getpid())
+	w, err := introspection.NewSocketWorker(introspection.SocketConfig{
+		SocketName: socketName,
+		Reporter:   reporterFunc(func() []worker.WorkerReport { return testReports }),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() { c.Check(worker.Stop(w), jc.ErrorIsNil) }()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Dial: func(string, string) (net.Conn, error) {
+				return net.Dial("unix", socketName)
+			},
+		},
+	}
+	reports := getReports(c, client, "http://introspection/workers")
+	c.Assert(reports, jc.DeepEquals, testReports)
+}
+
+func (s *workersSuite) TestSocketWorkerNeedsReporter(c *gc.C) {
+	_, err := introspection.NewSocketWorker(introspection.SocketConfig{
+		SocketName: "@jujud-introspection-test",
+	})
+	c.Assert(err, gc.NotNil)
+}
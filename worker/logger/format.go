@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+var (
+	formatMu sync.RWMutex
+	// jsonFields is nil unless log messages should be written as
+	// JSON, in which case it holds the fields that are added to
+	// every message.
+	jsonFields *params.LogRecord
+)
+
+// SetJSONFormat causes FormatJSON to be used for all log messages written
+// by the agent process, recording the given entity tag and environment
+// UUID with each message.
+func SetJSONFormat(entity, envUUID string) {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	jsonFields = &params.LogRecord{
+		Entity:  entity,
+		EnvUUID: envUUID,
+	}
+}
+
+// SetTextFormat reverts the effect of SetJSONFormat.
+func SetTextFormat() {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+	jsonFields = nil
+}
+
+// JSONFormat reports whether log messages should be written using
+// FormatJSON.
+func JSONFormat() bool {
+	formatMu.RLock()
+	defer formatMu.RUnlock()
+	return jsonFields != nil
+}
+
+// FormatJSON formats a log message as a single line JSON object,
+// including the fields recorded by the last call to SetJSONFormat.
+func FormatJSON(level loggo.Level, module, filename string, line int, timestamp time.Time, message string) string {
+	var record params.LogRecord
+	formatMu.RLock()
+	if jsonFields != nil {
+		record = *jsonFields
+	}
+	formatMu.RUnlock()
+	record.Time = timestamp.UTC()
+	record.Level = level.String()
+	record.Module = module
+	if filename != "" {
+		record.Location = fmt.Sprintf("%s:%d", filepath.Base(filename), line)
+	}
+	record.Message = message
+	data, err := json.Marshal(&record)
+	if err != nil {
+		// There's nothing in the record that cannot be marshalled,
+		// but never lose a message.
+		return fmt.Sprintf("%q", message)
+	}
+	return string(data)
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logger_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/logger"
+)
+
+type formatSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&formatSuite{})
+
+func (s *formatSuite) TearDownTest(c *gc.C) {
+	logger.SetTextFormat()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *formatSuite) TestTextFormatByDefault(c *gc.C) {
+	c.Assert(logger.JSONFormat(), jc.IsFalse)
+}
+
+func (s *formatSuite) TestSetJSONFormat(c *gc.C) {
+	logger.SetJSONFormat("machine-0", "some-uuid")
+	c.Assert(logger.JSONFormat(), jc.IsTrue)
+	logger.SetTextFormat()
+	c.Assert(logger.JSONFormat(), jc.IsFalse)
+}
+
+func (s *formatSuite) TestFormatJSON(c *gc.C) {
+	logger.SetJSONFormat("machine-0", "some-uuid")
+	timestamp := time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC)
+	formatted := logger.FormatJSON(
+		loggo.WARNING, "juju.worker.test", "/path/to/some.go", 42, timestamp, `say "hello"`,
+	)
+	var record params.LogRecord
+	err := json.Unmarshal([]byte(formatted), &record)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(record, jc.DeepEquals, params.LogRecord{
+		Time:     timestamp,
+		Level:    "WARNING",
+		Module:   "juju.worker.test",
+		Location: "some.go:42",
+		Message:  `say "hello"`,
+		Entity:   "machine-0",
+		EnvUUID:  "some-uuid",
+	})
+}
+
+func (s *formatSuite) TestFormatJSONWithoutFields(c *gc.C) {
+	timestamp := time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC)
+	formatted := logger.FormatJSON(loggo.INFO, "juju", "", 0, timestamp, "hi")
+	c.Assert(formatted, gc.Equals,
+		`{"time":"2015-10-01T12:30:00Z","level":"INFO","module":"juju","message":"hi"}`)
+}
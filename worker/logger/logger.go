@@ -9,6 +9,7 @@ import (
 	"github.com/juju/juju/agent"
 	"github.com/juju/juju/api/logger"
 	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/worker"
 )
 
@@ -20,6 +21,7 @@ type Logger struct {
 	api         *logger.State
 	agentConfig agent.Config
 	lastConfig  string
+	lastFormat  string
 }
 
 var _ worker.NotifyWatchHandler = (*Logger)(nil)
@@ -31,6 +33,10 @@ func NewLogger(api *logger.State, agentConfig agent.Config) worker.Worker {
 		api:         api,
 		agentConfig: agentConfig,
 		lastConfig:  loggo.LoggerInfo(),
+		lastFormat:  config.LoggingFormatText,
+	}
+	if JSONFormat() {
+		logger.lastFormat = config.LoggingFormatJSON
 	}
 	log.Debugf("initial log config: %q", logger.lastConfig)
 	return worker.NewNotifyWorker(logger)
@@ -54,6 +60,31 @@ func (logger *Logger) setLogging() {
 			logger.lastConfig = loggingConfig
 		}
 	}
+	logger.setFormat()
+}
+
+func (logger *Logger) setFormat() {
+	format, err := logger.api.LoggingFormat(logger.agentConfig.Tag())
+	if err != nil {
+		// Older servers do not know about the logging format; the
+		// agent keeps writing text.
+		log.Debugf("cannot get logging format: %v", err)
+		return
+	}
+	if format == logger.lastFormat {
+		return
+	}
+	log.Debugf("changing logging format from %q to %q", logger.lastFormat, format)
+	switch format {
+	case config.LoggingFormatJSON:
+		SetJSONFormat(
+			logger.agentConfig.Tag().String(),
+			logger.agentConfig.Environment().Id(),
+		)
+	default:
+		SetTextFormat()
+	}
+	logger.lastFormat = format
 }
 
 func (logger *Logger) SetUp() (watcher.NotifyWatcher, error) {
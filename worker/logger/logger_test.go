@@ -16,6 +16,7 @@ import (
 	apilogger "github.com/juju/juju/api/logger"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/logger"
 )
@@ -71,6 +72,10 @@ func (mock *mockConfig) Tag() names.Tag {
 	return mock.tag
 }
 
+func (mock *mockConfig) Environment() names.EnvironTag {
+	return coretesting.EnvironmentTag
+}
+
 func agentConfig(c *gc.C, tag names.Tag) *mockConfig {
 	return &mockConfig{c: c, tag: tag}
 }
@@ -102,3 +107,39 @@ func (s *LoggerSuite) TestInitialState(c *gc.C) {
 
 	s.waitLoggingInfo(c, expected)
 }
+
+func (s *LoggerSuite) waitJSONFormat(c *gc.C, expected bool) {
+	timeout := time.After(worstCase)
+	for {
+		select {
+		case <-timeout:
+			c.Fatalf("timeout while waiting for logging format to change")
+		case <-time.After(10 * time.Millisecond):
+			if logger.JSONFormat() != expected {
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (s *LoggerSuite) setLoggingFormat(c *gc.C, format string) {
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{"logging-format": format}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *LoggerSuite) TestLoggingFormat(c *gc.C) {
+	defer logger.SetTextFormat()
+	loggingWorker, _ := s.makeLogger(c)
+	defer worker.Stop(loggingWorker)
+	c.Assert(logger.JSONFormat(), jc.IsFalse)
+
+	s.setLoggingFormat(c, "json")
+	s.waitJSONFormat(c, true)
+	formatted := logger.FormatJSON(loggo.INFO, "juju.test", "", 0, time.Now(), "hello")
+	c.Assert(formatted, jc.Contains, `"entity":"`+s.machine.Tag().String()+`"`)
+	c.Assert(formatted, jc.Contains, `"env-uuid":"`+coretesting.EnvironmentTag.Id()+`"`)
+
+	s.setLoggingFormat(c, "text")
+	s.waitJSONFormat(c, false)
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package mongosessionupdater provides a worker that keeps a state
+// server's MongoDB session settings in line with its environment
+// config.
+package mongosessionupdater
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// Updater applies the mongo-pool-limit, mongo-socket-timeout and
+// mongo-sync-timeout settings of the state server environment to the
+// State's MongoDB session whenever they change.
+type Updater struct {
+	st *state.State
+}
+
+// New returns a worker.Worker that updates the MongoDB session
+// settings of the given State, which must be connected to the state
+// server environment.
+func New(st *state.State) worker.Worker {
+	return worker.NewNotifyWorker(&Updater{st: st})
+}
+
+func (u *Updater) SetUp() (watcher.NotifyWatcher, error) {
+	return u.st.WatchForEnvironConfigChanges(), nil
+}
+
+func (u *Updater) Handle() error {
+	cfg, err := u.st.EnvironConfig()
+	if err != nil {
+		return errors.Annotate(err, "cannot read environment config")
+	}
+	u.st.UpdateMongoSessionConfig(cfg)
+	return nil
+}
+
+func (u *Updater) TearDown() error {
+	// Nothing to cleanup, only state is the watcher
+	return nil
+}
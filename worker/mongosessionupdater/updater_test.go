@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package mongosessionupdater_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/mongosessionupdater"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type UpdaterSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&UpdaterSuite{})
+
+var _ worker.NotifyWatchHandler = (*mongosessionupdater.Updater)(nil)
+
+func (s *UpdaterSuite) TestUpdatesSessionConfig(c *gc.C) {
+	u := mongosessionupdater.New(s.State)
+	defer func() { c.Assert(worker.Stop(u), gc.IsNil) }()
+
+	err := s.State.UpdateEnvironConfig(map[string]interface{}{
+		"mongo-sync-timeout": 90,
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		s.State.StartSync()
+		if s.State.MongoSessionConfig().SyncTimeout == 90*time.Second {
+			return
+		}
+	}
+	c.Fatalf("session config not updated")
+}
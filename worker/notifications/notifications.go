@@ -0,0 +1,400 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package notifications provides a worker that posts notifications of
+// events in an environment, such as hook failures, machines going
+// down, services being scaled and completed upgrades, to the
+// environment's webhooks.
+package notifications
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.notifications")
+
+// checkPeriod is the interval at which the agents of the environment
+// are checked for machines that have gone down and completed upgrades,
+// and the scale events of the environment are checked for new ones.
+var checkPeriod = time.Minute
+
+// postTimeout is how long a notification may take to be posted.
+const postTimeout = 30 * time.Second
+
+// httpClient is used to post notifications. It only connects to the
+// addresses that webhooks may be posted to, so that a host name that
+// resolves to a private address cannot be used to reach it.
+var httpClient = &http.Client{
+	Timeout: postTimeout,
+	Transport: &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return dialWebhook(network, addr)
+		},
+	},
+}
+
+var dialWebhook = dialAllowed
+
+// dialAllowed connects to the given address, checking that each of
+// the host's addresses may be posted to.
+func dialAllowed(network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ips) == 0 {
+		return nil, errors.Errorf("no addresses for %q", host)
+	}
+	for _, ip := range ips {
+		if !state.WebhookAddressAllowed(ip) {
+			return nil, errors.Errorf("cannot post to %q: address %s is loopback, link-local or private", host, ip)
+		}
+	}
+	// The checked address is dialled, so that the host name cannot
+	// be resolved again to a different one.
+	return net.DialTimeout(network, net.JoinHostPort(ips[0].String(), port), postTimeout)
+}
+
+// queueSize is the number of notifications that may wait to be
+// posted. Notifications raised while the queue is full are dropped.
+const queueSize = 100
+
+// SignatureHeader is the HTTP header of each notification that holds
+// the hex-encoded HMAC-SHA256 of the request body, keyed with the
+// webhook's secret, when the webhook has a secret.
+const SignatureHeader = "X-Juju-Signature"
+
+// Notification is the body of the request posted to a webhook for
+// each event.
+type Notification struct {
+	// EnvironUUID holds the UUID of the environment in which the
+	// event happened.
+	EnvironUUID string `json:"environ-uuid"`
+
+	// Event holds the name of the event, such as "hook-failed".
+	Event string `json:"event"`
+
+	// Entity holds the tag of the entity the event concerns.
+	Entity string `json:"entity"`
+
+	// Message describes the event.
+	Message string `json:"message,omitempty"`
+
+	// Time holds when the event was seen.
+	Time time.Time `json:"time"`
+}
+
+// NewWorker returns a worker that posts notifications of the events in
+// the environment of the given state to the environment's webhooks.
+// It learns of hook failures from the environment's all-watcher, and
+// checks the agents of the environment's machines periodically.
+func NewWorker(st *state.State) worker.Worker {
+	n := &notifier{
+		st:         st,
+		hookErrors: make(map[string]time.Time),
+		agentAlive: make(map[string]bool),
+		queue:      make(chan delivery, queueSize),
+	}
+	return worker.NewSimpleWorker(n.loop)
+}
+
+// delivery holds a notification to be posted to a webhook.
+type delivery struct {
+	webhookId string
+	event     string
+	url       string
+	secret    string
+	body      []byte
+}
+
+type notifier struct {
+	st *state.State
+
+	// queue holds the notifications waiting to be posted, so that
+	// slow webhooks do not hold up the watching of the environment.
+	queue chan delivery
+
+	// hookErrors holds when each unit whose hook failed entered its
+	// error state, so that each failure is notified once.
+	hookErrors map[string]time.Time
+
+	// agentAlive holds whether the agent of each machine was alive
+	// when last checked.
+	agentAlive map[string]bool
+
+	// upgradedVersion holds the agent version that all the running
+	// machine agents were last seen running.
+	upgradedVersion version.Number
+
+	// scaledSince holds the time of the last scale event notified,
+	// or of the last one recorded when the worker started.
+	scaledSince time.Time
+}
+
+func (n *notifier) loop(stop <-chan struct{}) error {
+	w := n.st.Watch()
+	defer w.Stop()
+	go deliver(n.queue)
+	defer close(n.queue)
+	deltas := make(chan []multiwatcher.Delta)
+	watchErr := make(chan error, 1)
+	go func() {
+		for {
+			d, err := w.Next()
+			if err != nil {
+				watchErr <- err
+				return
+			}
+			select {
+			case deltas <- d:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	// The first check and the first deltas describe the environment
+	// as it is, and are not notified, so that restarting the worker
+	// does not repeat earlier notifications.
+	if err := n.checkAgents(true); err != nil {
+		return errors.Trace(err)
+	}
+	if last, err := n.st.LastScaleEvent(""); err == nil {
+		n.scaledSince = last.Time
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	first := true
+	check := time.After(checkPeriod)
+	for {
+		select {
+		case <-stop:
+			return nil
+		case err := <-watchErr:
+			return errors.Trace(err)
+		case d := <-deltas:
+			if err := n.handleDeltas(d, first); err != nil {
+				return errors.Trace(err)
+			}
+			first = false
+		case <-check:
+			if err := n.checkAgents(false); err != nil {
+				return errors.Trace(err)
+			}
+			if err := n.checkScaleEvents(); err != nil {
+				return errors.Trace(err)
+			}
+			check = time.After(checkPeriod)
+		}
+	}
+}
+
+// handleDeltas notifies the hook failures described by the given
+// deltas.
+func (n *notifier) handleDeltas(deltas []multiwatcher.Delta, quiet bool) error {
+	for _, delta := range deltas {
+		info, ok := delta.Entity.(*multiwatcher.UnitInfo)
+		if !ok {
+			continue
+		}
+		// A hook failure is shown as an error in the workload status.
+		status := info.WorkloadStatus
+		hook, _ := status.Data["hook"].(string)
+		if delta.Removed || status.Current != multiwatcher.Status(state.StatusError) || hook == "" {
+			delete(n.hookErrors, info.Name)
+			continue
+		}
+		var since time.Time
+		if status.Since != nil {
+			since = *status.Since
+		}
+		if last, ok := n.hookErrors[info.Name]; ok && last.Equal(since) {
+			continue
+		}
+		n.hookErrors[info.Name] = since
+		if quiet {
+			continue
+		}
+		if err := n.notify(state.WebhookHookFailed, names.NewUnitTag(info.Name), status.Message); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// checkAgents notifies the machines whose agents have gone down since
+// the last check, and the completion of any upgrade.
+func (n *notifier) checkAgents(quiet bool) error {
+	machines, err := n.st.AllMachines()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := n.st.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	targetVersion, _ := cfg.AgentVersion()
+	upgraded := true
+	agentAlive := make(map[string]bool)
+	for _, m := range machines {
+		if m.Life() != state.Alive {
+			continue
+		}
+		alive, err := m.AgentPresence()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		agentAlive[m.Id()] = alive
+		if n.agentAlive[m.Id()] && !alive && !quiet {
+			message := "agent is not communicating with the server"
+			if err := n.notify(state.WebhookMachineDown, m.Tag(), message); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		// Machines whose agents have not yet started will be
+		// started with the environment's agent version.
+		tools, err := m.AgentTools()
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		if tools.Version.Number != targetVersion {
+			upgraded = false
+		}
+	}
+	n.agentAlive = agentAlive
+
+	if !upgraded || targetVersion == n.upgradedVersion {
+		return nil
+	}
+	previous := n.upgradedVersion
+	n.upgradedVersion = targetVersion
+	if quiet {
+		return nil
+	}
+	message := fmt.Sprintf("upgraded to %s", targetVersion)
+	if previous != version.Zero {
+		message = fmt.Sprintf("upgraded from %s to %s", previous, targetVersion)
+	}
+	return errors.Trace(n.notify(state.WebhookUpgradeComplete, n.st.EnvironTag(), message))
+}
+
+// checkScaleEvents notifies the scale events recorded since the last
+// check.
+func (n *notifier) checkScaleEvents() error {
+	events, err := n.st.ScaleEvents("", n.scaledSince)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, event := range events {
+		message := fmt.Sprintf("scaled from %d to %d units: %s", event.Previous, event.Desired, event.Reason)
+		if err := n.notify(state.WebhookServiceScaled, names.NewServiceTag(event.Service), message); err != nil {
+			return errors.Trace(err)
+		}
+		if event.Time.After(n.scaledSince) {
+			n.scaledSince = event.Time
+		}
+	}
+	return nil
+}
+
+// notify queues a notification of the given event to be posted to each
+// webhook that wants it.
+func (n *notifier) notify(event string, tag names.Tag, message string) error {
+	webhooks, err := n.st.Webhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("%s: %s: %s", event, tag, message)
+	notification := Notification{
+		EnvironUUID: n.st.EnvironUUID(),
+		Event:       event,
+		Entity:      tag.String(),
+		Message:     message,
+		Time:        time.Now().UTC(),
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, webhook := range webhooks {
+		if !webhook.Wants(event) {
+			continue
+		}
+		d := delivery{
+			webhookId: webhook.Id(),
+			event:     event,
+			url:       webhook.URL(),
+			secret:    webhook.Secret(),
+			body:      body,
+		}
+		select {
+		case n.queue <- d:
+		default:
+			logger.Errorf("cannot notify webhook %s of %s: too many notifications waiting", webhook.Id(), event)
+		}
+	}
+	return nil
+}
+
+// deliver posts the queued notifications, one at a time, until the
+// queue is closed. Failures to post are logged but not retried.
+func deliver(queue <-chan delivery) {
+	for d := range queue {
+		if err := post(d.url, d.secret, d.body); err != nil {
+			logger.Errorf("cannot notify webhook %s of %s: %v", d.webhookId, d.event, err)
+		}
+	}
+}
+
+// post posts the given notification body to the given URL, signing it
+// with the given secret if it is not empty.
+func post(url, secret string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature of the given notification body for a
+// webhook with the given secret, as sent in SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
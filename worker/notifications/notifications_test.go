@@ -0,0 +1,204 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package notifications_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/notifications"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type notificationsSuite struct {
+	testing.JujuConnSuite
+	server    *httptest.Server
+	requests  chan notifications.Notification
+	signature chan string
+}
+
+var _ = gc.Suite(&notificationsSuite{})
+
+func (s *notificationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.PatchValue(notifications.CheckPeriod, coretesting.ShortWait)
+	s.requests = make(chan notifications.Notification, 10)
+	s.signature = make(chan string, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		c.Check(err, jc.ErrorIsNil)
+		var notification notifications.Notification
+		err = json.Unmarshal(body, &notification)
+		c.Check(err, jc.ErrorIsNil)
+		signature := r.Header.Get(notifications.SignatureHeader)
+		if signature != "" {
+			c.Check(signature, gc.Equals, notifications.Sign("s3cret", body))
+		}
+		s.signature <- signature
+		s.requests <- notification
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	// Webhooks may not be on loopback addresses, so notifications
+	// to hookURL are sent to the test server.
+	s.PatchValue(notifications.DialWebhook, func(network, addr string) (net.Conn, error) {
+		c.Check(addr, gc.Equals, "hooks.example.com:80")
+		return net.Dial(network, s.server.Listener.Addr().String())
+	})
+}
+
+const hookURL = "http://hooks.example.com/notify"
+
+func (s *notificationsSuite) startWorker(c *gc.C) {
+	w := notifications.NewWorker(s.State)
+	s.AddCleanup(func(c *gc.C) { c.Assert(worker.Stop(w), jc.ErrorIsNil) })
+}
+
+func (s *notificationsSuite) assertNotified(c *gc.C, event, entity, message string) string {
+	timeout := time.After(coretesting.LongWait)
+	for {
+		s.State.StartSync()
+		select {
+		case notification := <-s.requests:
+			c.Assert(notification.EnvironUUID, gc.Equals, s.State.EnvironUUID())
+			c.Assert(notification.Event, gc.Equals, event)
+			c.Assert(notification.Entity, gc.Equals, entity)
+			c.Assert(notification.Message, gc.Equals, message)
+			return <-s.signature
+		case <-time.After(coretesting.ShortWait):
+		case <-timeout:
+			c.Fatalf("timed out waiting for %s notification", event)
+		}
+	}
+}
+
+func (s *notificationsSuite) assertNotNotified(c *gc.C) {
+	s.State.StartSync()
+	select {
+	case notification := <-s.requests:
+		c.Fatalf("unexpected notification %#v", notification)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *notificationsSuite) failHook(c *gc.C, unit *state.Unit, hook string) {
+	err := unit.SetAgentStatus(state.StatusError, `hook failed: "`+hook+`"`, map[string]interface{}{
+		"hook": hook,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *notificationsSuite) TestHookFailed(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "s3cret", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, nil)
+
+	s.startWorker(c)
+	s.assertNotNotified(c)
+
+	s.failHook(c, unit, "install")
+	signature := s.assertNotified(c, "hook-failed", unit.Tag().String(), `hook failed: "install"`)
+	c.Assert(signature, gc.Not(gc.Equals), "")
+	s.assertNotNotified(c)
+}
+
+func (s *notificationsSuite) TestExistingHookFailureNotNotified(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, nil)
+	s.failHook(c, unit, "install")
+
+	s.startWorker(c)
+	s.assertNotNotified(c)
+}
+
+func (s *notificationsSuite) TestEventFilter(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "", []string{state.WebhookMachineDown})
+	c.Assert(err, jc.ErrorIsNil)
+	unit := s.Factory.MakeUnit(c, nil)
+
+	s.startWorker(c)
+	s.failHook(c, unit, "install")
+	s.assertNotNotified(c)
+}
+
+func (s *notificationsSuite) TestMachineDown(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	pinger, err := machine.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+	err = machine.WaitAgentPresence(coretesting.LongWait)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.startWorker(c)
+	s.assertNotNotified(c)
+
+	err = pinger.Kill()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotified(c, "machine-down", machine.Tag().String(), "agent is not communicating with the server")
+}
+
+func (s *notificationsSuite) TestServiceScaled(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "", []string{state.WebhookServiceScaled})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddScaleEvent("wordpress", 1, 2, "before the worker started", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.startWorker(c)
+	s.assertNotNotified(c)
+
+	_, err = s.State.AddScaleEvent("wordpress", 2, 3, "load is high", s.AdminUserTag(c))
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotified(c, "service-scaled", "service-wordpress", "scaled from 2 to 3 units: load is high")
+	s.assertNotNotified(c)
+}
+
+func (s *notificationsSuite) TestUpgradeComplete(c *gc.C) {
+	_, err := s.State.AddWebhook(hookURL, "", []string{state.WebhookUpgradeComplete})
+	c.Assert(err, jc.ErrorIsNil)
+	machine := s.Factory.MakeMachine(c, nil)
+	current := version.Current
+	err = machine.SetAgentVersion(current)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.startWorker(c)
+	s.assertNotNotified(c)
+
+	next := current
+	next.Patch++
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{
+		"agent-version": next.Number.String(),
+	}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNotNotified(c)
+
+	err = machine.SetAgentVersion(next)
+	c.Assert(err, jc.ErrorIsNil)
+	message := "upgraded from " + current.Number.String() + " to " + next.Number.String()
+	s.assertNotified(c, "upgrade-complete", s.State.EnvironTag().String(), message)
+}
+
+func (s *notificationsSuite) TestDialRejectsPrivateAddresses(c *gc.C) {
+	for _, addr := range []string{"127.0.0.1:80", "localhost:80", "[::1]:80", "169.254.169.254:80", "10.0.0.1:443"} {
+		_, err := notifications.DialAllowed("tcp", addr)
+		c.Check(err, gc.ErrorMatches, `cannot post to ".*": address .* is loopback, link-local or private`)
+	}
+}
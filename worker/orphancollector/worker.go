@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package orphancollector provides a worker that periodically removes
+// documents left behind by entities that no longer exist.
+package orphancollector
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.orphancollector")
+
+// DefaultCollectInterval is how often orphaned documents are collected
+// by default.
+const DefaultCollectInterval = time.Hour
+
+// CollectedKinds holds the kinds of inconsistency that the worker
+// repairs. They are all documents whose owners are gone, which nothing
+// else will ever remove; problems that might be fixed by an agent, or
+// that need an operator's judgement, are left for juju check-state.
+var CollectedKinds = []state.InconsistencyKind{
+	state.OrphanedSettings,
+	state.OrphanedLeadershipSettings,
+	state.ZeroRefSettings,
+	state.OrphanedStatus,
+}
+
+// Checker is the part of *state.State used by the worker.
+type Checker interface {
+	CheckConsistency(args state.CheckConsistencyParams) (*state.ConsistencyReport, error)
+}
+
+type collectWorker struct {
+	st       Checker
+	interval time.Duration
+}
+
+// New returns a worker that removes orphaned documents from the
+// environment every interval.
+func New(st Checker, interval time.Duration) worker.Worker {
+	w := &collectWorker{
+		st:       st,
+		interval: interval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+func (w *collectWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.interval):
+			if err := w.collect(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+func (w *collectWorker) collect() error {
+	report, err := w.st.CheckConsistency(state.CheckConsistencyParams{
+		Repair: true,
+		Kinds:  CollectedKinds,
+	})
+	if err != nil {
+		return errors.Annotate(err, "cannot collect orphaned documents")
+	}
+	for _, problem := range report.Inconsistencies {
+		if problem.Repaired {
+			logger.Infof("removed %s %q from %s: %s", problem.Kind, problem.DocID, problem.Collection, problem.Detail)
+		} else {
+			logger.Warningf("could not remove %s %q from %s: %s", problem.Kind, problem.DocID, problem.Collection, problem.Detail)
+		}
+	}
+	return nil
+}
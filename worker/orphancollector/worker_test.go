@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package orphancollector_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/orphancollector"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+type mockChecker struct {
+	err    error
+	called chan state.CheckConsistencyParams
+}
+
+func (m *mockChecker) CheckConsistency(args state.CheckConsistencyParams) (*state.ConsistencyReport, error) {
+	m.called <- args
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &state.ConsistencyReport{
+		Inconsistencies: []state.Inconsistency{{
+			Kind:       state.OrphanedStatus,
+			Collection: "statuses",
+			DocID:      "uuid:m#0",
+			Repairable: true,
+			Repaired:   true,
+		}},
+	}, nil
+}
+
+func (s *WorkerSuite) TestCollects(c *gc.C) {
+	checker := &mockChecker{called: make(chan state.CheckConsistencyParams, 10)}
+	w := orphancollector.New(checker, time.Millisecond)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	}()
+	for i := 0; i < 2; i++ {
+		select {
+		case args := <-checker.called:
+			c.Check(args, jc.DeepEquals, state.CheckConsistencyParams{
+				Repair: true,
+				Kinds:  orphancollector.CollectedKinds,
+			})
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for collection")
+		}
+	}
+}
+
+func (s *WorkerSuite) TestCollectError(c *gc.C) {
+	checker := &mockChecker{
+		err:    errors.New("boom"),
+		called: make(chan state.CheckConsistencyParams, 1),
+	}
+	w := orphancollector.New(checker, time.Millisecond)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot collect orphaned documents: boom")
+}
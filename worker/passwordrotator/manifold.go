@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package passwordrotator
+
+import (
+	"github.com/juju/errors"
+
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/dependency"
+	"github.com/juju/juju/worker/util"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a
+// Manifold will depend.
+type ManifoldConfig util.AgentApiManifoldConfig
+
+// Manifold returns a dependency manifold that runs a password rotator
+// worker, using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return util.AgentApiManifold(util.AgentApiManifoldConfig(config), newWorker)
+}
+
+// newWorker wraps New for the convenience of AgentApiManifold.
+var newWorker = func(agent agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
+	entity, err := apiagent.NewState(apiCaller).Entity(agent.Tag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return New(entity, agent, DefaultRotationInterval), nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package passwordrotator provides a worker that periodically replaces
+// an agent's API password, so that a copy of an old agent.conf does not
+// grant access indefinitely.
+package passwordrotator
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.passwordrotator")
+
+// DefaultRotationInterval is how often an agent's password is replaced.
+const DefaultRotationInterval = 24 * time.Hour
+
+// Entity is the agent's view of its own entity, through which it
+// replaces its password.
+type Entity interface {
+	SetNextPassword(password string) error
+	ConfirmPassword(password string) error
+}
+
+// Agent provides access to the agent's configuration.
+type Agent interface {
+	CurrentConfig() agent.Config
+	ChangeConfig(agent.ConfigMutator) error
+}
+
+var newPassword = utils.RandomPassword
+
+// New returns a worker that replaces the agent's password every
+// interval. The time of the last rotation is recorded in the agent's
+// configuration, so that the interval is measured across restarts of
+// the agent; a password that has never been rotated is replaced
+// straight away. A new password is first recorded in state alongside the
+// current one, then written to the agent's configuration, and only then
+// confirmed; the agent can log in at every step, however the process is
+// interrupted.
+func New(entity Entity, a Agent, interval time.Duration) worker.Worker {
+	w := &rotateWorker{
+		entity:   entity,
+		agent:    a,
+		interval: interval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type rotateWorker struct {
+	entity   Entity
+	agent    Agent
+	interval time.Duration
+
+	// attempted holds the time of the last attempt to rotate the
+	// password, so that an API server that does not support rotation
+	// is not asked again before the interval has passed.
+	attempted time.Time
+}
+
+func (w *rotateWorker) loop(stopCh <-chan struct{}) error {
+	// Complete any rotation that was interrupted after the new
+	// password was written but before it was confirmed.
+	if err := w.confirm(); err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.nextRotation()):
+			w.attempted = time.Now()
+			if err := w.rotate(); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
+
+// nextRotation returns how long to wait before the password is next
+// rotated: until the interval has passed since the last rotation
+// recorded in the agent's configuration, or since the last attempt.
+func (w *rotateWorker) nextRotation() time.Duration {
+	last := w.attempted
+	value := w.agent.CurrentConfig().Value(agent.PasswordRotated)
+	if rotated, err := time.Parse(time.RFC3339, value); err == nil {
+		if rotated.After(last) {
+			last = rotated
+		}
+	} else if value != "" {
+		logger.Warningf("ignoring invalid password rotation time %q", value)
+	}
+	if last.IsZero() {
+		return 0
+	}
+	if delay := last.Add(w.interval).Sub(time.Now()); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+func (w *rotateWorker) confirm() error {
+	password := w.agent.CurrentConfig().APIInfo().Password
+	err := w.entity.ConfirmPassword(password)
+	if params.IsCodeNotImplemented(err) {
+		logger.Debugf("API server does not support password rotation")
+		return nil
+	}
+	return errors.Annotate(err, "cannot confirm agent password")
+}
+
+func (w *rotateWorker) rotate() error {
+	oldPassword := w.agent.CurrentConfig().APIInfo().Password
+	password, err := newPassword()
+	if err != nil {
+		return errors.Annotate(err, "cannot generate agent password")
+	}
+	err = w.entity.SetNextPassword(password)
+	if params.IsCodeNotImplemented(err) {
+		logger.Debugf("API server does not support password rotation")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot set next agent password")
+	}
+	// The configuration is written before the new password is
+	// confirmed so that we never depend on a password we have not
+	// stored.
+	if err := w.agent.ChangeConfig(func(c agent.ConfigSetter) error {
+		c.SetOldPassword(oldPassword)
+		c.SetPassword(password)
+		c.SetValue(agent.PasswordRotated, time.Now().UTC().Format(time.RFC3339))
+		return nil
+	}); err != nil {
+		return errors.Annotate(err, "cannot write agent password")
+	}
+	if err := w.entity.ConfirmPassword(password); err != nil {
+		return errors.Annotate(err, "cannot confirm agent password")
+	}
+	logger.Infof("agent password rotated")
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package passwordrotator_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/passwordrotator"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	entity *mockEntity
+	agent  *mockAgent
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.entity = &mockEntity{calls: make(chan string, 100)}
+	s.agent = &mockAgent{password: "old-password", oldPassword: "older-password"}
+	s.PatchValue(passwordrotator.NewPassword, func() (string, error) {
+		return "new-password", nil
+	})
+}
+
+type mockEntity struct {
+	calls chan string
+
+	mu        sync.Mutex
+	passwords []string
+	errors    []error
+}
+
+func (e *mockEntity) SetNextPassword(password string) error {
+	return e.call("SetNextPassword", password)
+}
+
+func (e *mockEntity) ConfirmPassword(password string) error {
+	return e.call("ConfirmPassword", password)
+}
+
+func (e *mockEntity) call(name, password string) error {
+	e.mu.Lock()
+	e.passwords = append(e.passwords, name+" "+password)
+	var err error
+	if len(e.errors) > 0 {
+		err, e.errors = e.errors[0], e.errors[1:]
+	}
+	e.mu.Unlock()
+	e.calls <- name
+	return err
+}
+
+// checkCalls checks the first calls made to the entity.
+func (e *mockEntity) checkCalls(c *gc.C, expect ...string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c.Assert(len(e.passwords) >= len(expect), jc.IsTrue)
+	c.Assert(e.passwords[:len(expect)], jc.DeepEquals, expect)
+}
+
+type mockAgent struct {
+	mu          sync.Mutex
+	password    string
+	oldPassword string
+	rotated     string
+}
+
+func (a *mockAgent) CurrentConfig() agent.Config {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return mockConfig{password: a.password, rotated: a.rotated}
+}
+
+func (a *mockAgent) ChangeConfig(mutate agent.ConfigMutator) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return mutate(&mockSetter{agent: a})
+}
+
+func (a *mockAgent) passwords() (string, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.password, a.oldPassword
+}
+
+func (a *mockAgent) rotatedTime() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.rotated
+}
+
+type mockConfig struct {
+	agent.Config
+	password string
+	rotated  string
+}
+
+func (c mockConfig) APIInfo() *api.Info {
+	return &api.Info{Password: c.password}
+}
+
+func (c mockConfig) Value(key string) string {
+	if key == agent.PasswordRotated {
+		return c.rotated
+	}
+	return ""
+}
+
+type mockSetter struct {
+	agent.ConfigSetter
+	agent *mockAgent
+}
+
+func (s *mockSetter) SetPassword(password string) {
+	s.agent.password = password
+}
+
+func (s *mockSetter) SetOldPassword(password string) {
+	s.agent.oldPassword = password
+}
+
+func (s *mockSetter) SetValue(key, value string) {
+	if key == agent.PasswordRotated {
+		s.agent.rotated = value
+	}
+}
+
+func (s *workerSuite) waitCall(c *gc.C, name string) {
+	select {
+	case call := <-s.entity.calls:
+		c.Assert(call, gc.Equals, name)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %s", name)
+	}
+}
+
+func (s *workerSuite) assertNoCall(c *gc.C) {
+	select {
+	case call := <-s.entity.calls:
+		c.Fatalf("unexpected call %s", call)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *workerSuite) TestConfirmsOnStart(c *gc.C) {
+	s.agent.rotated = time.Now().UTC().Format(time.RFC3339)
+	w := passwordrotator.New(s.entity, s.agent, time.Hour)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitCall(c, "ConfirmPassword")
+	s.entity.checkCalls(c, "ConfirmPassword old-password")
+
+	// The password was rotated recently, so it is not rotated again
+	// until the interval has passed.
+	s.assertNoCall(c)
+}
+
+func (s *workerSuite) TestRotatesNeverRotatedPassword(c *gc.C) {
+	before := time.Now().UTC().Add(-time.Second)
+	w := passwordrotator.New(s.entity, s.agent, time.Hour)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitCall(c, "ConfirmPassword")
+	s.waitCall(c, "SetNextPassword")
+	s.waitCall(c, "ConfirmPassword")
+
+	password, _ := s.agent.passwords()
+	c.Assert(password, gc.Equals, "new-password")
+	rotated, err := time.Parse(time.RFC3339, s.agent.rotatedTime())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rotated.Before(before), jc.IsFalse)
+}
+
+func (s *workerSuite) TestRotatesOverduePassword(c *gc.C) {
+	// The agent was restarted after the interval had passed since
+	// the last rotation.
+	s.agent.rotated = time.Now().UTC().Add(-2 * time.Hour).Format(time.RFC3339)
+	w := passwordrotator.New(s.entity, s.agent, time.Hour)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitCall(c, "ConfirmPassword")
+	s.waitCall(c, "SetNextPassword")
+	s.waitCall(c, "ConfirmPassword")
+}
+
+func (s *workerSuite) TestRotates(c *gc.C) {
+	w := passwordrotator.New(s.entity, s.agent, coretesting.ShortWait)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitCall(c, "ConfirmPassword")
+	s.waitCall(c, "SetNextPassword")
+	s.waitCall(c, "ConfirmPassword")
+
+	password, oldPassword := s.agent.passwords()
+	c.Assert(password, gc.Equals, "new-password")
+	c.Assert(oldPassword, gc.Equals, "old-password")
+	s.entity.checkCalls(c,
+		"ConfirmPassword old-password",
+		"SetNextPassword new-password",
+		"ConfirmPassword new-password",
+	)
+}
+
+func (s *workerSuite) TestSetNextPasswordFails(c *gc.C) {
+	s.entity.errors = []error{nil, errors.New("boom")}
+	w := passwordrotator.New(s.entity, s.agent, coretesting.ShortWait)
+
+	s.waitCall(c, "ConfirmPassword")
+	s.waitCall(c, "SetNextPassword")
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot set next agent password: boom")
+
+	// The agent keeps its current password.
+	password, oldPassword := s.agent.passwords()
+	c.Assert(password, gc.Equals, "old-password")
+	c.Assert(oldPassword, gc.Equals, "older-password")
+}
+
+func (s *workerSuite) TestNotImplemented(c *gc.C) {
+	notImplemented := &params.Error{Code: params.CodeNotImplemented}
+	s.entity.errors = []error{notImplemented, notImplemented}
+	w := passwordrotator.New(s.entity, s.agent, coretesting.ShortWait)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	s.waitCall(c, "ConfirmPassword")
+	s.waitCall(c, "SetNextPassword")
+
+	password, _ := s.agent.passwords()
+	c.Assert(password, gc.Equals, "old-password")
+	c.Assert(s.agent.rotatedTime(), gc.Equals, "")
+}
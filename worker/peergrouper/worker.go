@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/pubsub"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker"
 )
@@ -112,17 +113,23 @@ type pgWorker struct {
 }
 
 // New returns a new worker that maintains the mongo replica set
-// with respect to the given state.
-func New(st *state.State) (worker.Worker, error) {
+// with respect to the given state. If hub is not nil, the worker
+// checks the replica set as soon as a machine removal is published
+// on it.
+func New(st *state.State, hub *pubsub.Hub) (worker.Worker, error) {
 	cfg, err := st.EnvironConfig()
 	if err != nil {
 		return nil, err
 	}
-	return newWorker(&stateShim{
+	w := newWorker(&stateShim{
 		State:     st,
 		mongoPort: cfg.StatePort(),
 		apiPort:   cfg.APIPort(),
-	}, newPublisher(st, cfg.PreferIPv6())), nil
+	}, newPublisher(st, cfg.PreferIPv6()))
+	if hub != nil {
+		w.(*pgWorker).subscribe(hub)
+	}
+	return w, nil
 }
 
 func newWorker(st stateInterface, pub publisherInterface) worker.Worker {
@@ -147,6 +154,21 @@ func newWorker(st stateInterface, pub publisherInterface) worker.Worker {
 	return w
 }
 
+// subscribe arranges for the worker to update the replica set
+// whenever a machine removal is published on the hub, until the worker
+// stops.
+func (w *pgWorker) subscribe(hub *pubsub.Hub) {
+	unsubscribe := hub.Subscribe(pubsub.MachineRemovedTopic, func(pubsub.Topic, interface{}) {
+		w.notify(func() (bool, error) {
+			return true, nil
+		})
+	})
+	go func() {
+		w.tomb.Wait()
+		unsubscribe()
+	}()
+}
+
 func (w *pgWorker) Kill() {
 	w.tomb.Kill(nil)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/pubsub"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/peergrouper"
@@ -22,7 +23,7 @@ type workerJujuConnSuite struct {
 var _ = gc.Suite(&workerJujuConnSuite{})
 
 func (s *workerJujuConnSuite) TestStartStop(c *gc.C) {
-	w, err := peergrouper.New(s.State)
+	w, err := peergrouper.New(s.State, pubsub.NewHub())
 	c.Assert(err, jc.ErrorIsNil)
 	err = worker.Stop(w)
 	c.Assert(err, jc.ErrorIsNil)
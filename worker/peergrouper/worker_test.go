@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/pubsub"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
 )
@@ -464,6 +465,40 @@ func (s *workerSuite) TestWorkerRetriesOnPublishError(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestWorkerUpdatesOnMachineRemoved(c *gc.C) {
+	DoTestForIPv4AndIPv6(func(ipVersion TestIPVersion) {
+		s.PatchValue(&pollInterval, coretesting.LongWait+time.Second)
+
+		publishCh := make(chan [][]network.HostPort, 100)
+		publish := func(apiServers [][]network.HostPort, instanceIds []instance.Id) error {
+			publishCh <- apiServers
+			return nil
+		}
+		st := NewFakeState()
+		InitState(c, st, 3, ipVersion)
+
+		hub := pubsub.NewHub()
+		w := newWorker(st, PublisherFunc(publish))
+		w.(*pgWorker).subscribe(hub)
+		defer func() {
+			c.Check(worker.Stop(w), gc.IsNil)
+		}()
+		select {
+		case <-publishCh:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for publish")
+		}
+
+		hub.Publish(pubsub.MachineRemovedTopic, pubsub.MachineRemoved{MachineId: "13"})
+		select {
+		case servers := <-publishCh:
+			AssertAPIHostPorts(c, servers, ExpectedAPIHostPorts(3, ipVersion))
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for publish after machine removal")
+		}
+	})
+}
+
 func (s *workerSuite) TestWorkerPublishesInstanceIds(c *gc.C) {
 	DoTestForIPv4AndIPv6(func(ipVersion TestIPVersion) {
 		s.PatchValue(&pollInterval, coretesting.LongWait+time.Second)
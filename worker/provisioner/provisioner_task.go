@@ -534,6 +534,8 @@ func constructStartInstanceParams(
 		Placement:         provisioningInfo.Placement,
 		DistributionGroup: machine.DistributionGroup,
 		Volumes:           volumes,
+
+		DistributionGroupName: provisioningInfo.DistributionGroupName,
 	}, nil
 }
 
@@ -592,8 +594,21 @@ func (task *provisionerTask) startMachines(machines []*apiprovisioner.Machine) e
 }
 
 func (task *provisionerTask) setErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
+	return task.setErrorStatusWithData(message, machine, err, nil)
+}
+
+// setProviderErrorStatus is like setErrorStatus, but also records the
+// raw error reported by the provider in the machine's status data.
+func (task *provisionerTask) setProviderErrorStatus(message string, machine *apiprovisioner.Machine, err error) error {
+	data := map[string]interface{}{
+		params.ProviderErrorStatusKey: errors.Cause(err).Error(),
+	}
+	return task.setErrorStatusWithData(message, machine, err, data)
+}
+
+func (task *provisionerTask) setErrorStatusWithData(message string, machine *apiprovisioner.Machine, err error, data map[string]interface{}) error {
 	logger.Errorf(message, machine, err)
-	if err1 := machine.SetStatus(params.StatusError, err.Error(), nil); err1 != nil {
+	if err1 := machine.SetStatus(params.StatusError, err.Error(), data); err1 != nil {
 		// Something is wrong with this machine, better report it back.
 		return errors.Annotatef(err1, "cannot set error status for machine %q", machine)
 	}
@@ -644,13 +659,13 @@ func (task *provisionerTask) startMachine(
 			logger.Infof("retryable error received on start instance - retrying instance creation")
 			result, err = task.broker.StartInstance(startInstanceParams)
 			if err != nil {
-				return task.setErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
+				return task.setProviderErrorStatus("cannot start instance for machine after a retry %q: %v", machine, err)
 			}
 		} else {
 			// Set the state to error, so the machine will be skipped next
 			// time until the error is resolved, but don't return an
 			// error; just keep going with the other machines.
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+			return task.setProviderErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
 	}
 
@@ -718,11 +733,11 @@ func volumesToApiserver(volumes []storage.Volume) []params.Volume {
 	result := make([]params.Volume, len(volumes))
 	for i, v := range volumes {
 		result[i] = params.Volume{
-			v.Tag.String(),
-			v.VolumeId,
-			v.HardwareId,
-			v.Size,
-			v.Persistent,
+			VolumeTag:  v.Tag.String(),
+			VolumeId:   v.VolumeId,
+			HardwareId: v.HardwareId,
+			Size:       v.Size,
+			Persistent: v.Persistent,
 		}
 	}
 	return result
@@ -559,6 +559,9 @@ func (s *ProvisionerSuite) TestProvisionerSetsErrorStatusWhenStartInstanceFailed
 		}
 		c.Assert(statusInfo.Status, gc.Equals, state.StatusError)
 		c.Assert(statusInfo.Message, gc.Equals, brokenMsg)
+		c.Assert(statusInfo.Data, jc.DeepEquals, map[string]interface{}{
+			"provider-error": brokenMsg,
+		})
 		break
 	}
 
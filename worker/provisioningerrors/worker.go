@@ -0,0 +1,185 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package provisioningerrors provides a worker that reports machines
+// that have been stuck in provisioning errors for longer than the
+// environment's provisioning-alert-delay setting.
+package provisioningerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.provisioningerrors")
+
+// checkPeriod is the interval at which machines are checked for
+// provisioning errors when no machine status changes, so that machines
+// are reported once they have been stuck for long enough.
+var checkPeriod = time.Minute
+
+// Facade defines the API methods used by the worker.
+type Facade interface {
+	// EnvironConfig returns the environment's configuration.
+	EnvironConfig() (*config.Config, error)
+
+	// WatchMachineStatuses returns a watcher that notifies of the ids
+	// of the machines whose statuses change.
+	WatchMachineStatuses() (apiwatcher.StringsWatcher, error)
+
+	// ProvisioningErrors returns the machines that have been stuck in
+	// provisioning errors for at least the given threshold.
+	ProvisioningErrors(threshold time.Duration) ([]params.ProvisioningError, error)
+}
+
+// Notifier reports a machine stuck in a provisioning error.
+type Notifier interface {
+	Notify(stuck params.ProvisioningError) error
+}
+
+// NewWorker returns a worker that reports each machine stuck in a
+// provisioning error once, by logging it and by posting it to the URL
+// held in the environment's provisioning-alert-url setting, if any.
+func NewWorker(facade Facade) worker.Worker {
+	r := &reporter{
+		facade:   facade,
+		reported: make(map[string]time.Time),
+	}
+	return worker.NewSimpleWorker(r.loop)
+}
+
+type reporter struct {
+	facade Facade
+
+	// reported holds when each reported machine entered its error
+	// state, so that a machine is reported again only if it fails
+	// again.
+	reported map[string]time.Time
+}
+
+func (r *reporter) loop(stop <-chan struct{}) error {
+	w, err := r.facade.WatchMachineStatuses()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case _, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+		case <-time.After(checkPeriod):
+		}
+		if err := r.check(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+func (r *reporter) check() error {
+	cfg, err := r.facade.EnvironConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stuck, err := r.facade.ProvisioningErrors(cfg.ProvisioningAlertDelay())
+	if err != nil {
+		return errors.Annotate(err, "cannot get provisioning errors")
+	}
+	notifier := newNotifier(cfg)
+	current := make(map[string]time.Time)
+	for _, e := range stuck {
+		current[e.MachineTag] = e.Since
+		if since, ok := r.reported[e.MachineTag]; ok && since.Equal(e.Since) {
+			continue
+		}
+		logger.Warningf("%s has been in a provisioning error since %v: %s", e.MachineTag, e.Since, e.Message)
+		if notifier == nil {
+			continue
+		}
+		if err := notifier.Notify(e); err != nil {
+			// The machine will be reported again at the next check.
+			logger.Errorf("cannot report provisioning error of %s: %v", e.MachineTag, err)
+			delete(current, e.MachineTag)
+		}
+	}
+	r.reported = current
+	return nil
+}
+
+// newNotifier returns the Notifier selected by the given environment
+// configuration, or nil if provisioning errors are only logged. It may
+// be replaced in tests.
+var newNotifier = func(cfg *config.Config) Notifier {
+	url := cfg.ProvisioningAlertURL()
+	if url == "" {
+		return nil
+	}
+	uuid, _ := cfg.UUID()
+	return &webhookNotifier{url: url, environUUID: uuid}
+}
+
+// WebhookRequest is the body of the request posted to the URL held in
+// the provisioning-alert-url setting for each stuck machine.
+type WebhookRequest struct {
+	// EnvironUUID holds the UUID of the machine's environment.
+	EnvironUUID string `json:"environ-uuid"`
+
+	// Machine holds the tag of the machine.
+	Machine string `json:"machine"`
+
+	// Message holds the machine's status message.
+	Message string `json:"message"`
+
+	// ProviderError holds the raw error reported by the provider,
+	// when it is known.
+	ProviderError string `json:"provider-error,omitempty"`
+
+	// Since holds when the machine entered its error state.
+	Since time.Time `json:"since"`
+}
+
+// webhookNotifier reports machines by posting them to a URL.
+type webhookNotifier struct {
+	url         string
+	environUUID string
+}
+
+// Notify is part of the Notifier interface.
+func (n *webhookNotifier) Notify(stuck params.ProvisioningError) error {
+	req := WebhookRequest{
+		EnvironUUID: n.environUUID,
+		Machine:     stuck.MachineTag,
+		Message:     stuck.Message,
+		Since:       stuck.Since,
+	}
+	if providerError, ok := stuck.Data[params.ProviderErrorStatusKey].(string); ok {
+		req.ProviderError = providerError
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := http.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("provisioning alert webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioningerrors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/provisioningerrors"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type workerSuite struct {
+	testing.IsolationSuite
+	facade   *mockFacade
+	notified chan params.ProvisioningError
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+type mockWatcher struct {
+	changes chan []string
+}
+
+func (w *mockWatcher) Changes() <-chan []string { return w.changes }
+func (w *mockWatcher) Stop() error              { return nil }
+func (w *mockWatcher) Err() error               { return errors.New("watcher died") }
+
+type mockFacade struct {
+	mu        sync.Mutex
+	cfg       *config.Config
+	watcher   *mockWatcher
+	stuck     []params.ProvisioningError
+	threshold time.Duration
+}
+
+func (f *mockFacade) EnvironConfig() (*config.Config, error) {
+	return f.cfg, nil
+}
+
+func (f *mockFacade) WatchMachineStatuses() (apiwatcher.StringsWatcher, error) {
+	return f.watcher, nil
+}
+
+func (f *mockFacade) ProvisioningErrors(threshold time.Duration) ([]params.ProvisioningError, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.threshold = threshold
+	return append([]params.ProvisioningError(nil), f.stuck...), nil
+}
+
+func (f *mockFacade) setStuck(stuck ...params.ProvisioningError) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stuck = stuck
+}
+
+type mockNotifier struct {
+	notified chan<- params.ProvisioningError
+	err      error
+}
+
+func (n *mockNotifier) Notify(stuck params.ProvisioningError) error {
+	n.notified <- stuck
+	return n.err
+}
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.notified = make(chan params.ProvisioningError, 10)
+	s.facade = &mockFacade{
+		cfg:     coretesting.EnvironConfig(c),
+		watcher: &mockWatcher{changes: make(chan []string, 1)},
+	}
+	s.PatchValue(provisioningerrors.NewNotifier, func(*config.Config) provisioningerrors.Notifier {
+		return &mockNotifier{notified: s.notified}
+	})
+	s.PatchValue(provisioningerrors.CheckPeriod, coretesting.LongWait)
+}
+
+func (s *workerSuite) startWorker(c *gc.C) worker.Worker {
+	w := provisioningerrors.NewWorker(s.facade)
+	s.AddCleanup(func(c *gc.C) {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	})
+	return w
+}
+
+func (s *workerSuite) assertNotified(c *gc.C, expect params.ProvisioningError) {
+	select {
+	case stuck := <-s.notified:
+		c.Assert(stuck, jc.DeepEquals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for notification")
+	}
+}
+
+func (s *workerSuite) assertNotNotified(c *gc.C) {
+	select {
+	case stuck := <-s.notified:
+		c.Fatalf("unexpected notification %#v", stuck)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+var stuckMachine = params.ProvisioningError{
+	MachineTag: "machine-1",
+	Message:    "cannot start instance",
+	Data:       map[string]interface{}{params.ProviderErrorStatusKey: "quota exceeded"},
+	Since:      time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func (s *workerSuite) TestReportsStuckMachinesOnce(c *gc.C) {
+	s.facade.setStuck(stuckMachine)
+	s.startWorker(c)
+	s.facade.watcher.changes <- []string{"1"}
+	s.assertNotified(c, stuckMachine)
+	s.facade.mu.Lock()
+	c.Assert(s.facade.threshold, gc.Equals, 15*time.Minute)
+	s.facade.mu.Unlock()
+
+	s.facade.watcher.changes <- []string{"1"}
+	s.assertNotNotified(c)
+
+	// A machine that fails again is reported again.
+	again := stuckMachine
+	again.Since = stuckMachine.Since.Add(time.Hour)
+	s.facade.setStuck(again)
+	s.facade.watcher.changes <- []string{"1"}
+	s.assertNotified(c, again)
+}
+
+func (s *workerSuite) TestChecksPeriodically(c *gc.C) {
+	s.PatchValue(provisioningerrors.CheckPeriod, coretesting.ShortWait)
+	s.startWorker(c)
+	s.facade.watcher.changes <- []string{}
+	s.assertNotNotified(c)
+
+	s.facade.setStuck(stuckMachine)
+	s.assertNotified(c, stuckMachine)
+}
+
+func (s *workerSuite) TestRetriesFailedNotification(c *gc.C) {
+	s.PatchValue(provisioningerrors.NewNotifier, func(*config.Config) provisioningerrors.Notifier {
+		return &mockNotifier{notified: s.notified, err: errors.New("boom")}
+	})
+	s.facade.setStuck(stuckMachine)
+	s.startWorker(c)
+	s.facade.watcher.changes <- []string{"1"}
+	s.assertNotified(c, stuckMachine)
+	s.facade.watcher.changes <- []string{"1"}
+	s.assertNotified(c, stuckMachine)
+}
+
+func (s *workerSuite) TestWatcherError(c *gc.C) {
+	w := provisioningerrors.NewWorker(s.facade)
+	close(s.facade.watcher.changes)
+	c.Assert(w.Wait(), gc.ErrorMatches, "watcher died")
+}
+
+func (s *workerSuite) TestDefaultNotifierWithoutURL(c *gc.C) {
+	notifier := provisioningerrors.DefaultNewNotifier(s.facade.cfg)
+	c.Assert(notifier, gc.IsNil)
+}
+
+func (s *workerSuite) TestWebhookNotifier(c *gc.C) {
+	requests := make(chan provisioningerrors.WebhookRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.Header.Get("Content-Type"), gc.Equals, "application/json")
+		var req provisioningerrors.WebhookRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		c.Check(err, jc.ErrorIsNil)
+		requests <- req
+	}))
+	defer server.Close()
+
+	cfg, err := s.facade.cfg.Apply(map[string]interface{}{
+		"provisioning-alert-url": server.URL,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	notifier := provisioningerrors.DefaultNewNotifier(cfg)
+	err = notifier.Notify(stuckMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(<-requests, jc.DeepEquals, provisioningerrors.WebhookRequest{
+		EnvironUUID:   coretesting.EnvironmentTag.Id(),
+		Machine:       "machine-1",
+		Message:       "cannot start instance",
+		ProviderError: "quota exceeded",
+		Since:         stuckMachine.Since,
+	})
+}
+
+func (s *workerSuite) TestWebhookNotifierFailure(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg, err := s.facade.cfg.Apply(map[string]interface{}{
+		"provisioning-alert-url": server.URL,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	notifier := provisioningerrors.DefaultNewNotifier(cfg)
+	err = notifier.Notify(stuckMachine)
+	c.Assert(err, gc.ErrorMatches, "provisioning alert webhook returned 500 Internal Server Error")
+}
@@ -82,7 +82,9 @@ func (r *Reboot) Handle() error {
 	logger.Debugf("Reboot worker got action: %v", rAction)
 	switch rAction {
 	case params.ShouldReboot:
-		r.machineLock.Lock(RebootMessage)
+		if err := r.drainHooks(); err != nil {
+			return errors.Trace(err)
+		}
 		return worker.ErrRebootMachine
 	case params.ShouldShutdown:
 		r.machineLock.Lock(RebootMessage)
@@ -91,6 +93,24 @@ func (r *Reboot) Handle() error {
 	return nil
 }
 
+// drainHooks waits for any running hooks to finish before the machine
+// reboots, recording the progress of the reboot as it goes. The
+// uniters hold the machine lock while they run hooks, so once the lock
+// is acquired no hook is running and no new one can start.
+func (r *Reboot) drainHooks() error {
+	if err := r.st.SetRebootStatus(params.RebootDraining); err != nil {
+		return errors.Annotate(err, "cannot set reboot status")
+	}
+	logger.Infof("waiting for running hooks to finish before rebooting")
+	if err := r.machineLock.Lock(RebootMessage); err != nil {
+		return errors.Trace(err)
+	}
+	if err := r.st.SetRebootStatus(params.RebootStarted); err != nil {
+		return errors.Annotate(err, "cannot set reboot status")
+	}
+	return nil
+}
+
 func (r *Reboot) TearDown() error {
 	// nothing to teardown.
 	return nil
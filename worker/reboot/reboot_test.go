@@ -2,6 +2,7 @@ package reboot_test
 
 import (
 	stdtesting "testing"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -117,3 +118,52 @@ func (s *rebootSuite) TestCleanupIsDoneOnBoot(c *gc.C) {
 
 	c.Assert(s.lock.IsLocked(), jc.IsFalse)
 }
+
+func (s *rebootSuite) TestWorkerWaitsForHooksBeforeReboot(c *gc.C) {
+	dir := c.MkDir()
+	hookLock, err := fslock.NewLock(dir, "fake")
+	c.Assert(err, jc.ErrorIsNil)
+	workerLock, err := fslock.NewLock(dir, "fake")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A uniter holds the machine lock while it runs a hook.
+	err = hookLock.Lock("uniter (mysql/0): running hook config-changed")
+	c.Assert(err, jc.ErrorIsNil)
+
+	wrk, err := reboot.NewReboot(s.rebootState, s.AgentConfigForTag(c, s.machine.Tag()), workerLock)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.rebootState.RequestReboot()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		status, err := s.machine.RebootStatus()
+		c.Assert(err, jc.ErrorIsNil)
+		if status == state.RebootDraining {
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("reboot status never became %q", state.RebootDraining)
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- wrk.Wait()
+	}()
+	select {
+	case err := <-done:
+		c.Fatalf("worker exited while a hook was running: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	err = hookLock.Unlock()
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, gc.Equals, worker.ErrRebootMachine)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("worker did not exit after the hook finished")
+	}
+	status, err := s.machine.RebootStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, gc.Equals, state.RebootStarted)
+}
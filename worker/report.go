@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package worker
+
+import (
+	"sort"
+)
+
+// The states a worker may be reported in.
+const (
+	StateStarting = "starting"
+	StateStarted  = "started"
+	StateStopping = "stopping"
+	StateStopped  = "stopped"
+)
+
+// WorkerReport describes a worker maintained by a Runner or a
+// dependency engine.
+type WorkerReport struct {
+	// Name identifies the worker within its runner or engine.
+	Name string `json:"name"`
+
+	// State holds one of StateStarting, StateStarted, StateStopping
+	// or StateStopped.
+	State string `json:"state"`
+
+	// Inputs holds the names of any workers this one depends on.
+	Inputs []string `json:"inputs,omitempty"`
+
+	// StartCount holds the number of times the worker has been
+	// started.
+	StartCount int `json:"start-count"`
+
+	// LastError holds the error with which the worker last stopped,
+	// if any.
+	LastError string `json:"last-error,omitempty"`
+}
+
+// Reporter is implemented by types that can report on the workers
+// they maintain.
+type Reporter interface {
+	// Report returns a report for each worker, ordered by name.
+	Report() []WorkerReport
+}
+
+// SortReports sorts the reports by worker name.
+func SortReports(reports []WorkerReport) {
+	sort.Sort(reportsByName(reports))
+}
+
+type reportsByName []WorkerReport
+
+func (r reportsByName) Len() int           { return len(r) }
+func (r reportsByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r reportsByName) Less(i, j int) bool { return r[i].Name < r[j].Name }
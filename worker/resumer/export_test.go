@@ -14,3 +14,19 @@ func SetInterval(i time.Duration) {
 func RestoreInterval() {
 	interval = defaultInterval
 }
+
+func SetMaxConcurrency(n int) {
+	maxConcurrency = n
+}
+
+func RestoreMaxConcurrency() {
+	maxConcurrency = defaultMaxConcurrency
+}
+
+func SetStuckAge(d time.Duration) {
+	stuckAge = d
+}
+
+func RestoreStuckAge() {
+	stuckAge = defaultStuckAge
+}
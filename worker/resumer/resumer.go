@@ -5,10 +5,15 @@ package resumer
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
 )
 
 var logger = loggo.GetLogger("juju.worker.resumer")
@@ -19,22 +24,55 @@ const defaultInterval = time.Minute
 // interval sets how often the resuming is called.
 var interval = defaultInterval
 
+// defaultMaxConcurrency is the standard value for the maxConcurrency
+// setting.
+const defaultMaxConcurrency = 4
+
+// maxConcurrency limits how many transactions are resumed at once, so
+// that a backlog of pending transactions does not swamp the database.
+var maxConcurrency = defaultMaxConcurrency
+
+// defaultStuckAge is the standard value for the stuckAge setting.
+const defaultStuckAge = 10 * time.Minute
+
+// stuckAge is how long a transaction must have been pending, and
+// failing to resume, before it is reported as stuck.
+var stuckAge = defaultStuckAge
+
 // TransactionResumer defines the interface for types capable to
 // resume transactions.
 type TransactionResumer interface {
-	// ResumeTransactions resumes all pending transactions.
-	ResumeTransactions() error
+	// PendingTransactions returns the transactions that have been
+	// neither applied nor aborted.
+	PendingTransactions() ([]state.PendingTransaction, error)
+
+	// ResumeTransaction resumes the pending transaction with the
+	// given id.
+	ResumeTransaction(id string) error
+}
+
+// StatusSetter defines the interface for types that can report stuck
+// transactions; it is satisfied by *state.Machine.
+type StatusSetter interface {
+	SetStatus(status state.Status, info string, data map[string]interface{}) error
 }
 
 // Resumer is responsible for a periodical resuming of pending transactions.
 type Resumer struct {
-	tomb tomb.Tomb
-	tr   TransactionResumer
+	tomb   tomb.Tomb
+	tr     TransactionResumer
+	status StatusSetter
+
+	// reported holds the ids of the stuck transactions most recently
+	// reported via status.
+	reported []string
 }
 
-// NewResumer periodically resumes pending transactions.
-func NewResumer(tr TransactionResumer) *Resumer {
-	rr := &Resumer{tr: tr}
+// NewResumer periodically resumes pending transactions. Transactions
+// that stay stuck are reported via the status of the given status
+// setter, if it is not nil.
+func NewResumer(tr TransactionResumer, status StatusSetter) *Resumer {
+	rr := &Resumer{tr: tr, status: status}
 	go func() {
 		defer rr.tomb.Done()
 		rr.tomb.Kill(rr.loop())
@@ -65,9 +103,89 @@ func (rr *Resumer) loop() error {
 		case <-rr.tomb.Dying():
 			return tomb.ErrDying
 		case <-time.After(interval):
-			if err := rr.tr.ResumeTransactions(); err != nil {
+			stuck, err := rr.resume()
+			if err != nil {
 				logger.Errorf("cannot resume transactions: %v", err)
+				continue
+			}
+			if err := rr.report(stuck); err != nil {
+				logger.Errorf("cannot report stuck transactions: %v", err)
 			}
 		}
 	}
 }
+
+// resume resumes all pending transactions, no more than maxConcurrency
+// at a time, and returns the ids of those that have been pending for
+// longer than stuckAge and still could not be resumed.
+func (rr *Resumer) resume() ([]string, error) {
+	pending, err := rr.tr.PendingTransactions()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	now := time.Now()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		stuck []string
+	)
+	sem := make(chan struct{}, maxConcurrency)
+	for _, txn := range pending {
+		select {
+		case <-rr.tomb.Dying():
+			wg.Wait()
+			return nil, tomb.ErrDying
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(txn state.PendingTransaction) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := rr.tr.ResumeTransaction(txn.Id)
+			if err == nil {
+				return
+			}
+			logger.Warningf("%v", err)
+			if now.Sub(txn.Started) >= stuckAge {
+				mu.Lock()
+				stuck = append(stuck, txn.Id)
+				mu.Unlock()
+			}
+		}(txn)
+	}
+	wg.Wait()
+	sort.Strings(stuck)
+	return stuck, nil
+}
+
+// report sets the status of the resumer's status setter to describe
+// the given stuck transactions, if they differ from those last
+// reported.
+func (rr *Resumer) report(stuck []string) error {
+	if rr.status == nil || sameStrings(stuck, rr.reported) {
+		return nil
+	}
+	var info string
+	var data map[string]interface{}
+	if len(stuck) > 0 {
+		info = fmt.Sprintf("%d stuck transactions", len(stuck))
+		data = map[string]interface{}{"stuck-transactions": stuck}
+	}
+	if err := rr.status.SetStatus(state.StatusStarted, info, data); err != nil {
+		return errors.Trace(err)
+	}
+	rr.reported = stuck
+	return nil
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
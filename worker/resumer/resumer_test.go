@@ -8,10 +8,12 @@ import (
 	stdtesting "testing"
 	"time"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/resumer"
 )
@@ -29,7 +31,7 @@ var _ = gc.Suite(&ResumerSuite{})
 func (s *ResumerSuite) TestRunStopWithState(c *gc.C) {
 	// Test with state ensures that state fulfills the
 	// TransactionResumer interface.
-	rr := resumer.NewResumer(s.State)
+	rr := resumer.NewResumer(s.State, nil)
 
 	c.Assert(rr.Stop(), gc.IsNil)
 }
@@ -42,7 +44,7 @@ func (s *ResumerSuite) TestResumerCalls(c *gc.C) {
 	defer resumer.RestoreInterval()
 
 	var tr transactionResumerMock
-	rr := resumer.NewResumer(&tr)
+	rr := resumer.NewResumer(&tr, nil)
 	defer func() { c.Assert(rr.Stop(), gc.IsNil) }()
 
 	time.Sleep(10 * testInterval)
@@ -62,16 +64,128 @@ func (s *ResumerSuite) TestResumerCalls(c *gc.C) {
 	}
 }
 
+func (s *ResumerSuite) TestResumerLimitsConcurrency(c *gc.C) {
+	resumer.SetInterval(10 * time.Millisecond)
+	defer resumer.RestoreInterval()
+	resumer.SetMaxConcurrency(2)
+	defer resumer.RestoreMaxConcurrency()
+
+	tr := transactionResumerMock{
+		pending: []state.PendingTransaction{
+			{Id: "a"}, {Id: "b"}, {Id: "c"}, {Id: "d"}, {Id: "e"},
+		},
+		delay: 5 * time.Millisecond,
+	}
+	rr := resumer.NewResumer(&tr, nil)
+	defer func() { c.Assert(rr.Stop(), gc.IsNil) }()
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		tr.mu.Lock()
+		resumed := len(tr.resumed)
+		tr.mu.Unlock()
+		if resumed >= 5 {
+			break
+		}
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	c.Assert(len(tr.resumed) >= 5, jc.IsTrue)
+	c.Assert(tr.maxActive <= 2, jc.IsTrue)
+}
+
+func (s *ResumerSuite) TestResumerReportsStuckTransactions(c *gc.C) {
+	resumer.SetInterval(10 * time.Millisecond)
+	defer resumer.RestoreInterval()
+	resumer.SetStuckAge(time.Minute)
+	defer resumer.RestoreStuckAge()
+
+	tr := transactionResumerMock{
+		pending: []state.PendingTransaction{
+			{Id: "old", Started: time.Now().Add(-time.Hour)},
+			{Id: "new", Started: time.Now()},
+		},
+		err: errors.New("boom"),
+	}
+	status := statusSetterMock{calls: make(chan statusCall, 10)}
+	rr := resumer.NewResumer(&tr, &status)
+	defer func() { c.Assert(rr.Stop(), gc.IsNil) }()
+
+	select {
+	case call := <-status.calls:
+		c.Assert(call, jc.DeepEquals, statusCall{
+			status: state.StatusStarted,
+			info:   "1 stuck transactions",
+			data:   map[string]interface{}{"stuck-transactions": []string{"old"}},
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+
+	// The same stuck transactions are not reported again.
+	select {
+	case call := <-status.calls:
+		c.Fatalf("unexpected status %#v", call)
+	case <-time.After(coretesting.ShortWait):
+	}
+
+	// Once the transactions resume, the report is cleared.
+	tr.mu.Lock()
+	tr.err = nil
+	tr.mu.Unlock()
+	select {
+	case call := <-status.calls:
+		c.Assert(call, jc.DeepEquals, statusCall{status: state.StatusStarted})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+}
+
 // transactionResumerMock is used to check the
-// calls of ResumeTransactions().
+// calls of PendingTransactions() and ResumeTransaction().
 type transactionResumerMock struct {
 	mu         sync.Mutex
 	timestamps []time.Time
+	pending    []state.PendingTransaction
+	delay      time.Duration
+	err        error
+	resumed    []string
+	active     int
+	maxActive  int
 }
 
-func (tr *transactionResumerMock) ResumeTransactions() error {
+func (tr *transactionResumerMock) PendingTransactions() ([]state.PendingTransaction, error) {
 	tr.mu.Lock()
+	defer tr.mu.Unlock()
 	tr.timestamps = append(tr.timestamps, time.Now())
+	return tr.pending, nil
+}
+
+func (tr *transactionResumerMock) ResumeTransaction(id string) error {
+	tr.mu.Lock()
+	tr.active++
+	if tr.active > tr.maxActive {
+		tr.maxActive = tr.active
+	}
 	tr.mu.Unlock()
+	time.Sleep(tr.delay)
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.active--
+	tr.resumed = append(tr.resumed, id)
+	return tr.err
+}
+
+type statusCall struct {
+	status state.Status
+	info   string
+	data   map[string]interface{}
+}
+
+type statusSetterMock struct {
+	calls chan statusCall
+}
+
+func (s *statusSetterMock) SetStatus(status state.Status, info string, data map[string]interface{}) error {
+	s.calls <- statusCall{status, info, data}
 	return nil
 }
@@ -39,11 +39,15 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan []WorkerReport
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 }
 
-var _ Runner = (*runner)(nil)
+var (
+	_ Runner   = (*runner)(nil)
+	_ Reporter = (*runner)(nil)
+)
 
 type startReq struct {
 	id    string
@@ -76,6 +80,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan []WorkerReport),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 	}
@@ -118,6 +123,18 @@ func (runner *runner) StopWorker(id string) error {
 	return ErrDead
 }
 
+// Report is part of the Reporter interface. It returns no reports if
+// the runner is not running.
+func (runner *runner) Report() []WorkerReport {
+	reply := make(chan []WorkerReport, 1)
+	select {
+	case runner.reportc <- reply:
+		return <-reply
+	case <-runner.tomb.Dead():
+	}
+	return nil
+}
+
 func (runner *runner) Wait() error {
 	return runner.tomb.Wait()
 }
@@ -138,6 +155,27 @@ type workerInfo struct {
 	worker       Worker
 	restartDelay time.Duration
 	stopping     bool
+	startCount   int
+	lastErr      error
+}
+
+// report returns a report on the worker with the given id.
+func (info *workerInfo) report(id string) WorkerReport {
+	report := WorkerReport{
+		Name:       id,
+		State:      StateStarting,
+		StartCount: info.startCount,
+	}
+	switch {
+	case info.stopping:
+		report.State = StateStopping
+	case info.worker != nil:
+		report.State = StateStarted
+	}
+	if info.lastErr != nil {
+		report.LastError = info.lastErr.Error()
+	}
+	return report
 }
 
 func (runner *runner) run() error {
@@ -194,6 +232,7 @@ func (runner *runner) run() error {
 		case info := <-runner.startedc:
 			workerInfo := workers[info.id]
 			workerInfo.worker = info.worker
+			workerInfo.startCount++
 			if isDying {
 				killWorker(info.id, workerInfo)
 			}
@@ -204,6 +243,7 @@ func (runner *runner) run() error {
 				break
 			}
 			if info.err != nil {
+				workerInfo.lastErr = info.err
 				if runner.isFatal(info.err) {
 					logger.Errorf("fatal %q: %v", info.id, info.err)
 					if finalError == nil || runner.moreImportant(info.err, finalError) {
@@ -227,6 +267,13 @@ func (runner *runner) run() error {
 			}
 			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
 			workerInfo.restartDelay = RestartDelay
+		case reply := <-runner.reportc:
+			reports := make([]WorkerReport, 0, len(workers))
+			for id, info := range workers {
+				reports = append(reports, info.report(id))
+			}
+			SortReports(reports)
+			reply <- reports
 		}
 	}
 }
@@ -83,6 +83,43 @@ func (*runnerSuite) TestOneWorkerRestart(c *gc.C) {
 	starter.assertStarted(c, false)
 }
 
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer func() { c.Check(worker.Stop(runner), gc.IsNil) }()
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("id", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	for i := 0; i < 2; i++ {
+		starter.die <- fmt.Errorf("error %d", i)
+		starter.assertStarted(c, false)
+		starter.assertStarted(c, true)
+	}
+
+	reporter, ok := runner.(worker.Reporter)
+	c.Assert(ok, jc.IsTrue)
+	expect := []worker.WorkerReport{{
+		Name:       "id",
+		State:      worker.StateStarted,
+		StartCount: 3,
+		LastError:  "error 1",
+	}}
+	var reports []worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		reports = reporter.Report()
+		if len(reports) == 1 && reports[0].StartCount == 3 {
+			break
+		}
+	}
+	c.Assert(reports, jc.DeepEquals, expect)
+}
+
+func (*runnerSuite) TestReportStoppedRunner(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	c.Assert(runner.(worker.Reporter).Report(), gc.HasLen, 0)
+}
+
 func (*runnerSuite) TestOneWorkerStartFatalError(c *gc.C) {
 	runner := worker.NewRunner(allFatal, noImportance)
 	starter := newTestWorkerStarter()
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package singular
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/lease"
+)
+
+// LeaseDuration holds the length of the lease claimed by a lease
+// connection. The lease is renewed on each ping, so this must be
+// comfortably longer than PingInterval; it bounds the time taken for
+// another holder to take over once the master has gone away.
+var LeaseDuration = 30 * time.Second
+
+// LeaseClaimer is implemented by types that leases can be claimed
+// from, such as *state.State. Claims must be consistent across every
+// state server for the lease to pick a single master.
+type LeaseClaimer interface {
+	// ClaimLease claims, or renews, the lease for the namespace on
+	// behalf of the given id, and returns the id of the lease owner.
+	// If the lease is owned by another id, lease.LeaseClaimDeniedErr
+	// is returned.
+	ClaimLease(namespace, id string, forDur time.Duration) (leaseOwnerId string, err error)
+}
+
+// NewLeaseConn returns a Conn whose master is the holder of the lease
+// for the given namespace. The connection is master if the lease can be
+// claimed for holder. Each ping renews the lease, or tries to claim it,
+// and fails if the lease has changed hands since IsMaster was called.
+func NewLeaseConn(claimer LeaseClaimer, namespace, holder string) Conn {
+	return &leaseConn{
+		claimer:   claimer,
+		namespace: namespace,
+		holder:    holder,
+	}
+}
+
+type leaseConn struct {
+	claimer   LeaseClaimer
+	namespace string
+	holder    string
+
+	mu       sync.Mutex
+	claimed  bool
+	isMaster bool
+}
+
+// IsMaster is part of the Conn interface.
+func (c *leaseConn) IsMaster() (bool, error) {
+	isMaster, err := c.claim()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claimed = true
+	c.isMaster = isMaster
+	return isMaster, nil
+}
+
+// Ping is part of the Conn interface.
+func (c *leaseConn) Ping() error {
+	isMaster, err := c.claim()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.claimed || isMaster == c.isMaster {
+		return nil
+	}
+	if isMaster {
+		return errors.Errorf("%q has taken over the lease for %q", c.holder, c.namespace)
+	}
+	return errors.Errorf("%q has lost the lease for %q", c.holder, c.namespace)
+}
+
+// claim tries to claim the lease, and reports whether it is held by
+// this connection's holder.
+func (c *leaseConn) claim() (bool, error) {
+	owner, err := c.claimer.ClaimLease(c.namespace, c.holder, LeaseDuration)
+	switch {
+	case err == lease.LeaseClaimDeniedErr:
+		return false, nil
+	case err != nil:
+		return false, errors.Annotatef(err, "cannot claim lease for %q", c.namespace)
+	}
+	return owner == c.holder, nil
+}
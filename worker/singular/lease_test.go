@@ -0,0 +1,155 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package singular_test
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/singular"
+)
+
+type leaseSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&leaseSuite{})
+
+func (s *leaseSuite) TestIsMaster(c *gc.C) {
+	claimer := &fakeClaimer{}
+	conn0 := singular.NewLeaseConn(claimer, "singular-env", "machine-0")
+	conn1 := singular.NewLeaseConn(claimer, "singular-env", "machine-1")
+
+	isMaster, err := conn0.IsMaster()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isMaster, jc.IsTrue)
+	isMaster, err = conn1.IsMaster()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isMaster, jc.IsFalse)
+	c.Assert(claimer.claims, jc.DeepEquals, []string{"machine-0", "machine-1"})
+	c.Assert(claimer.durations, jc.DeepEquals, []time.Duration{singular.LeaseDuration, singular.LeaseDuration})
+}
+
+func (s *leaseSuite) TestIsMasterError(c *gc.C) {
+	claimer := &fakeClaimer{err: fmt.Errorf("lease manager stopped")}
+	conn := singular.NewLeaseConn(claimer, "singular-env", "machine-0")
+	_, err := conn.IsMaster()
+	c.Assert(err, gc.ErrorMatches, `cannot claim lease for "singular-env": lease manager stopped`)
+}
+
+func (s *leaseSuite) TestPingRenewsLease(c *gc.C) {
+	claimer := &fakeClaimer{}
+	conn := singular.NewLeaseConn(claimer, "singular-env", "machine-0")
+	_, err := conn.IsMaster()
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < 3; i++ {
+		c.Assert(conn.Ping(), jc.ErrorIsNil)
+	}
+	c.Assert(claimer.claims, gc.HasLen, 4)
+}
+
+func (s *leaseSuite) TestPingFailsWhenLeaseLost(c *gc.C) {
+	claimer := &fakeClaimer{}
+	conn := singular.NewLeaseConn(claimer, "singular-env", "machine-0")
+	_, err := conn.IsMaster()
+	c.Assert(err, jc.ErrorIsNil)
+
+	claimer.setOwner("machine-1")
+	err = conn.Ping()
+	c.Assert(err, gc.ErrorMatches, `"machine-0" has lost the lease for "singular-env"`)
+}
+
+func (s *leaseSuite) TestPingFailsWhenLeaseTakenOver(c *gc.C) {
+	claimer := &fakeClaimer{owner: "machine-0"}
+	conn := singular.NewLeaseConn(claimer, "singular-env", "machine-1")
+	isMaster, err := conn.IsMaster()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(isMaster, jc.IsFalse)
+	c.Assert(conn.Ping(), jc.ErrorIsNil)
+
+	// The lease held by machine-0 expires.
+	claimer.setOwner("")
+	err = conn.Ping()
+	c.Assert(err, gc.ErrorMatches, `"machine-1" has taken over the lease for "singular-env"`)
+}
+
+func (s *leaseSuite) TestMasterWorkersStopWhenLeaseLost(c *gc.C) {
+	s.PatchValue(&singular.PingInterval, time.Millisecond)
+	claimer := &fakeClaimer{}
+	conn := singular.NewLeaseConn(claimer, "singular-env", "machine-0")
+	underlyingRunner := worker.NewRunner(
+		func(err error) bool { return true },
+		func(err0, err1 error) bool { return true },
+	)
+	r, err := singular.New(underlyingRunner, conn)
+	c.Assert(err, jc.ErrorIsNil)
+
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	err = r.StartWorker("worker", func() (worker.Worker, error) {
+		return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+			started <- struct{}{}
+			<-stop
+			stopped <- struct{}{}
+			return nil
+		}), nil
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-started:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for worker to start")
+	}
+
+	claimer.setOwner("machine-1")
+	select {
+	case <-stopped:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for worker to stop")
+	}
+	runWithTimeout(c, "wait for underlying runner", func() {
+		err = underlyingRunner.Wait()
+	})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" has lost the lease for "singular-env"`)
+}
+
+// fakeClaimer is a LeaseClaimer that holds a single lease, which is
+// granted to the first claimant while it has no owner.
+type fakeClaimer struct {
+	mu        sync.Mutex
+	owner     string
+	err       error
+	claims    []string
+	durations []time.Duration
+}
+
+func (f *fakeClaimer) ClaimLease(namespace, id string, forDur time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.claims = append(f.claims, id)
+	f.durations = append(f.durations, forDur)
+	if f.err != nil {
+		return "", f.err
+	}
+	if f.owner == "" {
+		f.owner = id
+	}
+	if f.owner != id {
+		return f.owner, lease.LeaseClaimDeniedErr
+	}
+	return id, nil
+}
+
+func (f *fakeClaimer) setOwner(owner string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.owner = owner
+}
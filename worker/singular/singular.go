@@ -43,7 +43,8 @@ type Conn interface {
 // run the workers or not.
 //
 // If conn.IsMaster returns true, any workers started will be started on the
-// underlying runner.
+// underlying runner. They are stopped with the ping error if a ping of
+// the connection fails.
 //
 // If conn.IsMaster returns false, any workers started will actually
 // start do-nothing placeholder workers on the underlying runner
@@ -93,25 +94,58 @@ func (r *runner) pinger() {
 }
 
 func (r *runner) StartWorker(id string, startFunc func() (worker.Worker, error)) error {
+	// Whether or not we are master, start a pinger so that we know
+	// when the connection master changes.
+	r.startPingerOnce.Do(func() {
+		go r.pinger()
+	})
 	if r.isMaster {
 		// We are master; the started workers should
 		// encounter an error as they do what they're supposed
 		// to do - we can just start the worker in the
-		// underlying runner.
+		// underlying runner, stopping it if we cease to be
+		// master.
 		logger.Infof("starting %q", id)
-		return r.Runner.StartWorker(id, startFunc)
+		return r.Runner.StartWorker(id, r.masterWorker(startFunc))
 	}
 	logger.Infof("standby %q", id)
-	// We're not master, so don't start the worker, but start a pinger so
-	// that we know when the connection master changes.
-	r.startPingerOnce.Do(func() {
-		go r.pinger()
-	})
+	// We're not master, so don't start the worker.
 	return r.Runner.StartWorker(id, func() (worker.Worker, error) {
 		return worker.NewSimpleWorker(r.waitPinger), nil
 	})
 }
 
+// masterWorker returns a function that starts a worker with startFunc
+// and stops it again, with the ping error, when the pinger dies.
+func (r *runner) masterWorker(startFunc func() (worker.Worker, error)) func() (worker.Worker, error) {
+	return func() (worker.Worker, error) {
+		w, err := startFunc()
+		if err != nil {
+			return nil, err
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- w.Wait()
+		}()
+		return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+			select {
+			case err := <-done:
+				return err
+			case <-stop:
+			case <-r.pingerDied:
+			}
+			w.Kill()
+			err := <-done
+			select {
+			case <-r.pingerDied:
+				return r.pingErr
+			default:
+			}
+			return err
+		}), nil
+	}
+}
+
 func (r *runner) waitPinger(stop <-chan struct{}) error {
 	select {
 	case <-stop:
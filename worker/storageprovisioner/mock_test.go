@@ -210,19 +210,15 @@ func (v *mockVolumeAccessor) VolumeParams(volumes []names.VolumeTag) ([]params.V
 func (v *mockVolumeAccessor) VolumeAttachmentParams(ids []params.MachineStorageId) ([]params.VolumeAttachmentParamsResult, error) {
 	var result []params.VolumeAttachmentParamsResult
 	for _, id := range ids {
-		if _, ok := v.provisionedAttachments[id]; ok {
-			result = append(result, params.VolumeAttachmentParamsResult{
-				Error: &params.Error{Message: "already provisioned"},
-			})
-		} else {
-			instanceId, _ := v.provisionedMachines[id.MachineTag]
-			result = append(result, params.VolumeAttachmentParamsResult{Result: params.VolumeAttachmentParams{
-				MachineTag: id.MachineTag,
-				VolumeTag:  id.AttachmentTag,
-				InstanceId: string(instanceId),
-				Provider:   "dummy",
-			}})
-		}
+		// Attachment parameters are available whether or not the
+		// attachment is provisioned, so that it can be detached.
+		instanceId, _ := v.provisionedMachines[id.MachineTag]
+		result = append(result, params.VolumeAttachmentParamsResult{Result: params.VolumeAttachmentParams{
+			MachineTag: id.MachineTag,
+			VolumeTag:  id.AttachmentTag,
+			InstanceId: string(instanceId),
+			Provider:   "dummy",
+		}})
 	}
 	return result, nil
 }
@@ -357,16 +353,20 @@ func newMockFilesystemAccessor() *mockFilesystemAccessor {
 }
 
 type mockLifecycleManager struct {
+	remove func([]names.Tag) ([]params.ErrorResult, error)
 }
 
 func (m *mockLifecycleManager) Life(volumes []names.Tag) ([]params.LifeResult, error) {
 	var result []params.LifeResult
 	for _, tag := range volumes {
 		id, _ := strconv.Atoi(tag.Id())
-		if id <= 100 {
+		switch {
+		case id <= 100:
 			result = append(result, params.LifeResult{Life: params.Alive})
-		} else {
+		case id <= 200:
 			result = append(result, params.LifeResult{Life: params.Dying})
+		default:
+			result = append(result, params.LifeResult{Life: params.Dead})
 		}
 	}
 	return result, nil
@@ -393,7 +393,10 @@ func (m *mockLifecycleManager) EnsureDead([]names.Tag) ([]params.ErrorResult, er
 	return nil, nil
 }
 
-func (m *mockLifecycleManager) Remove([]names.Tag) ([]params.ErrorResult, error) {
+func (m *mockLifecycleManager) Remove(tags []names.Tag) ([]params.ErrorResult, error) {
+	if m.remove != nil {
+		return m.remove(tags)
+	}
 	return nil, nil
 }
 
@@ -411,6 +414,9 @@ type dummyProvider struct {
 
 type dummyVolumeSource struct {
 	storage.VolumeSource
+
+	destroyVolumes func([]string) []error
+	detachVolumes  func([]storage.VolumeAttachmentParams) error
 }
 
 type dummyFilesystemSource struct {
@@ -463,6 +469,22 @@ func (*dummyVolumeSource) CreateVolumes(params []storage.VolumeParams) ([]storag
 	return volumes, volumeAttachments, nil
 }
 
+// DestroyVolumes destroys volumes.
+func (s *dummyVolumeSource) DestroyVolumes(volIds []string) []error {
+	if s.destroyVolumes != nil {
+		return s.destroyVolumes(volIds)
+	}
+	return make([]error, len(volIds))
+}
+
+// DetachVolumes detaches volumes from machines.
+func (s *dummyVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) error {
+	if s.detachVolumes != nil {
+		return s.detachVolumes(params)
+	}
+	return nil
+}
+
 // AttachVolumes attaches volumes to machines.
 func (*dummyVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
 	var volumeAttachments []storage.VolumeAttachment
@@ -124,6 +124,153 @@ func (s *storageProvisionerSuite) TestVolumeAdded(c *gc.C) {
 	waitChannel(c, volumeAttachmentInfoSet, "waiting for volume attachments to be set")
 }
 
+func (s *storageProvisionerSuite) TestReleasedVolumeRemovedWithoutDestroying(c *gc.C) {
+	volumeAccessor := newMockVolumeAccessor()
+	// Volumes with ids over 100 are Dying.
+	volumeAccessor.provisionedVolumes["volume-101"] = params.Volume{
+		VolumeTag:  "volume-101",
+		VolumeId:   "vol-101",
+		Persistent: true,
+		Releasing:  true,
+	}
+
+	removed := make(chan []names.Tag, 1)
+	lifecycleManager := &mockLifecycleManager{
+		remove: func(tags []names.Tag) ([]params.ErrorResult, error) {
+			removed <- tags
+			return make([]params.ErrorResult, len(tags)), nil
+		},
+	}
+	environAccessor := newMockEnvironAccessor(c)
+
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		lifecycleManager,
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.volumesWatcher.changes <- []string{"101"}
+	environAccessor.watcher.changes <- struct{}{}
+	select {
+	case tags := <-removed:
+		c.Assert(tags, jc.DeepEquals, []names.Tag{names.NewVolumeTag("101")})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for volume to be removed")
+	}
+}
+
+func (s *storageProvisionerSuite) TestDeadVolumeDestroyed(c *gc.C) {
+	volumeAccessor := newMockVolumeAccessor()
+	// Volumes with ids over 200 are Dead.
+	volumeAccessor.provisionedVolumes["volume-201"] = params.Volume{
+		VolumeTag:  "volume-201",
+		VolumeId:   "vol-201",
+		Persistent: true,
+		Provider:   "dummy",
+	}
+
+	destroyed := make(chan []string, 1)
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		return &dummyVolumeSource{
+			destroyVolumes: func(volIds []string) []error {
+				destroyed <- volIds
+				return make([]error, len(volIds))
+			},
+		}, nil
+	}
+	removed := make(chan []names.Tag, 1)
+	lifecycleManager := &mockLifecycleManager{
+		remove: func(tags []names.Tag) ([]params.ErrorResult, error) {
+			removed <- tags
+			return make([]params.ErrorResult, len(tags)), nil
+		},
+	}
+	environAccessor := newMockEnvironAccessor(c)
+
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		lifecycleManager,
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.volumesWatcher.changes <- []string{"201"}
+	environAccessor.watcher.changes <- struct{}{}
+	select {
+	case volIds := <-destroyed:
+		c.Assert(volIds, jc.DeepEquals, []string{"vol-201"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for volume to be destroyed")
+	}
+	select {
+	case tags := <-removed:
+		c.Assert(tags, jc.DeepEquals, []names.Tag{names.NewVolumeTag("201")})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for volume to be removed")
+	}
+}
+
+func (s *storageProvisionerSuite) TestDyingVolumeAttachmentDetached(c *gc.C) {
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.provisionedVolumes["volume-0"] = params.Volume{
+		VolumeTag: "volume-0",
+		VolumeId:  "vol-0",
+	}
+	volumeAccessor.provisionedMachines["machine-0"] = instance.Id("inst-0")
+	volumeAccessor.provisionedAttachments[dyingVolumeAttachmentId] = params.VolumeAttachment{
+		MachineTag: "machine-0",
+		VolumeTag:  "volume-0",
+	}
+
+	detached := make(chan []storage.VolumeAttachmentParams, 1)
+	s.provider.volumeSourceFunc = func(*config.Config, *storage.Config) (storage.VolumeSource, error) {
+		return &dummyVolumeSource{
+			detachVolumes: func(params []storage.VolumeAttachmentParams) error {
+				detached <- params
+				return nil
+			},
+		}, nil
+	}
+	environAccessor := newMockEnvironAccessor(c)
+
+	worker := storageprovisioner.NewStorageProvisioner(
+		coretesting.EnvironmentTag,
+		"storage-dir",
+		volumeAccessor,
+		newMockFilesystemAccessor(),
+		&mockLifecycleManager{},
+		environAccessor,
+	)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	volumeAccessor.attachmentsWatcher.changes <- []params.MachineStorageId{dyingVolumeAttachmentId}
+	environAccessor.watcher.changes <- struct{}{}
+	select {
+	case params := <-detached:
+		c.Assert(params, jc.DeepEquals, []storage.VolumeAttachmentParams{{
+			AttachmentParams: storage.AttachmentParams{
+				Provider:   "dummy",
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: "inst-0",
+			},
+			Volume:   names.NewVolumeTag("0"),
+			VolumeId: "vol-0",
+		}})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for volume to be detached")
+	}
+}
+
 func (s *storageProvisionerSuite) TestFilesystemAdded(c *gc.C) {
 	expectedFilesystems := []params.Filesystem{{
 		FilesystemTag: "filesystem-1",
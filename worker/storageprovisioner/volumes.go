@@ -111,29 +111,39 @@ func volumeAttachmentsChanged(ctx *context, ids []params.MachineStorageId) error
 // processDeadVolumes processes the VolumeResults for Dead volumes,
 // deprovisioning volumes and removing from state as necessary.
 func processDeadVolumes(ctx *context, tags []names.Tag, volumeResults []params.VolumeResult) error {
-	volumes := make([]params.Volume, len(volumeResults))
+	// Volumes that are being released are left in the cloud, and
+	// only removed from state; the rest are destroyed first.
+	var volumes []params.Volume
+	var destroyTags, removed []names.Tag
 	for i, result := range volumeResults {
 		if result.Error != nil {
 			return errors.Annotatef(result.Error, "getting volume information for volume %q", tags[i].Id())
 		}
-		volumes[i] = result.Result
-	}
-	if len(volumes) == 0 {
-		return nil
-	}
-	errorResults, err := destroyVolumes(volumes)
-	if err != nil {
-		return errors.Annotate(err, "destroying volumes")
-	}
-	destroyed := make([]names.Tag, 0, len(tags))
-	for i, tag := range tags {
-		if err := errorResults[i]; err != nil {
-			logger.Errorf("destroying %s: %v", names.ReadableString(tag), err)
+		if result.Result.Releasing {
+			logger.Debugf("releasing %s", names.ReadableString(tags[i]))
+			removed = append(removed, tags[i])
 			continue
 		}
-		destroyed = append(destroyed, tag)
+		volumes = append(volumes, result.Result)
+		destroyTags = append(destroyTags, tags[i])
+	}
+	if len(volumes) > 0 {
+		errorResults, err := destroyVolumes(ctx, volumes)
+		if err != nil {
+			return errors.Annotate(err, "destroying volumes")
+		}
+		for i, tag := range destroyTags {
+			if err := errorResults[i]; err != nil {
+				logger.Errorf("destroying %s: %v", names.ReadableString(tag), err)
+				continue
+			}
+			removed = append(removed, tag)
+		}
+	}
+	if len(removed) == 0 {
+		return nil
 	}
-	if err := removeEntities(ctx, destroyed); err != nil {
+	if err := removeEntities(ctx, removed); err != nil {
 		return errors.Annotate(err, "removing volumes from state")
 	}
 	return nil
@@ -149,17 +159,15 @@ func processDyingVolumeAttachments(
 	for _, id := range ids {
 		delete(ctx.pendingVolumeAttachments, id)
 	}
-	volumeAttachments := make([]params.VolumeAttachment, len(volumeAttachmentResults))
 	for i, result := range volumeAttachmentResults {
 		if result.Error != nil {
 			return errors.Annotatef(result.Error, "getting information for volume attachment %v", ids[i])
 		}
-		volumeAttachments[i] = result.Result
 	}
-	if len(volumeAttachments) == 0 {
+	if len(ids) == 0 {
 		return nil
 	}
-	errorResults, err := detachVolumes(volumeAttachments)
+	errorResults, err := detachVolumes(ctx, ids)
 	if err != nil {
 		return errors.Annotate(err, "detaching volumes")
 	}
@@ -495,23 +503,128 @@ func setVolumeAttachmentInfo(ctx *context, volumeAttachments []storage.VolumeAtt
 	return nil
 }
 
-func destroyVolumes(volumes []params.Volume) ([]error, error) {
-	panic("not implemented")
+// destroyVolumes destroys the specified volumes using the volume
+// sources of their providers, returning an error for each volume.
+// Volumes of non-dynamic providers are destroyed along with the
+// machines they are attached to, so there is nothing to do for them.
+func destroyVolumes(ctx *context, volumes []params.Volume) ([]error, error) {
+	volumeSources := make(map[string]storage.VolumeSource)
+	indicesBySource := make(map[string][]int)
+	volumeIdsBySource := make(map[string][]string)
+	errs := make([]error, len(volumes))
+	for i, volume := range volumes {
+		sourceName := volume.Provider
+		if sourceName == "" {
+			errs[i] = errors.Errorf("no storage provider recorded for volume %q", volume.VolumeTag)
+			continue
+		}
+		if _, ok := volumeSources[sourceName]; !ok {
+			volumeSource, err := volumeSource(
+				ctx.environConfig, ctx.storageDir, sourceName, storage.ProviderType(sourceName),
+			)
+			if errors.Cause(err) == errNonDynamic {
+				volumeSource = nil
+			} else if err != nil {
+				return nil, errors.Annotate(err, "getting volume source")
+			}
+			volumeSources[sourceName] = volumeSource
+		}
+		if volumeSources[sourceName] == nil {
+			continue
+		}
+		indicesBySource[sourceName] = append(indicesBySource[sourceName], i)
+		volumeIdsBySource[sourceName] = append(volumeIdsBySource[sourceName], volume.VolumeId)
+	}
+	for sourceName, volumeIds := range volumeIdsBySource {
+		sourceErrs := volumeSources[sourceName].DestroyVolumes(volumeIds)
+		if len(sourceErrs) != len(volumeIds) {
+			return nil, errors.Errorf(
+				"expected %d results from source %q, got %d",
+				len(volumeIds), sourceName, len(sourceErrs),
+			)
+		}
+		for j, i := range indicesBySource[sourceName] {
+			errs[i] = sourceErrs[j]
+		}
+	}
+	return errs, nil
 }
 
-func detachVolumes(attachments []params.VolumeAttachment) ([]error, error) {
-	panic("not implemented")
+// detachVolumes detaches the volumes of the specified attachments
+// using the volume sources of their providers, returning an error
+// for each attachment.
+func detachVolumes(ctx *context, ids []params.MachineStorageId) ([]error, error) {
+	paramsResults, err := ctx.volumeAccessor.VolumeAttachmentParams(ids)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting volume attachment params")
+	}
+	volumeTags := make([]names.VolumeTag, len(ids))
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			return nil, errors.Annotatef(result.Error, "getting parameters for volume attachment %v", ids[i])
+		}
+		volumeTags[i], err = names.ParseVolumeTag(result.Result.VolumeTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	volumeResults, err := ctx.volumeAccessor.Volumes(volumeTags)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting volume information")
+	}
+
+	volumeSources := make(map[string]storage.VolumeSource)
+	indicesBySource := make(map[string][]int)
+	paramsBySource := make(map[string][]storage.VolumeAttachmentParams)
+	errs := make([]error, len(ids))
+	for i, result := range paramsResults {
+		if volumeResults[i].Error != nil {
+			errs[i] = errors.Annotatef(volumeResults[i].Error, "getting information for volume %q", volumeTags[i].Id())
+			continue
+		}
+		attachmentParams, err := volumeAttachmentParamsFromParams(result.Result)
+		if err != nil {
+			return nil, errors.Annotate(err, "getting volume attachment parameters")
+		}
+		attachmentParams.VolumeId = volumeResults[i].Result.VolumeId
+		sourceName := string(attachmentParams.Provider)
+		if _, ok := volumeSources[sourceName]; !ok {
+			volumeSource, err := volumeSource(
+				ctx.environConfig, ctx.storageDir, sourceName, attachmentParams.Provider,
+			)
+			if errors.Cause(err) == errNonDynamic {
+				volumeSource = nil
+			} else if err != nil {
+				return nil, errors.Annotate(err, "getting volume source")
+			}
+			volumeSources[sourceName] = volumeSource
+		}
+		if volumeSources[sourceName] == nil {
+			continue
+		}
+		indicesBySource[sourceName] = append(indicesBySource[sourceName], i)
+		paramsBySource[sourceName] = append(paramsBySource[sourceName], attachmentParams)
+	}
+	for sourceName, params := range paramsBySource {
+		// DetachVolumes reports a single error for all of the
+		// attachments it is given.
+		err := volumeSources[sourceName].DetachVolumes(params)
+		for _, i := range indicesBySource[sourceName] {
+			errs[i] = err
+		}
+	}
+	return errs, nil
 }
 
 func volumesFromStorage(in []storage.Volume) []params.Volume {
 	out := make([]params.Volume, len(in))
 	for i, v := range in {
 		out[i] = params.Volume{
-			v.Tag.String(),
-			v.VolumeId,
-			v.HardwareId,
-			v.Size,
-			v.Persistent,
+			VolumeTag:  v.Tag.String(),
+			VolumeId:   v.VolumeId,
+			HardwareId: v.HardwareId,
+			Size:       v.Size,
+			Persistent: v.Persistent,
 		}
 	}
 	return out
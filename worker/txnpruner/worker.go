@@ -0,0 +1,53 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package txnpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+// DefaultPruneInterval is how often transactions are pruned by
+// default.
+const DefaultPruneInterval = time.Hour
+
+// New returns a worker which periodically wakes up to remove completed
+// transactions older than the environment's txn-prune-window setting
+// from the state database. This worker is intended to run just once,
+// on the MongoDB master.
+func New(st *state.State, interval time.Duration) worker.Worker {
+	w := &pruneWorker{
+		st:       st,
+		interval: interval,
+	}
+	return worker.NewSimpleWorker(w.loop)
+}
+
+type pruneWorker struct {
+	st       *state.State
+	interval time.Duration
+}
+
+func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		select {
+		case <-stopCh:
+			return tomb.ErrDying
+		case <-time.After(w.interval):
+			cfg, err := w.st.EnvironConfig()
+			if err != nil {
+				return errors.Annotate(err, "cannot read environment config")
+			}
+			minTxnTime := time.Now().Add(-cfg.TxnPruneWindow())
+			if err := state.PruneTransactions(w.st, minTxnTime); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package txnpruner_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/txnpruner"
+)
+
+func TestPackage(t *stdtesting.T) {
+	testing.MgoTestPackage(t)
+}
+
+var _ = gc.Suite(&suite{})
+
+type suite struct {
+	statetesting.StateSuite
+}
+
+func (s *suite) TestPrunesOldTransactions(c *gc.C) {
+	txns := s.State.MongoSession().DB("juju").C("txns")
+	oldId := bson.NewObjectIdWithTime(time.Now().Add(-48 * time.Hour))
+	err := txns.Insert(bson.D{{"_id", oldId}, {"s", 6}})
+	c.Assert(err, jc.ErrorIsNil)
+	newId := bson.NewObjectId()
+	err = txns.Insert(bson.D{{"_id", newId}, {"s", 6}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	pruner := txnpruner.New(s.State, time.Millisecond)
+	defer func() {
+		pruner.Kill()
+		c.Assert(pruner.Wait(), jc.ErrorIsNil)
+	}()
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		n, err := txns.FindId(oldId).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if n == 0 {
+			n, err := txns.FindId(newId).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(n, gc.Equals, 1)
+			return
+		}
+	}
+	c.Fatalf("old transaction was not pruned")
+}
@@ -16,6 +16,7 @@ var (
 	ActiveMetricsTimer  = &activeMetricsTimer
 	IdleWaitTime        = &idleWaitTime
 	LeadershipGuarantee = &leadershipGuarantee
+	NewWorker           = &newWorker
 )
 
 // manualTicker will be used to generate collect-metrics events
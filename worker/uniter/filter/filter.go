@@ -4,11 +4,14 @@
 package filter
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/hooks"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/api/uniter"
@@ -16,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 var filterLogger = loggo.GetLogger("juju.worker.uniter.filter")
@@ -50,6 +54,10 @@ type filter struct {
 	outMeterStatusOn    chan struct{}
 	outStorage          chan []names.StorageTag
 	outStorageOn        chan []names.StorageTag
+	outUpgradeSeries    chan hooks.Kind
+	outUpgradeSeriesOn  chan hooks.Kind
+	outPaused           chan bool
+	outPausedOn         chan bool
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
 	wantForcedUpgrade  chan bool
@@ -60,6 +68,12 @@ type filter struct {
 	// should be discarded.
 	discardConfig chan struct{}
 
+	// configQuietPeriod is how long the filter waits, after the last of
+	// a run of config or address changes, before it sends a single
+	// config event for them all. A zero period sends events straight
+	// away.
+	configQuietPeriod time.Duration
+
 	// discardLeaderSettings is used to indicate any pending Leader
 	// Settings event should be discarded.
 	discardLeaderSettings chan struct{}
@@ -105,13 +119,31 @@ type filter struct {
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// upgradeSeriesStatus reflects the unit's progress in the series
+	// upgrade of its machine, and upgradeSeriesHook the hook to run
+	// for it.
+	upgradeSeriesStatus params.UpgradeSeriesStatus
+	upgradeSeriesHook   hooks.Kind
+
+	// paused reflects whether the unit has been paused.
+	paused bool
 }
 
 // NewFilter returns a filter that handles state changes pertaining to the
 // supplied unit.
 func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
+	return NewFilterWithQuietPeriod(st, unitTag, 0)
+}
+
+// NewFilterWithQuietPeriod returns a filter that handles state changes
+// pertaining to the supplied unit, and which coalesces config and
+// address changes into a single config event once no further change
+// has been seen for configQuietPeriod.
+func NewFilterWithQuietPeriod(st *uniter.State, unitTag names.UnitTag, configQuietPeriod time.Duration) (Filter, error) {
 	f := &filter{
 		st:                    st,
+		configQuietPeriod:     configQuietPeriod,
 		outUnitDying:          make(chan struct{}),
 		outConfigOn:           make(chan struct{}),
 		outActionOn:           make(chan string),
@@ -121,6 +153,8 @@ func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
 		outRelationsOn:        make(chan []int),
 		outMeterStatusOn:      make(chan struct{}),
 		outStorageOn:          make(chan []names.StorageTag),
+		outUpgradeSeriesOn:    make(chan hooks.Kind),
+		outPausedOn:           make(chan bool),
 		wantForcedUpgrade:     make(chan bool),
 		wantResolved:          make(chan struct{}),
 		wantLeaderSettings:    make(chan bool),
@@ -207,6 +241,19 @@ func (f *filter) StorageEvents() <-chan []names.StorageTag {
 	return f.outStorageOn
 }
 
+// UpgradeSeriesEvents returns a channel that will receive the kind of
+// hook to run whenever the series upgrade of the unit's machine needs
+// the unit to take part.
+func (f *filter) UpgradeSeriesEvents() <-chan hooks.Kind {
+	return f.outUpgradeSeriesOn
+}
+
+// PausedEvents returns a channel that will receive whether the unit is
+// paused whenever the unit is paused or resumed.
+func (f *filter) PausedEvents() <-chan bool {
+	return f.outPausedOn
+}
+
 // WantUpgradeEvent controls whether the filter will generate upgrade
 // events for unforced service charm changes.
 func (f *filter) WantUpgradeEvent(mustForce bool) {
@@ -400,6 +447,18 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(leaderSettingsw, &f.tomb)
+	// Series upgrades are not supported by older API servers, in which
+	// case no upgrade series events are ever sent.
+	var upgradeSeriesChanges <-chan struct{}
+	upgradeSeriesw, err := f.unit.WatchUpgradeSeriesNotifications()
+	if errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err) {
+		filterLogger.Debugf("series upgrades not supported by the API server")
+	} else if err != nil {
+		return err
+	} else {
+		defer watcher.Stop(upgradeSeriesw, &f.tomb)
+		upgradeSeriesChanges = upgradeSeriesw.Changes()
+	}
 
 	// Ignore external requests for leader settings behaviour until we see the first change.
 	var discardLeaderSettings <-chan struct{}
@@ -413,6 +472,9 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 	var discardConfig chan struct{}
 	var seenConfigChange bool
 	var seenAddressChange bool
+	// configQuiet fires when the config quiet period has passed without
+	// any further config or address change.
+	var configQuiet <-chan time.Time
 	maybePrepareConfigEvent := func() {
 		if !seenAddressChange {
 			filterLogger.Debugf("no address change seen yet, skipping config event")
@@ -422,9 +484,14 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			filterLogger.Debugf("no config change seen yet, skipping config event")
 			return
 		}
+		discardConfig = f.discardConfig
+		if f.configQuietPeriod > 0 {
+			filterLogger.Debugf("deferring config event for %v", f.configQuietPeriod)
+			configQuiet = time.After(f.configQuietPeriod)
+			return
+		}
 		filterLogger.Debugf("preparing new config event")
 		f.outConfig = f.outConfigOn
-		discardConfig = f.discardConfig
 	}
 
 	for {
@@ -464,6 +531,10 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			}
 			seenAddressChange = true
 			maybePrepareConfigEvent()
+		case <-configQuiet:
+			filterLogger.Debugf("preparing new config event after quiet period")
+			configQuiet = nil
+			f.outConfig = f.outConfigOn
 		case _, ok = <-meterStatusw.Changes():
 			filterLogger.Debugf("got meter status change")
 			if !ok {
@@ -523,6 +594,14 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			}
 			discardLeaderSettings = f.discardLeaderSettings
 			wantLeaderSettings = f.wantLeaderSettings
+		case _, ok = <-upgradeSeriesChanges:
+			filterLogger.Debugf("got upgrade series change")
+			if !ok {
+				return watcher.EnsureErr(upgradeSeriesw)
+			}
+			if err = f.upgradeSeriesChanged(); err != nil {
+				return errors.Trace(err)
+			}
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -551,6 +630,12 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			filterLogger.Debugf("sent storage event")
 			f.outStorage = nil
 			f.storage = nil
+		case f.outUpgradeSeries <- f.upgradeSeriesHook:
+			filterLogger.Debugf("sent upgrade series event")
+			f.outUpgradeSeries = nil
+		case f.outPaused <- f.paused:
+			filterLogger.Debugf("sent paused event")
+			f.outPaused = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -630,6 +715,7 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		case <-discardConfig:
 			filterLogger.Debugf("discarded config event")
 			f.outConfig = nil
+			configQuiet = nil
 		case <-discardLeaderSettings:
 			filterLogger.Debugf("discarded leader settings event")
 			f.outLeaderSettings = nil
@@ -653,6 +739,32 @@ func (f *filter) meterStatusChanged() error {
 	return nil
 }
 
+// upgradeSeriesChanged responds to changes in the series upgrade of
+// the unit's machine. An event is prepared only when the unit is first
+// seen to be asked to take part in a phase of the upgrade, so that its
+// hook does not run again in response to the progress of other units.
+func (f *filter) upgradeSeriesChanged() error {
+	status, err := f.unit.UpgradeSeriesStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if status == f.upgradeSeriesStatus {
+		return nil
+	}
+	f.upgradeSeriesStatus = status
+	switch status {
+	case params.UpgradeSeriesPrepareStarted:
+		f.upgradeSeriesHook = hook.PreSeriesUpgrade
+		f.outUpgradeSeries = f.outUpgradeSeriesOn
+	case params.UpgradeSeriesCompleteStarted:
+		f.upgradeSeriesHook = hook.PostSeriesUpgrade
+		f.outUpgradeSeries = f.outUpgradeSeriesOn
+	default:
+		f.outUpgradeSeries = nil
+	}
+	return nil
+}
+
 // unitChanged responds to changes in the unit.
 func (f *filter) unitChanged() error {
 	if err := f.unit.Refresh(); err != nil {
@@ -679,6 +791,17 @@ func (f *filter) unitChanged() error {
 			f.outResolved = f.outResolvedOn
 		}
 	}
+	// Units cannot be paused through older API servers.
+	paused, err := f.unit.IsPaused()
+	if errors.IsNotImplemented(err) || params.IsCodeNotImplemented(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if paused != f.paused {
+		f.paused = paused
+		f.outPaused = f.outPausedOn
+	}
 	return nil
 }
 
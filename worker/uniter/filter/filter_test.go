@@ -24,6 +24,7 @@ import (
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/uniter/filter"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 type FilterSuite struct {
@@ -350,6 +351,46 @@ func (s *FilterSuite) TestConfigEvents(c *gc.C) {
 	configC.AssertOneReceive()
 }
 
+func (s *FilterSuite) TestConfigEventsQuietPeriod(c *gc.C) {
+	quietPeriod := 500 * time.Millisecond
+	f, err := filter.NewFilterWithQuietPeriod(s.uniter, s.unit.Tag().(names.UnitTag), quietPeriod)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+
+	err = s.machine.SetProviderAddresses(network.NewAddress("0.1.2.3"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = f.SetCharm(s.wpcharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	configC := s.notifyAsserterC(c, f.ConfigEvents())
+	configC.AssertOneReceive()
+
+	// Change the config several times in quick succession; no event is
+	// sent until the changes have stopped for the quiet period, and then
+	// only one event is sent for them all.
+	for _, title := range []string{"one", "two", "three"} {
+		err := s.wordpress.UpdateConfigSettings(charm.Settings{
+			"blog-title": title,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		s.BackingState.StartSync()
+		time.Sleep(quietPeriod / 5)
+	}
+	configC.AssertNoReceive()
+	time.Sleep(quietPeriod)
+	configC.AssertOneReceive()
+
+	// A pending event can still be discarded before the quiet period
+	// has passed.
+	err = s.wordpress.UpdateConfigSettings(charm.Settings{
+		"blog-title": "four",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.EvilSync()
+	f.DiscardConfigEvent()
+	time.Sleep(quietPeriod)
+	configC.AssertNoReceive()
+}
+
 func (s *FilterSuite) TestInitialAddressEventIgnored(c *gc.C) {
 	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
 	c.Assert(err, jc.ErrorIsNil)
@@ -631,6 +672,54 @@ func (s *FilterSuite) TestStorageEvents(c *gc.C) {
 	})
 }
 
+func (s *FilterSuite) TestUpgradeSeriesEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	upgradeSeriesC := s.contentAsserterC(c, f.UpgradeSeriesEvents())
+	// No upgrade is in progress initially.
+	upgradeSeriesC.AssertNoReceive()
+
+	err = s.machine.PrepareUpgradeSeries("trusty")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgradeSeriesC.AssertOneReceive(), gc.Equals, hook.PreSeriesUpgrade)
+
+	// Finishing the unit's part of the phase does not trigger an event.
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesPrepareCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	upgradeSeriesC.AssertNoReceive()
+
+	err = s.machine.CompleteUpgradeSeries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgradeSeriesC.AssertOneReceive(), gc.Equals, hook.PostSeriesUpgrade)
+
+	err = s.machine.SetUpgradeSeriesUnitStatus(s.unit.Name(), state.UpgradeSeriesCompleted)
+	c.Assert(err, jc.ErrorIsNil)
+	upgradeSeriesC.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestPausedEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	pausedC := s.contentAsserterC(c, f.PausedEvents())
+	// The unit is not paused initially.
+	pausedC.AssertNoReceive()
+
+	err = s.unit.SetPaused(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pausedC.AssertOneReceive(), jc.IsTrue)
+
+	// Other changes to the unit do not trigger an event.
+	err = s.unit.SetResolved(state.ResolvedRetryHooks)
+	c.Assert(err, jc.ErrorIsNil)
+	pausedC.AssertNoReceive()
+
+	err = s.unit.SetPaused(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pausedC.AssertOneReceive(), jc.IsFalse)
+}
+
 func (s *FilterSuite) setLeaderSetting(c *gc.C, key, value string) {
 	// s.wordpress is the service object
 	currentSettings, err := s.State.ReadLeadershipSettings(s.wordpress.Tag().Id())
@@ -6,6 +6,7 @@ package filter
 import (
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v5"
+	"gopkg.in/juju/charm.v5/hooks"
 
 	"github.com/juju/juju/apiserver/params"
 )
@@ -61,6 +62,15 @@ type Filter interface {
 	// associated storage instances whose Life status has changed.
 	StorageEvents() <-chan []names.StorageTag
 
+	// UpgradeSeriesEvents returns a channel that will receive the kind of
+	// hook to run whenever the series upgrade of the unit's machine needs
+	// the unit to take part.
+	UpgradeSeriesEvents() <-chan hooks.Kind
+
+	// PausedEvents returns a channel that will receive whether the unit is
+	// paused whenever the unit is paused or resumed.
+	PausedEvents() <-chan bool
+
 	// WantUpgradeEvent controls whether the filter will generate upgrade
 	// events for unforced service charm changes.
 	WantUpgradeEvent(mustForce bool)
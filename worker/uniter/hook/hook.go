@@ -16,6 +16,8 @@ const (
 	LeaderElected         hooks.Kind = "leader-elected"
 	LeaderDeposed         hooks.Kind = "leader-deposed"
 	LeaderSettingsChanged hooks.Kind = "leader-settings-changed"
+	PreSeriesUpgrade      hooks.Kind = "pre-series-upgrade"
+	PostSeriesUpgrade     hooks.Kind = "post-series-upgrade"
 )
 
 // Info holds details required to execute a hook. Not all fields are
@@ -57,7 +59,7 @@ func (hi Info) Validate() error {
 		}
 		return nil
 	// TODO(fwereade): define these in charm/hooks...
-	case LeaderElected, LeaderDeposed, LeaderSettingsChanged:
+	case LeaderElected, LeaderDeposed, LeaderSettingsChanged, PreSeriesUpgrade, PostSeriesUpgrade:
 		return nil
 	}
 	return fmt.Errorf("unknown hook kind %q", hi.Kind)
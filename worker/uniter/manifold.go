@@ -0,0 +1,89 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils/fslock"
+
+	coreagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	apileadership "github.com/juju/juju/api/leadership"
+	"github.com/juju/juju/api/uniter"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a Manifold will depend.
+type ManifoldConfig struct {
+	AgentName       string
+	ApiCallerName   string
+	MachineLockName string
+}
+
+// Manifold returns a dependency manifold that runs a uniter worker,
+// using the resource names defined in the supplied config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.ApiCallerName,
+			config.MachineLockName,
+		},
+		Start: func(getResource dependency.GetResourceFunc) (worker.Worker, error) {
+			var agent agent.Agent
+			if err := getResource(config.AgentName, &agent); err != nil {
+				return nil, err
+			}
+			var apiCaller base.APICaller
+			if err := getResource(config.ApiCallerName, &apiCaller); err != nil {
+				return nil, err
+			}
+			var machineLock *fslock.Lock
+			if err := getResource(config.MachineLockName, &machineLock); err != nil {
+				return nil, err
+			}
+			return newWorker(agent, apiCaller, machineLock)
+		},
+	}
+}
+
+// newWorker wraps NewUniter for the convenience of Manifold. It exists
+// to be patched out in tests.
+var newWorker = func(agent agent.Agent, apiCaller base.APICaller, machineLock *fslock.Lock) (worker.Worker, error) {
+	unitTag, ok := agent.Tag().(names.UnitTag)
+	if !ok {
+		return nil, errors.Errorf("expected a unit tag; got %q", agent.Tag())
+	}
+	uniterFacade := uniter.NewState(apiCaller, unitTag)
+	leadershipManager := apileadership.NewClient(apiCaller)
+	agentConfig := agent.CurrentConfig()
+	configQuietPeriod, err := configChangeQuietPeriod(agentConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dataDir := agentConfig.DataDir()
+	return NewUniter(uniterFacade, unitTag, leadershipManager, dataDir, machineLock, configQuietPeriod), nil
+}
+
+// configChangeQuietPeriod returns the config change quiet period set in
+// the supplied agent config, or zero if none is set.
+func configChangeQuietPeriod(agentConfig coreagent.Config) (time.Duration, error) {
+	value := agentConfig.Value(coreagent.ConfigChangeQuietPeriod)
+	if value == "" {
+		return 0, nil
+	}
+	period, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Annotatef(err, "invalid %s %q", coreagent.ConfigChangeQuietPeriod, value)
+	}
+	if period < 0 {
+		return 0, errors.Errorf("invalid %s %q: must not be negative", coreagent.ConfigChangeQuietPeriod, value)
+	}
+	return period, nil
+}
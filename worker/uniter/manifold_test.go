@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/fslock"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/agent"
+	"github.com/juju/juju/worker/dependency"
+	dt "github.com/juju/juju/worker/dependency/testing"
+	"github.com/juju/juju/worker/uniter"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	testing.Stub
+	manifold    dependency.Manifold
+	agent       agent.Agent
+	apiCaller   base.APICaller
+	machineLock *fslock.Lock
+	worker      worker.Worker
+	getResource dependency.GetResourceFunc
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.Stub = testing.Stub{}
+	s.worker = &dummyWorker{}
+	s.PatchValue(uniter.NewWorker, func(agent agent.Agent, apiCaller base.APICaller, machineLock *fslock.Lock) (worker.Worker, error) {
+		s.AddCall("newWorker", agent, apiCaller, machineLock)
+		if err := s.NextErr(); err != nil {
+			return nil, err
+		}
+		return s.worker, nil
+	})
+	s.manifold = uniter.Manifold(uniter.ManifoldConfig{
+		AgentName:       "agent-name",
+		ApiCallerName:   "api-caller-name",
+		MachineLockName: "machine-lock-name",
+	})
+	s.agent = &dummyAgent{}
+	s.apiCaller = &dummyApiCaller{}
+	s.machineLock = &fslock.Lock{}
+	s.getResource = dt.StubGetResource(dt.StubResources{
+		"agent-name":        dt.StubResource{Output: s.agent},
+		"api-caller-name":   dt.StubResource{Output: s.apiCaller},
+		"machine-lock-name": dt.StubResource{Output: s.machineLock},
+	})
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Check(s.manifold.Inputs, jc.DeepEquals, []string{"agent-name", "api-caller-name", "machine-lock-name"})
+}
+
+func (s *ManifoldSuite) TestOutput(c *gc.C) {
+	c.Check(s.manifold.Output, gc.IsNil)
+}
+
+func (s *ManifoldSuite) TestStartMissingDependency(c *gc.C) {
+	for _, name := range []string{"agent-name", "api-caller-name", "machine-lock-name"} {
+		resources := dt.StubResources{
+			"agent-name":        dt.StubResource{Output: s.agent},
+			"api-caller-name":   dt.StubResource{Output: s.apiCaller},
+			"machine-lock-name": dt.StubResource{Output: s.machineLock},
+		}
+		resources[name] = dt.StubResource{Error: dependency.ErrMissing}
+		worker, err := s.manifold.Start(dt.StubGetResource(resources))
+		c.Check(worker, gc.IsNil)
+		c.Check(err, gc.Equals, dependency.ErrMissing)
+	}
+	s.CheckCalls(c, nil)
+}
+
+func (s *ManifoldSuite) TestStartError(c *gc.C) {
+	s.Errors = []error{errors.New("no uniter for you")}
+	worker, err := s.manifold.Start(s.getResource)
+	c.Check(worker, gc.IsNil)
+	c.Check(err, gc.ErrorMatches, "no uniter for you")
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "newWorker",
+		Args:     []interface{}{s.agent, s.apiCaller, s.machineLock},
+	}})
+}
+
+func (s *ManifoldSuite) TestStartSuccess(c *gc.C) {
+	worker, err := s.manifold.Start(s.getResource)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(worker, gc.Equals, s.worker)
+	s.CheckCalls(c, []testing.StubCall{{
+		FuncName: "newWorker",
+		Args:     []interface{}{s.agent, s.apiCaller, s.machineLock},
+	}})
+}
+
+type dummyAgent struct {
+	agent.Agent
+}
+
+type dummyApiCaller struct {
+	base.APICaller
+}
+
+type dummyWorker struct {
+	worker.Worker
+}
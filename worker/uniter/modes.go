@@ -304,20 +304,41 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			time.Now(), lastUpdateStatus, statusPollInterval,
 		)
 
+		// While the unit is paused, relation changes are left queued
+		// and periodic hooks are not run until it is resumed.
+		relationsEvents := u.f.RelationsEvents()
+		relationHooks := u.relations.Hooks()
+		idleInfo := ""
+		if u.paused {
+			relationsEvents = nil
+			relationHooks = nil
+			collectMetricsSignal = nil
+			updateStatusSignal = nil
+			idleInfo = "paused"
+		}
+
 		var creator creator
 		select {
 		case <-time.After(idleWaitTime):
-			if err := setAgentStatus(u, params.StatusIdle, "", nil); err != nil {
+			if err := setAgentStatus(u, params.StatusIdle, idleInfo, nil); err != nil {
 				return nil, errors.Trace(err)
 			}
 			continue
+		case paused := <-u.f.PausedEvents():
+			if paused {
+				logger.Infof("unit paused")
+			} else {
+				logger.Infof("unit resumed")
+			}
+			u.paused = paused
+			continue
 		case <-u.tomb.Dying():
 			return nil, tomb.ErrDying
 		case <-u.f.UnitDying():
 			return modeAbideDyingLoop(u)
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
-		case ids := <-u.f.RelationsEvents():
+		case ids := <-relationsEvents:
 			creator = newUpdateRelationsOp(ids)
 		case actionId := <-u.f.ActionEvents():
 			creator = newActionOp(actionId)
@@ -327,11 +348,13 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			creator = newSimpleRunHookOp(hooks.ConfigChanged)
 		case <-u.f.MeterStatusEvents():
 			creator = newSimpleRunHookOp(hooks.MeterStatusChanged)
+		case kind := <-u.f.UpgradeSeriesEvents():
+			creator = newSimpleRunHookOp(kind)
 		case <-collectMetricsSignal:
 			creator = newSimpleRunHookOp(hooks.CollectMetrics)
 		case <-updateStatusSignal:
 			creator = newSimpleRunHookOp(hooks.UpdateStatus)
-		case hookInfo := <-u.relations.Hooks():
+		case hookInfo := <-relationHooks:
 			creator = newRunHookOp(hookInfo)
 		case hookInfo := <-u.storage.Hooks():
 			creator = newRunHookOp(hookInfo)
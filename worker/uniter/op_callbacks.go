@@ -84,6 +84,10 @@ func (opc *operationCallbacks) CommitHook(hi hook.Info) error {
 		opc.u.ranConfigChanged = true
 	case hi.Kind == hook.LeaderSettingsChanged:
 		opc.u.ranLeaderSettingsChanged = true
+	case hi.Kind == hook.PreSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesPrepareCompleted)
+	case hi.Kind == hook.PostSeriesUpgrade:
+		return opc.u.unit.SetUpgradeSeriesStatus(params.UpgradeSeriesCompleted)
 	}
 	return nil
 }
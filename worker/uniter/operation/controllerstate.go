@@ -0,0 +1,102 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation
+
+import (
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+)
+
+// StateReadWriter records the state of a uniter.
+type StateReadWriter interface {
+	// Read returns the recorded state. If no state has been recorded
+	// it returns ErrNoStateFile.
+	Read() (*State, error)
+
+	// Write records the supplied state.
+	Write(st *State) error
+}
+
+// UnitStateAccessor gets and sets the uniter state recorded for a unit
+// by the controller.
+type UnitStateAccessor interface {
+	UniterState() (string, error)
+	SetUniterState(state string) error
+}
+
+// ControllerState records the state of a uniter in the controller, so
+// that an agent replacing the unit's original one, perhaps in a fresh
+// container, resumes exactly where its predecessor stopped.
+//
+// Every state is also written to a local StateFile. That file is used
+// on its own when the controller cannot store uniter state, and its
+// contents are copied to the controller when the controller has no
+// state for the unit yet, so that existing units migrate seamlessly.
+type ControllerState struct {
+	accessor UnitStateAccessor
+	file     *StateFile
+}
+
+// NewControllerState returns a ControllerState that records state via
+// the supplied accessor, and in the local file at path.
+func NewControllerState(accessor UnitStateAccessor, path string) *ControllerState {
+	return &ControllerState{
+		accessor: accessor,
+		file:     NewStateFile(path),
+	}
+}
+
+// Read is part of the StateReadWriter interface.
+func (s *ControllerState) Read() (*State, error) {
+	data, err := s.accessor.UniterState()
+	if errors.IsNotImplemented(err) {
+		logger.Debugf("controller cannot store uniter state; using local state file")
+		return s.file.Read()
+	} else if err != nil {
+		return nil, errors.Annotate(err, "cannot read uniter state")
+	}
+	if data == "" {
+		st, err := s.file.Read()
+		if err != nil {
+			return nil, err
+		}
+		logger.Infof("migrating local uniter state to controller")
+		if err := s.setControllerState(st); err != nil {
+			return nil, err
+		}
+		return st, nil
+	}
+	var st State
+	if err := goyaml.Unmarshal([]byte(data), &st); err != nil {
+		return nil, errors.Annotate(err, "cannot parse uniter state")
+	}
+	if err := st.validate(); err != nil {
+		return nil, errors.Annotate(err, "invalid uniter state")
+	}
+	return &st, nil
+}
+
+// Write is part of the StateReadWriter interface.
+func (s *ControllerState) Write(st *State) error {
+	if err := s.file.Write(st); err != nil {
+		return err
+	}
+	err := s.setControllerState(st)
+	if errors.IsNotImplemented(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *ControllerState) setControllerState(st *State) error {
+	data, err := goyaml.Marshal(st)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = s.accessor.SetUniterState(string(data))
+	if err != nil && !errors.IsNotImplemented(err) {
+		return errors.Annotate(err, "cannot record uniter state")
+	}
+	return err
+}
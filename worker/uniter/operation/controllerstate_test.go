@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package operation_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/operation"
+)
+
+type ControllerStateSuite struct {
+	accessor *mockUnitStateAccessor
+	path     string
+}
+
+var _ = gc.Suite(&ControllerStateSuite{})
+
+func (s *ControllerStateSuite) SetUpTest(c *gc.C) {
+	s.accessor = &mockUnitStateAccessor{}
+	s.path = filepath.Join(c.MkDir(), "uniter")
+}
+
+func (s *ControllerStateSuite) TestStates(c *gc.C) {
+	for i, t := range stateTests {
+		if t.err != "" {
+			continue
+		}
+		c.Logf("test %d", i)
+		s.SetUpTest(c)
+		state := operation.NewControllerState(s.accessor, s.path)
+		_, err := state.Read()
+		c.Assert(err, gc.Equals, operation.ErrNoStateFile)
+		err = state.Write(&t.st)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(s.accessor.data, gc.Not(gc.Equals), "")
+
+		// A replacement agent, without the local file, reads the
+		// state from the controller.
+		state = operation.NewControllerState(s.accessor, filepath.Join(c.MkDir(), "uniter"))
+		st, err := state.Read()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(st, jc.DeepEquals, &t.st)
+
+		// The local file is kept up to date as well.
+		st, err = operation.NewStateFile(s.path).Read()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(st, jc.DeepEquals, &t.st)
+	}
+}
+
+func (s *ControllerStateSuite) TestMigratesLocalState(c *gc.C) {
+	st := &operation.State{
+		Kind:     operation.Install,
+		Step:     operation.Pending,
+		CharmURL: stcurl,
+	}
+	err := operation.NewStateFile(s.path).Write(st)
+	c.Assert(err, jc.ErrorIsNil)
+
+	read, err := operation.NewControllerState(s.accessor, s.path).Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, st)
+
+	read, err = operation.NewControllerState(s.accessor, filepath.Join(c.MkDir(), "uniter")).Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, st)
+}
+
+func (s *ControllerStateSuite) TestControllerStatePreferred(c *gc.C) {
+	st := &operation.State{
+		Kind: operation.Continue,
+		Step: operation.Pending,
+	}
+	err := operation.NewControllerState(s.accessor, filepath.Join(c.MkDir(), "uniter")).Write(st)
+	c.Assert(err, jc.ErrorIsNil)
+	err = operation.NewStateFile(s.path).Write(&operation.State{
+		Kind:     operation.Install,
+		Step:     operation.Pending,
+		CharmURL: stcurl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	read, err := operation.NewControllerState(s.accessor, s.path).Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, st)
+}
+
+func (s *ControllerStateSuite) TestNotImplementedFallsBackToFile(c *gc.C) {
+	s.accessor.err = errors.NotImplementedf("UniterState")
+	state := operation.NewControllerState(s.accessor, s.path)
+	_, err := state.Read()
+	c.Assert(err, gc.Equals, operation.ErrNoStateFile)
+
+	st := &operation.State{
+		Kind: operation.Continue,
+		Step: operation.Pending,
+	}
+	err = state.Write(st)
+	c.Assert(err, jc.ErrorIsNil)
+	read, err := state.Read()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, st)
+}
+
+func (s *ControllerStateSuite) TestErrors(c *gc.C) {
+	s.accessor.err = errors.New("blam")
+	state := operation.NewControllerState(s.accessor, s.path)
+	_, err := state.Read()
+	c.Assert(err, gc.ErrorMatches, "cannot read uniter state: blam")
+	err = state.Write(&operation.State{
+		Kind: operation.Continue,
+		Step: operation.Pending,
+	})
+	c.Assert(err, gc.ErrorMatches, "cannot record uniter state: blam")
+}
+
+func (s *ControllerStateSuite) TestInvalidControllerState(c *gc.C) {
+	s.accessor.data = "op: bloviate\nopstep: pending\n"
+	_, err := operation.NewControllerState(s.accessor, s.path).Read()
+	c.Assert(err, gc.ErrorMatches, `invalid uniter state: invalid operation state: unknown operation "bloviate"`)
+}
+
+type mockUnitStateAccessor struct {
+	data string
+	err  error
+}
+
+func (m *mockUnitStateAccessor) UniterState() (string, error) {
+	return m.data, m.err
+}
+
+func (m *mockUnitStateAccessor) SetUniterState(data string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.data = data
+	return nil
+}
@@ -26,8 +26,8 @@ var (
 )
 
 type executor struct {
-	file  *StateFile
-	state *State
+	stateRW StateReadWriter
+	state   *State
 }
 
 // NewExecutor returns an Executor which takes its starting state from the
@@ -35,8 +35,15 @@ type executor struct {
 // the executor's starting state will include a queued Install hook, for
 // the charm identified by the supplied func.
 func NewExecutor(stateFilePath string, getInstallCharm func() (*corecharm.URL, error)) (Executor, error) {
-	file := NewStateFile(stateFilePath)
-	state, err := file.Read()
+	return NewStateExecutor(NewStateFile(stateFilePath), getInstallCharm)
+}
+
+// NewStateExecutor returns an Executor which takes its starting state from
+// the supplied StateReadWriter, and records state changes there. If no
+// state has been recorded, the executor's starting state will include a
+// queued Install hook, for the charm identified by the supplied func.
+func NewStateExecutor(stateRW StateReadWriter, getInstallCharm func() (*corecharm.URL, error)) (Executor, error) {
+	state, err := stateRW.Read()
 	if err == ErrNoStateFile {
 		charmURL, err := getInstallCharm()
 		if err != nil {
@@ -51,8 +58,8 @@ func NewExecutor(stateFilePath string, getInstallCharm func() (*corecharm.URL, e
 		return nil, err
 	}
 	return &executor{
-		file:  file,
-		state: state,
+		stateRW: stateRW,
+		state:   state,
 	}, nil
 }
 
@@ -101,7 +108,7 @@ func (x *executor) writeState(newState State) error {
 	if err := newState.validate(); err != nil {
 		return err
 	}
-	if err := x.file.Write(&newState); err != nil {
+	if err := x.stateRW.Write(&newState); err != nil {
 		return errors.Annotatef(err, "writing state")
 	}
 	x.state = &newState
@@ -0,0 +1,106 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// The output streams of a running action.
+const (
+	actionOutputStdout = "stdout"
+	actionOutputStderr = "stderr"
+)
+
+// actionOutputFlushInterval is how often the output of a running action
+// is sent to the state server.
+var actionOutputFlushInterval = time.Second
+
+type actionOutputChunk struct {
+	stream string
+	data   string
+}
+
+// actionOutput collects the lines written by a running action and sends
+// them, in order, to the state server every actionOutputFlushInterval
+// and when stopped.
+type actionOutput struct {
+	context  Context
+	mu       sync.Mutex
+	chunks   []actionOutputChunk
+	disabled bool
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+func newActionOutput(context Context) *actionOutput {
+	output := &actionOutput{
+		context:  context,
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go output.loop()
+	return output
+}
+
+// sink returns a function that records lines written to the given
+// stream.
+func (o *actionOutput) sink(stream string) func(line string) {
+	return func(line string) {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		if o.disabled {
+			return
+		}
+		data := line + "\n"
+		if n := len(o.chunks); n > 0 && o.chunks[n-1].stream == stream {
+			o.chunks[n-1].data += data
+			return
+		}
+		o.chunks = append(o.chunks, actionOutputChunk{stream, data})
+	}
+}
+
+func (o *actionOutput) loop() {
+	defer close(o.done)
+	for {
+		select {
+		case <-o.stopping:
+			o.flush()
+			return
+		case <-time.After(actionOutputFlushInterval):
+			o.flush()
+		}
+	}
+}
+
+func (o *actionOutput) flush() {
+	o.mu.Lock()
+	chunks := o.chunks
+	o.chunks = nil
+	o.mu.Unlock()
+	for _, chunk := range chunks {
+		err := o.context.AppendActionOutput(chunk.stream, chunk.data)
+		if errors.IsNotImplemented(err) {
+			// The state server cannot record the output; it will
+			// still be logged.
+			logger.Debugf("not sending action output: %v", err)
+			o.mu.Lock()
+			o.disabled = true
+			o.mu.Unlock()
+			return
+		} else if err != nil {
+			logger.Errorf("cannot send action output: %v", err)
+		}
+	}
+}
+
+// stop sends any output not yet sent, and stops the flushing.
+func (o *actionOutput) stop() {
+	close(o.stopping)
+	<-o.done
+}
@@ -115,6 +115,10 @@ type HookContext struct {
 	// proxySettings are the current proxy settings that the uniter knows about.
 	proxySettings proxy.Settings
 
+	// hookEnvironment holds the extra environment variables set in the
+	// context of every hook, as configured in the environment.
+	hookEnvironment map[string]string
+
 	// metricsRecorder is used to write metrics batches to a storage (usually a file).
 	metricsRecorder MetricsRecorder
 
@@ -255,6 +259,14 @@ func (ctx *HookContext) AvailabilityZone() (string, bool) {
 	return ctx.availabilityzone, ctx.availabilityzone != ""
 }
 
+func (ctx *HookContext) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	return ctx.unit.NetworkInfo(bindingNames)
+}
+
+func (ctx *HookContext) GoalState() (params.GoalState, error) {
+	return ctx.unit.GoalState()
+}
+
 func (ctx *HookContext) HookStorage() (jujuc.ContextStorage, bool) {
 	return ctx.Storage(ctx.storageTag)
 }
@@ -343,6 +355,15 @@ func (ctx *HookContext) SetActionFailed() error {
 	return nil
 }
 
+// AppendActionOutput sends output written by the running action to the
+// state server, so that it can be watched before the action completes.
+func (ctx *HookContext) AppendActionOutput(stream, data string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return ctx.state.AppendActionOutput(ctx.actionData.ActionTag, stream, data)
+}
+
 // UpdateActionResults inserts new values for use with action-set and
 // action-fail.  The results struct will be delivered to the state server
 // upon completion of the Action.  It returns an error if not called on an
@@ -437,7 +458,8 @@ func (context *HookContext) HookVars(paths Paths) []string {
 			"JUJU_ACTION_TAG="+context.actionData.ActionTag.String(),
 		)
 	}
-	return append(vars, osDependentEnvVars(paths)...)
+	vars = append(vars, osDependentEnvVars(paths)...)
+	return mergeHookEnvironment(vars, context.hookEnvironment)
 }
 
 func (ctx *HookContext) handleReboot(err *error) {
@@ -14,6 +14,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v5"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -73,6 +74,32 @@ func (s *InterfaceSuite) TestAvailabilityZone(c *gc.C) {
 	c.Check(zone, gc.Equals, "a-zone")
 }
 
+func (s *InterfaceSuite) TestNetworkInfo(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	info, err := ctx.NetworkInfo([]string{"db", "foo"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, map[string]params.NetworkInfoResult{
+		"db": {IngressAddresses: []string{"u-0.testing.invalid"}},
+		"foo": {
+			Error: &params.Error{Message: `binding name "foo" not valid`},
+		},
+	})
+}
+
+func (s *InterfaceSuite) TestGoalState(c *gc.C) {
+	ctx := s.GetContext(c, -1, "")
+	goalState, err := ctx.GoalState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(goalState.Units, gc.HasLen, 1)
+	c.Assert(goalState.Units["u/0"].Status, gc.Equals, "unknown")
+	c.Assert(goalState.Relations, jc.DeepEquals, map[string]params.UnitsGoalState{
+		"db": {
+			"db0": {Status: "joined"},
+			"db1": {Status: "joined"},
+		},
+	})
+}
+
 func (s *InterfaceSuite) TestUnitStatus(c *gc.C) {
 	ctx := s.GetContext(c, -1, "")
 	defer runner.PatchCachedStatus(ctx.(runner.Context), "maintenance", "working", map[string]interface{}{"hello": "world"})()
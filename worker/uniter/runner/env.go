@@ -6,6 +6,7 @@ package runner
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/juju/juju/version"
@@ -56,6 +57,36 @@ func windowsEnv(paths Paths) []string {
 	}
 }
 
+// mergeHookEnvironment sets the extra variables configured for hooks
+// in vars, an os.Environ-style list. Existing values, such as the proxy
+// settings, are replaced in place; the remaining variables are appended
+// in name order. The variables set by juju itself are reserved, and
+// cannot appear in extra.
+func mergeHookEnvironment(vars []string, extra map[string]string) []string {
+	if len(extra) == 0 {
+		return vars
+	}
+	merged := make(map[string]bool)
+	for i, v := range vars {
+		name := strings.SplitN(v, "=", 2)[0]
+		if value, ok := extra[name]; ok {
+			vars[i] = name + "=" + value
+			merged[name] = true
+		}
+	}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		if !merged[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vars = append(vars, name+"="+extra[name])
+	}
+	return vars
+}
+
 // mergeEnvironment takes in a string array representing the desired environment
 // and merges it with the current environment. On Windows, clearing the environment,
 // or having missing environment variables, may lead to standard go packages not working
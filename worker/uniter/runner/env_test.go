@@ -158,6 +158,31 @@ func (s *EnvSuite) TestEnvWindows(c *gc.C) {
 	s.assertVars(c, actualVars, contextVars, pathsVars, windowsVars, relationVars)
 }
 
+func (s *EnvSuite) TestEnvHookEnvironment(c *gc.C) {
+	s.PatchValue(&version.Current.OS, version.Ubuntu)
+	os.Setenv("PATH", "foo:bar")
+	ubuntuVars := []string{
+		"PATH=path-to-tools:foo:bar",
+		"APT_LISTCHANGES_FRONTEND=none",
+		"DEBIAN_FRONTEND=readline",
+	}
+
+	ctx, contextVars := s.getContext()
+	runner.SetEnvironmentHookContextEnvironment(ctx, map[string]string{
+		"LANG":            "en_US.UTF-8",
+		"http_proxy":      "corporate-proxy",
+		"DEBIAN_FRONTEND": "readline",
+	})
+	for i, v := range contextVars {
+		if v == "http_proxy=some-http-proxy" {
+			contextVars[i] = "http_proxy=corporate-proxy"
+		}
+	}
+	paths, pathsVars := s.getPaths()
+	actualVars := ctx.HookVars(paths)
+	s.assertVars(c, actualVars, contextVars, pathsVars, ubuntuVars, []string{"LANG=en_US.UTF-8"})
+}
+
 func (s *EnvSuite) TestEnvUbuntu(c *gc.C) {
 	s.PatchValue(&version.Current.OS, version.Ubuntu)
 	os.Setenv("PATH", "foo:bar")
@@ -215,6 +215,12 @@ func NewEnvironmentHookContext(
 	}
 }
 
+// SetEnvironmentHookContextEnvironment exists purely to set the extra
+// variables used in hookVars.
+func SetEnvironmentHookContextEnvironment(context *HookContext, vars map[string]string) {
+	context.hookEnvironment = vars
+}
+
 // SetEnvironmentHookContextRelation exists purely to set the fields used in hookVars.
 // It makes no assumptions about the validity of context.
 func SetEnvironmentHookContextRelation(
@@ -358,6 +358,7 @@ func (f *factory) updateContext(ctx *HookContext) (err error) {
 		return err
 	}
 	ctx.proxySettings = environConfig.ProxySettings()
+	ctx.hookEnvironment = environConfig.HookEnvironment()
 
 	// Calling these last, because there's a potential race: they're not guaranteed
 	// to be set in time to be needed for a hook. If they're not, we just leave them
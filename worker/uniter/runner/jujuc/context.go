@@ -51,6 +51,10 @@ type Context interface {
 	// AvailabilityZone returns the executing unit's availablilty zone.
 	AvailabilityZone() (string, bool)
 
+	// NetworkInfo returns the network information of each of the named
+	// endpoint bindings of the executing unit, keyed on binding name.
+	NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error)
+
 	// OpenPorts marks the supplied port range for opening when the
 	// executing unit's service is exposed.
 	OpenPorts(protocol string, fromPort, toPort int) error
@@ -115,6 +119,10 @@ type Context interface {
 	// units belongs to.
 	OwnerTag() string
 
+	// GoalState returns the units and relations the executing unit's
+	// service is expected to have, whether or not they are up yet.
+	GoalState() (params.GoalState, error)
+
 	// AddMetric records a metric to return after hook execution.
 	AddMetric(string, string, time.Time) error
 
@@ -154,6 +162,16 @@ type ContextRelation interface {
 
 	// ReadSettings returns the settings of any remote unit in the relation.
 	ReadSettings(unit string) (params.Settings, error)
+
+	// ReadServiceSettings returns the settings that the named service
+	// shares with the other services in the relation.
+	ReadServiceSettings(service string) (params.Settings, error)
+
+	// WriteServiceSettings immediately applies the given changes to the
+	// settings that the local unit's service shares with the other
+	// services in the relation. Keys with empty values are deleted. It
+	// fails unless the local unit is the service's leader.
+	WriteServiceSettings(settings map[string]string) error
 }
 
 // ContextStorage expresses the capabilities of a hook with respect to a
@@ -0,0 +1,86 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// GoalStateCommand implements the goal-state command.
+type GoalStateCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+}
+
+func NewGoalStateCommand(ctx Context) cmd.Command {
+	return &GoalStateCommand{ctx: ctx}
+}
+
+func (c *GoalStateCommand) Info() *cmd.Info {
+	doc := `
+goal-state prints the units of this unit's service, and the services and
+units related to it in each of its relations, as they are expected to be
+once deployment settles. Units that are not yet up are included, so a
+charm can wait until all the peers it expects have joined.
+`
+	return &cmd.Info{
+		Name:    "goal-state",
+		Purpose: "print the expected units and relations of this unit's service",
+		Doc:     doc,
+	}
+}
+
+func (c *GoalStateCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+func (c *GoalStateCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// goalStateStatus holds the status of an entity printed by goal-state.
+type goalStateStatus struct {
+	Status string `json:"status" yaml:"status"`
+	Since  string `json:"since,omitempty" yaml:"since,omitempty"`
+}
+
+type unitsGoalState map[string]goalStateStatus
+
+// goalState holds the goal state printed by goal-state.
+type goalState struct {
+	Units     unitsGoalState            `json:"units" yaml:"units"`
+	Relations map[string]unitsGoalState `json:"relations" yaml:"relations"`
+}
+
+func (c *GoalStateCommand) Run(ctx *cmd.Context) error {
+	result, err := c.ctx.GoalState()
+	if err != nil {
+		return errors.Annotate(err, "cannot get goal state")
+	}
+	out := goalState{
+		Units:     formatUnitsGoalState(result.Units),
+		Relations: make(map[string]unitsGoalState),
+	}
+	for name, entities := range result.Relations {
+		out.Relations[name] = formatUnitsGoalState(entities)
+	}
+	return c.out.Write(ctx, out)
+}
+
+func formatUnitsGoalState(in params.UnitsGoalState) unitsGoalState {
+	out := make(unitsGoalState)
+	for name, status := range in {
+		formatted := goalStateStatus{Status: status.Status}
+		if status.Since != nil {
+			formatted.Since = status.Since.UTC().Format("2006-01-02 15:04:05Z")
+		}
+		out[name] = formatted
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"encoding/json"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type GoalStateSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&GoalStateSuite{})
+
+var goalStateAttributes = map[string]interface{}{
+	"units": map[string]interface{}{
+		"u/0": map[string]interface{}{"status": "active", "since": "2015-10-01 12:30:00Z"},
+		"u/1": map[string]interface{}{"status": "waiting", "since": "2015-10-01 12:30:00Z"},
+	},
+	"relations": map[string]interface{}{
+		"db": map[string]interface{}{
+			"mysql":   map[string]interface{}{"status": "joined"},
+			"mysql/0": map[string]interface{}{"status": "active", "since": "2015-10-01 12:30:00Z"},
+		},
+	},
+}
+
+var goalStateTests = []struct {
+	args   []string
+	format int
+}{
+	{nil, formatYaml},
+	{[]string{"--format", "yaml"}, formatYaml},
+	{[]string{"--format", "json"}, formatJson},
+}
+
+func (s *GoalStateSuite) TestOutputFormat(c *gc.C) {
+	for i, t := range goalStateTests {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, -1, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Assert(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+
+		var out map[string]interface{}
+		switch t.format {
+		case formatYaml:
+			var yamlOut map[interface{}]interface{}
+			c.Assert(goyaml.Unmarshal(bufferBytes(ctx.Stdout), &yamlOut), jc.ErrorIsNil)
+			out = stringKeys(yamlOut).(map[string]interface{})
+		case formatJson:
+			c.Assert(json.Unmarshal(bufferBytes(ctx.Stdout), &out), jc.ErrorIsNil)
+		}
+		c.Check(out, jc.DeepEquals, goalStateAttributes)
+	}
+}
+
+func (s *GoalStateSuite) TestUnknownArg(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("goal-state"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = testing.InitCommand(com, []string{"blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
+
+// stringKeys converts the maps decoded from YAML to maps with string
+// keys, as decoded from JSON.
+func stringKeys(in interface{}) interface{} {
+	m, ok := in.(map[interface{}]interface{})
+	if !ok {
+		return in
+	}
+	out := make(map[string]interface{})
+	for k, v := range m {
+		out[k.(string)] = stringKeys(v)
+	}
+	return out
+}
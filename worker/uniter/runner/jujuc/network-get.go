@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// NetworkGetCommand implements the network-get command.
+type NetworkGetCommand struct {
+	cmd.CommandBase
+	ctx            Context
+	bindingName    string
+	ingressAddress bool
+	egressSubnets  bool
+	out            cmd.Output
+}
+
+func NewNetworkGetCommand(ctx Context) cmd.Command {
+	return &NetworkGetCommand{ctx: ctx}
+}
+
+func (c *NetworkGetCommand) Info() *cmd.Info {
+	doc := `
+network-get prints the network information of the named endpoint binding:
+the addresses on which other units should reach this unit, and the subnets
+from which this unit's traffic originates. If the endpoint is bound to a
+space, only the addresses in that space are used.
+
+If the --ingress-address flag is passed, only the preferred ingress address
+is printed; if the --egress-subnets flag is passed, only the egress subnets
+are printed.
+`
+	return &cmd.Info{
+		Name:    "network-get",
+		Args:    "<binding-name> [--ingress-address | --egress-subnets]",
+		Purpose: "print network information for an endpoint binding",
+		Doc:     doc,
+	}
+}
+
+func (c *NetworkGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.BoolVar(&c.ingressAddress, "ingress-address", false, "print only the preferred ingress address")
+	f.BoolVar(&c.egressSubnets, "egress-subnets", false, "print only the egress subnets")
+}
+
+func (c *NetworkGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no binding name specified")
+	}
+	if c.ingressAddress && c.egressSubnets {
+		return errors.New("cannot specify both --ingress-address and --egress-subnets")
+	}
+	c.bindingName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// networkInfo holds the network information printed by network-get.
+type networkInfo struct {
+	Space            string   `json:"space,omitempty" yaml:"space,omitempty"`
+	IngressAddresses []string `json:"ingress-addresses" yaml:"ingress-addresses"`
+	EgressSubnets    []string `json:"egress-subnets" yaml:"egress-subnets"`
+}
+
+func (c *NetworkGetCommand) Run(ctx *cmd.Context) error {
+	results, err := c.ctx.NetworkInfo([]string{c.bindingName})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	result, ok := results[c.bindingName]
+	if !ok {
+		return errors.NotFoundf("binding %q", c.bindingName)
+	} else if result.Error != nil {
+		return result.Error
+	}
+	switch {
+	case c.ingressAddress:
+		if len(result.IngressAddresses) == 0 {
+			return errors.Errorf("no ingress address for binding %q", c.bindingName)
+		}
+		return c.out.Write(ctx, result.IngressAddresses[0])
+	case c.egressSubnets:
+		return c.out.Write(ctx, result.EgressSubnets)
+	}
+	return c.out.Write(ctx, networkInfo{
+		Space:            result.Space,
+		IngressAddresses: result.IngressAddresses,
+		EgressSubnets:    result.EgressSubnets,
+	})
+}
@@ -31,7 +31,7 @@ func (s *RelationIdsSuite) SetUpTest(c *gc.C) {
 func (s *RelationIdsSuite) AddRelatedServices(c *gc.C, relname string, count int) {
 	for i := 0; i < count; i++ {
 		id := len(s.rels)
-		s.rels[id] = &ContextRelation{id, relname, nil}
+		s.rels[id] = &ContextRelation{id, relname, nil, nil}
 	}
 }
 
@@ -0,0 +1,109 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+)
+
+// RelationServiceGetCommand implements the relation-service-get command.
+type RelationServiceGetCommand struct {
+	cmd.CommandBase
+	ctx         Context
+	RelationId  int
+	Key         string
+	ServiceName string
+	out         cmd.Output
+}
+
+func NewRelationServiceGetCommand(ctx Context) cmd.Command {
+	return &RelationServiceGetCommand{ctx: ctx}
+}
+
+func (c *RelationServiceGetCommand) Info() *cmd.Info {
+	args := "<key> <service name>"
+	doc := `
+relation-service-get prints the value of a service's relation setting,
+specified by key. Service settings are shared by the whole service and
+are written by its leader with relation-service-set; they are distinct
+from the settings of its units.
+If no key is given, or if the key is "-", all keys and values will be printed.
+`
+	if name, found := c.ctx.RemoteUnitName(); found {
+		args = "[<key> [<service name>]]"
+		doc += fmt.Sprintf("Current default service name is %q.", serviceOfUnit(name))
+	}
+	return &cmd.Info{
+		Name:    "relation-service-get",
+		Args:    args,
+		Purpose: "get service relation settings",
+		Doc:     doc,
+	}
+}
+
+func (c *RelationServiceGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	rV := newRelationIdValue(c.ctx, &c.RelationId)
+
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	f.Var(rV, "r", "specify a relation by id")
+	f.Var(rV, "relation", "")
+}
+
+func (c *RelationServiceGetCommand) Init(args []string) error {
+	if c.RelationId == -1 {
+		return fmt.Errorf("no relation id specified")
+	}
+	c.Key = ""
+	if len(args) > 0 {
+		if c.Key = args[0]; c.Key == "-" {
+			c.Key = ""
+		}
+		args = args[1:]
+	}
+	if name, found := c.ctx.RemoteUnitName(); found {
+		c.ServiceName = serviceOfUnit(name)
+	}
+	if len(args) > 0 {
+		c.ServiceName = args[0]
+		args = args[1:]
+	}
+	if c.ServiceName == "" {
+		return fmt.Errorf("no service name specified")
+	}
+	if !names.IsValidService(c.ServiceName) {
+		return fmt.Errorf("invalid service name %q", c.ServiceName)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *RelationServiceGetCommand) Run(ctx *cmd.Context) error {
+	r, found := c.ctx.Relation(c.RelationId)
+	if !found {
+		return fmt.Errorf("unknown relation id")
+	}
+	settings, err := r.ReadServiceSettings(c.ServiceName)
+	if err != nil {
+		return err
+	}
+	if c.Key == "" {
+		return c.out.Write(ctx, settings)
+	}
+	if value, ok := settings[c.Key]; ok {
+		return c.out.Write(ctx, value)
+	}
+	return c.out.Write(ctx, nil)
+}
+
+// serviceOfUnit returns the name of the service of the named unit.
+func serviceOfUnit(unitName string) string {
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return ""
+	}
+	return serviceName
+}
@@ -0,0 +1,115 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type RelationServiceGetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&RelationServiceGetSuite{})
+
+func (s *RelationServiceGetSuite) SetUpTest(c *gc.C) {
+	s.ContextSuite.SetUpTest(c)
+	s.rels[1].units["m/0"] = Settings{"pew": "unit"}
+	s.rels[1].services = map[string]Settings{
+		"m": {"database": "blog"},
+		"u": {"endpoint": "u.testing.invalid"},
+	}
+}
+
+var relationServiceGetTests = []struct {
+	summary string
+	relid   int
+	unit    string
+	args    []string
+	code    int
+	out     string
+}{
+	{
+		summary: "no default relation",
+		relid:   -1,
+		code:    2,
+		out:     `no relation id specified`,
+	}, {
+		summary: "no default service",
+		relid:   1,
+		code:    2,
+		out:     `no service name specified`,
+	}, {
+		summary: "invalid service",
+		relid:   1,
+		code:    2,
+		args:    []string{"-", "m/0"},
+		out:     `invalid service name "m/0"`,
+	}, {
+		summary: "too many arguments",
+		relid:   1,
+		code:    2,
+		args:    []string{"-", "m", "u"},
+		out:     `unrecognized args: \["u"\]`,
+	}, {
+		summary: "unknown service",
+		relid:   1,
+		code:    1,
+		args:    []string{"-", "bad"},
+		out:     `unknown service bad`,
+	}, {
+		summary: "all keys with implicit service",
+		relid:   1,
+		unit:    "m/0",
+		out:     "database: blog",
+	}, {
+		summary: "specific key with implicit service",
+		relid:   1,
+		unit:    "m/0",
+		args:    []string{"database"},
+		out:     "blog",
+	}, {
+		summary: "specific key with explicit service",
+		relid:   1,
+		unit:    "m/0",
+		args:    []string{"endpoint", "u"},
+		out:     "u.testing.invalid",
+	}, {
+		summary: "missing key",
+		relid:   1,
+		unit:    "m/0",
+		args:    []string{"pew"},
+	},
+}
+
+func (s *RelationServiceGetSuite) TestRelationServiceGet(c *gc.C) {
+	for i, t := range relationServiceGetTests {
+		c.Logf("test %d: %s", i, t.summary)
+		hctx := s.GetHookContext(c, t.relid, t.unit)
+		com, err := jujuc.NewCommand(hctx, cmdString("relation-service-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, t.code)
+		if code == 0 {
+			c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+			expect := t.out
+			if expect != "" {
+				expect = expect + "\n"
+			}
+			c.Check(bufferString(ctx.Stdout), gc.Equals, expect)
+		} else {
+			c.Check(bufferString(ctx.Stdout), gc.Equals, "")
+			expect := fmt.Sprintf(`(.|\n)*error: %s\n`, t.out)
+			c.Check(bufferString(ctx.Stderr), gc.Matches, expect)
+		}
+	}
+}
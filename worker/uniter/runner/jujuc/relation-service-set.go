@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
+)
+
+const relationServiceSetDoc = `
+"relation-service-set" writes the settings that the local unit's service
+shares with the other services in some relation, as distinct from the
+local unit's own settings. If no relation is specified then the current
+relation is used. Unlike relation-set, the settings are written to the
+state server immediately, and only the service's leader may write them.
+The setting values are stored as strings. Setting an empty string causes
+the setting to be removed.
+`
+
+// RelationServiceSetCommand implements the relation-service-set command.
+type RelationServiceSetCommand struct {
+	cmd.CommandBase
+	ctx        Context
+	RelationId int
+	Settings   map[string]string
+}
+
+func NewRelationServiceSetCommand(ctx Context) cmd.Command {
+	return &RelationServiceSetCommand{ctx: ctx}
+}
+
+func (c *RelationServiceSetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "relation-service-set",
+		Args:    "key=value [key=value ...]",
+		Purpose: "set service relation settings",
+		Doc:     relationServiceSetDoc,
+	}
+}
+
+func (c *RelationServiceSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	rV := newRelationIdValue(c.ctx, &c.RelationId)
+
+	f.Var(rV, "r", "specify a relation by id")
+	f.Var(rV, "relation", "")
+}
+
+func (c *RelationServiceSetCommand) Init(args []string) (err error) {
+	if c.RelationId == -1 {
+		return errors.Errorf("no relation id specified")
+	}
+	if len(args) == 0 {
+		return errors.Errorf("no settings specified")
+	}
+	c.Settings, err = keyvalues.Parse(args, true)
+	return errors.Trace(err)
+}
+
+func (c *RelationServiceSetCommand) Run(ctx *cmd.Context) error {
+	r, found := c.ctx.Relation(c.RelationId)
+	if !found {
+		return errors.Errorf("unknown relation id")
+	}
+	err := r.WriteServiceSettings(c.Settings)
+	return errors.Annotatef(err, "cannot write service relation settings")
+}
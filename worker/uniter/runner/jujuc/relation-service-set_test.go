@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type RelationServiceSetSuite struct {
+	ContextSuite
+}
+
+var _ = gc.Suite(&RelationServiceSetSuite{})
+
+var relationServiceSetInitTests = []struct {
+	relid int
+	args  []string
+	err   string
+}{
+	{
+		relid: -1,
+		args:  []string{"foo=bar"},
+		err:   `no relation id specified`,
+	}, {
+		relid: 1,
+		err:   `no settings specified`,
+	}, {
+		relid: 1,
+		args:  []string{"foo"},
+		err:   `expected "key=value", got "foo"`,
+	},
+}
+
+func (s *RelationServiceSetSuite) TestInit(c *gc.C) {
+	for i, t := range relationServiceSetInitTests {
+		c.Logf("test %d", i)
+		hctx := s.GetHookContext(c, t.relid, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("relation-service-set"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, fmt.Sprintf(`(.|\n)*error: %s\n`, t.err))
+	}
+}
+
+func (s *RelationServiceSetSuite) TestRun(c *gc.C) {
+	s.rels[1].services = map[string]Settings{"u": {"old": "value", "keep": "me"}}
+	hctx := s.GetHookContext(c, 1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-service-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"old=", "new=thing"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(s.rels[1].services["u"].Map(), jc.DeepEquals, params.Settings{
+		"keep": "me",
+		"new":  "thing",
+	})
+}
+
+func (s *RelationServiceSetSuite) TestRunExplicitRelation(c *gc.C) {
+	hctx := s.GetHookContext(c, 1, "")
+	com, err := jujuc.NewCommand(hctx, cmdString("relation-service-set"))
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := testing.Context(c)
+	code := cmd.Main(com, ctx, []string{"-r", "peer0:0", "foo=bar"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(s.rels[0].services["u"].Map(), jc.DeepEquals, params.Settings{"foo": "bar"})
+	c.Check(s.rels[1].services, gc.HasLen, 0)
+}
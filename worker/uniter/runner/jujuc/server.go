@@ -28,24 +28,28 @@ type creator func(Context) cmd.Command
 
 // baseCommands maps Command names to creators.
 var baseCommands = map[string]creator{
-	"close-port" + cmdSuffix:    NewClosePortCommand,
-	"config-get" + cmdSuffix:    NewConfigGetCommand,
-	"juju-log" + cmdSuffix:      NewJujuLogCommand,
-	"open-port" + cmdSuffix:     NewOpenPortCommand,
-	"opened-ports" + cmdSuffix:  NewOpenedPortsCommand,
-	"relation-get" + cmdSuffix:  NewRelationGetCommand,
-	"action-get" + cmdSuffix:    NewActionGetCommand,
-	"action-set" + cmdSuffix:    NewActionSetCommand,
-	"action-fail" + cmdSuffix:   NewActionFailCommand,
-	"relation-ids" + cmdSuffix:  NewRelationIdsCommand,
-	"relation-list" + cmdSuffix: NewRelationListCommand,
-	"relation-set" + cmdSuffix:  NewRelationSetCommand,
-	"unit-get" + cmdSuffix:      NewUnitGetCommand,
-	"owner-get" + cmdSuffix:     NewOwnerGetCommand,
-	"add-metric" + cmdSuffix:    NewAddMetricCommand,
-	"juju-reboot" + cmdSuffix:   NewJujuRebootCommand,
-	"status-get" + cmdSuffix:    NewStatusGetCommand,
-	"status-set" + cmdSuffix:    NewStatusSetCommand,
+	"close-port" + cmdSuffix:           NewClosePortCommand,
+	"config-get" + cmdSuffix:           NewConfigGetCommand,
+	"goal-state" + cmdSuffix:           NewGoalStateCommand,
+	"juju-log" + cmdSuffix:             NewJujuLogCommand,
+	"network-get" + cmdSuffix:          NewNetworkGetCommand,
+	"open-port" + cmdSuffix:            NewOpenPortCommand,
+	"opened-ports" + cmdSuffix:         NewOpenedPortsCommand,
+	"relation-get" + cmdSuffix:         NewRelationGetCommand,
+	"action-get" + cmdSuffix:           NewActionGetCommand,
+	"action-set" + cmdSuffix:           NewActionSetCommand,
+	"action-fail" + cmdSuffix:          NewActionFailCommand,
+	"relation-ids" + cmdSuffix:         NewRelationIdsCommand,
+	"relation-list" + cmdSuffix:        NewRelationListCommand,
+	"relation-set" + cmdSuffix:         NewRelationSetCommand,
+	"relation-service-get" + cmdSuffix: NewRelationServiceGetCommand,
+	"relation-service-set" + cmdSuffix: NewRelationServiceSetCommand,
+	"unit-get" + cmdSuffix:             NewUnitGetCommand,
+	"owner-get" + cmdSuffix:            NewOwnerGetCommand,
+	"add-metric" + cmdSuffix:           NewAddMetricCommand,
+	"juju-reboot" + cmdSuffix:          NewJujuRebootCommand,
+	"status-get" + cmdSuffix:           NewStatusGetCommand,
+	"status-set" + cmdSuffix:           NewStatusSetCommand,
 }
 
 var storageCommands = map[string]creator{
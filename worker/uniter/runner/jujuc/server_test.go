@@ -206,13 +206,17 @@ var newCommandTests = []struct {
 }{
 	{"close-port", ""},
 	{"config-get", ""},
+	{"goal-state", ""},
 	{"juju-log", ""},
+	{"network-get", ""},
 	{"open-port", ""},
 	{"opened-ports", ""},
 	{"relation-get", ""},
 	{"relation-ids", ""},
 	{"relation-list", ""},
 	{"relation-set", ""},
+	{"relation-service-get", ""},
+	{"relation-service-set", ""},
 	{"unit-get", ""},
 	{"storage-get", ""},
 	{"status-get", ""},
@@ -156,6 +156,46 @@ func (c *Context) AvailabilityZone() (string, bool) {
 	return "us-east-1a", true
 }
 
+func (c *Context) NetworkInfo(bindingNames []string) (map[string]params.NetworkInfoResult, error) {
+	results := make(map[string]params.NetworkInfoResult)
+	for _, name := range bindingNames {
+		switch name {
+		case "db":
+			results[name] = params.NetworkInfoResult{
+				Space:            "internal",
+				IngressAddresses: []string{"10.0.0.10", "10.0.0.11"},
+				EgressSubnets:    []string{"10.0.0.0/24"},
+			}
+		case "juju-info":
+			results[name] = params.NetworkInfoResult{
+				IngressAddresses: []string{"192.168.0.99"},
+				EgressSubnets:    []string{"192.168.0.99/32"},
+			}
+		default:
+			results[name] = params.NetworkInfoResult{
+				Error: &params.Error{Message: fmt.Sprintf("binding name %q not valid", name)},
+			}
+		}
+	}
+	return results, nil
+}
+
+func (c *Context) GoalState() (params.GoalState, error) {
+	since := time.Date(2015, 10, 1, 12, 30, 0, 0, time.UTC)
+	return params.GoalState{
+		Units: params.UnitsGoalState{
+			"u/0": {Status: "active", Since: &since},
+			"u/1": {Status: "waiting", Since: &since},
+		},
+		Relations: map[string]params.UnitsGoalState{
+			"db": {
+				"mysql":   {Status: "joined"},
+				"mysql/0": {Status: "active", Since: &since},
+			},
+		},
+	}, nil
+}
+
 func (c *Context) Storage(tag names.StorageTag) (jujuc.ContextStorage, bool) {
 	storage, ok := c.storage[tag]
 	return storage, ok
@@ -247,9 +287,10 @@ func (c *Context) OwnerTag() string {
 }
 
 type ContextRelation struct {
-	id    int
-	name  string
-	units map[string]Settings
+	id       int
+	name     string
+	units    map[string]Settings
+	services map[string]Settings
 }
 
 func (r *ContextRelation) Id() int {
@@ -285,6 +326,33 @@ func (r *ContextRelation) ReadSettings(name string) (params.Settings, error) {
 	return s.Map(), nil
 }
 
+func (r *ContextRelation) ReadServiceSettings(name string) (params.Settings, error) {
+	s, found := r.services[name]
+	if !found {
+		return nil, fmt.Errorf("unknown service %s", name)
+	}
+	return s.Map(), nil
+}
+
+func (r *ContextRelation) WriteServiceSettings(settings map[string]string) error {
+	if r.services == nil {
+		r.services = make(map[string]Settings)
+	}
+	s, found := r.services["u"]
+	if !found {
+		s = Settings{}
+		r.services["u"] = s
+	}
+	for k, v := range settings {
+		if v == "" {
+			s.Delete(k)
+		} else {
+			s.Set(k, v)
+		}
+	}
+	return nil
+}
+
 type ContextStorage struct {
 	tag      names.StorageTag
 	kind     storage.StorageKind
@@ -18,6 +18,8 @@ type hookLogger struct {
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+	// sink, if set, is also called with each line read.
+	sink func(line string)
 }
 
 func (l *hookLogger) run() {
@@ -38,6 +40,9 @@ func (l *hookLogger) run() {
 			return
 		}
 		l.logger.Infof("%s", line)
+		if l.sink != nil {
+			l.sink(string(line))
+		}
 		l.mu.Unlock()
 	}
 }
@@ -60,6 +60,14 @@ func (ctx *ContextRelation) ReadSettings(unit string) (settings params.Settings,
 	return ctx.cache.Settings(unit)
 }
 
+func (ctx *ContextRelation) ReadServiceSettings(service string) (params.Settings, error) {
+	return ctx.ru.ReadServiceSettings(service)
+}
+
+func (ctx *ContextRelation) WriteServiceSettings(settings map[string]string) error {
+	return ctx.ru.UpdateServiceSettings(params.Settings(settings))
+}
+
 func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 	if ctx.settings == nil {
 		node, err := ctx.ru.Settings()
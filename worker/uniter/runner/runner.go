@@ -43,6 +43,7 @@ type Context interface {
 	ActionData() (*ActionData, error)
 	SetProcess(process *os.Process)
 	FlushContext(badge string, failure error) error
+	AppendActionOutput(stream, data string) error
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 }
@@ -166,21 +167,50 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	}
 	ps.Stdout = outWriter
 	ps.Stderr = outWriter
-	hookLogger := &hookLogger{
+	loggers := []*hookLogger{{
 		r:      outReader,
 		done:   make(chan struct{}),
 		logger: runner.getLogger(hookName),
+	}}
+	writers := []*os.File{outWriter}
+	if charmLocation == "actions" {
+		// The output of an action is sent to the state server while
+		// it runs, as well as being logged, so stdout and stderr are
+		// kept apart.
+		errReader, errWriter, err := os.Pipe()
+		if err != nil {
+			outReader.Close()
+			outWriter.Close()
+			return errors.Errorf("cannot make logging pipe: %v", err)
+		}
+		ps.Stderr = errWriter
+		output := newActionOutput(runner.context)
+		defer output.stop()
+		loggers[0].sink = output.sink(actionOutputStdout)
+		loggers = append(loggers, &hookLogger{
+			r:      errReader,
+			done:   make(chan struct{}),
+			logger: loggers[0].logger,
+			sink:   output.sink(actionOutputStderr),
+		})
+		writers = append(writers, errWriter)
+	}
+	for _, hookLogger := range loggers {
+		go hookLogger.run()
 	}
-	go hookLogger.run()
 	err = ps.Start()
-	outWriter.Close()
+	for _, writer := range writers {
+		writer.Close()
+	}
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(ps.Process)
 		// Block until execution finishes
 		err = ps.Wait()
 	}
-	hookLogger.stop()
+	for _, hookLogger := range loggers {
+		hookLogger.stop()
+	}
 	return errors.Trace(err)
 }
 
@@ -151,6 +151,7 @@ type MockContext struct {
 	flushBadge   string
 	flushFailure error
 	flushResult  error
+	output       []string
 }
 
 func (ctx *MockContext) UnitName() string {
@@ -178,6 +179,11 @@ func (ctx *MockContext) FlushContext(badge string, failure error) error {
 	return ctx.flushResult
 }
 
+func (ctx *MockContext) AppendActionOutput(stream, data string) error {
+	ctx.output = append(ctx.output, stream+": "+data)
+	return nil
+}
+
 type RunMockContextSuite struct {
 	envtesting.IsolationSuite
 	paths RealPaths
@@ -270,6 +276,37 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunActionSendsOutput(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &runner.ActionData{},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "some output",
+		stderr: "some error",
+	}, s.paths.charm)
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.output, jc.SameContents, []string{
+		"stdout: some output\n",
+		"stderr: some error\n",
+	})
+}
+
+func (s *RunMockContextSuite) TestRunHookSendsNoOutput(c *gc.C) {
+	ctx := &MockContext{}
+	makeCharm(c, hookSpec{
+		name:   hookName,
+		perm:   0700,
+		stdout: "some output",
+	}, s.paths.charm)
+	err := runner.NewRunner(ctx, s.paths).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.output, gc.HasLen, 0)
+}
+
 func (s *RunMockContextSuite) TestRunCommandsFlushSuccess(c *gc.C) {
 	expectErr := errors.New("pew pew pew")
 	ctx := &MockContext{
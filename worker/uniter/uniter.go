@@ -79,6 +79,10 @@ type Uniter struct {
 	ranLeaderSettingsChanged bool
 	ranConfigChanged         bool
 
+	// paused is true while the unit is paused, in which case no periodic
+	// hooks are run and no relation changes are handled.
+	paused bool
+
 	// The execution observer is only used in tests at this stage. Should this
 	// need to be extended, perhaps a list of observers would be needed.
 	observer UniterExecutionObserver
@@ -90,17 +94,24 @@ type Uniter struct {
 	// updateStatusAt defines a function that will be used to generate signals for
 	// the update-status hook
 	updateStatusAt TimedSignal
+
+	// configQuietPeriod is how long the uniter waits after the last of a
+	// run of config changes before it runs the config-changed hook.
+	configQuietPeriod time.Duration
 }
 
 // NewUniter creates a new Uniter which will install, run, and upgrade
 // a charm on behalf of the unit with the given unitTag, by executing
-// hooks and operations provoked by changes in st.
+// hooks and operations provoked by changes in st. Config changes
+// are coalesced into a single config-changed hook once no further
+// change has been seen for configQuietPeriod.
 func NewUniter(
 	st *uniter.State,
 	unitTag names.UnitTag,
 	leadershipManager coreleadership.LeadershipManager,
 	dataDir string,
 	hookLock *fslock.Lock,
+	configQuietPeriod time.Duration,
 ) *Uniter {
 	u := &Uniter{
 		st:                st,
@@ -109,6 +120,7 @@ func NewUniter(
 		leadershipManager: leadershipManager,
 		collectMetricsAt:  inactiveMetricsTimer,
 		updateStatusAt:    updateStatusSignal,
+		configQuietPeriod: configQuietPeriod,
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -153,7 +165,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 	logger.Infof("unit %q started", u.unit)
 
 	// Start filtering state change events for consumption by modes.
-	u.f, err = filter.NewFilter(u.st, unitTag)
+	u.f, err = filter.NewFilterWithQuietPeriod(u.st, unitTag, u.configQuietPeriod)
 	if err != nil {
 		return err
 	}
@@ -266,8 +278,9 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		u.tomb.Dying(),
 	)
 
-	operationExecutor, err := operation.NewExecutor(
-		u.paths.State.OperationsFile, u.getServiceCharmURL,
+	operationExecutor, err := operation.NewStateExecutor(
+		operation.NewControllerState(u.unit, u.paths.State.OperationsFile),
+		u.getServiceCharmURL,
 	)
 	if err != nil {
 		return err
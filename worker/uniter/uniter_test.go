@@ -1251,6 +1251,37 @@ func (s *UniterSuite) TestUniterRelations(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterPause(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"paused unit defers relation hooks until resumed",
+			quickStartRelation{},
+			setPaused{true},
+			waitUnitAgent{
+				status: params.StatusIdle,
+				info:   "paused",
+				charm:  0,
+			},
+			changeRelationUnit{"mysql/0"},
+			waitHooks{},
+			setPaused{false},
+			waitHooks{"db-relation-changed mysql/0 db:0"},
+			waitUnitAgent{
+				status: params.StatusIdle,
+				charm:  0,
+			},
+			verifyRunning{},
+		), ut(
+			"paused unit still runs config-changed",
+			quickStart{},
+			setPaused{true},
+			changeConfig{"blog-title": "Goodness Gracious Me"},
+			waitHooks{"config-changed"},
+			verifyRunning{},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterRelationErrors(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		ut(
@@ -451,7 +451,7 @@ func (s startUniter) step(c *gc.C, ctx *context) {
 	locksDir := filepath.Join(ctx.dataDir, "locks")
 	lock, err := fslock.NewLock(locksDir, "uniter-hook-execution")
 	c.Assert(err, jc.ErrorIsNil)
-	ctx.uniter = uniter.NewUniter(ctx.api, tag, ctx.leader, ctx.dataDir, lock)
+	ctx.uniter = uniter.NewUniter(ctx.api, tag, ctx.leader, ctx.dataDir, lock, 0)
 	uniter.SetUniterObserver(ctx.uniter, ctx)
 }
 
@@ -858,6 +858,15 @@ func (s changeMeterStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setPaused struct {
+	paused bool
+}
+
+func (s setPaused) step(c *gc.C, ctx *context) {
+	err := ctx.unit.SetPaused(s.paused)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type metricsTick struct{}
 
 func (s metricsTick) step(c *gc.C, ctx *context) {
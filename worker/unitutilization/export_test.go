@@ -0,0 +1,26 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitutilization
+
+import (
+	"time"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+var SamplePeriod = &samplePeriod
+
+// Sampler exposes the sampler used by the worker.
+type Sampler interface {
+	Sample(now time.Time) ([]params.UnitUtilizationSample, error)
+}
+
+// NewSampler returns a sampler of the processes under procDir.
+func NewSampler(procDir string) Sampler {
+	return &sampler{procDir: procDir}
+}
+
+func (s *sampler) Sample(now time.Time) ([]params.UnitUtilizationSample, error) {
+	return s.sample(now)
+}
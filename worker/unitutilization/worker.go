@@ -0,0 +1,230 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package unitutilization provides a worker that periodically samples
+// the CPU and memory used by the processes of each unit agent on a
+// machine, and reports the samples to the API server.
+package unitutilization
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.unitutilization")
+
+// samplePeriod holds how often the units' processes are sampled.
+var samplePeriod = time.Minute
+
+// clockTicks holds the number of clock ticks per second in which the
+// kernel reports process CPU times. It is 100 on all the platforms
+// juju supports.
+const clockTicks = 100
+
+// Facade is the API the worker uses to report the samples.
+type Facade interface {
+	SetUnitUtilization(samples []params.UnitUtilizationSample) error
+}
+
+// New returns a worker that samples the process trees of the unit
+// agents found in the proc filesystem mounted at procDir, and reports
+// the resources each unit used during every sample period.
+func New(facade Facade, procDir string) worker.Worker {
+	return worker.NewSimpleWorker(func(stopCh <-chan struct{}) error {
+		s := &sampler{procDir: procDir}
+		if _, err := s.sample(time.Now()); err != nil {
+			return errors.Trace(err)
+		}
+		for {
+			select {
+			case <-stopCh:
+				return tomb.ErrDying
+			case <-time.After(samplePeriod):
+			}
+			samples, err := s.sample(time.Now())
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if len(samples) == 0 {
+				continue
+			}
+			if err := facade.SetUnitUtilization(samples); err != nil {
+				return errors.Annotate(err, "cannot report unit utilization")
+			}
+			logger.Debugf("utilization reported for %d units", len(samples))
+		}
+	})
+}
+
+// procStat holds the fields of /proc/<pid>/stat used by the sampler.
+type procStat struct {
+	ppid     int
+	cpuTicks uint64
+	rssPages uint64
+}
+
+// sampler measures the resources used by unit process trees between
+// successive calls to sample.
+type sampler struct {
+	procDir  string
+	lastTime time.Time
+	lastCPU  map[int]uint64
+}
+
+// sample returns a sample for each unit agent currently running, with
+// the CPU use measured since the previous call. The first call only
+// records a baseline, and returns no samples.
+func (s *sampler) sample(now time.Time) ([]params.UnitUtilizationSample, error) {
+	stats, units, err := s.readProcs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	children := make(map[int][]int)
+	for pid, stat := range stats {
+		children[stat.ppid] = append(children[stat.ppid], pid)
+	}
+	cpu := make(map[int]uint64)
+	for pid, stat := range stats {
+		cpu[pid] = stat.cpuTicks
+	}
+	lastCPU, lastTime := s.lastCPU, s.lastTime
+	s.lastCPU, s.lastTime = cpu, now
+	if lastCPU == nil {
+		return nil, nil
+	}
+	elapsed := now.Sub(lastTime).Seconds()
+	pageSize := uint64(os.Getpagesize())
+	var samples []params.UnitUtilizationSample
+	for unitName, rootPid := range units {
+		sample := params.UnitUtilizationSample{
+			UnitTag: names.NewUnitTag(unitName).String(),
+			Time:    now.UTC(),
+		}
+		var ticks uint64
+		for _, pid := range descendants(rootPid, children) {
+			stat := stats[pid]
+			// Processes started since the last sample have used
+			// all of their CPU time within the period.
+			if last, ok := lastCPU[pid]; ok && last <= stat.cpuTicks {
+				ticks += stat.cpuTicks - last
+			} else if !ok {
+				ticks += stat.cpuTicks
+			}
+			sample.MemoryBytes += stat.rssPages * pageSize
+			sample.Processes++
+		}
+		if elapsed > 0 {
+			sample.CPUPercent = float64(ticks) * 100 / clockTicks / elapsed
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// descendants returns the given pid and the pids of all its
+// descendants.
+func descendants(pid int, children map[int][]int) []int {
+	pids := []int{pid}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, children[pids[i]]...)
+	}
+	return pids
+}
+
+// readProcs returns the stats of all processes, keyed by pid, and the
+// pids of the unit agents, keyed by unit name. Processes that exit
+// while being read are ignored.
+func (s *sampler) readProcs() (map[int]procStat, map[string]int, error) {
+	entries, err := ioutil.ReadDir(s.procDir)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	stats := make(map[int]procStat)
+	units := make(map[string]int)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.procDir, entry.Name())
+		data, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		stat, err := parseStat(string(data))
+		if err != nil {
+			logger.Warningf("cannot parse stat of process %d: %v", pid, err)
+			continue
+		}
+		stats[pid] = stat
+		data, err = ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+		if err != nil {
+			continue
+		}
+		if unitName, ok := unitAgentName(string(data)); ok {
+			units[unitName] = pid
+		}
+	}
+	return stats, units, nil
+}
+
+// parseStat parses the contents of a /proc/<pid>/stat file. The
+// command name is skipped by looking for its closing parenthesis, as it
+// may hold spaces.
+func parseStat(data string) (procStat, error) {
+	i := strings.LastIndex(data, ")")
+	if i < 0 {
+		return procStat{}, errors.New("missing command name")
+	}
+	// Fields are counted from the process state, which follows the
+	// command name.
+	fields := strings.Fields(data[i+1:])
+	if len(fields) < 22 {
+		return procStat{}, errors.Errorf("expected at least 22 fields, got %d", len(fields))
+	}
+	var values [4]uint64
+	for j, index := range []int{1, 11, 12, 21} {
+		value, err := strconv.ParseUint(fields[index], 10, 64)
+		if err != nil {
+			return procStat{}, errors.Trace(err)
+		}
+		values[j] = value
+	}
+	return procStat{
+		ppid:     int(values[0]),
+		cpuTicks: values[1] + values[2],
+		rssPages: values[3],
+	}, nil
+}
+
+// unitAgentName returns the name of the unit whose agent was started
+// with the given NUL-separated command line, if it is a unit agent.
+func unitAgentName(cmdline string) (string, bool) {
+	args := strings.Split(strings.TrimRight(cmdline, "\x00"), "\x00")
+	if len(args) < 2 || filepath.Base(args[0]) != "jujud" || args[1] != "unit" {
+		return "", false
+	}
+	for i, arg := range args[2:] {
+		if strings.HasPrefix(arg, "--unit-name=") {
+			return strings.TrimPrefix(arg, "--unit-name="), true
+		}
+		if arg == "--unit-name" && i+3 < len(args) {
+			return args[i+3], true
+		}
+	}
+	return "", false
+}
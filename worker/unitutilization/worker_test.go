@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package unitutilization_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/unitutilization"
+)
+
+type workerSuite struct {
+	coretesting.BaseSuite
+	procDir string
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.procDir = c.MkDir()
+	s.addProc(c, 1, 0, 500, 10, "/sbin/init")
+	s.addProc(c, 100, 1, 50, 20, "/var/lib/juju/tools/unit-wordpress-0/jujud", "unit", "--data-dir", "/var/lib/juju", "--unit-name", "wordpress/0")
+	s.addProc(c, 101, 100, 100, 30, "/bin/sh", "hooks/config-changed")
+	s.addProc(c, 200, 1, 10, 40, "/var/lib/juju/tools/unit-mysql-0/jujud", "unit", "--unit-name=mysql/0")
+	s.addProc(c, 300, 1, 1000, 50, "/usr/sbin/sshd")
+}
+
+// addProc writes the stat and cmdline files of a process under the fake
+// proc directory.
+func (s *workerSuite) addProc(c *gc.C, pid, ppid int, cpuTicks, rssPages uint64, args ...string) {
+	dir := filepath.Join(s.procDir, strconv.Itoa(pid))
+	err := os.MkdirAll(dir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	name := filepath.Base(args[0])
+	stat := fmt.Sprintf("%d (%s) S %d 0 0 0 -1 0 0 0 0 0 %d %d 0 0 20 0 1 0 0 0 %d 0 0\n",
+		pid, name+" x", ppid, cpuTicks/2, cpuTicks-cpuTicks/2, rssPages)
+	err = ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	cmdline := strings.Join(args, "\x00") + "\x00"
+	err = ioutil.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *workerSuite) TestSample(c *gc.C) {
+	sampler := unitutilization.NewSampler(s.procDir)
+	t0 := time.Date(2015, 9, 1, 12, 0, 0, 0, time.UTC)
+	samples, err := sampler.Sample(t0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(samples, gc.HasLen, 0)
+
+	// The wordpress agent and its hook use another 150 ticks, and a
+	// new process using 50 ticks starts under the hook; mysql uses 10
+	// ticks.
+	s.addProc(c, 100, 1, 100, 20, "/var/lib/juju/tools/unit-wordpress-0/jujud", "unit", "--unit-name", "wordpress/0")
+	s.addProc(c, 101, 100, 200, 30, "/bin/sh", "hooks/config-changed")
+	s.addProc(c, 102, 101, 50, 10, "/usr/bin/python", "helper")
+	s.addProc(c, 200, 1, 20, 40, "/var/lib/juju/tools/unit-mysql-0/jujud", "unit", "--unit-name=mysql/0")
+	s.addProc(c, 300, 1, 5000, 50, "/usr/sbin/sshd")
+
+	t1 := t0.Add(10 * time.Second)
+	samples, err = sampler.Sample(t1)
+	c.Assert(err, jc.ErrorIsNil)
+	sort.Sort(samplesByUnit(samples))
+	pageSize := uint64(os.Getpagesize())
+	c.Assert(samples, jc.DeepEquals, []params.UnitUtilizationSample{{
+		UnitTag:     "unit-mysql-0",
+		Time:        t1,
+		CPUPercent:  1,
+		MemoryBytes: 40 * pageSize,
+		Processes:   1,
+	}, {
+		UnitTag:     "unit-wordpress-0",
+		Time:        t1,
+		CPUPercent:  20,
+		MemoryBytes: 60 * pageSize,
+		Processes:   3,
+	}})
+}
+
+func (s *workerSuite) TestSampleMissingProcDir(c *gc.C) {
+	sampler := unitutilization.NewSampler(filepath.Join(s.procDir, "missing"))
+	for i := 0; i < 2; i++ {
+		samples, err := sampler.Sample(time.Now())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(samples, gc.HasLen, 0)
+	}
+}
+
+func (s *workerSuite) TestReportsSamples(c *gc.C) {
+	s.PatchValue(unitutilization.SamplePeriod, 10*time.Millisecond)
+	facade := &fakeFacade{reported: make(chan []params.UnitUtilizationSample, 10)}
+	w := unitutilization.New(facade, s.procDir)
+	defer func() {
+		w.Kill()
+		c.Check(w.Wait(), jc.ErrorIsNil)
+	}()
+	select {
+	case samples := <-facade.reported:
+		var units []string
+		for _, sample := range samples {
+			units = append(units, sample.UnitTag)
+		}
+		c.Assert(units, jc.SameContents, []string{"unit-wordpress-0", "unit-mysql-0"})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for samples to be reported")
+	}
+}
+
+func (s *workerSuite) TestReportError(c *gc.C) {
+	s.PatchValue(unitutilization.SamplePeriod, 10*time.Millisecond)
+	facade := &fakeFacade{
+		reported: make(chan []params.UnitUtilizationSample, 10),
+		err:      errors.New("boom"),
+	}
+	w := unitutilization.New(facade, s.procDir)
+	err := w.Wait()
+	c.Assert(err, gc.ErrorMatches, "cannot report unit utilization: boom")
+}
+
+type samplesByUnit []params.UnitUtilizationSample
+
+func (s samplesByUnit) Len() int           { return len(s) }
+func (s samplesByUnit) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s samplesByUnit) Less(i, j int) bool { return s[i].UnitTag < s[j].UnitTag }
+
+type fakeFacade struct {
+	reported chan []params.UnitUtilizationSample
+	err      error
+}
+
+func (f *fakeFacade) SetUnitUtilization(samples []params.UnitUtilizationSample) error {
+	f.reported <- samples
+	return f.err
+}
@@ -6,4 +6,5 @@ package upgrader
 var (
 	RetryAfter           = &retryAfter
 	AllowedTargetVersion = allowedTargetVersion
+	CheckDowngrade       = &checkDowngrade
 )
@@ -4,8 +4,11 @@
 package upgrader
 
 import (
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/upgrader"
+	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agent"
 	"github.com/juju/juju/worker/dependency"
@@ -27,6 +30,12 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 var newWorker = func(agent agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 	currentConfig := agent.CurrentConfig()
 	upgraderFacade := upgrader.NewState(apiCaller)
+
+	// Before running the upgrader, ensure we record the Juju version
+	// this agent is running.
+	if err := upgraderFacade.SetVersion(currentConfig.Tag().String(), version.Current); err != nil {
+		return nil, errors.Annotate(err, "cannot set agent version")
+	}
 	return NewUpgrader(
 		upgraderFacade,
 		currentConfig,
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/api/upgrader"
 	"github.com/juju/juju/state/watcher"
 	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/upgrades"
 	"github.com/juju/juju/version"
 )
 
@@ -83,8 +84,14 @@ func (u *Upgrader) Stop() error {
 	return u.Wait()
 }
 
+// checkDowngrade reports whether agents can be safely downgraded
+// between two versions.
+var checkDowngrade = upgrades.CheckDowngrade
+
 // allowedTargetVersion checks if targetVersion is too different from
-// curVersion to allow a downgrade.
+// curVersion to allow a downgrade. Downgrades are allowed within a
+// patch series, to recover from a bad patch release, as long as no
+// upgrade steps have changed the database since targetVersion.
 func allowedTargetVersion(
 	origAgentVersion version.Number,
 	curVersion version.Number,
@@ -94,10 +101,11 @@ func allowedTargetVersion(
 	if upgradeRunning && targetVersion == origAgentVersion {
 		return true
 	}
-	if targetVersion.Major < curVersion.Major {
-		return false
+	if targetVersion.Compare(curVersion) >= 0 {
+		return true
 	}
-	if targetVersion.Major == curVersion.Major && targetVersion.Minor < curVersion.Minor {
+	if err := checkDowngrade(curVersion, targetVersion); err != nil {
+		logger.Debugf("%v", err)
 		return false
 	}
 	return true
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
+	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/utils/symlink"
@@ -396,3 +397,23 @@ func (s *AllowedTargetVersionSuite) TestAllowedTargetVersionSuite(c *gc.C) {
 		c.Check(result, gc.Equals, test.allowed)
 	}
 }
+
+func (s *AllowedTargetVersionSuite) TestAllowedTargetVersionDowngradeWindow(c *gc.C) {
+	var checked []version.Number
+	restore := gitjujutesting.PatchValue(upgrader.CheckDowngrade, func(from, to version.Number) error {
+		checked = append(checked, from, to)
+		return errors.New("upgrade steps for 1.2.3 have changed the database")
+	})
+	defer restore()
+
+	current := version.MustParse("1.2.3")
+	target := version.MustParse("1.2.2")
+	c.Check(upgrader.AllowedTargetVersion(current, current, false, target), jc.IsFalse)
+	c.Check(checked, jc.DeepEquals, []version.Number{current, target})
+
+	// Upgrades, and rolling back a failed upgrade, are unaffected.
+	checked = nil
+	c.Check(upgrader.AllowedTargetVersion(current, current, false, version.MustParse("1.2.4")), jc.IsTrue)
+	c.Check(upgrader.AllowedTargetVersion(target, current, true, target), jc.IsTrue)
+	c.Check(checked, gc.HasLen, 0)
+}